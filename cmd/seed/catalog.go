@@ -0,0 +1,196 @@
+package main
+
+import (
+	"fmt"
+	"math/rand/v2"
+)
+
+// Synthetic TMDb ids start well above the real catalogue so fixtures can never
+// be confused with live data
+const (
+	movieIDBase  = 9_000_000
+	seriesIDBase = 9_500_000
+)
+
+// Movie is a seeded film and the metadata its enrichment fixture carries
+type Movie struct {
+	TMDbID      int      `json:"tmdb_id"`
+	Title       string   `json:"title"`
+	Year        int      `json:"year"`
+	Runtime     int      `json:"runtime"`
+	Genres      []string `json:"genres"`
+	Overview    string   `json:"overview"`
+	VoteAverage float64  `json:"vote_average"`
+	File        string   `json:"file"`
+}
+
+// Series is a seeded TV show with its episodes
+type Series struct {
+	TMDbID      int       `json:"tmdb_id"`
+	Name        string    `json:"name"`
+	Year        int       `json:"year"`
+	Genres      []string  `json:"genres"`
+	Overview    string    `json:"overview"`
+	VoteAverage float64   `json:"vote_average"`
+	Episodes    []Episode `json:"episodes"`
+}
+
+// Episode is one seeded episode file
+type Episode struct {
+	Season  int    `json:"season"`
+	Episode int    `json:"episode"`
+	Title   string `json:"title"`
+	File    string `json:"file"`
+}
+
+// Album is a seeded album with tagged tracks
+type Album struct {
+	Artist string  `json:"artist"`
+	Title  string  `json:"title"`
+	Year   int     `json:"year"`
+	Genre  string  `json:"genre"`
+	Tracks []Track `json:"tracks"`
+}
+
+// Track is one seeded audio file
+type Track struct {
+	Number int    `json:"number"`
+	Title  string `json:"title"`
+	File   string `json:"file"`
+}
+
+// Catalog is everything the seed tool generates. It's written to
+// manifest.json so tests can assert against the expected metadata.
+type Catalog struct {
+	Seed   uint64   `json:"seed"`
+	Movies []Movie  `json:"movies"`
+	Series []Series `json:"series"`
+	Albums []Album  `json:"albums"`
+}
+
+var movieTitles = []struct {
+	title  string
+	year   int
+	genres []string
+}{
+	{"Nosferatu", 1922, []string{"Horror", "Fantasy"}},
+	{"Sherlock Jr.", 1924, []string{"Comedy", "Action"}},
+	{"The General", 1926, []string{"Comedy", "Adventure"}},
+	{"Metropolis", 1927, []string{"Science Fiction", "Drama"}},
+	{"His Girl Friday", 1940, []string{"Comedy", "Romance"}},
+	{"Detour", 1945, []string{"Crime", "Thriller"}},
+	{"The Stranger", 1946, []string{"Drama", "Thriller"}},
+	{"D.O.A.", 1949, []string{"Crime", "Mystery"}},
+	{"Plan 9 from Outer Space", 1957, []string{"Science Fiction", "Horror"}},
+	{"Charade", 1963, []string{"Mystery", "Romance"}},
+	{"Night of the Living Dead", 1968, []string{"Horror", "Thriller"}},
+	{"The Last Lighthouse Keeper", 2019, []string{"Drama"}},
+	{"Signal Under Glass", 2021, []string{"Science Fiction", "Mystery"}},
+	{"Borrowed Summer", 2022, []string{"Romance", "Comedy"}},
+	{"Harbor Lights", 2023, []string{"Crime", "Drama"}},
+}
+
+var seriesNames = []struct {
+	name   string
+	year   int
+	genres []string
+}{
+	{"The Quiet Coast", 2018, []string{"Drama", "Mystery"}},
+	{"Station Eleven Below", 2020, []string{"Science Fiction"}},
+	{"Kitchen Confidential Files", 2021, []string{"Comedy"}},
+	{"Northern Lines", 2022, []string{"Crime", "Drama"}},
+	{"Orbit Academy", 2023, []string{"Animation", "Family"}},
+}
+
+var episodeWords = []string{
+	"Pilot", "The Crossing", "Low Tide", "Cold Open", "Signal Fire", "Second Chances",
+	"The Long Night", "Homecoming", "Fault Lines", "Old Friends", "Blackout", "Reunion",
+}
+
+var artistAlbums = []struct {
+	artist string
+	album  string
+	year   int
+	genre  string
+}{
+	{"The Paper Kites Collective", "Maps of Small Towns", 2016, "Indie Folk"},
+	{"Neon Harbor", "Afterglow Drive", 2019, "Synthwave"},
+	{"Marisol Vega Trio", "Late Set at the Blue Room", 2014, "Jazz"},
+	{"Glass Orchard", "Everything Grows Back", 2021, "Alternative"},
+	{"Static Choir", "Frequencies", 2018, "Electronic"},
+}
+
+var trackWords = []string{
+	"Opening", "Northbound", "Paper Boats", "Satellite", "Slow Burn", "Undertow",
+	"Lanterns", "Weathervane", "Hourglass", "Closing Time", "Little Fires", "Echoes",
+}
+
+// buildCatalog picks counts of each media kind from the fixed title lists.
+// The seed only varies numeric details so names stay stable between runs.
+func buildCatalog(seed uint64, movies, series, albums int) *Catalog {
+	rng := rand.New(rand.NewPCG(seed, seed^0x5eed))
+	cat := &Catalog{Seed: seed}
+
+	for i := 0; i < movies && i < len(movieTitles); i++ {
+		m := movieTitles[i]
+		cat.Movies = append(cat.Movies, Movie{
+			TMDbID:      movieIDBase + i + 1,
+			Title:       m.title,
+			Year:        m.year,
+			Runtime:     80 + rng.IntN(70),
+			Genres:      m.genres,
+			Overview:    fmt.Sprintf("A seeded %s feature used for integration testing.", m.genres[0]),
+			VoteAverage: voteAverage(rng),
+			File:        fmt.Sprintf("movies/%s (%d)/%s (%d).mkv", m.title, m.year, m.title, m.year),
+		})
+	}
+
+	for i := 0; i < series && i < len(seriesNames); i++ {
+		s := seriesNames[i]
+		show := Series{
+			TMDbID:      seriesIDBase + i + 1,
+			Name:        s.name,
+			Year:        s.year,
+			Genres:      s.genres,
+			Overview:    fmt.Sprintf("A seeded %s series used for integration testing.", s.genres[0]),
+			VoteAverage: voteAverage(rng),
+		}
+		seasons := 1 + rng.IntN(2)
+		for season := 1; season <= seasons; season++ {
+			episodes := 3 + rng.IntN(4)
+			for ep := 1; ep <= episodes; ep++ {
+				title := episodeWords[(season*7+ep)%len(episodeWords)]
+				show.Episodes = append(show.Episodes, Episode{
+					Season:  season,
+					Episode: ep,
+					Title:   title,
+					File: fmt.Sprintf("tv/%s/Season %02d/%s - S%02dE%02d - %s.mkv",
+						s.name, season, s.name, season, ep, title),
+				})
+			}
+		}
+		cat.Series = append(cat.Series, show)
+	}
+
+	for i := 0; i < albums && i < len(artistAlbums); i++ {
+		a := artistAlbums[i]
+		album := Album{Artist: a.artist, Title: a.album, Year: a.year, Genre: a.genre}
+		tracks := 4 + rng.IntN(5)
+		for n := 1; n <= tracks; n++ {
+			title := trackWords[(i*5+n)%len(trackWords)]
+			album.Tracks = append(album.Tracks, Track{
+				Number: n,
+				Title:  title,
+				File:   fmt.Sprintf("music/%s/%s (%d)/%02d - %s.mp3", a.artist, a.album, a.year, n, title),
+			})
+		}
+		cat.Albums = append(cat.Albums, album)
+	}
+
+	return cat
+}
+
+// voteAverage returns a plausible rating rounded to one decimal place
+func voteAverage(rng *rand.Rand) float64 {
+	return float64(55+rng.IntN(35)) / 10
+}
@@ -0,0 +1,214 @@
+// Command seed builds a synthetic media library for integration tests and
+// demos: dummy movie, TV and music files with realistic names, TMDb
+// enrichment fixtures for every title, and a mock TMDb server that answers
+// from those fixtures so scan, enrich, browse and transcode flows run
+// deterministically without network access. The fixtures are also written as
+// a cassette for mock provider mode (VIEWRA_MOCK_PROVIDERS).
+//
+// Usage:
+//
+//	seed generate [-out dir] [-seed n] [-movies n] [-series n] [-albums n]
+//	seed serve-tmdb [-out dir] [-addr host:port]
+//	seed register [-out dir] [-api url] [-library-root dir] [-scan]
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+	"time"
+)
+
+const defaultOut = "viewra-data/seed"
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("seed: ")
+
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+
+	var err error
+	switch os.Args[1] {
+	case "generate":
+		err = runGenerate(os.Args[2:])
+	case "serve-tmdb":
+		err = runServeTMDb(os.Args[2:])
+	case "register":
+		err = runRegister(os.Args[2:])
+	case "-h", "-help", "--help", "help":
+		usage()
+		return
+	default:
+		log.Printf("unknown command %q", os.Args[1])
+		usage()
+		os.Exit(2)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, `Usage: seed <command> [flags]
+
+Commands:
+  generate     write the synthetic library, manifest.json and TMDb fixtures
+  serve-tmdb   run a mock TMDb API that answers from the generated fixtures
+  register     create the seeded libraries on a running Viewra server
+
+Run "seed <command> -h" for the flags of each command.`)
+}
+
+func runGenerate(args []string) error {
+	fs := flag.NewFlagSet("generate", flag.ExitOnError)
+	out := fs.String("out", defaultOut, "directory to write the library into")
+	seed := fs.Uint64("seed", 1, "seed for generated details such as runtimes and ratings")
+	movies := fs.Int("movies", 10, "number of movies (max 15)")
+	series := fs.Int("series", 3, "number of TV series (max 5)")
+	albums := fs.Int("albums", 3, "number of music albums (max 5)")
+	videoSize := fs.Int("video-size", 64<<10, "size in bytes of each dummy video file")
+	clean := fs.Bool("clean", false, "remove the output directory first")
+	fs.Parse(args)
+
+	if *movies < 0 || *series < 0 || *albums < 0 {
+		return errors.New("counts must not be negative")
+	}
+	if *clean {
+		if err := os.RemoveAll(*out); err != nil {
+			return fmt.Errorf("failed to clean %s: %w", *out, err)
+		}
+	}
+
+	cat := buildCatalog(*seed, *movies, *series, *albums)
+	files, err := writeLibrary(*out, cat, *videoSize)
+	if err != nil {
+		return err
+	}
+	fixtures, search := tmdbFixtures(cat)
+	if err := writeTMDbFixtures(tmdbFixtureDir(*out), fixtures, search); err != nil {
+		return err
+	}
+	if err := writeTMDbCassette(cassetteDir(*out), fixtures, search); err != nil {
+		return err
+	}
+
+	log.Printf("wrote %d media files (%d movies, %d series, %d albums) to %s",
+		files, len(cat.Movies), len(cat.Series), len(cat.Albums), *out)
+	log.Printf("TMDb fixtures in %s, cassettes for mock provider mode in %s", tmdbFixtureDir(*out), cassetteDir(*out))
+	return nil
+}
+
+func runServeTMDb(args []string) error {
+	fs := flag.NewFlagSet("serve-tmdb", flag.ExitOnError)
+	out := fs.String("out", defaultOut, "directory generate wrote the library into")
+	addr := fs.String("addr", "127.0.0.1:8089", "address to listen on")
+	fs.Parse(args)
+
+	dir := tmdbFixtureDir(*out)
+	if _, err := os.Stat(filepath.Join(dir, "search", "multi.json")); err != nil {
+		return fmt.Errorf("no TMDb fixtures in %s; run seed generate first", dir)
+	}
+
+	server := &http.Server{
+		Addr:              *addr,
+		Handler:           &tmdbServer{dir: dir, logger: log.Default()},
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		server.Shutdown(shutdownCtx)
+	}()
+
+	log.Printf("mock TMDb API listening on http://%s/3", *addr)
+	if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+func runRegister(args []string) error {
+	fs := flag.NewFlagSet("register", flag.ExitOnError)
+	out := fs.String("out", defaultOut, "directory generate wrote the library into")
+	api := fs.String("api", "http://localhost:8080", "base URL of the Viewra server")
+	root := fs.String("library-root", "", "library path as seen by the server (defaults to the absolute -out path)")
+	scan := fs.Bool("scan", false, "start a scan once the libraries exist")
+	fs.Parse(args)
+
+	if *root == "" {
+		abs, err := filepath.Abs(*out)
+		if err != nil {
+			return err
+		}
+		*root = abs
+	}
+
+	client := &http.Client{Timeout: 30 * time.Second}
+	for _, lib := range []struct{ dir, kind string }{
+		{"movies", "movie"},
+		{"tv", "tv"},
+		{"music", "music"},
+	} {
+		if _, err := os.Stat(filepath.Join(*out, lib.dir)); err != nil {
+			continue
+		}
+		// Library paths are interpreted by the server, which may run on
+		// another OS or in a container, so always join them with slashes
+		path := *root + "/" + lib.dir
+		if err := postJSON(client, *api+"/api/admin/media-libraries/", map[string]string{
+			"path": path,
+			"type": lib.kind,
+		}); err != nil {
+			return fmt.Errorf("failed to create %s library: %w", lib.kind, err)
+		}
+		log.Printf("created %s library at %s", lib.kind, path)
+	}
+
+	if *scan {
+		if err := postJSON(client, *api+"/api/scanner/scan", nil); err != nil {
+			return fmt.Errorf("failed to start scan: %w", err)
+		}
+		log.Printf("scan started")
+	}
+	return nil
+}
+
+func postJSON(client *http.Client, url string, body any) error {
+	var payload io.Reader = http.NoBody
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		payload = bytes.NewReader(data)
+	}
+
+	resp, err := client.Post(url, "application/json", payload)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 4<<10))
+		return fmt.Errorf("%s returned %s: %s", url, resp.Status, bytes.TrimSpace(msg))
+	}
+	return nil
+}
@@ -0,0 +1,362 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/mantonx/viewra/sdk/cassette"
+)
+
+// tmdbGenreIDs maps genre names to TMDb's ids; unknown genres get id 0
+var tmdbGenreIDs = map[string]int{
+	"Action": 28, "Adventure": 12, "Animation": 16, "Comedy": 35, "Crime": 80,
+	"Drama": 18, "Family": 10751, "Fantasy": 14, "Horror": 27, "Mystery": 9648,
+	"Romance": 10749, "Science Fiction": 878, "Thriller": 53,
+}
+
+// tmdbHost is the API host recorded in cassettes
+const tmdbHost = "api.themoviedb.org"
+
+// tmdbFixtures builds a TMDb response for every request the enricher makes
+// for the catalog, keyed by API path below /3/, plus the search results
+func tmdbFixtures(cat *Catalog) (map[string]any, []map[string]any) {
+	fixtures := map[string]any{}
+	var search []map[string]any
+
+	for _, m := range cat.Movies {
+		release := fmt.Sprintf("%d-01-01", m.Year)
+		search = append(search, map[string]any{
+			"id":                m.TMDbID,
+			"media_type":        "movie",
+			"title":             m.Title,
+			"original_title":    m.Title,
+			"overview":          m.Overview,
+			"release_date":      release,
+			"genre_ids":         genreIDs(m.Genres),
+			"vote_average":      m.VoteAverage,
+			"vote_count":        100,
+			"popularity":        int(m.VoteAverage * 10),
+			"poster_path":       fmt.Sprintf("/seed/movie-%d-poster.jpg", m.TMDbID),
+			"backdrop_path":     fmt.Sprintf("/seed/movie-%d-backdrop.jpg", m.TMDbID),
+			"original_language": "en",
+		})
+		details := map[string]any{
+			"id":                m.TMDbID,
+			"title":             m.Title,
+			"original_title":    m.Title,
+			"overview":          m.Overview,
+			"tagline":           "",
+			"release_date":      release,
+			"runtime":           m.Runtime,
+			"status":            "Released",
+			"genres":            genres(m.Genres),
+			"vote_average":      m.VoteAverage,
+			"vote_count":        100,
+			"popularity":        int(m.VoteAverage * 10),
+			"original_language": "en",
+			"poster_path":       fmt.Sprintf("/seed/movie-%d-poster.jpg", m.TMDbID),
+			"backdrop_path":     fmt.Sprintf("/seed/movie-%d-backdrop.jpg", m.TMDbID),
+		}
+		fixtures[fmt.Sprintf("movie/%d", m.TMDbID)] = details
+		fixtures[fmt.Sprintf("movie/%d/images", m.TMDbID)] = images(m.TMDbID, "movie")
+	}
+
+	for _, s := range cat.Series {
+		firstAir := fmt.Sprintf("%d-09-01", s.Year)
+		search = append(search, map[string]any{
+			"id":                s.TMDbID,
+			"media_type":        "tv",
+			"name":              s.Name,
+			"original_name":     s.Name,
+			"overview":          s.Overview,
+			"first_air_date":    firstAir,
+			"genre_ids":         genreIDs(s.Genres),
+			"vote_average":      s.VoteAverage,
+			"vote_count":        100,
+			"popularity":        int(s.VoteAverage * 10),
+			"poster_path":       fmt.Sprintf("/seed/tv-%d-poster.jpg", s.TMDbID),
+			"backdrop_path":     fmt.Sprintf("/seed/tv-%d-backdrop.jpg", s.TMDbID),
+			"origin_country":    []string{"US"},
+			"original_language": "en",
+		})
+
+		seasons := map[int][]map[string]any{}
+		for _, ep := range s.Episodes {
+			seasons[ep.Season] = append(seasons[ep.Season], map[string]any{
+				"id":             s.TMDbID*100 + ep.Season*10 + ep.Episode,
+				"name":           ep.Title,
+				"overview":       fmt.Sprintf("Episode %d of season %d of %s.", ep.Episode, ep.Season, s.Name),
+				"air_date":       firstAir,
+				"episode_number": ep.Episode,
+				"season_number":  ep.Season,
+				"still_path":     "",
+				"vote_average":   s.VoteAverage,
+				"vote_count":     10,
+			})
+		}
+
+		details := map[string]any{
+			"id":                 s.TMDbID,
+			"name":               s.Name,
+			"original_name":      s.Name,
+			"overview":           s.Overview,
+			"first_air_date":     firstAir,
+			"status":             "Returning Series",
+			"type":               "Scripted",
+			"in_production":      true,
+			"number_of_seasons":  len(seasons),
+			"number_of_episodes": len(s.Episodes),
+			"episode_run_time":   []int{42},
+			"genres":             genres(s.Genres),
+			"vote_average":       s.VoteAverage,
+			"vote_count":         100,
+			"popularity":         int(s.VoteAverage * 10),
+			"poster_path":        fmt.Sprintf("/seed/tv-%d-poster.jpg", s.TMDbID),
+			"backdrop_path":      fmt.Sprintf("/seed/tv-%d-backdrop.jpg", s.TMDbID),
+			"origin_country":     []string{"US"},
+			"original_language":  "en",
+		}
+		fixtures[fmt.Sprintf("tv/%d", s.TMDbID)] = details
+		fixtures[fmt.Sprintf("tv/%d/images", s.TMDbID)] = images(s.TMDbID, "tv")
+		for season, episodes := range seasons {
+			fixtures[fmt.Sprintf("tv/%d/season/%d", s.TMDbID, season)] = map[string]any{
+				"id":            s.TMDbID*100 + season,
+				"name":          fmt.Sprintf("Season %d", season),
+				"overview":      "",
+				"poster_path":   "",
+				"season_number": season,
+				"air_date":      firstAir,
+				"episodes":      episodes,
+			}
+		}
+	}
+
+	return fixtures, search
+}
+
+// writeTMDbFixtures writes the responses as files laid out by API path, e.g.
+// movie/9000001.json answers GET /3/movie/9000001, for serve-tmdb
+func writeTMDbFixtures(dir string, fixtures map[string]any, search []map[string]any) error {
+	for name, body := range fixtures {
+		if err := writeFixture(dir, name, body); err != nil {
+			return err
+		}
+	}
+	return writeFixture(dir, "search/multi", search)
+}
+
+// writeTMDbCassette writes the same responses as a cassette for mock
+// provider mode (VIEWRA_MOCK_PROVIDERS=replay with VIEWRA_PROVIDER_CASSETTES
+// pointing at dir), with one search interaction per title
+func writeTMDbCassette(dir string, fixtures map[string]any, search []map[string]any) error {
+	c := cassette.Cassette{Provider: "tmdb"}
+	for _, result := range search {
+		title, _ := result["title"].(string)
+		kind := "movie"
+		if title == "" {
+			title, _ = result["name"].(string)
+			kind = "tv"
+		}
+		body, err := json.Marshal(map[string]any{
+			"page":          1,
+			"results":       []map[string]any{result},
+			"total_pages":   1,
+			"total_results": 1,
+		})
+		if err != nil {
+			return err
+		}
+		for _, endpoint := range []string{"multi", kind} {
+			c.Interactions = append(c.Interactions, cassette.Interaction{
+				Request: cassette.Request{
+					Host:  tmdbHost,
+					Path:  "/3/search/" + endpoint,
+					Query: map[string]string{"query": title},
+				},
+				Response: cassette.Response{Body: body},
+			})
+		}
+	}
+
+	names := make([]string, 0, len(fixtures))
+	for name := range fixtures {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		body, err := json.Marshal(fixtures[name])
+		if err != nil {
+			return err
+		}
+		c.Interactions = append(c.Interactions, cassette.Interaction{
+			Request:  cassette.Request{Host: tmdbHost, Path: "/3/" + name},
+			Response: cassette.Response{Body: body},
+		})
+	}
+
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode TMDb cassette: %w", err)
+	}
+	return writeFile(dir, "tmdb.json", data)
+}
+
+func writeFixture(dir, name string, v any) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode fixture %s: %w", name, err)
+	}
+	return writeFile(dir, name+".json", data)
+}
+
+func genres(names []string) []map[string]any {
+	out := make([]map[string]any, 0, len(names))
+	for _, name := range names {
+		out = append(out, map[string]any{"id": tmdbGenreIDs[name], "name": name})
+	}
+	return out
+}
+
+func genreIDs(names []string) []int {
+	ids := make([]int, 0, len(names))
+	for _, name := range names {
+		ids = append(ids, tmdbGenreIDs[name])
+	}
+	return ids
+}
+
+func images(id int, kind string) map[string]any {
+	image := func(suffix string, w, h int) []map[string]any {
+		return []map[string]any{{
+			"file_path":    fmt.Sprintf("/seed/%s-%d-%s.jpg", kind, id, suffix),
+			"width":        w,
+			"height":       h,
+			"aspect_ratio": float64(w) / float64(h),
+			"vote_average": 5.0,
+			"vote_count":   1,
+			"iso_639_1":    "en",
+		}}
+	}
+	return map[string]any{
+		"id":        id,
+		"posters":   image("poster", 500, 750),
+		"backdrops": image("backdrop", 1280, 720),
+		"logos":     []map[string]any{},
+	}
+}
+
+// tmdbServer answers TMDb v3 API requests from a fixture directory written by
+// writeTMDbFixtures. Search endpoints filter search/multi.json by the query;
+// everything else maps the request path straight onto a fixture file.
+type tmdbServer struct {
+	dir    string
+	logger *log.Logger
+}
+
+func (s *tmdbServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.logger.Printf("%s %s", r.Method, r.URL.Path)
+
+	if r.Method != http.MethodGet {
+		writeTMDbError(w, http.StatusMethodNotAllowed, 3, "Method not allowed.")
+		return
+	}
+	rest, ok := strings.CutPrefix(path.Clean(r.URL.Path), "/3/")
+	if !ok {
+		writeTMDbError(w, http.StatusNotFound, 34, "The resource you requested could not be found.")
+		return
+	}
+
+	if kind, ok := strings.CutPrefix(rest, "search/"); ok {
+		s.search(w, r, kind)
+		return
+	}
+
+	data, err := os.ReadFile(filepath.Join(s.dir, filepath.FromSlash(rest)+".json"))
+	if err != nil {
+		writeTMDbError(w, http.StatusNotFound, 34, "The resource you requested could not be found.")
+		return
+	}
+	w.Header().Set("Content-Type", "application/json;charset=utf-8")
+	w.Write(data)
+}
+
+// search matches the query case-insensitively against titles, honouring the
+// year filters the enricher sends
+func (s *tmdbServer) search(w http.ResponseWriter, r *http.Request, kind string) {
+	data, err := os.ReadFile(filepath.Join(s.dir, "search", "multi.json"))
+	if err != nil {
+		writeTMDbError(w, http.StatusInternalServerError, 11, "Search fixtures are missing; run seed generate first.")
+		return
+	}
+	var all []map[string]any
+	if err := json.Unmarshal(data, &all); err != nil {
+		writeTMDbError(w, http.StatusInternalServerError, 11, "Search fixtures are invalid: "+err.Error())
+		return
+	}
+
+	query := strings.ToLower(strings.TrimSpace(r.URL.Query().Get("query")))
+	year := r.URL.Query().Get("year")
+	if year == "" {
+		year = r.URL.Query().Get("first_air_date_year")
+	}
+
+	results := []map[string]any{}
+	for _, result := range all {
+		mediaType, _ := result["media_type"].(string)
+		if kind != "multi" && kind != mediaType {
+			continue
+		}
+		title, _ := result["title"].(string)
+		if title == "" {
+			title, _ = result["name"].(string)
+		}
+		if query == "" || !strings.Contains(strings.ToLower(title), query) {
+			continue
+		}
+		if year != "" {
+			date, _ := result["release_date"].(string)
+			if date == "" {
+				date, _ = result["first_air_date"].(string)
+			}
+			if !strings.HasPrefix(date, year) {
+				continue
+			}
+		}
+		results = append(results, result)
+	}
+
+	w.Header().Set("Content-Type", "application/json;charset=utf-8")
+	json.NewEncoder(w).Encode(map[string]any{
+		"page":          1,
+		"results":       results,
+		"total_pages":   1,
+		"total_results": len(results),
+	})
+}
+
+func writeTMDbError(w http.ResponseWriter, status, code int, message string) {
+	w.Header().Set("Content-Type", "application/json;charset=utf-8")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]any{
+		"success":        false,
+		"status_code":    code,
+		"status_message": message,
+	})
+}
+
+// tmdbFixtureDir returns where generate writes TMDb fixtures under root
+func tmdbFixtureDir(root string) string {
+	return filepath.Join(root, "fixtures", "tmdb")
+}
+
+// cassetteDir returns where generate writes provider cassettes under root
+func cassetteDir(root string) string {
+	return filepath.Join(root, "fixtures", "cassettes")
+}
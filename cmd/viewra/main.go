@@ -17,10 +17,12 @@ import (
 	// Force module inclusion by importing directly in main
 	"github.com/mantonx/viewra/internal/modules/assetmodule"
 	_ "github.com/mantonx/viewra/internal/modules/databasemodule"
+	_ "github.com/mantonx/viewra/internal/modules/diskhealthmodule"
 	_ "github.com/mantonx/viewra/internal/modules/eventsmodule"
 	_ "github.com/mantonx/viewra/internal/modules/mediamodule"
 	_ "github.com/mantonx/viewra/internal/modules/playbackmodule"
 	_ "github.com/mantonx/viewra/internal/modules/scannermodule"
+	_ "github.com/mantonx/viewra/internal/modules/subtitlemodule"
 )
 
 func main() {
@@ -1096,6 +1096,8 @@ const MediaLibraryManager = () => {
                 <option value="movie">Movies</option>
                 <option value="tv">TV Shows</option>
                 <option value="music">Music</option>
+                <option value="mixed">Mixed (Movies &amp; TV)</option>
+                <option value="home">Home Videos</option>
               </select>
               <p className="text-xs text-slate-400 mt-1">
                 💡 Library type is automatically detected based on your path (e.g., "/media/music" →
@@ -1164,7 +1166,11 @@ const MediaLibraryManager = () => {
                             ? 'bg-blue-600 text-blue-100'
                             : library.type === 'tv'
                               ? 'bg-purple-600 text-purple-100'
-                              : 'bg-green-600 text-green-100'
+                              : library.type === 'mixed'
+                                ? 'bg-amber-600 text-amber-100'
+                                : library.type === 'home'
+                                  ? 'bg-rose-600 text-rose-100'
+                                  : 'bg-green-600 text-green-100'
                         }`}
                       >
                         {library.type.toUpperCase()}
@@ -1,7 +1,7 @@
 import React, { useState, useEffect, useCallback } from 'react';
 import { useParams, useNavigate } from 'react-router-dom';
 import { ArrowLeft, Play, Calendar, Clock, Info } from 'lucide-react';
-import type { TVShow, Season, Episode } from '@/types/tv.types';
+import type { TVShow, Season, Episode, NextEpisode } from '@/types/tv.types';
 
 interface MediaFile {
   id: string;
@@ -30,6 +30,7 @@ const TVShowDetail: React.FC = () => {
   const [loading, setLoading] = useState(true);
   const [error, setError] = useState<string | null>(null);
   const [selectedSeason, setSelectedSeason] = useState<number>(1);
+  const [nextEpisode, setNextEpisode] = useState<NextEpisode | null>(null);
 
   const loadTVShowData = useCallback(async () => {
     if (!showId) return;
@@ -49,6 +50,13 @@ const TVShowDetail: React.FC = () => {
 
       setTVShow(show);
 
+      // The watch-next engine decides what the play button starts
+      const detailResponse = await fetch(`/api/media/tv-shows/${showId}`);
+      if (detailResponse.ok) {
+        const detailData = await detailResponse.json();
+        setNextEpisode(detailData.next_episode ?? null);
+      }
+
       // Get all media files to find episodes for this show
       const filesResponse = await fetch(`/api/media/files?limit=1000`);
       const filesData = await filesResponse.json();
@@ -262,6 +270,16 @@ const TVShowDetail: React.FC = () => {
                 {tvShow.description}
               </p>
             )}
+            {nextEpisode && (
+              <button
+                onClick={() => handlePlayEpisode(nextEpisode.episode_id)}
+                className="mt-3 flex items-center gap-2 px-4 py-2 bg-purple-600 hover:bg-purple-700 text-white rounded-lg transition-colors"
+              >
+                <Play className="w-4 h-4" />
+                {nextEpisode.reason === 'resume' ? 'Resume' : 'Play'}{' '}
+                {`S${nextEpisode.season_number}E${nextEpisode.episode_number}`}
+              </button>
+            )}
           </div>
         </div>
       </div>
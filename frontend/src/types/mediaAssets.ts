@@ -65,10 +65,27 @@ export interface MediaAsset {
   format: string; // Always 'image/webp' for new assets
   preferred: boolean;
   language?: string;
+  palette?: ColorPalette; // Posters, backgrounds, fanart, banners and covers
+  has_text?: boolean; // Backgrounds and fanart: embedded title text detected
+  text_score?: number; // Width of the detected text line as a share of the image (0-1)
   created_at: string;
   updated_at: string;
 }
 
+export interface ColorSwatch {
+  hex: string; // #rrggbb
+  population: number; // Share of the image, 0-1
+}
+
+export interface ColorPalette {
+  dominant: string;
+  vibrant?: string;
+  muted?: string;
+  text: string; // #000000 or #ffffff, whichever reads best on dominant
+  is_dark: boolean;
+  swatches: ColorSwatch[];
+}
+
 export interface AssetRequest {
   entity_type: EntityType;
   entity_id: string; // UUID
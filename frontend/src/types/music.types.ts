@@ -4,6 +4,7 @@ export interface TrackMetadata {
   album: string;
   artist: string;
   album_artist: string;
+  disc_number: number;
   track_number: number;
   duration: number; // in nanoseconds
   lyrics?: string;
@@ -26,9 +27,17 @@ export interface MusicFile {
   audio_codec?: string;
   channels?: string;
   sample_rate?: number;
+  bit_depth?: number; // Lossless files only
+  lossless?: boolean;
+  badge?: AudioQualityBadge;
   resolution?: string;
   duration?: number;
   bitrate_kbps?: number;
+  encoder_delay?: number; // Samples to trim for gapless playback
+  encoder_padding?: number;
+  replay_gain?: number; // dB
+  replay_gain_peak?: number;
+  album_gain?: number; // dB
   language?: string;
   version_name?: string;
   media_id?: string;
@@ -48,6 +57,28 @@ export interface Album {
   tracks: Track[];
   artwork?: string;
   year?: number;
+  musicbrainz_id?: string;
+  release_type?: string;
+  release_status?: string;
+  country?: string;
+  barcode?: string;
+  labels?: AlbumLabel[];
+  disc_count?: number;
+  is_box_set?: boolean;
+  parent_album_id?: string;
+  box_set_position?: number;
+}
+
+export interface AlbumDisc {
+  disc_number: number;
+  tracks: Track[];
+}
+
+export interface AlbumLabel {
+  position: number;
+  name: string;
+  label_mbid?: string;
+  catalog_number?: string;
 }
 
 export interface SimpleAlbum {
@@ -72,11 +103,34 @@ export interface Track {
   duration: number;
   track_number: number;
   disc_number: number;
+  composer?: string;
+  work?: string;
+  work_mbid?: string;
+  movement?: string;
+  movement_number?: number;
+  movement_count?: number;
+  conductor?: string;
+  orchestra?: string;
   created_at: string;
   updated_at: string;
   album_id: string;
   artist_id: string;
   media_files: MediaFile[];
+  versions?: TrackVersion[]; // Best quality first
+}
+
+export type AudioQualityBadge = 'hi-res' | 'lossless' | 'lossy';
+
+export interface TrackVersion {
+  media_file_id: string;
+  container: string;
+  codec: string;
+  sample_rate: number;
+  bit_depth?: number;
+  bitrate_kbps: number;
+  channels?: number;
+  lossless: boolean;
+  badge?: AudioQualityBadge;
 }
 
 export interface Artist {
@@ -90,6 +144,27 @@ export interface Artist {
   updated_at: string;
   albums: Album[];
   tracks: Track[];
+  musicbrainz_id?: string;
+  type?: string;
+  begin_date?: string;
+  end_date?: string;
+  ended?: boolean;
+  disambiguation?: string;
+  relationships?: ArtistRelationship[];
+}
+
+export interface ArtistRelationship {
+  id: number;
+  artist_id: string;
+  type: string; // e.g. "member of band"
+  direction: 'forward' | 'backward';
+  target_mbid: string;
+  target_name: string;
+  target_artist_id?: string; // Set when the related artist is in the library
+  begin_date?: string;
+  end_date?: string;
+  ended: boolean;
+  attributes?: string;
 }
 
 export interface MediaFile {
@@ -109,3 +184,15 @@ export interface MediaFile {
   format: string;
   has_artwork: boolean;
 }
+
+export interface ComposerSummary {
+  name: string;
+  work_count: number;
+  track_count: number;
+}
+
+export interface ComposerWork {
+  title: string;
+  work_mbid?: string;
+  tracks: Track[];
+}
@@ -39,6 +39,24 @@ export interface Episode {
   updated_at: string;
 }
 
+// Episode a show's play button should start, from the watch-next engine
+export interface NextEpisode {
+  show_id: string;
+  episode_id: string;
+  title: string;
+  season_number: number;
+  episode_number: number;
+  media_file_id: string;
+  reason: 'start' | 'resume' | 'next';
+  resume_seconds?: number;
+  last_watched_at?: string;
+}
+
+export interface UpNextItem {
+  show: TVShow;
+  next: NextEpisode;
+}
+
 export interface TVShowFile {
   id: string;
   media_id: string; // Episode ID
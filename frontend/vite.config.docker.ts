@@ -18,6 +18,10 @@ export default defineConfig({
         changeOrigin: true,
         ws: true,
       },
+      '/admin/plugins': {
+        target: 'http://backend:8080',
+        changeOrigin: true,
+      },
     },
   },
   optimizeDeps: {
@@ -0,0 +1,204 @@
+// Package arr asks Radarr and Sonarr to search for better releases of movies
+// and episodes, and posts the same requests to a plain webhook for other
+// download managers.
+package arr
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// requestTimeout bounds each call to Radarr, Sonarr or the webhook
+const requestTimeout = 30 * time.Second
+
+// Movie identifies a movie to search for
+type Movie struct {
+	Title  string `json:"title"`
+	Year   int    `json:"year,omitempty"`
+	TmdbID string `json:"tmdb_id,omitempty"`
+	ImdbID string `json:"imdb_id,omitempty"`
+}
+
+// Episode identifies an episode to search for
+type Episode struct {
+	ShowTitle     string `json:"show_title"`
+	ShowTmdbID    string `json:"show_tmdb_id,omitempty"`
+	SeasonNumber  int    `json:"season_number"`
+	EpisodeNumber int    `json:"episode_number"`
+}
+
+// Client talks to the *arr HTTP API: /api/v3 with an X-Api-Key header
+type Client struct {
+	baseURL string
+	apiKey  string
+	http    *http.Client
+}
+
+// NewClient creates a client for a Radarr or Sonarr instance, or returns nil
+// when it isn't configured
+func NewClient(baseURL, apiKey string) *Client {
+	if baseURL == "" || apiKey == "" {
+		return nil
+	}
+	return &Client{
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		apiKey:  apiKey,
+		http:    &http.Client{Timeout: requestTimeout},
+	}
+}
+
+// SearchMovies asks Radarr to search for the movies it knows by TMDb ID. It
+// returns how many searches were queued; movies Radarr doesn't manage are
+// skipped.
+func (c *Client) SearchMovies(movies []Movie) (int, error) {
+	var movieIDs []int
+	for _, movie := range movies {
+		if movie.TmdbID == "" {
+			continue
+		}
+		var found []struct {
+			ID int `json:"id"`
+		}
+		if err := c.do(http.MethodGet, "/api/v3/movie?tmdbId="+movie.TmdbID, nil, &found); err != nil {
+			return 0, fmt.Errorf("failed to look up %s in Radarr: %w", movie.Title, err)
+		}
+		for _, radarrMovie := range found {
+			movieIDs = append(movieIDs, radarrMovie.ID)
+		}
+	}
+	if len(movieIDs) == 0 {
+		return 0, nil
+	}
+
+	command := map[string]interface{}{"name": "MoviesSearch", "movieIds": movieIDs}
+	if err := c.do(http.MethodPost, "/api/v3/command", command, nil); err != nil {
+		return 0, fmt.Errorf("failed to start Radarr search: %w", err)
+	}
+	return len(movieIDs), nil
+}
+
+// SearchEpisodes asks Sonarr to search for episodes of the series it knows,
+// matched by TMDb ID or else by title. It returns how many episode searches
+// were queued.
+func (c *Client) SearchEpisodes(episodes []Episode) (int, error) {
+	var series []struct {
+		ID     int    `json:"id"`
+		Title  string `json:"title"`
+		TmdbID int    `json:"tmdbId"`
+	}
+	if err := c.do(http.MethodGet, "/api/v3/series", nil, &series); err != nil {
+		return 0, fmt.Errorf("failed to list Sonarr series: %w", err)
+	}
+
+	seriesID := func(episode Episode) int {
+		for _, s := range series {
+			if episode.ShowTmdbID != "" && strconv.Itoa(s.TmdbID) == episode.ShowTmdbID {
+				return s.ID
+			}
+		}
+		for _, s := range series {
+			if strings.EqualFold(s.Title, episode.ShowTitle) {
+				return s.ID
+			}
+		}
+		return 0
+	}
+
+	// Sonarr searches by its own episode IDs, listed per series
+	type episodeKey struct{ season, episode int }
+	seriesEpisodes := make(map[int]map[episodeKey]int)
+	var episodeIDs []int
+	for _, episode := range episodes {
+		id := seriesID(episode)
+		if id == 0 {
+			continue
+		}
+		if _, ok := seriesEpisodes[id]; !ok {
+			var listed []struct {
+				ID            int `json:"id"`
+				SeasonNumber  int `json:"seasonNumber"`
+				EpisodeNumber int `json:"episodeNumber"`
+			}
+			if err := c.do(http.MethodGet, "/api/v3/episode?seriesId="+strconv.Itoa(id), nil, &listed); err != nil {
+				return 0, fmt.Errorf("failed to list Sonarr episodes of %s: %w", episode.ShowTitle, err)
+			}
+			seriesEpisodes[id] = make(map[episodeKey]int)
+			for _, e := range listed {
+				seriesEpisodes[id][episodeKey{e.SeasonNumber, e.EpisodeNumber}] = e.ID
+			}
+		}
+		if episodeID, ok := seriesEpisodes[id][episodeKey{episode.SeasonNumber, episode.EpisodeNumber}]; ok {
+			episodeIDs = append(episodeIDs, episodeID)
+		}
+	}
+	if len(episodeIDs) == 0 {
+		return 0, nil
+	}
+
+	command := map[string]interface{}{"name": "EpisodeSearch", "episodeIds": episodeIDs}
+	if err := c.do(http.MethodPost, "/api/v3/command", command, nil); err != nil {
+		return 0, fmt.Errorf("failed to start Sonarr search: %w", err)
+	}
+	return len(episodeIDs), nil
+}
+
+func (c *Client) do(method, path string, body, result interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequest(method, c.baseURL+path, reader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("X-Api-Key", c.apiKey)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("%s %s returned %d: %s", method, path, resp.StatusCode, strings.TrimSpace(string(message)))
+	}
+	if result == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(result)
+}
+
+// PostWebhook posts a JSON payload to a webhook URL
+func PostWebhook(url string, payload interface{}) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	client := &http.Client{Timeout: requestTimeout}
+	resp, err := client.Post(url, "application/json", bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("failed to post webhook: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,141 @@
+package auth
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/mantonx/viewra/internal/database"
+	"gorm.io/gorm"
+)
+
+// Restricted reports whether a user only sees the libraries granted to them
+func Restricted(user *database.User) bool {
+	return user != nil && user.RestrictLibraries && !user.IsAdmin()
+}
+
+// DeniedLibraries selects the IDs of the libraries a user may not see: for a
+// restricted user, every library they haven't been granted. It selects
+// nothing for admins and unrestricted users.
+func DeniedLibraries(db *gorm.DB, userID uint32) *gorm.DB {
+	granted := db.Model(&database.UserLibraryAccess{}).Select("library_id").Where("user_id = ?", userID)
+	restricted := db.Model(&database.User{}).Select("id").
+		Where("id = ? AND restrict_libraries = ? AND role <> ?", userID, true, database.UserRoleAdmin)
+	return db.Model(&database.MediaLibrary{}).Select("id").
+		Where("id NOT IN (?) AND EXISTS (?)", granted, restricted)
+}
+
+// GrantedLibraries returns the IDs of the libraries granted to a user
+func GrantedLibraries(db *gorm.DB, userID uint32) ([]uint32, error) {
+	var ids []uint32
+	if err := db.Model(&database.UserLibraryAccess{}).Where("user_id = ?", userID).
+		Order("library_id").Pluck("library_id", &ids).Error; err != nil {
+		return nil, fmt.Errorf("failed to load library access: %w", err)
+	}
+	return ids, nil
+}
+
+// SetGrantedLibraries replaces the libraries granted to a user
+func SetGrantedLibraries(db *gorm.DB, userID uint32, libraryIDs []uint32) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", userID).Delete(&database.UserLibraryAccess{}).Error; err != nil {
+			return fmt.Errorf("failed to clear library access: %w", err)
+		}
+		now := time.Now()
+		for _, libraryID := range libraryIDs {
+			grant := database.UserLibraryAccess{UserID: userID, LibraryID: libraryID, GrantedAt: now}
+			if err := tx.Save(&grant).Error; err != nil {
+				return fmt.Errorf("failed to grant library %d: %w", libraryID, err)
+			}
+		}
+		return nil
+	})
+}
+
+// CanAccessLibrary reports whether a user may see a library
+func CanAccessLibrary(db *gorm.DB, user *database.User, libraryID uint32) (bool, error) {
+	if !Restricted(user) {
+		return true, nil
+	}
+	var count int64
+	if err := db.Model(&database.UserLibraryAccess{}).
+		Where("user_id = ? AND library_id = ?", user.ID, libraryID).
+		Count(&count).Error; err != nil {
+		return false, fmt.Errorf("failed to load library access: %w", err)
+	}
+	return count > 0, nil
+}
+
+// CanAccessItem reports whether a user may see an item: a media file, a
+// movie, episode, track or home video, or a show, season, album or artist.
+// An item is visible when any of its files is in a library the user may
+// see. IDs that name no library item, such as device IDs, are allowed.
+func CanAccessItem(db *gorm.DB, user *database.User, id string) (bool, error) {
+	if !Restricted(user) || id == "" {
+		return true, nil
+	}
+
+	showEpisodes := db.Table("episodes").Select("episodes.id").
+		Joins("JOIN seasons ON seasons.id = episodes.season_id").
+		Where("seasons.tv_show_id = ? OR seasons.id = ?", id, id)
+	musicTracks := db.Table("tracks").Select("id").
+		Where("album_id = ? OR artist_id = ?", id, id)
+
+	var libraryIDs []uint32
+	if err := db.Model(&database.MediaFile{}).Distinct("library_id").
+		Where("id = ? OR media_id = ? OR media_id IN (?) OR media_id IN (?)", id, id, showEpisodes, musicTracks).
+		Pluck("library_id", &libraryIDs).Error; err != nil {
+		return false, fmt.Errorf("failed to load item libraries: %w", err)
+	}
+	if len(libraryIDs) == 0 {
+		return true, nil
+	}
+	return anyGranted(db, user, libraryIDs)
+}
+
+// CanAccessPath reports whether a user may play a file by path. Restricted
+// users may only play files in libraries they may see.
+func CanAccessPath(db *gorm.DB, user *database.User, path string) (bool, error) {
+	if !Restricted(user) {
+		return true, nil
+	}
+	var libraryIDs []uint32
+	if err := db.Model(&database.MediaFile{}).Where("path = ?", path).
+		Pluck("library_id", &libraryIDs).Error; err != nil {
+		return false, fmt.Errorf("failed to load media file: %w", err)
+	}
+	if len(libraryIDs) == 0 {
+		return false, nil
+	}
+	return anyGranted(db, user, libraryIDs)
+}
+
+// CanAccessTranscodeSession reports whether a user may stream or control a
+// transcode session, judged by the file it transcodes
+func CanAccessTranscodeSession(db *gorm.DB, user *database.User, sessionID string) (bool, error) {
+	if !Restricted(user) {
+		return true, nil
+	}
+	var session database.TranscodeSession
+	if err := db.Select("id, request").Where("id = ?", sessionID).Limit(1).Find(&session).Error; err != nil {
+		return false, fmt.Errorf("failed to load transcode session: %w", err)
+	}
+	if session.ID == "" {
+		// Unknown sessions are reported as such by the handlers
+		return true, nil
+	}
+	request, err := session.GetRequest()
+	if err != nil || request == nil {
+		return false, nil
+	}
+	return CanAccessPath(db, user, request.InputPath)
+}
+
+func anyGranted(db *gorm.DB, user *database.User, libraryIDs []uint32) (bool, error) {
+	var count int64
+	if err := db.Model(&database.UserLibraryAccess{}).
+		Where("user_id = ? AND library_id IN ?", user.ID, libraryIDs).
+		Count(&count).Error; err != nil {
+		return false, fmt.Errorf("failed to load library access: %w", err)
+	}
+	return count > 0, nil
+}
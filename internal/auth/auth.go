@@ -0,0 +1,308 @@
+// Package auth signs users in and decides what they may see. Users log in
+// with a password and get a bearer token: an HS256 JWT signed with the
+// configured secret, naming the user and the login session it belongs to,
+// so logging out revokes it. Admins see every library; other users can be
+// restricted to the libraries granted to them.
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mantonx/viewra/internal/config"
+	"github.com/mantonx/viewra/internal/database"
+	"github.com/mantonx/viewra/internal/utils"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+const (
+	// defaultTokenTTL applies when no positive token lifetime is configured
+	defaultTokenTTL = 24 * time.Hour
+
+	// lastSeenInterval limits how often a session's last use is recorded
+	lastSeenInterval = time.Minute
+
+	// MinPasswordLength is the shortest password accepted
+	MinPasswordLength = 8
+)
+
+// Context keys set for authenticated requests
+const (
+	userContextKey    = "auth_user"
+	sessionContextKey = "auth_session"
+)
+
+// Reasons a login or token is refused
+var (
+	ErrInvalidCredentials = errors.New("invalid username or password")
+	ErrNoSecret           = errors.New("token signing secret is not configured")
+	ErrMalformed          = errors.New("malformed token")
+	ErrSignature          = errors.New("invalid token signature")
+	ErrExpired            = errors.New("token has expired")
+	ErrSessionEnded       = errors.New("session has ended")
+)
+
+// Grant is what a successful login hands back
+type Grant struct {
+	Token     string         `json:"token"`
+	ExpiresAt time.Time      `json:"expires_at"`
+	User      *database.User `json:"user"`
+}
+
+// claims are the JWT claims of a token
+type claims struct {
+	Subject   string `json:"sub"`
+	SessionID string `json:"sid"`
+	IssuedAt  int64  `json:"iat"`
+	ExpiresAt int64  `json:"exp"`
+}
+
+// Enabled reports whether API requests must be authenticated
+func Enabled() bool {
+	return config.Get().Security.EnableAuthentication
+}
+
+// HashPassword hashes a password for storage
+func HashPassword(password string) (string, error) {
+	if len(password) < MinPasswordLength {
+		return "", fmt.Errorf("password must be at least %d characters", MinPasswordLength)
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", fmt.Errorf("failed to hash password: %w", err)
+	}
+	return string(hash), nil
+}
+
+// Login checks a user's password and starts a session for them
+func Login(db *gorm.DB, username, password, userAgent, clientIP string) (*Grant, error) {
+	var user database.User
+	if err := db.Where("username = ?", username).First(&user).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrInvalidCredentials
+		}
+		return nil, fmt.Errorf("failed to load user: %w", err)
+	}
+	if !CheckPassword(&user, password) {
+		return nil, ErrInvalidCredentials
+	}
+	return StartSession(db, &user, userAgent, clientIP)
+}
+
+// CheckPassword reports whether a password is the user's
+func CheckPassword(user *database.User, password string) bool {
+	return bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password)) == nil
+}
+
+// StartSession signs a user in without a password check, as when the first
+// admin is created
+func StartSession(db *gorm.DB, user *database.User, userAgent, clientIP string) (*Grant, error) {
+	security := config.Get().Security
+	if security.JWTSecret == "" {
+		return nil, ErrNoSecret
+	}
+	ttl := security.JWTExpiration
+	if ttl <= 0 {
+		ttl = defaultTokenTTL
+	}
+
+	now := time.Now()
+	session := database.AuthSession{
+		ID:         utils.GenerateUUID(),
+		UserID:     user.ID,
+		UserAgent:  userAgent,
+		ClientIP:   clientIP,
+		LastSeenAt: now,
+		ExpiresAt:  now.Add(ttl),
+	}
+	if err := db.Create(&session).Error; err != nil {
+		return nil, fmt.Errorf("failed to create session: %w", err)
+	}
+
+	token, err := sign(claims{
+		Subject:   strconv.FormatUint(uint64(user.ID), 10),
+		SessionID: session.ID,
+		IssuedAt:  now.Unix(),
+		ExpiresAt: session.ExpiresAt.Unix(),
+	}, security.JWTSecret)
+	if err != nil {
+		return nil, err
+	}
+	return &Grant{Token: token, ExpiresAt: session.ExpiresAt, User: user}, nil
+}
+
+// Authenticate verifies a bearer token and returns the user and session it
+// was issued for
+func Authenticate(db *gorm.DB, token string, now time.Time) (*database.User, *database.AuthSession, error) {
+	c, err := verify(token, config.Get().Security.JWTSecret, now)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var session database.AuthSession
+	err = db.Where("id = ? AND user_id = ?", c.SessionID, c.Subject).First(&session).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil, ErrSessionEnded
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load session: %w", err)
+	}
+	if !now.Before(session.ExpiresAt) {
+		return nil, nil, ErrExpired
+	}
+
+	var user database.User
+	err = db.First(&user, session.UserID).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil, ErrSessionEnded
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load user: %w", err)
+	}
+
+	if now.Sub(session.LastSeenAt) >= lastSeenInterval {
+		session.LastSeenAt = now
+		db.Model(&session).Update("last_seen_at", now)
+	}
+	return &user, &session, nil
+}
+
+// Logout ends a session, revoking its token
+func Logout(db *gorm.DB, sessionID string) error {
+	if err := db.Where("id = ?", sessionID).Delete(&database.AuthSession{}).Error; err != nil {
+		return fmt.Errorf("failed to end session: %w", err)
+	}
+	return nil
+}
+
+// EndUserSessions signs a user out everywhere, as when their password
+// changes or they are removed
+func EndUserSessions(db *gorm.DB, userID uint32) error {
+	if err := db.Where("user_id = ?", userID).Delete(&database.AuthSession{}).Error; err != nil {
+		return fmt.Errorf("failed to end sessions: %w", err)
+	}
+	return nil
+}
+
+// RequestToken returns the bearer token of a request, from the
+// Authorization header or, for players and image tags that can't set
+// headers, the access_token query parameter
+func RequestToken(c *gin.Context) string {
+	if token, found := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer "); found {
+		return strings.TrimSpace(token)
+	}
+	return c.Request.URL.Query().Get("access_token")
+}
+
+// SetUser records the authenticated user and session of a request
+func SetUser(c *gin.Context, user *database.User, session *database.AuthSession) {
+	c.Set(userContextKey, user)
+	c.Set(sessionContextKey, session)
+}
+
+// CurrentUser returns the authenticated user of a request
+func CurrentUser(c *gin.Context) (*database.User, bool) {
+	value, exists := c.Get(userContextKey)
+	if !exists {
+		return nil, false
+	}
+	user, ok := value.(*database.User)
+	return user, ok
+}
+
+// RequestUserID returns the ID of the authenticated user of a request, or
+// named, the user the request names itself, when it wasn't authenticated
+func RequestUserID(c *gin.Context, named uint32) uint32 {
+	if user, ok := CurrentUser(c); ok {
+		return user.ID
+	}
+	return named
+}
+
+// CurrentSession returns the session a request was authenticated with
+func CurrentSession(c *gin.Context) (*database.AuthSession, bool) {
+	value, exists := c.Get(sessionContextKey)
+	if !exists {
+		return nil, false
+	}
+	session, ok := value.(*database.AuthSession)
+	return session, ok
+}
+
+func sign(c claims, secret string) (string, error) {
+	header, err := json.Marshal(map[string]string{"alg": "HS256", "typ": "JWT"})
+	if err != nil {
+		return "", err
+	}
+	payload, err := json.Marshal(c)
+	if err != nil {
+		return "", err
+	}
+	unsigned := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+	return unsigned + "." + base64.RawURLEncoding.EncodeToString(mac(unsigned, secret)), nil
+}
+
+func verify(token, secret string, now time.Time) (*claims, error) {
+	if secret == "" {
+		return nil, ErrNoSecret
+	}
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, ErrMalformed
+	}
+
+	var header struct {
+		Alg string `json:"alg"`
+	}
+	if err := decodeSegment(parts[0], &header); err != nil {
+		return nil, err
+	}
+	if header.Alg != "HS256" {
+		return nil, fmt.Errorf("unsupported signing algorithm %q", header.Alg)
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, ErrMalformed
+	}
+	if !hmac.Equal(signature, mac(parts[0]+"."+parts[1], secret)) {
+		return nil, ErrSignature
+	}
+
+	var c claims
+	if err := decodeSegment(parts[1], &c); err != nil {
+		return nil, err
+	}
+	if c.SessionID == "" || c.Subject == "" {
+		return nil, ErrMalformed
+	}
+	if now.Unix() >= c.ExpiresAt {
+		return nil, ErrExpired
+	}
+	return &c, nil
+}
+
+func mac(data, secret string) []byte {
+	h := hmac.New(sha256.New, []byte(secret))
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
+
+func decodeSegment(segment string, v interface{}) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return ErrMalformed
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return ErrMalformed
+	}
+	return nil
+}
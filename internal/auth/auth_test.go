@@ -0,0 +1,66 @@
+package auth
+
+import (
+	"encoding/base64"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestVerify(t *testing.T) {
+	const secret = "test-secret"
+	now := time.Unix(1_700_000_000, 0)
+	valid := claims{Subject: "7", SessionID: "session", IssuedAt: now.Unix(), ExpiresAt: now.Add(time.Hour).Unix()}
+
+	signed := func(c claims, secret string) string {
+		token, err := sign(c, secret)
+		if err != nil {
+			t.Fatalf("sign() = %v", err)
+		}
+		return token
+	}
+	withHeader := func(header string) string {
+		parts := strings.Split(signed(valid, secret), ".")
+		parts[0] = base64.RawURLEncoding.EncodeToString([]byte(header))
+		unsigned := parts[0] + "." + parts[1]
+		return unsigned + "." + base64.RawURLEncoding.EncodeToString(mac(unsigned, secret))
+	}
+
+	tests := []struct {
+		name    string
+		token   string
+		secret  string
+		wantErr error
+	}{
+		{"valid", signed(valid, secret), secret, nil},
+		{"no secret", signed(valid, secret), "", ErrNoSecret},
+		{"wrong secret", signed(valid, "other"), secret, ErrSignature},
+		{"expired", signed(claims{Subject: "7", SessionID: "session", ExpiresAt: now.Unix()}, secret), secret, ErrExpired},
+		{"no expiry", signed(claims{Subject: "7", SessionID: "session"}, secret), secret, ErrExpired},
+		{"no session", signed(claims{Subject: "7", ExpiresAt: valid.ExpiresAt}, secret), secret, ErrMalformed},
+		{"no subject", signed(claims{SessionID: "session", ExpiresAt: valid.ExpiresAt}, secret), secret, ErrMalformed},
+		{"two segments", "a.b", secret, ErrMalformed},
+		{"bad signature encoding", signed(valid, secret) + "!", secret, ErrMalformed},
+		{"tampered payload", strings.Replace(signed(valid, secret), ".", ".x", 1), secret, ErrSignature},
+		{"alg none", withHeader(`{"alg":"none"}`), secret, errors.New("unsupported")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := verify(tt.token, tt.secret, now)
+			switch {
+			case tt.wantErr == nil && err != nil:
+				t.Fatalf("verify() = %v, want nil", err)
+			case tt.wantErr == nil:
+				if c.Subject != valid.Subject || c.SessionID != valid.SessionID {
+					t.Fatalf("verify() claims = %+v, want %+v", c, valid)
+				}
+			case err == nil:
+				t.Fatalf("verify() = nil, want %v", tt.wantErr)
+			case !errors.Is(err, tt.wantErr) && !strings.Contains(err.Error(), tt.wantErr.Error()):
+				t.Fatalf("verify() = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -47,6 +47,21 @@ type Config struct {
 
 	// Transcoding configuration
 	Transcoding TranscodingConfig `yaml:"transcoding" json:"transcoding"`
+
+	// Disk health monitoring configuration
+	DiskHealth DiskHealthConfig `yaml:"disk_health" json:"disk_health"`
+
+	// Quality upgrade configuration
+	Upgrades UpgradesConfig `yaml:"upgrades" json:"upgrades"`
+
+	// File organizer configuration
+	Organizer OrganizerConfig `yaml:"organizer" json:"organizer"`
+
+	// Recycle bin for deleted media files
+	RecycleBin RecycleBinConfig `yaml:"recycle_bin" json:"recycle_bin"`
+
+	// Download completion folders watched for new media
+	Downloads DownloadsConfig `yaml:"downloads" json:"downloads"`
 }
 
 // ServerConfig holds server-related configuration
@@ -89,6 +104,18 @@ type AssetConfig struct {
 	ThumbnailSizes   []int         `yaml:"thumbnail_sizes" json:"thumbnail_sizes" env:"VIEWRA_THUMBNAIL_SIZES"`
 	CacheDuration    time.Duration `yaml:"cache_duration" json:"cache_duration" env:"VIEWRA_ASSET_CACHE_DURATION" default:"24h"`
 	CleanupInterval  time.Duration `yaml:"cleanup_interval" json:"cleanup_interval" env:"VIEWRA_ASSET_CLEANUP_INTERVAL" default:"6h"`
+	OrphanRetention  time.Duration `yaml:"orphan_retention" json:"orphan_retention" env:"VIEWRA_ASSET_ORPHAN_RETENTION" default:"168h"` // How long an orphaned asset is kept before garbage collection deletes it
+	RetentionPolicy  string        `yaml:"retention_policy" json:"retention_policy" env:"VIEWRA_ASSET_RETENTION_POLICY" default:"all"`    // Which artwork of each type to keep: all, best or preferred
+
+	// Host download pool plugins fetch artwork through
+	DownloadConcurrency int           `yaml:"download_concurrency" json:"download_concurrency" env:"VIEWRA_ASSET_DOWNLOAD_CONCURRENCY" default:"4"`  // Downloads running at once across all plugins
+	DownloadHostDelay   time.Duration `yaml:"download_host_delay" json:"download_host_delay" env:"VIEWRA_ASSET_DOWNLOAD_HOST_DELAY" default:"250ms"` // Minimum gap between requests to the same host
+	DownloadRetries     int           `yaml:"download_retries" json:"download_retries" env:"VIEWRA_ASSET_DOWNLOAD_RETRIES" default:"3"`              // Retries after network errors, 429 and 5xx responses
+	DownloadTimeout     time.Duration `yaml:"download_timeout" json:"download_timeout" env:"VIEWRA_ASSET_DOWNLOAD_TIMEOUT" default:"30s"`            // Per attempt
+
+	// Shortest side, in pixels, of album art embedded in music files for it
+	// to be used instead of remote cover art
+	EmbeddedArtMinSize int `yaml:"embedded_art_min_size" json:"embedded_art_min_size" env:"VIEWRA_EMBEDDED_ART_MIN_SIZE" default:"500"`
 }
 
 // TranscodingConfig holds transcoding configuration
@@ -110,6 +137,27 @@ type TranscodingConfig struct {
 	ExtendedHours      int           `yaml:"extended_hours" json:"extended_hours" env:"VIEWRA_EXTENDED_RETENTION_HOURS" default:"48"`
 	LargeFileThreshold int64         `yaml:"large_file_threshold" json:"large_file_threshold" env:"VIEWRA_LARGE_FILE_MB" default:"500"` // In MB
 
+	// Loudness normalization (EBU R128)
+	LoudnessNormalization bool    `yaml:"loudness_normalization" json:"loudness_normalization" env:"VIEWRA_LOUDNESS_NORMALIZATION" default:"false"`
+	LoudnessTarget        float64 `yaml:"loudness_target" json:"loudness_target" env:"VIEWRA_LOUDNESS_TARGET" default:"-16"`        // Integrated loudness in LUFS
+	LoudnessTruePeak      float64 `yaml:"loudness_true_peak" json:"loudness_true_peak" env:"VIEWRA_LOUDNESS_TRUE_PEAK" default:"-1.5"` // Max true peak in dBTP
+	LoudnessRange         float64 `yaml:"loudness_range" json:"loudness_range" env:"VIEWRA_LOUDNESS_RANGE" default:"11"`            // Loudness range in LU
+
+	// Fast start: the first 30s of DASH/HLS output is cut into 1s segments
+	// so players can start after the first few seconds are encoded
+	FastStart bool `yaml:"fast_start" json:"fast_start" env:"VIEWRA_TRANSCODE_FAST_START" default:"true"`
+
+	// Transcode cache: viewers of the same item at the same settings share
+	// one transcode; sessions reused often are kept for ExtendedHours
+	TranscodeCache   bool `yaml:"transcode_cache" json:"transcode_cache" env:"VIEWRA_TRANSCODE_CACHE" default:"true"`
+	CachePopularHits int  `yaml:"cache_popular_hits" json:"cache_popular_hits" env:"VIEWRA_TRANSCODE_CACHE_POPULAR_HITS" default:"3"`
+
+	// Signed stream URLs: manifests and segments are served only through
+	// expiring URLs signed for the session and user
+	SignedURLs    bool          `yaml:"signed_urls" json:"signed_urls" env:"VIEWRA_SIGNED_STREAM_URLS" default:"true"`
+	SignedURLTTL  time.Duration `yaml:"signed_url_ttl" json:"signed_url_ttl" env:"VIEWRA_SIGNED_URL_TTL" default:"6h"`
+	URLSigningKey string        `yaml:"url_signing_key" json:"-" env:"VIEWRA_STREAM_SIGNING_KEY"` // Random per process when empty
+
 	// Legacy field for backwards compatibility (will be removed)
 	FFmpegPath string `yaml:"ffmpeg_path" json:"ffmpeg_path" env:"VIEWRA_FFMPEG_PATH" default:"ffmpeg"`
 }
@@ -127,6 +175,10 @@ type ScannerConfig struct {
 	AutoScanEnabled   bool          `yaml:"auto_scan_enabled" json:"auto_scan_enabled" env:"VIEWRA_AUTO_SCAN" default:"false"`
 	IgnorePatterns    []string      `yaml:"ignore_patterns" json:"ignore_patterns" env:"VIEWRA_IGNORE_PATTERNS"`
 	MaxFileSize       int64         `yaml:"max_file_size" json:"max_file_size" env:"VIEWRA_MAX_SCAN_FILE_SIZE" default:"10737418240"`
+
+	// Profiling times every scan's stages, metadata handlers and hooks, and
+	// adds the timings to the scan report
+	Profiling bool `yaml:"profiling" json:"profiling" env:"VIEWRA_SCAN_PROFILING" default:"false"`
 }
 
 // PluginConfig holds plugin system configuration
@@ -142,6 +194,29 @@ type PluginConfig struct {
 	AllowNetworkAccess   bool                  `yaml:"allow_network_access" json:"allow_network_access" env:"VIEWRA_PLUGIN_NETWORK" default:"true"`
 	AllowFileSystemWrite bool                  `yaml:"allow_filesystem_write" json:"allow_filesystem_write" env:"VIEWRA_PLUGIN_FS_WRITE" default:"false"`
 	HotReload            PluginHotReloadConfig `yaml:"hot_reload" json:"hot_reload"`
+
+	// GRPCMaxMessageSize caps messages in bytes on the host services gRPC
+	// server plugins save artwork through. It is reported to plugins during
+	// the handshake so they size their own limits to it.
+	GRPCMaxMessageSize int `yaml:"grpc_max_message_size" json:"grpc_max_message_size" env:"VIEWRA_PLUGIN_GRPC_MAX_MESSAGE_SIZE" default:"16777216"`
+
+	// MockProviders answers TMDb, MusicBrainz and AudioDB requests from
+	// recorded cassettes for offline development: off (default), replay or
+	// record. ProviderCassetteDir adds cassettes on top of the bundled ones.
+	MockProviders       string `yaml:"mock_providers" json:"mock_providers" env:"VIEWRA_MOCK_PROVIDERS"`
+	ProviderCassetteDir string `yaml:"provider_cassette_dir" json:"provider_cassette_dir" env:"VIEWRA_PROVIDER_CASSETTES"`
+
+	// ProviderChains orders the enrichment plugins tried for a media type
+	// (movie, episode, track, ...). Plugins of a chain get a scanned file
+	// one at a time, the next only when the previous failed or matched
+	// below the chain's confidence; other plugins get it as usual.
+	ProviderChains map[string]ProviderChain `yaml:"provider_chains" json:"provider_chains"`
+}
+
+// ProviderChain is an ordered list of enrichment plugins tried in turn
+type ProviderChain struct {
+	Providers     []string `yaml:"providers" json:"providers"`           // Plugin IDs, first tried first
+	MinConfidence float64  `yaml:"min_confidence" json:"min_confidence"` // Matches below this (0-1) fall through to the next provider, 0 leaves it to each plugin
 }
 
 // PluginHotReloadConfig configures hot reload behavior
@@ -226,9 +301,66 @@ type PerformanceConfig struct {
 	MaxProcs                 int     `yaml:"max_procs" json:"max_procs" env:"GOMAXPROCS" default:"0"`
 	MemoryThreshold          float64 `yaml:"memory_threshold" json:"memory_threshold" env:"VIEWRA_MEMORY_THRESHOLD" default:"85.0"`
 	CPUThreshold             float64 `yaml:"cpu_threshold" json:"cpu_threshold" env:"VIEWRA_CPU_THRESHOLD" default:"80.0"`
+	DiskThreshold            float64 `yaml:"disk_threshold" json:"disk_threshold" env:"VIEWRA_DISK_THRESHOLD" default:"90.0"`
 	EnableAdaptiveThrottling bool    `yaml:"enable_adaptive_throttling" json:"enable_adaptive_throttling" env:"VIEWRA_ADAPTIVE_THROTTLING" default:"true"`
 }
 
+// DiskHealthConfig holds S.M.A.R.T. monitoring of the disks backing libraries
+type DiskHealthConfig struct {
+	Enabled            bool          `yaml:"enabled" json:"enabled" env:"VIEWRA_DISK_HEALTH_ENABLED" default:"true"`
+	SmartctlPath       string        `yaml:"smartctl_path" json:"smartctl_path" env:"VIEWRA_SMARTCTL_PATH" default:"smartctl"`
+	CheckInterval      time.Duration `yaml:"check_interval" json:"check_interval" env:"VIEWRA_DISK_HEALTH_INTERVAL" default:"1h"`
+	TemperatureWarning int           `yaml:"temperature_warning" json:"temperature_warning" env:"VIEWRA_DISK_TEMPERATURE_WARNING" default:"55"` // Celsius
+}
+
+// OrganizerConfig holds how media files are renamed into a canonical
+// structure. Templates are relative to the library, or to DestinationRoot
+// when files are hardlinked or copied instead of moved; the file extension
+// is added to them.
+type OrganizerConfig struct {
+	AutoOrganize    bool   `yaml:"auto_organize" json:"auto_organize" env:"VIEWRA_ORGANIZER_AUTO" default:"false"` // Organize files as soon as they are enriched
+	Mode            string `yaml:"mode" json:"mode" env:"VIEWRA_ORGANIZER_MODE" default:"move"`                     // move, hardlink or copy
+	DestinationRoot string `yaml:"destination_root" json:"destination_root" env:"VIEWRA_ORGANIZER_DESTINATION"`     // Required by hardlink and copy, outside the libraries
+	MovieTemplate   string `yaml:"movie_template" json:"movie_template" env:"VIEWRA_ORGANIZER_MOVIE_TEMPLATE" default:"{Title} ({Year})/{Title} ({Year})"`
+	EpisodeTemplate string `yaml:"episode_template" json:"episode_template" env:"VIEWRA_ORGANIZER_EPISODE_TEMPLATE" default:"{Show} ({Year})/Season {S}/{Show} - S{S}E{E} - {Title}"`
+}
+
+// RecycleBinConfig holds where deleted media files are kept and for how
+// long they can be restored. The directory must be outside the libraries.
+type RecycleBinConfig struct {
+	Directory string        `yaml:"directory" json:"directory" env:"VIEWRA_RECYCLE_DIR"` // Defaults to recycle under the data directory
+	Retention time.Duration `yaml:"retention" json:"retention" env:"VIEWRA_RECYCLE_RETENTION" default:"720h"`
+}
+
+// DownloadsConfig holds the folders torrent and Usenet clients put completed
+// downloads in. A download is imported once its files have stopped changing
+// for StableFor, and only its files are scanned.
+type DownloadsConfig struct {
+	PollInterval time.Duration    `yaml:"poll_interval" json:"poll_interval" env:"VIEWRA_DOWNLOADS_POLL_INTERVAL" default:"30s"`
+	StableFor    time.Duration    `yaml:"stable_for" json:"stable_for" env:"VIEWRA_DOWNLOADS_STABLE_FOR" default:"1m"`
+	Folders      []DownloadFolder `yaml:"folders" json:"folders"`
+}
+
+// DownloadFolder is a completion folder and the library its downloads go to
+type DownloadFolder struct {
+	Path      string `yaml:"path" json:"path"`
+	LibraryID uint32 `yaml:"library_id" json:"library_id"`
+	Import    string `yaml:"import" json:"import"`     // move, hardlink or copy into the library; empty scans downloads where they are, for folders inside the library
+	Organize  bool   `yaml:"organize" json:"organize"` // Run the organizer on the new files once they are scanned
+}
+
+// UpgradesConfig holds where items below their library's quality target are
+// sent to be replaced by better releases
+type UpgradesConfig struct {
+	CheckInterval time.Duration `yaml:"check_interval" json:"check_interval" env:"VIEWRA_UPGRADE_CHECK_INTERVAL" default:"24h"`
+	AutoPush      bool          `yaml:"auto_push" json:"auto_push" env:"VIEWRA_UPGRADE_AUTO_PUSH" default:"false"` // Push newly found upgrades after each check
+	RadarrURL     string        `yaml:"radarr_url" json:"radarr_url" env:"VIEWRA_RADARR_URL"`
+	RadarrAPIKey  string        `yaml:"radarr_api_key" json:"-" env:"VIEWRA_RADARR_API_KEY"`
+	SonarrURL     string        `yaml:"sonarr_url" json:"sonarr_url" env:"VIEWRA_SONARR_URL"`
+	SonarrAPIKey  string        `yaml:"sonarr_api_key" json:"-" env:"VIEWRA_SONARR_API_KEY"`
+	WebhookURL    string        `yaml:"webhook_url" json:"webhook_url" env:"VIEWRA_UPGRADE_WEBHOOK_URL"`
+}
+
 // ConfigManager manages application configuration with hot-reload support
 type ConfigManager struct {
 	config     *Config
@@ -291,6 +423,15 @@ func DefaultConfig() *Config {
 			ThumbnailSizes:   []int{150, 300, 600},
 			CacheDuration:    24 * time.Hour,
 			CleanupInterval:  6 * time.Hour,
+			OrphanRetention:  7 * 24 * time.Hour,
+			RetentionPolicy:  "all",
+
+			DownloadConcurrency: 4,
+			DownloadHostDelay:   250 * time.Millisecond,
+			DownloadRetries:     3,
+			DownloadTimeout:     30 * time.Second,
+
+			EmbeddedArtMinSize: 500,
 		},
 		Scanner: ScannerConfig{
 			ParallelScanning:  true,
@@ -316,6 +457,7 @@ func DefaultConfig() *Config {
 			MemoryLimit:          512 * 1024 * 1024, // 512MB
 			AllowNetworkAccess:   true,
 			AllowFileSystemWrite: false,
+			GRPCMaxMessageSize:   16 * 1024 * 1024, // 16MB, artwork exceeds gRPC's 4MB default
 			HotReload: PluginHotReloadConfig{
 				Enabled:         getEnvBool("VIEWRA_HOT_RELOAD_ENABLED", true), // Force enable by default
 				DebounceDelayMs: getEnvInt("VIEWRA_HOT_RELOAD_DEBOUNCE_MS", 500),
@@ -424,6 +566,7 @@ func DefaultConfig() *Config {
 			MaxProcs:                 0, // Auto-detect
 			MemoryThreshold:          85.0,
 			CPUThreshold:             80.0,
+			DiskThreshold:            90.0,
 			EnableAdaptiveThrottling: true,
 		},
 		Transcoding: TranscodingConfig{
@@ -437,6 +580,40 @@ func DefaultConfig() *Config {
 			ExtendedHours:      48,
 			LargeFileThreshold: 500, // MB
 			FFmpegPath:         "ffmpeg",
+
+			LoudnessNormalization: false,
+			LoudnessTarget:        -16,
+			LoudnessTruePeak:      -1.5,
+			LoudnessRange:         11,
+
+			FastStart: true,
+
+			TranscodeCache:   true,
+			CachePopularHits: 3,
+
+			SignedURLs:   true,
+			SignedURLTTL: 6 * time.Hour,
+		},
+		DiskHealth: DiskHealthConfig{
+			Enabled:            true,
+			SmartctlPath:       "smartctl",
+			CheckInterval:      time.Hour,
+			TemperatureWarning: 55,
+		},
+		Upgrades: UpgradesConfig{
+			CheckInterval: 24 * time.Hour,
+		},
+		Organizer: OrganizerConfig{
+			Mode:            "move",
+			MovieTemplate:   "{Title} ({Year})/{Title} ({Year})",
+			EpisodeTemplate: "{Show} ({Year})/Season {S}/{Show} - S{S}E{E} - {Title}",
+		},
+		RecycleBin: RecycleBinConfig{
+			Retention: 30 * 24 * time.Hour,
+		},
+		Downloads: DownloadsConfig{
+			PollInterval: 30 * time.Second,
+			StableFor:    time.Minute,
 		},
 	}
 }
@@ -683,6 +860,24 @@ func (cm *ConfigManager) validateConfig(config *Config) error {
 		return fmt.Errorf("invalid max file size: %d", config.Assets.MaxFileSize)
 	}
 
+	if config.Plugins.GRPCMaxMessageSize <= 0 {
+		return fmt.Errorf("invalid plugin gRPC max message size: %d", config.Plugins.GRPCMaxMessageSize)
+	}
+
+	if config.Assets.DownloadConcurrency < 0 || config.Assets.DownloadRetries < 0 {
+		return fmt.Errorf("invalid asset download settings: concurrency %d, retries %d", config.Assets.DownloadConcurrency, config.Assets.DownloadRetries)
+	}
+
+	if config.Assets.EmbeddedArtMinSize < 0 {
+		return fmt.Errorf("invalid embedded art minimum size: %d", config.Assets.EmbeddedArtMinSize)
+	}
+
+	switch config.Assets.RetentionPolicy {
+	case "", "all", "best", "preferred":
+	default:
+		return fmt.Errorf("invalid asset retention policy: %s", config.Assets.RetentionPolicy)
+	}
+
 	return nil
 }
 
@@ -697,6 +892,11 @@ func (cm *ConfigManager) applyDerivedConfig(config *Config) {
 		config.Assets.DataDir = filepath.Join(config.Database.DataDir, "assets")
 	}
 
+	// Keep deleted files next to the other data unless told otherwise
+	if config.RecycleBin.Directory == "" {
+		config.RecycleBin.Directory = filepath.Join(config.Database.DataDir, "recycle")
+	}
+
 	// Auto-detect worker count if not set
 	if config.Scanner.WorkerCount == 0 {
 		// Use number of CPU cores, with reasonable limits
@@ -81,14 +81,17 @@ func Initialize() {
 
 	// Auto-migrate the schema
 	err = DB.AutoMigrate(
-		&User{}, &MediaLibrary{}, &ScanJob{},
+		&User{}, &FeedToken{}, &UserLibraryAccess{}, &UserParentalControl{}, &AuthSession{}, &MediaLibrary{}, &LibraryEnrichmentProvider{}, &LibraryArtworkSettings{}, &LibraryScanSchedule{}, &LibraryNamingRule{}, &LibraryStorageSample{}, &LibraryQualityTarget{}, &UpgradeWanted{}, &ScanJob{},
+		// Per-user browse preferences and server announcements
+		&UserHiddenItem{}, &UserHiddenLibrary{}, &UserFavorite{}, &Announcement{}, &AnnouncementDismissal{},
 		// New comprehensive metadata models
 		&MediaFile{}, &MediaAsset{}, &People{}, &Roles{},
-		&Artist{}, &Album{}, &Track{},
-		&Movie{}, &TVShow{}, &Season{}, &Episode{},
-		&MediaExternalIDs{}, &MediaEnrichment{},
+		&Artist{}, &ArtistRelationship{}, &Album{}, &AlbumLabel{}, &Track{},
+		&Movie{}, &TVShow{}, &Season{}, &Episode{}, &HomeVideo{}, &Collection{}, &CollectionItem{},
+		&MediaExternalIDs{}, &MediaEnrichment{}, &MediaFieldProvenance{},
+		&MediaEnrichmentSnapshot{}, &MediaEnrichmentSnapshotAsset{},
 		// Plugin system tables
-		&Plugin{}, &PluginPermission{}, &PluginEvent{}, &PluginHook{}, &PluginAdminPage{}, &PluginUIComponent{},
+		&Plugin{}, &PluginPermission{}, &PluginEvent{}, &PluginHook{}, &PluginHookResult{}, &PluginAdminPage{}, &PluginUIComponent{},
 		// Event system tables
 		&SystemEvent{},
 	)
@@ -2,25 +2,395 @@ package database
 
 import (
 	"database/sql/driver"
+	"encoding/json"
 	"fmt"
 	"time"
 )
 
+// User roles
+const (
+	UserRoleAdmin = "admin" // Manages the server and sees every library
+	UserRoleUser  = "user"  // Sees the libraries they are allowed
+)
+
 // User represents a user in the system
 type User struct {
-	ID        uint32    `gorm:"primaryKey" json:"id"`
-	Username  string    `gorm:"uniqueIndex;not null" json:"username"`
-	Email     string    `gorm:"uniqueIndex;not null" json:"email"`
-	Password  string    `gorm:"not null" json:"-"` // Don't include password in JSON responses
-	CreatedAt time.Time `json:"created_at"`
+	ID                uint32    `gorm:"primaryKey" json:"id"`
+	Username          string    `gorm:"uniqueIndex;not null" json:"username"`
+	Email             string    `gorm:"uniqueIndex;not null" json:"email"`
+	Password          string    `gorm:"not null" json:"-"`                                // bcrypt hash; never included in JSON responses
+	Role              string    `gorm:"not null;default:user" json:"role"`                // admin or user
+	RestrictLibraries bool      `gorm:"not null;default:false" json:"restrict_libraries"` // Only libraries granted in UserLibraryAccess are visible
+	CreatedAt         time.Time `json:"created_at"`
+	UpdatedAt         time.Time `json:"updated_at"`
+}
+
+// IsAdmin reports whether the user manages the server
+func (u *User) IsAdmin() bool {
+	return u.Role == UserRoleAdmin
+}
+
+// UserLibraryAccess grants a user whose libraries are restricted access to a
+// library. Unlike UserHiddenLibrary it is a permission: libraries a
+// restricted user hasn't been granted can't be browsed, opened or streamed.
+type UserLibraryAccess struct {
+	UserID    uint32    `gorm:"primaryKey" json:"user_id"`
+	LibraryID uint32    `gorm:"primaryKey" json:"library_id"`
+	GrantedAt time.Time `gorm:"not null" json:"granted_at"`
+}
+
+// UserParentalControl keeps a user to content rated for their age. Library
+// lists leave out movies and shows rated above MaxRating; entering the PIN
+// lifts the limit until OverrideUntil.
+type UserParentalControl struct {
+	UserID         uint32     `gorm:"primaryKey" json:"user_id"`
+	MaxRating      string     `gorm:"not null" json:"max_rating"`                  // Highest certification allowed, e.g. PG-13, TV-14 or FSK12
+	BlockUnrated   bool       `gorm:"not null;default:false" json:"block_unrated"` // Also hide items without a recognized rating
+	PINHash        string     `json:"-"`                                           // bcrypt hash of the override PIN
+	OverrideUntil  *time.Time `json:"override_until,omitempty"`
+	FailedAttempts int        `gorm:"not null;default:0" json:"-"`
+	LockedUntil    *time.Time `json:"-"` // Set after repeated wrong PINs
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
+
+// AuthSession is a login. The bearer token issued at login names its
+// session, so logging out or removing the user revokes the token.
+type AuthSession struct {
+	ID         string    `gorm:"type:varchar(36);primaryKey" json:"id"`
+	UserID     uint32    `gorm:"not null;index" json:"user_id"`
+	UserAgent  string    `json:"user_agent"`
+	ClientIP   string    `json:"client_ip"`
+	CreatedAt  time.Time `json:"created_at"`
+	LastSeenAt time.Time `json:"last_seen_at"`
+	ExpiresAt  time.Time `gorm:"not null;index" json:"expires_at"`
+}
+
+// FeedToken grants read-only access to a user's subscription feeds (calendar,
+// recently added) without a session, since calendar apps and feed readers
+// can't log in
+type FeedToken struct {
+	Token      string     `gorm:"primaryKey;type:varchar(64)" json:"token"`
+	UserID     uint32     `gorm:"not null;index" json:"user_id"`
+	Scope      string     `gorm:"not null;index" json:"scope"` // calendar, recent
+	CreatedAt  time.Time  `json:"created_at"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+}
+
+// Announcement is a server-wide banner shown to every user, such as a
+// maintenance window or a newly added library. It is shown between StartsAt
+// and EndsAt; either may be left open.
+type Announcement struct {
+	ID          uint32     `gorm:"primaryKey" json:"id"`
+	Title       string     `gorm:"not null" json:"title"`
+	Message     string     `gorm:"type:text" json:"message"`
+	Type        string     `gorm:"not null;default:info" json:"type"` // info, maintenance, library_added
+	StartsAt    *time.Time `gorm:"index" json:"starts_at,omitempty"`
+	EndsAt      *time.Time `gorm:"index" json:"ends_at,omitempty"`
+	Dismissible bool       `gorm:"not null" json:"dismissible"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+// AnnouncementDismissal records that a user closed an announcement
+type AnnouncementDismissal struct {
+	AnnouncementID uint32    `gorm:"primaryKey" json:"announcement_id"`
+	UserID         uint32    `gorm:"primaryKey" json:"user_id"`
+	DismissedAt    time.Time `gorm:"not null" json:"dismissed_at"`
+}
+
+// UserPlaybackPreferences stores a user's track selection, watched and
+// auto-advance preferences
+type UserPlaybackPreferences struct {
+	UserID            uint32 `gorm:"primaryKey" json:"user_id"`
+	AudioLanguages    string `json:"audio_languages"`    // Comma-separated language codes in priority order
+	SubtitleLanguages string `json:"subtitle_languages"` // Comma-separated language codes in priority order
+	SubtitleMode      string `json:"subtitle_mode"`      // forced, foreign, always, off; empty = forced
+
+	// Watched rules and auto-advance
+	WatchedPercent       int    `json:"watched_percent,omitempty"`        // Share of an item that counts as watched, 50-100; 0 = 90
+	IgnoreCreditsMarker  bool   `json:"ignore_credits_marker"`            // Only the percentage counts, not reaching the credits
+	AutoAdvance          string `json:"auto_advance,omitempty"`           // credits, end, off; empty = credits
+	AutoAdvanceCountdown int    `json:"auto_advance_countdown,omitempty"` // Seconds shown before the next episode starts, 0 = 10
+
 	UpdatedAt time.Time `json:"updated_at"`
 }
 
+// MediaMarker is a detected or hand-placed segment of a media file, such as
+// the intro or end credits
+type MediaMarker struct {
+	ID           uint      `gorm:"primaryKey" json:"id"`
+	MediaFileID  string    `gorm:"type:varchar(36);not null;index" json:"media_file_id"`
+	Type         string    `gorm:"not null" json:"type"` // intro, credits
+	StartSeconds float64   `json:"start_seconds"`
+	EndSeconds   float64   `json:"end_seconds"`
+	Source       string    `json:"source,omitempty"` // Detector or plugin that placed it, or manual
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// PlaybackSession records a single viewing of a media file for history and analytics
+type PlaybackSession struct {
+	ID                 string     `gorm:"primaryKey;type:varchar(36)" json:"id"`
+	UserID             uint32     `gorm:"index" json:"user_id,omitempty"`
+	MediaFileID        string     `gorm:"index;type:varchar(36)" json:"media_file_id"`
+	MediaID            string     `gorm:"index;type:varchar(36)" json:"media_id,omitempty"`
+	MediaType          string     `json:"media_type,omitempty"`
+	Method             string     `gorm:"index" json:"method"` // direct_play or transcode
+	TranscodeSessionID string     `json:"transcode_session_id,omitempty"`
+	TranscodeReason    string     `gorm:"type:text" json:"transcode_reason,omitempty"`
+	ClientName         string     `json:"client_name,omitempty"`
+	DeviceType         string     `json:"device_type,omitempty"`
+	UserAgent          string     `json:"user_agent,omitempty"`
+	ClientIP           string     `json:"client_ip,omitempty"`
+	DurationSeconds    float64    `json:"duration_seconds"`    // Length of the media
+	WatchedSeconds     float64    `json:"watched_seconds"`     // Time actually spent playing
+	PositionSeconds    float64    `json:"position_seconds"`    // Last playback position reported by the player
+	QualitySwitches    int        `json:"quality_switches"`    // ABR rendition changes reported by the player
+	Completed          bool       `json:"completed"`
+	Paused             bool       `json:"paused"` // Whether the player last reported playback paused
+	StartedAt          time.Time  `gorm:"not null;index" json:"started_at"`
+	LastSeenAt         time.Time  `gorm:"not null" json:"last_seen_at"`
+	EndedAt            *time.Time `gorm:"index" json:"ended_at,omitempty"`
+}
+
+// PlayQueue is a server-generated playlist, such as a shuffled show or a
+// collection marathon. Position is the index of the item playing now.
+type PlayQueue struct {
+	ID         string          `gorm:"primaryKey;type:varchar(36)" json:"id"`
+	UserID     uint32          `gorm:"index" json:"user_id"`
+	Mode       string          `gorm:"not null" json:"mode"`        // shuffle, marathon
+	SourceType string          `gorm:"not null" json:"source_type"` // show, collection
+	SourceID   string          `gorm:"not null" json:"source_id"`
+	Title      string          `json:"title"`
+	Position   int             `json:"position"`
+	Items      []PlayQueueItem `gorm:"foreignKey:QueueID;constraint:OnDelete:CASCADE" json:"items,omitempty"`
+	CreatedAt  time.Time       `json:"created_at"`
+	UpdatedAt  time.Time       `json:"updated_at"`
+}
+
+// PlayQueueItem is one entry of a play queue
+type PlayQueueItem struct {
+	QueueID     string    `gorm:"primaryKey;type:varchar(36)" json:"-"`
+	Index       int       `gorm:"primaryKey;autoIncrement:false" json:"index"`
+	MediaID     string    `gorm:"type:varchar(36);not null" json:"media_id"`
+	MediaType   MediaType `gorm:"type:text;not null" json:"media_type"`
+	MediaFileID string    `gorm:"type:varchar(36);not null;index" json:"media_file_id"`
+	Title       string    `json:"title"`
+	Completed   bool      `json:"completed"`
+}
+
+// UserRating is a user's 1-10 rating of a movie or episode
+type UserRating struct {
+	UserID    uint32    `gorm:"primaryKey" json:"user_id"`
+	MediaID   string    `gorm:"primaryKey;type:varchar(36)" json:"media_id"`
+	MediaType MediaType `gorm:"type:text;not null;index" json:"media_type"`
+	Rating    int       `gorm:"not null" json:"rating"`
+	RatedAt   time.Time `gorm:"not null" json:"rated_at"`
+}
+
+// UserHiddenItem hides a movie, show, album or other item from one user's
+// browse, search and recommendation lists. It is a viewing preference, not a
+// permission: the item can still be opened directly.
+type UserHiddenItem struct {
+	UserID    uint32    `gorm:"primaryKey" json:"user_id"`
+	MediaID   string    `gorm:"primaryKey;type:varchar(36)" json:"media_id"`
+	MediaType string    `gorm:"type:text;not null;index" json:"media_type"` // movie, tv_show, episode, artist, album, track, home_video
+	HiddenAt  time.Time `gorm:"not null" json:"hidden_at"`
+}
+
+// UserHiddenLibrary hides a whole library from one user's browse, search and
+// recommendation lists
+type UserHiddenLibrary struct {
+	UserID    uint32    `gorm:"primaryKey" json:"user_id"`
+	LibraryID uint32    `gorm:"primaryKey" json:"library_id"`
+	HiddenAt  time.Time `gorm:"not null" json:"hidden_at"`
+}
+
+// UserFavorite is something a user likes: a media item, a person or a
+// genre. Favorites make up the user's Favorites collection and weigh in
+// recommendations.
+type UserFavorite struct {
+	UserID      uint32    `gorm:"primaryKey" json:"user_id"`
+	TargetType  string    `gorm:"primaryKey;type:varchar(20)" json:"target_type"` // movie, tv_show, episode, artist, album, track, home_video, person, genre
+	TargetID    string    `gorm:"primaryKey;type:varchar(100)" json:"target_id"`  // Item or person ID, or lowercase genre name
+	Name        string    `json:"name"`                                           // Title or name when favorited
+	FavoritedAt time.Time `gorm:"not null;index" json:"favorited_at"`
+}
+
+// LibraryTranscodeProfile is a library's default transcode/optimize profile,
+// e.g. 10-bit HEVC for an anime library or keep-original for home videos
+type LibraryTranscodeProfile struct {
+	LibraryID     uint32    `gorm:"primaryKey" json:"library_id"`
+	Name          string    `json:"name"`
+	VideoCodec    string    `json:"video_codec,omitempty"`    // Preferred codec when the client supports it: h264, hevc, av1, vp9
+	TenBit        bool      `json:"ten_bit"`                  // Encode 10-bit where the codec allows it
+	MaxResolution string    `json:"max_resolution,omitempty"` // Upper bound such as 1080p, empty = client limit
+	Quality       int       `json:"quality,omitempty"`        // 0-100, 0 = planner default
+	KeepOriginal  bool      `json:"keep_original"`            // Direct play whenever the client can decode the source; never optimize
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+// ClientDevice is a registered client and the decode capabilities it
+// reported, reused for playback decisions that name its device ID
+type ClientDevice struct {
+	DeviceID        string    `gorm:"primaryKey" json:"device_id"`
+	UserID          uint32    `gorm:"index" json:"user_id,omitempty"`
+	Name            string    `json:"name,omitempty"`
+	UserAgent       string    `json:"user_agent,omitempty"`
+	SupportedCodecs string    `json:"supported_codecs"`         // Comma-separated, e.g. "h264,hevc,aac,opus"
+	MaxResolution   string    `json:"max_resolution,omitempty"` // e.g. 1080p, 2160p
+	MaxBitrate      int       `json:"max_bitrate,omitempty"`    // Kbps
+	SupportsHEVC    bool      `json:"supports_hevc"`
+	SupportsAV1     bool      `json:"supports_av1"`
+	SupportsHDR     bool      `json:"supports_hdr"`
+	DRMSystems      string    `json:"drm_systems,omitempty"` // Comma-separated, e.g. "widevine,playready"
+	LastSeenAt      time.Time `json:"last_seen_at"`
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
+}
+
+// LibraryEnrichmentProvider selects an enrichment plugin for a library.
+// A library without rows uses every enabled enrichment plugin.
+type LibraryEnrichmentProvider struct {
+	LibraryID uint32    `gorm:"primaryKey" json:"library_id"`
+	PluginID  string    `gorm:"primaryKey" json:"plugin_id"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// LibraryArtworkSettings holds a library's artwork selection preferences
+type LibraryArtworkSettings struct {
+	LibraryID               uint32    `gorm:"primaryKey" json:"library_id"`
+	PreferTextlessBackdrops bool      `json:"prefer_textless_backdrops"` // Pick backdrops without embedded title text, for libraries that overlay logos
+	UpdatedAt               time.Time `json:"updated_at"`
+}
+
+// LibraryScanSchedule scans a library automatically, on a cron expression or
+// every IntervalMinutes, and optionally when the server starts. Scheduled
+// and startup scans are held back during the blackout windows.
+type LibraryScanSchedule struct {
+	LibraryID         uint32      `gorm:"primaryKey" json:"library_id"`
+	Cron              string      `json:"cron,omitempty"`             // Five-field cron expression or a macro such as @daily
+	IntervalMinutes   int         `json:"interval_minutes,omitempty"` // Used when there is no cron expression
+	ScanOnStartup     bool        `json:"scan_on_startup"`
+	Blackouts         ScanWindows `gorm:"type:text" json:"blackouts"`
+	NextScanAt        *time.Time  `json:"next_scan_at,omitempty"`
+	LastScanAt        *time.Time  `json:"last_scan_at,omitempty"`
+	LastScanJobID     *uint32     `json:"last_scan_job_id,omitempty"`
+	PausedForBlackout bool        `json:"paused_for_blackout"` // The last scheduled scan was paused when a blackout began
+	UpdatedAt         time.Time   `json:"updated_at"`
+}
+
+// ScanWindow is a blackout window: a time of day, starting on some days of
+// the week (every day when none are given), in which scheduled scans don't run
+type ScanWindow struct {
+	Start string   `json:"start"` // HH:MM
+	End   string   `json:"end"`   // HH:MM; before Start for windows past midnight
+	Days  []string `json:"days,omitempty"`
+}
+
+// ScanWindows is stored as a JSON array
+type ScanWindows []ScanWindow
+
+// Value stores the windows as JSON
+func (w ScanWindows) Value() (driver.Value, error) {
+	if len(w) == 0 {
+		return "[]", nil
+	}
+	data, err := json.Marshal(w)
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+// Scan reads windows stored as JSON
+func (w *ScanWindows) Scan(value interface{}) error {
+	var data []byte
+	switch v := value.(type) {
+	case nil:
+		*w = nil
+		return nil
+	case string:
+		data = []byte(v)
+	case []byte:
+		data = v
+	default:
+		return fmt.Errorf("cannot scan %T into ScanWindows", value)
+	}
+	if len(data) == 0 {
+		*w = nil
+		return nil
+	}
+	return json.Unmarshal(data, w)
+}
+
+// LibraryNamingRule is a user-provided rewrite of a library's file names,
+// applied before they are parsed for matching so unusual release naming can
+// be read without new parser rules. Rules apply in Position order.
+type LibraryNamingRule struct {
+	ID          uint32    `gorm:"primaryKey" json:"id"`
+	LibraryID   uint32    `gorm:"not null;index" json:"library_id"`
+	Position    int       `gorm:"not null" json:"position"`
+	Pattern     string    `gorm:"not null" json:"pattern"` // Go regular expression matched against the file name without its extension
+	Replacement string    `json:"replacement"`             // May refer to submatches as $1 or ${name}
+	Description string    `json:"description,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// LibraryStorageSample records a library's size once a week, keyed by the
+// start of the week, so storage growth can be charted
+type LibraryStorageSample struct {
+	LibraryID  uint32    `gorm:"primaryKey" json:"library_id"`
+	WeekStart  time.Time `gorm:"primaryKey" json:"week_start"`
+	TotalBytes int64     `json:"total_bytes"`
+	FileCount  int64     `json:"file_count"`
+	SampledAt  time.Time `json:"sampled_at"`
+}
+
+// LibraryQualityTarget is the lowest resolution a library's movies and
+// episodes should have. Items whose best file is below it are upgrades wanted.
+type LibraryQualityTarget struct {
+	LibraryID     uint32    `gorm:"primaryKey" json:"library_id"`
+	MinResolution string    `gorm:"not null" json:"min_resolution"` // 720p, 1080p, 1440p or 2160p
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+// UpgradeWanted is a movie or episode whose best local file is below its
+// library's quality target
+type UpgradeWanted struct {
+	MediaID          string     `gorm:"type:varchar(36);primaryKey" json:"media_id"`
+	MediaType        MediaType  `gorm:"type:text;not null" json:"media_type"`
+	LibraryID        uint32     `gorm:"not null;index" json:"library_id"`
+	Title            string     `json:"title"`
+	BestResolution   string     `json:"best_resolution"`
+	TargetResolution string     `json:"target_resolution"`
+	DetectedAt       time.Time  `gorm:"not null" json:"detected_at"`
+	PushedAt         *time.Time `json:"pushed_at,omitempty"` // When last sent to Radarr, Sonarr or the webhook
+}
+
+// RecycledFile is a deleted media file kept in the recycle bin until it
+// expires. Its media_files row is snapshotted so a restore brings back the
+// file with its metadata, history and identity intact.
+type RecycledFile struct {
+	ID           string    `gorm:"type:varchar(36);primaryKey" json:"id"` // Also the file's directory in the recycle bin
+	MediaFileID  string    `gorm:"type:varchar(36);not null;index" json:"media_file_id"`
+	MediaID      string    `gorm:"type:varchar(36);index" json:"media_id"`
+	MediaType    MediaType `gorm:"type:text" json:"media_type"`
+	LibraryID    uint32    `gorm:"not null;index" json:"library_id"`
+	OriginalPath string    `gorm:"not null" json:"original_path"`
+	RecyclePath  string    `gorm:"not null" json:"recycle_path"`
+	SizeBytes    int64     `json:"size_bytes"`
+	Snapshot     string    `gorm:"type:text;not null" json:"-"` // The media_files row as JSON
+	Sidecars     string    `gorm:"type:text" json:"-"`          // Subtitle files recycled with it as JSON
+	DeletedBy    *uint32   `json:"deleted_by,omitempty"`        // User who deleted it, when signed in
+	RecycledAt   time.Time `gorm:"not null;index" json:"recycled_at"`
+	ExpiresAt    time.Time `gorm:"not null;index" json:"expires_at"`
+}
+
 // MediaLibrary represents a directory to scan for media files
 type MediaLibrary struct {
 	ID        uint32    `gorm:"primaryKey" json:"id"`
 	Path      string    `gorm:"not null" json:"path"`
-	Type      string    `gorm:"not null" json:"type"` // "movie", "tv", "music"
+	Type      string    `gorm:"not null" json:"type"` // "movie", "tv", "music", "mixed", "home"
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
 }
@@ -28,17 +398,26 @@ type MediaLibrary struct {
 // MediaLibraryRequest represents the request to create a new media library
 type MediaLibraryRequest struct {
 	Path string `json:"path" binding:"required"`
-	Type string `json:"type" binding:"required,oneof=movie tv music"`
+	Type string `json:"type" binding:"required,oneof=movie tv music mixed home"`
 }
 
 // MediaType enum for media_files.media_type and related fields
 type MediaType string
 
 const (
-	MediaTypeMovie   MediaType = "movie"
-	MediaTypeEpisode MediaType = "episode"
-	MediaTypeTrack   MediaType = "track"
-	MediaTypeImage   MediaType = "image"
+	MediaTypeMovie     MediaType = "movie"
+	MediaTypeEpisode   MediaType = "episode"
+	MediaTypeTrack     MediaType = "track"
+	MediaTypeImage     MediaType = "image"
+	MediaTypeHomeVideo MediaType = "home_video"
+)
+
+// MediaFile.ClassifiedBy values. Only mixed libraries classify per item.
+const (
+	ClassifiedByLibrary = "library" // Type follows from the library type
+	ClassifiedByNaming  = "naming"  // Episode or movie naming in the path
+	ClassifiedByGuess   = "guess"   // No naming matched, assumed to be a movie
+	ClassifiedByTMDb    = "tmdb"    // TMDb media_type confirmed or corrected a guess
 )
 
 func (mt MediaType) Value() (driver.Value, error) {
@@ -69,7 +448,7 @@ func (mt *MediaType) Scan(value interface{}) error {
 type MediaFile struct {
 	ID          string    `gorm:"type:varchar(36);primaryKey" json:"id"`
 	MediaID     string    `gorm:"type:varchar(36);not null;index" json:"media_id"` // FK to movie, episode, or track
-	MediaType   MediaType `gorm:"type:text;not null;index" json:"media_type"`      // ENUM: movie, episode, track
+	MediaType   MediaType `gorm:"type:text;not null;index" json:"media_type"`      // ENUM: movie, episode, track, home_video
 	LibraryID   uint32    `gorm:"not null;index" json:"library_id"`                // FK to MediaLibrary
 	ScanJobID   *uint32   `gorm:"index" json:"scan_job_id,omitempty"`              // Track which job discovered this file
 	Path        string    `gorm:"not null;uniqueIndex" json:"path"`                // Absolute or relative file path
@@ -106,15 +485,28 @@ type MediaFile struct {
 	ColorTransfer   string `json:"color_transfer"`   // Color transfer
 	HDRFormat       string `json:"hdr_format"`       // HDR format (HDR10, DV, etc.)
 	Interlaced      string `json:"interlaced"`       // Interlaced status
+	Deinterlace     string `json:"deinterlace"`      // Per-item deinterlace override (off, yadif, bwdif, ivtc), empty = detect
 	ReferenceFrames int    `json:"reference_frames"` // Number of reference frames
 
 	// Enhanced audio fields
-	AudioChannels   int    `json:"audio_channels"`    // Number of audio channels
-	AudioLayout     string `json:"audio_layout"`      // Audio channel layout
-	AudioSampleRate int    `json:"audio_sample_rate"` // Audio sample rate
-	AudioBitDepth   int    `json:"audio_bit_depth"`   // Audio bit depth
-	AudioLanguage   string `json:"audio_language"`    // Primary audio language
-	AudioProfile    string `json:"audio_profile"`     // Audio codec profile
+	AudioChannels   int    `json:"audio_channels"`              // Number of audio channels
+	AudioLayout     string `json:"audio_layout"`                // Audio channel layout
+	AudioSampleRate int    `json:"audio_sample_rate"`           // Audio sample rate
+	AudioBitDepth   int    `json:"audio_bit_depth"`             // Audio bit depth
+	AudioLanguage   string `json:"audio_language"`              // Primary audio language
+	AudioProfile    string `json:"audio_profile"`               // Audio codec profile
+	AudioLossless   bool   `gorm:"index" json:"audio_lossless"` // Lossless codec (FLAC, ALAC, PCM, ...)
+
+	// Gapless playback and loudness, read from the container and tags
+	EncoderDelay   int      `json:"encoder_delay"`              // Priming samples to skip at the start
+	EncoderPadding int      `json:"encoder_padding"`            // Padding samples to drop at the end
+	ReplayGain     *float64 `json:"replay_gain,omitempty"`      // ReplayGain track gain in dB
+	ReplayGainPeak *float64 `json:"replay_gain_peak,omitempty"` // ReplayGain track peak, linear
+	AlbumGain      *float64 `json:"album_gain,omitempty"`       // ReplayGain album gain in dB
+
+	// How media_type was decided: "library" from the library type, or in mixed
+	// libraries "naming", "guess" or "tmdb"
+	ClassifiedBy string `gorm:"type:text;default:library;index" json:"classified_by"`
 
 	LastSeen  time.Time `gorm:"not null" json:"last_seen"`
 	CreatedAt time.Time `json:"created_at"`
@@ -159,22 +551,32 @@ type MediaAsset struct {
 
 // People - Unified table for cast, crew, artists
 type People struct {
-	ID        string     `gorm:"type:varchar(36);primaryKey" json:"id"`
-	Name      string     `gorm:"not null;index" json:"name"`
-	Birthdate *time.Time `json:"birthdate"` // Optional
-	Image     string     `json:"image"`     // URL or path to portrait
-	CreatedAt time.Time  `json:"created_at"`
-	UpdatedAt time.Time  `json:"updated_at"`
+	ID                 string     `gorm:"type:varchar(36);primaryKey" json:"id"`
+	Name               string     `gorm:"not null;index" json:"name"`
+	TmdbID             string     `gorm:"index" json:"tmdb_id,omitempty"`
+	ImdbID             string     `gorm:"index" json:"imdb_id,omitempty"`
+	Biography          string     `gorm:"type:text" json:"biography,omitempty"`
+	Birthdate          *time.Time `json:"birthdate"` // Optional
+	Deathdate          *time.Time `json:"deathdate,omitempty"`
+	PlaceOfBirth       string     `json:"place_of_birth,omitempty"`
+	KnownForDepartment string     `json:"known_for_department,omitempty"`                     // e.g. Acting, Directing
+	Image              string     `json:"image"`                                              // URL or path to portrait
+	ProfileAssetID     string     `gorm:"type:varchar(36)" json:"profile_asset_id,omitempty"` // Headshot downloaded from Image
+	CreatedAt          time.Time  `json:"created_at"`
+	UpdatedAt          time.Time  `json:"updated_at"`
 }
 
 // Roles - Many-to-many relationship between people and media entities
 type Roles struct {
-	PersonID  string    `gorm:"type:varchar(36);not null;index" json:"person_id"` // FK to people
-	MediaID   string    `gorm:"type:varchar(36);not null;index" json:"media_id"`  // FK to movie, episode, or track
-	MediaType MediaType `gorm:"type:text;not null;index" json:"media_type"`       // ENUM: movie, episode, track
-	Role      string    `gorm:"not null;index" json:"role"`                       // e.g. director, actor, composer, guest
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	PersonID   string    `gorm:"type:varchar(36);not null;index" json:"person_id"` // FK to people
+	MediaID    string    `gorm:"type:varchar(36);not null;index" json:"media_id"`  // FK to movie, episode, or track
+	MediaType  MediaType `gorm:"type:text;not null;index" json:"media_type"`       // ENUM: movie, episode, track
+	Role       string    `gorm:"not null;index" json:"role"`                       // e.g. director, actor, composer, guest
+	Character  string    `json:"character,omitempty"`                              // Actors only
+	Department string    `json:"department,omitempty"`                             // e.g. Acting, Directing, Writing
+	Order      int       `gorm:"column:billing_order" json:"order"`                // Billing order, lowest first
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
 }
 
 // =============================================================================
@@ -183,12 +585,40 @@ type Roles struct {
 
 // Artist table
 type Artist struct {
-	ID          string    `gorm:"type:varchar(36);primaryKey" json:"id"`
-	Name        string    `gorm:"not null;index" json:"name"`
-	Description string    `json:"description"`
-	Image       string    `json:"image"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
+	ID          string `gorm:"type:varchar(36);primaryKey" json:"id"`
+	Name        string `gorm:"not null;index" json:"name"`
+	Description string `json:"description"`
+	Image       string `json:"image"`
+
+	// MusicBrainz artist data
+	MusicBrainzID  string               `gorm:"index" json:"musicbrainz_id,omitempty"`
+	Type           string               `json:"type,omitempty"`       // Person, Group, Orchestra, Choir, Character, Other
+	Country        string               `json:"country,omitempty"`    // ISO 3166-1 code
+	BeginDate      string               `json:"begin_date,omitempty"` // Birth or formation: YYYY, YYYY-MM or YYYY-MM-DD
+	EndDate        string               `json:"end_date,omitempty"`   // Death or dissolution
+	Ended          bool                 `json:"ended"`
+	Disambiguation string               `json:"disambiguation,omitempty"`
+	Relationships  []ArtistRelationship `gorm:"foreignKey:ArtistID;constraint:OnDelete:CASCADE" json:"relationships,omitempty"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// ArtistRelationship links an artist to another MusicBrainz artist, e.g. a
+// member of a band. TargetArtistID is set when the other artist is in the
+// library too.
+type ArtistRelationship struct {
+	ID             uint32  `gorm:"primaryKey" json:"id"`
+	ArtistID       string  `gorm:"type:varchar(36);not null;index" json:"artist_id"` // FK to Artist
+	Type           string  `gorm:"not null" json:"type"`                             // MusicBrainz relationship type, e.g. "member of band"
+	Direction      string  `json:"direction"`                                        // forward: artist → target, backward: target → artist
+	TargetMBID     string  `gorm:"column:target_mbid;index" json:"target_mbid"`
+	TargetName     string  `json:"target_name"`
+	TargetArtistID *string `gorm:"type:varchar(36);index" json:"target_artist_id,omitempty"`
+	BeginDate      string  `json:"begin_date,omitempty"`
+	EndDate        string  `json:"end_date,omitempty"`
+	Ended          bool    `json:"ended"`
+	Attributes     string  `json:"attributes,omitempty"` // Comma-separated, e.g. "lead vocals, guitar"
 }
 
 // Album table
@@ -199,23 +629,59 @@ type Album struct {
 	Artist      Artist     `gorm:"foreignKey:ArtistID" json:"artist,omitempty"`
 	ReleaseDate *time.Time `json:"release_date"`
 	Artwork     string     `json:"artwork"`
-	CreatedAt   time.Time  `json:"created_at"`
-	UpdatedAt   time.Time  `json:"updated_at"`
+
+	// MusicBrainz release data
+	MusicBrainzID string       `gorm:"index" json:"musicbrainz_id,omitempty"`
+	ReleaseType   string       `json:"release_type,omitempty"`   // Album, Single, EP, Compilation, etc.
+	ReleaseStatus string       `json:"release_status,omitempty"` // Official, Promotion, Bootleg, Pseudo-Release
+	Country       string       `json:"country,omitempty"`        // ISO 3166-1 code of the release
+	Barcode       string       `gorm:"index" json:"barcode,omitempty"`
+	Labels        []AlbumLabel `gorm:"foreignKey:AlbumID;constraint:OnDelete:CASCADE" json:"labels,omitempty"`
+
+	// Multi-disc releases and box sets
+	DiscCount      int     `gorm:"default:1" json:"disc_count"`
+	IsBoxSet       bool    `gorm:"index" json:"is_box_set"`                                 // Groups other albums as one release
+	ParentAlbumID  *string `gorm:"type:varchar(36);index" json:"parent_album_id,omitempty"` // Box set this album belongs to
+	BoxSetPosition int     `json:"box_set_position,omitempty"`                              // Order within the box set
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// AlbumLabel is a label that issued an album, with its catalog number
+type AlbumLabel struct {
+	AlbumID       string `gorm:"type:varchar(36);primaryKey" json:"-"`
+	Position      int    `gorm:"primaryKey;autoIncrement:false" json:"position"`
+	Name          string `json:"name"`
+	LabelMBID     string `gorm:"column:label_mbid;index" json:"label_mbid,omitempty"`
+	CatalogNumber string `gorm:"index" json:"catalog_number,omitempty"`
 }
 
 // Track table
 type Track struct {
-	ID          string    `gorm:"type:varchar(36);primaryKey" json:"id"`
-	Title       string    `gorm:"not null;index" json:"title"`
-	AlbumID     string    `gorm:"type:varchar(36);not null;index" json:"album_id"` // FK to Album
-	Album       Album     `gorm:"foreignKey:AlbumID" json:"album,omitempty"`
-	ArtistID    string    `gorm:"type:varchar(36);not null;index" json:"artist_id"` // FK to Artist
-	Artist      Artist    `gorm:"foreignKey:ArtistID" json:"artist,omitempty"`
-	TrackNumber int       `json:"track_number"`
-	Duration    int       `json:"duration"` // In seconds
-	Lyrics      string    `gorm:"type:text" json:"lyrics"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
+	ID          string `gorm:"type:varchar(36);primaryKey" json:"id"`
+	Title       string `gorm:"not null;index" json:"title"`
+	AlbumID     string `gorm:"type:varchar(36);not null;index" json:"album_id"` // FK to Album
+	Album       Album  `gorm:"foreignKey:AlbumID" json:"album,omitempty"`
+	ArtistID    string `gorm:"type:varchar(36);not null;index" json:"artist_id"` // FK to Artist
+	Artist      Artist `gorm:"foreignKey:ArtistID" json:"artist,omitempty"`
+	DiscNumber  int    `gorm:"default:1" json:"disc_number"`
+	TrackNumber int    `json:"track_number"`
+	Duration    int    `json:"duration"` // In seconds
+	Lyrics      string `gorm:"type:text" json:"lyrics"`
+
+	// Classical music: the work a track performs and who performs it
+	Composer       string `gorm:"index" json:"composer,omitempty"`
+	Work           string `gorm:"index" json:"work,omitempty"`
+	WorkMBID       string `gorm:"column:work_mbid;index" json:"work_mbid,omitempty"`
+	Movement       string `json:"movement,omitempty"`
+	MovementNumber int    `json:"movement_number,omitempty"`
+	MovementCount  int    `json:"movement_count,omitempty"`
+	Conductor      string `gorm:"index" json:"conductor,omitempty"`
+	Orchestra      string `gorm:"index" json:"orchestra,omitempty"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
 }
 
 // =============================================================================
@@ -286,16 +752,17 @@ type Movie struct {
 
 // TVShow table
 type TVShow struct {
-	ID           string     `gorm:"type:varchar(36);primaryKey" json:"id"`
-	Title        string     `gorm:"not null;index" json:"title"`
-	Description  string     `gorm:"type:text" json:"description"`
-	FirstAirDate *time.Time `json:"first_air_date"`
-	Status       string     `json:"status"` // e.g., Running, Ended
-	Poster       string     `json:"poster"`
-	Backdrop     string     `json:"backdrop"`
-	TmdbID       string     `gorm:"index" json:"tmdb_id"`
-	CreatedAt    time.Time  `json:"created_at"`
-	UpdatedAt    time.Time  `json:"updated_at"`
+	ID            string     `gorm:"type:varchar(36);primaryKey" json:"id"`
+	Title         string     `gorm:"not null;index" json:"title"`
+	Description   string     `gorm:"type:text" json:"description"`
+	FirstAirDate  *time.Time `json:"first_air_date"`
+	Status        string     `json:"status"`         // e.g., Running, Ended
+	ContentRating string     `json:"content_rating"` // e.g. TV-14, TV-MA
+	Poster        string     `json:"poster"`
+	Backdrop      string     `json:"backdrop"`
+	TmdbID        string     `gorm:"index" json:"tmdb_id"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
 }
 
 // Season table
@@ -326,6 +793,51 @@ type Episode struct {
 	UpdatedAt     time.Time  `json:"updated_at"`
 }
 
+// =============================================================================
+// HOME VIDEO TABLES
+// =============================================================================
+
+// HomeVideo is personal footage from a home video library. It is never
+// enriched; titles, descriptions and tags are edited by hand.
+type HomeVideo struct {
+	ID          string     `gorm:"type:varchar(36);primaryKey" json:"id"`
+	LibraryID   uint32     `gorm:"not null;index" json:"library_id"`
+	Title       string     `gorm:"not null;index" json:"title"`
+	Description string     `gorm:"type:text" json:"description"`
+	Tags        string     `gorm:"type:text" json:"tags"`       // JSON array of tags
+	RecordedAt  *time.Time `gorm:"index" json:"recorded_at"`    // When the footage was recorded
+	DateSource  string     `json:"date_source"`                 // container, filename, file or manual
+	Edited      bool       `gorm:"default:false" json:"edited"` // Title or tags were set by hand
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+// Collection is a franchise of movies, such as a TMDb collection. Movies
+// join it through CollectionItem when enrichment finds they belong to it.
+type Collection struct {
+	ID        string    `gorm:"type:varchar(36);primaryKey" json:"id"`
+	TmdbID    string    `gorm:"uniqueIndex" json:"tmdb_id"`
+	Name      string    `gorm:"not null;index" json:"name"`
+	Overview  string    `gorm:"type:text" json:"overview"`
+	Poster    string    `json:"poster"`
+	Backdrop  string    `json:"backdrop"`
+	PartCount int       `json:"part_count"` // Movies in the collection, owned or not
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// TableName returns the collection table name
+func (Collection) TableName() string {
+	return "collection"
+}
+
+// CollectionItem puts a movie in a collection
+type CollectionItem struct {
+	CollectionID string    `gorm:"type:varchar(36);primaryKey" json:"collection_id"`
+	MovieID      string    `gorm:"type:varchar(36);primaryKey;index" json:"movie_id"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
 // =============================================================================
 // METADATA ENRICHMENT TABLES
 // =============================================================================
@@ -349,6 +861,45 @@ type MediaEnrichment struct {
 	UpdatedAt time.Time `json:"updated_at"`
 }
 
+// MediaFieldProvenance - Records which source last set each enriched field of
+// a media item, and whether the field is locked against further enrichment
+type MediaFieldProvenance struct {
+	MediaID    string    `gorm:"type:varchar(36);primaryKey" json:"media_id"`
+	Field      string    `gorm:"type:varchar(100);primaryKey" json:"field"`
+	MediaType  MediaType `gorm:"type:text;not null;index" json:"media_type"`
+	Source     string    `gorm:"index" json:"source"` // Plugin that set the value; empty until one does
+	Value      string    `gorm:"type:text" json:"value"`
+	Confidence float64   `json:"confidence"`
+	Locked     bool      `gorm:"not null" json:"locked"` // Enrichment leaves locked fields alone
+	SetAt      time.Time `json:"set_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// MediaEnrichmentSnapshot - One version of a media item's enriched state,
+// recorded as enrichment changes it so a wrong match can be rolled back
+type MediaEnrichmentSnapshot struct {
+	ID          uint32    `gorm:"primaryKey" json:"id"`
+	MediaID     string    `gorm:"type:varchar(36);not null;index" json:"media_id"`
+	MediaType   MediaType `gorm:"type:text;not null" json:"media_type"`
+	Version     int       `gorm:"not null" json:"version"` // Counts up from 1 per media item
+	Reason      string    `gorm:"not null" json:"reason"`  // initial, enrichment, force_apply or rollback
+	Sources     string    `json:"sources"`                 // Comma-separated plugins with enrichment data
+	Entities    string    `gorm:"type:text" json:"-"`      // JSON rows of the item and the artist and album it updates
+	Enrichments string    `gorm:"type:text" json:"-"`      // JSON media_enrichments rows
+	ExternalIDs string    `gorm:"type:text" json:"-"`      // JSON media_external_ids rows
+	Provenance  string    `gorm:"type:text" json:"-"`      // JSON media_field_provenances rows
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// MediaEnrichmentSnapshotAsset - An artwork record of a snapshot. Asset files
+// listed here are kept when their asset is replaced, so rollback can restore them.
+type MediaEnrichmentSnapshotAsset struct {
+	SnapshotID uint32 `gorm:"primaryKey" json:"snapshot_id"`
+	AssetID    string `gorm:"type:varchar(36);primaryKey" json:"asset_id"`
+	Path       string `gorm:"index" json:"path"`
+	Row        string `gorm:"type:text" json:"-"` // JSON media_assets row
+}
+
 // =============================================================================
 // SCAN JOB (remains mostly the same)
 // =============================================================================
@@ -450,6 +1001,19 @@ type PluginHook struct {
 	UpdatedAt time.Time `json:"updated_at"`
 }
 
+// PluginHookResult is the latest outcome of a plugin's scanner hook for a
+// media file, recorded by the host so skipped and failed files can be reported
+type PluginHookResult struct {
+	MediaFileID string    `gorm:"primaryKey;type:varchar(36)" json:"media_file_id"`
+	PluginID    string    `gorm:"primaryKey" json:"plugin_id"`
+	LibraryID   uint32    `gorm:"index" json:"library_id"`
+	Status      string    `gorm:"not null;index" json:"status"`  // processed, skipped, failed
+	Reason      string    `gorm:"index" json:"reason,omitempty"` // Skip reason, e.g. disabled, unsupported_type, no_match
+	Detail      string    `json:"detail,omitempty"`              // Skip detail or error message
+	DurationMs  int64     `json:"duration_ms"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
 // PluginAdminPage represents admin pages provided by plugins
 type PluginAdminPage struct {
 	ID        uint32    `gorm:"primaryKey" json:"id"`
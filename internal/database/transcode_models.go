@@ -33,6 +33,8 @@ type TranscodeSession struct {
 	EndTime       *time.Time      `gorm:"index"`
 	LastAccessed  time.Time       `gorm:"not null;index"`
 	DirectoryPath string          `gorm:"type:varchar(512)"`
+	CacheKey      string          `gorm:"index;type:varchar(64)"` // Output identity shared by viewers of the same item and settings
+	CacheHits     int             `gorm:"not null;default:0"`     // Viewers served from this session's output after the first
 
 	// Indexes for efficient queries
 	// Index on (provider, status) for provider-specific queries
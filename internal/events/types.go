@@ -16,6 +16,9 @@ const (
 	EventMediaFileFound        EventType = "media.file.found"
 	EventMediaMetadataEnriched EventType = "media.metadata.enriched"
 	EventMediaFileDeleted      EventType = "media.file.deleted"
+	EventMediaFileOrganized    EventType = "media.file.organized"
+	EventMediaFileRestored     EventType = "media.file.restored"
+	EventDownloadImported      EventType = "media.download.imported"
 	// EventMediaFileUploaded event type removed as app won't support uploads
 
 	// Media Asset events
@@ -54,6 +57,14 @@ const (
 	EventScanResumed   EventType = "scan.resumed"
 	EventScanPaused    EventType = "scan.paused"
 
+	// Enrichment events
+	EventEnrichmentApplied EventType = "enrichment.applied"
+
+	// Disk health events
+	EventDiskHealthWarning    EventType = "disk.health.warning"
+	EventDiskFailurePredicted EventType = "disk.health.failing"
+	EventDiskHealthRecovered  EventType = "disk.health.recovered"
+
 	// General events
 	EventError   EventType = "error"
 	EventWarning EventType = "warning"
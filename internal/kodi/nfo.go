@@ -0,0 +1,258 @@
+// Package kodi reads and writes metadata kept alongside media files in the
+// layout Kodi uses: NFO files and artwork named after the media file or its
+// folder. Jellyfin and Emby read the same layout, so a library exported this
+// way can be moved between media servers without re-matching it.
+package kodi
+
+import (
+	"encoding/xml"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DateLayout is the date format of premiered, aired and release dates
+const DateLayout = "2006-01-02"
+
+// xmlHeader is written at the top of every NFO file
+const xmlHeader = `<?xml version="1.0" encoding="UTF-8" standalone="yes" ?>` + "\n"
+
+// UniqueID is an ID of the item at a metadata provider, e.g. imdb or tmdb
+type UniqueID struct {
+	Type    string `xml:"type,attr"`
+	Default bool   `xml:"default,attr,omitempty"`
+	Value   string `xml:",chardata"`
+}
+
+// Movie is a movie NFO: <basename>.nfo or movie.nfo
+type Movie struct {
+	XMLName       xml.Name   `xml:"movie"`
+	Title         string     `xml:"title"`
+	OriginalTitle string     `xml:"originaltitle,omitempty"`
+	Year          int        `xml:"year,omitempty"`
+	Plot          string     `xml:"plot,omitempty"`
+	Tagline       string     `xml:"tagline,omitempty"`
+	Runtime       int        `xml:"runtime,omitempty"` // Minutes
+	MPAA          string     `xml:"mpaa,omitempty"`
+	Premiered     string     `xml:"premiered,omitempty"`
+	Genres        []string   `xml:"genre"`
+	Studios       []string   `xml:"studio"`
+	Countries     []string   `xml:"country"`
+	Tags          []string   `xml:"tag"`
+	UniqueIDs     []UniqueID `xml:"uniqueid"`
+	ID            string     `xml:"id,omitempty"` // Legacy IMDb ID, still read by older servers
+}
+
+// TVShow is a show NFO: tvshow.nfo in the show folder
+type TVShow struct {
+	XMLName   xml.Name   `xml:"tvshow"`
+	Title     string     `xml:"title"`
+	Year      int        `xml:"year,omitempty"`
+	Plot      string     `xml:"plot,omitempty"`
+	Premiered string     `xml:"premiered,omitempty"`
+	Status    string     `xml:"status,omitempty"`
+	Genres    []string   `xml:"genre"`
+	UniqueIDs []UniqueID `xml:"uniqueid"`
+}
+
+// Episode is an episode NFO: <basename>.nfo
+type Episode struct {
+	XMLName   xml.Name   `xml:"episodedetails"`
+	Title     string     `xml:"title"`
+	ShowTitle string     `xml:"showtitle,omitempty"`
+	Season    int        `xml:"season"`
+	Episode   int        `xml:"episode"`
+	Plot      string     `xml:"plot,omitempty"`
+	Aired     string     `xml:"aired,omitempty"`
+	Runtime   int        `xml:"runtime,omitempty"` // Minutes
+	UniqueIDs []UniqueID `xml:"uniqueid"`
+}
+
+// Album is an album NFO: album.nfo in the album folder
+type Album struct {
+	XMLName            xml.Name `xml:"album"`
+	Title              string   `xml:"title"`
+	Artist             string   `xml:"artist,omitempty"`
+	Year               int      `xml:"year,omitempty"`
+	ReleaseDate        string   `xml:"releasedate,omitempty"`
+	ReleaseType        string   `xml:"releasetype,omitempty"`
+	Labels             []string `xml:"label"`
+	MusicBrainzAlbumID string   `xml:"musicbrainzalbumid,omitempty"`
+}
+
+// UniqueID returns the ID of the given type, falling back to the legacy
+// <id> element for IMDb
+func (m *Movie) UniqueID(idType string) string {
+	if id := findUniqueID(m.UniqueIDs, idType); id != "" {
+		return id
+	}
+	if idType == "imdb" && strings.HasPrefix(m.ID, "tt") {
+		return m.ID
+	}
+	return ""
+}
+
+// UniqueID returns the ID of the given type
+func (s *TVShow) UniqueID(idType string) string {
+	return findUniqueID(s.UniqueIDs, idType)
+}
+
+// UniqueID returns the ID of the given type
+func (e *Episode) UniqueID(idType string) string {
+	return findUniqueID(e.UniqueIDs, idType)
+}
+
+func findUniqueID(ids []UniqueID, idType string) string {
+	for _, id := range ids {
+		if strings.EqualFold(id.Type, idType) {
+			return strings.TrimSpace(id.Value)
+		}
+	}
+	return ""
+}
+
+// Write writes an NFO file, replacing any existing one
+func Write(path string, nfo interface{}) error {
+	data, err := xml.MarshalIndent(nfo, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode NFO: %w", err)
+	}
+	data = append([]byte(xmlHeader), data...)
+	data = append(data, '\n')
+	return os.WriteFile(path, data, 0644)
+}
+
+// Read reads an NFO file into nfo. Anything after the root element, such as
+// the provider URL some tools append, is ignored.
+func Read(path string, nfo interface{}) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	if err := xml.NewDecoder(file).Decode(nfo); err != nil {
+		return fmt.Errorf("failed to parse NFO %s: %w", path, err)
+	}
+	return nil
+}
+
+// ParseDate parses a premiered, aired or release date, accepting a bare year
+func ParseDate(value string) *time.Time {
+	value = strings.TrimSpace(value)
+	if date, err := time.Parse(DateLayout, value); err == nil {
+		return &date
+	}
+	if year, err := strconv.Atoi(value); err == nil && year > 0 {
+		date := time.Date(year, 1, 1, 0, 0, 0, 0, time.UTC)
+		return &date
+	}
+	return nil
+}
+
+// FormatDate formats a date for an NFO, or returns "" for no date
+func FormatDate(date *time.Time) string {
+	if date == nil || date.IsZero() {
+		return ""
+	}
+	return date.Format(DateLayout)
+}
+
+// Artwork kinds, as used in Kodi artwork file names
+const (
+	ArtPoster    = "poster"
+	ArtFanart    = "fanart"
+	ArtBanner    = "banner"
+	ArtClearLogo = "clearlogo"
+	ArtThumb     = "thumb"
+	ArtFolder    = "folder"
+)
+
+// imageExtensions are the artwork extensions recognized on import, in order
+// of preference
+var imageExtensions = []string{".jpg", ".jpeg", ".png", ".webp"}
+
+// seasonFolderPattern matches the season folders episodes are kept in
+var seasonFolderPattern = regexp.MustCompile(`(?i)^(season[ ._-]*\d+|s\d+|specials)$`)
+
+// BaseName returns a media file's path without its extension, the prefix of
+// its NFO and artwork
+func BaseName(mediaPath string) string {
+	return strings.TrimSuffix(mediaPath, filepath.Ext(mediaPath))
+}
+
+// MediaNFOPath returns the NFO path named after a media file
+func MediaNFOPath(mediaPath string) string {
+	return BaseName(mediaPath) + ".nfo"
+}
+
+// MediaArtworkPath returns the path of artwork named after a media file,
+// e.g. Movie (2010)-poster.jpg
+func MediaArtworkPath(mediaPath, kind, ext string) string {
+	return BaseName(mediaPath) + "-" + kind + ext
+}
+
+// FolderArtworkPath returns the path of artwork named after its kind in a
+// folder, e.g. poster.jpg
+func FolderArtworkPath(dir, kind, ext string) string {
+	return filepath.Join(dir, kind+ext)
+}
+
+// ShowDir returns the show folder of an episode: its folder, or the parent
+// of its season folder
+func ShowDir(episodePath string) string {
+	dir := filepath.Dir(episodePath)
+	if seasonFolderPattern.MatchString(filepath.Base(dir)) {
+		return filepath.Dir(dir)
+	}
+	return dir
+}
+
+// FindMovieNFO returns the NFO of a movie file: one named after the file,
+// or movie.nfo in its folder
+func FindMovieNFO(mediaPath string) string {
+	return firstExisting(MediaNFOPath(mediaPath), filepath.Join(filepath.Dir(mediaPath), "movie.nfo"))
+}
+
+// FindMediaArtwork returns artwork of a kind for a media file: named after
+// the file, or, when the media has a folder of its own, after the kind
+func FindMediaArtwork(mediaPath, kind string, ownFolder bool) string {
+	var candidates []string
+	for _, ext := range imageExtensions {
+		candidates = append(candidates, MediaArtworkPath(mediaPath, kind, ext))
+	}
+	if ownFolder {
+		for _, ext := range imageExtensions {
+			candidates = append(candidates, FolderArtworkPath(filepath.Dir(mediaPath), kind, ext))
+		}
+	}
+	return firstExisting(candidates...)
+}
+
+// FindFolderArtwork returns artwork of a kind in a folder
+func FindFolderArtwork(dir, kind string) string {
+	var candidates []string
+	for _, ext := range imageExtensions {
+		candidates = append(candidates, FolderArtworkPath(dir, kind, ext))
+	}
+	return firstExisting(candidates...)
+}
+
+// Exists reports whether a file exists
+func Exists(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && !info.IsDir()
+}
+
+func firstExisting(paths ...string) string {
+	for _, path := range paths {
+		if Exists(path) {
+			return path
+		}
+	}
+	return ""
+}
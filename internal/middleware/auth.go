@@ -0,0 +1,433 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mantonx/viewra/internal/auth"
+	"github.com/mantonx/viewra/internal/database"
+	"github.com/mantonx/viewra/internal/logger"
+	"github.com/mantonx/viewra/internal/streamurl"
+	"gorm.io/gorm"
+)
+
+// publicRoutes can be used without signing in
+var publicRoutes = map[string]bool{
+	"/api/health":      true,
+	"/api/users/login": true,
+	"/api/users/setup": true,
+}
+
+// publicPrefixes authenticate some other way: feeds by their feed token
+var publicPrefixes = []string{
+	"/api/feeds/",
+}
+
+// userRoutes are the routes users other than admins may use: browsing and
+// playing the libraries they can see, and managing their own data. Every
+// other route is admin-only, so new routes stay admin-only until added here.
+var userRoutes = map[string]bool{
+	"GET /api/db-status": true,
+	"GET /api/search":    true,
+
+	"GET /api/announcements":                   true,
+	"POST /api/announcements/:id/dismiss":      true,
+	"GET /api/collections":                     true,
+	"GET /api/collections/:id":                 true,
+	"GET /api/collections/:id/poster":          true,
+	"GET /api/people":                          true,
+	"GET /api/people/:id":                      true,
+	"GET /api/people/:id/filmography":          true,
+	"GET /api/users/me":                        true,
+	"POST /api/users/logout":                   true,
+	"PUT /api/users/:id/password":              true,
+	"GET /api/users/:id/libraries":             true,
+	"GET /api/users/:id/parental":              true,
+	"POST /api/users/:id/parental/unlock":      true,
+	"POST /api/users/:id/parental/lock":        true,
+	"GET /api/users/:id/feed-tokens":           true,
+	"POST /api/users/:id/feed-tokens":          true,
+	"DELETE /api/users/:id/feed-tokens/:token": true,
+
+	"GET /api/media/":                             true,
+	"GET /api/media/:id":                          true,
+	"GET /api/media/:id/artwork":                  true,
+	"GET /api/media/:id/mediainfo":                true,
+	"GET /api/media/:id/metadata":                 true,
+	"GET /api/media/:id/stream":                   true,
+	"GET /api/media/:id/subtitles":                true,
+	"GET /api/media/albums/:id":                   true,
+	"GET /api/media/artists/:id":                  true,
+	"GET /api/media/composers":                    true,
+	"GET /api/media/composers/:name/works":        true,
+	"GET /api/media/files":                        true,
+	"GET /api/media/files/:id":                    true,
+	"GET /api/media/files/:id/album-artwork":      true,
+	"GET /api/media/files/:id/album-id":           true,
+	"GET /api/media/files/:id/metadata":           true,
+	"GET /api/media/files/:id/stream":             true,
+	"POST /api/media/files/:id/playback-decision": true,
+	"GET /api/media/home-videos":                  true,
+	"GET /api/media/home-videos/:id":              true,
+	"GET /api/media/home-videos/timeline":         true,
+	"GET /api/media/libraries":                    true,
+	"GET /api/media/libraries/:id/files":          true,
+	"GET /api/media/libraries/:id/stats":          true,
+	"PUT /api/media/libraries/:id/visibility":     true,
+	"GET /api/media/music":                        true,
+	"GET /api/media/recommendations":              true,
+	"GET /api/media/tracks":                       true,
+	"GET /api/media/tv-shows":                     true,
+	"GET /api/media/tv-shows/:id":                 true,
+	"GET /api/media/up-next":                      true,
+	"GET /api/media/favorites":                    true,
+	"POST /api/media/favorites":                   true,
+	"DELETE /api/media/favorites/:type/:id":       true,
+	"GET /api/media/favorites/collection":         true,
+	"GET /api/media/hidden":                       true,
+	"POST /api/media/hidden":                      true,
+	"DELETE /api/media/hidden/:id":                true,
+	"POST /api/media/playback/start":              true,
+	"POST /api/media/playback/progress":           true,
+	"POST /api/media/playback/end":                true,
+
+	"GET /api/v1/assets/:id":                                         true,
+	"GET /api/v1/assets/:id/data":                                    true,
+	"GET /api/v1/assets/:id/palette":                                 true,
+	"GET /api/v1/assets/entity/:type/:id":                            true,
+	"GET /api/v1/assets/entity/:type/:id/preferred/:asset_type":      true,
+	"GET /api/v1/assets/entity/:type/:id/preferred/:asset_type/data": true,
+	"GET /api/v1/assets/entity-types":                                true,
+	"GET /api/v1/assets/types":                                       true,
+	"GET /api/v1/assets/sources":                                     true,
+
+	"POST /api/playback/decide":                                 true,
+	"POST /api/playback/info":                                   true,
+	"POST /api/playback/start":                                  true,
+	"GET /api/playback/session/:sessionId":                      true,
+	"DELETE /api/playback/session/:sessionId":                   true,
+	"GET /api/playback/session/:sessionId/stats":                true,
+	"POST /api/playback/seek-ahead":                             true,
+	"POST /api/playback/audio/start":                            true,
+	"GET /api/playback/audio/info/:mediaFileId":                 true,
+	"GET /api/playback/subtitles/:mediaFileId":                  true,
+	"GET /api/playback/subtitles/:mediaFileId/:track":           true,
+	"GET /api/playback/markers/:mediaFileId":                    true,
+	"GET /api/playback/next/:mediaFileId":                       true,
+	"GET /api/playback/preferences/:userId":                     true,
+	"PUT /api/playback/preferences/:userId":                     true,
+	"GET /api/playback/devices":                                 true,
+	"POST /api/playback/devices":                                true,
+	"GET /api/playback/devices/:deviceId":                       true,
+	"DELETE /api/playback/devices/:deviceId":                    true,
+	"GET /api/playback/queues":                                  true,
+	"POST /api/playback/queues":                                 true,
+	"GET /api/playback/queues/:id":                              true,
+	"DELETE /api/playback/queues/:id":                           true,
+	"POST /api/playback/queues/:id/skip":                        true,
+	"GET /api/playback/ratings/:userId":                         true,
+	"PUT /api/playback/ratings/:userId/:mediaId":                true,
+	"DELETE /api/playback/ratings/:userId/:mediaId":             true,
+	"GET /api/playback/history/:userId/continue":                true,
+	"GET /api/playback/history/:userId/progress/:mediaId":       true,
+	"GET /api/playback/history/:userId/export":                  true,
+	"POST /api/playback/history/:userId/import":                 true,
+	"GET /api/playback/analytics/history":                       true,
+	"POST /api/playback/analytics/sessions":                     true,
+	"POST /api/playback/analytics/sessions/:id/progress":        true,
+	"POST /api/playback/analytics/sessions/:id/end":             true,
+	"GET /api/playback/stream/:sessionId":                       true,
+	"GET /api/playback/stream/:sessionId/manifest.mpd":          true,
+	"HEAD /api/playback/stream/:sessionId/manifest.mpd":         true,
+	"GET /api/playback/stream/:sessionId/master.m3u8":           true,
+	"HEAD /api/playback/stream/:sessionId/master.m3u8":          true,
+	"GET /api/playback/stream/:sessionId/playlist.m3u8":         true,
+	"HEAD /api/playback/stream/:sessionId/playlist.m3u8":        true,
+	"GET /api/playback/stream/:sessionId/segment/:segmentName":  true,
+	"HEAD /api/playback/stream/:sessionId/segment/:segmentName": true,
+	"GET /api/playback/stream/:sessionId/:segmentFile":          true,
+	"HEAD /api/playback/stream/:sessionId/:segmentFile":         true,
+}
+
+// userPrefixes are route prefixes users other than admins may use: plugin
+// routes, which plugins declare at runtime and guard themselves
+var userPrefixes = []string{
+	"/api/plugins/",
+}
+
+// itemParams are the path parameters and body fields naming a library item
+var itemParams = []string{"id", "mediaId", "mediaFileId", "media_file_id", "item_id", "media_id"}
+
+// pathFields are the body fields naming a file to play by path
+var pathFields = []string{"input_path", "media_path"}
+
+// PublicCheck reports whether a request is for a route that can be used
+// without signing in, for routes declared at runtime, such as plugin API
+// routes
+type PublicCheck func(c *gin.Context) bool
+
+// RequireAuth authenticates /api requests when authentication is enabled in
+// the security config, and keeps users other than admins to their own data
+// and the libraries they may see. Routes outside userRoutes are admin-only,
+// user IDs in paths, queries and bodies must be the caller's own (user_id
+// defaults to it), and for users restricted to some libraries, items,
+// libraries, files and transcode sessions named by the request are checked
+// against their grants. Requests any of publicChecks accept are let through.
+func RequireAuth(publicChecks ...PublicCheck) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		path := c.Request.URL.Path
+		if !auth.Enabled() || !strings.HasPrefix(path, "/api/") || isPublic(c, path) || anyPublic(c, publicChecks) {
+			c.Next()
+			return
+		}
+
+		db := database.GetDB()
+		token := auth.RequestToken(c)
+		if token == "" {
+			c.Header("WWW-Authenticate", `Bearer realm="viewra"`)
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Authentication required"})
+			return
+		}
+		user, session, err := auth.Authenticate(db, token, time.Now())
+		if err != nil {
+			c.Header("WWW-Authenticate", `Bearer realm="viewra", error="invalid_token"`)
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
+				"error":   "Invalid token",
+				"details": err.Error(),
+			})
+			return
+		}
+		auth.SetUser(c, user, session)
+
+		if user.IsAdmin() {
+			c.Next()
+			return
+		}
+		if isAdminRoute(c.Request.Method, c.FullPath()) {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "Admin access required"})
+			return
+		}
+
+		body, err := jsonBody(c)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "failed to read request body"})
+			return
+		}
+		if !scopeToUser(c, user, body) {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "Access to another user's data is not allowed"})
+			return
+		}
+
+		allowed, err := canAccessRequest(c, db, user, body)
+		if err != nil {
+			logger.Error("Failed to check library access of user %d to %s: %v", user.ID, path, err)
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "failed to check library access"})
+			return
+		}
+		if !allowed {
+			// Not found rather than forbidden, so restricted users can't
+			// learn what the libraries they can't see hold
+			c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"error": "Not found"})
+			return
+		}
+		c.Next()
+	}
+}
+
+func isPublic(c *gin.Context, path string) bool {
+	if publicRoutes[path] || publicRoutes[strings.TrimSuffix(path, "/")] {
+		return true
+	}
+	for _, prefix := range publicPrefixes {
+		if strings.HasPrefix(path, prefix) {
+			return true
+		}
+	}
+	// Signed stream URLs carry their own grant, issued when playback started
+	return strings.HasPrefix(path, "/api/playback/stream/") && streamurl.IsToken(c.Param("sessionId"))
+}
+
+func anyPublic(c *gin.Context, checks []PublicCheck) bool {
+	for _, check := range checks {
+		if check(c) {
+			return true
+		}
+	}
+	return false
+}
+
+// isAdminRoute reports whether a route is admin-only: any route not open to
+// other users. Requests matching no route are left to answer 404.
+func isAdminRoute(method, route string) bool {
+	if route == "" || userRoutes[method+" "+route] {
+		return false
+	}
+	for _, prefix := range userPrefixes {
+		if strings.HasPrefix(route, prefix) {
+			return false
+		}
+	}
+	return true
+}
+
+// jsonBody decodes a JSON object body, leaving the body in place for the
+// handler. Other bodies decode to nil.
+func jsonBody(c *gin.Context) (map[string]interface{}, error) {
+	if c.Request.Body == nil || !strings.HasPrefix(c.ContentType(), "application/json") {
+		return nil, nil
+	}
+	data, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		return nil, err
+	}
+	c.Request.Body = io.NopCloser(bytes.NewReader(data))
+
+	var body map[string]interface{}
+	if json.Unmarshal(data, &body) != nil {
+		return nil, nil
+	}
+	return body, nil
+}
+
+// scopeToUser checks that the user IDs a request names are the caller's,
+// and sets user_id to the caller when the query leaves it out
+func scopeToUser(c *gin.Context, user *database.User, body map[string]interface{}) bool {
+	self := strconv.FormatUint(uint64(user.ID), 10)
+
+	for _, param := range c.Params {
+		if param.Key == "userId" || (param.Key == "id" && strings.HasPrefix(c.FullPath(), "/api/users/:id")) {
+			if param.Value != self {
+				return false
+			}
+		}
+	}
+
+	query := c.Request.URL.Query()
+	switch query.Get("user_id") {
+	case self:
+	case "":
+		query.Set("user_id", self)
+		c.Request.URL.RawQuery = query.Encode()
+	default:
+		return false
+	}
+
+	if !bodyUserIs(body, self) {
+		return false
+	}
+	if profile, ok := body["device_profile"].(map[string]interface{}); ok && !bodyUserIs(profile, self) {
+		return false
+	}
+	return true
+}
+
+// bodyUserIs reports whether the user_id of a body, if any, is self
+func bodyUserIs(body map[string]interface{}, self string) bool {
+	value, ok := body["user_id"]
+	if !ok || value == nil {
+		return true
+	}
+	switch v := value.(type) {
+	case float64:
+		return v == 0 || strconv.FormatFloat(v, 'f', -1, 64) == self
+	case string:
+		return v == "" || v == self
+	}
+	return false
+}
+
+// canAccessRequest checks the libraries, items, files and transcode
+// sessions a request names against the libraries the user may see
+func canAccessRequest(c *gin.Context, db *gorm.DB, user *database.User, body map[string]interface{}) (bool, error) {
+	if !auth.Restricted(user) {
+		return true, nil
+	}
+	route := c.FullPath()
+
+	for _, param := range c.Params {
+		var allowed bool
+		var err error
+		switch {
+		case param.Key == "libraryId" ||
+			(param.Key == "id" && (strings.Contains(route, "/libraries/:id") || strings.Contains(route, "/library/:id"))):
+			allowed, err = canAccessLibraryID(db, user, param.Value)
+		case param.Key == "id" && strings.HasPrefix(route, "/api/users/:id"):
+			continue
+		case param.Key == "sessionId":
+			allowed, err = auth.CanAccessTranscodeSession(db, user, param.Value)
+		case isItemParam(param.Key):
+			allowed, err = auth.CanAccessItem(db, user, param.Value)
+		default:
+			continue
+		}
+		if err != nil || !allowed {
+			return false, err
+		}
+	}
+
+	query := c.Request.URL.Query()
+	if libraryID := query.Get("library_id"); libraryID != "" {
+		if allowed, err := canAccessLibraryID(db, user, libraryID); err != nil || !allowed {
+			return false, err
+		}
+	}
+	if mediaFileID := query.Get("media_file_id"); mediaFileID != "" {
+		if allowed, err := auth.CanAccessItem(db, user, mediaFileID); err != nil || !allowed {
+			return false, err
+		}
+	}
+
+	return canAccessBody(db, user, body)
+}
+
+// canAccessBody checks the items, libraries, files and sessions a JSON body
+// names, such as the media file a transcode is started for
+func canAccessBody(db *gorm.DB, user *database.User, body map[string]interface{}) (bool, error) {
+	for _, field := range itemParams {
+		if id, ok := body[field].(string); ok && id != "" {
+			if allowed, err := auth.CanAccessItem(db, user, id); err != nil || !allowed {
+				return false, err
+			}
+		}
+	}
+	for _, field := range pathFields {
+		if path, ok := body[field].(string); ok && path != "" {
+			if allowed, err := auth.CanAccessPath(db, user, path); err != nil || !allowed {
+				return false, err
+			}
+		}
+	}
+	if sessionID, ok := body["session_id"].(string); ok && sessionID != "" {
+		if allowed, err := auth.CanAccessTranscodeSession(db, user, sessionID); err != nil || !allowed {
+			return false, err
+		}
+	}
+	if libraryID, ok := body["library_id"].(float64); ok {
+		return auth.CanAccessLibrary(db, user, uint32(libraryID))
+	}
+	return true, nil
+}
+
+func canAccessLibraryID(db *gorm.DB, user *database.User, value string) (bool, error) {
+	libraryID, err := strconv.ParseUint(value, 10, 32)
+	if err != nil {
+		// Handlers reject malformed IDs themselves
+		return true, nil
+	}
+	return auth.CanAccessLibrary(db, user, uint32(libraryID))
+}
+
+func isItemParam(key string) bool {
+	for _, param := range itemParams {
+		if key == param {
+			return true
+		}
+	}
+	return false
+}
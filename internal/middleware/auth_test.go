@@ -0,0 +1,167 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mantonx/viewra/internal/auth"
+	"github.com/mantonx/viewra/internal/config"
+	"github.com/mantonx/viewra/internal/database"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// setupAuthTest enables authentication against an in-memory database with
+// an admin, an unrestricted user and a user restricted to library 1, and
+// returns a router with RequireAuth and the tokens of the three users
+func setupAuthTest(t *testing.T) (*gin.Engine, map[string]string) {
+	t.Setenv("VIEWRA_ENABLE_AUTH", "true")
+	t.Setenv("VIEWRA_JWT_SECRET", "test-secret")
+	require.NoError(t, config.Load(""))
+	t.Cleanup(func() {
+		os.Unsetenv("VIEWRA_ENABLE_AUTH")
+		os.Unsetenv("VIEWRA_JWT_SECRET")
+		config.Load("")
+	})
+
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	require.NoError(t, err)
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	sqlDB.SetMaxOpenConns(1)
+	require.NoError(t, db.AutoMigrate(&database.User{}, &database.AuthSession{}, &database.UserLibraryAccess{},
+		&database.MediaLibrary{}, &database.MediaFile{}, &database.Season{}, &database.Episode{}, &database.Track{}))
+	previous := database.DB
+	database.DB = db
+	t.Cleanup(func() { database.DB = previous })
+
+	require.NoError(t, db.Create(&[]database.MediaLibrary{
+		{ID: 1, Path: "/media/movies", Type: "movie"},
+		{ID: 2, Path: "/media/private", Type: "movie"},
+	}).Error)
+	require.NoError(t, db.Create(&[]database.MediaFile{
+		{ID: "file-1", MediaID: "movie-1", MediaType: database.MediaTypeMovie, LibraryID: 1, Path: "/media/movies/a.mkv"},
+		{ID: "file-2", MediaID: "movie-2", MediaType: database.MediaTypeMovie, LibraryID: 2, Path: "/media/private/b.mkv"},
+	}).Error)
+
+	users := []database.User{
+		{ID: 1, Username: "admin", Email: "admin@example.com", Role: database.UserRoleAdmin},
+		{ID: 2, Username: "user", Email: "user@example.com", Role: database.UserRoleUser},
+		{ID: 3, Username: "kid", Email: "kid@example.com", Role: database.UserRoleUser, RestrictLibraries: true},
+	}
+	require.NoError(t, db.Create(&users).Error)
+	require.NoError(t, auth.SetGrantedLibraries(db, 3, []uint32{1}))
+
+	tokens := make(map[string]string)
+	for i := range users {
+		grant, err := auth.StartSession(db, &users[i], "test", "127.0.0.1")
+		require.NoError(t, err)
+		tokens[users[i].Username] = grant.Token
+	}
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(RequireAuth(func(c *gin.Context) bool {
+		return c.Request.URL.Path == "/api/plugins/demo/public"
+	}))
+	ok := func(c *gin.Context) {
+		c.String(http.StatusOK, c.Query("user_id"))
+	}
+	router.GET("/health", ok)
+	router.GET("/api/feeds/recent/:token", ok)
+	router.GET("/api/users/", ok)
+	router.GET("/api/users/:id/libraries", ok)
+	router.GET("/api/media/files/:id", ok)
+	router.PUT("/api/media/files/:id/metadata", ok)
+	router.PUT("/api/media/home-videos/:id", ok)
+	router.GET("/api/media/libraries/:id/stats", ok)
+	router.GET("/api/media/", ok)
+	router.GET("/api/playback/analytics", ok)
+	router.GET("/api/playback/analytics/history", ok)
+	router.POST("/api/playback/start", ok)
+	router.GET("/api/system/resources", ok)
+	router.Any("/api/plugins/*path", ok)
+	return router, tokens
+}
+
+func TestRequireAuth(t *testing.T) {
+	router, tokens := setupAuthTest(t)
+
+	tests := []struct {
+		name       string
+		user       string
+		method     string
+		path       string
+		body       string
+		wantStatus int
+		wantUserID string
+	}{
+		{"outside api", "", "GET", "/health", "", http.StatusOK, ""},
+		{"public feed", "", "GET", "/api/feeds/recent/abc", "", http.StatusOK, ""},
+		{"no token", "", "GET", "/api/media/files/file-1", "", http.StatusUnauthorized, ""},
+		{"invalid token", "bogus", "GET", "/api/media/files/file-1", "", http.StatusUnauthorized, ""},
+		{"public plugin route", "", "GET", "/api/plugins/demo/public", "", http.StatusOK, ""},
+		{"private plugin route", "", "GET", "/api/plugins/demo/private", "", http.StatusUnauthorized, ""},
+		{"private plugin route with token", "user", "GET", "/api/plugins/demo/private", "", http.StatusOK, "2"},
+
+		{"admin route for admin", "admin", "GET", "/api/users/", "", http.StatusOK, ""},
+		{"admin route for user", "user", "GET", "/api/users/", "", http.StatusForbidden, ""},
+		{"metadata edit for user", "user", "PUT", "/api/media/files/file-1/metadata", "{}", http.StatusForbidden, ""},
+		{"home video edit for user", "user", "PUT", "/api/media/home-videos/hv-1", "{}", http.StatusForbidden, ""},
+		{"analytics for user", "user", "GET", "/api/playback/analytics", "", http.StatusForbidden, ""},
+		{"analytics for admin", "admin", "GET", "/api/playback/analytics", "", http.StatusOK, ""},
+		{"server resources for user", "user", "GET", "/api/system/resources", "", http.StatusForbidden, ""},
+		{"server resources for admin", "admin", "GET", "/api/system/resources", "", http.StatusOK, ""},
+
+		{"own libraries", "user", "GET", "/api/users/2/libraries", "", http.StatusOK, "2"},
+		{"other user's libraries", "user", "GET", "/api/users/3/libraries", "", http.StatusForbidden, ""},
+		{"user_id defaults to self", "user", "GET", "/api/playback/analytics/history", "", http.StatusOK, "2"},
+		{"own user_id", "user", "GET", "/api/playback/analytics/history?user_id=2", "", http.StatusOK, "2"},
+		{"other user_id", "user", "GET", "/api/playback/analytics/history?user_id=1", "", http.StatusForbidden, ""},
+		{"other user_id in body", "user", "POST", "/api/playback/start", `{"user_id": 1}`, http.StatusForbidden, ""},
+		{"other user_id in device profile", "user", "POST", "/api/playback/start", `{"device_profile": {"user_id": "1"}}`, http.StatusForbidden, ""},
+		{"admin reads any user", "admin", "GET", "/api/playback/analytics/history?user_id=3", "", http.StatusOK, "3"},
+
+		{"unrestricted user sees every library", "user", "GET", "/api/media/files/file-2", "", http.StatusOK, "2"},
+		{"granted file", "kid", "GET", "/api/media/files/file-1", "", http.StatusOK, "3"},
+		{"file in other library", "kid", "GET", "/api/media/files/file-2", "", http.StatusNotFound, ""},
+		{"movie in other library", "kid", "GET", "/api/media/files/movie-2", "", http.StatusNotFound, ""},
+		{"granted library", "kid", "GET", "/api/media/libraries/1/stats", "", http.StatusOK, "3"},
+		{"other library", "kid", "GET", "/api/media/libraries/2/stats", "", http.StatusNotFound, ""},
+		{"other library in query", "kid", "GET", "/api/media/?library_id=2", "", http.StatusNotFound, ""},
+		{"other file in query", "kid", "GET", "/api/media/?media_file_id=file-2", "", http.StatusNotFound, ""},
+		{"other file in body", "kid", "POST", "/api/playback/start", `{"media_file_id": "file-2"}`, http.StatusNotFound, ""},
+		{"other path in body", "kid", "POST", "/api/playback/start", `{"input_path": "/media/private/b.mkv"}`, http.StatusNotFound, ""},
+		{"granted path in body", "kid", "POST", "/api/playback/start", `{"input_path": "/media/movies/a.mkv"}`, http.StatusOK, "3"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			if tt.body != "" {
+				req.Header.Set("Content-Type", "application/json")
+			}
+			if tt.user != "" {
+				token, known := tokens[tt.user]
+				if !known {
+					token = tt.user
+				}
+				req.Header.Set("Authorization", "Bearer "+token)
+			}
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			require.Equal(t, tt.wantStatus, w.Code, w.Body.String())
+			if tt.wantStatus == http.StatusOK && tt.wantUserID != "" {
+				require.Equal(t, tt.wantUserID, w.Body.String())
+			}
+		})
+	}
+}
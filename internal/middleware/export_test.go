@@ -0,0 +1,8 @@
+package middleware
+
+// Exported for the route tests, which build the server's router and so live
+// in middleware_test to avoid an import cycle
+var (
+	UserRoutes   = userRoutes
+	IsAdminRoute = isAdminRoute
+)
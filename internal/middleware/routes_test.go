@@ -0,0 +1,89 @@
+package middleware_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mantonx/viewra/internal/database"
+	"github.com/mantonx/viewra/internal/middleware"
+	"github.com/mantonx/viewra/internal/server"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// TestAdminRoutes walks the routes the server registers, checking that
+// every route open to users other than admins is a registered one and
+// that routes changing server state are admin-only
+func TestAdminRoutes(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	require.NoError(t, err)
+	previous := database.DB
+	database.DB = db
+	t.Cleanup(func() { database.DB = previous })
+
+	// Modules create their data directories below the working directory
+	t.Chdir(t.TempDir())
+	gin.SetMode(gin.TestMode)
+	registered := make(map[string]bool)
+	for _, route := range server.SetupRouter().Routes() {
+		registered[route.Method+" "+route.Path] = true
+	}
+
+	for route := range middleware.UserRoutes {
+		require.True(t, registered[route], "%s is open to users but not registered", route)
+	}
+
+	tests := []struct {
+		route string
+		admin bool
+	}{
+		{"PUT /api/media/:id/provenance/:field/lock", true},
+		{"POST /api/media/:id/enrichment/rollback", true},
+		{"POST /api/media/:id/identify", true},
+		{"GET /api/media/:id/identify/search", true},
+		{"POST /api/v1/assets/", true},
+		{"PUT /api/v1/assets/:id/preferred", true},
+		{"DELETE /api/v1/assets/:id", true},
+		{"DELETE /api/v1/assets/entity/:type/:id", true},
+		{"POST /api/v1/assets/cleanup", true},
+		{"POST /api/v1/assets/gc", true},
+		{"POST /api/v1/assets/retention/apply", true},
+		{"POST /api/v1/assets/palettes/backfill", true},
+		{"POST /api/v1/assets/text-detection/backfill", true},
+		{"GET /api/system/resources", true},
+		{"GET /api/system/resources/alerts", true},
+		{"POST /api/v1/events/clear", true},
+		{"POST /api/v1/dashboard/sections/:sectionId/actions/:actionId", true},
+		{"GET /api/users/", true},
+		{"PUT /api/users/:id/libraries", true},
+		{"POST /api/media/libraries", true},
+		{"PUT /api/media/files/:id/metadata", true},
+		{"GET /api/playback/sessions", true},
+		{"GET /api/playback/session/:sessionId/logs", true},
+		{"POST /api/admin/organizer/apply", true},
+		{"GET /api/config/", true},
+		{"POST /api/scan/start", true},
+		{"POST /api/scanner/scan", true},
+
+		{"GET /api/media/", false},
+		{"GET /api/media/files/:id/stream", false},
+		{"GET /api/v1/assets/entity/:type/:id/preferred/:asset_type/data", false},
+		{"GET /api/search", false},
+		{"POST /api/media/favorites", false},
+		{"POST /api/playback/start", false},
+		{"GET /api/playback/stream/:sessionId/master.m3u8", false},
+		{"PUT /api/playback/preferences/:userId", false},
+		{"GET /api/users/me", false},
+		{"GET /api/plugins/*path", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.route, func(t *testing.T) {
+			require.True(t, registered[tt.route], "route not registered")
+			method, path, _ := strings.Cut(tt.route, " ")
+			require.Equal(t, tt.admin, middleware.IsAdminRoute(method, path))
+		})
+	}
+}
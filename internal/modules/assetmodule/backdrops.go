@@ -0,0 +1,199 @@
+package assetmodule
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/google/uuid"
+	"github.com/mantonx/viewra/internal/database"
+)
+
+// textDetectTypes returns the asset types checked for embedded text
+func textDetectTypes() []AssetType {
+	types := make([]AssetType, 0, len(textDetectAssetTypes))
+	for assetType := range textDetectAssetTypes {
+		types = append(types, assetType)
+	}
+	return types
+}
+
+// applyBackdropPreference makes sure a textless backdrop is preferred over
+// one with title text when the entity's library asks for it. It runs after a
+// backdrop is saved and updates asset.Preferred if the asset lost or gained
+// the preference.
+func (m *Manager) applyBackdropPreference(asset *MediaAsset) {
+	if !detectsText(asset.Type) {
+		return
+	}
+	preferredID, err := m.selectTextlessBackdrop(asset.EntityType, asset.EntityID, asset.Type)
+	if err != nil {
+		log.Printf("WARNING: Failed to apply textless backdrop preference for %s/%s: %v", asset.EntityType, asset.EntityID, err)
+		return
+	}
+	if preferredID != uuid.Nil {
+		asset.Preferred = preferredID == asset.ID
+	}
+}
+
+// selectTextlessBackdrop replaces an entity's preferred backdrop with the
+// largest textless one when the preferred backdrop has text and the library
+// prefers textless backdrops. It returns the ID of the newly preferred asset,
+// or uuid.Nil when nothing changed.
+func (m *Manager) selectTextlessBackdrop(entityType EntityType, entityID uuid.UUID, assetType AssetType) (uuid.UUID, error) {
+	var preferred []MediaAsset
+	err := m.db.Where("entity_type = ? AND entity_id = ? AND type = ? AND preferred = ?",
+		entityType, entityID, assetType, true).Limit(1).Find(&preferred).Error
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("failed to find preferred asset: %w", err)
+	}
+	if len(preferred) == 0 || preferred[0].HasText == nil || !*preferred[0].HasText {
+		return uuid.Nil, nil
+	}
+
+	libraryID, ok := m.entityLibrary(entityType, entityID)
+	if !ok || !m.prefersTextlessBackdrops(libraryID) {
+		return uuid.Nil, nil
+	}
+
+	var textless []MediaAsset
+	err = m.db.Where("entity_type = ? AND entity_id = ? AND type = ? AND has_text = ?",
+		entityType, entityID, assetType, false).
+		Order("width DESC").Limit(1).Find(&textless).Error
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("failed to find textless assets: %w", err)
+	}
+	if len(textless) == 0 {
+		return uuid.Nil, nil
+	}
+
+	if err := m.SetPreferredAsset(textless[0].ID); err != nil {
+		return uuid.Nil, err
+	}
+	log.Printf("INFO: Preferred textless %s %s over %s for entity %s/%s",
+		assetType, textless[0].ID, preferred[0].ID, entityType, entityID)
+	return textless[0].ID, nil
+}
+
+// SelectTextlessBackdrops applies the textless backdrop preference to
+// existing artwork, e.g. after a library turns it on. A libraryID of 0 checks
+// every library. It returns how many preferred backdrops were replaced.
+func (m *Manager) SelectTextlessBackdrops(libraryID uint32) (int, error) {
+	var assets []MediaAsset
+	err := m.db.Where("preferred = ? AND has_text = ? AND type IN ?", true, true, textDetectTypes()).
+		Find(&assets).Error
+	if err != nil {
+		return 0, fmt.Errorf("failed to find backdrops with text: %w", err)
+	}
+
+	switched := 0
+	for _, asset := range assets {
+		if libraryID != 0 {
+			if id, ok := m.entityLibrary(asset.EntityType, asset.EntityID); !ok || id != libraryID {
+				continue
+			}
+		}
+		preferredID, err := m.selectTextlessBackdrop(asset.EntityType, asset.EntityID, asset.Type)
+		if err != nil {
+			log.Printf("WARNING: Failed to select textless backdrop for %s/%s: %v", asset.EntityType, asset.EntityID, err)
+			continue
+		}
+		if preferredID != uuid.Nil {
+			switched++
+		}
+	}
+	return switched, nil
+}
+
+// BackfillTextDetection checks backdrops saved before text detection was
+// recorded, then applies library textless preferences to them. It returns
+// how many assets were analyzed.
+func (m *Manager) BackfillTextDetection() (int, error) {
+	var assets []MediaAsset
+	if err := m.db.Where("has_text IS NULL AND type IN ?", textDetectTypes()).Find(&assets).Error; err != nil {
+		return 0, fmt.Errorf("failed to find unanalyzed backdrops: %w", err)
+	}
+
+	updated := 0
+	for i := range assets {
+		if err := m.detectStoredText(&assets[i]); err != nil {
+			log.Printf("WARNING: Failed to detect text for asset %s: %v", assets[i].ID, err)
+			continue
+		}
+		updated++
+	}
+
+	if updated > 0 {
+		if _, err := m.SelectTextlessBackdrops(0); err != nil {
+			return updated, err
+		}
+	}
+	return updated, nil
+}
+
+// detectStoredText decodes an asset's file, checks it for text and saves
+// the result on the record
+func (m *Manager) detectStoredText(asset *MediaAsset) error {
+	data, err := os.ReadFile(filepath.Join(m.assetsPath, asset.Path))
+	if err != nil {
+		return fmt.Errorf("failed to read asset file: %w", err)
+	}
+	img, err := decodeImage(data, asset.Format)
+	if err != nil {
+		return err
+	}
+	score, hasText := DetectText(img)
+	// Skip hooks and updated_at: the asset itself hasn't changed
+	err = m.db.Model(asset).UpdateColumns(map[string]interface{}{
+		"has_text":   hasText,
+		"text_score": score,
+	}).Error
+	if err != nil {
+		return fmt.Errorf("failed to save text detection: %w", err)
+	}
+	asset.HasText, asset.TextScore = &hasText, score
+	return nil
+}
+
+// entityLibrary returns the library holding the media an entity belongs to.
+// Only entities backed by media files (movies, shows, episodes, home videos,
+// artists and albums) have a library.
+func (m *Manager) entityLibrary(entityType EntityType, entityID uuid.UUID) (uint32, bool) {
+	query := m.db.Table("media_files")
+	switch entityType {
+	case EntityTypeMovie, EntityTypeEpisode, EntityTypeHomeVideo:
+		query = query.Where("media_files.media_id = ?", entityID.String())
+	case EntityTypeTVShow:
+		query = query.
+			Joins("JOIN episodes ON episodes.id = media_files.media_id").
+			Joins("JOIN seasons ON seasons.id = episodes.season_id").
+			Where("seasons.tv_show_id = ?", entityID.String())
+	case EntityTypeArtist:
+		query = query.
+			Joins("JOIN tracks ON tracks.id = media_files.media_id").
+			Where("tracks.artist_id = ?", entityID.String())
+	case EntityTypeAlbum:
+		query = query.
+			Joins("JOIN tracks ON tracks.id = media_files.media_id").
+			Where("tracks.album_id = ?", entityID.String())
+	default:
+		return 0, false
+	}
+
+	var libraryIDs []uint32
+	if err := query.Limit(1).Pluck("media_files.library_id", &libraryIDs).Error; err != nil || len(libraryIDs) == 0 {
+		return 0, false
+	}
+	return libraryIDs[0], true
+}
+
+// prefersTextlessBackdrops reports whether a library asks for backdrops
+// without embedded text
+func (m *Manager) prefersTextlessBackdrops(libraryID uint32) bool {
+	var settings []database.LibraryArtworkSettings
+	if err := m.db.Where("library_id = ?", libraryID).Limit(1).Find(&settings).Error; err != nil || len(settings) == 0 {
+		return false
+	}
+	return settings[0].PreferTextlessBackdrops
+}
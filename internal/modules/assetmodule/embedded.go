@@ -0,0 +1,78 @@
+package assetmodule
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/google/uuid"
+	"github.com/mantonx/viewra/internal/config"
+)
+
+// defaultEmbeddedArtMinSize is used when no configuration is loaded
+const defaultEmbeddedArtMinSize = 500
+
+// EmbeddedArtMinSize returns the shortest side, in pixels, album art
+// embedded in music files needs to be used instead of remote cover art. 0
+// accepts embedded art of any size.
+func EmbeddedArtMinSize() int {
+	if cfg := config.Get(); cfg != nil {
+		return cfg.Assets.EmbeddedArtMinSize
+	}
+	return defaultEmbeddedArtMinSize
+}
+
+// isEmbeddedCover reports whether an asset is album art extracted from a
+// music file
+func isEmbeddedCover(entityType EntityType, assetType AssetType, source AssetSource) bool {
+	return entityType == EntityTypeAlbum && assetType == AssetTypeCover && source == SourceEmbedded
+}
+
+// IsLowResolutionEmbeddedCover reports whether an asset is embedded album art
+// too small to stand in for remote cover art
+func IsLowResolutionEmbeddedCover(asset *AssetResponse) bool {
+	return isEmbeddedCover(asset.EntityType, asset.Type, asset.Source) &&
+		min(asset.Width, asset.Height) < EmbeddedArtMinSize()
+}
+
+// EmbeddedCover returns an album's embedded cover when it is large enough to
+// stand in for remote cover art, or nil when the album has none or only a
+// low resolution one
+func (m *Manager) EmbeddedCover(albumID uuid.UUID) (*AssetResponse, error) {
+	var covers []MediaAsset
+	err := m.db.Where("entity_type = ? AND entity_id = ? AND type = ? AND source = ?",
+		EntityTypeAlbum, albumID, AssetTypeCover, SourceEmbedded).
+		Order("width DESC").Limit(1).Find(&covers).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to find embedded cover: %w", err)
+	}
+	if len(covers) == 0 {
+		return nil, nil
+	}
+	cover := m.buildAssetResponse(&covers[0])
+	if IsLowResolutionEmbeddedCover(cover) {
+		return nil, nil
+	}
+	return cover, nil
+}
+
+// keepLargerCover stops low resolution embedded album art from taking the
+// preference from a larger cover saved from another source, so rescanning a
+// library doesn't undo the remote art fetched to replace it. It runs once
+// the request's image size is known.
+func (m *Manager) keepLargerCover(request *AssetRequest) {
+	if !request.Preferred || !isEmbeddedCover(request.EntityType, request.Type, request.Source) ||
+		min(request.Width, request.Height) >= EmbeddedArtMinSize() {
+		return
+	}
+
+	var preferred []MediaAsset
+	err := m.db.Where("entity_type = ? AND entity_id = ? AND type = ? AND source <> ? AND preferred = ?",
+		request.EntityType, request.EntityID, request.Type, SourceEmbedded, true).Limit(1).Find(&preferred).Error
+	if err != nil {
+		log.Printf("WARNING: Failed to find preferred cover for album %s: %v", request.EntityID, err)
+		return
+	}
+	if len(preferred) > 0 && preferred[0].Width*preferred[0].Height > request.Width*request.Height {
+		request.Preferred = false
+	}
+}
@@ -0,0 +1,188 @@
+package assetmodule
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// liveEntityQueries select the IDs of entities still backed by a media file,
+// by the entity type their assets are saved under. A movie, episode or track
+// row outlives its deleted file, so the row existing isn't enough. Entity
+// types without a table here are never treated as orphaned.
+var liveEntityQueries = map[EntityType]string{
+	EntityTypeMovie:     "SELECT media_id FROM media_files WHERE media_type = 'movie'",
+	EntityTypeEpisode:   "SELECT media_id FROM media_files WHERE media_type = 'episode'",
+	EntityTypeTrack:     "SELECT media_id FROM media_files WHERE media_type = 'track'",
+	EntityTypeHomeVideo: "SELECT media_id FROM media_files WHERE media_type = 'home_video'",
+	EntityTypeTVShow: `SELECT seasons.tv_show_id FROM seasons
+		JOIN episodes ON episodes.season_id = seasons.id
+		JOIN media_files ON media_files.media_id = episodes.id`,
+	EntityTypeAlbum: `SELECT tracks.album_id FROM tracks
+		JOIN media_files ON media_files.media_id = tracks.id
+		UNION SELECT albums.parent_album_id FROM albums
+		JOIN tracks ON tracks.album_id = albums.id
+		JOIN media_files ON media_files.media_id = tracks.id
+		WHERE albums.parent_album_id IS NOT NULL`,
+	EntityTypeArtist: `SELECT tracks.artist_id FROM tracks
+		JOIN media_files ON media_files.media_id = tracks.id
+		UNION SELECT albums.artist_id FROM albums
+		JOIN tracks ON tracks.album_id = albums.id
+		JOIN media_files ON media_files.media_id = tracks.id`,
+	EntityTypeActor: `SELECT roles.person_id FROM roles
+		JOIN media_files ON media_files.media_id = roles.media_id`,
+	EntityTypeDirector: `SELECT roles.person_id FROM roles
+		JOIN media_files ON media_files.media_id = roles.media_id`,
+	EntityTypePerson: `SELECT roles.person_id FROM roles
+		JOIN media_files ON media_files.media_id = roles.media_id`,
+}
+
+// OrphanedAsset records when garbage collection first found an asset whose
+// entity no longer has any media. The asset is deleted once it has stayed
+// orphaned for the retention window, so an item that's only missing while a
+// drive is unmounted or a file is re-matched keeps its artwork.
+type OrphanedAsset struct {
+	AssetID     uuid.UUID `gorm:"type:uuid;primaryKey" json:"asset_id"`
+	FirstSeenAt time.Time `gorm:"not null" json:"first_seen_at"`
+}
+
+// TableName returns the table name for OrphanedAsset
+func (OrphanedAsset) TableName() string {
+	return "media_asset_orphans"
+}
+
+// GCOrphan is an orphaned asset found by garbage collection
+type GCOrphan struct {
+	AssetID     uuid.UUID  `json:"asset_id"`
+	EntityType  EntityType `json:"entity_type"`
+	EntityID    uuid.UUID  `json:"entity_id"`
+	Type        AssetType  `json:"type"`
+	Path        string     `json:"path"`
+	SizeBytes   int64      `json:"size_bytes"`
+	FirstSeenAt time.Time  `json:"first_seen_at"`
+	DeleteAfter time.Time  `json:"delete_after"`
+	Deleted     bool       `json:"deleted"`
+}
+
+// GCReport summarizes a garbage collection run
+type GCReport struct {
+	DryRun           bool       `json:"dry_run"`
+	RetentionHours   int        `json:"retention_hours"`
+	OrphanedAssets   int        `json:"orphaned_assets"`
+	ReclaimableBytes int64      `json:"reclaimable_bytes"` // Freed if every orphan were deleted
+	DeletedAssets    int        `json:"deleted_assets"`
+	FreedBytes       int64      `json:"freed_bytes"`
+	Orphans          []GCOrphan `json:"orphans"`
+	StartedAt        time.Time  `json:"started_at"`
+	CompletedAt      time.Time  `json:"completed_at"`
+}
+
+// CollectGarbage finds assets whose entity no longer has any media and deletes
+// those that have been orphaned for longer than retention. Assets that have
+// regained their media are forgotten. A dry run only reports what would be
+// deleted, without recording or deleting anything.
+func (m *Manager) CollectGarbage(retention time.Duration, dryRun bool) (*GCReport, error) {
+	if !m.initialized {
+		return nil, fmt.Errorf("asset manager not initialized")
+	}
+
+	report := &GCReport{
+		DryRun:         dryRun,
+		RetentionHours: int(retention / time.Hour),
+		Orphans:        []GCOrphan{},
+		StartedAt:      time.Now(),
+	}
+
+	orphans, err := m.findOrphanedAssets()
+	if err != nil {
+		return nil, err
+	}
+
+	var tracked []OrphanedAsset
+	if err := m.db.Find(&tracked).Error; err != nil {
+		return nil, fmt.Errorf("failed to fetch orphaned assets: %w", err)
+	}
+	firstSeen := make(map[uuid.UUID]time.Time, len(tracked))
+	for _, orphan := range tracked {
+		firstSeen[orphan.AssetID] = orphan.FirstSeenAt
+	}
+
+	orphaned := make(map[uuid.UUID]bool, len(orphans))
+	for _, asset := range orphans {
+		orphaned[asset.ID] = true
+
+		seenAt, ok := firstSeen[asset.ID]
+		if !ok {
+			seenAt = report.StartedAt
+			if !dryRun {
+				if err := m.db.Create(&OrphanedAsset{AssetID: asset.ID, FirstSeenAt: seenAt}).Error; err != nil {
+					return nil, fmt.Errorf("failed to record orphaned asset: %w", err)
+				}
+			}
+		}
+
+		orphan := GCOrphan{
+			AssetID:     asset.ID,
+			EntityType:  asset.EntityType,
+			EntityID:    asset.EntityID,
+			Type:        asset.Type,
+			Path:        asset.Path,
+			FirstSeenAt: seenAt,
+			DeleteAfter: seenAt.Add(retention),
+		}
+		// Files kept for enrichment history aren't freed by deleting the asset
+		if !m.retainedByHistory(asset.Path) {
+			if info, err := os.Stat(filepath.Join(m.assetsPath, asset.Path)); err == nil {
+				orphan.SizeBytes = info.Size()
+			}
+		}
+		report.ReclaimableBytes += orphan.SizeBytes
+
+		if !dryRun && !report.StartedAt.Before(orphan.DeleteAfter) {
+			if err := m.RemoveAsset(asset.ID); err != nil {
+				log.Printf("WARNING: Failed to remove orphaned asset %s: %v", asset.ID, err)
+			} else {
+				m.db.Where("asset_id = ?", asset.ID).Delete(&OrphanedAsset{})
+				orphan.Deleted = true
+				report.DeletedAssets++
+				report.FreedBytes += orphan.SizeBytes
+			}
+		}
+		report.Orphans = append(report.Orphans, orphan)
+	}
+	report.OrphanedAssets = len(report.Orphans)
+
+	// Forget assets whose media came back, or that were removed some other way
+	if !dryRun {
+		for _, orphan := range tracked {
+			if !orphaned[orphan.AssetID] {
+				m.db.Where("asset_id = ?", orphan.AssetID).Delete(&OrphanedAsset{})
+			}
+		}
+	}
+
+	report.CompletedAt = time.Now()
+	return report, nil
+}
+
+// findOrphanedAssets returns the assets whose entity no longer has any media
+func (m *Manager) findOrphanedAssets() ([]MediaAsset, error) {
+	var orphans []MediaAsset
+	for _, entityType := range GetValidEntityTypes() {
+		query, ok := liveEntityQueries[entityType]
+		if !ok {
+			continue
+		}
+		var assets []MediaAsset
+		if err := m.db.Where("entity_type = ? AND entity_id NOT IN ("+query+")", entityType).
+			Order("created_at").Find(&assets).Error; err != nil {
+			return nil, fmt.Errorf("failed to find orphaned %s assets: %w", entityType, err)
+		}
+		orphans = append(orphans, assets...)
+	}
+	return orphans, nil
+}
@@ -16,6 +16,7 @@ import (
 
 	"github.com/chai2010/webp"
 	"github.com/google/uuid"
+	"github.com/mantonx/viewra/internal/database"
 	"github.com/mantonx/viewra/internal/events"
 	"gorm.io/gorm"
 )
@@ -75,6 +76,13 @@ func (m *Manager) ensureDirectoryStructure() error {
 	return nil
 }
 
+// imageAnalysis holds what SaveAsset derives from a decoded image
+type imageAnalysis struct {
+	palette   *ColorPalette
+	hasText   *bool
+	textScore float64
+}
+
 // SaveAsset saves a media asset to filesystem and database
 func (m *Manager) SaveAsset(request *AssetRequest) (*AssetResponse, error) {
 	if !m.initialized {
@@ -85,17 +93,32 @@ func (m *Manager) SaveAsset(request *AssetRequest) (*AssetResponse, error) {
 		return nil, fmt.Errorf("invalid request: %w", err)
 	}
 
-	// Convert image to WebP format with high quality (95)
-	webpData, width, height, err := m.convertToWebP(request.Data, request.Format, 95)
-	if err != nil {
-		return nil, fmt.Errorf("failed to convert image to WebP: %w", err)
-	}
+	// Text assets are stored as-is; images are normalized to WebP
+	var analysis imageAnalysis
+	if !IsSupportedTextFormat(request.Format) {
+		// Convert image to WebP format with high quality (95)
+		webpData, img, err := m.convertToWebP(request.Data, request.Format, 95)
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert image to WebP: %w", err)
+		}
 
-	// Update request with WebP data and format
-	request.Data = webpData
-	request.Format = "image/webp"
-	request.Width = width
-	request.Height = height
+		// Update request with WebP data and format
+		request.Data = webpData
+		request.Format = "image/webp"
+		request.Width = img.Bounds().Dx()
+		request.Height = img.Bounds().Dy()
+
+		// Artwork carries its colour palette so clients can theme around it
+		if hasPalette(request.Type) {
+			analysis.palette = ExtractPalette(img)
+		}
+		// Backdrops are checked for title text so logos can go on clean ones
+		if detectsText(request.Type) {
+			score, hasText := DetectText(img)
+			analysis.hasText, analysis.textScore = &hasText, score
+		}
+	}
+	m.keepLargerCover(request)
 
 	// Generate asset path using hash-based organization
 	relativePath, err := m.generateHashedAssetPath(request)
@@ -105,12 +128,12 @@ func (m *Manager) SaveAsset(request *AssetRequest) (*AssetResponse, error) {
 
 	// Check if asset already exists
 	var existing MediaAsset
-	err = m.db.Where("entity_type = ? AND entity_id = ? AND type = ? AND source = ?",
-		request.EntityType, request.EntityID, request.Type, request.Source).First(&existing).Error
+	err = m.db.Where("entity_type = ? AND entity_id = ? AND type = ? AND source = ? AND variant = ?",
+		request.EntityType, request.EntityID, request.Type, request.Source, request.Variant).First(&existing).Error
 
 	if err == nil {
 		// Asset exists, update it
-		return m.updateExistingAsset(&existing, request, relativePath)
+		return m.updateExistingAsset(&existing, request, relativePath, analysis)
 	}
 
 	if err != gorm.ErrRecordNotFound {
@@ -153,6 +176,10 @@ func (m *Manager) SaveAsset(request *AssetRequest) (*AssetResponse, error) {
 		Format:     request.Format,
 		Preferred:  request.Preferred,
 		Language:   request.Language,
+		Variant:    request.Variant,
+		Palette:    analysis.palette,
+		HasText:    analysis.hasText,
+		TextScore:  analysis.textScore,
 
 		// Optional compatibility fields
 		SizeBytes:  int64(len(request.Data)),
@@ -171,40 +198,43 @@ func (m *Manager) SaveAsset(request *AssetRequest) (*AssetResponse, error) {
 	log.Printf("INFO: Saved new asset: entity=%s/%s type=%s source=%s preferred=%v path=%s",
 		request.EntityType, request.EntityID, request.Type, request.Source, request.Preferred, relativePath)
 
+	m.applyBackdropPreference(asset)
+
 	// Publish event
 	m.publishAssetEvent(events.EventAssetCreated, asset)
 
+	m.applyRetentionPolicy(asset)
+
 	return m.buildAssetResponse(asset), nil
 }
 
-// convertToWebP converts an image to WebP format with specified quality
-func (m *Manager) convertToWebP(data []byte, originalFormat string, quality int) ([]byte, int, int, error) {
-	// If already WebP, just decode to get dimensions and re-encode with specified quality
-	if originalFormat == "image/webp" {
-		img, err := webp.Decode(bytes.NewReader(data))
-		if err != nil {
-			return nil, 0, 0, fmt.Errorf("failed to decode WebP image: %w", err)
-		}
-
-		bounds := img.Bounds()
-		width := bounds.Dx()
-		height := bounds.Dy()
-
-		// Re-encode with specified quality
-		var buf bytes.Buffer
-		if err := webp.Encode(&buf, img, &webp.Options{Quality: float32(quality)}); err != nil {
-			return nil, 0, 0, fmt.Errorf("failed to encode WebP image: %w", err)
-		}
+// convertToWebP converts an image to WebP format with specified quality and
+// returns the decoded source image along with the encoded data
+func (m *Manager) convertToWebP(data []byte, originalFormat string, quality int) ([]byte, image.Image, error) {
+	img, err := decodeImage(data, originalFormat)
+	if err != nil {
+		return nil, nil, err
+	}
 
-		return buf.Bytes(), width, height, nil
+	// Encode as WebP
+	var buf bytes.Buffer
+	options := &webp.Options{Quality: float32(quality)}
+	if err := webp.Encode(&buf, img, options); err != nil {
+		return nil, nil, fmt.Errorf("failed to encode as WebP: %w", err)
 	}
 
-	// Decode original image
+	return buf.Bytes(), img, nil
+}
+
+// decodeImage decodes image data of the given MIME type
+func decodeImage(data []byte, format string) (image.Image, error) {
 	var img image.Image
 	var err error
 
 	reader := bytes.NewReader(data)
-	switch originalFormat {
+	switch format {
+	case "image/webp":
+		img, err = webp.Decode(reader)
 	case "image/jpeg", "image/jpg":
 		img, err = jpeg.Decode(reader)
 	case "image/png":
@@ -217,22 +247,9 @@ func (m *Manager) convertToWebP(data []byte, originalFormat string, quality int)
 	}
 
 	if err != nil {
-		return nil, 0, 0, fmt.Errorf("failed to decode image: %w", err)
-	}
-
-	// Get image dimensions
-	bounds := img.Bounds()
-	width := bounds.Dx()
-	height := bounds.Dy()
-
-	// Encode as WebP
-	var buf bytes.Buffer
-	options := &webp.Options{Quality: float32(quality)}
-	if err := webp.Encode(&buf, img, options); err != nil {
-		return nil, 0, 0, fmt.Errorf("failed to encode as WebP: %w", err)
+		return nil, fmt.Errorf("failed to decode image: %w", err)
 	}
-
-	return buf.Bytes(), width, height, nil
+	return img, nil
 }
 
 // GetAssetDataWithQuality retrieves the binary data for an asset with optional quality adjustment
@@ -272,6 +289,69 @@ func (m *Manager) GetAssetDataWithQuality(id uuid.UUID, quality int) ([]byte, st
 	return data, asset.Format, nil
 }
 
+// GetAssetPalette returns an asset's colour palette, extracting and storing
+// it first for artwork saved before palettes were recorded
+func (m *Manager) GetAssetPalette(id uuid.UUID) (*ColorPalette, error) {
+	var asset MediaAsset
+	if err := m.db.First(&asset, "id = ?", id).Error; err != nil {
+		return nil, fmt.Errorf("asset not found: %w", err)
+	}
+	if asset.Palette != nil {
+		return asset.Palette, nil
+	}
+	if !hasPalette(asset.Type) {
+		return nil, fmt.Errorf("asset type %s has no palette", asset.Type)
+	}
+	return m.extractStoredPalette(&asset)
+}
+
+// BackfillPalettes extracts palettes for artwork saved before they were
+// recorded and returns how many assets were updated
+func (m *Manager) BackfillPalettes() (int, error) {
+	types := make([]AssetType, 0, len(paletteAssetTypes))
+	for assetType := range paletteAssetTypes {
+		types = append(types, assetType)
+	}
+
+	var assets []MediaAsset
+	if err := m.db.Where("palette IS NULL AND type IN ?", types).Find(&assets).Error; err != nil {
+		return 0, fmt.Errorf("failed to find assets without palettes: %w", err)
+	}
+
+	updated := 0
+	for i := range assets {
+		if _, err := m.extractStoredPalette(&assets[i]); err != nil {
+			log.Printf("WARNING: Failed to extract palette for asset %s: %v", assets[i].ID, err)
+			continue
+		}
+		updated++
+	}
+	return updated, nil
+}
+
+// extractStoredPalette decodes an asset's file, extracts its palette and
+// saves it on the record
+func (m *Manager) extractStoredPalette(asset *MediaAsset) (*ColorPalette, error) {
+	data, err := os.ReadFile(filepath.Join(m.assetsPath, asset.Path))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read asset file: %w", err)
+	}
+	img, err := decodeImage(data, asset.Format)
+	if err != nil {
+		return nil, err
+	}
+	palette := ExtractPalette(img)
+	if palette == nil {
+		return nil, fmt.Errorf("image has no opaque pixels")
+	}
+	// Skip hooks and updated_at: the asset itself hasn't changed
+	if err := m.db.Model(asset).UpdateColumn("palette", palette).Error; err != nil {
+		return nil, fmt.Errorf("failed to save palette: %w", err)
+	}
+	asset.Palette = palette
+	return palette, nil
+}
+
 // generateHashedAssetPath creates a hash-based relative path for an asset to prevent conflicts
 func (m *Manager) generateHashedAssetPath(request *AssetRequest) (string, error) {
 	// Create a hash from entity information for the subdirectory
@@ -282,6 +362,9 @@ func (m *Manager) generateHashedAssetPath(request *AssetRequest) (string, error)
 
 	// All images are now WebP, so use .webp extension
 	fileExt := ".webp"
+	if IsSupportedTextFormat(request.Format) {
+		fileExt = GetFileExtensionForMimeType(request.Format)
+	}
 
 	// Create path structure: {entity_type}/{entity_hash_prefix}/{content_hash}.webp
 	// Use first 2 chars of entity hash for directory sharding
@@ -332,7 +415,7 @@ func (m *Manager) saveAssetFile(fullPath string, data []byte) error {
 }
 
 // updateExistingAsset updates an existing asset
-func (m *Manager) updateExistingAsset(existing *MediaAsset, request *AssetRequest, newPath string) (*AssetResponse, error) {
+func (m *Manager) updateExistingAsset(existing *MediaAsset, request *AssetRequest, newPath string, analysis imageAnalysis) (*AssetResponse, error) {
 	oldPath := filepath.Join(m.assetsPath, existing.Path)
 	newFullPath := filepath.Join(m.assetsPath, newPath)
 
@@ -357,13 +440,16 @@ func (m *Manager) updateExistingAsset(existing *MediaAsset, request *AssetReques
 
 	// Update database record
 	updates := map[string]interface{}{
-		"path":      newPath,
-		"width":     request.Width,
-		"height":    request.Height,
-		"format":    request.Format,
-		"preferred": request.Preferred,
-		"language":  request.Language,
-		"plugin_id": request.PluginID,
+		"path":       newPath,
+		"width":      request.Width,
+		"height":     request.Height,
+		"format":     request.Format,
+		"preferred":  request.Preferred,
+		"language":   request.Language,
+		"plugin_id":  request.PluginID,
+		"palette":    analysis.palette,
+		"has_text":   analysis.hasText,
+		"text_score": analysis.textScore,
 		// Update legacy fields for compatibility
 		"size_bytes": int64(len(request.Data)),
 		"resolution": m.formatResolution(request.Width, request.Height),
@@ -376,8 +462,8 @@ func (m *Manager) updateExistingAsset(existing *MediaAsset, request *AssetReques
 		return nil, fmt.Errorf("failed to update asset in database: %w", err)
 	}
 
-	// Remove old file if path changed
-	if oldPath != newFullPath {
+	// Remove old file if path changed, unless enrichment history may restore it
+	if oldPath != newFullPath && !m.retainedByHistory(existing.Path) {
 		os.Remove(oldPath)
 	}
 
@@ -389,13 +475,20 @@ func (m *Manager) updateExistingAsset(existing *MediaAsset, request *AssetReques
 	existing.Preferred = request.Preferred
 	existing.Language = request.Language
 	existing.PluginID = request.PluginID
+	existing.Palette = analysis.palette
+	existing.HasText = analysis.hasText
+	existing.TextScore = analysis.textScore
 	existing.UpdatedAt = time.Now()
 
 	log.Printf("INFO: Updated existing asset: entity=%s/%s type=%s source=%s preferred=%v path=%s",
 		existing.EntityType, existing.EntityID, existing.Type, existing.Source, request.Preferred, newPath)
 
+	m.applyBackdropPreference(existing)
+
 	m.publishAssetEvent(events.EventAssetUpdated, existing)
 
+	m.applyRetentionPolicy(existing)
+
 	return m.buildAssetResponse(existing), nil
 }
 
@@ -426,6 +519,9 @@ func (m *Manager) GetAssetsByEntity(entityType EntityType, entityID uuid.UUID, f
 		if filter.Language != "" {
 			query = query.Where("language = ?", filter.Language)
 		}
+		if filter.Variant != "" {
+			query = query.Where("variant = ?", filter.Variant)
+		}
 		if filter.Limit > 0 {
 			query = query.Limit(filter.Limit)
 		}
@@ -507,9 +603,11 @@ func (m *Manager) RemoveAsset(id uuid.UUID) error {
 		return fmt.Errorf("failed to find asset: %w", err)
 	}
 
-	// Remove file
+	// Remove file, unless enrichment history may restore it
 	fullPath := filepath.Join(m.assetsPath, asset.Path)
-	if err := os.Remove(fullPath); err != nil && !os.IsNotExist(err) {
+	if m.retainedByHistory(asset.Path) {
+		log.Printf("INFO: Keeping asset file %s for enrichment history", asset.Path)
+	} else if err := os.Remove(fullPath); err != nil && !os.IsNotExist(err) {
 		log.Printf("WARNING: Failed to remove asset file %s: %v", fullPath, err)
 	}
 
@@ -634,7 +732,7 @@ func (m *Manager) validateRequest(request *AssetRequest) error {
 	if request.Format == "" {
 		return fmt.Errorf("format is required")
 	}
-	if !IsSupportedImageFormat(request.Format) {
+	if !IsSupportedImageFormat(request.Format) && !IsSupportedTextFormat(request.Format) {
 		return fmt.Errorf("unsupported format: %s", request.Format)
 	}
 
@@ -669,6 +767,10 @@ func (m *Manager) buildAssetResponse(asset *MediaAsset) *AssetResponse {
 		Format:     asset.Format,
 		Preferred:  asset.Preferred,
 		Language:   asset.Language,
+		Variant:    asset.Variant,
+		Palette:    asset.Palette,
+		HasText:    asset.HasText,
+		TextScore:  asset.TextScore,
 		CreatedAt:  asset.CreatedAt,
 		UpdatedAt:  asset.UpdatedAt,
 	}
@@ -733,7 +835,7 @@ func (m *Manager) CleanupOrphanedFiles() error {
 		var count int64
 		m.db.Model(&MediaAsset{}).Where("path = ?", relativePath).Count(&count)
 
-		if count == 0 {
+		if count == 0 && !m.retainedByHistory(relativePath) {
 			// Orphaned file, remove it
 			if err := os.Remove(path); err != nil {
 				log.Printf("WARNING: Failed to remove orphaned file %s: %v", path, err)
@@ -754,6 +856,16 @@ func (m *Manager) CleanupOrphanedFiles() error {
 	return nil
 }
 
+// retainedByHistory reports whether an enrichment snapshot refers to an asset
+// file, so rolling the item back can restore it
+func (m *Manager) retainedByHistory(path string) bool {
+	var count int64
+	if err := m.db.Model(&database.MediaEnrichmentSnapshotAsset{}).Where("path = ?", path).Count(&count).Error; err != nil {
+		return false
+	}
+	return count > 0
+}
+
 // formatResolution creates a resolution string from width and height
 func (m *Manager) formatResolution(width, height int) string {
 	if width <= 0 || height <= 0 {
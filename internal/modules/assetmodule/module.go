@@ -4,9 +4,11 @@ import (
 	"fmt"
 	"log"
 	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"github.com/mantonx/viewra/internal/config"
 	"github.com/mantonx/viewra/internal/database"
 	"github.com/mantonx/viewra/internal/events"
 	"github.com/mantonx/viewra/internal/modules/modulemanager"
@@ -72,7 +74,7 @@ func (m *Module) Migrate(db *gorm.DB) error {
 	log.Println("Migrating media asset module schema")
 
 	// Auto-migrate media asset models with new schema
-	err := db.AutoMigrate(&MediaAsset{})
+	err := db.AutoMigrate(&MediaAsset{}, &OrphanedAsset{})
 	if err != nil {
 		return fmt.Errorf("failed to migrate media asset schema: %w", err)
 	}
@@ -131,6 +133,25 @@ func (m *Module) Init() error {
 
 	m.initialized = true
 
+	// Analyze artwork saved before palettes and text detection were recorded
+	go func() {
+		updated, err := m.manager.BackfillPalettes()
+		if err != nil {
+			log.Printf("WARNING: Palette backfill failed: %v", err)
+		} else if updated > 0 {
+			log.Printf("Extracted colour palettes for %d existing assets", updated)
+		}
+
+		updated, err = m.manager.BackfillTextDetection()
+		if err != nil {
+			log.Printf("WARNING: Text detection backfill failed: %v", err)
+		} else if updated > 0 {
+			log.Printf("Checked %d existing backdrops for embedded text", updated)
+		}
+	}()
+
+	go m.startGarbageCollector()
+
 	// Publish initialization event
 	if m.eventBus != nil {
 		initEvent := events.NewSystemEvent(
@@ -162,6 +183,29 @@ func (m *Module) initializeManager() error {
 	return nil
 }
 
+// startGarbageCollector periodically deletes assets whose media is gone
+func (m *Module) startGarbageCollector() {
+	cfg := config.Get().Assets
+	if cfg.CleanupInterval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(cfg.CleanupInterval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		report, err := m.manager.CollectGarbage(cfg.OrphanRetention, false)
+		if err != nil {
+			log.Printf("WARNING: Asset garbage collection failed: %v", err)
+			continue
+		}
+		if report.OrphanedAssets > 0 {
+			log.Printf("Asset garbage collection: %d orphaned assets, %d deleted, %d bytes freed",
+				report.OrphanedAssets, report.DeletedAssets, report.FreedBytes)
+		}
+	}
+}
+
 // RegisterRoutes registers API routes for media assets
 func (m *Module) RegisterRoutes(router *gin.Engine) {
 	if !m.initialized {
@@ -185,10 +229,15 @@ func (m *Module) RegisterRoutes(router *gin.Engine) {
 
 		// Asset data endpoints
 		api.GET("/:id/data", m.getAssetData)
+		api.GET("/:id/palette", m.getAssetPalette)
 
 		// Statistics and management
 		api.GET("/stats", m.getAssetStats)
 		api.POST("/cleanup", m.cleanupOrphanedFiles)
+		api.POST("/gc", m.collectGarbage)
+		api.POST("/retention/apply", m.applyRetentionPolicy)
+		api.POST("/palettes/backfill", m.backfillPalettes)
+		api.POST("/text-detection/backfill", m.backfillTextDetection)
 
 		// Utility endpoints
 		api.GET("/types", m.getValidTypes)
@@ -416,6 +465,47 @@ func (m *Module) getAssetData(c *gin.Context) {
 	c.Data(200, format, data)
 }
 
+// getAssetPalette returns the dominant colour palette of an artwork asset
+func (m *Module) getAssetPalette(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		c.JSON(400, gin.H{"error": "Invalid asset ID format"})
+		return
+	}
+
+	palette, err := m.manager.GetAssetPalette(id)
+	if err != nil {
+		c.JSON(404, gin.H{"error": "Palette not available", "details": err.Error()})
+		return
+	}
+
+	c.Header("Cache-Control", "public, max-age=86400")
+	c.JSON(200, gin.H{"palette": palette, "success": true})
+}
+
+// backfillPalettes extracts palettes for artwork that doesn't have one yet
+func (m *Module) backfillPalettes(c *gin.Context) {
+	updated, err := m.manager.BackfillPalettes()
+	if err != nil {
+		c.JSON(500, gin.H{"error": "Failed to backfill palettes", "details": err.Error()})
+		return
+	}
+
+	c.JSON(200, gin.H{"updated": updated, "success": true})
+}
+
+// backfillTextDetection checks backdrops that haven't been analyzed for text
+func (m *Module) backfillTextDetection(c *gin.Context) {
+	updated, err := m.manager.BackfillTextDetection()
+	if err != nil {
+		c.JSON(500, gin.H{"error": "Failed to backfill text detection", "details": err.Error()})
+		return
+	}
+
+	c.JSON(200, gin.H{"updated": updated, "success": true})
+}
+
 // getAssetStats returns asset statistics
 func (m *Module) getAssetStats(c *gin.Context) {
 	stats, err := m.manager.GetStats()
@@ -437,6 +527,41 @@ func (m *Module) cleanupOrphanedFiles(c *gin.Context) {
 	c.JSON(200, gin.H{"success": true, "message": "Orphaned files cleaned up successfully"})
 }
 
+// collectGarbage runs asset garbage collection. dry_run=true only reports the
+// orphaned assets and reclaimable space; retention_hours overrides the
+// configured retention window.
+func (m *Module) collectGarbage(c *gin.Context) {
+	retention := config.Get().Assets.OrphanRetention
+	if hours := c.Query("retention_hours"); hours != "" {
+		parsed, err := strconv.Atoi(hours)
+		if err != nil || parsed < 0 {
+			c.JSON(400, gin.H{"error": "Invalid retention_hours"})
+			return
+		}
+		retention = time.Duration(parsed) * time.Hour
+	}
+	dryRun := c.Query("dry_run") == "true"
+
+	report, err := m.manager.CollectGarbage(retention, dryRun)
+	if err != nil {
+		c.JSON(500, gin.H{"error": "Failed to collect asset garbage", "details": err.Error()})
+		return
+	}
+
+	c.JSON(200, gin.H{"report": report, "success": true})
+}
+
+// applyRetentionPolicy removes existing artwork the retention policy doesn't keep
+func (m *Module) applyRetentionPolicy(c *gin.Context) {
+	result, err := m.manager.ApplyRetentionPolicy()
+	if err != nil {
+		c.JSON(500, gin.H{"error": "Failed to apply retention policy", "details": err.Error()})
+		return
+	}
+
+	c.JSON(200, gin.H{"result": result, "success": true})
+}
+
 // getValidTypes returns valid asset types for an entity type
 func (m *Module) getValidTypes(c *gin.Context) {
 	entityType := c.Query("entity_type")
@@ -492,6 +617,15 @@ func SaveMediaAsset(request *AssetRequest) (*AssetResponse, error) {
 	return manager.SaveAsset(request)
 }
 
+// ImportLocalMediaArtwork imports an artwork file found next to media using the global manager
+func ImportLocalMediaArtwork(entityType EntityType, entityID uuid.UUID, assetType AssetType, path string) (bool, error) {
+	manager := GetAssetManager()
+	if manager == nil {
+		return false, fmt.Errorf("asset manager not available")
+	}
+	return manager.ImportLocalArtwork(entityType, entityID, assetType, path)
+}
+
 // GetMediaAsset retrieves a media asset using the global manager
 func GetMediaAsset(id uuid.UUID) (*AssetResponse, error) {
 	manager := GetAssetManager()
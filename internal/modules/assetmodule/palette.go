@@ -0,0 +1,253 @@
+package assetmodule
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"image"
+	"math"
+	"sort"
+)
+
+const (
+	// paletteSampleSize is the longest side, in pixels, of the grid sampled
+	// from an image; posters don't need every pixel to find their colours
+	paletteSampleSize = 96
+	// paletteMaxSwatches is the number of colours kept in a palette
+	paletteMaxSwatches = 6
+	// paletteMergeDistance is how close, in RGB space, two colours must be to
+	// count as one swatch, so gradients don't split into near-identical entries
+	paletteMergeDistance = 40
+	// paletteMinPopulation is the share of the image a colour needs to be
+	// considered as the vibrant or muted accent
+	paletteMinPopulation = 0.02
+)
+
+// paletteAssetTypes are the artwork types clients build backgrounds from
+var paletteAssetTypes = map[AssetType]bool{
+	AssetTypePoster:     true,
+	AssetTypeBackground: true,
+	AssetTypeFanart:     true,
+	AssetTypeBanner:     true,
+	AssetTypeCover:      true,
+}
+
+// ColorPalette is the set of dominant colours extracted from an image.
+// Colours are #rrggbb hex strings.
+type ColorPalette struct {
+	Dominant string        `json:"dominant"`
+	Vibrant  string        `json:"vibrant,omitempty"` // most saturated prominent colour, for accents
+	Muted    string        `json:"muted,omitempty"`   // most common desaturated colour, for backgrounds
+	Text     string        `json:"text"`              // black or white, whichever reads best on Dominant
+	IsDark   bool          `json:"is_dark"`
+	Swatches []ColorSwatch `json:"swatches"`
+}
+
+// ColorSwatch is one colour in a palette and the share of the image it covers
+type ColorSwatch struct {
+	Hex        string  `json:"hex"`
+	Population float64 `json:"population"`
+}
+
+// Value stores the palette as JSON
+func (p *ColorPalette) Value() (driver.Value, error) {
+	if p == nil {
+		return nil, nil
+	}
+	data, err := json.Marshal(p)
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+// Scan reads a palette stored as JSON
+func (p *ColorPalette) Scan(value interface{}) error {
+	var data []byte
+	switch v := value.(type) {
+	case nil:
+		return nil
+	case string:
+		data = []byte(v)
+	case []byte:
+		data = v
+	default:
+		return fmt.Errorf("cannot scan %T into ColorPalette", value)
+	}
+	if len(data) == 0 {
+		return nil
+	}
+	return json.Unmarshal(data, p)
+}
+
+// hasPalette reports whether palettes are extracted for an asset type
+func hasPalette(assetType AssetType) bool {
+	return paletteAssetTypes[assetType]
+}
+
+// colorCluster accumulates sampled pixels of similar colour
+type colorCluster struct {
+	r, g, b float64 // running mean
+	count   int
+}
+
+func (c *colorCluster) add(r, g, b float64, count int) {
+	total := float64(c.count + count)
+	c.r = (c.r*float64(c.count) + r*float64(count)) / total
+	c.g = (c.g*float64(c.count) + g*float64(count)) / total
+	c.b = (c.b*float64(c.count) + b*float64(count)) / total
+	c.count += count
+}
+
+func (c *colorCluster) distance(r, g, b float64) float64 {
+	return math.Sqrt((c.r-r)*(c.r-r) + (c.g-g)*(c.g-g) + (c.b-b)*(c.b-b))
+}
+
+func (c *colorCluster) hex() string {
+	return fmt.Sprintf("#%02x%02x%02x", uint8(math.Round(c.r)), uint8(math.Round(c.g)), uint8(math.Round(c.b)))
+}
+
+// ExtractPalette finds the dominant colours of img. Pixels are sampled on a
+// grid, bucketed to 12-bit colour and then merged into clusters. Transparent
+// pixels are ignored. It returns nil for empty or fully transparent images.
+func ExtractPalette(img image.Image) *ColorPalette {
+	bounds := img.Bounds()
+	if bounds.Empty() {
+		return nil
+	}
+	step := max(1, max(bounds.Dx(), bounds.Dy())/paletteSampleSize)
+
+	type bucket struct{ r, g, b, count int }
+	buckets := map[int]*bucket{}
+	sampled := 0
+	for y := bounds.Min.Y; y < bounds.Max.Y; y += step {
+		for x := bounds.Min.X; x < bounds.Max.X; x += step {
+			r, g, b, a := img.At(x, y).RGBA()
+			if a < 0x8000 {
+				continue
+			}
+			// Undo alpha premultiplication for translucent pixels
+			if a < 0xffff {
+				r, g, b = r*0xffff/a, g*0xffff/a, b*0xffff/a
+			}
+			r8, g8, b8 := int(r>>8), int(g>>8), int(b>>8)
+			key := (r8>>4)<<8 | (g8>>4)<<4 | b8>>4
+			bk := buckets[key]
+			if bk == nil {
+				bk = &bucket{}
+				buckets[key] = bk
+			}
+			bk.r += r8
+			bk.g += g8
+			bk.b += b8
+			bk.count++
+			sampled++
+		}
+	}
+	if sampled == 0 {
+		return nil
+	}
+
+	sorted := make([]*bucket, 0, len(buckets))
+	for _, bk := range buckets {
+		sorted = append(sorted, bk)
+	}
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].count > sorted[j].count })
+
+	// Merge buckets into clusters, most common first, so each cluster is
+	// seeded by the strongest colour in its neighbourhood
+	var clusters []*colorCluster
+	for _, bk := range sorted {
+		n := float64(bk.count)
+		r, g, b := float64(bk.r)/n, float64(bk.g)/n, float64(bk.b)/n
+		merged := false
+		for _, c := range clusters {
+			if c.distance(r, g, b) < paletteMergeDistance {
+				c.add(r, g, b, bk.count)
+				merged = true
+				break
+			}
+		}
+		if !merged {
+			c := &colorCluster{}
+			c.add(r, g, b, bk.count)
+			clusters = append(clusters, c)
+		}
+	}
+	sort.SliceStable(clusters, func(i, j int) bool { return clusters[i].count > clusters[j].count })
+	if len(clusters) > paletteMaxSwatches {
+		clusters = clusters[:paletteMaxSwatches]
+	}
+
+	palette := &ColorPalette{Swatches: make([]ColorSwatch, 0, len(clusters))}
+	var vibrant, muted *colorCluster
+	var vibrantScore float64
+	for _, c := range clusters {
+		population := float64(c.count) / float64(sampled)
+		palette.Swatches = append(palette.Swatches, ColorSwatch{
+			Hex:        c.hex(),
+			Population: math.Round(population*1000) / 1000,
+		})
+		if population < paletteMinPopulation {
+			continue
+		}
+		_, saturation, lightness := hsl(c.r, c.g, c.b)
+		// Favour saturated mid-tones; very dark or light colours look grey
+		if score := saturation * (1 - math.Abs(lightness-0.5)*1.5); saturation >= 0.35 && score > vibrantScore {
+			vibrant, vibrantScore = c, score
+		}
+		if muted == nil && saturation < 0.35 {
+			muted = c
+		}
+	}
+
+	dominant := clusters[0]
+	palette.Dominant = dominant.hex()
+	if vibrant != nil {
+		palette.Vibrant = vibrant.hex()
+	}
+	if muted != nil {
+		palette.Muted = muted.hex()
+	}
+	// 0.179 is where black and white text have equal contrast (WCAG)
+	palette.IsDark = luminance(dominant.r, dominant.g, dominant.b) < 0.179
+	palette.Text = "#000000"
+	if palette.IsDark {
+		palette.Text = "#ffffff"
+	}
+	return palette
+}
+
+// hsl converts 0-255 RGB to hue (degrees), saturation and lightness (0-1)
+func hsl(r, g, b float64) (float64, float64, float64) {
+	r, g, b = r/255, g/255, b/255
+	hi, lo := math.Max(r, math.Max(g, b)), math.Min(r, math.Min(g, b))
+	l := (hi + lo) / 2
+	if hi == lo {
+		return 0, 0, l
+	}
+	d := hi - lo
+	s := d / (1 - math.Abs(2*l-1))
+	var h float64
+	switch hi {
+	case r:
+		h = math.Mod((g-b)/d, 6)
+	case g:
+		h = (b-r)/d + 2
+	default:
+		h = (r-g)/d + 4
+	}
+	return math.Mod(h*60+360, 360), s, l
+}
+
+// luminance returns the WCAG relative luminance of a 0-255 RGB colour
+func luminance(r, g, b float64) float64 {
+	channel := func(c float64) float64 {
+		c /= 255
+		if c <= 0.03928 {
+			return c / 12.92
+		}
+		return math.Pow((c+0.055)/1.055, 2.4)
+	}
+	return 0.2126*channel(r) + 0.7152*channel(g) + 0.0722*channel(b)
+}
@@ -0,0 +1,163 @@
+package assetmodule
+
+import (
+	"fmt"
+	"log"
+	"sort"
+
+	"github.com/google/uuid"
+	"github.com/mantonx/viewra/internal/config"
+)
+
+// Asset retention policies, choosing which artwork of each type an entity keeps
+const (
+	RetentionAll       = "all"       // Keep every asset saved
+	RetentionBest      = "best"      // Keep the highest-resolution asset and the preferred one
+	RetentionPreferred = "preferred" // Keep only the preferred asset
+)
+
+// RetentionResult summarizes assets removed by the retention policy
+type RetentionResult struct {
+	Policy        string `json:"policy"`
+	RemovedAssets int    `json:"removed_assets"`
+	FreedBytes    int64  `json:"freed_bytes"`
+}
+
+// retentionGroup identifies artwork that competes for the same slot: the same
+// type for the same entity, in the same language and variant
+type retentionGroup struct {
+	EntityType EntityType
+	EntityID   uuid.UUID
+	Type       AssetType
+	Language   string
+	Variant    string
+}
+
+// retentionPolicy returns the configured retention policy
+func retentionPolicy() string {
+	if cfg := config.Get(); cfg != nil && cfg.Assets.RetentionPolicy != "" {
+		return cfg.Assets.RetentionPolicy
+	}
+	return RetentionAll
+}
+
+// applyRetentionPolicy drops the artwork competing with a newly saved asset
+// that the retention policy doesn't keep. It runs after the backdrop
+// preference, so a textless backdrop chosen for a library stays preferred
+// and is kept.
+func (m *Manager) applyRetentionPolicy(asset *MediaAsset) {
+	policy := retentionPolicy()
+	if policy == RetentionAll || !IsSupportedImageFormat(asset.Format) {
+		return
+	}
+
+	group := retentionGroup{
+		EntityType: asset.EntityType,
+		EntityID:   asset.EntityID,
+		Type:       asset.Type,
+		Language:   asset.Language,
+		Variant:    asset.Variant,
+	}
+	removed, freed, err := m.enforceRetention(policy, group)
+	if err != nil {
+		log.Printf("WARNING: Failed to apply asset retention policy for %s/%s: %v", asset.EntityType, asset.EntityID, err)
+		return
+	}
+	if removed > 0 {
+		log.Printf("INFO: Retention policy %q removed %d %s assets (%d bytes) for entity %s/%s",
+			policy, removed, asset.Type, freed, asset.EntityType, asset.EntityID)
+	}
+}
+
+// ApplyRetentionPolicy applies the configured retention policy to existing
+// artwork, e.g. after the policy is tightened
+func (m *Manager) ApplyRetentionPolicy() (*RetentionResult, error) {
+	result := &RetentionResult{Policy: retentionPolicy()}
+	if result.Policy == RetentionAll {
+		return result, nil
+	}
+
+	var groups []retentionGroup
+	if err := m.db.Model(&MediaAsset{}).
+		Select("entity_type, entity_id, type, language, variant").
+		Where("format LIKE ?", "image/%").
+		Group("entity_type, entity_id, type, language, variant").
+		Having("COUNT(*) > 1").
+		Scan(&groups).Error; err != nil {
+		return nil, fmt.Errorf("failed to find competing assets: %w", err)
+	}
+
+	for _, group := range groups {
+		removed, freed, err := m.enforceRetention(result.Policy, group)
+		if err != nil {
+			log.Printf("WARNING: Failed to apply asset retention policy for %s/%s: %v", group.EntityType, group.EntityID, err)
+			continue
+		}
+		result.RemovedAssets += removed
+		result.FreedBytes += freed
+	}
+	return result, nil
+}
+
+// enforceRetention removes the assets of a group the policy doesn't keep. The
+// preferred asset and uploads by users are always kept.
+func (m *Manager) enforceRetention(policy string, group retentionGroup) (int, int64, error) {
+	var assets []MediaAsset
+	if err := m.db.Where("entity_type = ? AND entity_id = ? AND type = ? AND language = ? AND variant = ? AND format LIKE ?",
+		group.EntityType, group.EntityID, group.Type, group.Language, group.Variant, "image/%").
+		Find(&assets).Error; err != nil {
+		return 0, 0, fmt.Errorf("failed to find assets: %w", err)
+	}
+	if len(assets) < 2 {
+		return 0, 0, nil
+	}
+
+	// Highest resolution first; the newest wins a tie
+	sort.Slice(assets, func(i, j int) bool {
+		pi, pj := assets[i].Width*assets[i].Height, assets[j].Width*assets[j].Height
+		if pi != pj {
+			return pi > pj
+		}
+		return assets[i].UpdatedAt.After(assets[j].UpdatedAt)
+	})
+
+	keep := make(map[uuid.UUID]bool)
+	var preferred *MediaAsset
+	for i := range assets {
+		if assets[i].Preferred {
+			preferred = &assets[i]
+		}
+		if assets[i].Source == SourceUser {
+			keep[assets[i].ID] = true
+		}
+	}
+	switch policy {
+	case RetentionBest:
+		keep[assets[0].ID] = true
+	case RetentionPreferred:
+		// Without a preferred asset, the best one is what clients would show
+		if preferred == nil {
+			keep[assets[0].ID] = true
+		}
+	default:
+		return 0, 0, fmt.Errorf("unknown retention policy: %s", policy)
+	}
+	if preferred != nil {
+		keep[preferred.ID] = true
+	}
+
+	var removed int
+	var freed int64
+	for _, asset := range assets {
+		if keep[asset.ID] {
+			continue
+		}
+		if err := m.RemoveAsset(asset.ID); err != nil {
+			log.Printf("WARNING: Failed to remove asset %s: %v", asset.ID, err)
+			continue
+		}
+		removed++
+		freed += asset.SizeBytes
+	}
+	return removed, freed, nil
+}
@@ -0,0 +1,97 @@
+package assetmodule
+
+import (
+	"bytes"
+	"fmt"
+	"image/jpeg"
+	"image/png"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// ExportAssetData returns an image asset encoded as JPEG or PNG, for writing
+// artwork next to media files where other media servers can read it
+func (m *Manager) ExportAssetData(id uuid.UUID, format string) ([]byte, error) {
+	data, sourceFormat, err := m.GetAssetData(id)
+	if err != nil {
+		return nil, err
+	}
+	if !IsSupportedImageFormat(sourceFormat) {
+		return nil, fmt.Errorf("asset %s is not an image", id)
+	}
+	if sourceFormat == format {
+		return data, nil
+	}
+
+	img, err := decodeImage(data, sourceFormat)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	switch format {
+	case "image/png":
+		err = png.Encode(&buf, img)
+	case "image/jpeg":
+		err = jpeg.Encode(&buf, img, &jpeg.Options{Quality: 92})
+	default:
+		return nil, fmt.Errorf("unsupported export format: %s", format)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode %s: %w", format, err)
+	}
+	return buf.Bytes(), nil
+}
+
+// ImportLocalArtwork saves an artwork file found next to media as a local
+// asset of the entity. Artwork already imported is left alone, so rescans
+// don't rewrite it, and it only becomes preferred when no other asset of the
+// type is. It reports whether the artwork was saved.
+func (m *Manager) ImportLocalArtwork(entityType EntityType, entityID uuid.UUID, assetType AssetType, path string) (bool, error) {
+	var count int64
+	if err := m.db.Model(&MediaAsset{}).
+		Where("entity_type = ? AND entity_id = ? AND type = ? AND source = ?", entityType, entityID, assetType, SourceLocal).
+		Count(&count).Error; err != nil {
+		return false, fmt.Errorf("failed to check local artwork: %w", err)
+	}
+	if count > 0 {
+		return false, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return false, fmt.Errorf("failed to read artwork: %w", err)
+	}
+
+	var preferred int64
+	m.db.Model(&MediaAsset{}).
+		Where("entity_type = ? AND entity_id = ? AND type = ? AND preferred = ?", entityType, entityID, assetType, true).
+		Count(&preferred)
+
+	format := "image/jpeg"
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".png":
+		format = "image/png"
+	case ".webp":
+		format = "image/webp"
+	}
+
+	if _, err := m.SaveAsset(&AssetRequest{
+		EntityType: entityType,
+		EntityID:   entityID,
+		Type:       assetType,
+		Source:     SourceLocal,
+		Data:       data,
+		Format:     format,
+		Preferred:  preferred == 0,
+	}); err != nil {
+		return false, err
+	}
+
+	log.Printf("INFO: Imported local %s artwork for %s/%s from %s", assetType, entityType, entityID, path)
+	return true, nil
+}
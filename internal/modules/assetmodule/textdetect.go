@@ -0,0 +1,181 @@
+package assetmodule
+
+import (
+	"image"
+	"math"
+)
+
+const (
+	// textSampleWidth is the width, in pixels, backdrops are downscaled to
+	// before looking for text
+	textSampleWidth = 256
+	// textCellWidth and textCellHeight size the grid cells edges are counted in
+	textCellWidth  = 16
+	textCellHeight = 8
+	// textEdgeThreshold is the luma step between neighbouring pixels that
+	// counts as a glyph edge
+	textEdgeThreshold = 48
+	// textMinEdgeDensity and textMaxEdgeDensity bound the share of edge pixels
+	// in a cell that looks like lettering; flat sky has too few, foliage and
+	// noise too many
+	textMinEdgeDensity = 0.08
+	textMaxEdgeDensity = 0.4
+	// textMaxBusyShare is the share of candidate cells above which the whole
+	// image is treated as texture, such as foliage, crowds or film grain
+	textMaxBusyShare = 0.2
+	// textMaxBandRows is the tallest band of candidate cells that still reads
+	// as a line of text rather than texture
+	textMaxBandRows = 5
+	// TextScoreThreshold is the score from which a backdrop counts as
+	// containing text: a line spanning a quarter of the image width
+	TextScoreThreshold = 0.25
+)
+
+// textDetectAssetTypes are the artwork types checked for embedded text. Only
+// backdrops are checked: logos are overlaid on them, while posters are
+// expected to carry their title.
+var textDetectAssetTypes = map[AssetType]bool{
+	AssetTypeBackground: true,
+	AssetTypeFanart:     true,
+}
+
+// detectsText reports whether text detection runs for an asset type
+func detectsText(assetType AssetType) bool {
+	return textDetectAssetTypes[assetType]
+}
+
+// DetectText looks for embedded title text in img. It's a heuristic rather
+// than OCR: lettering shows up as a horizontal band of cells dense with
+// sharp vertical edges, set against calmer image above and below. The score
+// is the widest such band as a share of the image width; hasText is true when
+// it reaches TextScoreThreshold.
+func DetectText(img image.Image) (score float64, hasText bool) {
+	bounds := img.Bounds()
+	if bounds.Dx() < textCellWidth || bounds.Dy() < textCellHeight {
+		return 0, false
+	}
+
+	// Downscale to a luma grid by nearest-neighbour sampling
+	width := min(textSampleWidth, bounds.Dx())
+	height := max(textCellHeight, int(math.Round(float64(bounds.Dy())*float64(width)/float64(bounds.Dx()))))
+	luma := make([][]int, height)
+	for y := range luma {
+		luma[y] = make([]int, width)
+		sy := bounds.Min.Y + y*bounds.Dy()/height
+		for x := range luma[y] {
+			r, g, b, _ := img.At(bounds.Min.X+x*bounds.Dx()/width, sy).RGBA()
+			luma[y][x] = int(299*(r>>8)+587*(g>>8)+114*(b>>8)) / 1000
+		}
+	}
+
+	cols, rows := width/textCellWidth, height/textCellHeight
+	candidate := make([][]bool, rows)
+	busy := 0
+	for row := range candidate {
+		candidate[row] = make([]bool, cols)
+		for col := range candidate[row] {
+			if isTextCell(luma, col*textCellWidth, row*textCellHeight) {
+				candidate[row][col] = true
+				busy++
+			}
+		}
+	}
+	if float64(busy) > float64(rows*cols)*textMaxBusyShare {
+		return 0, false
+	}
+
+	// Find bands of consecutive rows with wide runs of candidate cells
+	best := 0
+	for top := 0; top < rows; {
+		start, length := longestRun(candidate[top])
+		if length < 2 {
+			top++
+			continue
+		}
+		bottom, widest, widestStart := top, length, start
+		for bottom+1 < rows {
+			s, l := longestRun(candidate[bottom+1])
+			if l < 2 {
+				break
+			}
+			bottom++
+			if l > widest {
+				widest, widestStart = l, s
+			}
+		}
+
+		// Text is short and stands apart from what's around it; tall bands
+		// and bands bleeding into busy neighbours are texture
+		if bottom-top+1 <= textMaxBandRows &&
+			!busyRow(candidate, top-1, widestStart, widest) &&
+			!busyRow(candidate, bottom+1, widestStart, widest) {
+			best = max(best, widest)
+		}
+		top = bottom + 1
+	}
+
+	score = float64(best) / float64(cols)
+	return score, score >= TextScoreThreshold
+}
+
+// isTextCell reports whether the cell at x, y has the edge density and
+// stroke pattern of lettering: enough sharp edges, spread over most lines
+func isTextCell(luma [][]int, x, y int) bool {
+	edges, strokeLines := 0, 0
+	for dy := 0; dy < textCellHeight; dy++ {
+		line := luma[y+dy]
+		lineEdges := 0
+		for dx := 0; dx < textCellWidth && x+dx+1 < len(line); dx++ {
+			if abs(line[x+dx+1]-line[x+dx]) >= textEdgeThreshold {
+				lineEdges++
+			}
+		}
+		edges += lineEdges
+		// A glyph stroke crossed by a line gives two edges
+		if lineEdges >= 2 {
+			strokeLines++
+		}
+	}
+	density := float64(edges) / float64(textCellWidth*textCellHeight)
+	return density >= textMinEdgeDensity && density <= textMaxEdgeDensity && strokeLines*2 >= textCellHeight
+}
+
+// longestRun returns the start and length of the longest run of true cells
+func longestRun(cells []bool) (int, int) {
+	bestStart, bestLength, start := 0, 0, -1
+	for i, c := range cells {
+		if !c {
+			start = -1
+			continue
+		}
+		if start < 0 {
+			start = i
+		}
+		if i-start+1 > bestLength {
+			bestStart, bestLength = start, i-start+1
+		}
+	}
+	return bestStart, bestLength
+}
+
+// busyRow reports whether at least half the cells of a row under a run are
+// candidates. Rows outside the grid are calm.
+func busyRow(candidate [][]bool, row, start, length int) bool {
+	if row < 0 || row >= len(candidate) {
+		return false
+	}
+	busy := 0
+	for col := start; col < start+length; col++ {
+		if candidate[row][col] {
+			busy++
+		}
+	}
+	return busy*2 >= length
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
@@ -23,6 +23,8 @@ const (
 	EntityTypeNetwork    EntityType = "network"
 	EntityTypeGenre      EntityType = "genre"
 	EntityTypeCollection EntityType = "collection"
+	EntityTypeHomeVideo  EntityType = "home_video"
+	EntityTypePerson     EntityType = "person" // Cast and crew, whatever their role
 )
 
 // AssetType represents the specific type of asset
@@ -50,7 +52,8 @@ const (
 	AssetTypeSpectrogram AssetType = "spectrogram"
 
 	// Movie/TV specific
-	AssetTypePoster AssetType = "poster"
+	AssetTypePoster   AssetType = "poster"
+	AssetTypeSubtitle AssetType = "subtitle" // WebVTT converted from a media file's subtitle track
 
 	// TV Show specific
 	AssetTypeNetworkLogo AssetType = "network_logo"
@@ -86,17 +89,21 @@ type MediaAsset struct {
 	ID uuid.UUID `gorm:"type:uuid;primary_key;default:(lower(hex(randomblob(4))) || '-' || lower(hex(randomblob(2))) || '-4' || substr(lower(hex(randomblob(2))),2) || '-' || substr('89ab',abs(random()) % 4 + 1, 1) || substr(lower(hex(randomblob(2))),2) || '-' || lower(hex(randomblob(6))))" json:"id"`
 
 	// Entity-based fields (clean schema)
-	EntityType EntityType  `gorm:"not null;index:idx_media_assets_entity" json:"entity_type"`
-	EntityID   uuid.UUID   `gorm:"type:uuid;not null;index:idx_media_assets_entity" json:"entity_id"`
-	Type       AssetType   `gorm:"not null;index:idx_media_assets_type" json:"type"`
-	Source     AssetSource `gorm:"not null;index:idx_media_assets_source" json:"source"`
-	PluginID   string      `gorm:"index:idx_media_assets_plugin" json:"plugin_id,omitempty"` // Specific plugin identifier when source is plugin/core
-	Path       string      `gorm:"not null" json:"path"`
-	Width      int         `gorm:"default:0" json:"width"`
-	Height     int         `gorm:"default:0" json:"height"`
-	Format     string      `gorm:"not null" json:"format"` // MIME type
-	Preferred  bool        `gorm:"default:false" json:"preferred"`
-	Language   string      `gorm:"default:''" json:"language,omitempty"`
+	EntityType EntityType    `gorm:"not null;index:idx_media_assets_entity" json:"entity_type"`
+	EntityID   uuid.UUID     `gorm:"type:uuid;not null;index:idx_media_assets_entity" json:"entity_id"`
+	Type       AssetType     `gorm:"not null;index:idx_media_assets_type" json:"type"`
+	Source     AssetSource   `gorm:"not null;index:idx_media_assets_source" json:"source"`
+	PluginID   string        `gorm:"index:idx_media_assets_plugin" json:"plugin_id,omitempty"` // Specific plugin identifier when source is plugin/core
+	Path       string        `gorm:"not null" json:"path"`
+	Width      int           `gorm:"default:0" json:"width"`
+	Height     int           `gorm:"default:0" json:"height"`
+	Format     string        `gorm:"not null" json:"format"` // MIME type
+	Preferred  bool          `gorm:"default:false" json:"preferred"`
+	Language   string        `gorm:"default:''" json:"language,omitempty"`
+	Variant    string        `gorm:"default:''" json:"variant,omitempty"` // Distinguishes assets of the same type, e.g. one per subtitle track
+	Palette    *ColorPalette `gorm:"type:text" json:"palette,omitempty"`  // Dominant colours, for artwork types
+	HasText    *bool         `json:"has_text,omitempty"`                  // Embedded title text detected, for backdrops; nil until analyzed
+	TextScore  float64       `gorm:"default:0" json:"text_score,omitempty"`
 
 	// Optional fields for compatibility and metadata
 	SizeBytes  int64  `gorm:"default:0" json:"size_bytes"`
@@ -124,24 +131,29 @@ type AssetRequest struct {
 	Format     string      `json:"format" binding:"required"` // MIME type
 	Preferred  bool        `json:"preferred,omitempty"`
 	Language   string      `json:"language,omitempty"`
+	Variant    string      `json:"variant,omitempty"`
 }
 
 // AssetResponse represents the response when retrieving a media asset
 type AssetResponse struct {
-	ID         uuid.UUID   `json:"id"`
-	EntityType EntityType  `json:"entity_type"`
-	EntityID   uuid.UUID   `json:"entity_id"`
-	Type       AssetType   `json:"type"`
-	Source     AssetSource `json:"source"`
-	PluginID   string      `json:"plugin_id,omitempty"`
-	Path       string      `json:"path"`
-	Width      int         `json:"width"`
-	Height     int         `json:"height"`
-	Format     string      `json:"format"`
-	Preferred  bool        `json:"preferred"`
-	Language   string      `json:"language,omitempty"`
-	CreatedAt  time.Time   `json:"created_at"`
-	UpdatedAt  time.Time   `json:"updated_at"`
+	ID         uuid.UUID     `json:"id"`
+	EntityType EntityType    `json:"entity_type"`
+	EntityID   uuid.UUID     `json:"entity_id"`
+	Type       AssetType     `json:"type"`
+	Source     AssetSource   `json:"source"`
+	PluginID   string        `json:"plugin_id,omitempty"`
+	Path       string        `json:"path"`
+	Width      int           `json:"width"`
+	Height     int           `json:"height"`
+	Format     string        `json:"format"`
+	Preferred  bool          `json:"preferred"`
+	Language   string        `json:"language,omitempty"`
+	Variant    string        `json:"variant,omitempty"`
+	Palette    *ColorPalette `json:"palette,omitempty"`
+	HasText    *bool         `json:"has_text,omitempty"`
+	TextScore  float64       `json:"text_score,omitempty"`
+	CreatedAt  time.Time     `json:"created_at"`
+	UpdatedAt  time.Time     `json:"updated_at"`
 }
 
 // AssetFilter represents filters for querying assets
@@ -153,6 +165,7 @@ type AssetFilter struct {
 	PluginID   string      `json:"plugin_id,omitempty"`
 	Preferred  *bool       `json:"preferred,omitempty"`
 	Language   string      `json:"language,omitempty"`
+	Variant    string      `json:"variant,omitempty"`
 	Limit      int         `json:"limit,omitempty"`
 	Offset     int         `json:"offset,omitempty"`
 }
@@ -186,6 +199,8 @@ func GetValidEntityTypes() []EntityType {
 		EntityTypeNetwork,
 		EntityTypeGenre,
 		EntityTypeCollection,
+		EntityTypeHomeVideo,
+		EntityTypePerson,
 	}
 }
 
@@ -199,13 +214,15 @@ func GetValidAssetTypes(entityType EntityType) []AssetType {
 	case EntityTypeTrack:
 		return []AssetType{AssetTypeWaveform, AssetTypeSpectrogram, AssetTypeCover}
 	case EntityTypeMovie:
-		return []AssetType{AssetTypePoster, AssetTypeLogo, AssetTypeBanner, AssetTypeBackground, AssetTypeThumb, AssetTypeFanart}
+		return []AssetType{AssetTypePoster, AssetTypeLogo, AssetTypeBanner, AssetTypeBackground, AssetTypeThumb, AssetTypeFanart, AssetTypeSubtitle}
 	case EntityTypeTVShow:
 		return []AssetType{AssetTypePoster, AssetTypeLogo, AssetTypeBanner, AssetTypeBackground, AssetTypeNetworkLogo, AssetTypeThumb, AssetTypeFanart}
 	case EntityTypeEpisode:
-		return []AssetType{AssetTypeScreenshot, AssetTypeThumb, AssetTypePoster}
+		return []AssetType{AssetTypeScreenshot, AssetTypeThumb, AssetTypePoster, AssetTypeSubtitle}
 	case EntityTypeActor:
 		return []AssetType{AssetTypeHeadshot, AssetTypePhoto, AssetTypeThumb, AssetTypeSignature}
+	case EntityTypePerson:
+		return []AssetType{AssetTypeHeadshot, AssetTypePhoto, AssetTypeThumb}
 	case EntityTypeDirector:
 		return []AssetType{AssetTypePortrait, AssetTypeSignature, AssetTypeLogo}
 	case EntityTypeStudio:
@@ -218,6 +235,8 @@ func GetValidAssetTypes(entityType EntityType) []AssetType {
 		return []AssetType{AssetTypeIcon, AssetTypeBackground, AssetTypeBanner}
 	case EntityTypeCollection:
 		return []AssetType{AssetTypeCover, AssetTypeBackground, AssetTypeLogo}
+	case EntityTypeHomeVideo:
+		return []AssetType{AssetTypeThumb, AssetTypeScreenshot}
 	default:
 		return []AssetType{}
 	}
@@ -255,6 +274,12 @@ func IsSupportedImageFormat(mimeType string) bool {
 	return false
 }
 
+// IsSupportedTextFormat checks if a MIME type is a supported text asset format.
+// Text assets are stored as-is rather than converted to WebP.
+func IsSupportedTextFormat(mimeType string) bool {
+	return mimeType == "text/vtt"
+}
+
 // GetFileExtensionForMimeType returns the appropriate file extension for a MIME type
 func GetFileExtensionForMimeType(mimeType string) string {
 	switch mimeType {
@@ -272,6 +297,8 @@ func GetFileExtensionForMimeType(mimeType string) string {
 		return ".tiff"
 	case "image/svg+xml":
 		return ".svg"
+	case "text/vtt":
+		return ".vtt"
 	default:
 		return ".jpg" // Default fallback
 	}
@@ -0,0 +1,135 @@
+// Package diskhealthmodule reports the S.M.A.R.T. health of the disks that
+// libraries live on, using smartctl when it is installed, and publishes
+// events when a disk starts predicting its own failure.
+package diskhealthmodule
+
+import (
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mantonx/viewra/internal/config"
+	"github.com/mantonx/viewra/internal/database"
+	"github.com/mantonx/viewra/internal/events"
+	"github.com/mantonx/viewra/internal/modules/modulemanager"
+	"gorm.io/gorm"
+)
+
+// Auto-register the module when imported
+func init() {
+	Register()
+}
+
+const (
+	ModuleID   = "system.diskhealth"
+	ModuleName = "Disk Health Monitor"
+)
+
+// Module monitors the health of library disks. It isn't a core module, so
+// it can be disabled in the module configuration.
+type Module struct {
+	id          string
+	name        string
+	version     string
+	core        bool
+	monitor     *Monitor
+	initialized bool
+}
+
+// Register registers this module with the module system
+func Register() {
+	diskHealthModule := &Module{
+		id:      ModuleID,
+		name:    ModuleName,
+		version: "1.0.0",
+		core:    false,
+	}
+	modulemanager.Register(diskHealthModule)
+}
+
+// ID returns the module ID
+func (m *Module) ID() string {
+	return m.id
+}
+
+// Name returns the module name
+func (m *Module) Name() string {
+	return m.name
+}
+
+// Core returns whether this is a core module
+func (m *Module) Core() bool {
+	return m.core
+}
+
+// Migrate handles database schema migrations. Disk health isn't stored.
+func (m *Module) Migrate(db *gorm.DB) error {
+	return nil
+}
+
+// Init initializes the module and starts periodic checks
+func (m *Module) Init() error {
+	cfg := config.Get().DiskHealth
+	if !cfg.Enabled {
+		log.Println("Disk health monitoring is disabled")
+		return nil
+	}
+
+	m.monitor = NewMonitor(database.GetDB(), events.GetGlobalEventBus())
+	m.initialized = true
+
+	go m.startChecks(cfg.CheckInterval)
+
+	log.Println("Disk health module initialized")
+	return nil
+}
+
+// startChecks checks the disks now and then on every interval
+func (m *Module) startChecks(interval time.Duration) {
+	if interval <= 0 {
+		interval = time.Hour
+	}
+
+	m.monitor.Check()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for range ticker.C {
+		m.monitor.Check()
+	}
+}
+
+// RegisterRoutes registers the disk health endpoints of the admin system API
+func (m *Module) RegisterRoutes(router *gin.Engine) {
+	if !m.initialized {
+		return
+	}
+
+	api := router.Group("/api/admin/system/disks")
+	{
+		api.GET("", m.getDiskHealth)
+		api.POST("/check", m.checkDiskHealth)
+	}
+}
+
+// getDiskHealth returns the latest health report of library disks. It
+// responds with 503 when a disk is failing so it can back external checks.
+func (m *Module) getDiskHealth(c *gin.Context) {
+	report := m.monitor.Report()
+	c.JSON(reportStatusCode(report), report)
+}
+
+// checkDiskHealth reads S.M.A.R.T. data again instead of waiting for the
+// next scheduled check
+func (m *Module) checkDiskHealth(c *gin.Context) {
+	report := m.monitor.Check()
+	c.JSON(reportStatusCode(report), report)
+}
+
+func reportStatusCode(report *Report) int {
+	if report.Status == StatusFailing {
+		return http.StatusServiceUnavailable
+	}
+	return http.StatusOK
+}
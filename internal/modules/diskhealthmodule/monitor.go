@@ -0,0 +1,292 @@
+package diskhealthmodule
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mantonx/viewra/internal/config"
+	"github.com/mantonx/viewra/internal/database"
+	"github.com/mantonx/viewra/internal/events"
+	"github.com/shirou/gopsutil/v4/disk"
+	"gorm.io/gorm"
+)
+
+// Partition names with a numeric suffix, e.g. sda1, nvme0n1p2, mmcblk0p1
+var (
+	prefixedPartitionPattern = regexp.MustCompile(`^(/dev/(?:nvme\d+n\d+|mmcblk\d+|loop\d+))p\d+$`)
+	partitionPattern         = regexp.MustCompile(`^(/dev/(?:sd|hd|vd|xvd)[a-z]+)\d+$`)
+)
+
+// statusRank orders statuses so only changes for the worse are notified
+var statusRank = map[string]int{
+	StatusHealthy: 0,
+	StatusWarning: 1,
+	StatusFailing: 2,
+}
+
+// DiskHealth is the S.M.A.R.T. health of a disk backing one or more libraries
+type DiskHealth struct {
+	Device         string          `json:"device"`
+	Model          string          `json:"model,omitempty"`
+	Serial         string          `json:"serial,omitempty"`
+	Status         string          `json:"status"`
+	SmartPassed    *bool           `json:"smart_passed,omitempty"`
+	Temperature    int             `json:"temperature,omitempty"` // Celsius
+	PowerOnHours   int             `json:"power_on_hours,omitempty"`
+	BadSectors     int64           `json:"bad_sectors"`
+	PercentageUsed int             `json:"percentage_used,omitempty"` // NVMe endurance used
+	Predictions    []string        `json:"predicted_failures"`
+	Error          string          `json:"error,omitempty"`
+	Libraries      []LibraryVolume `json:"libraries"`
+	CheckedAt      time.Time       `json:"checked_at"`
+}
+
+// LibraryVolume is a library and the filesystem it lives on
+type LibraryVolume struct {
+	LibraryID  uint32 `json:"library_id"`
+	Path       string `json:"path"`
+	Mountpoint string `json:"mountpoint,omitempty"`
+	Fstype     string `json:"fstype,omitempty"`
+}
+
+// Report is the health of every disk backing a library
+type Report struct {
+	Status            string       `json:"status"` // The worst known disk status
+	SmartctlAvailable bool         `json:"smartctl_available"`
+	Disks             []DiskHealth `json:"disks"`
+	CheckedAt         time.Time    `json:"checked_at"`
+}
+
+// Monitor checks the disks backing libraries and notifies when their health
+// changes
+type Monitor struct {
+	db       *gorm.DB
+	eventBus events.EventBus
+
+	// checkMu serializes checks, so a manual check waits for a running one
+	checkMu sync.Mutex
+	// lastStatus is the last known status of each device, for notifications
+	lastStatus map[string]string
+
+	mu     sync.RWMutex
+	report *Report
+}
+
+// NewMonitor creates a disk health monitor
+func NewMonitor(db *gorm.DB, eventBus events.EventBus) *Monitor {
+	return &Monitor{
+		db:         db,
+		eventBus:   eventBus,
+		lastStatus: make(map[string]string),
+	}
+}
+
+// Report returns the latest report, checking the disks if none has run yet
+func (m *Monitor) Report() *Report {
+	m.mu.RLock()
+	report := m.report
+	m.mu.RUnlock()
+	if report != nil {
+		return report
+	}
+	return m.Check()
+}
+
+// Check reads S.M.A.R.T. data for the disks backing every library
+func (m *Monitor) Check() *Report {
+	m.checkMu.Lock()
+	defer m.checkMu.Unlock()
+
+	cfg := config.Get().DiskHealth
+	report := &Report{
+		Status:            StatusUnknown,
+		SmartctlAvailable: smartctlAvailable(cfg.SmartctlPath),
+		Disks:             []DiskHealth{},
+		CheckedAt:         time.Now(),
+	}
+
+	disks, err := m.libraryDisks()
+	if err != nil {
+		log.Printf("WARNING: Failed to find library disks: %v", err)
+	}
+
+	for _, health := range disks {
+		switch {
+		case !strings.HasPrefix(health.Device, "/dev/"):
+			health.Status = StatusUnknown
+			health.Error = "no S.M.A.R.T. data for network or virtual filesystems"
+		case !report.SmartctlAvailable:
+			health.Status = StatusUnknown
+			health.Error = fmt.Sprintf("smartctl not found (%s)", cfg.SmartctlPath)
+		default:
+			if smart, err := runSmartctl(cfg.SmartctlPath, health.Device); err != nil {
+				health.Status = StatusUnknown
+				health.Error = err.Error()
+			} else {
+				applyReport(health, smart, cfg.TemperatureWarning)
+			}
+		}
+		health.CheckedAt = time.Now()
+
+		if health.Status != StatusUnknown && (report.Status == StatusUnknown || statusRank[health.Status] > statusRank[report.Status]) {
+			report.Status = health.Status
+		}
+		m.notify(health)
+		report.Disks = append(report.Disks, *health)
+	}
+
+	m.mu.Lock()
+	m.report = report
+	m.mu.Unlock()
+	return report
+}
+
+// libraryDisks groups libraries by the disk holding them. Libraries on
+// network or virtual filesystems are grouped by their filesystem source.
+func (m *Monitor) libraryDisks() ([]*DiskHealth, error) {
+	var libraries []database.MediaLibrary
+	if err := m.db.Order("id").Find(&libraries).Error; err != nil {
+		return nil, fmt.Errorf("failed to get libraries: %w", err)
+	}
+
+	partitions, err := disk.Partitions(true)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list partitions: %w", err)
+	}
+
+	byDevice := make(map[string]*DiskHealth)
+	for _, library := range libraries {
+		path, err := filepath.Abs(library.Path)
+		if err != nil {
+			continue
+		}
+		partition := mountpointFor(path, partitions)
+
+		device := partition.Device
+		if device == "" {
+			device = path
+		} else if strings.HasPrefix(device, "/dev/") {
+			device = parentDisk(device)
+		}
+
+		health, ok := byDevice[device]
+		if !ok {
+			health = &DiskHealth{Device: device, Predictions: []string{}}
+			byDevice[device] = health
+		}
+		health.Libraries = append(health.Libraries, LibraryVolume{
+			LibraryID:  library.ID,
+			Path:       library.Path,
+			Mountpoint: partition.Mountpoint,
+			Fstype:     partition.Fstype,
+		})
+	}
+
+	disks := make([]*DiskHealth, 0, len(byDevice))
+	for _, health := range byDevice {
+		disks = append(disks, health)
+	}
+	sort.Slice(disks, func(i, j int) bool { return disks[i].Device < disks[j].Device })
+	return disks, nil
+}
+
+// notify publishes an event when a disk's health gets worse, or recovers.
+// Unknown results, e.g. a drive that was asleep, don't change what is known.
+func (m *Monitor) notify(health *DiskHealth) {
+	if health.Status == StatusUnknown {
+		return
+	}
+	previous, known := m.lastStatus[health.Device]
+	m.lastStatus[health.Device] = health.Status
+	if !known {
+		previous = StatusHealthy
+	}
+
+	var eventType events.EventType
+	var priority events.EventPriority
+	switch {
+	case statusRank[health.Status] <= statusRank[previous]:
+		if health.Status != StatusHealthy || previous == StatusHealthy {
+			return
+		}
+		eventType, priority = events.EventDiskHealthRecovered, events.PriorityNormal
+	case health.Status == StatusFailing:
+		eventType, priority = events.EventDiskFailurePredicted, events.PriorityCritical
+	default:
+		eventType, priority = events.EventDiskHealthWarning, events.PriorityHigh
+	}
+
+	log.Printf("INFO: Disk %s health changed from %s to %s", health.Device, previous, health.Status)
+	if m.eventBus == nil {
+		return
+	}
+
+	paths := make([]string, 0, len(health.Libraries))
+	for _, library := range health.Libraries {
+		paths = append(paths, library.Path)
+	}
+	message := fmt.Sprintf("Disk %s (libraries: %s) is %s", health.Device, strings.Join(paths, ", "), health.Status)
+	if len(health.Predictions) > 0 {
+		message += ": " + strings.Join(health.Predictions, "; ")
+	}
+
+	event := events.NewSystemEvent(eventType, fmt.Sprintf("Disk %s", health.Status), message)
+	event.Priority = priority
+	event.Data = map[string]interface{}{
+		"device":             health.Device,
+		"model":              health.Model,
+		"serial":             health.Serial,
+		"status":             health.Status,
+		"previous_status":    previous,
+		"predicted_failures": health.Predictions,
+		"library_paths":      paths,
+	}
+	m.eventBus.PublishAsync(event)
+}
+
+// parentDisk returns the whole disk a partition is on, e.g. /dev/sda for
+// /dev/sda1, since S.M.A.R.T. data belongs to the disk
+func parentDisk(device string) string {
+	if resolved, err := filepath.EvalSymlinks(device); err == nil {
+		device = resolved
+	}
+
+	// sysfs nests partitions under their disk
+	name := filepath.Base(device)
+	if sysPath, err := filepath.EvalSymlinks(filepath.Join("/sys/class/block", name)); err == nil {
+		if _, err := os.Stat(filepath.Join(sysPath, "partition")); err == nil {
+			return "/dev/" + filepath.Base(filepath.Dir(sysPath))
+		}
+		return device
+	}
+
+	if matches := prefixedPartitionPattern.FindStringSubmatch(device); matches != nil {
+		return matches[1]
+	}
+	if matches := partitionPattern.FindStringSubmatch(device); matches != nil {
+		return matches[1]
+	}
+	return device
+}
+
+// mountpointFor returns the partition with the longest mountpoint containing path
+func mountpointFor(path string, partitions []disk.PartitionStat) disk.PartitionStat {
+	var best disk.PartitionStat
+	for _, partition := range partitions {
+		mount := partition.Mountpoint
+		if mount == "" || len(mount) <= len(best.Mountpoint) {
+			continue
+		}
+		if path == mount || strings.HasPrefix(path, strings.TrimSuffix(mount, string(filepath.Separator))+string(filepath.Separator)) {
+			best = partition
+		}
+	}
+	return best
+}
@@ -0,0 +1,163 @@
+package diskhealthmodule
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// Disk health statuses, from best to worst
+const (
+	StatusHealthy = "healthy"
+	StatusWarning = "warning" // Wear or errors worth watching
+	StatusFailing = "failing" // The drive predicts its own failure
+	StatusUnknown = "unknown" // No S.M.A.R.T. data, e.g. network shares or no smartctl
+)
+
+// smartctlTimeout bounds one smartctl call; a sleeping or busy drive can stall
+const smartctlTimeout = 30 * time.Second
+
+// ATA attributes whose raw values count sectors the drive couldn't read or
+// had to remap. Any non-zero count is an early sign of failure.
+var sectorAttributes = map[int]string{
+	5:   "reallocated sectors",
+	187: "reported uncorrectable errors",
+	197: "pending sectors",
+	198: "offline uncorrectable sectors",
+}
+
+// smartReport is the subset of `smartctl --json -a` output used here
+type smartReport struct {
+	Smartctl struct {
+		ExitStatus int `json:"exit_status"`
+		Messages   []struct {
+			String   string `json:"string"`
+			Severity string `json:"severity"`
+		} `json:"messages"`
+	} `json:"smartctl"`
+	ModelName    string `json:"model_name"`
+	SerialNumber string `json:"serial_number"`
+	SmartStatus  *struct {
+		Passed bool `json:"passed"`
+	} `json:"smart_status"`
+	Temperature struct {
+		Current int `json:"current"`
+	} `json:"temperature"`
+	PowerOnTime struct {
+		Hours int `json:"hours"`
+	} `json:"power_on_time"`
+	ATASmartAttributes struct {
+		Table []struct {
+			ID         int    `json:"id"`
+			Name       string `json:"name"`
+			WhenFailed string `json:"when_failed"`
+			Raw        struct {
+				Value int64 `json:"value"`
+			} `json:"raw"`
+		} `json:"table"`
+	} `json:"ata_smart_attributes"`
+	NVMeHealth *struct {
+		CriticalWarning int   `json:"critical_warning"`
+		PercentageUsed  int   `json:"percentage_used"`
+		MediaErrors     int64 `json:"media_errors"`
+	} `json:"nvme_smart_health_information_log"`
+}
+
+// runSmartctl runs smartctl on a device and decodes its JSON report. smartctl
+// sets exit status bits for failing health, so its output is decoded even
+// when it exits non-zero; only bits 0 and 1 (bad arguments or an unopenable
+// device) make the report unusable.
+func runSmartctl(smartctlPath, device string) (*smartReport, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), smartctlTimeout)
+	defer cancel()
+
+	output, err := exec.CommandContext(ctx, smartctlPath, "--json", "-a", device).Output()
+	var exitErr *exec.ExitError
+	if err != nil && !errors.As(err, &exitErr) {
+		return nil, fmt.Errorf("failed to run smartctl: %w", err)
+	}
+
+	var report smartReport
+	if err := json.Unmarshal(output, &report); err != nil {
+		return nil, fmt.Errorf("failed to parse smartctl output: %w", err)
+	}
+	if report.Smartctl.ExitStatus&0x3 != 0 {
+		for _, message := range report.Smartctl.Messages {
+			if message.Severity == "error" {
+				return nil, errors.New(message.String)
+			}
+		}
+		return nil, fmt.Errorf("smartctl could not read %s (exit status %d)", device, report.Smartctl.ExitStatus)
+	}
+	return &report, nil
+}
+
+// applyReport fills a disk's health from a smartctl report. The status is
+// the worst any check finds, and each finding is listed as a prediction.
+func applyReport(disk *DiskHealth, report *smartReport, temperatureWarning int) {
+	disk.Model = report.ModelName
+	disk.Serial = report.SerialNumber
+	disk.Temperature = report.Temperature.Current
+	disk.PowerOnHours = report.PowerOnTime.Hours
+	disk.Status = StatusHealthy
+	disk.Predictions = []string{}
+
+	flag := func(status, finding string) {
+		if status == StatusFailing || disk.Status == StatusHealthy {
+			disk.Status = status
+		}
+		disk.Predictions = append(disk.Predictions, finding)
+	}
+
+	if report.SmartStatus != nil {
+		passed := report.SmartStatus.Passed
+		disk.SmartPassed = &passed
+		if !passed {
+			flag(StatusFailing, "S.M.A.R.T. overall health self-assessment failed")
+		}
+	}
+
+	for _, attribute := range report.ATASmartAttributes.Table {
+		switch attribute.WhenFailed {
+		case "now":
+			flag(StatusFailing, fmt.Sprintf("Attribute %s is below its failure threshold", attribute.Name))
+		case "past":
+			flag(StatusWarning, fmt.Sprintf("Attribute %s was below its failure threshold in the past", attribute.Name))
+		}
+		if name, ok := sectorAttributes[attribute.ID]; ok && attribute.Raw.Value > 0 {
+			disk.BadSectors += attribute.Raw.Value
+			flag(StatusWarning, fmt.Sprintf("%d %s", attribute.Raw.Value, name))
+		}
+	}
+
+	if nvme := report.NVMeHealth; nvme != nil {
+		disk.PercentageUsed = nvme.PercentageUsed
+		if nvme.CriticalWarning != 0 {
+			flag(StatusFailing, fmt.Sprintf("NVMe critical warning 0x%02x", nvme.CriticalWarning))
+		}
+		if nvme.MediaErrors > 0 {
+			disk.BadSectors += nvme.MediaErrors
+			flag(StatusWarning, fmt.Sprintf("%d media and data integrity errors", nvme.MediaErrors))
+		}
+		if nvme.PercentageUsed >= 90 {
+			flag(StatusWarning, fmt.Sprintf("%d%% of rated endurance used", nvme.PercentageUsed))
+		}
+	}
+
+	if temperatureWarning > 0 && disk.Temperature >= temperatureWarning {
+		flag(StatusWarning, fmt.Sprintf("Temperature %d°C is at or above %d°C", disk.Temperature, temperatureWarning))
+	}
+}
+
+// smartctlAvailable reports whether smartctl can be run
+func smartctlAvailable(smartctlPath string) bool {
+	if strings.TrimSpace(smartctlPath) == "" {
+		return false
+	}
+	_, err := exec.LookPath(smartctlPath)
+	return err == nil
+}
@@ -0,0 +1,48 @@
+package downloadmodule
+
+import (
+	"time"
+)
+
+// Statuses of a download
+const (
+	StatusScanPending = "scan_pending" // Imported; scanned once no other scan of the library is running
+	StatusScanning    = "scanning"
+	StatusCompleted   = "completed"
+	StatusFailed      = "failed"
+)
+
+// Download is a finished download found in a completion folder, and what
+// became of it
+type Download struct {
+	ID              uint32     `gorm:"primaryKey" json:"id"`
+	SourcePath      string     `gorm:"not null;index" json:"source_path"` // File or directory in the completion folder
+	LibraryID       uint32     `gorm:"not null;index" json:"library_id"`
+	Mode            string     `json:"mode"`                        // move, hardlink or copy; empty when scanned in place
+	ImportPath      string     `gorm:"not null" json:"import_path"` // Where the download is in the library
+	Files           int        `json:"files"`
+	SizeBytes       int64      `json:"size_bytes"`
+	Organize        bool       `json:"organize"`
+	Status          string     `gorm:"not null;index" json:"status"`
+	Error           string     `json:"error,omitempty"`
+	ScanJobID       *uint32    `json:"scan_job_id,omitempty"`
+	OrganizeBatchID string     `json:"organize_batch_id,omitempty"` // Organizer batch to undo the renames with
+	CreatedAt       time.Time  `gorm:"index" json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+	CompletedAt     *time.Time `json:"completed_at,omitempty"`
+}
+
+// TableName keeps the downloads apart from other modules' tables
+func (Download) TableName() string {
+	return "download_imports"
+}
+
+// WaitingDownload is a download seen in a completion folder that is still
+// being written, or hasn't been left alone long enough yet
+type WaitingDownload struct {
+	Path      string    `json:"path"`
+	Files     int       `json:"files"`
+	SizeBytes int64     `json:"size_bytes"`
+	Partial   bool      `json:"partial"`         // Still has files the client is writing
+	Since     time.Time `json:"unchanged_since"` // When its files last changed
+}
@@ -0,0 +1,206 @@
+// Package downloadmodule watches the folders torrent and Usenet clients put
+// completed downloads in. Once a download's files stop changing it is
+// moved, hardlinked or copied into its library, only its files are
+// scanned, and it can be handed to the file organizer, so new releases
+// show up without rescanning whole libraries.
+package downloadmodule
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mantonx/viewra/internal/config"
+	"github.com/mantonx/viewra/internal/database"
+	"github.com/mantonx/viewra/internal/events"
+	"github.com/mantonx/viewra/internal/modules/modulemanager"
+	"github.com/mantonx/viewra/internal/modules/organizermodule"
+	"github.com/mantonx/viewra/internal/modules/scannermodule"
+	"gorm.io/gorm"
+)
+
+// Auto-register the module when imported
+func init() {
+	Register()
+}
+
+const (
+	ModuleID   = "system.downloads"
+	ModuleName = "Download Watcher"
+)
+
+// defaultDownloadLimit is the number of downloads listed by default
+const defaultDownloadLimit = 50
+
+// Module imports completed downloads. It isn't a core module, so it can be
+// disabled in the module configuration.
+type Module struct {
+	id          string
+	name        string
+	version     string
+	core        bool
+	watcher     *Watcher
+	initialized bool
+}
+
+// Register registers this module with the module system
+func Register() {
+	downloadModule := &Module{
+		id:      ModuleID,
+		name:    ModuleName,
+		version: "1.0.0",
+		core:    false,
+	}
+	modulemanager.Register(downloadModule)
+}
+
+// ID returns the module ID
+func (m *Module) ID() string {
+	return m.id
+}
+
+// Name returns the module name
+func (m *Module) Name() string {
+	return m.name
+}
+
+// Core returns whether this is a core module
+func (m *Module) Core() bool {
+	return m.core
+}
+
+// Migrate creates the table of imported downloads
+func (m *Module) Migrate(db *gorm.DB) error {
+	return db.AutoMigrate(&Download{})
+}
+
+// Init initializes the module and starts polling the completion folders.
+// Invalid folders leave the module off rather than stopping the server.
+func (m *Module) Init() error {
+	cfg := config.Get().Downloads
+	if len(cfg.Folders) == 0 {
+		log.Println("Download watcher has no completion folders configured")
+		return nil
+	}
+
+	watcher, err := NewWatcher(database.GetDB(), events.GetGlobalEventBus(), cfg)
+	if err != nil {
+		log.Printf("ERROR: Download watcher disabled: %v", err)
+		return nil
+	}
+	watcher.scanner = scanStarter
+	watcher.organizer = organizer
+	m.watcher = watcher
+	m.initialized = true
+
+	go m.startChecks(cfg.PollInterval)
+
+	log.Printf("Download watcher module initialized (%d folders)", len(cfg.Folders))
+	return nil
+}
+
+// startChecks checks the folders on every interval
+func (m *Module) startChecks(interval time.Duration) {
+	if interval <= 0 {
+		interval = 30 * time.Second
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for range ticker.C {
+		m.watcher.Check()
+	}
+}
+
+// scanStarter returns the scanner manager, or nil when the scanner isn't
+// running
+func scanStarter() ScanStarter {
+	module, ok := modulemanager.GetModule(scannermodule.ModuleID)
+	if !ok {
+		return nil
+	}
+	scannerModule, ok := module.(*scannermodule.Module)
+	if !ok {
+		return nil
+	}
+	manager := scannerModule.GetScannerManager()
+	if manager == nil {
+		return nil
+	}
+	return manager
+}
+
+// organizer returns the file organizer, or nil when it is disabled
+func organizer() *organizermodule.Organizer {
+	module, ok := modulemanager.GetModule(organizermodule.ModuleID)
+	if !ok {
+		return nil
+	}
+	organizerModule, ok := module.(*organizermodule.Module)
+	if !ok {
+		return nil
+	}
+	return organizerModule.Organizer()
+}
+
+// RegisterRoutes registers the download endpoints of the admin API
+func (m *Module) RegisterRoutes(router *gin.Engine) {
+	if !m.initialized {
+		return
+	}
+
+	api := router.Group("/api/admin/downloads")
+	{
+		api.GET("", m.getDownloads)
+		api.POST("/check", m.checkDownloads)
+		api.POST("/:id/retry", m.retryDownload)
+	}
+}
+
+// getDownloads lists imported downloads, newest first, and the downloads
+// still waiting for their files to settle
+func (m *Module) getDownloads(c *gin.Context) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(defaultDownloadLimit)))
+	if err != nil || limit <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be a positive number"})
+		return
+	}
+	downloads, err := m.watcher.Downloads(c.Query("status"), limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"downloads": downloads,
+		"waiting":   m.watcher.Waiting(),
+	})
+}
+
+// checkDownloads checks the folders now instead of waiting for the next poll
+func (m *Module) checkDownloads(c *gin.Context) {
+	m.watcher.Check()
+	m.getDownloads(c)
+}
+
+// retryDownload tries a failed download again
+func (m *Module) retryDownload(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid download ID"})
+		return
+	}
+	err = m.watcher.Retry(uint32(id))
+	switch {
+	case errors.Is(err, ErrDownloadNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, ErrNotFailed):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusOK, gin.H{"message": "Download will be retried on the next check"})
+	}
+}
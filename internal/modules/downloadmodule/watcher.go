@@ -0,0 +1,520 @@
+package downloadmodule
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mantonx/viewra/internal/config"
+	"github.com/mantonx/viewra/internal/database"
+	"github.com/mantonx/viewra/internal/events"
+	"github.com/mantonx/viewra/internal/modules/organizermodule"
+	"github.com/mantonx/viewra/internal/utils"
+	"gorm.io/gorm"
+)
+
+// Reasons a download can't be retried
+var (
+	ErrDownloadNotFound = errors.New("download not found")
+	ErrNotFailed        = errors.New("only failed downloads can be retried")
+)
+
+// partialSuffixes mark files a download client is still writing
+var partialSuffixes = []string{".part", ".partial", ".!qb", ".!ut", ".crdownload", ".aria2", ".tmp"}
+
+// partialPrefixes mark directories SABnzbd is still unpacking or gave up on
+var partialPrefixes = []string{"_unpack_", "_failed_"}
+
+// ScanStarter starts scans of some paths of a library: the scanner manager
+type ScanStarter interface {
+	ScanPaths(libraryID uint32, paths []string) (*database.ScanJob, error)
+}
+
+// Watcher polls download completion folders. A download, a file or
+// directory directly in a folder, is imported into its library once its
+// files have stopped changing; then only its files are scanned and, when
+// the folder asks for it, organized.
+type Watcher struct {
+	db       *gorm.DB
+	eventBus events.EventBus
+	config   config.DownloadsConfig
+
+	// Looked up on each check, as the modules providing them may start
+	// after this one or be disabled
+	scanner   func() ScanStarter
+	organizer func() *organizermodule.Organizer
+
+	mu      sync.Mutex
+	waiting map[string]*candidate // Downloads not yet stable, by path
+}
+
+// candidate is a download whose files were last seen changing at since
+type candidate struct {
+	snapshot snapshot
+	since    time.Time
+}
+
+// snapshot is the state of a download's files at one check
+type snapshot struct {
+	files      int
+	mediaFiles int
+	sizeBytes  int64
+	modTime    time.Time
+	partial    bool
+}
+
+// NewWatcher creates a watcher, failing on folders that aren't absolute or
+// import modes it doesn't know
+func NewWatcher(db *gorm.DB, eventBus events.EventBus, cfg config.DownloadsConfig) (*Watcher, error) {
+	for _, folder := range cfg.Folders {
+		if !filepath.IsAbs(folder.Path) {
+			return nil, fmt.Errorf("download folder %q is not an absolute path", folder.Path)
+		}
+		if folder.LibraryID == 0 {
+			return nil, fmt.Errorf("download folder %s has no library_id", folder.Path)
+		}
+		switch folder.Import {
+		case "", organizermodule.ModeMove, organizermodule.ModeHardlink, organizermodule.ModeCopy:
+		default:
+			return nil, fmt.Errorf("invalid import mode %q for %s, expected move, hardlink or copy", folder.Import, folder.Path)
+		}
+	}
+	if cfg.StableFor <= 0 {
+		cfg.StableFor = time.Minute
+	}
+
+	return &Watcher{
+		db:        db,
+		eventBus:  eventBus,
+		config:    cfg,
+		scanner:   func() ScanStarter { return nil },
+		organizer: func() *organizermodule.Organizer { return nil },
+		waiting:   make(map[string]*candidate),
+	}, nil
+}
+
+// Check imports the downloads that have finished, then moves earlier
+// downloads along: starting the scans that couldn't start yet, and
+// finishing downloads whose scans are done
+func (w *Watcher) Check() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	now := time.Now()
+	for _, folder := range w.config.Folders {
+		w.checkFolder(folder, now)
+	}
+	w.advance(now)
+}
+
+// Waiting lists the downloads that aren't stable yet, oldest first
+func (w *Watcher) Waiting() []WaitingDownload {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	waiting := make([]WaitingDownload, 0, len(w.waiting))
+	for path, candidate := range w.waiting {
+		waiting = append(waiting, WaitingDownload{
+			Path:      path,
+			Files:     candidate.snapshot.files,
+			SizeBytes: candidate.snapshot.sizeBytes,
+			Partial:   candidate.snapshot.partial,
+			Since:     candidate.since,
+		})
+	}
+	sort.Slice(waiting, func(i, j int) bool {
+		return waiting[i].Since.Before(waiting[j].Since)
+	})
+	return waiting
+}
+
+// Downloads lists imported downloads, newest first
+func (w *Watcher) Downloads(status string, limit int) ([]Download, error) {
+	query := w.db.Order("id DESC").Limit(limit)
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	var downloads []Download
+	if err := query.Find(&downloads).Error; err != nil {
+		return nil, fmt.Errorf("failed to get downloads: %w", err)
+	}
+	return downloads, nil
+}
+
+// Retry tries a failed download again: scanning it again when it was
+// imported, or importing it again on the next check when it wasn't
+func (w *Watcher) Retry(id uint32) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	var download Download
+	if err := w.db.First(&download, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrDownloadNotFound
+		}
+		return fmt.Errorf("failed to get download: %w", err)
+	}
+	if download.Status != StatusFailed {
+		return ErrNotFailed
+	}
+
+	if download.ScanJobID == nil {
+		if err := w.db.Delete(&download).Error; err != nil {
+			return fmt.Errorf("failed to reset download: %w", err)
+		}
+		return nil
+	}
+	if err := w.db.Model(&download).Updates(map[string]interface{}{
+		"status":      StatusScanPending,
+		"error":       "",
+		"scan_job_id": nil,
+	}).Error; err != nil {
+		return fmt.Errorf("failed to reset download: %w", err)
+	}
+	return nil
+}
+
+// checkFolder imports the downloads of a folder whose files haven't
+// changed for the configured time
+func (w *Watcher) checkFolder(folder config.DownloadFolder, now time.Time) {
+	entries, err := os.ReadDir(folder.Path)
+	if err != nil {
+		log.Printf("WARN: Failed to read download folder %s: %v", folder.Path, err)
+		return
+	}
+
+	seen := make(map[string]bool, len(entries))
+	for _, entry := range entries {
+		name := entry.Name()
+		if strings.HasPrefix(name, ".") || isPartial(name) {
+			continue
+		}
+		path := filepath.Join(folder.Path, name)
+		seen[path] = true
+
+		handled, err := w.handled(path)
+		if err != nil {
+			log.Printf("WARN: Failed to check download %s: %v", path, err)
+			continue
+		}
+		if handled {
+			delete(w.waiting, path)
+			continue
+		}
+
+		snap, err := takeSnapshot(path)
+		if err != nil {
+			log.Printf("WARN: Failed to read download %s: %v", path, err)
+			continue
+		}
+		waiting, ok := w.waiting[path]
+		if !ok || snap != waiting.snapshot {
+			w.waiting[path] = &candidate{snapshot: snap, since: now}
+			continue
+		}
+		if snap.partial || snap.mediaFiles == 0 || now.Sub(waiting.since) < w.config.StableFor {
+			continue
+		}
+
+		delete(w.waiting, path)
+		w.importDownload(folder, path, snap)
+	}
+
+	// Forget downloads deleted before they finished
+	for path := range w.waiting {
+		if filepath.Dir(path) == filepath.Clean(folder.Path) && !seen[path] {
+			delete(w.waiting, path)
+		}
+	}
+}
+
+// handled reports whether a download was already imported. A moved
+// download that shows up again is a new download with the same name, as
+// importing it took its files away.
+func (w *Watcher) handled(path string) (bool, error) {
+	var downloads []Download
+	if err := w.db.Where("source_path = ?", path).Order("id DESC").Limit(1).Find(&downloads).Error; err != nil {
+		return false, err
+	}
+	if len(downloads) == 0 {
+		return false, nil
+	}
+	last := downloads[0]
+	return last.Mode != organizermodule.ModeMove || last.Status == StatusFailed, nil
+}
+
+// importDownload puts a finished download into its library and starts the
+// scan of its files
+func (w *Watcher) importDownload(folder config.DownloadFolder, path string, snap snapshot) {
+	download := &Download{
+		SourcePath: path,
+		LibraryID:  folder.LibraryID,
+		Mode:       folder.Import,
+		ImportPath: path,
+		Files:      snap.files,
+		SizeBytes:  snap.sizeBytes,
+		Organize:   folder.Organize,
+		Status:     StatusScanPending,
+	}
+
+	if err := w.importFiles(folder, download); err != nil {
+		download.Status, download.Error = StatusFailed, err.Error()
+		log.Printf("WARN: Failed to import download %s: %v", path, err)
+	}
+	if err := w.db.Create(download).Error; err != nil {
+		log.Printf("ERROR: Failed to record download %s: %v", path, err)
+		return
+	}
+	if download.Status == StatusFailed {
+		return
+	}
+
+	log.Printf("INFO: Imported download %s into library %d (%d files)", path, folder.LibraryID, snap.files)
+	w.startScan(download)
+}
+
+// importFiles moves, hardlinks or copies a download's files into its
+// library, keeping their layout. Downloads in folders without an import
+// mode are scanned where they are, so the folder must be in the library.
+func (w *Watcher) importFiles(folder config.DownloadFolder, download *Download) error {
+	var library database.MediaLibrary
+	if err := w.db.First(&library, folder.LibraryID).Error; err != nil {
+		return fmt.Errorf("library %d not found", folder.LibraryID)
+	}
+
+	if folder.Import == "" {
+		if !utils.WithinDir(download.SourcePath, library.Path) {
+			return fmt.Errorf("download folder %s is outside library %s and has no import mode", folder.Path, library.Path)
+		}
+		return nil
+	}
+	// Downloads left in the library would be scanned twice
+	if utils.WithinDir(folder.Path, library.Path) {
+		return fmt.Errorf("download folder %s is inside library %s; leave its import mode empty", folder.Path, library.Path)
+	}
+
+	download.ImportPath = filepath.Join(library.Path, filepath.Base(download.SourcePath))
+	if _, err := os.Lstat(download.ImportPath); err == nil {
+		return fmt.Errorf("%s already exists in the library", download.ImportPath)
+	}
+
+	var dirs []string
+	err := filepath.WalkDir(download.SourcePath, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(download.SourcePath, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(download.ImportPath, rel)
+		if d.IsDir() {
+			dirs = append(dirs, path)
+			return os.MkdirAll(target, 0755)
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+			return err
+		}
+		return organizermodule.TransferFile(folder.Import, path, target)
+	})
+	if err != nil {
+		return err
+	}
+
+	if folder.Import == organizermodule.ModeMove {
+		// Deepest first; directories with files left in them stay
+		for i := len(dirs) - 1; i >= 0; i-- {
+			os.Remove(dirs[i])
+		}
+	}
+	return nil
+}
+
+// startScan scans an imported download. While another scan of the library
+// runs it stays pending and is tried again on the next check.
+func (w *Watcher) startScan(download *Download) {
+	scanner := w.scanner()
+	if scanner == nil {
+		w.db.Model(download).Update("error", "the scanner is not available")
+		return
+	}
+
+	job, err := scanner.ScanPaths(download.LibraryID, []string{download.ImportPath})
+	if err != nil {
+		w.db.Model(download).Update("error", err.Error())
+		return
+	}
+	w.db.Model(download).Updates(map[string]interface{}{
+		"status":      StatusScanning,
+		"scan_job_id": job.ID,
+		"error":       "",
+	})
+}
+
+// advance starts pending scans and finishes downloads whose scans are done
+func (w *Watcher) advance(now time.Time) {
+	var downloads []Download
+	if err := w.db.Where("status IN ?", []string{StatusScanPending, StatusScanning}).
+		Order("id").Find(&downloads).Error; err != nil {
+		log.Printf("WARN: Failed to get downloads in progress: %v", err)
+		return
+	}
+
+	for i := range downloads {
+		download := &downloads[i]
+		if download.Status == StatusScanPending {
+			w.startScan(download)
+			continue
+		}
+
+		var jobs []database.ScanJob
+		if download.ScanJobID != nil {
+			w.db.Where("id = ?", *download.ScanJobID).Limit(1).Find(&jobs)
+		}
+		if len(jobs) == 0 {
+			// The job was cleaned up before it finished; scan again
+			w.db.Model(download).Update("status", StatusScanPending)
+			continue
+		}
+		switch jobs[0].Status {
+		case "completed":
+			w.complete(download, now)
+		case "failed":
+			w.db.Model(download).Updates(map[string]interface{}{
+				"status": StatusFailed,
+				"error":  fmt.Sprintf("scan failed: %s", jobs[0].ErrorMessage),
+			})
+		}
+	}
+}
+
+// complete organizes a scanned download when its folder asks for it
+func (w *Watcher) complete(download *Download, now time.Time) {
+	updates := map[string]interface{}{
+		"status":       StatusCompleted,
+		"error":        "",
+		"completed_at": now,
+	}
+	if download.Organize {
+		batchID, err := w.organize(download)
+		if err != nil {
+			log.Printf("WARN: Failed to organize download %s: %v", download.ImportPath, err)
+			updates["error"] = err.Error()
+		}
+		updates["organize_batch_id"] = batchID
+	}
+	if err := w.db.Model(download).Updates(updates).Error; err != nil {
+		log.Printf("ERROR: Failed to complete download %s: %v", download.SourcePath, err)
+		return
+	}
+
+	log.Printf("INFO: Download %s scanned into library %d", download.ImportPath, download.LibraryID)
+	if w.eventBus != nil {
+		event := events.NewSystemEvent(
+			events.EventDownloadImported,
+			"Download Imported",
+			fmt.Sprintf("Imported %s", filepath.Base(download.ImportPath)),
+		)
+		event.Data = map[string]interface{}{
+			"download_id": download.ID,
+			"library_id":  download.LibraryID,
+			"source_path": download.SourcePath,
+			"import_path": download.ImportPath,
+			"files":       download.Files,
+		}
+		w.eventBus.PublishAsync(event)
+	}
+}
+
+// organize runs the organizer on the media files scanned from a download,
+// returning the batch it journaled them under
+func (w *Watcher) organize(download *Download) (string, error) {
+	organizer := w.organizer()
+	if organizer == nil {
+		return "", fmt.Errorf("the file organizer is disabled")
+	}
+
+	var files []database.MediaFile
+	if err := w.db.Select("id, path").
+		Where("library_id = ? AND (path = ? OR path LIKE ?)", download.LibraryID, download.ImportPath, download.ImportPath+"%").
+		Find(&files).Error; err != nil {
+		return "", fmt.Errorf("failed to get media files: %w", err)
+	}
+	var ids []string
+	for _, file := range files {
+		if utils.WithinDir(file.Path, download.ImportPath) {
+			ids = append(ids, file.ID)
+		}
+	}
+	if len(ids) == 0 {
+		return "", nil
+	}
+
+	plan, err := organizer.Apply(organizermodule.Request{MediaFileIDs: ids})
+	if err != nil {
+		return "", err
+	}
+	for _, move := range plan.Moves {
+		if move.Status == organizermodule.StatusConflict || move.Status == organizermodule.StatusFailed {
+			log.Printf("WARN: Downloaded file %s not organized: %s", move.SourcePath, move.Reason)
+		}
+	}
+	return plan.BatchID, nil
+}
+
+// takeSnapshot reads the files of a download
+func takeSnapshot(root string) (snapshot, error) {
+	var snap snapshot
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if isPartial(d.Name()) {
+			snap.partial = true
+		}
+		if d.IsDir() || !d.Type().IsRegular() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		snap.files++
+		snap.sizeBytes += info.Size()
+		if info.ModTime().After(snap.modTime) {
+			snap.modTime = info.ModTime()
+		}
+		if utils.IsMediaFileOptimized(path) {
+			snap.mediaFiles++
+		}
+		return nil
+	})
+	return snap, err
+}
+
+// isPartial reports whether a file or directory name says a download
+// client isn't done with it
+func isPartial(name string) bool {
+	lower := strings.ToLower(name)
+	for _, suffix := range partialSuffixes {
+		if strings.HasSuffix(lower, suffix) {
+			return true
+		}
+	}
+	for _, prefix := range partialPrefixes {
+		if strings.HasPrefix(lower, prefix) {
+			return true
+		}
+	}
+	return false
+}
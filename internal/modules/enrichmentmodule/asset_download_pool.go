@@ -0,0 +1,253 @@
+package enrichmentmodule
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mantonx/viewra/internal/config"
+)
+
+// Longest wait between retries, however long a provider's Retry-After asks for
+const maxDownloadBackoff = time.Minute
+
+// assetDownload is a downloaded asset before it is saved
+type assetDownload struct {
+	Data       []byte
+	MimeType   string
+	StatusCode int
+}
+
+// assetDownloadError is a download that failed, with the HTTP status of the
+// last attempt if the provider answered
+type assetDownloadError struct {
+	StatusCode int
+	err        error
+}
+
+func (e *assetDownloadError) Error() string { return e.err.Error() }
+func (e *assetDownloadError) Unwrap() error { return e.err }
+
+// assetDownloadPool downloads assets for every plugin through one shared
+// policy: at most Concurrency downloads run at once, requests to the same
+// host are spaced HostDelay apart and failures worth retrying (network
+// errors, 429 and 5xx responses) are retried with exponential backoff.
+type assetDownloadPool struct {
+	client      *http.Client
+	slots       chan struct{}
+	hostDelay   time.Duration
+	retries     int
+	baseBackoff time.Duration
+	maxSize     int64
+
+	mu       sync.Mutex
+	hostNext map[string]time.Time
+}
+
+func newAssetDownloadPool(cfg config.AssetConfig) *assetDownloadPool {
+	concurrency := cfg.DownloadConcurrency
+	if concurrency <= 0 {
+		concurrency = 4
+	}
+	timeout := cfg.DownloadTimeout
+	if timeout <= 0 {
+		timeout = 30 * time.Second
+	}
+	retries := cfg.DownloadRetries
+	if retries < 0 {
+		retries = 0
+	}
+
+	return &assetDownloadPool{
+		client:      &http.Client{Timeout: timeout},
+		slots:       make(chan struct{}, concurrency),
+		hostDelay:   cfg.DownloadHostDelay,
+		retries:     retries,
+		baseBackoff: time.Second,
+		maxSize:     cfg.MaxFileSize,
+		hostNext:    make(map[string]time.Time),
+	}
+}
+
+// Download fetches rawURL, waiting for the host's turn and a free slot
+// before each attempt. The slot is only held while a request is in flight,
+// so downloads waiting on a host or backing off don't hold up other hosts.
+// maxSize limits the asset below the pool's own limit when positive.
+func (p *assetDownloadPool) Download(ctx context.Context, rawURL string, headers map[string]string, maxSize int64) (*assetDownload, error) {
+	parsed, err := url.Parse(rawURL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return nil, &assetDownloadError{err: fmt.Errorf("invalid asset URL: %s", rawURL)}
+	}
+	if p.maxSize > 0 && (maxSize <= 0 || maxSize > p.maxSize) {
+		maxSize = p.maxSize
+	}
+
+	var lastErr error
+	for attempt := 0; attempt <= p.retries; attempt++ {
+		if err := p.waitForHost(ctx, parsed.Host); err != nil {
+			return nil, err
+		}
+
+		download, retryAfter, err := p.fetchInSlot(ctx, rawURL, headers, maxSize)
+		if err == nil {
+			return download, nil
+		}
+		lastErr = err
+		if retryAfter < 0 || attempt == p.retries {
+			break
+		}
+
+		backoff := p.baseBackoff << attempt
+		if retryAfter > backoff {
+			backoff = retryAfter
+		}
+		if backoff > maxDownloadBackoff {
+			backoff = maxDownloadBackoff
+		}
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	return nil, lastErr
+}
+
+// waitForHost takes the host's next turn and sleeps until it comes
+func (p *assetDownloadPool) waitForHost(ctx context.Context, host string) error {
+	if p.hostDelay <= 0 {
+		return nil
+	}
+
+	p.mu.Lock()
+	now := time.Now()
+	turn := p.hostNext[host]
+	if turn.Before(now) {
+		turn = now
+	}
+	p.hostNext[host] = turn.Add(p.hostDelay)
+	p.mu.Unlock()
+
+	wait := time.Until(turn)
+	if wait <= 0 {
+		return nil
+	}
+	select {
+	case <-time.After(wait):
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// fetchInSlot waits for a free slot and makes one attempt in it
+func (p *assetDownloadPool) fetchInSlot(ctx context.Context, rawURL string, headers map[string]string, maxSize int64) (*assetDownload, time.Duration, error) {
+	select {
+	case p.slots <- struct{}{}:
+	case <-ctx.Done():
+		return nil, -1, ctx.Err()
+	}
+	defer func() { <-p.slots }()
+
+	return p.fetch(ctx, rawURL, headers, maxSize)
+}
+
+// fetch makes one attempt. A non-negative retryAfter means the failure is
+// worth retrying, after at least that long.
+func (p *assetDownloadPool) fetch(ctx context.Context, rawURL string, headers map[string]string, maxSize int64) (*assetDownload, time.Duration, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, -1, &assetDownloadError{err: err}
+	}
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, -1, ctx.Err()
+		}
+		return nil, 0, &assetDownloadError{err: fmt.Errorf("failed to download asset: %w", err)}
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		// Drain a little so the connection can be reused
+		io.Copy(io.Discard, io.LimitReader(resp.Body, 64*1024))
+		downloadErr := &assetDownloadError{
+			StatusCode: resp.StatusCode,
+			err:        fmt.Errorf("HTTP error %d downloading asset", resp.StatusCode),
+		}
+		if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500 {
+			return nil, parseRetryAfter(resp.Header.Get("Retry-After")), downloadErr
+		}
+		return nil, -1, downloadErr
+	}
+
+	body := io.Reader(resp.Body)
+	if maxSize > 0 {
+		if resp.ContentLength > maxSize {
+			return nil, -1, &assetDownloadError{
+				StatusCode: resp.StatusCode,
+				err:        fmt.Errorf("asset too large: %d bytes > %d bytes limit", resp.ContentLength, maxSize),
+			}
+		}
+		body = io.LimitReader(resp.Body, maxSize+1)
+	}
+	data, err := io.ReadAll(body)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, -1, ctx.Err()
+		}
+		return nil, 0, &assetDownloadError{StatusCode: resp.StatusCode, err: fmt.Errorf("failed to read asset: %w", err)}
+	}
+	if maxSize > 0 && int64(len(data)) > maxSize {
+		return nil, -1, &assetDownloadError{
+			StatusCode: resp.StatusCode,
+			err:        fmt.Errorf("asset too large: over %d bytes limit", maxSize),
+		}
+	}
+
+	mimeType := resp.Header.Get("Content-Type")
+	if i := strings.Index(mimeType, ";"); i >= 0 {
+		mimeType = strings.TrimSpace(mimeType[:i])
+	}
+	if mimeType == "" || mimeType == "application/octet-stream" {
+		mimeType = http.DetectContentType(data)
+	}
+
+	return &assetDownload{Data: data, MimeType: mimeType, StatusCode: resp.StatusCode}, 0, nil
+}
+
+// parseRetryAfter reads a Retry-After header given in seconds or as a date
+func parseRetryAfter(value string) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if at, err := http.ParseTime(value); err == nil {
+		if wait := time.Until(at); wait > 0 {
+			return wait
+		}
+	}
+	return 0
+}
+
+// downloadStatusCode is the HTTP status of a failed download, if any
+func downloadStatusCode(err error) int {
+	var downloadErr *assetDownloadError
+	if errors.As(err, &downloadErr) {
+		return downloadErr.StatusCode
+	}
+	return 0
+}
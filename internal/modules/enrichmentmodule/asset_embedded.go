@@ -0,0 +1,55 @@
+package enrichmentmodule
+
+import (
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/mantonx/viewra/internal/modules/assetmodule"
+)
+
+// assetCategories are the categories plugin asset requests map to an entity
+// by; other categories fall back to the media file's type
+var assetCategories = map[string]bool{
+	"album": true, "artist": true, "track": true, "movie": true,
+	"tv": true, "collection": true, "episode": true,
+}
+
+// frontCoverSubtypes are the subtypes plugins send album front covers as
+var frontCoverSubtypes = map[string]bool{
+	"": true, "album_front": true, "front": true, "cover": true, "artwork": true, "poster": true,
+}
+
+// embeddedAlbumCover returns the cover art embedded in the music files of an
+// album when a plugin's asset request is for that album's front cover and
+// the embedded art is large enough to keep. Remote art is then not needed,
+// so it isn't downloaded or saved. It returns nil for any other request.
+func (s *AssetGRPCServer) embeddedAlbumCover(mediaFileID, category, subtype string) *assetmodule.AssetResponse {
+	category = strings.ToLower(category)
+	if (category != "album" && assetCategories[category]) || !frontCoverSubtypes[strings.ToLower(subtype)] {
+		return nil
+	}
+	assetManager := assetmodule.GetAssetManager()
+	if assetManager == nil {
+		return nil
+	}
+
+	var albumIDs []string
+	err := s.db.Table("media_files").
+		Joins("JOIN tracks ON tracks.id = media_files.media_id").
+		Where("media_files.id = ? AND media_files.media_type = ?", mediaFileID, "track").
+		Limit(1).Pluck("tracks.album_id", &albumIDs).Error
+	if err != nil || len(albumIDs) == 0 {
+		return nil
+	}
+	albumID, err := uuid.Parse(albumIDs[0])
+	if err != nil {
+		return nil
+	}
+
+	cover, err := assetManager.EmbeddedCover(albumID)
+	if err != nil {
+		s.logger.Warn("failed to check embedded cover", "album_id", albumID, "error", err)
+		return nil
+	}
+	return cover
+}
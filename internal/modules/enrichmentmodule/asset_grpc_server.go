@@ -3,7 +3,9 @@ package enrichmentmodule
 import (
 	"context"
 	"crypto/md5"
+	"crypto/sha256"
 	"encoding/binary"
+	"encoding/hex"
 	"fmt"
 	"log"
 	"strings"
@@ -11,7 +13,9 @@ import (
 	"github.com/google/uuid"
 	"github.com/hashicorp/go-hclog"
 	"github.com/mantonx/viewra/internal/config"
+	"github.com/mantonx/viewra/internal/database"
 	"github.com/mantonx/viewra/internal/modules/assetmodule"
+	"github.com/mantonx/viewra/internal/modules/subtitlemodule"
 	"github.com/mantonx/viewra/sdk/proto"
 	"google.golang.org/grpc/codes"
 	grpcstatus "google.golang.org/grpc/status"
@@ -32,17 +36,19 @@ import (
 // AssetGRPCServer implements the asset management service for external plugins
 type AssetGRPCServer struct {
 	proto.UnimplementedAssetServiceServer
-	logger hclog.Logger
-	config *config.Config
-	db     *gorm.DB
+	logger    hclog.Logger
+	config    *config.Config
+	db        *gorm.DB
+	downloads *assetDownloadPool
 }
 
 // NewAssetGRPCServer creates a new asset gRPC server instance
 func NewAssetGRPCServer(logger hclog.Logger, config *config.Config, db *gorm.DB) *AssetGRPCServer {
 	return &AssetGRPCServer{
-		logger: logger.Named("asset-grpc-server"),
-		config: config,
-		db:     db,
+		logger:    logger.Named("asset-grpc-server"),
+		config:    config,
+		db:        db,
+		downloads: newAssetDownloadPool(config.Assets),
 	}
 }
 
@@ -73,6 +79,21 @@ func (s *AssetGRPCServer) SaveAsset(ctx context.Context, req *proto.SaveAssetReq
 		}, nil
 	}
 
+	// Subtitles belong to the media file they were fetched for, not its movie or show
+	if strings.EqualFold(req.AssetType, "subtitle") {
+		return s.saveSubtitle(ctx, req)
+	}
+
+	// Cover art embedded in an album's files is kept over remote art
+	if cover := s.embeddedAlbumCover(req.MediaFileId, req.Category, req.Subtype); cover != nil {
+		s.logger.Debug("kept embedded album cover", "media_file_id", req.MediaFileId, "asset_id", cover.ID, "plugin_id", req.PluginId)
+		return &proto.SaveAssetResponse{
+			Success:      true,
+			AssetId:      s.uuidToUint32(cover.ID),
+			RelativePath: cover.Path,
+		}, nil
+	}
+
 	// Find the media file to get the associated album
 	var mediaFile struct {
 		ID       string
@@ -206,6 +227,25 @@ func (s *AssetGRPCServer) SaveAsset(ctx context.Context, req *proto.SaveAssetReq
 				}, nil
 			}
 		}
+	case "collection":
+		// Collection artwork is shared by every movie of the collection
+		entityType = assetmodule.EntityTypeCollection
+		tmdbID := req.Metadata["tmdb_collection_id"]
+		if tmdbID == "" {
+			return &proto.SaveAssetResponse{
+				Success: false,
+				Error:   "collection assets need tmdb_collection_id metadata",
+			}, nil
+		}
+		collection, err := ensureCollection(s.db, tmdbID, req.Metadata["collection_name"])
+		if err != nil {
+			s.logger.Error("Failed to find collection for asset", "tmdb_collection_id", tmdbID, "error", err)
+			return &proto.SaveAssetResponse{
+				Success: false,
+				Error:   err.Error(),
+			}, nil
+		}
+		entityID = uuid.MustParse(collection.ID)
 	case "episode":
 		entityType = assetmodule.EntityTypeEpisode
 	default:
@@ -271,7 +311,7 @@ func (s *AssetGRPCServer) SaveAsset(ctx context.Context, req *proto.SaveAssetReq
 		assetType = assetmodule.AssetTypeBanner
 	case "logo":
 		assetType = assetmodule.AssetTypeLogo
-	case "thumb", "thumbnail":
+	case "thumb", "thumbnail", "still":
 		assetType = assetmodule.AssetTypeThumb
 	default:
 		assetType = assetmodule.AssetTypeCover // Default to cover
@@ -327,6 +367,55 @@ func (s *AssetGRPCServer) SaveAsset(ctx context.Context, req *proto.SaveAssetReq
 	}, nil
 }
 
+// saveSubtitle saves a subtitle a plugin downloaded for a media file. The
+// language, forced, sdh and format metadata describe the track.
+func (s *AssetGRPCServer) saveSubtitle(ctx context.Context, req *proto.SaveAssetRequest) (*proto.SaveAssetResponse, error) {
+	if req.PluginId == "" {
+		return nil, grpcstatus.Error(codes.InvalidArgument, "plugin_id is required for subtitles")
+	}
+
+	var mediaFile database.MediaFile
+	if err := s.db.Where("id = ?", req.MediaFileId).First(&mediaFile).Error; err != nil {
+		s.logger.Error("Failed to find media file", "media_file_id", req.MediaFileId, "error", err)
+		return &proto.SaveAssetResponse{
+			Success: false,
+			Error:   fmt.Sprintf("media file not found: %v", err),
+		}, nil
+	}
+
+	format := req.Metadata["format"]
+	if format == "" {
+		format = req.MimeType
+	}
+	response, err := subtitlemodule.SaveDownload(ctx, &mediaFile, subtitlemodule.Download{
+		PluginID: req.PluginId,
+		Language: req.Metadata["language"],
+		Forced:   req.Metadata["forced"] == "true",
+		SDH:      req.Metadata["sdh"] == "true",
+		Format:   format,
+		Data:     req.Data,
+	})
+	if err != nil {
+		s.logger.Error("Failed to save subtitle", "media_file_id", req.MediaFileId, "plugin_id", req.PluginId, "error", err)
+		return &proto.SaveAssetResponse{
+			Success: false,
+			Error:   fmt.Sprintf("failed to save subtitle: %v", err),
+		}, nil
+	}
+
+	s.logger.Info("Saved downloaded subtitle",
+		"asset_id", response.ID,
+		"media_file_id", req.MediaFileId,
+		"language", response.Language,
+		"plugin_id", req.PluginId)
+
+	return &proto.SaveAssetResponse{
+		Success:      true,
+		AssetId:      s.uuidToUint32(response.ID),
+		RelativePath: response.Path,
+	}, nil
+}
+
 // uuidToUint32 converts a UUID to uint32 for legacy gRPC compatibility
 func (s *AssetGRPCServer) uuidToUint32(id uuid.UUID) uint32 {
 	// Create a hash of the UUID and take the first 4 bytes
@@ -511,7 +600,7 @@ func (s *AssetGRPCServer) AssetExists(ctx context.Context, req *proto.AssetExist
 		assetType = assetmodule.AssetTypeBanner
 	case "logo":
 		assetType = assetmodule.AssetTypeLogo
-	case "thumb", "thumbnail":
+	case "thumb", "thumbnail", "still":
 		assetType = assetmodule.AssetTypeThumb
 	default:
 		assetType = assetmodule.AssetTypeCover
@@ -534,16 +623,31 @@ func (s *AssetGRPCServer) AssetExists(ctx context.Context, req *proto.AssetExist
 		}, nil
 	}
 
-	// Return the first asset found
-	asset := assets[0]
-	s.logger.Debug("Found existing asset", 
-		"asset_id", asset.ID,
-		"path", asset.Path)
+	// Return the first asset found. Low resolution embedded album art doesn't
+	// count, so plugins still fetch a better cover.
+	for _, asset := range assets {
+		if assetmodule.IsLowResolutionEmbeddedCover(asset) {
+			continue
+		}
+		s.logger.Debug("Found existing asset", 
+			"asset_id", asset.ID,
+			"path", asset.Path)
+
+		return &proto.AssetExistsResponse{
+			Exists:       true,
+			AssetId:      s.uuidToUint32(asset.ID),
+			RelativePath: asset.Path,
+		}, nil
+	}
 
+	s.logger.Debug("Only low resolution embedded artwork found",
+		"entity_type", entityType,
+		"entity_id", entityID,
+		"asset_type", assetType)
 	return &proto.AssetExistsResponse{
-		Exists:       true,
-		AssetId:      s.uuidToUint32(asset.ID),
-		RelativePath: asset.Path,
+		Exists:       false,
+		AssetId:      0,
+		RelativePath: "",
 	}, nil
 }
 
@@ -575,4 +679,75 @@ func (s *AssetGRPCServer) RemoveAsset(ctx context.Context, req *proto.RemoveAsse
 		Success: false,
 		Error:   "asset removal by ID not implemented in UUID-based system",
 	}, nil
-} 
\ No newline at end of file
+} 
+
+// DownloadAsset downloads an asset through the shared download pool and saves
+// it as SaveAsset would. Failed downloads are reported in the response along
+// with the provider's HTTP status, so plugins can tell a missing image from
+// one worth trying again later.
+func (s *AssetGRPCServer) DownloadAsset(ctx context.Context, req *proto.DownloadAssetRequest) (*proto.DownloadAssetResponse, error) {
+	if req.Url == "" {
+		return nil, grpcstatus.Error(codes.InvalidArgument, "url is required")
+	}
+	if req.MediaFileId == "" {
+		return nil, grpcstatus.Error(codes.InvalidArgument, "media_file_id is required")
+	}
+	if req.AssetType == "" {
+		return nil, grpcstatus.Error(codes.InvalidArgument, "asset_type is required")
+	}
+
+	// Album covers aren't downloaded when the album's files carry good enough art
+	if cover := s.embeddedAlbumCover(req.MediaFileId, req.Category, req.Subtype); cover != nil {
+		s.logger.Debug("kept embedded album cover", "media_file_id", req.MediaFileId, "url", req.Url, "asset_id", cover.ID, "plugin_id", req.PluginId)
+		return &proto.DownloadAssetResponse{
+			Success:      true,
+			AssetId:      s.uuidToUint32(cover.ID),
+			RelativePath: cover.Path,
+			MimeType:     cover.Format,
+		}, nil
+	}
+
+	download, err := s.downloads.Download(ctx, req.Url, req.Headers, req.MaxSize)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, grpcstatus.FromContextError(ctx.Err()).Err()
+		}
+		s.logger.Debug("asset download failed", "url", req.Url, "plugin_id", req.PluginId, "error", err)
+		return &proto.DownloadAssetResponse{
+			Success:    false,
+			Error:      err.Error(),
+			StatusCode: int32(downloadStatusCode(err)),
+		}, nil
+	}
+
+	saved, err := s.SaveAsset(ctx, &proto.SaveAssetRequest{
+		MediaFileId: req.MediaFileId,
+		AssetType:   req.AssetType,
+		Category:    req.Category,
+		Subtype:     req.Subtype,
+		Data:        download.Data,
+		MimeType:    download.MimeType,
+		SourceUrl:   req.Url,
+		PluginId:    req.PluginId,
+		Metadata:    req.Metadata,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	hash := saved.Hash
+	if hash == "" {
+		sum := sha256.Sum256(download.Data)
+		hash = hex.EncodeToString(sum[:])
+	}
+	return &proto.DownloadAssetResponse{
+		Success:      saved.Success,
+		Error:        saved.Error,
+		AssetId:      saved.AssetId,
+		Hash:         hash,
+		RelativePath: saved.RelativePath,
+		Size:         int64(len(download.Data)),
+		MimeType:     download.MimeType,
+		StatusCode:   int32(download.StatusCode),
+	}, nil
+}
@@ -0,0 +1,161 @@
+package enrichmentmodule
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+
+	"github.com/mantonx/viewra/internal/database"
+)
+
+// workRelationship is one entry of the work_relationships field: a
+// MusicBrainz recording relationship to a work or to one of its performers
+type workRelationship struct {
+	Type       string `json:"type"` // performance, composer, conductor, performing orchestra
+	TargetMBID string `json:"target_mbid"`
+	TargetName string `json:"target_name"`
+	ParentMBID string `json:"parent_mbid"` // Parent work when the target is a movement
+	ParentName string `json:"parent_name"`
+	Position   int    `json:"position"` // Movement number within the parent work
+}
+
+// classicalFieldRules returns the rules for the classical track fields.
+// Embedded tags win over MusicBrainz, as for other track fields.
+func classicalFieldRules() map[string]FieldRule {
+	text := func(name string) FieldRule {
+		return FieldRule{
+			FieldName:      name,
+			MediaTypes:     []string{"track"},
+			SourcePriority: []string{"embedded", "musicbrainz"},
+			MergeStrategy:  MergeStrategyReplace,
+			ValidateFunc:   func(value string) bool { return strings.TrimSpace(value) != "" },
+			NormalizeFunc:  func(value string) string { return strings.TrimSpace(value) },
+		}
+	}
+	number := func(name string) FieldRule {
+		rule := text(name)
+		rule.ValidateFunc = func(value string) bool {
+			n, err := strconv.Atoi(strings.TrimSpace(value))
+			return err == nil && n > 0 && n <= 999
+		}
+		return rule
+	}
+
+	relationships := text("work_relationships")
+	relationships.SourcePriority = []string{"musicbrainz"}
+	relationships.ValidateFunc = func(value string) bool {
+		value = strings.TrimSpace(value)
+		return strings.HasPrefix(value, "[") && json.Valid([]byte(value))
+	}
+
+	return map[string]FieldRule{
+		"composer":           text("composer"),
+		"work":               text("work"),
+		"work_mbid":          text("work_mbid"),
+		"movement":           text("movement"),
+		"movement_number":    number("movement_number"),
+		"movement_count":     number("movement_count"),
+		"conductor":          text("conductor"),
+		"orchestra":          text("orchestra"),
+		"work_relationships": relationships,
+	}
+}
+
+// isClassicalField reports whether a track field is one of the classical fields
+func isClassicalField(fieldName string) bool {
+	_, ok := classicalFieldRules()[fieldName]
+	return ok
+}
+
+// applyClassicalField stores a classical field on a track
+func (m *Module) applyClassicalField(trackID, fieldName, value string) error {
+	track := m.db.Model(&database.Track{}).Where("id = ?", trackID)
+
+	switch fieldName {
+	case "composer", "work", "movement", "conductor", "orchestra":
+		return track.Update(fieldName, value).Error
+	case "work_mbid":
+		return track.Update("work_mbid", value).Error
+	case "movement_number", "movement_count":
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("invalid %s format: %s", fieldName, value)
+		}
+		return track.Update(fieldName, n).Error
+	case "work_relationships":
+		updates, err := workRelationshipUpdates(value)
+		if err != nil {
+			return err
+		}
+		if len(updates) == 0 {
+			return nil
+		}
+		return track.Updates(updates).Error
+	default:
+		log.Printf("WARN: Unknown classical field: %s", fieldName)
+		return nil
+	}
+}
+
+// workRelationshipUpdates turns a recording's work relationships into track
+// column updates. A performance of a movement records the parent work with
+// the movement; several composers or orchestras are joined.
+func workRelationshipUpdates(value string) (map[string]interface{}, error) {
+	var relationships []workRelationship
+	if err := json.Unmarshal([]byte(value), &relationships); err != nil {
+		return nil, fmt.Errorf("invalid work relationships: %w", err)
+	}
+
+	updates := make(map[string]interface{})
+	var composers, orchestras []string
+	for _, r := range relationships {
+		name := strings.TrimSpace(r.TargetName)
+		if name == "" {
+			continue
+		}
+		switch strings.ToLower(r.Type) {
+		case "performance":
+			if _, done := updates["work"]; done {
+				continue
+			}
+			if r.ParentName != "" {
+				updates["work"] = strings.TrimSpace(r.ParentName)
+				updates["work_mbid"] = r.ParentMBID
+				updates["movement"] = name
+				if r.Position > 0 {
+					updates["movement_number"] = r.Position
+				}
+			} else {
+				updates["work"] = name
+				updates["work_mbid"] = r.TargetMBID
+			}
+		case "composer":
+			composers = appendUnique(composers, name)
+		case "conductor":
+			if _, done := updates["conductor"]; !done {
+				updates["conductor"] = name
+			}
+		case "performing orchestra", "orchestra":
+			orchestras = appendUnique(orchestras, name)
+		}
+	}
+	if len(composers) > 0 {
+		updates["composer"] = strings.Join(composers, ", ")
+	}
+	if len(orchestras) > 0 {
+		updates["orchestra"] = strings.Join(orchestras, ", ")
+	}
+	return updates, nil
+}
+
+// appendUnique appends a name unless it is already in the list
+func appendUnique(names []string, name string) []string {
+	for _, existing := range names {
+		if strings.EqualFold(existing, name) {
+			return names
+		}
+	}
+	return append(names, name)
+}
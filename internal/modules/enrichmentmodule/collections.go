@@ -0,0 +1,117 @@
+package enrichmentmodule
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/mantonx/viewra/internal/database"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// collectionInfo is the value of the collection field: the TMDb collection
+// a movie belongs to
+type collectionInfo struct {
+	ID          int    `json:"id"`
+	Name        string `json:"name"`
+	Overview    string `json:"overview"`
+	PosterURL   string `json:"poster_url"`
+	BackdropURL string `json:"backdrop_url"`
+	PartCount   int    `json:"part_count"`
+}
+
+// collectionFieldRules returns the rule for the collection of a movie
+func collectionFieldRules() map[string]FieldRule {
+	return map[string]FieldRule{
+		"collection": {
+			FieldName:      "collection",
+			MediaTypes:     []string{"movie"},
+			SourcePriority: []string{"tmdb"},
+			MergeStrategy:  MergeStrategyReplace,
+			ValidateFunc: func(value string) bool {
+				_, err := parseCollection(value)
+				return err == nil
+			},
+			NormalizeFunc: func(value string) string { return strings.TrimSpace(value) },
+		},
+	}
+}
+
+func parseCollection(value string) (*collectionInfo, error) {
+	var info collectionInfo
+	if err := json.Unmarshal([]byte(value), &info); err != nil {
+		return nil, fmt.Errorf("invalid collection: %w", err)
+	}
+	if info.ID <= 0 || strings.TrimSpace(info.Name) == "" {
+		return nil, fmt.Errorf("collection needs an id and a name")
+	}
+	return &info, nil
+}
+
+// applyCollection stores the collection a movie belongs to, moving it out of
+// the collection it was in before if it was re-identified
+func (m *Module) applyCollection(movieID, value string) error {
+	info, err := parseCollection(value)
+	if err != nil {
+		return err
+	}
+
+	return m.db.Transaction(func(tx *gorm.DB) error {
+		collection, err := ensureCollection(tx, strconv.Itoa(info.ID), info.Name)
+		if err != nil {
+			return err
+		}
+		if err := tx.Model(collection).Updates(map[string]interface{}{
+			"name":       info.Name,
+			"overview":   info.Overview,
+			"poster":     info.PosterURL,
+			"backdrop":   info.BackdropURL,
+			"part_count": info.PartCount,
+		}).Error; err != nil {
+			return fmt.Errorf("failed to update collection: %w", err)
+		}
+
+		if err := tx.Model(&database.Movie{}).Where("id = ?", movieID).Update("collection", value).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("movie_id = ? AND collection_id <> ?", movieID, collection.ID).
+			Delete(&database.CollectionItem{}).Error; err != nil {
+			return fmt.Errorf("failed to remove movie from its old collection: %w", err)
+		}
+		item := database.CollectionItem{CollectionID: collection.ID, MovieID: movieID}
+		if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&item).Error; err != nil {
+			return fmt.Errorf("failed to add movie to collection: %w", err)
+		}
+		return nil
+	})
+}
+
+// ensureCollection returns the collection with a TMDb ID, creating it when
+// it is new. A collection's poster may arrive before the enrichment that
+// adds its first movie, so either can create it.
+func ensureCollection(db *gorm.DB, tmdbID, name string) (*database.Collection, error) {
+	var found []database.Collection
+	if err := db.Where("tmdb_id = ?", tmdbID).Limit(1).Find(&found).Error; err != nil {
+		return nil, fmt.Errorf("failed to find collection: %w", err)
+	}
+	if len(found) > 0 {
+		return &found[0], nil
+	}
+
+	collection := database.Collection{
+		ID:     uuid.New().String(),
+		TmdbID: tmdbID,
+		Name:   name,
+	}
+	if err := db.Clauses(clause.OnConflict{DoNothing: true}).Create(&collection).Error; err != nil {
+		return nil, fmt.Errorf("failed to create collection: %w", err)
+	}
+	// Another save may have created it first
+	if err := db.Where("tmdb_id = ?", tmdbID).First(&collection).Error; err != nil {
+		return nil, fmt.Errorf("failed to find collection: %w", err)
+	}
+	return &collection, nil
+}
@@ -1,12 +1,17 @@
 package enrichmentmodule
 
 import (
+	"errors"
 	"log"
 	"net/http"
 	"strconv"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/mantonx/viewra/internal/modules/pluginmodule"
+	plugins "github.com/mantonx/viewra/sdk"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 // =============================================================================
@@ -28,12 +33,28 @@ func (m *Module) RegisterRoutes(r *gin.Engine) {
 		enrichment.POST("/apply/:mediaFileId/:fieldName/:sourceName", m.ForceApplyEnrichmentHandler)
 		enrichment.GET("/sources", m.GetEnrichmentSourcesHandler)
 		enrichment.PUT("/sources/:sourceName", m.UpdateEnrichmentSourceHandler)
+		enrichment.GET("/field-priorities", m.GetFieldPrioritiesHandler)
+		enrichment.PUT("/field-priorities/:field", m.SetFieldPriorityHandler)
+		enrichment.DELETE("/field-priorities/:field", m.ResetFieldPriorityHandler)
 		enrichment.GET("/jobs", m.GetEnrichmentJobsHandler)
 		enrichment.POST("/jobs/:mediaFileId", m.TriggerEnrichmentJobHandler)
 		enrichment.GET("/progress", m.GetOverallProgressHandler)
 		enrichment.GET("/progress/tv-shows", m.GetTVShowProgressHandler)
 		enrichment.GET("/progress/movies", m.GetMovieProgressHandler)
 		enrichment.GET("/progress/music", m.GetMusicProgressHandler)
+		enrichment.POST("/simulate", m.SimulateMatchingHandler)
+	}
+
+	// Where each field of a media item came from and its enrichment history,
+	// beside the other /api/media/:id routes
+	media := api.Group("/media")
+	{
+		media.GET("/:id/provenance", m.GetProvenanceHandler)
+		media.PUT("/:id/provenance/:field/lock", m.SetFieldLockHandler)
+		media.GET("/:id/enrichment/history", m.GetEnrichmentHistoryHandler)
+		media.POST("/:id/enrichment/rollback", m.RollbackEnrichmentHandler)
+		media.GET("/:id/identify/search", m.SearchIdentifyHandler)
+		media.POST("/:id/identify", m.IdentifyMediaHandler)
 	}
 
 	log.Printf("✅ Registered enrichment module HTTP routes")
@@ -92,6 +113,235 @@ func (m *Module) ForceApplyEnrichmentHandler(c *gin.Context) {
 	})
 }
 
+// GetProvenanceHandler returns which source set each field of a media item
+func (m *Module) GetProvenanceHandler(c *gin.Context) {
+	provenance, err := m.GetProvenance(c.Param("id"))
+	if errors.Is(err, ErrMediaNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Media not found",
+		})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to get provenance",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"provenance": provenance,
+	})
+}
+
+// SetFieldLockHandler locks a field of a media item against enrichment, or
+// unlocks it
+func (m *Module) SetFieldLockHandler(c *gin.Context) {
+	var req struct {
+		Locked bool `json:"locked"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request body",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	provenance, err := m.SetFieldLock(c.Param("id"), c.Param("field"), req.Locked)
+	if errors.Is(err, ErrMediaNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Media not found",
+		})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to update field lock",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"provenance": provenance,
+	})
+}
+
+// GetEnrichmentHistoryHandler lists the enrichment versions of a media item
+func (m *Module) GetEnrichmentHistoryHandler(c *gin.Context) {
+	versions, err := m.GetEnrichmentHistory(c.Param("id"))
+	if errors.Is(err, ErrMediaNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Media not found",
+		})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to get enrichment history",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"versions": versions,
+		"count":    len(versions),
+	})
+}
+
+// RollbackEnrichmentHandler restores a media item to an earlier enrichment
+// version, the one before the latest unless the body names one
+func (m *Module) RollbackEnrichmentHandler(c *gin.Context) {
+	var req struct {
+		Version int `json:"version"`
+	}
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Invalid request body",
+				"details": err.Error(),
+			})
+			return
+		}
+	}
+
+	snapshot, err := m.RollbackEnrichment(c.Param("id"), req.Version)
+	if errors.Is(err, ErrMediaNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Media not found",
+		})
+		return
+	}
+	if errors.Is(err, ErrNoEarlierVersion) {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "No earlier enrichment version to roll back to",
+		})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to roll back enrichment",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Enrichment rolled back",
+		"version": snapshot,
+	})
+}
+
+// SearchIdentifyHandler searches a plugin's provider for what a media item
+// may be. The plugin query parameter names the plugin; the other query
+// parameters are passed to its search, e.g. title, year or tmdb_id.
+func (m *Module) SearchIdentifyHandler(c *gin.Context) {
+	pluginID := c.Query("plugin")
+	if pluginID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Plugin is required",
+		})
+		return
+	}
+	limit, _ := strconv.ParseUint(c.DefaultQuery("limit", "20"), 10, 32)
+	offset, _ := strconv.ParseUint(c.DefaultQuery("offset", "0"), 10, 32)
+
+	query := make(map[string]string)
+	for key, values := range c.Request.URL.Query() {
+		switch key {
+		case "plugin", "limit", "offset":
+			continue
+		}
+		if len(values) > 0 && values[0] != "" {
+			query[key] = values[0]
+		}
+	}
+
+	result, err := m.SearchIdentifyCandidates(c.Request.Context(), c.Param("id"), pluginID, query, uint32(limit), uint32(offset))
+	if err != nil {
+		writeIdentifyError(c, err, "Failed to search provider")
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// SimulateMatchingHandler reports how a plugin would match a list of file
+// names, without saving anything
+func (m *Module) SimulateMatchingHandler(c *gin.Context) {
+	var req SimulationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request body",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	report, err := m.SimulateMatching(c.Request.Context(), req)
+	if err != nil {
+		if errors.Is(err, ErrInvalidSimulation) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid simulation", "details": err.Error()})
+			return
+		}
+		writeIdentifyError(c, err, "Failed to simulate matching")
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+// IdentifyMediaHandler re-enriches a media item with the search result the
+// user picked, replacing the plugin's earlier match and its artwork
+func (m *Module) IdentifyMediaHandler(c *gin.Context) {
+	var req IdentifyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request body",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	job, err := m.IdentifyMedia(c.Request.Context(), c.Param("id"), req)
+	if err != nil {
+		writeIdentifyError(c, err, "Failed to identify media")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Media identified, enrichment will be applied",
+		"job":     job,
+	})
+}
+
+// writeIdentifyError maps identify failures to HTTP statuses
+func writeIdentifyError(c *gin.Context, err error, message string) {
+	var skip *plugins.SkipError
+	pluginErr, typed := plugins.AsPluginError(err)
+	switch {
+	case errors.Is(err, ErrMediaNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Media not found"})
+	case errors.Is(err, ErrPluginsUnavailable):
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "External plugins are not available"})
+	case errors.Is(err, pluginmodule.ErrPluginNotRunning):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Plugin is not running"})
+	case status.Code(err) == codes.Unimplemented:
+		c.JSON(http.StatusNotImplemented, gin.H{"error": "Plugin does not support this", "details": err.Error()})
+	case errors.As(err, &skip):
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": message, "reason": skip.Reason, "details": skip.Detail})
+	case typed && pluginErr.Code == plugins.ErrorCodeInvalidArgument:
+		c.JSON(http.StatusBadRequest, gin.H{"error": message, "details": pluginErr.Message})
+	case typed && pluginErr.Code == plugins.ErrorCodeNotFound:
+		c.JSON(http.StatusNotFound, gin.H{"error": message, "details": pluginErr.Message})
+	default:
+		c.JSON(http.StatusBadGateway, gin.H{"error": message, "details": err.Error()})
+	}
+}
+
 // GetEnrichmentSourcesHandler returns all enrichment sources
 func (m *Module) GetEnrichmentSourcesHandler(c *gin.Context) {
 	var sources []EnrichmentSource
@@ -157,6 +407,82 @@ func (m *Module) UpdateEnrichmentSourceHandler(c *gin.Context) {
 	})
 }
 
+// GetFieldPrioritiesHandler returns the order sources are preferred in for
+// each field, optionally for one media_type
+func (m *Module) GetFieldPrioritiesHandler(c *gin.Context) {
+	priorities, err := m.FieldPriorities(c.Query("media_type"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to fetch field priorities",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"field_priorities": priorities,
+	})
+}
+
+// SetFieldPriorityHandler sets the order sources are preferred in for a
+// field, for one media type or, without media_type, for all of them
+func (m *Module) SetFieldPriorityHandler(c *gin.Context) {
+	var req struct {
+		MediaType string   `json:"media_type"`
+		Sources   []string `json:"sources" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request body",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	priority, err := m.SetFieldPriority(c.Param("field"), req.MediaType, req.Sources)
+	if errors.Is(err, ErrUnknownField) {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "No enrichment field with that name applies to the media type",
+		})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to update field priority",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":        "Field priority updated successfully",
+		"field_priority": priority,
+	})
+}
+
+// ResetFieldPriorityHandler restores a field's default source order for a
+// media_type, or removes its override for every media type
+func (m *Module) ResetFieldPriorityHandler(c *gin.Context) {
+	err := m.ResetFieldPriority(c.Param("field"), c.Query("media_type"))
+	if errors.Is(err, ErrUnknownField) {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Enrichment field not found",
+		})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to reset field priority",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Field priority reset successfully",
+	})
+}
+
 // GetEnrichmentJobsHandler returns enrichment jobs with optional filtering
 func (m *Module) GetEnrichmentJobsHandler(c *gin.Context) {
 	limitStr := c.DefaultQuery("limit", "50")
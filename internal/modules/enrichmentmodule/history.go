@@ -0,0 +1,399 @@
+package enrichmentmodule
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mantonx/viewra/internal/database"
+	"github.com/mantonx/viewra/internal/events"
+	"gorm.io/gorm"
+)
+
+// Reasons a snapshot was recorded
+const (
+	SnapshotReasonInitial    = "initial"     // State before the first enrichment with history
+	SnapshotReasonEnrichment = "enrichment"  // After the enrichment worker applied fields
+	SnapshotReasonForceApply = "force_apply" // After a field was applied by hand
+	SnapshotReasonRollback   = "rollback"    // After rolling back to an earlier version
+)
+
+// maxEnrichmentSnapshots is how many versions are kept per media item
+const maxEnrichmentSnapshots = 10
+
+// ErrNoEarlierVersion is returned when rolling back an item without history to return to
+var ErrNoEarlierVersion = errors.New("no earlier enrichment version")
+
+// EnrichmentVersion describes one snapshot in a media item's history
+type EnrichmentVersion struct {
+	Version   int       `json:"version"`
+	Reason    string    `json:"reason"`
+	Sources   []string  `json:"sources"`
+	Title     string    `json:"title"`
+	Assets    int       `json:"assets"`
+	Current   bool      `json:"current"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// historyEntity is a row enrichment may change, and its assets
+type historyEntity struct {
+	Table      string
+	EntityType string
+	ID         string
+}
+
+// historyEntities lists the rows enrichment of a media item may change: the
+// item itself, and for tracks the artist and album whose names it sets
+func (m *Module) historyEntities(mediaFile *database.MediaFile) ([]historyEntity, error) {
+	switch mediaFile.MediaType {
+	case database.MediaTypeMovie:
+		return []historyEntity{{"movies", "movie", mediaFile.MediaID}}, nil
+	case database.MediaTypeEpisode:
+		return []historyEntity{{"episodes", "episode", mediaFile.MediaID}}, nil
+	case database.MediaTypeTrack:
+		var track database.Track
+		if err := m.db.Select("id, album_id, artist_id").Where("id = ?", mediaFile.MediaID).First(&track).Error; err != nil {
+			return nil, fmt.Errorf("track not found: %w", err)
+		}
+		return []historyEntity{
+			{"tracks", "track", track.ID},
+			{"albums", "album", track.AlbumID},
+			{"artists", "artist", track.ArtistID},
+		}, nil
+	default:
+		return nil, fmt.Errorf("unsupported media type: %s", mediaFile.MediaType)
+	}
+}
+
+// recordHistory records a snapshot of a media item, logging rather than
+// failing the enrichment that triggered it
+func (m *Module) recordHistory(mediaFile *database.MediaFile, reason string) {
+	if reason == SnapshotReasonInitial {
+		var count int64
+		if err := m.db.Model(&database.MediaEnrichmentSnapshot{}).Where("media_id = ?", mediaFile.MediaID).
+			Count(&count).Error; err != nil || count > 0 {
+			return
+		}
+	}
+	if _, err := m.takeSnapshot(mediaFile, reason); err != nil {
+		log.Printf("WARN: Failed to record enrichment history of %s: %v", mediaFile.MediaID, err)
+	}
+}
+
+// takeSnapshot records the current enriched state of a media item as its next version
+func (m *Module) takeSnapshot(mediaFile *database.MediaFile, reason string) (*database.MediaEnrichmentSnapshot, error) {
+	entities, err := m.historyEntities(mediaFile)
+	if err != nil {
+		return nil, err
+	}
+
+	rows := make(map[string]map[string]interface{})
+	var assets []map[string]interface{}
+	for _, entity := range entities {
+		row := make(map[string]interface{})
+		if err := m.db.Table(entity.Table).Where("id = ?", entity.ID).Take(&row).Error; err != nil {
+			return nil, fmt.Errorf("failed to read %s %s: %w", entity.EntityType, entity.ID, err)
+		}
+		rows[entity.Table] = row
+
+		var entityAssets []map[string]interface{}
+		if err := m.db.Table("media_assets").Where("entity_type = ? AND entity_id = ?", entity.EntityType, entity.ID).
+			Find(&entityAssets).Error; err != nil {
+			return nil, fmt.Errorf("failed to read %s assets: %w", entity.EntityType, err)
+		}
+		assets = append(assets, entityAssets...)
+	}
+
+	var enrichments []database.MediaEnrichment
+	if err := m.db.Where("media_id = ? AND media_type = ?", mediaFile.MediaID, mediaFile.MediaType).
+		Find(&enrichments).Error; err != nil {
+		return nil, fmt.Errorf("failed to read enrichments: %w", err)
+	}
+	var externalIDs []database.MediaExternalIDs
+	if err := m.db.Where("media_id = ? AND media_type = ?", mediaFile.MediaID, mediaFile.MediaType).
+		Find(&externalIDs).Error; err != nil {
+		return nil, fmt.Errorf("failed to read external IDs: %w", err)
+	}
+	var provenance []database.MediaFieldProvenance
+	if err := m.db.Where("media_id = ?", mediaFile.MediaID).Find(&provenance).Error; err != nil {
+		return nil, fmt.Errorf("failed to read provenance: %w", err)
+	}
+
+	sources := make([]string, 0, len(enrichments))
+	for _, enrichment := range enrichments {
+		sources = append(sources, enrichment.Plugin)
+	}
+	sort.Strings(sources)
+
+	snapshot := database.MediaEnrichmentSnapshot{
+		MediaID:   mediaFile.MediaID,
+		MediaType: mediaFile.MediaType,
+		Reason:    reason,
+		Sources:   strings.Join(sources, ","),
+	}
+	for _, part := range []struct {
+		field *string
+		value interface{}
+	}{
+		{&snapshot.Entities, rows},
+		{&snapshot.Enrichments, enrichments},
+		{&snapshot.ExternalIDs, externalIDs},
+		{&snapshot.Provenance, provenance},
+	} {
+		data, err := json.Marshal(part.value)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal snapshot: %w", err)
+		}
+		*part.field = string(data)
+	}
+
+	err = m.db.Transaction(func(tx *gorm.DB) error {
+		var latest int
+		if err := tx.Model(&database.MediaEnrichmentSnapshot{}).Where("media_id = ?", mediaFile.MediaID).
+			Select("COALESCE(MAX(version), 0)").Scan(&latest).Error; err != nil {
+			return err
+		}
+		snapshot.Version = latest + 1
+		if err := tx.Create(&snapshot).Error; err != nil {
+			return err
+		}
+
+		for _, asset := range assets {
+			data, err := json.Marshal(asset)
+			if err != nil {
+				return err
+			}
+			if err := tx.Create(&database.MediaEnrichmentSnapshotAsset{
+				SnapshotID: snapshot.ID,
+				AssetID:    fmt.Sprintf("%v", asset["id"]),
+				Path:       fmt.Sprintf("%v", asset["path"]),
+				Row:        string(data),
+			}).Error; err != nil {
+				return err
+			}
+		}
+
+		return m.pruneSnapshots(tx, mediaFile.MediaID)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to save snapshot: %w", err)
+	}
+	return &snapshot, nil
+}
+
+// pruneSnapshots drops the oldest versions of a media item beyond
+// maxEnrichmentSnapshots. Their asset files are left to orphan cleanup.
+func (m *Module) pruneSnapshots(tx *gorm.DB, mediaID string) error {
+	var stale []uint32
+	if err := tx.Model(&database.MediaEnrichmentSnapshot{}).Where("media_id = ?", mediaID).
+		Order("version DESC").Offset(maxEnrichmentSnapshots).Pluck("id", &stale).Error; err != nil {
+		return err
+	}
+	if len(stale) == 0 {
+		return nil
+	}
+	if err := tx.Where("snapshot_id IN ?", stale).Delete(&database.MediaEnrichmentSnapshotAsset{}).Error; err != nil {
+		return err
+	}
+	return tx.Where("id IN ?", stale).Delete(&database.MediaEnrichmentSnapshot{}).Error
+}
+
+// GetEnrichmentHistory lists the versions of a media item, newest first.
+// id is a media file ID or the ID of the movie, episode or track it holds.
+func (m *Module) GetEnrichmentHistory(id string) ([]EnrichmentVersion, error) {
+	mediaFile, err := m.findProvenanceMedia(id)
+	if err != nil {
+		return nil, err
+	}
+
+	var snapshots []database.MediaEnrichmentSnapshot
+	if err := m.db.Where("media_id = ?", mediaFile.MediaID).Order("version DESC").Find(&snapshots).Error; err != nil {
+		return nil, fmt.Errorf("failed to fetch history: %w", err)
+	}
+
+	versions := make([]EnrichmentVersion, 0, len(snapshots))
+	for i, snapshot := range snapshots {
+		version := EnrichmentVersion{
+			Version:   snapshot.Version,
+			Reason:    snapshot.Reason,
+			Sources:   []string{},
+			Current:   i == 0,
+			CreatedAt: snapshot.CreatedAt,
+		}
+		if snapshot.Sources != "" {
+			version.Sources = strings.Split(snapshot.Sources, ",")
+		}
+
+		var rows map[string]map[string]interface{}
+		if err := json.Unmarshal([]byte(snapshot.Entities), &rows); err == nil {
+			for _, table := range []string{"movies", "episodes", "tracks"} {
+				if title, ok := rows[table]["title"].(string); ok {
+					version.Title = title
+				}
+			}
+		}
+
+		var assets int64
+		if err := m.db.Model(&database.MediaEnrichmentSnapshotAsset{}).Where("snapshot_id = ?", snapshot.ID).
+			Count(&assets).Error; err != nil {
+			return nil, fmt.Errorf("failed to count snapshot assets: %w", err)
+		}
+		version.Assets = int(assets)
+
+		versions = append(versions, version)
+	}
+	return versions, nil
+}
+
+// RollbackEnrichment restores a media item to an earlier version: its fields,
+// enrichment data, external IDs, provenance and artwork. Locks set since are
+// kept. version 0 rolls back to the version before the latest. The restored
+// state is recorded as a new version, so a rollback can itself be undone.
+func (m *Module) RollbackEnrichment(id string, version int) (*database.MediaEnrichmentSnapshot, error) {
+	mediaFile, err := m.findProvenanceMedia(id)
+	if err != nil {
+		return nil, err
+	}
+
+	query := m.db.Where("media_id = ?", mediaFile.MediaID)
+	if version > 0 {
+		query = query.Where("version = ?", version)
+	} else {
+		query = query.Order("version DESC").Offset(1)
+	}
+	var target database.MediaEnrichmentSnapshot
+	if err := query.First(&target).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrNoEarlierVersion
+		}
+		return nil, fmt.Errorf("failed to fetch version: %w", err)
+	}
+
+	var rows map[string]map[string]interface{}
+	var enrichments []database.MediaEnrichment
+	var externalIDs []database.MediaExternalIDs
+	var provenance []database.MediaFieldProvenance
+	for _, part := range []struct {
+		data  string
+		value interface{}
+	}{
+		{target.Entities, &rows},
+		{target.Enrichments, &enrichments},
+		{target.ExternalIDs, &externalIDs},
+		{target.Provenance, &provenance},
+	} {
+		if err := json.Unmarshal([]byte(part.data), part.value); err != nil {
+			return nil, fmt.Errorf("failed to read version %d: %w", target.Version, err)
+		}
+	}
+	var snapshotAssets []database.MediaEnrichmentSnapshotAsset
+	if err := m.db.Where("snapshot_id = ?", target.ID).Find(&snapshotAssets).Error; err != nil {
+		return nil, fmt.Errorf("failed to read version %d assets: %w", target.Version, err)
+	}
+
+	entities, err := m.historyEntities(mediaFile)
+	if err != nil {
+		return nil, err
+	}
+	locked, err := m.lockedFields(mediaFile.MediaID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch locked fields: %w", err)
+	}
+
+	err = m.db.Transaction(func(tx *gorm.DB) error {
+		for _, entity := range entities {
+			row, ok := rows[entity.Table]
+			if !ok {
+				continue
+			}
+			delete(row, "id")
+			if err := tx.Table(entity.Table).Where("id = ?", entity.ID).Updates(row).Error; err != nil {
+				return fmt.Errorf("failed to restore %s: %w", entity.EntityType, err)
+			}
+			if err := tx.Table("media_assets").Where("entity_type = ? AND entity_id = ?", entity.EntityType, entity.ID).
+				Delete(nil).Error; err != nil {
+				return fmt.Errorf("failed to clear %s assets: %w", entity.EntityType, err)
+			}
+		}
+		for _, snapshotAsset := range snapshotAssets {
+			var asset map[string]interface{}
+			if err := json.Unmarshal([]byte(snapshotAsset.Row), &asset); err != nil {
+				return fmt.Errorf("failed to read asset %s: %w", snapshotAsset.AssetID, err)
+			}
+			if err := tx.Table("media_assets").Create(asset).Error; err != nil {
+				return fmt.Errorf("failed to restore asset %s: %w", snapshotAsset.AssetID, err)
+			}
+		}
+
+		if err := tx.Where("media_id = ? AND media_type = ?", mediaFile.MediaID, mediaFile.MediaType).
+			Delete(&database.MediaEnrichment{}).Error; err != nil {
+			return err
+		}
+		for _, enrichment := range enrichments {
+			if err := tx.Create(&enrichment).Error; err != nil {
+				return fmt.Errorf("failed to restore enrichment from %s: %w", enrichment.Plugin, err)
+			}
+		}
+
+		if err := tx.Where("media_id = ? AND media_type = ?", mediaFile.MediaID, mediaFile.MediaType).
+			Delete(&database.MediaExternalIDs{}).Error; err != nil {
+			return err
+		}
+		for _, externalID := range externalIDs {
+			if err := tx.Create(&externalID).Error; err != nil {
+				return fmt.Errorf("failed to restore %s ID: %w", externalID.Source, err)
+			}
+		}
+
+		if err := tx.Where("media_id = ?", mediaFile.MediaID).Delete(&database.MediaFieldProvenance{}).Error; err != nil {
+			return err
+		}
+		for _, record := range provenance {
+			record.Locked = locked[record.Field]
+			delete(locked, record.Field)
+			if err := tx.Create(&record).Error; err != nil {
+				return fmt.Errorf("failed to restore provenance of %s: %w", record.Field, err)
+			}
+		}
+		for field := range locked {
+			if err := tx.Create(&database.MediaFieldProvenance{
+				MediaID:   mediaFile.MediaID,
+				Field:     field,
+				MediaType: mediaFile.MediaType,
+				Locked:    true,
+			}).Error; err != nil {
+				return fmt.Errorf("failed to keep lock of %s: %w", field, err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot, err := m.takeSnapshot(mediaFile, SnapshotReasonRollback)
+	if err != nil {
+		return nil, err
+	}
+
+	if m.eventBus != nil {
+		event := events.NewSystemEvent(
+			"enrichment.rolled_back",
+			"Enrichment Rolled Back",
+			fmt.Sprintf("Rolled back %s %s to version %d", mediaFile.MediaType, mediaFile.MediaID, target.Version),
+		)
+		event.Data = map[string]interface{}{
+			"media_file_id": mediaFile.ID,
+			"media_id":      mediaFile.MediaID,
+			"version":       target.Version,
+		}
+		m.eventBus.PublishAsync(event)
+	}
+
+	log.Printf("INFO: Rolled back enrichment of %s %s to version %d", mediaFile.MediaType, mediaFile.MediaID, target.Version)
+	return snapshot, nil
+}
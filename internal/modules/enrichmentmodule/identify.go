@@ -0,0 +1,193 @@
+package enrichmentmodule
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+
+	"github.com/mantonx/viewra/internal/database"
+	"github.com/mantonx/viewra/internal/modules/assetmodule"
+	"github.com/mantonx/viewra/internal/modules/pluginmodule"
+	plugins "github.com/mantonx/viewra/sdk"
+	"github.com/mantonx/viewra/sdk/namingparser"
+)
+
+// ErrPluginsUnavailable is returned when identifying media without the
+// external plugin manager
+var ErrPluginsUnavailable = errors.New("external plugins are not available")
+
+// IdentifyCandidate is a provider search result a user can identify media as
+type IdentifyCandidate struct {
+	ID       string            `json:"id"`
+	Title    string            `json:"title"`
+	Score    float64           `json:"score,omitempty"`
+	Metadata map[string]string `json:"metadata,omitempty"` // e.g. media_type, year, overview, poster_url
+}
+
+// IdentifySearchResult is one page of candidates for a media item
+type IdentifySearchResult struct {
+	MediaFileID string              `json:"media_file_id"`
+	Query       map[string]string   `json:"query"`
+	Results     []IdentifyCandidate `json:"results"`
+	TotalCount  uint32              `json:"total_count"`
+	HasMore     bool                `json:"has_more"`
+}
+
+// IdentifyRequest is the search result a user picked for a media item
+type IdentifyRequest struct {
+	PluginID   string `json:"plugin_id" binding:"required"`
+	ExternalID string `json:"external_id" binding:"required"`
+	MediaType  string `json:"media_type"` // The result's media_type metadata, e.g. movie or tv
+}
+
+// externalPlugins returns the external plugin manager identify calls go through
+func (m *Module) externalPlugins() (*pluginmodule.ExternalPluginManager, error) {
+	extMgr, ok := m.externalPluginManager.(*pluginmodule.ExternalPluginManager)
+	if !ok || extMgr == nil {
+		return nil, ErrPluginsUnavailable
+	}
+	return extMgr, nil
+}
+
+// SearchIdentifyCandidates searches a plugin's provider for what a media
+// file or item may be. Without query fields, the title and year are read
+// from the file's name, and a match the plugin queued for review is listed
+// first.
+func (m *Module) SearchIdentifyCandidates(ctx context.Context, id, pluginID string, query map[string]string, limit, offset uint32) (*IdentifySearchResult, error) {
+	mediaFile, err := m.findProvenanceMedia(id)
+	if err != nil {
+		return nil, err
+	}
+	extMgr, err := m.externalPlugins()
+	if err != nil {
+		return nil, err
+	}
+
+	suggest := len(query) == 0 && offset == 0
+	if len(query) == 0 {
+		parsed := namingparser.Parse(pluginmodule.LibraryMatchPath(m.db, mediaFile.LibraryID, mediaFile.Path))
+		query = map[string]string{"title": parsed.Title}
+		if parsed.Year > 0 {
+			query["year"] = strconv.Itoa(parsed.Year)
+		}
+	}
+
+	resp, err := extMgr.SearchPlugin(ctx, pluginID, query, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &IdentifySearchResult{
+		MediaFileID: mediaFile.ID,
+		Query:       query,
+		Results:     make([]IdentifyCandidate, 0, len(resp.Results)),
+		TotalCount:  resp.TotalCount,
+		HasMore:     resp.HasMore,
+	}
+	for _, candidate := range resp.Results {
+		result.Results = append(result.Results, IdentifyCandidate{
+			ID:       candidate.Id,
+			Title:    candidate.Title,
+			Score:    candidate.Score,
+			Metadata: candidate.Metadata,
+		})
+	}
+	if suggest {
+		if review, ok := extMgr.PendingReview(pluginID, mediaFile.ID); ok {
+			result.Results = suggestReviewed(result.Results, review)
+		}
+	}
+	return result, nil
+}
+
+// suggestReviewed moves the candidate a plugin queued for review to the top
+// of the search results, adding it when the search didn't return it
+func suggestReviewed(results []IdentifyCandidate, review *plugins.ReviewCandidate) []IdentifyCandidate {
+	suggested := IdentifyCandidate{
+		ID:       review.ID,
+		Title:    review.Title,
+		Score:    review.Score,
+		Metadata: map[string]string{"media_type": review.MediaType},
+	}
+	if review.Year > 0 {
+		suggested.Metadata["year"] = strconv.Itoa(review.Year)
+	}
+
+	reordered := []IdentifyCandidate{suggested}
+	for _, candidate := range results {
+		if candidate.ID == review.ID {
+			// The search result carries the fuller metadata
+			reordered[0].Metadata = candidate.Metadata
+			continue
+		}
+		reordered = append(reordered, candidate)
+	}
+	return reordered
+}
+
+// IdentifyMedia re-enriches a media file or item with the search result a
+// user picked, overwriting what the plugin matched before. The plugin's
+// artwork for the item is removed first so it is downloaded again for the
+// new match, and a job applies the new enrichment to the item.
+func (m *Module) IdentifyMedia(ctx context.Context, id string, req IdentifyRequest) (*EnrichmentJob, error) {
+	mediaFile, err := m.findProvenanceMedia(id)
+	if err != nil {
+		return nil, err
+	}
+	extMgr, err := m.externalPlugins()
+	if err != nil {
+		return nil, err
+	}
+	// Nothing is removed for a plugin that can't match the item again
+	if !extMgr.IsPluginRunning(req.PluginID) {
+		return nil, pluginmodule.ErrPluginNotRunning
+	}
+
+	// Keep the current state restorable with a rollback
+	m.recordHistory(mediaFile, SnapshotReasonInitial)
+	m.removePluginAssets(mediaFile, req.PluginID)
+
+	if err := extMgr.IdentifyMediaFile(ctx, req.PluginID, mediaFile.ID, mediaFile.Path, req.ExternalID, req.MediaType); err != nil {
+		return nil, err
+	}
+
+	job := EnrichmentJob{
+		MediaFileID: mediaFile.ID,
+		JobType:     "apply_enrichment",
+		Status:      "pending",
+	}
+	if err := m.db.Create(&job).Error; err != nil {
+		return nil, fmt.Errorf("failed to create enrichment job: %w", err)
+	}
+	return &job, nil
+}
+
+// removePluginAssets removes the assets a plugin saved for a media item,
+// logging rather than failing the identify that triggered it
+func (m *Module) removePluginAssets(mediaFile *database.MediaFile, pluginID string) {
+	assetManager := assetmodule.GetAssetManager()
+	if assetManager == nil {
+		return
+	}
+	entities, err := m.historyEntities(mediaFile)
+	if err != nil {
+		log.Printf("WARN: Failed to find entities of %s for asset removal: %v", mediaFile.MediaID, err)
+		return
+	}
+
+	for _, entity := range entities {
+		var assets []assetmodule.MediaAsset
+		if err := m.db.Where("entity_type = ? AND entity_id = ? AND plugin_id = ?", entity.EntityType, entity.ID, pluginID).
+			Find(&assets).Error; err != nil {
+			log.Printf("WARN: Failed to find %s assets of %s: %v", pluginID, entity.ID, err)
+			continue
+		}
+		for _, asset := range assets {
+			if err := assetManager.RemoveAsset(asset.ID); err != nil {
+				log.Printf("WARN: Failed to remove asset %s: %v", asset.ID, err)
+			}
+		}
+	}
+}
@@ -0,0 +1,236 @@
+package enrichmentmodule
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/mantonx/viewra/internal/database"
+	"github.com/mantonx/viewra/sdk/proto"
+	"google.golang.org/grpc/codes"
+	grpcstatus "google.golang.org/grpc/status"
+	"gorm.io/gorm"
+)
+
+// MediaDataGRPCServer serves read-only library data to external plugins, so
+// they look up files and items through a stable API instead of core tables
+type MediaDataGRPCServer struct {
+	proto.UnimplementedMediaDataServiceServer
+	logger hclog.Logger
+	db     *gorm.DB
+}
+
+// NewMediaDataGRPCServer creates a new media data gRPC server instance
+func NewMediaDataGRPCServer(logger hclog.Logger, db *gorm.DB) *MediaDataGRPCServer {
+	return &MediaDataGRPCServer{
+		logger: logger.Named("media-data-grpc-server"),
+		db:     db,
+	}
+}
+
+// GetMediaFile returns a media file with its library and the titles of the
+// item it belongs to
+func (s *MediaDataGRPCServer) GetMediaFile(ctx context.Context, req *proto.GetMediaFileRequest) (*proto.GetMediaFileResponse, error) {
+	if req.MediaFileId == "" {
+		return nil, grpcstatus.Error(codes.InvalidArgument, "media_file_id is required")
+	}
+
+	var files []database.MediaFile
+	if err := s.db.WithContext(ctx).Where("id = ?", req.MediaFileId).Limit(1).Find(&files).Error; err != nil {
+		return nil, grpcstatus.Errorf(codes.Internal, "failed to load media file: %v", err)
+	}
+	if len(files) == 0 {
+		return &proto.GetMediaFileResponse{Found: false}, nil
+	}
+	file := files[0]
+
+	info := &proto.MediaFileInfo{
+		Id:         file.ID,
+		MediaId:    file.MediaID,
+		MediaType:  string(file.MediaType),
+		LibraryId:  file.LibraryID,
+		Path:       file.Path,
+		Container:  file.Container,
+		VideoCodec: file.VideoCodec,
+		AudioCodec: file.AudioCodec,
+		Resolution: file.Resolution,
+		Duration:   int32(file.Duration),
+		SizeBytes:  file.SizeBytes,
+	}
+
+	var libraries []database.MediaLibrary
+	if err := s.db.WithContext(ctx).Where("id = ?", file.LibraryID).Limit(1).Find(&libraries).Error; err == nil && len(libraries) > 0 {
+		info.LibraryType = libraries[0].Type
+		info.LibraryPath = libraries[0].Path
+	}
+
+	s.describeFileItem(ctx, &file, info)
+
+	return &proto.GetMediaFileResponse{Found: true, MediaFile: info}, nil
+}
+
+// describeFileItem fills in the title and, for episodes, the show of the
+// item a media file belongs to
+func (s *MediaDataGRPCServer) describeFileItem(ctx context.Context, file *database.MediaFile, info *proto.MediaFileInfo) {
+	db := s.db.WithContext(ctx)
+
+	switch file.MediaType {
+	case database.MediaTypeMovie:
+		var movies []database.Movie
+		if db.Where("id = ?", file.MediaID).Limit(1).Find(&movies).Error == nil && len(movies) > 0 {
+			info.Title = movies[0].Title
+			info.Year = yearOf(movies[0].ReleaseDate)
+		}
+	case database.MediaTypeEpisode:
+		var episodes []database.Episode
+		if db.Preload("Season.TVShow").Where("id = ?", file.MediaID).Limit(1).Find(&episodes).Error == nil && len(episodes) > 0 {
+			episode := episodes[0]
+			info.Title = episode.Title
+			info.Year = yearOf(episode.AirDate)
+			info.EpisodeNumber = int32(episode.EpisodeNumber)
+			info.SeasonNumber = int32(episode.Season.SeasonNumber)
+			info.ShowId = episode.Season.TVShowID
+			info.ShowTitle = episode.Season.TVShow.Title
+		}
+	case database.MediaTypeTrack:
+		var tracks []database.Track
+		if db.Where("id = ?", file.MediaID).Limit(1).Find(&tracks).Error == nil && len(tracks) > 0 {
+			info.Title = tracks[0].Title
+		}
+	}
+}
+
+// mediaItemRef identifies a library item by type and ID
+type mediaItemRef struct {
+	mediaType string
+	id        string
+}
+
+// FindMediaByExternalID returns the items carrying an external ID, from the
+// external IDs enrichment recorded and the TMDb and IMDb IDs kept on movies
+// and TV shows
+func (s *MediaDataGRPCServer) FindMediaByExternalID(ctx context.Context, req *proto.FindMediaByExternalIDRequest) (*proto.FindMediaByExternalIDResponse, error) {
+	source := strings.ToLower(strings.TrimSpace(req.Source))
+	externalID := strings.TrimSpace(req.ExternalId)
+	if source == "" || externalID == "" {
+		return nil, grpcstatus.Error(codes.InvalidArgument, "source and external_id are required")
+	}
+	db := s.db.WithContext(ctx)
+
+	var refs []mediaItemRef
+	seen := make(map[mediaItemRef]bool)
+	add := func(mediaType, id string) {
+		ref := mediaItemRef{mediaType: mediaType, id: id}
+		if !seen[ref] {
+			seen[ref] = true
+			refs = append(refs, ref)
+		}
+	}
+
+	var externalIDs []database.MediaExternalIDs
+	query := db.Where("source = ? AND external_id = ?", source, externalID)
+	if req.MediaType != "" {
+		query = query.Where("media_type = ?", req.MediaType)
+	}
+	if err := query.Find(&externalIDs).Error; err != nil {
+		return nil, grpcstatus.Errorf(codes.Internal, "failed to find external IDs: %v", err)
+	}
+	for _, ext := range externalIDs {
+		add(string(ext.MediaType), ext.MediaID)
+	}
+
+	wants := func(mediaType string) bool { return req.MediaType == "" || req.MediaType == mediaType }
+	if column := map[string]string{"tmdb": "tmdb_id", "imdb": "imdb_id"}[source]; column != "" && wants("movie") {
+		var ids []string
+		if err := db.Model(&database.Movie{}).Where(column+" = ?", externalID).Pluck("id", &ids).Error; err != nil {
+			return nil, grpcstatus.Errorf(codes.Internal, "failed to find movies: %v", err)
+		}
+		for _, id := range ids {
+			add("movie", id)
+		}
+	}
+	if source == "tmdb" && wants("tv_show") {
+		var ids []string
+		if err := db.Model(&database.TVShow{}).Where("tmdb_id = ?", externalID).Pluck("id", &ids).Error; err != nil {
+			return nil, grpcstatus.Errorf(codes.Internal, "failed to find TV shows: %v", err)
+		}
+		for _, id := range ids {
+			add("tv_show", id)
+		}
+	}
+
+	resp := &proto.FindMediaByExternalIDResponse{}
+	for _, ref := range refs {
+		if item := s.mediaItem(ctx, ref); item != nil {
+			resp.Items = append(resp.Items, item)
+		}
+	}
+	return resp, nil
+}
+
+// mediaItem describes a library item with every external ID known for it,
+// or returns nil when the item no longer exists
+func (s *MediaDataGRPCServer) mediaItem(ctx context.Context, ref mediaItemRef) *proto.MediaItemInfo {
+	db := s.db.WithContext(ctx)
+	item := &proto.MediaItemInfo{Id: ref.id, MediaType: ref.mediaType, ExternalIds: make(map[string]string)}
+
+	switch ref.mediaType {
+	case "movie":
+		var movies []database.Movie
+		if db.Where("id = ?", ref.id).Limit(1).Find(&movies).Error != nil || len(movies) == 0 {
+			return nil
+		}
+		item.Title = movies[0].Title
+		item.Year = yearOf(movies[0].ReleaseDate)
+		setExternalID(item, "tmdb", movies[0].TmdbID)
+		setExternalID(item, "imdb", movies[0].ImdbID)
+	case "tv_show":
+		var shows []database.TVShow
+		if db.Where("id = ?", ref.id).Limit(1).Find(&shows).Error != nil || len(shows) == 0 {
+			return nil
+		}
+		item.Title = shows[0].Title
+		item.Year = yearOf(shows[0].FirstAirDate)
+		setExternalID(item, "tmdb", shows[0].TmdbID)
+	case "episode":
+		var episodes []database.Episode
+		if db.Where("id = ?", ref.id).Limit(1).Find(&episodes).Error != nil || len(episodes) == 0 {
+			return nil
+		}
+		item.Title = episodes[0].Title
+		item.Year = yearOf(episodes[0].AirDate)
+	case "track":
+		var tracks []database.Track
+		if db.Where("id = ?", ref.id).Limit(1).Find(&tracks).Error != nil || len(tracks) == 0 {
+			return nil
+		}
+		item.Title = tracks[0].Title
+	default:
+		s.logger.Debug("external ID refers to an unsupported media type", "media_type", ref.mediaType, "media_id", ref.id)
+		return nil
+	}
+
+	var externalIDs []database.MediaExternalIDs
+	if err := db.Where("media_id = ? AND media_type = ?", ref.id, ref.mediaType).Find(&externalIDs).Error; err == nil {
+		for _, ext := range externalIDs {
+			setExternalID(item, ext.Source, ext.ExternalID)
+		}
+	}
+	return item
+}
+
+// setExternalID records an external ID on an item unless it is empty
+func setExternalID(item *proto.MediaItemInfo, source, value string) {
+	if value != "" {
+		item.ExternalIds[source] = value
+	}
+}
+
+// yearOf returns the year of a date, or 0 when it is unknown
+func yearOf(date *time.Time) int32 {
+	if date == nil || date.IsZero() {
+		return 0
+	}
+	return int32(date.Year())
+}
@@ -0,0 +1,516 @@
+package enrichmentmodule
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/mantonx/viewra/internal/database"
+	"github.com/mantonx/viewra/internal/utils"
+	"github.com/mantonx/viewra/sdk/proto"
+	"google.golang.org/grpc/codes"
+	grpcstatus "google.golang.org/grpc/status"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// mediaTypeTVShow is the media type external IDs of TV shows are recorded
+// under; shows have no media files, so it isn't a database.MediaType constant
+const mediaTypeTVShow database.MediaType = "tv_show"
+
+// MediaEntityGRPCServer creates and finds movies, shows, seasons, episodes
+// and people for external plugins. Upserts are serialized and run in a
+// transaction, so plugins enriching files concurrently resolve to the same
+// items instead of each creating their own.
+type MediaEntityGRPCServer struct {
+	proto.UnimplementedMediaEntityServiceServer
+	logger    hclog.Logger
+	db        *gorm.DB
+	mu        sync.Mutex
+	downloads *assetDownloadPool // Downloads people's profile images
+
+	profilesMu sync.Mutex
+	profiles   map[string]bool // People whose profile image is downloading
+}
+
+// NewMediaEntityGRPCServer creates a new media entity gRPC server instance.
+// Profile images are downloaded through downloads, shared with the asset
+// service so both keep to one download policy.
+func NewMediaEntityGRPCServer(logger hclog.Logger, db *gorm.DB, downloads *assetDownloadPool) *MediaEntityGRPCServer {
+	return &MediaEntityGRPCServer{
+		logger:    logger.Named("media-entity-grpc-server"),
+		db:        db,
+		downloads: downloads,
+		profiles:  make(map[string]bool),
+	}
+}
+
+// UpsertMovie finds the movie carrying one of the request's external IDs, or
+// else one with the same title and year, creating it when neither exists
+func (s *MediaEntityGRPCServer) UpsertMovie(ctx context.Context, req *proto.UpsertMovieRequest) (*proto.UpsertEntityResponse, error) {
+	title := strings.TrimSpace(req.Title)
+	if title == "" {
+		return nil, grpcstatus.Error(codes.InvalidArgument, "title is required")
+	}
+	externalIDs := normalizeExternalIDs(req.ExternalIds)
+
+	resp := &proto.UpsertEntityResponse{}
+	err := s.upsert(ctx, func(tx *gorm.DB) error {
+		movie, err := s.findMovie(tx, title, int(req.Year), externalIDs)
+		if err != nil {
+			return err
+		}
+
+		if movie == nil {
+			candidate := &database.Movie{
+				ID:          utils.GenerateUUID(),
+				Title:       title,
+				ReleaseDate: yearDate(int(req.Year)),
+				TmdbID:      externalIDs["tmdb"],
+				ImdbID:      externalIDs["imdb"],
+			}
+			result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(candidate)
+			if result.Error != nil {
+				return fmt.Errorf("failed to create movie: %w", result.Error)
+			}
+			if result.RowsAffected > 0 {
+				movie = candidate
+				resp.Created = true
+			} else if movie, err = s.findMovie(tx, title, int(req.Year), externalIDs); err != nil {
+				return err
+			} else if movie == nil {
+				return grpcstatus.Error(codes.Aborted, "movie was created concurrently but could not be loaded")
+			}
+		}
+		if !resp.Created {
+			updates := map[string]interface{}{}
+			if movie.ReleaseDate == nil && req.Year > 0 {
+				updates["release_date"] = yearDate(int(req.Year))
+			}
+			if movie.TmdbID == "" && externalIDs["tmdb"] != "" {
+				updates["tmdb_id"] = externalIDs["tmdb"]
+			}
+			if movie.ImdbID == "" && externalIDs["imdb"] != "" {
+				updates["imdb_id"] = externalIDs["imdb"]
+			}
+			if len(updates) > 0 {
+				if err := tx.Model(movie).Updates(updates).Error; err != nil {
+					return fmt.Errorf("failed to update movie: %w", err)
+				}
+			}
+		}
+		resp.Id = movie.ID
+
+		if err := saveExternalIDs(tx, movie.ID, database.MediaTypeMovie, externalIDs); err != nil {
+			return err
+		}
+		return linkMediaFile(tx, req.MediaFileId, movie.ID, database.MediaTypeMovie)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Debug("upserted movie", "movie_id", resp.Id, "title", title, "created", resp.Created)
+	return resp, nil
+}
+
+// findMovie returns the movie matching the external IDs, then the title and
+// year, or nil when there is none
+func (s *MediaEntityGRPCServer) findMovie(tx *gorm.DB, title string, year int, externalIDs map[string]string) (*database.Movie, error) {
+	var movies []database.Movie
+
+	id, err := findByExternalIDs(tx, database.MediaTypeMovie, externalIDs)
+	if err != nil {
+		return nil, err
+	}
+	query := tx.Where("id = ?", id)
+	if id == "" {
+		// Movies created before external IDs were recorded only carry them in columns
+		query = nil
+		for _, source := range []string{"tmdb", "imdb"} {
+			if value := externalIDs[source]; value != "" && query == nil {
+				query = tx.Where(source+"_id = ?", value)
+			} else if value != "" {
+				query = query.Or(source+"_id = ?", value)
+			}
+		}
+	}
+	if query != nil {
+		if err := query.Limit(1).Find(&movies).Error; err != nil {
+			return nil, fmt.Errorf("failed to find movie: %w", err)
+		}
+		if len(movies) > 0 {
+			return &movies[0], nil
+		}
+	}
+
+	if err := tx.Where("LOWER(title) = LOWER(?)", title).Order("created_at").Find(&movies).Error; err != nil {
+		return nil, fmt.Errorf("failed to find movie: %w", err)
+	}
+	for i := range movies {
+		movie := &movies[i]
+		// A same-titled movie from another year or with another TMDb ID is a remake
+		if year > 0 && movie.ReleaseDate != nil && movie.ReleaseDate.Year() != year {
+			continue
+		}
+		if movie.TmdbID != "" && externalIDs["tmdb"] != "" && movie.TmdbID != externalIDs["tmdb"] {
+			continue
+		}
+		return movie, nil
+	}
+	return nil, nil
+}
+
+// UpsertShow finds the TV show carrying one of the request's external IDs, or
+// else one with the same title and first air year, creating it when neither
+// exists
+func (s *MediaEntityGRPCServer) UpsertShow(ctx context.Context, req *proto.UpsertShowRequest) (*proto.UpsertEntityResponse, error) {
+	title := strings.TrimSpace(req.Title)
+	if title == "" {
+		return nil, grpcstatus.Error(codes.InvalidArgument, "title is required")
+	}
+	externalIDs := normalizeExternalIDs(req.ExternalIds)
+
+	resp := &proto.UpsertEntityResponse{}
+	err := s.upsert(ctx, func(tx *gorm.DB) error {
+		show, err := s.findShow(tx, title, int(req.Year), externalIDs)
+		if err != nil {
+			return err
+		}
+
+		if show == nil {
+			candidate := &database.TVShow{
+				ID:           utils.GenerateUUID(),
+				Title:        title,
+				FirstAirDate: yearDate(int(req.Year)),
+				TmdbID:       externalIDs["tmdb"],
+			}
+			result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(candidate)
+			if result.Error != nil {
+				return fmt.Errorf("failed to create TV show: %w", result.Error)
+			}
+			if result.RowsAffected > 0 {
+				show = candidate
+				resp.Created = true
+			} else if show, err = s.findShow(tx, title, int(req.Year), externalIDs); err != nil {
+				return err
+			} else if show == nil {
+				return grpcstatus.Error(codes.Aborted, "TV show was created concurrently but could not be loaded")
+			}
+		}
+		if !resp.Created {
+			updates := map[string]interface{}{}
+			if show.FirstAirDate == nil && req.Year > 0 {
+				updates["first_air_date"] = yearDate(int(req.Year))
+			}
+			if show.TmdbID == "" && externalIDs["tmdb"] != "" {
+				updates["tmdb_id"] = externalIDs["tmdb"]
+			}
+			if len(updates) > 0 {
+				if err := tx.Model(show).Updates(updates).Error; err != nil {
+					return fmt.Errorf("failed to update TV show: %w", err)
+				}
+			}
+		}
+		resp.Id = show.ID
+
+		return saveExternalIDs(tx, show.ID, mediaTypeTVShow, externalIDs)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Debug("upserted TV show", "show_id", resp.Id, "title", title, "created", resp.Created)
+	return resp, nil
+}
+
+// findShow returns the TV show matching the external IDs, then the title and
+// first air year, or nil when there is none
+func (s *MediaEntityGRPCServer) findShow(tx *gorm.DB, title string, year int, externalIDs map[string]string) (*database.TVShow, error) {
+	var shows []database.TVShow
+
+	id, err := findByExternalIDs(tx, mediaTypeTVShow, externalIDs)
+	if err != nil {
+		return nil, err
+	}
+	query := tx.Where("id = ?", id)
+	if id == "" {
+		query = tx.Where("tmdb_id = ?", externalIDs["tmdb"])
+	}
+	if id != "" || externalIDs["tmdb"] != "" {
+		if err := query.Limit(1).Find(&shows).Error; err != nil {
+			return nil, fmt.Errorf("failed to find TV show: %w", err)
+		}
+		if len(shows) > 0 {
+			return &shows[0], nil
+		}
+	}
+
+	if err := tx.Where("LOWER(title) = LOWER(?)", title).Order("created_at").Find(&shows).Error; err != nil {
+		return nil, fmt.Errorf("failed to find TV show: %w", err)
+	}
+	for i := range shows {
+		show := &shows[i]
+		if year > 0 && show.FirstAirDate != nil && show.FirstAirDate.Year() != year {
+			continue
+		}
+		if show.TmdbID != "" && externalIDs["tmdb"] != "" && show.TmdbID != externalIDs["tmdb"] {
+			continue
+		}
+		return show, nil
+	}
+	return nil, nil
+}
+
+// UpsertSeason finds or creates a season of an existing TV show
+func (s *MediaEntityGRPCServer) UpsertSeason(ctx context.Context, req *proto.UpsertSeasonRequest) (*proto.UpsertEntityResponse, error) {
+	if req.ShowId == "" {
+		return nil, grpcstatus.Error(codes.InvalidArgument, "show_id is required")
+	}
+	if req.SeasonNumber < 0 {
+		return nil, grpcstatus.Error(codes.InvalidArgument, "season_number must not be negative")
+	}
+
+	resp := &proto.UpsertEntityResponse{}
+	err := s.upsert(ctx, func(tx *gorm.DB) error {
+		var shows int64
+		if err := tx.Model(&database.TVShow{}).Where("id = ?", req.ShowId).Count(&shows).Error; err != nil {
+			return fmt.Errorf("failed to find TV show: %w", err)
+		}
+		if shows == 0 {
+			return grpcstatus.Errorf(codes.NotFound, "TV show %s not found", req.ShowId)
+		}
+
+		var seasons []database.Season
+		if err := tx.Where("tv_show_id = ? AND season_number = ?", req.ShowId, req.SeasonNumber).Limit(1).Find(&seasons).Error; err != nil {
+			return fmt.Errorf("failed to find season: %w", err)
+		}
+		if len(seasons) > 0 {
+			resp.Id = seasons[0].ID
+			return nil
+		}
+
+		season := &database.Season{
+			ID:           utils.GenerateUUID(),
+			TVShowID:     req.ShowId,
+			SeasonNumber: int(req.SeasonNumber),
+		}
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(season)
+		if result.Error != nil {
+			return fmt.Errorf("failed to create season: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			// A scanner created the season first
+			if err := tx.Where("tv_show_id = ? AND season_number = ?", req.ShowId, req.SeasonNumber).First(season).Error; err != nil {
+				return fmt.Errorf("failed to load season: %w", err)
+			}
+		}
+		resp.Id = season.ID
+		resp.Created = result.RowsAffected > 0
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// UpsertEpisode finds or creates an episode of an existing season, replacing
+// its title when the request has one
+func (s *MediaEntityGRPCServer) UpsertEpisode(ctx context.Context, req *proto.UpsertEpisodeRequest) (*proto.UpsertEntityResponse, error) {
+	if req.SeasonId == "" {
+		return nil, grpcstatus.Error(codes.InvalidArgument, "season_id is required")
+	}
+	if req.EpisodeNumber < 0 {
+		return nil, grpcstatus.Error(codes.InvalidArgument, "episode_number must not be negative")
+	}
+	title := strings.TrimSpace(req.Title)
+	externalIDs := normalizeExternalIDs(req.ExternalIds)
+
+	resp := &proto.UpsertEntityResponse{}
+	err := s.upsert(ctx, func(tx *gorm.DB) error {
+		var seasons int64
+		if err := tx.Model(&database.Season{}).Where("id = ?", req.SeasonId).Count(&seasons).Error; err != nil {
+			return fmt.Errorf("failed to find season: %w", err)
+		}
+		if seasons == 0 {
+			return grpcstatus.Errorf(codes.NotFound, "season %s not found", req.SeasonId)
+		}
+
+		var episodes []database.Episode
+		if err := tx.Where("season_id = ? AND episode_number = ?", req.SeasonId, req.EpisodeNumber).Limit(1).Find(&episodes).Error; err != nil {
+			return fmt.Errorf("failed to find episode: %w", err)
+		}
+
+		if len(episodes) == 0 {
+			created, err := createEpisode(tx, req.SeasonId, int(req.EpisodeNumber), title)
+			if err != nil {
+				return err
+			}
+			if created != nil {
+				resp.Id = created.ID
+				resp.Created = true
+			} else if err := tx.Where("season_id = ? AND episode_number = ?", req.SeasonId, req.EpisodeNumber).Limit(1).Find(&episodes).Error; err != nil {
+				return fmt.Errorf("failed to find episode: %w", err)
+			}
+		}
+
+		if !resp.Created {
+			if len(episodes) == 0 {
+				return grpcstatus.Error(codes.Aborted, "episode was created concurrently but could not be loaded")
+			}
+			episode := &episodes[0]
+			if title != "" && title != episode.Title {
+				if err := tx.Model(episode).Update("title", title).Error; err != nil {
+					return fmt.Errorf("failed to update episode: %w", err)
+				}
+			}
+			resp.Id = episode.ID
+		}
+
+		if err := saveExternalIDs(tx, resp.Id, database.MediaTypeEpisode, externalIDs); err != nil {
+			return err
+		}
+		return linkMediaFile(tx, req.MediaFileId, resp.Id, database.MediaTypeEpisode)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// createEpisode inserts an episode, or returns nil when a scanner created it
+// first
+func createEpisode(tx *gorm.DB, seasonID string, episodeNumber int, title string) (*database.Episode, error) {
+	if title == "" {
+		title = fmt.Sprintf("Episode %d", episodeNumber)
+	}
+	episode := &database.Episode{
+		ID:            utils.GenerateUUID(),
+		SeasonID:      seasonID,
+		Title:         title,
+		EpisodeNumber: episodeNumber,
+	}
+	result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(episode)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to create episode: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return nil, nil
+	}
+	return episode, nil
+}
+
+// upsert runs fn in a transaction, one at a time, and turns its errors into
+// gRPC status errors
+func (s *MediaEntityGRPCServer) upsert(ctx context.Context, fn func(tx *gorm.DB) error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	err := s.db.WithContext(ctx).Transaction(fn)
+	if err == nil {
+		return nil
+	}
+	if _, ok := grpcstatus.FromError(err); ok {
+		return err
+	}
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return grpcstatus.FromContextError(err).Err()
+	}
+	return grpcstatus.Error(codes.Internal, err.Error())
+}
+
+// normalizeExternalIDs lower-cases sources and drops empty IDs
+func normalizeExternalIDs(externalIDs map[string]string) map[string]string {
+	result := make(map[string]string, len(externalIDs))
+	for source, id := range externalIDs {
+		source = strings.ToLower(strings.TrimSpace(source))
+		id = strings.TrimSpace(id)
+		if source != "" && id != "" {
+			result[source] = id
+		}
+	}
+	return result
+}
+
+// findByExternalIDs returns the ID of the item of mediaType recorded with one
+// of the external IDs, checking sources in name order, or "" when none is
+func findByExternalIDs(tx *gorm.DB, mediaType database.MediaType, externalIDs map[string]string) (string, error) {
+	sources := make([]string, 0, len(externalIDs))
+	for source := range externalIDs {
+		sources = append(sources, source)
+	}
+	slices.Sort(sources)
+
+	for _, source := range sources {
+		var ids []string
+		if err := tx.Model(&database.MediaExternalIDs{}).
+			Where("media_type = ? AND source = ? AND external_id = ?", mediaType, source, externalIDs[source]).
+			Limit(1).Pluck("media_id", &ids).Error; err != nil {
+			return "", fmt.Errorf("failed to find external IDs: %w", err)
+		}
+		if len(ids) > 0 {
+			return ids[0], nil
+		}
+	}
+	return "", nil
+}
+
+// saveExternalIDs records an item's external IDs, replacing any it already
+// had from the same sources
+func saveExternalIDs(tx *gorm.DB, mediaID string, mediaType database.MediaType, externalIDs map[string]string) error {
+	for source, externalID := range externalIDs {
+		result := tx.Model(&database.MediaExternalIDs{}).
+			Where("media_id = ? AND media_type = ? AND source = ?", mediaID, mediaType, source).
+			Updates(map[string]interface{}{"external_id": externalID, "updated_at": time.Now()})
+		if result.Error != nil {
+			return fmt.Errorf("failed to update external IDs: %w", result.Error)
+		}
+		if result.RowsAffected > 0 {
+			continue
+		}
+
+		if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&database.MediaExternalIDs{
+			MediaID:    mediaID,
+			MediaType:  mediaType,
+			Source:     source,
+			ExternalID: externalID,
+		}).Error; err != nil {
+			return fmt.Errorf("failed to save external IDs: %w", err)
+		}
+	}
+	return nil
+}
+
+// linkMediaFile points a media file at the item it contains. An empty media
+// file ID links nothing.
+func linkMediaFile(tx *gorm.DB, mediaFileID, mediaID string, mediaType database.MediaType) error {
+	if mediaFileID == "" {
+		return nil
+	}
+
+	result := tx.Model(&database.MediaFile{}).
+		Where("id = ?", mediaFileID).
+		Updates(map[string]interface{}{"media_id": mediaID, "media_type": mediaType})
+	if result.Error != nil {
+		return fmt.Errorf("failed to link media file: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return grpcstatus.Errorf(codes.NotFound, "media file %s not found", mediaFileID)
+	}
+	return nil
+}
+
+// yearDate returns January 1st of a year, or nil when the year is unknown
+func yearDate(year int) *time.Time {
+	if year <= 0 {
+		return nil
+	}
+	date := time.Date(year, 1, 1, 0, 0, 0, 0, time.UTC)
+	return &date
+}
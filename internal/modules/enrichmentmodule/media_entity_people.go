@@ -0,0 +1,193 @@
+package enrichmentmodule
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/mantonx/viewra/internal/database"
+	"github.com/mantonx/viewra/internal/modules/assetmodule"
+	"github.com/mantonx/viewra/internal/modules/peoplemodule"
+	"github.com/mantonx/viewra/sdk/proto"
+	"google.golang.org/grpc/codes"
+	grpcstatus "google.golang.org/grpc/status"
+	"gorm.io/gorm"
+)
+
+// profileDownloadTimeout bounds a profile image download, including the wait
+// for a free slot in the download pool
+const profileDownloadTimeout = 5 * time.Minute
+
+// UpsertPerson finds the person carrying one of the request's external IDs,
+// or else one of the same name, creating them when neither exists. Their
+// profile image is downloaded in the background.
+func (s *MediaEntityGRPCServer) UpsertPerson(ctx context.Context, req *proto.UpsertPersonRequest) (*proto.UpsertEntityResponse, error) {
+	if req.Person == nil || strings.TrimSpace(req.Person.Name) == "" {
+		return nil, grpcstatus.Error(codes.InvalidArgument, "person name is required")
+	}
+	details := personDetails(req.Person)
+
+	var upserted *peoplemodule.Upserted
+	err := s.upsert(ctx, func(tx *gorm.DB) error {
+		var err error
+		upserted, err = peoplemodule.Upsert(tx, details)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if upserted.NeedsProfile {
+		s.downloadProfile(upserted.Person.ID, details.Image)
+	}
+	return &proto.UpsertEntityResponse{Id: upserted.Person.ID, Created: upserted.Created}, nil
+}
+
+// SetCredits replaces the cast and crew of the movie or episode a media file
+// belongs to, upserting each credited person
+func (s *MediaEntityGRPCServer) SetCredits(ctx context.Context, req *proto.SetCreditsRequest) (*proto.SetCreditsResponse, error) {
+	if req.MediaFileId == "" {
+		return nil, grpcstatus.Error(codes.InvalidArgument, "media_file_id is required")
+	}
+	credits := make([]peoplemodule.Credit, 0, len(req.Credits))
+	for i, credit := range req.Credits {
+		if credit.Person == nil || strings.TrimSpace(credit.Person.Name) == "" {
+			return nil, grpcstatus.Errorf(codes.InvalidArgument, "credit %d has no person name", i)
+		}
+		credits = append(credits, peoplemodule.Credit{
+			Person:     *personDetails(credit.Person),
+			Role:       credit.Role,
+			Character:  credit.Character,
+			Department: credit.Department,
+			Order:      int(credit.Order),
+		})
+	}
+
+	resp := &proto.SetCreditsResponse{}
+	var upserts []*peoplemodule.Upserted
+	err := s.upsert(ctx, func(tx *gorm.DB) error {
+		var file database.MediaFile
+		if err := tx.Select("id, media_id, media_type").Where("id = ?", req.MediaFileId).First(&file).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return grpcstatus.Errorf(codes.NotFound, "media file %s not found", req.MediaFileId)
+			}
+			return fmt.Errorf("failed to load media file: %w", err)
+		}
+		if file.MediaID == "" {
+			return grpcstatus.Errorf(codes.FailedPrecondition, "media file %s is not linked to a movie or episode", req.MediaFileId)
+		}
+		if file.MediaType != database.MediaTypeMovie && file.MediaType != database.MediaTypeEpisode {
+			return grpcstatus.Errorf(codes.FailedPrecondition, "media file %s is a %s, not a movie or episode", req.MediaFileId, file.MediaType)
+		}
+		resp.MediaId = file.MediaID
+
+		var err error
+		upserts, err = peoplemodule.SetCredits(tx, file.MediaID, file.MediaType, credits)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	resp.PersonIds = make([]string, 0, len(upserts))
+	for i, upserted := range upserts {
+		resp.PersonIds = append(resp.PersonIds, upserted.Person.ID)
+		if upserted.NeedsProfile {
+			s.downloadProfile(upserted.Person.ID, credits[i].Person.Image)
+		}
+	}
+	s.logger.Debug("set credits", "media_file_id", req.MediaFileId, "media_id", resp.MediaId, "credits", len(credits))
+	return resp, nil
+}
+
+// downloadProfile downloads a person's profile image through the shared
+// download pool and saves it as their headshot. Only one download per
+// person runs at a time.
+func (s *MediaEntityGRPCServer) downloadProfile(personID, imageURL string) {
+	if s.downloads == nil || imageURL == "" {
+		return
+	}
+	s.profilesMu.Lock()
+	if s.profiles[personID] {
+		s.profilesMu.Unlock()
+		return
+	}
+	s.profiles[personID] = true
+	s.profilesMu.Unlock()
+
+	go func() {
+		defer func() {
+			s.profilesMu.Lock()
+			delete(s.profiles, personID)
+			s.profilesMu.Unlock()
+		}()
+		if err := s.saveProfile(personID, imageURL); err != nil {
+			s.logger.Warn("failed to save profile image", "person_id", personID, "url", imageURL, "error", err)
+		}
+	}()
+}
+
+func (s *MediaEntityGRPCServer) saveProfile(personID, imageURL string) error {
+	entityID, err := uuid.Parse(personID)
+	if err != nil {
+		return fmt.Errorf("invalid person ID: %w", err)
+	}
+	assetManager := assetmodule.GetAssetManager()
+	if assetManager == nil {
+		return fmt.Errorf("asset manager not available")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), profileDownloadTimeout)
+	defer cancel()
+	download, err := s.downloads.Download(ctx, imageURL, nil, 0)
+	if err != nil {
+		return err
+	}
+	if !assetmodule.IsSupportedImageFormat(download.MimeType) {
+		return fmt.Errorf("unsupported image format %q", download.MimeType)
+	}
+
+	asset, err := assetManager.SaveAsset(&assetmodule.AssetRequest{
+		EntityType: assetmodule.EntityTypePerson,
+		EntityID:   entityID,
+		Type:       assetmodule.AssetTypeHeadshot,
+		Source:     assetmodule.SourcePlugin,
+		Data:       download.Data,
+		Format:     download.MimeType,
+		Preferred:  true,
+	})
+	if err != nil {
+		return err
+	}
+	return peoplemodule.SetProfileAsset(s.db, personID, asset.ID.String())
+}
+
+// personDetails converts a person from a plugin, reading its TMDb and IMDb
+// IDs and dates. Dates that don't parse are left unknown.
+func personDetails(info *proto.PersonInfo) *peoplemodule.Details {
+	externalIDs := normalizeExternalIDs(info.ExternalIds)
+	return &peoplemodule.Details{
+		Name:               strings.TrimSpace(info.Name),
+		TmdbID:             externalIDs["tmdb"],
+		ImdbID:             externalIDs["imdb"],
+		Biography:          strings.TrimSpace(info.Biography),
+		Birthdate:          parseDate(info.Birthdate),
+		Deathdate:          parseDate(info.Deathdate),
+		PlaceOfBirth:       strings.TrimSpace(info.PlaceOfBirth),
+		KnownForDepartment: info.KnownForDepartment,
+		Image:              strings.TrimSpace(info.ProfileUrl),
+	}
+}
+
+// parseDate parses a YYYY-MM-DD date, returning nil when it is empty or
+// invalid
+func parseDate(value string) *time.Time {
+	date, err := time.Parse("2006-01-02", strings.TrimSpace(value))
+	if err != nil {
+		return nil
+	}
+	return &date
+}
@@ -0,0 +1,426 @@
+package enrichmentmodule
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mantonx/viewra/internal/database"
+	"gorm.io/gorm"
+)
+
+// ErrUnknownField is returned when configuring a field no rule covers
+var ErrUnknownField = errors.New("unknown enrichment field")
+
+// EnrichmentFieldPriority overrides the order sources are preferred in for a
+// field, for every media type or for one
+type EnrichmentFieldPriority struct {
+	ID        uint32    `gorm:"primaryKey" json:"id"`
+	Field     string    `gorm:"not null;uniqueIndex:idx_enrichment_field_priority" json:"field"`
+	MediaType string    `gorm:"not null;default:'';uniqueIndex:idx_enrichment_field_priority" json:"media_type"` // Empty for every media type
+	Sources   string    `gorm:"type:text;not null" json:"sources"`                                               // JSON array of source names
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// FieldPriority is the order sources are preferred in for a field of a media type
+type FieldPriority struct {
+	Field      string   `json:"field"`
+	MediaType  string   `json:"media_type"`
+	Sources    []string `json:"sources"`
+	Default    []string `json:"default"`
+	Overridden bool     `json:"overridden"`
+}
+
+// MergedField is the value the merge chose for a field, and where it came from
+type MergedField struct {
+	Value      string   `json:"value"`
+	Source     string   `json:"source"`
+	Sources    []string `json:"sources,omitempty"` // Every source a merged list took values from
+	Confidence float64  `json:"confidence"`
+}
+
+// mergeCandidate is one source's enrichment of a media item
+type mergeCandidate struct {
+	source   string
+	priority int
+	data     EnrichmentData
+}
+
+// mergePolicy decides which source wins each field of one media type
+type mergePolicy struct {
+	mediaType      string
+	rules          map[string]FieldRule
+	overrides      map[string][]string // By field, the media type's own over the global one
+	sourcePriority map[string]int
+	disabled       map[string]bool
+}
+
+// loadMergePolicy reads the field overrides and source settings for a media type
+func (m *Module) loadMergePolicy(mediaType string) (*mergePolicy, error) {
+	policy := &mergePolicy{
+		mediaType:      mediaType,
+		rules:          m.GetFieldRules(),
+		overrides:      make(map[string][]string),
+		sourcePriority: make(map[string]int),
+		disabled:       make(map[string]bool),
+	}
+
+	var sources []EnrichmentSource
+	if err := m.db.Find(&sources).Error; err != nil {
+		return nil, fmt.Errorf("failed to fetch enrichment sources: %w", err)
+	}
+	for _, source := range sources {
+		policy.sourcePriority[source.Name] = source.Priority
+		policy.disabled[source.Name] = !source.Enabled
+	}
+
+	var overrides []EnrichmentFieldPriority
+	if err := m.db.Where("media_type IN ?", []string{"", mediaType}).
+		Order("media_type").Find(&overrides).Error; err != nil {
+		return nil, fmt.Errorf("failed to fetch field priorities: %w", err)
+	}
+	// Ordered so the media type's override replaces the global one
+	for _, override := range overrides {
+		var order []string
+		if err := json.Unmarshal([]byte(override.Sources), &order); err != nil {
+			log.Printf("WARN: Failed to parse source priority of field %s: %v", override.Field, err)
+			continue
+		}
+		policy.overrides[override.Field] = order
+	}
+	return policy, nil
+}
+
+// order returns the sources preferred for a field, most preferred first
+func (p *mergePolicy) order(field string) []string {
+	if order, ok := p.overrides[field]; ok {
+		return order
+	}
+	return p.rules[field].priorityFor(p.mediaType)
+}
+
+// priority returns a source's priority, falling back to the built-in default
+func (p *mergePolicy) priority(m *Module, source string) int {
+	if priority, ok := p.sourcePriority[source]; ok {
+		return priority
+	}
+	return m.getDefaultPriority(source)
+}
+
+// rank numbers the sources a field's priority lists, from 1
+func (p *mergePolicy) rank(field string) map[string]int {
+	rank := make(map[string]int)
+	for i, source := range p.order(field) {
+		rank[source] = i + 1
+	}
+	return rank
+}
+
+// sortCandidates orders the sources offering a field, most preferred first
+func (p *mergePolicy) sortCandidates(field string, candidates []mergeCandidate) {
+	rank := p.rank(field)
+	sort.SliceStable(candidates, func(i, j int) bool {
+		a, b := candidates[i], candidates[j]
+		return preferred(rank,
+			rankedSource{a.source, a.priority, a.data.ConfidenceScore},
+			rankedSource{b.source, b.priority, b.data.ConfidenceScore})
+	})
+}
+
+// rankedSource is what sources offering a field are ordered by
+type rankedSource struct {
+	source     string
+	priority   int
+	confidence float64
+}
+
+// preferred reports whether a is preferred to b: sources the field's
+// priority lists come first, in that order, then the rest by source
+// priority, confidence and name
+func preferred(rank map[string]int, a, b rankedSource) bool {
+	rankA, rankB := rank[a.source], rank[b.source]
+	if (rankA == 0) != (rankB == 0) {
+		return rankA != 0
+	}
+	if rankA != rankB {
+		return rankA < rankB
+	}
+	if a.priority != b.priority {
+		return a.priority < b.priority
+	}
+	if a.confidence != b.confidence {
+		return a.confidence > b.confidence
+	}
+	return a.source < b.source
+}
+
+// priorityFor returns the rule's source order for a media type
+func (r FieldRule) priorityFor(mediaType string) []string {
+	if order, ok := r.MediaTypePriority[mediaType]; ok {
+		return order
+	}
+	return r.SourcePriority
+}
+
+// mergeCandidates parses the enrichments stored for a media item, leaving out
+// disabled sources
+func (m *Module) mergeCandidates(enrichments []database.MediaEnrichment, policy *mergePolicy) []mergeCandidate {
+	candidates := make([]mergeCandidate, 0, len(enrichments))
+	for _, enrichment := range enrichments {
+		if policy.disabled[enrichment.Plugin] {
+			continue
+		}
+		var data EnrichmentData
+		if err := json.Unmarshal([]byte(enrichment.Payload), &data); err != nil {
+			log.Printf("WARN: Failed to parse enrichment data from %s: %v", enrichment.Plugin, err)
+			continue
+		}
+		candidates = append(candidates, mergeCandidate{
+			source:   enrichment.Plugin,
+			priority: policy.priority(m, enrichment.Plugin),
+			data:     data,
+		})
+	}
+	return candidates
+}
+
+// mergeEnrichmentData merges the enrichments of a media item field by field.
+// Each field takes the valid value of the source its priority prefers;
+// fields merging lists take the union of every source's values instead.
+func (m *Module) mergeEnrichmentData(enrichments []database.MediaEnrichment, mediaType string) (map[string]MergedField, error) {
+	policy, err := m.loadMergePolicy(mediaType)
+	if err != nil {
+		return nil, err
+	}
+	candidates := m.mergeCandidates(enrichments, policy)
+
+	offered := make(map[string]bool)
+	for _, candidate := range candidates {
+		for field := range candidate.data.Fields {
+			offered[field] = true
+		}
+	}
+
+	merged := make(map[string]MergedField)
+	for field := range offered {
+		rule, exists := policy.rules[field]
+		if !exists {
+			log.Printf("WARN: No rule found for field %s, skipping", field)
+			continue
+		}
+		if rule.MergeStrategy == MergeStrategySkip {
+			continue
+		}
+
+		ordered := make([]mergeCandidate, len(candidates))
+		copy(ordered, candidates)
+		policy.sortCandidates(field, ordered)
+
+		var result MergedField
+		var ok bool
+		if rule.MergeStrategy == MergeStrategyMerge {
+			result, ok = mergeFieldValues(field, rule, ordered)
+		} else {
+			result, ok = replaceFieldValue(field, rule, ordered)
+		}
+		if ok {
+			merged[field] = result
+		}
+	}
+	return merged, nil
+}
+
+// replaceFieldValue takes the first valid value offered for a field
+func replaceFieldValue(field string, rule FieldRule, candidates []mergeCandidate) (MergedField, bool) {
+	for _, candidate := range candidates {
+		value, offered := candidate.data.Fields[field]
+		if !offered || value == nil {
+			continue
+		}
+		valueStr := fmt.Sprintf("%v", value)
+		if rule.ValidateFunc != nil && !rule.ValidateFunc(valueStr) {
+			log.Printf("WARN: Invalid value for field %s from %s: %s", field, candidate.source, valueStr)
+			continue
+		}
+		if rule.NormalizeFunc != nil {
+			valueStr = rule.NormalizeFunc(valueStr)
+		}
+		return MergedField{
+			Value:      valueStr,
+			Source:     candidate.source,
+			Confidence: candidate.data.ConfidenceScore,
+		}, true
+	}
+	return MergedField{}, false
+}
+
+// mergeFieldValues takes the union of the lists offered for a field, in
+// source order, as a JSON array
+func mergeFieldValues(field string, rule FieldRule, candidates []mergeCandidate) (MergedField, bool) {
+	var result MergedField
+	var values []string
+	seen := make(map[string]bool)
+	for _, candidate := range candidates {
+		value, offered := candidate.data.Fields[field]
+		if !offered || value == nil {
+			continue
+		}
+		contributed := false
+		for _, item := range listValues(value) {
+			if rule.ValidateFunc != nil && !rule.ValidateFunc(item) {
+				continue
+			}
+			if rule.NormalizeFunc != nil {
+				item = rule.NormalizeFunc(item)
+			}
+			key := strings.ToLower(item)
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			values = append(values, item)
+			contributed = true
+		}
+		if !contributed {
+			continue
+		}
+		if result.Source == "" {
+			result.Source = candidate.source
+			result.Confidence = candidate.data.ConfidenceScore
+		}
+		result.Sources = append(result.Sources, candidate.source)
+	}
+	if len(values) == 0 {
+		return MergedField{}, false
+	}
+
+	encoded, err := json.Marshal(values)
+	if err != nil {
+		return MergedField{}, false
+	}
+	result.Value = string(encoded)
+	return result, true
+}
+
+// listValues splits a list value, which sources send as a JSON array or a
+// comma-separated string
+func listValues(value interface{}) []string {
+	switch v := value.(type) {
+	case []interface{}:
+		items := make([]string, 0, len(v))
+		for _, item := range v {
+			items = append(items, fmt.Sprintf("%v", item))
+		}
+		return items
+	case []string:
+		return v
+	}
+
+	valueStr := strings.TrimSpace(fmt.Sprintf("%v", value))
+	if strings.HasPrefix(valueStr, "[") {
+		var items []string
+		if err := json.Unmarshal([]byte(valueStr), &items); err == nil {
+			return items
+		}
+	}
+	return strings.Split(valueStr, ",")
+}
+
+// FieldPriorities returns the source order of every field for each media
+// type it applies to, or for one media type when mediaType is set
+func (m *Module) FieldPriorities(mediaType string) ([]FieldPriority, error) {
+	var overrides []EnrichmentFieldPriority
+	if err := m.db.Find(&overrides).Error; err != nil {
+		return nil, fmt.Errorf("failed to fetch field priorities: %w", err)
+	}
+	overridden := make(map[string][]string)
+	for _, override := range overrides {
+		var order []string
+		if err := json.Unmarshal([]byte(override.Sources), &order); err != nil {
+			log.Printf("WARN: Failed to parse source priority of field %s: %v", override.Field, err)
+			continue
+		}
+		overridden[override.Field+"/"+override.MediaType] = order
+	}
+
+	var priorities []FieldPriority
+	for name, rule := range m.GetFieldRules() {
+		for _, ruleType := range rule.MediaTypes {
+			if mediaType != "" && ruleType != mediaType {
+				continue
+			}
+			priority := FieldPriority{
+				Field:     name,
+				MediaType: ruleType,
+				Default:   rule.priorityFor(ruleType),
+			}
+			priority.Sources = priority.Default
+			if order, ok := overridden[name+"/"]; ok {
+				priority.Sources, priority.Overridden = order, true
+			}
+			if order, ok := overridden[name+"/"+ruleType]; ok {
+				priority.Sources, priority.Overridden = order, true
+			}
+			priorities = append(priorities, priority)
+		}
+	}
+	sort.Slice(priorities, func(i, j int) bool {
+		if priorities[i].Field != priorities[j].Field {
+			return priorities[i].Field < priorities[j].Field
+		}
+		return priorities[i].MediaType < priorities[j].MediaType
+	})
+	return priorities, nil
+}
+
+// SetFieldPriority sets the order sources are preferred in for a field, for
+// one media type or, when mediaType is empty, for every one. It applies to
+// enrichments applied from then on.
+func (m *Module) SetFieldPriority(field, mediaType string, sources []string) (*EnrichmentFieldPriority, error) {
+	rule, exists := m.GetFieldRules()[field]
+	if !exists || (mediaType != "" && !m.supportsMediaType(rule.MediaTypes, mediaType)) {
+		return nil, ErrUnknownField
+	}
+
+	order := make([]string, 0, len(sources))
+	seen := make(map[string]bool)
+	for _, source := range sources {
+		source = strings.TrimSpace(source)
+		if source != "" && !seen[source] {
+			seen[source] = true
+			order = append(order, source)
+		}
+	}
+	encoded, err := json.Marshal(order)
+	if err != nil {
+		return nil, err
+	}
+
+	var priority EnrichmentFieldPriority
+	err = m.db.Where("field = ? AND media_type = ?", field, mediaType).First(&priority).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	priority.Field = field
+	priority.MediaType = mediaType
+	priority.Sources = string(encoded)
+	if err := m.db.Save(&priority).Error; err != nil {
+		return nil, fmt.Errorf("failed to save field priority: %w", err)
+	}
+	return &priority, nil
+}
+
+// ResetFieldPriority removes a field's override for a media type, or its
+// global one when mediaType is empty, restoring the rule's order
+func (m *Module) ResetFieldPriority(field, mediaType string) error {
+	if _, exists := m.GetFieldRules()[field]; !exists {
+		return ErrUnknownField
+	}
+	return m.db.Where("field = ? AND media_type = ?", field, mediaType).
+		Delete(&EnrichmentFieldPriority{}).Error
+}
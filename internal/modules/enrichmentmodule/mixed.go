@@ -0,0 +1,175 @@
+package enrichmentmodule
+
+import (
+	"fmt"
+	"log"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/mantonx/viewra/internal/database"
+	"gorm.io/gorm"
+)
+
+// tmdbMediaType returns the TMDb type ("movie" or "tv") matching a media type
+func tmdbMediaType(mediaType database.MediaType) string {
+	switch mediaType {
+	case database.MediaTypeMovie:
+		return "movie"
+	case database.MediaTypeEpisode:
+		return "tv"
+	default:
+		return ""
+	}
+}
+
+// reconcileClassification checks an enrichment's media_type against the type
+// a mixed library assigned to the file, and reports whether the enrichment
+// applies to it. Types from naming are kept and mismatching enrichments are
+// dropped; guesses are confirmed or corrected by the enrichment.
+func (m *Module) reconcileClassification(mediaFile *database.MediaFile, sourceName string, enrichments map[string]interface{}) (bool, error) {
+	switch mediaFile.ClassifiedBy {
+	case database.ClassifiedByNaming, database.ClassifiedByGuess, database.ClassifiedByTMDb:
+	default:
+		return true, nil // Type comes from the library
+	}
+
+	enrichedType := strings.ToLower(enrichmentString(enrichments, "media_type"))
+	if enrichedType != "movie" && enrichedType != "tv" {
+		return true, nil
+	}
+	itemType := tmdbMediaType(mediaFile.MediaType)
+	if itemType == "" {
+		return true, nil
+	}
+
+	if enrichedType == itemType {
+		if mediaFile.ClassifiedBy == database.ClassifiedByGuess {
+			if err := m.db.Model(mediaFile).Update("classified_by", database.ClassifiedByTMDb).Error; err != nil {
+				return false, fmt.Errorf("failed to update classification: %w", err)
+			}
+			mediaFile.ClassifiedBy = database.ClassifiedByTMDb
+			log.Printf("INFO: %s confirmed %s as %s", sourceName, mediaFile.Path, mediaFile.MediaType)
+		}
+		return true, nil
+	}
+
+	if mediaFile.ClassifiedBy != database.ClassifiedByGuess || enrichedType != "tv" {
+		log.Printf("WARN: Ignoring %s enrichment for %s: %s match for a file classified as %s by %s",
+			sourceName, mediaFile.Path, enrichedType, mediaFile.MediaType, mediaFile.ClassifiedBy)
+		return false, nil
+	}
+
+	if err := m.reclassifyAsEpisode(mediaFile, enrichments); err != nil {
+		return false, fmt.Errorf("failed to reclassify as episode: %w", err)
+	}
+	log.Printf("INFO: %s reclassified %s as an episode", sourceName, mediaFile.Path)
+	return true, nil
+}
+
+// reclassifyAsEpisode turns a file guessed to be a movie into an episode of
+// the show TMDb matched. Without episode naming its number is unknown, so it
+// is added to the show's specials (season 0). The guessed movie is removed
+// when no other file belongs to it.
+func (m *Module) reclassifyAsEpisode(mediaFile *database.MediaFile, enrichments map[string]interface{}) error {
+	showTitle := enrichmentString(enrichments, "title")
+	if showTitle == "" {
+		return fmt.Errorf("enrichment has no show title")
+	}
+	tmdbID := enrichmentString(enrichments, "tmdb_id")
+
+	return m.db.Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+
+		var show database.TVShow
+		query := tx.Where("title = ?", showTitle)
+		if tmdbID != "" {
+			query = tx.Where("tmdb_id = ?", tmdbID)
+		}
+		if err := query.First(&show).Error; err == gorm.ErrRecordNotFound {
+			show = database.TVShow{ID: uuid.New().String(), Title: showTitle, TmdbID: tmdbID, CreatedAt: now, UpdatedAt: now}
+			if err := tx.Create(&show).Error; err != nil {
+				return err
+			}
+		} else if err != nil {
+			return err
+		}
+
+		var season database.Season
+		if err := tx.Where("tv_show_id = ? AND season_number = ?", show.ID, 0).First(&season).Error; err == gorm.ErrRecordNotFound {
+			season = database.Season{ID: uuid.New().String(), TVShowID: show.ID, SeasonNumber: 0, CreatedAt: now, UpdatedAt: now}
+			if err := tx.Create(&season).Error; err != nil {
+				return err
+			}
+		} else if err != nil {
+			return err
+		}
+
+		var lastEpisode int
+		if err := tx.Model(&database.Episode{}).Where("season_id = ?", season.ID).
+			Select("COALESCE(MAX(episode_number), 0)").Scan(&lastEpisode).Error; err != nil {
+			return err
+		}
+
+		title := strings.TrimSuffix(filepath.Base(mediaFile.Path), filepath.Ext(mediaFile.Path))
+		var movie database.Movie
+		guessedMovie := mediaFile.MediaType == database.MediaTypeMovie && mediaFile.MediaID != "" &&
+			tx.Where("id = ?", mediaFile.MediaID).First(&movie).Error == nil
+		if guessedMovie && movie.Title != "" {
+			title = movie.Title
+		}
+
+		episode := database.Episode{
+			ID:            uuid.New().String(),
+			SeasonID:      season.ID,
+			Title:         title,
+			EpisodeNumber: lastEpisode + 1,
+			Duration:      mediaFile.Duration,
+			CreatedAt:     now,
+			UpdatedAt:     now,
+		}
+		if err := tx.Create(&episode).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Model(mediaFile).Updates(map[string]interface{}{
+			"media_id":      episode.ID,
+			"media_type":    database.MediaTypeEpisode,
+			"classified_by": database.ClassifiedByTMDb,
+		}).Error; err != nil {
+			return err
+		}
+
+		if guessedMovie {
+			var remaining int64
+			if err := tx.Model(&database.MediaFile{}).
+				Where("media_id = ? AND media_type = ?", movie.ID, database.MediaTypeMovie).
+				Count(&remaining).Error; err != nil {
+				return err
+			}
+			if remaining == 0 {
+				if err := tx.Where("media_id = ? AND media_type = ?", movie.ID, database.MediaTypeMovie).
+					Delete(&database.MediaEnrichment{}).Error; err != nil {
+					return err
+				}
+				if err := tx.Delete(&movie).Error; err != nil {
+					return err
+				}
+			}
+		}
+
+		mediaFile.MediaID = episode.ID
+		mediaFile.MediaType = database.MediaTypeEpisode
+		mediaFile.ClassifiedBy = database.ClassifiedByTMDb
+		return nil
+	})
+}
+
+// enrichmentString returns an enrichment field as trimmed text
+func enrichmentString(enrichments map[string]interface{}, name string) string {
+	if enrichments[name] == nil {
+		return ""
+	}
+	return strings.TrimSpace(fmt.Sprintf("%v", enrichments[name]))
+}
@@ -17,9 +17,13 @@ import (
 	"github.com/mantonx/viewra/internal/modules/modulemanager"
 	"github.com/mantonx/viewra/internal/modules/pluginmodule"
 	"github.com/mantonx/viewra/internal/modules/scannermodule/scanner"
+	"github.com/mantonx/viewra/internal/parental"
+	plugins "github.com/mantonx/viewra/sdk"
 	// enrichmentpb "github.com/mantonx/viewra/sdk/grpc"
 	"github.com/mantonx/viewra/sdk/proto"
 	"google.golang.org/grpc"
+	_ "google.golang.org/grpc/encoding/gzip" // Registers gzip for compressed plugin requests
+	"google.golang.org/grpc/keepalive"
 	"gorm.io/gorm"
 )
 
@@ -62,6 +66,9 @@ type Module struct {
 	tvShowValidator    *TVShowValidator
 	duplicationManager *DuplicationManager
 	progressManager    *EnrichmentProgressManager
+
+	// Host watch history service, connected to the playback module once it runs
+	watchHistoryServer WatchHistoryGRPCServer
 }
 
 // Register registers this module with the module system
@@ -98,7 +105,11 @@ func (m *Module) Migrate(db *gorm.DB) error {
 	// Auto-migrate enrichment tables (MediaEnrichment and MediaExternalIDs already exist)
 	if err := m.db.AutoMigrate(
 		&EnrichmentSource{},
+		&EnrichmentFieldPriority{},
 		&EnrichmentJob{},
+		&database.MediaFieldProvenance{},
+		&database.MediaEnrichmentSnapshot{},
+		&database.MediaEnrichmentSnapshotAsset{},
 	); err != nil {
 		return fmt.Errorf("failed to migrate enrichment tables: %w", err)
 	}
@@ -176,10 +187,20 @@ func (m *Module) Start() error {
 		return fmt.Errorf("failed to listen on port %d: %w", m.grpcPort, err)
 	}
 
-	// Create gRPC server with larger message limits for artwork
+	// Get current config
+	cfg := config.Get()
+
+	// Create gRPC server with larger message limits for artwork. Plugins
+	// learn the limit during the ABI handshake; gzip requests are accepted
+	// and answered compressed.
 	opts := []grpc.ServerOption{
-		grpc.MaxRecvMsgSize(16 * 1024 * 1024), // 16MB, increased from default 4MB for artwork
-		grpc.MaxSendMsgSize(16 * 1024 * 1024), // 16MB
+		grpc.MaxRecvMsgSize(cfg.Plugins.GRPCMaxMessageSize),
+		grpc.MaxSendMsgSize(cfg.Plugins.GRPCMaxMessageSize),
+		// Plugin clients ping every 30s to keep long-lived connections alive
+		grpc.KeepaliveEnforcementPolicy(keepalive.EnforcementPolicy{
+			MinTime:             20 * time.Second,
+			PermitWithoutStream: true,
+		}),
 	}
 	m.grpcServer = grpc.NewServer(opts...)
 	
@@ -189,12 +210,18 @@ func (m *Module) Start() error {
 		Level: hclog.Debug,
 	})
 	
-	// Get current config
-	cfg := config.Get()
-	
 	// Register asset gRPC server
 	assetServer := NewAssetGRPCServer(logger, cfg, m.db)
 	proto.RegisterAssetServiceServer(m.grpcServer, assetServer)
+
+	// Register read-only media data gRPC server
+	proto.RegisterMediaDataServiceServer(m.grpcServer, NewMediaDataGRPCServer(logger, m.db))
+
+	// Register media entity gRPC server, through which plugins create library items
+	proto.RegisterMediaEntityServiceServer(m.grpcServer, NewMediaEntityGRPCServer(logger, m.db, assetServer.downloads))
+
+	// Register watch history server, through which plugins such as scrobblers add to users' history
+	plugins.RegisterWatchHistoryServer(m.grpcServer, &m.watchHistoryServer)
 	
 	// TODO: Fix enrichment gRPC server - protobuf path issues
 	// enrichmentServer := NewGRPCServer(m, m.db, logger.Named("enrichment-grpc"))
@@ -202,7 +229,7 @@ func (m *Module) Start() error {
 
 	// Start server in background
 	go func() {
-		log.Printf("INFO: Enrichment gRPC server listening on port %d (AssetService + EnrichmentService)", m.grpcPort)
+		log.Printf("INFO: Enrichment gRPC server listening on port %d (AssetService + MediaDataService + MediaEntityService + WatchHistoryService)", m.grpcPort)
 		if err := m.grpcServer.Serve(listener); err != nil {
 			log.Printf("ERROR: gRPC server failed: %v", err)
 		}
@@ -283,19 +310,42 @@ type FieldRule struct {
 	MergeStrategy  MergeStrategy
 	ValidateFunc   func(value string) bool
 	NormalizeFunc  func(value string) string
+
+	// MediaTypePriority replaces SourcePriority for some media types, e.g.
+	// TVDb before TMDb for episode titles
+	MediaTypePriority map[string][]string
 }
 
 // GetFieldRules returns the enrichment rules based on the priority table
 func (m *Module) GetFieldRules() map[string]FieldRule {
-	return map[string]FieldRule{
+	rules := map[string]FieldRule{
 		"title": {
 			FieldName:      "title",
 			MediaTypes:     []string{"track", "movie", "episode"},
-			SourcePriority: []string{"tmdb", "musicbrainz", "filename", "embedded"},
+			SourcePriority: []string{"tmdb", "tvdb", "musicbrainz", "filename", "embedded"},
+			MergeStrategy:  MergeStrategyReplace,
+			MediaTypePriority: map[string][]string{
+				"episode": {"tvdb", "tmdb", "filename", "embedded"},
+			},
+			ValidateFunc:  func(value string) bool { return strings.TrimSpace(value) != "" },
+			NormalizeFunc: func(value string) string { return strings.TrimSpace(value) },
+		},
+		"overview": {
+			FieldName:      "overview",
+			MediaTypes:     []string{"movie", "episode"},
+			SourcePriority: []string{"tmdb", "tvdb"},
 			MergeStrategy:  MergeStrategyReplace,
 			ValidateFunc:   func(value string) bool { return strings.TrimSpace(value) != "" },
 			NormalizeFunc:  func(value string) string { return strings.TrimSpace(value) },
 		},
+		"content_rating": {
+			FieldName:      "content_rating",
+			MediaTypes:     []string{"movie", "episode"},
+			SourcePriority: []string{"tmdb"},
+			MergeStrategy:  MergeStrategyReplace,
+			ValidateFunc:   parental.Valid,
+			NormalizeFunc:  func(value string) string { return strings.TrimSpace(value) },
+		},
 		"artist_name": {
 			FieldName:      "artist_name",
 			MediaTypes:     []string{"track"},
@@ -315,7 +365,7 @@ func (m *Module) GetFieldRules() map[string]FieldRule {
 		"release_year": {
 			FieldName:      "release_year",
 			MediaTypes:     []string{"track", "movie", "episode"},
-			SourcePriority: []string{"tmdb", "musicbrainz", "filename"},
+			SourcePriority: []string{"tmdb", "tvdb", "musicbrainz", "filename"},
 			MergeStrategy:  MergeStrategyReplace,
 			ValidateFunc: func(value string) bool {
 				if year, err := strconv.Atoi(value); err == nil {
@@ -336,7 +386,7 @@ func (m *Module) GetFieldRules() map[string]FieldRule {
 		"duration": {
 			FieldName:      "duration",
 			MediaTypes:     []string{"track", "movie", "episode"},
-			SourcePriority: []string{"embedded", "tmdb", "musicbrainz"},
+			SourcePriority: []string{"embedded", "tmdb", "tvdb", "musicbrainz"},
 			MergeStrategy:  MergeStrategyReplace,
 			ValidateFunc: func(value string) bool {
 				if duration, err := strconv.Atoi(value); err == nil {
@@ -359,7 +409,36 @@ func (m *Module) GetFieldRules() map[string]FieldRule {
 			},
 			NormalizeFunc: func(value string) string { return strings.TrimSpace(value) },
 		},
+		"disc_number": {
+			FieldName:      "disc_number",
+			MediaTypes:     []string{"track"},
+			SourcePriority: []string{"embedded", "musicbrainz"},
+			MergeStrategy:  MergeStrategyReplace,
+			ValidateFunc: func(value string) bool {
+				if discNum, err := strconv.Atoi(value); err == nil {
+					return discNum > 0 && discNum <= 999
+				}
+				return false
+			},
+			NormalizeFunc: func(value string) string { return strings.TrimSpace(value) },
+		},
+	}
+
+	// Artist and release fields from MusicBrainz
+	for name, rule := range musicEntityFieldRules() {
+		rules[name] = rule
+	}
+
+	// Composer, work and performers of classical recordings
+	for name, rule := range classicalFieldRules() {
+		rules[name] = rule
+	}
+
+	// Franchises movies belong to
+	for name, rule := range collectionFieldRules() {
+		rules[name] = rule
 	}
+	return rules
 }
 
 // RegisterEnrichmentData registers enriched metadata for later application
@@ -374,8 +453,16 @@ func (m *Module) RegisterEnrichmentData(mediaFileID, sourceName string, enrichme
 		return fmt.Errorf("media file not found: %w", err)
 	}
 
+	// Items in mixed libraries carry their own type, which TMDb may confirm or correct
+	applies, err := m.reconcileClassification(&mediaFile, sourceName, enrichments)
+	if err != nil {
+		log.Printf("WARN: Failed to reconcile classification of %s with %s: %v", mediaFileID, sourceName, err)
+	} else if !applies {
+		return nil
+	}
+
 	// **NEW: Validate TV show metadata before storing**
-	if mediaFile.MediaType == "episode" || strings.Contains(strings.ToLower(mediaFile.Path), "tv") {
+	if mediaFile.MediaType == "episode" || (mediaFile.ClassifiedBy == database.ClassifiedByLibrary && strings.Contains(strings.ToLower(mediaFile.Path), "tv")) {
 		if err := m.validateTVShowEnrichmentData(enrichments, sourceName); err != nil {
 			log.Printf("WARN: TV show enrichment validation failed for %s from %s: %v", mediaFileID, sourceName, err)
 			// Don't fail completely, but reduce confidence
@@ -484,10 +571,11 @@ func (m *Module) RegisterEnrichmentData(mediaFileID, sourceName string, enrichme
 func (m *Module) getDefaultPriority(sourceName string) int {
 	priorities := map[string]int{
 		"tmdb":        1,
-		"musicbrainz": 2,
-		"audiodb":     3,
-		"embedded":    4,
-		"filename":    5,
+		"tvdb":        2,
+		"musicbrainz": 3,
+		"audiodb":     4,
+		"embedded":    5,
+		"filename":    6,
 	}
 
 	if priority, exists := priorities[sourceName]; exists {
@@ -604,6 +692,12 @@ func (m *Module) processEnrichmentJob(job *EnrichmentJob) error {
 	}
 
 processEnrichments:
+	// Fields locked by an admin keep their current value
+	locked, err := m.lockedFields(mediaFile.MediaID)
+	if err != nil {
+		return fmt.Errorf("failed to fetch locked fields: %w", err)
+	}
+
 	// Get all enrichments for this media file
 	var enrichments []database.MediaEnrichment
 	if err := m.db.Where("media_id = ? AND media_type = ?", mediaFile.MediaID, mediaFile.MediaType).
@@ -617,8 +711,11 @@ processEnrichments:
 		return m.db.Save(job).Error
 	}
 
-	// Parse enrichment data and merge by priority
-	mergedData, err := m.mergeEnrichmentData(enrichments)
+	// Keep the item's state from before its first recorded enrichment
+	m.recordHistory(&mediaFile, SnapshotReasonInitial)
+
+	// Merge the sources field by field
+	merged, err := m.mergeEnrichmentData(enrichments, string(mediaFile.MediaType))
 	if err != nil {
 		return fmt.Errorf("failed to merge enrichment data: %w", err)
 	}
@@ -627,45 +724,45 @@ processEnrichments:
 	rules := m.GetFieldRules()
 
 	// Apply merged enrichments
-	for fieldName, value := range mergedData.Fields {
-		rule, exists := rules[fieldName]
-		if !exists {
-			log.Printf("WARN: No rule found for field %s, skipping", fieldName)
-			continue
-		}
+	for fieldName, field := range merged {
+		rule := rules[fieldName]
 
 		// Check if this field supports the media type
 		if !m.supportsMediaType(rule.MediaTypes, string(mediaFile.MediaType)) {
 			continue
 		}
 
-		valueStr := fmt.Sprintf("%v", value)
-
-		// Validate the value
-		if rule.ValidateFunc != nil && !rule.ValidateFunc(valueStr) {
-			log.Printf("WARN: Invalid value for field %s: %s", fieldName, valueStr)
+		if locked[fieldName] {
+			results[fieldName] = map[string]interface{}{
+				"applied": false,
+				"locked":  true,
+			}
 			continue
 		}
 
-		// Normalize the value
-		if rule.NormalizeFunc != nil {
-			valueStr = rule.NormalizeFunc(valueStr)
-		}
-
 		// Apply the enrichment
-		if err := m.applyFieldToEntity(mediaFile.MediaID, string(mediaFile.MediaType), fieldName, valueStr, rule.MergeStrategy); err != nil {
+		if err := m.applyFieldToEntity(mediaFile.MediaID, string(mediaFile.MediaType), fieldName, field.Value, rule.MergeStrategy); err != nil {
 			log.Printf("ERROR: Failed to apply enrichment for field %s: %v", fieldName, err)
 			continue
 		}
+		if err := m.recordProvenance(mediaFile.MediaID, mediaFile.MediaType, fieldName, field.Source, field.Value, field.Confidence); err != nil {
+			log.Printf("WARN: Failed to record provenance of field %s: %v", fieldName, err)
+		}
 
-		results[fieldName] = map[string]interface{}{
+		result := map[string]interface{}{
 			"applied":    true,
-			"source":     mergedData.Source,
-			"value":      valueStr,
-			"confidence": mergedData.ConfidenceScore,
+			"source":     field.Source,
+			"value":      field.Value,
+			"confidence": field.Confidence,
+		}
+		if len(field.Sources) > 1 {
+			result["sources"] = field.Sources
 		}
+		results[fieldName] = result
 	}
 
+	m.recordHistory(&mediaFile, SnapshotReasonEnrichment)
+
 	// Mark job as completed
 	job.Status = "completed"
 	resultsJSON, _ := json.Marshal(results)
@@ -679,7 +776,7 @@ processEnrichments:
 	// Emit enrichment applied event
 	if m.eventBus != nil {
 		event := events.NewSystemEvent(
-			"enrichment.applied",
+			events.EventEnrichmentApplied,
 			"Enrichment Applied",
 			fmt.Sprintf("Applied enrichments to media file %s", job.MediaFileID),
 		)
@@ -694,46 +791,6 @@ processEnrichments:
 	return nil
 }
 
-// mergeEnrichmentData merges enrichment data from multiple sources by priority
-func (m *Module) mergeEnrichmentData(enrichments []database.MediaEnrichment) (*EnrichmentData, error) {
-	if len(enrichments) == 0 {
-		return nil, fmt.Errorf("no enrichments to merge")
-	}
-
-	// Parse and sort by priority
-	var enrichmentDataList []EnrichmentData
-	for _, enrichment := range enrichments {
-		var data EnrichmentData
-		if err := json.Unmarshal([]byte(enrichment.Payload), &data); err != nil {
-			log.Printf("WARN: Failed to parse enrichment data from %s: %v", enrichment.Plugin, err)
-			continue
-		}
-		enrichmentDataList = append(enrichmentDataList, data)
-	}
-
-	// Sort by priority (lower = higher priority)
-	// For now, just use the first one - we could implement more sophisticated merging
-	if len(enrichmentDataList) == 0 {
-		return nil, fmt.Errorf("no valid enrichment data found")
-	}
-
-	// TODO: Implement proper priority-based merging
-	bestEnrichment := enrichmentDataList[0]
-	for _, data := range enrichmentDataList[1:] {
-		if data.SourcePriority < bestEnrichment.SourcePriority {
-			bestEnrichment = data
-		}
-	}
-
-	return &bestEnrichment, nil
-}
-
-// selectBestEnrichment is replaced by mergeEnrichmentData
-// (keeping for compatibility but updating signature)
-func (m *Module) selectBestEnrichment(enrichments []database.MediaEnrichment, rule FieldRule) (*EnrichmentData, error) {
-	return m.mergeEnrichmentData(enrichments)
-}
-
 // applyFieldEnrichment is replaced by applyFieldToEntity
 func (m *Module) applyFieldToEntity(entityID, mediaType, fieldName, value string, strategy MergeStrategy) error {
 	switch mediaType {
@@ -810,7 +867,19 @@ func (m *Module) applyTrackEnrichment(trackID, fieldName, value string, strategy
 		}
 		return fmt.Errorf("invalid track number format: %s", value)
 
+	case "disc_number":
+		if discNum, err := strconv.Atoi(value); err == nil {
+			return m.db.Model(&database.Track{}).Where("id = ?", trackID).Update("disc_number", discNum).Error
+		}
+		return fmt.Errorf("invalid disc number format: %s", value)
+
 	default:
+		if isMusicEntityField(fieldName) {
+			return m.applyMusicEntityField(trackID, fieldName, value)
+		}
+		if isClassicalField(fieldName) {
+			return m.applyClassicalField(trackID, fieldName, value)
+		}
 		log.Printf("WARN: Unknown track field: %s", fieldName)
 		return nil
 	}
@@ -827,6 +896,15 @@ func (m *Module) applyMovieEnrichment(movieID, fieldName, value string, strategy
 			return m.db.Model(&database.Movie{}).Where("id = ?", movieID).Update("release_date", releaseDate).Error
 		}
 		return fmt.Errorf("invalid year format: %s", value)
+	case "overview":
+		return m.db.Model(&database.Movie{}).Where("id = ?", movieID).Update("overview", value).Error
+	case "genres":
+		// Merged genres are a JSON array, as the column stores them
+		return m.db.Model(&database.Movie{}).Where("id = ?", movieID).Update("genres", value).Error
+	case "content_rating":
+		return m.db.Model(&database.Movie{}).Where("id = ?", movieID).Update("rating", value).Error
+	case "collection":
+		return m.applyCollection(movieID, value)
 	default:
 		log.Printf("WARN: Unknown movie field: %s", fieldName)
 		return nil
@@ -835,9 +913,25 @@ func (m *Module) applyMovieEnrichment(movieID, fieldName, value string, strategy
 
 // applyEpisodeEnrichment applies enrichment to episode entities
 func (m *Module) applyEpisodeEnrichment(episodeID, fieldName, value string, strategy MergeStrategy) error {
-	// TODO: Implement when episode model is available
-	log.Printf("INFO: Episode enrichment not yet implemented for field: %s", fieldName)
-	return nil
+	switch fieldName {
+	case "title":
+		return m.db.Model(&database.Episode{}).Where("id = ?", episodeID).Update("title", value).Error
+	case "overview":
+		return m.db.Model(&database.Episode{}).Where("id = ?", episodeID).Update("description", value).Error
+	case "duration":
+		if duration, err := strconv.Atoi(value); err == nil {
+			return m.db.Model(&database.Episode{}).Where("id = ?", episodeID).Update("duration", duration).Error
+		}
+		return fmt.Errorf("invalid duration format: %s", value)
+	case "content_rating":
+		// Shows are rated as a whole; their episodes take the show's rating
+		season := m.db.Table("episodes").Select("season_id").Where("id = ?", episodeID)
+		show := m.db.Table("seasons").Select("tv_show_id").Where("id IN (?)", season)
+		return m.db.Model(&database.TVShow{}).Where("id IN (?)", show).Update("content_rating", value).Error
+	default:
+		log.Printf("INFO: Episode enrichment not yet implemented for field: %s", fieldName)
+		return nil
+	}
 }
 
 // GetEnrichmentStatus returns enrichment status for a media file
@@ -897,23 +991,20 @@ func (m *Module) GetEnrichmentStatus(mediaFileID string) (map[string]interface{}
 			}
 
 			fieldInfo["enrichments"] = append(fieldInfo["enrichments"].([]map[string]interface{}), enrichmentInfo)
-
-			// Set best source to the one with highest priority (lowest number)
-			currentBestPriority, hasBest := fieldInfo["best_priority"]
-			if !hasBest || enrichmentData.SourcePriority < currentBestPriority.(int) {
-				fieldInfo["best_source"] = enrichment.Plugin
-				fieldInfo["best_priority"] = enrichmentData.SourcePriority
-			}
-
 			fieldStatus[fieldName] = fieldInfo
 		}
 	}
 
-	// Clean up temporary priority fields
-	for fieldName, fieldInfo := range fieldStatus {
-		if info, ok := fieldInfo.(map[string]interface{}); ok {
-			delete(info, "best_priority")
-			fieldStatus[fieldName] = info
+	// The best source is the one the merge takes each field from
+	if len(enrichments) > 0 {
+		merged, err := m.mergeEnrichmentData(enrichments, string(mediaFile.MediaType))
+		if err != nil {
+			log.Printf("WARN: Failed to merge enrichments of %s: %v", mediaFileID, err)
+		}
+		for fieldName, field := range merged {
+			if info, ok := fieldStatus[fieldName].(map[string]interface{}); ok {
+				info["best_source"] = field.Source
+			}
 		}
 	}
 
@@ -923,7 +1014,8 @@ func (m *Module) GetEnrichmentStatus(mediaFileID string) (map[string]interface{}
 	return status, nil
 }
 
-// ForceApplyEnrichment manually applies enrichment for a specific field
+// ForceApplyEnrichment manually applies enrichment for a specific field, locked
+// or not
 func (m *Module) ForceApplyEnrichment(mediaFileID, fieldName, sourceName string) error {
 	// Get media file to find media ID
 	var mediaFile database.MediaFile
@@ -955,10 +1047,16 @@ func (m *Module) ForceApplyEnrichment(mediaFileID, fieldName, sourceName string)
 		return fmt.Errorf("no rule found for field: %s", fieldName)
 	}
 
+	m.recordHistory(&mediaFile, SnapshotReasonInitial)
+
 	valueStr := fmt.Sprintf("%v", value)
 	if err := m.applyFieldToEntity(mediaFile.MediaID, string(mediaFile.MediaType), fieldName, valueStr, rule.MergeStrategy); err != nil {
 		return fmt.Errorf("failed to apply enrichment: %w", err)
 	}
+	if err := m.recordProvenance(mediaFile.MediaID, mediaFile.MediaType, fieldName, sourceName, valueStr, enrichmentData.ConfidenceScore); err != nil {
+		return fmt.Errorf("failed to record provenance: %w", err)
+	}
+	m.recordHistory(&mediaFile, SnapshotReasonForceApply)
 
 	enrichment.UpdatedAt = time.Now()
 	return m.db.Save(&enrichment).Error
@@ -978,6 +1076,13 @@ func (m *Module) SetExternalPluginManager(externalPluginManager interface{}) {
 	log.Printf("INFO: External plugin manager connected to enrichment module")
 }
 
+// SetWatchHistoryService connects the service behind the WatchHistoryService
+// plugins call to add to users' watch history
+func (m *Module) SetWatchHistoryService(service plugins.WatchHistoryService) {
+	m.watchHistoryServer.SetService(service)
+	log.Printf("INFO: Watch history service connected to enrichment module")
+}
+
 // OnMediaFileScanned is called by the scanner when a media file is scanned
 // This integrates with the existing scanner plugin hook system
 func (m *Module) OnMediaFileScanned(mediaFile *database.MediaFile, metadata interface{}) error {
@@ -988,6 +1093,14 @@ func (m *Module) OnMediaFileScanned(mediaFile *database.MediaFile, metadata inte
 
 	log.Printf("INFO: Enrichment module processing scanned file: %s", mediaFile.Path)
 
+	// Home videos are personal footage: skip external enrichment entirely to
+	// avoid bogus matches
+	var library database.MediaLibrary
+	if err := m.db.First(&library, mediaFile.LibraryID).Error; err == nil && library.Type == "home" {
+		log.Printf("DEBUG: Skipping enrichment for home video: %s", mediaFile.Path)
+		return nil
+	}
+
 	// DEBUG: Enhanced external plugin manager diagnostics
 	log.Printf("DEBUG: External plugin manager status - exists: %v", m.externalPluginManager != nil)
 	
@@ -998,29 +1111,7 @@ func (m *Module) OnMediaFileScanned(mediaFile *database.MediaFile, metadata inte
 			log.Printf("DEBUG: Type assertion successful, external plugin manager ready")
 			
 			// Convert metadata to map[string]string for external plugins
-			var metadataMap map[string]string
-			if metadata != nil {
-				switch v := metadata.(type) {
-				case map[string]string:
-					metadataMap = v
-				case map[string]interface{}:
-					// Convert map[string]interface{} to map[string]string
-					metadataMap = make(map[string]string)
-					for key, value := range v {
-						if str, ok := value.(string); ok {
-							metadataMap[key] = str
-						} else {
-							metadataMap[key] = fmt.Sprintf("%v", value)
-						}
-					}
-				default:
-					// For other types, create empty map and log
-					metadataMap = make(map[string]string)
-					log.Printf("DEBUG: Unsupported metadata type %T for file %s", metadata, mediaFile.Path)
-				}
-			} else {
-				metadataMap = make(map[string]string)
-			}
+			metadataMap := hookMetadataStrings(metadata, mediaFile.Path)
 			
 			// DEBUG: Log the actual metadata being passed to external plugins
 			log.Printf("DEBUG: Metadata being passed to external plugins for file %s: %+v", mediaFile.Path, metadataMap)
@@ -1053,6 +1144,71 @@ func (m *Module) OnMediaFileScanned(mediaFile *database.MediaFile, metadata inte
 	return nil
 }
 
+// OnMediaFileUpdated is called by the scanner when an existing media file
+// changes on disk. Plugins refresh their data for it and the enrichments are
+// applied again.
+func (m *Module) OnMediaFileUpdated(mediaFile *database.MediaFile, metadata interface{}) error {
+	if !m.enabled {
+		return nil
+	}
+
+	var library database.MediaLibrary
+	if err := m.db.First(&library, mediaFile.LibraryID).Error; err == nil && library.Type == "home" {
+		return nil
+	}
+
+	if extMgr, ok := m.externalPluginManager.(*pluginmodule.ExternalPluginManager); ok {
+		extMgr.NotifyMediaFileUpdated(mediaFile.ID, mediaFile.Path, hookMetadataStrings(metadata, mediaFile.Path))
+	}
+
+	job := EnrichmentJob{
+		MediaFileID: mediaFile.ID,
+		JobType:     "apply_enrichment",
+		Status:      "pending",
+	}
+	if err := m.db.Create(&job).Error; err != nil {
+		log.Printf("WARN: Failed to create enrichment job for %s: %v", mediaFile.Path, err)
+	}
+	return nil
+}
+
+// OnMediaFileRemoved is called by the scanner before a media file is deleted.
+// Plugins drop their data for the file, and its pending jobs are discarded.
+func (m *Module) OnMediaFileRemoved(mediaFile *database.MediaFile) error {
+	if extMgr, ok := m.externalPluginManager.(*pluginmodule.ExternalPluginManager); ok {
+		extMgr.NotifyMediaFileRemoved(mediaFile.ID, mediaFile.Path, nil)
+	}
+
+	if err := m.db.Where("media_file_id = ? AND status = ?", mediaFile.ID, "pending").Delete(&EnrichmentJob{}).Error; err != nil {
+		log.Printf("WARN: Failed to remove enrichment jobs for %s: %v", mediaFile.Path, err)
+	}
+	return nil
+}
+
+// hookMetadataStrings converts scanner metadata into the string map passed to
+// external plugins
+func hookMetadataStrings(metadata interface{}, path string) map[string]string {
+	switch v := metadata.(type) {
+	case map[string]string:
+		return v
+	case map[string]interface{}:
+		result := make(map[string]string, len(v))
+		for key, value := range v {
+			if str, ok := value.(string); ok {
+				result[key] = str
+			} else {
+				result[key] = fmt.Sprintf("%v", value)
+			}
+		}
+		return result
+	case nil:
+		return make(map[string]string)
+	default:
+		log.Printf("DEBUG: Unsupported metadata type %T for file %s", metadata, path)
+		return make(map[string]string)
+	}
+}
+
 // OnScanStarted is called when a scan starts (ScannerPluginHook interface)
 func (m *Module) OnScanStarted(jobID, libraryID uint, path string) error {
 	if !m.enabled {
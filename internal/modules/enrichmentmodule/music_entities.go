@@ -0,0 +1,249 @@
+package enrichmentmodule
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/mantonx/viewra/internal/database"
+	"gorm.io/gorm"
+)
+
+// partialDatePattern matches MusicBrainz partial dates: YYYY, YYYY-MM or YYYY-MM-DD
+var partialDatePattern = regexp.MustCompile(`^\d{4}(-\d{2}(-\d{2})?)?$`)
+
+// musicRelationship is one entry of the artist_relationships field
+type musicRelationship struct {
+	Type       string   `json:"type"`
+	Direction  string   `json:"direction"`
+	TargetMBID string   `json:"target_mbid"`
+	TargetName string   `json:"target_name"`
+	Begin      string   `json:"begin"`
+	End        string   `json:"end"`
+	Ended      bool     `json:"ended"`
+	Attributes []string `json:"attributes"`
+}
+
+// musicLabel is one entry of the release_labels field
+type musicLabel struct {
+	Name          string `json:"name"`
+	MBID          string `json:"mbid"`
+	CatalogNumber string `json:"catalog_number"`
+}
+
+// musicEntityFieldRules returns the rules for artist- and release-level
+// fields. They arrive with a track's enrichment but describe the track's
+// artist and album, and are stored on those entities.
+func musicEntityFieldRules() map[string]FieldRule {
+	text := func(name string) FieldRule {
+		return FieldRule{
+			FieldName:      name,
+			MediaTypes:     []string{"track"},
+			SourcePriority: []string{"musicbrainz"},
+			MergeStrategy:  MergeStrategyReplace,
+			ValidateFunc:   func(value string) bool { return strings.TrimSpace(value) != "" },
+			NormalizeFunc:  func(value string) string { return strings.TrimSpace(value) },
+		}
+	}
+	withValidation := func(rule FieldRule, validate func(string) bool) FieldRule {
+		rule.ValidateFunc = validate
+		return rule
+	}
+
+	isMBID := func(value string) bool {
+		_, err := uuid.Parse(strings.TrimSpace(value))
+		return err == nil
+	}
+	isDate := func(value string) bool { return partialDatePattern.MatchString(strings.TrimSpace(value)) }
+	isCountry := func(value string) bool { return len(strings.TrimSpace(value)) == 2 }
+	isBool := func(value string) bool {
+		_, err := strconv.ParseBool(strings.TrimSpace(value))
+		return err == nil
+	}
+	isBarcode := func(value string) bool {
+		value = strings.TrimSpace(value)
+		if value == "" {
+			return false
+		}
+		for _, r := range value {
+			if r < '0' || r > '9' {
+				return false
+			}
+		}
+		return true
+	}
+	isJSONArray := func(value string) bool {
+		value = strings.TrimSpace(value)
+		return strings.HasPrefix(value, "[") && json.Valid([]byte(value))
+	}
+
+	rules := map[string]FieldRule{
+		"artist_mbid":           withValidation(text("artist_mbid"), isMBID),
+		"artist_type":           text("artist_type"),
+		"artist_country":        withValidation(text("artist_country"), isCountry),
+		"artist_begin_date":     withValidation(text("artist_begin_date"), isDate),
+		"artist_end_date":       withValidation(text("artist_end_date"), isDate),
+		"artist_ended":          withValidation(text("artist_ended"), isBool),
+		"artist_disambiguation": text("artist_disambiguation"),
+		"artist_relationships":  withValidation(text("artist_relationships"), isJSONArray),
+		"release_mbid":          withValidation(text("release_mbid"), isMBID),
+		"release_type":          text("release_type"),
+		"release_status":        text("release_status"),
+		"release_country":       withValidation(text("release_country"), isCountry),
+		"release_barcode":       withValidation(text("release_barcode"), isBarcode),
+		"release_labels":        withValidation(text("release_labels"), isJSONArray),
+	}
+
+	// Country codes are stored upper case
+	for _, name := range []string{"artist_country", "release_country"} {
+		rule := rules[name]
+		rule.NormalizeFunc = func(value string) string { return strings.ToUpper(strings.TrimSpace(value)) }
+		rules[name] = rule
+	}
+	return rules
+}
+
+// isMusicEntityField reports whether a track field belongs to the artist or album
+func isMusicEntityField(fieldName string) bool {
+	_, ok := musicEntityFieldRules()[fieldName]
+	return ok
+}
+
+// applyMusicEntityField stores an artist- or release-level field on the
+// artist or album of a track
+func (m *Module) applyMusicEntityField(trackID, fieldName, value string) error {
+	var track database.Track
+	if err := m.db.Select("id, artist_id, album_id").Where("id = ?", trackID).First(&track).Error; err != nil {
+		return fmt.Errorf("track not found: %w", err)
+	}
+
+	artist := m.db.Model(&database.Artist{}).Where("id = ?", track.ArtistID)
+	album := m.db.Model(&database.Album{}).Where("id = ?", track.AlbumID)
+
+	switch fieldName {
+	case "artist_mbid":
+		if err := artist.Update("music_brainz_id", value).Error; err != nil {
+			return err
+		}
+		// Link relationships of other artists that point at this one
+		return m.db.Model(&database.ArtistRelationship{}).Where("target_mbid = ?", value).
+			Update("target_artist_id", track.ArtistID).Error
+	case "artist_type":
+		return artist.Update("type", value).Error
+	case "artist_country":
+		return artist.Update("country", value).Error
+	case "artist_begin_date":
+		return artist.Update("begin_date", value).Error
+	case "artist_end_date":
+		return artist.Update("end_date", value).Error
+	case "artist_ended":
+		ended, _ := strconv.ParseBool(value)
+		return artist.Update("ended", ended).Error
+	case "artist_disambiguation":
+		return artist.Update("disambiguation", value).Error
+	case "artist_relationships":
+		return m.replaceArtistRelationships(track.ArtistID, value)
+
+	case "release_mbid":
+		return album.Update("music_brainz_id", value).Error
+	case "release_type":
+		return album.Update("release_type", value).Error
+	case "release_status":
+		return album.Update("release_status", value).Error
+	case "release_country":
+		return album.Update("country", value).Error
+	case "release_barcode":
+		return album.Update("barcode", value).Error
+	case "release_labels":
+		return m.replaceAlbumLabels(track.AlbumID, value)
+
+	default:
+		log.Printf("WARN: Unknown music entity field: %s", fieldName)
+		return nil
+	}
+}
+
+// replaceArtistRelationships swaps an artist's relationships for the ones in
+// the JSON value, linking targets that are in the library
+func (m *Module) replaceArtistRelationships(artistID, value string) error {
+	var relationships []musicRelationship
+	if err := json.Unmarshal([]byte(value), &relationships); err != nil {
+		return fmt.Errorf("invalid artist relationships: %w", err)
+	}
+
+	return m.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("artist_id = ?", artistID).Delete(&database.ArtistRelationship{}).Error; err != nil {
+			return err
+		}
+		for _, r := range relationships {
+			if r.Type == "" || (r.TargetMBID == "" && r.TargetName == "") {
+				continue
+			}
+			direction := r.Direction
+			if direction == "" {
+				direction = "forward"
+			}
+			row := database.ArtistRelationship{
+				ArtistID:   artistID,
+				Type:       r.Type,
+				Direction:  direction,
+				TargetMBID: r.TargetMBID,
+				TargetName: r.TargetName,
+				BeginDate:  r.Begin,
+				EndDate:    r.End,
+				Ended:      r.Ended,
+				Attributes: strings.Join(r.Attributes, ", "),
+			}
+			if r.TargetMBID != "" {
+				var targets []string
+				if err := tx.Model(&database.Artist{}).Where("music_brainz_id = ?", r.TargetMBID).
+					Limit(1).Pluck("id", &targets).Error; err != nil {
+					return err
+				}
+				if len(targets) > 0 {
+					row.TargetArtistID = &targets[0]
+				}
+			}
+			if err := tx.Create(&row).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// replaceAlbumLabels swaps an album's labels for the ones in the JSON value
+func (m *Module) replaceAlbumLabels(albumID, value string) error {
+	var labels []musicLabel
+	if err := json.Unmarshal([]byte(value), &labels); err != nil {
+		return fmt.Errorf("invalid release labels: %w", err)
+	}
+
+	return m.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("album_id = ?", albumID).Delete(&database.AlbumLabel{}).Error; err != nil {
+			return err
+		}
+		position := 0
+		for _, l := range labels {
+			if l.Name == "" && l.CatalogNumber == "" {
+				continue
+			}
+			row := database.AlbumLabel{
+				AlbumID:       albumID,
+				Position:      position,
+				Name:          l.Name,
+				LabelMBID:     l.MBID,
+				CatalogNumber: l.CatalogNumber,
+			}
+			if err := tx.Create(&row).Error; err != nil {
+				return err
+			}
+			position++
+		}
+		return nil
+	})
+}
@@ -0,0 +1,232 @@
+package enrichmentmodule
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"github.com/mantonx/viewra/internal/database"
+	"gorm.io/gorm"
+)
+
+// ProvenanceSourceScanner marks fields no enrichment has set, which still hold
+// what the scanner read from the file's tags or name
+const ProvenanceSourceScanner = "scanner"
+
+// ErrMediaNotFound is returned when neither a media file nor a media item has the ID
+var ErrMediaNotFound = errors.New("media not found")
+
+// MediaProvenance explains where each displayed field of a media item came from
+type MediaProvenance struct {
+	MediaFileID string            `json:"media_file_id"`
+	MediaID     string            `json:"media_id"`
+	MediaType   string            `json:"media_type"`
+	Fields      []FieldProvenance `json:"fields"`
+}
+
+// FieldProvenance is the source of one field's value and the values every
+// enrichment source offered for it
+type FieldProvenance struct {
+	Field      string                `json:"field"`
+	Value      string                `json:"value,omitempty"`
+	Source     string                `json:"source"`
+	SetAt      *time.Time            `json:"set_at,omitempty"`
+	Confidence *float64              `json:"confidence,omitempty"`
+	Locked     bool                  `json:"locked"`
+	Candidates []ProvenanceCandidate `json:"candidates"`
+
+	// SourcePriority is the order sources are preferred in for the field;
+	// candidates are listed in the order the merge considers them
+	SourcePriority []string `json:"source_priority"`
+}
+
+// ProvenanceCandidate is a value an enrichment source offered for a field
+type ProvenanceCandidate struct {
+	Source     string      `json:"source"`
+	Value      interface{} `json:"value"`
+	Confidence float64     `json:"confidence"`
+	Priority   int         `json:"priority"` // Lower number = higher priority
+	UpdatedAt  time.Time   `json:"updated_at"`
+	Applied    bool        `json:"applied"`
+	Disabled   bool        `json:"disabled"` // The source is disabled, so the merge skips it
+}
+
+// recordProvenance notes that source set a field, keeping the field's lock
+func (m *Module) recordProvenance(mediaID string, mediaType database.MediaType, field, source, value string, confidence float64) error {
+	var provenance database.MediaFieldProvenance
+	err := m.db.Where("media_id = ? AND field = ?", mediaID, field).First(&provenance).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+
+	provenance.MediaID = mediaID
+	provenance.Field = field
+	provenance.MediaType = mediaType
+	provenance.Source = source
+	provenance.Value = value
+	provenance.Confidence = confidence
+	provenance.SetAt = time.Now()
+	return m.db.Save(&provenance).Error
+}
+
+// lockedFields returns the fields of a media item that enrichment must not change
+func (m *Module) lockedFields(mediaID string) (map[string]bool, error) {
+	var fields []string
+	if err := m.db.Model(&database.MediaFieldProvenance{}).
+		Where("media_id = ? AND locked = ?", mediaID, true).
+		Pluck("field", &fields).Error; err != nil {
+		return nil, err
+	}
+
+	locked := make(map[string]bool, len(fields))
+	for _, field := range fields {
+		locked[field] = true
+	}
+	return locked, nil
+}
+
+// SetFieldLock locks a field of a media item against enrichment, or unlocks it.
+// id is a media file ID or the ID of the movie, episode or track it holds.
+func (m *Module) SetFieldLock(id, field string, locked bool) (*database.MediaFieldProvenance, error) {
+	mediaFile, err := m.findProvenanceMedia(id)
+	if err != nil {
+		return nil, err
+	}
+
+	var provenance database.MediaFieldProvenance
+	err = m.db.Where("media_id = ? AND field = ?", mediaFile.MediaID, field).First(&provenance).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	provenance.MediaID = mediaFile.MediaID
+	provenance.Field = field
+	provenance.MediaType = mediaFile.MediaType
+	provenance.Locked = locked
+	if err := m.db.Save(&provenance).Error; err != nil {
+		return nil, err
+	}
+	return &provenance, nil
+}
+
+// GetProvenance reports, for every field shown for a media item, which source
+// set it, when, with what confidence and whether it is locked, along with
+// the values each enrichment source offered. id is a media file ID or the ID
+// of the movie, episode or track it holds.
+func (m *Module) GetProvenance(id string) (*MediaProvenance, error) {
+	mediaFile, err := m.findProvenanceMedia(id)
+	if err != nil {
+		return nil, err
+	}
+
+	var records []database.MediaFieldProvenance
+	if err := m.db.Where("media_id = ?", mediaFile.MediaID).Find(&records).Error; err != nil {
+		return nil, fmt.Errorf("failed to fetch provenance: %w", err)
+	}
+	var enrichments []database.MediaEnrichment
+	if err := m.db.Where("media_id = ? AND media_type = ?", mediaFile.MediaID, mediaFile.MediaType).
+		Find(&enrichments).Error; err != nil {
+		return nil, fmt.Errorf("failed to fetch enrichments: %w", err)
+	}
+	policy, err := m.loadMergePolicy(string(mediaFile.MediaType))
+	if err != nil {
+		return nil, err
+	}
+
+	fields := make(map[string]*FieldProvenance)
+	field := func(name string) *FieldProvenance {
+		if fields[name] == nil {
+			fields[name] = &FieldProvenance{
+				Field:      name,
+				Source:     ProvenanceSourceScanner,
+				Candidates: []ProvenanceCandidate{},
+			}
+		}
+		return fields[name]
+	}
+
+	// Every field enrichment can set for this media type is displayed
+	for name, rule := range m.GetFieldRules() {
+		if m.supportsMediaType(rule.MediaTypes, string(mediaFile.MediaType)) {
+			field(name)
+		}
+	}
+
+	for _, record := range records {
+		f := field(record.Field)
+		f.Locked = record.Locked
+		if record.Source == "" {
+			continue // Locked before any source set it
+		}
+		setAt, confidence := record.SetAt, record.Confidence
+		f.Value = record.Value
+		f.Source = record.Source
+		f.SetAt = &setAt
+		f.Confidence = &confidence
+	}
+
+	for _, enrichment := range enrichments {
+		var data EnrichmentData
+		if err := json.Unmarshal([]byte(enrichment.Payload), &data); err != nil {
+			log.Printf("WARN: Failed to parse enrichment payload from %s: %v", enrichment.Plugin, err)
+			continue
+		}
+		for name, value := range data.Fields {
+			f := field(name)
+			f.Candidates = append(f.Candidates, ProvenanceCandidate{
+				Source:     enrichment.Plugin,
+				Value:      value,
+				Confidence: data.ConfidenceScore,
+				Priority:   policy.priority(m, enrichment.Plugin),
+				UpdatedAt:  enrichment.UpdatedAt,
+				Applied:    f.Source == enrichment.Plugin,
+				Disabled:   policy.disabled[enrichment.Plugin],
+			})
+		}
+	}
+
+	result := &MediaProvenance{
+		MediaFileID: mediaFile.ID,
+		MediaID:     mediaFile.MediaID,
+		MediaType:   string(mediaFile.MediaType),
+		Fields:      make([]FieldProvenance, 0, len(fields)),
+	}
+	for _, f := range fields {
+		f.SourcePriority = policy.order(f.Field)
+		if f.SourcePriority == nil {
+			f.SourcePriority = []string{}
+		}
+		rank := policy.rank(f.Field)
+		sort.SliceStable(f.Candidates, func(i, j int) bool {
+			a, b := f.Candidates[i], f.Candidates[j]
+			return preferred(rank,
+				rankedSource{a.Source, a.Priority, a.Confidence},
+				rankedSource{b.Source, b.Priority, b.Confidence})
+		})
+		result.Fields = append(result.Fields, *f)
+	}
+	sort.Slice(result.Fields, func(i, j int) bool {
+		return result.Fields[i].Field < result.Fields[j].Field
+	})
+	return result, nil
+}
+
+// findProvenanceMedia finds the media file with the ID, or the first file of
+// the media item with it
+func (m *Module) findProvenanceMedia(id string) (*database.MediaFile, error) {
+	var mediaFile database.MediaFile
+	err := m.db.Where("id = ?", id).First(&mediaFile).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		err = m.db.Where("media_id = ?", id).Order("id").First(&mediaFile).Error
+	}
+	if errors.Is(err, gorm.ErrRecordNotFound) || (err == nil && mediaFile.MediaID == "") {
+		return nil, ErrMediaNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &mediaFile, nil
+}
@@ -0,0 +1,273 @@
+package enrichmentmodule
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+
+	"github.com/mantonx/viewra/internal/modules/pluginmodule"
+	plugins "github.com/mantonx/viewra/sdk"
+	"github.com/mantonx/viewra/sdk/namingparser"
+)
+
+// Simulation outcomes of a file name
+const (
+	SimulationMatched   = "matched"   // Scored at least the auto threshold
+	SimulationReview    = "review"    // Scored between the review and auto thresholds
+	SimulationUnmatched = "unmatched" // Scored below the review threshold, or had no candidates
+	SimulationError     = "error"     // The provider search failed
+)
+
+// Reasons a simulated file wasn't matched automatically
+const (
+	SimulationFailureNoTitle     = "no_title"     // No title could be read from the name
+	SimulationFailureNoResults   = "no_results"   // The search found nothing
+	SimulationFailureLowScore    = "low_score"    // The best candidate scored below the auto threshold
+	SimulationFailureSearchError = "search_error" // The search failed
+)
+
+// ErrInvalidSimulation is returned for simulation requests that can't be run
+var ErrInvalidSimulation = errors.New("invalid simulation request")
+
+// maxSimulationFiles bounds the file names one simulation searches for, as
+// each is a provider search
+const maxSimulationFiles = 1000
+
+// simulationExamples is how many file names are kept per failure pattern
+const simulationExamples = 5
+
+// SimulationRequest is a list of file names to run a plugin's matching
+// against. The thresholds default to the TMDb enricher's defaults. With a
+// library, its naming rules rewrite the names first, as they would in a scan.
+type SimulationRequest struct {
+	PluginID        string   `json:"plugin_id" binding:"required"`
+	Files           []string `json:"files" binding:"required"` // File names or paths; nothing is read from disk
+	MediaType       string   `json:"media_type"`               // movie or tv, when the library holds one type
+	LibraryID       uint32   `json:"library_id"`
+	AutoThreshold   float64  `json:"auto_threshold"`
+	ReviewThreshold float64  `json:"review_threshold"`
+}
+
+// SimulatedFile is how one file name would be matched
+type SimulatedFile struct {
+	File        string   `json:"file"`
+	MatchPath   string   `json:"match_path,omitempty"` // The name after the library's naming rules, when they changed it
+	ParsedTitle string   `json:"parsed_title,omitempty"`
+	ParsedYear  int      `json:"parsed_year,omitempty"`
+	Kind        string   `json:"kind,omitempty"` // movie or episode, from the name's pattern
+	Outcome     string   `json:"outcome"`
+	Failure     string   `json:"failure,omitempty"`
+	Hints       []string `json:"hints,omitempty"` // Naming patterns that tend to hurt matching
+	MatchID     string   `json:"match_id,omitempty"`
+	MatchTitle  string   `json:"match_title,omitempty"`
+	Score       float64  `json:"score"`
+	Error       string   `json:"error,omitempty"`
+}
+
+// SimulationPattern counts the files sharing a failure reason or naming hint
+type SimulationPattern struct {
+	Count    int      `json:"count"`
+	Examples []string `json:"examples"`
+}
+
+// SimulationReport summarizes how a plugin would match a list of file names
+type SimulationReport struct {
+	PluginID        string                        `json:"plugin_id"`
+	AutoThreshold   float64                       `json:"auto_threshold"`
+	ReviewThreshold float64                       `json:"review_threshold"`
+	Total           int                           `json:"total"`
+	Matched         int                           `json:"matched"`
+	Review          int                           `json:"review"`
+	Unmatched       int                           `json:"unmatched"`
+	Errors          int                           `json:"errors"`
+	MatchRate       float64                       `json:"match_rate"`    // Share matched automatically
+	ReviewRate      float64                       `json:"review_rate"`   // Share queued for review
+	ScoreBuckets    []int                         `json:"score_buckets"` // Best scores in tenths: [0] counts 0-0.1, [9] 0.9 and above
+	Failures        map[string]*SimulationPattern `json:"failures"`      // By failure reason
+	NamingHints     map[string]*SimulationPattern `json:"naming_hints"`  // Among files not matched automatically
+	Files           []SimulatedFile               `json:"files"`
+}
+
+// SimulateMatching runs a plugin's matching against a list of file names,
+// searching its provider for each as a scan would, and reports how many
+// would match. Nothing is written: no enrichment is saved and no hook
+// results are recorded, so thresholds and naming can be tuned before a
+// real scan.
+func (m *Module) SimulateMatching(ctx context.Context, req SimulationRequest) (*SimulationReport, error) {
+	if len(req.Files) > maxSimulationFiles {
+		return nil, fmt.Errorf("%w: at most %d files can be simulated at once", ErrInvalidSimulation, maxSimulationFiles)
+	}
+	if req.AutoThreshold == 0 {
+		req.AutoThreshold = 0.85
+	}
+	if req.ReviewThreshold == 0 {
+		req.ReviewThreshold = 0.6
+	}
+	if req.ReviewThreshold > req.AutoThreshold {
+		return nil, fmt.Errorf("%w: review threshold must not exceed the auto threshold", ErrInvalidSimulation)
+	}
+
+	extMgr, err := m.externalPlugins()
+	if err != nil {
+		return nil, err
+	}
+	if !extMgr.IsPluginRunning(req.PluginID) {
+		return nil, pluginmodule.ErrPluginNotRunning
+	}
+
+	rewrites, err := pluginmodule.LibraryNamingRewrites(m.db, req.LibraryID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load naming rules: %w", err)
+	}
+
+	report := &SimulationReport{
+		PluginID:        req.PluginID,
+		AutoThreshold:   req.AutoThreshold,
+		ReviewThreshold: req.ReviewThreshold,
+		ScoreBuckets:    make([]int, 10),
+		Failures:        make(map[string]*SimulationPattern),
+		NamingHints:     make(map[string]*SimulationPattern),
+		Files:           make([]SimulatedFile, 0, len(req.Files)),
+	}
+
+	for _, file := range req.Files {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		result := simulateFile(ctx, extMgr, req, file, rewrites)
+		switch result.Outcome {
+		case SimulationMatched:
+			report.Matched++
+		case SimulationReview:
+			report.Review++
+		case SimulationUnmatched:
+			report.Unmatched++
+		case SimulationError:
+			report.Errors++
+		}
+		if result.MatchID != "" {
+			bucket := int(result.Score * 10)
+			if bucket > 9 {
+				bucket = 9
+			}
+			if bucket < 0 {
+				bucket = 0
+			}
+			report.ScoreBuckets[bucket]++
+		}
+		if result.Failure != "" {
+			addSimulationPattern(report.Failures, result.Failure, file)
+		}
+		if result.Outcome != SimulationMatched {
+			for _, hint := range result.Hints {
+				addSimulationPattern(report.NamingHints, hint, file)
+			}
+		}
+		report.Files = append(report.Files, result)
+	}
+
+	report.Total = len(report.Files)
+	if report.Total > 0 {
+		report.MatchRate = float64(report.Matched) / float64(report.Total)
+		report.ReviewRate = float64(report.Review) / float64(report.Total)
+	}
+	return report, nil
+}
+
+// simulateFile searches a plugin's provider for one file name, rewritten by
+// the library's naming rules, and scores the best candidate against the
+// thresholds
+func simulateFile(ctx context.Context, extMgr *pluginmodule.ExternalPluginManager, req SimulationRequest, file string, rewrites []namingparser.Rewrite) SimulatedFile {
+	matchPath := namingparser.ApplyRewrites(file, rewrites)
+	parsed := namingparser.Parse(matchPath)
+	result := SimulatedFile{
+		File:        file,
+		ParsedTitle: parsed.Title,
+		ParsedYear:  parsed.Year,
+		Kind:        string(parsed.Kind),
+		Hints:       namingHints(matchPath, parsed),
+	}
+	if matchPath != file {
+		result.MatchPath = matchPath
+	}
+	if parsed.Title == "" {
+		result.Outcome = SimulationUnmatched
+		result.Failure = SimulationFailureNoTitle
+		return result
+	}
+
+	query := map[string]string{plugins.SearchQueryFilePath: matchPath}
+	if req.MediaType != "" {
+		query["media_type"] = req.MediaType
+	}
+	resp, err := extMgr.SearchPlugin(ctx, req.PluginID, query, 20, 0)
+	if err != nil {
+		result.Outcome, result.Failure, result.Error = SimulationError, SimulationFailureSearchError, err.Error()
+		return result
+	}
+	if len(resp.Results) == 0 {
+		result.Outcome = SimulationUnmatched
+		result.Failure = SimulationFailureNoResults
+		return result
+	}
+
+	best := resp.Results[0]
+	for _, candidate := range resp.Results[1:] {
+		if candidate.Score > best.Score {
+			best = candidate
+		}
+	}
+	result.MatchID = best.Id
+	result.MatchTitle = best.Title
+	result.Score = best.Score
+
+	switch {
+	case best.Score >= req.AutoThreshold:
+		result.Outcome = SimulationMatched
+	case best.Score >= req.ReviewThreshold:
+		result.Outcome = SimulationReview
+		result.Failure = SimulationFailureLowScore
+	default:
+		result.Outcome = SimulationUnmatched
+		result.Failure = SimulationFailureLowScore
+	}
+	return result
+}
+
+// namingHints lists the naming patterns of a file name that tend to cost
+// matches, for users to fix before a scan
+func namingHints(file string, parsed namingparser.Result) []string {
+	var hints []string
+	if parsed.Kind == namingparser.KindUnknown {
+		hints = append(hints, "no_year_or_episode") // Neither movie nor episode naming
+	}
+	if parsed.Kind == namingparser.KindMovie && parsed.Year == 0 {
+		hints = append(hints, "missing_year")
+	}
+	if parsed.Kind == namingparser.KindEpisode && len(parsed.Episodes) == 0 && len(parsed.AbsoluteEpisodes) > 0 {
+		hints = append(hints, "absolute_numbering")
+	}
+	if parsed.Kind == namingparser.KindEpisode && filepath.Dir(file) == "." {
+		hints = append(hints, "no_show_folder") // Only the name says which show it is
+	}
+	if parsed.ReleaseGroup != "" || len(parsed.Tokens) > 0 {
+		hints = append(hints, "release_tags")
+	}
+	return hints
+}
+
+// addSimulationPattern counts a file towards a pattern, keeping the first
+// few file names as examples
+func addSimulationPattern(patterns map[string]*SimulationPattern, key, file string) {
+	pattern, ok := patterns[key]
+	if !ok {
+		pattern = &SimulationPattern{Examples: []string{}}
+		patterns[key] = pattern
+	}
+	pattern.Count++
+	if len(pattern.Examples) < simulationExamples {
+		pattern.Examples = append(pattern.Examples, file)
+	}
+}
@@ -0,0 +1,37 @@
+package enrichmentmodule
+
+import (
+	"context"
+	"sync"
+
+	plugins "github.com/mantonx/viewra/sdk"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// WatchHistoryGRPCServer serves the host's WatchHistoryService to plugins.
+// Watch history belongs to the playback module, which is connected once the
+// modules are running; until then calls are answered Unavailable.
+type WatchHistoryGRPCServer struct {
+	mu   sync.RWMutex
+	impl plugins.WatchHistoryService
+}
+
+// SetService connects the service that records imported history
+func (s *WatchHistoryGRPCServer) SetService(impl plugins.WatchHistoryService) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.impl = impl
+}
+
+// ImportHistory implements plugins.WatchHistoryService
+func (s *WatchHistoryGRPCServer) ImportHistory(ctx context.Context, req *plugins.WatchHistoryImportRequest) (*plugins.WatchHistoryImportResponse, error) {
+	s.mu.RLock()
+	impl := s.impl
+	s.mu.RUnlock()
+
+	if impl == nil {
+		return nil, status.Error(codes.Unavailable, "watch history is not available")
+	}
+	return impl.ImportHistory(ctx, req)
+}
@@ -0,0 +1,150 @@
+package mediamodule
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mantonx/viewra/internal/database"
+	"github.com/mantonx/viewra/internal/utils"
+	"gorm.io/gorm"
+)
+
+// trackVersion is the audio quality of one file of a track, letting clients
+// tell apart e.g. a 24/96 FLAC from an MP3 of the same recording
+type trackVersion struct {
+	MediaFileID string `json:"media_file_id"`
+	Container   string `json:"container"`
+	Codec       string `json:"codec"`
+	SampleRate  int    `json:"sample_rate"`
+	BitDepth    int    `json:"bit_depth,omitempty"`
+	BitrateKbps int    `json:"bitrate_kbps"`
+	Channels    int    `json:"channels,omitempty"`
+	Lossless    bool   `json:"lossless"`
+	Badge       string `json:"badge,omitempty"` // hi-res, lossless or lossy
+}
+
+// trackWithVersions is a track with the audio quality of each of its files
+type trackWithVersions struct {
+	database.Track
+	Versions []trackVersion `json:"versions"`
+}
+
+// newTrackVersion describes the audio quality of a media file
+func newTrackVersion(mediaFile *database.MediaFile) trackVersion {
+	return trackVersion{
+		MediaFileID: mediaFile.ID,
+		Container:   mediaFile.Container,
+		Codec:       mediaFile.AudioCodec,
+		SampleRate:  mediaFile.SampleRate,
+		BitDepth:    mediaFile.AudioBitDepth,
+		BitrateKbps: mediaFile.BitrateKbps,
+		Channels:    mediaFile.AudioChannels,
+		Lossless:    mediaFile.AudioLossless,
+		Badge:       utils.AudioQualityBadge(mediaFile.AudioCodec, mediaFile.SampleRate, mediaFile.AudioBitDepth),
+	}
+}
+
+// withVersions attaches the files of each track, best quality first
+func (m *Module) withVersions(tracks []database.Track) ([]trackWithVersions, error) {
+	ids := make([]string, len(tracks))
+	for i, track := range tracks {
+		ids[i] = track.ID
+	}
+
+	var mediaFiles []database.MediaFile
+	if len(ids) > 0 {
+		if err := m.db.Where("media_id IN ? AND media_type = ?", ids, database.MediaTypeTrack).
+			Order("audio_lossless DESC, sample_rate DESC, audio_bit_depth DESC, bitrate_kbps DESC").
+			Find(&mediaFiles).Error; err != nil {
+			return nil, err
+		}
+	}
+
+	versions := make(map[string][]trackVersion)
+	for i := range mediaFiles {
+		versions[mediaFiles[i].MediaID] = append(versions[mediaFiles[i].MediaID], newTrackVersion(&mediaFiles[i]))
+	}
+
+	result := make([]trackWithVersions, len(tracks))
+	for i, track := range tracks {
+		result[i] = trackWithVersions{Track: track, Versions: versions[track.ID]}
+		if result[i].Versions == nil {
+			result[i].Versions = []trackVersion{}
+		}
+	}
+	return result, nil
+}
+
+// getTracks lists tracks that have a file matching the audio quality filters
+// (lossless, hi_res, codec, min_sample_rate, min_bit_depth, min_bitrate), with
+// the quality of each file
+func (m *Module) getTracks(c *gin.Context) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	if err != nil || limit < 1 || limit > 1000 {
+		limit = 50
+	}
+	offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if err != nil || offset < 0 {
+		offset = 0
+	}
+
+	filter, err := utils.ParseAudioQualityFilter(c.Query)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	// Leave out what the user in user_id has hidden
+	visibility, ok := m.visibilityFor(c)
+	if !ok {
+		return
+	}
+
+	matching := filter.Apply(m.db.Table("media_files").Select("media_files.media_id").
+		Where("media_files.media_type = ?", database.MediaTypeTrack), "media_files")
+	query := func() *gorm.DB {
+		query := m.db.Model(&database.Track{}).Where("id IN (?)", matching)
+		if visibility != nil {
+			query = visibility.Tracks(query, "tracks")
+		}
+		return query
+	}
+
+	var total int64
+	if err := query().Count(&total).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": fmt.Sprintf("Failed to count tracks: %v", err),
+		})
+		return
+	}
+
+	var tracks []database.Track
+	if err := query().Preload("Artist").Preload("Album").
+		Order("album_id, disc_number, track_number, title").
+		Limit(limit).Offset(offset).Find(&tracks).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": fmt.Sprintf("Failed to get tracks: %v", err),
+		})
+		return
+	}
+
+	result, err := m.withVersions(tracks)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": fmt.Sprintf("Failed to get track files: %v", err),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"tracks": result,
+		"total":  total,
+		"count":  len(result),
+		"limit":  limit,
+		"offset": offset,
+	})
+}
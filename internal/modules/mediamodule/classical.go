@@ -0,0 +1,111 @@
+package mediamodule
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mantonx/viewra/internal/database"
+)
+
+// composerSummary is one composer in the composer list
+type composerSummary struct {
+	Name       string `json:"name"`
+	WorkCount  int64  `json:"work_count"`
+	TrackCount int64  `json:"track_count"`
+}
+
+// composerWork is a work with its recorded movements in order
+type composerWork struct {
+	Title    string           `json:"title"`
+	WorkMBID string           `json:"work_mbid,omitempty"`
+	Tracks   []database.Track `json:"tracks"`
+}
+
+// getComposers lists composers with the number of works and tracks of each,
+// optionally filtered by name (?q=). Tracks the user in user_id has hidden
+// are not counted.
+func (m *Module) getComposers(c *gin.Context) {
+	visibility, ok := m.visibilityFor(c)
+	if !ok {
+		return
+	}
+
+	query := m.db.Model(&database.Track{}).
+		Select("composer AS name, COUNT(DISTINCT NULLIF(work, '')) AS work_count, COUNT(*) AS track_count").
+		Where("composer <> ''")
+	if q := c.Query("q"); q != "" {
+		query = query.Where("LOWER(composer) LIKE LOWER(?)", "%"+q+"%")
+	}
+	if visibility != nil {
+		query = visibility.Tracks(query, "tracks")
+	}
+
+	var composers []composerSummary
+	if err := query.Group("composer").Order("composer").Scan(&composers).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": fmt.Sprintf("Failed to get composers: %v", err),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"composers": composers,
+		"count":     len(composers),
+	})
+}
+
+// getComposerWorks returns a composer's works, each with its tracks ordered
+// by movement. Tracks without a work tag are listed under "other_tracks".
+func (m *Module) getComposerWorks(c *gin.Context) {
+	composer := c.Param("name")
+	visibility, ok := m.visibilityFor(c)
+	if !ok {
+		return
+	}
+
+	query := m.db.Model(&database.Track{})
+	if visibility != nil {
+		query = visibility.Tracks(query, "tracks")
+	}
+
+	var tracks []database.Track
+	if err := query.Preload("Album").Preload("Artist").
+		Where("composer = ?", composer).
+		Order("work, movement_number, disc_number, track_number, title").
+		Find(&tracks).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": fmt.Sprintf("Failed to get works: %v", err),
+		})
+		return
+	}
+	if len(tracks) == 0 {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Composer not found",
+		})
+		return
+	}
+
+	works := []composerWork{}
+	other := []database.Track{}
+	for _, track := range tracks {
+		if track.Work == "" {
+			other = append(other, track)
+			continue
+		}
+		if len(works) == 0 || works[len(works)-1].Title != track.Work {
+			works = append(works, composerWork{Title: track.Work})
+		}
+		work := &works[len(works)-1]
+		if work.WorkMBID == "" {
+			work.WorkMBID = track.WorkMBID
+		}
+		work.Tracks = append(work.Tracks, track)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"composer":     composer,
+		"works":        works,
+		"other_tracks": other,
+	})
+}
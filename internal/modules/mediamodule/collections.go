@@ -0,0 +1,171 @@
+package mediamodule
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/mantonx/viewra/internal/database"
+	"github.com/mantonx/viewra/internal/modules/assetmodule"
+	"gorm.io/gorm"
+)
+
+// collectionSummary is a collection in the collections list, with how many
+// of its movies are in the library
+type collectionSummary struct {
+	database.Collection
+	MovieCount int `json:"movie_count"`
+}
+
+// collectionMovie is a movie of a collection
+type collectionMovie struct {
+	ID          string     `json:"id"`
+	Title       string     `json:"title"`
+	ReleaseDate *time.Time `json:"release_date"`
+	Poster      string     `json:"poster"`
+	TmdbRating  float64    `json:"tmdb_rating"`
+}
+
+// collectionItems selects the items of the collections that the user in
+// user_id may see. Collections whose movies are all hidden from the user
+// have no items.
+func (m *Module) collectionItems(visibility *userVisibility) *gorm.DB {
+	query := m.db.Model(&database.CollectionItem{})
+	if visibility != nil {
+		query = visibility.Movies(query, "collection_items.movie_id")
+	}
+	return query
+}
+
+// getCollections lists the collections with movies in the library, by name,
+// optionally searching their names (?search=)
+func (m *Module) getCollections(c *gin.Context) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	if err != nil || limit < 1 || limit > 1000 {
+		limit = 50
+	}
+	offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if err != nil || offset < 0 {
+		offset = 0
+	}
+	visibility, ok := m.visibilityFor(c)
+	if !ok {
+		return
+	}
+
+	counts := m.collectionItems(visibility).
+		Select("collection_id, COUNT(*) AS movie_count").
+		Group("collection_id")
+	query := m.db.Table("collection").
+		Joins("JOIN (?) AS owned ON owned.collection_id = collection.id", counts)
+	if search := strings.TrimSpace(c.Query("search")); search != "" {
+		query = query.Where("LOWER(collection.name) LIKE ?", "%"+strings.ToLower(search)+"%")
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": fmt.Sprintf("Failed to count collections: %v", err),
+		})
+		return
+	}
+
+	collections := []collectionSummary{}
+	if err := query.Select("collection.*, owned.movie_count").
+		Order("collection.name").Limit(limit).Offset(offset).
+		Scan(&collections).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": fmt.Sprintf("Failed to get collections: %v", err),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"collections": collections,
+		"total":       total,
+		"count":       len(collections),
+		"limit":       limit,
+		"offset":      offset,
+	})
+}
+
+// getCollection returns a collection and its movies in the library, in
+// release order
+func (m *Module) getCollection(c *gin.Context) {
+	visibility, ok := m.visibilityFor(c)
+	if !ok {
+		return
+	}
+
+	var collection database.Collection
+	if err := m.db.Where("id = ?", c.Param("id")).First(&collection).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Collection not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": fmt.Sprintf("Failed to get collection: %v", err),
+		})
+		return
+	}
+
+	movieIDs := m.collectionItems(visibility).Select("movie_id").Where("collection_id = ?", collection.ID)
+	movies := []collectionMovie{}
+	if err := m.db.Model(&database.Movie{}).
+		Select("id, title, release_date, poster, tmdb_rating").
+		Where("id IN (?)", movieIDs).
+		Order("release_date, title").
+		Scan(&movies).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": fmt.Sprintf("Failed to get collection movies: %v", err),
+		})
+		return
+	}
+	// A collection the user can see none of is as good as missing
+	if len(movies) == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Collection not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"collection": collectionSummary{Collection: collection, MovieCount: len(movies)},
+		"movies":     movies,
+	})
+}
+
+// getCollectionPoster serves the poster saved for a collection
+func (m *Module) getCollectionPoster(c *gin.Context) {
+	collectionID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid collection ID"})
+		return
+	}
+	assets := assetmodule.GetAssetManager()
+	if assets == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Asset manager not available"})
+		return
+	}
+
+	asset, err := assets.GetPreferredAsset(assetmodule.EntityTypeCollection, collectionID, assetmodule.AssetTypeCover)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "No poster found for collection"})
+		return
+	}
+	quality := 0 // Original quality
+	if q, err := strconv.Atoi(c.Query("quality")); err == nil && q > 0 && q <= 100 {
+		quality = q
+	}
+	data, format, err := assets.GetAssetDataWithQuality(asset.ID, quality)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Poster data not found"})
+		return
+	}
+
+	c.Header("Cache-Control", "public, max-age=86400")
+	c.Data(http.StatusOK, format, data)
+}
@@ -40,6 +40,7 @@ type CleanupStats struct {
 	SeasonsDeleted         int64 `json:"seasons_deleted"`
 	TVShowsDeleted         int64 `json:"tv_shows_deleted"`
 	MoviesDeleted          int64 `json:"movies_deleted"`
+	HomeVideosDeleted      int64 `json:"home_videos_deleted"`
 	AssetsDeleted          int64 `json:"assets_deleted"`
 	EnrichmentsDeleted     int64 `json:"enrichments_deleted"`
 	ExternalIDsDeleted     int64 `json:"external_ids_deleted"`
@@ -137,6 +138,13 @@ func (lds *LibraryDeletionService) DeleteLibrary(libraryID uint32) *DeletionResu
 		// Don't fail the whole operation for orphaned cleanup issues
 	}
 
+	// Storage history and upgrade settings mean nothing without the library
+	for _, model := range []interface{}{&database.LibraryStorageSample{}, &database.LibraryQualityTarget{}, &database.UpgradeWanted{}} {
+		if err := lds.db.Where("library_id = ?", libraryID).Delete(model).Error; err != nil {
+			logger.Warn("Failed to delete library settings", "error", err)
+		}
+	}
+
 	// Step 6: Delete the library record itself
 	if err := lds.db.Delete(&library).Error; err != nil {
 		result.Error = fmt.Errorf("failed to delete library record: %w", err)
@@ -342,6 +350,11 @@ func (lds *LibraryDeletionService) cleanupMetadata(libraryID uint32, mediaFileID
 			logger.Info("Deleted movie assets", "count", movieAssetResult.RowsAffected)
 		}
 
+		// Take the movies out of their collections
+		if itemResult := lds.db.Where("movie_id IN ?", movieIDs).Delete(&database.CollectionItem{}); itemResult.Error != nil {
+			logger.Warn("Failed to delete collection items", "error", itemResult.Error)
+		}
+
 		// Delete movies
 		if movieResult := lds.db.Where("id IN ?", movieIDs).Delete(&database.Movie{}); movieResult.Error != nil {
 			logger.Warn("Failed to delete movies", "error", movieResult.Error)
@@ -353,6 +366,26 @@ func (lds *LibraryDeletionService) cleanupMetadata(libraryID uint32, mediaFileID
 		}
 	}
 
+	// Delete home videos and their thumbnails
+	var homeVideoIDs []string
+	if err := lds.db.Model(&database.HomeVideo{}).Where("library_id = ?", libraryID).Pluck("id", &homeVideoIDs).Error; err != nil {
+		logger.Warn("Failed to get home video IDs", "error", err)
+	} else if len(homeVideoIDs) > 0 {
+		logger.Info("Cleaning up home videos", "count", len(homeVideoIDs))
+
+		if assetResult := lds.db.Where("entity_id IN ? AND entity_type = ?", homeVideoIDs, "home_video").Delete(&database.MediaAsset{}); assetResult.Error != nil {
+			logger.Warn("Failed to delete home video assets", "error", assetResult.Error)
+		} else {
+			stats.AssetsDeleted += assetResult.RowsAffected
+		}
+
+		if result := lds.db.Where("id IN ?", homeVideoIDs).Delete(&database.HomeVideo{}); result.Error != nil {
+			logger.Warn("Failed to delete home videos", "error", result.Error)
+		} else {
+			stats.HomeVideosDeleted = result.RowsAffected
+		}
+	}
+
 	// Cleanup orphaned people (people with no remaining roles)
 	lds.cleanupOrphanedPeople(stats)
 
@@ -464,7 +497,26 @@ func (lds *LibraryDeletionService) cleanupMusicHierarchy(trackIDs []string, stat
 		}
 	}
 
+	// Box sets have no tracks of their own; they go once their last album does
 	if len(albumsToDelete) > 0 {
+		var boxSetIDs []string
+		if err := lds.db.Model(&database.Album{}).Where("id IN ? AND parent_album_id IS NOT NULL", albumsToDelete).
+			Distinct().Pluck("parent_album_id", &boxSetIDs).Error; err != nil {
+			logger.Warn("Failed to get box set IDs", "error", err)
+		}
+		for _, boxSetID := range boxSetIDs {
+			var remainingAlbums int64
+			if err := lds.db.Model(&database.Album{}).Where("parent_album_id = ? AND id NOT IN ?", boxSetID, albumsToDelete).
+				Count(&remainingAlbums).Error; err == nil && remainingAlbums == 0 {
+				albumsToDelete = append(albumsToDelete, boxSetID)
+			}
+		}
+	}
+
+	if len(albumsToDelete) > 0 {
+		if err := lds.db.Where("album_id IN ?", albumsToDelete).Delete(&database.AlbumLabel{}).Error; err != nil {
+			logger.Warn("Failed to delete album labels", "error", err)
+		}
 		if albumResult := lds.db.Where("id IN ?", albumsToDelete).Delete(&database.Album{}); albumResult.Error != nil {
 			logger.Warn("Failed to delete orphaned albums", "error", albumResult.Error)
 		} else {
@@ -484,6 +536,14 @@ func (lds *LibraryDeletionService) cleanupMusicHierarchy(trackIDs []string, stat
 	}
 
 	if len(artistsToDelete) > 0 {
+		if err := lds.db.Where("artist_id IN ?", artistsToDelete).Delete(&database.ArtistRelationship{}).Error; err != nil {
+			logger.Warn("Failed to delete artist relationships", "error", err)
+		}
+		// Relationships of other artists keep the MusicBrainz target, just not the local link
+		if err := lds.db.Model(&database.ArtistRelationship{}).Where("target_artist_id IN ?", artistsToDelete).
+			Update("target_artist_id", nil).Error; err != nil {
+			logger.Warn("Failed to unlink artist relationships", "error", err)
+		}
 		if artistResult := lds.db.Where("id IN ?", artistsToDelete).Delete(&database.Artist{}); artistResult.Error != nil {
 			logger.Warn("Failed to delete orphaned artists", "error", artistResult.Error)
 		} else {
@@ -496,7 +556,7 @@ func (lds *LibraryDeletionService) cleanupMusicHierarchy(trackIDs []string, stat
 func (lds *LibraryDeletionService) cleanupOrphanedPeople(stats *CleanupStats) {
 	var orphanedPeople []string
 	if err := lds.db.Raw(`
-		SELECT p.id FROM people p 
+		SELECT p.id FROM peoples p 
 		LEFT JOIN roles r ON p.id = r.person_id 
 		WHERE r.person_id IS NULL
 	`).Pluck("id", &orphanedPeople).Error; err != nil {
@@ -0,0 +1,38 @@
+package mediamodule
+
+import (
+	"log"
+
+	"gorm.io/gorm"
+)
+
+// entityConstraints are the unique keys of library items. Scanners and the
+// host's media entity service insert with ON CONFLICT DO NOTHING and reload
+// the existing row, so two writers racing on a new show, season, episode,
+// artist or album end up sharing one row instead of creating two. External ID keys are
+// partial because items without an ID keep an empty string.
+var entityConstraints = []struct {
+	name string
+	sql  string
+}{
+	{"idx_tv_shows_tmdb_id_unique", "CREATE UNIQUE INDEX IF NOT EXISTS idx_tv_shows_tmdb_id_unique ON tv_shows(tmdb_id) WHERE tmdb_id <> ''"},
+	{"idx_movies_tmdb_id_unique", "CREATE UNIQUE INDEX IF NOT EXISTS idx_movies_tmdb_id_unique ON movies(tmdb_id) WHERE tmdb_id <> ''"},
+	{"idx_movies_imdb_id_unique", "CREATE UNIQUE INDEX IF NOT EXISTS idx_movies_imdb_id_unique ON movies(imdb_id) WHERE imdb_id <> ''"},
+	{"idx_seasons_show_number_unique", "CREATE UNIQUE INDEX IF NOT EXISTS idx_seasons_show_number_unique ON seasons(tv_show_id, season_number)"},
+	{"idx_episodes_season_number_unique", "CREATE UNIQUE INDEX IF NOT EXISTS idx_episodes_season_number_unique ON episodes(season_id, episode_number)"},
+	{"idx_artists_name_unique", "CREATE UNIQUE INDEX IF NOT EXISTS idx_artists_name_unique ON artists(name)"},
+	{"idx_albums_title_artist_unique", "CREATE UNIQUE INDEX IF NOT EXISTS idx_albums_title_artist_unique ON albums(title, artist_id)"},
+	{"idx_media_external_ids_source_unique", "CREATE UNIQUE INDEX IF NOT EXISTS idx_media_external_ids_source_unique ON media_external_ids(media_id, media_type, source)"},
+}
+
+// createEntityConstraints adds the unique keys of library items. A key can't
+// be added while the table holds duplicates from before it existed; that is
+// logged rather than failing the migration, and upserts then behave as they
+// did without it until the duplicates are merged.
+func createEntityConstraints(db *gorm.DB) {
+	for _, constraint := range entityConstraints {
+		if err := db.Exec(constraint.sql).Error; err != nil {
+			log.Printf("WARNING: Could not add unique key %s, the table likely holds duplicates: %v", constraint.name, err)
+		}
+	}
+}
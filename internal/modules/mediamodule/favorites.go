@@ -0,0 +1,285 @@
+package mediamodule
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mantonx/viewra/internal/database"
+)
+
+// FavoritesCollectionName is the name of the virtual collection holding a
+// user's favorite media
+const FavoritesCollectionName = "Favorites"
+
+// favoriteTarget is a table favorites can point to and its display column
+type favoriteTarget struct {
+	table      string
+	nameColumn string
+}
+
+// favoriteTargets maps the favorite types backed by a table. Genres are
+// favorited by name and have no table.
+var favoriteTargets = map[string]favoriteTarget{
+	"movie":      {"movies", "title"},
+	"tv_show":    {"tv_shows", "title"},
+	"episode":    {"episodes", "title"},
+	"artist":     {"artists", "name"},
+	"album":      {"albums", "title"},
+	"track":      {"tracks", "title"},
+	"home_video": {"home_videos", "title"},
+	"person":     {"peoples", "name"},
+}
+
+// favoriteRequest names something to favorite. For genres the ID is the
+// genre's name.
+type favoriteRequest struct {
+	TargetType string `json:"target_type" binding:"required"`
+	TargetID   string `json:"target_id" binding:"required"`
+}
+
+// favoriteItem is one entry of the Favorites collection
+type favoriteItem struct {
+	MediaType   string    `json:"media_type"`
+	ID          string    `json:"id"`
+	Title       string    `json:"title"`
+	Image       string    `json:"image,omitempty"`
+	FavoritedAt time.Time `json:"favorited_at"`
+}
+
+// favoriteImageColumns are the columns holding each media type's artwork
+var favoriteImageColumns = map[string]string{
+	"movie":   "poster",
+	"tv_show": "poster",
+	"episode": "still_image",
+	"artist":  "image",
+	"album":   "artwork",
+}
+
+// getFavorites lists the favorites of the user in user_id, newest first,
+// optionally of one type (?type=)
+func (m *Module) getFavorites(c *gin.Context) {
+	userID, ok := parseWatchUserID(c)
+	if !ok {
+		return
+	}
+
+	query := m.db.Where("user_id = ?", userID)
+	if targetType := c.Query("type"); targetType != "" {
+		query = query.Where("target_type = ?", targetType)
+	}
+
+	var favorites []database.UserFavorite
+	if err := query.Order("favorited_at DESC").Find(&favorites).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": fmt.Sprintf("Failed to get favorites: %v", err),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"favorites": favorites,
+		"count":     len(favorites),
+	})
+}
+
+// addFavorite favorites a media item, person or genre for the user in user_id
+func (m *Module) addFavorite(c *gin.Context) {
+	userID, ok := parseWatchUserID(c)
+	if !ok {
+		return
+	}
+
+	var request favoriteRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	favorite := database.UserFavorite{
+		UserID:      userID,
+		TargetType:  request.TargetType,
+		TargetID:    strings.TrimSpace(request.TargetID),
+		FavoritedAt: time.Now(),
+	}
+
+	if request.TargetType == "genre" {
+		favorite.Name = favorite.TargetID
+		favorite.TargetID = strings.ToLower(favorite.TargetID)
+	} else {
+		target, ok := favoriteTargets[request.TargetType]
+		if !ok {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": fmt.Sprintf("invalid target_type value: %s", request.TargetType),
+			})
+			return
+		}
+
+		var names []string
+		if err := m.db.Table(target.table).Where("id = ?", favorite.TargetID).Limit(1).Pluck(target.nameColumn, &names).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error": fmt.Sprintf("Failed to find %s: %v", request.TargetType, err),
+			})
+			return
+		}
+		if len(names) == 0 {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error": "Item not found",
+			})
+			return
+		}
+		favorite.Name = names[0]
+	}
+	if favorite.TargetID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "target_id cannot be empty",
+		})
+		return
+	}
+
+	if err := m.db.Save(&favorite).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": fmt.Sprintf("Failed to add favorite: %v", err),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"favorite": favorite,
+	})
+}
+
+// removeFavorite removes a favorite of the user in user_id
+func (m *Module) removeFavorite(c *gin.Context) {
+	userID, ok := parseWatchUserID(c)
+	if !ok {
+		return
+	}
+
+	targetID := c.Param("id")
+	if c.Param("type") == "genre" {
+		targetID = strings.ToLower(targetID)
+	}
+
+	result := m.db.Where("user_id = ? AND target_type = ? AND target_id = ?", userID, c.Param("type"), targetID).
+		Delete(&database.UserFavorite{})
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": fmt.Sprintf("Failed to remove favorite: %v", result.Error),
+		})
+		return
+	}
+	if result.RowsAffected == 0 {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Favorite not found",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Favorite removed",
+	})
+}
+
+// getFavoritesCollection returns the Favorites collection of the user in
+// user_id: their favorite media, newest first. The collection is built from
+// the favorites on every request, so it follows them without being stored.
+// Items removed from the library or hidden by the user are left out.
+func (m *Module) getFavoritesCollection(c *gin.Context) {
+	userID, ok := parseWatchUserID(c)
+	if !ok {
+		return
+	}
+
+	var favorites []database.UserFavorite
+	if err := m.db.Where("user_id = ? AND target_type NOT IN ?", userID, []string{"person", "genre"}).
+		Order("favorited_at DESC").Find(&favorites).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": fmt.Sprintf("Failed to get favorites: %v", err),
+		})
+		return
+	}
+
+	idsByType := make(map[string][]string)
+	for _, favorite := range favorites {
+		idsByType[favorite.TargetType] = append(idsByType[favorite.TargetType], favorite.TargetID)
+	}
+
+	visibility, err := newUserVisibility(m.db, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": fmt.Sprintf("Failed to check parental controls: %v", err),
+		})
+		return
+	}
+	type row struct {
+		ID    string
+		Title string
+		Image string
+	}
+	rows := make(map[string]row)
+	for targetType, ids := range idsByType {
+		target, ok := favoriteTargets[targetType]
+		if !ok {
+			continue
+		}
+		imageColumn := "''"
+		if column, ok := favoriteImageColumns[targetType]; ok {
+			imageColumn = column
+		}
+
+		query := m.db.Table(target.table).
+			Select(fmt.Sprintf("id, %s AS title, %s AS image", target.nameColumn, imageColumn)).
+			Where("id IN ?", ids)
+		switch targetType {
+		case "movie":
+			query = visibility.Movies(query, "id")
+		case "tv_show":
+			query = visibility.Shows(query, "id")
+		case "track":
+			query = visibility.Tracks(query, target.table)
+		case "home_video":
+			query = visibility.HomeVideos(query, target.table)
+		default:
+			query = query.Where("id NOT IN (?)", visibility.hiddenItems(targetType))
+		}
+
+		var found []row
+		if err := query.Scan(&found).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error": fmt.Sprintf("Failed to get favorite %s items: %v", targetType, err),
+			})
+			return
+		}
+		for _, item := range found {
+			rows[targetType+":"+item.ID] = item
+		}
+	}
+
+	items := []favoriteItem{}
+	for _, favorite := range favorites {
+		item, ok := rows[favorite.TargetType+":"+favorite.TargetID]
+		if !ok {
+			continue
+		}
+		items = append(items, favoriteItem{
+			MediaType:   favorite.TargetType,
+			ID:          item.ID,
+			Title:       item.Title,
+			Image:       item.Image,
+			FavoritedAt: favorite.FavoritedAt,
+		})
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"collection": gin.H{
+			"name":  FavoritesCollectionName,
+			"items": items,
+		},
+		"count": len(items),
+	})
+}
@@ -0,0 +1,303 @@
+package mediamodule
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mantonx/viewra/internal/database"
+	"gorm.io/gorm"
+)
+
+// homeVideoItem is a home video with the file it is played from
+type homeVideoItem struct {
+	database.HomeVideo
+	MediaFileID string `json:"media_file_id"`
+	Duration    int    `json:"duration"`
+}
+
+// homeVideoPeriod is one month of the home video timeline
+type homeVideoPeriod struct {
+	Year  int   `json:"year"`
+	Month int   `json:"month"`
+	Count int64 `json:"count"`
+}
+
+// homeVideoUpdate holds the fields a user can edit. Omitted fields are kept.
+type homeVideoUpdate struct {
+	Title       *string    `json:"title"`
+	Description *string    `json:"description"`
+	Tags        *[]string  `json:"tags"`
+	RecordedAt  *time.Time `json:"recorded_at"`
+}
+
+// homeVideoQuery builds the home video query for the library_id, year, month,
+// tag, q and user_id filters
+func (m *Module) homeVideoQuery(c *gin.Context) (*gorm.DB, error) {
+	query := m.db.Model(&database.HomeVideo{})
+
+	if value := c.Query("user_id"); value != "" {
+		userID, err := strconv.ParseUint(value, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid user_id value: %s", value)
+		}
+		visibility := &userVisibility{db: m.db, userID: uint32(userID)}
+		query = visibility.HomeVideos(query, "home_videos")
+	}
+
+	if value := c.Query("library_id"); value != "" {
+		libraryID, err := strconv.ParseUint(value, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid library_id value: %s", value)
+		}
+		query = query.Where("library_id = ?", libraryID)
+	}
+
+	if value := c.Query("year"); value != "" {
+		year, err := strconv.Atoi(value)
+		if err != nil || year < 1 {
+			return nil, fmt.Errorf("invalid year value: %s", value)
+		}
+		start := time.Date(year, time.January, 1, 0, 0, 0, 0, time.Local)
+		end := start.AddDate(1, 0, 0)
+		if value := c.Query("month"); value != "" {
+			month, err := strconv.Atoi(value)
+			if err != nil || month < 1 || month > 12 {
+				return nil, fmt.Errorf("invalid month value: %s", value)
+			}
+			start = time.Date(year, time.Month(month), 1, 0, 0, 0, 0, time.Local)
+			end = start.AddDate(0, 1, 0)
+		}
+		query = query.Where("recorded_at >= ? AND recorded_at < ?", start, end)
+	}
+
+	if tag := strings.TrimSpace(c.Query("tag")); tag != "" {
+		encoded, _ := json.Marshal(tag)
+		query = query.Where("LOWER(tags) LIKE LOWER(?)", "%"+string(encoded)+"%")
+	}
+	if q := c.Query("q"); q != "" {
+		query = query.Where("LOWER(title) LIKE LOWER(?) OR LOWER(description) LIKE LOWER(?)", "%"+q+"%", "%"+q+"%")
+	}
+	return query, nil
+}
+
+// getHomeVideos lists home videos, most recently recorded first
+func (m *Module) getHomeVideos(c *gin.Context) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	if err != nil || limit < 1 || limit > 1000 {
+		limit = 50
+	}
+	offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if err != nil || offset < 0 {
+		offset = 0
+	}
+
+	query, err := m.homeVideoQuery(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": fmt.Sprintf("Failed to count home videos: %v", err),
+		})
+		return
+	}
+
+	var videos []database.HomeVideo
+	if err := query.Order("recorded_at DESC, title").Limit(limit).Offset(offset).Find(&videos).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": fmt.Sprintf("Failed to get home videos: %v", err),
+		})
+		return
+	}
+
+	items, err := m.withHomeVideoFiles(videos)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": fmt.Sprintf("Failed to get home video files: %v", err),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"home_videos": items,
+		"total":       total,
+		"count":       len(items),
+		"limit":       limit,
+		"offset":      offset,
+	})
+}
+
+// getHomeVideoTimeline counts home videos per month of recording, newest
+// first. Takes the same filters as getHomeVideos.
+func (m *Module) getHomeVideoTimeline(c *gin.Context) {
+	query, err := m.homeVideoQuery(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	var dates []time.Time
+	if err := query.Where("recorded_at IS NOT NULL").Order("recorded_at DESC").Pluck("recorded_at", &dates).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": fmt.Sprintf("Failed to get home video timeline: %v", err),
+		})
+		return
+	}
+
+	periods := []homeVideoPeriod{}
+	for _, date := range dates {
+		date = date.Local()
+		last := len(periods) - 1
+		if last < 0 || periods[last].Year != date.Year() || periods[last].Month != int(date.Month()) {
+			periods = append(periods, homeVideoPeriod{Year: date.Year(), Month: int(date.Month())})
+			last++
+		}
+		periods[last].Count++
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"periods": periods,
+		"count":   len(periods),
+	})
+}
+
+// getHomeVideo returns a home video with its file
+func (m *Module) getHomeVideo(c *gin.Context) {
+	var video database.HomeVideo
+	if err := m.db.Where("id = ?", c.Param("id")).First(&video).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Home video not found",
+		})
+		return
+	}
+
+	items, err := m.withHomeVideoFiles([]database.HomeVideo{video})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": fmt.Sprintf("Failed to get home video file: %v", err),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"home_video": items[0],
+	})
+}
+
+// updateHomeVideo sets a home video's title, description, tags or recording
+// date by hand
+func (m *Module) updateHomeVideo(c *gin.Context) {
+	var video database.HomeVideo
+	if err := m.db.Where("id = ?", c.Param("id")).First(&video).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Home video not found",
+		})
+		return
+	}
+
+	var request homeVideoUpdate
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	updates := map[string]interface{}{"edited": true}
+	if request.Title != nil {
+		title := strings.TrimSpace(*request.Title)
+		if title == "" {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "title cannot be empty",
+			})
+			return
+		}
+		updates["title"] = title
+	}
+	if request.Description != nil {
+		updates["description"] = strings.TrimSpace(*request.Description)
+	}
+	if request.Tags != nil {
+		tags, err := json.Marshal(normalizeTags(*request.Tags))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
+		updates["tags"] = string(tags)
+	}
+	if request.RecordedAt != nil {
+		updates["recorded_at"] = *request.RecordedAt
+		updates["date_source"] = "manual"
+	}
+
+	if err := m.db.Model(&video).Updates(updates).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": fmt.Sprintf("Failed to update home video: %v", err),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"home_video": video,
+	})
+}
+
+// withHomeVideoFiles attaches the media file of each home video
+func (m *Module) withHomeVideoFiles(videos []database.HomeVideo) ([]homeVideoItem, error) {
+	ids := make([]string, len(videos))
+	for i, video := range videos {
+		ids[i] = video.ID
+	}
+
+	var mediaFiles []database.MediaFile
+	if len(ids) > 0 {
+		if err := m.db.Where("media_id IN ? AND media_type = ?", ids, database.MediaTypeHomeVideo).
+			Find(&mediaFiles).Error; err != nil {
+			return nil, err
+		}
+	}
+	files := make(map[string]database.MediaFile)
+	for _, mediaFile := range mediaFiles {
+		files[mediaFile.MediaID] = mediaFile
+	}
+
+	items := make([]homeVideoItem, len(videos))
+	for i, video := range videos {
+		items[i] = homeVideoItem{HomeVideo: video}
+		if mediaFile, ok := files[video.ID]; ok {
+			items[i].MediaFileID = mediaFile.ID
+			items[i].Duration = mediaFile.Duration
+		}
+	}
+	return items, nil
+}
+
+// normalizeTags trims tags and drops empty and repeated ones
+func normalizeTags(tags []string) []string {
+	result := []string{}
+	seen := make(map[string]bool)
+	for _, tag := range tags {
+		tag = strings.TrimSpace(tag)
+		key := strings.ToLower(tag)
+		if tag == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		result = append(result, tag)
+	}
+	return result
+}
@@ -0,0 +1,346 @@
+package mediamodule
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/mantonx/viewra/internal/database"
+	"github.com/mantonx/viewra/internal/kodi"
+	"github.com/mantonx/viewra/internal/modules/assetmodule"
+)
+
+// libraryExportOptions controls what a library export writes
+type libraryExportOptions struct {
+	Overwrite   bool `json:"overwrite"`    // Replace NFOs and artwork already next to the media
+	SkipArtwork bool `json:"skip_artwork"` // Only write NFOs
+}
+
+// libraryExportResult counts the files a library export wrote
+type libraryExportResult struct {
+	LibraryID      uint32   `json:"library_id"`
+	NFOsWritten    int      `json:"nfos_written"`
+	ArtworkWritten int      `json:"artwork_written"`
+	Skipped        int      `json:"skipped"` // Already present and not overwritten
+	Errors         []string `json:"errors"`
+}
+
+// exportArtwork maps an asset type to the Kodi artwork it's written as. The
+// first asset type found for a kind is used.
+type exportArtwork struct {
+	kind   string
+	types  []assetmodule.AssetType
+	format string
+}
+
+var (
+	videoExportArtwork = []exportArtwork{
+		{kodi.ArtPoster, []assetmodule.AssetType{assetmodule.AssetTypePoster}, "image/jpeg"},
+		{kodi.ArtFanart, []assetmodule.AssetType{assetmodule.AssetTypeFanart, assetmodule.AssetTypeBackground}, "image/jpeg"},
+		{kodi.ArtBanner, []assetmodule.AssetType{assetmodule.AssetTypeBanner}, "image/jpeg"},
+		{kodi.ArtClearLogo, []assetmodule.AssetType{assetmodule.AssetTypeLogo}, "image/png"},
+	}
+	episodeExportArtwork = []exportArtwork{
+		{kodi.ArtThumb, []assetmodule.AssetType{assetmodule.AssetTypeThumb, assetmodule.AssetTypeScreenshot}, "image/jpeg"},
+	}
+	albumExportArtwork = []exportArtwork{
+		{kodi.ArtFolder, []assetmodule.AssetType{assetmodule.AssetTypeCover}, "image/jpeg"},
+	}
+)
+
+// libraryExporter writes NFOs and artwork for the media of one library
+type libraryExporter struct {
+	m       *Module
+	assets  *assetmodule.Manager
+	options libraryExportOptions
+	result  *libraryExportResult
+	done    map[string]bool // Show and album folders already written
+}
+
+// exportLibrary writes Kodi-style NFOs and artwork next to every movie,
+// episode and album of a library, so the library can be opened by another
+// media server with its matches and artwork intact
+func (m *Module) exportLibrary(libraryID uint32, options libraryExportOptions) (*libraryExportResult, error) {
+	var files []database.MediaFile
+	if err := m.db.Where("library_id = ? AND media_id != ''", libraryID).Order("path").Find(&files).Error; err != nil {
+		return nil, fmt.Errorf("failed to fetch media files: %w", err)
+	}
+
+	e := &libraryExporter{
+		m:       m,
+		options: options,
+		result:  &libraryExportResult{LibraryID: libraryID, Errors: []string{}},
+		done:    make(map[string]bool),
+	}
+	if !options.SkipArtwork {
+		e.assets = assetmodule.GetAssetManager()
+	}
+
+	for _, file := range files {
+		var err error
+		switch file.MediaType {
+		case database.MediaTypeMovie:
+			err = e.exportMovie(file)
+		case database.MediaTypeEpisode:
+			err = e.exportEpisode(file)
+		case database.MediaTypeTrack:
+			err = e.exportAlbum(file)
+		default:
+			continue
+		}
+		if err != nil {
+			e.result.Errors = append(e.result.Errors, fmt.Sprintf("%s: %v", file.Path, err))
+		}
+	}
+	return e.result, nil
+}
+
+// exportMovie writes <basename>.nfo and <basename>-poster.jpg etc. for a movie file
+func (e *libraryExporter) exportMovie(file database.MediaFile) error {
+	var movie database.Movie
+	if err := e.m.db.Where("id = ?", file.MediaID).First(&movie).Error; err != nil {
+		return fmt.Errorf("failed to fetch movie: %w", err)
+	}
+
+	nfo := &kodi.Movie{
+		Title:         movie.Title,
+		OriginalTitle: movie.OriginalTitle,
+		Plot:          movie.Overview,
+		Tagline:       movie.Tagline,
+		Runtime:       movie.Runtime,
+		MPAA:          movie.Rating,
+		Premiered:     kodi.FormatDate(movie.ReleaseDate),
+		Genres:        parseGenres(movie.Genres),
+		Studios:       parseGenres(movie.ProductionCompanies),
+		Countries:     parseGenres(movie.ProductionCountries),
+		UniqueIDs:     e.uniqueIDs(movie.ID, database.MediaTypeMovie, movie.ImdbID, movie.TmdbID),
+		ID:            movie.ImdbID,
+	}
+	if movie.ReleaseDate != nil {
+		nfo.Year = movie.ReleaseDate.Year()
+	}
+	if err := e.writeNFO(kodi.MediaNFOPath(file.Path), nfo); err != nil {
+		return err
+	}
+
+	return e.writeArtwork(assetmodule.EntityTypeMovie, movie.ID, videoExportArtwork, func(kind, ext string) string {
+		return kodi.MediaArtworkPath(file.Path, kind, ext)
+	})
+}
+
+// exportEpisode writes an episode's NFO and thumbnail, and tvshow.nfo and the
+// show artwork in the show folder the first time the show is seen
+func (e *libraryExporter) exportEpisode(file database.MediaFile) error {
+	var episode database.Episode
+	if err := e.m.db.Preload("Season.TVShow").Where("id = ?", file.MediaID).First(&episode).Error; err != nil {
+		return fmt.Errorf("failed to fetch episode: %w", err)
+	}
+	show := episode.Season.TVShow
+
+	showDir := kodi.ShowDir(file.Path)
+	if !e.done[showDir] && show.ID != "" {
+		e.done[showDir] = true
+		showNFO := &kodi.TVShow{
+			Title:     show.Title,
+			Plot:      show.Description,
+			Premiered: kodi.FormatDate(show.FirstAirDate),
+			Status:    show.Status,
+			UniqueIDs: e.uniqueIDs(show.ID, "tv_show", "", show.TmdbID),
+		}
+		if show.FirstAirDate != nil {
+			showNFO.Year = show.FirstAirDate.Year()
+		}
+		if err := e.writeNFO(filepath.Join(showDir, "tvshow.nfo"), showNFO); err != nil {
+			return err
+		}
+		if err := e.writeArtwork(assetmodule.EntityTypeTVShow, show.ID, videoExportArtwork, func(kind, ext string) string {
+			return kodi.FolderArtworkPath(showDir, kind, ext)
+		}); err != nil {
+			return err
+		}
+	}
+
+	nfo := &kodi.Episode{
+		Title:     episode.Title,
+		ShowTitle: show.Title,
+		Season:    episode.Season.SeasonNumber,
+		Episode:   episode.EpisodeNumber,
+		Plot:      episode.Description,
+		Aired:     kodi.FormatDate(episode.AirDate),
+		Runtime:   episode.Duration / 60,
+		UniqueIDs: e.uniqueIDs(episode.ID, database.MediaTypeEpisode, "", ""),
+	}
+	if err := e.writeNFO(kodi.MediaNFOPath(file.Path), nfo); err != nil {
+		return err
+	}
+
+	return e.writeArtwork(assetmodule.EntityTypeEpisode, episode.ID, episodeExportArtwork, func(kind, ext string) string {
+		return kodi.MediaArtworkPath(file.Path, kind, ext)
+	})
+}
+
+// exportAlbum writes album.nfo and folder.jpg in a track's folder the first
+// time the folder is seen
+func (e *libraryExporter) exportAlbum(file database.MediaFile) error {
+	albumDir := filepath.Dir(file.Path)
+	if e.done[albumDir] {
+		return nil
+	}
+	e.done[albumDir] = true
+
+	var track database.Track
+	if err := e.m.db.Preload("Album.Artist").Preload("Album.Labels").Where("id = ?", file.MediaID).First(&track).Error; err != nil {
+		return fmt.Errorf("failed to fetch track: %w", err)
+	}
+	album := track.Album
+
+	nfo := &kodi.Album{
+		Title:              album.Title,
+		Artist:             album.Artist.Name,
+		ReleaseDate:        kodi.FormatDate(album.ReleaseDate),
+		ReleaseType:        album.ReleaseType,
+		MusicBrainzAlbumID: album.MusicBrainzID,
+	}
+	if album.ReleaseDate != nil {
+		nfo.Year = album.ReleaseDate.Year()
+	}
+	for _, label := range album.Labels {
+		nfo.Labels = append(nfo.Labels, label.Name)
+	}
+	if err := e.writeNFO(filepath.Join(albumDir, "album.nfo"), nfo); err != nil {
+		return err
+	}
+
+	return e.writeArtwork(assetmodule.EntityTypeAlbum, album.ID, albumExportArtwork, func(kind, ext string) string {
+		return kodi.FolderArtworkPath(albumDir, kind, ext)
+	})
+}
+
+// uniqueIDs collects an item's provider IDs, with IMDb as the default
+func (e *libraryExporter) uniqueIDs(mediaID string, mediaType database.MediaType, imdbID, tmdbID string) []kodi.UniqueID {
+	ids := map[string]string{}
+	var externalIDs []database.MediaExternalIDs
+	e.m.db.Where("media_id = ? AND media_type = ?", mediaID, mediaType).Find(&externalIDs)
+	for _, id := range externalIDs {
+		if id.ExternalID != "" {
+			ids[id.Source] = id.ExternalID
+		}
+	}
+	if imdbID != "" {
+		ids["imdb"] = imdbID
+	}
+	if tmdbID != "" {
+		ids["tmdb"] = tmdbID
+	}
+
+	sources := make([]string, 0, len(ids))
+	for source := range ids {
+		sources = append(sources, source)
+	}
+	sort.Strings(sources)
+
+	uniqueIDs := make([]kodi.UniqueID, 0, len(sources))
+	for _, source := range sources {
+		uniqueIDs = append(uniqueIDs, kodi.UniqueID{Type: source, Value: ids[source], Default: source == "imdb"})
+	}
+	return uniqueIDs
+}
+
+// writeNFO writes an NFO unless one is there and overwriting is off
+func (e *libraryExporter) writeNFO(path string, nfo interface{}) error {
+	if !e.options.Overwrite && kodi.Exists(path) {
+		e.result.Skipped++
+		return nil
+	}
+	if err := kodi.Write(path, nfo); err != nil {
+		return err
+	}
+	e.result.NFOsWritten++
+	return nil
+}
+
+// writeArtwork writes the preferred asset of each kind of artwork an entity has
+func (e *libraryExporter) writeArtwork(entityType assetmodule.EntityType, entityID string, artwork []exportArtwork, pathFor func(kind, ext string) string) error {
+	if e.assets == nil {
+		return nil
+	}
+	id, err := uuid.Parse(entityID)
+	if err != nil {
+		return nil // Entities created before UUIDs have no assets
+	}
+
+	for _, art := range artwork {
+		ext := ".jpg"
+		if art.format == "image/png" {
+			ext = ".png"
+		}
+		path := pathFor(art.kind, ext)
+		if !e.options.Overwrite && kodi.Exists(path) {
+			e.result.Skipped++
+			continue
+		}
+
+		for _, assetType := range art.types {
+			asset, err := e.assets.GetPreferredAsset(entityType, id, assetType)
+			if err != nil {
+				continue // No artwork of this type
+			}
+			data, err := e.assets.ExportAssetData(asset.ID, art.format)
+			if err != nil {
+				return fmt.Errorf("failed to export %s: %w", art.kind, err)
+			}
+			if err := os.WriteFile(path, data, 0644); err != nil {
+				return fmt.Errorf("failed to write %s: %w", art.kind, err)
+			}
+			e.result.ArtworkWritten++
+			break
+		}
+	}
+	return nil
+}
+
+// exportLibraryHandler writes NFOs and artwork next to a library's media
+func (m *Module) exportLibraryHandler(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid library ID",
+		})
+		return
+	}
+
+	var library database.MediaLibrary
+	if err := m.db.First(&library, id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Library not found",
+		})
+		return
+	}
+
+	var options libraryExportOptions
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&options); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": fmt.Sprintf("Invalid request body: %v", err),
+			})
+			return
+		}
+	}
+
+	result, err := m.exportLibrary(library.ID, options)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": fmt.Sprintf("Failed to export library: %v", err),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"export": result,
+	})
+}
@@ -0,0 +1,322 @@
+package mediamodule
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mantonx/viewra/internal/database"
+)
+
+const (
+	defaultStorageLargestLimit = 20
+	maxStorageLargestLimit     = 100
+	defaultStorageGrowthWeeks  = 52
+
+	// storageSampleInterval is how often library sizes are sampled. Each
+	// sample replaces the one for the same week, so the week keeps its last.
+	storageSampleInterval = 24 * time.Hour
+)
+
+// efficientVideoCodecs are codecs the optimize job wouldn't shrink further.
+// Video in any other codec is a candidate for optimizing.
+var efficientVideoCodecs = map[string]bool{"hevc": true, "h265": true, "av1": true, "vp9": true}
+
+// storageBucket is how much of a library one resolution or codec takes up
+type storageBucket struct {
+	Key     string  `json:"key"`
+	Files   int64   `json:"files"`
+	Bytes   int64   `json:"bytes"`
+	Percent float64 `json:"percent"`
+}
+
+// storageItem is one of a library's largest files
+type storageItem struct {
+	MediaFileID       string             `json:"media_file_id"`
+	MediaID           string             `json:"media_id"`
+	MediaType         database.MediaType `json:"media_type"`
+	Path              string             `json:"path"`
+	SizeBytes         int64              `json:"size_bytes"`
+	Resolution        string             `json:"resolution"`
+	VideoCodec        string             `json:"video_codec,omitempty"`
+	BitrateKbps       int                `json:"bitrate_kbps,omitempty"`
+	OptimizeCandidate bool               `json:"optimize_candidate"`
+}
+
+// libraryStorage is a breakdown of the space a library uses
+type libraryStorage struct {
+	LibraryID              uint32                          `json:"library_id"`
+	TotalBytes             int64                           `json:"total_bytes"`
+	FileCount              int64                           `json:"file_count"`
+	ByResolution           []storageBucket                 `json:"by_resolution"`
+	ByCodec                []storageBucket                 `json:"by_codec"`
+	Largest                []storageItem                   `json:"largest"`
+	OptimizeCandidateFiles int64                           `json:"optimize_candidate_files"`
+	OptimizeCandidateBytes int64                           `json:"optimize_candidate_bytes"`
+	Growth                 []database.LibraryStorageSample `json:"growth"`
+}
+
+// libraryStorageBreakdown reports a library's size by resolution and codec,
+// its largest files and its weekly size history. The current week's sample
+// is refreshed on the way.
+func (m *Module) libraryStorageBreakdown(library *database.MediaLibrary, largestLimit, weeks int) (*libraryStorage, error) {
+	storage := &libraryStorage{
+		LibraryID:    library.ID,
+		ByResolution: []storageBucket{},
+		ByCodec:      []storageBucket{},
+		Largest:      []storageItem{},
+		Growth:       []database.LibraryStorageSample{},
+	}
+
+	sample, err := m.sampleLibraryStorage(library.ID)
+	if err != nil {
+		return nil, err
+	}
+	storage.TotalBytes, storage.FileCount = sample.TotalBytes, sample.FileCount
+
+	// Originals in a keep-original library are never optimized
+	var keepOriginal int64
+	m.db.Model(&database.LibraryTranscodeProfile{}).
+		Where("library_id = ? AND keep_original = ?", library.ID, true).
+		Count(&keepOriginal)
+	optimizable := func(videoCodec string) bool {
+		codec := strings.ToLower(videoCodec)
+		return keepOriginal == 0 && codec != "" && !efficientVideoCodecs[codec]
+	}
+
+	var resolutionRows []struct {
+		MediaType   database.MediaType
+		Resolution  string
+		VideoWidth  int
+		VideoHeight int
+		Files       int64
+		Bytes       int64
+	}
+	if err := m.db.Model(&database.MediaFile{}).
+		Select("media_type, resolution, video_width, video_height, COUNT(*) AS files, COALESCE(SUM(size_bytes), 0) AS bytes").
+		Where("library_id = ?", library.ID).
+		Group("media_type, resolution, video_width, video_height").
+		Scan(&resolutionRows).Error; err != nil {
+		return nil, fmt.Errorf("failed to group by resolution: %w", err)
+	}
+	resolutions := make(map[string]*storageBucket)
+	for _, row := range resolutionRows {
+		addToBucket(resolutions, storageResolution(row.MediaType, row.Resolution, row.VideoWidth, row.VideoHeight), row.Files, row.Bytes)
+	}
+	storage.ByResolution = sortedBuckets(resolutions, storage.TotalBytes)
+
+	var codecRows []struct {
+		VideoCodec string
+		AudioCodec string
+		Files      int64
+		Bytes      int64
+	}
+	if err := m.db.Model(&database.MediaFile{}).
+		Select("video_codec, audio_codec, COUNT(*) AS files, COALESCE(SUM(size_bytes), 0) AS bytes").
+		Where("library_id = ?", library.ID).
+		Group("video_codec, audio_codec").
+		Scan(&codecRows).Error; err != nil {
+		return nil, fmt.Errorf("failed to group by codec: %w", err)
+	}
+	codecs := make(map[string]*storageBucket)
+	for _, row := range codecRows {
+		// Video files are grouped by their video codec, audio files by theirs
+		codec := strings.ToLower(row.VideoCodec)
+		if codec == "" {
+			codec = strings.ToLower(row.AudioCodec)
+		}
+		if codec == "" {
+			codec = "unknown"
+		}
+		addToBucket(codecs, codec, row.Files, row.Bytes)
+		if optimizable(row.VideoCodec) {
+			storage.OptimizeCandidateFiles += row.Files
+			storage.OptimizeCandidateBytes += row.Bytes
+		}
+	}
+	storage.ByCodec = sortedBuckets(codecs, storage.TotalBytes)
+
+	var largest []database.MediaFile
+	if err := m.db.Select("id, media_id, media_type, path, size_bytes, resolution, video_width, video_height, video_codec, bitrate_kbps").
+		Where("library_id = ?", library.ID).
+		Order("size_bytes DESC").
+		Limit(largestLimit).
+		Find(&largest).Error; err != nil {
+		return nil, fmt.Errorf("failed to get largest files: %w", err)
+	}
+	for _, file := range largest {
+		storage.Largest = append(storage.Largest, storageItem{
+			MediaFileID:       file.ID,
+			MediaID:           file.MediaID,
+			MediaType:         file.MediaType,
+			Path:              file.Path,
+			SizeBytes:         file.SizeBytes,
+			Resolution:        storageResolution(file.MediaType, file.Resolution, file.VideoWidth, file.VideoHeight),
+			VideoCodec:        file.VideoCodec,
+			BitrateKbps:       file.BitrateKbps,
+			OptimizeCandidate: optimizable(file.VideoCodec),
+		})
+	}
+
+	since := weekStart(time.Now()).AddDate(0, 0, -7*(weeks-1))
+	if err := m.db.Where("library_id = ? AND week_start >= ?", library.ID, since).
+		Order("week_start").
+		Find(&storage.Growth).Error; err != nil {
+		return nil, fmt.Errorf("failed to get storage history: %w", err)
+	}
+
+	return storage, nil
+}
+
+// sampleLibraryStorage records a library's current size as this week's sample
+func (m *Module) sampleLibraryStorage(libraryID uint32) (*database.LibraryStorageSample, error) {
+	now := time.Now()
+	sample := &database.LibraryStorageSample{
+		LibraryID: libraryID,
+		WeekStart: weekStart(now),
+		SampledAt: now,
+	}
+	if err := m.db.Model(&database.MediaFile{}).
+		Select("COUNT(*)").
+		Where("library_id = ?", libraryID).
+		Scan(&sample.FileCount).Error; err != nil {
+		return nil, fmt.Errorf("failed to count library files: %w", err)
+	}
+	if err := m.db.Model(&database.MediaFile{}).
+		Select("COALESCE(SUM(size_bytes), 0)").
+		Where("library_id = ?", libraryID).
+		Scan(&sample.TotalBytes).Error; err != nil {
+		return nil, fmt.Errorf("failed to sum library size: %w", err)
+	}
+
+	if err := m.db.Save(sample).Error; err != nil {
+		return nil, fmt.Errorf("failed to save storage sample: %w", err)
+	}
+	return sample, nil
+}
+
+// startStorageSampler samples every library's size now and then daily, so
+// growth is charted even for libraries nobody looks at
+func (m *Module) startStorageSampler() {
+	sampleAll := func() {
+		var libraryIDs []uint32
+		if err := m.db.Model(&database.MediaLibrary{}).Pluck("id", &libraryIDs).Error; err != nil {
+			log.Printf("WARNING: Failed to list libraries for storage sampling: %v", err)
+			return
+		}
+		for _, libraryID := range libraryIDs {
+			if _, err := m.sampleLibraryStorage(libraryID); err != nil {
+				log.Printf("WARNING: Failed to sample storage of library %d: %v", libraryID, err)
+			}
+		}
+	}
+
+	sampleAll()
+	ticker := time.NewTicker(storageSampleInterval)
+	defer ticker.Stop()
+	for range ticker.C {
+		sampleAll()
+	}
+}
+
+// weekStart returns midnight UTC on the Monday of t's week
+func weekStart(t time.Time) time.Time {
+	t = t.UTC()
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	offset := (int(day.Weekday()) + 6) % 7 // Days since Monday
+	return day.AddDate(0, 0, -offset)
+}
+
+// storageResolution names the resolution class of a file from its frame
+// size, falling back to the scanned resolution label
+func storageResolution(mediaType database.MediaType, resolution string, width, height int) string {
+	switch {
+	case height >= 2000 || width >= 3800:
+		return "2160p"
+	case height >= 1400 || width >= 2500:
+		return "1440p"
+	case height >= 1000 || width >= 1900:
+		return "1080p"
+	case height >= 700 || width >= 1200:
+		return "720p"
+	case height > 0:
+		return "SD"
+	case mediaType == database.MediaTypeTrack:
+		return "audio"
+	case resolution != "":
+		return strings.ToLower(resolution)
+	}
+	return "unknown"
+}
+
+func addToBucket(buckets map[string]*storageBucket, key string, files, bytes int64) {
+	bucket, ok := buckets[key]
+	if !ok {
+		bucket = &storageBucket{Key: key}
+		buckets[key] = bucket
+	}
+	bucket.Files += files
+	bucket.Bytes += bytes
+}
+
+// sortedBuckets returns buckets largest first with their share of the total
+func sortedBuckets(buckets map[string]*storageBucket, totalBytes int64) []storageBucket {
+	sorted := make([]storageBucket, 0, len(buckets))
+	for _, bucket := range buckets {
+		if totalBytes > 0 {
+			bucket.Percent = float64(bucket.Bytes) / float64(totalBytes) * 100
+		}
+		sorted = append(sorted, *bucket)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].Bytes != sorted[j].Bytes {
+			return sorted[i].Bytes > sorted[j].Bytes
+		}
+		return sorted[i].Key < sorted[j].Key
+	})
+	return sorted
+}
+
+// getLibraryStorage reports how a library's storage is used
+// (`?largest=&weeks=`), to plan upgrades and find files worth optimizing
+func (m *Module) getLibraryStorage(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid library ID",
+		})
+		return
+	}
+
+	largestLimit, err := strconv.Atoi(c.DefaultQuery("largest", strconv.Itoa(defaultStorageLargestLimit)))
+	if err != nil || largestLimit < 1 || largestLimit > maxStorageLargestLimit {
+		largestLimit = defaultStorageLargestLimit
+	}
+	weeks, err := strconv.Atoi(c.DefaultQuery("weeks", strconv.Itoa(defaultStorageGrowthWeeks)))
+	if err != nil || weeks < 1 {
+		weeks = defaultStorageGrowthWeeks
+	}
+
+	var library database.MediaLibrary
+	if err := m.db.First(&library, id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Library not found",
+		})
+		return
+	}
+
+	storage, err := m.libraryStorageBreakdown(&library, largestLimit, weeks)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": fmt.Sprintf("Failed to get library storage: %v", err),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, storage)
+}
@@ -0,0 +1,259 @@
+package mediamodule
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mantonx/viewra/internal/database"
+)
+
+// mediaInfoVideoStream is a probed video stream
+type mediaInfoVideoStream struct {
+	Index          int    `json:"index"`
+	Title          string `json:"title,omitempty"`
+	Codec          string `json:"codec"`
+	CodecLongName  string `json:"codec_long_name,omitempty"`
+	Profile        string `json:"profile,omitempty"`
+	Level          string `json:"level,omitempty"`
+	Width          int    `json:"width"`
+	Height         int    `json:"height"`
+	Resolution     string `json:"resolution,omitempty"`
+	AspectRatio    string `json:"aspect_ratio,omitempty"`
+	Framerate      string `json:"framerate,omitempty"`
+	Interlaced     string `json:"interlaced,omitempty"`
+	Bitrate        int64  `json:"bitrate,omitempty"` // Bits per second
+	BitDepth       int    `json:"bit_depth,omitempty"`
+	PixelFormat    string `json:"pixel_format,omitempty"`
+	ColorPrimaries string `json:"color_primaries,omitempty"`
+	ColorSpace     string `json:"color_space,omitempty"`
+	ColorTransfer  string `json:"color_transfer,omitempty"`
+	ColorRange     string `json:"color_range,omitempty"`
+	HDRFormat      string `json:"hdr_format,omitempty"` // HDR10, HDR10+, Dolby Vision or HLG
+	MaxLuminance   string `json:"max_luminance,omitempty"`
+	MinLuminance   string `json:"min_luminance,omitempty"`
+	MaxCLL         string `json:"max_cll,omitempty"`
+	MaxFALL        string `json:"max_fall,omitempty"`
+	Language       string `json:"language,omitempty"`
+	Default        bool   `json:"default"`
+	Forced         bool   `json:"forced"`
+}
+
+// mediaInfoAudioStream is a probed audio stream
+type mediaInfoAudioStream struct {
+	Index         int    `json:"index"`
+	Title         string `json:"title,omitempty"`
+	Codec         string `json:"codec"`
+	CodecLongName string `json:"codec_long_name,omitempty"`
+	Profile       string `json:"profile,omitempty"`
+	Channels      int    `json:"channels"`
+	ChannelLayout string `json:"channel_layout,omitempty"`
+	SampleRate    int    `json:"sample_rate,omitempty"`
+	BitDepth      int    `json:"bit_depth,omitempty"`
+	Bitrate       int64  `json:"bitrate,omitempty"` // Bits per second
+	Language      string `json:"language,omitempty"`
+	Default       bool   `json:"default"`
+	Forced        bool   `json:"forced"`
+}
+
+// mediaInfoSubtitleStream is a probed subtitle stream
+type mediaInfoSubtitleStream struct {
+	Index         int    `json:"index"`
+	Title         string `json:"title,omitempty"`
+	Codec         string `json:"codec"`
+	CodecLongName string `json:"codec_long_name,omitempty"`
+	Language      string `json:"language,omitempty"`
+	Default       bool   `json:"default"`
+	Forced        bool   `json:"forced"`
+}
+
+// mediaInfoFile is the container and streams of one file of an item
+type mediaInfoFile struct {
+	MediaFileID string                    `json:"media_file_id"`
+	Path        string                    `json:"path"`
+	VersionName string                    `json:"version_name,omitempty"`
+	Container   string                    `json:"container"`
+	SizeBytes   int64                     `json:"size_bytes"`
+	Duration    float64                   `json:"duration"` // In seconds
+	Bitrate     int64                     `json:"bitrate"`  // Overall bits per second
+	Video       []mediaInfoVideoStream    `json:"video"`
+	Audio       []mediaInfoAudioStream    `json:"audio"`
+	Subtitles   []mediaInfoSubtitleStream `json:"subtitles"`
+	// Probed is false when only the summary columns of the file are known,
+	// e.g. for audio files or files scanned before streams were recorded
+	Probed bool `json:"probed"`
+}
+
+// The FFmpeg plugin stores bitrates and video bit depth as strings, as
+// ffprobe reports them; these shadow the numeric fields when decoding
+type (
+	probedVideoStream struct {
+		mediaInfoVideoStream
+		Bitrate  string `json:"bitrate"`
+		BitDepth string `json:"bit_depth"`
+	}
+	probedAudioStream struct {
+		mediaInfoAudioStream
+		Bitrate string `json:"bitrate"`
+	}
+	probedContainer struct {
+		Container string  `json:"container"`
+		Duration  float64 `json:"duration"`
+		Bitrate   int64   `json:"bitrate"`
+	}
+)
+
+// newMediaInfoFile describes a file from its probe data, falling back to the
+// summary columns for what wasn't probed
+func newMediaInfoFile(mediaFile *database.MediaFile) mediaInfoFile {
+	info := mediaInfoFile{
+		MediaFileID: mediaFile.ID,
+		Path:        mediaFile.Path,
+		VersionName: mediaFile.VersionName,
+		Container:   mediaFile.Container,
+		SizeBytes:   mediaFile.SizeBytes,
+		Duration:    float64(mediaFile.Duration),
+		Bitrate:     int64(mediaFile.BitrateKbps) * 1000,
+		Video:       []mediaInfoVideoStream{},
+		Audio:       []mediaInfoAudioStream{},
+		Subtitles:   []mediaInfoSubtitleStream{},
+	}
+
+	var container probedContainer
+	if mediaFile.TechnicalInfo != "" && json.Unmarshal([]byte(mediaFile.TechnicalInfo), &container) == nil {
+		info.Probed = true
+		if container.Container != "" {
+			info.Container = container.Container
+		}
+		if container.Duration > 0 {
+			info.Duration = container.Duration
+		}
+		if container.Bitrate > 0 {
+			info.Bitrate = container.Bitrate
+		}
+	}
+
+	var videoStreams []probedVideoStream
+	if mediaFile.VideoStreams != "" && json.Unmarshal([]byte(mediaFile.VideoStreams), &videoStreams) == nil {
+		for _, probed := range videoStreams {
+			stream := probed.mediaInfoVideoStream
+			stream.Bitrate = parseProbeInt(probed.Bitrate)
+			stream.BitDepth = int(parseProbeInt(probed.BitDepth))
+			info.Video = append(info.Video, stream)
+		}
+	} else if mediaFile.VideoCodec != "" {
+		bitDepth := int(parseProbeInt(mediaFile.VideoBitDepth))
+		info.Video = append(info.Video, mediaInfoVideoStream{
+			Codec:          mediaFile.VideoCodec,
+			Profile:        mediaFile.VideoProfile,
+			Width:          mediaFile.VideoWidth,
+			Height:         mediaFile.VideoHeight,
+			Resolution:     mediaFile.Resolution,
+			AspectRatio:    mediaFile.AspectRatio,
+			Framerate:      mediaFile.VideoFramerate,
+			Interlaced:     mediaFile.Interlaced,
+			BitDepth:       bitDepth,
+			PixelFormat:    mediaFile.PixelFormat,
+			ColorPrimaries: mediaFile.ColorPrimaries,
+			ColorSpace:     mediaFile.ColorSpace,
+			ColorTransfer:  mediaFile.ColorTransfer,
+			HDRFormat:      mediaFile.HDRFormat,
+			Default:        true,
+		})
+	}
+
+	var audioStreams []probedAudioStream
+	if mediaFile.AudioStreams != "" && json.Unmarshal([]byte(mediaFile.AudioStreams), &audioStreams) == nil {
+		for _, probed := range audioStreams {
+			stream := probed.mediaInfoAudioStream
+			stream.Bitrate = parseProbeInt(probed.Bitrate)
+			info.Audio = append(info.Audio, stream)
+		}
+	} else if mediaFile.AudioCodec != "" {
+		stream := mediaInfoAudioStream{
+			Codec:         mediaFile.AudioCodec,
+			Profile:       mediaFile.AudioProfile,
+			Channels:      mediaFile.AudioChannels,
+			ChannelLayout: mediaFile.AudioLayout,
+			SampleRate:    mediaFile.AudioSampleRate,
+			BitDepth:      mediaFile.AudioBitDepth,
+			Language:      mediaFile.AudioLanguage,
+			Default:       true,
+		}
+		if stream.SampleRate == 0 {
+			stream.SampleRate = mediaFile.SampleRate
+		}
+		if stream.Language == "und" {
+			stream.Language = ""
+		}
+		// An audio-only file's bitrate is its audio stream's
+		if len(info.Video) == 0 {
+			stream.Bitrate = info.Bitrate
+		}
+		info.Audio = append(info.Audio, stream)
+	}
+
+	if mediaFile.SubtitleStreams != "" {
+		_ = json.Unmarshal([]byte(mediaFile.SubtitleStreams), &info.Subtitles)
+		if info.Subtitles == nil {
+			info.Subtitles = []mediaInfoSubtitleStream{}
+		}
+	}
+
+	return info
+}
+
+// parseProbeInt reads the leading number of an ffprobe value such as
+// "5000000" or "10 bit", returning 0 when there is none
+func parseProbeInt(value string) int64 {
+	value = strings.TrimSpace(value)
+	end := 0
+	for end < len(value) && value[end] >= '0' && value[end] <= '9' {
+		end++
+	}
+	n, _ := strconv.ParseInt(value[:end], 10, 64)
+	return n
+}
+
+// getMediaInfo returns the probed container and streams of every file of a
+// movie, episode, track or home video, for a MediaInfo-style panel. The ID
+// may also be that of a single media file.
+func (m *Module) getMediaInfo(c *gin.Context) {
+	id := c.Param("id")
+
+	var mediaFiles []database.MediaFile
+	if err := m.db.Where("media_id = ?", id).Order("version_name, path").Find(&mediaFiles).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": fmt.Sprintf("Failed to get media files: %v", err),
+		})
+		return
+	}
+	if len(mediaFiles) == 0 {
+		if err := m.db.Where("id = ?", id).Limit(1).Find(&mediaFiles).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error": fmt.Sprintf("Failed to get media file: %v", err),
+			})
+			return
+		}
+	}
+	if len(mediaFiles) == 0 {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Media not found",
+		})
+		return
+	}
+
+	files := make([]mediaInfoFile, len(mediaFiles))
+	for i := range mediaFiles {
+		files[i] = newMediaInfoFile(&mediaFiles[i])
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"media_id":   mediaFiles[0].MediaID,
+		"media_type": mediaFiles[0].MediaType,
+		"files":      files,
+	})
+}
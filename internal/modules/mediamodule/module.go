@@ -89,14 +89,24 @@ func (m *Module) Initialize() error {
 		&database.People{},
 		&database.Roles{},
 		&database.Artist{},
+		&database.ArtistRelationship{},
 		&database.Album{},
+		&database.AlbumLabel{},
 		&database.Track{},
 		&database.Movie{},
 		&database.TVShow{},
 		&database.Season{},
 		&database.Episode{},
+		&database.HomeVideo{},
 		&database.MediaExternalIDs{},
 		&database.MediaEnrichment{},
+		&database.UserHiddenItem{},
+		&database.UserHiddenLibrary{},
+		&database.UserFavorite{},
+		&database.LibraryStorageSample{},
+		&database.LibraryQualityTarget{},
+		&database.UpgradeWanted{},
+		&database.RecycledFile{},
 	)
 	if err != nil {
 		return fmt.Errorf("failed to migrate media schema: %w", err)
@@ -117,19 +127,32 @@ func (m *Module) Migrate(db *gorm.DB) error {
 		&database.People{},
 		&database.Roles{},
 		&database.Artist{},
+		&database.ArtistRelationship{},
 		&database.Album{},
+		&database.AlbumLabel{},
 		&database.Track{},
 		&database.Movie{},
 		&database.TVShow{},
 		&database.Season{},
 		&database.Episode{},
+		&database.HomeVideo{},
 		&database.MediaExternalIDs{},
 		&database.MediaEnrichment{},
+		&database.UserHiddenItem{},
+		&database.UserHiddenLibrary{},
+		&database.UserFavorite{},
+		&database.LibraryStorageSample{},
+		&database.LibraryQualityTarget{},
+		&database.UpgradeWanted{},
+		&database.RecycledFile{},
 	)
 	if err != nil {
 		return fmt.Errorf("failed to migrate media schema: %w", err)
 	}
 
+	// Unique keys that let concurrent scanners and plugins upsert library items
+	createEntityConstraints(db)
+
 	return nil
 }
 
@@ -148,6 +171,15 @@ func (m *Module) Init() error {
 
 	m.initialized = true
 
+	// Sample library sizes for the storage growth history
+	go m.startStorageSampler()
+
+	// Check libraries against their quality targets
+	go m.startUpgradeChecks()
+
+	// Purge recycled files once they can no longer be restored
+	go m.startRecycleBinPurge()
+
 	// Publish initialization event
 	if m.eventBus != nil {
 		initEvent := events.NewSystemEvent(
@@ -217,11 +249,27 @@ func (m *Module) RegisterRoutes(router *gin.Engine) {
 		mediaGroup.DELETE("/libraries/:id", m.deleteLibrary)
 		mediaGroup.GET("/libraries/:id/stats", m.getLibraryStats)
 		mediaGroup.GET("/libraries/:id/files", m.getLibraryFiles)
+		mediaGroup.POST("/libraries/:id/export", m.exportLibraryHandler)
+		mediaGroup.GET("/libraries/:id/storage", m.getLibraryStorage)
+		mediaGroup.GET("/libraries/:id/quality-target", m.getQualityTarget)
+		mediaGroup.PUT("/libraries/:id/quality-target", m.setQualityTarget)
+
+		// Quality upgrades wanted
+		mediaGroup.GET("/upgrades", m.getUpgradesWanted)
+		mediaGroup.POST("/upgrades/check", m.checkUpgradesHandler)
+		mediaGroup.POST("/upgrades/push", m.pushUpgradesHandler)
 
 		// File management endpoints
 		mediaGroup.GET("/files", m.getFiles)
 		mediaGroup.GET("/files/:id", m.getFile)
 		mediaGroup.DELETE("/files/:id", m.deleteFile)
+		mediaGroup.POST("/files/delete", m.deleteFiles)
+
+		// Recycle bin of deleted files
+		mediaGroup.GET("/recycle-bin", m.getRecycleBin)
+		mediaGroup.DELETE("/recycle-bin", m.emptyRecycleBin)
+		mediaGroup.POST("/recycle-bin/:id/restore", m.restoreRecycledFile)
+		mediaGroup.DELETE("/recycle-bin/:id", m.purgeRecycledFile)
 
 		// Modern DASH/HLS streaming - use new PlaybackModule workflow exclusively
 		if m.playbackIntegration != nil {
@@ -242,8 +290,41 @@ func (m *Module) RegisterRoutes(router *gin.Engine) {
 		mediaGroup.GET("/files/:id/album-id", m.getFileAlbumId)
 		mediaGroup.GET("/files/:id/album-artwork", m.getFileAlbumArtwork)
 
+		// Probed stream details of an item's files
+		mediaGroup.GET("/:id/mediainfo", m.getMediaInfo)
+
+		// Music endpoints
+		mediaGroup.GET("/artists/:id", m.getArtist)
+		mediaGroup.GET("/albums/:id", m.getAlbum)
+		mediaGroup.GET("/tracks", m.getTracks)
+		mediaGroup.GET("/composers", m.getComposers)
+		mediaGroup.GET("/composers/:name/works", m.getComposerWorks)
+
 		// TV Shows endpoints
 		mediaGroup.GET("/tv-shows", m.getTVShows)
+		mediaGroup.GET("/tv-shows/:id", m.getTVShow)
+
+		// Home video endpoints
+		mediaGroup.GET("/home-videos", m.getHomeVideos)
+		mediaGroup.GET("/home-videos/timeline", m.getHomeVideoTimeline)
+		mediaGroup.GET("/home-videos/:id", m.getHomeVideo)
+		mediaGroup.PUT("/home-videos/:id", m.updateHomeVideo)
+
+		// Home feed
+		mediaGroup.GET("/up-next", m.getUpNext)
+
+		// Per-user hidden items and libraries
+		mediaGroup.GET("/hidden", m.getHiddenItems)
+		mediaGroup.POST("/hidden", m.hideItem)
+		mediaGroup.DELETE("/hidden/:id", m.unhideItem)
+		mediaGroup.PUT("/libraries/:id/visibility", m.setLibraryVisibility)
+
+		// Favorites and recommendations
+		mediaGroup.GET("/favorites", m.getFavorites)
+		mediaGroup.POST("/favorites", m.addFavorite)
+		mediaGroup.DELETE("/favorites/:type/:id", m.removeFavorite)
+		mediaGroup.GET("/favorites/collection", m.getFavoritesCollection)
+		mediaGroup.GET("/recommendations", m.getRecommendations)
 
 		// Metadata endpoints
 		mediaGroup.POST("/files/:id/metadata/extract", m.extractMetadata)
@@ -259,6 +340,14 @@ func (m *Module) RegisterRoutes(router *gin.Engine) {
 		mediaGroup.GET("/stats", m.getStats)
 	}
 
+	// Movie collections, such as franchises found by enrichment
+	collectionGroup := router.Group("/api/collections")
+	{
+		collectionGroup.GET("", m.getCollections)
+		collectionGroup.GET("/:id", m.getCollection)
+		collectionGroup.GET("/:id/poster", m.getCollectionPoster)
+	}
+
 	log.Println("INFO: 🎬 Media module configured for DASH/HLS-first streaming workflow")
 }
 
@@ -0,0 +1,124 @@
+package mediamodule
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mantonx/viewra/internal/database"
+	"gorm.io/gorm"
+)
+
+// getArtist returns an artist with its MusicBrainz details, relationships
+// and albums
+func (m *Module) getArtist(c *gin.Context) {
+	var artist database.Artist
+	err := m.db.Preload("Relationships", func(db *gorm.DB) *gorm.DB { return db.Order("type, target_name") }).
+		Where("id = ?", c.Param("id")).First(&artist).Error
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Artist not found",
+		})
+		return
+	}
+
+	var albums []database.Album
+	if err := m.db.Where("artist_id = ?", artist.ID).Order("release_date").Find(&albums).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": fmt.Sprintf("Failed to get albums: %v", err),
+		})
+		return
+	}
+
+	// Other library artists that list this one, e.g. the members of a band
+	var related []database.ArtistRelationship
+	if err := m.db.Where("target_artist_id = ?", artist.ID).Order("type").Find(&related).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": fmt.Sprintf("Failed to get related artists: %v", err),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"artist":     artist,
+		"albums":     albums,
+		"related_by": related,
+	})
+}
+
+// getAlbum returns an album with its release details, labels and tracks.
+// Tracks are ordered across discs and also grouped per disc, each with the
+// audio quality of its files; box sets list their albums.
+func (m *Module) getAlbum(c *gin.Context) {
+	var album database.Album
+	err := m.db.Preload("Artist").
+		Preload("Labels", func(db *gorm.DB) *gorm.DB { return db.Order("position") }).
+		Where("id = ?", c.Param("id")).First(&album).Error
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Album not found",
+		})
+		return
+	}
+
+	var tracks []database.Track
+	if err := m.db.Where("album_id = ?", album.ID).Order("disc_number, track_number, title").Find(&tracks).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": fmt.Sprintf("Failed to get tracks: %v", err),
+		})
+		return
+	}
+
+	tracksWithVersions, err := m.withVersions(tracks)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": fmt.Sprintf("Failed to get track files: %v", err),
+		})
+		return
+	}
+
+	// Albums of a box set, in box order
+	var children []database.Album
+	if err := m.db.Where("parent_album_id = ?", album.ID).Order("box_set_position, title").Find(&children).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": fmt.Sprintf("Failed to get box set albums: %v", err),
+		})
+		return
+	}
+
+	response := gin.H{
+		"album":  album,
+		"tracks": tracksWithVersions,
+		"discs":  groupTracksByDisc(tracksWithVersions),
+		"albums": children,
+	}
+	if album.ParentAlbumID != nil {
+		var parent database.Album
+		if err := m.db.Where("id = ?", *album.ParentAlbumID).First(&parent).Error; err == nil {
+			response["box_set"] = parent
+		}
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// albumDisc is one disc of an album with its tracks in order
+type albumDisc struct {
+	DiscNumber int                 `json:"disc_number"`
+	Tracks     []trackWithVersions `json:"tracks"`
+}
+
+// groupTracksByDisc splits tracks ordered by disc and track number into discs
+func groupTracksByDisc(tracks []trackWithVersions) []albumDisc {
+	discs := []albumDisc{}
+	for _, track := range tracks {
+		disc := track.DiscNumber
+		if disc == 0 {
+			disc = 1
+		}
+		if len(discs) == 0 || discs[len(discs)-1].DiscNumber != disc {
+			discs = append(discs, albumDisc{DiscNumber: disc})
+		}
+		discs[len(discs)-1].Tracks = append(discs[len(discs)-1].Tracks, track)
+	}
+	return discs
+}
@@ -5,11 +5,14 @@ import (
 	"fmt"
 	"log"
 	"net/http"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/mantonx/viewra/internal/auth"
 	"github.com/mantonx/viewra/internal/services"
+	"github.com/mantonx/viewra/internal/streamurl"
 	"github.com/mantonx/viewra/internal/types"
 	plugins "github.com/mantonx/viewra/sdk"
 	"gorm.io/gorm"
@@ -254,12 +257,7 @@ func (pi *PlaybackIntegration) handleTranscodingStream(c *gin.Context, mediaFile
 		log.Printf("🎬 ADAPTIVE STREAMING: Returning session info for %s adaptive streaming instead of progressive stream", strings.ToUpper(targetContainer))
 
 		// For DASH/HLS, return session information so frontend can construct manifest URLs
-		manifestEndpoint := ""
-		if targetContainer == "dash" {
-			manifestEndpoint = fmt.Sprintf("/api/playback/stream/%s/manifest.mpd", session.ID)
-		} else {
-			manifestEndpoint = fmt.Sprintf("/api/playback/stream/%s/playlist.m3u8", session.ID)
-		}
+		manifestEndpoint := streamurl.Manifest(session.ID, requestUserID(c), targetContainer)
 
 		log.Printf("🔍 [TELEMETRY] Adaptive streaming session info returned: session_id=%s container=%s manifest_url=%s setup_duration=%v",
 			session.ID, targetContainer, manifestEndpoint, time.Since(sessionStartTime))
@@ -357,7 +355,7 @@ func (pi *PlaybackIntegration) handleTranscodingStream(c *gin.Context, mediaFile
 		"session_id":     session.ID,
 		"provider":       sessionInfo.Provider,
 		"status":         string(sessionInfo.Status),
-		"stream_url":     fmt.Sprintf("/api/playback/stream/%s", session.ID),
+		"stream_url":     "/api/playback/stream/" + streamurl.Token(session.ID, requestUserID(c)),
 		"container":      targetContainer,
 		"resolution":     targetResolution,
 		"message":        "Transcoding session started - use stream_url for progressive streaming",
@@ -383,6 +381,13 @@ func (pi *PlaybackIntegration) serveDirectStream(c *gin.Context, mediaFile *Medi
 	c.File(mediaFile.Path)
 }
 
+// requestUserID returns the user stream URLs are issued to: the caller, or
+// the user_id query parameter when authentication is disabled
+func requestUserID(c *gin.Context) uint32 {
+	named, _ := strconv.ParseUint(c.Query("user_id"), 10, 32)
+	return auth.RequestUserID(c, uint32(named))
+}
+
 // createDeviceProfileFromRequest creates a device profile from the HTTP request
 func (pi *PlaybackIntegration) createDeviceProfileFromRequest(c *gin.Context) *types.DeviceProfile {
 	userAgent := c.GetHeader("User-Agent")
@@ -409,6 +414,12 @@ func (pi *PlaybackIntegration) createDeviceProfileFromRequest(c *gin.Context) *t
 		}
 	}
 
+	// A registered device's stored capabilities replace the sniffed ones
+	deviceID := c.GetHeader("X-Device-ID")
+	if deviceID == "" {
+		deviceID = c.Query("device_id")
+	}
+
 	// Check for explicit quality preference
 	preferredQuality := c.Query("quality")
 	if preferredQuality != "" {
@@ -425,6 +436,7 @@ func (pi *PlaybackIntegration) createDeviceProfileFromRequest(c *gin.Context) *t
 		SupportsAV1:     supportsAV1,
 		SupportsHDR:     false, // Detected separately if needed
 		ClientIP:        clientIP,
+		DeviceID:        deviceID,
 	}
 }
 
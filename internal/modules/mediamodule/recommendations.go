@@ -0,0 +1,338 @@
+package mediamodule
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mantonx/viewra/internal/database"
+	"gorm.io/gorm"
+)
+
+const (
+	// Recommendation score weights
+	favoriteGenreWeight  = 3.0 // Per genre the user favorited
+	favoritePersonWeight = 2.0 // Per favorite person in the cast or crew
+	likedGenreWeight     = 1.0 // Per favorite movie sharing a genre
+	ratingWeight         = 0.1 // Per point of TMDb rating, to break ties
+
+	defaultRecommendationLimit = 20
+	maxRecommendationLimit     = 100
+)
+
+// Recommendation is a movie or show suggested to a user, with why
+type Recommendation struct {
+	MediaType string   `json:"media_type"` // movie or tv_show
+	ID        string   `json:"id"`
+	Title     string   `json:"title"`
+	Poster    string   `json:"poster,omitempty"`
+	Score     float64  `json:"score"`
+	Reasons   []string `json:"reasons"`
+}
+
+// recommender scores unwatched movies and shows for a user from their
+// favorite genres, people and media
+type recommender struct {
+	db         *gorm.DB
+	userID     uint32
+	visibility *userVisibility
+
+	favoriteGenres map[string]string // Lowercase genre -> name as favorited
+	likedGenres    map[string]int    // Lowercase genre -> favorite movies with it
+	favoritePeople map[string]string // Person ID -> name
+	favoriteMedia  map[string]bool   // Favorite movie and show IDs
+}
+
+// Recommend returns up to limit recommendations, best first
+func (r *recommender) Recommend(limit int) ([]Recommendation, error) {
+	visibility, err := newUserVisibility(r.db, r.userID)
+	if err != nil {
+		return nil, err
+	}
+	r.visibility = visibility
+	if err := r.loadFavorites(); err != nil {
+		return nil, err
+	}
+
+	movies, err := r.scoreMovies()
+	if err != nil {
+		return nil, err
+	}
+	shows, err := r.scoreShows()
+	if err != nil {
+		return nil, err
+	}
+
+	recommendations := append(movies, shows...)
+	sort.SliceStable(recommendations, func(i, j int) bool {
+		if recommendations[i].Score != recommendations[j].Score {
+			return recommendations[i].Score > recommendations[j].Score
+		}
+		return recommendations[i].Title < recommendations[j].Title
+	})
+	if len(recommendations) > limit {
+		recommendations = recommendations[:limit]
+	}
+	return recommendations, nil
+}
+
+// loadFavorites reads the user's favorites and the genres of their favorite
+// movies
+func (r *recommender) loadFavorites() error {
+	var favorites []database.UserFavorite
+	if err := r.db.Where("user_id = ?", r.userID).Find(&favorites).Error; err != nil {
+		return fmt.Errorf("failed to load favorites: %w", err)
+	}
+
+	r.favoriteGenres = make(map[string]string)
+	r.likedGenres = make(map[string]int)
+	r.favoritePeople = make(map[string]string)
+	r.favoriteMedia = make(map[string]bool)
+
+	var movieIDs []string
+	for _, favorite := range favorites {
+		switch favorite.TargetType {
+		case "genre":
+			r.favoriteGenres[favorite.TargetID] = favorite.Name
+		case "person":
+			r.favoritePeople[favorite.TargetID] = favorite.Name
+		case "movie":
+			r.favoriteMedia[favorite.TargetID] = true
+			movieIDs = append(movieIDs, favorite.TargetID)
+		case "tv_show":
+			r.favoriteMedia[favorite.TargetID] = true
+		}
+	}
+
+	if len(movieIDs) > 0 {
+		var genres []string
+		if err := r.db.Model(&database.Movie{}).Where("id IN ?", movieIDs).Pluck("genres", &genres).Error; err != nil {
+			return fmt.Errorf("failed to load favorite movie genres: %w", err)
+		}
+		for _, raw := range genres {
+			for _, genre := range parseGenres(raw) {
+				r.likedGenres[strings.ToLower(genre)]++
+			}
+		}
+	}
+	return nil
+}
+
+// scoreMovies scores the movies the user has a file for, hasn't finished,
+// hasn't hidden and hasn't already favorited
+func (r *recommender) scoreMovies() ([]Recommendation, error) {
+	finished := r.db.Model(&database.PlaybackSession{}).Select("media_id").
+		Where("user_id = ? AND completed = ? AND media_id IS NOT NULL", r.userID, true)
+	playable := r.db.Model(&database.MediaFile{}).Select("media_id").
+		Where("media_type = ?", database.MediaTypeMovie)
+
+	query := r.db.Model(&database.Movie{}).
+		Select("id, title, poster, genres, tmdb_rating").
+		Where("id IN (?) AND id NOT IN (?)", playable, finished)
+	query = r.visibility.Movies(query, "id")
+
+	var movies []database.Movie
+	if err := query.Find(&movies).Error; err != nil {
+		return nil, fmt.Errorf("failed to load movies: %w", err)
+	}
+
+	ids := make([]string, 0, len(movies))
+	for _, movie := range movies {
+		ids = append(ids, movie.ID)
+	}
+	people, err := r.peopleIn(ids, database.MediaTypeMovie)
+	if err != nil {
+		return nil, err
+	}
+
+	recommendations := make([]Recommendation, 0, len(movies))
+	for _, movie := range movies {
+		if r.favoriteMedia[movie.ID] {
+			continue
+		}
+		rec := Recommendation{MediaType: "movie", ID: movie.ID, Title: movie.Title, Poster: movie.Poster, Reasons: []string{}}
+
+		liked := 0
+		for _, genre := range parseGenres(movie.Genres) {
+			key := strings.ToLower(genre)
+			if name, ok := r.favoriteGenres[key]; ok {
+				rec.Score += favoriteGenreWeight
+				rec.Reasons = append(rec.Reasons, "Favorite genre: "+name)
+			}
+			liked += r.likedGenres[key]
+		}
+		if liked > 0 {
+			rec.Score += likedGenreWeight * float64(liked)
+			rec.Reasons = append(rec.Reasons, "Like your favorite movies")
+		}
+		r.addPeople(&rec, people[movie.ID])
+		rec.Score += ratingWeight * movie.TmdbRating
+
+		recommendations = append(recommendations, rec)
+	}
+	return recommendations, nil
+}
+
+// scoreShows scores the shows with episodes the user has a file for but
+// hasn't started, hasn't hidden and hasn't already favorited. Shows in
+// progress belong to Up Next.
+func (r *recommender) scoreShows() ([]Recommendation, error) {
+	started := r.db.Table("playback_sessions").Select("seasons.tv_show_id").
+		Joins("JOIN episodes ON episodes.id = playback_sessions.media_id").
+		Joins("JOIN seasons ON seasons.id = episodes.season_id").
+		Where("playback_sessions.user_id = ?", r.userID)
+	playable := r.db.Table("media_files").Select("seasons.tv_show_id").
+		Joins("JOIN episodes ON episodes.id = media_files.media_id").
+		Joins("JOIN seasons ON seasons.id = episodes.season_id").
+		Where("media_files.media_type = ?", database.MediaTypeEpisode)
+
+	query := r.db.Model(&database.TVShow{}).
+		Select("id, title, poster").
+		Where("id IN (?) AND id NOT IN (?)", playable, started)
+	query = r.visibility.Shows(query, "id")
+
+	var shows []database.TVShow
+	if err := query.Find(&shows).Error; err != nil {
+		return nil, fmt.Errorf("failed to load shows: %w", err)
+	}
+
+	// Cast and crew are recorded per episode
+	var credits []struct {
+		TVShowID string
+		PersonID string
+	}
+	if len(r.favoritePeople) > 0 && len(shows) > 0 {
+		ids := make([]string, len(shows))
+		for i, show := range shows {
+			ids[i] = show.ID
+		}
+		if err := r.db.Table("roles").
+			Select("DISTINCT seasons.tv_show_id, roles.person_id").
+			Joins("JOIN episodes ON episodes.id = roles.media_id").
+			Joins("JOIN seasons ON seasons.id = episodes.season_id").
+			Where("roles.media_type = ? AND seasons.tv_show_id IN ? AND roles.person_id IN ?",
+				database.MediaTypeEpisode, ids, r.favoritePersonIDs()).
+			Scan(&credits).Error; err != nil {
+			return nil, fmt.Errorf("failed to load show credits: %w", err)
+		}
+	}
+	people := make(map[string][]string)
+	for _, credit := range credits {
+		people[credit.TVShowID] = append(people[credit.TVShowID], credit.PersonID)
+	}
+
+	recommendations := make([]Recommendation, 0, len(shows))
+	for _, show := range shows {
+		if r.favoriteMedia[show.ID] {
+			continue
+		}
+		rec := Recommendation{MediaType: "tv_show", ID: show.ID, Title: show.Title, Poster: show.Poster, Reasons: []string{}}
+		r.addPeople(&rec, people[show.ID])
+		recommendations = append(recommendations, rec)
+	}
+	return recommendations, nil
+}
+
+// peopleIn returns the favorite people credited on each item
+func (r *recommender) peopleIn(mediaIDs []string, mediaType database.MediaType) (map[string][]string, error) {
+	people := make(map[string][]string)
+	if len(r.favoritePeople) == 0 || len(mediaIDs) == 0 {
+		return people, nil
+	}
+
+	var roles []database.Roles
+	if err := r.db.Select("DISTINCT media_id, person_id").
+		Where("media_type = ? AND media_id IN ? AND person_id IN ?", mediaType, mediaIDs, r.favoritePersonIDs()).
+		Find(&roles).Error; err != nil {
+		return nil, fmt.Errorf("failed to load credits: %w", err)
+	}
+	for _, role := range roles {
+		people[role.MediaID] = append(people[role.MediaID], role.PersonID)
+	}
+	return people, nil
+}
+
+// favoritePersonIDs lists the IDs of the user's favorite people
+func (r *recommender) favoritePersonIDs() []string {
+	ids := make([]string, 0, len(r.favoritePeople))
+	for id := range r.favoritePeople {
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+// addPeople scores the favorite people credited on a recommendation
+func (r *recommender) addPeople(rec *Recommendation, personIDs []string) {
+	sort.Strings(personIDs)
+	for _, id := range personIDs {
+		rec.Score += favoritePersonWeight
+		rec.Reasons = append(rec.Reasons, "With "+r.favoritePeople[id])
+	}
+}
+
+// parseGenres reads a genres column, stored as a JSON array of names or of
+// {"name": ...} objects, or as comma-separated names
+func parseGenres(raw string) []string {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return nil
+	}
+
+	var names []string
+	if err := json.Unmarshal([]byte(raw), &names); err == nil {
+		return names
+	}
+	var objects []struct {
+		Name string `json:"name"`
+	}
+	if err := json.Unmarshal([]byte(raw), &objects); err == nil {
+		names = make([]string, 0, len(objects))
+		for _, object := range objects {
+			if object.Name != "" {
+				names = append(names, object.Name)
+			}
+		}
+		return names
+	}
+	if strings.HasPrefix(raw, "[") {
+		return nil // e.g. bare TMDb genre IDs
+	}
+
+	for _, name := range strings.Split(raw, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// getRecommendations returns movies and shows for the user in user_id, scored
+// by their favorite genres, people and media
+func (m *Module) getRecommendations(c *gin.Context) {
+	userID, ok := parseWatchUserID(c)
+	if !ok {
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(defaultRecommendationLimit)))
+	if err != nil || limit < 1 || limit > maxRecommendationLimit {
+		limit = defaultRecommendationLimit
+	}
+
+	recommendations, err := (&recommender{db: m.db, userID: userID}).Recommend(limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": fmt.Sprintf("Failed to get recommendations: %v", err),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"recommendations": recommendations,
+		"count":           len(recommendations),
+	})
+}
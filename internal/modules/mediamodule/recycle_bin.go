@@ -0,0 +1,546 @@
+package mediamodule
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mantonx/viewra/internal/auth"
+	"github.com/mantonx/viewra/internal/config"
+	"github.com/mantonx/viewra/internal/database"
+	"github.com/mantonx/viewra/internal/events"
+	"github.com/mantonx/viewra/internal/modules/subtitlemodule"
+	"github.com/mantonx/viewra/internal/utils"
+	"gorm.io/gorm"
+)
+
+const (
+	// recycleBinPurgeInterval is how often expired files are purged
+	recycleBinPurgeInterval = time.Hour
+
+	// defaultRecycleRetention applies when no positive retention is configured
+	defaultRecycleRetention = 30 * 24 * time.Hour
+
+	// maxBulkDelete bounds the files deleted by one request
+	maxBulkDelete = 500
+)
+
+// Statuses of a file in a bulk delete
+const (
+	recycleStatusRecycled = "recycled"
+	recycleStatusRemoved  = "removed" // The file was already gone, so only its row was deleted
+	recycleStatusNotFound = "not_found"
+	recycleStatusFailed   = "failed"
+)
+
+// Reasons a recycled file can't be restored
+var (
+	errRecycledNotFound = errors.New("recycled file not found")
+	errRecycledExpired  = errors.New("recycled file has expired")
+	errRestoreConflict  = errors.New("another file is at the original path")
+	errLibraryGone      = errors.New("the file's library no longer exists")
+	errRecycleInLibrary = errors.New("the recycle bin is inside a library")
+)
+
+// recycledSidecar is a subtitle file recycled with its media file
+type recycledSidecar struct {
+	OriginalPath string `json:"original_path"`
+	RecyclePath  string `json:"recycle_path"`
+}
+
+// bulkDeleteResult is the outcome for one file of a bulk delete
+type bulkDeleteResult struct {
+	MediaFileID string                 `json:"media_file_id"`
+	Status      string                 `json:"status"`
+	Recycled    *database.RecycledFile `json:"recycled,omitempty"`
+	Error       string                 `json:"error,omitempty"`
+}
+
+// recycleFile moves a media file and its subtitles into the recycle bin and
+// removes the file from its library. The row is deleted before the file is
+// moved, so the file monitor finds nothing left to remove; the movie,
+// episode or track it belongs to is kept for a restore. A file already gone
+// from disk has nothing to recycle, so only its row is deleted and no entry
+// is returned.
+func (m *Module) recycleFile(mediaFile *database.MediaFile, deletedBy *uint32) (*database.RecycledFile, error) {
+	cfg := config.Get().RecycleBin
+	if err := m.checkRecycleDirectory(cfg.Directory); err != nil {
+		return nil, err
+	}
+	if _, err := os.Stat(mediaFile.Path); errors.Is(err, os.ErrNotExist) {
+		return nil, m.removeMissingFile(mediaFile)
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to find %s: %w", mediaFile.Path, err)
+	}
+
+	snapshot, err := json.Marshal(mediaFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to snapshot media file: %w", err)
+	}
+	retention := cfg.Retention
+	if retention <= 0 {
+		retention = defaultRecycleRetention
+	}
+	now := time.Now()
+	entry := &database.RecycledFile{
+		ID:           utils.GenerateUUID(),
+		MediaFileID:  mediaFile.ID,
+		MediaID:      mediaFile.MediaID,
+		MediaType:    mediaFile.MediaType,
+		LibraryID:    mediaFile.LibraryID,
+		OriginalPath: mediaFile.Path,
+		SizeBytes:    mediaFile.SizeBytes,
+		Snapshot:     string(snapshot),
+		DeletedBy:    deletedBy,
+		RecycledAt:   now,
+		ExpiresAt:    now.Add(retention),
+	}
+	entryDir := filepath.Join(cfg.Directory, entry.ID)
+	entry.RecyclePath = filepath.Join(entryDir, filepath.Base(mediaFile.Path))
+	if err := os.MkdirAll(entryDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create recycle bin directory: %w", err)
+	}
+
+	err = m.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(entry).Error; err != nil {
+			return fmt.Errorf("failed to record recycled file: %w", err)
+		}
+		if err := tx.Delete(mediaFile).Error; err != nil {
+			return fmt.Errorf("failed to remove media file: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		os.Remove(entryDir)
+		return nil, err
+	}
+
+	if err := utils.MoveFile(mediaFile.Path, entry.RecyclePath); err != nil {
+		// Put the library back the way it was
+		m.db.Delete(entry)
+		if restoreErr := m.db.Create(mediaFile).Error; restoreErr != nil {
+			log.Printf("ERROR: Failed to restore media file %s after a failed recycle: %v", mediaFile.ID, restoreErr)
+		}
+		os.Remove(entryDir)
+		return nil, fmt.Errorf("failed to move %s to the recycle bin: %w", mediaFile.Path, err)
+	}
+
+	sidecars := recycleSidecars(mediaFile.Path, entryDir)
+	if len(sidecars) > 0 {
+		if data, err := json.Marshal(sidecars); err == nil {
+			entry.Sidecars = string(data)
+			m.db.Model(entry).Update("sidecars", entry.Sidecars)
+		}
+	}
+
+	log.Printf("INFO: Moved %s to the recycle bin (restorable until %s)", mediaFile.Path, entry.ExpiresAt.Format(time.RFC3339))
+	if m.eventBus != nil {
+		event := events.NewSystemEvent(
+			events.EventMediaFileDeleted,
+			"Media File Deleted",
+			fmt.Sprintf("Moved %s to the recycle bin", filepath.Base(mediaFile.Path)),
+		)
+		event.Data = map[string]interface{}{
+			"media_file_id": mediaFile.ID,
+			"media_id":      mediaFile.MediaID,
+			"library_id":    mediaFile.LibraryID,
+			"path":          mediaFile.Path,
+			"recycle_id":    entry.ID,
+			"expires_at":    entry.ExpiresAt,
+			"reason":        "recycled",
+		}
+		m.eventBus.PublishAsync(event)
+	}
+	return entry, nil
+}
+
+// removeMissingFile removes a media file that is no longer on disk from its
+// library
+func (m *Module) removeMissingFile(mediaFile *database.MediaFile) error {
+	if err := m.db.Delete(mediaFile).Error; err != nil {
+		return fmt.Errorf("failed to remove media file: %w", err)
+	}
+
+	log.Printf("INFO: Removed %s from its library; the file was already gone", mediaFile.Path)
+	if m.eventBus != nil {
+		event := events.NewSystemEvent(
+			events.EventMediaFileDeleted,
+			"Media File Deleted",
+			fmt.Sprintf("Removed missing file %s", filepath.Base(mediaFile.Path)),
+		)
+		event.Data = map[string]interface{}{
+			"media_file_id": mediaFile.ID,
+			"media_id":      mediaFile.MediaID,
+			"library_id":    mediaFile.LibraryID,
+			"path":          mediaFile.Path,
+			"reason":        "missing",
+		}
+		m.eventBus.PublishAsync(event)
+	}
+	return nil
+}
+
+// recycleSidecars moves a media file's subtitles into its recycle bin
+// directory. Subtitles that fail to move are left behind.
+func recycleSidecars(mediaPath, entryDir string) []recycledSidecar {
+	found, err := subtitlemodule.FindSidecars(mediaPath)
+	if err != nil {
+		return nil
+	}
+	var sidecars []recycledSidecar
+	for _, sidecar := range found {
+		target := filepath.Join(entryDir, filepath.Base(sidecar.Path))
+		if err := utils.MoveFile(sidecar.Path, target); err != nil {
+			log.Printf("WARN: Failed to move subtitle %s to the recycle bin: %v", sidecar.Path, err)
+			continue
+		}
+		sidecars = append(sidecars, recycledSidecar{OriginalPath: sidecar.Path, RecyclePath: target})
+	}
+	return sidecars
+}
+
+// restoreFile puts a recycled file, its subtitles and its media_files row
+// back where they were
+func (m *Module) restoreFile(id string) (*database.MediaFile, error) {
+	entry, err := m.loadRecycledFile(id)
+	if err != nil {
+		return nil, err
+	}
+	if !time.Now().Before(entry.ExpiresAt) {
+		return nil, errRecycledExpired
+	}
+
+	var mediaFile database.MediaFile
+	if err := json.Unmarshal([]byte(entry.Snapshot), &mediaFile); err != nil {
+		return nil, fmt.Errorf("failed to read media file snapshot: %w", err)
+	}
+
+	var libraries int64
+	if err := m.db.Model(&database.MediaLibrary{}).Where("id = ?", entry.LibraryID).Count(&libraries).Error; err != nil {
+		return nil, fmt.Errorf("failed to load library: %w", err)
+	}
+	if libraries == 0 {
+		return nil, errLibraryGone
+	}
+	if _, err := os.Lstat(entry.OriginalPath); err == nil {
+		return nil, errRestoreConflict
+	}
+	var existing int64
+	if err := m.db.Model(&database.MediaFile{}).Where("path = ? OR id = ?", entry.OriginalPath, mediaFile.ID).
+		Count(&existing).Error; err != nil {
+		return nil, fmt.Errorf("failed to check media files: %w", err)
+	}
+	if existing > 0 {
+		return nil, errRestoreConflict
+	}
+
+	if err := os.MkdirAll(filepath.Dir(entry.OriginalPath), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create %s: %w", filepath.Dir(entry.OriginalPath), err)
+	}
+	// The row goes back first so the file monitor sees a known file appear
+	if err := m.db.Create(&mediaFile).Error; err != nil {
+		return nil, fmt.Errorf("failed to restore media file: %w", err)
+	}
+	if err := utils.MoveFile(entry.RecyclePath, entry.OriginalPath); err != nil {
+		m.db.Delete(&mediaFile)
+		return nil, fmt.Errorf("failed to move %s back: %w", entry.OriginalPath, err)
+	}
+
+	var sidecars []recycledSidecar
+	if entry.Sidecars != "" {
+		json.Unmarshal([]byte(entry.Sidecars), &sidecars)
+	}
+	for _, sidecar := range sidecars {
+		if err := utils.MoveFile(sidecar.RecyclePath, sidecar.OriginalPath); err != nil {
+			log.Printf("WARN: Failed to restore subtitle %s: %v", sidecar.OriginalPath, err)
+		}
+	}
+
+	if err := m.db.Delete(entry).Error; err != nil {
+		log.Printf("WARN: Failed to remove restored file %s from the recycle bin: %v", entry.ID, err)
+	}
+	os.RemoveAll(filepath.Dir(entry.RecyclePath))
+
+	log.Printf("INFO: Restored %s from the recycle bin", entry.OriginalPath)
+	if m.eventBus != nil {
+		event := events.NewSystemEvent(
+			events.EventMediaFileRestored,
+			"Media File Restored",
+			fmt.Sprintf("Restored %s from the recycle bin", filepath.Base(entry.OriginalPath)),
+		)
+		event.Data = map[string]interface{}{
+			"media_file_id": mediaFile.ID,
+			"media_id":      mediaFile.MediaID,
+			"library_id":    mediaFile.LibraryID,
+			"path":          mediaFile.Path,
+		}
+		m.eventBus.PublishAsync(event)
+	}
+	return &mediaFile, nil
+}
+
+// purgeRecycled deletes a recycled file and its subtitles for good
+func (m *Module) purgeRecycled(entry *database.RecycledFile) error {
+	if err := os.RemoveAll(filepath.Dir(entry.RecyclePath)); err != nil {
+		return fmt.Errorf("failed to remove %s: %w", entry.RecyclePath, err)
+	}
+	if err := m.db.Delete(entry).Error; err != nil {
+		return fmt.Errorf("failed to remove recycled file %s: %w", entry.ID, err)
+	}
+	return nil
+}
+
+// purgeRecycledFiles purges the expired recycled files, or all of them,
+// returning how many were purged
+func (m *Module) purgeRecycledFiles(all bool) (int, error) {
+	query := m.db.Model(&database.RecycledFile{})
+	if !all {
+		query = query.Where("expires_at <= ?", time.Now())
+	}
+	var entries []database.RecycledFile
+	if err := query.Find(&entries).Error; err != nil {
+		return 0, fmt.Errorf("failed to load recycled files: %w", err)
+	}
+	purged := 0
+	for i := range entries {
+		if err := m.purgeRecycled(&entries[i]); err != nil {
+			log.Printf("WARN: %v", err)
+			continue
+		}
+		purged++
+	}
+	return purged, nil
+}
+
+// startRecycleBinPurge purges expired recycled files now and then hourly
+func (m *Module) startRecycleBinPurge() {
+	ticker := time.NewTicker(recycleBinPurgeInterval)
+	defer ticker.Stop()
+	for {
+		purged, err := m.purgeRecycledFiles(false)
+		if err != nil {
+			log.Printf("WARNING: Recycle bin purge failed: %v", err)
+		} else if purged > 0 {
+			log.Printf("INFO: Purged %d expired files from the recycle bin", purged)
+		}
+		<-ticker.C
+	}
+}
+
+// checkRecycleDirectory refuses a recycle bin inside a library, where
+// scans would pick deleted files up again
+func (m *Module) checkRecycleDirectory(dir string) error {
+	if dir == "" {
+		return errors.New("no recycle bin directory configured")
+	}
+	var libraries []database.MediaLibrary
+	if err := m.db.Find(&libraries).Error; err != nil {
+		return fmt.Errorf("failed to load libraries: %w", err)
+	}
+	for _, library := range libraries {
+		if utils.WithinDir(dir, library.Path) {
+			return errRecycleInLibrary
+		}
+	}
+	return nil
+}
+
+func (m *Module) loadRecycledFile(id string) (*database.RecycledFile, error) {
+	var entry database.RecycledFile
+	if err := m.db.Where("id = ?", id).First(&entry).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errRecycledNotFound
+		}
+		return nil, fmt.Errorf("failed to load recycled file: %w", err)
+	}
+	return &entry, nil
+}
+
+// refreshLibraryStorage resamples the size of libraries files left or
+// returned to
+func (m *Module) refreshLibraryStorage(libraryIDs map[uint32]bool) {
+	for libraryID := range libraryIDs {
+		if _, err := m.sampleLibraryStorage(libraryID); err != nil {
+			log.Printf("WARN: Failed to sample storage of library %d: %v", libraryID, err)
+		}
+	}
+}
+
+// deletedBy returns the signed-in user deleting files, if any
+func deletedBy(c *gin.Context) *uint32 {
+	if user, ok := auth.CurrentUser(c); ok {
+		return &user.ID
+	}
+	return nil
+}
+
+// deleteFile moves a media file to the recycle bin
+func (m *Module) deleteFile(c *gin.Context) {
+	var mediaFile database.MediaFile
+	if err := m.db.Where("id = ?", c.Param("id")).First(&mediaFile).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Media file not found"})
+		return
+	}
+
+	entry, err := m.recycleFile(&mediaFile, deletedBy(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": fmt.Sprintf("Failed to delete media file: %v", err),
+		})
+		return
+	}
+	m.refreshLibraryStorage(map[uint32]bool{mediaFile.LibraryID: true})
+
+	if entry == nil {
+		c.JSON(http.StatusOK, gin.H{
+			"message": "Media file was already missing and has been removed from its library",
+			"id":      mediaFile.ID,
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"message":  "Media file moved to the recycle bin",
+		"id":       mediaFile.ID,
+		"recycled": entry,
+	})
+}
+
+// deleteFiles moves several media files to the recycle bin, reporting
+// the outcome for each
+func (m *Module) deleteFiles(c *gin.Context) {
+	var req struct {
+		MediaFileIDs []string `json:"media_file_ids" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Invalid request: %v", err)})
+		return
+	}
+	if len(req.MediaFileIDs) == 0 || len(req.MediaFileIDs) > maxBulkDelete {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": fmt.Sprintf("media_file_ids must name between 1 and %d files", maxBulkDelete),
+		})
+		return
+	}
+
+	user := deletedBy(c)
+	libraries := make(map[uint32]bool)
+	results := make([]bulkDeleteResult, 0, len(req.MediaFileIDs))
+	recycled := 0
+	for _, id := range req.MediaFileIDs {
+		result := bulkDeleteResult{MediaFileID: id}
+		var mediaFile database.MediaFile
+		if err := m.db.Where("id = ?", id).First(&mediaFile).Error; err != nil {
+			result.Status = recycleStatusNotFound
+			results = append(results, result)
+			continue
+		}
+		entry, err := m.recycleFile(&mediaFile, user)
+		if err != nil {
+			result.Status = recycleStatusFailed
+			result.Error = err.Error()
+		} else {
+			result.Status = recycleStatusRecycled
+			if entry == nil {
+				result.Status = recycleStatusRemoved
+			}
+			result.Recycled = entry
+			libraries[mediaFile.LibraryID] = true
+			recycled++
+		}
+		results = append(results, result)
+	}
+	m.refreshLibraryStorage(libraries)
+
+	c.JSON(http.StatusOK, gin.H{
+		"results":  results,
+		"recycled": recycled,
+		"failed":   len(results) - recycled,
+	})
+}
+
+// getRecycleBin lists the files in the recycle bin, most recently deleted
+// first, optionally for one library (`?library_id=`)
+func (m *Module) getRecycleBin(c *gin.Context) {
+	query := m.db.Model(&database.RecycledFile{})
+	if libraryID := c.Query("library_id"); libraryID != "" {
+		query = query.Where("library_id = ?", libraryID)
+	}
+	var entries []database.RecycledFile
+	if err := query.Order("recycled_at DESC").Find(&entries).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": fmt.Sprintf("Failed to get recycle bin: %v", err),
+		})
+		return
+	}
+
+	var totalBytes int64
+	for _, entry := range entries {
+		totalBytes += entry.SizeBytes
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"files":       entries,
+		"count":       len(entries),
+		"total_bytes": totalBytes,
+		"retention":   config.Get().RecycleBin.Retention.String(),
+	})
+}
+
+// restoreRecycledFile puts a file in the recycle bin back in its library
+func (m *Module) restoreRecycledFile(c *gin.Context) {
+	mediaFile, err := m.restoreFile(c.Param("id"))
+	switch {
+	case errors.Is(err, errRecycledNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	case errors.Is(err, errRecycledExpired):
+		c.JSON(http.StatusGone, gin.H{"error": err.Error()})
+		return
+	case errors.Is(err, errRestoreConflict), errors.Is(err, errLibraryGone):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": fmt.Sprintf("Failed to restore media file: %v", err),
+		})
+		return
+	}
+	m.refreshLibraryStorage(map[uint32]bool{mediaFile.LibraryID: true})
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":    "Media file restored",
+		"media_file": mediaFile,
+	})
+}
+
+// purgeRecycledFile deletes a file in the recycle bin for good
+func (m *Module) purgeRecycledFile(c *gin.Context) {
+	entry, err := m.loadRecycledFile(c.Param("id"))
+	if errors.Is(err, errRecycledNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	if err == nil {
+		err = m.purgeRecycled(entry)
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Recycled file purged", "id": entry.ID})
+}
+
+// emptyRecycleBin deletes every file in the recycle bin for good
+func (m *Module) emptyRecycleBin(c *gin.Context) {
+	purged, err := m.purgeRecycledFiles(true)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Recycle bin emptied", "purged": purged})
+}
@@ -16,10 +16,18 @@ import (
 	"github.com/google/uuid"
 	"github.com/mantonx/viewra/internal/database"
 	"github.com/mantonx/viewra/internal/logger"
+	"github.com/mantonx/viewra/internal/utils"
+	"gorm.io/gorm"
 )
 
-// getLibraries returns all media libraries
+// getLibraries returns all media libraries, leaving out the ones the user in
+// user_id has hidden
 func (m *Module) getLibraries(c *gin.Context) {
+	visibility, ok := m.visibilityFor(c)
+	if !ok {
+		return
+	}
+
 	libraries, err := m.libraryManager.GetAllLibraries()
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
@@ -27,6 +35,14 @@ func (m *Module) getLibraries(c *gin.Context) {
 		})
 		return
 	}
+	if visibility != nil {
+		if libraries, err = visibility.Libraries(libraries); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error": fmt.Sprintf("Failed to get hidden libraries: %v", err),
+			})
+			return
+		}
+	}
 
 	c.JSON(http.StatusOK, gin.H{
 		"libraries": libraries,
@@ -167,14 +183,26 @@ func (m *Module) getLibraryFiles(c *gin.Context) {
 		offset = 0
 	}
 
+	visibility, ok := m.visibilityFor(c)
+	if !ok {
+		return
+	}
+	query := func() *gorm.DB {
+		query := m.db.Model(&database.MediaFile{}).Where("library_id = ?", id)
+		if visibility != nil {
+			query = visibility.Files(query, "media_files")
+		}
+		return query
+	}
+
 	var mediaFiles []database.MediaFile
 	var total int64
 
 	// Get total count
-	m.db.Model(&database.MediaFile{}).Where("library_id = ?", id).Count(&total)
+	query().Count(&total)
 
 	// Get paginated results
-	result := m.db.Where("library_id = ?", id).
+	result := query().
 		Limit(limit).
 		Offset(offset).
 		Order("path").
@@ -212,14 +240,36 @@ func (m *Module) getFiles(c *gin.Context) {
 		offset = 0
 	}
 
+	// Optional audio quality filters, e.g. ?lossless=true
+	filter, err := utils.ParseAudioQualityFilter(c.Query)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	// Leave out what the user in user_id has hidden
+	visibility, ok := m.visibilityFor(c)
+	if !ok {
+		return
+	}
+	query := func() *gorm.DB {
+		query := filter.Apply(m.db.Model(&database.MediaFile{}), "media_files")
+		if visibility != nil {
+			query = visibility.Files(query, "media_files")
+		}
+		return query
+	}
+
 	var mediaFiles []database.MediaFile
 	var total int64
 
 	// Get total count
-	m.db.Model(&database.MediaFile{}).Count(&total)
+	query().Count(&total)
 
 	// Get paginated results
-	result := m.db.Limit(limit).
+	result := query().Limit(limit).
 		Offset(offset).
 		Order("id DESC").
 		Find(&mediaFiles)
@@ -263,44 +313,6 @@ func (m *Module) getFile(c *gin.Context) {
 	})
 }
 
-// deleteFile deletes a media file
-func (m *Module) deleteFile(c *gin.Context) {
-	idStr := c.Param("id")
-	if idStr == "" {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "Invalid file ID",
-		})
-		return
-	}
-
-	var mediaFile database.MediaFile
-	if err := m.db.Where("id = ?", idStr).First(&mediaFile).Error; err != nil {
-		c.JSON(http.StatusNotFound, gin.H{
-			"error": "Media file not found",
-		})
-		return
-	}
-
-	// Then delete the file record
-	if err := m.db.Delete(&mediaFile).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": fmt.Sprintf("Failed to delete media file: %v", err),
-		})
-		return
-	}
-
-	// TODO: With new schema, metadata deletion would be through Artist/Album/Track relationships
-	// For now, just return success since MediaFile deletion is already handled
-	// if err := m.db.Where("media_file_id = ?", idStr).Delete(&database.MusicMetadata{}).Error; err != nil {
-	//	 return c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete music metadata"})
-	// }
-
-	c.JSON(http.StatusOK, gin.H{
-		"message": "Media file deleted successfully",
-		"id":      idStr,
-	})
-}
-
 // streamFile streams a media file
 func (m *Module) streamFile(c *gin.Context) {
 	idStr := c.Param("id")
@@ -432,9 +444,15 @@ func (m *Module) getFileMetadata(c *gin.Context) {
 			"type":         "track",
 			"track_id":     track.ID,
 			"title":        track.Title,
+			"disc_number":  track.DiscNumber,
 			"track_number": track.TrackNumber,
 			"duration":     track.Duration,
 			"lyrics":       track.Lyrics,
+			"composer":     track.Composer,
+			"work":         track.Work,
+			"movement":     track.Movement,
+			"conductor":    track.Conductor,
+			"orchestra":    track.Orchestra,
 			"artist": map[string]interface{}{
 				"id":          track.Artist.ID,
 				"name":        track.Artist.Name,
@@ -749,6 +767,7 @@ func (m *Module) updateMetadata(c *gin.Context) {
 		// Handle track metadata update
 		var trackUpdate struct {
 			Title       string `json:"title"`
+			DiscNumber  int    `json:"disc_number"`
 			TrackNumber int    `json:"track_number"`
 			Duration    int    `json:"duration"`
 			Lyrics      string `json:"lyrics"`
@@ -776,6 +795,9 @@ func (m *Module) updateMetadata(c *gin.Context) {
 		if trackUpdate.Title != "" {
 			track.Title = trackUpdate.Title
 		}
+		if trackUpdate.DiscNumber > 0 {
+			track.DiscNumber = trackUpdate.DiscNumber
+		}
 		if trackUpdate.TrackNumber > 0 {
 			track.TrackNumber = trackUpdate.TrackNumber
 		}
@@ -919,6 +941,15 @@ func (m *Module) getTVShows(c *gin.Context) {
 		query = query.Where("LOWER(title) LIKE ?", "%"+strings.ToLower(search)+"%")
 	}
 
+	// Leave out shows the user in user_id has hidden
+	visibility, ok := m.visibilityFor(c)
+	if !ok {
+		return
+	}
+	if visibility != nil {
+		query = visibility.Shows(query, "id")
+	}
+
 	// Get total count
 	query.Count(&total)
 
@@ -0,0 +1,528 @@
+package mediamodule
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mantonx/viewra/internal/arr"
+	"github.com/mantonx/viewra/internal/config"
+	"github.com/mantonx/viewra/internal/database"
+)
+
+// upgradeLookupBatch bounds the IDs in one IN query when loading titles
+const upgradeLookupBatch = 500
+
+// upgradeResolutionRanks orders the resolution classes of storageResolution.
+// Quality targets must be one of the ranked resolutions above SD.
+var upgradeResolutionRanks = map[string]int{
+	"sd":    1,
+	"480p":  1,
+	"576p":  1,
+	"720p":  2,
+	"1080p": 3,
+	"1440p": 4,
+	"2160p": 5,
+	"4k":    5,
+}
+
+// upgradeTargets are the resolutions a library may target
+var upgradeTargets = map[string]bool{"720p": true, "1080p": true, "1440p": true, "2160p": true}
+
+// errNoUpgradeDestination is returned when nothing is configured to push to
+var errNoUpgradeDestination = errors.New("no Radarr, Sonarr or upgrade webhook configured")
+
+// upgradeCheckResult summarizes a check for items below their quality target
+type upgradeCheckResult struct {
+	LibrariesChecked int `json:"libraries_checked"`
+	Wanted           int `json:"wanted"`
+	New              int `json:"new"`
+	Resolved         int `json:"resolved"` // Upgraded or removed since the last check
+}
+
+// upgradePushResult summarizes upgrades sent to Radarr, Sonarr and the webhook
+type upgradePushResult struct {
+	Items            int      `json:"items"`
+	RadarrSearches   int      `json:"radarr_searches"`
+	SonarrSearches   int      `json:"sonarr_searches"`
+	WebhookDelivered bool     `json:"webhook_delivered"`
+	Errors           []string `json:"errors"`
+}
+
+// checkUpgrades finds the movies and episodes whose best file is below their
+// library's quality target, for one library or, with libraryID 0, all that
+// have a target. Items found before keep when they were first detected and
+// pushed; items no longer below target are dropped from the list.
+func (m *Module) checkUpgrades(libraryID uint32) (*upgradeCheckResult, error) {
+	query := m.db.Model(&database.LibraryQualityTarget{})
+	if libraryID != 0 {
+		query = query.Where("library_id = ?", libraryID)
+	}
+	var targets []database.LibraryQualityTarget
+	if err := query.Find(&targets).Error; err != nil {
+		return nil, fmt.Errorf("failed to get quality targets: %w", err)
+	}
+
+	result := &upgradeCheckResult{}
+	for _, target := range targets {
+		if err := m.checkLibraryUpgrades(target, result); err != nil {
+			return nil, err
+		}
+		result.LibrariesChecked++
+	}
+	return result, nil
+}
+
+func (m *Module) checkLibraryUpgrades(target database.LibraryQualityTarget, result *upgradeCheckResult) error {
+	targetRank := upgradeResolutionRanks[target.MinResolution]
+
+	var files []database.MediaFile
+	if err := m.db.Select("media_id, media_type, resolution, video_width, video_height").
+		Where("library_id = ? AND media_type IN ? AND media_id != ''", target.LibraryID,
+			[]database.MediaType{database.MediaTypeMovie, database.MediaTypeEpisode}).
+		Find(&files).Error; err != nil {
+		return fmt.Errorf("failed to get library files: %w", err)
+	}
+
+	// The best version of each item decides; items of unknown resolution
+	// can't be judged and are left out
+	type best struct {
+		mediaType  database.MediaType
+		resolution string
+		rank       int
+	}
+	items := make(map[string]*best)
+	for _, file := range files {
+		resolution := storageResolution(file.MediaType, file.Resolution, file.VideoWidth, file.VideoHeight)
+		rank := upgradeResolutionRanks[strings.ToLower(resolution)]
+		if item, ok := items[file.MediaID]; !ok || rank > item.rank {
+			items[file.MediaID] = &best{mediaType: file.MediaType, resolution: resolution, rank: rank}
+		}
+	}
+
+	var existing []database.UpgradeWanted
+	if err := m.db.Where("library_id = ?", target.LibraryID).Find(&existing).Error; err != nil {
+		return fmt.Errorf("failed to get upgrades wanted: %w", err)
+	}
+	previous := make(map[string]database.UpgradeWanted, len(existing))
+	for _, upgrade := range existing {
+		previous[upgrade.MediaID] = upgrade
+	}
+
+	wanted := make(map[string]*best)
+	var movieIDs, episodeIDs []string
+	for mediaID, item := range items {
+		if item.rank == 0 || item.rank >= targetRank {
+			continue
+		}
+		wanted[mediaID] = item
+		if item.mediaType == database.MediaTypeMovie {
+			movieIDs = append(movieIDs, mediaID)
+		} else {
+			episodeIDs = append(episodeIDs, mediaID)
+		}
+	}
+	titles, err := m.upgradeTitles(movieIDs, episodeIDs)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	for mediaID, item := range wanted {
+		upgrade, found := previous[mediaID]
+		if !found {
+			upgrade = database.UpgradeWanted{MediaID: mediaID, DetectedAt: now}
+			result.New++
+		}
+		upgrade.MediaType = item.mediaType
+		upgrade.LibraryID = target.LibraryID
+		upgrade.Title = titles[mediaID]
+		upgrade.BestResolution = item.resolution
+		upgrade.TargetResolution = target.MinResolution
+		if err := m.db.Save(&upgrade).Error; err != nil {
+			return fmt.Errorf("failed to save upgrade wanted: %w", err)
+		}
+		result.Wanted++
+	}
+
+	for mediaID := range previous {
+		if _, ok := wanted[mediaID]; ok {
+			continue
+		}
+		if err := m.db.Where("media_id = ?", mediaID).Delete(&database.UpgradeWanted{}).Error; err != nil {
+			return fmt.Errorf("failed to remove resolved upgrade: %w", err)
+		}
+		result.Resolved++
+	}
+	return nil
+}
+
+// upgradeTitles returns display titles: "Title (Year)" for movies and
+// "Show S01E02" for episodes
+func (m *Module) upgradeTitles(movieIDs, episodeIDs []string) (map[string]string, error) {
+	titles := make(map[string]string)
+
+	for start := 0; start < len(movieIDs); start += upgradeLookupBatch {
+		batch := movieIDs[start:min(start+upgradeLookupBatch, len(movieIDs))]
+		var movies []database.Movie
+		if err := m.db.Select("id, title, release_date").Where("id IN ?", batch).Find(&movies).Error; err != nil {
+			return nil, fmt.Errorf("failed to get movie titles: %w", err)
+		}
+		for _, movie := range movies {
+			titles[movie.ID] = movie.Title
+			if movie.ReleaseDate != nil {
+				titles[movie.ID] = fmt.Sprintf("%s (%d)", movie.Title, movie.ReleaseDate.Year())
+			}
+		}
+	}
+
+	for start := 0; start < len(episodeIDs); start += upgradeLookupBatch {
+		batch := episodeIDs[start:min(start+upgradeLookupBatch, len(episodeIDs))]
+		episodes, err := m.upgradeEpisodes(batch)
+		if err != nil {
+			return nil, err
+		}
+		for id, episode := range episodes {
+			titles[id] = fmt.Sprintf("%s S%02dE%02d", episode.ShowTitle, episode.SeasonNumber, episode.EpisodeNumber)
+		}
+	}
+	return titles, nil
+}
+
+// upgradeEpisodes identifies episodes by show, season and episode number
+func (m *Module) upgradeEpisodes(episodeIDs []string) (map[string]arr.Episode, error) {
+	var rows []struct {
+		ID            string
+		ShowTitle     string
+		ShowTmdbID    string
+		SeasonNumber  int
+		EpisodeNumber int
+	}
+	if err := m.db.Table("episodes").
+		Select("episodes.id, tv_shows.title AS show_title, tv_shows.tmdb_id AS show_tmdb_id, seasons.season_number, episodes.episode_number").
+		Joins("JOIN seasons ON seasons.id = episodes.season_id").
+		Joins("JOIN tv_shows ON tv_shows.id = seasons.tv_show_id").
+		Where("episodes.id IN ?", episodeIDs).
+		Scan(&rows).Error; err != nil {
+		return nil, fmt.Errorf("failed to get episodes: %w", err)
+	}
+
+	episodes := make(map[string]arr.Episode, len(rows))
+	for _, row := range rows {
+		episodes[row.ID] = arr.Episode{
+			ShowTitle:     row.ShowTitle,
+			ShowTmdbID:    row.ShowTmdbID,
+			SeasonNumber:  row.SeasonNumber,
+			EpisodeNumber: row.EpisodeNumber,
+		}
+	}
+	return episodes, nil
+}
+
+// pushUpgrades asks Radarr and Sonarr to search for better releases of the
+// upgrades wanted, and posts them to the upgrade webhook. Only items never
+// pushed are sent unless all is set. Items are marked pushed when any
+// destination took them.
+func (m *Module) pushUpgrades(all bool) (*upgradePushResult, error) {
+	cfg := config.Get().Upgrades
+	radarr := arr.NewClient(cfg.RadarrURL, cfg.RadarrAPIKey)
+	sonarr := arr.NewClient(cfg.SonarrURL, cfg.SonarrAPIKey)
+	if radarr == nil && sonarr == nil && cfg.WebhookURL == "" {
+		return nil, errNoUpgradeDestination
+	}
+
+	query := m.db.Model(&database.UpgradeWanted{})
+	if !all {
+		query = query.Where("pushed_at IS NULL")
+	}
+	var upgrades []database.UpgradeWanted
+	if err := query.Order("detected_at").Find(&upgrades).Error; err != nil {
+		return nil, fmt.Errorf("failed to get upgrades wanted: %w", err)
+	}
+
+	result := &upgradePushResult{Items: len(upgrades), Errors: []string{}}
+	if len(upgrades) == 0 {
+		return result, nil
+	}
+
+	var movieIDs, episodeIDs []string
+	for _, upgrade := range upgrades {
+		if upgrade.MediaType == database.MediaTypeMovie {
+			movieIDs = append(movieIDs, upgrade.MediaID)
+		} else {
+			episodeIDs = append(episodeIDs, upgrade.MediaID)
+		}
+	}
+
+	movies := []arr.Movie{}
+	for start := 0; start < len(movieIDs); start += upgradeLookupBatch {
+		var rows []database.Movie
+		if err := m.db.Select("id, title, release_date, tmdb_id, imdb_id").
+			Where("id IN ?", movieIDs[start:min(start+upgradeLookupBatch, len(movieIDs))]).
+			Find(&rows).Error; err != nil {
+			return nil, fmt.Errorf("failed to get movies: %w", err)
+		}
+		for _, row := range rows {
+			movie := arr.Movie{Title: row.Title, TmdbID: row.TmdbID, ImdbID: row.ImdbID}
+			if row.ReleaseDate != nil {
+				movie.Year = row.ReleaseDate.Year()
+			}
+			movies = append(movies, movie)
+		}
+	}
+	episodes := []arr.Episode{}
+	for start := 0; start < len(episodeIDs); start += upgradeLookupBatch {
+		found, err := m.upgradeEpisodes(episodeIDs[start:min(start+upgradeLookupBatch, len(episodeIDs))])
+		if err != nil {
+			return nil, err
+		}
+		for _, episode := range found {
+			episodes = append(episodes, episode)
+		}
+	}
+
+	moviesTaken, episodesTaken := false, false
+	if radarr != nil && len(movies) > 0 {
+		if searched, err := radarr.SearchMovies(movies); err != nil {
+			result.Errors = append(result.Errors, err.Error())
+		} else {
+			result.RadarrSearches = searched
+			moviesTaken = true
+		}
+	}
+	if sonarr != nil && len(episodes) > 0 {
+		if searched, err := sonarr.SearchEpisodes(episodes); err != nil {
+			result.Errors = append(result.Errors, err.Error())
+		} else {
+			result.SonarrSearches = searched
+			episodesTaken = true
+		}
+	}
+	if cfg.WebhookURL != "" {
+		payload := map[string]interface{}{
+			"event":    "upgrades_wanted",
+			"movies":   movies,
+			"episodes": episodes,
+			"items":    upgrades,
+		}
+		if err := arr.PostWebhook(cfg.WebhookURL, payload); err != nil {
+			result.Errors = append(result.Errors, err.Error())
+		} else {
+			result.WebhookDelivered = true
+			moviesTaken, episodesTaken = true, true
+		}
+	}
+
+	now := time.Now()
+	for _, upgrade := range upgrades {
+		taken := episodesTaken
+		if upgrade.MediaType == database.MediaTypeMovie {
+			taken = moviesTaken
+		}
+		if !taken {
+			continue
+		}
+		if err := m.db.Model(&database.UpgradeWanted{}).
+			Where("media_id = ?", upgrade.MediaID).
+			Update("pushed_at", now).Error; err != nil {
+			log.Printf("WARNING: Failed to mark upgrade %s pushed: %v", upgrade.MediaID, err)
+		}
+	}
+	return result, nil
+}
+
+// startUpgradeChecks checks for upgrades on the configured interval, pushing
+// new ones when auto push is on
+func (m *Module) startUpgradeChecks() {
+	interval := config.Get().Upgrades.CheckInterval
+	if interval <= 0 {
+		interval = 24 * time.Hour
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for range ticker.C {
+		result, err := m.checkUpgrades(0)
+		if err != nil {
+			log.Printf("WARNING: Upgrade check failed: %v", err)
+			continue
+		}
+		if result.New > 0 {
+			log.Printf("INFO: Found %d new upgrades wanted (%d in total)", result.New, result.Wanted)
+		}
+
+		if result.New == 0 || !config.Get().Upgrades.AutoPush {
+			continue
+		}
+		if pushed, err := m.pushUpgrades(false); err != nil {
+			log.Printf("WARNING: Failed to push upgrades wanted: %v", err)
+		} else if len(pushed.Errors) > 0 {
+			log.Printf("WARNING: Some upgrades wanted weren't pushed: %s", strings.Join(pushed.Errors, "; "))
+		}
+	}
+}
+
+// getQualityTarget returns a library's quality target; min_resolution is
+// empty when the library has none
+func (m *Module) getQualityTarget(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid library ID"})
+		return
+	}
+
+	target := database.LibraryQualityTarget{LibraryID: uint32(id)}
+	var saved []database.LibraryQualityTarget
+	if err := m.db.Where("library_id = ?", id).Limit(1).Find(&saved).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": fmt.Sprintf("Failed to get quality target: %v", err),
+		})
+		return
+	}
+	if len(saved) > 0 {
+		target = saved[0]
+	}
+	c.JSON(http.StatusOK, target)
+}
+
+// setQualityTarget sets a library's quality target and checks the library
+// against it. An empty min_resolution removes the target and its upgrades.
+func (m *Module) setQualityTarget(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid library ID"})
+		return
+	}
+
+	var req struct {
+		MinResolution string `json:"min_resolution"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Invalid request: %v", err)})
+		return
+	}
+	minResolution := strings.ToLower(strings.TrimSpace(req.MinResolution))
+	if minResolution == "4k" {
+		minResolution = "2160p"
+	}
+	if minResolution != "" && !upgradeTargets[minResolution] {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": fmt.Sprintf("Unsupported min_resolution %q; use 720p, 1080p, 1440p or 2160p", req.MinResolution),
+		})
+		return
+	}
+
+	var library database.MediaLibrary
+	if err := m.db.First(&library, id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Library not found"})
+		return
+	}
+
+	if minResolution == "" {
+		for _, model := range []interface{}{&database.LibraryQualityTarget{}, &database.UpgradeWanted{}} {
+			if err := m.db.Where("library_id = ?", library.ID).Delete(model).Error; err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{
+					"error": fmt.Sprintf("Failed to remove quality target: %v", err),
+				})
+				return
+			}
+		}
+		c.JSON(http.StatusOK, gin.H{"target": nil})
+		return
+	}
+
+	target := database.LibraryQualityTarget{LibraryID: library.ID, MinResolution: minResolution}
+	if err := m.db.Save(&target).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": fmt.Sprintf("Failed to save quality target: %v", err),
+		})
+		return
+	}
+
+	result, err := m.checkUpgrades(library.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": fmt.Sprintf("Failed to check upgrades: %v", err),
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"target": target,
+		"check":  result,
+	})
+}
+
+// getUpgradesWanted lists items below their library's quality target
+// (`?library_id=&media_type=movie|episode&pushed=true|false`)
+func (m *Module) getUpgradesWanted(c *gin.Context) {
+	query := m.db.Model(&database.UpgradeWanted{})
+	if libraryID := c.Query("library_id"); libraryID != "" {
+		query = query.Where("library_id = ?", libraryID)
+	}
+	if mediaType := c.Query("media_type"); mediaType != "" {
+		query = query.Where("media_type = ?", mediaType)
+	}
+	switch c.Query("pushed") {
+	case "true":
+		query = query.Where("pushed_at IS NOT NULL")
+	case "false":
+		query = query.Where("pushed_at IS NULL")
+	}
+
+	upgrades := []database.UpgradeWanted{}
+	if err := query.Order("library_id, title").Find(&upgrades).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": fmt.Sprintf("Failed to get upgrades wanted: %v", err),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"upgrades": upgrades,
+		"count":    len(upgrades),
+	})
+}
+
+// checkUpgradesHandler checks libraries against their quality targets now
+// (`?library_id=` for one library)
+func (m *Module) checkUpgradesHandler(c *gin.Context) {
+	var libraryID uint64
+	if value := c.Query("library_id"); value != "" {
+		var err error
+		if libraryID, err = strconv.ParseUint(value, 10, 32); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid library ID"})
+			return
+		}
+	}
+
+	result, err := m.checkUpgrades(uint32(libraryID))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": fmt.Sprintf("Failed to check upgrades: %v", err),
+		})
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+// pushUpgradesHandler sends upgrades wanted to Radarr, Sonarr and the
+// upgrade webhook (`?all=true` to resend ones already pushed)
+func (m *Module) pushUpgradesHandler(c *gin.Context) {
+	result, err := m.pushUpgrades(c.Query("all") == "true")
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, errNoUpgradeDestination) {
+			status = http.StatusBadRequest
+		}
+		c.JSON(status, gin.H{
+			"error": fmt.Sprintf("Failed to push upgrades: %v", err),
+		})
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
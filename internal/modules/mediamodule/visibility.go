@@ -0,0 +1,382 @@
+package mediamodule
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mantonx/viewra/internal/auth"
+	"github.com/mantonx/viewra/internal/database"
+	"github.com/mantonx/viewra/internal/parental"
+	"gorm.io/gorm"
+)
+
+// hideableTables maps the item types a user can hide to their tables
+var hideableTables = map[string]string{
+	"movie":      "movies",
+	"tv_show":    "tv_shows",
+	"episode":    "episodes",
+	"artist":     "artists",
+	"album":      "albums",
+	"track":      "tracks",
+	"home_video": "home_videos",
+}
+
+// hideItemRequest names an item to hide
+type hideItemRequest struct {
+	MediaID   string `json:"media_id" binding:"required"`
+	MediaType string `json:"media_type" binding:"required"`
+}
+
+// libraryVisibilityRequest shows or hides a library
+type libraryVisibilityRequest struct {
+	Hidden bool `json:"hidden"`
+}
+
+// userVisibility filters list queries by the items and libraries a user has
+// hidden, the libraries they may not see and the movies and shows rated
+// above their parental controls. Every filter is a subquery, so hiding a
+// show or a library costs nothing when the user has hidden nothing.
+type userVisibility struct {
+	db     *gorm.DB
+	userID uint32
+	rated  *parental.Denied // nil without parental controls
+}
+
+// visibilityFor returns the visibility filters for the user in user_id, or
+// nil when the request names no user: admin and unscoped lists show
+// everything. Writes an error response and returns false for a bad user_id.
+func (m *Module) visibilityFor(c *gin.Context) (*userVisibility, bool) {
+	if c.Query("user_id") == "" {
+		return nil, true
+	}
+	userID, ok := parseWatchUserID(c)
+	if !ok {
+		return nil, false
+	}
+
+	visibility, err := newUserVisibility(m.db, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": fmt.Sprintf("Failed to check parental controls: %v", err),
+		})
+		return nil, false
+	}
+	return visibility, true
+}
+
+// newUserVisibility returns the visibility filters for a user, with the
+// ratings their parental controls deny worked out up front
+func newUserVisibility(db *gorm.DB, userID uint32) (*userVisibility, error) {
+	visibility := &userVisibility{db: db, userID: userID}
+	limit, err := parental.ForUser(db, userID, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	if limit != nil {
+		if visibility.rated, err = limit.Denied(db); err != nil {
+			return nil, err
+		}
+	}
+	return visibility, nil
+}
+
+// hiddenItems selects the IDs of the items of a type the user has hidden, or
+// of every item they have hidden when mediaType is empty
+func (v *userVisibility) hiddenItems(mediaType string) *gorm.DB {
+	query := v.db.Model(&database.UserHiddenItem{}).Select("media_id").Where("user_id = ?", v.userID)
+	if mediaType != "" {
+		query = query.Where("media_type = ?", mediaType)
+	}
+	return query
+}
+
+// hiddenLibraries selects the IDs of the libraries the user has hidden, and
+// of the libraries they may not see
+func (v *userVisibility) hiddenLibraries() *gorm.DB {
+	hidden := v.db.Model(&database.UserHiddenLibrary{}).Select("library_id").
+		Where("user_id = ?", v.userID)
+	return v.db.Model(&database.MediaLibrary{}).Select("id").
+		Where("id IN (?) OR id IN (?)", hidden, auth.DeniedLibraries(v.db, v.userID))
+}
+
+// onlyInHiddenLibraries selects the items of a type whose files all belong to
+// hidden libraries. Items found in a visible library too stay visible.
+func (v *userVisibility) onlyInHiddenLibraries(mediaType database.MediaType) *gorm.DB {
+	return v.db.Table("media_files").Select("media_id").
+		Where("media_type = ? AND media_id IS NOT NULL AND media_id <> ''", mediaType).
+		Group("media_id").
+		Having("SUM(CASE WHEN library_id IN (?) THEN 0 ELSE 1 END) = 0", v.hiddenLibraries())
+}
+
+// hiddenShows selects the shows the user has hidden, directly or by hiding
+// every library their episodes are in
+func (v *userVisibility) hiddenShows() *gorm.DB {
+	return v.db.Table("media_files").Select("seasons.tv_show_id").
+		Joins("JOIN episodes ON episodes.id = media_files.media_id").
+		Joins("JOIN seasons ON seasons.id = episodes.season_id").
+		Where("media_files.media_type = ?", database.MediaTypeEpisode).
+		Group("seasons.tv_show_id").
+		Having("SUM(CASE WHEN media_files.library_id IN (?) THEN 0 ELSE 1 END) = 0", v.hiddenLibraries())
+}
+
+// Shows removes hidden shows, and shows rated above the user's parental
+// controls, from a query; column holds the show ID
+func (v *userVisibility) Shows(query *gorm.DB, column string) *gorm.DB {
+	query = query.
+		Where(column+" NOT IN (?)", v.hiddenItems("tv_show")).
+		Where(column+" NOT IN (?)", v.hiddenShows())
+	if v.rated != nil {
+		query = query.Where(column+" NOT IN (?)", v.rated.Shows(v.db))
+	}
+	return query
+}
+
+// Movies removes hidden movies, movies only in hidden libraries and movies
+// rated above the user's parental controls from a query; column holds the
+// movie ID
+func (v *userVisibility) Movies(query *gorm.DB, column string) *gorm.DB {
+	query = query.
+		Where(column+" NOT IN (?)", v.hiddenItems("movie")).
+		Where(column+" NOT IN (?)", v.onlyInHiddenLibraries(database.MediaTypeMovie))
+	if v.rated != nil {
+		query = query.Where(column+" NOT IN (?)", v.rated.Movies(v.db))
+	}
+	return query
+}
+
+// Tracks removes hidden tracks, and tracks of hidden albums and artists, from
+// a query on the tracks table
+func (v *userVisibility) Tracks(query *gorm.DB, table string) *gorm.DB {
+	return query.
+		Where(table+".id NOT IN (?)", v.hiddenItems("track")).
+		Where(table+".album_id NOT IN (?)", v.hiddenItems("album")).
+		Where(table+".artist_id NOT IN (?)", v.hiddenItems("artist")).
+		Where(table+".id NOT IN (?)", v.onlyInHiddenLibraries(database.MediaTypeTrack))
+}
+
+// HomeVideos removes hidden home videos and home video libraries from a
+// query on the home_videos table
+func (v *userVisibility) HomeVideos(query *gorm.DB, table string) *gorm.DB {
+	return query.
+		Where(table+".id NOT IN (?)", v.hiddenItems("home_video")).
+		Where(table+".library_id NOT IN (?)", v.hiddenLibraries())
+}
+
+// Files removes files of hidden libraries and hidden items from a query on the
+// media_files table. Episodes of hidden shows and tracks of hidden albums and
+// artists are hidden with them, as are files of movies and shows rated above
+// the user's parental controls.
+func (v *userVisibility) Files(query *gorm.DB, table string) *gorm.DB {
+	mediaID := "COALESCE(" + table + ".media_id, '')"
+	hiddenEpisodes := v.db.Table("episodes").Select("episodes.id").
+		Joins("JOIN seasons ON seasons.id = episodes.season_id").
+		Where("seasons.tv_show_id IN (?)", v.hiddenItems("tv_show"))
+	hiddenTracks := v.db.Table("tracks").Select("tracks.id").
+		Where("tracks.album_id IN (?) OR tracks.artist_id IN (?)", v.hiddenItems("album"), v.hiddenItems("artist"))
+
+	query = query.
+		Where(table+".library_id NOT IN (?)", v.hiddenLibraries()).
+		Where(mediaID+" NOT IN (?)", v.hiddenItems("")).
+		Where(mediaID+" NOT IN (?)", hiddenEpisodes).
+		Where(mediaID+" NOT IN (?)", hiddenTracks)
+	if v.rated != nil {
+		ratedEpisodes := v.db.Table("episodes").Select("episodes.id").
+			Joins("JOIN seasons ON seasons.id = episodes.season_id").
+			Where("seasons.tv_show_id IN (?)", v.rated.Shows(v.db))
+		query = query.
+			Where(mediaID+" NOT IN (?)", v.rated.Movies(v.db)).
+			Where(mediaID+" NOT IN (?)", ratedEpisodes)
+	}
+	return query
+}
+
+// Libraries drops the libraries the user has hidden or may not see from a list
+func (v *userVisibility) Libraries(libraries []*database.MediaLibrary) ([]*database.MediaLibrary, error) {
+	var hidden []uint32
+	if err := v.hiddenLibraries().Pluck("id", &hidden).Error; err != nil {
+		return nil, err
+	}
+	if len(hidden) == 0 {
+		return libraries, nil
+	}
+
+	hiddenIDs := make(map[uint32]bool, len(hidden))
+	for _, id := range hidden {
+		hiddenIDs[id] = true
+	}
+	visible := make([]*database.MediaLibrary, 0, len(libraries))
+	for _, library := range libraries {
+		if !hiddenIDs[library.ID] {
+			visible = append(visible, library)
+		}
+	}
+	return visible, nil
+}
+
+// getHiddenItems lists the items and libraries the user in user_id has hidden
+func (m *Module) getHiddenItems(c *gin.Context) {
+	userID, ok := parseWatchUserID(c)
+	if !ok {
+		return
+	}
+
+	var items []database.UserHiddenItem
+	if err := m.db.Where("user_id = ?", userID).Order("hidden_at DESC").Find(&items).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": fmt.Sprintf("Failed to get hidden items: %v", err),
+		})
+		return
+	}
+
+	var libraries []database.UserHiddenLibrary
+	if err := m.db.Where("user_id = ?", userID).Order("library_id").Find(&libraries).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": fmt.Sprintf("Failed to get hidden libraries: %v", err),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"items":     items,
+		"libraries": libraries,
+		"count":     len(items),
+	})
+}
+
+// hideItem hides an item from the user in user_id
+func (m *Module) hideItem(c *gin.Context) {
+	userID, ok := parseWatchUserID(c)
+	if !ok {
+		return
+	}
+
+	var request hideItemRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	table, ok := hideableTables[request.MediaType]
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": fmt.Sprintf("invalid media_type value: %s", request.MediaType),
+		})
+		return
+	}
+
+	var count int64
+	if err := m.db.Table(table).Where("id = ?", request.MediaID).Count(&count).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": fmt.Sprintf("Failed to find item: %v", err),
+		})
+		return
+	}
+	if count == 0 {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Item not found",
+		})
+		return
+	}
+
+	item := database.UserHiddenItem{
+		UserID:    userID,
+		MediaID:   request.MediaID,
+		MediaType: request.MediaType,
+		HiddenAt:  time.Now(),
+	}
+	if err := m.db.Save(&item).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": fmt.Sprintf("Failed to hide item: %v", err),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"hidden": item,
+	})
+}
+
+// unhideItem shows a hidden item to the user in user_id again
+func (m *Module) unhideItem(c *gin.Context) {
+	userID, ok := parseWatchUserID(c)
+	if !ok {
+		return
+	}
+
+	result := m.db.Where("user_id = ? AND media_id = ?", userID, c.Param("id")).Delete(&database.UserHiddenItem{})
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": fmt.Sprintf("Failed to unhide item: %v", result.Error),
+		})
+		return
+	}
+	if result.RowsAffected == 0 {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Hidden item not found",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Item unhidden",
+	})
+}
+
+// setLibraryVisibility hides a library from, or shows it to, the user in
+// user_id
+func (m *Module) setLibraryVisibility(c *gin.Context) {
+	userID, ok := parseWatchUserID(c)
+	if !ok {
+		return
+	}
+
+	libraryID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid library ID",
+		})
+		return
+	}
+
+	var request libraryVisibilityRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	var library database.MediaLibrary
+	if err := m.db.First(&library, libraryID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Library not found",
+		})
+		return
+	}
+
+	if request.Hidden {
+		err = m.db.Save(&database.UserHiddenLibrary{
+			UserID:    userID,
+			LibraryID: library.ID,
+			HiddenAt:  time.Now(),
+		}).Error
+	} else {
+		err = m.db.Where("user_id = ? AND library_id = ?", userID, library.ID).Delete(&database.UserHiddenLibrary{}).Error
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": fmt.Sprintf("Failed to update library visibility: %v", err),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"library_id": library.ID,
+		"hidden":     request.Hidden,
+	})
+}
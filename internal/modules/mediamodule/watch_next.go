@@ -0,0 +1,418 @@
+package mediamodule
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mantonx/viewra/internal/database"
+	"gorm.io/gorm"
+)
+
+const (
+	// Reasons the watch-next engine picked an episode
+	WatchNextStart  = "start"  // Nothing watched yet
+	WatchNextResume = "resume" // Partly watched, most recent activity
+	WatchNextNext   = "next"   // Follows the last episode watched
+
+	// resumeMinSeconds is how much of an episode must be played before the
+	// play button resumes it rather than moving on
+	resumeMinSeconds = 60
+	// specialsSeason is the season number TMDb and scanners use for specials
+	specialsSeason = 0
+
+	defaultUpNextLimit = 20
+	maxUpNextLimit     = 100
+)
+
+// NextEpisode is the episode a show's play button should start
+type NextEpisode struct {
+	ShowID        string     `json:"show_id"`
+	EpisodeID     string     `json:"episode_id"`
+	Title         string     `json:"title"`
+	SeasonNumber  int        `json:"season_number"`
+	EpisodeNumber int        `json:"episode_number"`
+	MediaFileID   string     `json:"media_file_id"`
+	Reason        string     `json:"reason"` // start, resume or next
+	ResumeSeconds float64    `json:"resume_seconds,omitempty"`
+	LastWatchedAt *time.Time `json:"last_watched_at,omitempty"`
+}
+
+// UpNextItem is a show in progress and its next episode
+type UpNextItem struct {
+	Show database.TVShow `json:"show"`
+	Next *NextEpisode    `json:"next"`
+}
+
+// watchNextEpisode is one episode slot of a show with a user's watch state.
+// Episodes scanned twice with the same numbers share a slot.
+type watchNextEpisode struct {
+	ids           []string
+	title         string
+	season        int
+	episode       int
+	files         []database.MediaFile
+	watched       bool
+	watchedAt     time.Time // When it was last finished
+	lastActivity  time.Time
+	lastSession   *database.PlaybackSession
+	lastPlayedFID string
+}
+
+// watchNext computes what a user should watch next from playback sessions
+type watchNext struct {
+	db *gorm.DB
+}
+
+// NextEpisode returns the episode a user should watch next in a show, or nil
+// when the show has no playable episodes left for them. An episode partly
+// watched most recently is resumed. Otherwise it's the first unwatched episode
+// after the last one finished, so episodes the user skipped don't pull them
+// back. Specials are never picked as next, and never block progress, but a
+// special in progress is resumed.
+func (w *watchNext) NextEpisode(userID uint32, showID string) (*NextEpisode, error) {
+	episodes, err := w.loadEpisodes(userID, showID)
+	if err != nil {
+		return nil, err
+	}
+
+	var latest, anchor *watchNextEpisode
+	for _, ep := range episodes {
+		if len(ep.files) == 0 {
+			continue
+		}
+		if latest == nil || ep.lastActivity.After(latest.lastActivity) {
+			latest = ep
+		}
+		if ep.watched && ep.season != specialsSeason && (anchor == nil || ep.watchedAt.After(anchor.watchedAt)) {
+			anchor = ep
+		}
+	}
+
+	var lastWatchedAt *time.Time
+	if latest != nil && !latest.lastActivity.IsZero() {
+		lastWatchedAt = &latest.lastActivity
+		if isResumable(latest.lastSession) {
+			next := w.build(showID, latest, WatchNextResume)
+			next.ResumeSeconds = resumePosition(latest.lastSession)
+			next.LastWatchedAt = lastWatchedAt
+			return next, nil
+		}
+	}
+
+	reason := WatchNextStart
+	start := 0
+	if anchor != nil {
+		reason = WatchNextNext
+		for i, ep := range episodes {
+			if ep == anchor {
+				start = i + 1
+				break
+			}
+		}
+	}
+	var following *watchNextEpisode
+	for _, ep := range episodes[start:] {
+		if ep.season == specialsSeason || len(ep.files) == 0 {
+			continue
+		}
+		if following == nil {
+			following = ep
+		}
+		if !ep.watched {
+			next := w.build(showID, ep, reason)
+			next.LastWatchedAt = lastWatchedAt
+			return next, nil
+		}
+	}
+	// Everything after the last episode was watched before: the user is
+	// rewatching, so carry on in order
+	if anchor != nil && following != nil {
+		next := w.build(showID, following, reason)
+		next.LastWatchedAt = lastWatchedAt
+		return next, nil
+	}
+	return nil, nil
+}
+
+// UpNext returns the next episode of each show a user has been watching,
+// most recently watched show first. Shows the user has caught up on or
+// hidden are left out.
+func (w *watchNext) UpNext(userID uint32, limit int) ([]UpNextItem, error) {
+	query := w.db.Table("playback_sessions").
+		Joins("JOIN episodes ON episodes.id = playback_sessions.media_id").
+		Joins("JOIN seasons ON seasons.id = episodes.season_id").
+		Where("playback_sessions.user_id = ? AND playback_sessions.media_type = ?", userID, string(database.MediaTypeEpisode))
+	visibility, err := newUserVisibility(w.db, userID)
+	if err != nil {
+		return nil, err
+	}
+	query = visibility.Shows(query, "seasons.tv_show_id")
+
+	var showIDs []string
+	err = query.
+		Group("seasons.tv_show_id").
+		Order("MAX(playback_sessions.last_seen_at) DESC").
+		Pluck("seasons.tv_show_id", &showIDs).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to find watched shows: %w", err)
+	}
+
+	items := make([]UpNextItem, 0, min(len(showIDs), limit))
+	for _, showID := range showIDs {
+		if len(items) >= limit {
+			break
+		}
+		next, err := w.NextEpisode(userID, showID)
+		if err != nil {
+			return nil, err
+		}
+		if next == nil {
+			continue
+		}
+		var show database.TVShow
+		if err := w.db.Where("id = ?", showID).First(&show).Error; err != nil {
+			continue
+		}
+		items = append(items, UpNextItem{Show: show, Next: next})
+	}
+	return items, nil
+}
+
+// loadEpisodes returns a show's episodes in airing order with their files
+// and the user's watch state
+func (w *watchNext) loadEpisodes(userID uint32, showID string) ([]*watchNextEpisode, error) {
+	var rows []struct {
+		ID            string
+		Title         string
+		EpisodeNumber int
+		SeasonNumber  int
+	}
+	err := w.db.Table("episodes").
+		Select("episodes.id, episodes.title, episodes.episode_number, seasons.season_number").
+		Joins("JOIN seasons ON seasons.id = episodes.season_id").
+		Where("seasons.tv_show_id = ?", showID).
+		Order("seasons.season_number, episodes.episode_number").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to load episodes: %w", err)
+	}
+	if len(rows) == 0 {
+		return nil, nil
+	}
+
+	type slotKey struct{ season, episode int }
+	slots := make(map[slotKey]*watchNextEpisode, len(rows))
+	byID := make(map[string]*watchNextEpisode, len(rows))
+	episodes := make([]*watchNextEpisode, 0, len(rows))
+	ids := make([]string, 0, len(rows))
+	for _, row := range rows {
+		key := slotKey{row.SeasonNumber, row.EpisodeNumber}
+		ep := slots[key]
+		if ep == nil {
+			ep = &watchNextEpisode{title: row.Title, season: row.SeasonNumber, episode: row.EpisodeNumber}
+			slots[key] = ep
+			episodes = append(episodes, ep)
+		}
+		ep.ids = append(ep.ids, row.ID)
+		byID[row.ID] = ep
+		ids = append(ids, row.ID)
+	}
+
+	var files []database.MediaFile
+	err = w.db.Select("id, media_id, bitrate_kbps, size_bytes, duration").
+		Where("media_type = ? AND media_id IN ?", database.MediaTypeEpisode, ids).
+		Find(&files).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to load episode files: %w", err)
+	}
+	for _, file := range files {
+		byID[file.MediaID].files = append(byID[file.MediaID].files, file)
+	}
+
+	var sessions []database.PlaybackSession
+	err = w.db.Where("user_id = ? AND media_id IN ?", userID, ids).
+		Order("last_seen_at").
+		Find(&sessions).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to load watch history: %w", err)
+	}
+	for i := range sessions {
+		session := &sessions[i]
+		ep := byID[session.MediaID]
+		if isWatched(session) {
+			ep.watched = true
+			ep.watchedAt = session.LastSeenAt
+		}
+		ep.lastActivity = session.LastSeenAt
+		ep.lastSession = session
+		ep.lastPlayedFID = session.MediaFileID
+	}
+
+	return episodes, nil
+}
+
+// build describes an episode slot, choosing the file to play
+func (w *watchNext) build(showID string, ep *watchNextEpisode, reason string) *NextEpisode {
+	return &NextEpisode{
+		ShowID:        showID,
+		EpisodeID:     ep.ids[0],
+		Title:         ep.title,
+		SeasonNumber:  ep.season,
+		EpisodeNumber: ep.episode,
+		MediaFileID:   preferredVersion(ep),
+		Reason:        reason,
+	}
+}
+
+// preferredVersion picks the file to play for an episode with several
+// versions: the one the user last played, otherwise the highest bitrate
+func preferredVersion(ep *watchNextEpisode) string {
+	for _, file := range ep.files {
+		if file.ID == ep.lastPlayedFID {
+			return file.ID
+		}
+	}
+	files := append([]database.MediaFile(nil), ep.files...)
+	sort.SliceStable(files, func(i, j int) bool {
+		if files[i].BitrateKbps != files[j].BitrateKbps {
+			return files[i].BitrateKbps > files[j].BitrateKbps
+		}
+		return files[i].SizeBytes > files[j].SizeBytes
+	})
+	return files[0].ID
+}
+
+// isWatched reports whether a session played an episode to the end. The
+// playback module marks sessions completed by the user's watched rules.
+func isWatched(session *database.PlaybackSession) bool {
+	return session.Completed
+}
+
+// isResumable reports whether a session stopped partway through an episode
+func isResumable(session *database.PlaybackSession) bool {
+	return session != nil && !isWatched(session) && resumePosition(session) >= resumeMinSeconds
+}
+
+// resumePosition is where playback of a session stopped. Sessions from
+// players that don't report a position fall back to the time played.
+func resumePosition(session *database.PlaybackSession) float64 {
+	if session.PositionSeconds > 0 {
+		return session.PositionSeconds
+	}
+	return session.WatchedSeconds
+}
+
+// parseWatchUserID reads the optional user_id query parameter. Sessions
+// recorded without a user belong to user 0.
+func parseWatchUserID(c *gin.Context) (uint32, bool) {
+	userIDStr := c.Query("user_id")
+	if userIDStr == "" {
+		return 0, true
+	}
+	userID, err := strconv.ParseUint(userIDStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid user ID",
+		})
+		return 0, false
+	}
+	return uint32(userID), true
+}
+
+// getTVShow returns a show with its seasons, episodes and, for the user in
+// user_id, the episode its play button should start
+func (m *Module) getTVShow(c *gin.Context) {
+	userID, ok := parseWatchUserID(c)
+	if !ok {
+		return
+	}
+
+	var show database.TVShow
+	if err := m.db.Where("id = ?", c.Param("id")).First(&show).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "TV show not found",
+		})
+		return
+	}
+
+	var seasons []database.Season
+	if err := m.db.Where("tv_show_id = ?", show.ID).Order("season_number").Find(&seasons).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": fmt.Sprintf("Failed to get seasons: %v", err),
+		})
+		return
+	}
+	seasonIDs := make([]string, len(seasons))
+	for i, season := range seasons {
+		seasonIDs[i] = season.ID
+	}
+
+	var episodes []database.Episode
+	if err := m.db.Where("season_id IN ?", seasonIDs).Order("episode_number").Find(&episodes).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": fmt.Sprintf("Failed to get episodes: %v", err),
+		})
+		return
+	}
+	episodesBySeason := make(map[string][]database.Episode, len(seasons))
+	for _, episode := range episodes {
+		episodesBySeason[episode.SeasonID] = append(episodesBySeason[episode.SeasonID], episode)
+	}
+
+	type seasonDetail struct {
+		database.Season
+		Episodes []database.Episode `json:"episodes"`
+	}
+	details := make([]seasonDetail, len(seasons))
+	for i, season := range seasons {
+		details[i] = seasonDetail{Season: season, Episodes: episodesBySeason[season.ID]}
+		if details[i].Episodes == nil {
+			details[i].Episodes = []database.Episode{}
+		}
+	}
+
+	next, err := (&watchNext{db: m.db}).NextEpisode(userID, show.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": fmt.Sprintf("Failed to get next episode: %v", err),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"tv_show":      show,
+		"seasons":      details,
+		"next_episode": next,
+	})
+}
+
+// getUpNext returns the home feed's Up Next row: the next episode of each
+// show the user is watching
+func (m *Module) getUpNext(c *gin.Context) {
+	userID, ok := parseWatchUserID(c)
+	if !ok {
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(defaultUpNextLimit)))
+	if err != nil || limit < 1 || limit > maxUpNextLimit {
+		limit = defaultUpNextLimit
+	}
+
+	items, err := (&watchNext{db: m.db}).UpNext(userID, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": fmt.Sprintf("Failed to get up next: %v", err),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"up_next": items,
+		"count":   len(items),
+	})
+}
@@ -0,0 +1,47 @@
+package organizermodule
+
+import (
+	"time"
+)
+
+// Organize modes
+const (
+	ModeMove     = "move"     // Rename the file in place; the library follows it
+	ModeHardlink = "hardlink" // Link the file into the destination root
+	ModeCopy     = "copy"     // Copy the file into the destination root
+)
+
+// Kinds of files an operation moves
+const (
+	KindMedia   = "media"
+	KindSidecar = "sidecar" // Subtitle file beside a media file
+)
+
+// Operation is an undo journal entry: one file organized as part of a
+// batch. Undoing a batch reverses its operations in reverse order.
+type Operation struct {
+	ID              uint32     `gorm:"primaryKey" json:"id"`
+	BatchID         string     `gorm:"type:varchar(36);not null;index" json:"batch_id"`
+	MediaFileID     string     `gorm:"type:varchar(36);index" json:"media_file_id"`
+	Kind            string     `gorm:"not null" json:"kind"` // media or sidecar
+	Mode            string     `gorm:"not null" json:"mode"` // move, hardlink or copy
+	SourcePath      string     `gorm:"not null" json:"source_path"`
+	DestinationPath string     `gorm:"not null" json:"destination_path"`
+	Root            string     `json:"root"` // Directory below which directories emptied by the operation are removed
+	CreatedAt       time.Time  `gorm:"index" json:"created_at"`
+	UndoneAt        *time.Time `json:"undone_at,omitempty"`
+}
+
+// TableName keeps the journal apart from other modules' tables
+func (Operation) TableName() string {
+	return "organizer_operations"
+}
+
+// Batch summarizes the operations applied together
+type Batch struct {
+	BatchID   string     `json:"batch_id"`
+	Mode      string     `json:"mode"`
+	Files     int        `json:"files"`
+	CreatedAt time.Time  `json:"created_at"`
+	UndoneAt  *time.Time `json:"undone_at,omitempty"`
+}
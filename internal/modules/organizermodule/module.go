@@ -0,0 +1,224 @@
+// Package organizermodule renames and moves media files into a canonical
+// structure described by templates, such as
+// "{Show} ({Year})/Season {S}/{Show} - S{S}E{E} - {Title}". Changes can be
+// previewed, are journaled so they can be undone, and can hardlink or copy
+// files into another directory instead of moving them.
+package organizermodule
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mantonx/viewra/internal/config"
+	"github.com/mantonx/viewra/internal/database"
+	"github.com/mantonx/viewra/internal/events"
+	"github.com/mantonx/viewra/internal/modules/modulemanager"
+	"gorm.io/gorm"
+)
+
+// Auto-register the module when imported
+func init() {
+	Register()
+}
+
+const (
+	ModuleID   = "system.organizer"
+	ModuleName = "File Organizer"
+)
+
+// defaultJournalLimit is the number of batches listed by default
+const defaultJournalLimit = 50
+
+// Module organizes library files. It isn't a core module, so it can be
+// disabled in the module configuration.
+type Module struct {
+	id          string
+	name        string
+	version     string
+	core        bool
+	organizer   *Organizer
+	initialized bool
+}
+
+// Register registers this module with the module system
+func Register() {
+	organizerModule := &Module{
+		id:      ModuleID,
+		name:    ModuleName,
+		version: "1.0.0",
+		core:    false,
+	}
+	modulemanager.Register(organizerModule)
+}
+
+// ID returns the module ID
+func (m *Module) ID() string {
+	return m.id
+}
+
+// Name returns the module name
+func (m *Module) Name() string {
+	return m.name
+}
+
+// Core returns whether this is a core module
+func (m *Module) Core() bool {
+	return m.core
+}
+
+// Migrate creates the undo journal
+func (m *Module) Migrate(db *gorm.DB) error {
+	return db.AutoMigrate(&Operation{})
+}
+
+// Init initializes the module and, when configured, organizes files as
+// they are enriched. Invalid templates leave the module off rather than
+// stopping the server.
+func (m *Module) Init() error {
+	cfg := config.Get().Organizer
+	eventBus := events.GetGlobalEventBus()
+
+	organizer, err := NewOrganizer(database.GetDB(), eventBus, cfg)
+	if err != nil {
+		log.Printf("ERROR: File organizer disabled: %v", err)
+		return nil
+	}
+	m.organizer = organizer
+	m.initialized = true
+
+	if cfg.AutoOrganize && eventBus != nil {
+		filter := events.EventFilter{Types: []events.EventType{events.EventEnrichmentApplied}}
+		if _, err := eventBus.Subscribe(context.Background(), filter, m.onEnrichmentApplied); err != nil {
+			log.Printf("WARN: Failed to subscribe organizer to enrichment events: %v", err)
+		}
+	}
+
+	log.Printf("File organizer module initialized (mode: %s, auto organize: %t)", cfg.Mode, cfg.AutoOrganize)
+	return nil
+}
+
+// Organizer returns the module's organizer, or nil when its configuration
+// left it off
+func (m *Module) Organizer() *Organizer {
+	return m.organizer
+}
+
+// onEnrichmentApplied organizes a file once its metadata is enriched
+func (m *Module) onEnrichmentApplied(event events.Event) error {
+	mediaFileID, _ := event.Data["media_file_id"].(string)
+	if mediaFileID == "" {
+		return nil
+	}
+
+	// Copies can take a while; don't hold up the event bus
+	go func() {
+		plan, err := m.organizer.Apply(Request{MediaFileIDs: []string{mediaFileID}})
+		if err != nil {
+			log.Printf("WARN: Failed to organize enriched media file %s: %v", mediaFileID, err)
+			return
+		}
+		for _, move := range plan.Moves {
+			if move.Status == StatusConflict || move.Status == StatusFailed {
+				log.Printf("WARN: Enriched media file %s not organized: %s", move.SourcePath, move.Reason)
+			}
+		}
+	}()
+	return nil
+}
+
+// RegisterRoutes registers the organizer endpoints of the admin API
+func (m *Module) RegisterRoutes(router *gin.Engine) {
+	if !m.initialized {
+		return
+	}
+
+	api := router.Group("/api/admin/organizer")
+	{
+		api.POST("/preview", m.previewOrganize)
+		api.POST("/apply", m.applyOrganize)
+		api.GET("/journal", m.getJournal)
+		api.GET("/journal/:batchId", m.getJournalBatch)
+		api.POST("/journal/:batchId/undo", m.undoBatch)
+	}
+}
+
+// previewOrganize lists where files would go without touching them
+func (m *Module) previewOrganize(c *gin.Context) {
+	var req Request
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	plan, err := m.organizer.Preview(req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, plan)
+}
+
+// applyOrganize organizes files, returning the batch to undo it with
+func (m *Module) applyOrganize(c *gin.Context) {
+	var req Request
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	plan, err := m.organizer.Apply(req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, plan)
+}
+
+// getJournal lists recent batches, newest first
+func (m *Module) getJournal(c *gin.Context) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(defaultJournalLimit)))
+	if err != nil || limit <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be a positive number"})
+		return
+	}
+	batches, err := m.organizer.Journal(limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"batches": batches})
+}
+
+// getJournalBatch lists the files a batch touched
+func (m *Module) getJournalBatch(c *gin.Context) {
+	batchID := c.Param("batchId")
+	operations, err := m.organizer.BatchOperations(batchID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if len(operations) == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Batch not found"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"batch_id":   batchID,
+		"operations": operations,
+	})
+}
+
+// undoBatch puts a batch's files back where they were
+func (m *Module) undoBatch(c *gin.Context) {
+	result, err := m.organizer.Undo(c.Param("batchId"))
+	if errors.Is(err, ErrBatchNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
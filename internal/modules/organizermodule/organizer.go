@@ -0,0 +1,614 @@
+package organizermodule
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/mantonx/viewra/internal/config"
+	"github.com/mantonx/viewra/internal/database"
+	"github.com/mantonx/viewra/internal/events"
+	"github.com/mantonx/viewra/internal/modules/subtitlemodule"
+	"github.com/mantonx/viewra/internal/utils"
+	"gorm.io/gorm"
+)
+
+// Statuses of a planned or applied move
+const (
+	StatusReady     = "ready"     // Would be organized
+	StatusOrganized = "organized" // Already where its template puts it
+	StatusConflict  = "conflict"  // Another file is at the destination
+	StatusSkipped   = "skipped"   // Not a movie or episode, or missing metadata
+	StatusDone      = "done"      // Organized by this batch
+	StatusFailed    = "failed"
+)
+
+// ErrBatchNotFound is returned when undoing a batch with nothing left to undo
+var ErrBatchNotFound = errors.New("batch not found or already undone")
+
+// Request selects the files to organize: the movies and episodes of a
+// library, or the given media files
+type Request struct {
+	LibraryID       uint32   `json:"library_id"`
+	MediaFileIDs    []string `json:"media_file_ids"`
+	Mode            string   `json:"mode"`             // Defaults to the configured mode
+	DestinationRoot string   `json:"destination_root"` // Defaults to the configured root
+}
+
+// SidecarMove is a subtitle file renamed along with its media file
+type SidecarMove struct {
+	SourcePath      string `json:"source_path"`
+	DestinationPath string `json:"destination_path"`
+}
+
+// Move is what happens, or would happen, to one media file
+type Move struct {
+	MediaFileID     string        `json:"media_file_id"`
+	SourcePath      string        `json:"source_path"`
+	DestinationPath string        `json:"destination_path,omitempty"`
+	Sidecars        []SidecarMove `json:"sidecars,omitempty"`
+	Status          string        `json:"status"`
+	Reason          string        `json:"reason,omitempty"`
+
+	root string // The library, or the destination root
+}
+
+// Plan lists the moves of a request. Previews return it as is; applying it
+// records the batch the moves were journaled under.
+type Plan struct {
+	BatchID string         `json:"batch_id,omitempty"`
+	Mode    string         `json:"mode"`
+	Moves   []Move         `json:"moves"`
+	Counts  map[string]int `json:"counts"` // Moves by status
+}
+
+// UndoResult reports the undo of a batch
+type UndoResult struct {
+	BatchID  string        `json:"batch_id"`
+	Restored int           `json:"restored"` // Files back where they were
+	Failed   []UndoFailure `json:"failed"`
+}
+
+// UndoFailure is an operation that couldn't be undone; it stays in the
+// journal so the undo can be retried
+type UndoFailure struct {
+	OperationID uint32 `json:"operation_id"`
+	Path        string `json:"path"`
+	Error       string `json:"error"`
+}
+
+// Organizer renames media files into the structure their templates
+// describe, journaling every file it touches so a batch can be undone.
+// Applies and undos run one at a time.
+type Organizer struct {
+	db              *gorm.DB
+	eventBus        events.EventBus
+	config          config.OrganizerConfig
+	movieTemplate   *Template
+	episodeTemplate *Template
+
+	mu sync.Mutex
+}
+
+// NewOrganizer creates an organizer, failing on invalid templates or modes
+func NewOrganizer(db *gorm.DB, eventBus events.EventBus, cfg config.OrganizerConfig) (*Organizer, error) {
+	movieTemplate, err := ParseTemplate(cfg.MovieTemplate)
+	if err != nil {
+		return nil, fmt.Errorf("invalid movie template: %w", err)
+	}
+	episodeTemplate, err := ParseTemplate(cfg.EpisodeTemplate)
+	if err != nil {
+		return nil, fmt.Errorf("invalid episode template: %w", err)
+	}
+	if !validMode(cfg.Mode) {
+		return nil, fmt.Errorf("invalid mode %q, expected move, hardlink or copy", cfg.Mode)
+	}
+
+	return &Organizer{
+		db:              db,
+		eventBus:        eventBus,
+		config:          cfg,
+		movieTemplate:   movieTemplate,
+		episodeTemplate: episodeTemplate,
+	}, nil
+}
+
+func validMode(mode string) bool {
+	return mode == ModeMove || mode == ModeHardlink || mode == ModeCopy
+}
+
+// Preview plans a request without touching any file
+func (o *Organizer) Preview(req Request) (*Plan, error) {
+	return o.plan(req)
+}
+
+// Apply organizes the files of a request, journaling them under a new
+// batch. Files that aren't ready are left alone.
+func (o *Organizer) Apply(req Request) (*Plan, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	plan, err := o.plan(req)
+	if err != nil {
+		return nil, err
+	}
+
+	batchID := uuid.New().String()
+	for i := range plan.Moves {
+		move := &plan.Moves[i]
+		if move.Status != StatusReady {
+			continue
+		}
+		if err := o.organize(batchID, plan.Mode, move); err != nil {
+			move.Status, move.Reason = StatusFailed, err.Error()
+			log.Printf("WARN: Failed to organize %s: %v", move.SourcePath, err)
+			continue
+		}
+		move.Status = StatusDone
+		plan.BatchID = batchID
+	}
+	plan.Counts = countMoves(plan.Moves)
+	return plan, nil
+}
+
+// plan works out where the files of a request belong
+func (o *Organizer) plan(req Request) (*Plan, error) {
+	mode := req.Mode
+	if mode == "" {
+		mode = o.config.Mode
+	}
+	if !validMode(mode) {
+		return nil, fmt.Errorf("invalid mode %q, expected move, hardlink or copy", mode)
+	}
+
+	var libraries []database.MediaLibrary
+	if err := o.db.Find(&libraries).Error; err != nil {
+		return nil, fmt.Errorf("failed to get libraries: %w", err)
+	}
+	libraryPaths := make(map[uint32]string, len(libraries))
+	for _, library := range libraries {
+		libraryPaths[library.ID] = library.Path
+	}
+
+	root := ""
+	if mode != ModeMove {
+		root = req.DestinationRoot
+		if root == "" {
+			root = o.config.DestinationRoot
+		}
+		if root == "" || !filepath.IsAbs(root) {
+			return nil, fmt.Errorf("%s needs an absolute destination root", mode)
+		}
+		root = filepath.Clean(root)
+		// Organized copies inside a library would be scanned as more files
+		for _, library := range libraries {
+			if utils.WithinDir(root, library.Path) || utils.WithinDir(library.Path, root) {
+				return nil, fmt.Errorf("destination root %s overlaps library %s", root, library.Path)
+			}
+		}
+	}
+
+	query := o.db.Order("path")
+	switch {
+	case len(req.MediaFileIDs) > 0:
+		query = query.Where("id IN ?", req.MediaFileIDs)
+	case req.LibraryID != 0:
+		query = query.Where("library_id = ? AND media_type IN ?", req.LibraryID,
+			[]database.MediaType{database.MediaTypeMovie, database.MediaTypeEpisode})
+	default:
+		return nil, fmt.Errorf("a library_id or media_file_ids are required")
+	}
+	var mediaFiles []database.MediaFile
+	if err := query.Find(&mediaFiles).Error; err != nil {
+		return nil, fmt.Errorf("failed to get media files: %w", err)
+	}
+
+	plan := &Plan{Mode: mode, Moves: make([]Move, 0, len(mediaFiles))}
+	claimed := make(map[string]string) // Destination to the file planned there
+	for i := range mediaFiles {
+		file := &mediaFiles[i]
+		fileRoot := root
+		if mode == ModeMove {
+			fileRoot = libraryPaths[file.LibraryID]
+		}
+
+		move := o.planFile(file, mode, fileRoot)
+		if move.Status == StatusReady {
+			key := strings.ToLower(move.DestinationPath)
+			if other, ok := claimed[key]; ok {
+				move.Status, move.Reason = StatusConflict, fmt.Sprintf("%s is organized to the same path", other)
+			} else {
+				claimed[key] = file.Path
+			}
+		}
+		plan.Moves = append(plan.Moves, move)
+	}
+	plan.Counts = countMoves(plan.Moves)
+	return plan, nil
+}
+
+// planFile works out where a media file belongs below root
+func (o *Organizer) planFile(file *database.MediaFile, mode, root string) Move {
+	move := Move{MediaFileID: file.ID, SourcePath: file.Path, root: root}
+	skip := func(reason string) Move {
+		move.Status, move.Reason = StatusSkipped, reason
+		return move
+	}
+
+	if root == "" {
+		return skip("library not found")
+	}
+	var template *Template
+	switch file.MediaType {
+	case database.MediaTypeMovie:
+		template = o.movieTemplate
+	case database.MediaTypeEpisode:
+		template = o.episodeTemplate
+	default:
+		return skip("not a movie or episode")
+	}
+	values, err := o.values(file)
+	if err != nil {
+		return skip(err.Error())
+	}
+	relative := template.Render(values)
+	if relative == "" {
+		return skip("template rendered an empty path")
+	}
+	move.DestinationPath = filepath.Join(root, relative) + filepath.Ext(file.Path)
+
+	source, err := os.Stat(file.Path)
+	if err != nil {
+		return skip("file not found")
+	}
+	if move.DestinationPath == file.Path {
+		move.Status = StatusOrganized
+		return move
+	}
+	if existing, err := os.Stat(move.DestinationPath); err == nil {
+		if os.SameFile(source, existing) || (mode == ModeCopy && existing.Size() == source.Size()) {
+			move.Status = StatusOrganized
+			return move
+		}
+		move.Status, move.Reason = StatusConflict, "another file is at the destination"
+		return move
+	}
+
+	move.Sidecars = planSidecars(file.Path, move.DestinationPath)
+	move.Status = StatusReady
+	return move
+}
+
+// planSidecars renames the subtitle files beside a media file after its new
+// name, keeping their tags, e.g. "Old.en.srt" to "New.en.srt". Subtitles
+// that would replace another file stay where they are.
+func planSidecars(source, destination string) []SidecarMove {
+	sidecars, err := subtitlemodule.FindSidecars(source)
+	if err != nil {
+		return nil
+	}
+	oldStem := strings.TrimSuffix(filepath.Base(source), filepath.Ext(source))
+	newStem := strings.TrimSuffix(destination, filepath.Ext(destination))
+
+	var moves []SidecarMove
+	for _, sidecar := range sidecars {
+		name := filepath.Base(sidecar.Path)
+		target := newStem + name[len(oldStem):]
+		if _, err := os.Stat(target); err == nil {
+			continue
+		}
+		moves = append(moves, SidecarMove{SourcePath: sidecar.Path, DestinationPath: target})
+	}
+	return moves
+}
+
+// values reads the metadata a media file's template is filled in with
+func (o *Organizer) values(file *database.MediaFile) (Values, error) {
+	values := Values{Resolution: file.Resolution, Version: file.VersionName}
+
+	switch file.MediaType {
+	case database.MediaTypeMovie:
+		var movie database.Movie
+		if err := o.db.Select("id, title, release_date").Where("id = ?", file.MediaID).First(&movie).Error; err != nil {
+			return values, fmt.Errorf("movie not found")
+		}
+		values.Title = movie.Title
+		if movie.ReleaseDate != nil {
+			values.Year = movie.ReleaseDate.Year()
+		}
+		if values.Title == "" {
+			return values, fmt.Errorf("movie has no title")
+		}
+	case database.MediaTypeEpisode:
+		var episode database.Episode
+		if err := o.db.Preload("Season.TVShow").Where("id = ?", file.MediaID).First(&episode).Error; err != nil {
+			return values, fmt.Errorf("episode not found")
+		}
+		values.Title = episode.Title
+		values.Episode = episode.EpisodeNumber
+		values.Season = episode.Season.SeasonNumber
+		values.Show = episode.Season.TVShow.Title
+		if episode.Season.TVShow.FirstAirDate != nil {
+			values.Year = episode.Season.TVShow.FirstAirDate.Year()
+		}
+		if values.Show == "" {
+			return values, fmt.Errorf("episode has no show title")
+		}
+	}
+	return values, nil
+}
+
+// organize moves, links or copies a planned file and its subtitles,
+// journaling each before it is touched
+func (o *Organizer) organize(batchID, mode string, move *Move) error {
+	if err := os.MkdirAll(filepath.Dir(move.DestinationPath), 0755); err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
+	}
+
+	operation := &Operation{
+		BatchID:         batchID,
+		MediaFileID:     move.MediaFileID,
+		Kind:            KindMedia,
+		Mode:            mode,
+		SourcePath:      move.SourcePath,
+		DestinationPath: move.DestinationPath,
+		Root:            move.root,
+	}
+	if err := o.db.Create(operation).Error; err != nil {
+		return fmt.Errorf("failed to journal operation: %w", err)
+	}
+
+	var err error
+	if mode == ModeMove {
+		err = o.moveMediaFile(move.MediaFileID, move.SourcePath, move.DestinationPath)
+	} else {
+		err = TransferFile(mode, move.SourcePath, move.DestinationPath)
+	}
+	if err != nil {
+		o.db.Delete(operation)
+		utils.RemoveEmptyDirs(filepath.Dir(move.DestinationPath), move.root)
+		return err
+	}
+
+	for _, sidecar := range move.Sidecars {
+		operation := &Operation{
+			BatchID:         batchID,
+			MediaFileID:     move.MediaFileID,
+			Kind:            KindSidecar,
+			Mode:            mode,
+			SourcePath:      sidecar.SourcePath,
+			DestinationPath: sidecar.DestinationPath,
+			Root:            move.root,
+		}
+		if err := o.db.Create(operation).Error; err != nil {
+			log.Printf("WARN: Failed to journal subtitle file %s: %v", sidecar.SourcePath, err)
+			continue
+		}
+		if err := TransferFile(mode, sidecar.SourcePath, sidecar.DestinationPath); err != nil {
+			o.db.Delete(operation)
+			log.Printf("WARN: Failed to organize subtitle file %s: %v", sidecar.SourcePath, err)
+		}
+	}
+
+	if mode == ModeMove {
+		utils.RemoveEmptyDirs(filepath.Dir(move.SourcePath), move.root)
+	}
+
+	log.Printf("INFO: Organized %s to %s (%s)", move.SourcePath, move.DestinationPath, mode)
+	if o.eventBus != nil {
+		event := events.NewSystemEvent(
+			events.EventMediaFileOrganized,
+			"Media File Organized",
+			fmt.Sprintf("Organized %s", filepath.Base(move.DestinationPath)),
+		)
+		event.Data = map[string]interface{}{
+			"media_file_id":    move.MediaFileID,
+			"source_path":      move.SourcePath,
+			"destination_path": move.DestinationPath,
+			"mode":             mode,
+			"batch_id":         batchID,
+		}
+		o.eventBus.PublishAsync(event)
+	}
+	return nil
+}
+
+// moveMediaFile renames a library file, pointing its media file at the new
+// path first so the file monitor sees a known file arrive rather than one
+// disappear
+func (o *Organizer) moveMediaFile(mediaFileID, source, destination string) error {
+	if err := o.setMediaFilePath(mediaFileID, source, destination); err != nil {
+		return err
+	}
+	if err := utils.MoveFile(source, destination); err != nil {
+		if restoreErr := o.setMediaFilePath(mediaFileID, destination, source); restoreErr != nil {
+			log.Printf("ERROR: Failed to restore path of media file %s: %v", mediaFileID, restoreErr)
+		}
+		return err
+	}
+	return nil
+}
+
+// setMediaFilePath updates a media file's path if it is still at from
+func (o *Organizer) setMediaFilePath(mediaFileID, from, to string) error {
+	err := o.db.Model(&database.MediaFile{}).
+		Where("id = ? AND path = ?", mediaFileID, from).
+		Updates(map[string]interface{}{"path": to, "updated_at": time.Now()}).Error
+	if err != nil {
+		return fmt.Errorf("failed to update media file path: %w", err)
+	}
+	return nil
+}
+
+// Journal lists the most recent batches, newest first
+func (o *Organizer) Journal(limit int) ([]Batch, error) {
+	var recent []struct {
+		BatchID string
+		LastID  uint32
+	}
+	if err := o.db.Model(&Operation{}).
+		Select("batch_id, MAX(id) AS last_id").
+		Group("batch_id").
+		Order("last_id DESC").
+		Limit(limit).
+		Scan(&recent).Error; err != nil {
+		return nil, fmt.Errorf("failed to get journal: %w", err)
+	}
+
+	batchIDs := make([]string, 0, len(recent))
+	for _, batch := range recent {
+		batchIDs = append(batchIDs, batch.BatchID)
+	}
+	var operations []Operation
+	if len(batchIDs) > 0 {
+		if err := o.db.Where("batch_id IN ?", batchIDs).Find(&operations).Error; err != nil {
+			return nil, fmt.Errorf("failed to get journal: %w", err)
+		}
+	}
+
+	batches := make(map[string]*Batch, len(batchIDs))
+	pending := make(map[string]bool)
+	for _, operation := range operations {
+		batch, ok := batches[operation.BatchID]
+		if !ok {
+			batch = &Batch{BatchID: operation.BatchID, Mode: operation.Mode, CreatedAt: operation.CreatedAt}
+			batches[operation.BatchID] = batch
+		}
+		if operation.Kind == KindMedia {
+			batch.Files++
+		}
+		if operation.CreatedAt.Before(batch.CreatedAt) {
+			batch.CreatedAt = operation.CreatedAt
+		}
+		switch {
+		case operation.UndoneAt == nil:
+			pending[operation.BatchID] = true
+		case batch.UndoneAt == nil || operation.UndoneAt.After(*batch.UndoneAt):
+			batch.UndoneAt = operation.UndoneAt
+		}
+	}
+
+	result := make([]Batch, 0, len(batchIDs))
+	for _, batchID := range batchIDs {
+		batch := batches[batchID]
+		// A batch is undone once all of its operations are
+		if pending[batchID] {
+			batch.UndoneAt = nil
+		}
+		result = append(result, *batch)
+	}
+	return result, nil
+}
+
+// BatchOperations lists the operations of a batch in the order applied
+func (o *Organizer) BatchOperations(batchID string) ([]Operation, error) {
+	var operations []Operation
+	if err := o.db.Where("batch_id = ?", batchID).Order("id").Find(&operations).Error; err != nil {
+		return nil, fmt.Errorf("failed to get batch: %w", err)
+	}
+	return operations, nil
+}
+
+// Undo puts the files of a batch back, newest operation first. Operations
+// that fail are reported and can be retried.
+func (o *Organizer) Undo(batchID string) (*UndoResult, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	var operations []Operation
+	if err := o.db.Where("batch_id = ? AND undone_at IS NULL", batchID).Order("id DESC").Find(&operations).Error; err != nil {
+		return nil, fmt.Errorf("failed to get batch: %w", err)
+	}
+	if len(operations) == 0 {
+		return nil, ErrBatchNotFound
+	}
+
+	result := &UndoResult{BatchID: batchID, Failed: []UndoFailure{}}
+	for i := range operations {
+		operation := &operations[i]
+		if err := o.undo(operation); err != nil {
+			result.Failed = append(result.Failed, UndoFailure{
+				OperationID: operation.ID,
+				Path:        operation.DestinationPath,
+				Error:       err.Error(),
+			})
+			continue
+		}
+
+		now := time.Now()
+		if err := o.db.Model(operation).Update("undone_at", now).Error; err != nil {
+			log.Printf("WARN: Failed to mark organizer operation %d undone: %v", operation.ID, err)
+		}
+		if operation.Kind == KindMedia {
+			result.Restored++
+		}
+	}
+
+	log.Printf("INFO: Undid organizer batch %s: %d files restored, %d operations failed", batchID, result.Restored, len(result.Failed))
+	return result, nil
+}
+
+// undo reverses one operation: moved files are moved back, links and copies
+// are removed
+func (o *Organizer) undo(operation *Operation) error {
+	if operation.Mode != ModeMove {
+		if err := os.Remove(operation.DestinationPath); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		utils.RemoveEmptyDirs(filepath.Dir(operation.DestinationPath), operation.Root)
+		return nil
+	}
+
+	if _, err := os.Stat(operation.SourcePath); err == nil {
+		return fmt.Errorf("another file is at %s", operation.SourcePath)
+	}
+	if err := os.MkdirAll(filepath.Dir(operation.SourcePath), 0755); err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
+	}
+
+	var err error
+	if operation.Kind == KindMedia {
+		err = o.moveMediaFile(operation.MediaFileID, operation.DestinationPath, operation.SourcePath)
+	} else {
+		err = utils.MoveFile(operation.DestinationPath, operation.SourcePath)
+	}
+	if err != nil {
+		return err
+	}
+	utils.RemoveEmptyDirs(filepath.Dir(operation.DestinationPath), operation.Root)
+	return nil
+}
+
+// TransferFile puts a file at destination the way mode says: moved,
+// hardlinked or copied
+func TransferFile(mode, source, destination string) error {
+	switch mode {
+	case ModeHardlink:
+		if err := os.Link(source, destination); err != nil {
+			if errors.Is(err, syscall.EXDEV) {
+				return fmt.Errorf("hardlinks need the destination on the same file system as the library: %w", err)
+			}
+			return err
+		}
+		return nil
+	case ModeCopy:
+		return utils.CopyFile(source, destination)
+	default:
+		return utils.MoveFile(source, destination)
+	}
+}
+
+func countMoves(moves []Move) map[string]int {
+	counts := make(map[string]int)
+	for _, move := range moves {
+		counts[move.Status]++
+	}
+	return counts
+}
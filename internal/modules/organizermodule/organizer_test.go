@@ -0,0 +1,210 @@
+package organizermodule
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/mantonx/viewra/internal/config"
+	"github.com/mantonx/viewra/internal/database"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// setupOrganizerTest creates a library holding an organized movie, a movie
+// with a subtitle file, an episode, a second file of the same movie, a file
+// missing from disk and one whose movie is gone
+func setupOrganizerTest(t *testing.T) (*gorm.DB, *Organizer, string) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	require.NoError(t, err)
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	sqlDB.SetMaxOpenConns(1)
+	require.NoError(t, db.AutoMigrate(&database.MediaLibrary{}, &database.MediaFile{}, &database.Movie{},
+		&database.TVShow{}, &database.Season{}, &database.Episode{}, &Operation{}))
+
+	library := t.TempDir()
+	for _, name := range []string{"Alien (1979)/Alien (1979).mkv", "matrix.mkv", "matrix.en.srt", "pilot.mkv", "z-matrix.mkv"} {
+		path := filepath.Join(library, filepath.FromSlash(name))
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte(name), 0644))
+	}
+
+	date := func(year int) *time.Time {
+		d := time.Date(year, 1, 1, 0, 0, 0, 0, time.UTC)
+		return &d
+	}
+	require.NoError(t, db.Create(&database.MediaLibrary{ID: 1, Path: library, Type: "movie"}).Error)
+	require.NoError(t, db.Create(&[]database.Movie{
+		{ID: "alien", Title: "Alien", ReleaseDate: date(1979)},
+		{ID: "matrix", Title: "The Matrix", ReleaseDate: date(1999)},
+		{ID: "hackers", Title: "Hackers", ReleaseDate: date(1995)},
+	}).Error)
+	require.NoError(t, db.Create(&database.TVShow{ID: "show", Title: "Lost", FirstAirDate: date(2004)}).Error)
+	require.NoError(t, db.Create(&database.Season{ID: "season", TVShowID: "show", SeasonNumber: 1}).Error)
+	require.NoError(t, db.Create(&database.Episode{ID: "pilot", SeasonID: "season", Title: "Pilot", EpisodeNumber: 1}).Error)
+	require.NoError(t, db.Create(&[]database.MediaFile{
+		{ID: "file-alien", MediaID: "alien", MediaType: database.MediaTypeMovie, LibraryID: 1,
+			Path: filepath.Join(library, "Alien (1979)", "Alien (1979).mkv")},
+		{ID: "file-matrix", MediaID: "matrix", MediaType: database.MediaTypeMovie, LibraryID: 1, Path: filepath.Join(library, "matrix.mkv")},
+		{ID: "file-pilot", MediaID: "pilot", MediaType: database.MediaTypeEpisode, LibraryID: 1, Path: filepath.Join(library, "pilot.mkv")},
+		{ID: "file-duplicate", MediaID: "matrix", MediaType: database.MediaTypeMovie, LibraryID: 1, Path: filepath.Join(library, "z-matrix.mkv")},
+		{ID: "file-missing", MediaID: "hackers", MediaType: database.MediaTypeMovie, LibraryID: 1, Path: filepath.Join(library, "hackers.mkv")},
+		{ID: "file-orphan", MediaID: "deleted", MediaType: database.MediaTypeMovie, LibraryID: 1, Path: filepath.Join(library, "orphan.mkv")},
+	}).Error)
+
+	organizer, err := NewOrganizer(db, nil, config.OrganizerConfig{
+		Mode:            ModeMove,
+		MovieTemplate:   "{Title} ({Year})/{Title} ({Year})",
+		EpisodeTemplate: "{Show} ({Year})/Season {S}/{Show} - S{S}E{E} - {Title}",
+	})
+	require.NoError(t, err)
+	return db, organizer, library
+}
+
+// mediaFilePath reads the path the database has for a media file
+func mediaFilePath(t *testing.T, db *gorm.DB, id string) string {
+	var file database.MediaFile
+	require.NoError(t, db.Where("id = ?", id).First(&file).Error)
+	return file.Path
+}
+
+func TestPlanRequests(t *testing.T) {
+	_, organizer, library := setupOrganizerTest(t)
+
+	tests := []struct {
+		name    string
+		req     Request
+		wantErr string
+	}{
+		{"no files selected", Request{}, "library_id or media_file_ids are required"},
+		{"unknown mode", Request{LibraryID: 1, Mode: "symlink"}, "invalid mode"},
+		{"copy without a root", Request{LibraryID: 1, Mode: ModeCopy}, "needs an absolute destination root"},
+		{"relative root", Request{LibraryID: 1, Mode: ModeHardlink, DestinationRoot: "organized"}, "needs an absolute destination root"},
+		{"root inside the library", Request{LibraryID: 1, Mode: ModeCopy, DestinationRoot: filepath.Join(library, "organized")}, "overlaps library"},
+		{"root above the library", Request{LibraryID: 1, Mode: ModeCopy, DestinationRoot: filepath.Dir(library)}, "overlaps library"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := organizer.Preview(tt.req)
+			require.Error(t, err)
+			require.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestPreview(t *testing.T) {
+	db, organizer, library := setupOrganizerTest(t)
+
+	plan, err := organizer.Preview(Request{LibraryID: 1})
+	require.NoError(t, err)
+	require.Empty(t, plan.BatchID)
+	require.Equal(t, ModeMove, plan.Mode)
+	require.Equal(t, map[string]int{StatusOrganized: 1, StatusReady: 2, StatusConflict: 1, StatusSkipped: 2}, plan.Counts)
+
+	matrix := filepath.Join(library, "The Matrix (1999)", "The Matrix (1999).mkv")
+	tests := []struct {
+		mediaFileID string
+		status      string
+		destination string
+		reason      string
+		sidecars    []SidecarMove
+	}{
+		{"file-alien", StatusOrganized, filepath.Join(library, "Alien (1979)", "Alien (1979).mkv"), "", nil},
+		{"file-missing", StatusSkipped, filepath.Join(library, "Hackers (1995)", "Hackers (1995).mkv"), "file not found", nil},
+		{"file-matrix", StatusReady, matrix, "", []SidecarMove{{
+			SourcePath:      filepath.Join(library, "matrix.en.srt"),
+			DestinationPath: filepath.Join(library, "The Matrix (1999)", "The Matrix (1999).en.srt"),
+		}}},
+		{"file-orphan", StatusSkipped, "", "movie not found", nil},
+		{"file-pilot", StatusReady, filepath.Join(library, "Lost (2004)", "Season 01", "Lost - S01E01 - Pilot.mkv"), "", nil},
+		{"file-duplicate", StatusConflict, matrix, filepath.Join(library, "matrix.mkv") + " is organized to the same path", nil},
+	}
+	require.Len(t, plan.Moves, len(tests))
+	for i, tt := range tests {
+		t.Run(tt.mediaFileID, func(t *testing.T) {
+			move := plan.Moves[i]
+			require.Equal(t, tt.mediaFileID, move.MediaFileID)
+			require.Equal(t, tt.status, move.Status)
+			require.Equal(t, tt.destination, move.DestinationPath)
+			require.Equal(t, tt.reason, move.Reason)
+			require.Equal(t, tt.sidecars, move.Sidecars)
+		})
+	}
+
+	// A preview leaves files, paths and the journal alone
+	require.FileExists(t, filepath.Join(library, "matrix.mkv"))
+	require.FileExists(t, filepath.Join(library, "matrix.en.srt"))
+	require.NoDirExists(t, filepath.Join(library, "The Matrix (1999)"))
+	require.Equal(t, filepath.Join(library, "matrix.mkv"), mediaFilePath(t, db, "file-matrix"))
+	var operations int64
+	require.NoError(t, db.Model(&Operation{}).Count(&operations).Error)
+	require.Zero(t, operations)
+}
+
+func TestApplyAndUndo(t *testing.T) {
+	db, organizer, library := setupOrganizerTest(t)
+
+	plan, err := organizer.Apply(Request{MediaFileIDs: []string{"file-matrix", "file-pilot", "file-orphan"}})
+	require.NoError(t, err)
+	require.NotEmpty(t, plan.BatchID)
+	require.Equal(t, map[string]int{StatusDone: 2, StatusSkipped: 1}, plan.Counts)
+
+	matrix := filepath.Join(library, "The Matrix (1999)", "The Matrix (1999).mkv")
+	pilot := filepath.Join(library, "Lost (2004)", "Season 01", "Lost - S01E01 - Pilot.mkv")
+	require.FileExists(t, matrix)
+	require.FileExists(t, filepath.Join(library, "The Matrix (1999)", "The Matrix (1999).en.srt"))
+	require.FileExists(t, pilot)
+	require.NoFileExists(t, filepath.Join(library, "matrix.mkv"))
+	require.NoFileExists(t, filepath.Join(library, "matrix.en.srt"))
+	require.Equal(t, matrix, mediaFilePath(t, db, "file-matrix"))
+	require.Equal(t, pilot, mediaFilePath(t, db, "file-pilot"))
+
+	operations, err := organizer.BatchOperations(plan.BatchID)
+	require.NoError(t, err)
+	require.Len(t, operations, 3)
+
+	// Organized files are planned where they are
+	again, err := organizer.Preview(Request{MediaFileIDs: []string{"file-matrix", "file-pilot"}})
+	require.NoError(t, err)
+	require.Equal(t, map[string]int{StatusOrganized: 2}, again.Counts)
+
+	result, err := organizer.Undo(plan.BatchID)
+	require.NoError(t, err)
+	require.Equal(t, 2, result.Restored)
+	require.Empty(t, result.Failed)
+
+	require.FileExists(t, filepath.Join(library, "matrix.mkv"))
+	require.FileExists(t, filepath.Join(library, "matrix.en.srt"))
+	require.FileExists(t, filepath.Join(library, "pilot.mkv"))
+	require.NoDirExists(t, filepath.Join(library, "The Matrix (1999)"))
+	require.NoDirExists(t, filepath.Join(library, "Lost (2004)"))
+	require.DirExists(t, library)
+	require.Equal(t, filepath.Join(library, "matrix.mkv"), mediaFilePath(t, db, "file-matrix"))
+	require.Equal(t, filepath.Join(library, "pilot.mkv"), mediaFilePath(t, db, "file-pilot"))
+
+	_, err = organizer.Undo(plan.BatchID)
+	require.ErrorIs(t, err, ErrBatchNotFound)
+}
+
+func TestCopyAndUndo(t *testing.T) {
+	_, organizer, library := setupOrganizerTest(t)
+	root := t.TempDir()
+
+	plan, err := organizer.Apply(Request{MediaFileIDs: []string{"file-matrix"}, Mode: ModeCopy, DestinationRoot: root})
+	require.NoError(t, err)
+	require.Equal(t, map[string]int{StatusDone: 1}, plan.Counts)
+	copied := filepath.Join(root, "The Matrix (1999)", "The Matrix (1999).mkv")
+	require.FileExists(t, copied)
+	require.FileExists(t, filepath.Join(library, "matrix.mkv"))
+
+	result, err := organizer.Undo(plan.BatchID)
+	require.NoError(t, err)
+	require.Equal(t, 1, result.Restored)
+	require.NoFileExists(t, copied)
+	require.NoDirExists(t, filepath.Join(root, "The Matrix (1999)"))
+	require.FileExists(t, filepath.Join(library, "matrix.mkv"))
+}
@@ -0,0 +1,211 @@
+package organizermodule
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Template fields, referred to in templates as {Field}. Numbers can be
+// zero-padded to a width with {Field:N}; seasons and episodes are padded to
+// two digits by default.
+const (
+	FieldTitle      = "Title"      // Movie or episode title
+	FieldYear       = "Year"       // Movie release year, or the show's first air year
+	FieldShow       = "Show"       // Show title
+	FieldSeason     = "S"          // Season number
+	FieldEpisode    = "E"          // Episode number
+	FieldResolution = "Resolution" // e.g. 1080p
+	FieldVersion    = "Version"    // e.g. Director's Cut
+)
+
+var templateFields = map[string]bool{
+	FieldTitle:      true,
+	FieldYear:       true,
+	FieldShow:       true,
+	FieldSeason:     true,
+	FieldEpisode:    true,
+	FieldResolution: true,
+	FieldVersion:    true,
+}
+
+// numberFields are the fields that can be padded
+var numberFields = map[string]int{
+	FieldYear:    0,
+	FieldSeason:  2,
+	FieldEpisode: 2,
+}
+
+// Values are what a template is filled in with for a media file
+type Values struct {
+	Title      string
+	Year       int
+	Show       string
+	Season     int
+	Episode    int
+	Resolution string
+	Version    string
+}
+
+// Template renders a relative path, without extension, from a media file's
+// metadata, e.g. "{Show} ({Year})/Season {S}/{Show} - S{S}E{E} - {Title}"
+type Template struct {
+	raw   string
+	parts []templatePart
+}
+
+// templatePart is literal text or a field reference
+type templatePart struct {
+	literal string
+	field   string
+	width   int
+}
+
+var (
+	// fieldPattern matches a field reference
+	fieldPattern = regexp.MustCompile(`\{([A-Za-z]+)(?::(\d+))?\}`)
+
+	// emptyBrackets matches the brackets left by empty fields, e.g. "()"
+	emptyBrackets = regexp.MustCompile(`\(\s*\)|\[\s*\]|\{\s*\}`)
+
+	// separatorRun matches the separators left by empty fields, e.g. " -  - "
+	separatorRun = regexp.MustCompile(`\s*-\s*(-\s*)+`)
+)
+
+// invalidNameChars replaces the characters file systems don't allow in
+// names, so values such as "Face/Off" or "Mission: Impossible" stay readable
+var invalidNameChars = strings.NewReplacer(
+	"/", "-",
+	"\\", "-",
+	":", " -",
+	"*", "",
+	"?", "",
+	"\"", "'",
+	"<", "",
+	">", "",
+	"|", "-",
+)
+
+// ParseTemplate parses a template. Templates use "/" between directories,
+// must be relative and may only refer to known fields.
+func ParseTemplate(raw string) (*Template, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return nil, fmt.Errorf("template is empty")
+	}
+	if strings.HasPrefix(raw, "/") || filepath.IsAbs(raw) {
+		return nil, fmt.Errorf("template %q must be relative", raw)
+	}
+	for _, segment := range strings.Split(raw, "/") {
+		if segment == ".." || segment == "." {
+			return nil, fmt.Errorf("template %q may not contain %q", raw, segment)
+		}
+	}
+
+	t := &Template{raw: raw}
+	last := 0
+	for _, match := range fieldPattern.FindAllStringSubmatchIndex(raw, -1) {
+		field := raw[match[2]:match[3]]
+		if !templateFields[field] {
+			return nil, fmt.Errorf("template %q refers to unknown field {%s}", raw, field)
+		}
+		width, padded := numberFields[field]
+		if match[4] >= 0 {
+			if !padded {
+				return nil, fmt.Errorf("template %q pads {%s}, which is not a number", raw, field)
+			}
+			width, _ = strconv.Atoi(raw[match[4]:match[5]])
+		}
+
+		if err := t.addLiteral(raw[last:match[0]]); err != nil {
+			return nil, err
+		}
+		t.parts = append(t.parts, templatePart{field: field, width: width})
+		last = match[1]
+	}
+	if err := t.addLiteral(raw[last:]); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+func (t *Template) addLiteral(literal string) error {
+	if strings.ContainsAny(literal, "{}") {
+		return fmt.Errorf("template %q has an unmatched brace", t.raw)
+	}
+	if literal != "" {
+		t.parts = append(t.parts, templatePart{literal: literal})
+	}
+	return nil
+}
+
+// String returns the template as written
+func (t *Template) String() string {
+	return t.raw
+}
+
+// Render fills the template in and returns the relative path it names.
+// Empty fields are left out along with the brackets and separators around
+// them, and directories left empty are dropped.
+func (t *Template) Render(values Values) string {
+	var b strings.Builder
+	for _, part := range t.parts {
+		if part.field == "" {
+			b.WriteString(part.literal)
+			continue
+		}
+		b.WriteString(fieldValue(values, part))
+	}
+
+	var segments []string
+	for _, segment := range strings.Split(b.String(), "/") {
+		if segment = cleanSegment(segment); segment != "" {
+			segments = append(segments, segment)
+		}
+	}
+	return filepath.Join(segments...)
+}
+
+// fieldValue formats a field, made safe to use in a file name
+func fieldValue(values Values, part templatePart) string {
+	switch part.field {
+	case FieldTitle:
+		return invalidNameChars.Replace(values.Title)
+	case FieldShow:
+		return invalidNameChars.Replace(values.Show)
+	case FieldResolution:
+		return invalidNameChars.Replace(values.Resolution)
+	case FieldVersion:
+		return invalidNameChars.Replace(values.Version)
+	case FieldYear:
+		if values.Year <= 0 {
+			return ""
+		}
+		return padNumber(values.Year, part.width)
+	case FieldSeason:
+		return padNumber(values.Season, part.width)
+	case FieldEpisode:
+		return padNumber(values.Episode, part.width)
+	}
+	return ""
+}
+
+func padNumber(n, width int) string {
+	return fmt.Sprintf("%0*d", width, n)
+}
+
+// cleanSegment tidies a rendered file or directory name
+func cleanSegment(segment string) string {
+	segment = emptyBrackets.ReplaceAllString(segment, "")
+	segment = separatorRun.ReplaceAllString(segment, " - ")
+	segment = strings.Join(strings.Fields(segment), " ")
+	segment = strings.Trim(segment, " -")
+	// Names ending in a dot aren't allowed on Windows shares
+	segment = strings.TrimRight(segment, ". ")
+	if segment == "." || segment == ".." {
+		return ""
+	}
+	return segment
+}
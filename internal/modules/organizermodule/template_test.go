@@ -0,0 +1,87 @@
+package organizermodule
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTemplate(t *testing.T) {
+	tests := []struct {
+		name    string
+		raw     string
+		wantErr string
+	}{
+		{"movie", "{Title} ({Year})/{Title} ({Year})", ""},
+		{"episode", "{Show} ({Year})/Season {S}/{Show} - S{S}E{E:3} - {Title}", ""},
+		{"empty", "  ", "empty"},
+		{"absolute", "/media/{Title}", "must be relative"},
+		{"parent directory", "../{Title}", `may not contain ".."`},
+		{"nested parent directory", "{Title}/../{Title}", `may not contain ".."`},
+		{"current directory", "./{Title}", `may not contain "."`},
+		{"unknown field", "{Title} {Director}", "unknown field {Director}"},
+		{"padded text field", "{Title:2}", "not a number"},
+		{"unmatched open brace", "{Title", "unmatched brace"},
+		{"unmatched close brace", "Title}", "unmatched brace"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			template, err := ParseTemplate(tt.raw)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, strings.TrimSpace(tt.raw), template.String())
+		})
+	}
+}
+
+func TestTemplateRender(t *testing.T) {
+	const (
+		movie   = "{Title} ({Year})/{Title} ({Year})"
+		episode = "{Show} ({Year})/Season {S}/{Show} - S{S}E{E} - {Title}"
+	)
+
+	tests := []struct {
+		name     string
+		template string
+		values   Values
+		want     string
+	}{
+		{"movie", movie, Values{Title: "The Matrix", Year: 1999}, "The Matrix (1999)/The Matrix (1999)"},
+		{"episode", episode, Values{Show: "Lost", Year: 2004, Season: 1, Episode: 2, Title: "Pilot"},
+			"Lost (2004)/Season 01/Lost - S01E02 - Pilot"},
+		{"padding", "{Show} {S:1}x{E:3}", Values{Show: "Lost", Season: 1, Episode: 2}, "Lost 1x002"},
+
+		{"empty year drops its brackets", movie, Values{Title: "The Matrix"}, "The Matrix/The Matrix"},
+		{"empty title drops its separator", episode, Values{Show: "Lost", Year: 2004, Season: 1, Episode: 2},
+			"Lost (2004)/Season 01/Lost - S01E02"},
+		{"empty field between separators", "{Show} - {Title} - {Resolution}", Values{Show: "Lost", Resolution: "1080p"},
+			"Lost - 1080p"},
+		{"empty square brackets", "{Title} [{Version}]", Values{Title: "Alien"}, "Alien"},
+		{"filled square brackets", "{Title} [{Version}]", Values{Title: "Alien", Version: "Director's Cut"},
+			"Alien [Director's Cut]"},
+		{"empty directory", "{Version}/{Title}", Values{Title: "Alien"}, "Alien"},
+
+		{"slash", movie, Values{Title: "Face/Off", Year: 1997}, "Face-Off (1997)/Face-Off (1997)"},
+		{"colon", "{Title}", Values{Title: "Mission: Impossible"}, "Mission - Impossible"},
+		{"reserved characters", "{Title}", Values{Title: `What? <Now> "Quoted" *Star* A|B`}, "What Now 'Quoted' Star A-B"},
+		{"trailing dots", "{Title}", Values{Title: "Se7en..."}, "Se7en"},
+		{"dot directory", "{Title}/{Show}", Values{Title: "..", Show: "Lost"}, "Lost"},
+		{"path in a value", "{Title}", Values{Title: "../../etc/passwd"}, "..-..-etc-passwd"},
+		{"nothing left", "{Version}", Values{}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			template, err := ParseTemplate(tt.template)
+			require.NoError(t, err)
+			require.Equal(t, filepath.FromSlash(tt.want), template.Render(tt.values))
+		})
+	}
+}
@@ -0,0 +1,156 @@
+package peoplemodule
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/mantonx/viewra/internal/auth"
+	"github.com/mantonx/viewra/internal/database"
+	"github.com/mantonx/viewra/internal/parental"
+	"gorm.io/gorm"
+)
+
+// Visibility hides what a user may not or doesn't want to see
+type Visibility struct {
+	UserID uint32
+	Rated  *parental.Denied // nil without parental controls
+}
+
+// CreditRole is one of a person's parts in an item
+type CreditRole struct {
+	Role       string `json:"role"`
+	Character  string `json:"character,omitempty"`
+	Department string `json:"department,omitempty"`
+}
+
+// FilmographyEntry is a movie or TV show in the library a person is
+// credited on
+type FilmographyEntry struct {
+	MediaType string       `json:"media_type"` // movie or tv_show
+	ID        string       `json:"id"`
+	Title     string       `json:"title"`
+	Year      int          `json:"year,omitempty"`
+	Poster    string       `json:"poster,omitempty"`
+	Roles     []CreditRole `json:"roles"`
+
+	// Episodes in the library the person is credited on, for TV shows
+	Episodes int `json:"episodes,omitempty"`
+}
+
+// creditRow is a credit joined with the item it belongs to
+type creditRow struct {
+	ID         string
+	Title      string
+	Date       *time.Time
+	Poster     string
+	EpisodeID  string
+	Role       string
+	Character  string
+	Department string
+}
+
+// Filmography lists the movies and TV shows with files in the library that
+// a person is credited on, newest first. TV credits are recorded per
+// episode and grouped by show. With visibility, items the user has hidden
+// or may not see are left out.
+func Filmography(db *gorm.DB, personID string, visibility *Visibility) ([]FilmographyEntry, error) {
+	var movies []creditRow
+	movieQuery := db.Table("roles").
+		Select("movies.id, movies.title, movies.release_date AS date, movies.poster, "+
+			"roles.role, roles.character, roles.department").
+		Joins("JOIN movies ON movies.id = roles.media_id").
+		Where("roles.person_id = ? AND roles.media_type = ?", personID, database.MediaTypeMovie).
+		Where("EXISTS (?)", libraryFiles(db, "movies.id", visibility))
+	if visibility != nil {
+		movieQuery = visibility.hideItems(db, movieQuery, "movies.id")
+		if visibility.Rated != nil {
+			movieQuery = movieQuery.Where("movies.id NOT IN (?)", visibility.Rated.Movies(db))
+		}
+	}
+	if err := movieQuery.Order("roles.billing_order").Scan(&movies).Error; err != nil {
+		return nil, fmt.Errorf("failed to load movie credits: %w", err)
+	}
+
+	var episodes []creditRow
+	episodeQuery := db.Table("roles").
+		Select("tv_shows.id, tv_shows.title, tv_shows.first_air_date AS date, tv_shows.poster, episodes.id AS episode_id, "+
+			"roles.role, roles.character, roles.department").
+		Joins("JOIN episodes ON episodes.id = roles.media_id").
+		Joins("JOIN seasons ON seasons.id = episodes.season_id").
+		Joins("JOIN tv_shows ON tv_shows.id = seasons.tv_show_id").
+		Where("roles.person_id = ? AND roles.media_type = ?", personID, database.MediaTypeEpisode).
+		Where("EXISTS (?)", libraryFiles(db, "episodes.id", visibility))
+	if visibility != nil {
+		episodeQuery = visibility.hideItems(db, episodeQuery, "episodes.id")
+		episodeQuery = visibility.hideItems(db, episodeQuery, "tv_shows.id")
+		if visibility.Rated != nil {
+			episodeQuery = episodeQuery.Where("tv_shows.id NOT IN (?)", visibility.Rated.Shows(db))
+		}
+	}
+	if err := episodeQuery.Order("roles.billing_order").Scan(&episodes).Error; err != nil {
+		return nil, fmt.Errorf("failed to load TV credits: %w", err)
+	}
+
+	entries := groupCredits(movies, "movie")
+	entries = append(entries, groupCredits(episodes, "tv_show")...)
+	sort.SliceStable(entries, func(i, j int) bool {
+		if entries[i].Year != entries[j].Year {
+			return entries[i].Year > entries[j].Year
+		}
+		return entries[i].Title < entries[j].Title
+	})
+	return entries, nil
+}
+
+// groupCredits collects credit rows into one entry per item, with each
+// distinct role once
+func groupCredits(rows []creditRow, mediaType string) []FilmographyEntry {
+	var entries []FilmographyEntry
+	index := make(map[string]int)
+	roles := make(map[string]bool)
+	episodes := make(map[string]bool)
+	for _, row := range rows {
+		i, ok := index[row.ID]
+		if !ok {
+			entry := FilmographyEntry{MediaType: mediaType, ID: row.ID, Title: row.Title, Poster: row.Poster, Roles: []CreditRole{}}
+			if row.Date != nil {
+				entry.Year = row.Date.Year()
+			}
+			i = len(entries)
+			index[row.ID] = i
+			entries = append(entries, entry)
+		}
+		entry := &entries[i]
+
+		if row.EpisodeID != "" && !episodes[row.EpisodeID] {
+			episodes[row.EpisodeID] = true
+			entry.Episodes++
+		}
+		role := CreditRole{Role: row.Role, Character: row.Character, Department: row.Department}
+		if key := row.ID + "\x00" + role.Role + "\x00" + role.Character; !roles[key] {
+			roles[key] = true
+			entry.Roles = append(entry.Roles, role)
+		}
+	}
+	return entries
+}
+
+// libraryFiles selects the media files of an item, in libraries the user
+// may see
+func libraryFiles(db *gorm.DB, itemColumn string, visibility *Visibility) *gorm.DB {
+	query := db.Table("media_files").Select("1").Where("media_files.media_id = " + itemColumn)
+	if visibility != nil {
+		hiddenLibraries := db.Model(&database.UserHiddenLibrary{}).Select("library_id").Where("user_id = ?", visibility.UserID)
+		query = query.
+			Where("media_files.library_id NOT IN (?)", hiddenLibraries).
+			Where("media_files.library_id NOT IN (?)", auth.DeniedLibraries(db, visibility.UserID))
+	}
+	return query
+}
+
+// hideItems leaves out the items the user has hidden
+func (v *Visibility) hideItems(db *gorm.DB, query *gorm.DB, itemColumn string) *gorm.DB {
+	hiddenItems := db.Model(&database.UserHiddenItem{}).Select("media_id").Where("user_id = ?", v.UserID)
+	return query.Where(itemColumn+" NOT IN (?)", hiddenItems)
+}
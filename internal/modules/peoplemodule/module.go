@@ -0,0 +1,226 @@
+// Package peoplemodule keeps the cast and crew of the library. People are
+// recorded by metadata providers through the host, matched by TMDb or IMDb
+// ID before name, and carry their biography, birth and death dates and a
+// downloaded headshot. Credits tie them to movies and episodes, and the
+// module lists each person's filmography within the library.
+package peoplemodule
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mantonx/viewra/internal/database"
+	"github.com/mantonx/viewra/internal/modules/modulemanager"
+	"github.com/mantonx/viewra/internal/parental"
+	"gorm.io/gorm"
+)
+
+// Auto-register the module when imported
+func init() {
+	Register()
+}
+
+const (
+	ModuleID   = "system.people"
+	ModuleName = "People"
+)
+
+// People per page by default, and at most
+const (
+	defaultListLimit = 50
+	maxListLimit     = 200
+)
+
+// Person is a person as the API returns them
+type Person struct {
+	database.People
+	ProfileURL string `json:"profile_url,omitempty"` // Downloaded headshot
+}
+
+// Module serves the people of the library
+type Module struct {
+	id      string
+	name    string
+	version string
+	core    bool
+	db      *gorm.DB
+}
+
+// Register registers this module with the module system
+func Register() {
+	peopleModule := &Module{
+		id:      ModuleID,
+		name:    ModuleName,
+		version: "1.0.0",
+		core:    true,
+	}
+	modulemanager.Register(peopleModule)
+}
+
+// ID returns the module ID
+func (m *Module) ID() string {
+	return m.id
+}
+
+// Name returns the module name
+func (m *Module) Name() string {
+	return m.name
+}
+
+// Core returns whether this is a core module
+func (m *Module) Core() bool {
+	return m.core
+}
+
+// Migrate brings the people and credits tables up to date
+func (m *Module) Migrate(db *gorm.DB) error {
+	return db.AutoMigrate(&database.People{}, &database.Roles{})
+}
+
+// Init initializes the module
+func (m *Module) Init() error {
+	m.db = database.GetDB()
+	log.Println("People module initialized")
+	return nil
+}
+
+// RegisterRoutes registers the people endpoints
+func (m *Module) RegisterRoutes(router *gin.Engine) {
+	people := router.Group("/api/people")
+	{
+		people.GET("", m.listPeople)
+		people.GET("/:id", m.getPerson)
+		people.GET("/:id/filmography", m.getFilmography)
+	}
+}
+
+// listPeople lists the people credited on items in the library, by name.
+// q narrows them to names containing it, role to people with that role.
+func (m *Module) listPeople(c *gin.Context) {
+	limit, offset := defaultListLimit, 0
+	for _, number := range []struct {
+		name  string
+		value *int
+	}{{"limit", &limit}, {"offset", &offset}} {
+		if raw := c.Query(number.name); raw != "" {
+			value, err := strconv.Atoi(raw)
+			if err != nil || value < 0 {
+				c.JSON(http.StatusBadRequest, gin.H{"error": number.name + " must be a non-negative number"})
+				return
+			}
+			*number.value = value
+		}
+	}
+	if limit == 0 || limit > maxListLimit {
+		limit = maxListLimit
+	}
+
+	credited := m.db.Table("roles").Select("roles.person_id").
+		Joins("JOIN media_files ON media_files.media_id = roles.media_id")
+	if role := strings.ToLower(strings.TrimSpace(c.Query("role"))); role != "" {
+		credited = credited.Where("roles.role = ?", role)
+	}
+	query := m.db.Model(&database.People{}).Where("id IN (?)", credited)
+	if q := strings.TrimSpace(c.Query("q")); q != "" {
+		query = query.Where("LOWER(name) LIKE ?", "%"+strings.ToLower(q)+"%")
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to count people: " + err.Error()})
+		return
+	}
+	var people []database.People
+	if err := query.Order("name").Limit(limit).Offset(offset).Find(&people).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load people: " + err.Error()})
+		return
+	}
+
+	results := make([]Person, 0, len(people))
+	for _, person := range people {
+		results = append(results, toPerson(person))
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"people": results,
+		"total":  total,
+		"limit":  limit,
+		"offset": offset,
+	})
+}
+
+// getPerson returns a person's details
+func (m *Module) getPerson(c *gin.Context) {
+	person, ok := m.loadPerson(c)
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, person)
+}
+
+// getFilmography lists the movies and shows in the library a person is
+// credited on. For the user in user_id, items they have hidden or may not
+// see are left out.
+func (m *Module) getFilmography(c *gin.Context) {
+	person, ok := m.loadPerson(c)
+	if !ok {
+		return
+	}
+
+	var visibility *Visibility
+	if raw := c.Query("user_id"); raw != "" {
+		userID, err := strconv.ParseUint(raw, 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+			return
+		}
+		visibility = &Visibility{UserID: uint32(userID)}
+		limit, err := parental.ForUser(m.db, visibility.UserID, time.Now())
+		if err == nil && limit != nil {
+			visibility.Rated, err = limit.Denied(m.db)
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check parental controls: " + err.Error()})
+			return
+		}
+	}
+
+	entries, err := Filmography(m.db, person.ID, visibility)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"person":      person,
+		"filmography": entries,
+	})
+}
+
+// loadPerson loads the person in the request path, answering the request
+// when there is none
+func (m *Module) loadPerson(c *gin.Context) (*Person, bool) {
+	var person database.People
+	if err := m.db.Where("id = ?", c.Param("id")).First(&person).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Person not found"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load person: " + err.Error()})
+		}
+		return nil, false
+	}
+	result := toPerson(person)
+	return &result, true
+}
+
+func toPerson(person database.People) Person {
+	result := Person{People: person}
+	if person.ProfileAssetID != "" {
+		result.ProfileURL = fmt.Sprintf("/api/v1/assets/%s/data", person.ProfileAssetID)
+	}
+	return result
+}
@@ -0,0 +1,222 @@
+package peoplemodule
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mantonx/viewra/internal/database"
+	"github.com/mantonx/viewra/internal/utils"
+	"gorm.io/gorm"
+)
+
+// RoleActor is the role of cast members. Crew roles are their lower-cased
+// job, such as "director" or "screenplay".
+const RoleActor = "actor"
+
+// Details describes a person as a metadata provider knows them. Empty
+// fields keep what is already stored.
+type Details struct {
+	Name               string
+	TmdbID             string
+	ImdbID             string
+	Biography          string
+	Birthdate          *time.Time
+	Deathdate          *time.Time
+	PlaceOfBirth       string
+	KnownForDepartment string
+	Image              string // Profile image URL
+}
+
+// Credit is a person's part in a movie or episode
+type Credit struct {
+	Person     Details
+	Role       string
+	Character  string
+	Department string
+	Order      int
+}
+
+// Upserted is the person an upsert resolved to
+type Upserted struct {
+	Person  *database.People
+	Created bool
+
+	// NeedsProfile is set when the person's profile image is new, changed or
+	// was never downloaded
+	NeedsProfile bool
+}
+
+// Upsert finds the person with the details' TMDb or IMDb ID, or else one of
+// the same name recorded without a TMDb ID, creating them when neither
+// exists, and fills in the details. Callers serialize upserts and run them
+// in a transaction, so concurrent enrichment doesn't create duplicates.
+func Upsert(tx *gorm.DB, details *Details) (*Upserted, error) {
+	name := strings.TrimSpace(details.Name)
+	if name == "" {
+		return nil, fmt.Errorf("person name is required")
+	}
+
+	person, err := find(tx, name, details)
+	if err != nil {
+		return nil, err
+	}
+	if person == nil {
+		person = &database.People{
+			ID:                 utils.GenerateUUID(),
+			Name:               name,
+			TmdbID:             details.TmdbID,
+			ImdbID:             details.ImdbID,
+			Biography:          details.Biography,
+			Birthdate:          details.Birthdate,
+			Deathdate:          details.Deathdate,
+			PlaceOfBirth:       details.PlaceOfBirth,
+			KnownForDepartment: details.KnownForDepartment,
+			Image:              details.Image,
+		}
+		if err := tx.Create(person).Error; err != nil {
+			return nil, fmt.Errorf("failed to create person: %w", err)
+		}
+		return &Upserted{Person: person, Created: true, NeedsProfile: person.Image != ""}, nil
+	}
+
+	result := &Upserted{
+		Person:       person,
+		NeedsProfile: details.Image != "" && (details.Image != person.Image || person.ProfileAssetID == ""),
+	}
+	updates := map[string]interface{}{}
+	texts := []struct {
+		column  string
+		current string
+		value   string
+	}{
+		{"name", person.Name, name},
+		{"tmdb_id", person.TmdbID, details.TmdbID},
+		{"imdb_id", person.ImdbID, details.ImdbID},
+		{"biography", person.Biography, details.Biography},
+		{"place_of_birth", person.PlaceOfBirth, details.PlaceOfBirth},
+		{"known_for_department", person.KnownForDepartment, details.KnownForDepartment},
+		{"image", person.Image, details.Image},
+	}
+	for _, text := range texts {
+		if text.value != "" && text.value != text.current {
+			updates[text.column] = text.value
+		}
+	}
+	if details.Birthdate != nil {
+		updates["birthdate"] = details.Birthdate
+	}
+	if details.Deathdate != nil {
+		updates["deathdate"] = details.Deathdate
+	}
+	if len(updates) > 0 {
+		if err := tx.Model(person).Updates(updates).Error; err != nil {
+			return nil, fmt.Errorf("failed to update person: %w", err)
+		}
+	}
+	return result, nil
+}
+
+// find returns the person matching the details' external IDs, then their
+// name, or nil when there is none
+func find(tx *gorm.DB, name string, details *Details) (*database.People, error) {
+	var people []database.People
+	for _, id := range []struct{ column, value string }{{"tmdb_id", details.TmdbID}, {"imdb_id", details.ImdbID}} {
+		if id.value == "" {
+			continue
+		}
+		if err := tx.Where(id.column+" = ?", id.value).Order("created_at").Limit(1).Find(&people).Error; err != nil {
+			return nil, fmt.Errorf("failed to find person: %w", err)
+		}
+		if len(people) > 0 {
+			return &people[0], nil
+		}
+	}
+
+	// People written before TMDb IDs were recorded are adopted by name; a
+	// namesake with another TMDb ID is someone else
+	query := tx.Where("LOWER(name) = LOWER(?)", name)
+	if details.TmdbID != "" {
+		query = query.Where("tmdb_id = '' OR tmdb_id IS NULL")
+	}
+	if err := query.Order("created_at").Limit(1).Find(&people).Error; err != nil {
+		return nil, fmt.Errorf("failed to find person: %w", err)
+	}
+	if len(people) > 0 {
+		return &people[0], nil
+	}
+	return nil, nil
+}
+
+// SetCredits replaces the cast and crew of a movie or episode, upserting
+// each credited person. It returns the upsert of each credit's person, in
+// order.
+func SetCredits(tx *gorm.DB, mediaID string, mediaType database.MediaType, credits []Credit) ([]*Upserted, error) {
+	if err := tx.Where("media_id = ? AND media_type = ?", mediaID, mediaType).Delete(&database.Roles{}).Error; err != nil {
+		return nil, fmt.Errorf("failed to clear credits: %w", err)
+	}
+
+	upserts := make([]*Upserted, 0, len(credits))
+	byPerson := make(map[string]*Upserted)
+	seen := make(map[string]bool)
+	roles := make([]database.Roles, 0, len(credits))
+	for i := range credits {
+		credit := &credits[i]
+		upserted, err := Upsert(tx, &credit.Person)
+		if err != nil {
+			return nil, fmt.Errorf("credit %d: %w", i, err)
+		}
+		// A person credited twice resolves once, and downloads their profile once
+		if earlier, ok := byPerson[upserted.Person.ID]; ok {
+			upserted = &Upserted{Person: earlier.Person}
+		} else {
+			byPerson[upserted.Person.ID] = upserted
+		}
+		upserts = append(upserts, upserted)
+
+		role := strings.ToLower(strings.TrimSpace(credit.Role))
+		if role == "" {
+			role = RoleActor
+		}
+		character := strings.TrimSpace(credit.Character)
+		key := upserted.Person.ID + "\x00" + role + "\x00" + character
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		roles = append(roles, database.Roles{
+			PersonID:   upserted.Person.ID,
+			MediaID:    mediaID,
+			MediaType:  mediaType,
+			Role:       role,
+			Character:  character,
+			Department: credit.Department,
+			Order:      credit.Order,
+		})
+	}
+	if len(roles) > 0 {
+		if err := tx.CreateInBatches(roles, 200).Error; err != nil {
+			return nil, fmt.Errorf("failed to save credits: %w", err)
+		}
+	}
+
+	// Touching the item lets the search index pick up its new cast
+	table := "movies"
+	if mediaType == database.MediaTypeEpisode {
+		table = "episodes"
+	}
+	if err := tx.Table(table).Where("id = ?", mediaID).Update("updated_at", time.Now()).Error; err != nil {
+		return nil, fmt.Errorf("failed to update %s: %w", mediaType, err)
+	}
+	return upserts, nil
+}
+
+// SetProfileAsset records the headshot downloaded from a person's profile
+// image
+func SetProfileAsset(db *gorm.DB, personID, assetID string) error {
+	if err := db.Model(&database.People{}).Where("id = ?", personID).
+		Update("profile_asset_id", assetID).Error; err != nil {
+		return fmt.Errorf("failed to record profile image: %w", err)
+	}
+	return nil
+}
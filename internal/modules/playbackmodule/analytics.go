@@ -0,0 +1,422 @@
+package playbackmodule
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/mantonx/viewra/internal/database"
+	plugins "github.com/mantonx/viewra/sdk"
+	"gorm.io/gorm"
+)
+
+// Playback methods recorded on PlaybackSession.Method
+const (
+	PlaybackMethodDirectPlay = "direct_play"
+	PlaybackMethodTranscode  = "transcode"
+	PlaybackMethodImported   = "imported" // History imported from another service
+)
+
+const (
+	defaultAnalyticsDays = 30
+	maxAnalyticsDays     = 365
+	analyticsTopItems    = 10
+	// completedThreshold is the fraction of a file that counts as watched to the end
+	completedThreshold = 0.9
+)
+
+// PlaybackSessionStart is reported by the player when playback begins
+type PlaybackSessionStart struct {
+	MediaFileID        string  `json:"media_file_id" binding:"required"`
+	UserID             uint32  `json:"user_id,omitempty"`
+	Method             string  `json:"method,omitempty"` // direct_play or transcode, inferred from TranscodeSessionID when empty
+	TranscodeSessionID string  `json:"transcode_session_id,omitempty"`
+	TranscodeReason    string  `json:"transcode_reason,omitempty"`
+	ClientName         string  `json:"client_name,omitempty"`
+	DeviceType         string  `json:"device_type,omitempty"`
+	DurationSeconds    float64 `json:"duration_seconds,omitempty"`
+}
+
+// PlaybackSessionUpdate is reported periodically and when playback ends
+type PlaybackSessionUpdate struct {
+	WatchedSeconds  float64 `json:"watched_seconds"`
+	PositionSeconds float64 `json:"position_seconds,omitempty"` // Current playback position
+	QualitySwitches int     `json:"quality_switches"`
+	Completed       bool    `json:"completed,omitempty"`
+	Paused          bool    `json:"paused,omitempty"` // Playback is paused; a later update without it resumes
+}
+
+// PlaybackAnalytics aggregates playback sessions over a time window
+type PlaybackAnalytics struct {
+	Since            time.Time        `json:"since"`
+	TotalSessions    int64            `json:"total_sessions"`
+	WatchedHours     float64          `json:"watched_hours"`
+	DirectPlay       int64            `json:"direct_play"`
+	Transcode        int64            `json:"transcode"`
+	TranscodeRatio   float64          `json:"transcode_ratio"`
+	QualitySwitches  int64            `json:"quality_switches"`
+	PeakConcurrency  int              `json:"peak_concurrency"`
+	PeakAt           *time.Time       `json:"peak_at,omitempty"`
+	MostWatched      []WatchedItem    `json:"most_watched"`
+	TranscodeReasons []AnalyticsCount `json:"transcode_reasons"`
+	Devices          []AnalyticsCount `json:"devices"`
+}
+
+// WatchedItem is a media file ranked by play count
+type WatchedItem struct {
+	MediaFileID    string  `json:"media_file_id"`
+	MediaID        string  `json:"media_id,omitempty"`
+	MediaType      string  `json:"media_type,omitempty"`
+	Plays          int64   `json:"plays"`
+	WatchedSeconds float64 `json:"watched_seconds"`
+}
+
+// AnalyticsCount is a labelled session count
+type AnalyticsCount struct {
+	Label string `json:"label"`
+	Count int64  `json:"count"`
+}
+
+// StartPlaybackSession records the start of a viewing
+func (m *Manager) StartPlaybackSession(start *PlaybackSessionStart, userAgent, clientIP string) (*database.PlaybackSession, error) {
+	if m.db == nil {
+		return nil, fmt.Errorf("database not available")
+	}
+
+	var mediaFile database.MediaFile
+	if err := m.db.Select("id, media_id, media_type, duration").Where("id = ?", start.MediaFileID).First(&mediaFile).Error; err != nil {
+		return nil, fmt.Errorf("media file not found: %w", err)
+	}
+
+	method := start.Method
+	if method == "" {
+		method = PlaybackMethodDirectPlay
+		if start.TranscodeSessionID != "" {
+			method = PlaybackMethodTranscode
+		}
+	}
+	if method != PlaybackMethodDirectPlay && method != PlaybackMethodTranscode {
+		return nil, fmt.Errorf("unknown playback method %q", method)
+	}
+
+	duration := start.DurationSeconds
+	if duration <= 0 {
+		duration = float64(mediaFile.Duration)
+	}
+
+	now := time.Now()
+	session := &database.PlaybackSession{
+		ID:                 uuid.New().String(),
+		UserID:             start.UserID,
+		MediaFileID:        mediaFile.ID,
+		MediaID:            mediaFile.MediaID,
+		MediaType:          string(mediaFile.MediaType),
+		Method:             method,
+		TranscodeSessionID: start.TranscodeSessionID,
+		TranscodeReason:    start.TranscodeReason,
+		ClientName:         start.ClientName,
+		DeviceType:         start.DeviceType,
+		UserAgent:          userAgent,
+		ClientIP:           clientIP,
+		DurationSeconds:    duration,
+		StartedAt:          now,
+		LastSeenAt:         now,
+	}
+
+	if err := m.db.Create(session).Error; err != nil {
+		return nil, fmt.Errorf("failed to record playback session: %w", err)
+	}
+
+	m.notifyPlaybackEvent(plugins.PlaybackEventStarted, session)
+
+	return session, nil
+}
+
+// UpdatePlaybackSession records progress for an open session and, when end is
+// set, closes it
+func (m *Manager) UpdatePlaybackSession(id string, update *PlaybackSessionUpdate, end bool) (*database.PlaybackSession, error) {
+	if m.db == nil {
+		return nil, fmt.Errorf("database not available")
+	}
+
+	var session database.PlaybackSession
+	if err := m.db.Where("id = ?", id).First(&session).Error; err != nil {
+		return nil, fmt.Errorf("playback session not found: %s", id)
+	}
+	if session.EndedAt != nil {
+		return nil, fmt.Errorf("playback session already ended: %s", id)
+	}
+
+	now := time.Now()
+	// Players report running totals; never let a late update move them backwards
+	if update.WatchedSeconds > session.WatchedSeconds {
+		session.WatchedSeconds = update.WatchedSeconds
+	}
+	if update.QualitySwitches > session.QualitySwitches {
+		session.QualitySwitches = update.QualitySwitches
+	}
+	// The position moves both ways as the user seeks
+	if update.PositionSeconds > 0 {
+		session.PositionSeconds = update.PositionSeconds
+	}
+	session.LastSeenAt = now
+
+	// Mark the item watched as soon as the user's rules say so, so watch
+	// state is right even if the player never reports the end
+	wasCompleted := session.Completed
+	session.Completed = session.Completed || update.Completed ||
+		m.sessionWatched(&session, m.watchRulesFor(session.UserID))
+	wasPaused := session.Paused
+	session.Paused = update.Paused && !end
+	if end {
+		session.EndedAt = &now
+	}
+
+	if err := m.db.Save(&session).Error; err != nil {
+		return nil, fmt.Errorf("failed to update playback session: %w", err)
+	}
+
+	if session.Completed && !wasCompleted {
+		m.advancePlayQueues(&session)
+	}
+
+	switch {
+	case end:
+		m.notifyPlaybackEvent(plugins.PlaybackEventStopped, &session)
+	case session.Paused && !wasPaused:
+		m.notifyPlaybackEvent(plugins.PlaybackEventPaused, &session)
+	case wasPaused && !session.Paused:
+		m.notifyPlaybackEvent(plugins.PlaybackEventStarted, &session)
+	}
+
+	return &session, nil
+}
+
+// GetPlaybackAnalytics aggregates sessions started within the last days
+func (m *Manager) GetPlaybackAnalytics(days int) (*PlaybackAnalytics, error) {
+	if m.db == nil {
+		return nil, fmt.Errorf("database not available")
+	}
+
+	since := time.Now().AddDate(0, 0, -days)
+	analytics := &PlaybackAnalytics{
+		Since:            since,
+		MostWatched:      []WatchedItem{},
+		TranscodeReasons: []AnalyticsCount{},
+		Devices:          []AnalyticsCount{},
+	}
+
+	sessions := m.db.Model(&database.PlaybackSession{}).Where("started_at >= ?", since)
+
+	var totals struct {
+		Sessions        int64
+		WatchedSeconds  float64
+		QualitySwitches int64
+	}
+	if err := sessions.Session(&gorm.Session{}).
+		Select("count(*) as sessions, coalesce(sum(watched_seconds), 0) as watched_seconds, coalesce(sum(quality_switches), 0) as quality_switches").
+		Scan(&totals).Error; err != nil {
+		return nil, fmt.Errorf("failed to aggregate sessions: %w", err)
+	}
+	analytics.TotalSessions = totals.Sessions
+	analytics.WatchedHours = totals.WatchedSeconds / 3600
+	analytics.QualitySwitches = totals.QualitySwitches
+
+	var methods []AnalyticsCount
+	if err := sessions.Session(&gorm.Session{}).
+		Select("method as label, count(*) as count").Group("method").
+		Scan(&methods).Error; err != nil {
+		return nil, fmt.Errorf("failed to count playback methods: %w", err)
+	}
+	for _, method := range methods {
+		switch method.Label {
+		case PlaybackMethodDirectPlay:
+			analytics.DirectPlay = method.Count
+		case PlaybackMethodTranscode:
+			analytics.Transcode = method.Count
+		}
+	}
+	if total := analytics.DirectPlay + analytics.Transcode; total > 0 {
+		analytics.TranscodeRatio = float64(analytics.Transcode) / float64(total)
+	}
+
+	if err := sessions.Session(&gorm.Session{}).
+		Select("media_file_id, media_id, media_type, count(*) as plays, sum(watched_seconds) as watched_seconds").
+		Group("media_file_id, media_id, media_type").
+		Order("plays desc, watched_seconds desc").
+		Limit(analyticsTopItems).
+		Scan(&analytics.MostWatched).Error; err != nil {
+		return nil, fmt.Errorf("failed to rank media: %w", err)
+	}
+
+	if err := sessions.Session(&gorm.Session{}).
+		Select("transcode_reason as label, count(*) as count").
+		Where("method = ? AND transcode_reason <> ''", PlaybackMethodTranscode).
+		Group("transcode_reason").Order("count desc").Limit(analyticsTopItems).
+		Scan(&analytics.TranscodeReasons).Error; err != nil {
+		return nil, fmt.Errorf("failed to count transcode reasons: %w", err)
+	}
+
+	if err := sessions.Session(&gorm.Session{}).
+		Select("coalesce(nullif(device_type, ''), 'unknown') as label, count(*) as count").
+		Group("label").Order("count desc").
+		Scan(&analytics.Devices).Error; err != nil {
+		return nil, fmt.Errorf("failed to count devices: %w", err)
+	}
+
+	peak, err := m.peakConcurrency(since)
+	if err != nil {
+		return nil, err
+	}
+	if peak.Sessions > 0 {
+		analytics.PeakConcurrency = peak.Sessions
+		analytics.PeakAt = &peak.At
+	}
+
+	return analytics, nil
+}
+
+// concurrencyPeak is the most sessions playing at once and when that began
+type concurrencyPeak struct {
+	At       time.Time
+	Sessions int
+}
+
+// peakConcurrency finds the highest number of sessions started since since
+// that were playing at once. The count can only rise when a session starts,
+// so it is counted at each start; sessions that were never ended count until
+// they were last heard from, and a session ending as another starts doesn't
+// overlap it.
+func (m *Manager) peakConcurrency(since time.Time) (concurrencyPeak, error) {
+	var peak concurrencyPeak
+	err := m.db.Raw(`SELECT s.started_at AS at, (
+			SELECT count(*) FROM playback_sessions t
+			WHERE t.started_at >= ? AND t.started_at <= s.started_at
+				AND coalesce(t.ended_at, t.last_seen_at) > s.started_at
+		) AS sessions
+		FROM playback_sessions s
+		WHERE s.started_at >= ?
+		ORDER BY sessions DESC, s.started_at ASC
+		LIMIT 1`, since, since).Scan(&peak).Error
+	if err != nil {
+		return peak, fmt.Errorf("failed to compute peak concurrency: %w", err)
+	}
+	return peak, nil
+}
+
+// HandleStartPlaybackSession records the start of a viewing
+func (h *APIHandler) HandleStartPlaybackSession(c *gin.Context) {
+	var request PlaybackSessionStart
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	session, err := h.manager.StartPlaybackSession(&request, c.Request.UserAgent(), c.ClientIP())
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, session)
+}
+
+// HandleUpdatePlaybackSession records progress for a viewing
+func (h *APIHandler) HandleUpdatePlaybackSession(c *gin.Context) {
+	h.updatePlaybackSession(c, false)
+}
+
+// HandleEndPlaybackSession closes a viewing
+func (h *APIHandler) HandleEndPlaybackSession(c *gin.Context) {
+	h.updatePlaybackSession(c, true)
+}
+
+func (h *APIHandler) updatePlaybackSession(c *gin.Context, end bool) {
+	var update PlaybackSessionUpdate
+	if err := c.ShouldBindJSON(&update); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	session, err := h.manager.UpdatePlaybackSession(c.Param("id"), &update, end)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, session)
+}
+
+// HandleListPlaybackHistory lists recorded sessions, newest first
+func (h *APIHandler) HandleListPlaybackHistory(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if limit <= 0 || limit > 500 {
+		limit = 50
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	query := h.manager.db.Model(&database.PlaybackSession{})
+	if userID := c.Query("user_id"); userID != "" {
+		query = query.Where("user_id = ?", userID)
+	}
+	if mediaFileID := c.Query("media_file_id"); mediaFileID != "" {
+		query = query.Where("media_file_id = ?", mediaFileID)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	var sessions []database.PlaybackSession
+	if err := query.Order("started_at desc").Limit(limit).Offset(offset).Find(&sessions).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"sessions": sessions,
+		"total":    total,
+		"limit":    limit,
+		"offset":   offset,
+	})
+}
+
+// HandleGetPlaybackAnalytics returns aggregate playback statistics
+func (h *APIHandler) HandleGetPlaybackAnalytics(c *gin.Context) {
+	days, err := strconv.Atoi(c.DefaultQuery("days", strconv.Itoa(defaultAnalyticsDays)))
+	if err != nil || days <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid days"})
+		return
+	}
+	if days > maxAnalyticsDays {
+		days = maxAnalyticsDays
+	}
+
+	analytics, err := h.manager.GetPlaybackAnalytics(days)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, analytics)
+}
+
+// RegisterAnalyticsRoutes registers playback history and analytics endpoints
+func RegisterAnalyticsRoutes(api *gin.RouterGroup, handler *APIHandler) {
+	analytics := api.Group("/analytics")
+	{
+		analytics.GET("", handler.HandleGetPlaybackAnalytics)
+		analytics.GET("/history", handler.HandleListPlaybackHistory)
+		analytics.POST("/sessions", handler.HandleStartPlaybackSession)
+		analytics.POST("/sessions/:id/progress", handler.HandleUpdatePlaybackSession)
+		analytics.POST("/sessions/:id/end", handler.HandleEndPlaybackSession)
+	}
+}
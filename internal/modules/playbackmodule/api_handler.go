@@ -13,10 +13,12 @@ import (
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/mantonx/viewra/internal/auth"
 	"github.com/mantonx/viewra/internal/config"
 	"github.com/mantonx/viewra/internal/database"
 	"github.com/mantonx/viewra/internal/logger"
 	"github.com/mantonx/viewra/internal/modules/playbackmodule/core"
+	"github.com/mantonx/viewra/internal/streamurl"
 )
 
 // APIHandler handles HTTP requests for the playback module
@@ -98,6 +100,7 @@ func (h *APIHandler) HandleStartTranscode(c *gin.Context) {
 			}
 		}
 		
+		deviceProfile.UserID = auth.RequestUserID(c, deviceProfile.UserID)
 		session, err := h.manager.StartTranscodeFromMediaFile(mediaRequest.MediaFileID, mediaRequest.Container, mediaRequest.SeekPosition, mediaRequest.EnableABR, deviceProfile)
 		if err != nil {
 			logger.Error("failed to start transcode from media file", "error", err)
@@ -108,12 +111,16 @@ func (h *APIHandler) HandleStartTranscode(c *gin.Context) {
 		logger.Info("transcode session created successfully", "session_id", session.ID)
 		
 		// Return the session information
-		c.JSON(http.StatusOK, gin.H{
+		response := gin.H{
 			"id":           session.ID,
 			"status":       session.Status,
-			"manifest_url": fmt.Sprintf("/api/playback/stream/%s/manifest.mpd", session.ID),
+			"manifest_url": sessionManifestURL(session, deviceProfile.UserID),
 			"provider":     session.Provider,
-		})
+		}
+		if hlsURL := sessionHLSURL(session, deviceProfile.UserID); hlsURL != "" {
+			response["hls_manifest_url"] = hlsURL
+		}
+		c.JSON(http.StatusOK, response)
 		return
 	}
 	
@@ -153,6 +160,8 @@ func (h *APIHandler) HandleStartTranscode(c *gin.Context) {
 		}
 	}
 
+	deviceProfile.UserID = auth.RequestUserID(c, deviceProfile.UserID)
+
 	// Use playback planner to make intelligent decisions
 	decision, err := h.manager.DecidePlayback(directRequest.InputPath, deviceProfile)
 	if err != nil {
@@ -206,12 +215,48 @@ func (h *APIHandler) HandleStartTranscode(c *gin.Context) {
 	logger.Info("transcode session created successfully", "session_id", session.ID)
 
 	// Return the session information
-	c.JSON(http.StatusOK, gin.H{
+	response := gin.H{
 		"id":           session.ID,
 		"status":       session.Status,
-		"manifest_url": fmt.Sprintf("/api/playback/stream/%s/manifest.mpd", session.ID),
+		"manifest_url": streamurl.Manifest(session.ID, deviceProfile.UserID, request.Container),
 		"provider":     session.Provider,
-	})
+	}
+	if hlsURL := sessionHLSURL(session, deviceProfile.UserID); hlsURL != "" {
+		response["hls_manifest_url"] = hlsURL
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// HandleStartAudioStream starts music playback, transcoding to a lower bitrate
+// audio-only stream when the client can't take the source directly
+func (h *APIHandler) HandleStartAudioStream(c *gin.Context) {
+	var request AudioStreamRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	request.UserID = auth.RequestUserID(c, request.UserID)
+
+	decision, err := h.manager.StartAudioStream(&request)
+	if err != nil {
+		logger.Error("failed to start audio stream", "media_file_id", request.MediaFileID, "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to start audio stream: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, decision)
+}
+
+// HandleGetTrackPlaybackInfo returns a track's gapless and loudness hints so
+// clients can prepare the next track of an album or crossfade
+func (h *APIHandler) HandleGetTrackPlaybackInfo(c *gin.Context) {
+	info, err := h.manager.GetTrackPlaybackInfo(c.Param("mediaFileId"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, info)
 }
 
 // HandleSeekAhead handles seek-ahead transcoding requests
@@ -272,7 +317,7 @@ func (h *APIHandler) HandleSeekAhead(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{
 		"id":           newSession.ID,
 		"status":       newSession.Status,
-		"manifest_url": fmt.Sprintf("/api/playback/stream/%s/manifest.mpd", newSession.ID),
+		"manifest_url": streamurl.Manifest(newSession.ID, auth.RequestUserID(c, 0), seekRequest.Container),
 		"provider":     newSession.Provider,
 	})
 }
@@ -290,6 +335,36 @@ func (h *APIHandler) HandleGetSession(c *gin.Context) {
 	c.JSON(http.StatusOK, session)
 }
 
+// HandleGetSessionStats returns real-time encoder statistics for a session
+func (h *APIHandler) HandleGetSessionStats(c *gin.Context) {
+	sessionID := c.Param("sessionId")
+
+	session, err := h.manager.GetSession(sessionID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	progress, err := session.GetProgress()
+	if err != nil {
+		logger.Error("failed to decode session progress", "error", err, "session_id", sessionID)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to decode session progress"})
+		return
+	}
+
+	var percent float64
+	if progress != nil {
+		percent = progress.PercentComplete
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"session_id": session.ID,
+		"status":     session.Status,
+		"progress":   percent,
+		"stats":      core.NewTranscodeStats(progress),
+	})
+}
+
 // HandleStopTranscode terminates a transcoding session
 func (h *APIHandler) HandleStopTranscode(c *gin.Context) {
 	sessionID := c.Param("sessionId")
@@ -328,7 +403,7 @@ func (h *APIHandler) HandleStopAllSessions(c *gin.Context) {
 	
 	for _, session := range sessions {
 		if session.Status == "running" || session.Status == "queued" {
-			if err := h.manager.StopSession(session.ID); err != nil {
+			if err := h.manager.stopSessionForAll(session.ID); err != nil {
 				errors = append(errors, fmt.Sprintf("session %s: %v", session.ID, err))
 				logger.Error("failed to stop session", "session_id", session.ID, "error", err)
 			} else {
@@ -587,9 +662,9 @@ func (h *APIHandler) HandleStreamTranscode(c *gin.Context) {
 	if session.Request != "" {
 		request, err := session.GetRequest()
 		if err == nil && request != nil && (request.Container == "dash" || request.Container == "hls") {
-			manifestURL := fmt.Sprintf("/api/playback/stream/%s/manifest.mpd", sessionID)
+			manifestURL := fmt.Sprintf("/api/playback/stream/%s/manifest.mpd", streamPathID(c, sessionID))
 			if request.Container == "hls" {
-				manifestURL = fmt.Sprintf("/api/playback/stream/%s/playlist.m3u8", sessionID)
+				manifestURL = fmt.Sprintf("/api/playback/stream/%s/master.m3u8", streamPathID(c, sessionID))
 			}
 			c.Redirect(http.StatusFound, manifestURL)
 			return
@@ -606,6 +681,13 @@ func (h *APIHandler) HandleDashManifest(c *gin.Context) {
 	h.serveManifestFile(c, sessionID, "manifest.mpd")
 }
 
+// HandleHlsMasterPlaylist serves the HLS master playlist, listing a media
+// playlist per variant
+func (h *APIHandler) HandleHlsMasterPlaylist(c *gin.Context) {
+	sessionID := c.Param("sessionId")
+	h.serveManifestFile(c, sessionID, "master.m3u8")
+}
+
 // HandleHlsPlaylist serves HLS playlist files
 func (h *APIHandler) HandleHlsPlaylist(c *gin.Context) {
 	sessionID := c.Param("sessionId")
@@ -619,10 +701,16 @@ func (h *APIHandler) HandleSegment(c *gin.Context) {
 	h.serveSegmentFile(c, sessionID, segmentName)
 }
 
-// HandleDashSegmentSpecific serves DASH segments with specific naming pattern
+// HandleDashSegmentSpecific serves DASH segments with specific naming pattern,
+// and the per-variant HLS media playlists
 func (h *APIHandler) HandleDashSegmentSpecific(c *gin.Context) {
 	sessionID := c.Param("sessionId")
 	segmentFile := c.Param("segmentFile")
+	if filepath.Ext(segmentFile) == ".m3u8" {
+		// Playlists grow as the transcode runs, so they get the manifest's no-cache headers
+		h.serveManifestFile(c, sessionID, segmentFile)
+		return
+	}
 	h.serveSegmentFile(c, sessionID, segmentFile)
 }
 
@@ -702,7 +790,7 @@ func (h *APIHandler) serveManifestFile(c *gin.Context, sessionID, filename strin
 
 	// Set appropriate content type
 	contentType := "application/dash+xml"
-	if filename == "playlist.m3u8" {
+	if filepath.Ext(filename) == ".m3u8" {
 		contentType = "application/vnd.apple.mpegurl"
 	}
 
@@ -770,7 +858,8 @@ func (h *APIHandler) serveManifestFile(c *gin.Context, sessionID, filename strin
 			host = "localhost:8080"
 		}
 		
-		baseURL := fmt.Sprintf("%s://%s/api/playback/stream/%s", proto, host, sessionID)
+		// Segments are fetched with the same signed token as the manifest
+		baseURL := fmt.Sprintf("%s://%s/api/playback/stream/%s", proto, host, streamPathID(c, sessionID))
 		modifiedManifest := h.injectBaseURL(manifestData, baseURL)
 		
 		// Send the modified manifest
@@ -826,6 +915,14 @@ func (h *APIHandler) serveSegmentFile(c *gin.Context, sessionID, segmentName str
 		contentType = "video/mp2t"
 	case ".m3u8":
 		contentType = "application/vnd.apple.mpegurl"
+	case ".ogg", ".opus":
+		contentType = "audio/ogg"
+	case ".m4a":
+		contentType = "audio/mp4"
+	case ".mp3":
+		contentType = "audio/mpeg"
+	case ".flac":
+		contentType = "audio/flac"
 	}
 
 	// Handle byte-range requests
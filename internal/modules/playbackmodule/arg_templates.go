@@ -0,0 +1,206 @@
+package playbackmodule
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mantonx/viewra/internal/database"
+	plugins "github.com/mantonx/viewra/sdk"
+	"github.com/mantonx/viewra/sdk/transcoding/ffmpeg"
+)
+
+const (
+	defaultTemplateTestSeconds = 5
+	maxTemplateTestSeconds     = 30
+	templateTestStderrLines    = 40
+)
+
+// ArgTemplateTestRequest describes a short trial encode with an argument template
+type ArgTemplateTestRequest struct {
+	Template    ffmpeg.ArgTemplate `json:"template"`
+	MediaFileID string             `json:"media_file_id,omitempty"` // defaults to the first media file
+	VideoCodec  string             `json:"video_codec,omitempty"`   // defaults to h264
+	Container   string             `json:"container,omitempty"`     // defaults to mp4
+	Duration    int                `json:"duration_seconds,omitempty"`
+}
+
+// ArgTemplateTestResult reports the outcome of a template trial encode
+type ArgTemplateTestResult struct {
+	Success   bool     `json:"success"`
+	Matched   bool     `json:"matched"` // false when the template's codec/container filters skip the request
+	Command   []string `json:"command"`
+	Error     string   `json:"error,omitempty"`
+	Stderr    string   `json:"stderr,omitempty"`
+	ElapsedMs int64    `json:"elapsed_ms"`
+}
+
+// TestArgTemplate builds the FFmpeg command for a template and runs it for a
+// few seconds of the input, so bad encoder options fail here rather than
+// during playback.
+func (m *Manager) TestArgTemplate(ctx context.Context, req *ArgTemplateTestRequest) (*ArgTemplateTestResult, error) {
+	if err := req.Template.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid template: %w", err)
+	}
+
+	ffmpegPath := findExecutable("ffmpeg")
+	if ffmpegPath == "" {
+		return nil, fmt.Errorf("ffmpeg not found in PATH")
+	}
+
+	inputPath, err := m.templateTestInput(req.MediaFileID)
+	if err != nil {
+		return nil, err
+	}
+
+	duration := req.Duration
+	if duration <= 0 {
+		duration = defaultTemplateTestSeconds
+	}
+	if duration > maxTemplateTestSeconds {
+		duration = maxTemplateTestSeconds
+	}
+
+	transcodeReq := plugins.TranscodeRequest{
+		InputPath:  inputPath,
+		VideoCodec: req.VideoCodec,
+		Container:  req.Container,
+		AudioCodec: "aac",
+		Quality:    50,
+	}
+	if transcodeReq.VideoCodec == "" {
+		transcodeReq.VideoCodec = "h264"
+	}
+	if transcodeReq.Container == "" {
+		transcodeReq.Container = "mp4"
+	}
+
+	if err := os.MkdirAll(m.config.TempDirectory, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create temp directory: %w", err)
+	}
+	testDir, err := os.MkdirTemp(m.config.TempDirectory, "template_test_")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create test directory: %w", err)
+	}
+	defer os.RemoveAll(testDir)
+
+	builder := ffmpeg.NewFFmpegArgsBuilder(m.logger.Named("template-test"))
+	if err := builder.SetTemplates([]ffmpeg.ArgTemplate{req.Template}); err != nil {
+		return nil, err
+	}
+
+	args := builder.BuildArgs(transcodeReq, filepath.Join(testDir, "output."+transcodeReq.Container))
+
+	// Limit the output duration; the output path is always the last argument
+	last := len(args) - 1
+	args = append(args[:last:last], "-t", fmt.Sprintf("%d", duration), args[last])
+
+	result := &ArgTemplateTestResult{
+		Matched: req.Template.Matches(transcodeReq),
+		Command: append([]string{ffmpegPath}, args...),
+	}
+
+	runCtx, cancel := context.WithTimeout(ctx, time.Duration(duration*4+10)*time.Second)
+	defer cancel()
+
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(runCtx, ffmpegPath, args...)
+	cmd.Stderr = &stderr
+
+	start := time.Now()
+	runErr := cmd.Run()
+	result.ElapsedMs = time.Since(start).Milliseconds()
+	result.Stderr = lastLines(stderr.String(), templateTestStderrLines)
+
+	if runErr != nil {
+		result.Error = runErr.Error()
+		if runCtx.Err() == context.DeadlineExceeded {
+			result.Error = "test encode timed out"
+		}
+	} else {
+		result.Success = true
+	}
+
+	m.logger.Info("ran ffmpeg template test",
+		"template", req.Template.Name,
+		"input", inputPath,
+		"success", result.Success,
+		"elapsed_ms", result.ElapsedMs)
+
+	return result, nil
+}
+
+// templateTestInput resolves the input file for a template test
+func (m *Manager) templateTestInput(mediaFileID string) (string, error) {
+	if m.db == nil {
+		return "", fmt.Errorf("database not available")
+	}
+
+	var mediaFile database.MediaFile
+	query := m.db.Select("path")
+	if mediaFileID != "" {
+		query = query.Where("id = ?", mediaFileID)
+	}
+	if err := query.First(&mediaFile).Error; err != nil {
+		return "", fmt.Errorf("media file not found: %w", err)
+	}
+	return mediaFile.Path, nil
+}
+
+// lastLines returns at most n trailing lines of s
+func lastLines(s string, n int) string {
+	lines := strings.Split(strings.TrimRight(s, "\n"), "\n")
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	return strings.Join(lines, "\n")
+}
+
+// HandleValidateArgTemplate checks an argument template without running it
+func (h *APIHandler) HandleValidateArgTemplate(c *gin.Context) {
+	var template ffmpeg.ArgTemplate
+	if err := c.ShouldBindJSON(&template); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := template.Validate(); err != nil {
+		c.JSON(http.StatusOK, gin.H{"valid": false, "error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"valid": true})
+}
+
+// HandleTestArgTemplate runs a short trial encode with an argument template
+func (h *APIHandler) HandleTestArgTemplate(c *gin.Context) {
+	var request ArgTemplateTestRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	result, err := h.manager.TestArgTemplate(c.Request.Context(), &request)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// RegisterArgTemplateRoutes registers FFmpeg argument template endpoints
+func RegisterArgTemplateRoutes(api *gin.RouterGroup, handler *APIHandler) {
+	templates := api.Group("/templates")
+	{
+		templates.POST("/validate", handler.HandleValidateArgTemplate)
+		templates.POST("/test", handler.HandleTestArgTemplate)
+	}
+}
@@ -0,0 +1,180 @@
+package playbackmodule
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mantonx/viewra/internal/database"
+	"github.com/mantonx/viewra/internal/streamurl"
+	plugins "github.com/mantonx/viewra/sdk"
+)
+
+// audioBitrateLadder lists the audio-only bitrates in kbps, highest first
+var audioBitrateLadder = []int{320, 256, 192, 160, 128, 96, 64, 48}
+
+// maxUsefulAudioBitrate caps the bitrate per codec; going higher only wastes bandwidth
+var maxUsefulAudioBitrate = map[string]int{
+	"opus": 192,
+	"aac":  256,
+}
+
+// AudioStreamRequest describes a music streaming request from a client
+type AudioStreamRequest struct {
+	MediaFileID     string   `json:"media_file_id" binding:"required"`
+	SupportedCodecs []string `json:"supported_codecs"`        // e.g. ["flac", "opus", "aac"]
+	MaxBitrate      int      `json:"max_bitrate,omitempty"`   // kbps, 0 = unconstrained
+	Container       string   `json:"container,omitempty"`     // "hls", "dash", "ogg", "m4a"; defaults to "hls"
+	SeekPosition    float64  `json:"seek_position,omitempty"` // seconds
+	UserID          uint32   `json:"user_id,omitempty"`       // The user stream URLs are issued to
+}
+
+// AudioStreamDecision is the result of an audio streaming request
+type AudioStreamDecision struct {
+	DirectPlay bool   `json:"direct_play"`
+	StreamURL  string `json:"stream_url"`
+	SessionID  string `json:"session_id,omitempty"`
+	Codec      string `json:"codec"`
+	Bitrate    int    `json:"bitrate_kbps,omitempty"`
+	Container  string `json:"container"`
+	Reason     string `json:"reason"`
+
+	// Hints for gapless album playback and crossfading
+	Gapless  *GaplessInfo   `json:"gapless,omitempty"`
+	Loudness *TrackLoudness `json:"loudness,omitempty"`
+}
+
+// StartAudioStream serves a track directly when the client can play it within its
+// bandwidth budget, and otherwise starts an audio-only transcode sized to fit.
+func (m *Manager) StartAudioStream(req *AudioStreamRequest) (*AudioStreamDecision, error) {
+	if !m.initialized {
+		return nil, fmt.Errorf("playback manager not initialized")
+	}
+
+	var mediaFile database.MediaFile
+	if err := m.db.Where("id = ?", req.MediaFileID).First(&mediaFile).Error; err != nil {
+		return nil, fmt.Errorf("media file not found: %w", err)
+	}
+
+	sourceCodec := strings.ToLower(mediaFile.AudioCodec)
+	if canDirectPlayAudio(&mediaFile, req) {
+		return &AudioStreamDecision{
+			DirectPlay: true,
+			StreamURL:  fmt.Sprintf("/api/media/files/%s/stream", mediaFile.ID),
+			Codec:      sourceCodec,
+			Bitrate:    mediaFile.BitrateKbps,
+			Container:  mediaFile.Container,
+			Reason:     "client supports source codec within bandwidth limit",
+			Gapless:    sourceGaplessInfo(&mediaFile),
+			Loudness:   m.trackLoudness(&mediaFile),
+		}, nil
+	}
+
+	codec := selectAudioCodec(req.SupportedCodecs)
+	bitrate := selectAudioBitrate(codec, req.MaxBitrate)
+
+	container := strings.ToLower(req.Container)
+	if container == "" {
+		// fMP4 HLS segments keep track transitions gapless
+		container = "hls"
+	}
+
+	request := &plugins.TranscodeRequest{
+		InputPath:    mediaFile.Path,
+		Container:    container,
+		AudioCodec:   codec,
+		AudioBitrate: bitrate,
+		AudioOnly:    true,
+		Gapless:      true,
+		Seek:         time.Duration(req.SeekPosition * float64(time.Second)),
+	}
+
+	m.logger.Info("starting audio-only transcode",
+		"media_file_id", mediaFile.ID,
+		"source_codec", sourceCodec,
+		"source_bitrate", mediaFile.BitrateKbps,
+		"target_codec", codec,
+		"target_bitrate", bitrate,
+		"container", container)
+
+	session, err := m.StartTranscode(request)
+	if err != nil {
+		return nil, err
+	}
+
+	reason := fmt.Sprintf("transcoding %s to %s at %dkbps", sourceCodec, codec, bitrate)
+	if req.MaxBitrate > 0 && mediaFile.BitrateKbps > req.MaxBitrate {
+		reason = fmt.Sprintf("source bitrate %dkbps exceeds client limit %dkbps, %s",
+			mediaFile.BitrateKbps, req.MaxBitrate, reason)
+	}
+
+	// StartTranscode fills in request.Loudness when the server normalizes levels
+	loudness := m.trackLoudness(&mediaFile)
+	if loudness != nil {
+		loudness.Applied = request.Loudness != nil
+	}
+
+	return &AudioStreamDecision{
+		SessionID: session.ID,
+		StreamURL: audioStreamURL(session.ID, req.UserID, container),
+		Codec:     codec,
+		Bitrate:   bitrate,
+		Container: container,
+		Reason:    reason,
+		Gapless:   transcodeGaplessInfo(codec, mediaFile.SampleRate),
+		Loudness:  loudness,
+	}, nil
+}
+
+// canDirectPlayAudio reports whether the source file can be sent as-is
+func canDirectPlayAudio(mediaFile *database.MediaFile, req *AudioStreamRequest) bool {
+	if mediaFile.AudioCodec == "" {
+		return false
+	}
+	if req.MaxBitrate > 0 && mediaFile.BitrateKbps > req.MaxBitrate {
+		return false
+	}
+
+	for _, codec := range req.SupportedCodecs {
+		if strings.EqualFold(codec, mediaFile.AudioCodec) {
+			return true
+		}
+	}
+	return false
+}
+
+// selectAudioCodec prefers Opus, which is transparent at lower bitrates than AAC
+func selectAudioCodec(supportedCodecs []string) string {
+	for _, codec := range supportedCodecs {
+		if strings.EqualFold(codec, "opus") {
+			return "opus"
+		}
+	}
+	return "aac"
+}
+
+// selectAudioBitrate picks the highest ladder bitrate that fits the client limit
+func selectAudioBitrate(codec string, maxBitrate int) int {
+	ceiling := maxUsefulAudioBitrate[codec]
+	if maxBitrate > 0 && maxBitrate < ceiling {
+		ceiling = maxBitrate
+	}
+
+	for _, bitrate := range audioBitrateLadder {
+		if bitrate <= ceiling {
+			return bitrate
+		}
+	}
+	return audioBitrateLadder[len(audioBitrateLadder)-1]
+}
+
+// audioStreamURL returns the client URL for an audio transcode session,
+// issued to userID
+func audioStreamURL(sessionID string, userID uint32, container string) string {
+	switch container {
+	case "hls", "dash":
+		return streamurl.Manifest(sessionID, userID, container)
+	default:
+		return streamurl.File(sessionID, userID, "output."+container)
+	}
+}
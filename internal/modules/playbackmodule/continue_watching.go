@@ -0,0 +1,292 @@
+package playbackmodule
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mantonx/viewra/internal/database"
+)
+
+// Reasons an item is on a continue-watching list
+const (
+	ContinueReasonResume = "resume" // Stopped part way through
+	ContinueReasonNext   = "next"   // The episode after one that was finished
+)
+
+const (
+	defaultContinueWatchingLimit = 20
+	maxContinueWatchingLimit     = 100
+	// continueWatchingWindow is how long an untouched item stays on the list
+	continueWatchingWindow = 90 * 24 * time.Hour
+)
+
+// ContinueWatchingItem is a movie or episode to pick up again
+type ContinueWatchingItem struct {
+	Reason          string    `json:"reason"` // resume or next
+	MediaID         string    `json:"media_id"`
+	MediaType       string    `json:"media_type"`
+	MediaFileID     string    `json:"media_file_id"`
+	Title           string    `json:"title,omitempty"`
+	Year            int       `json:"year,omitempty"`
+	ShowTitle       string    `json:"show_title,omitempty"`
+	Season          int       `json:"season,omitempty"`
+	Episode         int       `json:"episode,omitempty"`
+	PositionSeconds float64   `json:"position_seconds"` // Where to resume, 0 for next episodes
+	DurationSeconds float64   `json:"duration_seconds,omitempty"`
+	Progress        float64   `json:"progress"` // Percent watched
+	LastWatchedAt   time.Time `json:"last_watched_at"`
+}
+
+// WatchState is a user's progress through one movie, episode or track
+type WatchState struct {
+	MediaID               string     `json:"media_id"`
+	Watched               bool       `json:"watched"`
+	PlayCount             int64      `json:"play_count"` // Viewings watched to the end
+	ResumePositionSeconds float64    `json:"resume_position_seconds"`
+	LastWatchedAt         *time.Time `json:"last_watched_at,omitempty"`
+}
+
+// ContinueWatching lists what a user was last watching, most recent first:
+// movies and episodes stopped part way through, and for shows whose latest
+// episode was finished, the episode after it. Each show appears once.
+func (m *Manager) ContinueWatching(userID uint32, limit int) ([]ContinueWatchingItem, error) {
+	if m.db == nil {
+		return nil, fmt.Errorf("database not available")
+	}
+
+	var sessions []database.PlaybackSession
+	err := m.db.Where("user_id = ? AND media_type IN ? AND last_seen_at > ?", userID,
+		[]string{string(database.MediaTypeMovie), string(database.MediaTypeEpisode)},
+		time.Now().Add(-continueWatchingWindow)).
+		Order("last_seen_at DESC").
+		Find(&sessions).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to load playback sessions: %w", err)
+	}
+
+	// Only the latest viewing of each item counts
+	latest := make([]database.PlaybackSession, 0, len(sessions))
+	seen := make(map[string]bool)
+	var episodeIDs []string
+	for _, session := range sessions {
+		if session.MediaID == "" || seen[session.MediaID] {
+			continue
+		}
+		seen[session.MediaID] = true
+		latest = append(latest, session)
+		if session.MediaType == string(database.MediaTypeEpisode) {
+			episodeIDs = append(episodeIDs, session.MediaID)
+		}
+	}
+	showOf, err := m.episodeShows(episodeIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	rules := m.watchRulesFor(userID)
+	descriptions := make(map[string]HistoryEntry)
+	shows := make(map[string]bool)
+	items := []ContinueWatchingItem{}
+	for i := range latest {
+		if len(items) >= limit {
+			break
+		}
+		session := &latest[i]
+		if showID := showOf[session.MediaID]; showID != "" {
+			if shows[showID] {
+				continue
+			}
+			shows[showID] = true
+		}
+
+		watched := m.sessionWatched(session, rules)
+		if !watched {
+			position := session.PositionSeconds
+			if position <= 0 {
+				position = session.WatchedSeconds
+			}
+			if position < resumeMinSeconds {
+				continue
+			}
+			entry := m.describeHistoryMedia(descriptions, session.MediaID, session.MediaType)
+			item := continueWatchingItem(ContinueReasonResume, entry, session.MediaFileID, session.LastSeenAt)
+			item.PositionSeconds = position
+			item.DurationSeconds = session.DurationSeconds
+			if session.DurationSeconds > 0 {
+				item.Progress = min(100, position/session.DurationSeconds*100)
+			}
+			items = append(items, item)
+			continue
+		}
+
+		if session.MediaType != string(database.MediaTypeEpisode) {
+			continue
+		}
+		item, ok, err := m.nextEpisodeItem(userID, session, descriptions)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			items = append(items, item)
+		}
+	}
+	return items, nil
+}
+
+// nextEpisodeItem returns the episode after a finished one, unless the
+// user has already watched it
+func (m *Manager) nextEpisodeItem(userID uint32, session *database.PlaybackSession, descriptions map[string]HistoryEntry) (ContinueWatchingItem, bool, error) {
+	// Imported viewings have no file; any file of the episode will do
+	var files []database.MediaFile
+	if err := m.db.Select("id, media_id, media_type, resolution").Where("media_id = ?", session.MediaID).Find(&files).Error; err != nil {
+		return ContinueWatchingItem{}, false, fmt.Errorf("failed to load media files: %w", err)
+	}
+	if len(files) == 0 {
+		return ContinueWatchingItem{}, false, nil
+	}
+	file := &files[0]
+	for i := range files {
+		if files[i].ID == session.MediaFileID {
+			file = &files[i]
+		}
+	}
+	next, err := m.followingEpisode(file)
+	if err != nil || next == nil {
+		return ContinueWatchingItem{}, false, err
+	}
+
+	var finished int64
+	if err := m.db.Model(&database.PlaybackSession{}).
+		Where("user_id = ? AND media_id = ? AND completed = ?", userID, next.EpisodeID, true).
+		Count(&finished).Error; err != nil {
+		return ContinueWatchingItem{}, false, fmt.Errorf("failed to load playback sessions: %w", err)
+	}
+	if finished > 0 {
+		return ContinueWatchingItem{}, false, nil
+	}
+
+	entry := m.describeHistoryMedia(descriptions, next.EpisodeID, string(database.MediaTypeEpisode))
+	return continueWatchingItem(ContinueReasonNext, entry, next.MediaFileID, session.LastSeenAt), true, nil
+}
+
+// episodeShows maps episode IDs to the shows they belong to
+func (m *Manager) episodeShows(episodeIDs []string) (map[string]string, error) {
+	shows := make(map[string]string, len(episodeIDs))
+	if len(episodeIDs) == 0 {
+		return shows, nil
+	}
+
+	var rows []struct {
+		EpisodeID string
+		ShowID    string
+	}
+	err := m.db.Table("episodes").
+		Select("episodes.id AS episode_id, seasons.tv_show_id AS show_id").
+		Joins("JOIN seasons ON seasons.id = episodes.season_id").
+		Where("episodes.id IN ?", episodeIDs).
+		Scan(&rows).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to load episodes: %w", err)
+	}
+	for _, row := range rows {
+		shows[row.EpisodeID] = row.ShowID
+	}
+	return shows, nil
+}
+
+func continueWatchingItem(reason string, entry HistoryEntry, mediaFileID string, lastWatched time.Time) ContinueWatchingItem {
+	return ContinueWatchingItem{
+		Reason:        reason,
+		MediaID:       entry.MediaID,
+		MediaType:     entry.MediaType,
+		MediaFileID:   mediaFileID,
+		Title:         entry.Title,
+		Year:          entry.Year,
+		ShowTitle:     entry.ShowTitle,
+		Season:        entry.Season,
+		Episode:       entry.Episode,
+		LastWatchedAt: lastWatched,
+	}
+}
+
+// GetWatchState returns a user's progress through a media item
+func (m *Manager) GetWatchState(userID uint32, mediaID string) (*WatchState, error) {
+	if m.db == nil {
+		return nil, fmt.Errorf("database not available")
+	}
+
+	state := &WatchState{MediaID: mediaID}
+	if err := m.db.Model(&database.PlaybackSession{}).
+		Where("user_id = ? AND media_id = ? AND completed = ?", userID, mediaID, true).
+		Count(&state.PlayCount).Error; err != nil {
+		return nil, fmt.Errorf("failed to load playback sessions: %w", err)
+	}
+	state.Watched = state.PlayCount > 0
+
+	var sessions []database.PlaybackSession
+	if err := m.db.Select("last_seen_at").Where("user_id = ? AND media_id = ?", userID, mediaID).
+		Order("last_seen_at DESC").Limit(1).Find(&sessions).Error; err != nil {
+		return nil, fmt.Errorf("failed to load playback sessions: %w", err)
+	}
+	if len(sessions) > 0 {
+		state.LastWatchedAt = &sessions[0].LastSeenAt
+	}
+	state.ResumePositionSeconds = m.resumePosition(mediaID, userID)
+	return state, nil
+}
+
+// HandleContinueWatching lists a user's in-progress movies and episodes and
+// the next episodes of shows they are watching
+func (h *APIHandler) HandleContinueWatching(c *gin.Context) {
+	userID, ok := parseHistoryUserID(c)
+	if !ok {
+		return
+	}
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(defaultContinueWatchingLimit)))
+	if err != nil || limit <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid limit"})
+		return
+	}
+	limit = min(limit, maxContinueWatchingLimit)
+
+	items, err := h.manager.ContinueWatching(userID, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"items": items,
+		"count": len(items),
+	})
+}
+
+// HandleGetWatchState returns a user's resume position and watched status
+// for a media item
+func (h *APIHandler) HandleGetWatchState(c *gin.Context) {
+	userID, ok := parseHistoryUserID(c)
+	if !ok {
+		return
+	}
+
+	state, err := h.manager.GetWatchState(userID, c.Param("mediaId"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, state)
+}
+
+// RegisterContinueWatchingRoutes registers continue-watching and watch state
+// endpoints
+func RegisterContinueWatchingRoutes(api *gin.RouterGroup, handler *APIHandler) {
+	history := api.Group("/history/:userId")
+	{
+		history.GET("/continue", handler.HandleContinueWatching)
+		history.GET("/progress/:mediaId", handler.HandleGetWatchState)
+	}
+}
@@ -35,6 +35,7 @@ type CleanupConfig struct {
 	MaxTotalSizeGB     int64
 	CleanupInterval    time.Duration
 	LargeFileThreshold int64
+	PopularCacheHits   int // Cache hits that earn a session extended retention
 	ProviderOverrides  map[string]ProviderCleanupConfig
 }
 
@@ -120,6 +121,7 @@ func (cs *CleanupService) cleanupAllProviders() {
 		ExtendedHours:      cs.config.ExtendedHours,
 		MaxTotalSizeGB:     cs.config.MaxTotalSizeGB,
 		LargeFileThreshold: cs.config.LargeFileThreshold,
+		PopularCacheHits:   cs.config.PopularCacheHits,
 	}
 
 	// Clean up expired sessions from database
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"sort"
+	"strings"
 	"sync"
 
 	"github.com/hashicorp/go-hclog"
@@ -98,6 +99,30 @@ func (pm *ProviderManager) ListProviders() []plugins.ProviderInfo {
 	return infos
 }
 
+// SupportsEncoding reports whether any registered provider can encode the codec.
+// Providers advertise encoders as "<codec>_encoding" capabilities.
+func (pm *ProviderManager) SupportsEncoding(codec string) bool {
+	codec = strings.ToLower(codec)
+	switch codec {
+	case "hevc":
+		codec = "h265"
+	case "avc":
+		codec = "h264"
+	}
+	capability := codec + "_encoding"
+
+	// GetInfo may call out to a plugin process, so don't hold the lock here
+	for _, provider := range pm.GetProviders() {
+		for _, c := range provider.GetInfo().Capabilities {
+			if c == capability {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
 // SelectProvider selects the best provider for a transcoding request
 func (pm *ProviderManager) SelectProvider(ctx context.Context, req *plugins.TranscodeRequest) (plugins.TranscodingProvider, error) {
 	pm.mu.RLock()
@@ -40,6 +40,7 @@ func (s *SessionStore) CreateSession(provider string, req *plugins.TranscodeRequ
 		Provider:     provider,
 		Status:       database.TranscodeStatusQueued,
 		Request:      string(requestJSON),
+		CacheKey:     TranscodeCacheKey(req),
 		StartTime:    time.Now(),
 		LastAccessed: time.Now(),
 	}
@@ -233,8 +234,13 @@ func (s *SessionStore) CleanupExpiredSessions(policy RetentionPolicy) (int, erro
 
 	// Find sessions to cleanup
 	var sessions []*database.TranscodeSession
-	if err := s.db.Where("last_accessed < ? AND status IN ?", cutoffTime, []string{"completed", "failed", "cancelled"}).
-		Find(&sessions).Error; err != nil {
+	query := s.db.Where("last_accessed < ? AND status IN ?", cutoffTime, []string{"completed", "failed", "cancelled"})
+	if policy.PopularCacheHits > 0 && policy.ExtendedHours > policy.RetentionHours {
+		// Popular cached transcodes are kept for the extended period
+		extendedCutoff := time.Now().Add(-time.Duration(policy.ExtendedHours) * time.Hour)
+		query = query.Where("cache_hits < ? OR last_accessed < ?", policy.PopularCacheHits, extendedCutoff)
+	}
+	if err := query.Find(&sessions).Error; err != nil {
 		return 0, fmt.Errorf("failed to find expired sessions: %w", err)
 	}
 
@@ -379,7 +385,8 @@ type ProviderStats struct {
 
 type RetentionPolicy struct {
 	RetentionHours     int   // Default retention in hours
-	ExtendedHours      int   // Extended retention for smaller files
+	ExtendedHours      int   // Extended retention for popular cached transcodes
 	MaxTotalSizeGB     int64 // Maximum total size in GB
 	LargeFileThreshold int64 // Threshold for "large" files in bytes
+	PopularCacheHits   int   // Cache hits that earn a session extended retention, 0 = never
 }
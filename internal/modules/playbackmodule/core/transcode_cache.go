@@ -0,0 +1,176 @@
+package core
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/mantonx/viewra/internal/database"
+	plugins "github.com/mantonx/viewra/sdk"
+	"gorm.io/gorm"
+)
+
+// cacheKeyInput is what decides a transcode's output. The source's size and
+// modification time are included so a replaced file isn't served stale.
+type cacheKeyInput struct {
+	InputPath        string
+	InputSize        int64
+	InputModTime     int64
+	Container        string
+	VideoCodec       string
+	AudioCodec       string
+	AudioBitrate     int
+	AudioOnly        bool
+	AudioStreamIndex int
+	Gapless          bool
+	Loudness         *plugins.LoudnessSettings
+	Deinterlace      plugins.DeinterlaceMode
+	TenBit           bool
+	// Subtitle settings are left out of keys when unset, so those of
+	// transcodes without subtitles are unchanged
+	SubtitleMode       plugins.SubtitleMode `json:",omitempty"`
+	SubtitleTrackIndex int                  `json:",omitempty"`
+	SubtitlePath       string               `json:",omitempty"`
+	Resolution         *plugins.Resolution
+	Quality            int
+	SpeedPriority      plugins.SpeedPriority
+	EnableABR          bool
+	FastStart          bool
+}
+
+// TranscodeCacheKey identifies transcodes that produce the same output: the
+// same source encoded with the same settings from the start. It is empty for
+// requests whose output isn't shared, such as ones starting mid-file.
+func TranscodeCacheKey(req *plugins.TranscodeRequest) string {
+	if req == nil || req.Seek > 0 {
+		return ""
+	}
+	info, err := os.Stat(req.InputPath)
+	if err != nil {
+		return ""
+	}
+
+	input, err := json.Marshal(cacheKeyInput{
+		InputPath:          req.InputPath,
+		InputSize:          info.Size(),
+		InputModTime:       info.ModTime().Unix(),
+		Container:          req.Container,
+		VideoCodec:         req.VideoCodec,
+		AudioCodec:         req.AudioCodec,
+		AudioBitrate:       req.AudioBitrate,
+		AudioOnly:          req.AudioOnly,
+		AudioStreamIndex:   req.AudioStreamIndex,
+		Gapless:            req.Gapless,
+		Loudness:           req.Loudness,
+		Deinterlace:        req.Deinterlace,
+		TenBit:             req.TenBit,
+		SubtitleMode:       req.SubtitleMode,
+		SubtitleTrackIndex: req.SubtitleTrackIndex,
+		SubtitlePath:       req.SubtitlePath,
+		Resolution:         req.Resolution,
+		Quality:            req.Quality,
+		SpeedPriority:      req.SpeedPriority,
+		EnableABR:          req.EnableABR,
+		FastStart:          req.FastStart,
+	})
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256(input)
+	return hex.EncodeToString(sum[:])
+}
+
+// FindCachedSession returns the latest session producing the output of the
+// cache key, if one is still being transcoded or completed with its files
+// on disk
+func (s *SessionStore) FindCachedSession(cacheKey string) (*database.TranscodeSession, error) {
+	if cacheKey == "" {
+		return nil, nil
+	}
+
+	var sessions []*database.TranscodeSession
+	statuses := []database.TranscodeStatus{
+		database.TranscodeStatusQueued,
+		database.TranscodeStatusRunning,
+		database.TranscodeStatusCompleted,
+	}
+	if err := s.db.Where("cache_key = ? AND status IN ?", cacheKey, statuses).
+		Order("start_time DESC").Find(&sessions).Error; err != nil {
+		return nil, fmt.Errorf("failed to find cached sessions: %w", err)
+	}
+
+	for _, session := range sessions {
+		if session.Status == database.TranscodeStatusCompleted {
+			if _, err := os.Stat(session.DirectoryPath); session.DirectoryPath == "" || err != nil {
+				continue
+			}
+		}
+		return session, nil
+	}
+	return nil, nil
+}
+
+// RecordCacheHit counts a viewer served from a session's existing output
+func (s *SessionStore) RecordCacheHit(sessionID string) error {
+	updates := map[string]interface{}{
+		"cache_hits":    gorm.Expr("cache_hits + 1"),
+		"last_accessed": time.Now(),
+	}
+	if err := s.db.Model(&database.TranscodeSession{}).Where("id = ?", sessionID).Updates(updates).Error; err != nil {
+		return fmt.Errorf("failed to record cache hit: %w", err)
+	}
+	return nil
+}
+
+// cachedSession returns a session whose output can be served for the
+// request instead of starting another transcode, counting the new viewer
+func (ts *TranscodeService) cachedSession(req *plugins.TranscodeRequest) *database.TranscodeSession {
+	if !ts.config.TranscodeCache {
+		return nil
+	}
+
+	session, err := ts.sessionStore.FindCachedSession(TranscodeCacheKey(req))
+	if err != nil {
+		ts.logger.Warn("failed to look up cached transcode", "error", err)
+		return nil
+	}
+	if session == nil {
+		return nil
+	}
+
+	if err := ts.sessionStore.RecordCacheHit(session.ID); err != nil {
+		ts.logger.Warn("failed to record cache hit", "error", err, "session_id", session.ID)
+	}
+	session.CacheHits++
+	ts.addViewer(session.ID)
+
+	ts.logger.Info("serving cached transcode",
+		"session_id", session.ID,
+		"status", session.Status,
+		"cache_hits", session.CacheHits)
+
+	return session
+}
+
+// addViewer counts a viewer of a session
+func (ts *TranscodeService) addViewer(sessionID string) {
+	ts.viewersMu.Lock()
+	defer ts.viewersMu.Unlock()
+	ts.viewers[sessionID]++
+}
+
+// removeViewer drops a viewer of a session and returns how many remain
+func (ts *TranscodeService) removeViewer(sessionID string) int {
+	ts.viewersMu.Lock()
+	defer ts.viewersMu.Unlock()
+	remaining := ts.viewers[sessionID] - 1
+	if remaining <= 0 {
+		delete(ts.viewers, sessionID)
+		return 0
+	}
+	ts.viewers[sessionID] = remaining
+	return remaining
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/hashicorp/go-hclog"
@@ -23,6 +24,10 @@ type TranscodeService struct {
 	providerManager *ProviderManager
 	logger          hclog.Logger
 	db              *gorm.DB
+
+	// Viewers of each session started or served from the cache since startup
+	viewersMu sync.Mutex
+	viewers   map[string]int
 }
 
 // NewTranscodeService creates a new transcode service
@@ -44,6 +49,7 @@ func NewTranscodeService(cfg config.TranscodingConfig, db *gorm.DB, logger hclog
 		MaxTotalSizeGB:     cfg.MaxDiskUsageGB,
 		CleanupInterval:    cfg.CleanupInterval,
 		LargeFileThreshold: cfg.LargeFileThreshold * 1024 * 1024, // Convert MB to bytes
+		PopularCacheHits:   cfg.CachePopularHits,
 	}
 	cleanupService := NewCleanupService(cleanupConfig, sessionStore, fileManager, logger)
 
@@ -58,6 +64,7 @@ func NewTranscodeService(cfg config.TranscodingConfig, db *gorm.DB, logger hclog
 		providerManager: providerManager,
 		logger:          logger.Named("transcode-service"),
 		db:              db,
+		viewers:         make(map[string]int),
 	}
 
 	// Start cleanup service in background
@@ -99,6 +106,11 @@ func (ts *TranscodeService) StartTranscode(ctx context.Context, req *plugins.Tra
 		return nil, fmt.Errorf("container format cannot be empty")
 	}
 
+	// Viewers of the same item at the same settings share one transcode
+	if session := ts.cachedSession(req); session != nil {
+		return session, nil
+	}
+
 	// Check session limits
 	activeSessions, err := ts.sessionStore.GetActiveSessions()
 	if err != nil {
@@ -143,6 +155,7 @@ func (ts *TranscodeService) StartTranscode(ctx context.Context, req *plugins.Tra
 		return nil, fmt.Errorf("failed to create session directory: %w", err)
 	}
 	session.DirectoryPath = dirPath
+	ts.addViewer(session.ID)
 
 	// Update session with directory path
 	if err := ts.db.Model(session).Update("directory_path", dirPath).Error; err != nil {
@@ -193,7 +206,7 @@ func (ts *TranscodeService) StartTranscode(ctx context.Context, req *plugins.Tra
 	if req.Container == "dash" || req.Container == "hls" {
 		manifestFile := "manifest.mpd"
 		if req.Container == "hls" {
-			manifestFile = "playlist.m3u8"
+			manifestFile = "master.m3u8"
 		}
 		
 		manifestPath := fmt.Sprintf("%s/%s", dirPath, manifestFile)
@@ -284,6 +297,18 @@ func (ts *TranscodeService) StopTranscode(sessionID string) error {
 		return fmt.Errorf("session not found: %w", err)
 	}
 
+	// A shared transcode keeps going while others are watching it
+	if remaining := ts.removeViewer(sessionID); remaining > 0 {
+		ts.logger.Info("viewer left shared transcoding session", "session_id", sessionID, "viewers", remaining)
+		return nil
+	}
+
+	// Completed cacheable output stays for later viewers until retention expires
+	if session.Status == database.TranscodeStatusCompleted && session.CacheKey != "" && ts.config.TranscodeCache {
+		ts.logger.Info("keeping cached transcode output", "session_id", sessionID)
+		return nil
+	}
+
 	// Get provider
 	_, err = ts.providerManager.GetProvider(session.Provider)
 	if err != nil {
@@ -301,6 +326,16 @@ func (ts *TranscodeService) StopTranscode(sessionID string) error {
 	return nil
 }
 
+// ForceStopTranscode stops a transcoding operation even if other viewers
+// share it
+func (ts *TranscodeService) ForceStopTranscode(sessionID string) error {
+	ts.viewersMu.Lock()
+	delete(ts.viewers, sessionID)
+	ts.viewersMu.Unlock()
+
+	return ts.StopTranscode(sessionID)
+}
+
 // GetSession returns session information
 func (ts *TranscodeService) GetSession(sessionID string) (*database.TranscodeSession, error) {
 	return ts.sessionStore.GetSession(sessionID)
@@ -356,17 +391,23 @@ func (ts *TranscodeService) GetDashboardData() (*TranscodingDashboardData, error
 
 	// Convert sessions to views
 	var sessionViews []TranscodeSessionView
+	var totalSpeed float64
+	var speedSamples int
 	for _, session := range activeSessions {
 		view := TranscodeSessionView{
 			ID:       session.ID,
 			Provider: session.Provider,
-			Status:   string(session.Status),
-			Progress: 0,
+			Status:    string(session.Status),
+			Progress:  0,
+			StartTime: session.StartTime,
 		}
 
 		if session.Progress != "" {
 			if progress, err := session.GetProgress(); err == nil && progress != nil {
 				view.Progress = progress.PercentComplete
+				view.Stats = NewTranscodeStats(progress)
+				totalSpeed += progress.CurrentSpeed
+				speedSamples++
 			}
 		}
 
@@ -381,12 +422,18 @@ func (ts *TranscodeService) GetDashboardData() (*TranscodingDashboardData, error
 		sessionViews = append(sessionViews, view)
 	}
 
+	performance := PerformanceMetrics{}
+	if speedSamples > 0 {
+		performance.AverageSpeed = totalSpeed / float64(speedSamples)
+	}
+
 	return &TranscodingDashboardData{
 		Overview: overview,
 		Sessions: sessionViews,
 		Hardware: HardwareStatus{
 			Available: ts.getAvailableHardware(),
 		},
+		Performance: performance,
 	}, nil
 }
 
@@ -442,6 +489,36 @@ type TranscodeSessionView struct {
 	Quality       int                   `json:"quality"`
 	SpeedPriority plugins.SpeedPriority `json:"speed_priority"`
 	StartTime     time.Time             `json:"start_time"`
+	Stats         *TranscodeStats       `json:"stats,omitempty"`
+}
+
+// TranscodeStats contains real-time encoder statistics reported by the provider
+type TranscodeStats struct {
+	FPS           float64 `json:"fps"`
+	Speed         float64 `json:"speed"`
+	Frame         int64   `json:"frame"`
+	TotalFrames   int64   `json:"total_frames"`
+	Bitrate       float64 `json:"bitrate_kbps"`
+	DroppedFrames int64   `json:"dropped_frames"`
+	BytesWritten  int64   `json:"bytes_written"`
+	TimeRemaining float64 `json:"time_remaining_seconds"`
+}
+
+// NewTranscodeStats extracts encoder statistics from provider progress
+func NewTranscodeStats(progress *plugins.TranscodingProgress) *TranscodeStats {
+	if progress == nil {
+		return &TranscodeStats{}
+	}
+	return &TranscodeStats{
+		FPS:           progress.CurrentFPS,
+		Speed:         progress.CurrentSpeed,
+		Frame:         progress.CurrentFrame,
+		TotalFrames:   progress.TotalFrames,
+		Bitrate:       progress.CurrentBitrate,
+		DroppedFrames: progress.DroppedFrames,
+		BytesWritten:  progress.BytesWritten,
+		TimeRemaining: progress.TimeRemaining.Seconds(),
+	}
 }
 
 type HardwareStatus struct {
@@ -0,0 +1,201 @@
+package playbackmodule
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mantonx/viewra/internal/database"
+	plugins "github.com/mantonx/viewra/sdk"
+	"github.com/mantonx/viewra/sdk/transcoding/ffmpeg"
+)
+
+const (
+	// idetFrames is how many frames the interlace detector inspects
+	idetFrames = 600
+	// idetTimeout bounds a detection run on a slow disk
+	idetTimeout = 20 * time.Second
+)
+
+// idetMultiFramePattern matches the idet filter's multi-frame summary line
+var idetMultiFramePattern = regexp.MustCompile(`Multi frame detection:\s*TFF:\s*(\d+)\s*BFF:\s*(\d+)\s*Progressive:\s*(\d+)`)
+
+// scanTypePending marks a file whose scan type is being detected
+type scanTypePending struct{}
+
+// applyDeinterlacing picks the deinterlace mode for a request. A per-item
+// override wins; otherwise sources probed as interlaced use the scan type
+// analysis, which tells true interlaced video from telecined film.
+func (m *Manager) applyDeinterlacing(request *plugins.TranscodeRequest) {
+	if request.Deinterlace != plugins.DeinterlaceAuto || request.AudioOnly || m.db == nil {
+		return
+	}
+
+	var mediaFile database.MediaFile
+	if err := m.db.Where("path = ?", request.InputPath).First(&mediaFile).Error; err != nil {
+		return
+	}
+
+	if mediaFile.Deinterlace != "" {
+		request.Deinterlace = ffmpeg.ParseDeinterlaceMode(mediaFile.Deinterlace)
+		m.logger.Debug("using deinterlace override", "media_file_id", mediaFile.ID, "mode", request.Deinterlace)
+		return
+	}
+
+	if !strings.EqualFold(mediaFile.Interlaced, "yes") {
+		return
+	}
+
+	request.Deinterlace = m.scanType(&mediaFile)
+	m.logger.Info("interlaced source",
+		"media_file_id", mediaFile.ID,
+		"framerate", mediaFile.VideoFramerate,
+		"mode", request.Deinterlace)
+}
+
+// scanType returns the detected deinterlace mode of a file probed as
+// interlaced. Detection takes a while, so the first request for a file starts
+// it in the background and deinterlaces meanwhile, which is the safe choice
+// without analysis; later requests use the cached result.
+func (m *Manager) scanType(mediaFile *database.MediaFile) plugins.DeinterlaceMode {
+	cached, running := m.scanTypes.LoadOrStore(mediaFile.Path, scanTypePending{})
+	if mode, ok := cached.(plugins.DeinterlaceMode); ok {
+		return mode
+	}
+	if !running {
+		file := *mediaFile
+		go m.detectScanType(&file)
+	}
+	return plugins.DeinterlaceBwdif
+}
+
+// detectScanType runs FFmpeg's idet filter over a sample of the file and
+// caches the mode it calls for. Telecined film shows a 3:2 mix of
+// progressive and combed frames, while interlaced video is combed
+// throughout. A failed run isn't cached, so the next request retries it.
+func (m *Manager) detectScanType(mediaFile *database.MediaFile) {
+	interlacedFrames, progressiveFrames, err := runInterlaceDetection(m.ctx, mediaFile.Path, mediaFile.Duration)
+	if err != nil {
+		m.logger.Warn("interlace detection failed, deinterlacing", "path", mediaFile.Path, "error", err)
+		m.scanTypes.Delete(mediaFile.Path)
+		return
+	}
+
+	mode := plugins.DeinterlaceBwdif
+	if total := interlacedFrames + progressiveFrames; total > 0 {
+		ratio := float64(interlacedFrames) / float64(total)
+		switch {
+		case ratio < 0.15:
+			// Flagged interlaced but the content is progressive (e.g. PsF)
+			mode = plugins.DeinterlaceOff
+		case ratio <= 0.6 && isNTSCFramerate(mediaFile.VideoFramerate):
+			mode = plugins.DeinterlaceTelecine
+		}
+	}
+
+	m.logger.Info("detected scan type", "media_file_id", mediaFile.ID, "mode", mode)
+	m.scanTypes.Store(mediaFile.Path, mode)
+}
+
+// runInterlaceDetection returns the interlaced and progressive frame counts
+// reported by the idet filter
+func runInterlaceDetection(ctx context.Context, path string, durationSeconds int) (int, int, error) {
+	ffmpegPath := findExecutable("ffmpeg")
+	if ffmpegPath == "" {
+		return 0, 0, fmt.Errorf("ffmpeg not found in PATH")
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, idetTimeout)
+	defer cancel()
+
+	var args []string
+	// Skip opening titles and logos, which are often progressive even on interlaced discs
+	if durationSeconds > 300 {
+		args = append(args, "-ss", "120")
+	}
+	args = append(args,
+		"-hide_banner", "-nostats",
+		"-i", path,
+		"-map", "0:v:0",
+		"-vf", "idet",
+		"-frames:v", strconv.Itoa(idetFrames),
+		"-an", "-sn", "-dn",
+		"-f", "null", "-",
+	)
+
+	output, err := exec.CommandContext(ctx, ffmpegPath, args...).CombinedOutput()
+	if err != nil {
+		return 0, 0, fmt.Errorf("idet failed: %w", err)
+	}
+
+	match := idetMultiFramePattern.FindStringSubmatch(string(output))
+	if match == nil {
+		return 0, 0, fmt.Errorf("no idet summary in ffmpeg output")
+	}
+
+	tff, _ := strconv.Atoi(match[1])
+	bff, _ := strconv.Atoi(match[2])
+	progressive, _ := strconv.Atoi(match[3])
+	return tff + bff, progressive, nil
+}
+
+// isNTSCFramerate reports whether the framerate is 29.97/30fps, the rates
+// 3:2 pulldown produces
+func isNTSCFramerate(framerate string) bool {
+	var fps float64
+	if num, den, ok := strings.Cut(framerate, "/"); ok {
+		n, _ := strconv.ParseFloat(num, 64)
+		d, _ := strconv.ParseFloat(den, 64)
+		if d > 0 {
+			fps = n / d
+		}
+	} else {
+		fps, _ = strconv.ParseFloat(framerate, 64)
+	}
+	return fps > 29.9 && fps < 30.1
+}
+
+// SetDeinterlaceOverride stores a per-item deinterlace mode; an empty mode
+// restores automatic detection
+func (m *Manager) SetDeinterlaceOverride(mediaFileID, mode string) (plugins.DeinterlaceMode, error) {
+	parsed := ffmpeg.ParseDeinterlaceMode(mode)
+	if mode != "" && mode != "auto" && parsed == plugins.DeinterlaceAuto {
+		return "", fmt.Errorf("unknown deinterlace mode %q", mode)
+	}
+
+	result := m.db.Model(&database.MediaFile{}).Where("id = ?", mediaFileID).Update("deinterlace", string(parsed))
+	if result.Error != nil {
+		return "", result.Error
+	}
+	if result.RowsAffected == 0 {
+		return "", fmt.Errorf("media file not found: %s", mediaFileID)
+	}
+
+	return parsed, nil
+}
+
+// HandleSetDeinterlace sets the deinterlace override for a media file
+func (h *APIHandler) HandleSetDeinterlace(c *gin.Context) {
+	var request struct {
+		Mode string `json:"mode"` // off, yadif, bwdif, ivtc; empty or "auto" to detect
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	mediaFileID := c.Param("mediaFileId")
+	mode, err := h.manager.SetDeinterlaceOverride(mediaFileID, request.Mode)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"media_file_id": mediaFileID, "mode": mode})
+}
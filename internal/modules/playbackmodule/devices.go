@@ -0,0 +1,188 @@
+package playbackmodule
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mantonx/viewra/internal/database"
+)
+
+// DeviceRegistration is what a client reports about itself once: its decode
+// capabilities under its device ID, and a name to show in device lists
+type DeviceRegistration struct {
+	DeviceProfile
+	Name string `json:"name,omitempty"`
+}
+
+// RegisterDevice stores the capabilities a client reports under its device
+// ID, replacing those of an earlier registration
+func (m *Manager) RegisterDevice(registration *DeviceRegistration) (*database.ClientDevice, error) {
+	deviceID := strings.TrimSpace(registration.DeviceID)
+	if deviceID == "" {
+		return nil, fmt.Errorf("device_id is required")
+	}
+	if registration.MaxBitrate < 0 {
+		return nil, fmt.Errorf("max_bitrate must not be negative")
+	}
+
+	now := time.Now()
+	device := &database.ClientDevice{
+		DeviceID:        deviceID,
+		UserID:          registration.UserID,
+		Name:            strings.TrimSpace(registration.Name),
+		UserAgent:       registration.UserAgent,
+		SupportedCodecs: joinDeviceList(registration.SupportedCodecs),
+		MaxResolution:   strings.ToLower(strings.TrimSpace(registration.MaxResolution)),
+		MaxBitrate:      registration.MaxBitrate,
+		SupportsHEVC:    registration.SupportsHEVC,
+		SupportsAV1:     registration.SupportsAV1,
+		SupportsHDR:     registration.SupportsHDR,
+		DRMSystems:      joinDeviceList(registration.DRMSystems),
+		LastSeenAt:      now,
+		CreatedAt:       now,
+	}
+
+	// Save updates every column, so keep when the device first registered
+	var existing []database.ClientDevice
+	if err := m.db.Where("device_id = ?", deviceID).Limit(1).Find(&existing).Error; err != nil {
+		return nil, fmt.Errorf("failed to look up device: %w", err)
+	}
+	if len(existing) > 0 {
+		device.CreatedAt = existing[0].CreatedAt
+	}
+
+	if err := m.db.Save(device).Error; err != nil {
+		return nil, fmt.Errorf("failed to save device: %w", err)
+	}
+
+	m.logger.Info("registered client device",
+		"device_id", device.DeviceID,
+		"name", device.Name,
+		"codecs", device.SupportedCodecs,
+		"max_resolution", device.MaxResolution)
+
+	return device, nil
+}
+
+// applyRegisteredDevice replaces the capabilities of a profile that names a
+// registered device with the stored ones. The user, client address and user
+// agent of the request are kept, falling back to the registration's.
+func (m *Manager) applyRegisteredDevice(profile *DeviceProfile) {
+	if m.db == nil || profile == nil || profile.DeviceID == "" {
+		return
+	}
+
+	var devices []database.ClientDevice
+	if err := m.db.Where("device_id = ?", profile.DeviceID).Limit(1).Find(&devices).Error; err != nil || len(devices) == 0 {
+		m.logger.Debug("device not registered, using request capabilities", "device_id", profile.DeviceID)
+		return
+	}
+	device := devices[0]
+
+	profile.SupportedCodecs = splitDeviceList(device.SupportedCodecs)
+	profile.MaxResolution = device.MaxResolution
+	profile.MaxBitrate = device.MaxBitrate
+	profile.SupportsHEVC = device.SupportsHEVC
+	profile.SupportsAV1 = device.SupportsAV1
+	profile.SupportsHDR = device.SupportsHDR
+	profile.DRMSystems = splitDeviceList(device.DRMSystems)
+	if profile.UserID == 0 {
+		profile.UserID = device.UserID
+	}
+	if profile.UserAgent == "" {
+		profile.UserAgent = device.UserAgent
+	}
+
+	m.db.Model(&database.ClientDevice{}).Where("device_id = ?", device.DeviceID).UpdateColumn("last_seen_at", time.Now())
+}
+
+// joinDeviceList stores a reported list lower-cased and comma-separated
+func joinDeviceList(values []string) string {
+	var normalized []string
+	for _, value := range values {
+		if value = strings.ToLower(strings.TrimSpace(value)); value != "" {
+			normalized = append(normalized, value)
+		}
+	}
+	return strings.Join(normalized, ",")
+}
+
+// splitDeviceList reads a list stored by joinDeviceList
+func splitDeviceList(list string) []string {
+	if list == "" {
+		return nil
+	}
+	return strings.Split(list, ",")
+}
+
+// HandleRegisterDevice stores a client's capabilities under its device ID
+func (h *APIHandler) HandleRegisterDevice(c *gin.Context) {
+	var registration DeviceRegistration
+	if err := c.ShouldBindJSON(&registration); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if registration.UserAgent == "" {
+		registration.UserAgent = c.Request.UserAgent()
+	}
+
+	device, err := h.manager.RegisterDevice(&registration)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, device)
+}
+
+// HandleListDevices returns registered devices, optionally only a user's
+func (h *APIHandler) HandleListDevices(c *gin.Context) {
+	query := h.manager.db.Order("last_seen_at DESC")
+	if userIDParam := c.Query("user_id"); userIDParam != "" {
+		userID, err := strconv.ParseUint(userIDParam, 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user ID"})
+			return
+		}
+		query = query.Where("user_id = ?", userID)
+	}
+
+	var devices []database.ClientDevice
+	if err := query.Find(&devices).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"devices": devices})
+}
+
+// HandleGetDevice returns a registered device
+func (h *APIHandler) HandleGetDevice(c *gin.Context) {
+	var devices []database.ClientDevice
+	if err := h.manager.db.Where("device_id = ?", c.Param("deviceId")).Limit(1).Find(&devices).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if len(devices) == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "device not registered"})
+		return
+	}
+
+	c.JSON(http.StatusOK, devices[0])
+}
+
+// HandleDeleteDevice forgets a device; it must register again before its
+// device ID stands in for capabilities
+func (h *APIHandler) HandleDeleteDevice(c *gin.Context) {
+	deviceID := c.Param("deviceId")
+	if err := h.manager.db.Delete(&database.ClientDevice{}, "device_id = ?", deviceID).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"device_id": deviceID, "deleted": true})
+}
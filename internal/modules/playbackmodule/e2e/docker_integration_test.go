@@ -12,6 +12,7 @@ import (
 	"time"
 
 	"github.com/mantonx/viewra/internal/config"
+	"github.com/mantonx/viewra/internal/streamurl"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -147,7 +148,7 @@ func TestE2EDockerTranscodingIntegration(t *testing.T) {
 		}
 
 		// Test DASH manifest endpoint
-		req := httptest.NewRequest("GET", fmt.Sprintf("/api/playback/stream/%s/manifest.mpd", sessionID), nil)
+		req := httptest.NewRequest("GET", streamurl.File(sessionID, 0, "manifest.mpd"), nil)
 		w := httptest.NewRecorder()
 		router.ServeHTTP(w, req)
 
@@ -173,7 +174,7 @@ func TestE2EDockerTranscodingIntegration(t *testing.T) {
 		require.NotEmpty(t, sessionID, "Session ID should be available")
 
 		// Test serving a specific segment
-		req := httptest.NewRequest("GET", fmt.Sprintf("/api/playback/stream/%s/init-stream0.m4s", sessionID), nil)
+		req := httptest.NewRequest("GET", streamurl.File(sessionID, 0, "init-stream0.m4s"), nil)
 		w := httptest.NewRecorder()
 		router.ServeHTTP(w, req)
 
@@ -227,7 +228,7 @@ func TestE2EDockerTranscodingIntegration(t *testing.T) {
 		assert.FileExists(t, playlistPath, "HLS playlist should be created in Docker volume")
 
 		// Test HLS playlist serving
-		req = httptest.NewRequest("GET", fmt.Sprintf("/api/playback/stream/%s/playlist.m3u8", hlsSessionID), nil)
+		req = httptest.NewRequest("GET", streamurl.File(hlsSessionID, 0, "playlist.m3u8"), nil)
 		hlsW := NewTestableResponseWriter()
 		defer hlsW.Close()
 		router.ServeHTTP(hlsW, req)
@@ -19,6 +19,7 @@ import (
 	"github.com/mantonx/viewra/internal/config"
 	"github.com/mantonx/viewra/internal/database"
 	"github.com/mantonx/viewra/internal/modules/playbackmodule"
+	"github.com/mantonx/viewra/internal/streamurl"
 	plugins "github.com/mantonx/viewra/sdk"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -699,7 +700,7 @@ func TestE2EErrorHandling(t *testing.T) {
 		t.Logf("✅ Non-existent session DELETE handled correctly")
 
 		// Test streaming from non-existent session
-		req = httptest.NewRequest("GET", "/api/playback/stream/nonexistent_session_id/manifest.mpd", nil)
+		req = httptest.NewRequest("GET", streamurl.File("nonexistent_session_id", 0, "manifest.mpd"), nil)
 		w = httptest.NewRecorder()
 		router.ServeHTTP(w, req)
 
@@ -822,7 +823,7 @@ func TestE2ENetworkResilienceScenarios(t *testing.T) {
 		// Simulate client disconnect by trying to access stream multiple times
 		// then stopping abruptly
 		for i := 0; i < 3; i++ {
-			req := httptest.NewRequest("GET", fmt.Sprintf("/api/playback/stream/%s", sessionID), nil)
+			req := httptest.NewRequest("GET", "/api/playback/stream/"+streamurl.Token(sessionID, 0), nil)
 			w := httptest.NewRecorder()
 			router.ServeHTTP(w, req)
 			// Don't wait for response, simulate disconnect
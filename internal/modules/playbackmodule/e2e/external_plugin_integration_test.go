@@ -15,6 +15,7 @@ import (
 	"github.com/hashicorp/go-hclog"
 	"github.com/mantonx/viewra/internal/modules/playbackmodule"
 	"github.com/mantonx/viewra/internal/modules/pluginmodule"
+	"github.com/mantonx/viewra/internal/streamurl"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"gorm.io/gorm"
@@ -246,7 +247,7 @@ func TestE2EExternalPluginIntegration(t *testing.T) {
 
 		// Test streaming from completed session
 		if finalStatus == "completed" {
-			req = httptest.NewRequest("GET", fmt.Sprintf("/api/playback/stream/%s", sessionID), nil)
+			req = httptest.NewRequest("GET", "/api/playback/stream/"+streamurl.Token(sessionID, 0), nil)
 			w = httptest.NewRecorder()
 			router.ServeHTTP(w, req)
 
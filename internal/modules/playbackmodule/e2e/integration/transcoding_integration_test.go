@@ -21,6 +21,7 @@ import (
 	"github.com/mantonx/viewra/internal/config"
 	"github.com/mantonx/viewra/internal/database"
 	"github.com/mantonx/viewra/internal/modules/playbackmodule"
+	"github.com/mantonx/viewra/internal/streamurl"
 	plugins "github.com/mantonx/viewra/sdk"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -773,7 +774,7 @@ func TestE2ETranscodingDASH(t *testing.T) {
 
 		var manifestContent string
 		for time.Now().Before(deadline) {
-			req := httptest.NewRequest("GET", fmt.Sprintf("/api/playback/stream/%s/manifest.mpd", sessionID), nil)
+			req := httptest.NewRequest("GET", streamurl.File(sessionID, 0, "manifest.mpd"), nil)
 			w := httptest.NewRecorder()
 			router.ServeHTTP(w, req)
 
@@ -835,7 +836,7 @@ func TestE2ETranscodingDASH(t *testing.T) {
 		// Test serving a segment
 		if len(segments) > 0 {
 			segmentName := segments[0]
-			req := httptest.NewRequest("GET", fmt.Sprintf("/api/playback/stream/%s/%s", sessionID, segmentName), nil)
+			req := httptest.NewRequest("GET", streamurl.File(sessionID, 0, segmentName), nil)
 			w := httptest.NewRecorder()
 			router.ServeHTTP(w, req)
 
@@ -1052,7 +1053,7 @@ func TestE2ETranscodingHLS(t *testing.T) {
 
 		var playlistContent string
 		for time.Now().Before(deadline) {
-			req := httptest.NewRequest("GET", fmt.Sprintf("/api/playback/stream/%s/playlist.m3u8", sessionID), nil)
+			req := httptest.NewRequest("GET", streamurl.File(sessionID, 0, "playlist.m3u8"), nil)
 			w := NewTestableResponseWriter()
 			defer w.Close()
 			router.ServeHTTP(w, req)
@@ -1114,7 +1115,7 @@ func TestE2ETranscodingHLS(t *testing.T) {
 		// Test serving a segment
 		if len(segments) > 0 {
 			segmentName := segments[0]
-			req := httptest.NewRequest("GET", fmt.Sprintf("/api/playback/stream/%s/%s", sessionID, segmentName), nil)
+			req := httptest.NewRequest("GET", streamurl.File(sessionID, 0, segmentName), nil)
 			w := httptest.NewRecorder()
 			router.ServeHTTP(w, req)
 
@@ -9,6 +9,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/mantonx/viewra/internal/streamurl"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -59,7 +60,7 @@ func TestE2EProgressiveStreaming(t *testing.T) {
 		time.Sleep(1 * time.Second)
 
 		// Test progressive streaming
-		req = httptest.NewRequest("GET", fmt.Sprintf("/api/playback/stream/%s", sessionID), nil)
+		req = httptest.NewRequest("GET", "/api/playback/stream/"+streamurl.Token(sessionID, 0), nil)
 		w = httptest.NewRecorder()
 		router.ServeHTTP(w, req)
 
@@ -116,7 +117,7 @@ func TestE2EProgressiveStreaming(t *testing.T) {
 		time.Sleep(1 * time.Second)
 
 		// Test progressive streaming
-		req = httptest.NewRequest("GET", fmt.Sprintf("/api/playback/stream/%s", sessionID), nil)
+		req = httptest.NewRequest("GET", "/api/playback/stream/"+streamurl.Token(sessionID, 0), nil)
 		w = httptest.NewRecorder()
 		router.ServeHTTP(w, req)
 
@@ -159,7 +160,7 @@ func TestE2EProgressiveStreaming(t *testing.T) {
 		time.Sleep(1 * time.Second)
 
 		// Test DASH manifest serving
-		req = httptest.NewRequest("GET", fmt.Sprintf("/api/playback/stream/%s/manifest.mpd", sessionID), nil)
+		req = httptest.NewRequest("GET", streamurl.File(sessionID, 0, "manifest.mpd"), nil)
 		w = httptest.NewRecorder()
 		router.ServeHTTP(w, req)
 
@@ -211,7 +212,7 @@ func TestE2EProgressiveStreaming(t *testing.T) {
 		time.Sleep(1 * time.Second)
 
 		// Test HLS playlist serving
-		req = httptest.NewRequest("GET", fmt.Sprintf("/api/playback/stream/%s/playlist.m3u8", sessionID), nil)
+		req = httptest.NewRequest("GET", streamurl.File(sessionID, 0, "playlist.m3u8"), nil)
 		w = httptest.NewRecorder()
 		router.ServeHTTP(w, req)
 
@@ -269,7 +270,7 @@ func TestE2EProgressiveStreaming(t *testing.T) {
 		}
 
 		for _, segment := range segments {
-			req = httptest.NewRequest("GET", fmt.Sprintf("/api/playback/stream/%s/%s", sessionID, segment), nil)
+			req = httptest.NewRequest("GET", streamurl.File(sessionID, 0, segment), nil)
 			w = httptest.NewRecorder()
 			router.ServeHTTP(w, req)
 
@@ -322,9 +323,9 @@ func TestE2EProgressiveStreaming(t *testing.T) {
 
 		// Test HEAD requests (important for video players)
 		headEndpoints := []string{
-			fmt.Sprintf("/api/playback/stream/%s/manifest.mpd", sessionID),
-			fmt.Sprintf("/api/playback/stream/%s/init-stream0.m4s", sessionID),
-			fmt.Sprintf("/api/playback/stream/%s/init-stream1.m4s", sessionID),
+			streamurl.File(sessionID, 0, "manifest.mpd"),
+			streamurl.File(sessionID, 0, "init-stream0.m4s"),
+			streamurl.File(sessionID, 0, "init-stream1.m4s"),
 		}
 
 		for _, endpoint := range headEndpoints {
@@ -377,7 +378,7 @@ func TestE2EProgressiveStreaming(t *testing.T) {
 		time.Sleep(1 * time.Second)
 
 		// Test Range requests (important for seeking)
-		req = httptest.NewRequest("GET", fmt.Sprintf("/api/playback/stream/%s/init-stream0.m4s", sessionID), nil)
+		req = httptest.NewRequest("GET", streamurl.File(sessionID, 0, "init-stream0.m4s"), nil)
 		req.Header.Set("Range", "bytes=0-1023")
 		w = httptest.NewRecorder()
 		router.ServeHTTP(w, req)
@@ -399,10 +400,10 @@ func TestE2EProgressiveStreaming(t *testing.T) {
 
 	t.Run("NonExistentSession_ShouldReturn404", func(t *testing.T) {
 		endpoints := []string{
-			"/api/playback/stream/nonexistent_session",
-			"/api/playback/stream/nonexistent_session/manifest.mpd",
-			"/api/playback/stream/nonexistent_session/playlist.m3u8",
-			"/api/playback/stream/nonexistent_session/segment_1.m4s",
+			"/api/playback/stream/" + streamurl.Token("nonexistent_session", 0),
+			streamurl.File("nonexistent_session", 0, "manifest.mpd"),
+			streamurl.File("nonexistent_session", 0, "playlist.m3u8"),
+			streamurl.File("nonexistent_session", 0, "segment_1.m4s"),
 		}
 
 		for _, endpoint := range endpoints {
@@ -443,7 +444,7 @@ func TestE2EProgressiveStreaming(t *testing.T) {
 
 		// Simulate client disconnects by starting multiple streams and abandoning them
 		for i := 0; i < 3; i++ {
-			req = httptest.NewRequest("GET", fmt.Sprintf("/api/playback/stream/%s", sessionID), nil)
+			req = httptest.NewRequest("GET", "/api/playback/stream/"+streamurl.Token(sessionID, 0), nil)
 			w = httptest.NewRecorder()
 
 			// Simulate client disconnect by starting and immediately canceling
@@ -503,7 +504,7 @@ func TestE2EProgressiveStreaming(t *testing.T) {
 		time.Sleep(1 * time.Second)
 
 		// Test CORS headers for streaming endpoints
-		req = httptest.NewRequest("GET", fmt.Sprintf("/api/playback/stream/%s/manifest.mpd", sessionID), nil)
+		req = httptest.NewRequest("GET", streamurl.File(sessionID, 0, "manifest.mpd"), nil)
 		req.Header.Set("Origin", "http://localhost:3000")
 		w = httptest.NewRecorder()
 		router.ServeHTTP(w, req)
@@ -583,7 +584,7 @@ func TestE2EStreamingPerformance(t *testing.T) {
 
 		for i, sessionID := range sessionIDs {
 			go func(idx int, id string) {
-				req := httptest.NewRequest("GET", fmt.Sprintf("/api/playback/stream/%s", id), nil)
+				req := httptest.NewRequest("GET", "/api/playback/stream/"+streamurl.Token(id, 0), nil)
 				w := httptest.NewRecorder()
 				router.ServeHTTP(w, req)
 
@@ -642,7 +643,7 @@ func TestE2EStreamingPerformance(t *testing.T) {
 		sessionCreationTime := time.Since(startTime)
 
 		// Measure time to first streamable content
-		req = httptest.NewRequest("GET", fmt.Sprintf("/api/playback/stream/%s", sessionID), nil)
+		req = httptest.NewRequest("GET", "/api/playback/stream/"+streamurl.Token(sessionID, 0), nil)
 
 		streamStart := time.Now()
 		w = httptest.NewRecorder()
@@ -0,0 +1,123 @@
+package playbackmodule
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/mantonx/viewra/internal/database"
+	"github.com/mantonx/viewra/sdk/transcoding/ffmpeg"
+)
+
+// replayGainReference is the loudness ReplayGain 2.0 gains are relative to, in LUFS
+const replayGainReference = -18.0
+
+// GaplessInfo tells the client how much to trim from a track so album
+// playback joins tracks without gaps. Sample counts are at SampleRate.
+type GaplessInfo struct {
+	EncoderDelay       int  `json:"encoder_delay_samples"`   // Priming samples at the start
+	EncoderPadding     int  `json:"encoder_padding_samples"` // Padding samples at the end
+	SampleRate         int  `json:"sample_rate"`
+	HandledByContainer bool `json:"handled_by_container"` // The stream signals its own trimming; don't trim again
+}
+
+// TrackLoudness gives the gain needed to play a track at the target loudness,
+// so clients can level-match tracks when crossfading
+type TrackLoudness struct {
+	Source      string   `json:"source"` // audio_analysis or replaygain
+	TargetLUFS  float64  `json:"target_lufs"`
+	Integrated  float64  `json:"integrated_lufs"`
+	TruePeak    *float64 `json:"true_peak_dbtp,omitempty"`
+	Peak        *float64 `json:"peak,omitempty"`          // Linear sample peak from ReplayGain
+	TrackGainDB float64  `json:"track_gain_db"`           // Gain to reach the target
+	AlbumGainDB *float64 `json:"album_gain_db,omitempty"` // Gain that keeps the album's relative levels
+	Applied     bool     `json:"applied"`                 // The server already normalized the stream
+}
+
+// TrackPlaybackInfo is the gapless and loudness information of a track,
+// letting clients prepare the next track before the current one ends
+type TrackPlaybackInfo struct {
+	MediaFileID string         `json:"media_file_id"`
+	Duration    int            `json:"duration"`
+	Gapless     *GaplessInfo   `json:"gapless"`
+	Loudness    *TrackLoudness `json:"loudness,omitempty"`
+}
+
+// GetTrackPlaybackInfo returns the gapless and loudness information of a
+// media file as stored, for direct play
+func (m *Manager) GetTrackPlaybackInfo(mediaFileID string) (*TrackPlaybackInfo, error) {
+	var mediaFile database.MediaFile
+	if err := m.db.Where("id = ?", mediaFileID).First(&mediaFile).Error; err != nil {
+		return nil, fmt.Errorf("media file not found: %s", mediaFileID)
+	}
+
+	return &TrackPlaybackInfo{
+		MediaFileID: mediaFile.ID,
+		Duration:    mediaFile.Duration,
+		Gapless:     sourceGaplessInfo(&mediaFile),
+		Loudness:    m.trackLoudness(&mediaFile),
+	}, nil
+}
+
+// sourceGaplessInfo returns the delay and padding recorded for the file at scan time
+func sourceGaplessInfo(mediaFile *database.MediaFile) *GaplessInfo {
+	return &GaplessInfo{
+		EncoderDelay:   mediaFile.EncoderDelay,
+		EncoderPadding: mediaFile.EncoderPadding,
+		SampleRate:     mediaFile.SampleRate,
+	}
+}
+
+// transcodeGaplessInfo describes a gapless transcode. The output container
+// carries the new encoder's priming and padding, and decoders trim them.
+func transcodeGaplessInfo(codec string, sourceRate int) *GaplessInfo {
+	rate := sourceRate
+	if strings.EqualFold(codec, "opus") {
+		rate = 48000
+	}
+	return &GaplessInfo{SampleRate: rate, HandledByContainer: true}
+}
+
+// trackLoudness reports the gain that brings a track to the configured
+// target. Measurements from the audio analysis plugin are preferred over
+// ReplayGain tags. Returns nil when neither is available.
+func (m *Manager) trackLoudness(mediaFile *database.MediaFile) *TrackLoudness {
+	target := m.config.LoudnessTarget
+	if target == 0 {
+		target = ffmpeg.DefaultLoudnessTarget
+	}
+
+	var albumGain *float64
+	if mediaFile.AlbumGain != nil {
+		gain := target - replayGainReference + *mediaFile.AlbumGain
+		albumGain = &gain
+	}
+
+	measurement, err := m.getLoudnessMeasurement(mediaFile.MediaID)
+	if err != nil {
+		m.logger.Warn("failed to read stored loudness measurement", "media_id", mediaFile.MediaID, "error", err)
+	}
+	if measurement != nil {
+		truePeak := measurement.TruePeak
+		return &TrackLoudness{
+			Source:      audioAnalysisPlugin,
+			TargetLUFS:  target,
+			Integrated:  measurement.Integrated,
+			TruePeak:    &truePeak,
+			TrackGainDB: target - measurement.Integrated,
+			AlbumGainDB: albumGain,
+		}
+	}
+
+	if mediaFile.ReplayGain == nil {
+		return nil
+	}
+	integrated := replayGainReference - *mediaFile.ReplayGain
+	return &TrackLoudness{
+		Source:      "replaygain",
+		TargetLUFS:  target,
+		Integrated:  integrated,
+		Peak:        mediaFile.ReplayGainPeak,
+		TrackGainDB: target - integrated,
+		AlbumGainDB: albumGain,
+	}
+}
@@ -0,0 +1,590 @@
+package playbackmodule
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/mantonx/viewra/internal/database"
+	"gorm.io/gorm"
+)
+
+// Watch history export formats
+const (
+	HistoryFormatJSON  = "json"
+	HistoryFormatCSV   = "csv"
+	HistoryFormatTrakt = "trakt"
+)
+
+// History entry kinds
+const (
+	HistoryKindWatch  = "watch"
+	HistoryKindRating = "rating"
+)
+
+const (
+	// maxHistoryImportBytes bounds the size of an uploaded history file
+	maxHistoryImportBytes = 50 << 20
+	// maxUnmatchedReported caps the unmatched titles listed in an import result
+	maxUnmatchedReported = 100
+)
+
+// historyCSVHeader is the column order of CSV exports; imports match columns by name
+var historyCSVHeader = []string{
+	"kind", "media_type", "media_id", "title", "year", "show_title", "show_tmdb_id",
+	"season", "episode", "tmdb_id", "imdb_id", "date", "watched_seconds", "completed", "rating",
+}
+
+// HistoryEntry is a watch or rating in portable form. External IDs and
+// titles allow matching against another library when media IDs don't exist.
+type HistoryEntry struct {
+	Kind           string    `json:"kind"`
+	MediaID        string    `json:"media_id,omitempty"`
+	MediaType      string    `json:"media_type"`
+	Title          string    `json:"title"`
+	Year           int       `json:"year,omitempty"`
+	ShowTitle      string    `json:"show_title,omitempty"`
+	ShowTmdbID     string    `json:"show_tmdb_id,omitempty"`
+	Season         int       `json:"season,omitempty"`
+	Episode        int       `json:"episode,omitempty"`
+	TmdbID         string    `json:"tmdb_id,omitempty"`
+	ImdbID         string    `json:"imdb_id,omitempty"`
+	Date           time.Time `json:"date"` // Watched or rated time
+	WatchedSeconds float64   `json:"watched_seconds,omitempty"`
+	Completed      bool      `json:"completed,omitempty"`
+	Rating         int       `json:"rating,omitempty"`
+}
+
+// HistoryExport is a user's full watch history and ratings
+type HistoryExport struct {
+	UserID     uint32         `json:"user_id"`
+	ExportedAt time.Time      `json:"exported_at"`
+	History    []HistoryEntry `json:"history"`
+	Ratings    []HistoryEntry `json:"ratings"`
+}
+
+// HistoryImportResult summarizes an import
+type HistoryImportResult struct {
+	Watches   int      `json:"watches"`
+	Ratings   int      `json:"ratings"`
+	Duplicate int      `json:"duplicate"`
+	Unmatched []string `json:"unmatched"`
+}
+
+// ExportHistory collects a user's watch history and ratings
+func (m *Manager) ExportHistory(userID uint32) (*HistoryExport, error) {
+	if m.db == nil {
+		return nil, fmt.Errorf("database not available")
+	}
+
+	export := &HistoryExport{
+		UserID:     userID,
+		ExportedAt: time.Now().UTC(),
+		History:    []HistoryEntry{},
+		Ratings:    []HistoryEntry{},
+	}
+	described := make(map[string]HistoryEntry)
+
+	var sessions []database.PlaybackSession
+	if err := m.db.Where("user_id = ?", userID).Order("started_at").Find(&sessions).Error; err != nil {
+		return nil, fmt.Errorf("failed to load watch history: %w", err)
+	}
+	for _, session := range sessions {
+		entry := m.describeHistoryMedia(described, session.MediaID, session.MediaType)
+		entry.Kind = HistoryKindWatch
+		entry.Date = session.StartedAt.UTC()
+		entry.WatchedSeconds = session.WatchedSeconds
+		entry.Completed = session.Completed
+		export.History = append(export.History, entry)
+	}
+
+	var ratings []database.UserRating
+	if err := m.db.Where("user_id = ?", userID).Order("rated_at").Find(&ratings).Error; err != nil {
+		return nil, fmt.Errorf("failed to load ratings: %w", err)
+	}
+	for _, rating := range ratings {
+		entry := m.describeHistoryMedia(described, rating.MediaID, string(rating.MediaType))
+		entry.Kind = HistoryKindRating
+		entry.Date = rating.RatedAt.UTC()
+		entry.Rating = rating.Rating
+		export.Ratings = append(export.Ratings, entry)
+	}
+
+	return export, nil
+}
+
+// describeHistoryMedia fills titles and external IDs for a media item, caching by ID
+func (m *Manager) describeHistoryMedia(cache map[string]HistoryEntry, mediaID, mediaType string) HistoryEntry {
+	if entry, ok := cache[mediaID]; ok {
+		return entry
+	}
+
+	entry := HistoryEntry{MediaID: mediaID, MediaType: mediaType}
+	switch database.MediaType(mediaType) {
+	case database.MediaTypeMovie:
+		var movie database.Movie
+		if err := m.db.Select("id, title, release_date, tmdb_id, imdb_id").Where("id = ?", mediaID).First(&movie).Error; err == nil {
+			entry.Title = movie.Title
+			entry.TmdbID = movie.TmdbID
+			entry.ImdbID = movie.ImdbID
+			if movie.ReleaseDate != nil {
+				entry.Year = movie.ReleaseDate.Year()
+			}
+		}
+	case database.MediaTypeEpisode:
+		var episode database.Episode
+		if err := m.db.Preload("Season.TVShow").Where("id = ?", mediaID).First(&episode).Error; err == nil {
+			entry.Title = episode.Title
+			entry.Episode = episode.EpisodeNumber
+			entry.Season = episode.Season.SeasonNumber
+			entry.ShowTitle = episode.Season.TVShow.Title
+			entry.ShowTmdbID = episode.Season.TVShow.TmdbID
+			if episode.Season.TVShow.FirstAirDate != nil {
+				entry.Year = episode.Season.TVShow.FirstAirDate.Year()
+			}
+		}
+	case database.MediaTypeTrack:
+		var track database.Track
+		if err := m.db.Select("id, title").Where("id = ?", mediaID).First(&track).Error; err == nil {
+			entry.Title = track.Title
+		}
+	}
+
+	cache[mediaID] = entry
+	return entry
+}
+
+// ImportHistory adds watches and ratings to a user's history. Entries are
+// matched by media ID, then TMDb/IMDb IDs, then title; watches already
+// recorded at the same time are skipped so repeated imports are harmless.
+func (m *Manager) ImportHistory(userID uint32, entries []HistoryEntry) (*HistoryImportResult, error) {
+	if m.db == nil {
+		return nil, fmt.Errorf("database not available")
+	}
+
+	result := &HistoryImportResult{Unmatched: []string{}}
+	for _, entry := range entries {
+		mediaID, ok := m.resolveHistoryMedia(entry)
+		if !ok {
+			if len(result.Unmatched) < maxUnmatchedReported {
+				result.Unmatched = append(result.Unmatched, historyEntryLabel(entry))
+			}
+			continue
+		}
+
+		switch entry.Kind {
+		case HistoryKindRating:
+			if entry.Rating < 1 || entry.Rating > 10 {
+				continue
+			}
+			rating := database.UserRating{
+				UserID:    userID,
+				MediaID:   mediaID,
+				MediaType: database.MediaType(entry.MediaType),
+				Rating:    entry.Rating,
+				RatedAt:   historyEntryDate(entry),
+			}
+			if err := m.db.Save(&rating).Error; err != nil {
+				return nil, fmt.Errorf("failed to import rating: %w", err)
+			}
+			result.Ratings++
+
+		default:
+			watchedAt := historyEntryDate(entry)
+
+			var existing int64
+			m.db.Model(&database.PlaybackSession{}).
+				Where("user_id = ? AND media_id = ? AND started_at = ?", userID, mediaID, watchedAt).
+				Count(&existing)
+			if existing > 0 {
+				result.Duplicate++
+				continue
+			}
+
+			var mediaFile database.MediaFile
+			m.db.Select("id").Where("media_id = ?", mediaID).Limit(1).Find(&mediaFile)
+
+			session := database.PlaybackSession{
+				ID:             uuid.New().String(),
+				UserID:         userID,
+				MediaFileID:    mediaFile.ID,
+				MediaID:        mediaID,
+				MediaType:      entry.MediaType,
+				Method:         PlaybackMethodImported,
+				WatchedSeconds: entry.WatchedSeconds,
+				Completed:      entry.Completed,
+				StartedAt:      watchedAt,
+				LastSeenAt:     watchedAt,
+				EndedAt:        &watchedAt,
+			}
+			if err := m.db.Create(&session).Error; err != nil {
+				return nil, fmt.Errorf("failed to import watch: %w", err)
+			}
+			result.Watches++
+		}
+	}
+
+	m.logger.Info("imported watch history",
+		"user_id", userID,
+		"watches", result.Watches,
+		"ratings", result.Ratings,
+		"duplicate", result.Duplicate,
+		"unmatched", len(result.Unmatched))
+
+	return result, nil
+}
+
+// resolveHistoryMedia finds the local media ID for an imported entry
+func (m *Manager) resolveHistoryMedia(entry HistoryEntry) (string, bool) {
+	switch database.MediaType(entry.MediaType) {
+	case database.MediaTypeMovie:
+		query := m.db.Model(&database.Movie{}).Select("id")
+		switch {
+		case entry.MediaID != "":
+			query = query.Where("id = ?", entry.MediaID)
+		case entry.TmdbID != "":
+			query = query.Where("tmdb_id = ?", entry.TmdbID)
+		case entry.ImdbID != "":
+			query = query.Where("imdb_id = ?", entry.ImdbID)
+		case entry.Title != "" && entry.Year > 0:
+			// Title alone is too ambiguous for remakes; require the year
+			query = query.Where("LOWER(title) = LOWER(?) AND release_date >= ? AND release_date < ?",
+				entry.Title,
+				time.Date(entry.Year, 1, 1, 0, 0, 0, 0, time.UTC),
+				time.Date(entry.Year+1, 1, 1, 0, 0, 0, 0, time.UTC))
+		default:
+			return "", false
+		}
+		return firstID(query)
+
+	case database.MediaTypeEpisode:
+		if entry.MediaID != "" {
+			return firstID(m.db.Model(&database.Episode{}).Select("id").Where("id = ?", entry.MediaID))
+		}
+		if entry.Season <= 0 || entry.Episode <= 0 {
+			return "", false
+		}
+		query := m.db.Table("episodes").Select("episodes.id").
+			Joins("JOIN seasons ON seasons.id = episodes.season_id").
+			Joins("JOIN tv_shows ON tv_shows.id = seasons.tv_show_id").
+			Where("seasons.season_number = ? AND episodes.episode_number = ?", entry.Season, entry.Episode)
+		switch {
+		case entry.ShowTmdbID != "":
+			query = query.Where("tv_shows.tmdb_id = ?", entry.ShowTmdbID)
+		case entry.ShowTitle != "":
+			query = query.Where("LOWER(tv_shows.title) = LOWER(?)", entry.ShowTitle)
+		default:
+			return "", false
+		}
+		return firstID(query)
+
+	case database.MediaTypeTrack:
+		if entry.MediaID == "" {
+			return "", false
+		}
+		return firstID(m.db.Model(&database.Track{}).Select("id").Where("id = ?", entry.MediaID))
+	}
+
+	return "", false
+}
+
+// firstID returns the id column of the first row a query matches
+func firstID(query *gorm.DB) (string, bool) {
+	var ids []string
+	if err := query.Limit(1).Pluck("id", &ids).Error; err != nil || len(ids) == 0 {
+		return "", false
+	}
+	return ids[0], true
+}
+
+// historyEntryDate returns the entry's date, defaulting to now for undated entries
+func historyEntryDate(entry HistoryEntry) time.Time {
+	if entry.Date.IsZero() {
+		return time.Now().UTC()
+	}
+	return entry.Date.UTC()
+}
+
+// historyEntryLabel describes an entry for the unmatched list
+func historyEntryLabel(entry HistoryEntry) string {
+	if entry.ShowTitle != "" {
+		return fmt.Sprintf("%s S%02dE%02d", entry.ShowTitle, entry.Season, entry.Episode)
+	}
+	if entry.Year > 0 {
+		return fmt.Sprintf("%s (%d)", entry.Title, entry.Year)
+	}
+	if entry.Title != "" {
+		return entry.Title
+	}
+	return entry.MediaID
+}
+
+// writeHistoryCSV writes history and ratings as a single CSV, one row per entry
+func writeHistoryCSV(w io.Writer, export *HistoryExport) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(historyCSVHeader); err != nil {
+		return err
+	}
+
+	entries := append(append([]HistoryEntry{}, export.History...), export.Ratings...)
+	for _, e := range entries {
+		record := []string{
+			e.Kind, e.MediaType, e.MediaID, e.Title, formatOptionalInt(e.Year), e.ShowTitle, e.ShowTmdbID,
+			formatOptionalInt(e.Season), formatOptionalInt(e.Episode), e.TmdbID, e.ImdbID,
+			e.Date.Format(time.RFC3339), strconv.FormatFloat(e.WatchedSeconds, 'f', 0, 64),
+			strconv.FormatBool(e.Completed), formatOptionalInt(e.Rating),
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// readHistoryCSV parses a CSV in the export layout; columns are matched by header name
+func readHistoryCSV(r io.Reader) ([]HistoryEntry, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CSV header: %w", err)
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	if _, ok := columns["media_type"]; !ok {
+		return nil, fmt.Errorf("CSV is missing the media_type column")
+	}
+
+	var entries []HistoryEntry
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CSV: %w", err)
+		}
+
+		field := func(name string) string {
+			if i, ok := columns[name]; ok && i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+		number := func(name string) int {
+			n, _ := strconv.Atoi(field(name))
+			return n
+		}
+
+		entry := HistoryEntry{
+			Kind:       field("kind"),
+			MediaType:  field("media_type"),
+			MediaID:    field("media_id"),
+			Title:      field("title"),
+			Year:       number("year"),
+			ShowTitle:  field("show_title"),
+			ShowTmdbID: field("show_tmdb_id"),
+			Season:     number("season"),
+			Episode:    number("episode"),
+			TmdbID:     field("tmdb_id"),
+			ImdbID:     field("imdb_id"),
+			Rating:     number("rating"),
+		}
+		entry.Date, _ = time.Parse(time.RFC3339, field("date"))
+		entry.WatchedSeconds, _ = strconv.ParseFloat(field("watched_seconds"), 64)
+		entry.Completed, _ = strconv.ParseBool(field("completed"))
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
+
+// formatOptionalInt leaves zero values blank in CSV output
+func formatOptionalInt(n int) string {
+	if n == 0 {
+		return ""
+	}
+	return strconv.Itoa(n)
+}
+
+// parseHistoryUserID reads the userId path parameter
+func parseHistoryUserID(c *gin.Context) (uint32, bool) {
+	userID, err := strconv.ParseUint(c.Param("userId"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user ID"})
+		return 0, false
+	}
+	return uint32(userID), true
+}
+
+// HandleExportHistory exports a user's watch history and ratings
+func (h *APIHandler) HandleExportHistory(c *gin.Context) {
+	userID, ok := parseHistoryUserID(c)
+	if !ok {
+		return
+	}
+
+	export, err := h.manager.ExportHistory(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	format := c.DefaultQuery("format", HistoryFormatJSON)
+	filename := fmt.Sprintf("viewra-history-%d-%s", userID, export.ExportedAt.Format("20060102"))
+
+	switch format {
+	case HistoryFormatJSON:
+		c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename+".json"))
+		c.JSON(http.StatusOK, export)
+	case HistoryFormatCSV:
+		var buf bytes.Buffer
+		if err := writeHistoryCSV(&buf, export); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename+".csv"))
+		c.Data(http.StatusOK, "text/csv; charset=utf-8", buf.Bytes())
+	case HistoryFormatTrakt:
+		c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename+"-trakt.json"))
+		c.JSON(http.StatusOK, toTraktExport(export))
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("unknown export format %q", format)})
+	}
+}
+
+// HandleImportHistory imports watch history and ratings from an uploaded file
+func (h *APIHandler) HandleImportHistory(c *gin.Context) {
+	userID, ok := parseHistoryUserID(c)
+	if !ok {
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(c.Request.Body, maxHistoryImportBytes))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "failed to read import body"})
+		return
+	}
+
+	var entries []HistoryEntry
+	format := c.DefaultQuery("format", HistoryFormatJSON)
+	switch format {
+	case HistoryFormatJSON:
+		var export HistoryExport
+		if err := json.Unmarshal(body, &export); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid JSON export: " + err.Error()})
+			return
+		}
+		entries = append(export.History, export.Ratings...)
+	case HistoryFormatCSV:
+		entries, err = readHistoryCSV(bytes.NewReader(body))
+	case HistoryFormatTrakt:
+		entries, err = parseTraktItems(body)
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("unknown import format %q", format)})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	result, err := h.manager.ImportHistory(userID, entries)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// HandleListRatings returns a user's ratings
+func (h *APIHandler) HandleListRatings(c *gin.Context) {
+	userID, ok := parseHistoryUserID(c)
+	if !ok {
+		return
+	}
+
+	var ratings []database.UserRating
+	if err := h.manager.db.Where("user_id = ?", userID).Order("rated_at desc").Find(&ratings).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"ratings": ratings})
+}
+
+// HandleSetRating rates a movie or episode on a 1-10 scale
+func (h *APIHandler) HandleSetRating(c *gin.Context) {
+	userID, ok := parseHistoryUserID(c)
+	if !ok {
+		return
+	}
+
+	var request struct {
+		MediaType string `json:"media_type" binding:"required,oneof=movie episode"`
+		Rating    int    `json:"rating" binding:"required,min=1,max=10"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	rating := database.UserRating{
+		UserID:    userID,
+		MediaID:   c.Param("mediaId"),
+		MediaType: database.MediaType(request.MediaType),
+		Rating:    request.Rating,
+		RatedAt:   time.Now().UTC(),
+	}
+	if err := h.manager.db.Save(&rating).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to save rating: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, rating)
+}
+
+// HandleDeleteRating removes a rating
+func (h *APIHandler) HandleDeleteRating(c *gin.Context) {
+	userID, ok := parseHistoryUserID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.manager.db.Delete(&database.UserRating{}, "user_id = ? AND media_id = ?", userID, c.Param("mediaId")).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"deleted": true})
+}
+
+// RegisterHistoryExportRoutes registers watch history export/import and rating endpoints
+func RegisterHistoryExportRoutes(api *gin.RouterGroup, handler *APIHandler) {
+	history := api.Group("/history/:userId")
+	{
+		history.GET("/export", handler.HandleExportHistory)
+		history.POST("/import", handler.HandleImportHistory)
+	}
+
+	ratings := api.Group("/ratings/:userId")
+	{
+		ratings.GET("", handler.HandleListRatings)
+		ratings.PUT("/:mediaId", handler.HandleSetRating)
+		ratings.DELETE("/:mediaId", handler.HandleDeleteRating)
+	}
+}
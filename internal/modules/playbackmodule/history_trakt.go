@@ -0,0 +1,221 @@
+package playbackmodule
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/mantonx/viewra/internal/database"
+)
+
+// traktIDs identifies an item to Trakt
+type traktIDs struct {
+	Tmdb int    `json:"tmdb,omitempty"`
+	Imdb string `json:"imdb,omitempty"`
+}
+
+// traktMovie is a movie in a Trakt sync request body
+type traktMovie struct {
+	Title     string   `json:"title"`
+	Year      int      `json:"year,omitempty"`
+	IDs       traktIDs `json:"ids"`
+	WatchedAt string   `json:"watched_at,omitempty"`
+	RatedAt   string   `json:"rated_at,omitempty"`
+	Rating    int      `json:"rating,omitempty"`
+}
+
+// traktEpisode is an episode nested under a show's season
+type traktEpisode struct {
+	Number    int    `json:"number"`
+	WatchedAt string `json:"watched_at,omitempty"`
+	RatedAt   string `json:"rated_at,omitempty"`
+	Rating    int    `json:"rating,omitempty"`
+}
+
+// traktSeason groups episodes of a show
+type traktSeason struct {
+	Number   int            `json:"number"`
+	Episodes []traktEpisode `json:"episodes"`
+}
+
+// traktShow is a show in a Trakt sync request body
+type traktShow struct {
+	Title   string        `json:"title"`
+	Year    int           `json:"year,omitempty"`
+	IDs     traktIDs      `json:"ids"`
+	Seasons []traktSeason `json:"seasons"`
+}
+
+// traktSync is the body of Trakt's POST /sync/history and /sync/ratings
+type traktSync struct {
+	Movies []traktMovie `json:"movies"`
+	Shows  []traktShow  `json:"shows"`
+}
+
+// TraktExport holds request bodies ready to post to Trakt's sync endpoints
+type TraktExport struct {
+	History traktSync `json:"history"`
+	Ratings traktSync `json:"ratings"`
+}
+
+// traktItem is an entry from Trakt's GET /sync/history or /sync/ratings
+// responses, which is also the layout of Trakt's own data exports
+type traktItem struct {
+	WatchedAt *time.Time `json:"watched_at"`
+	RatedAt   *time.Time `json:"rated_at"`
+	Rating    int        `json:"rating"`
+	Type      string     `json:"type"`
+	Movie     *struct {
+		Title string       `json:"title"`
+		Year  int          `json:"year"`
+		IDs   traktItemIDs `json:"ids"`
+	} `json:"movie"`
+	Show *struct {
+		Title string       `json:"title"`
+		Year  int          `json:"year"`
+		IDs   traktItemIDs `json:"ids"`
+	} `json:"show"`
+	Episode *struct {
+		Season int          `json:"season"`
+		Number int          `json:"number"`
+		Title  string       `json:"title"`
+		IDs    traktItemIDs `json:"ids"`
+	} `json:"episode"`
+}
+
+// traktItemIDs are the IDs Trakt returns; only TMDb and IMDb are used for matching
+type traktItemIDs struct {
+	Tmdb int    `json:"tmdb"`
+	Imdb string `json:"imdb"`
+}
+
+// toTraktExport converts an export into Trakt sync bodies. Trakt only tracks
+// movies and episodes, and history only counts completed watches.
+func toTraktExport(export *HistoryExport) *TraktExport {
+	return &TraktExport{
+		History: buildTraktSync(export.History, true),
+		Ratings: buildTraktSync(export.Ratings, false),
+	}
+}
+
+// buildTraktSync groups entries into Trakt's movies/shows layout
+func buildTraktSync(entries []HistoryEntry, completedOnly bool) traktSync {
+	sync := traktSync{Movies: []traktMovie{}, Shows: []traktShow{}}
+	shows := make(map[string]int)
+
+	for _, entry := range entries {
+		if completedOnly && !entry.Completed {
+			continue
+		}
+
+		var watchedAt, ratedAt string
+		if entry.Kind == HistoryKindRating {
+			ratedAt = entry.Date.Format(time.RFC3339)
+		} else {
+			watchedAt = entry.Date.Format(time.RFC3339)
+		}
+
+		switch database.MediaType(entry.MediaType) {
+		case database.MediaTypeMovie:
+			tmdb, _ := strconv.Atoi(entry.TmdbID)
+			sync.Movies = append(sync.Movies, traktMovie{
+				Title:     entry.Title,
+				Year:      entry.Year,
+				IDs:       traktIDs{Tmdb: tmdb, Imdb: entry.ImdbID},
+				WatchedAt: watchedAt,
+				RatedAt:   ratedAt,
+				Rating:    entry.Rating,
+			})
+
+		case database.MediaTypeEpisode:
+			if entry.ShowTitle == "" || entry.Season <= 0 || entry.Episode <= 0 {
+				continue
+			}
+			key := entry.ShowTmdbID + "|" + entry.ShowTitle
+			index, ok := shows[key]
+			if !ok {
+				tmdb, _ := strconv.Atoi(entry.ShowTmdbID)
+				sync.Shows = append(sync.Shows, traktShow{
+					Title: entry.ShowTitle,
+					Year:  entry.Year,
+					IDs:   traktIDs{Tmdb: tmdb},
+				})
+				index = len(sync.Shows) - 1
+				shows[key] = index
+			}
+			show := &sync.Shows[index]
+
+			seasonIndex := -1
+			for i := range show.Seasons {
+				if show.Seasons[i].Number == entry.Season {
+					seasonIndex = i
+					break
+				}
+			}
+			if seasonIndex < 0 {
+				show.Seasons = append(show.Seasons, traktSeason{Number: entry.Season})
+				seasonIndex = len(show.Seasons) - 1
+			}
+			show.Seasons[seasonIndex].Episodes = append(show.Seasons[seasonIndex].Episodes, traktEpisode{
+				Number:    entry.Episode,
+				WatchedAt: watchedAt,
+				RatedAt:   ratedAt,
+				Rating:    entry.Rating,
+			})
+		}
+	}
+
+	return sync
+}
+
+// parseTraktItems converts Trakt history and rating items into import entries.
+// Items with a rating become ratings; show and season ratings are skipped.
+func parseTraktItems(body []byte) ([]HistoryEntry, error) {
+	var items []traktItem
+	if err := json.Unmarshal(body, &items); err != nil {
+		return nil, fmt.Errorf("invalid Trakt export, expected a JSON array of history or rating items: %w", err)
+	}
+
+	entries := make([]HistoryEntry, 0, len(items))
+	for _, item := range items {
+		entry := HistoryEntry{Kind: HistoryKindWatch, Completed: true}
+		if item.Rating > 0 {
+			entry.Kind = HistoryKindRating
+			entry.Rating = item.Rating
+			entry.Completed = false
+			if item.RatedAt != nil {
+				entry.Date = *item.RatedAt
+			}
+		} else if item.WatchedAt != nil {
+			entry.Date = *item.WatchedAt
+		}
+
+		switch {
+		case item.Type == "movie" && item.Movie != nil:
+			entry.MediaType = string(database.MediaTypeMovie)
+			entry.Title = item.Movie.Title
+			entry.Year = item.Movie.Year
+			entry.ImdbID = item.Movie.IDs.Imdb
+			if item.Movie.IDs.Tmdb > 0 {
+				entry.TmdbID = strconv.Itoa(item.Movie.IDs.Tmdb)
+			}
+		case item.Type == "episode" && item.Episode != nil && item.Show != nil:
+			entry.MediaType = string(database.MediaTypeEpisode)
+			entry.Title = item.Episode.Title
+			entry.Season = item.Episode.Season
+			entry.Episode = item.Episode.Number
+			entry.ShowTitle = item.Show.Title
+			entry.Year = item.Show.Year
+			if item.Show.IDs.Tmdb > 0 {
+				entry.ShowTmdbID = strconv.Itoa(item.Show.IDs.Tmdb)
+			}
+		default:
+			continue
+		}
+
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
@@ -0,0 +1,164 @@
+package playbackmodule
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mantonx/viewra/internal/database"
+	"gorm.io/gorm"
+)
+
+// libraryProfileCodecs are the codecs a library profile may prefer
+var libraryProfileCodecs = map[string]bool{"h264": true, "hevc": true, "av1": true, "vp9": true}
+
+// libraryProfileResolutions are the resolution caps a library profile may set
+var libraryProfileResolutions = map[string]bool{"480p": true, "720p": true, "1080p": true, "1440p": true, "2160p": true}
+
+// libraryProfileStore loads library transcode profiles from the database
+type libraryProfileStore struct {
+	db *gorm.DB
+}
+
+// LibraryProfileForPath returns the transcode profile of the library containing
+// the media file, or nil when the file is unknown or no profile is assigned.
+// Optimize jobs use the same lookup, skipping libraries that keep originals.
+func (s *libraryProfileStore) LibraryProfileForPath(mediaPath string) *database.LibraryTranscodeProfile {
+	var mediaFile database.MediaFile
+	if err := s.db.Select("library_id").Where("path = ?", mediaPath).First(&mediaFile).Error; err != nil {
+		return nil
+	}
+	return s.get(mediaFile.LibraryID)
+}
+
+// get returns a library's profile, or nil if none is assigned
+func (s *libraryProfileStore) get(libraryID uint32) *database.LibraryTranscodeProfile {
+	var profiles []database.LibraryTranscodeProfile
+	if err := s.db.Where("library_id = ?", libraryID).Limit(1).Find(&profiles).Error; err != nil || len(profiles) == 0 {
+		return nil
+	}
+	return &profiles[0]
+}
+
+// LibraryProfileForPath returns the transcode profile that applies to a media file
+func (m *Manager) LibraryProfileForPath(mediaPath string) *database.LibraryTranscodeProfile {
+	if m.db == nil {
+		return nil
+	}
+	return (&libraryProfileStore{db: m.db}).LibraryProfileForPath(mediaPath)
+}
+
+// SetLibraryProfile validates and stores a library's default transcode profile
+func (m *Manager) SetLibraryProfile(profile *database.LibraryTranscodeProfile) error {
+	profile.VideoCodec = strings.ToLower(strings.TrimSpace(profile.VideoCodec))
+	if profile.VideoCodec == "h265" {
+		profile.VideoCodec = "hevc"
+	}
+	profile.MaxResolution = strings.ToLower(strings.TrimSpace(profile.MaxResolution))
+
+	if profile.VideoCodec != "" && !libraryProfileCodecs[profile.VideoCodec] {
+		return fmt.Errorf("unsupported video codec %q", profile.VideoCodec)
+	}
+	if profile.MaxResolution != "" && !libraryProfileResolutions[profile.MaxResolution] {
+		return fmt.Errorf("unsupported max resolution %q", profile.MaxResolution)
+	}
+	if profile.Quality < 0 || profile.Quality > 100 {
+		return fmt.Errorf("quality must be between 0 and 100")
+	}
+	if profile.TenBit && profile.VideoCodec == "h264" {
+		return fmt.Errorf("10-bit output requires hevc, av1 or vp9")
+	}
+
+	var library database.MediaLibrary
+	if err := m.db.First(&library, profile.LibraryID).Error; err != nil {
+		return fmt.Errorf("library not found: %d", profile.LibraryID)
+	}
+
+	if err := m.db.Save(profile).Error; err != nil {
+		return fmt.Errorf("failed to save library profile: %w", err)
+	}
+
+	m.logger.Info("updated library transcode profile",
+		"library_id", profile.LibraryID,
+		"name", profile.Name,
+		"codec", profile.VideoCodec,
+		"keep_original", profile.KeepOriginal)
+
+	return nil
+}
+
+// parseLibraryID reads the libraryId path parameter
+func parseLibraryID(c *gin.Context) (uint32, bool) {
+	libraryID, err := strconv.ParseUint(c.Param("libraryId"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid library ID"})
+		return 0, false
+	}
+	return uint32(libraryID), true
+}
+
+// HandleListLibraryProfiles returns all assigned library profiles
+func (h *APIHandler) HandleListLibraryProfiles(c *gin.Context) {
+	var profiles []database.LibraryTranscodeProfile
+	if err := h.manager.db.Order("library_id").Find(&profiles).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"profiles": profiles})
+}
+
+// HandleGetLibraryProfile returns a library's transcode profile
+func (h *APIHandler) HandleGetLibraryProfile(c *gin.Context) {
+	libraryID, ok := parseLibraryID(c)
+	if !ok {
+		return
+	}
+
+	profile := (&libraryProfileStore{db: h.manager.db}).get(libraryID)
+	if profile == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "no profile assigned to library"})
+		return
+	}
+
+	c.JSON(http.StatusOK, profile)
+}
+
+// HandleSetLibraryProfile assigns a library's transcode profile
+func (h *APIHandler) HandleSetLibraryProfile(c *gin.Context) {
+	libraryID, ok := parseLibraryID(c)
+	if !ok {
+		return
+	}
+
+	var profile database.LibraryTranscodeProfile
+	if err := c.ShouldBindJSON(&profile); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	profile.LibraryID = libraryID
+	if err := h.manager.SetLibraryProfile(&profile); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, profile)
+}
+
+// HandleDeleteLibraryProfile removes a library's profile, restoring planner defaults
+func (h *APIHandler) HandleDeleteLibraryProfile(c *gin.Context) {
+	libraryID, ok := parseLibraryID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.manager.db.Delete(&database.LibraryTranscodeProfile{}, "library_id = ?", libraryID).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"library_id": libraryID, "deleted": true})
+}
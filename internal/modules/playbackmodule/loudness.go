@@ -0,0 +1,90 @@
+package playbackmodule
+
+import (
+	"encoding/json"
+
+	"github.com/mantonx/viewra/internal/database"
+	plugins "github.com/mantonx/viewra/sdk"
+)
+
+// audioAnalysisPlugin is the enrichment source name used by the audio analysis
+// plugin when it stores EBU R128 measurements for a media item
+const audioAnalysisPlugin = "audio_analysis"
+
+// applyLoudnessNormalization adds loudness normalization to the request when it
+// is enabled. Stored measurements from the audio analysis plugin are used when
+// available so FFmpeg can apply a single linear gain instead of dynamic leveling.
+func (m *Manager) applyLoudnessNormalization(request *plugins.TranscodeRequest) {
+	if !m.config.LoudnessNormalization || request.Loudness != nil {
+		return
+	}
+
+	request.Loudness = &plugins.LoudnessSettings{
+		TargetIntegrated: m.config.LoudnessTarget,
+		TargetTruePeak:   m.config.LoudnessTruePeak,
+		TargetRange:      m.config.LoudnessRange,
+	}
+
+	if m.db == nil {
+		return
+	}
+
+	var mediaFile database.MediaFile
+	if err := m.db.Where("path = ?", request.InputPath).First(&mediaFile).Error; err != nil {
+		return
+	}
+
+	measurement, err := m.getLoudnessMeasurement(mediaFile.MediaID)
+	if err != nil {
+		m.logger.Warn("failed to read stored loudness measurement", "media_id", mediaFile.MediaID, "error", err)
+		return
+	}
+	if measurement != nil {
+		request.Loudness.Measured = measurement
+		m.logger.Debug("using stored loudness measurement",
+			"media_id", mediaFile.MediaID,
+			"integrated", measurement.Integrated,
+			"true_peak", measurement.TruePeak)
+	}
+}
+
+// getLoudnessMeasurement loads the audio analysis plugin's loudness values for a
+// media item. Returns nil without error when no measurement has been stored.
+func (m *Manager) getLoudnessMeasurement(mediaID string) (*plugins.LoudnessMeasurement, error) {
+	var enrichments []database.MediaEnrichment
+	if err := m.db.Where("media_id = ? AND plugin = ?", mediaID, audioAnalysisPlugin).
+		Limit(1).Find(&enrichments).Error; err != nil {
+		return nil, err
+	}
+	if len(enrichments) == 0 {
+		return nil, nil
+	}
+
+	var payload struct {
+		Fields map[string]interface{} `json:"fields"`
+	}
+	if err := json.Unmarshal([]byte(enrichments[0].Payload), &payload); err != nil {
+		return nil, err
+	}
+
+	integrated, ok := payload.Fields["loudness_integrated"].(float64)
+	if !ok {
+		return nil, nil
+	}
+
+	measurement := &plugins.LoudnessMeasurement{Integrated: integrated}
+	if v, ok := payload.Fields["loudness_true_peak"].(float64); ok {
+		measurement.TruePeak = v
+	}
+	if v, ok := payload.Fields["loudness_range"].(float64); ok {
+		measurement.Range = v
+	}
+	if v, ok := payload.Fields["loudness_threshold"].(float64); ok {
+		measurement.Threshold = v
+	}
+	if v, ok := payload.Fields["loudness_target_offset"].(float64); ok {
+		measurement.TargetOffset = v
+	}
+
+	return measurement, nil
+}
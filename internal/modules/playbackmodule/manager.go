@@ -3,6 +3,7 @@ package playbackmodule
 import (
 	"context"
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/hashicorp/go-hclog"
@@ -35,6 +36,12 @@ type Manager struct {
 	// Plugin integration
 	pluginManager PluginManagerInterface
 
+	// Detected scan type per file path, so telecine analysis runs once per file
+	// and off the playback path
+	scanTypes sync.Map
+	// Probed chapters per file path, for playback info
+	chapters sync.Map
+
 	// Configuration
 	config      config.TranscodingConfig
 	enabled     bool
@@ -72,6 +79,7 @@ func NewManager(db *gorm.DB, eventBus events.EventBus, pluginManager PluginManag
 		MaxTotalSizeGB:     cfg.Transcoding.MaxDiskUsageGB,
 		CleanupInterval:    cfg.Transcoding.CleanupInterval,
 		LargeFileThreshold: cfg.Transcoding.LargeFileThreshold * 1024 * 1024,
+		PopularCacheHits:   cfg.Transcoding.CachePopularHits,
 	}
 
 	cleanupService := core.NewCleanupService(cleanupConfig, sessionStore, fileManager, logger.Named("cleanup-service"))
@@ -82,6 +90,15 @@ func NewManager(db *gorm.DB, eventBus events.EventBus, pluginManager PluginManag
 	// Create media validator
 	mediaValidator := NewStandardMediaValidator(logger.Named("media-validator"))
 
+	// Create playback planner, negotiating codecs against the registered encoders
+	planner := &PlaybackPlannerImpl{mediaAnalyzer: NewFFProbeMediaAnalyzer()}
+	if transcodingService != nil {
+		planner.SetEncoderCapabilities(transcodingService.GetProviderManager())
+	}
+	if db != nil {
+		planner.SetLibraryProfiles(&libraryProfileStore{db: db})
+	}
+
 	return &Manager{
 		logger:      logger,
 		db:          db,
@@ -93,7 +110,7 @@ func NewManager(db *gorm.DB, eventBus events.EventBus, pluginManager PluginManag
 		initialized: false,
 
 		// Core services  
-		planner:            NewPlaybackPlanner(NewFFProbeMediaAnalyzer()),
+		planner:            planner,
 		transcodingService: transcodingService,
 		cleanupService:     cleanupService,
 		fileManager:        fileManager,
@@ -147,7 +164,7 @@ func (m *Manager) Shutdown() error {
 	if err == nil {
 		for _, session := range sessions {
 			if session.Status == "running" || session.Status == "queued" {
-				_ = m.StopSession(session.ID)
+				_ = m.stopSessionForAll(session.ID)
 			}
 		}
 	}
@@ -187,7 +204,18 @@ func (m *Manager) DecidePlayback(mediaPath string, deviceProfile *DeviceProfile)
 			"warnings", validation.Warnings)
 	}
 
-	return m.planner.DecidePlayback(mediaPath, deviceProfile)
+	// A registered device stands in for the capabilities it reported
+	m.applyRegisteredDevice(deviceProfile)
+
+	decision, err := m.planner.DecidePlayback(mediaPath, deviceProfile)
+	if err != nil {
+		return nil, err
+	}
+
+	// Honor forced/default dispositions and the user's language preferences
+	m.applyTrackSelection(decision, mediaPath, deviceProfile)
+
+	return decision, nil
 }
 
 // StartTranscode initiates a new transcoding session with error recovery
@@ -261,6 +289,15 @@ func (m *Manager) StartTranscode(request *plugins.TranscodeRequest) (*database.T
 		}
 	}
 	
+	// Normalize loudness when enabled, using stored measurements if available
+	m.applyLoudnessNormalization(request)
+
+	// Deinterlace or inverse telecine based on the per-item override or probe data
+	m.applyDeinterlacing(request)
+
+	// Short opening segments cut time to first frame
+	request.FastStart = m.config.FastStart
+
 	// Execute transcoding with error recovery and fallback
 	var session *database.TranscodeSession
 	
@@ -299,6 +336,8 @@ func (m *Manager) StartTranscodeFromMediaFile(mediaFileID string, container stri
 
 	m.logger.Info("found media file", "path", mediaFile.Path, "container", mediaFile.Container)
 
+	m.applyRegisteredDevice(deviceProfile)
+
 	// Use playback planner to make intelligent decisions
 	decision, err := m.planner.DecidePlayback(mediaFile.Path, deviceProfile)
 	if err != nil {
@@ -306,6 +345,8 @@ func (m *Manager) StartTranscodeFromMediaFile(mediaFileID string, container stri
 		return nil, fmt.Errorf("failed to make playback decision: %w", err)
 	}
 
+	m.applyTrackSelection(decision, mediaFile.Path, deviceProfile)
+
 	// Check if transcoding is even needed
 	if !decision.ShouldTranscode {
 		m.logger.Info("direct play recommended", "reason", decision.Reason)
@@ -355,6 +396,19 @@ func (m *Manager) StopSession(sessionID string) error {
 	return m.transcodingService.StopTranscode(sessionID)
 }
 
+// stopSessionForAll stops a session for every viewer sharing it
+func (m *Manager) stopSessionForAll(sessionID string) error {
+	if !m.initialized {
+		return fmt.Errorf("playback manager not initialized")
+	}
+
+	if m.transcodingService == nil {
+		return fmt.Errorf("transcoding service not available")
+	}
+
+	return m.transcodingService.ForceStopTranscode(sessionID)
+}
+
 // GetSession retrieves session information
 func (m *Manager) GetSession(sessionID string) (*database.TranscodeSession, error) {
 	if !m.initialized {
@@ -460,7 +514,7 @@ func (m *Manager) SetEnabled(enabled bool) {
 		if err == nil {
 			for _, session := range sessions {
 				if session.Status == "running" || session.Status == "queued" {
-					_ = m.StopSession(session.ID)
+					_ = m.stopSessionForAll(session.ID)
 				}
 			}
 		}
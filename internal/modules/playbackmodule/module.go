@@ -80,6 +80,34 @@ func (m *Module) Migrate(db *gorm.DB) error {
 		return fmt.Errorf("failed to migrate TranscodeSession: %w", err)
 	}
 
+	if err := db.AutoMigrate(&database.UserPlaybackPreferences{}); err != nil {
+		return fmt.Errorf("failed to migrate UserPlaybackPreferences: %w", err)
+	}
+
+	if err := db.AutoMigrate(&database.LibraryTranscodeProfile{}); err != nil {
+		return fmt.Errorf("failed to migrate LibraryTranscodeProfile: %w", err)
+	}
+
+	if err := db.AutoMigrate(&database.ClientDevice{}); err != nil {
+		return fmt.Errorf("failed to migrate ClientDevice: %w", err)
+	}
+
+	if err := db.AutoMigrate(&database.PlaybackSession{}); err != nil {
+		return fmt.Errorf("failed to migrate PlaybackSession: %w", err)
+	}
+
+	if err := db.AutoMigrate(&database.UserRating{}); err != nil {
+		return fmt.Errorf("failed to migrate UserRating: %w", err)
+	}
+
+	if err := db.AutoMigrate(&database.MediaMarker{}); err != nil {
+		return fmt.Errorf("failed to migrate MediaMarker: %w", err)
+	}
+
+	if err := db.AutoMigrate(&database.PlayQueue{}, &database.PlayQueueItem{}); err != nil {
+		return fmt.Errorf("failed to migrate PlayQueue: %w", err)
+	}
+
 	// Any other playback-related models
 
 	return nil
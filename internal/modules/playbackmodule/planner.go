@@ -4,12 +4,25 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/mantonx/viewra/internal/database"
 	plugins "github.com/mantonx/viewra/sdk"
 )
 
+// EncoderCapabilities reports which video codecs the transcoding backend can encode
+type EncoderCapabilities interface {
+	SupportsEncoding(codec string) bool
+}
+
+// LibraryProfileSource resolves the transcode profile of the library a file belongs to
+type LibraryProfileSource interface {
+	LibraryProfileForPath(mediaPath string) *database.LibraryTranscodeProfile
+}
+
 // PlaybackPlannerImpl implements the PlaybackPlanner interface
 type PlaybackPlannerImpl struct {
-	mediaAnalyzer MediaAnalyzer
+	mediaAnalyzer   MediaAnalyzer
+	encoders        EncoderCapabilities
+	libraryProfiles LibraryProfileSource
 }
 
 // NewPlaybackPlanner creates a new playback planner with media analyzer
@@ -19,6 +32,25 @@ func NewPlaybackPlanner(mediaAnalyzer MediaAnalyzer) PlaybackPlanner {
 	}
 }
 
+// SetEncoderCapabilities sets the encoder capability source used for codec negotiation.
+// Without one, only H.264 is selected for transcodes.
+func (p *PlaybackPlannerImpl) SetEncoderCapabilities(encoders EncoderCapabilities) {
+	p.encoders = encoders
+}
+
+// SetLibraryProfiles sets the source of per-library transcode profiles
+func (p *PlaybackPlannerImpl) SetLibraryProfiles(profiles LibraryProfileSource) {
+	p.libraryProfiles = profiles
+}
+
+// libraryProfile returns the profile for the file's library, or nil if none is assigned
+func (p *PlaybackPlannerImpl) libraryProfile(mediaPath string) *database.LibraryTranscodeProfile {
+	if p.libraryProfiles == nil {
+		return nil
+	}
+	return p.libraryProfiles.LibraryProfileForPath(mediaPath)
+}
+
 // DecidePlayback determines whether to direct play or transcode based on media and device capabilities
 func (p *PlaybackPlannerImpl) DecidePlayback(mediaPath string, deviceProfile *DeviceProfile) (*PlaybackDecision, error) {
 	// Analyze media file using injected analyzer
@@ -27,18 +59,24 @@ func (p *PlaybackPlannerImpl) DecidePlayback(mediaPath string, deviceProfile *De
 		return nil, fmt.Errorf("failed to analyze media: %w", err)
 	}
 
+	libProfile := p.libraryProfile(mediaPath)
+
 	// Check if direct play is possible
-	if p.canDirectPlay(mediaInfo, deviceProfile) {
+	if p.canDirectPlay(mediaInfo, deviceProfile, libProfile) {
+		reason := "Media is compatible with client capabilities"
+		if libProfile != nil && libProfile.KeepOriginal {
+			reason += " (library keeps original)"
+		}
 		return &PlaybackDecision{
 			ShouldTranscode: false,
 			DirectPlayURL:   mediaPath,
 			StreamURL:       mediaPath, // For frontend compatibility
-			Reason:          "Media is compatible with client capabilities",
+			Reason:          reason,
 		}, nil
 	}
 
 	// Determine transcoding parameters
-	transcodeParams, reason := p.determineTranscodeParams(mediaPath, mediaInfo, deviceProfile)
+	transcodeParams, reason := p.determineTranscodeParams(mediaPath, mediaInfo, deviceProfile, libProfile)
 
 	return &PlaybackDecision{
 		ShouldTranscode: true,
@@ -47,8 +85,10 @@ func (p *PlaybackPlannerImpl) DecidePlayback(mediaPath string, deviceProfile *De
 	}, nil
 }
 
-// canDirectPlay checks if the media can be played directly without transcoding
-func (p *PlaybackPlannerImpl) canDirectPlay(media *MediaInfo, profile *DeviceProfile) bool {
+// canDirectPlay checks if the media can be played directly without transcoding.
+// Libraries that keep originals ignore the client's bitrate and resolution
+// limits, so only undecodable sources are transcoded.
+func (p *PlaybackPlannerImpl) canDirectPlay(media *MediaInfo, profile *DeviceProfile, libProfile *database.LibraryTranscodeProfile) bool {
 	// Check container format
 	if !p.isContainerSupported(media.Container, profile) {
 		return false
@@ -59,6 +99,10 @@ func (p *PlaybackPlannerImpl) canDirectPlay(media *MediaInfo, profile *DevicePro
 		return false
 	}
 
+	if libProfile != nil && libProfile.KeepOriginal {
+		return !media.HasHDR || profile.SupportsHDR
+	}
+
 	// Check bitrate limits
 	if profile.MaxBitrate > 0 && media.Bitrate > int64(profile.MaxBitrate) {
 		return false
@@ -152,17 +196,24 @@ func (p *PlaybackPlannerImpl) isWebBrowser(userAgent string) bool {
 }
 
 // determineTranscodeParams determines the optimal transcoding parameters
-func (p *PlaybackPlannerImpl) determineTranscodeParams(mediaPath string, media *MediaInfo, profile *DeviceProfile) (*plugins.TranscodeRequest, string) {
+func (p *PlaybackPlannerImpl) determineTranscodeParams(mediaPath string, media *MediaInfo, profile *DeviceProfile, libProfile *database.LibraryTranscodeProfile) (*plugins.TranscodeRequest, string) {
 	var reasons []string
 
-	// Determine target codec
-	targetCodec := p.selectTargetCodec(media.VideoCodec, profile)
+	// Determine target resolution
+	targetResolution := p.selectTargetResolution(media.Resolution, profile.MaxResolution)
+	if libProfile != nil && libProfile.MaxResolution != "" {
+		targetResolution = p.selectTargetResolution(targetResolution, libProfile.MaxResolution)
+	}
+
+	// Determine target codec, preferring the library's codec when the client can play it
+	targetCodec := p.selectTargetCodec(media.VideoCodec, targetResolution, profile)
+	if libProfile != nil && libProfile.VideoCodec != "" && p.canUseLibraryCodec(libProfile.VideoCodec, profile) {
+		targetCodec = strings.ToLower(libProfile.VideoCodec)
+	}
 	if targetCodec != media.VideoCodec {
 		reasons = append(reasons, fmt.Sprintf("codec change: %s -> %s", media.VideoCodec, targetCodec))
 	}
 
-	// Determine target resolution
-	targetResolution := p.selectTargetResolution(media.Resolution, profile.MaxResolution)
 	var resolution *plugins.Resolution
 	if targetResolution != media.Resolution {
 		reasons = append(reasons, fmt.Sprintf("resolution change: %s -> %s", media.Resolution, targetResolution))
@@ -176,7 +227,7 @@ func (p *PlaybackPlannerImpl) determineTranscodeParams(mediaPath string, media *
 	}
 
 	// Determine target bitrate
-	targetBitrate := p.calculateTargetBitrate(targetResolution, profile.MaxBitrate)
+	targetBitrate := p.calculateTargetBitrate(targetResolution, targetCodec, profile.MaxBitrate)
 	if int64(targetBitrate) < media.Bitrate {
 		reasons = append(reasons, fmt.Sprintf("bitrate reduction: %d -> %d", media.Bitrate, targetBitrate))
 	}
@@ -189,6 +240,14 @@ func (p *PlaybackPlannerImpl) determineTranscodeParams(mediaPath string, media *
 
 	// Determine quality based on bitrate (0-100 scale)
 	quality := p.calculateQuality(targetBitrate)
+	if libProfile != nil && libProfile.Quality > 0 {
+		quality = libProfile.Quality
+	}
+
+	tenBit := libProfile != nil && libProfile.TenBit
+	if libProfile != nil {
+		reasons = append(reasons, fmt.Sprintf("library profile %q", libProfile.Name))
+	}
 
 	// ENHANCED: Smart ABR enablement based on device profile
 	enableABR := p.shouldEnableABR(targetContainer, profile, media)
@@ -213,6 +272,7 @@ func (p *PlaybackPlannerImpl) determineTranscodeParams(mediaPath string, media *
 		Seek:          0, // No seek by default
 		// Duration field removed - not in TranscodeRequest
 		EnableABR:     enableABR,
+		TenBit:        tenBit,
 	}, reason
 }
 
@@ -235,20 +295,25 @@ func (p *PlaybackPlannerImpl) calculateQuality(bitrate int) int {
 }
 
 // selectTargetCodec chooses the best codec for the client
-func (p *PlaybackPlannerImpl) selectTargetCodec(sourceCodec string, profile *DeviceProfile) string {
+func (p *PlaybackPlannerImpl) selectTargetCodec(sourceCodec, targetResolution string, profile *DeviceProfile) string {
+	// Use AV1 when both sides support it and the bandwidth savings matter
+	if p.shouldUseAV1(targetResolution, profile) {
+		return "av1"
+	}
+
 	// Prefer H.264 for maximum compatibility
 	if p.isCodecSupported("h264", profile.SupportedCodecs) {
 		return "h264"
 	}
 
 	// Use HEVC if supported and client supports it
-	if profile.SupportsHEVC && p.isCodecSupported("hevc", profile.SupportedCodecs) {
+	if profile.SupportsHEVC && p.isCodecSupported("hevc", profile.SupportedCodecs) && p.canEncode("hevc") {
 		return "hevc"
 	}
 
 	// Fall back to VP8/VP9 for web browsers
 	if p.isWebBrowser(profile.UserAgent) {
-		if p.isCodecSupported("vp9", profile.SupportedCodecs) {
+		if p.isCodecSupported("vp9", profile.SupportedCodecs) && p.canEncode("vp9") {
 			return "vp9"
 		}
 		if p.isCodecSupported("vp8", profile.SupportedCodecs) {
@@ -260,6 +325,52 @@ func (p *PlaybackPlannerImpl) selectTargetCodec(sourceCodec string, profile *Dev
 	return "h264"
 }
 
+// canUseLibraryCodec reports whether a library's preferred codec can be encoded
+// and played by the client
+func (p *PlaybackPlannerImpl) canUseLibraryCodec(codec string, profile *DeviceProfile) bool {
+	codec = strings.ToLower(codec)
+	if codec != "h264" && !p.canEncode(codec) {
+		return false
+	}
+
+	switch codec {
+	case "hevc":
+		return profile.SupportsHEVC || p.isCodecSupported(codec, profile.SupportedCodecs)
+	case "av1":
+		return profile.SupportsAV1 || p.isCodecSupported(codec, profile.SupportedCodecs)
+	default:
+		return p.isCodecSupported(codec, profile.SupportedCodecs)
+	}
+}
+
+// shouldUseAV1 decides whether AV1 is worth its encoding cost for this client.
+// AV1 saves roughly 40% bitrate over H.264 but is expensive to encode, so it is
+// only chosen for high resolutions or bandwidth-constrained clients, and never
+// for mobile devices where decode support and battery life are uncertain.
+func (p *PlaybackPlannerImpl) shouldUseAV1(targetResolution string, profile *DeviceProfile) bool {
+	if !profile.SupportsAV1 && !p.isCodecSupported("av1", profile.SupportedCodecs) {
+		return false
+	}
+	if !p.canEncode("av1") {
+		return false
+	}
+	if p.determineSpeedPriority(profile) == plugins.SpeedPriorityFastest {
+		return false
+	}
+
+	if p.getResolutionHeight(targetResolution) >= 1080 {
+		return true
+	}
+
+	// Below 1080p AV1 only pays off when the client is short on bandwidth
+	return profile.MaxBitrate > 0 && profile.MaxBitrate < p.calculateTargetBitrate(targetResolution, "h264", 0)
+}
+
+// canEncode reports whether a transcoding provider can produce the codec
+func (p *PlaybackPlannerImpl) canEncode(codec string) bool {
+	return p.encoders != nil && p.encoders.SupportsEncoding(codec)
+}
+
 // selectTargetResolution chooses the appropriate resolution
 func (p *PlaybackPlannerImpl) selectTargetResolution(sourceRes, maxRes string) string {
 	if maxRes == "" {
@@ -277,8 +388,8 @@ func (p *PlaybackPlannerImpl) selectTargetResolution(sourceRes, maxRes string) s
 	return maxRes
 }
 
-// calculateTargetBitrate calculates appropriate bitrate for resolution
-func (p *PlaybackPlannerImpl) calculateTargetBitrate(resolution string, maxBitrate int) int {
+// calculateTargetBitrate calculates appropriate bitrate for resolution and codec
+func (p *PlaybackPlannerImpl) calculateTargetBitrate(resolution, codec string, maxBitrate int) int {
 	// Base bitrates for different resolutions (in kbps)
 	baseBitrates := map[string]int{
 		"480p":  1500,
@@ -293,6 +404,14 @@ func (p *PlaybackPlannerImpl) calculateTargetBitrate(resolution string, maxBitra
 		targetBitrate = 6000 // Default to 1080p bitrate
 	}
 
+	// Newer codecs reach the same quality at a lower bitrate
+	switch codec {
+	case "av1":
+		targetBitrate = targetBitrate * 6 / 10
+	case "hevc", "vp9":
+		targetBitrate = targetBitrate * 7 / 10
+	}
+
 	// Apply client bitrate limit if specified
 	if maxBitrate > 0 && targetBitrate > maxBitrate {
 		targetBitrate = maxBitrate
@@ -303,6 +422,14 @@ func (p *PlaybackPlannerImpl) calculateTargetBitrate(resolution string, maxBitra
 
 // selectTargetContainer chooses the best container format for the client
 func (p *PlaybackPlannerImpl) selectTargetContainer(sourceContainer string, profile *DeviceProfile) string {
+	// A streaming format the client asked for wins
+	switch strings.ToLower(strings.TrimSpace(profile.TargetContainer)) {
+	case "hls":
+		return "hls"
+	case "dash":
+		return "dash"
+	}
+
 	// Use device-specific container selection for optimal playback
 	userAgent := strings.ToLower(profile.UserAgent)
 	
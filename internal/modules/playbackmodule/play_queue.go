@@ -0,0 +1,445 @@
+package playbackmodule
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/rand/v2"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/mantonx/viewra/internal/database"
+	"gorm.io/gorm"
+)
+
+// Play queue modes
+const (
+	QueueModeShuffle  = "shuffle"  // Every item in random order
+	QueueModeMarathon = "marathon" // Every item in airing or release order
+)
+
+// Play queue sources
+const (
+	QueueSourceShow       = "show"       // Episodes of a TV show
+	QueueSourceCollection = "collection" // Movies of a TMDb collection
+)
+
+// PlayQueueRequest asks for a queue to be generated
+type PlayQueueRequest struct {
+	UserID          uint32 `json:"user_id"`
+	Mode            string `json:"mode" binding:"required"`
+	SourceType      string `json:"source_type" binding:"required"`
+	SourceID        string `json:"source_id" binding:"required"`
+	UnwatchedOnly   bool   `json:"unwatched_only,omitempty"`
+	IncludeSpecials bool   `json:"include_specials,omitempty"`
+	Seed            uint64 `json:"seed,omitempty"` // Makes a shuffle reproducible; 0 = random
+}
+
+// PlayQueueView is a queue with the item playing now and the one after it
+type PlayQueueView struct {
+	*database.PlayQueue
+	Current  *database.PlayQueueItem `json:"current,omitempty"`
+	Next     *database.PlayQueueItem `json:"next,omitempty"`
+	Finished bool                    `json:"finished"`
+}
+
+// queueCandidate is an item considered for a queue before ordering
+type queueCandidate struct {
+	item  database.PlayQueueItem
+	order string // Sort key for marathon order
+}
+
+// CreatePlayQueue generates a queue from a show or collection
+func (m *Manager) CreatePlayQueue(request *PlayQueueRequest) (*database.PlayQueue, error) {
+	request.Mode = strings.ToLower(request.Mode)
+	if request.Mode != QueueModeShuffle && request.Mode != QueueModeMarathon {
+		return nil, fmt.Errorf("unknown queue mode %q", request.Mode)
+	}
+
+	var candidates []queueCandidate
+	var title string
+	var err error
+	switch strings.ToLower(request.SourceType) {
+	case QueueSourceShow:
+		candidates, title, err = m.showQueueCandidates(request.SourceID, request.IncludeSpecials)
+	case QueueSourceCollection:
+		candidates, title, err = m.collectionQueueCandidates(request.SourceID)
+	default:
+		return nil, fmt.Errorf("unknown queue source %q", request.SourceType)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if request.UnwatchedOnly {
+		watched, err := m.watchedMediaIDs(request.UserID, candidates)
+		if err != nil {
+			return nil, err
+		}
+		kept := candidates[:0]
+		for _, c := range candidates {
+			if !watched[c.item.MediaID] {
+				kept = append(kept, c)
+			}
+		}
+		candidates = kept
+	}
+	if len(candidates) == 0 {
+		return nil, fmt.Errorf("nothing to play")
+	}
+
+	if request.Mode == QueueModeShuffle {
+		seed := request.Seed
+		if seed == 0 {
+			seed = rand.Uint64()
+		}
+		rng := rand.New(rand.NewPCG(seed, seed))
+		rng.Shuffle(len(candidates), func(i, j int) { candidates[i], candidates[j] = candidates[j], candidates[i] })
+	} else {
+		sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].order < candidates[j].order })
+	}
+
+	queue := &database.PlayQueue{
+		ID:         uuid.New().String(),
+		UserID:     request.UserID,
+		Mode:       request.Mode,
+		SourceType: strings.ToLower(request.SourceType),
+		SourceID:   request.SourceID,
+		Title:      title,
+		Items:      make([]database.PlayQueueItem, len(candidates)),
+	}
+	for i, c := range candidates {
+		c.item.Index = i
+		queue.Items[i] = c.item
+	}
+
+	if err := m.db.Create(queue).Error; err != nil {
+		return nil, fmt.Errorf("failed to save play queue: %w", err)
+	}
+
+	m.logger.Info("created play queue",
+		"queue_id", queue.ID,
+		"mode", queue.Mode,
+		"source", queue.SourceType+":"+queue.SourceID,
+		"items", len(queue.Items))
+
+	return queue, nil
+}
+
+// showQueueCandidates returns the playable episodes of a show
+func (m *Manager) showQueueCandidates(showID string, includeSpecials bool) ([]queueCandidate, string, error) {
+	var show database.TVShow
+	if err := m.db.Where("id = ?", showID).First(&show).Error; err != nil {
+		return nil, "", fmt.Errorf("TV show not found: %s", showID)
+	}
+
+	query := m.db.Table("episodes").
+		Select("episodes.id, episodes.title, seasons.season_number, episodes.episode_number").
+		Joins("JOIN seasons ON seasons.id = episodes.season_id").
+		Where("seasons.tv_show_id = ?", show.ID)
+	if !includeSpecials {
+		query = query.Where("seasons.season_number > 0")
+	}
+	var episodes []struct {
+		ID            string
+		Title         string
+		SeasonNumber  int
+		EpisodeNumber int
+	}
+	if err := query.Scan(&episodes).Error; err != nil {
+		return nil, "", fmt.Errorf("failed to load episodes: %w", err)
+	}
+
+	ids := make([]string, len(episodes))
+	for i, episode := range episodes {
+		ids[i] = episode.ID
+	}
+	files, err := m.bestFiles(ids)
+	if err != nil {
+		return nil, "", err
+	}
+
+	candidates := make([]queueCandidate, 0, len(episodes))
+	for _, episode := range episodes {
+		fileID, ok := files[episode.ID]
+		if !ok {
+			continue
+		}
+		// Specials sort after the regular seasons in a marathon
+		season := episode.SeasonNumber
+		if season == 0 {
+			season = 9999
+		}
+		candidates = append(candidates, queueCandidate{
+			item: database.PlayQueueItem{
+				MediaID:     episode.ID,
+				MediaType:   database.MediaTypeEpisode,
+				MediaFileID: fileID,
+				Title:       fmt.Sprintf("S%02dE%02d %s", episode.SeasonNumber, episode.EpisodeNumber, episode.Title),
+			},
+			order: fmt.Sprintf("%05d-%05d", season, episode.EpisodeNumber),
+		})
+	}
+	return candidates, show.Title, nil
+}
+
+// collectionQueueCandidates returns the playable movies of a collection,
+// identified by its TMDb collection ID
+func (m *Manager) collectionQueueCandidates(collectionID string) ([]queueCandidate, string, error) {
+	var movies []database.Movie
+	if err := m.db.Select("id, title, release_date, collection").Where("collection IS NOT NULL AND collection != ''").Find(&movies).Error; err != nil {
+		return nil, "", fmt.Errorf("failed to load movies: %w", err)
+	}
+
+	var title string
+	var members []database.Movie
+	for _, movie := range movies {
+		var collection struct {
+			ID   json.Number `json:"id"`
+			Name string      `json:"name"`
+		}
+		if err := json.Unmarshal([]byte(movie.Collection), &collection); err != nil || collection.ID.String() != collectionID {
+			continue
+		}
+		title = collection.Name
+		members = append(members, movie)
+	}
+	if len(members) == 0 {
+		return nil, "", fmt.Errorf("collection not found: %s", collectionID)
+	}
+
+	ids := make([]string, len(members))
+	for i, movie := range members {
+		ids[i] = movie.ID
+	}
+	files, err := m.bestFiles(ids)
+	if err != nil {
+		return nil, "", err
+	}
+
+	candidates := make([]queueCandidate, 0, len(members))
+	for _, movie := range members {
+		fileID, ok := files[movie.ID]
+		if !ok {
+			continue
+		}
+		// Unreleased or undated movies go last
+		order := "9999-99-99"
+		if movie.ReleaseDate != nil {
+			order = movie.ReleaseDate.Format("2006-01-02")
+		}
+		candidates = append(candidates, queueCandidate{
+			item: database.PlayQueueItem{
+				MediaID:     movie.ID,
+				MediaType:   database.MediaTypeMovie,
+				MediaFileID: fileID,
+				Title:       movie.Title,
+			},
+			order: order + movie.Title,
+		})
+	}
+	return candidates, title, nil
+}
+
+// bestFiles maps media IDs to the file to play for each, the highest
+// bitrate version when there are several
+func (m *Manager) bestFiles(mediaIDs []string) (map[string]string, error) {
+	files := make(map[string]string, len(mediaIDs))
+	if len(mediaIDs) == 0 {
+		return files, nil
+	}
+	var rows []database.MediaFile
+	if err := m.db.Select("id, media_id, bitrate_kbps").Where("media_id IN ?", mediaIDs).Order("bitrate_kbps DESC").Find(&rows).Error; err != nil {
+		return nil, fmt.Errorf("failed to load media files: %w", err)
+	}
+	for _, row := range rows {
+		if _, ok := files[row.MediaID]; !ok {
+			files[row.MediaID] = row.ID
+		}
+	}
+	return files, nil
+}
+
+// watchedMediaIDs returns which candidates a user has finished
+func (m *Manager) watchedMediaIDs(userID uint32, candidates []queueCandidate) (map[string]bool, error) {
+	ids := make([]string, len(candidates))
+	for i, c := range candidates {
+		ids[i] = c.item.MediaID
+	}
+	var watched []string
+	err := m.db.Model(&database.PlaybackSession{}).
+		Where("user_id = ? AND completed = ? AND media_id IN ?", userID, true, ids).
+		Distinct().Pluck("media_id", &watched).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to load watch history: %w", err)
+	}
+	set := make(map[string]bool, len(watched))
+	for _, id := range watched {
+		set[id] = true
+	}
+	return set, nil
+}
+
+// GetPlayQueue returns a queue with its items in order
+func (m *Manager) GetPlayQueue(id string) (*PlayQueueView, error) {
+	var queue database.PlayQueue
+	err := m.db.Preload("Items", func(db *gorm.DB) *gorm.DB { return db.Order("\"index\"") }).
+		Where("id = ?", id).First(&queue).Error
+	if err != nil {
+		return nil, fmt.Errorf("play queue not found: %s", id)
+	}
+	return newPlayQueueView(&queue), nil
+}
+
+// newPlayQueueView resolves the current and next items of a queue
+func newPlayQueueView(queue *database.PlayQueue) *PlayQueueView {
+	view := &PlayQueueView{PlayQueue: queue, Finished: queue.Position >= len(queue.Items)}
+	if queue.Position < len(queue.Items) {
+		view.Current = &queue.Items[queue.Position]
+	}
+	if queue.Position+1 < len(queue.Items) {
+		view.Next = &queue.Items[queue.Position+1]
+	}
+	return view
+}
+
+// SkipPlayQueueItem moves a queue past its current item without marking it watched
+func (m *Manager) SkipPlayQueueItem(id string) (*PlayQueueView, error) {
+	view, err := m.GetPlayQueue(id)
+	if err != nil {
+		return nil, err
+	}
+	if view.Finished {
+		return view, nil
+	}
+	if err := m.db.Model(view.PlayQueue).Update("position", view.Position+1).Error; err != nil {
+		return nil, fmt.Errorf("failed to skip queue item: %w", err)
+	}
+	return newPlayQueueView(view.PlayQueue), nil
+}
+
+// advancePlayQueues marks a finished item watched in the user's queues and
+// moves queues that were playing it on to their next item
+func (m *Manager) advancePlayQueues(session *database.PlaybackSession) {
+	if session.MediaID == "" {
+		return
+	}
+
+	var items []database.PlayQueueItem
+	err := m.db.Joins("JOIN play_queues ON play_queues.id = play_queue_items.queue_id").
+		Where("play_queues.user_id = ? AND play_queue_items.media_id = ? AND play_queue_items.completed = ?",
+			session.UserID, session.MediaID, false).
+		Find(&items).Error
+	if err != nil {
+		m.logger.Warn("failed to find play queue items", "media_id", session.MediaID, "error", err)
+		return
+	}
+
+	for _, item := range items {
+		err := m.db.Model(&database.PlayQueueItem{}).
+			Where("queue_id = ? AND \"index\" = ?", item.QueueID, item.Index).
+			Update("completed", true).Error
+		if err != nil {
+			m.logger.Warn("failed to mark play queue item watched", "queue_id", item.QueueID, "error", err)
+			continue
+		}
+		// Only move on when the finished item is the one the queue is on
+		err = m.db.Model(&database.PlayQueue{}).
+			Where("id = ? AND position = ?", item.QueueID, item.Index).
+			Update("position", item.Index+1).Error
+		if err != nil {
+			m.logger.Warn("failed to advance play queue", "queue_id", item.QueueID, "error", err)
+		}
+	}
+}
+
+// HandleCreatePlayQueue generates a shuffle or marathon queue
+func (h *APIHandler) HandleCreatePlayQueue(c *gin.Context) {
+	var request PlayQueueRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	queue, err := h.manager.CreatePlayQueue(&request)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, newPlayQueueView(queue))
+}
+
+// HandleListPlayQueues lists a user's queues, newest first, without items
+func (h *APIHandler) HandleListPlayQueues(c *gin.Context) {
+	query := h.manager.db.Order("updated_at DESC")
+	if userID := c.Query("user_id"); userID != "" {
+		if _, err := strconv.ParseUint(userID, 10, 32); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user ID"})
+			return
+		}
+		query = query.Where("user_id = ?", userID)
+	}
+
+	var queues []database.PlayQueue
+	if err := query.Find(&queues).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"queues": queues})
+}
+
+// HandleGetPlayQueue returns a queue with its items
+func (h *APIHandler) HandleGetPlayQueue(c *gin.Context) {
+	view, err := h.manager.GetPlayQueue(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, view)
+}
+
+// HandleSkipPlayQueueItem moves a queue to its next item
+func (h *APIHandler) HandleSkipPlayQueueItem(c *gin.Context) {
+	view, err := h.manager.SkipPlayQueueItem(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, view)
+}
+
+// HandleDeletePlayQueue removes a queue
+func (h *APIHandler) HandleDeletePlayQueue(c *gin.Context) {
+	id := c.Param("id")
+	err := h.manager.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("queue_id = ?", id).Delete(&database.PlayQueueItem{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("id = ?", id).Delete(&database.PlayQueue{}).Error
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"deleted": true})
+}
+
+// RegisterPlayQueueRoutes registers shuffle and marathon queue endpoints
+func RegisterPlayQueueRoutes(api *gin.RouterGroup, handler *APIHandler) {
+	queues := api.Group("/queues")
+	{
+		queues.POST("", handler.HandleCreatePlayQueue)
+		queues.GET("", handler.HandleListPlayQueues)
+		queues.GET("/:id", handler.HandleGetPlayQueue)
+		queues.POST("/:id/skip", handler.HandleSkipPlayQueueItem)
+		queues.DELETE("/:id", handler.HandleDeletePlayQueue)
+	}
+}
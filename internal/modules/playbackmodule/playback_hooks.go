@@ -0,0 +1,51 @@
+package playbackmodule
+
+import (
+	"time"
+
+	"github.com/mantonx/viewra/internal/database"
+	plugins "github.com/mantonx/viewra/sdk"
+)
+
+// playbackEventNotifier is implemented by plugin managers that pass playback
+// events on to plugins serving the PlaybackHookService
+type playbackEventNotifier interface {
+	NotifyPlaybackEvent(event *plugins.PlaybackEvent)
+}
+
+// notifyPlaybackEvent reports a session's change of state to plugins, with
+// the titles and external IDs of what is playing
+func (m *Manager) notifyPlaybackEvent(eventType string, session *database.PlaybackSession) {
+	notifier, ok := m.pluginManager.(playbackEventNotifier)
+	if !ok {
+		return
+	}
+
+	media := m.describeHistoryMedia(map[string]HistoryEntry{}, session.MediaID, session.MediaType)
+	event := &plugins.PlaybackEvent{
+		Type:            eventType,
+		SessionID:       session.ID,
+		UserID:          session.UserID,
+		MediaFileID:     session.MediaFileID,
+		MediaID:         session.MediaID,
+		MediaType:       session.MediaType,
+		PositionSeconds: session.PositionSeconds,
+		DurationSeconds: session.DurationSeconds,
+		WatchedSeconds:  session.WatchedSeconds,
+		Completed:       session.Completed,
+		Title:           media.Title,
+		Year:            media.Year,
+		TmdbID:          media.TmdbID,
+		ImdbID:          media.ImdbID,
+		ShowTitle:       media.ShowTitle,
+		ShowTmdbID:      media.ShowTmdbID,
+		Season:          media.Season,
+		Episode:         media.Episode,
+		OccurredAt:      time.Now(),
+	}
+	if session.DurationSeconds > 0 {
+		event.Progress = min(100, session.PositionSeconds/session.DurationSeconds*100)
+	}
+
+	notifier.NotifyPlaybackEvent(event)
+}
@@ -0,0 +1,456 @@
+package playbackmodule
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mantonx/viewra/internal/auth"
+	"github.com/mantonx/viewra/internal/database"
+	"github.com/mantonx/viewra/internal/streamurl"
+)
+
+const (
+	// chapterProbeTimeout bounds reading a file's chapters with ffprobe
+	chapterProbeTimeout = 15 * time.Second
+	// resumeMinSeconds is how far into a file playback must have stopped to
+	// offer resuming, matching the media module's Up Next
+	resumeMinSeconds = 60
+)
+
+var (
+	// errItemNotFound is returned when an ID matches neither an item nor a file
+	errItemNotFound = errors.New("no media file found for item")
+	// errVersionNotFound is returned when the file asked for isn't the item's
+	errVersionNotFound = errors.New("media file is not a version of this item")
+)
+
+// PlaybackInfoRequest asks how a client should play an item
+type PlaybackInfoRequest struct {
+	ItemID        string         `json:"item_id" binding:"required"` // Movie, episode, track or home video ID, or a media file ID
+	MediaFileID   string         `json:"media_file_id,omitempty"`    // Version to play when the item has several
+	UserID        uint32         `json:"user_id,omitempty"`          // For track preferences and the resume position
+	DeviceProfile *DeviceProfile `json:"device_profile,omitempty"`
+	EnableABR     bool           `json:"enable_abr,omitempty"`
+	PlanOnly      bool           `json:"plan_only,omitempty"` // Decide without starting a transcode session
+}
+
+// PlaybackVersion is one file of an item the player may switch to
+type PlaybackVersion struct {
+	MediaFileID string `json:"media_file_id"`
+	VersionName string `json:"version_name,omitempty"`
+	Container   string `json:"container"`
+	Resolution  string `json:"resolution,omitempty"`
+	VideoCodec  string `json:"video_codec,omitempty"`
+	BitrateKbps int    `json:"bitrate_kbps,omitempty"`
+}
+
+// AudioTrack describes an audio track of a media file
+type AudioTrack struct {
+	Index         int    `json:"index"` // Counted among audio streams, as in 0:a:N
+	Codec         string `json:"codec"`
+	Language      string `json:"language,omitempty"`
+	Title         string `json:"title,omitempty"`
+	Channels      int    `json:"channels,omitempty"`
+	ChannelLayout string `json:"channel_layout,omitempty"`
+	Default       bool   `json:"default"`
+	Forced        bool   `json:"forced"`
+}
+
+// Chapter is a chapter of a media file
+type Chapter struct {
+	Title        string  `json:"title,omitempty"`
+	StartSeconds float64 `json:"start_seconds"`
+	EndSeconds   float64 `json:"end_seconds"`
+}
+
+// PlaybackInfo is everything a player needs to start an item: the stream
+// plan and its URL, the tracks to offer, chapters, markers and where to
+// resume
+type PlaybackInfo struct {
+	ItemID                string                 `json:"item_id"`
+	MediaType             string                 `json:"media_type"`
+	MediaFileID           string                 `json:"media_file_id"`
+	Versions              []PlaybackVersion      `json:"versions"`
+	Method                string                 `json:"method"` // direct_play or transcode
+	Decision              *PlaybackDecision      `json:"decision"`
+	DirectURL             string                 `json:"direct_url,omitempty"`
+	ManifestURL           string                 `json:"manifest_url,omitempty"`
+	SessionID             string                 `json:"session_id,omitempty"`
+	AudioTracks           []AudioTrack           `json:"audio_tracks"`
+	SubtitleTracks        []SubtitleTrack        `json:"subtitle_tracks"`
+	Chapters              []Chapter              `json:"chapters"`
+	Markers               []database.MediaMarker `json:"markers"`
+	DurationSeconds       float64                `json:"duration_seconds"`
+	ResumePositionSeconds float64                `json:"resume_position_seconds"`
+	NextUp                *NextUp                `json:"next_up,omitempty"`
+	AutoAdvance           *AutoAdvance           `json:"auto_advance,omitempty"`
+}
+
+// GetPlaybackInfo decides how to play an item on a client and gathers what
+// the player shows alongside it. A transcode session is started when the
+// client can't play the file directly, unless the request is plan only.
+func (m *Manager) GetPlaybackInfo(req *PlaybackInfoRequest) (*PlaybackInfo, error) {
+	if !m.initialized {
+		return nil, fmt.Errorf("playback manager not initialized")
+	}
+
+	profile := req.DeviceProfile
+	if profile == nil {
+		profile = defaultDeviceProfile()
+	}
+	m.applyRegisteredDevice(profile)
+	if profile.UserID == 0 {
+		profile.UserID = req.UserID
+	}
+	userID := profile.UserID
+
+	files, err := m.itemFiles(req.ItemID)
+	if err != nil {
+		return nil, err
+	}
+	// A file ID given as the item ID picks that version
+	mediaFileID := req.MediaFileID
+	for _, file := range files {
+		if mediaFileID == "" && file.ID == req.ItemID {
+			mediaFileID = file.ID
+		}
+	}
+	mediaFile, err := m.playbackVersion(files, mediaFileID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	info := &PlaybackInfo{
+		ItemID:          mediaFile.MediaID,
+		MediaType:       string(mediaFile.MediaType),
+		MediaFileID:     mediaFile.ID,
+		Versions:        make([]PlaybackVersion, 0, len(files)),
+		AudioTracks:     audioTracks(mediaFile),
+		Chapters:        []Chapter{},
+		Markers:         []database.MediaMarker{},
+		DurationSeconds: float64(mediaFile.Duration),
+	}
+	for _, file := range files {
+		info.Versions = append(info.Versions, PlaybackVersion{
+			MediaFileID: file.ID,
+			VersionName: file.VersionName,
+			Container:   file.Container,
+			Resolution:  file.Resolution,
+			VideoCodec:  file.VideoCodec,
+			BitrateKbps: file.BitrateKbps,
+		})
+	}
+
+	decision, err := m.DecidePlayback(mediaFile.Path, profile)
+	if err != nil {
+		return nil, err
+	}
+	info.Decision = decision
+
+	if !decision.ShouldTranscode {
+		info.Method = PlaybackMethodDirectPlay
+		info.DirectURL = fmt.Sprintf("/api/media/files/%s/stream", mediaFile.ID)
+		// Don't hand out the file system path
+		decision.DirectPlayURL, decision.StreamURL = info.DirectURL, info.DirectURL
+	} else {
+		info.Method = PlaybackMethodTranscode
+		if !req.PlanOnly && decision.TranscodeParams != nil {
+			decision.TranscodeParams.EnableABR = req.EnableABR
+			session, err := m.StartTranscode(decision.TranscodeParams)
+			if err != nil {
+				return nil, fmt.Errorf("failed to start transcoding session: %w", err)
+			}
+			info.SessionID = session.ID
+			info.ManifestURL = streamurl.Manifest(session.ID, userID, decision.TranscodeParams.Container)
+			decision.SessionID, decision.ManifestURL = info.SessionID, info.ManifestURL
+		}
+	}
+
+	if info.SubtitleTracks, err = m.GetSubtitleTracks(mediaFile.ID); err != nil {
+		m.logger.Warn("failed to list subtitle tracks", "media_file_id", mediaFile.ID, "error", err)
+		info.SubtitleTracks = []SubtitleTrack{}
+	}
+	if err := m.db.Where("media_file_id = ?", mediaFile.ID).Order("start_seconds").Find(&info.Markers).Error; err != nil {
+		m.logger.Warn("failed to get markers", "media_file_id", mediaFile.ID, "error", err)
+	}
+	if chapters, err := m.fileChapters(mediaFile.Path); err != nil {
+		m.logger.Debug("failed to read chapters", "media_file_id", mediaFile.ID, "error", err)
+	} else {
+		info.Chapters = chapters
+	}
+	info.ResumePositionSeconds = m.resumePosition(mediaFile.MediaID, userID)
+
+	if mediaFile.MediaType == database.MediaTypeEpisode {
+		if next, advance, err := m.GetNextUp(mediaFile.ID, userID); err == nil {
+			info.NextUp, info.AutoAdvance = next, advance
+		}
+	}
+
+	return info, nil
+}
+
+// itemFiles returns the files of an item, or the file with that ID
+func (m *Manager) itemFiles(itemID string) ([]database.MediaFile, error) {
+	var files []database.MediaFile
+	if err := m.db.Where("media_id = ?", itemID).Order("version_name, path").Find(&files).Error; err != nil {
+		return nil, fmt.Errorf("failed to get media files: %w", err)
+	}
+	if len(files) > 0 {
+		return files, nil
+	}
+
+	if err := m.db.Where("id = ?", itemID).Limit(1).Find(&files).Error; err != nil {
+		return nil, fmt.Errorf("failed to get media file: %w", err)
+	}
+	if len(files) == 0 {
+		return nil, errItemNotFound
+	}
+	// A file ID stands for its whole item, so the other versions are offered
+	if files[0].MediaID != "" {
+		var versions []database.MediaFile
+		if err := m.db.Where("media_id = ?", files[0].MediaID).Order("version_name, path").Find(&versions).Error; err == nil && len(versions) > 0 {
+			return versions, nil
+		}
+	}
+	return files, nil
+}
+
+// playbackVersion picks the file to play: the one asked for, else the one
+// the user last played, else the highest bitrate
+func (m *Manager) playbackVersion(files []database.MediaFile, mediaFileID string, userID uint32) (*database.MediaFile, error) {
+	if mediaFileID != "" {
+		for i := range files {
+			if files[i].ID == mediaFileID {
+				return &files[i], nil
+			}
+		}
+		return nil, errVersionNotFound
+	}
+	if len(files) == 1 {
+		return &files[0], nil
+	}
+
+	var sessions []database.PlaybackSession
+	if err := m.db.Select("media_file_id").
+		Where("media_id = ? AND user_id = ?", files[0].MediaID, userID).
+		Order("last_seen_at DESC").Limit(1).Find(&sessions).Error; err == nil && len(sessions) > 0 {
+		for i := range files {
+			if files[i].ID == sessions[0].MediaFileID {
+				return &files[i], nil
+			}
+		}
+	}
+
+	best := &files[0]
+	for i := range files {
+		if files[i].BitrateKbps > best.BitrateKbps ||
+			(files[i].BitrateKbps == best.BitrateKbps && files[i].SizeBytes > best.SizeBytes) {
+			best = &files[i]
+		}
+	}
+	return best, nil
+}
+
+// resumePosition is where the user's last viewing of an item stopped, or 0
+// when it was finished or barely started
+func (m *Manager) resumePosition(mediaID string, userID uint32) float64 {
+	var sessions []database.PlaybackSession
+	if err := m.db.Where("media_id = ? AND user_id = ?", mediaID, userID).
+		Order("last_seen_at DESC").Limit(1).Find(&sessions).Error; err != nil || len(sessions) == 0 {
+		return 0
+	}
+	session := sessions[0]
+	if m.sessionWatched(&session, m.watchRulesFor(userID)) {
+		return 0
+	}
+
+	// Players that don't report a position fall back to the time played
+	position := session.PositionSeconds
+	if position <= 0 {
+		position = session.WatchedSeconds
+	}
+	if position < resumeMinSeconds {
+		return 0
+	}
+	return position
+}
+
+// audioTracks lists a media file's audio tracks from its stored probe info
+func audioTracks(mediaFile *database.MediaFile) []AudioTrack {
+	tracks := []AudioTrack{}
+	if mediaFile.AudioStreams == "" {
+		if mediaFile.AudioCodec != "" {
+			language := mediaFile.AudioLanguage
+			if language == "und" {
+				language = ""
+			}
+			tracks = append(tracks, AudioTrack{
+				Codec:         mediaFile.AudioCodec,
+				Language:      language,
+				Channels:      mediaFile.AudioChannels,
+				ChannelLayout: mediaFile.AudioLayout,
+				Default:       true,
+			})
+		}
+		return tracks
+	}
+
+	var streams []struct {
+		Codec         string `json:"codec"`
+		Language      string `json:"language"`
+		Title         string `json:"title"`
+		Channels      int    `json:"channels"`
+		ChannelLayout string `json:"channel_layout"`
+		Default       bool   `json:"default"`
+		Forced        bool   `json:"forced"`
+	}
+	if err := json.Unmarshal([]byte(mediaFile.AudioStreams), &streams); err != nil {
+		return tracks
+	}
+	for i, stream := range streams {
+		tracks = append(tracks, AudioTrack{
+			Index:         i,
+			Codec:         stream.Codec,
+			Language:      stream.Language,
+			Title:         stream.Title,
+			Channels:      stream.Channels,
+			ChannelLayout: stream.ChannelLayout,
+			Default:       stream.Default,
+			Forced:        stream.Forced,
+		})
+	}
+	return tracks
+}
+
+// chapterCacheEntry holds a file's chapters while the file is unchanged
+type chapterCacheEntry struct {
+	modTime  time.Time
+	size     int64
+	chapters []Chapter
+}
+
+// fileChapters reads a file's chapters with ffprobe, reusing the result
+// until the file changes
+func (m *Manager) fileChapters(path string) ([]Chapter, error) {
+	stat, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if cached, ok := m.chapters.Load(path); ok {
+		entry := cached.(chapterCacheEntry)
+		if entry.modTime.Equal(stat.ModTime()) && entry.size == stat.Size() {
+			return entry.chapters, nil
+		}
+	}
+
+	chapters, err := probeChapters(path)
+	if err != nil {
+		return nil, err
+	}
+	m.chapters.Store(path, chapterCacheEntry{modTime: stat.ModTime(), size: stat.Size(), chapters: chapters})
+	return chapters, nil
+}
+
+// probeChapters runs ffprobe for the chapters of a file
+func probeChapters(path string) ([]Chapter, error) {
+	ffprobePath := findExecutable("ffprobe")
+	if ffprobePath == "" {
+		return nil, fmt.Errorf("ffprobe not found in PATH")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), chapterProbeTimeout)
+	defer cancel()
+
+	output, err := exec.CommandContext(ctx, ffprobePath,
+		"-v", "quiet",
+		"-print_format", "json",
+		"-show_chapters",
+		path,
+	).Output()
+	if err != nil {
+		return nil, fmt.Errorf("ffprobe failed: %w", err)
+	}
+	return parseChapters(output)
+}
+
+// parseChapters reads ffprobe's -show_chapters JSON output
+func parseChapters(output []byte) ([]Chapter, error) {
+	var probe struct {
+		Chapters []struct {
+			StartTime string            `json:"start_time"`
+			EndTime   string            `json:"end_time"`
+			Tags      map[string]string `json:"tags"`
+		} `json:"chapters"`
+	}
+	if err := json.Unmarshal(output, &probe); err != nil {
+		return nil, fmt.Errorf("failed to parse ffprobe output: %w", err)
+	}
+
+	chapters := make([]Chapter, 0, len(probe.Chapters))
+	for _, probed := range probe.Chapters {
+		start, _ := strconv.ParseFloat(probed.StartTime, 64)
+		end, _ := strconv.ParseFloat(probed.EndTime, 64)
+		chapters = append(chapters, Chapter{
+			Title:        strings.TrimSpace(probed.Tags["title"]),
+			StartSeconds: start,
+			EndSeconds:   end,
+		})
+	}
+	sort.Slice(chapters, func(i, j int) bool { return chapters[i].StartSeconds < chapters[j].StartSeconds })
+	return chapters, nil
+}
+
+// defaultDeviceProfile is assumed for clients that don't describe themselves
+func defaultDeviceProfile() *DeviceProfile {
+	return &DeviceProfile{
+		UserAgent:       "unknown",
+		SupportedCodecs: []string{"h264", "aac"},
+		MaxResolution:   "1080p",
+		MaxBitrate:      6000,
+	}
+}
+
+// HandleGetPlaybackInfo negotiates playback of an item with the client's
+// capabilities and returns everything the player needs to start it
+func (h *APIHandler) HandleGetPlaybackInfo(c *gin.Context) {
+	var request PlaybackInfoRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if request.DeviceProfile != nil && request.DeviceProfile.UserAgent == "" {
+		request.DeviceProfile.UserAgent = c.Request.UserAgent()
+	}
+	if request.DeviceProfile != nil && request.DeviceProfile.ClientIP == "" {
+		request.DeviceProfile.ClientIP = c.ClientIP()
+	}
+	request.UserID = auth.RequestUserID(c, request.UserID)
+	if request.DeviceProfile != nil {
+		request.DeviceProfile.UserID = auth.RequestUserID(c, request.DeviceProfile.UserID)
+	}
+
+	info, err := h.manager.GetPlaybackInfo(&request)
+	if err != nil {
+		status := http.StatusInternalServerError
+		switch {
+		case errors.Is(err, errItemNotFound):
+			status = http.StatusNotFound
+		case errors.Is(err, errVersionNotFound):
+			status = http.StatusBadRequest
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, info)
+}
@@ -3,6 +3,7 @@ package playbackmodule
 import (
 	"github.com/hashicorp/go-hclog"
 	"github.com/mantonx/viewra/internal/modules/pluginmodule"
+	plugins "github.com/mantonx/viewra/sdk"
 )
 
 // ExternalPluginManagerAdapter adapts the external plugin manager to our interface
@@ -141,3 +142,11 @@ func (a *ExternalPluginManagerAdapter) GetRunningPlugins() []PluginInfo {
 
 	return result
 }
+
+// NotifyPlaybackEvent passes a playback event on to plugins serving the PlaybackHookService
+func (a *PluginModuleAdapter) NotifyPlaybackEvent(event *plugins.PlaybackEvent) {
+	if a.extManager == nil {
+		return
+	}
+	a.extManager.NotifyPlaybackEvent(event)
+}
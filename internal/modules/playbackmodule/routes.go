@@ -10,6 +10,7 @@ func RegisterRoutes(r *gin.Engine, handler *APIHandler) {
 	{
 		// Decision endpoints
 		api.POST("/decide", handler.HandlePlaybackDecision)
+		api.POST("/info", handler.HandleGetPlaybackInfo)
 
 		// Session management
 		api.POST("/start", handler.HandleStartTranscode)
@@ -17,12 +18,40 @@ func RegisterRoutes(r *gin.Engine, handler *APIHandler) {
 		api.DELETE("/session/:sessionId", handler.HandleStopTranscode)
 		api.GET("/sessions", handler.HandleListSessions)
 		api.GET("/session/:sessionId/logs", handler.HandleGetFFmpegLogs)
+		api.GET("/session/:sessionId/stats", handler.HandleGetSessionStats)
 		
 		// Enhanced session management
 		api.DELETE("/sessions/all", handler.HandleStopAllSessions)
 		api.POST("/sessions/cleanup", handler.HandleCleanupStaleSessions)
 		api.GET("/sessions/orphaned", handler.HandleListOrphanedSessions)
 
+		// Audio-only streaming for music
+		api.POST("/audio/start", handler.HandleStartAudioStream)
+		api.GET("/audio/info/:mediaFileId", handler.HandleGetTrackPlaybackInfo)
+
+		// Per-user audio/subtitle track preferences
+		api.GET("/preferences/:userId", handler.HandleGetPlaybackPreferences)
+		api.PUT("/preferences/:userId", handler.HandleUpdatePlaybackPreferences)
+
+		// Subtitle tracks converted for browser playback
+		api.GET("/subtitles/:mediaFileId", handler.HandleListSubtitles)
+		api.GET("/subtitles/:mediaFileId/:track", handler.HandleGetSubtitle)
+
+		// Per-item deinterlace override
+		api.PUT("/media/:mediaFileId/deinterlace", handler.HandleSetDeinterlace)
+
+		// Registered client devices and their capabilities
+		api.POST("/devices", handler.HandleRegisterDevice)
+		api.GET("/devices", handler.HandleListDevices)
+		api.GET("/devices/:deviceId", handler.HandleGetDevice)
+		api.DELETE("/devices/:deviceId", handler.HandleDeleteDevice)
+
+		// Per-library default transcode profiles
+		api.GET("/libraries/profiles", handler.HandleListLibraryProfiles)
+		api.GET("/libraries/:libraryId/profile", handler.HandleGetLibraryProfile)
+		api.PUT("/libraries/:libraryId/profile", handler.HandleSetLibraryProfile)
+		api.DELETE("/libraries/:libraryId/profile", handler.HandleDeleteLibraryProfile)
+
 		// Seek-ahead functionality
 		api.POST("/seek-ahead", handler.HandleSeekAhead)
 
@@ -30,16 +59,19 @@ func RegisterRoutes(r *gin.Engine, handler *APIHandler) {
 		api.GET("/stats", handler.HandleGetStats)
 		api.GET("/health", handler.HandleHealthCheck)
 
-		// Streaming endpoints
-		api.GET("/stream/:sessionId", handler.HandleStreamTranscode)
-		api.GET("/stream/:sessionId/manifest.mpd", handler.HandleDashManifest)
-		api.HEAD("/stream/:sessionId/manifest.mpd", handler.HandleDashManifest)
-		api.GET("/stream/:sessionId/playlist.m3u8", handler.HandleHlsPlaylist)
-		api.HEAD("/stream/:sessionId/playlist.m3u8", handler.HandleHlsPlaylist)
-		api.GET("/stream/:sessionId/segment/:segmentName", handler.HandleSegment)
-		api.HEAD("/stream/:sessionId/segment/:segmentName", handler.HandleSegment)
-		api.GET("/stream/:sessionId/:segmentFile", handler.HandleDashSegmentSpecific)
-		api.HEAD("/stream/:sessionId/:segmentFile", handler.HandleDashSegmentSpecific)
+		// Streaming endpoints, reached through signed session URLs
+		stream := api.Group("/stream", handler.RequireStreamSignature)
+		stream.GET("/:sessionId", handler.HandleStreamTranscode)
+		stream.GET("/:sessionId/manifest.mpd", handler.HandleDashManifest)
+		stream.HEAD("/:sessionId/manifest.mpd", handler.HandleDashManifest)
+		stream.GET("/:sessionId/master.m3u8", handler.HandleHlsMasterPlaylist)
+		stream.HEAD("/:sessionId/master.m3u8", handler.HandleHlsMasterPlaylist)
+		stream.GET("/:sessionId/playlist.m3u8", handler.HandleHlsPlaylist)
+		stream.HEAD("/:sessionId/playlist.m3u8", handler.HandleHlsPlaylist)
+		stream.GET("/:sessionId/segment/:segmentName", handler.HandleSegment)
+		stream.HEAD("/:sessionId/segment/:segmentName", handler.HandleSegment)
+		stream.GET("/:sessionId/:segmentFile", handler.HandleDashSegmentSpecific)
+		stream.HEAD("/:sessionId/:segmentFile", handler.HandleDashSegmentSpecific)
 
 		// Cleanup endpoints
 		api.POST("/cleanup/run", handler.HandleManualCleanup)
@@ -53,5 +85,23 @@ func RegisterRoutes(r *gin.Engine, handler *APIHandler) {
 		
 		// FFmpeg monitoring
 		RegisterMonitoringRoutes(api, handler)
+
+		// FFmpeg argument templates
+		RegisterArgTemplateRoutes(api, handler)
+
+		// Playback history and analytics
+		RegisterAnalyticsRoutes(api, handler)
+
+		// Watch history and ratings export/import
+		RegisterHistoryExportRoutes(api, handler)
+
+		// Continue watching and per-item watch state
+		RegisterContinueWatchingRoutes(api, handler)
+
+		// Intro/credits markers and auto-advance
+		RegisterWatchRuleRoutes(api, handler)
+
+		// Shuffle and marathon play queues
+		RegisterPlayQueueRoutes(api, handler)
 	}
 }
@@ -39,6 +39,8 @@ func (p *PlaybackServiceImpl) DecidePlayback(mediaPath string, deviceProfile *ty
 		SupportsAV1:     deviceProfile.SupportsAV1,
 		SupportsHDR:     deviceProfile.SupportsHDR,
 		ClientIP:        deviceProfile.ClientIP,
+		UserID:          deviceProfile.UserID,
+		DeviceID:        deviceProfile.DeviceID,
 	}
 	
 	decision, err := p.manager.DecidePlayback(mediaPath, internalProfile)
@@ -51,6 +53,7 @@ func (p *PlaybackServiceImpl) DecidePlayback(mediaPath string, deviceProfile *ty
 		ShouldTranscode: decision.ShouldTranscode,
 		DirectPlayURL:   decision.DirectPlayURL,
 		TranscodeParams: decision.TranscodeParams,
+		Tracks:          decision.Tracks,
 		Reason:          decision.Reason,
 	}, nil
 }
@@ -0,0 +1,108 @@
+package playbackmodule
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mantonx/viewra/internal/auth"
+	"github.com/mantonx/viewra/internal/database"
+	"github.com/mantonx/viewra/internal/logger"
+	"github.com/mantonx/viewra/internal/streamurl"
+)
+
+// streamTokenKey holds the signed token a stream request came in with
+const streamTokenKey = "stream_token"
+
+// RequireStreamSignature verifies the signed token that stands in for the
+// session ID of stream URLs and swaps in the session it grants, so handlers
+// see the real session ID. While authentication is enabled, the user the
+// token was issued to must still be allowed to stream the session. Bare
+// session IDs are refused while signed URLs are enabled.
+func (h *APIHandler) RequireStreamSignature(c *gin.Context) {
+	segment := c.Param("sessionId")
+	if !streamurl.IsToken(segment) {
+		if streamurl.Enabled() {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "signed stream URL required"})
+			return
+		}
+		c.Next()
+		return
+	}
+
+	claims, err := streamurl.Verify(segment, time.Now())
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		return
+	}
+	allowed, err := h.streamUserMayAccess(claims)
+	if err != nil {
+		logger.Error("failed to check stream token user", "session_id", claims.SessionID, "user_id", claims.UserID, "error", err)
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "failed to check stream access"})
+		return
+	}
+	if !allowed {
+		c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "stream token is not valid for this user"})
+		return
+	}
+
+	for i := range c.Params {
+		if c.Params[i].Key == "sessionId" {
+			c.Params[i].Value = claims.SessionID
+		}
+	}
+	c.Set(streamTokenKey, segment)
+	c.Next()
+}
+
+// streamUserMayAccess reports whether the user a stream token was issued to
+// may stream its session: they must still exist and, when restricted to some
+// libraries, be able to see the file the session transcodes. Tokens issued
+// to no user are only honored while authentication is disabled.
+func (h *APIHandler) streamUserMayAccess(claims *streamurl.Claims) (bool, error) {
+	if !auth.Enabled() {
+		return true, nil
+	}
+	if claims.UserID == 0 {
+		return false, nil
+	}
+
+	db := h.manager.db
+	var user database.User
+	if err := db.Where("id = ?", claims.UserID).Limit(1).Find(&user).Error; err != nil {
+		return false, err
+	}
+	if user.ID == 0 {
+		return false, nil
+	}
+	return auth.CanAccessTranscodeSession(db, &user, claims.SessionID)
+}
+
+// streamPathID is what identifies the session in URLs handed back for this
+// request: the token it was signed with, or the bare session ID
+func streamPathID(c *gin.Context, sessionID string) string {
+	if token := c.GetString(streamTokenKey); token != "" {
+		return token
+	}
+	return sessionID
+}
+
+// sessionManifestURL returns the signed manifest URL for a session's
+// container: the HLS master playlist or the DASH manifest
+func sessionManifestURL(session *database.TranscodeSession, userID uint32) string {
+	container := ""
+	if request, err := session.GetRequest(); err == nil && request != nil {
+		container = request.Container
+	}
+	return streamurl.Manifest(session.ID, userID, container)
+}
+
+// sessionHLSURL returns the signed URL of the HLS master playlist a DASH ABR
+// session writes beside its manifest, or "" for other sessions
+func sessionHLSURL(session *database.TranscodeSession, userID uint32) string {
+	request, err := session.GetRequest()
+	if err != nil || request == nil || request.Container != "dash" || !request.EnableABR {
+		return ""
+	}
+	return streamurl.File(session.ID, userID, "master.m3u8")
+}
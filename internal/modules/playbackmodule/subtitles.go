@@ -0,0 +1,227 @@
+package playbackmodule
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/mantonx/viewra/internal/database"
+	"github.com/mantonx/viewra/internal/modules/assetmodule"
+	"github.com/mantonx/viewra/internal/modules/subtitlemodule"
+)
+
+// Subtitle conversion methods
+const (
+	SubtitleMethodWebVTT = subtitlemodule.MethodWebVTT
+	SubtitleMethodBurnIn = subtitlemodule.MethodBurnIn
+)
+
+// Subtitle styles for WebVTT conversion
+const (
+	SubtitleStyleStyled = subtitlemodule.StyleStyled
+	SubtitleStylePlain  = subtitlemodule.StylePlain
+)
+
+// SubtitleTrack describes a subtitle track and how it can be delivered to browsers
+type SubtitleTrack struct {
+	Index    int    `json:"index"` // Counted among subtitle streams, as in 0:s:N
+	Codec    string `json:"codec"`
+	Language string `json:"language,omitempty"`
+	Title    string `json:"title,omitempty"`
+	Default  bool   `json:"default"`
+	Forced   bool   `json:"forced"`
+	Method   string `json:"method"`
+	URL      string `json:"url,omitempty"`
+}
+
+// subtitleStream is the stored probe info for a subtitle stream
+type subtitleStream struct {
+	Codec    string `json:"codec"`
+	Language string `json:"language"`
+	Title    string `json:"title"`
+	Default  bool   `json:"default"`
+	Forced   bool   `json:"forced"`
+}
+
+// GetSubtitleTracks lists a media file's subtitle tracks with their conversion method
+func (m *Manager) GetSubtitleTracks(mediaFileID string) ([]SubtitleTrack, error) {
+	mediaFile, streams, err := m.loadSubtitleStreams(mediaFileID)
+	if err != nil {
+		return nil, err
+	}
+
+	tracks := make([]SubtitleTrack, 0, len(streams))
+	for i, stream := range streams {
+		track := SubtitleTrack{
+			Index:    i,
+			Codec:    stream.Codec,
+			Language: stream.Language,
+			Title:    stream.Title,
+			Default:  stream.Default,
+			Forced:   stream.Forced,
+			Method:   subtitlemodule.Method(stream.Codec),
+		}
+		if track.Method == SubtitleMethodWebVTT {
+			track.URL = fmt.Sprintf("/api/playback/subtitles/%s/%d.vtt", mediaFile.ID, i)
+		}
+		tracks = append(tracks, track)
+	}
+
+	return tracks, nil
+}
+
+// GetSubtitleWebVTT returns a subtitle track as WebVTT, converting it on first
+// request and serving the cached asset afterwards
+func (m *Manager) GetSubtitleWebVTT(ctx context.Context, mediaFileID string, trackIndex int, style string) ([]byte, error) {
+	if style == "" {
+		style = SubtitleStyleStyled
+	}
+	if style != SubtitleStyleStyled && style != SubtitleStylePlain {
+		return nil, fmt.Errorf("unknown subtitle style %q", style)
+	}
+
+	mediaFile, streams, err := m.loadSubtitleStreams(mediaFileID)
+	if err != nil {
+		return nil, err
+	}
+	if trackIndex < 0 || trackIndex >= len(streams) {
+		return nil, fmt.Errorf("subtitle track %d not found", trackIndex)
+	}
+
+	stream := streams[trackIndex]
+	if subtitlemodule.Method(stream.Codec) == SubtitleMethodBurnIn {
+		return nil, &ImageSubtitleError{Codec: stream.Codec}
+	}
+
+	entityType, entityID, cacheable := subtitlemodule.AssetEntity(mediaFile)
+	variant := subtitlemodule.EmbeddedVariant(mediaFile.ID, trackIndex, style)
+
+	if cacheable {
+		if data, ok := m.getCachedSubtitle(entityType, entityID, variant); ok {
+			return data, nil
+		}
+	}
+
+	data, err := subtitlemodule.ConvertToWebVTT(ctx, mediaFile.Path, trackIndex)
+	if err != nil {
+		return nil, err
+	}
+	if style == SubtitleStylePlain {
+		data = subtitlemodule.StripMarkup(data)
+	}
+
+	if cacheable {
+		_, err := assetmodule.SaveMediaAsset(&assetmodule.AssetRequest{
+			EntityType: entityType,
+			EntityID:   entityID,
+			Type:       assetmodule.AssetTypeSubtitle,
+			Source:     assetmodule.SourceCore,
+			Data:       data,
+			Format:     "text/vtt",
+			Language:   stream.Language,
+			Variant:    variant,
+		})
+		if err != nil {
+			m.logger.Warn("failed to cache converted subtitle", "media_file_id", mediaFile.ID, "track", trackIndex, "error", err)
+		}
+	}
+
+	m.logger.Info("converted subtitle track to WebVTT",
+		"media_file_id", mediaFile.ID,
+		"track", trackIndex,
+		"codec", stream.Codec,
+		"style", style,
+		"bytes", len(data))
+
+	return data, nil
+}
+
+// ImageSubtitleError is returned for bitmap subtitle tracks, which can only be
+// shown by burning them into the video during transcoding
+type ImageSubtitleError struct {
+	Codec string
+}
+
+func (e *ImageSubtitleError) Error() string {
+	return fmt.Sprintf("%s subtitles are image-based and must be burned in", e.Codec)
+}
+
+// loadSubtitleStreams loads a media file and its stored subtitle stream info
+func (m *Manager) loadSubtitleStreams(mediaFileID string) (*database.MediaFile, []subtitleStream, error) {
+	var mediaFile database.MediaFile
+	if err := m.db.Where("id = ?", mediaFileID).First(&mediaFile).Error; err != nil {
+		return nil, nil, fmt.Errorf("media file not found: %w", err)
+	}
+
+	var streams []subtitleStream
+	if mediaFile.SubtitleStreams != "" {
+		if err := json.Unmarshal([]byte(mediaFile.SubtitleStreams), &streams); err != nil {
+			return nil, nil, fmt.Errorf("failed to parse subtitle streams: %w", err)
+		}
+	}
+
+	return &mediaFile, streams, nil
+}
+
+// getCachedSubtitle returns a previously converted subtitle asset
+func (m *Manager) getCachedSubtitle(entityType assetmodule.EntityType, entityID uuid.UUID, variant string) ([]byte, bool) {
+	assets, err := assetmodule.GetMediaAssetsByEntity(entityType, entityID, &assetmodule.AssetFilter{
+		Type:    assetmodule.AssetTypeSubtitle,
+		Variant: variant,
+		Limit:   1,
+	})
+	if err != nil || len(assets) == 0 {
+		return nil, false
+	}
+
+	assetManager := assetmodule.GetAssetManager()
+	if assetManager == nil {
+		return nil, false
+	}
+	data, _, err := assetManager.GetAssetData(assets[0].ID)
+	if err != nil {
+		return nil, false
+	}
+	return data, true
+}
+
+// HandleListSubtitles lists a media file's subtitle tracks
+func (h *APIHandler) HandleListSubtitles(c *gin.Context) {
+	tracks, err := h.manager.GetSubtitleTracks(c.Param("mediaFileId"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"tracks": tracks})
+}
+
+// HandleGetSubtitle serves a subtitle track as WebVTT
+func (h *APIHandler) HandleGetSubtitle(c *gin.Context) {
+	trackIndex, err := strconv.Atoi(strings.TrimSuffix(c.Param("track"), ".vtt"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid subtitle track"})
+		return
+	}
+
+	data, err := h.manager.GetSubtitleWebVTT(c.Request.Context(), c.Param("mediaFileId"), trackIndex, c.Query("style"))
+	if err != nil {
+		if imageErr, ok := err.(*ImageSubtitleError); ok {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{
+				"error":  imageErr.Error(),
+				"method": SubtitleMethodBurnIn,
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Header("Cache-Control", "public, max-age=86400")
+	c.Data(http.StatusOK, "text/vtt; charset=utf-8", data)
+}
@@ -0,0 +1,293 @@
+package playbackmodule
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mantonx/viewra/internal/database"
+	"github.com/mantonx/viewra/internal/modules/subtitlemodule"
+	"github.com/mantonx/viewra/internal/types"
+	plugins "github.com/mantonx/viewra/sdk"
+)
+
+// Subtitle modes for UserPlaybackPreferences.SubtitleMode
+const (
+	SubtitleModeForced  = "forced"  // Only forced subtitles for the selected audio language
+	SubtitleModeForeign = "foreign" // Forced subtitles, plus full subtitles when the audio isn't a preferred language
+	SubtitleModeAlways  = "always"  // Full subtitles in a preferred language whenever available
+	SubtitleModeOff     = "off"     // Never select subtitles
+)
+
+// probedTrack is the subset of stored audio/subtitle stream info used for track selection
+type probedTrack struct {
+	Language string `json:"language"`
+	Title    string `json:"title"`
+	Codec    string `json:"codec"`
+	Default  bool   `json:"default"`
+	Forced   bool   `json:"forced"`
+}
+
+// languageAliases maps ISO 639-2 codes to ISO 639-1 where the prefix doesn't match
+var languageAliases = map[string]string{
+	"eng": "en", "fre": "fr", "fra": "fr", "ger": "de", "deu": "de", "spa": "es",
+	"ita": "it", "por": "pt", "rus": "ru", "jpn": "ja", "kor": "ko", "chi": "zh",
+	"zho": "zh", "dut": "nl", "nld": "nl", "swe": "sv", "dan": "da", "nor": "no",
+	"nob": "no", "fin": "fi", "pol": "pl", "cze": "cs", "ces": "cs", "gre": "el",
+	"ell": "el", "tur": "tr", "ara": "ar", "heb": "he", "hin": "hi", "hun": "hu",
+	"rum": "ro", "ron": "ro", "tha": "th", "vie": "vi", "ukr": "uk", "ind": "id",
+}
+
+// normalizeLanguage lowercases a language code and maps 3-letter codes to 2-letter ones
+func normalizeLanguage(language string) string {
+	language = strings.ToLower(strings.TrimSpace(language))
+	if alias, ok := languageAliases[language]; ok {
+		return alias
+	}
+	if language == "und" {
+		return ""
+	}
+	return language
+}
+
+// splitLanguages parses a comma-separated preference list
+func splitLanguages(list string) []string {
+	var languages []string
+	for _, language := range strings.Split(list, ",") {
+		if normalized := normalizeLanguage(language); normalized != "" {
+			languages = append(languages, normalized)
+		}
+	}
+	return languages
+}
+
+// applyTrackSelection chooses audio and subtitle tracks for the decision and
+// points the transcode at the selected audio track. A selected image-based
+// subtitle, which can't be served as WebVTT, is burned into the transcode.
+func (m *Manager) applyTrackSelection(decision *PlaybackDecision, mediaPath string, deviceProfile *DeviceProfile) {
+	if m.db == nil || decision == nil {
+		return
+	}
+
+	var mediaFile database.MediaFile
+	if err := m.db.Where("path = ?", mediaPath).First(&mediaFile).Error; err != nil {
+		return
+	}
+
+	var audio, subtitles []probedTrack
+	if mediaFile.AudioStreams != "" {
+		if err := json.Unmarshal([]byte(mediaFile.AudioStreams), &audio); err != nil {
+			m.logger.Warn("failed to parse audio streams", "media_file_id", mediaFile.ID, "error", err)
+		}
+	}
+	if mediaFile.SubtitleStreams != "" {
+		if err := json.Unmarshal([]byte(mediaFile.SubtitleStreams), &subtitles); err != nil {
+			m.logger.Warn("failed to parse subtitle streams", "media_file_id", mediaFile.ID, "error", err)
+		}
+	}
+
+	var prefs *database.UserPlaybackPreferences
+	if deviceProfile != nil && deviceProfile.UserID != 0 {
+		prefs = m.getPlaybackPreferences(deviceProfile.UserID)
+	}
+
+	decision.Tracks = selectTracks(audio, subtitles, prefs)
+	if decision.TranscodeParams != nil {
+		decision.TranscodeParams.AudioStreamIndex = decision.Tracks.AudioIndex
+
+		index := decision.Tracks.SubtitleIndex
+		if index >= 0 && index < len(subtitles) && subtitlemodule.Method(subtitles[index].Codec) == SubtitleMethodBurnIn {
+			decision.TranscodeParams.SubtitleMode = plugins.SubtitleBurnIn
+			decision.TranscodeParams.SubtitleTrackIndex = index
+			decision.TranscodeParams.SubtitleCodec = subtitles[index].Codec
+		}
+	}
+}
+
+// getPlaybackPreferences returns the user's stored preferences, or nil if none are set
+func (m *Manager) getPlaybackPreferences(userID uint32) *database.UserPlaybackPreferences {
+	var prefs []database.UserPlaybackPreferences
+	if err := m.db.Where("user_id = ?", userID).Limit(1).Find(&prefs).Error; err != nil || len(prefs) == 0 {
+		return nil
+	}
+	return &prefs[0]
+}
+
+// selectTracks applies the stream disposition flags and user language
+// preferences. Preferred languages win; the default flag breaks ties within a
+// language and decides when no preference matches.
+func selectTracks(audio, subtitles []probedTrack, prefs *database.UserPlaybackPreferences) *types.TrackSelection {
+	selection := &types.TrackSelection{SubtitleIndex: -1}
+
+	var audioLanguages, subtitleLanguages []string
+	mode := ""
+	if prefs != nil {
+		audioLanguages = splitLanguages(prefs.AudioLanguages)
+		subtitleLanguages = splitLanguages(prefs.SubtitleLanguages)
+		mode = strings.ToLower(prefs.SubtitleMode)
+	}
+
+	// Audio: first preferred language present, otherwise the default track
+	selection.AudioIndex = -1
+	for _, language := range audioLanguages {
+		if index := pickTrack(audio, language, false); index >= 0 {
+			selection.AudioIndex = index
+			selection.Reason = "preferred audio language"
+			break
+		}
+	}
+	if selection.AudioIndex < 0 {
+		selection.AudioIndex = pickTrack(audio, "", false)
+		selection.Reason = "default audio track"
+	}
+	if selection.AudioIndex < 0 {
+		selection.AudioIndex = 0
+		selection.Reason = "first audio track"
+	}
+	if selection.AudioIndex < len(audio) {
+		selection.AudioLanguage = normalizeLanguage(audio[selection.AudioIndex].Language)
+	}
+
+	if mode == SubtitleModeOff || len(subtitles) == 0 {
+		return selection
+	}
+
+	// Forced subtitles translate foreign dialogue for viewers of the selected audio
+	for i, track := range subtitles {
+		language := normalizeLanguage(track.Language)
+		if track.Forced && (language == "" || language == selection.AudioLanguage) {
+			setSubtitle(selection, i, track, "forced subtitles for audio language")
+			return selection
+		}
+	}
+
+	wantFull := mode == SubtitleModeAlways
+	if mode == SubtitleModeForeign && len(subtitleLanguages) > 0 {
+		wantFull = !containsLanguage(audioLanguages, selection.AudioLanguage)
+	}
+	if wantFull {
+		for _, language := range subtitleLanguages {
+			if index := pickTrack(subtitles, language, true); index >= 0 {
+				setSubtitle(selection, index, subtitles[index], "preferred subtitle language")
+				return selection
+			}
+		}
+	}
+
+	// Without an explicit mode the file's own default subtitle is honored
+	if mode == "" {
+		for i, track := range subtitles {
+			if track.Default {
+				setSubtitle(selection, i, track, "default subtitle track")
+				return selection
+			}
+		}
+	}
+
+	return selection
+}
+
+// setSubtitle records the chosen subtitle track
+func setSubtitle(s *types.TrackSelection, index int, track probedTrack, reason string) {
+	s.SubtitleIndex = index
+	s.SubtitleLanguage = normalizeLanguage(track.Language)
+	s.SubtitleForced = track.Forced
+	s.Reason += ", " + reason
+}
+
+// pickTrack returns the best track in a language (any language when empty):
+// default-flagged first, then anything that isn't commentary, then the first
+// match. Forced tracks are skipped when skipForced is set.
+func pickTrack(tracks []probedTrack, language string, skipForced bool) int {
+	first, regular := -1, -1
+	for i, track := range tracks {
+		if language != "" && normalizeLanguage(track.Language) != language {
+			continue
+		}
+		if skipForced && track.Forced {
+			continue
+		}
+		if track.Default {
+			return i
+		}
+		if first < 0 {
+			first = i
+		}
+		if regular < 0 && !strings.Contains(strings.ToLower(track.Title), "commentary") {
+			regular = i
+		}
+	}
+	if language == "" {
+		// Nothing flagged default; let the caller fall back to the first track
+		return -1
+	}
+	if regular >= 0 {
+		return regular
+	}
+	return first
+}
+
+// containsLanguage reports whether languages contains language
+func containsLanguage(languages []string, language string) bool {
+	for _, l := range languages {
+		if l == language {
+			return true
+		}
+	}
+	return false
+}
+
+// HandleGetPlaybackPreferences returns a user's track and watch preferences
+func (h *APIHandler) HandleGetPlaybackPreferences(c *gin.Context) {
+	userID, err := strconv.ParseUint(c.Param("userId"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user ID"})
+		return
+	}
+
+	prefs := h.manager.getPlaybackPreferences(uint32(userID))
+	if prefs == nil {
+		prefs = &database.UserPlaybackPreferences{UserID: uint32(userID)}
+	}
+
+	c.JSON(http.StatusOK, prefs)
+}
+
+// HandleUpdatePlaybackPreferences stores a user's track and watch preferences
+func (h *APIHandler) HandleUpdatePlaybackPreferences(c *gin.Context) {
+	userID, err := strconv.ParseUint(c.Param("userId"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user ID"})
+		return
+	}
+
+	var prefs database.UserPlaybackPreferences
+	if err := c.ShouldBindJSON(&prefs); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	switch strings.ToLower(prefs.SubtitleMode) {
+	case "", SubtitleModeForced, SubtitleModeForeign, SubtitleModeAlways, SubtitleModeOff:
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("unknown subtitle mode %q", prefs.SubtitleMode)})
+		return
+	}
+
+	if err := validateWatchPreferences(&prefs); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	prefs.UserID = uint32(userID)
+	prefs.SubtitleMode = strings.ToLower(prefs.SubtitleMode)
+	if err := h.manager.db.Save(&prefs).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to save preferences: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, prefs)
+}
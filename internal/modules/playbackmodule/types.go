@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/mantonx/viewra/internal/database"
+	"github.com/mantonx/viewra/internal/types"
 	plugins "github.com/mantonx/viewra/sdk"
 )
 
@@ -20,6 +21,10 @@ type DeviceProfile struct {
 	SupportsAV1     bool     `json:"supports_av1"`
 	SupportsHDR     bool     `json:"supports_hdr"`
 	ClientIP        string   `json:"client_ip"`
+	UserID          uint32   `json:"user_id,omitempty"`          // Used to look up track language preferences
+	DeviceID        string   `json:"device_id,omitempty"`        // Registered device whose stored capabilities apply
+	DRMSystems      []string `json:"drm_systems,omitempty"`      // e.g. widevine, playready, fairplay
+	TargetContainer string   `json:"target_container,omitempty"` // "hls" or "dash" to pick the streaming format; empty picks by user agent
 }
 
 // PlaybackDecision represents the decision made by the planner
@@ -29,6 +34,7 @@ type PlaybackDecision struct {
 	StreamURL       string                    `json:"stream_url,omitempty"`        // URL for streaming (either direct or transcoded)
 	ManifestURL     string                    `json:"manifest_url,omitempty"`      // URL for DASH/HLS manifest
 	TranscodeParams *plugins.TranscodeRequest `json:"transcode_params,omitempty"`
+	Tracks          *types.TrackSelection     `json:"tracks,omitempty"`
 	Reason          string                    `json:"reason"`
 	SessionID       string                    `json:"session_id,omitempty"` // Transcoding session ID if applicable
 }
@@ -0,0 +1,66 @@
+package playbackmodule
+
+import (
+	"context"
+
+	plugins "github.com/mantonx/viewra/sdk"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// maxPluginHistoryEntries caps the entries of one plugin history import;
+// plugins syncing longer histories send them in pages
+const maxPluginHistoryEntries = 1000
+
+// watchHistoryService imports watch history sent by plugins, such as the
+// watches and ratings a scrobbler syncs back from its service
+type watchHistoryService struct {
+	manager *Manager
+}
+
+// WatchHistoryService returns the service plugins add watch history through
+func (m *Manager) WatchHistoryService() plugins.WatchHistoryService {
+	return &watchHistoryService{manager: m}
+}
+
+// ImportHistory implements plugins.WatchHistoryService like a history file import
+func (s *watchHistoryService) ImportHistory(ctx context.Context, req *plugins.WatchHistoryImportRequest) (*plugins.WatchHistoryImportResponse, error) {
+	if len(req.Entries) > maxPluginHistoryEntries {
+		return nil, status.Errorf(codes.InvalidArgument, "too many history entries: %d, at most %d per import", len(req.Entries), maxPluginHistoryEntries)
+	}
+
+	entries := make([]HistoryEntry, 0, len(req.Entries))
+	for _, entry := range req.Entries {
+		if entry == nil {
+			continue
+		}
+		entries = append(entries, HistoryEntry{
+			Kind:           entry.Kind,
+			MediaID:        entry.MediaID,
+			MediaType:      entry.MediaType,
+			Title:          entry.Title,
+			Year:           entry.Year,
+			ShowTitle:      entry.ShowTitle,
+			ShowTmdbID:     entry.ShowTmdbID,
+			Season:         entry.Season,
+			Episode:        entry.Episode,
+			TmdbID:         entry.TmdbID,
+			ImdbID:         entry.ImdbID,
+			Date:           entry.Date,
+			WatchedSeconds: entry.WatchedSeconds,
+			Completed:      entry.Completed,
+			Rating:         entry.Rating,
+		})
+	}
+
+	result, err := s.manager.ImportHistory(req.UserID, entries)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to import watch history: %v", err)
+	}
+	return &plugins.WatchHistoryImportResponse{
+		Watches:   result.Watches,
+		Ratings:   result.Ratings,
+		Duplicate: result.Duplicate,
+		Unmatched: result.Unmatched,
+	}, nil
+}
@@ -0,0 +1,350 @@
+package playbackmodule
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mantonx/viewra/internal/database"
+	"gorm.io/gorm"
+)
+
+// Marker types stored as MediaMarker.Type
+const (
+	MarkerTypeIntro   = "intro"
+	MarkerTypeCredits = "credits"
+)
+
+// Auto-advance modes for UserPlaybackPreferences.AutoAdvance
+const (
+	AutoAdvanceCredits = "credits" // Count down to the next episode when the credits start
+	AutoAdvanceEnd     = "end"     // Count down at the end of the file
+	AutoAdvanceOff     = "off"     // Never start the next episode automatically
+)
+
+const (
+	defaultAutoAdvanceCountdown = 10
+	maxAutoAdvanceCountdown     = 60
+	minWatchedPercent           = 50
+	// minCreditsShare is how far into a file a credits marker must start to
+	// count; markers earlier than this are likely misdetections
+	minCreditsShare = 0.5
+)
+
+// WatchRules decide when an item counts as watched and what happens at its end
+type WatchRules struct {
+	WatchedFraction      float64 `json:"watched_fraction"`
+	UseCreditsMarker     bool    `json:"use_credits_marker"`
+	AutoAdvance          string  `json:"auto_advance"`
+	AutoAdvanceCountdown int     `json:"auto_advance_countdown"`
+}
+
+// AutoAdvance tells the player when to offer the next episode
+type AutoAdvance struct {
+	Mode             string  `json:"mode"`
+	CountdownSeconds int     `json:"countdown_seconds"`
+	StartsAtSeconds  float64 `json:"starts_at_seconds,omitempty"` // Position at which to show the countdown
+}
+
+// NextUp is the episode that follows a media file, for auto-advance
+type NextUp struct {
+	EpisodeID     string `json:"episode_id"`
+	MediaFileID   string `json:"media_file_id"`
+	Title         string `json:"title"`
+	SeasonNumber  int    `json:"season_number"`
+	EpisodeNumber int    `json:"episode_number"`
+}
+
+// watchRulesFor resolves a user's watched and auto-advance preferences,
+// falling back to the defaults for unset values
+func (m *Manager) watchRulesFor(userID uint32) WatchRules {
+	rules := WatchRules{
+		WatchedFraction:      completedThreshold,
+		UseCreditsMarker:     true,
+		AutoAdvance:          AutoAdvanceCredits,
+		AutoAdvanceCountdown: defaultAutoAdvanceCountdown,
+	}
+	prefs := m.getPlaybackPreferences(userID)
+	if prefs == nil {
+		return rules
+	}
+	if prefs.WatchedPercent > 0 {
+		rules.WatchedFraction = float64(prefs.WatchedPercent) / 100
+	}
+	rules.UseCreditsMarker = !prefs.IgnoreCreditsMarker
+	if prefs.AutoAdvance != "" {
+		rules.AutoAdvance = prefs.AutoAdvance
+	}
+	if prefs.AutoAdvanceCountdown > 0 {
+		rules.AutoAdvanceCountdown = prefs.AutoAdvanceCountdown
+	}
+	return rules
+}
+
+// validateWatchPreferences checks and normalizes the watched and
+// auto-advance fields of playback preferences
+func validateWatchPreferences(prefs *database.UserPlaybackPreferences) error {
+	if prefs.WatchedPercent != 0 && (prefs.WatchedPercent < minWatchedPercent || prefs.WatchedPercent > 100) {
+		return fmt.Errorf("watched percent must be between %d and 100", minWatchedPercent)
+	}
+	prefs.AutoAdvance = strings.ToLower(prefs.AutoAdvance)
+	switch prefs.AutoAdvance {
+	case "", AutoAdvanceCredits, AutoAdvanceEnd, AutoAdvanceOff:
+	default:
+		return fmt.Errorf("unknown auto-advance mode %q", prefs.AutoAdvance)
+	}
+	if prefs.AutoAdvanceCountdown < 0 || prefs.AutoAdvanceCountdown > maxAutoAdvanceCountdown {
+		return fmt.Errorf("auto-advance countdown must be between 0 and %d seconds", maxAutoAdvanceCountdown)
+	}
+	return nil
+}
+
+// creditsStart returns where the end credits of a media file begin, if a
+// plausible credits marker has been detected
+func (m *Manager) creditsStart(mediaFileID string, duration float64) (float64, bool) {
+	var markers []database.MediaMarker
+	err := m.db.Where("media_file_id = ? AND type = ?", mediaFileID, MarkerTypeCredits).
+		Order("start_seconds DESC").Limit(1).Find(&markers).Error
+	if err != nil || len(markers) == 0 {
+		return 0, false
+	}
+	start := markers[0].StartSeconds
+	if duration > 0 && start < duration*minCreditsShare {
+		return 0, false
+	}
+	return start, true
+}
+
+// sessionWatched applies the watched rules to a session: the player said it
+// finished, the position reached the credits, or enough of it was played
+func (m *Manager) sessionWatched(session *database.PlaybackSession, rules WatchRules) bool {
+	if session.Completed {
+		return true
+	}
+	progress := max(session.PositionSeconds, session.WatchedSeconds)
+	if rules.UseCreditsMarker && session.PositionSeconds > 0 {
+		if start, ok := m.creditsStart(session.MediaFileID, session.DurationSeconds); ok && session.PositionSeconds >= start {
+			return true
+		}
+	}
+	return session.DurationSeconds > 0 && progress >= session.DurationSeconds*rules.WatchedFraction
+}
+
+// GetNextUp returns the episode after a media file and when the player
+// should offer it. next is nil for movies, the last episode of a show, or
+// when the following episode has no file.
+func (m *Manager) GetNextUp(mediaFileID string, userID uint32) (*NextUp, *AutoAdvance, error) {
+	var file database.MediaFile
+	if err := m.db.Select("id, media_id, media_type, resolution, duration").Where("id = ?", mediaFileID).First(&file).Error; err != nil {
+		return nil, nil, fmt.Errorf("media file not found: %s", mediaFileID)
+	}
+
+	rules := m.watchRulesFor(userID)
+	advance := &AutoAdvance{Mode: rules.AutoAdvance, CountdownSeconds: rules.AutoAdvanceCountdown}
+	if file.MediaType != database.MediaTypeEpisode {
+		advance.Mode = AutoAdvanceOff
+		return nil, advance, nil
+	}
+
+	next, err := m.followingEpisode(&file)
+	if err != nil {
+		return nil, nil, err
+	}
+	if next == nil {
+		advance.Mode = AutoAdvanceOff
+		return nil, advance, nil
+	}
+
+	duration := float64(file.Duration)
+	switch advance.Mode {
+	case AutoAdvanceCredits:
+		if start, ok := m.creditsStart(file.ID, duration); ok {
+			advance.StartsAtSeconds = start
+			break
+		}
+		// Without a credits marker, fall back to the end of the file
+		advance.Mode = AutoAdvanceEnd
+		fallthrough
+	case AutoAdvanceEnd:
+		if duration > 0 {
+			advance.StartsAtSeconds = max(0, duration-float64(advance.CountdownSeconds))
+		}
+	}
+	return next, advance, nil
+}
+
+// followingEpisode finds the episode after the one a file belongs to. Regular
+// episodes are followed by the next regular episode, skipping specials;
+// specials by the next special. Of several versions, the one matching the
+// current file's resolution is picked, otherwise the highest bitrate.
+func (m *Manager) followingEpisode(file *database.MediaFile) (*NextUp, error) {
+	var current struct {
+		ShowID       string
+		SeasonNumber int
+		Number       int
+	}
+	err := m.db.Table("episodes").
+		Select("seasons.tv_show_id AS show_id, seasons.season_number, episodes.episode_number AS number").
+		Joins("JOIN seasons ON seasons.id = episodes.season_id").
+		Where("episodes.id = ?", file.MediaID).
+		Scan(&current).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to load episode: %w", err)
+	}
+	if current.ShowID == "" {
+		return nil, nil
+	}
+
+	query := m.db.Table("episodes").
+		Select("episodes.id, episodes.title, seasons.season_number, episodes.episode_number").
+		Joins("JOIN seasons ON seasons.id = episodes.season_id").
+		Where("seasons.tv_show_id = ?", current.ShowID).
+		Where("EXISTS (SELECT 1 FROM media_files WHERE media_files.media_id = episodes.id)")
+	if current.SeasonNumber == 0 {
+		query = query.Where("seasons.season_number = 0 AND episodes.episode_number > ?", current.Number)
+	} else {
+		query = query.Where("seasons.season_number > 0").
+			Where("seasons.season_number > ? OR (seasons.season_number = ? AND episodes.episode_number > ?)",
+				current.SeasonNumber, current.SeasonNumber, current.Number)
+	}
+
+	var episodes []struct {
+		ID            string
+		Title         string
+		SeasonNumber  int
+		EpisodeNumber int
+	}
+	if err := query.Order("seasons.season_number, episodes.episode_number").Limit(1).Scan(&episodes).Error; err != nil {
+		return nil, fmt.Errorf("failed to find next episode: %w", err)
+	}
+	if len(episodes) == 0 {
+		return nil, nil
+	}
+	next := episodes[0]
+
+	var files []database.MediaFile
+	err = m.db.Select("id, resolution, bitrate_kbps").
+		Where("media_id = ?", next.ID).
+		Order("bitrate_kbps DESC").
+		Find(&files).Error
+	if err != nil || len(files) == 0 {
+		return nil, err
+	}
+	chosen := files[0]
+	for _, f := range files {
+		if file.Resolution != "" && f.Resolution == file.Resolution {
+			chosen = f
+			break
+		}
+	}
+
+	return &NextUp{
+		EpisodeID:     next.ID,
+		MediaFileID:   chosen.ID,
+		Title:         next.Title,
+		SeasonNumber:  next.SeasonNumber,
+		EpisodeNumber: next.EpisodeNumber,
+	}, nil
+}
+
+// SetMarkers replaces the markers a source placed on a media file
+func (m *Manager) SetMarkers(mediaFileID, source string, markers []database.MediaMarker) error {
+	for i := range markers {
+		marker := &markers[i]
+		marker.Type = strings.ToLower(marker.Type)
+		if marker.Type != MarkerTypeIntro && marker.Type != MarkerTypeCredits {
+			return fmt.Errorf("unknown marker type %q", marker.Type)
+		}
+		if marker.StartSeconds < 0 || marker.EndSeconds < marker.StartSeconds {
+			return fmt.Errorf("invalid %s marker %.1f-%.1f", marker.Type, marker.StartSeconds, marker.EndSeconds)
+		}
+		marker.ID = 0
+		marker.MediaFileID = mediaFileID
+		marker.Source = source
+	}
+
+	return m.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("media_file_id = ? AND source = ?", mediaFileID, source).Delete(&database.MediaMarker{}).Error; err != nil {
+			return err
+		}
+		if len(markers) == 0 {
+			return nil
+		}
+		return tx.Create(&markers).Error
+	})
+}
+
+// HandleGetMarkers returns the intro and credits markers of a media file
+func (h *APIHandler) HandleGetMarkers(c *gin.Context) {
+	var markers []database.MediaMarker
+	if err := h.manager.db.Where("media_file_id = ?", c.Param("mediaFileId")).Order("start_seconds").Find(&markers).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"markers": markers})
+}
+
+// HandleSetMarkers replaces the markers one source placed on a media file.
+// Detectors report under their own source so they don't clobber each other
+// or hand-placed markers.
+func (h *APIHandler) HandleSetMarkers(c *gin.Context) {
+	var request struct {
+		Source  string                 `json:"source"`
+		Markers []database.MediaMarker `json:"markers"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if request.Source == "" {
+		request.Source = "manual"
+	}
+
+	mediaFileID := c.Param("mediaFileId")
+	var count int64
+	if err := h.manager.db.Model(&database.MediaFile{}).Where("id = ?", mediaFileID).Count(&count).Error; err != nil || count == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "media file not found"})
+		return
+	}
+
+	if err := h.manager.SetMarkers(mediaFileID, request.Source, request.Markers); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"markers": request.Markers})
+}
+
+// HandleGetNextUp returns the episode after a media file and the
+// auto-advance timing for the user in user_id
+func (h *APIHandler) HandleGetNextUp(c *gin.Context) {
+	var userID uint64
+	if userIDStr := c.Query("user_id"); userIDStr != "" {
+		var err error
+		if userID, err = strconv.ParseUint(userIDStr, 10, 32); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user ID"})
+			return
+		}
+	}
+
+	next, advance, err := h.manager.GetNextUp(c.Param("mediaFileId"), uint32(userID))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"next":         next,
+		"auto_advance": advance,
+	})
+}
+
+// RegisterWatchRuleRoutes registers marker and auto-advance endpoints
+func RegisterWatchRuleRoutes(api *gin.RouterGroup, handler *APIHandler) {
+	api.GET("/markers/:mediaFileId", handler.HandleGetMarkers)
+	api.PUT("/markers/:mediaFileId", handler.HandleSetMarkers)
+	api.GET("/next/:mediaFileId", handler.HandleGetNextUp)
+}
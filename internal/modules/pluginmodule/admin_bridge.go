@@ -0,0 +1,193 @@
+package pluginmodule
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	plugins "github.com/mantonx/viewra/sdk"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// adminBridgeTimeout bounds a single forwarded admin page request
+	adminBridgeTimeout = 30 * time.Second
+	// adminBridgeMaxBody limits request bodies forwarded to plugins. Responses are
+	// bounded by the gRPC client's default 4MB message limit.
+	adminBridgeMaxBody = 2 * 1024 * 1024
+)
+
+// hopHeaders are connection-level headers that aren't forwarded in either direction
+var hopHeaders = []string{
+	"Connection", "Keep-Alive", "Proxy-Authenticate", "Proxy-Authorization",
+	"Te", "Trailer", "Transfer-Encoding", "Upgrade", "Content-Length",
+}
+
+// ErrPluginNotRunning is returned when calling a plugin that isn't loaded
+var ErrPluginNotRunning = errors.New("plugin is not running")
+
+// ForwardHTTP sends an HTTP request to a running plugin's HTTP handler
+func (m *ExternalPluginManager) ForwardHTTP(ctx context.Context, pluginID string, req *plugins.BridgeRequest) (*plugins.BridgeResponse, error) {
+	client, ok := m.runningClient(pluginID)
+	if !ok {
+		return nil, ErrPluginNotRunning
+	}
+	if err := client.requireService(plugins.HTTPBridgeServiceName); err != nil {
+		return nil, err
+	}
+
+	return plugins.ForwardHTTP(ctx, client.conn, req)
+}
+
+// proxyAdminPageHandler forwards /admin/plugins/:id/* to the plugin's HTTP handler,
+// letting plugins render the admin pages they advertise through GetAdminPages
+func (pm *PluginModule) proxyAdminPageHandler(c *gin.Context) {
+	pluginID := c.Param("id")
+	if pm.externalManager == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "External plugins are not available"})
+		return
+	}
+	if _, exists := pm.externalManager.GetPlugin(pluginID); !exists {
+		c.JSON(http.StatusNotFound, gin.H{"error": ErrPluginNotFound})
+		return
+	}
+	body, ok := readBridgeBody(c, adminBridgeMaxBody)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), adminBridgeTimeout)
+	defer cancel()
+
+	resp, err := pm.externalManager.ForwardHTTP(ctx, pluginID,
+		newBridgeRequest(c, "/admin/plugins/"+pluginID, c.Param("path"), body))
+	if err != nil {
+		pm.writeBridgeError(c, pluginID, err, "Plugin does not serve admin page content")
+		return
+	}
+	writeBridgeResponse(c, resp)
+}
+
+// readBridgeBody reads the request body, responding with 413 when it exceeds limit
+func readBridgeBody(c *gin.Context, limit int64) ([]byte, bool) {
+	body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, limit))
+	if err != nil {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{
+			"error":   "Request body too large",
+			"details": err.Error(),
+		})
+		return nil, false
+	}
+	return body, true
+}
+
+// newBridgeRequest builds the request forwarded to a plugin, with prefix
+// stripped from the path and passed along in X-Forwarded-Prefix
+func newBridgeRequest(c *gin.Context, prefix, path string, body []byte) *plugins.BridgeRequest {
+	header := c.Request.Header.Clone()
+	for _, name := range hopHeaders {
+		header.Del(name)
+	}
+	// Plugins get the authenticated user instead of the caller's credentials
+	header.Del("Authorization")
+	header.Del(pluginUserHeader)
+	if user := pluginRequestUser(c); user != "" {
+		header.Set(pluginUserHeader, user)
+	}
+	rawQuery := c.Request.URL.RawQuery
+	if query := c.Request.URL.Query(); query.Has("access_token") {
+		query.Del("access_token")
+		rawQuery = query.Encode()
+	}
+	header.Set("X-Forwarded-Prefix", prefix)
+	header.Set("X-Forwarded-For", c.ClientIP())
+
+	return &plugins.BridgeRequest{
+		Method:     c.Request.Method,
+		Path:       "/" + strings.TrimPrefix(path, "/"),
+		RawQuery:   rawQuery,
+		Header:     header,
+		Body:       body,
+		RemoteAddr: c.Request.RemoteAddr,
+	}
+}
+
+// writeBridgeError maps a failed forward to an HTTP status
+func (pm *PluginModule) writeBridgeError(c *gin.Context, pluginID string, err error, unimplemented string) {
+	switch {
+	case errors.Is(err, ErrPluginNotRunning):
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Plugin is not running"})
+	case status.Code(err) == codes.Unimplemented:
+		c.JSON(http.StatusNotFound, gin.H{"error": unimplemented})
+	case status.Code(err) == codes.DeadlineExceeded:
+		c.JSON(http.StatusGatewayTimeout, gin.H{"error": "Plugin did not respond in time"})
+	default:
+		if pluginErr, ok := plugins.AsPluginError(err); ok {
+			writePluginError(c, pluginErr)
+			return
+		}
+		pm.logger.Warn("request to plugin failed", "plugin_id", pluginID, "error", err)
+		c.JSON(http.StatusBadGateway, gin.H{
+			"error":   "Plugin request failed",
+			"details": err.Error(),
+		})
+	}
+}
+
+// writePluginError maps a typed plugin error to an HTTP status with its code
+func writePluginError(c *gin.Context, pluginErr *plugins.PluginError) {
+	statusCode := http.StatusBadGateway
+	message := "Plugin request failed"
+	switch pluginErr.Code {
+	case plugins.ErrorCodeRateLimited:
+		statusCode = http.StatusTooManyRequests
+		message = "Plugin is rate limited by its provider"
+		if pluginErr.RetryAfter > 0 {
+			c.Header("Retry-After", strconv.Itoa(int(math.Ceil(pluginErr.RetryAfter.Seconds()))))
+		}
+	case plugins.ErrorCodeNotFound:
+		statusCode = http.StatusNotFound
+		message = "Not found"
+	case plugins.ErrorCodeAuthFailed:
+		// The plugin's credentials were rejected, not the caller's
+		message = "Plugin could not authenticate with its provider; check the plugin's API key"
+	case plugins.ErrorCodeTemporary:
+		statusCode = http.StatusServiceUnavailable
+		message = "Plugin is temporarily unavailable"
+	case plugins.ErrorCodeInvalidArgument:
+		statusCode = http.StatusBadRequest
+		message = "Invalid request"
+	}
+
+	c.JSON(statusCode, gin.H{
+		"error":   message,
+		"code":    pluginErr.Code,
+		"details": pluginErr.Error(),
+	})
+}
+
+// writeBridgeResponse copies a plugin's response to the client
+func writeBridgeResponse(c *gin.Context, resp *plugins.BridgeResponse) {
+	for name, values := range resp.Header {
+		for _, value := range values {
+			c.Writer.Header().Add(name, value)
+		}
+	}
+	for _, name := range hopHeaders {
+		c.Writer.Header().Del(name)
+	}
+
+	statusCode := resp.Status
+	if statusCode == 0 {
+		statusCode = http.StatusOK
+	}
+	c.Status(statusCode)
+	c.Writer.Write(resp.Body)
+}
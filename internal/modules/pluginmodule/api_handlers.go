@@ -69,6 +69,12 @@ func (h *PluginAPIHandlers) RegisterRoutes(router *gin.Engine) {
 		pluginAPI.GET("/categories", h.handleGetPluginCategories)
 		pluginAPI.GET("/capabilities", h.handleGetSystemCapabilities)
 
+		// Scanner Hook Results
+		pluginAPI.GET("/hook-results/stats", h.handleGetHookResultStats)
+		pluginAPI.GET("/hook-results/unmatched", h.handleGetUnmatchedItems)
+		pluginAPI.GET("/hook-results/reviews", h.handleGetReviewQueue)
+		pluginAPI.POST("/hook-results/reviews/:media_file_id/reject", h.handleRejectReview)
+
 		// Individual Plugin Management
 		pluginAPI.GET("/:id", h.handleGetPlugin)
 		pluginAPI.PUT("/:id", h.handleUpdatePlugin)
@@ -90,6 +96,7 @@ func (h *PluginAPIHandlers) RegisterRoutes(router *gin.Engine) {
 		pluginAPI.GET("/:id/config", h.handleGetPluginConfig)
 		pluginAPI.PUT("/:id/config", h.handleUpdatePluginConfig)
 		pluginAPI.GET("/:id/config/schema", h.handleGetPluginConfigSchema)
+		pluginAPI.GET("/:id/config/json-schema", h.handleGetPluginConfigJSONSchema)
 		pluginAPI.POST("/:id/config/validate", h.handleValidatePluginConfig)
 		pluginAPI.POST("/:id/config/reset", h.handleResetPluginConfig)
 
@@ -681,6 +688,31 @@ func (h *PluginAPIHandlers) handleGetPluginConfigSchema(c *gin.Context) {
 	h.successResponse(c, schema, "Plugin configuration schema retrieved successfully")
 }
 
+// handleGetPluginConfigJSONSchema returns the plugin's settings as JSON Schema for form rendering
+func (h *PluginAPIHandlers) handleGetPluginConfigJSONSchema(c *gin.Context) {
+	pluginID := c.Param("id")
+	if pluginID == "" {
+		h.errorResponse(c, http.StatusBadRequest,
+			fmt.Errorf("plugin ID required"), "Plugin ID parameter is required")
+		return
+	}
+
+	if h.pluginModule == nil {
+		h.errorResponse(c, http.StatusServiceUnavailable,
+			fmt.Errorf("plugin module not available"), "Plugin module unavailable")
+		return
+	}
+
+	schema, err := h.pluginModule.GetConfigurationJSONSchema(c.Request.Context(), pluginID)
+	if err != nil {
+		h.errorResponse(c, http.StatusInternalServerError, err,
+			"Failed to retrieve plugin configuration JSON schema")
+		return
+	}
+
+	h.successResponse(c, schema, "Plugin configuration JSON schema retrieved successfully")
+}
+
 func (h *PluginAPIHandlers) handleValidatePluginConfig(c *gin.Context) {
 	pluginID := c.Param("id")
 	if pluginID == "" {
@@ -0,0 +1,295 @@
+package pluginmodule
+
+import (
+	"context"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mantonx/viewra/internal/database"
+)
+
+// capabilityQueryTimeout bounds each gRPC call made while describing a running plugin
+const capabilityQueryTimeout = 2 * time.Second
+
+// PluginCapabilities is a normalized description of what a plugin offers,
+// letting the frontend adapt its UI without knowing about specific plugins
+type PluginCapabilities struct {
+	ID                  string                   `json:"id"`
+	Name                string                   `json:"name"`
+	Type                string                   `json:"type"`
+	Version             string                   `json:"version"`
+	IsCore              bool                     `json:"is_core"`
+	Enabled             bool                     `json:"enabled"`
+	Running             bool                     `json:"running"`
+	MediaTypes          []string                 `json:"media_types"`
+	SupportedExtensions []string                 `json:"supported_extensions,omitempty"`
+	Features            []string                 `json:"features"`
+	Search              *SearchCapabilities      `json:"search,omitempty"`
+	AdminPages          []AdminPageCapability    `json:"admin_pages,omitempty"`
+	Routes              []RouteCapability        `json:"routes,omitempty"`
+	Transcoding         *TranscodingCapabilities `json:"transcoding,omitempty"`
+	ABI                 *PluginABI               `json:"abi,omitempty"`
+}
+
+// SearchCapabilities describes a plugin's search service
+type SearchCapabilities struct {
+	Fields             []string `json:"fields"`
+	SupportsPagination bool     `json:"supports_pagination"`
+	MaxResults         uint32   `json:"max_results,omitempty"`
+}
+
+// AdminPageCapability is an admin page a plugin contributes
+type AdminPageCapability struct {
+	ID       string `json:"id"`
+	Title    string `json:"title"`
+	Path     string `json:"path"`
+	URL      string `json:"url"`
+	Type     string `json:"type"`
+	Icon     string `json:"icon,omitempty"`
+	Category string `json:"category,omitempty"`
+}
+
+// RouteCapability is an API route a plugin registers under /api/plugins/<id>
+type RouteCapability struct {
+	Method      string `json:"method"`
+	Path        string `json:"path"`
+	Description string `json:"description,omitempty"`
+}
+
+// TranscodingCapabilities describes what a transcoder plugin can produce
+type TranscodingCapabilities struct {
+	Formats        []string `json:"formats"`
+	Adaptive       bool     `json:"adaptive"`
+	Encoders       []string `json:"encoders,omitempty"`
+	QualityPresets []string `json:"quality_presets,omitempty"`
+}
+
+// audioExtensions and videoExtensions classify the files core plugins handle
+var (
+	audioExtensions = []string{".mp3", ".flac", ".m4a", ".aac", ".ogg", ".opus", ".wav", ".wma", ".alac", ".ape"}
+	videoExtensions = []string{".mp4", ".mkv", ".avi", ".mov", ".webm", ".m4v", ".wmv", ".ts"}
+)
+
+// mediaTypesByTag maps manifest tags to media types
+var mediaTypesByTag = map[string][]string{
+	"movies": {"movie"},
+	"movie":  {"movie"},
+	"tv":     {"tv_show", "episode"},
+	"music":  {"artist", "album", "track"},
+	"audio":  {"track"},
+}
+
+// GetPluginCapabilities describes every installed core and external plugin.
+// Running external plugins are queried for search fields, routes and
+// transcoding formats; stopped plugins only report their manifest.
+func (pm *PluginModule) GetPluginCapabilities(ctx context.Context) []PluginCapabilities {
+	var result []PluginCapabilities
+
+	if pm.coreManager != nil {
+		for name, plugin := range pm.coreManager.ListCorePlugins() {
+			result = append(result, PluginCapabilities{
+				ID:                  name,
+				Name:                plugin.GetDisplayName(),
+				Type:                plugin.GetPluginType(),
+				Version:             "1.0.0",
+				IsCore:              true,
+				Enabled:             plugin.IsEnabled(),
+				Running:             plugin.IsEnabled(),
+				MediaTypes:          mediaTypesForExtensions(plugin.GetSupportedExtensions()),
+				SupportedExtensions: plugin.GetSupportedExtensions(),
+				Features:            []string{"metadata_extraction"},
+			})
+		}
+	}
+
+	if pm.externalManager != nil {
+		adminPages := pm.externalManager.adminPagesByPlugin()
+		for _, plugin := range pm.externalManager.snapshotPlugins() {
+			caps := PluginCapabilities{
+				ID:         plugin.ID,
+				Name:       plugin.Name,
+				Type:       plugin.Type,
+				Version:    plugin.Version,
+				Enabled:    plugin.Running || pm.externalManager.isPluginEnabled(plugin.ID),
+				Running:    plugin.Running,
+				MediaTypes: pluginMediaTypes(plugin),
+				Features:   append([]string{}, plugin.Capabilities...),
+				AdminPages: adminPages[plugin.ID],
+			}
+			if plugin.Running {
+				pm.externalManager.describeRunningPlugin(ctx, plugin, &caps)
+			}
+			result = append(result, caps)
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].IsCore != result[j].IsCore {
+			return result[i].IsCore
+		}
+		return result[i].ID < result[j].ID
+	})
+
+	return result
+}
+
+// snapshotPlugins returns copies of the registered external plugins
+func (m *ExternalPluginManager) snapshotPlugins() []ExternalPlugin {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	plugins := make([]ExternalPlugin, 0, len(m.plugins))
+	for _, plugin := range m.plugins {
+		plugins = append(plugins, *plugin)
+	}
+	return plugins
+}
+
+// isPluginEnabled reports whether the plugin is enabled in the database
+func (m *ExternalPluginManager) isPluginEnabled(pluginID string) bool {
+	var count int64
+	m.db.Model(&database.Plugin{}).
+		Where("plugin_id = ? AND status IN ?", pluginID, []string{"enabled", "running"}).
+		Count(&count)
+	return count > 0
+}
+
+// adminPagesByPlugin groups the enabled admin pages discovered from plugins
+func (m *ExternalPluginManager) adminPagesByPlugin() map[string][]AdminPageCapability {
+	var pages []database.PluginAdminPage
+	if err := m.db.Where("enabled = ?", true).Order("sort_order, title").Find(&pages).Error; err != nil {
+		m.logger.Warn("failed to load plugin admin pages", "error", err)
+		return nil
+	}
+
+	byPlugin := make(map[string][]AdminPageCapability)
+	for _, page := range pages {
+		byPlugin[page.PluginID] = append(byPlugin[page.PluginID], AdminPageCapability{
+			ID:       page.PageID,
+			Title:    page.Title,
+			Path:     page.Path,
+			URL:      page.URL,
+			Type:     page.Type,
+			Icon:     page.Icon,
+			Category: page.Category,
+		})
+	}
+	return byPlugin
+}
+
+// describeRunningPlugin fills in the capabilities a running plugin reports over gRPC.
+// Services a plugin doesn't implement return errors and are left out.
+func (m *ExternalPluginManager) describeRunningPlugin(ctx context.Context, plugin ExternalPlugin, caps *PluginCapabilities) {
+	client, ok := m.runningClient(plugin.ID)
+	if !ok {
+		return
+	}
+	caps.ABI = client.abi
+
+	searchCtx, cancel := context.WithTimeout(ctx, capabilityQueryTimeout)
+	if resp, err := client.GetSearchCapabilities(searchCtx); err == nil && len(resp.SupportedFields) > 0 {
+		caps.Search = &SearchCapabilities{
+			Fields:             resp.SupportedFields,
+			SupportsPagination: resp.SupportsPagination,
+			MaxResults:         resp.MaxResults,
+		}
+	}
+	cancel()
+
+	routesCtx, cancel := context.WithTimeout(ctx, capabilityQueryTimeout)
+	if routes, err := client.GetRegisteredRoutes(routesCtx); err == nil {
+		for _, route := range routes {
+			caps.Routes = append(caps.Routes, RouteCapability{
+				Method:      strings.ToUpper(route.Method),
+				Path:        "/api/plugins/" + plugin.ID + "/" + strings.TrimPrefix(route.Path, "/"),
+				Description: route.Description,
+			})
+		}
+	}
+	cancel()
+
+	if plugin.Type == "transcoder" {
+		provider := newExternalTranscodingProvider(&ExternalPluginInfo{ID: plugin.ID, Name: plugin.Name, Version: plugin.Version, Type: plugin.Type}, client)
+
+		transcoding := &TranscodingCapabilities{Encoders: provider.GetInfo().Capabilities}
+		for _, format := range provider.GetSupportedFormats() {
+			transcoding.Formats = append(transcoding.Formats, format.Format)
+			if format.Adaptive || format.Format == "dash" || format.Format == "hls" {
+				transcoding.Adaptive = true
+			}
+		}
+		for _, preset := range provider.GetQualityPresets() {
+			transcoding.QualityPresets = append(transcoding.QualityPresets, preset.ID)
+		}
+		caps.Transcoding = transcoding
+		caps.Features = appendFeature(caps.Features, "transcoding")
+	}
+
+	if caps.Search != nil {
+		caps.Features = appendFeature(caps.Features, "search_service")
+	}
+	if len(caps.Routes) > 0 {
+		caps.Features = appendFeature(caps.Features, "api_endpoints")
+	}
+}
+
+// runningClient returns the gRPC client of a loaded plugin
+func (m *ExternalPluginManager) runningClient(pluginID string) (*ExternalPluginGRPCClient, bool) {
+	m.mu.RLock()
+	iface := m.pluginInterfaces[pluginID]
+	m.mu.RUnlock()
+
+	client, ok := iface.(*ExternalPluginGRPCClient)
+	if !ok || client.conn == nil {
+		return nil, false
+	}
+	return client, true
+}
+
+// mediaTypesForExtensions derives media types from the file extensions a plugin handles
+func mediaTypesForExtensions(exts []string) []string {
+	types := []string{}
+	for _, ext := range exts {
+		ext = strings.ToLower(ext)
+		if slices.Contains(audioExtensions, ext) {
+			types = appendFeature(types, "track")
+		}
+		if slices.Contains(videoExtensions, ext) {
+			types = appendFeature(types, "movie")
+			types = appendFeature(types, "episode")
+		}
+	}
+	sort.Strings(types)
+	return types
+}
+
+// mediaTypesForTags derives media types from a plugin's manifest tags
+func mediaTypesForTags(tags []string) []string {
+	types := []string{}
+	for _, tag := range tags {
+		for _, mediaType := range mediaTypesByTag[strings.ToLower(tag)] {
+			types = appendFeature(types, mediaType)
+		}
+	}
+	sort.Strings(types)
+	return types
+}
+
+// pluginMediaTypes returns the media types a plugin declared, falling back
+// to those implied by its tags
+func pluginMediaTypes(plugin ExternalPlugin) []string {
+	if len(plugin.MediaTypes) > 0 {
+		return append([]string{}, plugin.MediaTypes...)
+	}
+	return mediaTypesForTags(plugin.Tags)
+}
+
+// appendFeature appends value unless it is already present
+func appendFeature(values []string, value string) []string {
+	if slices.Contains(values, value) {
+		return values
+	}
+	return append(values, value)
+}
@@ -0,0 +1,173 @@
+package pluginmodule
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	plugins "github.com/mantonx/viewra/sdk"
+)
+
+// Sources of a plugin's configuration JSON Schema
+const (
+	ConfigSchemaSourcePlugin = "plugin"
+	ConfigSchemaSourceCUE    = "cue"
+)
+
+// ConfigJSONSchema is a plugin's settings described as JSON Schema, ready for
+// the admin UI to render and validate a configuration form
+type ConfigJSONSchema struct {
+	PluginID   string                  `json:"plugin_id"`
+	Source     string                  `json:"source"`
+	Schema     map[string]interface{}  `json:"schema"`
+	Examples   map[string]interface{}  `json:"examples,omitempty"`
+	Categories []ConfigurationCategory `json:"categories,omitempty"`
+}
+
+// GetConfigurationJSONSchema returns the JSON Schema for a plugin's settings.
+// A running plugin's ConfigurationService takes precedence; otherwise the schema
+// is derived from the plugin's CUE file.
+func (pm *PluginModule) GetConfigurationJSONSchema(ctx context.Context, pluginID string) (*ConfigJSONSchema, error) {
+	if pm.externalManager != nil {
+		if client, ok := pm.externalManager.runningClient(pluginID); ok && client.abi.Supports(plugins.ConfigurationSchemaServiceName) {
+			schemaCtx, cancel := context.WithTimeout(ctx, capabilityQueryTimeout)
+			resp, err := plugins.GetPluginJSONSchema(schemaCtx, client.conn)
+			cancel()
+			if err == nil && hasSchemaProperties(resp.Schema) {
+				return &ConfigJSONSchema{
+					PluginID: pluginID,
+					Source:   ConfigSchemaSourcePlugin,
+					Schema:   resp.Schema,
+					Examples: resp.Examples,
+				}, nil
+			}
+			if err != nil {
+				pm.logger.Debug("plugin did not provide a configuration schema", "plugin_id", pluginID, "error", err)
+			}
+		}
+	}
+
+	if pm.configManager == nil {
+		return nil, fmt.Errorf("configuration manager not available")
+	}
+
+	schema, err := pm.configManager.GetConfigurationSchema(pluginID)
+	if err != nil {
+		return nil, err
+	}
+	if schema == nil {
+		return nil, fmt.Errorf("no configuration schema for plugin %s", pluginID)
+	}
+
+	return &ConfigJSONSchema{
+		PluginID:   pluginID,
+		Source:     ConfigSchemaSourceCUE,
+		Schema:     schema.JSONSchema(),
+		Categories: schema.Categories,
+	}, nil
+}
+
+// JSONSchema converts the CUE-derived schema to a JSON Schema object. UI hints
+// without a JSON Schema keyword are kept as x- extension keywords.
+func (s *ConfigurationSchema) JSONSchema() map[string]interface{} {
+	schema := map[string]interface{}{
+		"$schema":    plugins.JSONSchemaDraft,
+		"type":       "object",
+		"properties": propertiesJSONSchema(s.Properties),
+	}
+	if s.Title != "" {
+		schema["title"] = s.Title
+	}
+	if s.Description != "" {
+		schema["description"] = s.Description
+	}
+	if len(s.Required) > 0 {
+		schema["required"] = s.Required
+	}
+	return schema
+}
+
+func propertiesJSONSchema(properties map[string]ConfigurationProperty) map[string]interface{} {
+	result := make(map[string]interface{}, len(properties))
+	for name, property := range properties {
+		result[name] = property.jsonSchema()
+	}
+	return result
+}
+
+func (p ConfigurationProperty) jsonSchema() map[string]interface{} {
+	schema := make(map[string]interface{})
+
+	if p.Type != "" {
+		schema["type"] = p.Type
+	}
+	if p.Title != "" {
+		schema["title"] = p.Title
+	}
+	if p.Description != "" {
+		schema["description"] = p.Description
+	}
+	if p.Default != nil {
+		schema["default"] = p.Default
+	}
+	if len(p.Enum) > 0 {
+		schema["enum"] = p.Enum
+	}
+	if p.Minimum != nil {
+		schema["minimum"] = *p.Minimum
+	}
+	if p.Maximum != nil {
+		schema["maximum"] = *p.Maximum
+	}
+	if p.MinLength != nil {
+		schema["minLength"] = *p.MinLength
+	}
+	if p.MaxLength != nil {
+		schema["maxLength"] = *p.MaxLength
+	}
+	if p.Pattern != "" {
+		schema["pattern"] = p.Pattern
+	}
+	if p.Format != "" {
+		schema["format"] = p.Format
+	}
+	if p.ReadOnly {
+		schema["readOnly"] = true
+	}
+	if p.Sensitive {
+		schema["writeOnly"] = true
+		if p.Format == "" {
+			schema["format"] = "password"
+		}
+	}
+	if p.Items != nil {
+		schema["items"] = p.Items.jsonSchema()
+	}
+	if len(p.Properties) > 0 {
+		schema["properties"] = propertiesJSONSchema(p.Properties)
+	}
+
+	if p.Category != "" {
+		schema["x-category"] = p.Category
+	}
+	if p.Order != 0 {
+		schema["x-order"] = p.Order
+	}
+	schema["x-advanced"] = p.Advanced
+	if len(p.Dependencies) > 0 {
+		deps := append([]string{}, p.Dependencies...)
+		sort.Strings(deps)
+		schema["x-dependencies"] = deps
+	}
+	if p.Conditional != nil {
+		schema["x-conditional"] = p.Conditional
+	}
+
+	return schema
+}
+
+// hasSchemaProperties reports whether a JSON Schema defines any properties
+func hasSchemaProperties(schema map[string]interface{}) bool {
+	properties, ok := schema["properties"].(map[string]interface{})
+	return ok && len(properties) > 0
+}
@@ -668,6 +668,17 @@ func (pcm *PluginConfigManager) convertMapToConfigurationProperty(propMap map[st
 	if defaultVal, ok := propMap["default"]; ok {
 		prop.Default = defaultVal
 	}
+	if minimum, ok := propMap["minimum"].(float64); ok {
+		prop.Minimum = &minimum
+	}
+	if maximum, ok := propMap["maximum"].(float64); ok {
+		prop.Maximum = &maximum
+	}
+	if enum, ok := propMap["enum"].([]string); ok {
+		for _, value := range enum {
+			prop.Enum = append(prop.Enum, value)
+		}
+	}
 
 	// Copy UI metadata fields for basic/advanced classification
 	if isBasic, ok := propMap["is_basic"].(bool); ok {
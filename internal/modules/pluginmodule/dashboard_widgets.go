@@ -0,0 +1,91 @@
+package pluginmodule
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	plugins "github.com/mantonx/viewra/sdk"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// PluginDashboardWidget is a widget contributed by a plugin to the admin home
+// screen, with its current data or the error fetching it
+type PluginDashboardWidget struct {
+	PluginID   string `json:"plugin_id"`
+	PluginName string `json:"plugin_name"`
+	*plugins.DashboardWidgetState
+}
+
+// DashboardWidgetError records a plugin whose widgets couldn't be fetched
+type DashboardWidgetError struct {
+	PluginID string `json:"plugin_id"`
+	Error    string `json:"error"`
+}
+
+// GetDashboardWidgets collects the widgets of every running plugin that
+// serves the DashboardWidgetService. Plugins are queried in parallel, each
+// bounded by capabilityQueryTimeout, so a slow plugin only loses its own
+// widgets.
+func (pm *PluginModule) GetDashboardWidgets(ctx context.Context) ([]PluginDashboardWidget, []DashboardWidgetError) {
+	widgets := []PluginDashboardWidget{}
+	failures := []DashboardWidgetError{}
+	if pm.externalManager == nil {
+		return widgets, failures
+	}
+
+	var (
+		mu sync.Mutex
+		wg sync.WaitGroup
+	)
+	for _, plugin := range pm.externalManager.snapshotPlugins() {
+		if !plugin.Running {
+			continue
+		}
+		client, ok := pm.externalManager.runningClient(plugin.ID)
+		if !ok || !client.abi.Supports(plugins.DashboardWidgetServiceName) {
+			continue
+		}
+
+		wg.Add(1)
+		go func(plugin ExternalPlugin, client *ExternalPluginGRPCClient) {
+			defer wg.Done()
+
+			widgetCtx, cancel := context.WithTimeout(ctx, capabilityQueryTimeout)
+			resp, err := plugins.GetPluginDashboardWidgets(widgetCtx, client.conn)
+			cancel()
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				// Plugins from before the handshake can't say whether they serve widgets
+				if status.Code(err) != codes.Unimplemented {
+					pm.logger.Debug("failed to get dashboard widgets", "plugin_id", plugin.ID, "error", err)
+					failures = append(failures, DashboardWidgetError{PluginID: plugin.ID, Error: err.Error()})
+				}
+				return
+			}
+			for _, state := range resp.Widgets {
+				if state == nil || state.Widget == nil {
+					continue
+				}
+				widgets = append(widgets, PluginDashboardWidget{
+					PluginID:             plugin.ID,
+					PluginName:           plugin.Name,
+					DashboardWidgetState: state,
+				})
+			}
+		}(plugin, client)
+	}
+	wg.Wait()
+
+	// Keep each plugin's widgets in the order it declared them
+	sort.SliceStable(widgets, func(i, j int) bool {
+		return widgets[i].PluginID < widgets[j].PluginID
+	})
+	sort.Slice(failures, func(i, j int) bool {
+		return failures[i].PluginID < failures[j].PluginID
+	})
+	return widgets, failures
+}
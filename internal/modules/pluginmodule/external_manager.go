@@ -2,12 +2,15 @@ package pluginmodule
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"sort"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -19,6 +22,8 @@ import (
 	plugins "github.com/mantonx/viewra/sdk"
 	"github.com/mantonx/viewra/sdk/proto"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	"gorm.io/gorm"
 )
 
@@ -49,6 +54,9 @@ type ExternalPluginInterface interface {
 	OnMediaFileScanned(mediaFileID string, filePath string, metadata map[string]string) error
 	OnScanStarted(scanJobID, libraryID uint32, libraryPath string) error
 	OnScanCompleted(scanJobID, libraryID uint32, stats map[string]string) error
+	OnMediaFileRemoved(mediaFileID string, filePath string, metadata map[string]string) error
+	OnMediaFileUpdated(mediaFileID string, filePath string, metadata map[string]string) error
+	OnMediaFilesScanned(files []plugins.ScannedMediaFile) ([]error, error)
 }
 
 // ExternalPluginContext provides context for plugin operations
@@ -93,6 +101,8 @@ func (p *GRPCExternalPlugin) GRPCServer(broker *goplugin.GRPCBroker, s *grpc.Ser
 type ExternalPluginGRPCClient struct {
 	broker *goplugin.GRPCBroker
 	conn   *grpc.ClientConn
+	// abi is the handshake result, set when the plugin is loaded
+	abi *PluginABI
 }
 
 // ExternalPluginAdapter adapts the GRPC client to implement plugins.Implementation
@@ -168,11 +178,7 @@ func (a *ExternalPluginAdapter) EnhancedAdminPageService() plugins.EnhancedAdmin
 func (a *ExternalPluginAdapter) TranscodingProvider() plugins.TranscodingProvider {
 	if a.pluginInfo != nil && a.pluginInfo.Type == "transcoder" {
 		// Create a basic transcoding provider that uses the GRPC client
-		return &ExternalTranscodingProvider{
-			pluginID:   a.pluginInfo.ID,
-			pluginInfo: a.pluginInfo,
-			client:     a.client,
-		}
+		return newExternalTranscodingProvider(a.pluginInfo, a.client)
 	}
 	return nil
 }
@@ -661,6 +667,18 @@ func (a *ExternalPluginAdapter) OnScanCompleted(scanJobID, libraryID uint32, sta
 	return a.client.OnScanCompleted(scanJobID, libraryID, stats)
 }
 
+func (a *ExternalPluginAdapter) OnMediaFileRemoved(mediaFileID string, filePath string, metadata map[string]string) error {
+	return a.client.OnMediaFileRemoved(mediaFileID, filePath, metadata)
+}
+
+func (a *ExternalPluginAdapter) OnMediaFileUpdated(mediaFileID string, filePath string, metadata map[string]string) error {
+	return a.client.OnMediaFileUpdated(mediaFileID, filePath, metadata)
+}
+
+func (a *ExternalPluginAdapter) OnMediaFilesScanned(files []plugins.ScannedMediaFile) ([]error, error) {
+	return a.client.OnMediaFilesScanned(files)
+}
+
 // Core plugin service implementations for ExternalPluginGRPCClient
 func (c *ExternalPluginGRPCClient) Initialize(ctx *ExternalPluginContext) error {
 	client := proto.NewPluginServiceClient(c.conn)
@@ -803,11 +821,16 @@ func (c *ExternalPluginGRPCClient) OnMediaFileScanned(mediaFileID string, filePa
 		Metadata:    metadata,
 	}
 
-	_, err := client.OnMediaFileScanned(context.Background(), req)
+	resp, err := client.OnMediaFileScanned(context.Background(), req)
 	if err != nil {
 		return fmt.Errorf("plugin OnMediaFileScanned failed: %w", err)
 	}
 
+	// Skips come back as a result rather than a gRPC error
+	if resp.GetStatus() == string(plugins.HookStatusSkipped) {
+		return plugins.SkipHook(resp.GetReason(), resp.GetDetail())
+	}
+
 	return nil
 }
 
@@ -841,6 +864,97 @@ func (c *ExternalPluginGRPCClient) OnScanCompleted(scanJobID, libraryID uint32,
 	return err
 }
 
+// OnMediaFileRemoved tells the plugin a media file is about to be deleted.
+// Plugins without per-file data don't implement the hook, which is not an error.
+func (c *ExternalPluginGRPCClient) OnMediaFileRemoved(mediaFileID string, filePath string, metadata map[string]string) error {
+	client := proto.NewScannerHookServiceClient(c.conn)
+
+	_, err := client.OnMediaFileRemoved(context.Background(), &proto.OnMediaFileRemovedRequest{
+		MediaFileId: mediaFileID,
+		FilePath:    filePath,
+		Metadata:    metadata,
+	})
+	if status.Code(err) == codes.Unimplemented {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("plugin OnMediaFileRemoved failed: %w", err)
+	}
+
+	return nil
+}
+
+// OnMediaFileUpdated tells the plugin an existing media file changed on disk.
+// Plugins that don't implement the hook are sent OnMediaFileScanned instead.
+func (c *ExternalPluginGRPCClient) OnMediaFileUpdated(mediaFileID string, filePath string, metadata map[string]string) error {
+	client := proto.NewScannerHookServiceClient(c.conn)
+
+	resp, err := client.OnMediaFileUpdated(context.Background(), &proto.OnMediaFileUpdatedRequest{
+		MediaFileId: mediaFileID,
+		FilePath:    filePath,
+		Metadata:    metadata,
+	})
+	if status.Code(err) == codes.Unimplemented {
+		return c.OnMediaFileScanned(mediaFileID, filePath, metadata)
+	}
+	if err != nil {
+		return fmt.Errorf("plugin OnMediaFileUpdated failed: %w", err)
+	}
+
+	if resp.GetStatus() == string(plugins.HookStatusSkipped) {
+		return plugins.SkipHook(resp.GetReason(), resp.GetDetail())
+	}
+
+	return nil
+}
+
+// OnMediaFilesScanned sends a batch of scanned files in one call and returns
+// the outcome of each, in order, as OnMediaFileScanned would. Plugins built
+// against an SDK without the batched hook get one call per file.
+func (c *ExternalPluginGRPCClient) OnMediaFilesScanned(files []plugins.ScannedMediaFile) ([]error, error) {
+	client := proto.NewScannerHookServiceClient(c.conn)
+
+	req := &proto.OnMediaFilesScannedRequest{Files: make([]*proto.OnMediaFileScannedRequest, len(files))}
+	for i, file := range files {
+		req.Files[i] = &proto.OnMediaFileScannedRequest{
+			MediaFileId: file.MediaFileID,
+			FilePath:    file.FilePath,
+			Metadata:    file.Metadata,
+		}
+	}
+
+	resp, err := client.OnMediaFilesScanned(context.Background(), req)
+	if status.Code(err) == codes.Unimplemented {
+		results := make([]error, len(files))
+		for i, file := range files {
+			results[i] = c.OnMediaFileScanned(file.MediaFileID, file.FilePath, file.Metadata)
+		}
+		return results, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("plugin OnMediaFilesScanned failed: %w", err)
+	}
+
+	outcomes := make(map[string]*proto.MediaFileHookResult, len(resp.Results))
+	for _, result := range resp.Results {
+		outcomes[result.MediaFileId] = result
+	}
+
+	results := make([]error, len(files))
+	for i, file := range files {
+		result, ok := outcomes[file.MediaFileID]
+		switch {
+		case !ok:
+			results[i] = fmt.Errorf("plugin returned no result for media file %s", file.MediaFileID)
+		case result.Status == string(plugins.HookStatusSkipped):
+			results[i] = plugins.SkipHook(result.Reason, result.Detail)
+		case result.Status == string(plugins.HookStatusFailed):
+			results[i] = errors.New(result.Detail)
+		}
+	}
+	return results, nil
+}
+
 // GetAdminPages gets admin pages from the plugin via GRPC
 func (c *ExternalPluginGRPCClient) GetAdminPages() ([]*proto.AdminPageConfig, error) {
 	// Create proto client
@@ -854,6 +968,42 @@ func (c *ExternalPluginGRPCClient) GetAdminPages() ([]*proto.AdminPageConfig, er
 	return resp.Pages, nil
 }
 
+// GetSearchCapabilities gets the fields the plugin's search service accepts via GRPC
+func (c *ExternalPluginGRPCClient) GetSearchCapabilities(ctx context.Context) (*proto.GetSearchCapabilitiesResponse, error) {
+	client := proto.NewSearchServiceClient(c.conn)
+	return client.GetSearchCapabilities(ctx, &proto.GetSearchCapabilitiesRequest{})
+}
+
+// Search runs a search through the plugin's search service via GRPC
+func (c *ExternalPluginGRPCClient) Search(ctx context.Context, query map[string]string, limit, offset uint32) (*proto.SearchResponse, error) {
+	client := proto.NewSearchServiceClient(c.conn)
+	return client.Search(ctx, &proto.SearchRequest{Query: query, Limit: limit, Offset: offset})
+}
+
+// GetSupportedTypes gets the media types the plugin's metadata scraper handles via GRPC
+func (c *ExternalPluginGRPCClient) GetSupportedTypes(ctx context.Context) ([]string, error) {
+	client := proto.NewMetadataScraperServiceClient(c.conn)
+
+	resp, err := client.GetSupportedTypes(ctx, &proto.GetSupportedTypesRequest{})
+	if err != nil {
+		return nil, err
+	}
+
+	return resp.Types, nil
+}
+
+// GetRegisteredRoutes gets the API routes the plugin registers via GRPC
+func (c *ExternalPluginGRPCClient) GetRegisteredRoutes(ctx context.Context) ([]*proto.APIRoute, error) {
+	client := proto.NewAPIRegistrationServiceClient(c.conn)
+
+	resp, err := client.GetRegisteredRoutes(ctx, &proto.GetRegisteredRoutesRequest{})
+	if err != nil {
+		return nil, err
+	}
+
+	return resp.Routes, nil
+}
+
 // ExternalPluginManager manages external plugins
 type ExternalPluginManager struct {
 	db     *gorm.DB
@@ -884,6 +1034,9 @@ type ExternalPluginManager struct {
 
 	// Dashboard integration
 	dashboardManager *DashboardManager
+
+	// Scanned files queued for plugins taking batched scanner hooks
+	scanBatches *scanHookBatcher
 }
 
 // ExternalPluginManifest represents the parsed CUE configuration
@@ -894,6 +1047,8 @@ type ExternalPluginManifest struct {
 	Description    string                 `json:"description"`
 	Author         string                 `json:"author"`
 	Type           string                 `json:"type"`
+	Tags           []string               `json:"tags"`
+	MediaTypes     []string               `json:"media_types"` // Media types the plugin's scanner hooks handle, empty = all
 	EnabledDefault bool                   `json:"enabled_by_default"`
 	Capabilities   map[string]interface{} `json:"capabilities"`
 	EntryPoints    map[string]string      `json:"entry_points"`
@@ -905,7 +1060,7 @@ func NewExternalPluginManager(db *gorm.DB, logger hclog.Logger) *ExternalPluginM
 	// Initialize reliability configuration
 	reliabilityConfig := config.DefaultPluginReliabilityConfig()
 
-	return &ExternalPluginManager{
+	m := &ExternalPluginManager{
 		db:               db,
 		logger:           logger,
 		plugins:          make(map[string]*ExternalPlugin),
@@ -917,6 +1072,8 @@ func NewExternalPluginManager(db *gorm.DB, logger hclog.Logger) *ExternalPluginM
 		fallbackManager:   NewFallbackManager(logger, db, nil), // Use default config
 		reliabilityConfig: reliabilityConfig,
 	}
+	m.scanBatches = newScanHookBatcher(m.deliverScannedBatch)
+	return m
 }
 
 // Initialize initializes the external plugin manager
@@ -932,6 +1089,7 @@ func (m *ExternalPluginManager) Initialize(ctx context.Context, pluginDir string
 	go m.fallbackManager.StartCleanupRoutine(m.ctx)
 
 	m.logger.Info("starting reliability monitoring systems")
+	m.logMockProviderMode()
 
 	// Discover and register plugins from the plugin directory
 	if err := m.discoverAndRegisterPlugins(); err != nil {
@@ -1115,6 +1273,9 @@ func (m *ExternalPluginManager) parsePluginManifest(cuePath string) (*ExternalPl
 	inPluginBlock := false
 	inSettingsBlock := false
 	inEntryPointsBlock := false
+	inCapabilitiesBlock := false
+	inTagsBlock := false
+	inMediaTypesBlock := false
 	blockDepth := 0
 
 	for _, line := range lines {
@@ -1150,6 +1311,34 @@ func (m *ExternalPluginManager) parsePluginManifest(cuePath string) (*ExternalPl
 				continue
 			}
 
+			// Check for capabilities block
+			if strings.Contains(line, "capabilities:") && strings.Contains(line, "{") {
+				inCapabilitiesBlock = true
+				continue
+			}
+
+			// Check for tags list
+			if strings.HasPrefix(line, "tags:") && strings.Contains(line, "[") {
+				inTagsBlock = !strings.Contains(line, "]")
+				for _, tag := range strings.Split(line[strings.Index(line, "[")+1:], ",") {
+					if tag = strings.Trim(strings.TrimSpace(strings.TrimSuffix(tag, "]")), `"`); tag != "" {
+						manifest.Tags = append(manifest.Tags, tag)
+					}
+				}
+				continue
+			}
+
+			// Check for media_types list
+			if strings.HasPrefix(line, "media_types:") && strings.Contains(line, "[") {
+				inMediaTypesBlock = !strings.Contains(line, "]")
+				for _, mediaType := range strings.Split(line[strings.Index(line, "[")+1:], ",") {
+					if mediaType = strings.Trim(strings.TrimSpace(strings.TrimSuffix(mediaType, "]")), `"`); mediaType != "" {
+						manifest.MediaTypes = append(manifest.MediaTypes, mediaType)
+					}
+				}
+				continue
+			}
+
 			// Skip settings block content
 			if inSettingsBlock {
 				if blockDepth <= 1 {
@@ -1158,6 +1347,39 @@ func (m *ExternalPluginManager) parsePluginManifest(cuePath string) (*ExternalPl
 				continue
 			}
 
+			// Parse capabilities block of boolean flags
+			if inCapabilitiesBlock {
+				if key, value, ok := strings.Cut(line, ":"); ok {
+					manifest.Capabilities[strings.TrimSpace(key)] = strings.TrimSpace(value) == "true"
+				}
+				if blockDepth <= 1 {
+					inCapabilitiesBlock = false
+				}
+				continue
+			}
+
+			// Parse tags list
+			if inTagsBlock {
+				if tag := strings.Trim(strings.TrimSuffix(strings.TrimSpace(strings.TrimSuffix(line, "]")), ","), `"`); tag != "" {
+					manifest.Tags = append(manifest.Tags, tag)
+				}
+				if strings.Contains(line, "]") {
+					inTagsBlock = false
+				}
+				continue
+			}
+
+			// Parse media_types list
+			if inMediaTypesBlock {
+				if mediaType := strings.Trim(strings.TrimSuffix(strings.TrimSpace(strings.TrimSuffix(line, "]")), ","), `"`); mediaType != "" {
+					manifest.MediaTypes = append(manifest.MediaTypes, mediaType)
+				}
+				if strings.Contains(line, "]") {
+					inMediaTypesBlock = false
+				}
+				continue
+			}
+
 			// Parse entry_points block
 			if inEntryPointsBlock {
 				if strings.Contains(line, "main:") {
@@ -1236,9 +1458,17 @@ func (m *ExternalPluginManager) registerExternalPlugin(manifest *ExternalPluginM
 		Type:        manifest.Type,
 		Version:     manifest.Version,
 		Description: manifest.Description,
+		Tags:        manifest.Tags,
+		MediaTypes:  normalizeMediaTypes(manifest.MediaTypes),
 		Running:     false,
 		Path:        binaryPath,
 	}
+	for name, value := range manifest.Capabilities {
+		if enabled, ok := value.(bool); ok && enabled {
+			plugin.Capabilities = append(plugin.Capabilities, name)
+		}
+	}
+	sort.Strings(plugin.Capabilities)
 
 	// Store in memory
 	m.plugins[manifest.ID] = plugin
@@ -1271,9 +1501,7 @@ func (m *ExternalPluginManager) ensurePluginInDatabase(manifest *ExternalPluginM
 			cfg := config.Get().Plugins
 
 			// Check if enrichment plugins should be enabled by default
-			isEnrichmentPlugin := manifest.Type == "metadata_scraper" ||
-				strings.Contains(manifest.ID, "enricher") ||
-				strings.Contains(strings.ToLower(manifest.Name), "enricher")
+			isEnrichmentPlugin := IsEnrichmentPlugin(manifest.ID, manifest.Name, manifest.Type)
 
 			// Enable plugin if:
 			// 1. It's marked as enabled_by_default AND respect_default_config is true
@@ -1413,6 +1641,7 @@ func (m *ExternalPluginManager) LoadPlugin(ctx context.Context, pluginID string)
 		"VIEWRA_LOG_LEVEL=debug",
 		"VIEWRA_BASE_PATH="+filepath.Dir(plugin.Path),
 	)
+	cmd.Env = append(cmd.Env, m.mockProviderEnv()...)
 
 	// Create plugin client using hashicorp/go-plugin with timeout from config
 	client := goplugin.NewClient(&goplugin.ClientConfig{
@@ -1466,6 +1695,21 @@ func (m *ExternalPluginManager) LoadPlugin(ctx context.Context, pluginID string)
 		return errors.New(ErrPluginInterface)
 	}
 
+	// Check protocol compatibility before calling anything else on the plugin
+	if grpcClient, ok := pluginInterface.(*ExternalPluginGRPCClient); ok {
+		abi, err := m.negotiateABI(ctx, pluginID, grpcClient)
+		if err != nil {
+			client.Kill()
+			m.updatePluginStatus(pluginID, "error")
+
+			responseTime := time.Since(startTime)
+			m.healthMonitor.RecordRequest(pluginID, false, responseTime, err)
+
+			return err
+		}
+		grpcClient.abi = abi
+	}
+
 	// Initialize the plugin
 	pluginCtx := &ExternalPluginContext{
 		PluginID:        pluginID,
@@ -1511,6 +1755,7 @@ func (m *ExternalPluginManager) LoadPlugin(ctx context.Context, pluginID string)
 	// Update plugin status
 	plugin.Running = true
 	plugin.LastStarted = time.Now()
+	m.applyDeclaredMediaTypes(ctx, plugin, pluginInterface)
 
 	// NEW: Register plugin with health monitor and record successful start
 	m.registerPluginHealth(pluginID, pluginInterface)
@@ -1858,67 +2103,170 @@ func (m *ExternalPluginManager) GetRunningPluginInterface(pluginID string) (inte
 	return pluginInterface, exists
 }
 
-// NotifyMediaFileScanned notifies all running external plugins about a scanned media file
+// NotifyMediaFileScanned notifies all running external plugins about a scanned media file.
+// Plugins in the provider chain for the file's media type are tried one at a
+// time instead, see runProviderChain.
 func (m *ExternalPluginManager) NotifyMediaFileScanned(mediaFileID string, filePath string, metadata map[string]string) {
-	m.mu.RLock()
-	runningPlugins := make(map[string]ExternalPluginInterface)
-	for id, iface := range m.pluginInterfaces {
-		runningPlugins[id] = iface
+	file := m.scannedFile(mediaFileID)
+	metadata = file.hookMetadata(metadata)
+	m.addMatchPath(metadata, file.LibraryID, filePath)
+	runningPlugins := m.fileHookPlugins(m.libraryProvidersForFile(mediaFileID), file.MediaType)
+
+	if chain := m.takeProviderChain(file.MediaType, runningPlugins); chain != nil {
+		go m.runProviderChain(chain, mediaFileID, filePath, metadata, file.LibraryID)
 	}
-	m.mu.RUnlock()
+
+	for pluginID, pluginInterface := range runningPlugins {
+		// Plugins taking batches get the file with their next batch
+		if m.batchesScannerHooks(pluginID) {
+			m.scanBatches.add(pluginID, pluginInterface, plugins.ScannedMediaFile{
+				MediaFileID: mediaFileID,
+				FilePath:    filePath,
+				Metadata:    metadata,
+			}, file.LibraryID)
+			continue
+		}
+
+		go m.deliverScannedFile(pluginID, pluginInterface, mediaFileID, filePath, metadata, file.LibraryID)
+	}
+}
+
+// deliverScannedFile sends a scanned file to one plugin, records the outcome
+// and returns its status and skip reason
+func (m *ExternalPluginManager) deliverScannedFile(id string, iface ExternalPluginInterface, mediaFileID, filePath string, metadata map[string]string, libraryID uint32) (plugins.HookStatus, string) {
+	// NEW: Check circuit breaker before making request
+	if !m.healthMonitor.ShouldAllowRequest(id) {
+		m.logger.Warn("skipping plugin notification due to circuit breaker", "plugin_id", id)
+		m.recordHookResult(id, mediaFileID, libraryID, plugins.HookStatusSkipped, hookSkipCircuitOpen, "", 0)
+		return plugins.HookStatusSkipped, hookSkipCircuitOpen
+	}
+
+	// NEW: Track request time
+	startTime := time.Now()
+
+	// NEW: Prepare fallback request for both success and failure scenarios
+	fallbackRequest := &FallbackRequest{
+		PluginID:    id,
+		Operation:   "OnMediaFileScanned",
+		MediaFileID: mediaFileID,
+		RequestTime: startTime,
+		Parameters: map[string]interface{}{
+			"file_path": filePath,
+			"metadata":  metadata,
+		},
+	}
+
+	// Rate limited and temporary failures are retried with the plugin's delay hint
+	err := m.callWithRetry(m.ctx, id, func() error {
+		return iface.OnMediaFileScanned(mediaFileID, filePath, metadata)
+	})
+
+	// NEW: Record request result in health monitor
+	responseTime := time.Since(startTime)
+	success := !countsAsPluginFailure(err)
+	m.healthMonitor.RecordRequest(id, success, responseTime, err)
+
+	status, reason, detail := hookOutcome(err)
+	m.recordHookResult(id, mediaFileID, libraryID, status, reason, detail, responseTime)
+
+	if err != nil && !success {
+		m.logger.Error("plugin media file notification failed", "plugin", id, "code", pluginErrorCode(err), "error", err)
+
+		// NEW: Try fallback if available
+		fallbackRequest.OriginalError = err
+
+		if fallbackResponse, fallbackErr := m.fallbackManager.HandleFailure(context.Background(), fallbackRequest); fallbackErr == nil {
+			m.logger.Info("fallback handled plugin failure",
+				"plugin_id", id,
+				"strategy", fallbackResponse.Strategy,
+				"from_cache", fallbackResponse.FromCache)
+		}
+	} else if err != nil {
+		m.logger.Debug("plugin skipped media file", "plugin", id, "reason", reason, "detail", detail)
+	} else {
+		// NEW: Cache successful operation for future fallback
+		cacheKey := fmt.Sprintf("%s:%s:%s", id, "OnMediaFileScanned", mediaFileID)
+		cacheData := map[string]interface{}{
+			"media_file_id": mediaFileID,
+			"file_path":     filePath,
+			"metadata":      metadata,
+			"success":       true,
+		}
+		m.fallbackManager.StoreCacheEntry(cacheKey, cacheData, id, 1.0)
+	}
+	return status, reason
+}
+
+// NotifyMediaFileUpdated notifies the plugins handling a media file that it
+// changed on disk, so they can refresh what they stored for it
+func (m *ExternalPluginManager) NotifyMediaFileUpdated(mediaFileID string, filePath string, metadata map[string]string) {
+	file := m.scannedFile(mediaFileID)
+	metadata = file.hookMetadata(metadata)
+	m.addMatchPath(metadata, file.LibraryID, filePath)
+	runningPlugins := m.fileHookPlugins(m.libraryProvidersForFile(mediaFileID), file.MediaType)
 
 	for pluginID, pluginInterface := range runningPlugins {
 		go func(id string, iface ExternalPluginInterface) {
-			// NEW: Check circuit breaker before making request
 			if !m.healthMonitor.ShouldAllowRequest(id) {
 				m.logger.Warn("skipping plugin notification due to circuit breaker", "plugin_id", id)
+				m.recordHookResult(id, mediaFileID, file.LibraryID, plugins.HookStatusSkipped, hookSkipCircuitOpen, "", 0)
 				return
 			}
 
-			// NEW: Track request time
 			startTime := time.Now()
+			err := m.callWithRetry(m.ctx, id, func() error {
+				return iface.OnMediaFileUpdated(mediaFileID, filePath, metadata)
+			})
 
-			// NEW: Prepare fallback request for both success and failure scenarios
-			fallbackRequest := &FallbackRequest{
-				PluginID:    id,
-				Operation:   "OnMediaFileScanned",
-				MediaFileID: mediaFileID,
-				RequestTime: startTime,
-				Parameters: map[string]interface{}{
-					"file_path": filePath,
-					"metadata":  metadata,
-				},
+			responseTime := time.Since(startTime)
+			success := !countsAsPluginFailure(err)
+			m.healthMonitor.RecordRequest(id, success, responseTime, err)
+
+			status, reason, detail := hookOutcome(err)
+			m.recordHookResult(id, mediaFileID, file.LibraryID, status, reason, detail, responseTime)
+
+			if err != nil && !success {
+				m.logger.Error("plugin media file update notification failed", "plugin", id, "code", pluginErrorCode(err), "error", err)
+			} else if err != nil {
+				m.logger.Debug("plugin skipped updated media file", "plugin", id, "reason", reason, "detail", detail)
 			}
+		}(pluginID, pluginInterface)
+	}
+}
 
-			err := iface.OnMediaFileScanned(mediaFileID, filePath, metadata)
+// NotifyMediaFileRemoved notifies plugins that a media file is about to be
+// deleted, so they can drop their enrichment rows and assets for it. It must
+// be called while the file's row still exists; the file's hook results are
+// removed with it. Every plugin taking hooks for the file's media type is
+// notified, including enrichers no longer selected for the library, as they
+// may still hold data from before the selection changed.
+func (m *ExternalPluginManager) NotifyMediaFileRemoved(mediaFileID string, filePath string, metadata map[string]string) {
+	file := m.scannedFile(mediaFileID)
+	metadata = file.hookMetadata(metadata)
+	runningPlugins := m.fileHookPlugins(nil, file.MediaType)
 
-			// NEW: Record request result in health monitor
-			responseTime := time.Since(startTime)
-			success := err == nil
-			m.healthMonitor.RecordRequest(id, success, responseTime, err)
+	if m.db != nil {
+		if err := m.db.Where("media_file_id = ?", mediaFileID).Delete(&database.PluginHookResult{}).Error; err != nil {
+			m.logger.Warn("failed to remove scanner hook results", "media_file_id", mediaFileID, "error", err)
+		}
+	}
 
-			if err != nil {
-				m.logger.Error("plugin media file notification failed", "plugin", id, "error", err)
+	for pluginID, pluginInterface := range runningPlugins {
+		go func(id string, iface ExternalPluginInterface) {
+			if !m.healthMonitor.ShouldAllowRequest(id) {
+				m.logger.Warn("skipping plugin notification due to circuit breaker", "plugin_id", id)
+				return
+			}
 
-				// NEW: Try fallback if available
-				fallbackRequest.OriginalError = err
+			startTime := time.Now()
+			err := m.callWithRetry(m.ctx, id, func() error {
+				return iface.OnMediaFileRemoved(mediaFileID, filePath, metadata)
+			})
 
-				if fallbackResponse, fallbackErr := m.fallbackManager.HandleFailure(context.Background(), fallbackRequest); fallbackErr == nil {
-					m.logger.Info("fallback handled plugin failure",
-						"plugin_id", id,
-						"strategy", fallbackResponse.Strategy,
-						"from_cache", fallbackResponse.FromCache)
-				}
-			} else {
-				// NEW: Cache successful operation for future fallback
-				cacheKey := fmt.Sprintf("%s:%s:%s", id, "OnMediaFileScanned", mediaFileID)
-				cacheData := map[string]interface{}{
-					"media_file_id": mediaFileID,
-					"file_path":     filePath,
-					"metadata":      metadata,
-					"success":       true,
-				}
-				m.fallbackManager.StoreCacheEntry(cacheKey, cacheData, id, 1.0)
+			success := !countsAsPluginFailure(err)
+			m.healthMonitor.RecordRequest(id, success, time.Since(startTime), err)
+			if err != nil && !success {
+				m.logger.Error("plugin media file removal notification failed", "plugin", id, "code", pluginErrorCode(err), "error", err)
 			}
 		}(pluginID, pluginInterface)
 	}
@@ -1929,6 +2277,9 @@ func (m *ExternalPluginManager) NotifyScanStarted(scanJobID, libraryID uint32, l
 	m.mu.RLock()
 	runningPlugins := make(map[string]ExternalPluginInterface)
 	for id, iface := range m.pluginInterfaces {
+		if plugin, ok := m.plugins[id]; ok && !plugin.handlesScannerHooks("") {
+			continue
+		}
 		runningPlugins[id] = iface
 	}
 	m.mu.RUnlock()
@@ -1944,9 +2295,15 @@ func (m *ExternalPluginManager) NotifyScanStarted(scanJobID, libraryID uint32, l
 
 // NotifyScanCompleted notifies all running external plugins that a scan has completed
 func (m *ExternalPluginManager) NotifyScanCompleted(scanJobID, libraryID uint32, stats map[string]string) {
+	// Send the scan's last files without waiting for their batches to fill
+	m.scanBatches.flushAll()
+
 	m.mu.RLock()
 	runningPlugins := make(map[string]ExternalPluginInterface)
 	for id, iface := range m.pluginInterfaces {
+		if plugin, ok := m.plugins[id]; ok && !plugin.handlesScannerHooks("") {
+			continue
+		}
 		runningPlugins[id] = iface
 	}
 	m.mu.RUnlock()
@@ -2082,6 +2439,7 @@ func (m *ExternalPluginManager) autoLoadEnabledPlugins(ctx context.Context) erro
 			"VIEWRA_LOG_LEVEL=debug",
 			"VIEWRA_BASE_PATH="+filepath.Dir(plugin.Path),
 		)
+		cmd.Env = append(cmd.Env, m.mockProviderEnv()...)
 
 		// Create plugin client using hashicorp/go-plugin
 		client := goplugin.NewClient(&goplugin.ClientConfig{
@@ -2124,6 +2482,19 @@ func (m *ExternalPluginManager) autoLoadEnabledPlugins(ctx context.Context) erro
 			continue
 		}
 
+		// Check protocol compatibility before calling anything else on the plugin
+		if grpcClient, ok := pluginInterface.(*ExternalPluginGRPCClient); ok {
+			abi, err := m.negotiateABI(ctx, pluginID, grpcClient)
+			if err != nil {
+				client.Kill()
+				m.logger.Error("plugin is incompatible with this host", "plugin", pluginID, "error", err)
+				m.updatePluginStatus(pluginID, "error")
+				failedCount++
+				continue
+			}
+			grpcClient.abi = abi
+		}
+
 		// Initialize the plugin
 		pluginCtx := &ExternalPluginContext{
 			PluginID:        pluginID,
@@ -2169,6 +2540,7 @@ func (m *ExternalPluginManager) autoLoadEnabledPlugins(ctx context.Context) erro
 		// Update plugin status
 		plugin.Running = true
 		plugin.LastStarted = time.Now()
+		m.applyDeclaredMediaTypes(ctx, plugin, pluginInterface)
 
 		// Monitor the plugin process in a goroutine
 		go m.monitorPluginProcess(pluginID, client)
@@ -2415,26 +2787,61 @@ func (m *ExternalPluginManager) discoverAdminPagesViaGRPC(pluginID string, clien
 	return nil
 }
 
+// defaultProviderPriority is the priority of a provider whose plugin
+// couldn't be asked for its own
+const defaultProviderPriority = 50
+
 // ExternalTranscodingProvider wraps an external plugin to provide transcoding services
 type ExternalTranscodingProvider struct {
 	pluginID   string
 	pluginInfo *ExternalPluginInfo
 	client     *ExternalPluginGRPCClient
+	info       plugins.ProviderInfo
 }
 
 // Ensure it implements the interface
 var _ plugins.TranscodingProvider = (*ExternalTranscodingProvider)(nil)
 
-// GetInfo returns provider information
-func (p *ExternalTranscodingProvider) GetInfo() plugins.ProviderInfo {
-	return plugins.ProviderInfo{
-		ID:          p.pluginID,
-		Name:        p.pluginInfo.Name,
-		Version:     p.pluginInfo.Version,
-		Description: p.pluginInfo.Description,
-		Author:      p.pluginInfo.Author,
-		Priority:    50, // Default priority
+// newExternalTranscodingProvider wraps a transcoder plugin, asking it once for
+// its priority and encoder capabilities. GetInfo is called while the provider
+// manager holds its lock, so it must not make a call of its own.
+func newExternalTranscodingProvider(pluginInfo *ExternalPluginInfo, client *ExternalPluginGRPCClient) *ExternalTranscodingProvider {
+	p := &ExternalTranscodingProvider{
+		pluginID:   pluginInfo.ID,
+		pluginInfo: pluginInfo,
+		client:     client,
+		info: plugins.ProviderInfo{
+			ID:          pluginInfo.ID,
+			Name:        pluginInfo.Name,
+			Version:     pluginInfo.Version,
+			Description: pluginInfo.Description,
+			Author:      pluginInfo.Author,
+			Priority:    defaultProviderPriority,
+		},
+	}
+
+	// Fetch encoder capabilities from the plugin so the host can negotiate codecs
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	resp, err := proto.NewTranscodingProviderServiceClient(client.conn).GetProviderInfo(ctx, &proto.GetProviderInfoRequest{})
+	if err != nil || resp.Info == nil {
+		hclog.Default().Named("external-transcoding-provider").Warn("failed to get provider info, using defaults",
+			"plugin_id", pluginInfo.ID, "error", err)
+		return p
+	}
+
+	p.info.Priority = int(resp.Info.Priority)
+	for capability := range resp.Info.Capabilities {
+		p.info.Capabilities = append(p.info.Capabilities, capability)
 	}
+	sort.Strings(p.info.Capabilities)
+
+	return p
+}
+
+// GetInfo returns the provider information fetched when the provider was created
+func (p *ExternalTranscodingProvider) GetInfo() plugins.ProviderInfo {
+	return p.info
 }
 
 // GetSupportedFormats returns supported container formats
@@ -2512,6 +2919,18 @@ func (p *ExternalTranscodingProvider) GetQualityPresets() []plugins.QualityPrese
 	}
 }
 
+// addSubtitleOptions passes the request's subtitle burn-in or passthrough
+// settings, which have no proto fields, as extra options
+func addSubtitleOptions(options map[string]string, req plugins.TranscodeRequest) {
+	if req.SubtitleMode == plugins.SubtitleNone {
+		return
+	}
+	options["subtitle_mode"] = string(req.SubtitleMode)
+	options["subtitle_stream"] = strconv.Itoa(req.SubtitleTrackIndex)
+	options["subtitle_codec"] = req.SubtitleCodec
+	options["subtitle_path"] = req.SubtitlePath
+}
+
 // StartTranscode starts a new transcoding job
 func (p *ExternalTranscodingProvider) StartTranscode(ctx context.Context, req plugins.TranscodeRequest) (*plugins.TranscodeHandle, error) {
 	logger := hclog.Default().Named("external-transcoding-provider")
@@ -2536,20 +2955,35 @@ func (p *ExternalTranscodingProvider) StartTranscode(ctx context.Context, req pl
 			Container:         req.Container,
 			VideoCodec:        req.VideoCodec,
 			AudioCodec:        req.AudioCodec,
+			AudioBitrateKbps:  int32(req.AudioBitrate),
 			PreferHardware:    req.PreferHardware,
 			HardwareType:      string(req.HardwareType),
 			// EnableAbr:         req.EnableABR, // TODO: Uncomment after proto regeneration
 			SeekNs:            int64(req.Seek), // Convert time.Duration to nanoseconds
 			ExtraOptions:      map[string]string{
 				"enable_abr": fmt.Sprintf("%t", req.EnableABR), // Pass ABR flag via extra options
+				"audio_only": fmt.Sprintf("%t", req.AudioOnly),
+				"deinterlace": string(req.Deinterlace),
+				"audio_stream": strconv.Itoa(req.AudioStreamIndex),
+				"ten_bit": fmt.Sprintf("%t", req.TenBit),
+				"gapless": fmt.Sprintf("%t", req.Gapless),
+				"fast_start": fmt.Sprintf("%t", req.FastStart),
 			},
 		},
 	}
+	addSubtitleOptions(protoReq.Request.ExtraOptions, req)
 	
 	// Handle resolution if provided
 	if req.Resolution != nil {
 		protoReq.Request.Resolution = fmt.Sprintf("%dx%d", req.Resolution.Width, req.Resolution.Height)
 	}
+
+	// Loudness settings have no proto field, so pass them as JSON
+	if req.Loudness != nil {
+		if loudnessJSON, err := json.Marshal(req.Loudness); err == nil {
+			protoReq.Request.ExtraOptions["loudness"] = string(loudnessJSON)
+		}
+	}
 	
 	logger.Info("Sending gRPC StartTranscode request",
 		"plugin_id", p.pluginID,
@@ -2631,6 +3065,9 @@ func (p *ExternalTranscodingProvider) GetProgress(handle *plugins.TranscodeHandl
 		TimeElapsed:     time.Duration(resp.Progress.TimeElapsed),
 		TimeRemaining:   time.Duration(resp.Progress.TimeRemaining),
 		CurrentSpeed:    resp.Progress.CurrentSpeed,
+		CurrentFrame:    int64(resp.Progress.CurrentFrame),
+		CurrentFPS:      resp.Progress.CurrentFps,
+		CurrentBitrate:  resp.Progress.CurrentBitrate,
 		BytesRead:       resp.Progress.BytesRead,
 		BytesWritten:    resp.Progress.BytesWritten,
 	}
@@ -2687,12 +3124,16 @@ func (p *ExternalTranscodingProvider) StartStream(ctx context.Context, req plugi
 			Container:         req.Container,
 			VideoCodec:        req.VideoCodec,
 			AudioCodec:        req.AudioCodec,
+			AudioBitrateKbps:  int32(req.AudioBitrate),
 			PreferHardware:    req.PreferHardware,
 			HardwareType:      string(req.HardwareType),
 			SeekNs:            int64(req.Seek), // Convert time.Duration to nanoseconds
-			ExtraOptions:      make(map[string]string), // Empty for now
+			ExtraOptions:      map[string]string{
+				"audio_only": fmt.Sprintf("%t", req.AudioOnly),
+			},
 		},
 	}
+	addSubtitleOptions(protoReq.Request.ExtraOptions, req)
 	
 	// Handle resolution if provided
 	if req.Resolution != nil {
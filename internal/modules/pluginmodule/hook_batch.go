@@ -0,0 +1,153 @@
+package pluginmodule
+
+import (
+	"sync"
+	"time"
+
+	plugins "github.com/mantonx/viewra/sdk"
+)
+
+const (
+	// batchScannerHooksCapability is the manifest capability of plugins that
+	// take scanned files in batches rather than one call per file
+	batchScannerHooksCapability = "batch_scanner_hooks"
+
+	// scannerHookBatchSize is the most files sent in one batched scanner hook
+	scannerHookBatchSize = 100
+
+	// scannerHookBatchDelay is how long a partial batch waits for more files
+	// before it is sent, so the last files of a scan aren't held back
+	scannerHookBatchDelay = 2 * time.Second
+)
+
+// scannedFileBatch is the files queued for one plugin's next batched hook
+type scannedFileBatch struct {
+	iface      ExternalPluginInterface
+	files      []plugins.ScannedMediaFile
+	libraryIDs []uint32
+	timer      *time.Timer
+}
+
+// scanHookBatcher collects scanned files per plugin and hands them to deliver
+// once scannerHookBatchSize have queued up or scannerHookBatchDelay has passed
+type scanHookBatcher struct {
+	mu      sync.Mutex
+	pending map[string]*scannedFileBatch
+	deliver func(pluginID string, batch *scannedFileBatch)
+}
+
+func newScanHookBatcher(deliver func(pluginID string, batch *scannedFileBatch)) *scanHookBatcher {
+	return &scanHookBatcher{
+		pending: make(map[string]*scannedFileBatch),
+		deliver: deliver,
+	}
+}
+
+// add queues a file for the plugin, delivering the batch once it is full
+func (b *scanHookBatcher) add(pluginID string, iface ExternalPluginInterface, file plugins.ScannedMediaFile, libraryID uint32) {
+	b.mu.Lock()
+	batch, ok := b.pending[pluginID]
+	if !ok {
+		batch = &scannedFileBatch{iface: iface}
+		b.pending[pluginID] = batch
+		batch.timer = time.AfterFunc(scannerHookBatchDelay, func() {
+			b.flush(pluginID, batch)
+		})
+	}
+	batch.files = append(batch.files, file)
+	batch.libraryIDs = append(batch.libraryIDs, libraryID)
+
+	full := len(batch.files) >= scannerHookBatchSize
+	if full {
+		batch.timer.Stop()
+		delete(b.pending, pluginID)
+	}
+	b.mu.Unlock()
+
+	if full {
+		go b.deliver(pluginID, batch)
+	}
+}
+
+// flush delivers the plugin's batch if it hasn't been sent already
+func (b *scanHookBatcher) flush(pluginID string, batch *scannedFileBatch) {
+	b.mu.Lock()
+	if b.pending[pluginID] != batch {
+		b.mu.Unlock()
+		return
+	}
+	delete(b.pending, pluginID)
+	b.mu.Unlock()
+
+	b.deliver(pluginID, batch)
+}
+
+// flushAll delivers every partial batch, as when a scan completes
+func (b *scanHookBatcher) flushAll() {
+	b.mu.Lock()
+	batches := b.pending
+	b.pending = make(map[string]*scannedFileBatch)
+	b.mu.Unlock()
+
+	for pluginID, batch := range batches {
+		batch.timer.Stop()
+		go b.deliver(pluginID, batch)
+	}
+}
+
+// batchesScannerHooks reports whether the plugin declared that it takes
+// scanned files in batches
+func (m *ExternalPluginManager) batchesScannerHooks(pluginID string) bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	plugin, ok := m.plugins[pluginID]
+	if !ok {
+		return false
+	}
+	for _, capability := range plugin.Capabilities {
+		if capability == batchScannerHooksCapability {
+			return true
+		}
+	}
+	return false
+}
+
+// deliverScannedBatch sends a batch of scanned files to a plugin in one call
+// and records the outcome for each file. An error for the whole call, such
+// as the plugin being unreachable, is recorded against every file.
+func (m *ExternalPluginManager) deliverScannedBatch(pluginID string, batch *scannedFileBatch) {
+	if !m.healthMonitor.ShouldAllowRequest(pluginID) {
+		m.logger.Warn("skipping plugin notification due to circuit breaker", "plugin_id", pluginID, "files", len(batch.files))
+		for i, file := range batch.files {
+			m.recordHookResult(pluginID, file.MediaFileID, batch.libraryIDs[i], plugins.HookStatusSkipped, hookSkipCircuitOpen, "", 0)
+		}
+		return
+	}
+
+	startTime := time.Now()
+	var results []error
+	err := m.callWithRetry(m.ctx, pluginID, func() error {
+		var callErr error
+		results, callErr = batch.iface.OnMediaFilesScanned(batch.files)
+		return callErr
+	})
+
+	responseTime := time.Since(startTime)
+	success := !countsAsPluginFailure(err)
+	m.healthMonitor.RecordRequest(pluginID, success, responseTime, err)
+
+	perFile := responseTime / time.Duration(len(batch.files))
+	for i, file := range batch.files {
+		fileErr := err
+		if err == nil && i < len(results) {
+			fileErr = results[i]
+		}
+		status, reason, detail := hookOutcome(fileErr)
+		m.recordHookResult(pluginID, file.MediaFileID, batch.libraryIDs[i], status, reason, detail, perFile)
+	}
+
+	if err != nil && !success {
+		m.logger.Error("plugin batched media file notification failed", "plugin", pluginID, "files", len(batch.files), "code", pluginErrorCode(err), "error", err)
+	}
+}
@@ -0,0 +1,203 @@
+package pluginmodule
+
+import (
+	"context"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mantonx/viewra/internal/database"
+	plugins "github.com/mantonx/viewra/sdk"
+)
+
+// hookSkipCircuitOpen is the skip reason recorded when the host withholds a
+// hook from a plugin whose circuit breaker is open
+const hookSkipCircuitOpen = "circuit_open"
+
+// applyDeclaredMediaTypes asks a freshly started plugin which media types its
+// metadata scraper handles. Declared types replace the manifest's
+// media_types, so a plugin configured to skip movies or TV shows stops
+// receiving those files; plugins without a scraper keep the manifest's list.
+func (m *ExternalPluginManager) applyDeclaredMediaTypes(ctx context.Context, plugin *ExternalPlugin, iface ExternalPluginInterface) {
+	client, ok := iface.(*ExternalPluginGRPCClient)
+	if !ok || client.conn == nil {
+		return
+	}
+
+	queryCtx, cancel := context.WithTimeout(ctx, capabilityQueryTimeout)
+	defer cancel()
+
+	declared, err := client.GetSupportedTypes(queryCtx)
+	if err != nil {
+		m.logger.Debug("plugin does not declare supported media types", "plugin", plugin.ID, "error", err)
+		return
+	}
+	if mediaTypes := normalizeMediaTypes(declared); len(mediaTypes) > 0 {
+		plugin.MediaTypes = mediaTypes
+		m.logger.Info("plugin declared supported media types", "plugin", plugin.ID, "media_types", mediaTypes)
+	}
+}
+
+// normalizeMediaTypes maps declared media types and tag-style names such as
+// "tv" onto the media types files are stored with
+func normalizeMediaTypes(declared []string) []string {
+	var mediaTypes []string
+	for _, value := range declared {
+		value = strings.ToLower(strings.TrimSpace(value))
+		if value == "" {
+			continue
+		}
+		if mapped, ok := mediaTypesByTag[value]; ok {
+			for _, mediaType := range mapped {
+				mediaTypes = appendFeature(mediaTypes, mediaType)
+			}
+			continue
+		}
+		mediaTypes = appendFeature(mediaTypes, value)
+	}
+	slices.Sort(mediaTypes)
+	return mediaTypes
+}
+
+// handlesScannerHooks reports whether scanner hooks for a file of the media
+// type should reach the plugin. Plugins whose manifest lists capabilities
+// without scanner_hooks never receive them, and plugins that declare media
+// types only receive files of those types. An empty media type, as for scan
+// start and completion, matches every plugin taking scanner hooks.
+func (p *ExternalPlugin) handlesScannerHooks(mediaType string) bool {
+	if len(p.Capabilities) > 0 && !slices.Contains(p.Capabilities, "scanner_hooks") {
+		return false
+	}
+	if mediaType == "" || len(p.MediaTypes) == 0 {
+		return true
+	}
+	return slices.Contains(p.MediaTypes, mediaType)
+}
+
+// fileHookPlugins returns the running plugins that should receive scanner
+// hooks for a file of the media type. When providers is non-nil, it is the
+// library's enrichment provider selection and other enrichers are left out.
+func (m *ExternalPluginManager) fileHookPlugins(providers map[string]bool, mediaType string) map[string]ExternalPluginInterface {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	runningPlugins := make(map[string]ExternalPluginInterface)
+	for id, iface := range m.pluginInterfaces {
+		plugin, known := m.plugins[id]
+		// Libraries with a provider selection only reach the selected enrichers
+		if providers != nil && !providers[id] {
+			if known && IsEnrichmentPlugin(plugin.ID, plugin.Name, plugin.Type) {
+				continue
+			}
+		}
+		// Plugins only hear about files of the media types they handle
+		if known && !plugin.handlesScannerHooks(mediaType) {
+			continue
+		}
+		runningPlugins[id] = iface
+	}
+	return runningPlugins
+}
+
+// scannedFileInfo is what the host knows about a scanned file and its
+// library, passed to plugins so they don't query core tables themselves
+type scannedFileInfo struct {
+	MediaType   string
+	LibraryID   uint32
+	LibraryType string
+	LibraryPath string
+	Container   string
+	Duration    int
+	SizeBytes   int64
+}
+
+// scannedFile loads a media file and its library, or returns zero values when
+// they can't be determined
+func (m *ExternalPluginManager) scannedFile(mediaFileID string) scannedFileInfo {
+	if m.db == nil {
+		return scannedFileInfo{}
+	}
+
+	var files []scannedFileInfo
+	err := m.db.Table("media_files").
+		Select("media_files.media_type, media_files.library_id, media_libraries.type AS library_type, " +
+			"media_libraries.path AS library_path, media_files.container, media_files.duration, media_files.size_bytes").
+		Joins("LEFT JOIN media_libraries ON media_libraries.id = media_files.library_id").
+		Where("media_files.id = ?", mediaFileID).
+		Limit(1).
+		Scan(&files).Error
+	if err != nil {
+		m.logger.Warn("failed to load media file", "media_file_id", mediaFileID, "error", err)
+		return scannedFileInfo{}
+	}
+	if len(files) == 0 {
+		return scannedFileInfo{}
+	}
+	return files[0]
+}
+
+// hookMetadata returns a copy of the scanner's metadata with the host's
+// file and library details added under the plugins.HookMetadata* keys
+func (f scannedFileInfo) hookMetadata(metadata map[string]string) map[string]string {
+	result := make(map[string]string, len(metadata)+7)
+	for key, value := range metadata {
+		result[key] = value
+	}
+
+	set := func(key, value string) {
+		if value != "" {
+			result[key] = value
+		}
+	}
+	set(plugins.HookMetadataMediaType, f.MediaType)
+	set(plugins.HookMetadataLibraryType, f.LibraryType)
+	set(plugins.HookMetadataLibraryPath, f.LibraryPath)
+	set(plugins.HookMetadataContainer, f.Container)
+	if f.LibraryID != 0 {
+		result[plugins.HookMetadataLibraryID] = strconv.FormatUint(uint64(f.LibraryID), 10)
+	}
+	if f.Duration > 0 {
+		result[plugins.HookMetadataDuration] = strconv.Itoa(f.Duration)
+	}
+	if f.SizeBytes > 0 {
+		result[plugins.HookMetadataSizeBytes] = strconv.FormatInt(f.SizeBytes, 10)
+	}
+	return result
+}
+
+// hookOutcome classifies the error a scanner hook returned. Not-found errors
+// are the plugin finding no match, so they count as skips rather than failures.
+func hookOutcome(err error) (plugins.HookStatus, string, string) {
+	if err == nil {
+		return plugins.HookStatusProcessed, "", ""
+	}
+	if skipErr, ok := plugins.AsSkipError(err); ok {
+		return plugins.HookStatusSkipped, skipErr.Reason, skipErr.Detail
+	}
+	if pluginErr, ok := plugins.AsPluginError(err); ok && pluginErr.Code == plugins.ErrorCodeNotFound {
+		return plugins.HookStatusSkipped, plugins.SkipReasonNoMatch, pluginErr.Message
+	}
+	return plugins.HookStatusFailed, "", err.Error()
+}
+
+// recordHookResult stores the outcome of a plugin's scanner hook for a file,
+// replacing the one from an earlier scan
+func (m *ExternalPluginManager) recordHookResult(pluginID, mediaFileID string, libraryID uint32, status plugins.HookStatus, reason, detail string, duration time.Duration) {
+	if m.db == nil {
+		return
+	}
+
+	result := &database.PluginHookResult{
+		MediaFileID: mediaFileID,
+		PluginID:    pluginID,
+		LibraryID:   libraryID,
+		Status:      string(status),
+		Reason:      reason,
+		Detail:      detail,
+		DurationMs:  duration.Milliseconds(),
+	}
+	if err := m.db.Save(result).Error; err != nil {
+		m.logger.Warn("failed to record scanner hook result", "plugin", pluginID, "media_file_id", mediaFileID, "error", err)
+	}
+}
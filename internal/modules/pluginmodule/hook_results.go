@@ -0,0 +1,205 @@
+package pluginmodule
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mantonx/viewra/internal/database"
+	plugins "github.com/mantonx/viewra/sdk"
+	"gorm.io/gorm"
+)
+
+// HookResultCount is how many files a plugin's scanner hook ended with a
+// status and reason for
+type HookResultCount struct {
+	PluginID string `json:"plugin_id"`
+	Status   string `json:"status"`
+	Reason   string `json:"reason,omitempty"`
+	Count    int64  `json:"count"`
+}
+
+// UnmatchedItem is a media file a plugin's scanner hook skipped or failed on
+type UnmatchedItem struct {
+	MediaFileID string    `json:"media_file_id"`
+	Path        string    `json:"path"`
+	MediaType   string    `json:"media_type"`
+	LibraryID   uint32    `json:"library_id"`
+	PluginID    string    `json:"plugin_id"`
+	Status      string    `json:"status"`
+	Reason      string    `json:"reason,omitempty"`
+	Detail      string    `json:"detail,omitempty"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// ReviewItem is a media file a plugin matched with too little confidence to
+// apply, waiting for a user to confirm the candidate or reject it
+type ReviewItem struct {
+	MediaFileID string                   `json:"media_file_id"`
+	Path        string                   `json:"path"`
+	MediaType   string                   `json:"media_type"`
+	LibraryID   uint32                   `json:"library_id"`
+	PluginID    string                   `json:"plugin_id"`
+	Candidate   *plugins.ReviewCandidate `json:"candidate"`
+	UpdatedAt   time.Time                `json:"updated_at"`
+}
+
+// hookResultQuery applies the library_id and plugin_id filters shared by the
+// hook result endpoints
+func (h *PluginAPIHandlers) hookResultQuery(c *gin.Context) (*gorm.DB, error) {
+	query := h.db.Model(&database.PluginHookResult{})
+	if libraryParam := c.Query("library_id"); libraryParam != "" {
+		libraryID, err := strconv.ParseUint(libraryParam, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid library ID")
+		}
+		query = query.Where("plugin_hook_results.library_id = ?", libraryID)
+	}
+	if pluginID := c.Query("plugin_id"); pluginID != "" {
+		query = query.Where("plugin_hook_results.plugin_id = ?", pluginID)
+	}
+	return query, nil
+}
+
+// handleGetHookResultStats counts scanner hook outcomes per plugin, status
+// and skip reason
+func (h *PluginAPIHandlers) handleGetHookResultStats(c *gin.Context) {
+	query, err := h.hookResultQuery(c)
+	if err != nil {
+		h.errorResponse(c, http.StatusBadRequest, err, "Invalid filter")
+		return
+	}
+
+	counts := []HookResultCount{}
+	if err := query.Select("plugin_id, status, reason, COUNT(*) AS count").
+		Group("plugin_id, status, reason").
+		Order("plugin_id, status, reason").
+		Scan(&counts).Error; err != nil {
+		h.errorResponse(c, http.StatusInternalServerError, err, "Failed to load hook result statistics")
+		return
+	}
+
+	h.successResponse(c, counts, "Hook result statistics retrieved successfully")
+}
+
+// handleGetUnmatchedItems lists files a plugin skipped or failed on, other
+// than those it had already processed or that an earlier provider of its
+// chain enriched. Filter by reason with ?reason=.
+func (h *PluginAPIHandlers) handleGetUnmatchedItems(c *gin.Context) {
+	page, limit := h.parsePagination(c)
+
+	query, err := h.hookResultQuery(c)
+	if err != nil {
+		h.errorResponse(c, http.StatusBadRequest, err, "Invalid filter")
+		return
+	}
+	query = query.
+		Joins("JOIN media_files ON media_files.id = plugin_hook_results.media_file_id").
+		Where("plugin_hook_results.status <> ?", string(plugins.HookStatusProcessed)).
+		Where("plugin_hook_results.reason NOT IN ?", []string{plugins.SkipReasonAlreadyProcessed, hookSkipChainSatisfied})
+	if reason := c.Query("reason"); reason != "" {
+		query = query.Where("plugin_hook_results.reason = ?", reason)
+	}
+	query = query.Session(&gorm.Session{})
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		h.errorResponse(c, http.StatusInternalServerError, err, "Failed to count unmatched items")
+		return
+	}
+
+	items := []UnmatchedItem{}
+	if err := query.Select("plugin_hook_results.media_file_id, media_files.path, media_files.media_type, " +
+		"plugin_hook_results.library_id, plugin_hook_results.plugin_id, plugin_hook_results.status, " +
+		"plugin_hook_results.reason, plugin_hook_results.detail, plugin_hook_results.updated_at").
+		Order("plugin_hook_results.updated_at DESC").
+		Offset((page - 1) * limit).Limit(limit).
+		Scan(&items).Error; err != nil {
+		h.errorResponse(c, http.StatusInternalServerError, err, "Failed to load unmatched items")
+		return
+	}
+
+	h.paginatedResponse(c, items, h.createPaginationMeta(page, limit, total), "Unmatched items retrieved successfully")
+}
+
+// handleGetReviewQueue lists files waiting for a user to confirm the match a
+// plugin queued for review. A candidate is confirmed by identifying the file
+// as it, and rejected with handleRejectReview.
+func (h *PluginAPIHandlers) handleGetReviewQueue(c *gin.Context) {
+	page, limit := h.parsePagination(c)
+
+	query, err := h.hookResultQuery(c)
+	if err != nil {
+		h.errorResponse(c, http.StatusBadRequest, err, "Invalid filter")
+		return
+	}
+	query = query.
+		Joins("JOIN media_files ON media_files.id = plugin_hook_results.media_file_id").
+		Where("plugin_hook_results.reason = ?", plugins.SkipReasonNeedsReview).
+		Session(&gorm.Session{})
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		h.errorResponse(c, http.StatusInternalServerError, err, "Failed to count review queue")
+		return
+	}
+
+	var rows []UnmatchedItem
+	if err := query.Select("plugin_hook_results.media_file_id, media_files.path, media_files.media_type, " +
+		"plugin_hook_results.library_id, plugin_hook_results.plugin_id, plugin_hook_results.detail, " +
+		"plugin_hook_results.updated_at").
+		Order("plugin_hook_results.updated_at DESC").
+		Offset((page - 1) * limit).Limit(limit).
+		Scan(&rows).Error; err != nil {
+		h.errorResponse(c, http.StatusInternalServerError, err, "Failed to load review queue")
+		return
+	}
+
+	items := make([]ReviewItem, 0, len(rows))
+	for _, row := range rows {
+		candidate, _ := plugins.AsReviewCandidate(plugins.SkipHook(plugins.SkipReasonNeedsReview, row.Detail))
+		items = append(items, ReviewItem{
+			MediaFileID: row.MediaFileID,
+			Path:        row.Path,
+			MediaType:   row.MediaType,
+			LibraryID:   row.LibraryID,
+			PluginID:    row.PluginID,
+			Candidate:   candidate,
+			UpdatedAt:   row.UpdatedAt,
+		})
+	}
+
+	h.paginatedResponse(c, items, h.createPaginationMeta(page, limit, total), "Review queue retrieved successfully")
+}
+
+// handleRejectReview turns down the match a plugin queued for a file, which
+// leaves the file unmatched for that plugin until it is identified by hand
+// or a later scan finds a better match
+func (h *PluginAPIHandlers) handleRejectReview(c *gin.Context) {
+	mediaFileID := c.Param("media_file_id")
+	pluginID := c.Query("plugin_id")
+	if pluginID == "" {
+		h.errorResponse(c, http.StatusBadRequest, fmt.Errorf("plugin_id is required"), "Invalid request")
+		return
+	}
+
+	result := h.db.Model(&database.PluginHookResult{}).
+		Where("media_file_id = ? AND plugin_id = ? AND reason = ?", mediaFileID, pluginID, plugins.SkipReasonNeedsReview).
+		Updates(map[string]interface{}{
+			"reason":     plugins.SkipReasonNoMatch,
+			"detail":     "match rejected by user",
+			"updated_at": time.Now(),
+		})
+	if result.Error != nil {
+		h.errorResponse(c, http.StatusInternalServerError, result.Error, "Failed to reject match")
+		return
+	}
+	if result.RowsAffected == 0 {
+		h.errorResponse(c, http.StatusNotFound, fmt.Errorf("no match is waiting for review"), "Review not found")
+		return
+	}
+
+	h.successResponse(c, gin.H{"media_file_id": mediaFileID, "plugin_id": pluginID}, "Match rejected")
+}
@@ -0,0 +1,95 @@
+package pluginmodule
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/mantonx/viewra/internal/database"
+	plugins "github.com/mantonx/viewra/sdk"
+	"github.com/mantonx/viewra/sdk/proto"
+)
+
+// SearchPlugin runs a search through a plugin's SearchService, for users
+// identifying a media file by hand. Rate limited and temporary failures are
+// retried; the plugin's error comes back typed, so AsPluginError reads its
+// code. Plugins built against older SDKs report failures in the response.
+func (m *ExternalPluginManager) SearchPlugin(ctx context.Context, pluginID string, query map[string]string, limit, offset uint32) (*proto.SearchResponse, error) {
+	client, ok := m.runningClient(pluginID)
+	if !ok {
+		return nil, ErrPluginNotRunning
+	}
+	if err := client.requireService(proto.SearchService_ServiceDesc.ServiceName); err != nil {
+		return nil, err
+	}
+
+	var resp *proto.SearchResponse
+	startTime := time.Now()
+	err := m.callWithRetry(ctx, pluginID, func() error {
+		var err error
+		resp, err = client.Search(ctx, query, limit, offset)
+		return err
+	})
+	m.healthMonitor.RecordRequest(pluginID, !countsAsPluginFailure(err), time.Since(startTime), err)
+	if err != nil {
+		return nil, err
+	}
+	if !resp.Success {
+		return nil, errors.New(resp.Error)
+	}
+	return resp, nil
+}
+
+// IsPluginRunning reports whether a plugin is loaded and can be called
+func (m *ExternalPluginManager) IsPluginRunning(pluginID string) bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	_, ok := m.pluginInterfaces[pluginID]
+	return ok
+}
+
+// IdentifyMediaFile has one plugin match a media file to the search result a
+// user picked, by sending it OnMediaFileUpdated with the result's ID. Unlike
+// the scanner notifications it waits for the plugin and returns its error.
+func (m *ExternalPluginManager) IdentifyMediaFile(ctx context.Context, pluginID, mediaFileID, filePath, externalID, mediaType string) error {
+	m.mu.RLock()
+	iface, ok := m.pluginInterfaces[pluginID]
+	m.mu.RUnlock()
+	if !ok {
+		return ErrPluginNotRunning
+	}
+
+	file := m.scannedFile(mediaFileID)
+	metadata := file.hookMetadata(map[string]string{plugins.HookMetadataIdentifyID: externalID})
+	m.addMatchPath(metadata, file.LibraryID, filePath)
+	if mediaType != "" {
+		metadata[plugins.HookMetadataIdentifyType] = mediaType
+	}
+
+	startTime := time.Now()
+	err := m.callWithRetry(ctx, pluginID, func() error {
+		return iface.OnMediaFileUpdated(mediaFileID, filePath, metadata)
+	})
+
+	responseTime := time.Since(startTime)
+	m.healthMonitor.RecordRequest(pluginID, !countsAsPluginFailure(err), responseTime, err)
+
+	status, reason, detail := hookOutcome(err)
+	m.recordHookResult(pluginID, mediaFileID, file.LibraryID, status, reason, detail, responseTime)
+	return err
+}
+
+// PendingReview returns the match a plugin queued for a user to confirm for
+// a media file, if there is one
+func (m *ExternalPluginManager) PendingReview(pluginID, mediaFileID string) (*plugins.ReviewCandidate, bool) {
+	if m.db == nil {
+		return nil, false
+	}
+
+	var result database.PluginHookResult
+	if err := m.db.Where("media_file_id = ? AND plugin_id = ? AND reason = ?", mediaFileID, pluginID, plugins.SkipReasonNeedsReview).
+		First(&result).Error; err != nil {
+		return nil, false
+	}
+	return plugins.AsReviewCandidate(plugins.SkipHook(result.Reason, result.Detail))
+}
@@ -0,0 +1,53 @@
+package pluginmodule
+
+import (
+	"github.com/mantonx/viewra/internal/database"
+	"github.com/mantonx/viewra/internal/logger"
+	plugins "github.com/mantonx/viewra/sdk"
+	"github.com/mantonx/viewra/sdk/namingparser"
+	"gorm.io/gorm"
+)
+
+// LibraryNamingRewrites returns a library's naming rules compiled, in the
+// order they apply. Rules are validated when saved; any that no longer
+// compile are skipped.
+func LibraryNamingRewrites(db *gorm.DB, libraryID uint32) ([]namingparser.Rewrite, error) {
+	if db == nil || libraryID == 0 {
+		return nil, nil
+	}
+
+	var rules []database.LibraryNamingRule
+	if err := db.Where("library_id = ?", libraryID).Order("position, id").Find(&rules).Error; err != nil {
+		return nil, err
+	}
+
+	rewrites := make([]namingparser.Rewrite, 0, len(rules))
+	for _, rule := range rules {
+		rewrite, err := namingparser.CompileRewrite(rule.Pattern, rule.Replacement)
+		if err != nil {
+			logger.Warn("Skipping naming rule %d of library %d: %v", rule.ID, libraryID, err)
+			continue
+		}
+		rewrites = append(rewrites, rewrite)
+	}
+	return rewrites, nil
+}
+
+// LibraryMatchPath returns the path a file of the library is matched by: its
+// path with the library's naming rules applied
+func LibraryMatchPath(db *gorm.DB, libraryID uint32, filePath string) string {
+	rewrites, err := LibraryNamingRewrites(db, libraryID)
+	if err != nil {
+		logger.Warn("Failed to load naming rules of library %d: %v", libraryID, err)
+		return filePath
+	}
+	return namingparser.ApplyRewrites(filePath, rewrites)
+}
+
+// addMatchPath sets the hook metadata's match path when the library's
+// naming rules rewrite the file's name
+func (m *ExternalPluginManager) addMatchPath(metadata map[string]string, libraryID uint32, filePath string) {
+	if matchPath := LibraryMatchPath(m.db, libraryID, filePath); matchPath != filePath {
+		metadata[plugins.HookMetadataMatchPath] = matchPath
+	}
+}
@@ -0,0 +1,41 @@
+package pluginmodule
+
+import (
+	"strings"
+
+	"github.com/mantonx/viewra/internal/database"
+)
+
+// IsEnrichmentPlugin reports whether a plugin enriches metadata, either by
+// declaring the metadata_scraper type or by the enricher naming convention
+func IsEnrichmentPlugin(id, name, pluginType string) bool {
+	return pluginType == "metadata_scraper" ||
+		strings.Contains(id, "enricher") ||
+		strings.Contains(strings.ToLower(name), "enricher")
+}
+
+// libraryProvidersForFile returns the enrichment plugins selected for the
+// media file's library, or nil when the library uses every enricher
+func (m *ExternalPluginManager) libraryProvidersForFile(mediaFileID string) map[string]bool {
+	if m.db == nil {
+		return nil
+	}
+
+	var providers []database.LibraryEnrichmentProvider
+	err := m.db.Where("library_id = (?)",
+		m.db.Model(&database.MediaFile{}).Select("library_id").Where("id = ?", mediaFileID),
+	).Find(&providers).Error
+	if err != nil {
+		m.logger.Warn("failed to load library enrichment providers", "media_file_id", mediaFileID, "error", err)
+		return nil
+	}
+	if len(providers) == 0 {
+		return nil
+	}
+
+	selected := make(map[string]bool, len(providers))
+	for _, provider := range providers {
+		selected[provider.PluginID] = true
+	}
+	return selected
+}
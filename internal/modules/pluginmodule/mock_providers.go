@@ -0,0 +1,46 @@
+package pluginmodule
+
+import (
+	"path/filepath"
+
+	"github.com/mantonx/viewra/internal/config"
+	"github.com/mantonx/viewra/sdk/cassette"
+)
+
+// mockProviderEnv returns the environment that puts plugins in mock provider
+// mode, so enrichers answer TMDb, MusicBrainz and AudioDB requests from
+// recorded cassettes. It's empty when mock mode is off.
+func (m *ExternalPluginManager) mockProviderEnv() []string {
+	cfg := config.Get().Plugins
+	mode, err := cassette.ParseMode(cfg.MockProviders)
+	if err != nil {
+		m.logger.Warn("ignoring invalid mock provider mode", "error", err)
+		return nil
+	}
+	if mode == cassette.ModeOff {
+		return nil
+	}
+
+	env := []string{cassette.ModeEnv + "=" + string(mode)}
+	if cfg.ProviderCassetteDir != "" {
+		// Plugins run from their own directory, so relative paths must be
+		// resolved against the host's working directory first
+		dir, err := filepath.Abs(cfg.ProviderCassetteDir)
+		if err != nil {
+			dir = cfg.ProviderCassetteDir
+		}
+		env = append(env, cassette.DirEnv+"="+dir)
+	}
+	return env
+}
+
+// logMockProviderMode warns at startup that provider traffic is mocked
+func (m *ExternalPluginManager) logMockProviderMode() {
+	env := m.mockProviderEnv()
+	if len(env) == 0 {
+		return
+	}
+	cfg := config.Get().Plugins
+	m.logger.Warn("mock provider mode enabled: enrichment plugins will use recorded responses instead of live metadata providers",
+		"mode", cfg.MockProviders, "cassette_dir", cfg.ProviderCassetteDir)
+}
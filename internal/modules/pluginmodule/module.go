@@ -82,6 +82,9 @@ type PluginModule struct {
 
 	// Dashboard manager
 	dashboardManager *DashboardManager
+
+	// Dispatches /api/plugins/<id>/* to plugin-declared routes
+	routeProxy *pluginRouteProxy
 }
 
 // Module interface implementation
@@ -521,6 +524,9 @@ func (pm *PluginModule) RegisterRoutes(router *gin.Engine) {
 		pm.logger.Info("registered external plugin health monitoring routes")
 	}
 
+	// Serve plugin admin page content through the HTTP bridge, to admins only
+	router.Group("/admin/plugins", requirePluginAdmin).Any("/:id/*path", pm.proxyAdminPageHandler)
+
 	pm.logger.Info("plugin module HTTP routes registered successfully")
 }
 
@@ -789,6 +795,7 @@ func NewPluginModule(db *gorm.DB, config *PluginModuleConfig) *PluginModule {
 		mediaManager:     NewMediaPluginManager(db, logger),
 		configManager:    configManager,
 		dashboardManager: NewDashboardManager(logger),
+		routeProxy:       newPluginRouteProxy(externalManager, logger),
 	}
 
 	// Initialize API handlers with all dependencies
@@ -0,0 +1,56 @@
+package pluginmodule
+
+import (
+	"context"
+	"time"
+
+	plugins "github.com/mantonx/viewra/sdk"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// playbackHookTimeout bounds the delivery of one playback event to a plugin,
+// long enough for a plugin to pass it on to a remote service
+const playbackHookTimeout = 15 * time.Second
+
+// NotifyPlaybackEvent reports a playback event to every running plugin that
+// serves the PlaybackHookService. Events are delivered in the background,
+// so playback never waits on a plugin.
+func (m *ExternalPluginManager) NotifyPlaybackEvent(event *plugins.PlaybackEvent) {
+	if event.OccurredAt.IsZero() {
+		event.OccurredAt = time.Now()
+	}
+
+	for _, plugin := range m.snapshotPlugins() {
+		if !plugin.Running {
+			continue
+		}
+		client, ok := m.runningClient(plugin.ID)
+		// Plugins that predate the handshake report no services; leave them out
+		if !ok || client.abi == nil || !client.abi.Supports(plugins.PlaybackHookServiceName) {
+			continue
+		}
+
+		go func(pluginID string, client *ExternalPluginGRPCClient) {
+			if !m.healthMonitor.ShouldAllowRequest(pluginID) {
+				m.logger.Warn("skipping playback event due to circuit breaker", "plugin_id", pluginID, "type", event.Type)
+				return
+			}
+
+			ctx, cancel := context.WithTimeout(m.ctx, playbackHookTimeout)
+			defer cancel()
+
+			startTime := time.Now()
+			err := plugins.SendPlaybackEvent(ctx, client.conn, event)
+			if status.Code(err) == codes.Unimplemented {
+				return
+			}
+
+			success := !countsAsPluginFailure(err)
+			m.healthMonitor.RecordRequest(pluginID, success, time.Since(startTime), err)
+			if err != nil {
+				m.logger.Warn("plugin playback event failed", "plugin", pluginID, "type", event.Type, "session_id", event.SessionID, "error", err)
+			}
+		}(plugin.ID, client)
+	}
+}
@@ -0,0 +1,102 @@
+package pluginmodule
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/mantonx/viewra/internal/config"
+	plugins "github.com/mantonx/viewra/sdk"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// HostVersion identifies this host in the plugin handshake
+const HostVersion = "viewra/" + plugins.SDKVersion
+
+// minPluginABIVersion is the oldest plugin protocol the host still loads.
+// Plugins older than this are rejected with a request to rebuild them.
+const minPluginABIVersion = plugins.LegacyABIVersion
+
+// PluginABI is the result of the version handshake with a loaded plugin
+type PluginABI struct {
+	SDKVersion string   `json:"sdk_version"`
+	ABIVersion int      `json:"abi_version"`
+	Services   []string `json:"services"`
+	// Compatibility is set for plugins that predate the handshake; only the
+	// plugin.proto services are used with them
+	Compatibility bool `json:"compatibility_mode"`
+}
+
+// Supports reports whether the plugin serves the named gRPC service
+func (a *PluginABI) Supports(service string) bool {
+	if a == nil {
+		return true
+	}
+	return slices.Contains(a.Services, service)
+}
+
+// negotiateABI runs the version handshake with a freshly started plugin.
+// Plugins without the handshake service run in compatibility mode; plugins
+// whose protocol is too old or that need a newer host return an error
+// explaining what to do.
+func (m *ExternalPluginManager) negotiateABI(ctx context.Context, pluginID string, client *ExternalPluginGRPCClient) (*PluginABI, error) {
+	handshakeCtx, cancel := context.WithTimeout(ctx, capabilityQueryTimeout)
+	defer cancel()
+
+	resp, err := plugins.NegotiateABI(handshakeCtx, client.conn, &plugins.ABIHandshakeRequest{
+		HostVersion:    HostVersion,
+		ABIVersion:     plugins.ABIVersion,
+		MinABIVersion:  minPluginABIVersion,
+		MaxMessageSize: config.Get().Plugins.GRPCMaxMessageSize,
+	})
+	switch {
+	case status.Code(err) == codes.Unimplemented:
+		if plugins.LegacyABIVersion < minPluginABIVersion {
+			return nil, fmt.Errorf("plugin %s was built with an SDK that predates ABI negotiation and is no longer supported; rebuild it against SDK %s",
+				pluginID, plugins.SDKVersion)
+		}
+		m.logger.Warn("plugin predates ABI negotiation, running in compatibility mode; rebuild it to enable newer services",
+			"plugin", pluginID, "host_sdk_version", plugins.SDKVersion)
+		return &PluginABI{
+			ABIVersion:    plugins.LegacyABIVersion,
+			Services:      plugins.LegacyServices(),
+			Compatibility: true,
+		}, nil
+	case status.Code(err) == codes.FailedPrecondition:
+		return nil, fmt.Errorf("plugin %s refused this host: %s", pluginID, status.Convert(err).Message())
+	case err != nil:
+		return nil, fmt.Errorf("plugin %s ABI handshake failed: %w", pluginID, err)
+	}
+
+	if resp.ABIVersion < minPluginABIVersion {
+		return nil, fmt.Errorf("plugin %s uses ABI %d (SDK %s) but this host requires ABI %d or newer; rebuild it against SDK %s",
+			pluginID, resp.ABIVersion, resp.SDKVersion, minPluginABIVersion, plugins.SDKVersion)
+	}
+	if resp.MinHostABIVersion > plugins.ABIVersion {
+		return nil, fmt.Errorf("plugin %s (SDK %s) requires host ABI %d but this host speaks ABI %d; upgrade Viewra to use it",
+			pluginID, resp.SDKVersion, resp.MinHostABIVersion, plugins.ABIVersion)
+	}
+
+	abi := &PluginABI{
+		SDKVersion:    resp.SDKVersion,
+		ABIVersion:    resp.ABIVersion,
+		Services:      resp.Services,
+		Compatibility: resp.ABIVersion != plugins.ABIVersion,
+	}
+	m.logger.Info("negotiated plugin ABI",
+		"plugin", pluginID,
+		"sdk_version", abi.SDKVersion,
+		"abi_version", abi.ABIVersion,
+		"compatibility_mode", abi.Compatibility)
+	return abi, nil
+}
+
+// requireService returns a codes.Unimplemented status when the plugin's
+// negotiated ABI doesn't include service, sparing a call it can't answer
+func (c *ExternalPluginGRPCClient) requireService(service string) error {
+	if !c.abi.Supports(service) {
+		return status.Errorf(codes.Unimplemented, "plugin does not serve %s", service)
+	}
+	return nil
+}
@@ -0,0 +1,74 @@
+package pluginmodule
+
+import (
+	"context"
+	"time"
+
+	plugins "github.com/mantonx/viewra/sdk"
+)
+
+// callWithRetry runs call, retrying failures the plugin reports as rate limited
+// or temporary. The plugin's RetryAfter hint is honoured; otherwise the delay
+// backs off per the plugin's reliability config. Other errors return at once.
+func (m *ExternalPluginManager) callWithRetry(ctx context.Context, pluginID string, call func() error) error {
+	cfg := m.reliabilityConfig.GetPluginConfig(pluginID)
+	delay := cfg.InitialRetryDelay
+
+	var err error
+	for attempt := 0; ; attempt++ {
+		if err = call(); err == nil {
+			return nil
+		}
+
+		pluginErr, ok := plugins.AsPluginError(err)
+		if !ok || !pluginErr.Retryable() || attempt >= cfg.MaxRetries {
+			return err
+		}
+
+		wait := delay
+		if pluginErr.RetryAfter > 0 {
+			wait = pluginErr.RetryAfter
+		}
+		if cfg.MaxRetryDelay > 0 && wait > cfg.MaxRetryDelay {
+			wait = cfg.MaxRetryDelay
+		}
+
+		m.logger.Debug("retrying plugin call",
+			"plugin_id", pluginID,
+			"code", pluginErr.Code,
+			"attempt", attempt+1,
+			"wait", wait)
+
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(wait):
+		}
+		delay = time.Duration(float64(delay) * cfg.BackoffMultiplier)
+	}
+}
+
+// countsAsPluginFailure reports whether an error reflects an unhealthy plugin.
+// Not-found and invalid-argument errors are correct answers and shouldn't
+// trip the circuit breaker.
+func countsAsPluginFailure(err error) bool {
+	if err == nil {
+		return false
+	}
+	if _, skipped := plugins.AsSkipError(err); skipped {
+		return false
+	}
+	pluginErr, ok := plugins.AsPluginError(err)
+	if !ok {
+		return true
+	}
+	return pluginErr.Code != plugins.ErrorCodeNotFound && pluginErr.Code != plugins.ErrorCodeInvalidArgument
+}
+
+// pluginErrorCode returns err's plugin error code for logging, or INTERNAL when untyped
+func pluginErrorCode(err error) plugins.ErrorCode {
+	if pluginErr, ok := plugins.AsPluginError(err); ok {
+		return pluginErr.Code
+	}
+	return plugins.ErrorCodeInternal
+}
@@ -0,0 +1,91 @@
+package pluginmodule
+
+import (
+	"strconv"
+
+	"github.com/mantonx/viewra/internal/config"
+	plugins "github.com/mantonx/viewra/sdk"
+)
+
+// hookSkipChainSatisfied is the skip reason recorded for the providers of a
+// chain left untried because an earlier one enriched the file
+const hookSkipChainSatisfied = "chain_satisfied"
+
+// providerChainStep is a running plugin of a provider chain
+type providerChainStep struct {
+	pluginID string
+	iface    ExternalPluginInterface
+}
+
+// providerChainRun is the running plugins of the chain configured for a
+// media type, in chain order
+type providerChainRun struct {
+	steps         []providerChainStep
+	minConfidence float64
+}
+
+// takeProviderChain moves the plugins of the media type's provider chain out
+// of running and returns them in chain order, or returns nil when the media
+// type has no chain or none of its plugins would get the file. Chain plugins
+// get files one at a time even when they take scanner hooks in batches.
+func (m *ExternalPluginManager) takeProviderChain(mediaType string, running map[string]ExternalPluginInterface) *providerChainRun {
+	chain, ok := config.Get().Plugins.ProviderChains[mediaType]
+	if !ok || mediaType == "" {
+		return nil
+	}
+
+	run := &providerChainRun{minConfidence: chain.MinConfidence}
+	for _, pluginID := range chain.Providers {
+		iface, ok := running[pluginID]
+		if !ok {
+			continue
+		}
+		delete(running, pluginID)
+		run.steps = append(run.steps, providerChainStep{pluginID: pluginID, iface: iface})
+	}
+	if len(run.steps) == 0 {
+		return nil
+	}
+	return run
+}
+
+// runProviderChain sends a scanned file to the chain's plugins in order until
+// one enriches it. A plugin that fails, finds no match or matches below the
+// chain's confidence passes the file on to the next; the plugins after the
+// one that enriched it are recorded as skipped. The chain's confidence is
+// passed to each plugin under plugins.HookMetadataMinConfidence.
+func (m *ExternalPluginManager) runProviderChain(run *providerChainRun, mediaFileID, filePath string, metadata map[string]string, libraryID uint32) {
+	if run.minConfidence > 0 {
+		chainMetadata := make(map[string]string, len(metadata)+1)
+		for key, value := range metadata {
+			chainMetadata[key] = value
+		}
+		chainMetadata[plugins.HookMetadataMinConfidence] = strconv.FormatFloat(run.minConfidence, 'f', -1, 64)
+		metadata = chainMetadata
+	}
+
+	for i, step := range run.steps {
+		status, reason := m.deliverScannedFile(step.pluginID, step.iface, mediaFileID, filePath, metadata, libraryID)
+		if !chainContinues(status, reason) {
+			for _, skipped := range run.steps[i+1:] {
+				m.recordHookResult(skipped.pluginID, mediaFileID, libraryID, plugins.HookStatusSkipped, hookSkipChainSatisfied, step.pluginID, 0)
+			}
+			return
+		}
+		m.logger.Debug("provider chain falling through", "plugin", step.pluginID, "media_file_id", mediaFileID, "status", status, "reason", reason)
+	}
+}
+
+// chainContinues reports whether a chain plugin's outcome passes the file on
+// to the next provider. A file the plugin had already enriched ends the
+// chain like a new match does.
+func chainContinues(status plugins.HookStatus, reason string) bool {
+	switch status {
+	case plugins.HookStatusProcessed:
+		return false
+	case plugins.HookStatusSkipped:
+		return reason != plugins.SkipReasonAlreadyProcessed
+	default:
+		return true
+	}
+}
@@ -0,0 +1,95 @@
+package pluginmodule
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	plugins "github.com/mantonx/viewra/sdk"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// PluginProviderMetrics is the latency histogram and status code counts of
+// the requests one plugin made to an external provider
+type PluginProviderMetrics struct {
+	PluginID   string `json:"plugin_id"`
+	PluginName string `json:"plugin_name"`
+	*plugins.ProviderSnapshot
+}
+
+// ProviderMetricsError records a plugin whose performance snapshot couldn't be fetched
+type ProviderMetricsError struct {
+	PluginID string `json:"plugin_id"`
+	Error    string `json:"error"`
+}
+
+// GetProviderMetrics collects the external provider metrics of every running
+// plugin that serves the PerformanceMonitorService, slowest provider first by
+// 95th percentile. Plugins are queried in parallel like dashboard widgets.
+func (pm *PluginModule) GetProviderMetrics(ctx context.Context) ([]PluginProviderMetrics, []ProviderMetricsError) {
+	metrics := []PluginProviderMetrics{}
+	failures := []ProviderMetricsError{}
+	if pm.externalManager == nil {
+		return metrics, failures
+	}
+
+	var (
+		mu sync.Mutex
+		wg sync.WaitGroup
+	)
+	for _, plugin := range pm.externalManager.snapshotPlugins() {
+		if !plugin.Running {
+			continue
+		}
+		client, ok := pm.externalManager.runningClient(plugin.ID)
+		if !ok || !client.abi.Supports(plugins.PerformanceMonitorServiceName) {
+			continue
+		}
+
+		wg.Add(1)
+		go func(plugin ExternalPlugin, client *ExternalPluginGRPCClient) {
+			defer wg.Done()
+
+			snapshotCtx, cancel := context.WithTimeout(ctx, capabilityQueryTimeout)
+			snapshot, err := plugins.GetPluginPerformanceSnapshot(snapshotCtx, client.conn)
+			cancel()
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				// Plugins without a performance monitor have nothing to report
+				if status.Code(err) != codes.Unimplemented {
+					pm.logger.Debug("failed to get performance snapshot", "plugin_id", plugin.ID, "error", err)
+					failures = append(failures, ProviderMetricsError{PluginID: plugin.ID, Error: err.Error()})
+				}
+				return
+			}
+			for _, provider := range snapshot.Providers {
+				if provider == nil {
+					continue
+				}
+				metrics = append(metrics, PluginProviderMetrics{
+					PluginID:         plugin.ID,
+					PluginName:       plugin.Name,
+					ProviderSnapshot: provider,
+				})
+			}
+		}(plugin, client)
+	}
+	wg.Wait()
+
+	sort.Slice(metrics, func(i, j int) bool {
+		if metrics[i].P95 != metrics[j].P95 {
+			return metrics[i].P95 > metrics[j].P95
+		}
+		if metrics[i].PluginID != metrics[j].PluginID {
+			return metrics[i].PluginID < metrics[j].PluginID
+		}
+		return metrics[i].Provider < metrics[j].Provider
+	})
+	sort.Slice(failures, func(i, j int) bool {
+		return failures[i].PluginID < failures[j].PluginID
+	})
+	return metrics, failures
+}
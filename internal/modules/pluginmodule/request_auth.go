@@ -0,0 +1,73 @@
+package pluginmodule
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mantonx/viewra/internal/auth"
+	"github.com/mantonx/viewra/internal/database"
+)
+
+// pluginUserHeader tells a plugin which user made a forwarded request. Incoming
+// values are always dropped so clients can't spoof it.
+const pluginUserHeader = "X-Viewra-User"
+
+// authorizePluginRequest enforces authentication for requests forwarded to
+// plugins. When authentication is disabled in the security config every
+// request is allowed; otherwise the bearer token must name a live session,
+// checked as for every other API request. Requests RequireAuth already
+// authenticated aren't checked again. Responds with 401 and returns false
+// when rejected.
+func authorizePluginRequest(c *gin.Context) bool {
+	if !auth.Enabled() {
+		return true
+	}
+	if _, ok := auth.CurrentUser(c); ok {
+		return true
+	}
+
+	token := auth.RequestToken(c)
+	if token == "" {
+		c.Header("WWW-Authenticate", `Bearer realm="viewra"`)
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Authentication required"})
+		return false
+	}
+	user, session, err := auth.Authenticate(database.GetDB(), token, time.Now())
+	if err != nil {
+		c.Header("WWW-Authenticate", `Bearer realm="viewra", error="invalid_token"`)
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":   "Invalid token",
+			"details": err.Error(),
+		})
+		return false
+	}
+
+	auth.SetUser(c, user, session)
+	return true
+}
+
+// requirePluginAdmin restricts plugin admin pages to admins. They are served
+// outside /api, where the global RequireAuth middleware doesn't apply, so the
+// token is checked here. Aborts with 401 or 403 when refused.
+func requirePluginAdmin(c *gin.Context) {
+	if !authorizePluginRequest(c) {
+		c.Abort()
+		return
+	}
+	if user, ok := auth.CurrentUser(c); ok && !user.IsAdmin() {
+		c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "Admin access required"})
+		return
+	}
+	c.Next()
+}
+
+// pluginRequestUser is the value of pluginUserHeader for a request: the
+// authenticated user's ID, or "" when it wasn't authenticated
+func pluginRequestUser(c *gin.Context) string {
+	if user, ok := auth.CurrentUser(c); ok {
+		return strconv.FormatUint(uint64(user.ID), 10)
+	}
+	return ""
+}
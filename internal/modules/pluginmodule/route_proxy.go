@@ -0,0 +1,257 @@
+package pluginmodule
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hashicorp/go-hclog"
+	"github.com/mantonx/viewra/internal/config"
+	plugins "github.com/mantonx/viewra/sdk"
+)
+
+const (
+	// routeProxyTimeout bounds a single forwarded API request
+	routeProxyTimeout = 30 * time.Second
+	// routeProxyMaxBody limits request bodies forwarded to plugin API routes
+	routeProxyMaxBody = 1024 * 1024
+	// routeProxyMaxQuery limits the query string forwarded to plugin API routes
+	routeProxyMaxQuery = 8 * 1024
+	// routeCacheTTL is how long a plugin's declared routes are reused before asking again
+	routeCacheTTL = 30 * time.Second
+	// routeRateBurstSeconds sizes each plugin's burst allowance in seconds of its rate limit
+	routeRateBurstSeconds = 10
+)
+
+// pluginRouteProxy dispatches /api/plugins/<id>/* requests to the routes a
+// plugin declares through APIRegistrationService
+type pluginRouteProxy struct {
+	externalManager *ExternalPluginManager
+	logger          hclog.Logger
+
+	mu       sync.Mutex
+	routes   map[string]cachedPluginRoutes
+	limiters map[string]*tokenBucket
+}
+
+type cachedPluginRoutes struct {
+	routes    []*plugins.APIRoute
+	fetchedAt time.Time
+}
+
+func newPluginRouteProxy(externalManager *ExternalPluginManager, logger hclog.Logger) *pluginRouteProxy {
+	return &pluginRouteProxy{
+		externalManager: externalManager,
+		logger:          logger.Named("route-proxy"),
+		routes:          make(map[string]cachedPluginRoutes),
+		limiters:        make(map[string]*tokenBucket),
+	}
+}
+
+// ServePluginRoute forwards a request for /api/plugins/<pluginID>/<path> to the
+// plugin. Only declared routes are reachable; routes not marked Public require
+// authentication, and each plugin is rate limited independently.
+func (pm *PluginModule) ServePluginRoute(c *gin.Context, pluginID, path string) {
+	if pm.externalManager == nil || pm.routeProxy == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "External plugins are not available"})
+		return
+	}
+	proxy := pm.routeProxy
+
+	if _, exists := pm.externalManager.GetPlugin(pluginID); !exists {
+		c.JSON(http.StatusNotFound, gin.H{"error": ErrPluginNotFound})
+		return
+	}
+
+	routes, err := proxy.pluginRoutes(c.Request.Context(), pluginID)
+	if err != nil {
+		pm.writeBridgeError(c, pluginID, err, "Plugin does not register API routes")
+		return
+	}
+
+	path = "/" + strings.Trim(path, "/")
+	route, methodAllowed := matchPluginRoute(routes, c.Request.Method, path)
+	if route == nil {
+		if methodAllowed {
+			c.JSON(http.StatusMethodNotAllowed, gin.H{"error": "Method not allowed"})
+			return
+		}
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":     "Plugin route not found",
+			"plugin_id": pluginID,
+			"path":      path,
+		})
+		return
+	}
+
+	if !route.Public && !authorizePluginRequest(c) {
+		return
+	}
+
+	if retryAfter, ok := proxy.allow(pluginID); !ok {
+		c.Header("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+		c.JSON(http.StatusTooManyRequests, gin.H{
+			"error":     "Rate limit exceeded",
+			"plugin_id": pluginID,
+		})
+		return
+	}
+
+	if len(c.Request.URL.RawQuery) > routeProxyMaxQuery {
+		c.JSON(http.StatusRequestURITooLong, gin.H{"error": "Query string too long"})
+		return
+	}
+	body, ok := readBridgeBody(c, routeProxyMaxBody)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), routeProxyTimeout)
+	defer cancel()
+
+	resp, err := pm.externalManager.ForwardHTTP(ctx, pluginID,
+		newBridgeRequest(c, "/api/plugins/"+pluginID, path, body))
+	if err != nil {
+		pm.writeBridgeError(c, pluginID, err, "Plugin does not serve its API routes")
+		return
+	}
+	writeBridgeResponse(c, resp)
+}
+
+// IsPublicRoute reports whether the route a request for
+// /api/plugins/<pluginID>/<path> is matched to is declared Public by the
+// plugin. Unknown plugins and routes, and plugins whose routes can't be
+// loaded, are not public.
+func (pm *PluginModule) IsPublicRoute(ctx context.Context, pluginID, method, path string) bool {
+	if pm.externalManager == nil || pm.routeProxy == nil {
+		return false
+	}
+	routes, err := pm.routeProxy.pluginRoutes(ctx, pluginID)
+	if err != nil {
+		return false
+	}
+	route, _ := matchPluginRoute(routes, method, "/"+strings.Trim(path, "/"))
+	return route != nil && route.Public
+}
+
+// pluginRoutes returns the plugin's declared routes, cached for routeCacheTTL
+func (p *pluginRouteProxy) pluginRoutes(ctx context.Context, pluginID string) ([]*plugins.APIRoute, error) {
+	p.mu.Lock()
+	cached, ok := p.routes[pluginID]
+	p.mu.Unlock()
+	if ok && time.Since(cached.fetchedAt) < routeCacheTTL {
+		return cached.routes, nil
+	}
+
+	client, ok := p.externalManager.runningClient(pluginID)
+	if !ok {
+		return nil, ErrPluginNotRunning
+	}
+	if err := client.requireService(plugins.HTTPBridgeServiceName); err != nil {
+		return nil, err
+	}
+
+	routesCtx, cancel := context.WithTimeout(ctx, capabilityQueryTimeout)
+	defer cancel()
+
+	routes, err := plugins.GetBridgeRoutes(routesCtx, client.conn)
+	if err != nil {
+		return nil, err
+	}
+
+	p.mu.Lock()
+	p.routes[pluginID] = cachedPluginRoutes{routes: routes, fetchedAt: time.Now()}
+	p.mu.Unlock()
+
+	return routes, nil
+}
+
+// allow takes a token from the plugin's bucket, returning how long to wait when empty
+func (p *pluginRouteProxy) allow(pluginID string) (time.Duration, bool) {
+	security := config.Get().Security
+	if !security.RateLimitEnabled || security.RateLimitRPM <= 0 {
+		return 0, true
+	}
+	rate := float64(security.RateLimitRPM) / 60
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	bucket, ok := p.limiters[pluginID]
+	if !ok {
+		bucket = &tokenBucket{}
+		p.limiters[pluginID] = bucket
+	}
+	return bucket.take(time.Now(), rate, math.Max(1, rate*routeRateBurstSeconds))
+}
+
+// tokenBucket is a lazily refilled token bucket
+type tokenBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// take refills the bucket for the time elapsed and consumes one token
+func (b *tokenBucket) take(now time.Time, rate, burst float64) (time.Duration, bool) {
+	if b.last.IsZero() {
+		b.tokens = burst
+	} else {
+		b.tokens = math.Min(burst, b.tokens+now.Sub(b.last).Seconds()*rate)
+	}
+	b.last = now
+
+	if b.tokens >= 1 {
+		b.tokens--
+		return 0, true
+	}
+	return time.Duration((1 - b.tokens) / rate * float64(time.Second)), false
+}
+
+// matchPluginRoute finds the declared route for a request. Route paths may use
+// :name or {name} segments and end in * to match any remainder. When no route
+// matches, methodAllowed reports whether the path exists for another method.
+func matchPluginRoute(routes []*plugins.APIRoute, method, path string) (route *plugins.APIRoute, methodAllowed bool) {
+	for _, candidate := range routes {
+		if candidate == nil || !matchRoutePath(candidate.Path, path) {
+			continue
+		}
+		routeMethod := strings.ToUpper(candidate.Method)
+		if routeMethod == method || routeMethod == "*" || routeMethod == "ANY" ||
+			(routeMethod == http.MethodGet && method == http.MethodHead) {
+			return candidate, false
+		}
+		methodAllowed = true
+	}
+	return nil, methodAllowed
+}
+
+func matchRoutePath(pattern, path string) bool {
+	patternSegments := strings.Split(strings.Trim(pattern, "/"), "/")
+	pathSegments := strings.Split(strings.Trim(path, "/"), "/")
+
+	for i, segment := range patternSegments {
+		if strings.HasPrefix(segment, "*") {
+			return true
+		}
+		if i >= len(pathSegments) {
+			return false
+		}
+		isParam := strings.HasPrefix(segment, ":") ||
+			(strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}"))
+		if isParam {
+			if pathSegments[i] == "" {
+				return false
+			}
+			continue
+		}
+		if segment != pathSegments[i] {
+			return false
+		}
+	}
+	return len(patternSegments) == len(pathSegments)
+}
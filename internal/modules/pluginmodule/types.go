@@ -67,15 +67,18 @@ type MetadataContext struct {
 
 // ExternalPlugin represents an external plugin instance
 type ExternalPlugin struct {
-	ID          string    `json:"id"`
-	Name        string    `json:"name"`
-	Type        string    `json:"type"`
-	Version     string    `json:"version"`
-	Description string    `json:"description"`
-	Running     bool      `json:"running"`
-	Path        string    `json:"path"`
-	LastStarted time.Time `json:"last_started"`
-	LastStopped time.Time `json:"last_stopped"`
+	ID           string    `json:"id"`
+	Name         string    `json:"name"`
+	Type         string    `json:"type"`
+	Version      string    `json:"version"`
+	Description  string    `json:"description"`
+	Tags         []string  `json:"tags,omitempty"`
+	Capabilities []string  `json:"capabilities,omitempty"` // Manifest capability flags that are enabled
+	MediaTypes   []string  `json:"media_types,omitempty"`  // Media types the plugin's scanner hooks handle, empty = all
+	Running      bool      `json:"running"`
+	Path         string    `json:"path"`
+	LastStarted  time.Time `json:"last_started"`
+	LastStopped  time.Time `json:"last_stopped"`
 }
 
 // PluginInfo represents information about a plugin for API responses
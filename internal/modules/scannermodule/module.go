@@ -29,6 +29,7 @@ const (
 // Module implements the scanner functionality as a module
 type Module struct {
 	scannerManager *scanner.Manager
+	scheduler      *scanner.Scheduler
 	db             *gorm.DB
 	eventBus       events.EventBus
 	pluginModule   *pluginmodule.PluginModule
@@ -139,6 +140,11 @@ func (m *Module) Start() error {
 		logger.Info("File monitoring service started successfully")
 	}
 
+	// Start per-library scan schedules, including scans on startup
+	logger.Info("Starting scan scheduler...")
+	m.scheduler = scanner.NewScheduler(m.db, m.scannerManager)
+	m.scheduler.Start()
+
 	logger.Info("Scanner module started successfully")
 	return nil
 }
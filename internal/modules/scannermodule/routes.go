@@ -1,7 +1,11 @@
 package scannermodule
 
 import (
+	"bytes"
+	"errors"
+	"fmt"
 	"net/http"
+	"os"
 	"strconv"
 
 	"github.com/gin-gonic/gin"
@@ -23,6 +27,7 @@ func (m *Module) RegisterRoutes(router *gin.Engine) {
 
 		// Individual scan job operations
 		api.GET("/jobs/:id", m.getScanStatus)
+		api.GET("/jobs/:id/report", m.getScanReport)
 		api.DELETE("/jobs/:id", m.cancelScan)
 		api.POST("/resume/:id", m.resumeScan)
 
@@ -56,6 +61,56 @@ func (m *Module) getScanStatus(c *gin.Context) {
 	c.JSON(http.StatusOK, status)
 }
 
+// getScanReport downloads the per-file report of a finished scan job as JSON or CSV
+func (m *Module) getScanReport(c *gin.Context) {
+	jobID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid job ID",
+		})
+		return
+	}
+
+	format := c.DefaultQuery("format", "json")
+	if format != "json" && format != "csv" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Unsupported format, expected json or csv",
+		})
+		return
+	}
+
+	report, err := m.scannerManager.GetScanReport(uint32(jobID))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error": "No report available for this scan job",
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	filename := fmt.Sprintf("scan-%d-report.%s", jobID, format)
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+
+	if format == "csv" {
+		var buf bytes.Buffer
+		if err := report.WriteCSV(&buf); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
+		c.Data(http.StatusOK, "text/csv; charset=utf-8", buf.Bytes())
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
 // cancelScan cancels a specific scan job
 func (m *Module) cancelScan(c *gin.Context) {
 	jobIDStr := c.Param("id")
@@ -3,6 +3,7 @@ package scanner
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io/fs"
 	"os"
@@ -20,6 +21,7 @@ import (
 	"github.com/mantonx/viewra/internal/events"
 	"github.com/mantonx/viewra/internal/logger"
 	"github.com/mantonx/viewra/internal/modules/pluginmodule"
+	"github.com/mantonx/viewra/internal/utils"
 	"gorm.io/gorm"
 )
 
@@ -193,6 +195,8 @@ type LibraryScanner struct {
 	bytesProcessed atomic.Int64
 	bytesFound     atomic.Int64
 	errorsCount    atomic.Int64
+	filesQueued    atomic.Int64
+	filesDone      atomic.Int64
 
 	// Scanner control
 	ctx     context.Context
@@ -211,6 +215,20 @@ type LibraryScanner struct {
 	// Progress tracking
 	lastProgressUpdate time.Time
 	progressMutex      sync.RWMutex
+
+	// Per-file outcomes, written to reportDir when the scan ends.
+	// Reports are disabled when reportDir is empty.
+	report    *scanReportCollector
+	reportDir string
+
+	// Time spent per stage, handler and hook, when profiling is on. Only
+	// the walker touches queueWait.
+	profiling bool
+	profile   *scanProfiler
+	queueWait time.Duration
+
+	// Files and directories to scan instead of the whole library
+	paths []string
 }
 
 func NewLibraryScanner(db *gorm.DB, jobID uint32, eventBus events.EventBus, pluginModule *pluginmodule.PluginModule, enrichmentHook ScannerPluginHook) *LibraryScanner {
@@ -255,7 +273,17 @@ func (ls *LibraryScanner) Start(libraryID uint32) error {
 		return fmt.Errorf("failed to get library: %w", err)
 	}
 
-	logger.Info("Starting scan", "library_id", libraryID, "path", library.Path, "job_id", ls.jobID)
+	roots := ls.paths
+	if len(roots) == 0 {
+		roots = []string{library.Path}
+	}
+
+	logger.Info("Starting scan", "library_id", libraryID, "paths", roots, "job_id", ls.jobID)
+
+	ls.report = newScanReportCollector(libraryID)
+	if ls.profiling {
+		ls.profile = newScanProfiler(ls.workers)
+	}
 
 	// Start worker goroutines
 	for i := 0; i < ls.workers; i++ {
@@ -271,10 +299,14 @@ func (ls *LibraryScanner) Start(libraryID uint32) error {
 	ls.wg.Add(1)
 	go func() {
 		defer ls.wg.Done()
-		if err := ls.scanDirectory(library.Path, uint(libraryID)); err != nil {
+		if err := ls.scanRoots(roots, uint(libraryID)); err != nil {
 			logger.Error("Scan failed", "error", err, "job_id", ls.jobID)
 			ls.updateScanJobStatus("failed", fmt.Sprintf("Scan failed: %v", err))
+			ls.saveReport("failed")
 		} else {
+			// Let the workers drain the queue so the report covers every file
+			ls.waitForQueuedFiles()
+			ls.saveReport("completed")
 			ls.finalizeScan()
 		}
 
@@ -313,6 +345,19 @@ func (ls *LibraryScanner) Pause() {
 	}
 }
 
+// scanRoots walks each of the files and directories to scan in turn
+func (ls *LibraryScanner) scanRoots(roots []string, libraryID uint) error {
+	for _, root := range roots {
+		start, waited := time.Now(), ls.queueWait
+		err := ls.scanDirectory(root, libraryID)
+		ls.profile.record(ProfileKindStage, profileStageWalk, time.Since(start)-(ls.queueWait-waited))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (ls *LibraryScanner) scanDirectory(dirPath string, libraryID uint) error {
 	logger.Info("Scanning directory", "path", dirPath, "job_id", ls.jobID)
 
@@ -333,6 +378,7 @@ func (ls *LibraryScanner) scanDirectory(dirPath string, libraryID uint) error {
 		if err != nil {
 			logger.Warn("Error accessing path", "path", path, "error", err)
 			ls.errorsCount.Add(1)
+			ls.report.add(ScanReportEntry{Path: path, Outcome: ScanOutcomeFailed, Error: err.Error()})
 			return nil // Continue walking
 		}
 
@@ -358,10 +404,10 @@ func (ls *LibraryScanner) scanDirectory(dirPath string, libraryID uint) error {
 			"specials-banner.", "season-specials-poster.", "season-specials-banner.",
 		}
 
-		shouldSkip := false
+		skipReason := ""
 		for _, pattern := range artworkPatterns {
 			if strings.Contains(fileName, pattern) {
-				shouldSkip = true
+				skipReason = "artwork file"
 				logger.Debug("Skipping artwork file", "path", path, "pattern", pattern)
 				break
 			}
@@ -372,7 +418,7 @@ func (ls *LibraryScanner) scanDirectory(dirPath string, libraryID uint) error {
 		ext := strings.ToLower(filepath.Ext(path))
 		for _, metaExt := range metadataExtensions {
 			if ext == metaExt {
-				shouldSkip = true
+				skipReason = "metadata or subtitle file"
 				logger.Debug("Skipping metadata file", "path", path, "extension", ext)
 				break
 			}
@@ -382,14 +428,15 @@ func (ls *LibraryScanner) scanDirectory(dirPath string, libraryID uint) error {
 		systemPatterns := []string{".ds_store", "thumbs.db", ".tmp", ".temp", ".bak", ".backup", ".old", ".orig"}
 		for _, sysPattern := range systemPatterns {
 			if strings.Contains(fileName, sysPattern) {
-				shouldSkip = true
+				skipReason = "system or temporary file"
 				logger.Debug("Skipping system file", "path", path, "pattern", sysPattern)
 				break
 			}
 		}
 
-		if shouldSkip {
+		if skipReason != "" {
 			ls.filesSkipped.Add(1)
+			ls.report.skipped(path, skipReason)
 			return nil
 		}
 
@@ -403,18 +450,25 @@ func (ls *LibraryScanner) scanDirectory(dirPath string, libraryID uint) error {
 			}
 
 			// Queue file for processing
+			queuedAt := time.Now()
 			select {
 			case ls.fileQueue <- path:
 				// File queued successfully
+				ls.filesQueued.Add(1)
 			case <-ls.ctx.Done():
 				return fmt.Errorf("scan cancelled while queueing file")
 			case <-time.After(5 * time.Second):
 				logger.Warn("File queue full, skipping file", "path", path)
 				ls.filesSkipped.Add(1)
+				ls.report.skipped(path, "file queue full")
 			}
+			waited := time.Since(queuedAt)
+			ls.queueWait += waited
+			ls.profile.record(ProfileKindStage, profileStageQueueWait, waited)
 		} else {
 			// Not a media file, skip
 			ls.filesSkipped.Add(1)
+			ls.report.skipped(path, "not a supported media file")
 		}
 
 		return nil
@@ -437,6 +491,7 @@ func (ls *LibraryScanner) fileWorker(libraryID uint) {
 			} else {
 				ls.filesProcessed.Add(1)
 			}
+			ls.filesDone.Add(1)
 
 		case <-ls.ctx.Done():
 			return // Context cancelled
@@ -444,8 +499,25 @@ func (ls *LibraryScanner) fileWorker(libraryID uint) {
 	}
 }
 
+// processFile processes a single file and records its outcome in the scan report
 func (ls *LibraryScanner) processFile(filePath string, libraryID uint) error {
+	start := time.Now()
+	entry := ScanReportEntry{Path: filePath}
+
+	err := ls.scanFile(filePath, libraryID, &entry)
+	if err != nil {
+		entry.Outcome = ScanOutcomeFailed
+		entry.Error = err.Error()
+	}
+	entry.DurationMs = time.Since(start).Milliseconds()
+	ls.report.add(entry)
+
+	return err
+}
+
+func (ls *LibraryScanner) scanFile(filePath string, libraryID uint, entry *ScanReportEntry) error {
 	// Get file info
+	lookupStart := time.Now()
 	fileInfo, err := os.Stat(filePath)
 	if err != nil {
 		return fmt.Errorf("failed to stat file: %w", err)
@@ -454,10 +526,31 @@ func (ls *LibraryScanner) processFile(filePath string, libraryID uint) error {
 	// Check if file already exists in database
 	var existingFile database.MediaFile
 	err = ls.db.Where("path = ? AND library_id = ?", filePath, libraryID).First(&existingFile).Error
+	ls.profile.since(ProfileKindStage, profileStageLookup, lookupStart)
 	if err == nil {
 		// File already exists, update last_seen
-		ls.db.Model(&existingFile).Update("last_seen", time.Now())
+		now := time.Now()
+		updates := map[string]interface{}{"last_seen": now}
+		changed := existingFile.SizeBytes != fileInfo.Size()
+		if changed {
+			// Replaced or retagged since the last scan
+			updates["size_bytes"] = fileInfo.Size()
+			updates["updated_at"] = now
+		}
+		ls.db.Model(&existingFile).Updates(updates)
 		ls.bytesProcessed.Add(fileInfo.Size())
+		entry.Outcome = ScanOutcomeUnchanged
+		entry.MediaType = string(existingFile.MediaType)
+		entry.MediaFileID = existingFile.ID
+
+		if changed {
+			entry.Outcome = ScanOutcomeUpdated
+			if changeHook, ok := ls.enrichmentHook.(MediaFileChangeHook); ok {
+				if err := changeHook.OnMediaFileUpdated(&existingFile, nil); err != nil {
+					logger.Warn("Media file update hook failed", "path", filePath, "error", err)
+				}
+			}
+		}
 		return nil
 	}
 
@@ -485,24 +578,45 @@ func (ls *LibraryScanner) processFile(filePath string, libraryID uint) error {
 
 	// IMPORTANT: Set media_type based on library type and file extension
 	mediaFile.MediaType = ls.determineMediaType(library.Type, ext)
+	mediaFile.ClassifiedBy = database.ClassifiedByLibrary
+
+	// Mixed libraries hold movies and shows side by side, so each video is
+	// classified from its naming instead of the library type
+	if library.Type == "mixed" && mediaFile.MediaType == database.MediaTypeMovie {
+		mediaFile.MediaType, mediaFile.ClassifiedBy = classifyMixedVideo(pluginmodule.LibraryMatchPath(ls.db, uint32(libraryID), filePath))
+		logger.Debug("Classified file in mixed library", "path", filePath, "media_type", mediaFile.MediaType, "classified_by", mediaFile.ClassifiedBy)
+	}
 
 	// Extract technical metadata using FFprobe BEFORE saving to database
+	probeStart := time.Now()
 	if err := ls.extractTechnicalMetadata(mediaFile); err != nil {
 		logger.Warn("Failed to extract technical metadata", "path", filePath, "error", err)
 		// Continue even if technical metadata extraction fails
 	}
+	ls.profile.since(ProfileKindStage, profileStageProbe, probeStart)
 
 	// Save to database FIRST before calling plugins
+	saveStart := time.Now()
 	if err := ls.db.Create(mediaFile).Error; err != nil {
 		return fmt.Errorf("failed to save media file: %w", err)
 	}
+	ls.profile.since(ProfileKindStage, profileStageSave, saveStart)
+
+	entry.Outcome = ScanOutcomeAdded
+	entry.MediaFileID = mediaFile.ID
 
 	// Extract metadata using plugins if available (AFTER saving to database)
 	if ls.pluginModule != nil {
-		if err := ls.extractMetadata(mediaFile); err != nil {
+		sources, err := ls.extractMetadata(mediaFile)
+		if err != nil {
 			logger.Warn("Failed to extract metadata", "path", filePath, "error", err)
+			entry.Reason = fmt.Sprintf("metadata extraction failed: %v", err)
 			// Continue even if metadata extraction fails
 		}
+		if len(sources) > 0 {
+			entry.Outcome = ScanOutcomeMatched
+			entry.Sources = sources
+		}
 
 		// IMPORTANT: Reload media file from database after metadata extraction
 		// to ensure we have the updated media_id and media_type that was set by
@@ -539,7 +653,7 @@ func (ls *LibraryScanner) processFile(filePath string, libraryID uint) error {
 		metadata := ls.getMetadataForEnrichment(mediaFile)
 
 		logger.Debug("Calling enrichment hook with metadata", "path", filePath, "metadata_size", len(metadata), "media_file_id", mediaFile.ID)
-		if err := ls.enrichmentHook.OnMediaFileScanned(mediaFile, metadata); err != nil {
+		if err := ls.notifyFileScanned(mediaFile, metadata); err != nil {
 			logger.Warn("Enrichment hook failed", "path", filePath, "error", err)
 			// Continue even if enrichment hook fails
 		} else {
@@ -550,19 +664,22 @@ func (ls *LibraryScanner) processFile(filePath string, libraryID uint) error {
 	}
 
 	ls.bytesProcessed.Add(fileInfo.Size())
+	entry.MediaType = string(mediaFile.MediaType)
 
 	logger.Debug("Processed file", "path", filePath, "size", fileInfo.Size())
 	return nil
 }
 
-func (ls *LibraryScanner) extractMetadata(mediaFile *database.MediaFile) error {
+// extractMetadata runs every matching file handler and returns the names of
+// those that succeeded
+func (ls *LibraryScanner) extractMetadata(mediaFile *database.MediaFile) ([]string, error) {
 	// Get enabled file handlers from plugin system
 	handlers := ls.pluginModule.GetEnabledFileHandlers()
 
 	// Get file info for plugin matching
 	fileInfo, err := os.Stat(mediaFile.Path)
 	if err != nil {
-		return fmt.Errorf("failed to get file info: %w", err)
+		return nil, fmt.Errorf("failed to get file info: %w", err)
 	}
 
 	var processedBy []string
@@ -579,7 +696,10 @@ func (ls *LibraryScanner) extractMetadata(mediaFile *database.MediaFile) error {
 				PluginID:  handler.GetName(),
 			}
 
-			if err := handler.HandleFile(mediaFile.Path, ctx); err != nil {
+			handlerStart := time.Now()
+			err := handler.HandleFile(mediaFile.Path, ctx)
+			ls.profile.since(ProfileKindHandler, handler.GetName(), handlerStart)
+			if err != nil {
 				logger.Warn("Handler failed", "handler", handler.GetName(), "file", mediaFile.Path, "error", err)
 				lastError = err
 				continue // Try next handler
@@ -592,17 +712,17 @@ func (ls *LibraryScanner) extractMetadata(mediaFile *database.MediaFile) error {
 
 	if len(processedBy) > 0 {
 		logger.Debug("File processed by multiple handlers", "file", mediaFile.Path, "handlers", processedBy)
-		return nil // Success if at least one handler succeeded
+		return processedBy, nil // Success if at least one handler succeeded
 	}
 
 	// If no handlers processed the file and we had errors, return the last error
 	if lastError != nil {
-		return lastError
+		return nil, lastError
 	}
 
 	// No handlers matched this file - not an error
 	logger.Debug("No handlers matched file", "file", mediaFile.Path)
-	return nil
+	return nil, nil
 }
 
 func (ls *LibraryScanner) progressUpdater() {
@@ -718,6 +838,80 @@ func (ls *LibraryScanner) finalizeScan() {
 		"errors", errorsCount)
 }
 
+// waitForQueuedFiles blocks until the workers have handled every queued file
+// or the scan is cancelled
+func (ls *LibraryScanner) waitForQueuedFiles() {
+	ticker := time.NewTicker(100 * time.Millisecond)
+	defer ticker.Stop()
+
+	for ls.filesDone.Load() < ls.filesQueued.Load() {
+		select {
+		case <-ticker.C:
+		case <-ls.ctx.Done():
+			return
+		}
+	}
+}
+
+// SetPaths limits the scan to some files and directories of the library
+func (ls *LibraryScanner) SetPaths(paths []string) {
+	ls.paths = paths
+}
+
+// SetReportDir enables per-file scan reports, written to dir when the scan ends
+func (ls *LibraryScanner) SetReportDir(dir string) {
+	ls.reportDir = dir
+}
+
+// saveReport writes the scan report, logging rather than failing the scan on error
+func (ls *LibraryScanner) saveReport(status string) {
+	if ls.reportDir == "" || ls.report == nil {
+		return
+	}
+
+	report := ls.report.build(ls.jobID, status)
+	report.Profile = ls.profile.build(ls.db, ls.jobID)
+	if report.Profile != nil {
+		logger.Info("Scan profile for job %d after %dms, slowest: %s", ls.jobID, report.Profile.ElapsedMs, strings.Join(report.Profile.slowest(3), "; "))
+	}
+	if err := writeScanReport(ls.reportDir, report); err != nil {
+		logger.Error("Failed to write scan report", "job_id", ls.jobID, "error", err)
+	}
+}
+
+// SetProfiling turns on timing of the scan's stages, handlers and hooks,
+// added to the scan report. It takes effect when the scan starts.
+func (ls *LibraryScanner) SetProfiling(enabled bool) {
+	ls.profiling = enabled
+}
+
+// Profile returns the timings of the scan so far, or nil when it isn't
+// being profiled
+func (ls *LibraryScanner) Profile() *ScanProfile {
+	return ls.profile.build(ls.db, ls.jobID)
+}
+
+// notifyFileScanned calls the scan hooks for a new file. When profiling,
+// they are called one at a time, as scanHooks would, so each can be timed.
+func (ls *LibraryScanner) notifyFileScanned(mediaFile *database.MediaFile, metadata interface{}) error {
+	if ls.profile == nil {
+		return ls.enrichmentHook.OnMediaFileScanned(mediaFile, metadata)
+	}
+
+	hooks, ok := ls.enrichmentHook.(scanHooks)
+	if !ok {
+		hooks = scanHooks{ls.enrichmentHook}
+	}
+
+	var errs []error
+	for _, hook := range hooks {
+		start := time.Now()
+		errs = append(errs, hook.OnMediaFileScanned(mediaFile, metadata))
+		ls.profile.since(ProfileKindHook, hook.Name(), start)
+	}
+	return errors.Join(errs...)
+}
+
 func (ls *LibraryScanner) updateScanJobStatus(status, message string) error {
 	updates := map[string]interface{}{
 		"status":         status,
@@ -745,6 +939,7 @@ func (ls *LibraryScanner) isMediaFile(path string) bool {
 		".mkv": true, ".avi": true, ".mov": true, ".wmv": true, ".flv": true,
 		".webm": true, ".m4v": true, ".3gp": true, ".ts": true, ".mpg": true,
 		".mpeg": true, ".rm": true, ".rmvb": true, ".asf": true, ".divx": true,
+		".mts": true, ".m2ts": true, // AVCHD camcorder footage
 
 		// IMPORTANT: Images are NOT media files - they should be treated as assets
 		// Removing image extensions from media file detection to prevent
@@ -785,6 +980,8 @@ func (ls *LibraryScanner) getContainerFromExtension(ext string) string {
 		".ts":   "ts",
 		".mpg":  "mpg",
 		".mpeg": "mpeg",
+		".mts":  "mpegts",
+		".m2ts": "mpegts",
 
 		// Image formats
 		".jpg":  "jpeg",
@@ -933,6 +1130,7 @@ func (ls *LibraryScanner) getMetadataForEnrichment(mediaFile *database.MediaFile
 	// Add basic file information
 	metadata["file_path"] = mediaFile.Path
 	metadata["media_type"] = mediaFile.MediaType
+	metadata["classified_by"] = mediaFile.ClassifiedBy
 	metadata["container"] = mediaFile.Container
 	if mediaFile.Duration > 0 {
 		metadata["file_duration"] = mediaFile.Duration
@@ -963,6 +1161,7 @@ func (ls *LibraryScanner) extractDirectMetadata(filePath string) map[string]inte
 		".mp4": true, ".mkv": true, ".avi": true, ".mov": true, ".wmv": true,
 		".flv": true, ".webm": true, ".m4v": true, ".3gp": true, ".ts": true,
 		".mpg": true, ".mpeg": true, ".rm": true, ".rmvb": true, ".asf": true, ".divx": true,
+		".mts": true, ".m2ts": true,
 	}
 
 	isAudioFile := audioExts[ext]
@@ -1147,6 +1346,7 @@ func (ls *LibraryScanner) determineMediaType(libraryType string, ext string) dat
 		".mp4": true, ".mkv": true, ".avi": true, ".mov": true, ".wmv": true,
 		".flv": true, ".webm": true, ".m4v": true, ".3gp": true, ".ts": true,
 		".mpg": true, ".mpeg": true, ".rm": true, ".rmvb": true, ".asf": true, ".divx": true,
+		".mts": true, ".m2ts": true,
 	}
 
 	// Image file extensions
@@ -1206,6 +1406,28 @@ func (ls *LibraryScanner) determineMediaType(libraryType string, ext string) dat
 		logger.Warn("Unsupported file type in TV library", "ext", ext, "library_type", libraryType)
 		return database.MediaTypeEpisode // Default to episode for TV libraries
 
+	case "home":
+		// Home video libraries hold personal footage, organized by recording date
+		if audioExts[ext] {
+			logger.Info("Audio file found in home video library - treating as track", "ext", ext, "library_type", libraryType)
+			return database.MediaTypeTrack
+		}
+		if !videoExts[ext] {
+			logger.Warn("Unsupported file type in home video library", "ext", ext, "library_type", libraryType)
+		}
+		return database.MediaTypeHomeVideo
+
+	case "mixed":
+		// Mixed libraries: videos are classified per item by the caller
+		if audioExts[ext] {
+			logger.Info("Audio file found in mixed library - treating as track", "ext", ext, "library_type", libraryType)
+			return database.MediaTypeTrack
+		}
+		if !videoExts[ext] {
+			logger.Warn("Unsupported file type in mixed library", "ext", ext, "library_type", libraryType)
+		}
+		return database.MediaTypeMovie
+
 	default:
 		// Unknown library type - try to guess based on file extension
 		logger.Warn("Unknown library type, guessing media_type from extension", "library_type", libraryType, "ext", ext)
@@ -1255,6 +1477,11 @@ func (ls *LibraryScanner) extractTechnicalMetadata(mediaFile *database.MediaFile
 			Profile       string            `json:"profile,omitempty"`
 			Level         int               `json:"level,omitempty"`
 			ChannelLayout string            `json:"channel_layout,omitempty"`
+			StartTime     string            `json:"start_time,omitempty"`
+			InitialPad    int               `json:"initial_padding,omitempty"`
+			SampleFmt     string            `json:"sample_fmt,omitempty"`
+			BitsPerSample int               `json:"bits_per_sample,omitempty"`
+			BitsPerRaw    string            `json:"bits_per_raw_sample,omitempty"`
 			Tags          map[string]string `json:"tags"`
 		} `json:"streams"`
 	}
@@ -1321,8 +1548,18 @@ func (ls *LibraryScanner) extractTechnicalMetadata(mediaFile *database.MediaFile
 			if stream.Profile != "" {
 				mediaFile.AudioProfile = stream.Profile
 			}
+			mediaFile.AudioLossless = utils.IsLosslessAudioCodec(stream.CodecName)
+			mediaFile.AudioBitDepth = utils.AudioBitDepth(stream.CodecName, stream.BitsPerRaw, stream.BitsPerSample, stream.SampleFmt)
+			applyGaplessInfo(mediaFile, gaplessProbe{
+				CodecName:      stream.CodecName,
+				SampleRate:     mediaFile.SampleRate,
+				StartTime:      stream.StartTime,
+				InitialPadding: stream.InitialPad,
+				StreamTags:     stream.Tags,
+				FormatTags:     probeOutput.Format.Tags,
+			})
 			audioStreamFound = true
-			extractedFields = append(extractedFields, "audio_codec", "sample_rate", "channels", "audio_channels")
+			extractedFields = append(extractedFields, "audio_codec", "sample_rate", "channels", "audio_channels", "audio_lossless", "audio_bit_depth")
 		}
 	}
 
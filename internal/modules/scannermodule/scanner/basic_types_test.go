@@ -232,6 +232,16 @@ func TestDetermineMediaType(t *testing.T) {
 		{"TV Library - Unknown Audio-like", "tv", ".flac", database.MediaTypeTrack},
 		{"TV Library - Gibberish", "tv", ".xyz", database.MediaTypeEpisode}, // Fallback for TV
 
+		// Mixed Library (videos are classified per item afterwards)
+		{"Mixed Library - MKV", "mixed", ".mkv", database.MediaTypeMovie},
+		{"Mixed Library - MP3", "mixed", ".mp3", database.MediaTypeTrack},
+		{"Mixed Library - JPG", "mixed", ".jpg", database.MediaTypeImage},
+
+		// Home Video Library
+		{"Home Video Library - MOV", "home", ".mov", database.MediaTypeHomeVideo},
+		{"Home Video Library - MTS", "home", ".mts", database.MediaTypeHomeVideo},
+		{"Home Video Library - JPG", "home", ".jpg", database.MediaTypeImage},
+
 		// Unknown Library Type
 		{"Unknown Library - MP3", "other", ".mp3", database.MediaTypeTrack},
 		{"Unknown Library - MKV", "other", ".mkv", database.MediaTypeMovie}, // Default video to movie
@@ -253,6 +263,33 @@ func TestDetermineMediaType(t *testing.T) {
 	}
 }
 
+func TestClassifyMixedVideo(t *testing.T) {
+	testCases := []struct {
+		path         string
+		mediaType    database.MediaType
+		classifiedBy string
+	}{
+		{"/downloads/Show.Name.S01E02.1080p.WEB.mkv", database.MediaTypeEpisode, database.ClassifiedByNaming},
+		{"/downloads/Show Name - s2e10 - Title.mp4", database.MediaTypeEpisode, database.ClassifiedByNaming},
+		{"/downloads/Show Name 3x07.avi", database.MediaTypeEpisode, database.ClassifiedByNaming},
+		{"/downloads/The Daily Show - 2013-02-08 - Guest.mkv", database.MediaTypeEpisode, database.ClassifiedByNaming},
+		{"/downloads/Show Name/Season 1/01 - Pilot.mkv", database.MediaTypeEpisode, database.ClassifiedByNaming},
+		{"/downloads/[SubsPlease] Show Name - 12 (1080p) [A1B2C3D4].mkv", database.MediaTypeEpisode, database.ClassifiedByNaming},
+		{"/downloads/Movie Title (2010).mkv", database.MediaTypeMovie, database.ClassifiedByNaming},
+		{"/downloads/Movie.Title.1999.1080p.BluRay.x264-GROUP.mkv", database.MediaTypeMovie, database.ClassifiedByNaming},
+		{"/downloads/1917 (2019).mkv", database.MediaTypeMovie, database.ClassifiedByNaming},
+		{"/downloads/Some Video.mkv", database.MediaTypeMovie, database.ClassifiedByGuess},
+	}
+
+	for _, tc := range testCases {
+		t.Run(filepath.Base(tc.path), func(t *testing.T) {
+			mediaType, classifiedBy := classifyMixedVideo(tc.path)
+			assert.Equal(t, tc.mediaType, mediaType)
+			assert.Equal(t, tc.classifiedBy, classifiedBy)
+		})
+	}
+}
+
 // Helper to create a temporary file for testing filepath.WalkDir related logic
 func createTempFile(t *testing.T, dir, name string, content []byte) string {
 	t.Helper()
@@ -365,6 +402,114 @@ func TestExtractTechnicalMetadata_Audio(t *testing.T) {
 	assert.Equal(t, 0, mediaFile.VideoHeight)
 }
 
+func TestExtractTechnicalMetadata_Gapless(t *testing.T) {
+	ls := &LibraryScanner{}
+	mediaFile := &database.MediaFile{Path: "/test/track.m4a"}
+
+	ffprobeOutput := `{
+		"format": {
+			"duration": "240.000",
+			"bit_rate": "256000",
+			"tags": {
+				"iTunSMPB": " 00000000 00000840 000001CA 0000000000A1B5F6 00000000 00000000",
+				"replaygain_track_gain": "-7.89 dB",
+				"replaygain_track_peak": "0.988",
+				"REPLAYGAIN_ALBUM_GAIN": "-8.20 dB"
+			}
+		},
+		"streams": [
+			{
+				"codec_type": "audio",
+				"codec_name": "aac",
+				"sample_rate": "44100",
+				"channels": 2
+			}
+		]
+	}`
+
+	mockExecCommandOutput(t, "ffprobe", nil, []byte(ffprobeOutput), nil)
+
+	require.NoError(t, ls.extractTechnicalMetadata(mediaFile))
+
+	assert.Equal(t, 2112, mediaFile.EncoderDelay)
+	assert.Equal(t, 458, mediaFile.EncoderPadding)
+	require.NotNil(t, mediaFile.ReplayGain)
+	assert.InDelta(t, -7.89, *mediaFile.ReplayGain, 0.001)
+	require.NotNil(t, mediaFile.ReplayGainPeak)
+	assert.InDelta(t, 0.988, *mediaFile.ReplayGainPeak, 0.001)
+	require.NotNil(t, mediaFile.AlbumGain)
+	assert.InDelta(t, -8.2, *mediaFile.AlbumGain, 0.001)
+}
+
+func TestExtractTechnicalMetadata_GaplessLAME(t *testing.T) {
+	ls := &LibraryScanner{}
+	mediaFile := &database.MediaFile{Path: "/test/track.mp3"}
+
+	ffprobeOutput := `{
+		"format": {"duration": "185.200"},
+		"streams": [
+			{
+				"codec_type": "audio",
+				"codec_name": "mp3",
+				"sample_rate": "44100",
+				"start_time": "0.025057",
+				"tags": {"R128_TRACK_GAIN": "-1280"}
+			}
+		]
+	}`
+
+	mockExecCommandOutput(t, "ffprobe", nil, []byte(ffprobeOutput), nil)
+
+	require.NoError(t, ls.extractTechnicalMetadata(mediaFile))
+
+	assert.Equal(t, 1105, mediaFile.EncoderDelay)
+	assert.Equal(t, 0, mediaFile.EncoderPadding)
+	require.NotNil(t, mediaFile.ReplayGain)
+	assert.InDelta(t, 0.0, *mediaFile.ReplayGain, 0.001)
+	assert.Nil(t, mediaFile.AlbumGain)
+}
+
+func TestExtractTechnicalMetadata_AudioQuality(t *testing.T) {
+	ls := &LibraryScanner{}
+	mediaFile := &database.MediaFile{Path: "/test/track.flac"}
+
+	ffprobeOutput := `{
+		"format": {"duration": "312.400", "bit_rate": "2875000"},
+		"streams": [
+			{
+				"codec_type": "audio",
+				"codec_name": "flac",
+				"sample_rate": "96000",
+				"channels": 2,
+				"sample_fmt": "s32",
+				"bits_per_raw_sample": "24"
+			}
+		]
+	}`
+
+	mockExecCommandOutput(t, "ffprobe", nil, []byte(ffprobeOutput), nil)
+
+	require.NoError(t, ls.extractTechnicalMetadata(mediaFile))
+
+	assert.True(t, mediaFile.AudioLossless)
+	assert.Equal(t, 24, mediaFile.AudioBitDepth)
+	assert.Equal(t, 96000, mediaFile.SampleRate)
+
+	// Lossy codecs have no meaningful bit depth
+	mediaFile = &database.MediaFile{Path: "/test/track.mp3"}
+	ffprobeOutput = `{
+		"format": {"duration": "185.200"},
+		"streams": [{"codec_type": "audio", "codec_name": "mp3", "sample_rate": "44100", "sample_fmt": "fltp"}]
+	}`
+
+	mockExecCommandOutput(t, "ffprobe", nil, []byte(ffprobeOutput), nil)
+
+	require.NoError(t, ls.extractTechnicalMetadata(mediaFile))
+
+	assert.False(t, mediaFile.AudioLossless)
+	assert.Equal(t, 0, mediaFile.AudioBitDepth)
+}
+
 func TestExtractTechnicalMetadata_MissingData(t *testing.T) {
 	ls := &LibraryScanner{}
 	filePath := "/test/missing_data.mkv"
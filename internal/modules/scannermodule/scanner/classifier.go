@@ -0,0 +1,22 @@
+package scanner
+
+import (
+	"github.com/mantonx/viewra/internal/database"
+	"github.com/mantonx/viewra/sdk/namingparser"
+)
+
+// classifyMixedVideo decides whether a video file in a mixed library is a
+// movie or an episode from its naming, read with the SDK's naming parser
+// that enrichment plugins use too. Files named with episode numbering, an
+// air date or kept in a season folder are episodes and titles followed by a
+// year are movies. Files matching neither naming are assumed to be movies
+// until TMDb says otherwise.
+func classifyMixedVideo(path string) (database.MediaType, string) {
+	switch namingparser.Parse(path).Kind {
+	case namingparser.KindEpisode:
+		return database.MediaTypeEpisode, database.ClassifiedByNaming
+	case namingparser.KindMovie:
+		return database.MediaTypeMovie, database.ClassifiedByNaming
+	}
+	return database.MediaTypeMovie, database.ClassifiedByGuess
+}
@@ -67,6 +67,9 @@ type FileProcessor struct {
 	db           *gorm.DB
 	pluginModule *pluginmodule.PluginModule
 	eventBus     events.EventBus
+
+	mu         sync.RWMutex
+	changeHook MediaFileChangeHook
 }
 
 // NewFileMonitor creates a new file monitor
@@ -100,6 +103,13 @@ func NewFileMonitor(db *gorm.DB, eventBus events.EventBus, pluginModule *pluginm
 	return fm, nil
 }
 
+// SetChangeHook sets the hook told about modified and removed files
+func (fm *FileMonitor) SetChangeHook(hook MediaFileChangeHook) {
+	fm.fileProcessor.mu.Lock()
+	defer fm.fileProcessor.mu.Unlock()
+	fm.fileProcessor.changeHook = hook
+}
+
 // Start begins the file monitoring service
 func (fm *FileMonitor) Start() error {
 	fm.mu.Lock()
@@ -489,16 +499,50 @@ func (fp *FileProcessor) ProcessNewFile(filePath string, libraryID uint) error {
 func (fp *FileProcessor) ProcessModifiedFile(filePath string, libraryID uint) error {
 	logger.Debug("Processing modified file", "path", filePath, "library_id", libraryID)
 
-	// Remove existing entry and re-process
-	fp.db.Where("path = ? AND library_id = ?", filePath, libraryID).Delete(&database.MediaFile{})
+	var existingFile database.MediaFile
+	if err := fp.db.Where("path = ? AND library_id = ?", filePath, libraryID).First(&existingFile).Error; err != nil {
+		return fp.scanAndSaveFile(filePath, libraryID)
+	}
 
-	return fp.scanAndSaveFile(filePath, libraryID)
+	fileInfo, err := os.Stat(filePath)
+	if err != nil {
+		return fmt.Errorf("failed to stat file: %w", err)
+	}
+
+	// Update the existing entry in place so the file keeps its ID, and with
+	// it the enrichments and playback history attached to it
+	now := time.Now()
+	if err := fp.db.Model(&existingFile).Updates(map[string]interface{}{
+		"size_bytes": fileInfo.Size(),
+		"last_seen":  now,
+		"updated_at": now,
+	}).Error; err != nil {
+		return fmt.Errorf("failed to update media file: %w", err)
+	}
+
+	if hook := fp.getChangeHook(); hook != nil {
+		if err := hook.OnMediaFileUpdated(&existingFile, nil); err != nil {
+			logger.Warn("Media file update hook failed", "path", filePath, "error", err)
+		}
+	}
+
+	return nil
 }
 
 // ProcessRemovedFile handles file deletion
 func (fp *FileProcessor) ProcessRemovedFile(filePath string, libraryID uint) error {
 	logger.Debug("Processing removed file", "path", filePath, "library_id", libraryID)
 
+	// Let hooks clean up while the file's row still exists
+	if hook := fp.getChangeHook(); hook != nil {
+		var existingFile database.MediaFile
+		if err := fp.db.Where("path = ? AND library_id = ?", filePath, libraryID).First(&existingFile).Error; err == nil {
+			if err := hook.OnMediaFileRemoved(&existingFile); err != nil {
+				logger.Warn("Media file removal hook failed", "path", filePath, "error", err)
+			}
+		}
+	}
+
 	// Remove from database
 	result := fp.db.Where("path = ? AND library_id = ?", filePath, libraryID).Delete(&database.MediaFile{})
 	if result.Error != nil {
@@ -526,6 +570,12 @@ func (fp *FileProcessor) ProcessRemovedFile(filePath string, libraryID uint) err
 	return nil
 }
 
+func (fp *FileProcessor) getChangeHook() MediaFileChangeHook {
+	fp.mu.RLock()
+	defer fp.mu.RUnlock()
+	return fp.changeHook
+}
+
 // scanAndSaveFile scans and saves a single file (similar to scanner logic)
 func (fp *FileProcessor) scanAndSaveFile(filePath string, libraryID uint) error {
 	// Get file info
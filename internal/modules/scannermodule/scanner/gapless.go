@@ -0,0 +1,110 @@
+package scanner
+
+import (
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/mantonx/viewra/internal/database"
+)
+
+// r128ToReplayGain converts Opus R128 gains, referenced to -23 LUFS, to the
+// -18 LUFS ReplayGain reference
+const r128ToReplayGain = 5.0
+
+// gaplessProbe is the part of an audio stream's ffprobe output that describes
+// encoder delay and loudness
+type gaplessProbe struct {
+	CodecName      string
+	SampleRate     int
+	StartTime      string
+	InitialPadding int
+	StreamTags     map[string]string
+	FormatTags     map[string]string
+}
+
+// applyGaplessInfo records encoder delay/padding and ReplayGain values on the
+// media file so players can join tracks without gaps and match levels when
+// crossfading. The iTunSMPB tag is the most precise source; otherwise the
+// container's initial padding or, for MP3, the LAME header delay that FFmpeg
+// reports as the stream start time are used.
+func applyGaplessInfo(mediaFile *database.MediaFile, probe gaplessProbe) {
+	tag := func(key string) string {
+		if value := lookupTag(probe.StreamTags, key); value != "" {
+			return value
+		}
+		return lookupTag(probe.FormatTags, key)
+	}
+
+	if delay, padding, ok := parseITunSMPB(tag("iTunSMPB")); ok {
+		mediaFile.EncoderDelay, mediaFile.EncoderPadding = delay, padding
+	} else if probe.InitialPadding > 0 {
+		mediaFile.EncoderDelay = probe.InitialPadding
+	} else if probe.CodecName == "mp3" && probe.SampleRate > 0 {
+		if start, err := strconv.ParseFloat(probe.StartTime, 64); err == nil && start > 0 {
+			mediaFile.EncoderDelay = int(math.Round(start * float64(probe.SampleRate)))
+		}
+	}
+
+	if gain, ok := parseGain(tag("REPLAYGAIN_TRACK_GAIN")); ok {
+		mediaFile.ReplayGain = &gain
+	} else if gain, ok := parseR128Gain(tag("R128_TRACK_GAIN")); ok {
+		mediaFile.ReplayGain = &gain
+	}
+	if peak, err := strconv.ParseFloat(strings.TrimSpace(tag("REPLAYGAIN_TRACK_PEAK")), 64); err == nil {
+		mediaFile.ReplayGainPeak = &peak
+	}
+	if gain, ok := parseGain(tag("REPLAYGAIN_ALBUM_GAIN")); ok {
+		mediaFile.AlbumGain = &gain
+	} else if gain, ok := parseR128Gain(tag("R128_ALBUM_GAIN")); ok {
+		mediaFile.AlbumGain = &gain
+	}
+}
+
+// lookupTag finds a tag regardless of the case the tagger used
+func lookupTag(tags map[string]string, key string) string {
+	for name, value := range tags {
+		if strings.EqualFold(name, key) {
+			return value
+		}
+	}
+	return ""
+}
+
+// parseITunSMPB reads the encoder delay and padding from an iTunes gapless
+// tag, e.g. " 00000000 00000840 000001CA 00000000003F31F6 ..."
+func parseITunSMPB(value string) (delay, padding int, ok bool) {
+	fields := strings.Fields(value)
+	if len(fields) < 3 {
+		return 0, 0, false
+	}
+	d, err := strconv.ParseInt(fields[1], 16, 32)
+	if err != nil {
+		return 0, 0, false
+	}
+	p, err := strconv.ParseInt(fields[2], 16, 32)
+	if err != nil {
+		return 0, 0, false
+	}
+	return int(d), int(p), true
+}
+
+// parseGain parses a ReplayGain value such as "-7.89 dB"
+func parseGain(value string) (float64, bool) {
+	value = strings.TrimSpace(value)
+	value = strings.TrimSpace(strings.TrimSuffix(strings.TrimSuffix(value, "dB"), "db"))
+	if value == "" {
+		return 0, false
+	}
+	gain, err := strconv.ParseFloat(value, 64)
+	return gain, err == nil
+}
+
+// parseR128Gain parses an Opus R128 gain, a Q7.8 fixed-point integer
+func parseR128Gain(value string) (float64, bool) {
+	q, err := strconv.Atoi(strings.TrimSpace(value))
+	if err != nil {
+		return 0, false
+	}
+	return float64(q)/256 + r128ToReplayGain, true
+}
@@ -3,10 +3,12 @@ package scanner
 import (
 	"context"
 	"fmt"
+	"path/filepath"
 	"runtime"
 	"sync"
 	"time"
 
+	"github.com/mantonx/viewra/internal/config"
 	"github.com/mantonx/viewra/internal/database"
 	"github.com/mantonx/viewra/internal/events"
 	"github.com/mantonx/viewra/internal/logger"
@@ -53,7 +55,8 @@ type Manager struct {
 	db             *gorm.DB
 	eventBus       events.EventBus
 	pluginModule   *pluginmodule.PluginModule
-	enrichmentHook ScannerPluginHook // Add enrichment hook
+	enrichmentHook ScannerPluginHook   // Add enrichment hook
+	scanHooks      []ScannerPluginHook // Called for each file after the enrichment hook
 	safeguards     *SafeguardSystem
 	mu             sync.RWMutex
 	scanners       map[uint32]*LibraryScanner // jobID -> scanner mapping
@@ -362,7 +365,19 @@ func (m *Manager) cleanupDuplicateJobs() error {
 
 // StartScan creates and starts a new scan job for the specified library.
 // It validates that no scan is already running for the library before starting.
+// The scan is profiled when scanner.profiling is set.
 func (m *Manager) StartScan(libraryID uint32) (*database.ScanJob, error) {
+	return m.startScan(libraryID, config.Get().Scanner.Profiling)
+}
+
+// StartProfiledScan starts a scan like StartScan, timing its stages, metadata
+// handlers and hooks whatever the configuration says. The timings are added
+// to the scan report.
+func (m *Manager) StartProfiledScan(libraryID uint32) (*database.ScanJob, error) {
+	return m.startScan(libraryID, true)
+}
+
+func (m *Manager) startScan(libraryID uint32, profile bool) (*database.ScanJob, error) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
@@ -405,7 +420,9 @@ func (m *Manager) StartScan(libraryID uint32) (*database.ScanJob, error) {
 	}
 
 	// Create and register scanner
-	scanner := NewLibraryScanner(m.db, scanJob.ID, m.eventBus, m.pluginModule, m.enrichmentHook)
+	scanner := NewLibraryScanner(m.db, scanJob.ID, m.eventBus, m.pluginModule, m.fileHook())
+	scanner.SetReportDir(m.ReportDir())
+	scanner.SetProfiling(profile)
 	m.scanners[scanJob.ID] = scanner
 
 	// Register enrichment hook with the new scanner if available
@@ -421,6 +438,61 @@ func (m *Manager) StartScan(libraryID uint32) (*database.ScanJob, error) {
 	return scanJob, nil
 }
 
+// ScanPaths starts a scan of some files and directories of a library, such
+// as newly imported downloads, leaving the rest of the library alone. Unlike
+// StartScan it doesn't clean up the library's data first.
+func (m *Manager) ScanPaths(libraryID uint32, paths []string) (*database.ScanJob, error) {
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("no paths to scan")
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var library database.MediaLibrary
+	if err := m.db.First(&library, libraryID).Error; err != nil {
+		return nil, fmt.Errorf("library not found: %w", err)
+	}
+	for _, path := range paths {
+		if !utils.WithinDir(path, library.Path) {
+			return nil, fmt.Errorf("%s is not in library %d", path, libraryID)
+		}
+	}
+
+	if err := utils.ValidateScanJob(m.db, libraryID); err != nil {
+		return nil, err
+	}
+	scanJob, err := utils.CreateScanJob(m.db, libraryID)
+	if err != nil {
+		return nil, err
+	}
+
+	if m.eventBus != nil {
+		startEvent := events.NewSystemEvent(
+			events.EventScanStarted,
+			"Media Scan Started",
+			fmt.Sprintf("Starting scan of %d paths in library #%d", len(paths), libraryID),
+		)
+		startEvent.Data = map[string]interface{}{
+			"libraryId": libraryID,
+			"scanJobId": scanJob.ID,
+			"path":      library.Path,
+			"paths":     paths,
+		}
+		m.eventBus.PublishAsync(startEvent)
+	}
+
+	scanner := NewLibraryScanner(m.db, scanJob.ID, m.eventBus, m.pluginModule, m.fileHook())
+	scanner.SetPaths(paths)
+	scanner.SetReportDir(m.ReportDir())
+	scanner.SetProfiling(config.Get().Scanner.Profiling)
+	m.scanners[scanJob.ID] = scanner
+
+	go m.runScanJob(scanner, scanJob.ID, libraryID, false)
+
+	return scanJob, nil
+}
+
 // runScanJob executes a scan job in a goroutine and handles cleanup.
 func (m *Manager) runScanJob(scanner *LibraryScanner, jobID, libraryID uint32, isResume bool) {
 	defer func() {
@@ -450,6 +522,9 @@ func (m *Manager) runScanJob(scanner *LibraryScanner, jobID, libraryID uint32, i
 				} else {
 					eventData["duration"] = "unknown"
 				}
+				if profile := scanner.Profile(); profile != nil {
+					eventData["profile"] = profile
+				}
 
 				completeEvent.Data = eventData
 				m.eventBus.PublishAsync(completeEvent)
@@ -643,7 +718,9 @@ func (m *Manager) ResumeScan(jobID uint32) error {
 	}
 
 	// Create and register new scanner
-	scanner := NewLibraryScanner(m.db, jobID, m.eventBus, m.pluginModule, m.enrichmentHook)
+	scanner := NewLibraryScanner(m.db, jobID, m.eventBus, m.pluginModule, m.fileHook())
+	scanner.SetReportDir(m.ReportDir())
+	scanner.SetProfiling(config.Get().Scanner.Profiling)
 	m.scanners[jobID] = scanner
 
 	// Register enrichment hook with the resumed scanner if available
@@ -694,6 +771,16 @@ func (m *Manager) GetScanStatus(jobID uint32) (*database.ScanJob, error) {
 	return &scanJob, nil
 }
 
+// ReportDir returns the directory holding per-file scan reports.
+func (m *Manager) ReportDir() string {
+	return filepath.Join(config.Get().Database.DataDir, "scan-reports")
+}
+
+// GetScanReport returns the per-file report written when a scan job ended.
+func (m *Manager) GetScanReport(jobID uint32) (*ScanReport, error) {
+	return LoadScanReport(m.ReportDir(), jobID)
+}
+
 // GetAllScans returns all scan jobs ordered by creation date.
 func (m *Manager) GetAllScans() ([]database.ScanJob, error) {
 	var scanJobs []database.ScanJob
@@ -1136,6 +1223,11 @@ func (m *Manager) GetDetailedScanProgress(jobID uint32) (map[string]interface{},
 		"container_aware": containerAware,
 	}
 
+	// Stage, handler and hook timings so far, for profiled scans
+	if profile := scanner.Profile(); profile != nil {
+		detailedMetrics["profile"] = profile
+	}
+
 	// Add container-specific metrics if running in a container
 	if containerAware {
 		// detailedMetrics["cgroup_version"] = scanner.adaptiveThrottler.cgroupVersion
@@ -1184,6 +1276,9 @@ func (m *Manager) RegisterEnrichmentHook(hook ScannerPluginHook) {
 
 	m.enrichmentHook = hook
 
+	// Hooks that track file changes also hear about monitored files
+	m.updateChangeHook()
+
 	// Register with all active scanners
 	for _, scanner := range m.scanners {
 		if scanner.enhancedPluginRouter != nil {
@@ -20,6 +20,14 @@ type ScannerPluginHook interface {
 	Name() string
 }
 
+// MediaFileChangeHook is implemented by scanner hooks that also want to know
+// when a known media file changes on disk or is deleted. OnMediaFileRemoved
+// is called before the file's row is deleted.
+type MediaFileChangeHook interface {
+	OnMediaFileUpdated(mediaFile *database.MediaFile, metadata interface{}) error
+	OnMediaFileRemoved(mediaFile *database.MediaFile) error
+}
+
 // ScanStats represents scan completion statistics
 type ScanStats struct {
 	FilesProcessed int64
@@ -0,0 +1,114 @@
+package scanner
+
+import (
+	"errors"
+
+	"github.com/mantonx/viewra/internal/database"
+	"github.com/mantonx/viewra/internal/logger"
+)
+
+// scanHooks passes scanner events on to several hooks in turn. Every hook is
+// called even when an earlier one fails, and their errors are joined.
+type scanHooks []ScannerPluginHook
+
+func (h scanHooks) OnScanStarted(jobID, libraryID uint, path string) error {
+	var errs []error
+	for _, hook := range h {
+		errs = append(errs, hook.OnScanStarted(jobID, libraryID, path))
+	}
+	return errors.Join(errs...)
+}
+
+func (h scanHooks) OnFileScanned(mediaFile *database.MediaFile, metadata interface{}) error {
+	var errs []error
+	for _, hook := range h {
+		errs = append(errs, hook.OnFileScanned(mediaFile, metadata))
+	}
+	return errors.Join(errs...)
+}
+
+func (h scanHooks) OnMediaFileScanned(mediaFile *database.MediaFile, metadata interface{}) error {
+	var errs []error
+	for _, hook := range h {
+		errs = append(errs, hook.OnMediaFileScanned(mediaFile, metadata))
+	}
+	return errors.Join(errs...)
+}
+
+func (h scanHooks) OnScanCompleted(libraryID uint, stats ScanStats) error {
+	var errs []error
+	for _, hook := range h {
+		errs = append(errs, hook.OnScanCompleted(libraryID, stats))
+	}
+	return errors.Join(errs...)
+}
+
+func (h scanHooks) Name() string {
+	return "scan_hooks"
+}
+
+// OnMediaFileUpdated is passed on to the hooks that track file changes
+func (h scanHooks) OnMediaFileUpdated(mediaFile *database.MediaFile, metadata interface{}) error {
+	var errs []error
+	for _, hook := range h {
+		if changeHook, ok := hook.(MediaFileChangeHook); ok {
+			errs = append(errs, changeHook.OnMediaFileUpdated(mediaFile, metadata))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// OnMediaFileRemoved is passed on to the hooks that track file changes
+func (h scanHooks) OnMediaFileRemoved(mediaFile *database.MediaFile) error {
+	var errs []error
+	for _, hook := range h {
+		if changeHook, ok := hook.(MediaFileChangeHook); ok {
+			errs = append(errs, changeHook.OnMediaFileRemoved(mediaFile))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// fileHook returns the hook scanners call for each file: the enrichment
+// hook followed by the other registered scan hooks. Callers hold m.mu.
+func (m *Manager) fileHook() ScannerPluginHook {
+	var hooks scanHooks
+	if m.enrichmentHook != nil {
+		hooks = append(hooks, m.enrichmentHook)
+	}
+	hooks = append(hooks, m.scanHooks...)
+
+	switch len(hooks) {
+	case 0:
+		return nil
+	case 1:
+		return hooks[0]
+	default:
+		return hooks
+	}
+}
+
+// updateChangeHook tells the file monitor about the hooks that track file
+// changes. Callers hold m.mu.
+func (m *Manager) updateChangeHook() {
+	if m.fileMonitor == nil {
+		return
+	}
+	if changeHook, ok := m.fileHook().(MediaFileChangeHook); ok {
+		m.fileMonitor.SetChangeHook(changeHook)
+	}
+}
+
+// RegisterScanHook registers a hook called for every scanned file after the
+// enrichment hook, for modules that process files as they are found. Hooks
+// that also implement MediaFileChangeHook hear about changed and removed
+// files. Scans already running keep the hooks they started with.
+func (m *Manager) RegisterScanHook(hook ScannerPluginHook) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.scanHooks = append(m.scanHooks, hook)
+	m.updateChangeHook()
+
+	logger.Info("Scan hook registered with scanner manager", "hook", hook.Name())
+}
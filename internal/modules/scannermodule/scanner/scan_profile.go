@@ -0,0 +1,200 @@
+package scanner
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/mantonx/viewra/internal/database"
+	"github.com/mantonx/viewra/internal/logger"
+	"gorm.io/gorm"
+)
+
+// Kinds of work a scan profile times
+const (
+	// ProfileKindStage is a step of the scanner itself: walking directories,
+	// looking files up, probing them with FFprobe and saving them
+	ProfileKindStage = "stage"
+	// ProfileKindHandler is a metadata handler plugin run on each new file
+	ProfileKindHandler = "handler"
+	// ProfileKindHook is a scan hook called for each new file, such as the
+	// enrichment module
+	ProfileKindHook = "hook"
+	// ProfileKindPluginHook is an external plugin's scanner hook. These run in
+	// the background, so their time isn't part of the scan's own.
+	ProfileKindPluginHook = "plugin_hook"
+)
+
+// Scanner stages timed by a scan profile
+const (
+	profileStageWalk      = "walk"
+	profileStageQueueWait = "queue_wait"
+	profileStageLookup    = "lookup"
+	profileStageProbe     = "probe"
+	profileStageSave      = "save"
+)
+
+// ScanTiming is the time spent on one kind of work across a scan
+type ScanTiming struct {
+	Kind    string  `json:"kind"`
+	Name    string  `json:"name"`
+	Calls   int64   `json:"calls"`
+	TotalMs int64   `json:"total_ms"`
+	AvgMs   float64 `json:"avg_ms"`
+	MaxMs   int64   `json:"max_ms"`
+}
+
+// ScanProfile breaks a scan's time down by stage, handler and hook, slowest
+// first. Times of work done by several workers at once add up, so they can
+// exceed the scan's elapsed time.
+type ScanProfile struct {
+	ElapsedMs int64        `json:"elapsed_ms"`
+	Workers   int          `json:"workers"`
+	Timings   []ScanTiming `json:"timings"`
+}
+
+type profileKey struct {
+	kind string
+	name string
+}
+
+type profileTotals struct {
+	calls int64
+	total time.Duration
+	max   time.Duration
+}
+
+// scanProfiler accumulates timings from the walker and file workers. A nil
+// profiler records nothing, so call sites needn't check whether profiling is
+// on.
+type scanProfiler struct {
+	mu        sync.Mutex
+	startedAt time.Time
+	workers   int
+	totals    map[profileKey]*profileTotals
+}
+
+func newScanProfiler(workers int) *scanProfiler {
+	return &scanProfiler{
+		startedAt: time.Now(),
+		workers:   workers,
+		totals:    make(map[profileKey]*profileTotals),
+	}
+}
+
+// record adds one timed call
+func (p *scanProfiler) record(kind, name string, d time.Duration) {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	key := profileKey{kind: kind, name: name}
+	totals, ok := p.totals[key]
+	if !ok {
+		totals = &profileTotals{}
+		p.totals[key] = totals
+	}
+	totals.calls++
+	totals.total += d
+	if d > totals.max {
+		totals.max = d
+	}
+}
+
+// since records a call that started at start
+func (p *scanProfiler) since(kind, name string, start time.Time) {
+	p.record(kind, name, time.Since(start))
+}
+
+// build snapshots the timings so far, adding the external plugin hook
+// results recorded for the job's files
+func (p *scanProfiler) build(db *gorm.DB, jobID uint32) *ScanProfile {
+	if p == nil {
+		return nil
+	}
+
+	p.mu.Lock()
+	profile := &ScanProfile{
+		ElapsedMs: time.Since(p.startedAt).Milliseconds(),
+		Workers:   p.workers,
+		Timings:   make([]ScanTiming, 0, len(p.totals)),
+	}
+	for key, totals := range p.totals {
+		profile.Timings = append(profile.Timings, ScanTiming{
+			Kind:    key.kind,
+			Name:    key.name,
+			Calls:   totals.calls,
+			TotalMs: totals.total.Milliseconds(),
+			AvgMs:   roundMs(float64(totals.total.Microseconds()) / 1000 / float64(totals.calls)),
+			MaxMs:   totals.max.Milliseconds(),
+		})
+	}
+	p.mu.Unlock()
+
+	if db != nil {
+		profile.Timings = append(profile.Timings, pluginHookTimings(db, jobID)...)
+	}
+
+	// Sub-millisecond totals are compared through the average
+	sort.Slice(profile.Timings, func(i, j int) bool {
+		a, b := profile.Timings[i], profile.Timings[j]
+		return a.AvgMs*float64(a.Calls) > b.AvgMs*float64(b.Calls)
+	})
+	return profile
+}
+
+// pluginHookTimings sums the external plugin hook results for the files a
+// scan job added. Plugins still working through the scan's files when this
+// runs are only partly counted.
+func pluginHookTimings(db *gorm.DB, jobID uint32) []ScanTiming {
+	var rows []struct {
+		PluginID string
+		Calls    int64
+		TotalMs  int64
+		MaxMs    int64
+	}
+	err := db.Model(&database.PluginHookResult{}).
+		Select("plugin_hook_results.plugin_id, COUNT(*) AS calls, SUM(plugin_hook_results.duration_ms) AS total_ms, MAX(plugin_hook_results.duration_ms) AS max_ms").
+		Joins("JOIN media_files ON media_files.id = plugin_hook_results.media_file_id").
+		Where("media_files.scan_job_id = ?", jobID).
+		Group("plugin_hook_results.plugin_id").
+		Scan(&rows).Error
+	if err != nil {
+		logger.Warn("Failed to load plugin hook timings for scan job %d: %v", jobID, err)
+		return nil
+	}
+
+	timings := make([]ScanTiming, 0, len(rows))
+	for _, row := range rows {
+		timings = append(timings, ScanTiming{
+			Kind:    ProfileKindPluginHook,
+			Name:    row.PluginID,
+			Calls:   row.Calls,
+			TotalMs: row.TotalMs,
+			AvgMs:   roundMs(float64(row.TotalMs) / float64(row.Calls)),
+			MaxMs:   row.MaxMs,
+		})
+	}
+	return timings
+}
+
+// roundMs rounds a millisecond average to the microsecond
+func roundMs(ms float64) float64 {
+	return math.Round(ms*1000) / 1000
+}
+
+// slowest describes the n timings taking longest, for logging
+func (p *ScanProfile) slowest(n int) []string {
+	if n > len(p.Timings) {
+		n = len(p.Timings)
+	}
+	slowest := make([]string, 0, n)
+	for _, timing := range p.Timings[:n] {
+		slowest = append(slowest, fmt.Sprintf("%s %s: %dms over %d calls", timing.Kind, timing.Name, timing.TotalMs, timing.Calls))
+	}
+	return slowest
+}
@@ -0,0 +1,55 @@
+package scanner
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mantonx/viewra/internal/database"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func TestScanProfilerDisabled(t *testing.T) {
+	var profiler *scanProfiler
+	profiler.record(ProfileKindStage, profileStageProbe, time.Second)
+	assert.Nil(t, profiler.build(nil, 1))
+}
+
+func TestScanProfilerBuild(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&database.MediaFile{}, &database.PluginHookResult{}))
+
+	jobID, otherJobID := uint32(3), uint32(4)
+	require.NoError(t, db.Create([]database.MediaFile{
+		{ID: "file-1", Path: "/media/a.mkv", ScanJobID: &jobID},
+		{ID: "file-2", Path: "/media/b.mkv", ScanJobID: &jobID},
+		{ID: "file-3", Path: "/media/c.mkv", ScanJobID: &otherJobID},
+	}).Error)
+	require.NoError(t, db.Create([]database.PluginHookResult{
+		{MediaFileID: "file-1", PluginID: "tmdb", Status: "processed", DurationMs: 400},
+		{MediaFileID: "file-2", PluginID: "tmdb", Status: "processed", DurationMs: 200},
+		{MediaFileID: "file-3", PluginID: "tmdb", Status: "processed", DurationMs: 9000},
+	}).Error)
+
+	profiler := newScanProfiler(4)
+	profiler.record(ProfileKindStage, profileStageProbe, 100*time.Millisecond)
+	profiler.record(ProfileKindStage, profileStageProbe, 300*time.Millisecond)
+	profiler.record(ProfileKindHandler, "music_metadata_extractor", 50*time.Millisecond)
+	profiler.record(ProfileKindStage, profileStageLookup, 250*time.Microsecond)
+
+	profile := profiler.build(db, jobID)
+	require.NotNil(t, profile)
+	assert.Equal(t, 4, profile.Workers)
+	require.Len(t, profile.Timings, 4)
+
+	// Slowest first, counting only the job's own files
+	assert.Equal(t, ScanTiming{Kind: ProfileKindPluginHook, Name: "tmdb", Calls: 2, TotalMs: 600, AvgMs: 300, MaxMs: 400}, profile.Timings[0])
+	assert.Equal(t, ScanTiming{Kind: ProfileKindStage, Name: profileStageProbe, Calls: 2, TotalMs: 400, AvgMs: 200, MaxMs: 300}, profile.Timings[1])
+	assert.Equal(t, "music_metadata_extractor", profile.Timings[2].Name)
+	assert.Equal(t, profileStageLookup, profile.Timings[3].Name)
+	assert.Equal(t, 0.25, profile.Timings[3].AvgMs)
+}
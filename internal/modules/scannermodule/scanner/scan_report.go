@@ -0,0 +1,168 @@
+package scanner
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ScanOutcome describes what happened to a file during a scan
+type ScanOutcome string
+
+const (
+	// ScanOutcomeMatched is a new file that at least one metadata handler processed
+	ScanOutcomeMatched ScanOutcome = "matched"
+	// ScanOutcomeAdded is a new file that no metadata handler processed
+	ScanOutcomeAdded ScanOutcome = "added"
+	// ScanOutcomeUnchanged is a file that was already in the library
+	ScanOutcomeUnchanged ScanOutcome = "unchanged"
+	// ScanOutcomeUpdated is a file already in the library whose size changed
+	ScanOutcomeUpdated ScanOutcome = "updated"
+	// ScanOutcomeSkipped is a file the scanner chose not to process
+	ScanOutcomeSkipped ScanOutcome = "skipped"
+	// ScanOutcomeFailed is a file that could not be processed
+	ScanOutcomeFailed ScanOutcome = "failed"
+)
+
+// ScanReportEntry is the outcome for a single file
+type ScanReportEntry struct {
+	Path        string      `json:"path"`
+	Outcome     ScanOutcome `json:"outcome"`
+	MediaType   string      `json:"media_type,omitempty"`
+	MediaFileID string      `json:"media_file_id,omitempty"`
+	Sources     []string    `json:"sources,omitempty"`
+	Reason      string      `json:"reason,omitempty"`
+	Error       string      `json:"error,omitempty"`
+	DurationMs  int64       `json:"duration_ms"`
+}
+
+// ScanReport lists every file seen by a scan job
+type ScanReport struct {
+	JobID       uint32              `json:"job_id"`
+	LibraryID   uint32              `json:"library_id"`
+	Status      string              `json:"status"`
+	StartedAt   time.Time           `json:"started_at"`
+	CompletedAt time.Time           `json:"completed_at"`
+	Summary     map[ScanOutcome]int `json:"summary"`
+	Profile     *ScanProfile        `json:"profile,omitempty"` // Set when the scan was profiled
+	Entries     []ScanReportEntry   `json:"entries"`
+}
+
+// scanReportCollector accumulates entries from the walker and file workers
+type scanReportCollector struct {
+	mu        sync.Mutex
+	libraryID uint32
+	startedAt time.Time
+	entries   []ScanReportEntry
+}
+
+func newScanReportCollector(libraryID uint32) *scanReportCollector {
+	return &scanReportCollector{
+		libraryID: libraryID,
+		startedAt: time.Now(),
+	}
+}
+
+func (rc *scanReportCollector) add(entry ScanReportEntry) {
+	if rc == nil {
+		return
+	}
+	rc.mu.Lock()
+	rc.entries = append(rc.entries, entry)
+	rc.mu.Unlock()
+}
+
+func (rc *scanReportCollector) skipped(path, reason string) {
+	rc.add(ScanReportEntry{Path: path, Outcome: ScanOutcomeSkipped, Reason: reason})
+}
+
+// build snapshots the collected entries into a report
+func (rc *scanReportCollector) build(jobID uint32, status string) *ScanReport {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	report := &ScanReport{
+		JobID:       jobID,
+		LibraryID:   rc.libraryID,
+		Status:      status,
+		StartedAt:   rc.startedAt,
+		CompletedAt: time.Now(),
+		Summary:     make(map[ScanOutcome]int),
+		Entries:     make([]ScanReportEntry, len(rc.entries)),
+	}
+	copy(report.Entries, rc.entries)
+	for _, entry := range report.Entries {
+		report.Summary[entry.Outcome]++
+	}
+	return report
+}
+
+// ScanReportPath returns where the report for a scan job is stored
+func ScanReportPath(dir string, jobID uint32) string {
+	return filepath.Join(dir, fmt.Sprintf("scan-%d.json", jobID))
+}
+
+// writeScanReport saves the report as JSON, replacing any earlier report for the job
+func writeScanReport(dir string, report *ScanReport) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create report directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode scan report: %w", err)
+	}
+
+	// Write to a temp file first so a reader never sees a partial report
+	path := ScanReportPath(dir, report.JobID)
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("failed to write scan report: %w", err)
+	}
+	return os.Rename(tmp, path)
+}
+
+// LoadScanReport reads a stored scan report
+func LoadScanReport(dir string, jobID uint32) (*ScanReport, error) {
+	data, err := os.ReadFile(ScanReportPath(dir, jobID))
+	if err != nil {
+		return nil, err
+	}
+
+	var report ScanReport
+	if err := json.Unmarshal(data, &report); err != nil {
+		return nil, fmt.Errorf("failed to decode scan report: %w", err)
+	}
+	return &report, nil
+}
+
+// WriteCSV writes one row per file
+func (r *ScanReport) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{"path", "outcome", "media_type", "media_file_id", "sources", "reason", "error", "duration_ms"}); err != nil {
+		return err
+	}
+	for _, entry := range r.Entries {
+		if err := cw.Write([]string{
+			entry.Path,
+			string(entry.Outcome),
+			entry.MediaType,
+			entry.MediaFileID,
+			strings.Join(entry.Sources, ";"),
+			entry.Reason,
+			entry.Error,
+			strconv.FormatInt(entry.DurationMs, 10),
+		}); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
@@ -0,0 +1,199 @@
+package scanner
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mantonx/viewra/internal/database"
+	"github.com/mantonx/viewra/internal/logger"
+	"github.com/mantonx/viewra/internal/schedule"
+	"github.com/mantonx/viewra/internal/utils"
+	"gorm.io/gorm"
+)
+
+// scheduleCheckInterval is how often the scan schedules are checked, the
+// finest step a cron expression can take
+const scheduleCheckInterval = time.Minute
+
+// LibrarySchedule is a library's parsed scan schedule
+type LibrarySchedule struct {
+	cron      *schedule.Cron
+	interval  time.Duration
+	blackouts []*schedule.Window
+}
+
+// ParseLibrarySchedule checks a library's scan schedule. It may have a cron
+// expression or an interval, not both, and neither when it only scans on
+// startup.
+func ParseLibrarySchedule(s *database.LibraryScanSchedule) (*LibrarySchedule, error) {
+	parsed := &LibrarySchedule{}
+	cron := strings.TrimSpace(s.Cron)
+	switch {
+	case cron != "" && s.IntervalMinutes != 0:
+		return nil, fmt.Errorf("set either a cron expression or an interval, not both")
+	case cron != "":
+		var err error
+		if parsed.cron, err = schedule.ParseCron(cron); err != nil {
+			return nil, err
+		}
+	case s.IntervalMinutes < 0:
+		return nil, fmt.Errorf("interval must be positive")
+	case s.IntervalMinutes > 0:
+		parsed.interval = time.Duration(s.IntervalMinutes) * time.Minute
+	}
+
+	for i, window := range s.Blackouts {
+		w, err := schedule.ParseWindow(window.Start, window.End, window.Days)
+		if err != nil {
+			return nil, fmt.Errorf("blackout %d: %w", i+1, err)
+		}
+		parsed.blackouts = append(parsed.blackouts, w)
+	}
+	return parsed, nil
+}
+
+// Next returns when the library is next scanned after after, or nil when it
+// only scans on startup
+func (s *LibrarySchedule) Next(after time.Time) *time.Time {
+	var next time.Time
+	switch {
+	case s.cron != nil:
+		next = s.cron.Next(after)
+	case s.interval > 0:
+		next = after.Add(s.interval)
+	}
+	if next.IsZero() {
+		return nil
+	}
+	return &next
+}
+
+// InBlackout reports whether scheduled scans are held back at t
+func (s *LibrarySchedule) InBlackout(t time.Time) bool {
+	return schedule.InAny(s.blackouts, t)
+}
+
+// scheduledScanner starts, pauses and resumes scans; the Manager in
+// production
+type scheduledScanner interface {
+	StartScan(libraryID uint32) (*database.ScanJob, error)
+	StopScan(jobID uint32) error
+	ResumeScan(jobID uint32) error
+}
+
+// Scheduler starts library scans on their schedules. A scan that falls due
+// during a blackout starts when the blackout ends, and a scheduled scan still
+// running when a blackout begins is paused until it ends. Manual scans
+// aren't affected by blackouts.
+type Scheduler struct {
+	db      *gorm.DB
+	scanner scheduledScanner
+}
+
+// NewScheduler returns a scheduler starting scans with manager
+func NewScheduler(db *gorm.DB, manager *Manager) *Scheduler {
+	return &Scheduler{db: db, scanner: manager}
+}
+
+// Start queues the startup scans and checks the schedules every minute
+func (s *Scheduler) Start() {
+	if err := s.QueueStartupScans(time.Now()); err != nil {
+		logger.Error("Failed to queue startup scans: %v", err)
+	}
+	go func() {
+		s.Check(time.Now())
+		ticker := time.NewTicker(scheduleCheckInterval)
+		defer ticker.Stop()
+		for now := range ticker.C {
+			s.Check(now)
+		}
+	}()
+}
+
+// QueueStartupScans makes the libraries that scan on startup due now
+func (s *Scheduler) QueueStartupScans(now time.Time) error {
+	return s.db.Model(&database.LibraryScanSchedule{}).
+		Where("scan_on_startup = ?", true).
+		Update("next_scan_at", now).Error
+}
+
+// Check starts the scans that are due and pauses or resumes scheduled scans
+// as blackouts begin and end
+func (s *Scheduler) Check(now time.Time) {
+	var schedules []database.LibraryScanSchedule
+	libraries := s.db.Model(&database.MediaLibrary{}).Select("id")
+	if err := s.db.Where("library_id IN (?)", libraries).Find(&schedules).Error; err != nil {
+		logger.Error("Failed to load scan schedules: %v", err)
+		return
+	}
+	for i := range schedules {
+		if err := s.check(&schedules[i], now); err != nil {
+			logger.Warn("Scheduled scan of library %d failed: %v", schedules[i].LibraryID, err)
+		}
+	}
+}
+
+func (s *Scheduler) check(libSchedule *database.LibraryScanSchedule, now time.Time) error {
+	parsed, err := ParseLibrarySchedule(libSchedule)
+	if err != nil {
+		return err
+	}
+	updates := map[string]interface{}{}
+	defer func() {
+		if len(updates) > 0 {
+			if err := s.db.Model(libSchedule).Updates(updates).Error; err != nil {
+				logger.Error("Failed to update scan schedule of library %d: %v", libSchedule.LibraryID, err)
+			}
+		}
+	}()
+
+	if parsed.InBlackout(now) {
+		if !libSchedule.PausedForBlackout && s.jobHasStatus(libSchedule.LastScanJobID, utils.StatusRunning) {
+			if err := s.scanner.StopScan(*libSchedule.LastScanJobID); err != nil {
+				return fmt.Errorf("failed to pause scan for blackout: %w", err)
+			}
+			updates["paused_for_blackout"] = true
+			logger.Info("Paused scheduled scan of library %d for a blackout", libSchedule.LibraryID)
+		}
+		return nil
+	}
+
+	if libSchedule.PausedForBlackout {
+		updates["paused_for_blackout"] = false
+		// Left alone if it was resumed or stopped by hand meanwhile
+		if s.jobHasStatus(libSchedule.LastScanJobID, utils.StatusPaused) {
+			if err := s.scanner.ResumeScan(*libSchedule.LastScanJobID); err != nil {
+				return fmt.Errorf("failed to resume scan after blackout: %w", err)
+			}
+			logger.Info("Resumed scheduled scan of library %d after a blackout", libSchedule.LibraryID)
+			return nil
+		}
+	}
+
+	if libSchedule.NextScanAt == nil || now.Before(*libSchedule.NextScanAt) {
+		return nil
+	}
+	// Occurrences missed while the server was down are made up once, and
+	// the schedule carries on from now
+	updates["next_scan_at"] = parsed.Next(now)
+
+	job, err := s.scanner.StartScan(libSchedule.LibraryID)
+	if err != nil {
+		return fmt.Errorf("failed to start scan: %w", err)
+	}
+	updates["last_scan_at"] = now
+	updates["last_scan_job_id"] = job.ID
+	logger.Info("Started scheduled scan %d of library %d", job.ID, libSchedule.LibraryID)
+	return nil
+}
+
+// jobHasStatus reports whether a scan job exists and has a status
+func (s *Scheduler) jobHasStatus(jobID *uint32, status utils.ScanJobStatus) bool {
+	if jobID == nil {
+		return false
+	}
+	var count int64
+	s.db.Model(&database.ScanJob{}).Where("id = ? AND status = ?", *jobID, string(status)).Count(&count)
+	return count > 0
+}
@@ -0,0 +1,223 @@
+package scanner
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mantonx/viewra/internal/database"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// fakeScheduledScanner records the scans the scheduler starts, pauses and
+// resumes, updating their jobs as the manager would
+type fakeScheduledScanner struct {
+	db      *gorm.DB
+	started []uint32
+	paused  []uint32
+	resumed []uint32
+}
+
+func (f *fakeScheduledScanner) StartScan(libraryID uint32) (*database.ScanJob, error) {
+	job := &database.ScanJob{LibraryID: libraryID, Status: "running"}
+	if err := f.db.Create(job).Error; err != nil {
+		return nil, err
+	}
+	f.started = append(f.started, libraryID)
+	return job, nil
+}
+
+func (f *fakeScheduledScanner) StopScan(jobID uint32) error {
+	f.paused = append(f.paused, jobID)
+	return f.db.Model(&database.ScanJob{}).Where("id = ?", jobID).Update("status", "paused").Error
+}
+
+func (f *fakeScheduledScanner) ResumeScan(jobID uint32) error {
+	f.resumed = append(f.resumed, jobID)
+	return f.db.Model(&database.ScanJob{}).Where("id = ?", jobID).Update("status", "running").Error
+}
+
+func setupSchedulerTest(t *testing.T) (*Scheduler, *fakeScheduledScanner, *gorm.DB) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&database.MediaLibrary{}, &database.ScanJob{}, &database.LibraryScanSchedule{}))
+	require.NoError(t, db.Create(&database.MediaLibrary{ID: 1, Path: "/media/movies", Type: "movie"}).Error)
+
+	fake := &fakeScheduledScanner{db: db}
+	return &Scheduler{db: db, scanner: fake}, fake, db
+}
+
+func loadSchedule(t *testing.T, db *gorm.DB) database.LibraryScanSchedule {
+	var schedule database.LibraryScanSchedule
+	require.NoError(t, db.First(&schedule, "library_id = ?", 1).Error)
+	return schedule
+}
+
+func TestParseLibrarySchedule(t *testing.T) {
+	tests := []struct {
+		name     string
+		schedule database.LibraryScanSchedule
+		wantErr  bool
+	}{
+		{"cron", database.LibraryScanSchedule{Cron: "30 3 * * mon-fri"}, false},
+		{"macro", database.LibraryScanSchedule{Cron: "@daily"}, false},
+		{"interval", database.LibraryScanSchedule{IntervalMinutes: 90}, false},
+		{"startup only", database.LibraryScanSchedule{ScanOnStartup: true}, false},
+		{"cron and interval", database.LibraryScanSchedule{Cron: "@hourly", IntervalMinutes: 60}, true},
+		{"negative interval", database.LibraryScanSchedule{IntervalMinutes: -5}, true},
+		{"too few fields", database.LibraryScanSchedule{Cron: "0 3 * *"}, true},
+		{"minute out of range", database.LibraryScanSchedule{Cron: "60 3 * * *"}, true},
+		{"bad blackout", database.LibraryScanSchedule{Blackouts: database.ScanWindows{{Start: "25:00", End: "06:00"}}}, true},
+		{"empty blackout", database.LibraryScanSchedule{Blackouts: database.ScanWindows{{Start: "06:00", End: "06:00"}}}, true},
+		{"bad blackout day", database.LibraryScanSchedule{Blackouts: database.ScanWindows{{Start: "18:00", End: "23:00", Days: []string{"someday"}}}}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseLibrarySchedule(&tt.schedule)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestLibraryScheduleNext(t *testing.T) {
+	// A Wednesday
+	now := time.Date(2026, time.March, 4, 10, 15, 0, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		cron string
+		want time.Time
+	}{
+		{"later today", "30 11 * * *", time.Date(2026, time.March, 4, 11, 30, 0, 0, time.UTC)},
+		{"tomorrow", "0 3 * * *", time.Date(2026, time.March, 5, 3, 0, 0, 0, time.UTC)},
+		{"every 20 minutes", "*/20 * * * *", time.Date(2026, time.March, 4, 10, 20, 0, 0, time.UTC)},
+		{"weekend", "0 4 * * sat,sun", time.Date(2026, time.March, 7, 4, 0, 0, 0, time.UTC)},
+		{"sunday as 7", "0 4 * * 7", time.Date(2026, time.March, 8, 4, 0, 0, 0, time.UTC)},
+		{"first of month", "@monthly", time.Date(2026, time.April, 1, 0, 0, 0, 0, time.UTC)},
+		{"day of month or weekday", "0 0 15 * fri", time.Date(2026, time.March, 6, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parsed, err := ParseLibrarySchedule(&database.LibraryScanSchedule{Cron: tt.cron})
+			require.NoError(t, err)
+			next := parsed.Next(now)
+			require.NotNil(t, next)
+			assert.Equal(t, tt.want, *next)
+		})
+	}
+
+	t.Run("interval", func(t *testing.T) {
+		parsed, err := ParseLibrarySchedule(&database.LibraryScanSchedule{IntervalMinutes: 45})
+		require.NoError(t, err)
+		assert.Equal(t, now.Add(45*time.Minute), *parsed.Next(now))
+	})
+
+	t.Run("impossible date", func(t *testing.T) {
+		parsed, err := ParseLibrarySchedule(&database.LibraryScanSchedule{Cron: "0 0 30 feb *"})
+		require.NoError(t, err)
+		assert.Nil(t, parsed.Next(now))
+	})
+
+	t.Run("startup only", func(t *testing.T) {
+		parsed, err := ParseLibrarySchedule(&database.LibraryScanSchedule{ScanOnStartup: true})
+		require.NoError(t, err)
+		assert.Nil(t, parsed.Next(now))
+	})
+}
+
+func TestLibraryScheduleBlackoutPastMidnight(t *testing.T) {
+	parsed, err := ParseLibrarySchedule(&database.LibraryScanSchedule{
+		Blackouts: database.ScanWindows{{Start: "22:00", End: "06:00", Days: []string{"fri"}}},
+	})
+	require.NoError(t, err)
+
+	friday := time.Date(2026, time.March, 6, 0, 0, 0, 0, time.UTC)
+	assert.False(t, parsed.InBlackout(friday.Add(2*time.Hour)), "Friday morning follows Thursday, not a window day")
+	assert.True(t, parsed.InBlackout(friday.Add(23*time.Hour)))
+	assert.True(t, parsed.InBlackout(friday.Add(29*time.Hour)), "Saturday 05:00 is still in Friday's window")
+	assert.False(t, parsed.InBlackout(friday.Add(30*time.Hour)))
+	assert.False(t, parsed.InBlackout(friday.Add(47*time.Hour)), "Saturday evening isn't a window day")
+}
+
+func TestSchedulerStartsDueScans(t *testing.T) {
+	scheduler, fake, db := setupSchedulerTest(t)
+	now := time.Date(2026, time.March, 4, 3, 0, 0, 0, time.UTC)
+	due := now.Add(-time.Minute)
+	require.NoError(t, db.Create(&database.LibraryScanSchedule{LibraryID: 1, IntervalMinutes: 60, NextScanAt: &due}).Error)
+
+	scheduler.Check(now)
+	assert.Equal(t, []uint32{1}, fake.started)
+	schedule := loadSchedule(t, db)
+	require.NotNil(t, schedule.NextScanAt)
+	assert.True(t, schedule.NextScanAt.Equal(now.Add(time.Hour)))
+	require.NotNil(t, schedule.LastScanJobID)
+
+	// Not due again until the next interval
+	scheduler.Check(now.Add(30 * time.Minute))
+	assert.Len(t, fake.started, 1)
+	scheduler.Check(now.Add(time.Hour))
+	assert.Len(t, fake.started, 2)
+}
+
+func TestSchedulerSkipsDeletedLibraries(t *testing.T) {
+	scheduler, fake, db := setupSchedulerTest(t)
+	now := time.Now()
+	require.NoError(t, db.Create(&database.LibraryScanSchedule{LibraryID: 2, IntervalMinutes: 60, NextScanAt: &now}).Error)
+
+	scheduler.Check(now)
+	assert.Empty(t, fake.started)
+}
+
+func TestSchedulerStartupScans(t *testing.T) {
+	scheduler, fake, db := setupSchedulerTest(t)
+	require.NoError(t, db.Create(&database.LibraryScanSchedule{LibraryID: 1, ScanOnStartup: true}).Error)
+	now := time.Date(2026, time.March, 4, 12, 0, 0, 0, time.UTC)
+
+	require.NoError(t, scheduler.QueueStartupScans(now))
+	scheduler.Check(now)
+	assert.Equal(t, []uint32{1}, fake.started)
+	assert.Nil(t, loadSchedule(t, db).NextScanAt, "a startup-only library isn't scanned again until the next start")
+}
+
+func TestSchedulerBlackouts(t *testing.T) {
+	scheduler, fake, db := setupSchedulerTest(t)
+	due := time.Date(2026, time.March, 4, 18, 30, 0, 0, time.UTC)
+	require.NoError(t, db.Create(&database.LibraryScanSchedule{
+		LibraryID:  1,
+		Cron:       "30 18 * * *",
+		NextScanAt: &due,
+		Blackouts:  database.ScanWindows{{Start: "19:00", End: "23:00"}, {Start: "12:00", End: "13:00"}},
+	}).Error)
+
+	// Due outside a blackout: starts
+	scheduler.Check(due)
+	require.Len(t, fake.started, 1)
+	jobID := *loadSchedule(t, db).LastScanJobID
+
+	// Still running when the blackout begins: paused, once
+	scheduler.Check(due.Add(45 * time.Minute))
+	scheduler.Check(due.Add(50 * time.Minute))
+	assert.Equal(t, []uint32{jobID}, fake.paused)
+	assert.True(t, loadSchedule(t, db).PausedForBlackout)
+
+	// Resumed when it ends
+	scheduler.Check(due.Add(5 * time.Hour))
+	assert.Equal(t, []uint32{jobID}, fake.resumed)
+	assert.False(t, loadSchedule(t, db).PausedForBlackout)
+
+	// A scan falling due in a blackout waits for it to end
+	require.NoError(t, db.Model(&database.ScanJob{}).Where("id = ?", jobID).Update("status", "completed").Error)
+	noon := time.Date(2026, time.March, 5, 12, 0, 0, 0, time.UTC)
+	require.NoError(t, db.Model(&database.LibraryScanSchedule{}).Where("library_id = ?", 1).Update("next_scan_at", noon).Error)
+	scheduler.Check(noon.Add(10 * time.Minute))
+	assert.Len(t, fake.started, 1)
+	scheduler.Check(noon.Add(time.Hour))
+	assert.Len(t, fake.started, 2)
+}
@@ -0,0 +1,468 @@
+package searchmodule
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mantonx/viewra/internal/database"
+	"gorm.io/gorm"
+)
+
+// Kinds of searchable item
+const (
+	KindMovie     = "movie"
+	KindTVShow    = "tv_show"
+	KindEpisode   = "episode"
+	KindArtist    = "artist"
+	KindAlbum     = "album"
+	KindTrack     = "track"
+	KindHomeVideo = "home_video"
+)
+
+// Fields of a document's full-text entry, in the order of the full-text
+// table's columns
+type field int
+
+const (
+	fieldTitle field = iota
+	fieldAltTitle
+	fieldPeople
+	fieldGenre
+	fieldKeyword
+	fieldContext // The show of an episode, the album of a track
+	fieldOverview
+	fieldPath
+	fieldCount
+)
+
+// fieldColumns name the full-text columns of each field
+var fieldColumns = [fieldCount]string{
+	"title", "alt_title", "people", "genre", "keyword", "context", "overview", "path",
+}
+
+// fieldWeights are how much a match in each field counts towards a
+// document's rank
+var fieldWeights = [fieldCount]float64{5, 3, 2, 2, 1.5, 1.5, 1, 0.5}
+
+// Paths indexed per item; a track on many compilations needn't add them all
+const maxIndexedPaths = 3
+
+// Document is one searchable item. Its text is kept in the full-text table
+// under the same ID, and the libraries holding it in DocumentLibrary rows.
+type Document struct {
+	ID              uint32    `gorm:"primaryKey" json:"-"`
+	Kind            string    `gorm:"type:varchar(20);not null;uniqueIndex:idx_search_document_entity" json:"kind"`
+	EntityID        string    `gorm:"type:varchar(36);not null;uniqueIndex:idx_search_document_entity" json:"id"`
+	ParentID        string    `gorm:"type:varchar(36);index" json:"parent_id,omitempty"` // Show of an episode, album of a track, artist of an album
+	MediaFileID     string    `gorm:"type:varchar(36)" json:"media_file_id,omitempty"`
+	Title           string    `json:"title"`
+	TitleKey        string    `json:"-"` // The title's terms, to rank whole title matches first
+	Subtitle        string    `json:"subtitle,omitempty"`
+	Year            int       `gorm:"index" json:"year,omitempty"`
+	Genres          string    `json:"-"` // Normalized and bar-delimited, e.g. |action|science fiction|
+	SourceUpdatedAt time.Time `json:"-"` // The item's updated_at when indexed
+	IndexedAt       time.Time `json:"indexed_at"`
+
+	LibraryIDs []uint32 `gorm:"-" json:"library_ids,omitempty"`
+}
+
+// TableName returns the search document table name
+func (Document) TableName() string {
+	return "search_documents"
+}
+
+// DocumentLibrary records that a library holds files of a document's item.
+// An item is in every library one of its files is in.
+type DocumentLibrary struct {
+	DocumentID uint32 `gorm:"primaryKey"`
+	LibraryID  uint32 `gorm:"primaryKey;index"`
+}
+
+// TableName returns the search document library table name
+func (DocumentLibrary) TableName() string {
+	return "search_document_libraries"
+}
+
+// docBuilder collects a document, its text by field and its libraries
+type docBuilder struct {
+	doc       Document
+	text      [fieldCount][]string
+	people    map[string]bool
+	libraries map[uint32]bool
+	paths     int
+}
+
+func newDocBuilder(kind, entityID string, updatedAt time.Time) *docBuilder {
+	return &docBuilder{
+		doc:       Document{Kind: kind, EntityID: entityID, SourceUpdatedAt: updatedAt},
+		people:    make(map[string]bool),
+		libraries: make(map[uint32]bool),
+	}
+}
+
+// add indexes text in a field. Text is stored as its normalized terms, so
+// the full-text tokenizer sees the same words queries are split into.
+func (b *docBuilder) add(text string, f field) {
+	if terms := tokenize(text); len(terms) > 0 {
+		b.text[f] = append(b.text[f], strings.Join(terms, " "))
+	}
+}
+
+// setTitle sets the document's title and indexes it
+func (b *docBuilder) setTitle(title string) {
+	b.doc.Title = title
+	b.doc.TitleKey = strings.Join(tokenize(title), " ")
+	b.add(title, fieldTitle)
+}
+
+// fields returns the text of each field
+func (b *docBuilder) fields() [fieldCount]string {
+	var fields [fieldCount]string
+	for f, text := range b.text {
+		fields[f] = strings.Join(text, " ")
+	}
+	return fields
+}
+
+// addPeople indexes names, counting each person once however many of the
+// item's fields list them
+func (b *docBuilder) addPeople(names []string) {
+	for _, name := range names {
+		key := normalize(strings.TrimSpace(name))
+		if key == "" || b.people[key] {
+			continue
+		}
+		b.people[key] = true
+		b.add(name, fieldPeople)
+	}
+}
+
+// addGenres indexes genres and keeps them for filtering
+func (b *docBuilder) addGenres(genres []string) {
+	for _, genre := range genres {
+		genre = normalize(strings.TrimSpace(genre))
+		if genre == "" || strings.Contains(b.doc.Genres, "|"+genre+"|") {
+			continue
+		}
+		if b.doc.Genres == "" {
+			b.doc.Genres = "|"
+		}
+		b.doc.Genres += genre + "|"
+		b.add(genre, fieldGenre)
+	}
+}
+
+// addLibrary records a library holding the item
+func (b *docBuilder) addLibrary(libraryID uint32) {
+	if libraryID != 0 {
+		b.libraries[libraryID] = true
+	}
+}
+
+// addFiles records the libraries of an item's files, points the document at
+// its first file and, when paths is set, indexes their paths
+func (b *docBuilder) addFiles(files []fileRow, paths bool) {
+	for _, file := range files {
+		if b.doc.MediaFileID == "" {
+			b.doc.MediaFileID = file.ID
+		}
+		b.addLibrary(file.LibraryID)
+		if paths && b.paths < maxIndexedPaths && file.Path != "" {
+			b.paths++
+			b.add(file.Path, fieldPath)
+		}
+	}
+}
+
+// source builds the documents of one kind of item
+type source struct {
+	kind  string
+	table string
+	build func(db *gorm.DB, ids []string) ([]*docBuilder, error)
+}
+
+var sources = []source{
+	{kind: KindMovie, table: "movies", build: buildMovies},
+	{kind: KindTVShow, table: "tv_shows", build: buildShows},
+	{kind: KindEpisode, table: "episodes", build: buildEpisodes},
+	{kind: KindArtist, table: "artists", build: buildArtists},
+	{kind: KindAlbum, table: "albums", build: buildAlbums},
+	{kind: KindTrack, table: "tracks", build: buildTracks},
+	{kind: KindHomeVideo, table: "home_videos", build: buildHomeVideos},
+}
+
+// sourceFor returns the source of a kind of item
+func sourceFor(kind string) (*source, bool) {
+	for i := range sources {
+		if sources[i].kind == kind {
+			return &sources[i], true
+		}
+	}
+	return nil, false
+}
+
+// fileRow is a media file of the item in OwnerID
+type fileRow struct {
+	OwnerID   string
+	ID        string
+	LibraryID uint32
+	Path      string
+}
+
+// fileColumns are the media file columns selected with an owner_id
+const fileColumns = "media_files.id, media_files.library_id, media_files.path"
+
+// loadFiles groups the files a query selects by owner, oldest first
+func loadFiles(query *gorm.DB) (map[string][]fileRow, error) {
+	var rows []fileRow
+	if err := query.Order("media_files.created_at, media_files.id").Scan(&rows).Error; err != nil {
+		return nil, fmt.Errorf("failed to load media files: %w", err)
+	}
+	files := make(map[string][]fileRow)
+	for _, row := range rows {
+		files[row.OwnerID] = append(files[row.OwnerID], row)
+	}
+	return files, nil
+}
+
+// ownFiles selects the files of items that are media files' media_id
+func ownFiles(db *gorm.DB, mediaType database.MediaType, ids []string) *gorm.DB {
+	return db.Table("media_files").
+		Select("media_files.media_id AS owner_id, "+fileColumns).
+		Where("media_files.media_type = ? AND media_files.media_id IN ?", mediaType, ids)
+}
+
+// loadPeople loads the names of the people with roles in items
+func loadPeople(db *gorm.DB, ids []string) (map[string][]string, error) {
+	var rows []struct {
+		MediaID string
+		Name    string
+	}
+	if err := db.Table("roles").Select("roles.media_id, peoples.name").
+		Joins("JOIN peoples ON peoples.id = roles.person_id").
+		Where("roles.media_id IN ?", ids).Scan(&rows).Error; err != nil {
+		return nil, fmt.Errorf("failed to load people: %w", err)
+	}
+	people := make(map[string][]string)
+	for _, row := range rows {
+		people[row.MediaID] = append(people[row.MediaID], row.Name)
+	}
+	return people, nil
+}
+
+func yearOf(t *time.Time) int {
+	if t == nil || t.IsZero() {
+		return 0
+	}
+	return t.Year()
+}
+
+func buildMovies(db *gorm.DB, ids []string) ([]*docBuilder, error) {
+	var movies []database.Movie
+	if err := db.Where("id IN ?", ids).Find(&movies).Error; err != nil {
+		return nil, fmt.Errorf("failed to load movies: %w", err)
+	}
+	files, err := loadFiles(ownFiles(db, database.MediaTypeMovie, ids))
+	if err != nil {
+		return nil, err
+	}
+	people, err := loadPeople(db, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	builders := make([]*docBuilder, 0, len(movies))
+	for _, movie := range movies {
+		b := newDocBuilder(KindMovie, movie.ID, movie.UpdatedAt)
+		b.setTitle(movie.Title)
+		b.doc.Year = yearOf(movie.ReleaseDate)
+		if movie.OriginalTitle != movie.Title {
+			b.add(movie.OriginalTitle, fieldAltTitle)
+		}
+		b.add(movie.Tagline, fieldOverview)
+		b.add(movie.Overview, fieldOverview)
+		b.addGenres(listValues(movie.Genres))
+		for _, keyword := range listValues(movie.Keywords) {
+			b.add(keyword, fieldKeyword)
+		}
+		b.addPeople(listValues(movie.MainCast))
+		b.addPeople(listValues(movie.MainCrew))
+		b.addPeople(people[movie.ID])
+		b.addFiles(files[movie.ID], true)
+		builders = append(builders, b)
+	}
+	return builders, nil
+}
+
+func buildShows(db *gorm.DB, ids []string) ([]*docBuilder, error) {
+	var shows []database.TVShow
+	if err := db.Where("id IN ?", ids).Find(&shows).Error; err != nil {
+		return nil, fmt.Errorf("failed to load TV shows: %w", err)
+	}
+	files, err := loadFiles(db.Table("media_files").
+		Select("seasons.tv_show_id AS owner_id, "+fileColumns).
+		Joins("JOIN episodes ON episodes.id = media_files.media_id").
+		Joins("JOIN seasons ON seasons.id = episodes.season_id").
+		Where("media_files.media_type = ? AND seasons.tv_show_id IN ?", database.MediaTypeEpisode, ids))
+	if err != nil {
+		return nil, err
+	}
+	people, err := loadPeople(db, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	builders := make([]*docBuilder, 0, len(shows))
+	for _, show := range shows {
+		b := newDocBuilder(KindTVShow, show.ID, show.UpdatedAt)
+		b.setTitle(show.Title)
+		b.doc.Year = yearOf(show.FirstAirDate)
+		b.add(show.Description, fieldOverview)
+		b.addPeople(people[show.ID])
+		// Episode paths belong to the episodes; the show only needs libraries
+		b.addFiles(files[show.ID], false)
+		builders = append(builders, b)
+	}
+	return builders, nil
+}
+
+func buildEpisodes(db *gorm.DB, ids []string) ([]*docBuilder, error) {
+	var episodes []database.Episode
+	if err := db.Preload("Season.TVShow").Where("id IN ?", ids).Find(&episodes).Error; err != nil {
+		return nil, fmt.Errorf("failed to load episodes: %w", err)
+	}
+	files, err := loadFiles(ownFiles(db, database.MediaTypeEpisode, ids))
+	if err != nil {
+		return nil, err
+	}
+	people, err := loadPeople(db, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	builders := make([]*docBuilder, 0, len(episodes))
+	for _, episode := range episodes {
+		show := episode.Season.TVShow
+		b := newDocBuilder(KindEpisode, episode.ID, episode.UpdatedAt)
+		b.setTitle(episode.Title)
+		b.doc.ParentID = show.ID
+		b.doc.Subtitle = fmt.Sprintf("%s S%02dE%02d", show.Title, episode.Season.SeasonNumber, episode.EpisodeNumber)
+		b.doc.Year = yearOf(episode.AirDate)
+		b.add(show.Title, fieldContext)
+		b.add(episode.Description, fieldOverview)
+		b.addPeople(people[episode.ID])
+		b.addFiles(files[episode.ID], true)
+		builders = append(builders, b)
+	}
+	return builders, nil
+}
+
+func buildArtists(db *gorm.DB, ids []string) ([]*docBuilder, error) {
+	var artists []database.Artist
+	if err := db.Where("id IN ?", ids).Find(&artists).Error; err != nil {
+		return nil, fmt.Errorf("failed to load artists: %w", err)
+	}
+	files, err := loadFiles(db.Table("media_files").
+		Select("tracks.artist_id AS owner_id, "+fileColumns).
+		Joins("JOIN tracks ON tracks.id = media_files.media_id").
+		Where("media_files.media_type = ? AND tracks.artist_id IN ?", database.MediaTypeTrack, ids))
+	if err != nil {
+		return nil, err
+	}
+
+	builders := make([]*docBuilder, 0, len(artists))
+	for _, artist := range artists {
+		b := newDocBuilder(KindArtist, artist.ID, artist.UpdatedAt)
+		b.setTitle(artist.Name)
+		b.doc.Subtitle = artist.Disambiguation
+		b.add(artist.Disambiguation, fieldOverview)
+		b.add(artist.Description, fieldOverview)
+		b.addFiles(files[artist.ID], false)
+		builders = append(builders, b)
+	}
+	return builders, nil
+}
+
+func buildAlbums(db *gorm.DB, ids []string) ([]*docBuilder, error) {
+	var albums []database.Album
+	if err := db.Preload("Artist").Where("id IN ?", ids).Find(&albums).Error; err != nil {
+		return nil, fmt.Errorf("failed to load albums: %w", err)
+	}
+	files, err := loadFiles(db.Table("media_files").
+		Select("tracks.album_id AS owner_id, "+fileColumns).
+		Joins("JOIN tracks ON tracks.id = media_files.media_id").
+		Where("media_files.media_type = ? AND tracks.album_id IN ?", database.MediaTypeTrack, ids))
+	if err != nil {
+		return nil, err
+	}
+
+	builders := make([]*docBuilder, 0, len(albums))
+	for _, album := range albums {
+		b := newDocBuilder(KindAlbum, album.ID, album.UpdatedAt)
+		b.setTitle(album.Title)
+		b.doc.ParentID = album.ArtistID
+		b.doc.Subtitle = album.Artist.Name
+		b.doc.Year = yearOf(album.ReleaseDate)
+		b.addPeople([]string{album.Artist.Name})
+		b.addFiles(files[album.ID], false)
+		builders = append(builders, b)
+	}
+	return builders, nil
+}
+
+func buildTracks(db *gorm.DB, ids []string) ([]*docBuilder, error) {
+	var tracks []database.Track
+	if err := db.Preload("Album").Preload("Artist").Where("id IN ?", ids).Find(&tracks).Error; err != nil {
+		return nil, fmt.Errorf("failed to load tracks: %w", err)
+	}
+	files, err := loadFiles(ownFiles(db, database.MediaTypeTrack, ids))
+	if err != nil {
+		return nil, err
+	}
+
+	builders := make([]*docBuilder, 0, len(tracks))
+	for _, track := range tracks {
+		b := newDocBuilder(KindTrack, track.ID, track.UpdatedAt)
+		b.setTitle(track.Title)
+		b.doc.ParentID = track.AlbumID
+		b.doc.Subtitle = track.Artist.Name
+		if track.Album.Title != "" {
+			b.doc.Subtitle += " - " + track.Album.Title
+		}
+		b.doc.Year = yearOf(track.Album.ReleaseDate)
+		b.add(track.Work, fieldAltTitle)
+		b.add(track.Album.Title, fieldContext)
+		b.addPeople([]string{track.Artist.Name, track.Composer, track.Conductor, track.Orchestra})
+		b.addFiles(files[track.ID], true)
+		builders = append(builders, b)
+	}
+	return builders, nil
+}
+
+func buildHomeVideos(db *gorm.DB, ids []string) ([]*docBuilder, error) {
+	var videos []database.HomeVideo
+	if err := db.Where("id IN ?", ids).Find(&videos).Error; err != nil {
+		return nil, fmt.Errorf("failed to load home videos: %w", err)
+	}
+	files, err := loadFiles(ownFiles(db, database.MediaTypeHomeVideo, ids))
+	if err != nil {
+		return nil, err
+	}
+
+	builders := make([]*docBuilder, 0, len(videos))
+	for _, video := range videos {
+		b := newDocBuilder(KindHomeVideo, video.ID, video.UpdatedAt)
+		b.setTitle(video.Title)
+		b.doc.Year = yearOf(video.RecordedAt)
+		b.add(video.Description, fieldOverview)
+		for _, tag := range listValues(video.Tags) {
+			b.add(tag, fieldKeyword)
+		}
+		b.addFiles(files[video.ID], true)
+		// Home videos belong to their library even before a file is linked
+		b.addLibrary(video.LibraryID)
+		builders = append(builders, b)
+	}
+	return builders, nil
+}
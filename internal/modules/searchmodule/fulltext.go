@@ -0,0 +1,174 @@
+package searchmodule
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// fullTextTable holds the text of every document, keyed by document ID
+const fullTextTable = "search_fts"
+
+// fullText keeps documents' text in the database's own full-text index:
+// FTS5 on SQLite, a weighted tsvector on Postgres
+type fullText interface {
+	// migrate creates the full-text table
+	migrate(db *gorm.DB) error
+	// put replaces the text of a document
+	put(tx *gorm.DB, docID uint32, fields [fieldCount]string) error
+	// remove drops the text of documents
+	remove(tx *gorm.DB, docIDs []uint32) error
+	// match selects document_id and relevance, higher is better, of the
+	// documents containing every term. The last term also matches longer
+	// terms starting with it.
+	match(db *gorm.DB, terms []string) *gorm.DB
+}
+
+// fullTextFor returns the full-text index of a database's dialect
+func fullTextFor(db *gorm.DB) fullText {
+	if db.Dialector.Name() == "postgres" {
+		return postgresFullText{}
+	}
+	return sqliteFullText{}
+}
+
+// Migrate creates the search index tables. The first migration to the
+// full-text table drops documents indexed without it, so the next sync
+// indexes them again.
+func Migrate(db *gorm.DB) error {
+	if err := db.AutoMigrate(&Document{}, &DocumentLibrary{}); err != nil {
+		return err
+	}
+
+	fts := fullTextFor(db)
+	if db.Migrator().HasTable(fullTextTable) {
+		return fts.migrate(db)
+	}
+	if err := fts.migrate(db); err != nil {
+		return err
+	}
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Migrator().DropTable("search_terms"); err != nil {
+			return err
+		}
+		if err := tx.Session(&gorm.Session{AllowGlobalUpdate: true}).Delete(&DocumentLibrary{}).Error; err != nil {
+			return err
+		}
+		return tx.Session(&gorm.Session{AllowGlobalUpdate: true}).Delete(&Document{}).Error
+	})
+}
+
+// prefixTerm reports whether a query's last term is matched as a prefix. A
+// single letter would match too much to be useful.
+func prefixTerm(terms []string, i int) bool {
+	return i == len(terms)-1 && len(terms[i]) > 1
+}
+
+// sqliteFullText is an FTS5 table with a column per field, ranked with its
+// bm25 function. SQLite needs FTS5 compiled in, which the go-sqlite3 driver
+// does with the sqlite_fts5 build tag.
+type sqliteFullText struct{}
+
+func (sqliteFullText) migrate(db *gorm.DB) error {
+	sql := "CREATE VIRTUAL TABLE IF NOT EXISTS " + fullTextTable + " USING fts5(" +
+		strings.Join(fieldColumns[:], ", ") + ", tokenize = 'unicode61', prefix = '2 3')"
+	if err := db.Exec(sql).Error; err != nil {
+		return fmt.Errorf("failed to create full-text table, SQLite must be built with FTS5 (-tags sqlite_fts5): %w", err)
+	}
+	return nil
+}
+
+func (sqliteFullText) put(tx *gorm.DB, docID uint32, fields [fieldCount]string) error {
+	if err := tx.Exec("DELETE FROM "+fullTextTable+" WHERE rowid = ?", docID).Error; err != nil {
+		return err
+	}
+	values := make([]interface{}, 0, len(fields)+1)
+	values = append(values, docID)
+	for _, text := range fields {
+		values = append(values, text)
+	}
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(values)), ", ")
+	return tx.Exec("INSERT INTO "+fullTextTable+" (rowid, "+strings.Join(fieldColumns[:], ", ")+") VALUES ("+placeholders+")",
+		values...).Error
+}
+
+func (sqliteFullText) remove(tx *gorm.DB, docIDs []uint32) error {
+	return tx.Exec("DELETE FROM "+fullTextTable+" WHERE rowid IN ?", docIDs).Error
+}
+
+func (sqliteFullText) match(db *gorm.DB, terms []string) *gorm.DB {
+	// Terms hold only letters and digits, so quoting them is enough to keep
+	// them from being read as query syntax
+	phrases := make([]string, len(terms))
+	for i, term := range terms {
+		phrases[i] = `"` + term + `"`
+		if prefixTerm(terms, i) {
+			phrases[i] += "*"
+		}
+	}
+
+	weights := make([]string, len(fieldWeights))
+	for i, weight := range fieldWeights {
+		weights[i] = strconv.FormatFloat(weight, 'f', -1, 64)
+	}
+	// bm25 is negative, lower for better matches
+	return db.Raw("SELECT rowid AS document_id, -bm25("+fullTextTable+", "+strings.Join(weights, ", ")+") AS relevance FROM "+
+		fullTextTable+" WHERE "+fullTextTable+" MATCH ?", strings.Join(phrases, " "))
+}
+
+// fieldClasses are the tsvector weight classes of each field. Postgres has
+// four, so fields of similar weight share one.
+var fieldClasses = [fieldCount]string{"A", "B", "C", "C", "C", "B", "D", "D"}
+
+// postgresFullText is a table of tsvectors weighting each field by its class,
+// ranked with ts_rank. The simple configuration neither stems nor drops
+// words, matching how queries are split.
+type postgresFullText struct{}
+
+func (postgresFullText) migrate(db *gorm.DB) error {
+	if err := db.Exec("CREATE TABLE IF NOT EXISTS " + fullTextTable + " (document_id bigint PRIMARY KEY, vector tsvector NOT NULL)").Error; err != nil {
+		return fmt.Errorf("failed to create full-text table: %w", err)
+	}
+	if err := db.Exec("CREATE INDEX IF NOT EXISTS idx_search_fts_vector ON " + fullTextTable + " USING GIN (vector)").Error; err != nil {
+		return fmt.Errorf("failed to create full-text index: %w", err)
+	}
+	return nil
+}
+
+func (postgresFullText) put(tx *gorm.DB, docID uint32, fields [fieldCount]string) error {
+	byClass := map[string][]string{}
+	for f, text := range fields {
+		if text != "" {
+			byClass[fieldClasses[f]] = append(byClass[fieldClasses[f]], text)
+		}
+	}
+	classes := []string{"A", "B", "C", "D"}
+	vectors := make([]string, len(classes))
+	values := []interface{}{docID}
+	for i, class := range classes {
+		vectors[i] = "setweight(to_tsvector('simple', ?), '" + class + "')"
+		values = append(values, strings.Join(byClass[class], " "))
+	}
+	return tx.Exec("INSERT INTO "+fullTextTable+" (document_id, vector) VALUES (?, "+strings.Join(vectors, " || ")+
+		") ON CONFLICT (document_id) DO UPDATE SET vector = EXCLUDED.vector", values...).Error
+}
+
+func (postgresFullText) remove(tx *gorm.DB, docIDs []uint32) error {
+	return tx.Exec("DELETE FROM "+fullTextTable+" WHERE document_id IN ?", docIDs).Error
+}
+
+func (postgresFullText) match(db *gorm.DB, terms []string) *gorm.DB {
+	// Terms hold only letters and digits, so they can't carry tsquery syntax
+	lexemes := make([]string, len(terms))
+	for i, term := range terms {
+		lexemes[i] = term
+		if prefixTerm(terms, i) {
+			lexemes[i] += ":*"
+		}
+	}
+	query := strings.Join(lexemes, " & ")
+	return db.Raw("SELECT document_id, ts_rank(vector, to_tsquery('simple', ?)) AS relevance FROM "+fullTextTable+
+		" WHERE vector @@ to_tsquery('simple', ?)", query, query)
+}
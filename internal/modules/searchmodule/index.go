@@ -0,0 +1,315 @@
+package searchmodule
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/mantonx/viewra/internal/database"
+	"gorm.io/gorm"
+)
+
+// Items indexed per transaction
+const indexBatchSize = 200
+
+// SyncResult counts the documents a sync changed
+type SyncResult struct {
+	Indexed    int   `json:"indexed"`
+	Removed    int   `json:"removed"`
+	DurationMs int64 `json:"duration_ms"`
+}
+
+// Status describes the index
+type Status struct {
+	Documents  map[string]int64 `json:"documents"`
+	Syncing    bool             `json:"syncing"`
+	LastSyncAt *time.Time       `json:"last_sync_at,omitempty"`
+	LastSync   *SyncResult      `json:"last_sync,omitempty"`
+}
+
+// Index keeps a search document for every movie, show, episode, artist,
+// album, track and home video. Syncs compare each item's updated_at with the
+// one it was indexed at, so only changed items are rebuilt.
+type Index struct {
+	db  *gorm.DB
+	fts fullText
+
+	// Only one sync or rebuild runs at a time
+	syncMu sync.Mutex
+
+	mu         sync.Mutex
+	syncing    bool
+	lastSyncAt *time.Time
+	lastSync   *SyncResult
+}
+
+// NewIndex creates an index over the library in db, whose tables Migrate
+// has created
+func NewIndex(db *gorm.DB) *Index {
+	return &Index{db: db, fts: fullTextFor(db)}
+}
+
+// Sync indexes new and changed items and drops the documents of removed
+// ones
+func (ix *Index) Sync() (*SyncResult, error) {
+	ix.syncMu.Lock()
+	defer ix.syncMu.Unlock()
+	ix.setSyncing(true)
+	defer ix.setSyncing(false)
+
+	start := time.Now()
+	result := &SyncResult{}
+	for i := range sources {
+		indexed, removed, err := ix.syncSource(&sources[i])
+		result.Indexed += indexed
+		result.Removed += removed
+		if err != nil {
+			return result, fmt.Errorf("failed to sync %s search documents: %w", sources[i].kind, err)
+		}
+	}
+	result.DurationMs = time.Since(start).Milliseconds()
+
+	ix.mu.Lock()
+	now := time.Now()
+	ix.lastSyncAt = &now
+	ix.lastSync = result
+	ix.mu.Unlock()
+	return result, nil
+}
+
+// Rebuild drops every document and indexes the library from scratch
+func (ix *Index) Rebuild() (*SyncResult, error) {
+	ix.syncMu.Lock()
+	err := ix.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("DELETE FROM " + fullTextTable).Error; err != nil {
+			return err
+		}
+		if err := tx.Session(&gorm.Session{AllowGlobalUpdate: true}).Delete(&DocumentLibrary{}).Error; err != nil {
+			return err
+		}
+		return tx.Session(&gorm.Session{AllowGlobalUpdate: true}).Delete(&Document{}).Error
+	})
+	ix.syncMu.Unlock()
+	if err != nil {
+		return nil, fmt.Errorf("failed to clear search index: %w", err)
+	}
+	return ix.Sync()
+}
+
+// syncSource brings the documents of one kind of item up to date
+func (ix *Index) syncSource(src *source) (indexed, removed int, err error) {
+	var items []struct {
+		ID        string
+		UpdatedAt time.Time
+	}
+	if err := ix.db.Table(src.table).Select("id, updated_at").Scan(&items).Error; err != nil {
+		return 0, 0, err
+	}
+	var docs []struct {
+		ID              uint32
+		EntityID        string
+		SourceUpdatedAt time.Time
+	}
+	if err := ix.db.Model(&Document{}).Select("id, entity_id, source_updated_at").
+		Where("kind = ?", src.kind).Scan(&docs).Error; err != nil {
+		return 0, 0, err
+	}
+
+	indexedAt := make(map[string]time.Time, len(docs))
+	for _, doc := range docs {
+		indexedAt[doc.EntityID] = doc.SourceUpdatedAt
+	}
+	current := make(map[string]bool, len(items))
+	var stale []string
+	for _, item := range items {
+		current[item.ID] = true
+		if at, ok := indexedAt[item.ID]; !ok || !at.Equal(item.UpdatedAt) {
+			stale = append(stale, item.ID)
+		}
+	}
+	var orphans []uint32
+	for _, doc := range docs {
+		if !current[doc.EntityID] {
+			orphans = append(orphans, doc.ID)
+		}
+	}
+
+	if len(orphans) > 0 {
+		if err := ix.db.Transaction(func(tx *gorm.DB) error {
+			return ix.deleteDocuments(tx, orphans)
+		}); err != nil {
+			return 0, 0, err
+		}
+	}
+	for start := 0; start < len(stale); start += indexBatchSize {
+		end := min(start+indexBatchSize, len(stale))
+		if err := ix.index(src, stale[start:end]); err != nil {
+			return indexed, len(orphans), err
+		}
+		indexed += end - start
+	}
+	return indexed, len(orphans), nil
+}
+
+// index rebuilds the documents of items, dropping those of items that no
+// longer exist
+func (ix *Index) index(src *source, ids []string) error {
+	builders, err := src.build(ix.db, ids)
+	if err != nil {
+		return err
+	}
+
+	return ix.db.Transaction(func(tx *gorm.DB) error {
+		var existing []Document
+		if err := tx.Select("id, entity_id").Where("kind = ? AND entity_id IN ?", src.kind, ids).
+			Find(&existing).Error; err != nil {
+			return err
+		}
+		existingIDs := make(map[string]uint32, len(existing))
+		for _, doc := range existing {
+			existingIDs[doc.EntityID] = doc.ID
+		}
+
+		now := time.Now()
+		built := make(map[string]bool, len(builders))
+		for _, b := range builders {
+			built[b.doc.EntityID] = true
+			b.doc.ID = existingIDs[b.doc.EntityID]
+			b.doc.IndexedAt = now
+			if b.doc.ID != 0 {
+				if err := tx.Where("document_id = ?", b.doc.ID).Delete(&DocumentLibrary{}).Error; err != nil {
+					return err
+				}
+			}
+			if err := tx.Save(&b.doc).Error; err != nil {
+				return err
+			}
+
+			if len(b.libraries) > 0 {
+				libraries := make([]DocumentLibrary, 0, len(b.libraries))
+				for libraryID := range b.libraries {
+					libraries = append(libraries, DocumentLibrary{DocumentID: b.doc.ID, LibraryID: libraryID})
+				}
+				if err := tx.Create(&libraries).Error; err != nil {
+					return err
+				}
+			}
+			if err := ix.fts.put(tx, b.doc.ID, b.fields()); err != nil {
+				return err
+			}
+		}
+
+		var gone []uint32
+		for entityID, docID := range existingIDs {
+			if !built[entityID] {
+				gone = append(gone, docID)
+			}
+		}
+		return ix.deleteDocuments(tx, gone)
+	})
+}
+
+// IndexMediaFile reindexes the items a media file belongs to: its movie,
+// episode and show, track with its album and artist, or home video. Called
+// as enrichment changes their metadata.
+func (ix *Index) IndexMediaFile(mediaFileID string) error {
+	var file database.MediaFile
+	if err := ix.db.Select("id, media_id, media_type").Where("id = ?", mediaFileID).First(&file).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return fmt.Errorf("failed to load media file: %w", err)
+	}
+	if file.MediaID == "" {
+		return nil
+	}
+
+	items := map[string]string{}
+	switch file.MediaType {
+	case database.MediaTypeMovie:
+		items[KindMovie] = file.MediaID
+	case database.MediaTypeHomeVideo:
+		items[KindHomeVideo] = file.MediaID
+	case database.MediaTypeEpisode:
+		items[KindEpisode] = file.MediaID
+		var showIDs []string
+		if err := ix.db.Table("episodes").Select("seasons.tv_show_id").
+			Joins("JOIN seasons ON seasons.id = episodes.season_id").
+			Where("episodes.id = ?", file.MediaID).Pluck("seasons.tv_show_id", &showIDs).Error; err != nil {
+			return fmt.Errorf("failed to load episode show: %w", err)
+		}
+		if len(showIDs) > 0 {
+			items[KindTVShow] = showIDs[0]
+		}
+	case database.MediaTypeTrack:
+		items[KindTrack] = file.MediaID
+		var track database.Track
+		if err := ix.db.Select("id, album_id, artist_id").Where("id = ?", file.MediaID).Limit(1).Find(&track).Error; err != nil {
+			return fmt.Errorf("failed to load track: %w", err)
+		}
+		if track.AlbumID != "" {
+			items[KindAlbum] = track.AlbumID
+		}
+		if track.ArtistID != "" {
+			items[KindArtist] = track.ArtistID
+		}
+	default:
+		return nil
+	}
+
+	ix.syncMu.Lock()
+	defer ix.syncMu.Unlock()
+	for kind, id := range items {
+		src, _ := sourceFor(kind)
+		if err := ix.index(src, []string{id}); err != nil {
+			return fmt.Errorf("failed to index %s %s: %w", kind, id, err)
+		}
+	}
+	return nil
+}
+
+// Status counts the index's documents by kind
+func (ix *Index) Status() (*Status, error) {
+	var counts []struct {
+		Kind  string
+		Count int64
+	}
+	if err := ix.db.Model(&Document{}).Select("kind, COUNT(*) AS count").Group("kind").Scan(&counts).Error; err != nil {
+		return nil, fmt.Errorf("failed to count search documents: %w", err)
+	}
+	status := &Status{Documents: make(map[string]int64, len(sources))}
+	for _, src := range sources {
+		status.Documents[src.kind] = 0
+	}
+	for _, count := range counts {
+		status.Documents[count.Kind] = count.Count
+	}
+
+	ix.mu.Lock()
+	status.Syncing = ix.syncing
+	status.LastSyncAt = ix.lastSyncAt
+	status.LastSync = ix.lastSync
+	ix.mu.Unlock()
+	return status, nil
+}
+
+func (ix *Index) setSyncing(syncing bool) {
+	ix.mu.Lock()
+	ix.syncing = syncing
+	ix.mu.Unlock()
+}
+
+// deleteDocuments removes documents with their text and libraries
+func (ix *Index) deleteDocuments(tx *gorm.DB, ids []uint32) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	if err := ix.fts.remove(tx, ids); err != nil {
+		return err
+	}
+	if err := tx.Where("document_id IN ?", ids).Delete(&DocumentLibrary{}).Error; err != nil {
+		return err
+	}
+	return tx.Where("id IN ?", ids).Delete(&Document{}).Error
+}
@@ -0,0 +1,279 @@
+// Package searchmodule indexes the library for full-text search. Titles,
+// overviews, cast, genres, keywords and file paths of movies, shows,
+// episodes, artists, albums, tracks and home videos are kept in the
+// database's full-text index (FTS5 on SQLite, tsvector on Postgres), brought
+// up to date as scans finish and enrichment changes metadata, and searched
+// through /api/search.
+package searchmodule
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mantonx/viewra/internal/database"
+	"github.com/mantonx/viewra/internal/events"
+	"github.com/mantonx/viewra/internal/modules/modulemanager"
+	"github.com/mantonx/viewra/internal/parental"
+	"gorm.io/gorm"
+)
+
+// Auto-register the module when imported
+func init() {
+	Register()
+}
+
+const (
+	ModuleID   = "system.search"
+	ModuleName = "Search"
+)
+
+// Results per page by default, and at most
+const (
+	defaultSearchLimit = 20
+	maxSearchLimit     = 100
+)
+
+// Module keeps the search index and serves searches
+type Module struct {
+	id      string
+	name    string
+	version string
+	core    bool
+	db      *gorm.DB
+	index   *Index
+}
+
+// Register registers this module with the module system
+func Register() {
+	searchModule := &Module{
+		id:      ModuleID,
+		name:    ModuleName,
+		version: "1.0.0",
+		core:    true,
+	}
+	modulemanager.Register(searchModule)
+}
+
+// ID returns the module ID
+func (m *Module) ID() string {
+	return m.id
+}
+
+// Name returns the module name
+func (m *Module) Name() string {
+	return m.name
+}
+
+// Core returns whether this is a core module
+func (m *Module) Core() bool {
+	return m.core
+}
+
+// Migrate creates the search index tables. A database without full-text
+// support leaves search unavailable rather than stopping the server.
+func (m *Module) Migrate(db *gorm.DB) error {
+	if err := Migrate(db); err != nil {
+		log.Printf("ERROR: Search index unavailable: %v", err)
+	}
+	return nil
+}
+
+// Init initializes the module, brings the index up to date in the
+// background and keeps it there as the library changes
+func (m *Module) Init() error {
+	m.db = database.GetDB()
+	m.index = NewIndex(m.db)
+
+	if eventBus := events.GetGlobalEventBus(); eventBus != nil {
+		fileEvents := events.EventFilter{Types: []events.EventType{
+			events.EventEnrichmentApplied,
+			events.EventMediaFileOrganized,
+			events.EventMediaFileRestored,
+		}}
+		if _, err := eventBus.Subscribe(context.Background(), fileEvents, m.onMediaFileChanged); err != nil {
+			log.Printf("WARN: Failed to subscribe search index to enrichment events: %v", err)
+		}
+		libraryEvents := events.EventFilter{Types: []events.EventType{
+			events.EventScanCompleted,
+			events.EventMediaFileDeleted,
+		}}
+		if _, err := eventBus.Subscribe(context.Background(), libraryEvents, m.onLibraryChanged); err != nil {
+			log.Printf("WARN: Failed to subscribe search index to scan events: %v", err)
+		}
+	}
+
+	go m.sync("startup")
+
+	log.Println("Search module initialized")
+	return nil
+}
+
+// Index returns the module's search index
+func (m *Module) Index() *Index {
+	return m.index
+}
+
+// sync brings the index up to date, logging what changed
+func (m *Module) sync(reason string) {
+	result, err := m.index.Sync()
+	if err != nil {
+		log.Printf("ERROR: Search index sync after %s failed: %v", reason, err)
+		return
+	}
+	if result.Indexed > 0 || result.Removed > 0 {
+		log.Printf("INFO: Search index synced after %s: %d indexed, %d removed in %dms",
+			reason, result.Indexed, result.Removed, result.DurationMs)
+	}
+}
+
+// onMediaFileChanged reindexes the items of a file whose metadata or path
+// changed
+func (m *Module) onMediaFileChanged(event events.Event) error {
+	mediaFileID, _ := event.Data["media_file_id"].(string)
+	if mediaFileID == "" {
+		return nil
+	}
+
+	go func() {
+		if err := m.index.IndexMediaFile(mediaFileID); err != nil {
+			log.Printf("WARN: Failed to update search index for media file %s: %v", mediaFileID, err)
+		}
+	}()
+	return nil
+}
+
+// onLibraryChanged syncs the index once a scan has added items or files
+// were deleted
+func (m *Module) onLibraryChanged(event events.Event) error {
+	go m.sync(string(event.Type))
+	return nil
+}
+
+// RegisterRoutes registers the search endpoint and the index's admin
+// endpoints
+func (m *Module) RegisterRoutes(router *gin.Engine) {
+	router.GET("/api/search", m.search)
+
+	admin := router.Group("/api/admin/search")
+	{
+		admin.GET("/status", m.getStatus)
+		admin.POST("/sync", m.startSync)
+		admin.POST("/rebuild", m.startRebuild)
+	}
+}
+
+// search searches the library. For the user in user_id, items they have
+// hidden or may not see are left out.
+func (m *Module) search(c *gin.Context) {
+	query := Query{Text: strings.TrimSpace(c.Query("q"))}
+	if query.Text == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "q is required"})
+		return
+	}
+
+	if types := c.Query("type"); types != "" {
+		for _, kind := range strings.Split(types, ",") {
+			kind = strings.TrimSpace(kind)
+			if _, ok := sourceFor(kind); !ok {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Unknown type: " + kind})
+				return
+			}
+			query.Kinds = append(query.Kinds, kind)
+		}
+	}
+	query.Genre = c.Query("genre")
+
+	numbers := []struct {
+		name  string
+		value *int
+		def   int
+	}{
+		{"limit", &query.Limit, defaultSearchLimit},
+		{"offset", &query.Offset, 0},
+		{"year_from", &query.YearFrom, 0},
+		{"year_to", &query.YearTo, 0},
+	}
+	for _, number := range numbers {
+		*number.value = number.def
+		if raw := c.Query(number.name); raw != "" {
+			value, err := strconv.Atoi(raw)
+			if err != nil || value < 0 {
+				c.JSON(http.StatusBadRequest, gin.H{"error": number.name + " must be a non-negative number"})
+				return
+			}
+			*number.value = value
+		}
+	}
+	if query.Limit == 0 || query.Limit > maxSearchLimit {
+		query.Limit = maxSearchLimit
+	}
+
+	if raw := c.Query("library_id"); raw != "" {
+		libraryID, err := strconv.ParseUint(raw, 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid library ID"})
+			return
+		}
+		query.LibraryID = uint32(libraryID)
+	}
+
+	if raw := c.Query("user_id"); raw != "" {
+		userID, err := strconv.ParseUint(raw, 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+			return
+		}
+		visibility := &Visibility{UserID: uint32(userID)}
+		limit, err := parental.ForUser(m.db, visibility.UserID, time.Now())
+		if err == nil && limit != nil {
+			visibility.Rated, err = limit.Denied(m.db)
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check parental controls: " + err.Error()})
+			return
+		}
+		query.Visibility = visibility
+	}
+
+	results, err := m.index.Search(query)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, results)
+}
+
+// getStatus describes the search index
+func (m *Module) getStatus(c *gin.Context) {
+	status, err := m.index.Status()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, status)
+}
+
+// startSync brings the index up to date in the background
+func (m *Module) startSync(c *gin.Context) {
+	go m.sync("request")
+	c.JSON(http.StatusAccepted, gin.H{"message": "Search index sync started"})
+}
+
+// startRebuild rebuilds the index from scratch in the background. Searches
+// find less until it finishes.
+func (m *Module) startRebuild(c *gin.Context) {
+	go func() {
+		result, err := m.index.Rebuild()
+		if err != nil {
+			log.Printf("ERROR: Search index rebuild failed: %v", err)
+			return
+		}
+		log.Printf("INFO: Search index rebuilt: %d documents in %dms", result.Indexed, result.DurationMs)
+	}()
+	c.JSON(http.StatusAccepted, gin.H{"message": "Search index rebuild started"})
+}
@@ -0,0 +1,177 @@
+package searchmodule
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/mantonx/viewra/internal/auth"
+	"github.com/mantonx/viewra/internal/database"
+	"github.com/mantonx/viewra/internal/parental"
+	"gorm.io/gorm"
+)
+
+// Whole title matches rank above titles that only start with the query,
+// which rank above other matches
+const (
+	titleMatchBoost  = 2.0
+	titlePrefixBoost = 1.3
+)
+
+// Query is a search with its filters
+type Query struct {
+	Text      string
+	Kinds     []string
+	LibraryID uint32
+	Genre     string
+	YearFrom  int
+	YearTo    int
+	Limit     int
+	Offset    int
+
+	// Visibility applies a user's hidden items and libraries, library access
+	// and parental controls. nil searches everything.
+	Visibility *Visibility
+}
+
+// Visibility hides what a user may not or doesn't want to see
+type Visibility struct {
+	UserID uint32
+	Rated  *parental.Denied // nil without parental controls
+}
+
+// Result is a document matching a search
+type Result struct {
+	Document
+	Score float64 `json:"score"`
+}
+
+// Results is a page of search results
+type Results struct {
+	Query   string         `json:"query"`
+	Total   int            `json:"total"`
+	Kinds   map[string]int `json:"kinds"` // Matches of each kind, before the kind filter
+	Limit   int            `json:"limit"`
+	Offset  int            `json:"offset"`
+	Results []Result       `json:"results"`
+}
+
+// Search ranks the documents containing every term of the query by the
+// full-text index's relevance, which weights each field by how much it says
+// about an item. The last term also matches longer terms starting with it,
+// so results appear while a word is still being typed. Filtering, counting
+// and paging all happen in the database.
+func (ix *Index) Search(q Query) (*Results, error) {
+	results := &Results{Query: q.Text, Kinds: map[string]int{}, Limit: q.Limit, Offset: q.Offset, Results: []Result{}}
+	terms := queryTerms(q.Text)
+	if len(terms) == 0 {
+		return results, nil
+	}
+
+	matches := func() *gorm.DB {
+		query := ix.db.Table("search_documents").
+			Joins("JOIN (?) AS matches ON matches.document_id = search_documents.id", ix.fts.match(ix.db, terms))
+		return ix.filter(query, q)
+	}
+
+	var counts []struct {
+		Kind  string
+		Count int
+	}
+	if err := matches().Select("search_documents.kind, COUNT(*) AS count").
+		Group("search_documents.kind").Scan(&counts).Error; err != nil {
+		return nil, fmt.Errorf("failed to search index: %w", err)
+	}
+	kinds := make(map[string]bool, len(q.Kinds))
+	for _, kind := range q.Kinds {
+		kinds[kind] = true
+	}
+	for _, count := range counts {
+		results.Kinds[count.Kind] = count.Count
+		if len(kinds) == 0 || kinds[count.Kind] {
+			results.Total += count.Count
+		}
+	}
+	if results.Total == 0 || q.Offset >= results.Total {
+		return results, nil
+	}
+
+	// Titles are compared by their terms, so "matrix" is the whole of "The
+	// Matrix"
+	wanted := strings.Join(terms, " ")
+	page := matches().Select("search_documents.*, matches.relevance * CASE "+
+		"WHEN search_documents.title_key = ? THEN ? "+
+		"WHEN search_documents.title_key LIKE ? THEN ? "+
+		"ELSE 1 END AS score", wanted, titleMatchBoost, wanted+"%", titlePrefixBoost)
+	if len(q.Kinds) > 0 {
+		page = page.Where("search_documents.kind IN ?", q.Kinds)
+	}
+	if err := page.Order("score DESC, search_documents.title, search_documents.id").
+		Limit(q.Limit).Offset(q.Offset).Scan(&results.Results).Error; err != nil {
+		return nil, fmt.Errorf("failed to load search results: %w", err)
+	}
+
+	docIDs := make([]uint32, len(results.Results))
+	for i := range results.Results {
+		docIDs[i] = results.Results[i].ID
+		results.Results[i].Score = math.Round(results.Results[i].Score*1000) / 1000
+	}
+	var libraries []DocumentLibrary
+	if err := ix.db.Where("document_id IN ?", docIDs).Order("library_id").Find(&libraries).Error; err != nil {
+		return nil, fmt.Errorf("failed to load search result libraries: %w", err)
+	}
+	byDocument := make(map[uint32][]uint32, len(docIDs))
+	for _, library := range libraries {
+		byDocument[library.DocumentID] = append(byDocument[library.DocumentID], library.LibraryID)
+	}
+	for i := range results.Results {
+		results.Results[i].LibraryIDs = byDocument[results.Results[i].ID]
+	}
+	return results, nil
+}
+
+// filter applies the query's filters other than kind
+func (ix *Index) filter(query *gorm.DB, q Query) *gorm.DB {
+	if q.LibraryID != 0 {
+		query = query.Where("search_documents.id IN (?)",
+			ix.db.Model(&DocumentLibrary{}).Select("document_id").Where("library_id = ?", q.LibraryID))
+	}
+	if q.Genre != "" {
+		query = query.Where("search_documents.genres LIKE ?", "%|"+normalize(strings.TrimSpace(q.Genre))+"|%")
+	}
+	if q.YearFrom != 0 {
+		query = query.Where("search_documents.year >= ?", q.YearFrom)
+	}
+	if q.YearTo != 0 {
+		query = query.Where("search_documents.year > 0 AND search_documents.year <= ?", q.YearTo)
+	}
+	if q.Visibility != nil {
+		query = q.Visibility.apply(ix.db, query)
+	}
+	return query
+}
+
+// apply removes the documents of items the user has hidden, directly or
+// through their show or album, of movies and shows rated above their
+// parental controls and of items only in libraries they have hidden or may
+// not see. Items in no library, such as an artist without tracks, stay.
+func (v *Visibility) apply(db *gorm.DB, query *gorm.DB) *gorm.DB {
+	hiddenItems := db.Model(&database.UserHiddenItem{}).Select("media_id").Where("user_id = ?", v.UserID)
+	hiddenLibraries := db.Model(&database.UserHiddenLibrary{}).Select("library_id").Where("user_id = ?", v.UserID)
+	inLibrary := db.Model(&DocumentLibrary{}).Select("document_id")
+	inVisibleLibrary := db.Model(&DocumentLibrary{}).Select("document_id").
+		Where("library_id NOT IN (?)", hiddenLibraries).
+		Where("library_id NOT IN (?)", auth.DeniedLibraries(db, v.UserID))
+
+	query = query.
+		Where("search_documents.entity_id NOT IN (?)", hiddenItems).
+		Where("COALESCE(search_documents.parent_id, '') NOT IN (?)", hiddenItems).
+		Where("(search_documents.id NOT IN (?) OR search_documents.id IN (?))", inLibrary, inVisibleLibrary)
+	if v.Rated != nil {
+		query = query.
+			Where("NOT (search_documents.kind = ? AND search_documents.entity_id IN (?))", KindMovie, v.Rated.Movies(db)).
+			Where("NOT (search_documents.kind = ? AND search_documents.entity_id IN (?))", KindTVShow, v.Rated.Shows(db)).
+			Where("NOT (search_documents.kind = ? AND search_documents.parent_id IN (?))", KindEpisode, v.Rated.Shows(db))
+	}
+	return query
+}
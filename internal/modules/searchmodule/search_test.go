@@ -0,0 +1,196 @@
+package searchmodule
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mantonx/viewra/internal/auth"
+	"github.com/mantonx/viewra/internal/database"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// setupSearchTest indexes a small library: movies in a public library 1 and
+// a private library 2, one of them with files in both, and a show with an
+// episode. SQLite must have FTS5, which go-sqlite3 compiles in with the
+// sqlite_fts5 build tag.
+func setupSearchTest(t *testing.T) (*gorm.DB, *Index) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	require.NoError(t, err)
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	sqlDB.SetMaxOpenConns(1)
+	require.NoError(t, db.AutoMigrate(&database.MediaLibrary{}, &database.MediaFile{}, &database.Movie{},
+		&database.TVShow{}, &database.Season{}, &database.Episode{}, &database.Artist{}, &database.Album{},
+		&database.Track{}, &database.HomeVideo{}, &database.People{}, &database.Roles{},
+		&database.User{}, &database.UserLibraryAccess{}, &database.UserHiddenItem{}, &database.UserHiddenLibrary{}))
+	if err := Migrate(db); err != nil {
+		t.Skipf("full-text search unavailable, run with -tags sqlite_fts5: %v", err)
+	}
+
+	date := func(year int) *time.Time {
+		d := time.Date(year, 1, 1, 0, 0, 0, 0, time.UTC)
+		return &d
+	}
+	require.NoError(t, db.Create(&[]database.MediaLibrary{
+		{ID: 1, Path: "/media/movies", Type: "movie"},
+		{ID: 2, Path: "/media/private", Type: "movie"},
+	}).Error)
+	require.NoError(t, db.Create(&[]database.Movie{
+		{ID: "matrix", Title: "The Matrix", ReleaseDate: date(1999), Genres: `["Action", "Science Fiction"]`,
+			Overview: "A hacker learns the truth about his reality."},
+		{ID: "reloaded", Title: "The Matrix Reloaded", ReleaseDate: date(2003), Genres: `["Action"]`,
+			Overview: "Neo and the rebels fight on."},
+		{ID: "hackers", Title: "Hackers", ReleaseDate: date(1995), Genres: `["Crime"]`,
+			Overview: "Teenagers break into the matrix of a corporation."},
+		{ID: "amelie", Title: "Amélie", ReleaseDate: date(2001), Genres: `["Comedy"]`},
+		{ID: "shared", Title: "Shared Film", ReleaseDate: date(2010)},
+	}).Error)
+	require.NoError(t, db.Create(&[]database.MediaFile{
+		{ID: "file-matrix", MediaID: "matrix", MediaType: database.MediaTypeMovie, LibraryID: 1, Path: "/media/movies/The Matrix.mkv"},
+		{ID: "file-reloaded", MediaID: "reloaded", MediaType: database.MediaTypeMovie, LibraryID: 1, Path: "/media/movies/Reloaded.mkv"},
+		{ID: "file-hackers", MediaID: "hackers", MediaType: database.MediaTypeMovie, LibraryID: 1, Path: "/media/movies/Hackers.mkv"},
+		{ID: "file-amelie", MediaID: "amelie", MediaType: database.MediaTypeMovie, LibraryID: 2, Path: "/media/private/Amelie.mkv"},
+		{ID: "file-shared-1", MediaID: "shared", MediaType: database.MediaTypeMovie, LibraryID: 1, Path: "/media/movies/Shared.mkv"},
+		{ID: "file-shared-2", MediaID: "shared", MediaType: database.MediaTypeMovie, LibraryID: 2, Path: "/media/private/Shared.mkv"},
+		{ID: "file-episode", MediaID: "pilot", MediaType: database.MediaTypeEpisode, LibraryID: 2, Path: "/media/private/Show/S01E01.mkv"},
+	}).Error)
+	require.NoError(t, db.Create(&database.TVShow{ID: "show", Title: "Matrix Stories", FirstAirDate: date(2005)}).Error)
+	require.NoError(t, db.Create(&database.Season{ID: "season", TVShowID: "show", SeasonNumber: 1}).Error)
+	require.NoError(t, db.Create(&database.Episode{ID: "pilot", SeasonID: "season", Title: "Pilot", EpisodeNumber: 1}).Error)
+
+	ix := NewIndex(db)
+	result, err := ix.Sync()
+	require.NoError(t, err)
+	require.Equal(t, 7, result.Indexed)
+	return db, ix
+}
+
+// resultIDs lists the entity IDs of results in order
+func resultIDs(results *Results) []string {
+	ids := make([]string, len(results.Results))
+	for i, result := range results.Results {
+		ids[i] = result.EntityID
+	}
+	return ids
+}
+
+func TestSearchRanking(t *testing.T) {
+	_, ix := setupSearchTest(t)
+
+	tests := []struct {
+		name  string
+		query string
+		want  []string
+	}{
+		{"whole title first, then title prefix, then other fields, then overview", "matrix",
+			[]string{"matrix", "show", "reloaded", "pilot", "hackers"}},
+		{"every term must match", "matrix reloaded", []string{"reloaded"}},
+		{"last term matches as a prefix", "matrix reloa", []string{"reloaded"}},
+		{"earlier terms don't match as a prefix", "matr reloaded", nil},
+		{"accents are folded", "amelie", []string{"amelie"}},
+		{"genres are searchable", "science fiction", []string{"matrix"}},
+		{"paths are searchable", "s01e01", []string{"pilot"}},
+		{"query syntax is ignored", `matrix" OR "amelie`, nil},
+		{"no match", "nothing", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results, err := ix.Search(Query{Text: tt.query, Limit: 20})
+			require.NoError(t, err)
+			require.Equal(t, len(tt.want), results.Total)
+			if tt.want == nil {
+				tt.want = []string{}
+			}
+			require.Equal(t, tt.want, resultIDs(results))
+		})
+	}
+}
+
+func TestSearchFilters(t *testing.T) {
+	db, ix := setupSearchTest(t)
+
+	require.NoError(t, db.Create(&[]database.User{
+		{ID: 1, Username: "user", Email: "user@example.com", Role: database.UserRoleUser},
+		{ID: 2, Username: "kid", Email: "kid@example.com", Role: database.UserRoleUser, RestrictLibraries: true},
+	}).Error)
+	require.NoError(t, auth.SetGrantedLibraries(db, 2, []uint32{1}))
+	require.NoError(t, db.Create(&database.UserHiddenItem{UserID: 1, MediaID: "show", HiddenAt: time.Now()}).Error)
+
+	tests := []struct {
+		name      string
+		query     Query
+		want      []string
+		wantTotal int
+		wantKinds map[string]int
+	}{
+		{"kind", Query{Text: "matrix", Kinds: []string{KindTVShow, KindEpisode}},
+			[]string{"show", "pilot"}, 2, map[string]int{KindMovie: 3, KindTVShow: 1, KindEpisode: 1}},
+		{"genre", Query{Text: "matrix", Genre: "Action"}, []string{"matrix", "reloaded"}, 2, map[string]int{KindMovie: 2}},
+		{"year range", Query{Text: "matrix", YearFrom: 1996, YearTo: 2004}, []string{"matrix", "reloaded"}, 2, map[string]int{KindMovie: 2}},
+		{"library", Query{Text: "film", LibraryID: 2}, []string{"shared"}, 1, map[string]int{KindMovie: 1}},
+		{"page", Query{Text: "matrix", Offset: 1, Limit: 2}, []string{"show", "reloaded"}, 5,
+			map[string]int{KindMovie: 3, KindTVShow: 1, KindEpisode: 1}},
+		{"past the last page", Query{Text: "matrix", Offset: 5, Limit: 2}, []string{}, 5,
+			map[string]int{KindMovie: 3, KindTVShow: 1, KindEpisode: 1}},
+		{"hidden show and its episodes", Query{Text: "matrix", Visibility: &Visibility{UserID: 1}},
+			[]string{"matrix", "reloaded", "hackers"}, 3, map[string]int{KindMovie: 3}},
+		{"denied library", Query{Text: "amelie", Visibility: &Visibility{UserID: 2}}, []string{}, 0, map[string]int{}},
+		{"item also in a granted library", Query{Text: "shared", Visibility: &Visibility{UserID: 2}},
+			[]string{"shared"}, 1, map[string]int{KindMovie: 1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.query.Limit == 0 {
+				tt.query.Limit = 20
+			}
+			results, err := ix.Search(tt.query)
+			require.NoError(t, err)
+			require.Equal(t, tt.want, resultIDs(results))
+			require.Equal(t, tt.wantTotal, results.Total)
+			require.Equal(t, tt.wantKinds, results.Kinds)
+		})
+	}
+}
+
+func TestSearchLibraries(t *testing.T) {
+	_, ix := setupSearchTest(t)
+
+	results, err := ix.Search(Query{Text: "shared", Limit: 20})
+	require.NoError(t, err)
+	require.Len(t, results.Results, 1)
+	require.Equal(t, []uint32{1, 2}, results.Results[0].LibraryIDs)
+}
+
+func TestSync(t *testing.T) {
+	db, ix := setupSearchTest(t)
+
+	result, err := ix.Sync()
+	require.NoError(t, err)
+	require.Zero(t, result.Indexed, "unchanged items are indexed again")
+	require.Zero(t, result.Removed)
+
+	require.NoError(t, db.Model(&database.Movie{}).Where("id = ?", "hackers").
+		Updates(map[string]interface{}{"title": "Hackers Returned", "updated_at": time.Now().Add(time.Minute)}).Error)
+	require.NoError(t, db.Delete(&database.Movie{}, "id = ?", "amelie").Error)
+	result, err = ix.Sync()
+	require.NoError(t, err)
+	require.Equal(t, 1, result.Indexed)
+	require.Equal(t, 1, result.Removed)
+
+	results, err := ix.Search(Query{Text: "returned", Limit: 20})
+	require.NoError(t, err)
+	require.Equal(t, []string{"hackers"}, resultIDs(results))
+	results, err = ix.Search(Query{Text: "amelie", Limit: 20})
+	require.NoError(t, err)
+	require.Empty(t, results.Results)
+
+	result, err = ix.Rebuild()
+	require.NoError(t, err)
+	require.Equal(t, 6, result.Indexed)
+	results, err = ix.Search(Query{Text: "matrix", Limit: 20})
+	require.NoError(t, err)
+	require.Equal(t, 5, results.Total)
+}
@@ -0,0 +1,130 @@
+package searchmodule
+
+import (
+	"encoding/json"
+	"strings"
+	"unicode"
+)
+
+// Longest term indexed; longer runs of letters are cut to this
+const maxTermLength = 64
+
+// stopwords are skipped unless a query has nothing else
+var stopwords = map[string]bool{
+	"a": true, "an": true, "and": true, "at": true, "by": true, "for": true,
+	"in": true, "is": true, "it": true, "of": true, "on": true, "or": true,
+	"the": true, "to": true, "with": true,
+}
+
+// accentFolds maps accented Latin letters to their plain forms, so "amelie"
+// finds "Amélie"
+var accentFolds = map[rune]string{
+	'à': "a", 'á': "a", 'â': "a", 'ã': "a", 'ä': "a", 'å': "a", 'æ': "ae",
+	'ç': "c", 'è': "e", 'é': "e", 'ê': "e", 'ë': "e",
+	'ì': "i", 'í': "i", 'î': "i", 'ï': "i", 'ñ': "n",
+	'ò': "o", 'ó': "o", 'ô': "o", 'õ': "o", 'ö': "o", 'ø': "o", 'œ': "oe",
+	'ù': "u", 'ú': "u", 'û': "u", 'ü': "u", 'ý': "y", 'ÿ': "y", 'ß': "ss",
+}
+
+// normalize lowercases text and folds accents
+func normalize(text string) string {
+	var b strings.Builder
+	b.Grow(len(text))
+	for _, r := range strings.ToLower(text) {
+		if fold, ok := accentFolds[r]; ok {
+			b.WriteString(fold)
+		} else {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// tokenize splits text into normalized terms, dropping stopwords. Apostrophes
+// join rather than split, so "Ocean's" is one term.
+func tokenize(text string) []string {
+	return splitTerms(text, true)
+}
+
+// queryTerms splits a query into distinct terms, keeping stopwords when the
+// query has nothing else
+func queryTerms(query string) []string {
+	terms := splitTerms(query, true)
+	if len(terms) == 0 {
+		terms = splitTerms(query, false)
+	}
+
+	seen := make(map[string]bool, len(terms))
+	distinct := terms[:0]
+	for _, term := range terms {
+		if !seen[term] {
+			seen[term] = true
+			distinct = append(distinct, term)
+		}
+	}
+	return distinct
+}
+
+func splitTerms(text string, dropStopwords bool) []string {
+	var terms []string
+	var current strings.Builder
+	flush := func() {
+		term := current.String()
+		current.Reset()
+		if term == "" || (dropStopwords && stopwords[term]) {
+			return
+		}
+		if len(term) > maxTermLength {
+			term = term[:maxTermLength]
+		}
+		terms = append(terms, term)
+	}
+
+	for _, r := range normalize(text) {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			current.WriteRune(r)
+		case r == '\'' || r == '’':
+		default:
+			flush()
+		}
+	}
+	flush()
+	return terms
+}
+
+// listValues reads a list stored as text: a JSON array of strings or of
+// objects with a name, or a comma-separated list
+func listValues(value string) []string {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil
+	}
+
+	if strings.HasPrefix(value, "[") {
+		var names []string
+		if err := json.Unmarshal([]byte(value), &names); err == nil {
+			return names
+		}
+		var objects []struct {
+			Name string `json:"name"`
+		}
+		if err := json.Unmarshal([]byte(value), &objects); err == nil {
+			names = make([]string, 0, len(objects))
+			for _, object := range objects {
+				if object.Name != "" {
+					names = append(names, object.Name)
+				}
+			}
+			return names
+		}
+	}
+
+	var names []string
+	for _, name := range strings.Split(value, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
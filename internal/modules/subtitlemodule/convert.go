@@ -0,0 +1,120 @@
+package subtitlemodule
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/mantonx/viewra/internal/database"
+	"github.com/mantonx/viewra/internal/modules/assetmodule"
+)
+
+// Subtitle conversion methods
+const (
+	MethodWebVTT = "webvtt"  // Text track converted to WebVTT
+	MethodBurnIn = "burn_in" // Image-based track that must be rendered into the video
+)
+
+// Subtitle styles for WebVTT conversion
+const (
+	StyleStyled = "styled" // Keep bold/italic/underline; ASS positioning and effects are dropped
+	StylePlain  = "plain"  // Strip all markup, for clients with poor cue rendering
+)
+
+// conversionTimeout bounds a single track extraction; the whole file is read
+const conversionTimeout = 5 * time.Minute
+
+// imageCodecs are bitmap formats that browsers can't render as text. There
+// is no OCR, so they are never converted to WebVTT.
+var imageCodecs = map[string]bool{
+	"hdmv_pgs_subtitle": true,
+	"pgssub":            true,
+	"dvd_subtitle":      true,
+	"dvdsub":            true,
+	"dvb_subtitle":      true,
+	"dvbsub":            true,
+	"xsub":              true,
+}
+
+// vttTagPattern matches WebVTT cue markup such as <i>, </b> or <c.yellow>
+var vttTagPattern = regexp.MustCompile(`</?[a-zA-Z][^>]*>`)
+
+// Method returns how a subtitle codec can be delivered to a browser
+func Method(codec string) string {
+	if imageCodecs[strings.ToLower(codec)] {
+		return MethodBurnIn
+	}
+	return MethodWebVTT
+}
+
+// EmbeddedVariant is the asset variant a media file's embedded subtitle
+// track is stored under once converted
+func EmbeddedVariant(mediaFileID string, trackIndex int, style string) string {
+	return fmt.Sprintf("%s/s%d/%s", mediaFileID, trackIndex, style)
+}
+
+// AssetEntity maps a media file to the asset entity its subtitles are stored
+// under. Only movie and episode files have one.
+func AssetEntity(mediaFile *database.MediaFile) (assetmodule.EntityType, uuid.UUID, bool) {
+	entityID, err := uuid.Parse(mediaFile.MediaID)
+	if err != nil {
+		return "", uuid.Nil, false
+	}
+
+	switch mediaFile.MediaType {
+	case database.MediaTypeMovie:
+		return assetmodule.EntityTypeMovie, entityID, true
+	case database.MediaTypeEpisode:
+		return assetmodule.EntityTypeEpisode, entityID, true
+	default:
+		return "", uuid.Nil, false
+	}
+}
+
+// ConvertToWebVTT extracts a text subtitle track with FFmpeg's WebVTT muxer.
+// trackIndex counts subtitle streams, as in 0:s:N; a sidecar file's only
+// track is 0.
+func ConvertToWebVTT(ctx context.Context, inputPath string, trackIndex int) ([]byte, error) {
+	ffmpegPath, err := exec.LookPath("ffmpeg")
+	if err != nil {
+		return nil, fmt.Errorf("ffmpeg not found in PATH")
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, conversionTimeout)
+	defer cancel()
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, ffmpegPath,
+		"-hide_banner", "-nostats", "-loglevel", "error",
+		"-i", inputPath,
+		"-map", fmt.Sprintf("0:s:%d", trackIndex),
+		"-c:s", "webvtt",
+		"-f", "webvtt",
+		"-",
+	)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("subtitle conversion failed: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+
+	return stdout.Bytes(), nil
+}
+
+// StripMarkup removes cue markup, leaving plain text cues
+func StripMarkup(data []byte) []byte {
+	lines := strings.Split(string(data), "\n")
+	for i, line := range lines {
+		// Timing lines contain "-->" and no markup
+		if !strings.Contains(line, "-->") {
+			lines[i] = vttTagPattern.ReplaceAllString(line, "")
+		}
+	}
+	return []byte(strings.Join(lines, "\n"))
+}
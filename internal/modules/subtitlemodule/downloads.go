@@ -0,0 +1,148 @@
+package subtitlemodule
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/mantonx/viewra/internal/database"
+	"github.com/mantonx/viewra/internal/modules/assetmodule"
+)
+
+// downloadFormats maps the formats plugins send downloaded subtitles in to
+// the file extension FFmpeg reads them by
+var downloadFormats = map[string]string{
+	"srt":                  ".srt",
+	"subrip":               ".srt",
+	"application/x-subrip": ".srt",
+	"ass":                  ".ass",
+	"ssa":                  ".ssa",
+	"text/x-ssa":           ".ssa",
+}
+
+// Download is a subtitle a plugin fetched for a media file, e.g. from
+// OpenSubtitles, and saved through the host asset service
+type Download struct {
+	PluginID string
+	Language string
+	Forced   bool
+	SDH      bool
+	Format   string // webvtt, srt, ass or ssa, or their MIME type
+	Data     []byte
+}
+
+// SaveDownload stores a downloaded subtitle as a WebVTT asset of the media
+// file's movie or episode. A plugin's download of the same language and kind
+// replaces its earlier one.
+func SaveDownload(ctx context.Context, mediaFile *database.MediaFile, download Download) (*assetmodule.AssetResponse, error) {
+	entityType, entityID, ok := AssetEntity(mediaFile)
+	if !ok {
+		return nil, fmt.Errorf("subtitles are only stored for movie and episode files")
+	}
+	if download.PluginID == "" || strings.Contains(download.PluginID, "/") {
+		return nil, fmt.Errorf("invalid plugin ID %q", download.PluginID)
+	}
+
+	data, err := downloadWebVTT(ctx, download)
+	if err != nil {
+		return nil, err
+	}
+
+	language := strings.ToLower(strings.TrimSpace(download.Language))
+	return assetmodule.SaveMediaAsset(&assetmodule.AssetRequest{
+		EntityType: entityType,
+		EntityID:   entityID,
+		Type:       assetmodule.AssetTypeSubtitle,
+		Source:     assetmodule.SourcePlugin,
+		PluginID:   download.PluginID,
+		Data:       data,
+		Format:     "text/vtt",
+		Language:   language,
+		Variant:    downloadVariant(mediaFile.ID, download.PluginID, downloadName(language, download.Forced, download.SDH)),
+	})
+}
+
+// downloadedTrack lists a downloaded subtitle asset as a track, or returns
+// false when the asset isn't a download
+func downloadedTrack(mediaFile *database.MediaFile, asset *assetmodule.AssetResponse) (Track, bool) {
+	rest, ok := strings.CutPrefix(asset.Variant, downloadVariant(mediaFile.ID, "", ""))
+	if !ok {
+		return Track{}, false
+	}
+	pluginID, name, _ := strings.Cut(rest, "/")
+
+	track := Track{
+		MediaFileID: mediaFile.ID,
+		Source:      SourceDownloaded,
+		Provider:    pluginID,
+		Codec:       "webvtt",
+		Language:    asset.Language,
+		Method:      MethodWebVTT,
+		URL:         fmt.Sprintf("/api/v1/assets/%s/data", asset.ID),
+	}
+	for _, tag := range strings.Split(strings.TrimSuffix(name, ".vtt"), ".") {
+		switch tag {
+		case "forced":
+			track.Forced = true
+		case "sdh":
+			track.Title = "SDH"
+		}
+	}
+	return track, true
+}
+
+// downloadVariant is the asset variant a plugin's downloaded subtitle is
+// stored under. Empty IDs and names give the prefix shared by them.
+func downloadVariant(mediaFileID, pluginID, name string) string {
+	if pluginID == "" {
+		return fmt.Sprintf("%s/download/", mediaFileID)
+	}
+	return fmt.Sprintf("%s/download/%s/%s", mediaFileID, pluginID, name)
+}
+
+// downloadName names a download by its language and kind, tagged like
+// subtitle files beside media, e.g. en.forced.vtt
+func downloadName(language string, forced, sdh bool) string {
+	if language == "" || strings.ContainsAny(language, "./") {
+		language = "und"
+	}
+	name := language
+	if forced {
+		name += ".forced"
+	}
+	if sdh {
+		name += ".sdh"
+	}
+	return name + ".vtt"
+}
+
+// downloadWebVTT returns a download as WebVTT, converting other formats
+func downloadWebVTT(ctx context.Context, download Download) ([]byte, error) {
+	format := strings.ToLower(strings.TrimSpace(download.Format))
+	if format == "webvtt" || format == "vtt" || format == "text/vtt" ||
+		bytes.HasPrefix(bytes.TrimPrefix(download.Data, []byte("\xef\xbb\xbf")), []byte("WEBVTT")) {
+		return download.Data, nil
+	}
+
+	ext, ok := downloadFormats[format]
+	if !ok {
+		return nil, fmt.Errorf("unsupported subtitle format %q", download.Format)
+	}
+
+	// FFmpeg reads the subtitle from a file named by its format
+	file, err := os.CreateTemp("", "viewra-subtitle-*"+ext)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temporary subtitle file: %w", err)
+	}
+	defer os.Remove(file.Name())
+	if _, err := file.Write(download.Data); err != nil {
+		file.Close()
+		return nil, fmt.Errorf("failed to write temporary subtitle file: %w", err)
+	}
+	if err := file.Close(); err != nil {
+		return nil, fmt.Errorf("failed to write temporary subtitle file: %w", err)
+	}
+	return ConvertToWebVTT(ctx, file.Name(), 0)
+}
@@ -0,0 +1,350 @@
+package subtitlemodule
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/mantonx/viewra/internal/database"
+	"github.com/mantonx/viewra/internal/modules/assetmodule"
+	"gorm.io/gorm"
+)
+
+// Track sources
+const (
+	SourceEmbedded   = "embedded"   // A stream of the media file's container
+	SourceSidecar    = "sidecar"    // A subtitle file beside the media file
+	SourceDownloaded = "downloaded" // Fetched by a plugin, e.g. from OpenSubtitles
+)
+
+// queueSize bounds the files waiting for extraction. Files that don't fit
+// are converted when a client first asks for their tracks instead.
+const queueSize = 1024
+
+// Track is a subtitle track of a media file and where to fetch it as WebVTT
+type Track struct {
+	MediaFileID string `json:"media_file_id"`
+	Source      string `json:"source"`
+	Index       int    `json:"index"`              // Counted among the tracks of its source; for embedded ones as in 0:s:N
+	File        string `json:"file,omitempty"`     // Sidecar tracks: the subtitle file's name
+	Provider    string `json:"provider,omitempty"` // Downloaded tracks: the plugin that fetched it
+	Codec       string `json:"codec"`
+	Language    string `json:"language,omitempty"`
+	Title       string `json:"title,omitempty"`
+	Default     bool   `json:"default"`
+	Forced      bool   `json:"forced"`
+	Method      string `json:"method"`
+	URL         string `json:"url,omitempty"`
+}
+
+// embeddedStream is the stored probe info for a subtitle stream
+type embeddedStream struct {
+	Codec    string `json:"codec"`
+	Language string `json:"language"`
+	Title    string `json:"title"`
+	Default  bool   `json:"default"`
+	Forced   bool   `json:"forced"`
+}
+
+// Manager extracts media files' subtitles to WebVTT assets: the text tracks
+// embedded in their containers, and the subtitle files stored beside them.
+// Image-based tracks such as PGS can only be burned in while transcoding, so
+// they are listed but never extracted.
+type Manager struct {
+	db    *gorm.DB
+	queue chan string // IDs of media files waiting for extraction
+}
+
+// NewManager creates a subtitle manager
+func NewManager(db *gorm.DB) *Manager {
+	return &Manager{
+		db:    db,
+		queue: make(chan string, queueSize),
+	}
+}
+
+// Run extracts the subtitles of queued media files one at a time, as each
+// extraction reads the whole file, until ctx is done
+func (m *Manager) Run(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case mediaFileID := <-m.queue:
+			var mediaFile database.MediaFile
+			if err := m.db.Where("id = ?", mediaFileID).First(&mediaFile).Error; err != nil {
+				continue // Removed while queued
+			}
+			if err := m.ExtractEmbedded(ctx, &mediaFile); err != nil {
+				log.Printf("WARN: Failed to extract embedded subtitles of %s: %v", mediaFile.Path, err)
+			}
+			if err := m.IngestSidecars(ctx, &mediaFile); err != nil {
+				log.Printf("WARN: Failed to ingest subtitle files of %s: %v", mediaFile.Path, err)
+			}
+		}
+	}
+}
+
+// Enqueue queues a media file for extraction, returning false when the
+// queue is full
+func (m *Manager) Enqueue(mediaFileID string) bool {
+	select {
+	case m.queue <- mediaFileID:
+		return true
+	default:
+		return false
+	}
+}
+
+// ExtractEmbedded converts a media file's embedded text tracks to WebVTT
+// assets, skipping those already converted. They are stored under the same
+// variants the playback module caches its conversions under.
+func (m *Manager) ExtractEmbedded(ctx context.Context, mediaFile *database.MediaFile) error {
+	entityType, entityID, ok := AssetEntity(mediaFile)
+	if !ok {
+		return nil
+	}
+	streams, err := embeddedStreams(mediaFile)
+	if err != nil {
+		return err
+	}
+
+	for i, stream := range streams {
+		if Method(stream.Codec) != MethodWebVTT {
+			continue
+		}
+		variant := EmbeddedVariant(mediaFile.ID, i, StyleStyled)
+		if m.hasAsset(entityType, entityID, variant) {
+			continue
+		}
+
+		data, err := ConvertToWebVTT(ctx, mediaFile.Path, i)
+		if err != nil {
+			return fmt.Errorf("track %d: %w", i, err)
+		}
+		if _, err := assetmodule.SaveMediaAsset(&assetmodule.AssetRequest{
+			EntityType: entityType,
+			EntityID:   entityID,
+			Type:       assetmodule.AssetTypeSubtitle,
+			Source:     assetmodule.SourceCore,
+			Data:       data,
+			Format:     "text/vtt",
+			Language:   stream.Language,
+			Variant:    variant,
+		}); err != nil {
+			return fmt.Errorf("track %d: failed to save subtitle: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// IngestSidecars stores the subtitle files beside a media file as WebVTT
+// assets. Files changed since they were stored are converted again, and the
+// assets of files no longer there are removed.
+func (m *Manager) IngestSidecars(ctx context.Context, mediaFile *database.MediaFile) error {
+	entityType, entityID, ok := AssetEntity(mediaFile)
+	if !ok {
+		return nil
+	}
+	sidecars, err := FindSidecars(mediaFile.Path)
+	if err != nil {
+		return err
+	}
+
+	stored := make(map[string]*assetmodule.AssetResponse)
+	for _, asset := range m.fileAssets(mediaFile, entityType, entityID) {
+		if strings.HasPrefix(asset.Variant, sidecarVariant(mediaFile.ID, "")) {
+			stored[asset.Variant] = asset
+		}
+	}
+
+	for _, sidecar := range sidecars {
+		variant := sidecarVariant(mediaFile.ID, filepath.Base(sidecar.Path))
+		info, err := os.Stat(sidecar.Path)
+		if err != nil {
+			continue
+		}
+		if asset, ok := stored[variant]; ok {
+			delete(stored, variant)
+			if !info.ModTime().After(asset.UpdatedAt) {
+				continue
+			}
+		}
+
+		data, err := sidecarWebVTT(ctx, sidecar)
+		if err != nil {
+			log.Printf("WARN: Failed to convert subtitle file %s: %v", sidecar.Path, err)
+			continue
+		}
+		if _, err := assetmodule.SaveMediaAsset(&assetmodule.AssetRequest{
+			EntityType: entityType,
+			EntityID:   entityID,
+			Type:       assetmodule.AssetTypeSubtitle,
+			Source:     assetmodule.SourceLocal,
+			Data:       data,
+			Format:     "text/vtt",
+			Language:   sidecar.Language,
+			Variant:    variant,
+		}); err != nil {
+			return fmt.Errorf("failed to save subtitle file %s: %w", sidecar.Path, err)
+		}
+	}
+
+	for _, asset := range stored {
+		if err := assetmodule.RemoveMediaAsset(asset.ID); err != nil {
+			log.Printf("WARN: Failed to remove subtitle asset %s: %v", asset.ID, err)
+		}
+	}
+	return nil
+}
+
+// RemoveFileSubtitles removes every subtitle asset stored for a media file.
+// Other files of the same movie or episode keep theirs.
+func (m *Manager) RemoveFileSubtitles(mediaFile *database.MediaFile) {
+	m.removeFileSubtitles(mediaFile, false)
+}
+
+// RemoveExtractedSubtitles removes the subtitle assets extracted from a media
+// file and the files beside it. Downloaded subtitles are kept: the plugins
+// that fetched them replace them when told the file changed.
+func (m *Manager) RemoveExtractedSubtitles(mediaFile *database.MediaFile) {
+	m.removeFileSubtitles(mediaFile, true)
+}
+
+func (m *Manager) removeFileSubtitles(mediaFile *database.MediaFile, keepDownloads bool) {
+	entityType, entityID, ok := AssetEntity(mediaFile)
+	if !ok {
+		return
+	}
+	for _, asset := range m.fileAssets(mediaFile, entityType, entityID) {
+		if keepDownloads && strings.HasPrefix(asset.Variant, downloadVariant(mediaFile.ID, "", "")) {
+			continue
+		}
+		if err := assetmodule.RemoveMediaAsset(asset.ID); err != nil {
+			log.Printf("WARN: Failed to remove subtitle asset %s: %v", asset.ID, err)
+		}
+	}
+}
+
+// Tracks lists a media file's embedded, sidecar and downloaded subtitle
+// tracks. Subtitle files added since the last scan are picked up here.
+func (m *Manager) Tracks(ctx context.Context, mediaFile *database.MediaFile) ([]Track, error) {
+	streams, err := embeddedStreams(mediaFile)
+	if err != nil {
+		return nil, err
+	}
+
+	tracks := make([]Track, 0, len(streams))
+	for i, stream := range streams {
+		track := Track{
+			MediaFileID: mediaFile.ID,
+			Source:      SourceEmbedded,
+			Index:       i,
+			Codec:       stream.Codec,
+			Language:    stream.Language,
+			Title:       stream.Title,
+			Default:     stream.Default,
+			Forced:      stream.Forced,
+			Method:      Method(stream.Codec),
+		}
+		if track.Method == MethodWebVTT {
+			track.URL = fmt.Sprintf("/api/playback/subtitles/%s/%d.vtt", mediaFile.ID, i)
+		}
+		tracks = append(tracks, track)
+	}
+
+	if err := m.IngestSidecars(ctx, mediaFile); err != nil {
+		log.Printf("WARN: Failed to ingest subtitle files of %s: %v", mediaFile.Path, err)
+	}
+
+	entityType, entityID, ok := AssetEntity(mediaFile)
+	if !ok {
+		return tracks, nil
+	}
+	sidecars, downloads := 0, 0
+	for _, asset := range m.fileAssets(mediaFile, entityType, entityID) {
+		name, ok := strings.CutPrefix(asset.Variant, sidecarVariant(mediaFile.ID, ""))
+		if !ok {
+			if track, ok := downloadedTrack(mediaFile, asset); ok {
+				track.Index = downloads
+				tracks = append(tracks, track)
+				downloads++
+			}
+			continue
+		}
+		sidecar, _ := parseSidecar(mediaFile.Path, name)
+		tracks = append(tracks, Track{
+			MediaFileID: mediaFile.ID,
+			Source:      SourceSidecar,
+			Index:       sidecars,
+			File:        name,
+			Codec:       sidecar.Codec,
+			Language:    asset.Language,
+			Title:       sidecar.Title,
+			Default:     sidecar.Default,
+			Forced:      sidecar.Forced,
+			Method:      MethodWebVTT,
+			URL:         fmt.Sprintf("/api/v1/assets/%s/data", asset.ID),
+		})
+		sidecars++
+	}
+	return tracks, nil
+}
+
+// fileAssets returns the subtitle assets stored for a media file
+func (m *Manager) fileAssets(mediaFile *database.MediaFile, entityType assetmodule.EntityType, entityID uuid.UUID) []*assetmodule.AssetResponse {
+	assets, err := assetmodule.GetMediaAssetsByEntity(entityType, entityID, &assetmodule.AssetFilter{
+		Type: assetmodule.AssetTypeSubtitle,
+	})
+	if err != nil {
+		return nil
+	}
+
+	var fileAssets []*assetmodule.AssetResponse
+	for _, asset := range assets {
+		if strings.HasPrefix(asset.Variant, mediaFile.ID+"/") {
+			fileAssets = append(fileAssets, asset)
+		}
+	}
+	return fileAssets
+}
+
+// hasAsset reports whether a subtitle asset is stored under variant
+func (m *Manager) hasAsset(entityType assetmodule.EntityType, entityID uuid.UUID, variant string) bool {
+	assets, err := assetmodule.GetMediaAssetsByEntity(entityType, entityID, &assetmodule.AssetFilter{
+		Type:    assetmodule.AssetTypeSubtitle,
+		Variant: variant,
+		Limit:   1,
+	})
+	return err == nil && len(assets) > 0
+}
+
+// sidecarVariant is the asset variant a subtitle file beside a media file is
+// stored under. An empty name gives the prefix shared by all of them.
+func sidecarVariant(mediaFileID, name string) string {
+	return fmt.Sprintf("%s/sidecar/%s", mediaFileID, name)
+}
+
+// sidecarWebVTT reads a subtitle file as WebVTT, converting other formats
+func sidecarWebVTT(ctx context.Context, sidecar Sidecar) ([]byte, error) {
+	if sidecar.Codec == "webvtt" {
+		return os.ReadFile(sidecar.Path)
+	}
+	return ConvertToWebVTT(ctx, sidecar.Path, 0)
+}
+
+// embeddedStreams parses a media file's stored subtitle stream info
+func embeddedStreams(mediaFile *database.MediaFile) ([]embeddedStream, error) {
+	var streams []embeddedStream
+	if mediaFile.SubtitleStreams != "" {
+		if err := json.Unmarshal([]byte(mediaFile.SubtitleStreams), &streams); err != nil {
+			return nil, fmt.Errorf("failed to parse subtitle streams: %w", err)
+		}
+	}
+	return streams, nil
+}
@@ -0,0 +1,176 @@
+// Package subtitlemodule extracts media files' subtitles to WebVTT: the text
+// tracks embedded in their containers and the .srt, .ass, .ssa and .vtt
+// files stored beside them. Extractions are stored as subtitle assets of the
+// file's movie or episode, and listed by /api/media/:id/subtitles.
+package subtitlemodule
+
+import (
+	"context"
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mantonx/viewra/internal/database"
+	"github.com/mantonx/viewra/internal/modules/modulemanager"
+	"github.com/mantonx/viewra/internal/modules/scannermodule/scanner"
+	"gorm.io/gorm"
+)
+
+// Auto-register the module when imported
+func init() {
+	Register()
+}
+
+const (
+	ModuleID   = "system.subtitles"
+	ModuleName = "Subtitle Extractor"
+)
+
+// Module extracts subtitles as the scanner finds media files. It isn't a
+// core module, so it can be disabled in the module configuration.
+type Module struct {
+	id          string
+	name        string
+	version     string
+	core        bool
+	db          *gorm.DB
+	manager     *Manager
+	initialized bool
+}
+
+// Register registers this module with the module system
+func Register() {
+	subtitleModule := &Module{
+		id:      ModuleID,
+		name:    ModuleName,
+		version: "1.0.0",
+		core:    false,
+	}
+	modulemanager.Register(subtitleModule)
+}
+
+// ID returns the module ID
+func (m *Module) ID() string {
+	return m.id
+}
+
+// Name returns the module name
+func (m *Module) Name() string {
+	return m.name
+}
+
+// Core returns whether this is a core module
+func (m *Module) Core() bool {
+	return m.core
+}
+
+// Migrate handles database schema migrations. Subtitles are stored as assets.
+func (m *Module) Migrate(db *gorm.DB) error {
+	return nil
+}
+
+// Init initializes the module and starts the extraction worker
+func (m *Module) Init() error {
+	m.db = database.GetDB()
+	m.manager = NewManager(m.db)
+	m.initialized = true
+
+	go m.manager.Run(context.Background())
+
+	log.Println("Subtitle module initialized")
+	return nil
+}
+
+// RegisterRoutes registers the subtitle endpoints of the media API
+func (m *Module) RegisterRoutes(router *gin.Engine) {
+	if !m.initialized {
+		return
+	}
+
+	api := router.Group("/api/media")
+	{
+		api.GET("/:id/subtitles", m.getSubtitles)
+	}
+}
+
+// getSubtitles lists the subtitle tracks of a media item's files, or of a
+// single media file
+func (m *Module) getSubtitles(c *gin.Context) {
+	id := c.Param("id")
+
+	var mediaFiles []database.MediaFile
+	if err := m.db.Where("media_id = ?", id).Order("version_name, path").Find(&mediaFiles).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get media files"})
+		return
+	}
+	if len(mediaFiles) == 0 {
+		if err := m.db.Where("id = ?", id).Limit(1).Find(&mediaFiles).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get media file"})
+			return
+		}
+	}
+	if len(mediaFiles) == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Media not found"})
+		return
+	}
+
+	tracks := []Track{}
+	for i := range mediaFiles {
+		fileTracks, err := m.manager.Tracks(c.Request.Context(), &mediaFiles[i])
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		tracks = append(tracks, fileTracks...)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"media_id": mediaFiles[0].MediaID,
+		"tracks":   tracks,
+	})
+}
+
+// OnMediaFileScanned queues a newly scanned file's subtitles for extraction
+func (m *Module) OnMediaFileScanned(mediaFile *database.MediaFile, metadata interface{}) error {
+	if !m.initialized || mediaFile == nil {
+		return nil
+	}
+	if !m.manager.Enqueue(mediaFile.ID) {
+		log.Printf("DEBUG: Subtitle queue full, %s is extracted on request", mediaFile.Path)
+	}
+	return nil
+}
+
+// OnMediaFileUpdated extracts a changed file's subtitles again, as its
+// tracks may differ
+func (m *Module) OnMediaFileUpdated(mediaFile *database.MediaFile, metadata interface{}) error {
+	if !m.initialized || mediaFile == nil {
+		return nil
+	}
+	m.manager.RemoveExtractedSubtitles(mediaFile)
+	return m.OnMediaFileScanned(mediaFile, metadata)
+}
+
+// OnMediaFileRemoved removes a deleted file's subtitles
+func (m *Module) OnMediaFileRemoved(mediaFile *database.MediaFile) error {
+	if !m.initialized || mediaFile == nil {
+		return nil
+	}
+	m.manager.RemoveFileSubtitles(mediaFile)
+	return nil
+}
+
+// OnScanStarted is part of the scanner hook interface
+func (m *Module) OnScanStarted(jobID, libraryID uint, path string) error {
+	return nil
+}
+
+// OnFileScanned is part of the scanner hook interface
+func (m *Module) OnFileScanned(mediaFile *database.MediaFile, metadata interface{}) error {
+	return nil
+}
+
+// OnScanCompleted is part of the scanner hook interface
+func (m *Module) OnScanCompleted(libraryID uint, stats scanner.ScanStats) error {
+	return nil
+}
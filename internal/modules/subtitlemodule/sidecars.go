@@ -0,0 +1,101 @@
+package subtitlemodule
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode"
+)
+
+// sidecarCodecs maps the subtitle file extensions read from beside a video
+// to their codec names
+var sidecarCodecs = map[string]string{
+	".srt": "subrip",
+	".ass": "ass",
+	".ssa": "ssa",
+	".vtt": "webvtt",
+}
+
+// Sidecar is a subtitle file stored beside a media file
+type Sidecar struct {
+	Path     string
+	Codec    string
+	Language string
+	Title    string // e.g. SDH
+	Default  bool
+	Forced   bool
+}
+
+// FindSidecars lists the subtitle files beside a media file that belong to
+// it: those named after the file, optionally followed by dot-separated tags,
+// e.g. "Movie (2020).en.forced.srt" beside "Movie (2020).mkv".
+func FindSidecars(mediaPath string) ([]Sidecar, error) {
+	entries, err := os.ReadDir(filepath.Dir(mediaPath))
+	if err != nil {
+		return nil, err
+	}
+
+	var sidecars []Sidecar
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		if sidecar, ok := parseSidecar(mediaPath, entry.Name()); ok {
+			sidecars = append(sidecars, sidecar)
+		}
+	}
+	return sidecars, nil
+}
+
+// parseSidecar reads what a subtitle file's name says about its track, or
+// returns false when the file isn't a subtitle of the media file
+func parseSidecar(mediaPath, name string) (Sidecar, bool) {
+	ext := strings.ToLower(filepath.Ext(name))
+	codec, ok := sidecarCodecs[ext]
+	if !ok {
+		return Sidecar{}, false
+	}
+
+	mediaBase := filepath.Base(mediaPath)
+	stem := strings.TrimSuffix(mediaBase, filepath.Ext(mediaBase))
+	rest := strings.TrimSuffix(name, filepath.Ext(name))
+	if !strings.EqualFold(rest, stem) && !strings.HasPrefix(strings.ToLower(rest), strings.ToLower(stem)+".") {
+		return Sidecar{}, false
+	}
+
+	sidecar := Sidecar{
+		Path:  filepath.Join(filepath.Dir(mediaPath), name),
+		Codec: codec,
+	}
+	for _, tag := range strings.Split(rest[len(stem):], ".") {
+		switch lower := strings.ToLower(tag); {
+		case lower == "":
+		case lower == "forced" || lower == "foreign":
+			sidecar.Forced = true
+		case lower == "default":
+			sidecar.Default = true
+		case lower == "sdh" || lower == "cc" || lower == "hi":
+			sidecar.Title = "SDH"
+		case sidecar.Language == "" && isLanguageCode(lower):
+			sidecar.Language = lower
+		case sidecar.Title == "":
+			sidecar.Title = tag
+		}
+	}
+	return sidecar, true
+}
+
+// isLanguageCode reports whether a file name tag looks like an ISO 639
+// language code, e.g. en or eng, optionally with a region as in pt-br
+func isLanguageCode(tag string) bool {
+	code, region, _ := strings.Cut(tag, "-")
+	if len(code) < 2 || len(code) > 3 || len(region) > 4 {
+		return false
+	}
+	for _, r := range code + region {
+		if !unicode.IsLetter(r) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,178 @@
+package parental
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/mantonx/viewra/internal/database"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+const (
+	// OverrideDuration is how long a correct PIN lifts a user's limit
+	OverrideDuration = time.Hour
+
+	// maxPINAttempts wrong PINs in a row lock the override for pinLockout
+	maxPINAttempts = 5
+	pinLockout     = 15 * time.Minute
+)
+
+// Reasons a limit can't be set or lifted
+var (
+	ErrUnknownRating = errors.New("unrecognized content rating")
+	ErrInvalidPIN    = errors.New("PIN must be 4 to 8 digits")
+	ErrNoPIN         = errors.New("no override PIN is set")
+	ErrWrongPIN      = errors.New("wrong PIN")
+	ErrLockedOut     = errors.New("too many wrong PINs; try again later")
+)
+
+// Limit is the most mature content a user may see
+type Limit struct {
+	MaxAge       int
+	BlockUnrated bool
+}
+
+// ForUser returns the limit a user is kept to now, or nil when they have no
+// parental controls or have lifted them with their PIN
+func ForUser(db *gorm.DB, userID uint32, now time.Time) (*Limit, error) {
+	var controls []database.UserParentalControl
+	if err := db.Where("user_id = ?", userID).Limit(1).Find(&controls).Error; err != nil {
+		return nil, fmt.Errorf("failed to load parental controls: %w", err)
+	}
+	if len(controls) == 0 {
+		return nil, nil
+	}
+	control := &controls[0]
+	if control.OverrideUntil != nil && now.Before(*control.OverrideUntil) {
+		return nil, nil
+	}
+	age, ok := Age(control.MaxRating)
+	if !ok {
+		return nil, nil
+	}
+	return &Limit{MaxAge: age, BlockUnrated: control.BlockUnrated}, nil
+}
+
+// Allows reports whether an item with a certification is within the limit
+func (l *Limit) Allows(rating string) bool {
+	age, ok := Age(rating)
+	if !ok {
+		return !l.BlockUnrated
+	}
+	return age <= l.MaxAge
+}
+
+// Denied is what a limit hides, worked out once for a request
+type Denied struct {
+	movieRatings []string
+	showRatings  []string
+	unrated      bool
+}
+
+// Denied finds the ratings in the library the limit doesn't allow. Ratings
+// are written many ways ("PG-13", "Rated PG-13", "US:PG-13"), so the
+// distinct values are classified here rather than in SQL.
+func (l *Limit) Denied(db *gorm.DB) (*Denied, error) {
+	movieRatings, err := l.deniedRatings(db, &database.Movie{}, "rating")
+	if err != nil {
+		return nil, err
+	}
+	showRatings, err := l.deniedRatings(db, &database.TVShow{}, "content_rating")
+	if err != nil {
+		return nil, err
+	}
+	return &Denied{movieRatings: movieRatings, showRatings: showRatings, unrated: l.BlockUnrated}, nil
+}
+
+func (l *Limit) deniedRatings(db *gorm.DB, model interface{}, column string) ([]string, error) {
+	var ratings []string
+	if err := db.Model(model).Where(column+" IS NOT NULL").Distinct(column).Pluck(column, &ratings).Error; err != nil {
+		return nil, fmt.Errorf("failed to load content ratings: %w", err)
+	}
+	denied := []string{}
+	for _, rating := range ratings {
+		if !l.Allows(rating) {
+			denied = append(denied, rating)
+		}
+	}
+	return denied, nil
+}
+
+// Movies selects the IDs of the movies rated above the limit
+func (d *Denied) Movies(db *gorm.DB) *gorm.DB {
+	return d.rated(db.Model(&database.Movie{}), "rating", d.movieRatings)
+}
+
+// Shows selects the IDs of the shows rated above the limit. Episodes take
+// their show's rating.
+func (d *Denied) Shows(db *gorm.DB) *gorm.DB {
+	return d.rated(db.Model(&database.TVShow{}), "content_rating", d.showRatings)
+}
+
+func (d *Denied) rated(query *gorm.DB, column string, ratings []string) *gorm.DB {
+	query = query.Select("id")
+	if d.unrated {
+		return query.Where(column+" IN ? OR "+column+" IS NULL", ratings)
+	}
+	return query.Where(column+" IN ?", ratings)
+}
+
+// HashPIN hashes an override PIN for storage
+func HashPIN(pin string) (string, error) {
+	if len(pin) < 4 || len(pin) > 8 {
+		return "", ErrInvalidPIN
+	}
+	for _, r := range pin {
+		if r < '0' || r > '9' {
+			return "", ErrInvalidPIN
+		}
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte(pin), bcrypt.DefaultCost)
+	if err != nil {
+		return "", fmt.Errorf("failed to hash PIN: %w", err)
+	}
+	return string(hash), nil
+}
+
+// Unlock lifts a user's limit for OverrideDuration when the PIN is right.
+// Repeated wrong PINs lock the override for a while, so it can't be guessed.
+func Unlock(db *gorm.DB, control *database.UserParentalControl, pin string, now time.Time) error {
+	if control.PINHash == "" {
+		return ErrNoPIN
+	}
+	if control.LockedUntil != nil && now.Before(*control.LockedUntil) {
+		return ErrLockedOut
+	}
+
+	if bcrypt.CompareHashAndPassword([]byte(control.PINHash), []byte(pin)) != nil {
+		updates := map[string]interface{}{"failed_attempts": control.FailedAttempts + 1}
+		if control.FailedAttempts+1 >= maxPINAttempts {
+			updates["failed_attempts"] = 0
+			updates["locked_until"] = now.Add(pinLockout)
+		}
+		if err := db.Model(control).Updates(updates).Error; err != nil {
+			return fmt.Errorf("failed to record PIN attempt: %w", err)
+		}
+		return ErrWrongPIN
+	}
+
+	until := now.Add(OverrideDuration)
+	if err := db.Model(control).Updates(map[string]interface{}{
+		"override_until":  until,
+		"failed_attempts": 0,
+		"locked_until":    nil,
+	}).Error; err != nil {
+		return fmt.Errorf("failed to lift parental controls: %w", err)
+	}
+	return nil
+}
+
+// Lock ends a PIN override early
+func Lock(db *gorm.DB, control *database.UserParentalControl) error {
+	if err := db.Model(control).Update("override_until", nil).Error; err != nil {
+		return fmt.Errorf("failed to restore parental controls: %w", err)
+	}
+	return nil
+}
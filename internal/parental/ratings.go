@@ -0,0 +1,102 @@
+// Package parental keeps users to the content ratings they are allowed.
+// Certifications from any country (PG-13, TV-MA, 15, FSK16, MA15+...) are
+// mapped to the youngest age they suit, and a user's maximum rating is
+// compared on that scale, so a limit of PG-13 also hides FSK16 and allows
+// TV-PG.
+package parental
+
+import (
+	"strconv"
+	"strings"
+)
+
+// ratingAges maps normalized certifications to the youngest age they suit.
+// Plain numbers, as used by most countries, are ages already.
+var ratingAges = map[string]int{
+	// United States films
+	"G":     0,
+	"PG":    10,
+	"PG-13": 13,
+	"R":     17,
+	"NC-17": 18,
+	"X":     18,
+
+	// United States television
+	"TV-Y":     0,
+	"TV-G":     0,
+	"TV-Y7":    7,
+	"TV-Y7-FV": 7,
+	"TV-PG":    10,
+	"TV-14":    14,
+	"TV-MA":    17,
+
+	// United Kingdom
+	"U":   0,
+	"UC":  0,
+	"12A": 12,
+	"R18": 18,
+
+	// Canada
+	"14A": 14,
+	"18A": 18,
+
+	// Australia
+	"M":     15,
+	"MA15+": 15,
+	"R18+":  18,
+	"X18+":  18,
+
+	// France and Brazil
+	"TP": 0,
+	"L":  0,
+}
+
+// unratedCertifications say outright that an item has no rating
+var unratedCertifications = map[string]bool{
+	"":          true,
+	"NR":        true,
+	"UNRATED":   true,
+	"NOTRATED":  true,
+	"NOT RATED": true,
+}
+
+// Age returns the youngest age a certification suits, or false when it is
+// empty, says the item is unrated or isn't recognized
+func Age(rating string) (int, bool) {
+	rating = normalize(rating)
+	if unratedCertifications[rating] {
+		return 0, false
+	}
+	if age, ok := ratingAges[rating]; ok {
+		return age, true
+	}
+
+	// Germany's FSK 0-18, France's -12 and numbered ratings such as 15 or 16+
+	number := strings.TrimPrefix(rating, "FSK")
+	number = strings.TrimPrefix(number, "-")
+	number = strings.TrimSuffix(number, "+")
+	if age, err := strconv.Atoi(number); err == nil && age >= 0 && age <= 21 {
+		return age, true
+	}
+	return 0, false
+}
+
+// Valid reports whether a certification is recognized
+func Valid(rating string) bool {
+	_, ok := Age(rating)
+	return ok
+}
+
+// normalize uppercases a certification and strips what NFO files and
+// providers put around it: "Rated PG-13", "US:PG-13", "FSK 16"
+func normalize(rating string) string {
+	rating = strings.ToUpper(strings.TrimSpace(rating))
+	rating = strings.TrimPrefix(rating, "RATED ")
+	if country, rest, found := strings.Cut(rating, ":"); found && len(country) <= 3 {
+		rating = strings.TrimSpace(rest)
+	}
+	if unratedCertifications[rating] {
+		return rating
+	}
+	return strings.ReplaceAll(rating, " ", "")
+}
@@ -8,6 +8,7 @@ import (
 	// Import all core plugins to trigger their factory registration
 	_ "github.com/mantonx/viewra/internal/plugins/enrichment"
 	_ "github.com/mantonx/viewra/internal/plugins/ffmpeg"
+	_ "github.com/mantonx/viewra/internal/plugins/homevideo"
 	_ "github.com/mantonx/viewra/internal/plugins/moviestructure"
 	_ "github.com/mantonx/viewra/internal/plugins/tvstructure"
 )
@@ -0,0 +1,125 @@
+package enrichment
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/dhowden/tag"
+	"github.com/mantonx/viewra/internal/database"
+)
+
+// classicalTags lists the raw tag names each classical field is read from.
+// Vorbis comments are lower-cased by the tag library, ID3 uses frame IDs
+// (v2.3/2.4 and v2.2) or TXXX descriptions, and MP4 uses iTunes freeform names.
+// TIT1 is left out for the work: most taggers use it as the grouping.
+var classicalTags = map[string][]string{
+	"work":          {"work"},
+	"movement":      {"movementname", "MVNM"},
+	"movementIndex": {"movement", "MVIN"},
+	"movementTotal": {"movementtotal"},
+	"conductor":     {"conductor", "TPE3", "TP3"},
+	"orchestra":     {"orchestra", "ensemble"},
+}
+
+// titleMovementPattern matches classical titles that name the work and a
+// numbered movement, e.g. "Symphony No. 5 in C minor, Op. 67: I. Allegro con brio"
+var titleMovementPattern = regexp.MustCompile(`^(.+?):\s+([IVXLC]+)\.\s+(.+)$`)
+
+// applyClassicalTags reads composer, work, movement, conductor and orchestra
+// tags. Pop-oriented taggers put all of this into the title, so a
+// "Work: I. Movement" title is split when no work tag is present.
+func applyClassicalTags(metadata tag.Metadata, trackInfo *TrackInfo) {
+	raw := metadata.Raw()
+
+	trackInfo.Composer = strings.TrimSpace(metadata.Composer())
+	trackInfo.Work = rawTag(raw, classicalTags["work"]...)
+	trackInfo.Movement = rawTag(raw, classicalTags["movement"]...)
+	trackInfo.Conductor = rawTag(raw, classicalTags["conductor"]...)
+	trackInfo.Orchestra = rawTag(raw, classicalTags["orchestra"]...)
+
+	// MVIN is "2/4"; Vorbis splits number and total into two comments
+	index := rawTag(raw, classicalTags["movementIndex"]...)
+	number, total, _ := strings.Cut(index, "/")
+	trackInfo.MovementNumber, _ = strconv.Atoi(strings.TrimSpace(number))
+	trackInfo.MovementCount, _ = strconv.Atoi(strings.TrimSpace(total))
+	if count, err := strconv.Atoi(rawTag(raw, classicalTags["movementTotal"]...)); err == nil {
+		trackInfo.MovementCount = count
+	}
+
+	if trackInfo.Work != "" || trackInfo.Composer == "" {
+		return
+	}
+	if match := titleMovementPattern.FindStringSubmatch(trackInfo.Title); match != nil {
+		if number := romanToInt(match[2]); number > 0 {
+			trackInfo.Work = strings.TrimSpace(match[1])
+			trackInfo.Movement = strings.TrimSpace(match[3])
+			trackInfo.MovementNumber = number
+		}
+	}
+}
+
+// applyClassicalFields copies the classical tags found in the file onto a
+// track. Fields without a tag keep their value, which may come from enrichment.
+func applyClassicalFields(track *database.Track, trackInfo *TrackInfo) {
+	set := func(field *string, value string) {
+		if value != "" {
+			*field = value
+		}
+	}
+	set(&track.Composer, trackInfo.Composer)
+	set(&track.Work, trackInfo.Work)
+	set(&track.Movement, trackInfo.Movement)
+	set(&track.Conductor, trackInfo.Conductor)
+	set(&track.Orchestra, trackInfo.Orchestra)
+	if trackInfo.MovementNumber > 0 {
+		track.MovementNumber = trackInfo.MovementNumber
+	}
+	if trackInfo.MovementCount > 0 {
+		track.MovementCount = trackInfo.MovementCount
+	}
+}
+
+// rawTag returns the first non-empty raw tag among the names, matching frame
+// IDs and comment names case-insensitively and TXXX frames by description
+func rawTag(raw map[string]interface{}, names ...string) string {
+	for _, name := range names {
+		for key, value := range raw {
+			var text string
+			switch v := value.(type) {
+			case string:
+				if strings.EqualFold(key, name) {
+					text = v
+				}
+			case *tag.Comm:
+				// User-defined text frames may be stored as TXXX, TXXX_0, ...
+				if strings.EqualFold(v.Description, name) {
+					text = v.Text
+				}
+			}
+			if text = strings.TrimSpace(text); text != "" {
+				return text
+			}
+		}
+	}
+	return ""
+}
+
+// romanToInt converts a movement's roman numeral, returning 0 if it is not one
+func romanToInt(numeral string) int {
+	values := map[rune]int{'I': 1, 'V': 5, 'X': 10, 'L': 50, 'C': 100}
+	total, previous := 0, 0
+	for i := len(numeral) - 1; i >= 0; i-- {
+		value, ok := values[rune(numeral[i])]
+		if !ok {
+			return 0
+		}
+		if value < previous {
+			total -= value
+		} else {
+			total += value
+			previous = value
+		}
+	}
+	return total
+}
@@ -1,12 +1,15 @@
 package enrichment
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"io/fs"
 	"log"
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/dhowden/tag"
@@ -15,6 +18,7 @@ import (
 	"github.com/mantonx/viewra/internal/modules/assetmodule"
 	"github.com/mantonx/viewra/internal/modules/pluginmodule"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // Register Enrichment core plugin with the correct pluginmodule registry
@@ -28,6 +32,15 @@ func init() {
 type EnrichmentCorePlugin struct {
 	enabled bool
 	db      *gorm.DB
+
+	// Box set layout of release folders, keyed by folder path
+	boxSets   map[string]*boxSetInfo
+	boxSetsMu sync.Mutex
+
+	// Hash of the embedded cover last saved for each album, so the tracks of
+	// an album don't each save the same art
+	embeddedCovers   map[string]string
+	embeddedCoversMu sync.Mutex
 }
 
 // NewEnrichmentCorePlugin creates a new enrichment core plugin
@@ -68,6 +81,11 @@ func (p *EnrichmentCorePlugin) Match(path string, info fs.FileInfo) bool {
 		return false
 	}
 
+	return p.isSupportedExtension(path)
+}
+
+// isSupportedExtension reports whether the file has a supported audio extension
+func (p *EnrichmentCorePlugin) isSupportedExtension(path string) bool {
 	ext := strings.ToLower(filepath.Ext(path))
 	for _, supportedExt := range p.GetSupportedExtensions() {
 		if ext == supportedExt {
@@ -131,6 +149,9 @@ func (p *EnrichmentCorePlugin) HandleFile(path string, ctx *pluginmodule.Metadat
 		}
 	}
 
+	// Work out the disc and merge "(Disc N)" albums into one release
+	p.applyDiscInfo(path, trackInfo)
+
 	// Create or get Artist
 	artist, err := p.createOrGetArtist(trackInfo.Artist)
 	if err != nil {
@@ -143,6 +164,17 @@ func (p *EnrichmentCorePlugin) HandleFile(path string, ctx *pluginmodule.Metadat
 		return fmt.Errorf("failed to create/get album: %w", err)
 	}
 
+	if err := p.updateAlbumDiscs(album, trackInfo); err != nil {
+		log.Printf("WARNING: Failed to update discs of album %s: %v", album.ID, err)
+	}
+
+	// Group albums of a box set under a parent release
+	if trackInfo.BoxSet != "" {
+		if err := p.linkBoxSet(album, artist.ID, trackInfo); err != nil {
+			log.Printf("WARNING: Failed to link album %s to box set '%s': %v", album.ID, trackInfo.BoxSet, err)
+		}
+	}
+
 	// Create or update Track
 	track, err := p.createOrUpdateTrack(trackInfo, artist.ID, album.ID)
 	if err != nil {
@@ -179,10 +211,13 @@ func (p *EnrichmentCorePlugin) HandleFile(path string, ctx *pluginmodule.Metadat
 		// Not a fatal error - continue without enrichment tracking
 	}
 
-	// Extract and save artwork if present
-	if err := p.extractAndSaveArtwork(path, album.ID); err != nil {
-		log.Printf("WARNING: Failed to extract artwork from %s: %v", path, err)
-		// Not a fatal error - continue without artwork
+	// Save the cover art embedded in the file, if any. Remote art is only
+	// fetched for albums without embedded art or with low resolution art.
+	if trackInfo.Artwork != nil {
+		if err := p.saveEmbeddedArtwork(trackInfo.Artwork, album.ID); err != nil {
+			log.Printf("WARNING: Failed to save embedded artwork from %s: %v", path, err)
+			// Not a fatal error - continue without artwork
+		}
 	}
 
 	// NEW: Scan for external artwork files in the album directory
@@ -203,8 +238,27 @@ type TrackInfo struct {
 	Album       string
 	Genre       string
 	Year        int
+	DiscNumber  int
+	DiscTotal   int
 	TrackNumber int
 	Duration    int
+
+	// Classical music fields
+	Composer       string
+	Work           string
+	Movement       string
+	MovementNumber int
+	MovementCount  int
+	Conductor      string
+	Orchestra      string
+
+	// Set when the album is one of several in a box set folder
+	BoxSet         string
+	BoxSetPosition int
+	BoxSetDiscs    int
+
+	// Cover art embedded in the file's tags
+	Artwork *tag.Picture
 }
 
 // extractMetadata extracts metadata from a music file using tag library
@@ -256,6 +310,19 @@ func (p *EnrichmentCorePlugin) extractMetadata(path string) (*TrackInfo, error)
 		trackInfo.TrackNumber = trackNum
 	}
 
+	// Parse disc number and total
+	if discNum, discTotal := metadata.Disc(); discNum != 0 {
+		trackInfo.DiscNumber = discNum
+		trackInfo.DiscTotal = discTotal
+	}
+
+	// Composer, work and movement for classical recordings
+	applyClassicalTags(metadata, trackInfo)
+
+	if artwork := metadata.Picture(); artwork != nil && len(artwork.Data) > 0 {
+		trackInfo.Artwork = artwork
+	}
+
 	// Get file info for additional metadata
 	if fileInfo, err := os.Stat(path); err == nil {
 		trackInfo.Duration = int(p.estimateDuration(fileInfo.Size(), path).Seconds())
@@ -266,161 +333,80 @@ func (p *EnrichmentCorePlugin) extractMetadata(path string) (*TrackInfo, error)
 
 // createOrGetArtist creates a new artist or returns existing one
 func (p *EnrichmentCorePlugin) createOrGetArtist(artistName string) (*database.Artist, error) {
-	// First, try to get existing artist (handles most cases efficiently)
 	var artist database.Artist
 	result := p.db.Where("name = ?", artistName).First(&artist)
 	if result.Error == nil {
 		return &artist, nil
 	}
 
-	// If not found, use transaction with retry to handle race conditions
-	for attempt := 0; attempt < 3; attempt++ {
-		// Start transaction
-		tx := p.db.Begin()
-		if tx.Error != nil {
-			return nil, fmt.Errorf("failed to start transaction: %w", tx.Error)
-		}
-
-		// Try to get existing artist again within transaction
-		var existingArtist database.Artist
-		err := tx.Where("name = ?", artistName).First(&existingArtist).Error
-		if err == nil {
-			// Found existing artist, rollback and return it
-			tx.Rollback()
-			return &existingArtist, nil
-		}
-
-		// Create new artist with unique constraint handling
-		newArtist := database.Artist{
-			ID:   uuid.New().String(),
-			Name: artistName,
-		}
-
-		err = tx.Create(&newArtist).Error
-		if err != nil {
-			tx.Rollback()
-			
-			// Check if it's a unique constraint violation (duplicate name)
-			if strings.Contains(strings.ToLower(err.Error()), "unique") || 
-			   strings.Contains(strings.ToLower(err.Error()), "duplicate") {
-				
-				log.Printf("DEBUG: Detected concurrent artist creation for '%s', retrying (attempt %d)", artistName, attempt+1)
-				
-				// Another thread created the artist, try to get it
-				var concurrentArtist database.Artist
-				if retryErr := p.db.Where("name = ?", artistName).First(&concurrentArtist).Error; retryErr == nil {
-					return &concurrentArtist, nil
-				}
-				
-				// If still not found, retry the whole process
-				if attempt < 2 {
-					continue
-				}
-			}
-			
-			return nil, fmt.Errorf("failed to create artist after %d attempts: %w", attempt+1, err)
-		}
-
-		// Commit transaction
-		if err := tx.Commit().Error; err != nil {
-			return nil, fmt.Errorf("failed to commit artist creation: %w", err)
+	// Artists are unique by name, so a worker that loses the race to create
+	// one loads the winner's row instead
+	artist = database.Artist{
+		ID:   uuid.New().String(),
+		Name: artistName,
+	}
+	created := p.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&artist)
+	if created.Error != nil {
+		return nil, fmt.Errorf("failed to create artist: %w", created.Error)
+	}
+	if created.RowsAffected == 0 {
+		if err := p.db.Where("name = ?", artistName).First(&artist).Error; err != nil {
+			return nil, fmt.Errorf("failed to load artist: %w", err)
 		}
-
-		log.Printf("INFO: Created new artist: %s (ID: %s)", artistName, newArtist.ID)
-		return &newArtist, nil
+		return &artist, nil
 	}
 
-	return nil, fmt.Errorf("failed to create or get artist '%s' after 3 attempts", artistName)
+	log.Printf("INFO: Created new artist: %s (ID: %s)", artistName, artist.ID)
+	return &artist, nil
 }
 
 // createOrGetAlbum creates a new album or returns existing one
 func (p *EnrichmentCorePlugin) createOrGetAlbum(albumTitle string, artistID string, year int) (*database.Album, error) {
-	// First, try to get existing album (handles most cases efficiently)
 	var album database.Album
 	result := p.db.Where("title = ? AND artist_id = ?", albumTitle, artistID).First(&album)
 	if result.Error == nil {
 		return &album, nil
 	}
 
-	// If not found, use transaction with retry to handle race conditions
-	for attempt := 0; attempt < 3; attempt++ {
-		// Start transaction
-		tx := p.db.Begin()
-		if tx.Error != nil {
-			return nil, fmt.Errorf("failed to start transaction: %w", tx.Error)
-		}
-
-		// Try to get existing album again within transaction
-		var existingAlbum database.Album
-		err := tx.Where("title = ? AND artist_id = ?", albumTitle, artistID).First(&existingAlbum).Error
-		if err == nil {
-			// Found existing album, rollback and return it
-			tx.Rollback()
-			return &existingAlbum, nil
-		}
-
-		// Create new album with unique constraint handling
-		newAlbum := database.Album{
-			ID:       uuid.New().String(),
-			Title:    albumTitle,
-			ArtistID: artistID,
-		}
-
-		// Set release date if year is provided
-		if year > 0 {
-			releaseDate := time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC)
-			newAlbum.ReleaseDate = &releaseDate
-		}
-
-		err = tx.Create(&newAlbum).Error
-		if err != nil {
-			tx.Rollback()
-			
-			// Check if it's a unique constraint violation (duplicate title+artist)
-			if strings.Contains(strings.ToLower(err.Error()), "unique") || 
-			   strings.Contains(strings.ToLower(err.Error()), "duplicate") {
-				
-				log.Printf("DEBUG: Detected concurrent album creation for '%s' by artist %s, retrying (attempt %d)", albumTitle, artistID, attempt+1)
-				
-				// Another thread created the album, try to get it
-				var concurrentAlbum database.Album
-				if retryErr := p.db.Where("title = ? AND artist_id = ?", albumTitle, artistID).First(&concurrentAlbum).Error; retryErr == nil {
-					return &concurrentAlbum, nil
-				}
-				
-				// If still not found, retry the whole process
-				if attempt < 2 {
-					continue
-				}
-			}
-			
-			return nil, fmt.Errorf("failed to create album after %d attempts: %w", attempt+1, err)
-		}
-
-		// Commit transaction
-		if err := tx.Commit().Error; err != nil {
-			return nil, fmt.Errorf("failed to commit album creation: %w", err)
+	// Albums are unique by title and artist, so a worker that loses the race
+	// to create one loads the winner's row instead
+	album = database.Album{
+		ID:       uuid.New().String(),
+		Title:    albumTitle,
+		ArtistID: artistID,
+	}
+	if year > 0 {
+		releaseDate := time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC)
+		album.ReleaseDate = &releaseDate
+	}
+	created := p.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&album)
+	if created.Error != nil {
+		return nil, fmt.Errorf("failed to create album: %w", created.Error)
+	}
+	if created.RowsAffected == 0 {
+		if err := p.db.Where("title = ? AND artist_id = ?", albumTitle, artistID).First(&album).Error; err != nil {
+			return nil, fmt.Errorf("failed to load album: %w", err)
 		}
-
-		log.Printf("INFO: Created new album: '%s' for artist %s (ID: %s)", albumTitle, artistID, newAlbum.ID)
-		return &newAlbum, nil
+		return &album, nil
 	}
 
-	return nil, fmt.Errorf("failed to create or get album '%s' for artist %s after 3 attempts", albumTitle, artistID)
+	log.Printf("INFO: Created new album: '%s' for artist %s (ID: %s)", albumTitle, artistID, album.ID)
+	return &album, nil
 }
 
 // createOrUpdateTrack creates a new track or updates existing one
 func (p *EnrichmentCorePlugin) createOrUpdateTrack(trackInfo *TrackInfo, artistID string, albumID string) (*database.Track, error) {
 	var track database.Track
 
-	// Check if track already exists for this album
-	result := p.db.Where("title = ? AND album_id = ?", trackInfo.Title, albumID).First(&track)
+	// Check if track already exists on this disc of the album
+	result := p.db.Where("title = ? AND album_id = ? AND disc_number = ?", trackInfo.Title, albumID, trackInfo.DiscNumber).First(&track)
 
 	if result.Error == nil {
 		// Update existing track
 		track.ArtistID = artistID
 		track.TrackNumber = trackInfo.TrackNumber
 		track.Duration = trackInfo.Duration
+		applyClassicalFields(&track, trackInfo)
 
 		if err := p.db.Save(&track).Error; err != nil {
 			return nil, fmt.Errorf("failed to update track: %w", err)
@@ -435,9 +421,11 @@ func (p *EnrichmentCorePlugin) createOrUpdateTrack(trackInfo *TrackInfo, artistI
 		Title:       trackInfo.Title,
 		AlbumID:     albumID,
 		ArtistID:    artistID,
+		DiscNumber:  trackInfo.DiscNumber,
 		TrackNumber: trackInfo.TrackNumber,
 		Duration:    trackInfo.Duration,
 	}
+	applyClassicalFields(&track, trackInfo)
 
 	if err := p.db.Create(&track).Error; err != nil {
 		return nil, fmt.Errorf("failed to create track: %w", err)
@@ -456,24 +444,17 @@ func (p *EnrichmentCorePlugin) linkMediaFileToTrack(mediaFileID string, trackID
 		}).Error
 }
 
-// extractAndSaveArtwork extracts and saves artwork from a music file
-func (p *EnrichmentCorePlugin) extractAndSaveArtwork(path string, albumID string) error {
-	file, err := os.Open(path)
-	if err != nil {
-		return fmt.Errorf("failed to open file: %w", err)
-	}
-	defer file.Close()
-
-	// Parse tags to get artwork
-	metadata, err := tag.ReadFrom(file)
-	if err != nil {
-		return fmt.Errorf("failed to read tags: %w", err)
-	}
-
-	artwork := metadata.Picture()
-	if artwork == nil || len(artwork.Data) == 0 {
-		return fmt.Errorf("no artwork found in file")
+// saveEmbeddedArtwork saves the cover art embedded in a music file as its
+// album's cover. Art already saved for the album is skipped.
+func (p *EnrichmentCorePlugin) saveEmbeddedArtwork(artwork *tag.Picture, albumID string) error {
+	sum := sha256.Sum256(artwork.Data)
+	hash := hex.EncodeToString(sum[:])
+	p.embeddedCoversMu.Lock()
+	if p.embeddedCovers[albumID] == hash {
+		p.embeddedCoversMu.Unlock()
+		return nil
 	}
+	p.embeddedCoversMu.Unlock()
 
 	// Detect MIME type
 	mimeType := p.detectImageMimeType(artwork.Data)
@@ -505,13 +486,21 @@ func (p *EnrichmentCorePlugin) extractAndSaveArtwork(path string, albumID string
 		Preferred:  true,
 	}
 
-	// Save the artwork
-	_, err = assetManager.SaveAsset(request)
+	// Save the artwork. Low resolution art doesn't take the preference from
+	// a larger cover already fetched for the album.
+	saved, err := assetManager.SaveAsset(request)
 	if err != nil {
 		return fmt.Errorf("failed to save artwork: %w", err)
 	}
 
-	log.Printf("INFO: Saved artwork for album %s", albumID)
+	p.embeddedCoversMu.Lock()
+	if p.embeddedCovers == nil {
+		p.embeddedCovers = make(map[string]string)
+	}
+	p.embeddedCovers[albumID] = hash
+	p.embeddedCoversMu.Unlock()
+
+	log.Printf("INFO: Saved embedded artwork for album %s (%dx%d, preferred: %t)", albumID, saved.Width, saved.Height, saved.Preferred)
 	return nil
 }
 
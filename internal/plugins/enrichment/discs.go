@@ -0,0 +1,263 @@
+package enrichment
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/dhowden/tag"
+	"github.com/mantonx/viewra/internal/database"
+)
+
+var (
+	// discFolderPattern matches disc folders such as "CD1", "Disc 2" or "disk_03 - Bonus"
+	discFolderPattern = regexp.MustCompile(`(?i)^(?:cd|disc|disk)[\s._-]*(\d{1,3})\b`)
+
+	// discSuffixPattern matches a disc marker at the end of an album title,
+	// e.g. "Greatest Hits (Disc 2)", "Live [CD 1]" or "Anthology - Disk 3"
+	discSuffixPattern = regexp.MustCompile(`(?i)\s*(?:[(\[]\s*(?:cd|disc|disk)[\s._]*(\d{1,3})\s*[)\]]|[-:,]?\s+(?:cd|disc|disk)[\s._]*(\d{1,3}))\s*$`)
+)
+
+// boxSetInfo describes a release folder whose disc folders hold different
+// albums, e.g. "The Complete Studio Recordings/CD1..CD8"
+type boxSetInfo struct {
+	Title string
+	// Discs maps a disc folder name to its album's place in the box set
+	Discs map[string]boxSetDisc
+}
+
+// boxSetDisc is one disc folder of a box set
+type boxSetDisc struct {
+	Position   int // Position of the disc's album within the box set
+	DiscNumber int // Disc number within that album
+}
+
+// parseDiscFolder returns the disc number of a disc folder name
+func parseDiscFolder(name string) (int, bool) {
+	match := discFolderPattern.FindStringSubmatch(strings.TrimSpace(name))
+	if match == nil {
+		return 0, false
+	}
+	disc, err := strconv.Atoi(match[1])
+	if err != nil || disc == 0 {
+		return 0, false
+	}
+	return disc, true
+}
+
+// splitDiscSuffix strips a disc marker from an album title and returns the
+// disc number it named, or 0 when the title has none
+func splitDiscSuffix(album string) (string, int) {
+	match := discSuffixPattern.FindStringSubmatchIndex(album)
+	if match == nil {
+		return album, 0
+	}
+	title := strings.TrimSpace(album[:match[0]])
+	if title == "" {
+		return album, 0
+	}
+
+	number := ""
+	for group := 1; group <= 2; group++ {
+		if start := match[2*group]; start >= 0 {
+			number = album[start:match[2*group+1]]
+		}
+	}
+	disc, err := strconv.Atoi(number)
+	if err != nil || disc == 0 {
+		return album, 0
+	}
+	return title, disc
+}
+
+// applyDiscInfo fills in the disc number of a track and removes disc markers
+// from its album title so the discs of a release share one album. Tags take
+// precedence, then the album title, then a disc folder the file sits in.
+func (p *EnrichmentCorePlugin) applyDiscInfo(path string, trackInfo *TrackInfo) {
+	title, titleDisc := splitDiscSuffix(trackInfo.Album)
+	trackInfo.Album = title
+
+	discDir := filepath.Dir(path)
+	folderDisc, inDiscFolder := parseDiscFolder(filepath.Base(discDir))
+
+	switch {
+	case trackInfo.DiscNumber > 0:
+	case titleDisc > 0:
+		trackInfo.DiscNumber = titleDisc
+	case inDiscFolder:
+		trackInfo.DiscNumber = folderDisc
+	default:
+		trackInfo.DiscNumber = 1
+	}
+	if trackInfo.DiscTotal < trackInfo.DiscNumber {
+		trackInfo.DiscTotal = trackInfo.DiscNumber
+	}
+
+	if !inDiscFolder {
+		return
+	}
+
+	box := p.detectBoxSet(filepath.Dir(discDir))
+	if box == nil {
+		return
+	}
+	disc, ok := box.Discs[filepath.Base(discDir)]
+	if !ok {
+		return
+	}
+
+	// Box set tags usually number discs across the whole set, so count
+	// discs within the album instead
+	trackInfo.BoxSet = box.Title
+	trackInfo.BoxSetPosition = disc.Position
+	trackInfo.BoxSetDiscs = len(box.Discs)
+	trackInfo.DiscNumber = disc.DiscNumber
+	trackInfo.DiscTotal = 0
+	for _, other := range box.Discs {
+		if other.Position == disc.Position && other.DiscNumber > trackInfo.DiscTotal {
+			trackInfo.DiscTotal = other.DiscNumber
+		}
+	}
+}
+
+// detectBoxSet reports whether the disc folders of a release folder hold more
+// than one album. The first audio file of each disc folder names its album.
+// Results are cached per folder for the rest of the scan.
+func (p *EnrichmentCorePlugin) detectBoxSet(releaseDir string) *boxSetInfo {
+	p.boxSetsMu.Lock()
+	defer p.boxSetsMu.Unlock()
+
+	if box, ok := p.boxSets[releaseDir]; ok {
+		return box
+	}
+
+	box := p.readBoxSet(releaseDir)
+	if p.boxSets == nil {
+		p.boxSets = make(map[string]*boxSetInfo)
+	}
+	p.boxSets[releaseDir] = box
+	return box
+}
+
+// readBoxSet reads the album titles of the disc folders in a release folder
+func (p *EnrichmentCorePlugin) readBoxSet(releaseDir string) *boxSetInfo {
+	entries, err := os.ReadDir(releaseDir)
+	if err != nil {
+		return nil
+	}
+
+	type discFolder struct {
+		name   string
+		number int
+		album  string
+	}
+	var folders []discFolder
+	albums := make(map[string]bool)
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		number, ok := parseDiscFolder(entry.Name())
+		if !ok {
+			continue
+		}
+		album := p.firstAlbumTitle(filepath.Join(releaseDir, entry.Name()))
+		if album == "" {
+			continue
+		}
+		folders = append(folders, discFolder{name: entry.Name(), number: number, album: album})
+		albums[strings.ToLower(album)] = true
+	}
+	if len(albums) < 2 {
+		return nil
+	}
+
+	sort.Slice(folders, func(i, j int) bool { return folders[i].number < folders[j].number })
+
+	box := &boxSetInfo{
+		Title: filepath.Base(releaseDir),
+		Discs: make(map[string]boxSetDisc, len(folders)),
+	}
+	positions := make(map[string]int)
+	discCounts := make(map[string]int)
+	for _, folder := range folders {
+		key := strings.ToLower(folder.album)
+		if _, ok := positions[key]; !ok {
+			positions[key] = len(positions) + 1
+		}
+		discCounts[key]++
+		box.Discs[folder.name] = boxSetDisc{Position: positions[key], DiscNumber: discCounts[key]}
+	}
+	return box
+}
+
+// firstAlbumTitle returns the album tag of the first supported audio file in
+// a folder, without any disc marker
+func (p *EnrichmentCorePlugin) firstAlbumTitle(dir string) string {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return ""
+	}
+	for _, entry := range entries {
+		if entry.IsDir() || !p.isSupportedExtension(entry.Name()) {
+			continue
+		}
+		file, err := os.Open(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			continue
+		}
+		metadata, err := tag.ReadFrom(file)
+		file.Close()
+		if err != nil || metadata.Album() == "" {
+			continue
+		}
+		title, _ := splitDiscSuffix(metadata.Album())
+		return title
+	}
+	return ""
+}
+
+// updateAlbumDiscs raises an album's disc count to cover the track's disc
+func (p *EnrichmentCorePlugin) updateAlbumDiscs(album *database.Album, trackInfo *TrackInfo) error {
+	discs := trackInfo.DiscTotal
+	if trackInfo.DiscNumber > discs {
+		discs = trackInfo.DiscNumber
+	}
+	if discs <= album.DiscCount {
+		return nil
+	}
+	album.DiscCount = discs
+	return p.db.Model(&database.Album{}).Where("id = ? AND disc_count < ?", album.ID, discs).
+		Update("disc_count", discs).Error
+}
+
+// linkBoxSet files an album under the box set release it was found in,
+// creating the box set album on first use
+func (p *EnrichmentCorePlugin) linkBoxSet(album *database.Album, artistID string, trackInfo *TrackInfo) error {
+	box, err := p.createOrGetAlbum(trackInfo.BoxSet, artistID, 0)
+	if err != nil {
+		return err
+	}
+	if box.ID == album.ID {
+		return nil
+	}
+
+	if err := p.db.Model(&database.Album{}).Where("id = ?", box.ID).
+		Updates(map[string]interface{}{
+			"is_box_set": true,
+			"disc_count": trackInfo.BoxSetDiscs,
+		}).Error; err != nil {
+		return err
+	}
+
+	album.ParentAlbumID = &box.ID
+	album.BoxSetPosition = trackInfo.BoxSetPosition
+	return p.db.Model(&database.Album{}).Where("id = ?", album.ID).
+		Updates(map[string]interface{}{
+			"parent_album_id":  box.ID,
+			"box_set_position": trackInfo.BoxSetPosition,
+		}).Error
+}
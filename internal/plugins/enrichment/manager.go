@@ -121,13 +121,21 @@ func (m *Manager) OnMediaFileScanned(mediaFile *database.MediaFile, metadata int
 		return nil // Don't fail scanning if we can't get library info
 	}
 
-	// Get library-specific plugin restrictions from config
-	librarySettings, hasRestrictions := m.config.LibraryPluginRestrictions[library.Type]
+	// Home videos are personal footage that no enricher can match
+	if library.Type == "home" {
+		log.Printf("DEBUG: Skipping enrichment for home video %s", mediaFile.Path)
+		return nil
+	}
+
+	// Get library-specific plugin restrictions from config. Items in mixed
+	// libraries use the restrictions of the library type matching their own type.
+	libraryType := restrictionLibraryType(library.Type, mediaFile.MediaType)
+	librarySettings, hasRestrictions := m.config.LibraryPluginRestrictions[libraryType]
 	if !hasRestrictions {
-		log.Printf("DEBUG: No plugin restrictions configured for library type: %s", library.Type)
+		log.Printf("DEBUG: No plugin restrictions configured for library type: %s", libraryType)
 		// If no restrictions configured, use all plugins (backwards compatibility)
 	} else {
-		log.Printf("DEBUG: Applying plugin restrictions for library type: %s", library.Type)
+		log.Printf("DEBUG: Applying plugin restrictions for library type: %s", libraryType)
 	}
 
 	// Convert metadata to map[string]string for internal plugins
@@ -167,7 +175,7 @@ func (m *Manager) OnMediaFileScanned(mediaFile *database.MediaFile, metadata int
 
 		// Check if plugin is allowed for this library type
 		if hasRestrictions && !m.isPluginAllowedForLibrary(pluginName, librarySettings) {
-			log.Printf("DEBUG: Plugin %s not allowed for library type %s, skipping", pluginName, library.Type)
+			log.Printf("DEBUG: Plugin %s not allowed for library type %s, skipping", pluginName, libraryType)
 			continue
 		}
 
@@ -188,6 +196,22 @@ func (m *Manager) OnMediaFileScanned(mediaFile *database.MediaFile, metadata int
 	return nil
 }
 
+// restrictionLibraryType returns the library type whose plugin restrictions
+// apply to a file. In mixed libraries that follows the file's own type.
+func restrictionLibraryType(libraryType string, mediaType database.MediaType) string {
+	if libraryType != "mixed" {
+		return libraryType
+	}
+	switch mediaType {
+	case database.MediaTypeEpisode:
+		return "tv"
+	case database.MediaTypeTrack:
+		return "music"
+	default:
+		return "movie"
+	}
+}
+
 // isPluginAllowedForLibrary checks if a plugin is allowed for the given library type
 func (m *Manager) isPluginAllowedForLibrary(pluginName string, settings config.LibraryPluginSettings) bool {
 	// Check if plugin is explicitly disallowed
@@ -13,7 +13,9 @@ import (
 
 	"github.com/mantonx/viewra/internal/database"
 	"github.com/mantonx/viewra/internal/modules/pluginmodule"
+	"github.com/mantonx/viewra/internal/utils"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // Register FFmpeg core plugin with the correct pluginmodule registry
@@ -124,6 +126,7 @@ type AudioTechnicalInfo struct {
 	Duration   float64 // Duration in seconds
 	Codec      string  // Audio codec
 	IsLossless bool    // Whether the format is lossless
+	BitDepth   int     // Bit depth of lossless audio, 0 for lossy codecs
 }
 
 // FFmpegCorePlugin implements the CorePlugin interface for audio and video files
@@ -357,8 +360,16 @@ func (p *FFmpegCorePlugin) createOrGetArtist(db *gorm.DB, artistName string) (*d
 		artist.ID = fmt.Sprintf("artist-%s-%d", strings.ReplaceAll(strings.ToLower(artistName), " ", "-"), time.Now().Unix())
 	}
 
-	if err := db.Create(&artist).Error; err != nil {
-		return nil, fmt.Errorf("failed to create artist: %w", err)
+	// Artists are unique by name, so a worker that loses the race to create
+	// one loads the winner's row instead
+	created := db.Clauses(clause.OnConflict{DoNothing: true}).Create(&artist)
+	if created.Error != nil {
+		return nil, fmt.Errorf("failed to create artist: %w", created.Error)
+	}
+	if created.RowsAffected == 0 {
+		if err := db.Where("name = ?", artistName).First(&artist).Error; err != nil {
+			return nil, fmt.Errorf("failed to load artist: %w", err)
+		}
 	}
 
 	return &artist, nil
@@ -387,8 +398,16 @@ func (p *FFmpegCorePlugin) createOrGetAlbum(db *gorm.DB, albumTitle string, arti
 		album.ID = fmt.Sprintf("album-%s-%s-%d", artistID, strings.ReplaceAll(strings.ToLower(albumTitle), " ", "-"), time.Now().Unix())
 	}
 
-	if err := db.Create(&album).Error; err != nil {
-		return nil, fmt.Errorf("failed to create album: %w", err)
+	// Albums are unique by title and artist, so a worker that loses the race
+	// to create one loads the winner's row instead
+	created := db.Clauses(clause.OnConflict{DoNothing: true}).Create(&album)
+	if created.Error != nil {
+		return nil, fmt.Errorf("failed to create album: %w", created.Error)
+	}
+	if created.RowsAffected == 0 {
+		if err := db.Where("title = ? AND artist_id = ?", albumTitle, artistID).First(&album).Error; err != nil {
+			return nil, fmt.Errorf("failed to load album: %w", err)
+		}
 	}
 
 	return &album, nil
@@ -591,6 +610,7 @@ func (p *FFmpegCorePlugin) extractAudioTechnicalInfo(filePath string) (*AudioTec
 
 	// Determine if format is lossless
 	info.IsLossless = p.isLosslessFormat(info.Format, info.Codec)
+	info.BitDepth = utils.AudioBitDepth(info.Codec, audioStream.BitsPerRawSample, audioStream.BitsPerSample, audioStream.SampleFmt)
 
 	debugLog("SUCCESS: FFprobe extraction complete for %s - Format: %s, Bitrate: %d, SampleRate: %d, Channels: %d\n",
 		filePath, info.Format, info.Bitrate, info.SampleRate, info.Channels)
@@ -645,18 +665,7 @@ func (p *FFmpegCorePlugin) isLosslessFormat(format, codec string) bool {
 		"wv":   true, // WavPack
 	}
 
-	losslessCodecs := map[string]bool{
-		"flac":      true,
-		"pcm_s16le": true,
-		"pcm_s24le": true,
-		"pcm_s32le": true,
-		"pcm_f32le": true,
-		"pcm_f64le": true,
-		"ape":       true,
-		"wavpack":   true,
-	}
-
-	return losslessFormats[format] || losslessCodecs[codec]
+	return losslessFormats[format] || utils.IsLosslessAudioCodec(codec)
 }
 
 // getMediaType determines if a file is audio or video based on extension
@@ -722,6 +731,8 @@ func (p *FFmpegCorePlugin) updateMediaFileWithTechnicalInfo(filePath string, med
 			// Enhanced audio fields
 			"audio_channels":    audioInfo.Channels,
 			"audio_sample_rate": audioInfo.SampleRate,
+			"audio_bit_depth":   audioInfo.BitDepth,
+			"audio_lossless":    audioInfo.IsLossless,
 			"audio_language":    "und", // undetermined, could be enhanced later
 		}
 
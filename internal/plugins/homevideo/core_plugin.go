@@ -0,0 +1,360 @@
+package homevideo
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/mantonx/viewra/internal/database"
+	"github.com/mantonx/viewra/internal/modules/assetmodule"
+	"github.com/mantonx/viewra/internal/modules/pluginmodule"
+)
+
+// Register Home Video core plugin with the global registry
+func init() {
+	pluginmodule.RegisterCorePluginFactory("home_video", func() pluginmodule.CorePlugin {
+		return NewHomeVideoCorePlugin()
+	})
+}
+
+// thumbnailTimeout bounds the ffmpeg frame grab for one video
+const thumbnailTimeout = 30 * time.Second
+
+// creationDateTags are the container tags holding the recording date, most
+// precise first. QuickTime's creationdate keeps the camera's time zone.
+var creationDateTags = []string{"com.apple.quicktime.creationdate", "creation_time", "date"}
+
+// filenameDatePattern matches camera file names such as VID_20230514_102231,
+// PXL_20230514_102231123 or "2023-05-14 10.22.31"
+var filenameDatePattern = regexp.MustCompile(`((?:19|20)\d{2})[-_.]?(\d{2})[-_.]?(\d{2})(?:[-_. T]?(\d{2})[-_.:]?(\d{2})[-_.:]?(\d{2}))?`)
+
+// cameraPrefixPattern matches the prefixes cameras and phones put before the date
+var cameraPrefixPattern = regexp.MustCompile(`(?i)^(vid|mvi|mov|img|pxl|dji|gopr|gh\d*|dsc|clip)?[\s_-]*`)
+
+// HomeVideoCorePlugin implements the CorePlugin interface for personal
+// footage in home video libraries. Videos are organized by recording date and
+// never sent to external enrichers.
+type HomeVideoCorePlugin struct {
+	name          string
+	supportedExts []string
+	enabled       bool
+	initialized   bool
+}
+
+// NewHomeVideoCorePlugin creates a new home video core plugin instance
+func NewHomeVideoCorePlugin() pluginmodule.CorePlugin {
+	return &HomeVideoCorePlugin{
+		name:    "home_video_core_plugin",
+		enabled: true,
+		supportedExts: []string{
+			// Formats written by phones, camcorders and action cameras
+			".mp4", ".mov", ".m4v", ".mts", ".m2ts", ".avi", ".mkv",
+			".3gp", ".mpg", ".mpeg", ".wmv", ".webm",
+		},
+	}
+}
+
+// GetName returns the plugin name (implements FileHandlerPlugin)
+func (p *HomeVideoCorePlugin) GetName() string {
+	return p.name
+}
+
+// GetPluginType returns the plugin type (implements FileHandlerPlugin)
+func (p *HomeVideoCorePlugin) GetPluginType() string {
+	return "home_video_parser"
+}
+
+// GetType returns the plugin type (implements BasePlugin)
+func (p *HomeVideoCorePlugin) GetType() string {
+	return "home_video"
+}
+
+// GetDisplayName returns a human-readable display name for the plugin (implements CorePlugin)
+func (p *HomeVideoCorePlugin) GetDisplayName() string {
+	return "Home Video Core Plugin"
+}
+
+// GetSupportedExtensions returns the file extensions this plugin supports (implements FileHandlerPlugin)
+func (p *HomeVideoCorePlugin) GetSupportedExtensions() []string {
+	return p.supportedExts
+}
+
+// IsEnabled returns whether the plugin is enabled (implements CorePlugin)
+func (p *HomeVideoCorePlugin) IsEnabled() bool {
+	return p.enabled
+}
+
+// Enable enables the plugin (implements CorePlugin)
+func (p *HomeVideoCorePlugin) Enable() error {
+	p.enabled = true
+	return p.Initialize()
+}
+
+// Disable disables the plugin (implements CorePlugin)
+func (p *HomeVideoCorePlugin) Disable() error {
+	p.enabled = false
+	return p.Shutdown()
+}
+
+// Initialize performs any setup needed for the plugin (implements CorePlugin)
+func (p *HomeVideoCorePlugin) Initialize() error {
+	if p.initialized {
+		return nil
+	}
+
+	fmt.Printf("DEBUG: Initializing Home Video Core Plugin\n")
+	p.initialized = true
+	fmt.Printf("✅ Home Video plugin initialized - Recording dates and thumbnails available\n")
+	return nil
+}
+
+// Shutdown performs any cleanup needed when the plugin is disabled (implements CorePlugin)
+func (p *HomeVideoCorePlugin) Shutdown() error {
+	fmt.Printf("DEBUG: Shutting down Home Video Core Plugin\n")
+	p.initialized = false
+	return nil
+}
+
+// Match determines if this plugin can handle the given file (implements FileHandlerPlugin)
+func (p *HomeVideoCorePlugin) Match(path string, info fs.FileInfo) bool {
+	if !p.enabled || !p.initialized || info.IsDir() {
+		return false
+	}
+
+	ext := strings.ToLower(filepath.Ext(path))
+	for _, supportedExt := range p.supportedExts {
+		if ext == supportedExt {
+			return true
+		}
+	}
+	return false
+}
+
+// HandleFile creates a home video for a file in a home video library (implements FileHandlerPlugin)
+func (p *HomeVideoCorePlugin) HandleFile(path string, ctx *pluginmodule.MetadataContext) error {
+	if !p.enabled || !p.initialized {
+		return fmt.Errorf("Home Video plugin is disabled or not initialized")
+	}
+	if ctx.MediaFile == nil || ctx.MediaFile.LibraryID == 0 {
+		return nil
+	}
+
+	db := ctx.DB
+
+	// Only process files from home video libraries
+	var library database.MediaLibrary
+	if err := db.First(&library, ctx.MediaFile.LibraryID).Error; err != nil {
+		return fmt.Errorf("failed to get library info: %w", err)
+	}
+	if library.Type != "home" {
+		return nil
+	}
+
+	recordedAt, dateSource := p.recordingDate(path)
+
+	video := &database.HomeVideo{
+		ID:         uuid.New().String(),
+		LibraryID:  library.ID,
+		Title:      defaultTitle(path, recordedAt),
+		Tags:       "[]",
+		RecordedAt: recordedAt,
+		DateSource: dateSource,
+		CreatedAt:  time.Now(),
+		UpdatedAt:  time.Now(),
+	}
+	if err := db.Create(video).Error; err != nil {
+		return fmt.Errorf("failed to create home video: %w", err)
+	}
+
+	ctx.MediaFile.MediaID = video.ID
+	ctx.MediaFile.MediaType = database.MediaTypeHomeVideo
+	if err := db.Save(ctx.MediaFile).Error; err != nil {
+		return fmt.Errorf("failed to update media file: %w", err)
+	}
+
+	if err := p.saveThumbnail(video.ID, path, ctx.MediaFile.Duration); err != nil {
+		fmt.Printf("WARN: Failed to generate thumbnail for %s: %v\n", path, err)
+	}
+
+	fmt.Printf("✅ Created home video: %s (%s from %s) -> Media File: %s\n",
+		video.Title, formatDate(recordedAt), dateSource, ctx.MediaFile.ID)
+	return nil
+}
+
+// recordingDate returns when a video was recorded and where the date came
+// from: the container's creation tags, a date in the file name, or the
+// file's modification time
+func (p *HomeVideoCorePlugin) recordingDate(path string) (*time.Time, string) {
+	if date := containerDate(path); date != nil {
+		return date, "container"
+	}
+	if date := filenameDate(filepath.Base(path)); date != nil {
+		return date, "filename"
+	}
+	if info, err := os.Stat(path); err == nil {
+		modTime := info.ModTime()
+		return &modTime, "file"
+	}
+	return nil, ""
+}
+
+// containerDate reads the recording date from the container or video stream tags
+func containerDate(path string) *time.Time {
+	output, err := exec.Command("ffprobe", "-v", "quiet", "-print_format", "json",
+		"-show_format", "-show_streams", path).Output()
+	if err != nil {
+		return nil
+	}
+
+	var probe struct {
+		Format struct {
+			Tags map[string]string `json:"tags"`
+		} `json:"format"`
+		Streams []struct {
+			CodecType string            `json:"codec_type"`
+			Tags      map[string]string `json:"tags"`
+		} `json:"streams"`
+	}
+	if err := json.Unmarshal(output, &probe); err != nil {
+		return nil
+	}
+
+	tagSets := []map[string]string{probe.Format.Tags}
+	for _, stream := range probe.Streams {
+		if stream.CodecType == "video" {
+			tagSets = append(tagSets, stream.Tags)
+		}
+	}
+	for _, tag := range creationDateTags {
+		for _, tags := range tagSets {
+			for key, value := range tags {
+				if strings.EqualFold(key, tag) {
+					if date := parseCreationDate(value); date != nil {
+						return date
+					}
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// parseCreationDate parses a container date, ignoring the zero dates
+// cameras without a clock write (1904 and 1970 epochs)
+func parseCreationDate(value string) *time.Time {
+	layouts := []string{
+		time.RFC3339Nano,
+		"2006-01-02T15:04:05-0700",
+		"2006-01-02T15:04:05.000000Z",
+		"2006-01-02 15:04:05",
+		"2006:01:02 15:04:05",
+		"2006-01-02",
+	}
+	value = strings.TrimSpace(value)
+	for _, layout := range layouts {
+		if date, err := time.Parse(layout, value); err == nil {
+			if date.Year() <= 1970 {
+				return nil
+			}
+			return &date
+		}
+	}
+	return nil
+}
+
+// filenameDate reads a recording date written into a file name
+func filenameDate(name string) *time.Time {
+	match := filenameDatePattern.FindStringSubmatch(name)
+	if match == nil {
+		return nil
+	}
+
+	value := match[1] + match[2] + match[3]
+	layout := "20060102"
+	if match[4] != "" {
+		value += match[4] + match[5] + match[6]
+		layout += "150405"
+	}
+	date, err := time.ParseInLocation(layout, value, time.Local)
+	if err != nil {
+		return nil
+	}
+	return &date
+}
+
+// defaultTitle names a video after its file, or after its recording date
+// when the file name is only a camera code and a date
+func defaultTitle(path string, recordedAt *time.Time) string {
+	name := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	rest := filenameDatePattern.ReplaceAllString(name, "")
+	rest = cameraPrefixPattern.ReplaceAllString(rest, "")
+	if recordedAt != nil && !strings.ContainsAny(strings.ToLower(rest), "abcdefghijklmnopqrstuvwxyz") {
+		return recordedAt.Format("January 2, 2006 15:04")
+	}
+
+	title := strings.NewReplacer("_", " ", ".", " ").Replace(name)
+	return strings.Join(strings.Fields(title), " ")
+}
+
+// formatDate formats an optional date for logging
+func formatDate(date *time.Time) string {
+	if date == nil {
+		return "unknown date"
+	}
+	return date.Format("2006-01-02")
+}
+
+// saveThumbnail grabs a frame a tenth of the way in and stores it as the
+// video's thumbnail
+func (p *HomeVideoCorePlugin) saveThumbnail(videoID, path string, durationSeconds int) error {
+	ctx, cancel := context.WithTimeout(context.Background(), thumbnailTimeout)
+	defer cancel()
+
+	offset := durationSeconds / 10
+	if offset > 60 {
+		offset = 60
+	}
+
+	var output bytes.Buffer
+	cmd := exec.CommandContext(ctx, "ffmpeg",
+		"-hide_banner", "-loglevel", "error",
+		"-ss", fmt.Sprintf("%d", offset),
+		"-i", path,
+		"-frames:v", "1",
+		"-vf", "scale=640:-2",
+		"-f", "image2", "-c:v", "mjpeg",
+		"pipe:1")
+	cmd.Stdout = &output
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("ffmpeg failed: %w", err)
+	}
+	if output.Len() == 0 {
+		return fmt.Errorf("ffmpeg produced no frame")
+	}
+
+	videoUUID, err := uuid.Parse(videoID)
+	if err != nil {
+		return fmt.Errorf("invalid home video ID format: %w", err)
+	}
+
+	_, err = assetmodule.SaveMediaAsset(&assetmodule.AssetRequest{
+		EntityType: assetmodule.EntityTypeHomeVideo,
+		EntityID:   videoUUID,
+		Type:       assetmodule.AssetTypeThumb,
+		Source:     assetmodule.SourceCore,
+		PluginID:   p.name,
+		Data:       output.Bytes(),
+		Format:     "image/jpeg",
+		Preferred:  true,
+	})
+	return err
+}
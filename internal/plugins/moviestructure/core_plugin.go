@@ -7,12 +7,15 @@ import (
 	"regexp"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/mantonx/viewra/internal/database"
+	"github.com/mantonx/viewra/internal/kodi"
 	"github.com/mantonx/viewra/internal/modules/pluginmodule"
 	"github.com/mantonx/viewra/internal/utils"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // Register Movie Structure core plugin with the global registry
@@ -28,6 +31,10 @@ type MovieStructureCorePlugin struct {
 	supportedExts []string
 	enabled       bool
 	initialized   bool
+
+	// movieMu serializes movie lookups and creation across scan workers;
+	// movies parsed from file names are matched by title and year only
+	movieMu sync.Mutex
 }
 
 // MovieInfo holds parsed movie information
@@ -170,8 +177,13 @@ func (p *MovieStructureCorePlugin) HandleFile(path string, ctx *pluginmodule.Met
 			return fmt.Errorf("failed to get library info: %w", err)
 		}
 
-		// Only process if this is a movie library
-		if library.Type != "movie" {
+		// Only process if this is a movie library, or a file classified as a
+		// movie in a mixed library
+		if library.Type == "mixed" && ctx.MediaFile.MediaType != database.MediaTypeMovie {
+			fmt.Printf("DEBUG: Skipping file %s - classified as %s in mixed library\n", path, ctx.MediaFile.MediaType)
+			return nil
+		}
+		if library.Type != "movie" && library.Type != "mixed" {
 			fmt.Printf("DEBUG: Skipping file %s - not from movie library (library type: %s)\n", path, library.Type)
 			return nil
 		}
@@ -191,10 +203,13 @@ func (p *MovieStructureCorePlugin) HandleFile(path string, ctx *pluginmodule.Met
 		return nil
 	}
 
+	// A Kodi NFO next to the file overrides what the file name suggests
+	nfo := p.readNFO(path, movieInfo)
+
 	fmt.Printf("DEBUG: Parsed movie info: %+v\n", movieInfo)
 
 	// Create movie structure in database
-	err = p.createMovieStructure(db, ctx.MediaFile, movieInfo, ctx.PluginID)
+	err = p.createMovieStructure(db, ctx.MediaFile, movieInfo, nfo, ctx.PluginID)
 	if err != nil {
 		return fmt.Errorf("failed to create movie structure: %w", err)
 	}
@@ -414,7 +429,7 @@ func (p *MovieStructureCorePlugin) extractAdditionalMetadata(info *MovieInfo, fi
 }
 
 // createMovieStructure creates or updates movie records in database
-func (p *MovieStructureCorePlugin) createMovieStructure(db *gorm.DB, mediaFile *database.MediaFile, movieInfo *MovieInfo, pluginID string) error {
+func (p *MovieStructureCorePlugin) createMovieStructure(db *gorm.DB, mediaFile *database.MediaFile, movieInfo *MovieInfo, nfo *kodi.Movie, pluginID string) error {
 	// Create or get the movie record
 	movie, err := p.createOrGetMovie(db, movieInfo)
 	if err != nil {
@@ -429,12 +444,22 @@ func (p *MovieStructureCorePlugin) createMovieStructure(db *gorm.DB, mediaFile *
 		return fmt.Errorf("failed to update media file: %w", err)
 	}
 
+	// Metadata and artwork kept alongside the file, e.g. by another media server
+	source := "filename"
+	if nfo != nil {
+		source = "nfo"
+		if err := p.applyNFO(db, movie, nfo); err != nil {
+			return err
+		}
+	}
+	p.importArtwork(movie, mediaFile.Path)
+
 	// Create MediaEnrichment record to track that this plugin processed the media
 	enrichment := database.MediaEnrichment{
 		MediaID:   movie.ID,
 		MediaType: database.MediaTypeMovie,
 		Plugin:    pluginID,
-		Payload:   fmt.Sprintf("{\"title\":\"%s\",\"year\":%d,\"source\":\"filename\"}", movieInfo.Title, movieInfo.Year),
+		Payload:   fmt.Sprintf("{\"title\":\"%s\",\"year\":%d,\"source\":\"%s\"}", movieInfo.Title, movieInfo.Year, source),
 		UpdatedAt: time.Now(),
 	}
 
@@ -456,6 +481,9 @@ func (p *MovieStructureCorePlugin) createMovieStructure(db *gorm.DB, mediaFile *
 
 // createOrGetMovie creates a new movie or returns existing one
 func (p *MovieStructureCorePlugin) createOrGetMovie(db *gorm.DB, movieInfo *MovieInfo) (*database.Movie, error) {
+	p.movieMu.Lock()
+	defer p.movieMu.Unlock()
+
 	var movie database.Movie
 
 	// Try to find existing movie by title and year (SQLite compatible)
@@ -487,8 +515,19 @@ func (p *MovieStructureCorePlugin) createOrGetMovie(db *gorm.DB, movieInfo *Movi
 		UpdatedAt:   time.Now(),
 	}
 
-	if err := db.Create(&movie).Error; err != nil {
-		return nil, fmt.Errorf("failed to create movie: %w", err)
+	created := db.Clauses(clause.OnConflict{DoNothing: true}).Create(&movie)
+	if created.Error != nil {
+		return nil, fmt.Errorf("failed to create movie: %w", created.Error)
+	}
+	if created.RowsAffected == 0 {
+		// Another writer already created the movie; reload it by whichever
+		// unique key conflicted
+		var existing database.Movie
+		if err := db.Where("id = ? OR (imdb_id <> '' AND imdb_id = ?) OR (tmdb_id <> '' AND tmdb_id = ?)",
+			movie.ID, movie.ImdbID, movie.TmdbID).First(&existing).Error; err != nil {
+			return nil, fmt.Errorf("failed to load movie: %w", err)
+		}
+		return &existing, nil
 	}
 
 	fmt.Printf("✅ Created new movie: %s (%d)\n", movie.Title, movieInfo.Year)
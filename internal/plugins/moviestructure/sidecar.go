@@ -0,0 +1,157 @@
+package moviestructure
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/mantonx/viewra/internal/database"
+	"github.com/mantonx/viewra/internal/kodi"
+	"github.com/mantonx/viewra/internal/modules/assetmodule"
+	"gorm.io/gorm"
+)
+
+// movieArtwork maps the Kodi artwork found next to a movie to asset types
+var movieArtwork = map[string]assetmodule.AssetType{
+	kodi.ArtPoster:    assetmodule.AssetTypePoster,
+	kodi.ArtFanart:    assetmodule.AssetTypeFanart,
+	kodi.ArtBanner:    assetmodule.AssetTypeBanner,
+	kodi.ArtClearLogo: assetmodule.AssetTypeLogo,
+}
+
+// readNFO reads the Kodi NFO next to a movie file, if there is one. Its
+// title, year and IMDb ID replace what the file name suggests, since they
+// were chosen by whoever curated the library.
+func (p *MovieStructureCorePlugin) readNFO(path string, movieInfo *MovieInfo) *kodi.Movie {
+	nfoPath := kodi.FindMovieNFO(path)
+	if nfoPath == "" {
+		return nil
+	}
+
+	var nfo kodi.Movie
+	if err := kodi.Read(nfoPath, &nfo); err != nil {
+		fmt.Printf("WARNING: Ignoring NFO for %s: %v\n", path, err)
+		return nil
+	}
+
+	if title := strings.TrimSpace(nfo.Title); title != "" {
+		movieInfo.Title = title
+	}
+	if nfo.Year > 0 {
+		movieInfo.Year = nfo.Year
+	} else if premiered := kodi.ParseDate(nfo.Premiered); premiered != nil {
+		movieInfo.Year = premiered.Year()
+	}
+	if imdbID := nfo.UniqueID("imdb"); imdbID != "" {
+		movieInfo.ImdbID = imdbID
+	}
+	return &nfo
+}
+
+// applyNFO fills a movie from its NFO. Only fields the NFO sets are changed.
+func (p *MovieStructureCorePlugin) applyNFO(db *gorm.DB, movie *database.Movie, nfo *kodi.Movie) error {
+	updates := map[string]interface{}{}
+	setString := func(column, value string) {
+		if value = strings.TrimSpace(value); value != "" {
+			updates[column] = value
+		}
+	}
+	setList := func(column string, values []string) {
+		if len(values) > 0 {
+			encoded, _ := json.Marshal(values)
+			updates[column] = string(encoded)
+		}
+	}
+
+	setString("original_title", nfo.OriginalTitle)
+	setString("overview", nfo.Plot)
+	setString("tagline", nfo.Tagline)
+	setString("rating", nfo.MPAA)
+	setString("imdb_id", nfo.UniqueID("imdb"))
+	setString("tmdb_id", nfo.UniqueID("tmdb"))
+	setList("genres", nfo.Genres)
+	setList("production_companies", nfo.Studios)
+	setList("production_countries", nfo.Countries)
+	setList("keywords", nfo.Tags)
+	if nfo.Runtime > 0 {
+		updates["runtime"] = nfo.Runtime
+	}
+	if premiered := kodi.ParseDate(nfo.Premiered); premiered != nil {
+		updates["release_date"] = premiered
+	}
+	if len(updates) > 0 {
+		updates["updated_at"] = time.Now()
+		if err := db.Model(movie).Updates(updates).Error; err != nil {
+			return fmt.Errorf("failed to apply NFO: %w", err)
+		}
+	}
+
+	for _, id := range nfo.UniqueIDs {
+		source, value := strings.ToLower(strings.TrimSpace(id.Type)), strings.TrimSpace(id.Value)
+		if source == "" || value == "" {
+			continue
+		}
+		var count int64
+		db.Model(&database.MediaExternalIDs{}).
+			Where("media_id = ? AND media_type = ? AND source = ?", movie.ID, database.MediaTypeMovie, source).
+			Count(&count)
+		if count > 0 {
+			continue
+		}
+		if err := db.Create(&database.MediaExternalIDs{
+			MediaID:    movie.ID,
+			MediaType:  database.MediaTypeMovie,
+			Source:     source,
+			ExternalID: value,
+			CreatedAt:  time.Now(),
+			UpdatedAt:  time.Now(),
+		}).Error; err != nil {
+			return fmt.Errorf("failed to save external ID: %w", err)
+		}
+	}
+	return nil
+}
+
+// importArtwork imports the Kodi artwork next to a movie file as local assets
+func (p *MovieStructureCorePlugin) importArtwork(movie *database.Movie, path string) {
+	movieID, err := uuid.Parse(movie.ID)
+	if err != nil {
+		return
+	}
+
+	ownFolder := p.inOwnFolder(path)
+	for kind, assetType := range movieArtwork {
+		artworkPath := kodi.FindMediaArtwork(path, kind, ownFolder)
+		if artworkPath == "" {
+			continue
+		}
+		if _, err := assetmodule.ImportLocalMediaArtwork(assetmodule.EntityTypeMovie, movieID, assetType, artworkPath); err != nil {
+			fmt.Printf("WARNING: Failed to import %s for %s: %v\n", artworkPath, movie.Title, err)
+		}
+	}
+}
+
+// inOwnFolder reports whether a movie file is the only movie in its folder,
+// in which case artwork like poster.jpg in the folder belongs to it
+func (p *MovieStructureCorePlugin) inOwnFolder(path string) bool {
+	entries, err := os.ReadDir(filepath.Dir(path))
+	if err != nil {
+		return false
+	}
+
+	movies := 0
+	for _, entry := range entries {
+		name := strings.ToLower(entry.Name())
+		if entry.IsDir() || strings.Contains(name, "trailer") || strings.Contains(name, "sample") {
+			continue
+		}
+		if p.isExtensionSupported(filepath.Ext(name)) {
+			movies++
+		}
+	}
+	return movies == 1
+}
@@ -7,12 +7,14 @@ import (
 	"regexp"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/mantonx/viewra/internal/database"
 	"github.com/mantonx/viewra/internal/modules/pluginmodule"
 	"github.com/mantonx/viewra/internal/utils"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // Register TV Structure core plugin with the global registry
@@ -28,6 +30,10 @@ type TVStructureCorePlugin struct {
 	supportedExts []string
 	enabled       bool
 	initialized   bool
+
+	// showMu serializes show lookups and creation across scan workers;
+	// shows parsed from file names have no external ID to key on
+	showMu sync.Mutex
 }
 
 // TVShowInfo holds parsed TV show information
@@ -157,6 +163,19 @@ func (p *TVStructureCorePlugin) HandleFile(path string, ctx *pluginmodule.Metada
 	// Get database connection from context
 	db := ctx.DB
 
+	// In mixed libraries only files classified as episodes are shows, and
+	// dated home videos are never episodes
+	if ctx.MediaFile != nil && ctx.MediaFile.LibraryID != 0 && ctx.MediaFile.MediaType != database.MediaTypeEpisode {
+		var library database.MediaLibrary
+		if err := db.First(&library, ctx.MediaFile.LibraryID).Error; err != nil {
+			return fmt.Errorf("failed to get library info: %w", err)
+		}
+		if library.Type == "mixed" || library.Type == "home" {
+			fmt.Printf("DEBUG: Skipping file %s - classified as %s in %s library\n", path, ctx.MediaFile.MediaType, library.Type)
+			return nil
+		}
+	}
+
 	// Parse TV show information from file path
 	showInfo, err := p.parseTVShowFromPath(path)
 	if err != nil {
@@ -169,10 +188,13 @@ func (p *TVStructureCorePlugin) HandleFile(path string, ctx *pluginmodule.Metada
 		return nil
 	}
 
+	// Kodi NFOs next to the file override what the path suggests
+	sidecar := p.readNFO(path, showInfo)
+
 	fmt.Printf("DEBUG: Parsed TV show info: %+v\n", showInfo)
 
 	// Create TV show structure in database
-	err = p.createTVShowStructure(db, ctx.MediaFile, showInfo, ctx.PluginID)
+	err = p.createTVShowStructure(db, ctx.MediaFile, showInfo, sidecar, ctx.PluginID)
 	if err != nil {
 		return fmt.Errorf("failed to create TV show structure: %w", err)
 	}
@@ -612,7 +634,7 @@ func (p *TVStructureCorePlugin) extractAdditionalMetadata(info *TVShowInfo, file
 }
 
 // createTVShowStructure creates TV show, season, and episode records in the database
-func (p *TVStructureCorePlugin) createTVShowStructure(db *gorm.DB, mediaFile *database.MediaFile, showInfo *TVShowInfo, pluginID string) error {
+func (p *TVStructureCorePlugin) createTVShowStructure(db *gorm.DB, mediaFile *database.MediaFile, showInfo *TVShowInfo, sidecar *episodeSidecar, pluginID string) error {
 	// Create or get TV show
 	tvShow, err := p.createOrGetTVShow(db, showInfo)
 	if err != nil {
@@ -639,13 +661,23 @@ func (p *TVStructureCorePlugin) createTVShowStructure(db *gorm.DB, mediaFile *da
 		return fmt.Errorf("failed to link media file to episode: %w", err)
 	}
 
+	// Metadata and artwork kept alongside the file, e.g. by another media server
+	source := "filename"
+	if sidecar != nil {
+		source = "nfo"
+		if err := p.applyNFO(db, tvShow, episode, sidecar); err != nil {
+			return err
+		}
+	}
+	p.importArtwork(tvShow, episode, mediaFile.Path)
+
 	// Create MediaEnrichment record to track that this plugin processed the media
 	enrichment := database.MediaEnrichment{
 		MediaID:   episode.ID,
 		MediaType: database.MediaTypeEpisode,
 		Plugin:    pluginID,
-		Payload: fmt.Sprintf("{\"show\":\"%s\",\"season\":%d,\"episode\":%d,\"title\":\"%s\",\"source\":\"filename\"}",
-			showInfo.ShowName, showInfo.SeasonNumber, showInfo.EpisodeNumber, showInfo.EpisodeTitle),
+		Payload: fmt.Sprintf("{\"show\":\"%s\",\"season\":%d,\"episode\":%d,\"title\":\"%s\",\"source\":\"%s\"}",
+			showInfo.ShowName, showInfo.SeasonNumber, showInfo.EpisodeNumber, showInfo.EpisodeTitle, source),
 		UpdatedAt: time.Now(),
 	}
 
@@ -667,6 +699,9 @@ func (p *TVStructureCorePlugin) createTVShowStructure(db *gorm.DB, mediaFile *da
 
 // createOrGetTVShow creates or retrieves a TV show record
 func (p *TVStructureCorePlugin) createOrGetTVShow(db *gorm.DB, showInfo *TVShowInfo) (*database.TVShow, error) {
+	p.showMu.Lock()
+	defer p.showMu.Unlock()
+
 	// ENHANCED DUPLICATE PREVENTION: Check for existing TV show by multiple criteria
 	var existingShow database.TVShow
 	
@@ -748,8 +783,16 @@ func (p *TVStructureCorePlugin) createOrGetSeason(db *gorm.DB, tvShowID string,
 		UpdatedAt:    time.Now(),
 	}
 
-	if err := db.Create(season).Error; err != nil {
-		return nil, fmt.Errorf("failed to create season: %w", err)
+	result := db.Clauses(clause.OnConflict{DoNothing: true}).Create(season)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to create season: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		// Another worker created the season first
+		if err := db.Where("tv_show_id = ? AND season_number = ?", tvShowID, seasonNumber).First(&existingSeason).Error; err != nil {
+			return nil, fmt.Errorf("failed to load season: %w", err)
+		}
+		return &existingSeason, nil
 	}
 
 	return season, nil
@@ -784,8 +827,16 @@ func (p *TVStructureCorePlugin) createOrGetEpisode(db *gorm.DB, seasonID string,
 		UpdatedAt:     time.Now(),
 	}
 
-	if err := db.Create(episode).Error; err != nil {
-		return nil, fmt.Errorf("failed to create episode: %w", err)
+	result := db.Clauses(clause.OnConflict{DoNothing: true}).Create(episode)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to create episode: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		// Another worker created the episode first
+		if err := db.Where("season_id = ? AND episode_number = ?", seasonID, episodeNumber).First(&existingEpisode).Error; err != nil {
+			return nil, fmt.Errorf("failed to load episode: %w", err)
+		}
+		return &existingEpisode, nil
 	}
 
 	return episode, nil
@@ -0,0 +1,142 @@
+package tvstructure
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/mantonx/viewra/internal/database"
+	"github.com/mantonx/viewra/internal/kodi"
+	"github.com/mantonx/viewra/internal/modules/assetmodule"
+	"gorm.io/gorm"
+)
+
+// showArtwork maps the Kodi artwork in a show folder to asset types
+var showArtwork = map[string]assetmodule.AssetType{
+	kodi.ArtPoster:    assetmodule.AssetTypePoster,
+	kodi.ArtFanart:    assetmodule.AssetTypeFanart,
+	kodi.ArtBanner:    assetmodule.AssetTypeBanner,
+	kodi.ArtClearLogo: assetmodule.AssetTypeLogo,
+}
+
+// episodeSidecar holds the Kodi NFOs found for an episode file
+type episodeSidecar struct {
+	show    *kodi.TVShow
+	episode *kodi.Episode
+}
+
+// readNFO reads tvshow.nfo in the show folder and the NFO named after the
+// episode, if there are any. What they say about the show name, season and
+// episode replaces what the path suggests.
+func (p *TVStructureCorePlugin) readNFO(path string, showInfo *TVShowInfo) *episodeSidecar {
+	sidecar := &episodeSidecar{}
+
+	if nfoPath := filepath.Join(kodi.ShowDir(path), "tvshow.nfo"); kodi.Exists(nfoPath) {
+		var show kodi.TVShow
+		if err := kodi.Read(nfoPath, &show); err != nil {
+			fmt.Printf("WARNING: Ignoring show NFO for %s: %v\n", path, err)
+		} else {
+			sidecar.show = &show
+			if title := strings.TrimSpace(show.Title); title != "" {
+				showInfo.ShowName = title
+			}
+			if show.Year > 0 {
+				showInfo.Year = show.Year
+			}
+		}
+	}
+
+	if nfoPath := kodi.MediaNFOPath(path); kodi.Exists(nfoPath) {
+		var episode kodi.Episode
+		if err := kodi.Read(nfoPath, &episode); err != nil {
+			fmt.Printf("WARNING: Ignoring episode NFO for %s: %v\n", path, err)
+		} else {
+			sidecar.episode = &episode
+			if episode.Episode > 0 {
+				showInfo.SeasonNumber = episode.Season
+				showInfo.EpisodeNumber = episode.Episode
+			}
+			if title := strings.TrimSpace(episode.Title); title != "" {
+				showInfo.EpisodeTitle = title
+			}
+		}
+	}
+
+	if sidecar.show == nil && sidecar.episode == nil {
+		return nil
+	}
+	return sidecar
+}
+
+// applyNFO fills a show and episode from their NFOs. Only fields the NFOs
+// set are changed.
+func (p *TVStructureCorePlugin) applyNFO(db *gorm.DB, tvShow *database.TVShow, episode *database.Episode, sidecar *episodeSidecar) error {
+	if show := sidecar.show; show != nil {
+		updates := map[string]interface{}{}
+		if plot := strings.TrimSpace(show.Plot); plot != "" {
+			updates["description"] = plot
+		}
+		if status := strings.TrimSpace(show.Status); status != "" {
+			updates["status"] = status
+		}
+		if tmdbID := show.UniqueID("tmdb"); tmdbID != "" {
+			updates["tmdb_id"] = tmdbID
+		}
+		if premiered := kodi.ParseDate(show.Premiered); premiered != nil {
+			updates["first_air_date"] = premiered
+		}
+		if len(updates) > 0 {
+			updates["updated_at"] = time.Now()
+			if err := db.Model(tvShow).Updates(updates).Error; err != nil {
+				return fmt.Errorf("failed to apply show NFO: %w", err)
+			}
+		}
+	}
+
+	if nfo := sidecar.episode; nfo != nil {
+		updates := map[string]interface{}{}
+		if plot := strings.TrimSpace(nfo.Plot); plot != "" {
+			updates["description"] = plot
+		}
+		if aired := kodi.ParseDate(nfo.Aired); aired != nil {
+			updates["air_date"] = aired
+		}
+		if nfo.Runtime > 0 {
+			updates["duration"] = nfo.Runtime * 60
+		}
+		if len(updates) > 0 {
+			updates["updated_at"] = time.Now()
+			if err := db.Model(episode).Updates(updates).Error; err != nil {
+				return fmt.Errorf("failed to apply episode NFO: %w", err)
+			}
+		}
+	}
+	return nil
+}
+
+// importArtwork imports the Kodi artwork in the show folder and the
+// thumbnail named after the episode as local assets
+func (p *TVStructureCorePlugin) importArtwork(tvShow *database.TVShow, episode *database.Episode, path string) {
+	if showID, err := uuid.Parse(tvShow.ID); err == nil {
+		showDir := kodi.ShowDir(path)
+		for kind, assetType := range showArtwork {
+			artworkPath := kodi.FindFolderArtwork(showDir, kind)
+			if artworkPath == "" {
+				continue
+			}
+			if _, err := assetmodule.ImportLocalMediaArtwork(assetmodule.EntityTypeTVShow, showID, assetType, artworkPath); err != nil {
+				fmt.Printf("WARNING: Failed to import %s for %s: %v\n", artworkPath, tvShow.Title, err)
+			}
+		}
+	}
+
+	if episodeID, err := uuid.Parse(episode.ID); err == nil {
+		if artworkPath := kodi.FindMediaArtwork(path, kodi.ArtThumb, false); artworkPath != "" {
+			if _, err := assetmodule.ImportLocalMediaArtwork(assetmodule.EntityTypeEpisode, episodeID, assetmodule.AssetTypeThumb, artworkPath); err != nil {
+				fmt.Printf("WARNING: Failed to import %s for %s: %v\n", artworkPath, episode.Title, err)
+			}
+		}
+	}
+}
@@ -0,0 +1,209 @@
+// Package schedule works out when recurring jobs such as library scans run:
+// five-field cron expressions, and daily windows in which they must not.
+package schedule
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxSearchYears bounds the search for an expression's next time, so that
+// expressions that can never match (such as February 30th) give up
+const maxSearchYears = 5
+
+// macros are the named expressions accepted in place of five fields
+var macros = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+var monthNames = map[string]int{
+	"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+	"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+}
+
+var dayNames = map[string]int{
+	"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+}
+
+// field is the set of values a cron field matches
+type field struct {
+	values map[int]bool
+	any    bool // The field was *, so it doesn't restrict the day
+}
+
+// Cron is a parsed cron expression: minute, hour, day of month, month and
+// day of week
+type Cron struct {
+	expr    string
+	minute  field
+	hour    field
+	day     field
+	month   field
+	weekday field
+}
+
+// ParseCron parses a five-field cron expression or a macro such as @daily.
+// Fields take *, numbers, ranges (1-5), steps (*/15, 0-30/10) and lists
+// (1,15); months and weekdays also take names (jan, mon). Weekday 7 is
+// Sunday, as is 0.
+func ParseCron(expr string) (*Cron, error) {
+	expr = strings.TrimSpace(expr)
+	spec := expr
+	if macro, ok := macros[strings.ToLower(spec)]; ok {
+		spec = macro
+	}
+
+	fields := strings.Fields(spec)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("cron expression %q needs 5 fields, has %d", expr, len(fields))
+	}
+
+	cron := &Cron{expr: expr}
+	var err error
+	if cron.minute, err = parseField(fields[0], 0, 59, nil); err != nil {
+		return nil, fmt.Errorf("minute: %w", err)
+	}
+	if cron.hour, err = parseField(fields[1], 0, 23, nil); err != nil {
+		return nil, fmt.Errorf("hour: %w", err)
+	}
+	if cron.day, err = parseField(fields[2], 1, 31, nil); err != nil {
+		return nil, fmt.Errorf("day of month: %w", err)
+	}
+	if cron.month, err = parseField(fields[3], 1, 12, monthNames); err != nil {
+		return nil, fmt.Errorf("month: %w", err)
+	}
+	if cron.weekday, err = parseField(fields[4], 0, 7, dayNames); err != nil {
+		return nil, fmt.Errorf("day of week: %w", err)
+	}
+	if cron.weekday.values[7] {
+		cron.weekday.values[0] = true
+	}
+	return cron, nil
+}
+
+// String returns the expression as it was written
+func (c *Cron) String() string {
+	return c.expr
+}
+
+// Next returns the first time after after that the expression matches, in
+// after's location, or the zero time if it matches none in the next years
+func (c *Cron) Next(after time.Time) time.Time {
+	t := after.Truncate(time.Minute).Add(time.Minute)
+	limit := t.AddDate(maxSearchYears, 0, 0)
+
+	for t.Before(limit) {
+		if !c.month.values[int(t.Month())] {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+		if !c.matchesDay(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+		if !c.hour.values[t.Hour()] {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, t.Location())
+			continue
+		}
+		if !c.minute.values[t.Minute()] {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t
+	}
+	return time.Time{}
+}
+
+// matchesDay applies cron's rule for the two day fields: when both are
+// restricted, a day matching either is enough
+func (c *Cron) matchesDay(t time.Time) bool {
+	day := c.day.values[t.Day()]
+	weekday := c.weekday.values[int(t.Weekday())]
+	switch {
+	case c.day.any && c.weekday.any:
+		return true
+	case c.day.any:
+		return weekday
+	case c.weekday.any:
+		return day
+	default:
+		return day || weekday
+	}
+}
+
+func parseField(spec string, min, max int, names map[string]int) (field, error) {
+	f := field{values: make(map[int]bool), any: spec == "*"}
+	for _, part := range strings.Split(spec, ",") {
+		if err := f.add(part, min, max, names); err != nil {
+			return field{}, err
+		}
+	}
+	return f, nil
+}
+
+// add adds the values of one list entry: *, n, a-b, each optionally /step
+func (f *field) add(part string, min, max int, names map[string]int) error {
+	rangeSpec, step := part, 1
+	if i := strings.Index(part, "/"); i >= 0 {
+		rangeSpec = part[:i]
+		n, err := strconv.Atoi(part[i+1:])
+		if err != nil || n <= 0 {
+			return fmt.Errorf("invalid step in %q", part)
+		}
+		step = n
+	}
+
+	low, high := min, max
+	switch {
+	case rangeSpec == "*":
+	case strings.Contains(rangeSpec, "-"):
+		bounds := strings.SplitN(rangeSpec, "-", 2)
+		var err error
+		if low, err = parseValue(bounds[0], min, max, names); err != nil {
+			return err
+		}
+		if high, err = parseValue(bounds[1], min, max, names); err != nil {
+			return err
+		}
+		if low > high {
+			return fmt.Errorf("range %q ends before it starts", rangeSpec)
+		}
+	default:
+		value, err := parseValue(rangeSpec, min, max, names)
+		if err != nil {
+			return err
+		}
+		low = value
+		// A step after a single value runs to the end of the field
+		if step == 1 {
+			high = value
+		}
+	}
+
+	for value := low; value <= high; value += step {
+		f.values[value] = true
+	}
+	return nil
+}
+
+func parseValue(spec string, min, max int, names map[string]int) (int, error) {
+	if value, ok := names[strings.ToLower(spec)]; ok {
+		return value, nil
+	}
+	value, err := strconv.Atoi(spec)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value %q", spec)
+	}
+	if value < min || value > max {
+		return 0, fmt.Errorf("value %d is outside %d-%d", value, min, max)
+	}
+	return value, nil
+}
@@ -0,0 +1,77 @@
+package schedule
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Window is a time of day, on some days of the week, in which jobs must not
+// run. A window whose end is before its start runs past midnight, into the
+// day after each of its days.
+type Window struct {
+	start int // Minutes after midnight
+	end   int
+	days  [7]bool
+}
+
+// ParseWindow parses a window from its start and end ("22:00", "06:30") and
+// the days it starts on ("mon", "sat"); no days means every day
+func ParseWindow(start, end string, days []string) (*Window, error) {
+	w := &Window{}
+	var err error
+	if w.start, err = parseClock(start); err != nil {
+		return nil, fmt.Errorf("start: %w", err)
+	}
+	if w.end, err = parseClock(end); err != nil {
+		return nil, fmt.Errorf("end: %w", err)
+	}
+	if w.start == w.end {
+		return nil, fmt.Errorf("window %s-%s is empty", start, end)
+	}
+
+	if len(days) == 0 {
+		for i := range w.days {
+			w.days[i] = true
+		}
+	}
+	for _, day := range days {
+		weekday, ok := dayNames[strings.ToLower(strings.TrimSpace(day))]
+		if !ok {
+			return nil, fmt.Errorf("invalid day %q", day)
+		}
+		w.days[weekday] = true
+	}
+	return w, nil
+}
+
+// Contains reports whether t falls in the window
+func (w *Window) Contains(t time.Time) bool {
+	minute := t.Hour()*60 + t.Minute()
+	today := int(t.Weekday())
+
+	if w.start < w.end {
+		return w.days[today] && minute >= w.start && minute < w.end
+	}
+	// Past midnight: the evening of a window day, or the morning after one
+	yesterday := (today + 6) % 7
+	return (w.days[today] && minute >= w.start) || (w.days[yesterday] && minute < w.end)
+}
+
+// InAny reports whether t falls in any of the windows
+func InAny(windows []*Window, t time.Time) bool {
+	for _, w := range windows {
+		if w.Contains(t) {
+			return true
+		}
+	}
+	return false
+}
+
+func parseClock(value string) (int, error) {
+	t, err := time.Parse("15:04", strings.TrimSpace(value))
+	if err != nil {
+		return 0, fmt.Errorf("invalid time %q, want HH:MM", value)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
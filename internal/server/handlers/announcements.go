@@ -0,0 +1,264 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mantonx/viewra/internal/database"
+	"gorm.io/gorm"
+)
+
+// Announcement types
+const (
+	AnnouncementInfo         = "info"
+	AnnouncementMaintenance  = "maintenance"
+	AnnouncementLibraryAdded = "library_added"
+)
+
+// announcementRequest creates or replaces an announcement
+type announcementRequest struct {
+	Title       string     `json:"title" binding:"required"`
+	Message     string     `json:"message"`
+	Type        string     `json:"type" binding:"omitempty,oneof=info maintenance library_added"`
+	StartsAt    *time.Time `json:"starts_at"`
+	EndsAt      *time.Time `json:"ends_at"`
+	Dismissible *bool      `json:"dismissible"`
+}
+
+// AnnouncementsHandler serves server-wide announcements and their per-user
+// dismissal state
+type AnnouncementsHandler struct{}
+
+// NewAnnouncementsHandler creates a new announcements handler
+func NewAnnouncementsHandler() *AnnouncementsHandler {
+	return &AnnouncementsHandler{}
+}
+
+// GetAnnouncements lists the announcements showing now that the user in
+// user_id hasn't dismissed. ?include_dismissed=true lists those too, marked.
+func (h *AnnouncementsHandler) GetAnnouncements(c *gin.Context) {
+	userID, ok := parseAnnouncementUserID(c)
+	if !ok {
+		return
+	}
+
+	db := database.GetDB()
+	now := time.Now()
+	var announcements []database.Announcement
+	if err := db.Where("(starts_at IS NULL OR starts_at <= ?) AND (ends_at IS NULL OR ends_at > ?)", now, now).
+		Order("created_at DESC").Find(&announcements).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to retrieve announcements",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	var dismissedIDs []uint32
+	if err := db.Model(&database.AnnouncementDismissal{}).Where("user_id = ?", userID).
+		Pluck("announcement_id", &dismissedIDs).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to retrieve dismissed announcements",
+			"details": err.Error(),
+		})
+		return
+	}
+	dismissed := make(map[uint32]bool, len(dismissedIDs))
+	for _, id := range dismissedIDs {
+		dismissed[id] = true
+	}
+
+	type userAnnouncement struct {
+		database.Announcement
+		Dismissed bool `json:"dismissed"`
+	}
+	includeDismissed := c.Query("include_dismissed") == "true"
+	result := []userAnnouncement{}
+	for _, announcement := range announcements {
+		if dismissed[announcement.ID] && !includeDismissed {
+			continue
+		}
+		result = append(result, userAnnouncement{Announcement: announcement, Dismissed: dismissed[announcement.ID]})
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"announcements": result,
+		"count":         len(result),
+	})
+}
+
+// DismissAnnouncement hides an announcement from the user in user_id
+func (h *AnnouncementsHandler) DismissAnnouncement(c *gin.Context) {
+	userID, ok := parseAnnouncementUserID(c)
+	if !ok {
+		return
+	}
+	announcement, ok := findAnnouncement(c)
+	if !ok {
+		return
+	}
+	if !announcement.Dismissible {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Announcement cannot be dismissed"})
+		return
+	}
+
+	dismissal := database.AnnouncementDismissal{
+		AnnouncementID: announcement.ID,
+		UserID:         userID,
+		DismissedAt:    time.Now(),
+	}
+	if err := database.GetDB().Save(&dismissal).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to dismiss announcement",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"dismissal": dismissal})
+}
+
+// ListAllAnnouncements lists every announcement, including scheduled and
+// expired ones, for the admin page
+func (h *AnnouncementsHandler) ListAllAnnouncements(c *gin.Context) {
+	var announcements []database.Announcement
+	if err := database.GetDB().Order("created_at DESC").Find(&announcements).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to retrieve announcements",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"announcements": announcements,
+		"count":         len(announcements),
+	})
+}
+
+// CreateAnnouncement publishes an announcement
+func (h *AnnouncementsHandler) CreateAnnouncement(c *gin.Context) {
+	var announcement database.Announcement
+	if !bindAnnouncement(c, &announcement) {
+		return
+	}
+
+	if err := database.GetDB().Create(&announcement).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to create announcement",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"announcement": announcement})
+}
+
+// UpdateAnnouncement replaces an announcement. Users who dismissed it don't
+// see it again.
+func (h *AnnouncementsHandler) UpdateAnnouncement(c *gin.Context) {
+	announcement, ok := findAnnouncement(c)
+	if !ok {
+		return
+	}
+	if !bindAnnouncement(c, announcement) {
+		return
+	}
+
+	if err := database.GetDB().Save(announcement).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to update announcement",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"announcement": announcement})
+}
+
+// DeleteAnnouncement removes an announcement and its dismissals
+func (h *AnnouncementsHandler) DeleteAnnouncement(c *gin.Context) {
+	announcement, ok := findAnnouncement(c)
+	if !ok {
+		return
+	}
+
+	err := database.GetDB().Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("announcement_id = ?", announcement.ID).Delete(&database.AnnouncementDismissal{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(announcement).Error
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to delete announcement",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Announcement deleted"})
+}
+
+// bindAnnouncement reads an announcement request into announcement, writing a
+// 400 response when it is invalid
+func bindAnnouncement(c *gin.Context, announcement *database.Announcement) bool {
+	var req announcementRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request format",
+			"details": err.Error(),
+		})
+		return false
+	}
+
+	req.Title = strings.TrimSpace(req.Title)
+	if req.Title == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Title cannot be empty"})
+		return false
+	}
+	if req.StartsAt != nil && req.EndsAt != nil && !req.EndsAt.After(*req.StartsAt) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ends_at must be after starts_at"})
+		return false
+	}
+
+	announcement.Title = req.Title
+	announcement.Message = strings.TrimSpace(req.Message)
+	announcement.Type = req.Type
+	if announcement.Type == "" {
+		announcement.Type = AnnouncementInfo
+	}
+	announcement.StartsAt = req.StartsAt
+	announcement.EndsAt = req.EndsAt
+	announcement.Dismissible = req.Dismissible == nil || *req.Dismissible
+	return true
+}
+
+// findAnnouncement loads the announcement in the id path parameter, writing
+// a 404 response when there is none
+func findAnnouncement(c *gin.Context) (*database.Announcement, bool) {
+	var announcement database.Announcement
+	if err := database.GetDB().First(&announcement, "id = ?", c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Announcement not found"})
+		return nil, false
+	}
+	return &announcement, true
+}
+
+// parseAnnouncementUserID reads the optional user_id query parameter.
+// Requests without a user share user 0's dismissals.
+func parseAnnouncementUserID(c *gin.Context) (uint32, bool) {
+	userIDStr := c.Query("user_id")
+	if userIDStr == "" {
+		return 0, true
+	}
+	userID, err := strconv.ParseUint(userIDStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return 0, false
+	}
+	return uint32(userID), true
+}
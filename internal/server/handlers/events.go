@@ -159,6 +159,9 @@ func (h *EventsHandler) GetEventTypes(c *gin.Context) {
 		string(events.EventMediaFileFound),
 		string(events.EventMediaMetadataEnriched),
 		string(events.EventMediaFileDeleted),
+		string(events.EventMediaFileOrganized),
+		string(events.EventMediaFileRestored),
+		string(events.EventDownloadImported),
 		string(events.EventUserCreated),
 		string(events.EventUserLoggedIn),
 		string(events.EventUserDeviceRegistered),
@@ -179,6 +182,10 @@ func (h *EventsHandler) GetEventTypes(c *gin.Context) {
 		string(events.EventScanFailed),
 		string(events.EventScanResumed),
 		string(events.EventScanPaused),
+		string(events.EventEnrichmentApplied),
+		string(events.EventDiskHealthWarning),
+		string(events.EventDiskFailurePredicted),
+		string(events.EventDiskHealthRecovered),
 		string(events.EventError),
 		string(events.EventWarning),
 		string(events.EventInfo),
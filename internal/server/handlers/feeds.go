@@ -0,0 +1,334 @@
+package handlers
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mantonx/viewra/internal/auth"
+	"github.com/mantonx/viewra/internal/database"
+	"gorm.io/gorm"
+)
+
+// Feed token scopes
+const (
+	FeedScopeCalendar = "calendar"
+	FeedScopeRecent   = "recent"
+)
+
+const (
+	// calendarPastDays keeps recently aired items in the feed so they don't vanish on air day
+	calendarPastDays = 14
+	// calendarFutureDays bounds how far ahead releases are listed
+	calendarFutureDays = 180
+)
+
+// FeedsHandler serves token-authenticated subscription feeds
+type FeedsHandler struct{}
+
+// NewFeedsHandler creates a new feeds handler
+func NewFeedsHandler() *FeedsHandler {
+	return &FeedsHandler{}
+}
+
+// ListFeedTokens lists a user's feed tokens
+func (h *FeedsHandler) ListFeedTokens(c *gin.Context) {
+	userID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	var tokens []database.FeedToken
+	if err := database.GetDB().Where("user_id = ?", userID).Order("created_at").Find(&tokens).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to retrieve feed tokens",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"tokens": tokens,
+		"count":  len(tokens),
+	})
+}
+
+// CreateFeedToken issues a new feed token and returns its subscription URL
+func (h *FeedsHandler) CreateFeedToken(c *gin.Context) {
+	userID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	var req struct {
+		Scope string `json:"scope" binding:"required,oneof=calendar recent"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request format",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	db := database.GetDB()
+	var user database.User
+	if err := db.First(&user, userID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	}
+
+	token, err := generateFeedToken()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate token"})
+		return
+	}
+
+	feedToken := database.FeedToken{
+		Token:  token,
+		UserID: user.ID,
+		Scope:  req.Scope,
+	}
+	if err := db.Create(&feedToken).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to create feed token",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"token": feedToken,
+		"urls":  feedURLs(c, feedToken),
+	})
+}
+
+// RevokeFeedToken deletes a feed token, breaking existing subscriptions
+func (h *FeedsHandler) RevokeFeedToken(c *gin.Context) {
+	result := database.GetDB().
+		Where("user_id = ? AND token = ?", c.Param("id"), c.Param("token")).
+		Delete(&database.FeedToken{})
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to revoke feed token",
+			"details": result.Error.Error(),
+		})
+		return
+	}
+	if result.RowsAffected == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Feed token not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Feed token revoked"})
+}
+
+// GetCalendarFeed serves upcoming episode air dates and movie releases as iCalendar
+func (h *FeedsHandler) GetCalendarFeed(c *gin.Context) {
+	feedToken, ok := authorizeFeed(c, strings.TrimSuffix(c.Param("token"), ".ics"), FeedScopeCalendar)
+	if !ok {
+		return
+	}
+
+	db := database.GetDB()
+	denied := auth.DeniedLibraries(db, feedToken.UserID)
+	now := time.Now().UTC()
+	from := now.AddDate(0, 0, -calendarPastDays)
+	to := now.AddDate(0, 0, calendarFutureDays)
+
+	var episodes []database.Episode
+	if err := db.Preload("Season.TVShow").
+		Where("air_date >= ? AND air_date < ?", from, to).
+		Where("season_id IN (?)", db.Model(&database.Season{}).Select("id").
+			Where("tv_show_id IN (?) OR tv_show_id NOT IN (?)",
+				showFiles(db).Where("media_files.library_id NOT IN (?)", denied).Select("seasons.tv_show_id"),
+				showFiles(db).Select("seasons.tv_show_id"))).
+		Order("air_date").Find(&episodes).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load episodes"})
+		return
+	}
+
+	var movies []database.Movie
+	if err := db.Select("id, title, overview, release_date").
+		Where("release_date >= ? AND release_date < ?", from, to).
+		Where("id IN (?) OR id NOT IN (?)",
+			movieFiles(db).Where("library_id NOT IN (?)", denied).Select("media_id"),
+			movieFiles(db).Select("media_id")).
+		Order("release_date").Find(&movies).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load movies"})
+		return
+	}
+
+	cal := newICalendar("Viewra Upcoming", now)
+	for _, episode := range episodes {
+		summary := fmt.Sprintf("%s S%02dE%02d", episode.Season.TVShow.Title, episode.Season.SeasonNumber, episode.EpisodeNumber)
+		if episode.Title != "" {
+			summary += " - " + episode.Title
+		}
+		cal.addAllDayEvent("episode-"+episode.ID, *episode.AirDate, summary, episode.Description)
+	}
+	for _, movie := range movies {
+		cal.addAllDayEvent("movie-"+movie.ID, *movie.ReleaseDate, movie.Title, movie.Overview)
+	}
+
+	c.Header("Cache-Control", "private, max-age=3600")
+	c.Data(http.StatusOK, "text/calendar; charset=utf-8", cal.bytes())
+}
+
+// showFiles selects the media files of TV show episodes, joined to their
+// seasons. Upcoming items are listed when a library the user may see holds
+// files of them, or when no library does.
+func showFiles(db *gorm.DB) *gorm.DB {
+	return db.Model(&database.MediaFile{}).
+		Joins("JOIN episodes ON episodes.id = media_files.media_id").
+		Joins("JOIN seasons ON seasons.id = episodes.season_id").
+		Where("media_files.media_type = ?", database.MediaTypeEpisode)
+}
+
+// movieFiles selects the media files of movies
+func movieFiles(db *gorm.DB) *gorm.DB {
+	return db.Model(&database.MediaFile{}).Where("media_type = ?", database.MediaTypeMovie)
+}
+
+// authorizeFeed resolves a feed token, rejecting unknown tokens and the wrong scope
+func authorizeFeed(c *gin.Context, token, scope string) (*database.FeedToken, bool) {
+	db := database.GetDB()
+
+	var feedToken database.FeedToken
+	if err := db.Where("token = ? AND scope = ?", token, scope).First(&feedToken).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Feed not found"})
+		return nil, false
+	}
+
+	now := time.Now()
+	db.Model(&feedToken).Update("last_used_at", &now)
+
+	return &feedToken, true
+}
+
+// generateFeedToken returns a random 256-bit hex token
+func generateFeedToken() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+// feedBaseURL returns the externally visible scheme and host of the request
+func feedBaseURL(c *gin.Context) string {
+	scheme := "http"
+	if c.Request.TLS != nil {
+		scheme = "https"
+	}
+	if proto := c.GetHeader("X-Forwarded-Proto"); proto != "" {
+		scheme = proto
+	}
+	return scheme + "://" + c.Request.Host
+}
+
+// feedURLs returns the subscription URLs a token unlocks
+func feedURLs(c *gin.Context, token database.FeedToken) map[string]string {
+	base := feedBaseURL(c) + "/api/feeds"
+	switch token.Scope {
+	case FeedScopeCalendar:
+		return map[string]string{"ical": fmt.Sprintf("%s/calendar/%s.ics", base, token.Token)}
+	case FeedScopeRecent:
+		return map[string]string{
+			"rss":          fmt.Sprintf("%s/recent/%s.rss", base, token.Token),
+			"json":         fmt.Sprintf("%s/recent/%s.json", base, token.Token),
+			"library_rss":  fmt.Sprintf("%s/libraries/{libraryId}/recent/%s.rss", base, token.Token),
+			"library_json": fmt.Sprintf("%s/libraries/{libraryId}/recent/%s.json", base, token.Token),
+		}
+	default:
+		return map[string]string{}
+	}
+}
+
+// iCalendar builds an RFC 5545 calendar
+type iCalendar struct {
+	lines []string
+	stamp string
+}
+
+func newICalendar(name string, now time.Time) *iCalendar {
+	return &iCalendar{
+		lines: []string{
+			"BEGIN:VCALENDAR",
+			"VERSION:2.0",
+			"PRODID:-//Viewra//Upcoming Releases//EN",
+			"CALSCALE:GREGORIAN",
+			"METHOD:PUBLISH",
+			"X-WR-CALNAME:" + escapeICalText(name),
+			"REFRESH-INTERVAL;VALUE=DURATION:PT6H",
+		},
+		stamp: now.Format("20060102T150405Z"),
+	}
+}
+
+// addAllDayEvent adds an event spanning the given date. Air dates carry no
+// reliable time of day, so events are all-day rather than guessing a timezone.
+func (cal *iCalendar) addAllDayEvent(uid string, date time.Time, summary, description string) {
+	start := date.UTC()
+	cal.lines = append(cal.lines,
+		"BEGIN:VEVENT",
+		"UID:"+uid+"@viewra",
+		"DTSTAMP:"+cal.stamp,
+		"DTSTART;VALUE=DATE:"+start.Format("20060102"),
+		"DTEND;VALUE=DATE:"+start.AddDate(0, 0, 1).Format("20060102"),
+		"SUMMARY:"+escapeICalText(summary),
+	)
+	if description != "" {
+		cal.lines = append(cal.lines, "DESCRIPTION:"+escapeICalText(description))
+	}
+	cal.lines = append(cal.lines, "TRANSP:TRANSPARENT", "END:VEVENT")
+}
+
+// bytes renders the calendar with CRLF line endings and folded long lines
+func (cal *iCalendar) bytes() []byte {
+	var b strings.Builder
+	for _, line := range append(cal.lines, "END:VCALENDAR") {
+		b.WriteString(foldICalLine(line))
+		b.WriteString("\r\n")
+	}
+	return []byte(b.String())
+}
+
+// escapeICalText escapes TEXT values per RFC 5545 section 3.3.11
+func escapeICalText(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, ";", `\;`)
+	s = strings.ReplaceAll(s, ",", `\,`)
+	s = strings.ReplaceAll(s, "\r\n", `\n`)
+	s = strings.ReplaceAll(s, "\n", `\n`)
+	return s
+}
+
+// foldICalLine splits lines longer than 75 octets, continuing with a leading
+// space, without breaking multi-byte characters
+func foldICalLine(line string) string {
+	const limit = 75
+	if len(line) <= limit {
+		return line
+	}
+
+	var b strings.Builder
+	width := 0
+	for _, r := range line {
+		size := len(string(r))
+		if width+size > limit {
+			b.WriteString("\r\n ")
+			width = 1
+		}
+		b.WriteRune(r)
+		width += size
+	}
+	return b.String()
+}
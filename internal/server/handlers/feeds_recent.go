@@ -0,0 +1,314 @@
+package handlers
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mantonx/viewra/internal/auth"
+	"github.com/mantonx/viewra/internal/database"
+)
+
+const (
+	// recentFeedItems is the number of items in a recently added feed
+	recentFeedItems = 50
+	// recentFeedScanLimit bounds the media files read to fill the feed, since
+	// album tracks collapse into one item
+	recentFeedScanLimit = 1000
+)
+
+// recentItem is a feed entry for a newly added movie, episode or album
+type recentItem struct {
+	ID         string
+	Title      string
+	Summary    string
+	Link       string
+	ArtworkURL string
+	MediaType  string
+	LibraryID  uint32
+	DateAdded  time.Time
+}
+
+// rssFeed is an RSS 2.0 document
+type rssFeed struct {
+	XMLName xml.Name   `xml:"rss"`
+	Version string     `xml:"version,attr"`
+	Channel rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title         string    `xml:"title"`
+	Link          string    `xml:"link"`
+	Description   string    `xml:"description"`
+	LastBuildDate string    `xml:"lastBuildDate"`
+	TTL           int       `xml:"ttl"`
+	Items         []rssItem `xml:"item"`
+}
+
+type rssItem struct {
+	Title       string        `xml:"title"`
+	Link        string        `xml:"link"`
+	Description string        `xml:"description,omitempty"`
+	GUID        rssGUID       `xml:"guid"`
+	PubDate     string        `xml:"pubDate"`
+	Category    string        `xml:"category,omitempty"`
+	Enclosure   *rssEnclosure `xml:"enclosure,omitempty"`
+}
+
+type rssGUID struct {
+	Value       string `xml:",chardata"`
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+}
+
+type rssEnclosure struct {
+	URL    string `xml:"url,attr"`
+	Length int    `xml:"length,attr"`
+	Type   string `xml:"type,attr"`
+}
+
+// jsonFeed is a JSON Feed 1.1 document
+type jsonFeed struct {
+	Version     string         `json:"version"`
+	Title       string         `json:"title"`
+	HomePageURL string         `json:"home_page_url"`
+	FeedURL     string         `json:"feed_url"`
+	Items       []jsonFeedItem `json:"items"`
+}
+
+type jsonFeedItem struct {
+	ID            string               `json:"id"`
+	URL           string               `json:"url"`
+	Title         string               `json:"title"`
+	ContentText   string               `json:"content_text,omitempty"`
+	Image         string               `json:"image,omitempty"`
+	DatePublished string               `json:"date_published"`
+	Tags          []string             `json:"tags,omitempty"`
+	Attachments   []jsonFeedAttachment `json:"attachments,omitempty"`
+}
+
+type jsonFeedAttachment struct {
+	URL      string `json:"url"`
+	MimeType string `json:"mime_type"`
+}
+
+// GetRecentFeed serves recently added media across all libraries. The token
+// suffix selects the format: .rss (default) or .json for JSON Feed.
+func (h *FeedsHandler) GetRecentFeed(c *gin.Context) {
+	h.serveRecentFeed(c, 0)
+}
+
+// GetLibraryRecentFeed serves recently added media for one library
+func (h *FeedsHandler) GetLibraryRecentFeed(c *gin.Context) {
+	libraryID, err := strconv.ParseUint(c.Param("libraryId"), 10, 32)
+	if err != nil || libraryID == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid library ID"})
+		return
+	}
+	h.serveRecentFeed(c, uint32(libraryID))
+}
+
+func (h *FeedsHandler) serveRecentFeed(c *gin.Context, libraryID uint32) {
+	tokenParam := c.Param("token")
+	format := "rss"
+	if strings.HasSuffix(tokenParam, ".json") {
+		format = "json"
+	}
+	token := strings.TrimSuffix(strings.TrimSuffix(tokenParam, ".json"), ".rss")
+
+	feedToken, ok := authorizeFeed(c, token, FeedScopeRecent)
+	if !ok {
+		return
+	}
+
+	title := "Viewra - Recently Added"
+	if libraryID != 0 {
+		db := database.GetDB()
+		var library database.MediaLibrary
+		if err := db.Where("id NOT IN (?)", auth.DeniedLibraries(db, feedToken.UserID)).
+			First(&library, libraryID).Error; err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Library not found"})
+			return
+		}
+		title = fmt.Sprintf("Viewra - Recently Added to %s", library.Path)
+	}
+
+	base := feedBaseURL(c)
+	items, err := loadRecentItems(base, libraryID, feedToken.UserID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to load recently added media",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.Header("Cache-Control", "private, max-age=900")
+
+	if format == "json" {
+		feed := jsonFeed{
+			Version:     "https://jsonfeed.org/version/1.1",
+			Title:       title,
+			HomePageURL: base + "/",
+			FeedURL:     base + c.Request.URL.Path,
+			Items:       make([]jsonFeedItem, 0, len(items)),
+		}
+		for _, item := range items {
+			entry := jsonFeedItem{
+				ID:            item.ID,
+				URL:           item.Link,
+				Title:         item.Title,
+				ContentText:   item.Summary,
+				DatePublished: item.DateAdded.UTC().Format(time.RFC3339),
+				Tags:          []string{item.MediaType},
+			}
+			if item.ArtworkURL != "" {
+				entry.Image = item.ArtworkURL
+				entry.Attachments = []jsonFeedAttachment{{URL: item.ArtworkURL, MimeType: "image/webp"}}
+			}
+			feed.Items = append(feed.Items, entry)
+		}
+		data, err := json.Marshal(feed)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to render feed"})
+			return
+		}
+		c.Data(http.StatusOK, "application/feed+json; charset=utf-8", data)
+		return
+	}
+
+	feed := rssFeed{
+		Version: "2.0",
+		Channel: rssChannel{
+			Title:         title,
+			Link:          base + "/",
+			Description:   "Media recently added to Viewra",
+			LastBuildDate: time.Now().UTC().Format(time.RFC1123Z),
+			TTL:           15,
+			Items:         make([]rssItem, 0, len(items)),
+		},
+	}
+	for _, item := range items {
+		entry := rssItem{
+			Title:       item.Title,
+			Link:        item.Link,
+			Description: item.Summary,
+			GUID:        rssGUID{Value: item.ID},
+			PubDate:     item.DateAdded.UTC().Format(time.RFC1123Z),
+			Category:    item.MediaType,
+		}
+		if item.ArtworkURL != "" {
+			// Asset size isn't known without loading it; RSS allows 0 for unknown lengths
+			entry.Enclosure = &rssEnclosure{URL: item.ArtworkURL, Type: "image/webp"}
+		}
+		feed.Channel.Items = append(feed.Channel.Items, entry)
+	}
+
+	data, err := xml.MarshalIndent(feed, "", "  ")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to render feed"})
+		return
+	}
+	c.Data(http.StatusOK, "application/rss+xml; charset=utf-8", append([]byte(xml.Header), data...))
+}
+
+// loadRecentItems returns the newest media in the libraries a user may see,
+// collapsing album tracks into a single album entry
+func loadRecentItems(base string, libraryID, userID uint32) ([]recentItem, error) {
+	db := database.GetDB()
+
+	query := db.Select("id, media_id, media_type, library_id, created_at").
+		Where("media_type IN ?", []database.MediaType{database.MediaTypeMovie, database.MediaTypeEpisode, database.MediaTypeTrack}).
+		Where("library_id NOT IN (?)", auth.DeniedLibraries(db, userID)).
+		Order("created_at desc").
+		Limit(recentFeedScanLimit)
+	if libraryID != 0 {
+		query = query.Where("library_id = ?", libraryID)
+	}
+
+	var files []database.MediaFile
+	if err := query.Find(&files).Error; err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool)
+	items := make([]recentItem, 0, recentFeedItems)
+	for _, file := range files {
+		if len(items) >= recentFeedItems {
+			break
+		}
+
+		item, ok := describeRecentMedia(base, file)
+		if !ok || seen[item.ID] {
+			continue
+		}
+		seen[item.ID] = true
+		items = append(items, item)
+	}
+
+	return items, nil
+}
+
+// describeRecentMedia builds a feed entry for a media file's movie, episode or album
+func describeRecentMedia(base string, file database.MediaFile) (recentItem, bool) {
+	db := database.GetDB()
+	item := recentItem{
+		MediaType: string(file.MediaType),
+		LibraryID: file.LibraryID,
+		DateAdded: file.CreatedAt,
+	}
+	assetURL := func(entityType, entityID, assetType string) string {
+		return fmt.Sprintf("%s/api/v1/assets/entity/%s/%s/preferred/%s/data", base, entityType, entityID, assetType)
+	}
+
+	switch file.MediaType {
+	case database.MediaTypeMovie:
+		var movie database.Movie
+		if err := db.Select("id, title, overview, release_date").Where("id = ?", file.MediaID).First(&movie).Error; err != nil {
+			return item, false
+		}
+		item.ID = "movie:" + movie.ID
+		item.Title = movie.Title
+		if movie.ReleaseDate != nil {
+			item.Title = fmt.Sprintf("%s (%d)", movie.Title, movie.ReleaseDate.Year())
+		}
+		item.Summary = movie.Overview
+		item.Link = base + "/player/movie/" + movie.ID
+		item.ArtworkURL = assetURL("movie", movie.ID, "poster")
+
+	case database.MediaTypeEpisode:
+		var episode database.Episode
+		if err := db.Preload("Season.TVShow").Where("id = ?", file.MediaID).First(&episode).Error; err != nil {
+			return item, false
+		}
+		show := episode.Season.TVShow
+		item.ID = "episode:" + episode.ID
+		item.Title = fmt.Sprintf("%s S%02dE%02d", show.Title, episode.Season.SeasonNumber, episode.EpisodeNumber)
+		if episode.Title != "" {
+			item.Title += " - " + episode.Title
+		}
+		item.Summary = episode.Description
+		item.Link = base + "/tv-shows/" + show.ID
+		item.ArtworkURL = assetURL("tv_show", show.ID, "poster")
+
+	case database.MediaTypeTrack:
+		var track database.Track
+		if err := db.Preload("Album.Artist").Where("id = ?", file.MediaID).First(&track).Error; err != nil {
+			return item, false
+		}
+		item.MediaType = "album"
+		item.ID = "album:" + track.AlbumID
+		item.Title = fmt.Sprintf("%s - %s", track.Album.Artist.Name, track.Album.Title)
+		item.Link = base + "/music"
+		item.ArtworkURL = assetURL("album", track.AlbumID, "cover")
+
+	default:
+		return item, false
+	}
+
+	return item, true
+}
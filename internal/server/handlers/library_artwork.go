@@ -0,0 +1,99 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mantonx/viewra/internal/database"
+	"github.com/mantonx/viewra/internal/logger"
+	"github.com/mantonx/viewra/internal/modules/assetmodule"
+)
+
+// GetLibraryArtworkSettings returns a library's artwork selection settings.
+// Libraries without saved settings use the defaults.
+func (h *AdminHandler) GetLibraryArtworkSettings(c *gin.Context) {
+	libraryID, ok := parseLibraryID(c)
+	if !ok {
+		return
+	}
+
+	db := database.GetDB()
+	var library database.MediaLibrary
+	if err := db.First(&library, libraryID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Library not found"})
+		return
+	}
+
+	settings := database.LibraryArtworkSettings{LibraryID: library.ID}
+	var saved []database.LibraryArtworkSettings
+	if err := db.Where("library_id = ?", library.ID).Limit(1).Find(&saved).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to retrieve artwork settings",
+			"details": err.Error(),
+		})
+		return
+	}
+	if len(saved) > 0 {
+		settings = saved[0]
+	}
+
+	c.JSON(http.StatusOK, settings)
+}
+
+// SetLibraryArtworkSettings stores a library's artwork selection settings.
+// Turning on textless backdrops re-selects the preferred backdrop of titles
+// whose current one has embedded text.
+func (h *AdminHandler) SetLibraryArtworkSettings(c *gin.Context) {
+	libraryID, ok := parseLibraryID(c)
+	if !ok {
+		return
+	}
+
+	var req struct {
+		PreferTextlessBackdrops bool `json:"prefer_textless_backdrops"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request format",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	db := database.GetDB()
+	var library database.MediaLibrary
+	if err := db.First(&library, libraryID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Library not found"})
+		return
+	}
+
+	settings := database.LibraryArtworkSettings{
+		LibraryID:               library.ID,
+		PreferTextlessBackdrops: req.PreferTextlessBackdrops,
+	}
+	if err := db.Save(&settings).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to save artwork settings",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	switched := 0
+	if settings.PreferTextlessBackdrops {
+		if manager := assetmodule.GetAssetManager(); manager != nil {
+			var err error
+			if switched, err = manager.SelectTextlessBackdrops(library.ID); err != nil {
+				logger.Warn("Failed to apply textless backdrop preference for library %d: %v", library.ID, err)
+			}
+		}
+	}
+
+	logger.Info("Updated artwork settings for library %d: prefer_textless_backdrops=%v, %d backdrops switched",
+		library.ID, settings.PreferTextlessBackdrops, switched)
+
+	c.JSON(http.StatusOK, gin.H{
+		"settings":           settings,
+		"backdrops_switched": switched,
+	})
+}
@@ -0,0 +1,144 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mantonx/viewra/internal/database"
+	"github.com/mantonx/viewra/internal/logger"
+	"github.com/mantonx/viewra/sdk/namingparser"
+	"gorm.io/gorm"
+)
+
+// Bounds on a library's naming rules, as every rule runs on every file name
+// the library's scans and matches read
+const (
+	maxLibraryNamingRules  = 50
+	maxNamingPatternLength = 512
+)
+
+// namingRule is a naming rule as it is read and written through the API
+type namingRule struct {
+	Pattern     string `json:"pattern" binding:"required"`
+	Replacement string `json:"replacement"`
+	Description string `json:"description,omitempty"`
+}
+
+// GetLibraryNamingRules lists the rules rewriting a library's file names
+// before matching, in the order they apply
+func (h *AdminHandler) GetLibraryNamingRules(c *gin.Context) {
+	libraryID, ok := parseLibraryID(c)
+	if !ok {
+		return
+	}
+
+	db := database.GetDB()
+	var library database.MediaLibrary
+	if err := db.First(&library, libraryID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Library not found"})
+		return
+	}
+
+	var rules []database.LibraryNamingRule
+	if err := db.Where("library_id = ?", libraryID).Order("position, id").Find(&rules).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to retrieve naming rules",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"library_id": libraryID,
+		"rules":      rules,
+	})
+}
+
+// SetLibraryNamingRules replaces a library's naming rules. Rules apply in
+// the order given; an empty list removes them. With sample file names, the
+// response shows what each is rewritten to.
+func (h *AdminHandler) SetLibraryNamingRules(c *gin.Context) {
+	libraryID, ok := parseLibraryID(c)
+	if !ok {
+		return
+	}
+
+	var req struct {
+		Rules   []namingRule `json:"rules"`
+		Samples []string     `json:"samples"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request format",
+			"details": err.Error(),
+		})
+		return
+	}
+	if len(req.Rules) > maxLibraryNamingRules {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Too many naming rules", "max": maxLibraryNamingRules})
+		return
+	}
+
+	db := database.GetDB()
+	var library database.MediaLibrary
+	if err := db.First(&library, libraryID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Library not found"})
+		return
+	}
+
+	rewrites := make([]namingparser.Rewrite, 0, len(req.Rules))
+	rules := make([]database.LibraryNamingRule, 0, len(req.Rules))
+	for i, rule := range req.Rules {
+		if len(rule.Pattern) > maxNamingPatternLength {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Naming rule pattern is too long", "index": i, "max": maxNamingPatternLength})
+			return
+		}
+		rewrite, err := namingparser.CompileRewrite(rule.Pattern, rule.Replacement)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Invalid naming rule",
+				"index":   i,
+				"details": err.Error(),
+			})
+			return
+		}
+		rewrites = append(rewrites, rewrite)
+		rules = append(rules, database.LibraryNamingRule{
+			LibraryID:   library.ID,
+			Position:    i,
+			Pattern:     rule.Pattern,
+			Replacement: rule.Replacement,
+			Description: rule.Description,
+		})
+	}
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("library_id = ?", library.ID).Delete(&database.LibraryNamingRule{}).Error; err != nil {
+			return err
+		}
+		if len(rules) == 0 {
+			return nil
+		}
+		return tx.Create(&rules).Error
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to save naming rules",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	logger.Info("Updated library naming rules", "library_id", library.ID, "rules", len(rules))
+
+	samples := make(map[string]string, len(req.Samples))
+	for _, sample := range req.Samples {
+		samples[sample] = namingparser.ApplyRewrites(sample, rewrites)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"library_id": library.ID,
+		"rules":      rules,
+		"samples":    samples,
+	})
+}
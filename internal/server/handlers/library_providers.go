@@ -0,0 +1,191 @@
+package handlers
+
+import (
+	"net/http"
+	"slices"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mantonx/viewra/internal/database"
+	"github.com/mantonx/viewra/internal/logger"
+	"github.com/mantonx/viewra/internal/modules/pluginmodule"
+	"gorm.io/gorm"
+)
+
+// enrichmentProvider describes an installed enrichment plugin and whether a library uses it
+type enrichmentProvider struct {
+	PluginID string `json:"plugin_id"`
+	Name     string `json:"name"`
+	Status   string `json:"status"`
+	Selected bool   `json:"selected"`
+}
+
+// GetLibraryEnrichmentProviders lists the enrichment plugins a library uses.
+// An empty selection means every enabled enricher runs for the library.
+func (h *AdminHandler) GetLibraryEnrichmentProviders(c *gin.Context) {
+	libraryID, ok := parseLibraryID(c)
+	if !ok {
+		return
+	}
+
+	db := database.GetDB()
+	var library database.MediaLibrary
+	if err := db.First(&library, libraryID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Library not found"})
+		return
+	}
+
+	var selected []database.LibraryEnrichmentProvider
+	if err := db.Where("library_id = ?", libraryID).Find(&selected).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to retrieve enrichment providers",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	available, err := enrichmentPlugins(db)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to retrieve plugins",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	selectedIDs := make([]string, 0, len(selected))
+	isSelected := make(map[string]bool, len(selected))
+	for _, provider := range selected {
+		selectedIDs = append(selectedIDs, provider.PluginID)
+		isSelected[provider.PluginID] = true
+	}
+
+	providers := make([]enrichmentProvider, 0, len(available))
+	for _, plugin := range available {
+		providers = append(providers, enrichmentProvider{
+			PluginID: plugin.PluginID,
+			Name:     plugin.Name,
+			Status:   plugin.Status,
+			Selected: len(selected) == 0 || isSelected[plugin.PluginID],
+		})
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"library_id":   libraryID,
+		"library_type": library.Type,
+		"use_all":      len(selected) == 0,
+		"selected":     selectedIDs,
+		"providers":    providers,
+	})
+}
+
+// SetLibraryEnrichmentProviders replaces a library's enrichment provider
+// selection. An empty list restores the default of using every enricher.
+func (h *AdminHandler) SetLibraryEnrichmentProviders(c *gin.Context) {
+	libraryID, ok := parseLibraryID(c)
+	if !ok {
+		return
+	}
+
+	var req struct {
+		Providers []string `json:"providers"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request format",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	db := database.GetDB()
+	var library database.MediaLibrary
+	if err := db.First(&library, libraryID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Library not found"})
+		return
+	}
+
+	available, err := enrichmentPlugins(db)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to retrieve plugins",
+			"details": err.Error(),
+		})
+		return
+	}
+	known := make(map[string]bool, len(available))
+	for _, plugin := range available {
+		known[plugin.PluginID] = true
+	}
+
+	selectedIDs := make([]string, 0, len(req.Providers))
+	providers := make([]database.LibraryEnrichmentProvider, 0, len(req.Providers))
+	for _, pluginID := range req.Providers {
+		if !known[pluginID] {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":     "Unknown enrichment plugin",
+				"plugin_id": pluginID,
+			})
+			return
+		}
+		if slices.Contains(selectedIDs, pluginID) {
+			continue
+		}
+		selectedIDs = append(selectedIDs, pluginID)
+		providers = append(providers, database.LibraryEnrichmentProvider{
+			LibraryID: library.ID,
+			PluginID:  pluginID,
+		})
+	}
+
+	err = db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("library_id = ?", library.ID).Delete(&database.LibraryEnrichmentProvider{}).Error; err != nil {
+			return err
+		}
+		if len(providers) == 0 {
+			return nil
+		}
+		return tx.Create(&providers).Error
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to save enrichment providers",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	logger.Info("Updated library enrichment providers", "library_id", library.ID, "providers", selectedIDs)
+
+	c.JSON(http.StatusOK, gin.H{
+		"library_id": library.ID,
+		"use_all":    len(providers) == 0,
+		"selected":   selectedIDs,
+	})
+}
+
+// enrichmentPlugins returns installed plugins that enrich metadata
+func enrichmentPlugins(db *gorm.DB) ([]database.Plugin, error) {
+	var plugins []database.Plugin
+	if err := db.Order("name").Find(&plugins).Error; err != nil {
+		return nil, err
+	}
+
+	enrichers := make([]database.Plugin, 0, len(plugins))
+	for _, plugin := range plugins {
+		if pluginmodule.IsEnrichmentPlugin(plugin.PluginID, plugin.Name, plugin.Type) {
+			enrichers = append(enrichers, plugin)
+		}
+	}
+	return enrichers, nil
+}
+
+// parseLibraryID reads the :id parameter, responding with 400 when invalid
+func parseLibraryID(c *gin.Context) (uint32, bool) {
+	libraryID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid library ID"})
+		return 0, false
+	}
+	return uint32(libraryID), true
+}
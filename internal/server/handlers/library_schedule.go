@@ -0,0 +1,130 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mantonx/viewra/internal/database"
+	"github.com/mantonx/viewra/internal/logger"
+	"github.com/mantonx/viewra/internal/modules/scannermodule/scanner"
+)
+
+// maxScanBlackouts bounds the blackout windows of a library
+const maxScanBlackouts = 20
+
+// GetLibraryScanSchedule returns a library's scan schedule. Libraries
+// without one are only scanned by hand or by the file monitor.
+func (h *AdminHandler) GetLibraryScanSchedule(c *gin.Context) {
+	libraryID, ok := parseLibraryID(c)
+	if !ok {
+		return
+	}
+
+	db := database.GetDB()
+	var library database.MediaLibrary
+	if err := db.First(&library, libraryID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Library not found"})
+		return
+	}
+
+	schedule := database.LibraryScanSchedule{LibraryID: library.ID, Blackouts: database.ScanWindows{}}
+	var saved []database.LibraryScanSchedule
+	if err := db.Where("library_id = ?", library.ID).Limit(1).Find(&saved).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to retrieve scan schedule",
+			"details": err.Error(),
+		})
+		return
+	}
+	if len(saved) > 0 {
+		schedule = saved[0]
+	}
+
+	c.JSON(http.StatusOK, schedule)
+}
+
+// SetLibraryScanSchedule replaces a library's scan schedule: a cron
+// expression or an interval in minutes (or neither), whether to scan when
+// the server starts, and blackout windows in which scheduled scans wait.
+// The next scan is worked out from now.
+func (h *AdminHandler) SetLibraryScanSchedule(c *gin.Context) {
+	libraryID, ok := parseLibraryID(c)
+	if !ok {
+		return
+	}
+
+	var req struct {
+		Cron            string               `json:"cron"`
+		IntervalMinutes int                  `json:"interval_minutes"`
+		ScanOnStartup   bool                 `json:"scan_on_startup"`
+		Blackouts       database.ScanWindows `json:"blackouts"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request format",
+			"details": err.Error(),
+		})
+		return
+	}
+	if len(req.Blackouts) > maxScanBlackouts {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": fmt.Sprintf("A library can have at most %d blackout windows", maxScanBlackouts),
+		})
+		return
+	}
+
+	db := database.GetDB()
+	var library database.MediaLibrary
+	if err := db.First(&library, libraryID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Library not found"})
+		return
+	}
+
+	// Keep the record of the last scheduled scan, so a scan paused for a
+	// blackout is still resumed
+	schedule := database.LibraryScanSchedule{LibraryID: library.ID}
+	var saved []database.LibraryScanSchedule
+	if err := db.Where("library_id = ?", library.ID).Limit(1).Find(&saved).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to retrieve scan schedule",
+			"details": err.Error(),
+		})
+		return
+	}
+	if len(saved) > 0 {
+		schedule = saved[0]
+	}
+	schedule.Cron = strings.TrimSpace(req.Cron)
+	schedule.IntervalMinutes = req.IntervalMinutes
+	schedule.ScanOnStartup = req.ScanOnStartup
+	schedule.Blackouts = req.Blackouts
+	if schedule.Blackouts == nil {
+		schedule.Blackouts = database.ScanWindows{}
+	}
+
+	parsed, err := scanner.ParseLibrarySchedule(&schedule)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid scan schedule",
+			"details": err.Error(),
+		})
+		return
+	}
+	schedule.NextScanAt = parsed.Next(time.Now())
+
+	if err := db.Save(&schedule).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to save scan schedule",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	logger.Info("Updated scan schedule for library %d: cron=%q interval=%dm startup=%v blackouts=%d",
+		library.ID, schedule.Cron, schedule.IntervalMinutes, schedule.ScanOnStartup, len(schedule.Blackouts))
+
+	c.JSON(http.StatusOK, schedule)
+}
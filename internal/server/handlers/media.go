@@ -12,6 +12,7 @@ import (
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/mantonx/viewra/internal/auth"
 	"github.com/mantonx/viewra/internal/database"
 	"github.com/mantonx/viewra/internal/events"
 	"github.com/mantonx/viewra/internal/utils"
@@ -35,7 +36,12 @@ func (h *MediaHandler) GetMedia(c *gin.Context) {
 	var mediaFiles []database.MediaFile
 	db := database.GetDB()
 
-	result := db.Find(&mediaFiles)
+	visible, ok := visibleLibrariesScope(c, db)
+	if !ok {
+		return
+	}
+
+	result := db.Scopes(visible).Find(&mediaFiles)
 	if result.Error != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error":   "Failed to retrieve media",
@@ -50,6 +56,25 @@ func (h *MediaHandler) GetMedia(c *gin.Context) {
 	})
 }
 
+// visibleLibrariesScope keeps a media file query to the libraries the user
+// in user_id may see. The auth middleware sets user_id to the caller for
+// users other than admins; without one every library is listed. Writes an
+// error response and returns false for a malformed user_id.
+func visibleLibrariesScope(c *gin.Context, db *gorm.DB) (func(*gorm.DB) *gorm.DB, bool) {
+	userIDStr := c.Query("user_id")
+	if userIDStr == "" {
+		return func(query *gorm.DB) *gorm.DB { return query }, true
+	}
+	userID, err := strconv.ParseUint(userIDStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return nil, false
+	}
+	return func(query *gorm.DB) *gorm.DB {
+		return query.Where("media_files.library_id NOT IN (?)", auth.DeniedLibraries(db, uint32(userID)))
+	}, true
+}
+
 // GetMediaByID retrieves a specific media file by ID
 func (h *MediaHandler) GetMediaByID(c *gin.Context) {
 	mediaIDStr := c.Param("id")
@@ -394,6 +419,7 @@ func GetMediaFileMetadata(c *gin.Context) {
 					"artist":      track.Artist.Name,
 					"album":       track.Album.Title,
 					"album_artist": track.Album.Artist.Name,
+					"disc_number":  track.DiscNumber,
 					"track_number": track.TrackNumber,
 
 					"duration":     track.Duration,
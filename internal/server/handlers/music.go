@@ -10,6 +10,7 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/mantonx/viewra/internal/database"
 	"github.com/mantonx/viewra/internal/events"
+	"github.com/mantonx/viewra/internal/utils"
 )
 
 // MusicHandler handles music-related API endpoints
@@ -64,6 +65,7 @@ func (h *MusicHandler) GetMusicMetadata(c *gin.Context) {
 		"artist":       track.Artist.Name,
 		"album":        track.Album.Title,
 		"album_artist": track.Album.Artist.Name,
+		"disc_number":  track.DiscNumber,
 		"track_number": track.TrackNumber,
 		"duration":     track.Duration,
 		"lyrics":       track.Lyrics,
@@ -87,7 +89,20 @@ func (h *MusicHandler) GetMusicFiles(c *gin.Context) {
 		offset = 0
 	}
 
+	// Optional audio quality filters, e.g. ?lossless=true&min_bit_depth=24
+	filter, err := utils.ParseAudioQualityFilter(c.Query)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
 	db := database.GetDB()
+	visible, ok := visibleLibrariesScope(c, db)
+	if !ok {
+		return
+	}
 
 	// Find all music libraries dynamically
 	var musicLibraries []database.MediaLibrary
@@ -120,7 +135,7 @@ func (h *MusicHandler) GetMusicFiles(c *gin.Context) {
 
 	// Query to fetch MediaFiles that are music tracks
 	var mediaFiles []database.MediaFile
-	err = db.Where("library_id IN ? AND media_type = ?", libraryIDs, database.MediaTypeTrack).
+	err = filter.Apply(db.Scopes(visible).Where("library_id IN ? AND media_type = ?", libraryIDs, database.MediaTypeTrack), "media_files").
 		Limit(limit).
 		Offset(offset).
 		Find(&mediaFiles).Error
@@ -135,8 +150,8 @@ func (h *MusicHandler) GetMusicFiles(c *gin.Context) {
 
 	// Get total count for all music libraries
 	var total int64
-	db.Model(&database.MediaFile{}).
-		Where("library_id IN ? AND media_type = ?", libraryIDs, database.MediaTypeTrack).
+	filter.Apply(db.Model(&database.MediaFile{}).Scopes(visible).
+		Where("library_id IN ? AND media_type = ?", libraryIDs, database.MediaTypeTrack), "media_files").
 		Count(&total)
 
 	// Build response with track metadata
@@ -159,6 +174,9 @@ func (h *MusicHandler) GetMusicFiles(c *gin.Context) {
 			"audio_codec":  mediaFile.AudioCodec,
 			"channels":     mediaFile.Channels,
 			"sample_rate":  mediaFile.SampleRate,
+			"bit_depth":    mediaFile.AudioBitDepth,
+			"lossless":     mediaFile.AudioLossless,
+			"badge":        utils.AudioQualityBadge(mediaFile.AudioCodec, mediaFile.SampleRate, mediaFile.AudioBitDepth),
 			"resolution":   mediaFile.Resolution,
 			"duration":     mediaFile.Duration,
 			"size_bytes":   mediaFile.SizeBytes,
@@ -179,6 +197,7 @@ func (h *MusicHandler) GetMusicFiles(c *gin.Context) {
 				"artist":       track.Artist.Name,
 				"album":        track.Album.Title,
 				"album_artist": track.Album.Artist.Name,
+				"disc_number":  track.DiscNumber,
 				"track_number": track.TrackNumber,
 				"duration":     track.Duration,
 				"lyrics":       track.Lyrics,
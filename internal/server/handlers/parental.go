@@ -0,0 +1,209 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mantonx/viewra/internal/database"
+	"github.com/mantonx/viewra/internal/parental"
+)
+
+// parentalControlRequest sets a user's parental controls. An empty
+// max_rating removes them; pin is kept when left out and cleared when empty.
+type parentalControlRequest struct {
+	MaxRating    string  `json:"max_rating"`
+	BlockUnrated bool    `json:"block_unrated"`
+	PIN          *string `json:"pin"`
+}
+
+// unlockRequest lifts a user's parental controls for a while
+type unlockRequest struct {
+	PIN string `json:"pin" binding:"required"`
+}
+
+// GetParentalControls returns a user's maximum content rating and whether
+// a PIN override is set or active
+func (h *UsersHandler) GetParentalControls(c *gin.Context) {
+	user, ok := loadUserParam(c)
+	if !ok {
+		return
+	}
+	control, ok := loadParentalControl(c, user.ID)
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, parentalControlResponse(user.ID, control))
+}
+
+// SetParentalControls sets or removes a user's maximum content rating and
+// override PIN
+func (h *UsersHandler) SetParentalControls(c *gin.Context) {
+	user, ok := loadUserParam(c)
+	if !ok {
+		return
+	}
+
+	var req parentalControlRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request format",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	db := database.GetDB()
+	if req.MaxRating == "" {
+		if err := db.Where("user_id = ?", user.ID).Delete(&database.UserParentalControl{}).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error":   "Failed to remove parental controls",
+				"details": err.Error(),
+			})
+			return
+		}
+		c.JSON(http.StatusOK, parentalControlResponse(user.ID, nil))
+		return
+	}
+	if !parental.Valid(req.MaxRating) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": parental.ErrUnknownRating.Error()})
+		return
+	}
+
+	control, ok := loadParentalControl(c, user.ID)
+	if !ok {
+		return
+	}
+	if control == nil {
+		control = &database.UserParentalControl{UserID: user.ID}
+	}
+	control.MaxRating = req.MaxRating
+	control.BlockUnrated = req.BlockUnrated
+	if req.PIN != nil {
+		control.PINHash = ""
+		control.OverrideUntil = nil
+		if *req.PIN != "" {
+			hash, err := parental.HashPIN(*req.PIN)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			}
+			control.PINHash = hash
+		}
+	}
+	if err := db.Save(control).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to save parental controls",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, parentalControlResponse(user.ID, control))
+}
+
+// UnlockParentalControls lifts a user's parental controls for
+// parental.OverrideDuration when their PIN is right
+func (h *UsersHandler) UnlockParentalControls(c *gin.Context) {
+	user, ok := loadUserParam(c)
+	if !ok {
+		return
+	}
+
+	var req unlockRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request format",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	control, ok := loadParentalControl(c, user.ID)
+	if !ok {
+		return
+	}
+	if control == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "No parental controls are set"})
+		return
+	}
+
+	err := parental.Unlock(database.GetDB(), control, req.PIN, time.Now())
+	switch {
+	case errors.Is(err, parental.ErrNoPIN):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	case errors.Is(err, parental.ErrWrongPIN):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		return
+	case errors.Is(err, parental.ErrLockedOut):
+		c.JSON(http.StatusTooManyRequests, gin.H{"error": err.Error()})
+		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	control, ok = loadParentalControl(c, user.ID)
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, parentalControlResponse(user.ID, control))
+}
+
+// LockParentalControls ends a PIN override early
+func (h *UsersHandler) LockParentalControls(c *gin.Context) {
+	user, ok := loadUserParam(c)
+	if !ok {
+		return
+	}
+	control, ok := loadParentalControl(c, user.ID)
+	if !ok {
+		return
+	}
+	if control != nil {
+		if err := parental.Lock(database.GetDB(), control); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		control.OverrideUntil = nil
+	}
+	c.JSON(http.StatusOK, parentalControlResponse(user.ID, control))
+}
+
+// loadParentalControl loads a user's parental controls, or nil when they
+// have none, writing an error response and returning false on failure
+func loadParentalControl(c *gin.Context, userID uint32) (*database.UserParentalControl, bool) {
+	var controls []database.UserParentalControl
+	if err := database.GetDB().Where("user_id = ?", userID).Limit(1).Find(&controls).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to load parental controls",
+			"details": err.Error(),
+		})
+		return nil, false
+	}
+	if len(controls) == 0 {
+		return nil, true
+	}
+	return &controls[0], true
+}
+
+func parentalControlResponse(userID uint32, control *database.UserParentalControl) gin.H {
+	if control == nil {
+		return gin.H{"user_id": userID, "enabled": false}
+	}
+	overridden := control.OverrideUntil != nil && time.Now().Before(*control.OverrideUntil)
+	response := gin.H{
+		"user_id":       userID,
+		"enabled":       true,
+		"max_rating":    control.MaxRating,
+		"block_unrated": control.BlockUnrated,
+		"has_pin":       control.PINHash != "",
+		"overridden":    overridden,
+	}
+	if overridden {
+		response["override_until"] = control.OverrideUntil
+	}
+	return response
+}
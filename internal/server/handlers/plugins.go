@@ -468,10 +468,83 @@ func GetPluginUIComponents(c *gin.Context) {
 	})
 }
 
+// GetPluginCapabilities describes what each installed plugin offers so the
+// frontend can adapt its UI without hard-coding plugin knowledge
+func GetPluginCapabilities(c *gin.Context) {
+	if pluginModule == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{
+			"error": "Plugin module not initialized",
+		})
+		return
+	}
+
+	capabilities := pluginModule.GetPluginCapabilities(c.Request.Context())
+
+	c.JSON(http.StatusOK, gin.H{
+		"plugins": capabilities,
+		"count":   len(capabilities),
+	})
+}
+
+// GetDashboardWidgets returns the widgets running plugins contribute to the
+// admin home screen, with their current data. Plugins that fail to answer
+// are listed in errors instead of failing the request.
+func GetDashboardWidgets(c *gin.Context) {
+	if pluginModule == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{
+			"error": "Plugin module not initialized",
+		})
+		return
+	}
+
+	widgets, failures := pluginModule.GetDashboardWidgets(c.Request.Context())
+
+	c.JSON(http.StatusOK, gin.H{
+		"widgets": widgets,
+		"errors":  failures,
+		"count":   len(widgets),
+	})
+}
+
+// GetProviderMetrics returns the latency histograms and status code counts of
+// the requests running plugins make to external providers such as TMDb, so
+// slow APIs can be spotted from the admin dashboard. Plugins that fail to
+// answer are listed in errors instead of failing the request.
+func GetProviderMetrics(c *gin.Context) {
+	if pluginModule == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{
+			"error": "Plugin module not initialized",
+		})
+		return
+	}
+
+	providers, failures := pluginModule.GetProviderMetrics(c.Request.Context())
+
+	c.JSON(http.StatusOK, gin.H{
+		"providers": providers,
+		"errors":    failures,
+		"count":     len(providers),
+	})
+}
+
 // =============================================================================
 // PLUGIN ROUTE PROXY
 // =============================================================================
 
+// IsPublicPluginRoute reports whether a request is for a plugin API route the
+// plugin declares public, which RequireAuth then lets through without a token
+func IsPublicPluginRoute(c *gin.Context) bool {
+	pluginPath, ok := strings.CutPrefix(c.Request.URL.Path, "/api/plugins/")
+	if !ok || pluginModule == nil {
+		return false
+	}
+	pluginID, routePath, _ := strings.Cut(pluginPath, "/")
+	if pluginID == "" {
+		return false
+	}
+	return pluginModule.IsPublicRoute(c.Request.Context(), pluginID, c.Request.Method, routePath)
+}
+
 // HandlePluginRoute handles dynamic plugin routes
 func HandlePluginRoute(c *gin.Context) {
 	// Extract plugin path from the URL
@@ -480,9 +553,26 @@ func HandlePluginRoute(c *gin.Context) {
 	// Remove leading slash if present
 	pluginPath = strings.TrimPrefix(pluginPath, "/")
 
-	c.JSON(http.StatusNotImplemented, gin.H{
-		"error":       "Plugin routes not yet implemented",
-		"plugin_path": pluginPath,
-		"method":      c.Request.Method,
-	})
+	// Gin can't register static routes beside the /*path catch-all, so the
+	// capabilities endpoint is dispatched here
+	if pluginPath == "capabilities" && c.Request.Method == http.MethodGet {
+		GetPluginCapabilities(c)
+		return
+	}
+
+	if pluginModule == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{
+			"error": "Plugin module not initialized",
+		})
+		return
+	}
+
+	// The first segment names the plugin; the rest is the plugin's own route
+	pluginID, routePath, _ := strings.Cut(pluginPath, "/")
+	if pluginID == "" {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Plugin ID is required"})
+		return
+	}
+
+	pluginModule.ServePluginRoute(c, pluginID, routePath)
 }
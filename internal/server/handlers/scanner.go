@@ -7,8 +7,10 @@ import (
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/mantonx/viewra/internal/config"
 	"github.com/mantonx/viewra/internal/database"
 	"github.com/mantonx/viewra/internal/logger"
+	"github.com/mantonx/viewra/internal/modules/assetmodule"
 	"github.com/mantonx/viewra/internal/modules/modulemanager"
 	"github.com/mantonx/viewra/internal/modules/scannermodule"
 	"github.com/mantonx/viewra/internal/modules/scannermodule/scanner"
@@ -69,7 +71,12 @@ func StartLibraryScan(c *gin.Context) {
 		return
 	}
 
-	scanJob, err := scannerManager.StartScan(uint32(libraryID))
+	// ?profile=true times the scan's stages, handlers and hooks in its report
+	start := scannerManager.StartScan
+	if c.Query("profile") == "true" {
+		start = scannerManager.StartProfiledScan
+	}
+	scanJob, err := start(uint32(libraryID))
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{
 			"error":   "Failed to start scan",
@@ -943,19 +950,19 @@ func GetMonitoringStatus(c *gin.Context) {
 	})
 }
 
-// CleanupOrphanedAssets removes assets that reference non-existent media files
+// CleanupOrphanedAssets runs asset garbage collection, deleting assets whose
+// media has been gone for longer than the configured retention window.
+// dry_run=true only reports the orphaned assets and reclaimable space.
 func CleanupOrphanedAssets(c *gin.Context) {
-	_, err := getScannerManager()
-	if err != nil {
+	manager := assetmodule.GetAssetManager()
+	if manager == nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
-			"error":   "Scanner module not available",
-			"details": err.Error(),
+			"error": "Asset module not available",
 		})
 		return
 	}
 
-	assetsRemoved, filesRemoved := 0, 0
-	err = fmt.Errorf("CleanupOrphanedAssets method is deprecated")
+	report, err := manager.CollectGarbage(config.Get().Assets.OrphanRetention, c.Query("dry_run") == "true")
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error":   "Failed to cleanup orphaned assets",
@@ -965,9 +972,12 @@ func CleanupOrphanedAssets(c *gin.Context) {
 	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"message":        "Orphaned assets cleaned up successfully",
-		"assets_removed": assetsRemoved,
-		"files_removed":  filesRemoved,
+		"message":           "Orphaned assets cleaned up successfully",
+		"orphaned_assets":   report.OrphanedAssets,
+		"reclaimable_bytes": report.ReclaimableBytes,
+		"assets_removed":    report.DeletedAssets,
+		"freed_bytes":       report.FreedBytes,
+		"report":            report,
 	})
 }
 
@@ -1,9 +1,484 @@
 package handlers
 
+import (
+	"context"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mantonx/viewra/internal/config"
+	"github.com/shirou/gopsutil/v4/cpu"
+	"github.com/shirou/gopsutil/v4/disk"
+	"github.com/shirou/gopsutil/v4/load"
+	"github.com/shirou/gopsutil/v4/mem"
+	"github.com/shirou/gopsutil/v4/process"
+)
+
+const (
+	// transcodeUsageTTL is how long a transcode directory scan is reused, since
+	// walking many segment files on every poll is expensive
+	transcodeUsageTTL = 30 * time.Second
+	// resourceQueryTimeout bounds the gopsutil calls made for one request
+	resourceQueryTimeout = 5 * time.Second
+)
+
 // SystemHandler handles system diagnostic endpoints
-type SystemHandler struct{}
+type SystemHandler struct {
+	startTime time.Time
+
+	// processMu guards process, whose CPU percent tracks the previous call
+	processMu sync.Mutex
+	process   *process.Process
+
+	mu             sync.Mutex
+	transcodeUsage *TranscodeDirUsage
+}
 
 // NewSystemHandler creates a new system handler
 func NewSystemHandler() *SystemHandler {
-	return &SystemHandler{}
+	h := &SystemHandler{startTime: time.Now()}
+	// Keep one process handle so CPU percent is measured between successive calls
+	if proc, err := process.NewProcess(int32(os.Getpid())); err == nil {
+		h.process = proc
+		proc.Percent(0)
+	}
+	// Prime the host CPU baseline; the first non-blocking sample is meaningless
+	cpu.Percent(0, false)
+	return h
+}
+
+// SystemResources is a snapshot of the server's own resource usage
+type SystemResources struct {
+	Timestamp time.Time          `json:"timestamp"`
+	Host      HostResources      `json:"host"`
+	Process   ProcessResources   `json:"process"`
+	Container *ContainerLimits   `json:"container,omitempty"`
+	Volumes   []VolumeUsage      `json:"volumes"`
+	Transcode *TranscodeDirUsage `json:"transcode,omitempty"`
+	Alerts    []ResourceAlert    `json:"alerts"`
+}
+
+// HostResources describes the machine or container the server runs on
+type HostResources struct {
+	CPUCores      int     `json:"cpu_cores"`
+	CPUPercent    float64 `json:"cpu_percent"`
+	Load1         float64 `json:"load_1,omitempty"`
+	Load5         float64 `json:"load_5,omitempty"`
+	Load15        float64 `json:"load_15,omitempty"`
+	MemoryTotal   uint64  `json:"memory_total"`
+	MemoryUsed    uint64  `json:"memory_used"`
+	MemoryPercent float64 `json:"memory_percent"`
+	SwapTotal     uint64  `json:"swap_total"`
+	SwapUsed      uint64  `json:"swap_used"`
+}
+
+// ProcessResources describes the Viewra server process
+type ProcessResources struct {
+	PID           int     `json:"pid"`
+	UptimeSeconds int64   `json:"uptime_seconds"`
+	CPUPercent    float64 `json:"cpu_percent"`
+	MemoryRSS     uint64  `json:"memory_rss"`
+	Goroutines    int     `json:"goroutines"`
+	HeapAlloc     uint64  `json:"heap_alloc"`
+	HeapSys       uint64  `json:"heap_sys"`
+	NumGC         uint32  `json:"num_gc"`
+	OpenFiles     int     `json:"open_files,omitempty"`
+}
+
+// ContainerLimits are the cgroup limits applied when running in a container.
+// Zero limits mean unlimited.
+type ContainerLimits struct {
+	Runtime       string  `json:"runtime"`
+	CgroupVersion int     `json:"cgroup_version,omitempty"`
+	MemoryLimit   uint64  `json:"memory_limit,omitempty"`
+	MemoryUsage   uint64  `json:"memory_usage,omitempty"`
+	MemoryPercent float64 `json:"memory_percent,omitempty"`
+	CPULimit      float64 `json:"cpu_limit,omitempty"` // in cores
+}
+
+// VolumeUsage is disk usage for the volume holding one of the server's directories
+type VolumeUsage struct {
+	Label       string  `json:"label"`
+	Path        string  `json:"path"`
+	Mountpoint  string  `json:"mountpoint,omitempty"`
+	Fstype      string  `json:"fstype,omitempty"`
+	Total       uint64  `json:"total"`
+	Used        uint64  `json:"used"`
+	Free        uint64  `json:"free"`
+	UsedPercent float64 `json:"used_percent"`
+}
+
+// TranscodeDirUsage is how much space transcoding output takes up
+type TranscodeDirUsage struct {
+	Path         string    `json:"path"`
+	Bytes        int64     `json:"bytes"`
+	Files        int       `json:"files"`
+	Sessions     int       `json:"sessions"`
+	LimitBytes   int64     `json:"limit_bytes,omitempty"`
+	LimitPercent float64   `json:"limit_percent,omitempty"`
+	ScannedAt    time.Time `json:"scanned_at"`
+}
+
+// ResourceAlert reports a resource over its configured threshold
+type ResourceAlert struct {
+	Resource  string  `json:"resource"`
+	Level     string  `json:"level"` // "warning" or "critical"
+	Message   string  `json:"message"`
+	Value     float64 `json:"value"`
+	Threshold float64 `json:"threshold"`
+}
+
+// GetResources returns CPU, memory, disk and transcode directory usage
+func (h *SystemHandler) GetResources(c *gin.Context) {
+	c.JSON(http.StatusOK, h.collect(c.Request.Context()))
+}
+
+// GetResourceAlerts reports resources over their thresholds. It responds with
+// 503 when any alert is critical so it can back external health checks.
+func (h *SystemHandler) GetResourceAlerts(c *gin.Context) {
+	resources := h.collect(c.Request.Context())
+
+	status := "healthy"
+	code := http.StatusOK
+	for _, alert := range resources.Alerts {
+		if alert.Level == "critical" {
+			status = "critical"
+			code = http.StatusServiceUnavailable
+			break
+		}
+		status = "warning"
+	}
+
+	c.JSON(code, gin.H{
+		"status":    status,
+		"alerts":    resources.Alerts,
+		"timestamp": resources.Timestamp,
+	})
+}
+
+// collect gathers a resource snapshot. Metrics that can't be read on this
+// platform are left at zero rather than failing the request.
+func (h *SystemHandler) collect(ctx context.Context) *SystemResources {
+	ctx, cancel := context.WithTimeout(ctx, resourceQueryTimeout)
+	defer cancel()
+
+	cfg := config.Get()
+	resources := &SystemResources{
+		Timestamp: time.Now(),
+		Host:      h.hostResources(ctx),
+		Process:   h.processResources(ctx),
+		Container: containerLimits(),
+		Volumes:   volumeUsage(ctx, cfg),
+		Alerts:    []ResourceAlert{},
+	}
+	if cfg.Transcoding.DataDir != "" {
+		resources.Transcode = h.transcodeDirUsage(cfg.Transcoding.DataDir, cfg.Transcoding.MaxDiskUsageGB)
+	}
+	resources.Alerts = resourceAlerts(resources, cfg.Performance)
+	return resources
+}
+
+func (h *SystemHandler) hostResources(ctx context.Context) HostResources {
+	host := HostResources{CPUCores: runtime.NumCPU()}
+
+	// An interval of 0 compares against the previous call, so this doesn't block
+	if percents, err := cpu.PercentWithContext(ctx, 0, false); err == nil && len(percents) > 0 {
+		host.CPUPercent = percents[0]
+	}
+	if avg, err := load.AvgWithContext(ctx); err == nil {
+		host.Load1, host.Load5, host.Load15 = avg.Load1, avg.Load5, avg.Load15
+	}
+	if vm, err := mem.VirtualMemoryWithContext(ctx); err == nil {
+		host.MemoryTotal = vm.Total
+		host.MemoryUsed = vm.Used
+		host.MemoryPercent = vm.UsedPercent
+	}
+	if swap, err := mem.SwapMemoryWithContext(ctx); err == nil {
+		host.SwapTotal = swap.Total
+		host.SwapUsed = swap.Used
+	}
+	return host
+}
+
+func (h *SystemHandler) processResources(ctx context.Context) ProcessResources {
+	var memStats runtime.MemStats
+	runtime.ReadMemStats(&memStats)
+
+	proc := ProcessResources{
+		PID:           os.Getpid(),
+		UptimeSeconds: int64(time.Since(h.startTime).Seconds()),
+		Goroutines:    runtime.NumGoroutine(),
+		HeapAlloc:     memStats.HeapAlloc,
+		HeapSys:       memStats.HeapSys,
+		NumGC:         memStats.NumGC,
+	}
+
+	h.processMu.Lock()
+	defer h.processMu.Unlock()
+	if h.process != nil {
+		if percent, err := h.process.PercentWithContext(ctx, 0); err == nil {
+			proc.CPUPercent = percent
+		}
+		if info, err := h.process.MemoryInfoWithContext(ctx); err == nil {
+			proc.MemoryRSS = info.RSS
+		}
+		if fds, err := h.process.NumFDsWithContext(ctx); err == nil {
+			proc.OpenFiles = int(fds)
+		}
+	}
+	return proc
+}
+
+// volumeUsage reports disk usage for each configured data directory. Paths on
+// the same mount are reported once, under the first label that uses it.
+func volumeUsage(ctx context.Context, cfg *config.Config) []VolumeUsage {
+	dirs := []struct{ label, path string }{
+		{"data", cfg.Database.DataDir},
+		{"assets", cfg.Assets.DataDir},
+		{"transcoding", cfg.Transcoding.DataDir},
+		{"temp", cfg.Transcoding.TempDirectory},
+		{"plugins", cfg.Plugins.PluginDir},
+	}
+
+	partitions, _ := disk.PartitionsWithContext(ctx, true)
+
+	volumes := []VolumeUsage{}
+	seen := make(map[string]bool)
+	for _, dir := range dirs {
+		if dir.path == "" {
+			continue
+		}
+		path, err := filepath.Abs(dir.path)
+		if err != nil {
+			continue
+		}
+		usage, err := disk.UsageWithContext(ctx, existingParent(path))
+		if err != nil {
+			continue
+		}
+
+		mount := mountpointFor(path, partitions)
+		key := mount.Mountpoint
+		if key == "" {
+			key = usage.Path
+		}
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		volumes = append(volumes, VolumeUsage{
+			Label:       dir.label,
+			Path:        path,
+			Mountpoint:  mount.Mountpoint,
+			Fstype:      firstNonEmpty(mount.Fstype, usage.Fstype),
+			Total:       usage.Total,
+			Used:        usage.Used,
+			Free:        usage.Free,
+			UsedPercent: usage.UsedPercent,
+		})
+	}
+	return volumes
+}
+
+// existingParent walks up from path to the nearest directory that exists
+func existingParent(path string) string {
+	for {
+		if _, err := os.Stat(path); err == nil {
+			return path
+		}
+		parent := filepath.Dir(path)
+		if parent == path {
+			return path
+		}
+		path = parent
+	}
+}
+
+// mountpointFor returns the partition with the longest mountpoint containing path
+func mountpointFor(path string, partitions []disk.PartitionStat) disk.PartitionStat {
+	var best disk.PartitionStat
+	for _, partition := range partitions {
+		mount := partition.Mountpoint
+		if mount == "" || len(mount) <= len(best.Mountpoint) {
+			continue
+		}
+		if path == mount || strings.HasPrefix(path, strings.TrimSuffix(mount, string(filepath.Separator))+string(filepath.Separator)) {
+			best = partition
+		}
+	}
+	return best
+}
+
+// transcodeDirUsage sums the size of transcoding output, caching the result for transcodeUsageTTL
+func (h *SystemHandler) transcodeDirUsage(dir string, maxGB int64) *TranscodeDirUsage {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if cached := h.transcodeUsage; cached != nil && cached.Path == dir && time.Since(cached.ScannedAt) < transcodeUsageTTL {
+		return cached
+	}
+
+	usage := &TranscodeDirUsage{Path: dir, ScannedAt: time.Now()}
+	if entries, err := os.ReadDir(dir); err == nil {
+		for _, entry := range entries {
+			if entry.IsDir() {
+				usage.Sessions++
+			}
+		}
+	}
+	filepath.WalkDir(dir, func(_ string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return nil
+		}
+		if info, err := d.Info(); err == nil {
+			usage.Bytes += info.Size()
+			usage.Files++
+		}
+		return nil
+	})
+
+	if maxGB > 0 {
+		usage.LimitBytes = maxGB * 1024 * 1024 * 1024
+		usage.LimitPercent = float64(usage.Bytes) / float64(usage.LimitBytes) * 100
+	}
+
+	h.transcodeUsage = usage
+	return usage
+}
+
+// resourceAlerts compares a snapshot against the performance thresholds.
+// Values at or above the threshold warn; 95% and above is critical.
+func resourceAlerts(resources *SystemResources, perf config.PerformanceConfig) []ResourceAlert {
+	alerts := []ResourceAlert{}
+	check := func(resource, message string, value, threshold float64) {
+		if threshold <= 0 || value < threshold {
+			return
+		}
+		level := "warning"
+		if value >= 95 {
+			level = "critical"
+		}
+		alerts = append(alerts, ResourceAlert{
+			Resource:  resource,
+			Level:     level,
+			Message:   message,
+			Value:     value,
+			Threshold: threshold,
+		})
+	}
+
+	check("cpu", "Host CPU usage is high", resources.Host.CPUPercent, perf.CPUThreshold)
+
+	memoryPercent := resources.Host.MemoryPercent
+	if resources.Container != nil && resources.Container.MemoryPercent > 0 {
+		memoryPercent = resources.Container.MemoryPercent
+	}
+	check("memory", "Memory usage is high", memoryPercent, perf.MemoryThreshold)
+
+	for _, volume := range resources.Volumes {
+		check("disk:"+volume.Label, "Volume for "+volume.Label+" ("+volume.Path+") is filling up", volume.UsedPercent, perf.DiskThreshold)
+	}
+
+	if resources.Transcode != nil && resources.Transcode.LimitBytes > 0 {
+		check("transcode", "Transcoding output is near its disk limit", resources.Transcode.LimitPercent, perf.DiskThreshold)
+	}
+	return alerts
+}
+
+// containerLimits detects a container runtime and reads its cgroup limits.
+// Returns nil when not running in a container.
+func containerLimits() *ContainerLimits {
+	runtimeName := detectContainerRuntime()
+	if runtimeName == "" {
+		return nil
+	}
+	limits := &ContainerLimits{Runtime: runtimeName}
+
+	// cgroup v2 exposes a unified hierarchy with memory.max
+	if limit, ok := readCgroupValue("/sys/fs/cgroup/memory.max"); ok {
+		limits.CgroupVersion = 2
+		limits.MemoryLimit = limit
+		limits.MemoryUsage, _ = readCgroupValue("/sys/fs/cgroup/memory.current")
+		if data, err := os.ReadFile("/sys/fs/cgroup/cpu.max"); err == nil {
+			fields := strings.Fields(string(data))
+			if len(fields) == 2 && fields[0] != "max" {
+				quota, _ := strconv.ParseFloat(fields[0], 64)
+				period, _ := strconv.ParseFloat(fields[1], 64)
+				if period > 0 {
+					limits.CPULimit = quota / period
+				}
+			}
+		}
+	} else if limit, ok := readCgroupValue("/sys/fs/cgroup/memory/memory.limit_in_bytes"); ok {
+		limits.CgroupVersion = 1
+		limits.MemoryLimit = limit
+		limits.MemoryUsage, _ = readCgroupValue("/sys/fs/cgroup/memory/memory.usage_in_bytes")
+		quota, quotaOK := readCgroupValue("/sys/fs/cgroup/cpu/cpu.cfs_quota_us")
+		period, periodOK := readCgroupValue("/sys/fs/cgroup/cpu/cpu.cfs_period_us")
+		if quotaOK && periodOK && quota > 0 && period > 0 {
+			limits.CPULimit = float64(quota) / float64(period)
+		}
+	}
+
+	if limits.MemoryLimit > 0 && limits.MemoryUsage > 0 {
+		limits.MemoryPercent = float64(limits.MemoryUsage) / float64(limits.MemoryLimit) * 100
+	}
+	return limits
+}
+
+// detectContainerRuntime returns the container runtime, or "" outside a container
+func detectContainerRuntime() string {
+	if _, err := os.Stat("/.dockerenv"); err == nil {
+		return "docker"
+	}
+	if _, err := os.Stat("/run/.containerenv"); err == nil {
+		return "podman"
+	}
+	if data, err := os.ReadFile("/proc/1/cgroup"); err == nil {
+		content := string(data)
+		switch {
+		case strings.Contains(content, "kubepods"):
+			return "kubernetes"
+		case strings.Contains(content, "docker"):
+			return "docker"
+		case strings.Contains(content, "containerd"):
+			return "containerd"
+		}
+	}
+	return ""
+}
+
+// readCgroupValue reads a numeric cgroup file. "max" and the v1 unlimited
+// sentinel read as 0 (no limit); missing files return false.
+func readCgroupValue(path string) (uint64, bool) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, false
+	}
+	value := strings.TrimSpace(string(data))
+	if value == "max" {
+		return 0, true
+	}
+	n, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || n < 0 || n >= 1<<62 {
+		return 0, true
+	}
+	return uint64(n), true
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+	return ""
 }
@@ -2,11 +2,17 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
+	"strconv"
+	"sync"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/mantonx/viewra/internal/auth"
 	"github.com/mantonx/viewra/internal/database"
 	"github.com/mantonx/viewra/internal/events"
+	"gorm.io/gorm"
 )
 
 // UsersHandler handles user-related API endpoints
@@ -21,12 +27,43 @@ func NewUsersHandler(eventBus events.EventBus) *UsersHandler {
 	}
 }
 
+// setupMu serializes first admin creation, so concurrent setup requests
+// can't both find no admin and each create one
+var setupMu sync.Mutex
+
+// errAdminExists refuses setup on a server that already has an admin
+var errAdminExists = errors.New("server already has an admin")
+
+// createUserRequest describes a new account
+type createUserRequest struct {
+	Username          string   `json:"username" binding:"required"`
+	Email             string   `json:"email" binding:"required"`
+	Password          string   `json:"password" binding:"required"`
+	Role              string   `json:"role" binding:"omitempty,oneof=admin user"`
+	RestrictLibraries bool     `json:"restrict_libraries"`
+	LibraryIDs        []uint32 `json:"library_ids"` // Libraries a restricted user may see
+}
+
+// updateUserRequest changes an account; fields left out are kept
+type updateUserRequest struct {
+	Email             *string `json:"email"`
+	Password          *string `json:"password"`
+	Role              *string `json:"role" binding:"omitempty,oneof=admin user"`
+	RestrictLibraries *bool   `json:"restrict_libraries"`
+}
+
+// libraryAccessRequest sets the libraries a user may see
+type libraryAccessRequest struct {
+	RestrictLibraries bool     `json:"restrict_libraries"`
+	LibraryIDs        []uint32 `json:"library_ids"`
+}
+
 // GetUsers retrieves all users from the database
 func (h *UsersHandler) GetUsers(c *gin.Context) {
 	var users []database.User
 	db := database.GetDB()
 
-	result := db.Find(&users)
+	result := db.Order("id").Find(&users)
 	if result.Error != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error":   "Failed to retrieve users",
@@ -43,28 +80,128 @@ func (h *UsersHandler) GetUsers(c *gin.Context) {
 
 // CreateUser creates a new user account
 func (h *UsersHandler) CreateUser(c *gin.Context) {
-	var user database.User
+	var req createUserRequest
 
 	// Bind and validate JSON input
-	if err := c.ShouldBindJSON(&user); err != nil {
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request format",
+			"details": err.Error(),
+		})
+		return
+	}
+	if req.Role == "" {
+		req.Role = database.UserRoleUser
+	}
+
+	user, ok := h.createUser(c, database.GetDB(), req)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"user":    user,
+		"message": "User created successfully",
+	})
+}
+
+// SetupAdmin creates the first admin account, signing them in. It is only
+// allowed while no admin exists, so a new server can be claimed.
+func (h *UsersHandler) SetupAdmin(c *gin.Context) {
+	var req createUserRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{
 			"error":   "Invalid request format",
 			"details": err.Error(),
 		})
 		return
 	}
+	req.Role = database.UserRoleAdmin
+	req.RestrictLibraries = false
 
-	// Create user in database
+	setupMu.Lock()
+	defer setupMu.Unlock()
+
+	// The check and the insert share a transaction, so a failed insert leaves
+	// the server unclaimed
 	db := database.GetDB()
-	result := db.Create(&user)
-	if result.Error != nil {
+	var user *database.User
+	created := false
+	err := db.Transaction(func(tx *gorm.DB) error {
+		var admins int64
+		if err := tx.Model(&database.User{}).Where("role = ?", database.UserRoleAdmin).Count(&admins).Error; err != nil {
+			return err
+		}
+		if admins > 0 {
+			return errAdminExists
+		}
+		user, created = h.createUser(c, tx, req)
+		if !created {
+			return errors.New("admin not created")
+		}
+		return nil
+	})
+	if errors.Is(err, errAdminExists) {
+		c.JSON(http.StatusConflict, gin.H{"error": "Server already has an admin"})
+		return
+	}
+	if err != nil {
+		if !created && c.Writer.Written() {
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{
-			"error":   "Failed to create user",
-			"details": result.Error.Error(),
+			"error":   "Failed to create admin",
+			"details": err.Error(),
 		})
 		return
 	}
 
+	response := gin.H{
+		"user":    user,
+		"message": "Admin created successfully",
+	}
+	if auth.Enabled() {
+		grant, err := auth.StartSession(db, user, c.Request.UserAgent(), c.ClientIP())
+		if err != nil {
+			response["login_error"] = err.Error()
+		} else {
+			response["token"] = grant.Token
+			response["expires_at"] = grant.ExpiresAt
+		}
+	}
+	c.JSON(http.StatusCreated, response)
+}
+
+// createUser stores a new account with a hashed password and its library
+// grants, writing an error response and returning false on failure
+func (h *UsersHandler) createUser(c *gin.Context, db *gorm.DB, req createUserRequest) (*database.User, bool) {
+	hash, err := auth.HashPassword(req.Password)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return nil, false
+	}
+
+	user := database.User{
+		Username:          req.Username,
+		Email:             req.Email,
+		Password:          hash,
+		Role:              req.Role,
+		RestrictLibraries: req.RestrictLibraries,
+	}
+	err = db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&user).Error; err != nil {
+			return err
+		}
+		return auth.SetGrantedLibraries(tx, user.ID, req.LibraryIDs)
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to create user",
+			"details": err.Error(),
+		})
+		return nil, false
+	}
+
 	// Publish user created event
 	if h.eventBus != nil {
 		userEvent := events.NewSystemEvent(
@@ -75,24 +212,256 @@ func (h *UsersHandler) CreateUser(c *gin.Context) {
 		userEvent.Data = map[string]interface{}{
 			"userId":   user.ID,
 			"username": user.Username,
+			"role":     user.Role,
 		}
 		h.eventBus.PublishAsync(userEvent)
 	}
+	return &user, true
+}
 
-	c.JSON(http.StatusCreated, gin.H{
-		"user":    user,
-		"message": "User created successfully",
+// UpdateUser changes a user's email, password, role or library restriction
+func (h *UsersHandler) UpdateUser(c *gin.Context) {
+	user, ok := loadUserParam(c)
+	if !ok {
+		return
+	}
+
+	var req updateUserRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request format",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	db := database.GetDB()
+	updates := map[string]interface{}{}
+	if req.Email != nil {
+		updates["email"] = *req.Email
+	}
+	if req.Password != nil {
+		hash, err := auth.HashPassword(*req.Password)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		updates["password"] = hash
+	}
+	if req.Role != nil && *req.Role != user.Role {
+		if user.IsAdmin() && !otherAdminExists(c, db, user.ID) {
+			return
+		}
+		updates["role"] = *req.Role
+	}
+	if req.RestrictLibraries != nil {
+		updates["restrict_libraries"] = *req.RestrictLibraries
+	}
+
+	if len(updates) > 0 {
+		if err := db.Model(user).Updates(updates).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error":   "Failed to update user",
+				"details": err.Error(),
+			})
+			return
+		}
+	}
+	if req.Password != nil {
+		// Sign out everywhere the old password was used
+		if err := auth.EndUserSessions(db, user.ID); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+	}
+	if err := db.First(user, user.ID).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"user": user})
+}
+
+// ChangePassword changes a user's password. Users other than admins must
+// give their current password.
+func (h *UsersHandler) ChangePassword(c *gin.Context) {
+	user, ok := loadUserParam(c)
+	if !ok {
+		return
+	}
+
+	var req struct {
+		CurrentPassword string `json:"current_password"`
+		NewPassword     string `json:"new_password" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request format",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	if caller, signedIn := auth.CurrentUser(c); !signedIn || !caller.IsAdmin() {
+		if !auth.CheckPassword(user, req.CurrentPassword) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Current password is incorrect"})
+			return
+		}
+	}
+
+	hash, err := auth.HashPassword(req.NewPassword)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	db := database.GetDB()
+	if err := db.Model(user).Update("password", hash).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to change password",
+			"details": err.Error(),
+		})
+		return
+	}
+	if err := auth.EndUserSessions(db, user.ID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Password changed; sign in again"})
+}
+
+// DeleteUser removes a user along with their sessions, library grants and
+// parental controls
+func (h *UsersHandler) DeleteUser(c *gin.Context) {
+	user, ok := loadUserParam(c)
+	if !ok {
+		return
+	}
+
+	db := database.GetDB()
+	if user.IsAdmin() && !otherAdminExists(c, db, user.ID) {
+		return
+	}
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		for _, model := range []interface{}{&database.UserLibraryAccess{}, &database.UserParentalControl{}} {
+			if err := tx.Where("user_id = ?", user.ID).Delete(model).Error; err != nil {
+				return err
+			}
+		}
+		if err := auth.EndUserSessions(tx, user.ID); err != nil {
+			return err
+		}
+		return tx.Delete(user).Error
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to delete user",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "User deleted"})
+}
+
+// GetUserLibraries returns whether a user is restricted to some libraries
+// and the libraries granted to them
+func (h *UsersHandler) GetUserLibraries(c *gin.Context) {
+	user, ok := loadUserParam(c)
+	if !ok {
+		return
+	}
+
+	libraryIDs, err := auth.GrantedLibraries(database.GetDB(), user.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"user_id":            user.ID,
+		"restrict_libraries": user.RestrictLibraries,
+		"library_ids":        libraryIDs,
 	})
 }
 
+// SetUserLibraries restricts a user to some libraries, or lifts the
+// restriction
+func (h *UsersHandler) SetUserLibraries(c *gin.Context) {
+	user, ok := loadUserParam(c)
+	if !ok {
+		return
+	}
+
+	var req libraryAccessRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request format",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	db := database.GetDB()
+	if len(req.LibraryIDs) > 0 {
+		var found int64
+		if err := db.Model(&database.MediaLibrary{}).Where("id IN ?", req.LibraryIDs).Count(&found).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		if int(found) != len(uniqueIDs(req.LibraryIDs)) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Unknown library ID"})
+			return
+		}
+	}
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(user).Update("restrict_libraries", req.RestrictLibraries).Error; err != nil {
+			return err
+		}
+		return auth.SetGrantedLibraries(tx, user.ID, req.LibraryIDs)
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to set library access",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"user_id":            user.ID,
+		"restrict_libraries": req.RestrictLibraries,
+		"library_ids":        uniqueIDs(req.LibraryIDs),
+	})
+}
+
+// GetCurrentUser returns the signed-in user and the libraries they may see
+func (h *UsersHandler) GetCurrentUser(c *gin.Context) {
+	user, ok := auth.CurrentUser(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Not signed in"})
+		return
+	}
+
+	response := gin.H{"user": user}
+	if auth.Restricted(user) {
+		libraryIDs, err := auth.GrantedLibraries(database.GetDB(), user.ID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		response["library_ids"] = libraryIDs
+	}
+	c.JSON(http.StatusOK, response)
+}
+
 // LoginUser handles user login and session creation
 func (h *UsersHandler) LoginUser(c *gin.Context) {
-	// User login logic would go here
-
-	// Example for handling login event
 	var loginRequest struct {
-		Username string `json:"username"`
-		Password string `json:"password"`
+		Username string `json:"username" binding:"required"`
+		Password string `json:"password" binding:"required"`
 	}
 
 	if err := c.ShouldBindJSON(&loginRequest); err != nil {
@@ -103,11 +472,18 @@ func (h *UsersHandler) LoginUser(c *gin.Context) {
 		return
 	}
 
-	// Authentication logic would go here
-	// ...
-
-	// For example purposes, user with ID 1
-	userID := uint(1)
+	grant, err := auth.Login(database.GetDB(), loginRequest.Username, loginRequest.Password, c.Request.UserAgent(), c.ClientIP())
+	if errors.Is(err, auth.ErrInvalidCredentials) {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Login failed",
+			"details": err.Error(),
+		})
+		return
+	}
 
 	// Publish login event
 	if h.eventBus != nil {
@@ -117,8 +493,8 @@ func (h *UsersHandler) LoginUser(c *gin.Context) {
 			"User logged in successfully",
 		)
 		loginEvent.Data = map[string]interface{}{
-			"userId":    userID,
-			"username":  loginRequest.Username,
+			"userId":    grant.User.ID,
+			"username":  grant.User.Username,
 			"ipAddress": c.ClientIP(),
 			"userAgent": c.GetHeader("User-Agent"),
 		}
@@ -126,18 +502,31 @@ func (h *UsersHandler) LoginUser(c *gin.Context) {
 	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"message": "Login successful",
-		"token":   "sample-token-would-go-here",
+		"message":    "Login successful",
+		"token":      grant.Token,
+		"expires_at": grant.ExpiresAt,
+		"user":       grant.User,
 	})
 }
 
-// LogoutUser handles user logout
+// LogoutUser ends the session the request's token belongs to
 func (h *UsersHandler) LogoutUser(c *gin.Context) {
-	// User logout logic would go here
+	db := database.GetDB()
+	user, signedIn := auth.CurrentUser(c)
+	session, _ := auth.CurrentSession(c)
+	if !signedIn {
+		var err error
+		user, session, err = auth.Authenticate(db, auth.RequestToken(c), time.Now())
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Not signed in"})
+			return
+		}
+	}
 
-	// For example purposes
-	userID := uint(1)
-	username := "example_user"
+	if err := auth.Logout(db, session.ID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
 
 	// Publish logout event
 	if h.eventBus != nil {
@@ -147,8 +536,8 @@ func (h *UsersHandler) LogoutUser(c *gin.Context) {
 			"User logged out successfully",
 		)
 		logoutEvent.Data = map[string]interface{}{
-			"userId":   userID,
-			"username": username,
+			"userId":   user.ID,
+			"username": user.Username,
 		}
 		h.eventBus.PublishAsync(logoutEvent)
 	}
@@ -158,6 +547,57 @@ func (h *UsersHandler) LogoutUser(c *gin.Context) {
 	})
 }
 
+// loadUserParam loads the user named by the id path parameter, writing an
+// error response and returning false when there is none
+func loadUserParam(c *gin.Context) (*database.User, bool) {
+	userID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return nil, false
+	}
+
+	var user database.User
+	if err := database.GetDB().First(&user, userID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+			return nil, false
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to load user",
+			"details": err.Error(),
+		})
+		return nil, false
+	}
+	return &user, true
+}
+
+// otherAdminExists checks that removing an admin leaves another one, so
+// the server can't be locked out, writing a 409 response when it wouldn't
+func otherAdminExists(c *gin.Context, db *gorm.DB, userID uint32) bool {
+	var admins int64
+	if err := db.Model(&database.User{}).Where("role = ? AND id <> ?", database.UserRoleAdmin, userID).Count(&admins).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return false
+	}
+	if admins == 0 {
+		c.JSON(http.StatusConflict, gin.H{"error": "The last admin can't be removed"})
+		return false
+	}
+	return true
+}
+
+func uniqueIDs(ids []uint32) []uint32 {
+	seen := make(map[uint32]bool, len(ids))
+	unique := make([]uint32, 0, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+	return unique
+}
+
 // Keep original function-based handlers for backward compatibility
 // These will delegate to the struct-based handlers
 
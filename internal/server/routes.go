@@ -29,6 +29,8 @@ func setupRoutesWithEventHandlers(r *gin.Engine, pluginMgr *pluginmodule.PluginM
 		plugins := api.Group("/plugins")
 		{
 			plugins.Any("/*path", handlers.HandlePluginRoute)
+			apiroutes.Register(plugins.BasePath()+"/capabilities", "GET", "Describe the features, media types, admin pages and routes each plugin offers.")
+			apiroutes.Register(plugins.BasePath()+"/:id/*path", "ANY", "Proxy a request to a route the plugin registers, with auth and rate limiting.")
 		}
 
 		// Core plugin management routes
@@ -90,6 +92,8 @@ func setupRoutesWithEventHandlers(r *gin.Engine, pluginMgr *pluginmodule.PluginM
 		setupMediaRoutesWithEvents(api, systemEventBus)
 		setupUserRoutesWithEvents(api, systemEventBus)
 		setupAdminRoutesWithEvents(api, systemEventBus)
+		setupFeedRoutes(api)
+		setupAnnouncementRoutes(api)
 
 		// Call setup functions
 		setupEventRoutes(api)
@@ -153,6 +157,16 @@ func setupHealthRoutes(api *gin.RouterGroup) {
 
 	api.GET("/connection-pool", handlers.HandleConnectionPoolStats)
 	apiroutes.Register(api.BasePath()+"/connection-pool", "GET", "Detailed database connection pool statistics and performance metrics.")
+
+	systemHandler := handlers.NewSystemHandler()
+	system := api.Group("/system")
+	{
+		system.GET("/resources", systemHandler.GetResources)
+		apiroutes.Register(system.BasePath()+"/resources", "GET", "Server CPU, memory, per-volume disk and transcode directory usage.")
+
+		system.GET("/resources/alerts", systemHandler.GetResourceAlerts)
+		apiroutes.Register(system.BasePath()+"/resources/alerts", "GET", "Resources over their configured thresholds; 503 when any is critical.")
+	}
 }
 
 // =============================================================================
@@ -209,16 +223,49 @@ func setupUserRoutesWithEvents(api *gin.RouterGroup, eventBus events.EventBus) {
 	users := api.Group("/users")
 	{
 		users.GET("/", usersHandler.GetUsers)
-		apiroutes.Register(users.BasePath()+"/", "GET", "List all users.")
+		apiroutes.Register(users.BasePath()+"/", "GET", "List all users (admin).")
 
 		users.POST("/", usersHandler.CreateUser)
-		apiroutes.Register(users.BasePath()+"/", "POST", "Create a new user.")
+		apiroutes.Register(users.BasePath()+"/", "POST", "Create a new user (admin).")
 
 		users.POST("/login", usersHandler.LoginUser)
 		apiroutes.Register(users.BasePath()+"/login", "POST", "Login a user.")
 
 		users.POST("/logout", usersHandler.LogoutUser)
 		apiroutes.Register(users.BasePath()+"/logout", "POST", "Logout a user.")
+
+		users.POST("/setup", usersHandler.SetupAdmin)
+		apiroutes.Register(users.BasePath()+"/setup", "POST", "Create the first admin account while the server has none.")
+
+		users.GET("/me", usersHandler.GetCurrentUser)
+		apiroutes.Register(users.BasePath()+"/me", "GET", "Get the signed-in user and the libraries they may see.")
+
+		users.PUT("/:id", usersHandler.UpdateUser)
+		apiroutes.Register(users.BasePath()+"/:id", "PUT", "Update a user's email, password, role or library restriction (admin).")
+
+		users.DELETE("/:id", usersHandler.DeleteUser)
+		apiroutes.Register(users.BasePath()+"/:id", "DELETE", "Delete a user (admin).")
+
+		users.PUT("/:id/password", usersHandler.ChangePassword)
+		apiroutes.Register(users.BasePath()+"/:id/password", "PUT", "Change a user's password.")
+
+		users.GET("/:id/libraries", usersHandler.GetUserLibraries)
+		apiroutes.Register(users.BasePath()+"/:id/libraries", "GET", "Get the libraries a user may see.")
+
+		users.PUT("/:id/libraries", usersHandler.SetUserLibraries)
+		apiroutes.Register(users.BasePath()+"/:id/libraries", "PUT", "Restrict a user to some libraries, or lift the restriction (admin).")
+
+		users.GET("/:id/parental", usersHandler.GetParentalControls)
+		apiroutes.Register(users.BasePath()+"/:id/parental", "GET", "Get a user's maximum content rating and PIN override state.")
+
+		users.PUT("/:id/parental", usersHandler.SetParentalControls)
+		apiroutes.Register(users.BasePath()+"/:id/parental", "PUT", "Set or remove a user's maximum content rating and override PIN (admin).")
+
+		users.POST("/:id/parental/unlock", usersHandler.UnlockParentalControls)
+		apiroutes.Register(users.BasePath()+"/:id/parental/unlock", "POST", "Lift a user's parental controls for an hour with their PIN.")
+
+		users.POST("/:id/parental/lock", usersHandler.LockParentalControls)
+		apiroutes.Register(users.BasePath()+"/:id/parental/lock", "POST", "End a parental control PIN override early.")
 	}
 }
 
@@ -243,6 +290,22 @@ func setupAdminRoutesWithEvents(api *gin.RouterGroup, eventBus events.EventBus)
 			apiroutes.Register(libraries.BasePath()+"/:id/stats", "GET", "Get statistics for a media library.")
 			libraries.GET("/:id/files", adminHandler.GetMediaFiles)
 			apiroutes.Register(libraries.BasePath()+"/:id/files", "GET", "List files in a media library.")
+			libraries.GET("/:id/enrichment-providers", adminHandler.GetLibraryEnrichmentProviders)
+			apiroutes.Register(libraries.BasePath()+"/:id/enrichment-providers", "GET", "List the enrichment providers used by a media library.")
+			libraries.PUT("/:id/enrichment-providers", adminHandler.SetLibraryEnrichmentProviders)
+			apiroutes.Register(libraries.BasePath()+"/:id/enrichment-providers", "PUT", "Select the enrichment providers used by a media library.")
+			libraries.GET("/:id/artwork", adminHandler.GetLibraryArtworkSettings)
+			apiroutes.Register(libraries.BasePath()+"/:id/artwork", "GET", "Get the artwork selection settings of a media library.")
+			libraries.PUT("/:id/artwork", adminHandler.SetLibraryArtworkSettings)
+			apiroutes.Register(libraries.BasePath()+"/:id/artwork", "PUT", "Update the artwork selection settings of a media library.")
+			libraries.GET("/:id/scan-schedule", adminHandler.GetLibraryScanSchedule)
+			apiroutes.Register(libraries.BasePath()+"/:id/scan-schedule", "GET", "Get the automatic scan schedule of a media library.")
+			libraries.PUT("/:id/scan-schedule", adminHandler.SetLibraryScanSchedule)
+			apiroutes.Register(libraries.BasePath()+"/:id/scan-schedule", "PUT", "Set when a media library is scanned automatically: cron or interval, on startup, and blackout windows.")
+			libraries.GET("/:id/naming-rules", adminHandler.GetLibraryNamingRules)
+			apiroutes.Register(libraries.BasePath()+"/:id/naming-rules", "GET", "List the rules rewriting a media library's file names before matching.")
+			libraries.PUT("/:id/naming-rules", adminHandler.SetLibraryNamingRules)
+			apiroutes.Register(libraries.BasePath()+"/:id/naming-rules", "PUT", "Replace the rules rewriting a media library's file names before matching.")
 		}
 
 		scanner := admin.Group("/scanner")
@@ -266,7 +329,7 @@ func setupAdminRoutesWithEvents(api *gin.RouterGroup, eventBus events.EventBus)
 			scanner.POST("/cleanup-orphaned", handlers.CleanupOrphanedJobs)
 			apiroutes.Register(scanner.BasePath()+"/cleanup-orphaned", "POST", "Cleanup orphaned scanner jobs.")
 			scanner.POST("/cleanup-orphaned-assets", handlers.CleanupOrphanedAssets)
-			apiroutes.Register(scanner.BasePath()+"/cleanup-orphaned-assets", "POST", "Cleanup orphaned assets that reference non-existent media files.")
+			apiroutes.Register(scanner.BasePath()+"/cleanup-orphaned-assets", "POST", "Delete assets whose media has been gone longer than the retention window (dry_run=true to only report).")
 			scanner.POST("/cleanup-orphaned-files", handlers.CleanupOrphanedFiles)
 			apiroutes.Register(scanner.BasePath()+"/cleanup-orphaned-files", "POST", "Cleanup orphaned asset files from filesystem that have no database records.")
 			scanner.DELETE("/jobs/:id", handlers.DeleteScanJob)
@@ -309,6 +372,14 @@ func setupAdminRoutesWithEvents(api *gin.RouterGroup, eventBus events.EventBus)
 			apiroutes.Register(scanner.BasePath()+"/health/:id", "GET", "Monitor scan health and detect potential issues.")
 		}
 
+		dashboard := admin.Group("/dashboard")
+		{
+			dashboard.GET("/widgets", handlers.GetDashboardWidgets)
+			apiroutes.Register(dashboard.BasePath()+"/widgets", "GET", "List the dashboard widgets running plugins contribute, with their current data.")
+			dashboard.GET("/provider-metrics", handlers.GetProviderMetrics)
+			apiroutes.Register(dashboard.BasePath()+"/provider-metrics", "GET", "List the latency and status codes of running plugins' requests to external providers.")
+		}
+
 		pluginsGR := admin.Group("/plugins")
 		{
 			pluginsGR.GET("/", handlers.GetPlugins)
@@ -360,6 +431,73 @@ func setupEventRoutes(api *gin.RouterGroup) {
 	// apiroutes.Register(api.BasePath()+"/events/summary", "GET", "Get a summary of event activity.")
 }
 
+// =============================================================================
+// FEED ROUTES
+// =============================================================================
+
+// setupFeedRoutes configures token-authenticated subscription feeds
+func setupFeedRoutes(api *gin.RouterGroup) {
+	feedsHandler := handlers.NewFeedsHandler()
+
+	users := api.Group("/users/:id/feed-tokens")
+	{
+		users.GET("", feedsHandler.ListFeedTokens)
+		apiroutes.Register(users.BasePath(), "GET", "List a user's feed tokens.")
+
+		users.POST("", feedsHandler.CreateFeedToken)
+		apiroutes.Register(users.BasePath(), "POST", "Create a feed token for calendar or recently-added feeds.")
+
+		users.DELETE("/:token", feedsHandler.RevokeFeedToken)
+		apiroutes.Register(users.BasePath()+"/:token", "DELETE", "Revoke a feed token.")
+	}
+
+	feeds := api.Group("/feeds")
+	{
+		feeds.GET("/calendar/:token", feedsHandler.GetCalendarFeed)
+		apiroutes.Register(feeds.BasePath()+"/calendar/:token", "GET", "iCal feed of upcoming episodes and releases.")
+
+		feeds.GET("/recent/:token", feedsHandler.GetRecentFeed)
+		apiroutes.Register(feeds.BasePath()+"/recent/:token", "GET", "RSS (.rss) or JSON Feed (.json) of recently added media.")
+
+		feeds.GET("/libraries/:libraryId/recent/:token", feedsHandler.GetLibraryRecentFeed)
+		apiroutes.Register(feeds.BasePath()+"/libraries/:libraryId/recent/:token", "GET", "Recently added media feed for one library.")
+	}
+}
+
+// =============================================================================
+// ANNOUNCEMENT ROUTES
+// =============================================================================
+
+// setupAnnouncementRoutes configures server-wide announcements and their
+// admin endpoints
+func setupAnnouncementRoutes(api *gin.RouterGroup) {
+	announcementsHandler := handlers.NewAnnouncementsHandler()
+
+	announcements := api.Group("/announcements")
+	{
+		announcements.GET("", announcementsHandler.GetAnnouncements)
+		apiroutes.Register(announcements.BasePath(), "GET", "List the announcements showing now that the user hasn't dismissed.")
+
+		announcements.POST("/:id/dismiss", announcementsHandler.DismissAnnouncement)
+		apiroutes.Register(announcements.BasePath()+"/:id/dismiss", "POST", "Dismiss an announcement for the user.")
+	}
+
+	admin := api.Group("/admin/announcements")
+	{
+		admin.GET("", announcementsHandler.ListAllAnnouncements)
+		apiroutes.Register(admin.BasePath(), "GET", "List all announcements, including scheduled and expired ones.")
+
+		admin.POST("", announcementsHandler.CreateAnnouncement)
+		apiroutes.Register(admin.BasePath(), "POST", "Create an announcement.")
+
+		admin.PUT("/:id", announcementsHandler.UpdateAnnouncement)
+		apiroutes.Register(admin.BasePath()+"/:id", "PUT", "Update an announcement.")
+
+		admin.DELETE("/:id", announcementsHandler.DeleteAnnouncement)
+		apiroutes.Register(admin.BasePath()+"/:id", "DELETE", "Delete an announcement.")
+	}
+}
+
 // =============================================================================
 // SCAN ROUTES
 // =============================================================================
@@ -15,22 +15,29 @@ import (
 	"github.com/mantonx/viewra/internal/database"
 	"github.com/mantonx/viewra/internal/events"
 	"github.com/mantonx/viewra/internal/logger"
+	"github.com/mantonx/viewra/internal/middleware"
 	"github.com/mantonx/viewra/internal/modules/enrichmentmodule"
 	"github.com/mantonx/viewra/internal/modules/mediamodule"
 	"github.com/mantonx/viewra/internal/modules/modulemanager"
 	"github.com/mantonx/viewra/internal/modules/playbackmodule"
 	"github.com/mantonx/viewra/internal/modules/pluginmodule"
 	"github.com/mantonx/viewra/internal/modules/scannermodule"
+	"github.com/mantonx/viewra/internal/modules/subtitlemodule"
 	"github.com/mantonx/viewra/internal/server/handlers"
 
 	// Import all modules to trigger their registration
 	_ "github.com/mantonx/viewra/internal/modules/assetmodule"
 	_ "github.com/mantonx/viewra/internal/modules/databasemodule"
+	_ "github.com/mantonx/viewra/internal/modules/diskhealthmodule"
+	_ "github.com/mantonx/viewra/internal/modules/downloadmodule"
 	_ "github.com/mantonx/viewra/internal/modules/enrichmentmodule"
 	_ "github.com/mantonx/viewra/internal/modules/eventsmodule"
 	_ "github.com/mantonx/viewra/internal/modules/mediamodule"
+	_ "github.com/mantonx/viewra/internal/modules/organizermodule"
+	_ "github.com/mantonx/viewra/internal/modules/peoplemodule"
 	_ "github.com/mantonx/viewra/internal/modules/playbackmodule"
 	_ "github.com/mantonx/viewra/internal/modules/scannermodule"
+	_ "github.com/mantonx/viewra/internal/modules/searchmodule"
 
 	// Bootstrap core plugins
 	_ "github.com/mantonx/viewra/internal/plugins/bootstrap"
@@ -66,6 +73,12 @@ func SetupRouter() *gin.Engine {
 		c.Next()
 	})
 
+	// Authentication and per-library access control, when enabled
+	if security := config.Get().Security; security.EnableAuthentication && security.JWTSecret == "" {
+		logger.Warn("Authentication is enabled but no JWT secret is configured; logins will fail")
+	}
+	r.Use(middleware.RequireAuth(handlers.IsPublicPluginRoute))
+
 	// Initialize event bus system
 	if err := initializeEventBus(); err != nil {
 		log.Printf("Failed to initialize event bus: %v", err)
@@ -291,6 +304,13 @@ func connectPluginManagerToModules() error {
 						log.Printf("✅ Connected plugin module to playback module via adapter")
 					}
 				}
+				// Let plugins add to watch history through the host services
+				if enrichmentModule != nil {
+					if manager := playbackMod.GetManager(); manager != nil {
+						enrichmentModule.SetWatchHistoryService(manager.WatchHistoryService())
+						log.Printf("✅ Connected playback watch history to plugin host services")
+					}
+				}
 			}
 		}
 
@@ -312,6 +332,24 @@ func connectPluginManagerToModules() error {
 				}
 			}
 		}
+
+		// Connect subtitle module to scanner
+		if module.ID() == subtitlemodule.ModuleID {
+			if subtitleModule, ok := module.(*subtitlemodule.Module); ok {
+				for _, scannerMod := range modules {
+					if scannerMod.ID() == "system.scanner" {
+						if scannerModule, ok := scannerMod.(*scannermodule.Module); ok {
+							manager := scannerModule.GetScannerManager()
+							if manager != nil {
+								manager.RegisterScanHook(subtitleModule)
+								log.Printf("✅ Registered subtitle module as scanner hook")
+							}
+						}
+						break
+					}
+				}
+			}
+		}
 	}
 
 	// Plugin module connectivity for playback is now handled via service registry
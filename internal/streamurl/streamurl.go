@@ -0,0 +1,159 @@
+// Package streamurl signs the URLs transcode manifests and segments are
+// served from. A signed token takes the place of the session ID in the
+// path, so URLs resolved relative to a manifest carry it too, and it names
+// the session, the user it was issued to and when it expires.
+package streamurl
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mantonx/viewra/internal/config"
+)
+
+// defaultTTL applies when no positive TTL is configured
+const defaultTTL = 6 * time.Hour
+
+// Reasons a token is refused
+var (
+	ErrMalformed = errors.New("malformed stream token")
+	ErrSignature = errors.New("invalid stream token signature")
+	ErrExpired   = errors.New("stream token has expired")
+)
+
+var (
+	processKeyMu sync.Mutex
+	processKey   []byte
+)
+
+// Claims are what a verified token grants
+type Claims struct {
+	SessionID string
+	UserID    uint32
+	ExpiresAt time.Time
+}
+
+// Enabled reports whether stream URLs are signed and unsigned ones refused
+func Enabled() bool {
+	return config.Get().Transcoding.SignedURLs
+}
+
+// Token returns the path segment standing in for a session's ID, valid for
+// the configured TTL. It is the bare session ID when signing is disabled, or
+// when no signing key is available, which signed stream routes refuse.
+func Token(sessionID string, userID uint32) string {
+	cfg := config.Get().Transcoding
+	if !cfg.SignedURLs {
+		return sessionID
+	}
+	ttl := cfg.SignedURLTTL
+	if ttl <= 0 {
+		ttl = defaultTTL
+	}
+	token, err := sign(sessionID, userID, time.Now().Add(ttl))
+	if err != nil {
+		log.Printf("ERROR: Failed to sign stream URL of session %s: %v", sessionID, err)
+		return sessionID
+	}
+	return token
+}
+
+// Manifest returns the URL of a session's manifest: the master playlist
+// master.m3u8 for HLS, manifest.mpd otherwise
+func Manifest(sessionID string, userID uint32, container string) string {
+	if container == "hls" {
+		return File(sessionID, userID, "master.m3u8")
+	}
+	return File(sessionID, userID, "manifest.mpd")
+}
+
+// File returns the URL of a file of a session's output
+func File(sessionID string, userID uint32, name string) string {
+	return fmt.Sprintf("/api/playback/stream/%s/%s", Token(sessionID, userID), name)
+}
+
+// IsToken reports whether a path segment is a signed token rather than a
+// bare session ID
+func IsToken(segment string) bool {
+	return strings.Count(segment, ".") == 3
+}
+
+// Verify checks a token's signature and expiry and returns its claims
+func Verify(token string, now time.Time) (*Claims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 4 || parts[0] == "" {
+		return nil, ErrMalformed
+	}
+	userID, err := strconv.ParseUint(parts[1], 10, 32)
+	if err != nil {
+		return nil, ErrMalformed
+	}
+	expires, err := strconv.ParseInt(parts[2], 10, 64)
+	if err != nil {
+		return nil, ErrMalformed
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[3])
+	if err != nil {
+		return nil, ErrMalformed
+	}
+
+	expected, err := mac(parts[0] + "." + parts[1] + "." + parts[2])
+	if err != nil {
+		return nil, err
+	}
+	if !hmac.Equal(signature, expected) {
+		return nil, ErrSignature
+	}
+	claims := &Claims{SessionID: parts[0], UserID: uint32(userID), ExpiresAt: time.Unix(expires, 0)}
+	if !now.Before(claims.ExpiresAt) {
+		return nil, ErrExpired
+	}
+	return claims, nil
+}
+
+func sign(sessionID string, userID uint32, expires time.Time) (string, error) {
+	payload := fmt.Sprintf("%s.%d.%d", sessionID, userID, expires.Unix())
+	signature, err := mac(payload)
+	if err != nil {
+		return "", err
+	}
+	return payload + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
+
+func mac(payload string) ([]byte, error) {
+	k, err := key()
+	if err != nil {
+		return nil, err
+	}
+	h := hmac.New(sha256.New, k)
+	h.Write([]byte(payload))
+	return h.Sum(nil), nil
+}
+
+// key is the configured signing key, or a random one kept for the life of
+// the process, which invalidates outstanding URLs on restart. A key that
+// fails to generate is tried again on the next call.
+func key() ([]byte, error) {
+	if configured := config.Get().Transcoding.URLSigningKey; configured != "" {
+		return []byte(configured), nil
+	}
+	processKeyMu.Lock()
+	defer processKeyMu.Unlock()
+	if processKey == nil {
+		generated := make([]byte, 32)
+		if _, err := rand.Read(generated); err != nil {
+			return nil, fmt.Errorf("failed to generate stream URL signing key: %w", err)
+		}
+		processKey = generated
+	}
+	return processKey, nil
+}
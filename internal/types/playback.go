@@ -10,6 +10,8 @@ type DeviceProfile struct {
 	SupportsAV1     bool     `json:"supports_av1"`
 	SupportsHDR     bool     `json:"supports_hdr"`
 	ClientIP        string   `json:"client_ip"`
+	UserID          uint32   `json:"user_id,omitempty"`   // Used to look up track language preferences
+	DeviceID        string   `json:"device_id,omitempty"` // Registered device whose stored capabilities apply
 }
 
 // PlaybackDecision represents the decision made by the planner
@@ -17,9 +19,21 @@ type PlaybackDecision struct {
 	ShouldTranscode bool                `json:"should_transcode"`
 	DirectPlayURL   string              `json:"direct_play_url,omitempty"`
 	TranscodeParams interface{}         `json:"transcode_params,omitempty"` // Using interface{} to avoid circular imports
+	Tracks          *TrackSelection     `json:"tracks,omitempty"`
 	Reason          string              `json:"reason"`
 }
 
+// TrackSelection describes the audio and subtitle tracks chosen for playback.
+// Indexes count streams of the same type, matching FFmpeg's 0:a:N / 0:s:N specifiers.
+type TrackSelection struct {
+	AudioIndex       int    `json:"audio_index"`
+	AudioLanguage    string `json:"audio_language,omitempty"`
+	SubtitleIndex    int    `json:"subtitle_index"` // -1 when no subtitle is shown
+	SubtitleLanguage string `json:"subtitle_language,omitempty"`
+	SubtitleForced   bool   `json:"subtitle_forced,omitempty"`
+	Reason           string `json:"reason"`
+}
+
 // TranscodingStats represents overall transcoding statistics
 type TranscodingStats struct {
 	ActiveSessions    int                          `json:"active_sessions"`
@@ -0,0 +1,167 @@
+package utils
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// Audio quality badges shown next to tracks
+const (
+	AudioBadgeHiRes    = "hi-res"   // Lossless above CD quality
+	AudioBadgeLossless = "lossless" // Lossless at CD quality or below
+	AudioBadgeLossy    = "lossy"
+)
+
+// losslessAudioCodecs lists the ffprobe codec names that are lossless
+var losslessAudioCodecs = map[string]bool{
+	"flac":    true,
+	"alac":    true,
+	"ape":     true,
+	"wavpack": true,
+	"tta":     true,
+	"mlp":     true,
+	"truehd":  true,
+	"shorten": true,
+}
+
+// sampleFormatBits maps ffprobe sample formats to their bit depth
+var sampleFormatBits = map[string]int{
+	"u8": 8, "u8p": 8,
+	"s16": 16, "s16p": 16,
+	"s32": 32, "s32p": 32,
+	"flt": 32, "fltp": 32,
+	"s64": 64, "s64p": 64,
+	"dbl": 64, "dblp": 64,
+}
+
+// IsLosslessAudioCodec reports whether an ffprobe codec name is lossless.
+// All PCM variants are lossless.
+func IsLosslessAudioCodec(codec string) bool {
+	codec = strings.ToLower(codec)
+	return losslessAudioCodecs[codec] || strings.HasPrefix(codec, "pcm_")
+}
+
+// AudioBitDepth returns the bit depth of a lossless audio stream from its
+// ffprobe fields, or 0 for lossy codecs where bit depth has no meaning.
+// bits_per_raw_sample is the most precise: 24-bit FLAC decodes to s32.
+func AudioBitDepth(codec, bitsPerRawSample string, bitsPerSample int, sampleFormat string) int {
+	if !IsLosslessAudioCodec(codec) {
+		return 0
+	}
+	if bits, err := strconv.Atoi(bitsPerRawSample); err == nil && bits > 0 {
+		return bits
+	}
+	if bitsPerSample > 0 {
+		return bitsPerSample
+	}
+	return sampleFormatBits[strings.ToLower(sampleFormat)]
+}
+
+// AudioQualityBadge classifies a file as hi-res, lossless or lossy. Files
+// without an audio codec get no badge.
+func AudioQualityBadge(codec string, sampleRate, bitDepth int) string {
+	if codec == "" {
+		return ""
+	}
+	if !IsLosslessAudioCodec(codec) {
+		return AudioBadgeLossy
+	}
+	if sampleRate > 48000 || bitDepth > 16 {
+		return AudioBadgeHiRes
+	}
+	return AudioBadgeLossless
+}
+
+// AudioQualityFilter narrows media file queries by audio quality. Browse
+// endpoints build it from query parameters.
+type AudioQualityFilter struct {
+	Lossless      *bool
+	HiRes         bool
+	Codecs        []string
+	MinSampleRate int
+	MinBitDepth   int
+	MinBitrate    int // kbps
+}
+
+// ParseAudioQualityFilter reads lossless, hi_res, codec (comma separated),
+// min_sample_rate, min_bit_depth and min_bitrate from a query getter such as
+// gin's Context.Query
+func ParseAudioQualityFilter(query func(string) string) (AudioQualityFilter, error) {
+	var filter AudioQualityFilter
+
+	if value := query("lossless"); value != "" {
+		lossless, err := strconv.ParseBool(value)
+		if err != nil {
+			return filter, fmt.Errorf("invalid lossless value: %s", value)
+		}
+		filter.Lossless = &lossless
+	}
+	if value := query("hi_res"); value != "" {
+		hiRes, err := strconv.ParseBool(value)
+		if err != nil {
+			return filter, fmt.Errorf("invalid hi_res value: %s", value)
+		}
+		filter.HiRes = hiRes
+	}
+	if value := query("codec"); value != "" {
+		for _, codec := range strings.Split(value, ",") {
+			if codec = strings.ToLower(strings.TrimSpace(codec)); codec != "" {
+				filter.Codecs = append(filter.Codecs, codec)
+			}
+		}
+	}
+
+	numbers := map[string]*int{
+		"min_sample_rate": &filter.MinSampleRate,
+		"min_bit_depth":   &filter.MinBitDepth,
+		"min_bitrate":     &filter.MinBitrate,
+	}
+	for name, target := range numbers {
+		value := query(name)
+		if value == "" {
+			continue
+		}
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 0 {
+			return filter, fmt.Errorf("invalid %s value: %s", name, value)
+		}
+		*target = n
+	}
+	return filter, nil
+}
+
+// IsEmpty reports whether the filter has no conditions
+func (f AudioQualityFilter) IsEmpty() bool {
+	return f.Lossless == nil && !f.HiRes && len(f.Codecs) == 0 &&
+		f.MinSampleRate == 0 && f.MinBitDepth == 0 && f.MinBitrate == 0
+}
+
+// Apply adds the filter's conditions to a query on media_files. table is the
+// name or alias the media_files columns are qualified with.
+func (f AudioQualityFilter) Apply(query *gorm.DB, table string) *gorm.DB {
+	column := func(name string) string { return table + "." + name }
+
+	if f.Lossless != nil {
+		query = query.Where(column("audio_lossless")+" = ?", *f.Lossless)
+	}
+	if f.HiRes {
+		query = query.Where(column("audio_lossless")+" = ? AND ("+column("sample_rate")+" > ? OR "+column("audio_bit_depth")+" > ?)",
+			true, 48000, 16)
+	}
+	if len(f.Codecs) > 0 {
+		query = query.Where("LOWER("+column("audio_codec")+") IN ?", f.Codecs)
+	}
+	if f.MinSampleRate > 0 {
+		query = query.Where(column("sample_rate")+" >= ?", f.MinSampleRate)
+	}
+	if f.MinBitDepth > 0 {
+		query = query.Where(column("audio_bit_depth")+" >= ?", f.MinBitDepth)
+	}
+	if f.MinBitrate > 0 {
+		query = query.Where(column("bitrate_kbps")+" >= ?", f.MinBitrate)
+	}
+	return query
+}
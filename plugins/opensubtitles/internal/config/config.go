@@ -0,0 +1,131 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// How subtitles for the deaf and hard of hearing (SDH) are treated
+const (
+	HearingImpairedInclude = "include" // Consider SDH and regular subtitles
+	HearingImpairedExclude = "exclude" // Only regular subtitles
+	HearingImpairedOnly    = "only"    // Only SDH subtitles
+)
+
+// Config represents the complete plugin configuration structure
+// This mirrors the CUE schema defined in plugin.cue
+type Config struct {
+	API       APIConfig       `json:"api"`
+	Subtitles SubtitlesConfig `json:"subtitles"`
+	Features  FeaturesConfig  `json:"features"`
+}
+
+// APIConfig contains OpenSubtitles REST API settings
+type APIConfig struct {
+	Key        string  `json:"key"`         // OpenSubtitles consumer API key (sensitive)
+	Username   string  `json:"username"`    // Account login, raising the daily download quota
+	Password   string  `json:"password"`    // Account password (sensitive)
+	BaseURL    string  `json:"base_url"`    // API root, e.g. https://api.opensubtitles.com/api/v1
+	UserAgent  string  `json:"user_agent"`  // Application name the API requires on every request
+	TimeoutSec int     `json:"timeout_sec"` // Request timeout in seconds
+	RateLimit  float64 `json:"rate_limit"`  // Requests per second
+}
+
+// SubtitlesConfig selects which subtitles are downloaded
+type SubtitlesConfig struct {
+	Languages                string `json:"languages"`                  // Comma-separated language codes, e.g. "en,pt-BR"
+	HearingImpaired          string `json:"hearing_impaired"`           // include, exclude or only
+	Forced                   bool   `json:"forced"`                     // Also download forced (foreign parts only) subtitles
+	HashOnly                 bool   `json:"hash_only"`                  // Only accept subtitles matched by file hash
+	ExcludeMachineTranslated bool   `json:"exclude_machine_translated"` // Skip machine and AI translated subtitles
+}
+
+// FeaturesConfig contains feature toggle settings
+type FeaturesConfig struct {
+	AutoDownload      bool `json:"auto_download"`      // Download subtitles during scanning
+	OverwriteExisting bool `json:"overwrite_existing"` // Download again for files that already have subtitles
+}
+
+// DefaultConfig returns the default configuration
+func DefaultConfig() *Config {
+	return &Config{
+		API: APIConfig{
+			Key:        "", // Must be provided by user
+			BaseURL:    "https://api.opensubtitles.com/api/v1",
+			UserAgent:  "Viewra v1.0.0",
+			TimeoutSec: 30,
+			RateLimit:  4, // OpenSubtitles allows 5 requests per second
+		},
+		Subtitles: SubtitlesConfig{
+			Languages:                "en",
+			HearingImpaired:          HearingImpairedInclude,
+			ExcludeMachineTranslated: true,
+		},
+		Features: FeaturesConfig{
+			AutoDownload:      true,
+			OverwriteExisting: false,
+		},
+	}
+}
+
+// GetRequestTimeout returns the request timeout duration
+func (c *APIConfig) GetRequestTimeout() time.Duration {
+	return time.Duration(c.TimeoutSec) * time.Second
+}
+
+// GetRequestDelay returns the minimum delay between API requests
+func (c *APIConfig) GetRequestDelay() time.Duration {
+	return time.Duration(float64(time.Second) / c.RateLimit)
+}
+
+// GetLanguages returns the configured language codes in order, without
+// duplicates
+func (c *SubtitlesConfig) GetLanguages() []string {
+	var languages []string
+	seen := make(map[string]bool)
+	for _, language := range strings.Split(c.Languages, ",") {
+		language = strings.TrimSpace(language)
+		if language == "" || seen[strings.ToLower(language)] {
+			continue
+		}
+		seen[strings.ToLower(language)] = true
+		languages = append(languages, language)
+	}
+	return languages
+}
+
+// Validate validates the configuration
+func (c *Config) Validate() error {
+	if c.API.BaseURL == "" {
+		return fmt.Errorf("API base URL is required")
+	}
+
+	if c.API.UserAgent == "" {
+		return fmt.Errorf("API user agent is required")
+	}
+
+	if c.API.RateLimit <= 0 {
+		return fmt.Errorf("API rate limit must be positive")
+	}
+
+	if c.API.TimeoutSec <= 0 {
+		return fmt.Errorf("API timeout must be positive")
+	}
+
+	if (c.API.Username == "") != (c.API.Password == "") {
+		return fmt.Errorf("API username and password must be set together")
+	}
+
+	if len(c.Subtitles.GetLanguages()) == 0 {
+		return fmt.Errorf("at least one subtitle language is required")
+	}
+
+	switch strings.ToLower(c.Subtitles.HearingImpaired) {
+	case HearingImpairedInclude, HearingImpairedExclude, HearingImpairedOnly:
+	default:
+		return fmt.Errorf("invalid hearing_impaired %q: use include, exclude or only", c.Subtitles.HearingImpaired)
+	}
+
+	return nil
+}
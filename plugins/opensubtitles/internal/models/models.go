@@ -0,0 +1,46 @@
+package models
+
+import (
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// SubtitleDownload records a subtitle downloaded for a media file, so later
+// scans don't spend the download quota on it again
+type SubtitleDownload struct {
+	ID          uint32 `gorm:"primaryKey" json:"id"`
+	MediaFileID string `gorm:"not null;index" json:"media_file_id"` // Reference to media file
+	Language    string `gorm:"not null" json:"language"`            // Language code as configured, e.g. en
+	Forced      bool   `gorm:"not null;default:false" json:"forced"`
+	SDH         bool   `gorm:"not null;default:false" json:"sdh"` // For the deaf and hard of hearing
+
+	SubtitleID string `gorm:"not null" json:"subtitle_id"` // OpenSubtitles subtitle ID
+	FileID     int    `gorm:"not null" json:"file_id"`     // OpenSubtitles file ID the download was requested for
+	Release    string `json:"release,omitempty"`           // Release name the subtitle was made for
+	MovieHash  string `json:"movie_hash,omitempty"`        // OpenSubtitles hash of the media file, empty when it couldn't be read
+	HashMatch  bool   `gorm:"not null;default:false" json:"hash_match"`
+	AssetID    uint32 `json:"asset_id,omitempty"` // Host asset the subtitle was saved as
+
+	DownloadedAt time.Time `gorm:"autoCreateTime" json:"downloaded_at"`
+	UpdatedAt    time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// TableName returns the table name for SubtitleDownload
+func (SubtitleDownload) TableName() string {
+	return "opensubtitles_downloads"
+}
+
+// Migrate creates or updates the plugin's tables and adds the unique key on
+// media file, language and kind that download upserts rely on
+func Migrate(db *gorm.DB) error {
+	if err := db.AutoMigrate(&SubtitleDownload{}); err != nil {
+		return err
+	}
+
+	if err := db.Exec("CREATE UNIQUE INDEX IF NOT EXISTS idx_opensubtitles_downloads_track_unique ON opensubtitles_downloads(media_file_id, language, forced)").Error; err != nil {
+		return fmt.Errorf("failed to add unique key on media file and track: %w", err)
+	}
+	return nil
+}
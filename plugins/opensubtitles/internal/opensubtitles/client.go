@@ -0,0 +1,390 @@
+package opensubtitles
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mantonx/viewra/plugins/opensubtitles/internal/config"
+	plugins "github.com/mantonx/viewra/sdk"
+)
+
+// maxSubtitleSize bounds a downloaded subtitle file
+const maxSubtitleSize = 10 * 1024 * 1024
+
+// SearchParams narrows a subtitle search. Set MovieHash to find subtitles
+// timed against the same release, or Query and the numbers to search by name.
+type SearchParams struct {
+	MovieHash string
+	Query     string
+	Year      int
+	Season    int
+	Episode   int
+	Type      string // movie or episode, empty for both
+	Languages []string
+}
+
+// Subtitle is a subtitle returned by /subtitles
+type Subtitle struct {
+	ID         string             `json:"id"`
+	Attributes SubtitleAttributes `json:"attributes"`
+}
+
+// SubtitleAttributes describes a subtitle and the files it was uploaded as
+type SubtitleAttributes struct {
+	Language          string `json:"language"`
+	DownloadCount     int    `json:"download_count"`
+	HearingImpaired   bool   `json:"hearing_impaired"`
+	ForeignPartsOnly  bool   `json:"foreign_parts_only"`
+	MachineTranslated bool   `json:"machine_translated"`
+	AITranslated      bool   `json:"ai_translated"`
+	MovieHashMatch    bool   `json:"moviehash_match"`
+	Release           string `json:"release"`
+	URL               string `json:"url"`
+	Files             []File `json:"files"`
+}
+
+// File is one file of a subtitle; multi-CD releases have several
+type File struct {
+	FileID   int    `json:"file_id"`
+	FileName string `json:"file_name"`
+}
+
+// Download is a downloaded subtitle file
+type Download struct {
+	Data   []byte
+	Format string // File extension without the dot, e.g. webvtt or srt
+}
+
+// QuotaError is returned when the account's daily download quota is used up
+type QuotaError struct {
+	ResetAt time.Time
+}
+
+func (e *QuotaError) Error() string {
+	if e.ResetAt.IsZero() {
+		return "OpenSubtitles download quota used up"
+	}
+	return fmt.Sprintf("OpenSubtitles download quota used up until %s", e.ResetAt.Format(time.RFC3339))
+}
+
+// Client handles OpenSubtitles REST API interactions. When an account is
+// configured it logs in on first use and reuses the token until the API
+// rejects it; anonymous downloads get a smaller quota.
+type Client struct {
+	config     *config.Config
+	logger     plugins.Logger
+	httpClient *http.Client
+	monitor    *plugins.BasePerformanceMonitor
+
+	mu          sync.Mutex
+	token       string
+	lastAPICall time.Time
+	quotaReset  time.Time // Set while the download quota is used up
+}
+
+// NewClient creates a new OpenSubtitles API client. Its requests are
+// recorded in monitor when it is set.
+func NewClient(cfg *config.Config, logger plugins.Logger, monitor *plugins.BasePerformanceMonitor) *Client {
+	return &Client{
+		config:     cfg,
+		logger:     logger,
+		httpClient: newHTTPClient(cfg, monitor),
+		monitor:    monitor,
+	}
+}
+
+// newHTTPClient returns the provider client requests are made with
+func newHTTPClient(cfg *config.Config, monitor *plugins.BasePerformanceMonitor) *http.Client {
+	return plugins.InstrumentProviderClient(plugins.NewProviderHTTPClient(cfg.API.GetRequestTimeout()), monitor, "opensubtitles")
+}
+
+// UpdateConfiguration switches the client to new settings, logging in
+// again on the next request
+func (c *Client) UpdateConfiguration(cfg *config.Config) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.config = cfg
+	c.token = ""
+	c.quotaReset = time.Time{}
+	c.httpClient = newHTTPClient(cfg, c.monitor)
+}
+
+// Search lists the subtitles matching params, filtered by the configured
+// hearing impaired and machine translation settings. Forced subtitles are
+// included only when forced is set.
+func (c *Client) Search(params SearchParams, forced bool) ([]Subtitle, error) {
+	query := url.Values{}
+	if params.MovieHash != "" {
+		query.Set("moviehash", params.MovieHash)
+	}
+	if params.Query != "" {
+		query.Set("query", params.Query)
+	}
+	if params.Year > 0 {
+		query.Set("year", strconv.Itoa(params.Year))
+	}
+	if params.Season > 0 {
+		query.Set("season_number", strconv.Itoa(params.Season))
+	}
+	if params.Episode > 0 {
+		query.Set("episode_number", strconv.Itoa(params.Episode))
+	}
+	if params.Type != "" {
+		query.Set("type", params.Type)
+	}
+	// The API expects lower case, sorted parameter values
+	languages := make([]string, len(params.Languages))
+	for i, language := range params.Languages {
+		languages[i] = strings.ToLower(language)
+	}
+	query.Set("languages", strings.Join(sortedUnique(languages), ","))
+
+	c.mu.Lock()
+	subtitles := c.config.Subtitles
+	c.mu.Unlock()
+	query.Set("hearing_impaired", strings.ToLower(subtitles.HearingImpaired))
+	if forced {
+		query.Set("foreign_parts_only", "include")
+	} else {
+		query.Set("foreign_parts_only", "exclude")
+	}
+	if subtitles.ExcludeMachineTranslated {
+		query.Set("machine_translated", "exclude")
+		query.Set("ai_translated", "exclude")
+	}
+
+	var response struct {
+		Data []Subtitle `json:"data"`
+	}
+	if _, err := c.call("GET", "/subtitles?"+query.Encode(), nil, &response); err != nil {
+		return nil, fmt.Errorf("failed to search subtitles: %w", err)
+	}
+	return response.Data, nil
+}
+
+// Download requests a download link for a subtitle file and fetches it as
+// WebVTT. Each call counts against the daily download quota.
+func (c *Client) Download(fileID int) (*Download, error) {
+	c.mu.Lock()
+	reset := c.quotaReset
+	c.mu.Unlock()
+	if !reset.IsZero() && time.Now().Before(reset) {
+		return nil, &QuotaError{ResetAt: reset}
+	}
+
+	var link struct {
+		Link         string `json:"link"`
+		FileName     string `json:"file_name"`
+		Remaining    *int   `json:"remaining"`
+		Message      string `json:"message"`
+		ResetTimeUTC string `json:"reset_time_utc"`
+	}
+	status, err := c.call("POST", "/download", map[string]interface{}{"file_id": fileID, "sub_format": "webvtt"}, &link)
+	if status == http.StatusNotAcceptable {
+		// The quota is used up; the body still says when it resets
+		return nil, c.quotaExhausted(link.ResetTimeUTC)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to request download of file %d: %w", fileID, err)
+	}
+	if link.Link == "" {
+		return nil, fmt.Errorf("no download link for file %d: %s", fileID, link.Message)
+	}
+	if link.Remaining != nil && *link.Remaining <= 0 {
+		c.quotaExhausted(link.ResetTimeUTC)
+	}
+
+	data, err := c.fetch(link.Link)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download file %d: %w", fileID, err)
+	}
+	format := strings.TrimPrefix(path.Ext(link.FileName), ".")
+	if format == "" {
+		format = "webvtt"
+	}
+	return &Download{Data: data, Format: format}, nil
+}
+
+// quotaExhausted stops downloads until the quota resets, or for a day when
+// the reset time is unknown
+func (c *Client) quotaExhausted(resetTimeUTC string) error {
+	reset, err := time.Parse(time.RFC3339, resetTimeUTC)
+	if err != nil {
+		reset = time.Now().Add(24 * time.Hour)
+	}
+	c.mu.Lock()
+	c.quotaReset = reset
+	c.mu.Unlock()
+	c.logger.Warn("OpenSubtitles download quota used up", "reset_at", reset.Format(time.RFC3339))
+	return &QuotaError{ResetAt: reset}
+}
+
+// call sends an API request, decoding the JSON response into result. An
+// expired token is replaced and the request retried once.
+func (c *Client) call(method, apiPath string, body interface{}, result interface{}) (int, error) {
+	status, err := c.do(method, apiPath, body, result)
+	if status == http.StatusUnauthorized {
+		c.mu.Lock()
+		hadToken := c.token != ""
+		c.token = ""
+		c.mu.Unlock()
+		if hadToken {
+			status, err = c.do(method, apiPath, body, result)
+		}
+	}
+	return status, err
+}
+
+func (c *Client) do(method, apiPath string, body interface{}, result interface{}) (int, error) {
+	token, err := c.ensureToken()
+	if err != nil {
+		return 0, err
+	}
+	c.waitForRateLimit()
+
+	req, err := c.newRequest(method, apiPath, body)
+	if err != nil {
+		return 0, err
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	return c.send(req, result)
+}
+
+// ensureToken returns the bearer token, logging in when an account is
+// configured and there is none. Without an account it returns "".
+func (c *Client) ensureToken() (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.token != "" || c.config.API.Username == "" {
+		return c.token, nil
+	}
+
+	req, err := c.newRequest("POST", "/login", map[string]string{
+		"username": c.config.API.Username,
+		"password": c.config.API.Password,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	var login struct {
+		Token string `json:"token"`
+	}
+	if _, err := c.send(req, &login); err != nil {
+		return "", fmt.Errorf("OpenSubtitles login failed: %w", err)
+	}
+	if login.Token == "" {
+		return "", fmt.Errorf("OpenSubtitles login returned no token")
+	}
+	c.token = login.Token
+	return c.token, nil
+}
+
+// newRequest builds an API request with the headers OpenSubtitles requires
+func (c *Client) newRequest(method, apiPath string, body interface{}) (*http.Request, error) {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return nil, err
+		}
+		reader = bytes.NewReader(data)
+	}
+	req, err := http.NewRequest(method, strings.TrimSuffix(c.config.API.BaseURL, "/")+apiPath, reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Api-Key", c.config.API.Key)
+	req.Header.Set("User-Agent", c.config.API.UserAgent)
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	return req, nil
+}
+
+// send performs a request and decodes its JSON body into result. Error
+// responses are decoded too, as some carry details such as quota resets.
+func (c *Client) send(req *http.Request, result interface{}) (int, error) {
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("HTTP request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return resp.StatusCode, fmt.Errorf("failed to read response body: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		json.Unmarshal(body, result)
+		return resp.StatusCode, fmt.Errorf("OpenSubtitles API returned status %d", resp.StatusCode)
+	}
+	if err := json.Unmarshal(body, result); err != nil {
+		return resp.StatusCode, fmt.Errorf("failed to unmarshal JSON response: %w", err)
+	}
+	return resp.StatusCode, nil
+}
+
+// fetch downloads a subtitle file from a download link
+func (c *Client) fetch(link string) ([]byte, error) {
+	req, err := http.NewRequest("GET", link, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("User-Agent", c.config.API.UserAgent)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("HTTP request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("download returned status %d", resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxSubtitleSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read subtitle: %w", err)
+	}
+	if len(data) > maxSubtitleSize {
+		return nil, fmt.Errorf("subtitle is larger than %d bytes", maxSubtitleSize)
+	}
+	return data, nil
+}
+
+// waitForRateLimit spaces requests by the configured rate limit
+func (c *Client) waitForRateLimit() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if wait := c.config.API.GetRequestDelay() - time.Since(c.lastAPICall); wait > 0 {
+		time.Sleep(wait)
+	}
+	c.lastAPICall = time.Now()
+}
+
+// sortedUnique sorts values and drops duplicates
+func sortedUnique(values []string) []string {
+	seen := make(map[string]bool, len(values))
+	var unique []string
+	for _, value := range values {
+		if value != "" && !seen[value] {
+			seen[value] = true
+			unique = append(unique, value)
+		}
+	}
+	sort.Strings(unique)
+	return unique
+}
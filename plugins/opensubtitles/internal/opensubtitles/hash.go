@@ -0,0 +1,43 @@
+package opensubtitles
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"os"
+)
+
+// hashChunkSize is the size of the file's head and tail read into the hash
+const hashChunkSize = 64 * 1024
+
+// FileHash computes the OpenSubtitles hash of a video file: its size plus
+// the 64-bit little-endian words of its first and last 64 KiB, summed with
+// overflow. Subtitles searched by it were timed against the same release.
+func FileHash(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to open file: %w", err)
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return "", fmt.Errorf("failed to stat file: %w", err)
+	}
+	size := info.Size()
+	if size < hashChunkSize {
+		return "", fmt.Errorf("file is too small to hash: %d bytes", size)
+	}
+
+	hash := uint64(size)
+	chunk := make([]byte, hashChunkSize)
+	for _, offset := range []int64{0, size - hashChunkSize} {
+		if _, err := file.ReadAt(chunk, offset); err != nil && err != io.EOF {
+			return "", fmt.Errorf("failed to read file: %w", err)
+		}
+		for i := 0; i < hashChunkSize; i += 8 {
+			hash += binary.LittleEndian.Uint64(chunk[i:])
+		}
+	}
+	return fmt.Sprintf("%016x", hash), nil
+}
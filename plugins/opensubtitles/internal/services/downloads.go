@@ -0,0 +1,334 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mantonx/viewra/plugins/opensubtitles/internal/config"
+	"github.com/mantonx/viewra/plugins/opensubtitles/internal/models"
+	"github.com/mantonx/viewra/plugins/opensubtitles/internal/opensubtitles"
+	plugins "github.com/mantonx/viewra/sdk"
+	"github.com/mantonx/viewra/sdk/namingparser"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// track is a subtitle wanted for a media file: one language, regular or forced
+type track struct {
+	Language string
+	Forced   bool
+}
+
+func (t track) String() string {
+	if t.Forced {
+		return t.Language + " (forced)"
+	}
+	return t.Language
+}
+
+// DownloadService finds subtitles for video files on OpenSubtitles and saves
+// them through the host asset service
+type DownloadService struct {
+	db            *gorm.DB
+	config        *config.Config
+	client        *opensubtitles.Client
+	unifiedClient *plugins.UnifiedServiceClient
+	logger        plugins.Logger
+}
+
+// NewDownloadService creates a new download service, recording its
+// OpenSubtitles requests in monitor
+func NewDownloadService(db *gorm.DB, cfg *config.Config, client *plugins.UnifiedServiceClient, logger plugins.Logger, monitor *plugins.BasePerformanceMonitor) *DownloadService {
+	return &DownloadService{
+		db:            db,
+		config:        cfg,
+		client:        opensubtitles.NewClient(cfg, logger, monitor),
+		unifiedClient: client,
+		logger:        logger,
+	}
+}
+
+// UpdateConfiguration updates the service configuration
+func (s *DownloadService) UpdateConfiguration(newConfig *config.Config) {
+	s.config = newConfig
+	s.client.UpdateConfiguration(newConfig)
+}
+
+// ProcessMediaFile downloads the configured languages' subtitles for a video
+// file. Subtitles matched by the file's hash are preferred, as they are timed
+// against the same release; other files fall back to a search by the title
+// in the file name unless hash_only is set.
+func (s *DownloadService) ProcessMediaFile(mediaFileID string, filePath string, metadata map[string]string) error {
+	if s.unifiedClient == nil {
+		return fmt.Errorf("not connected to host services")
+	}
+
+	wanted, err := s.wantedTracks(mediaFileID)
+	if err != nil {
+		return err
+	}
+	if len(wanted) == 0 {
+		return plugins.SkipHook(plugins.SkipReasonAlreadyProcessed, "subtitles already downloaded")
+	}
+
+	movieHash, err := opensubtitles.FileHash(filePath)
+	if err != nil {
+		if s.config.Subtitles.HashOnly {
+			return plugins.SkipHook(plugins.SkipReasonMissingMetadata, fmt.Sprintf("can't hash file: %v", err))
+		}
+		s.logger.Debug("can't hash file, searching by name", "error", err, "media_file_id", mediaFileID)
+	}
+
+	picks, err := s.findSubtitles(wanted, movieHash, plugins.MatchPath(filePath, metadata), metadata[plugins.HookMetadataMediaType])
+	if err != nil {
+		return err
+	}
+	if len(picks) == 0 {
+		return plugins.SkipHook(plugins.SkipReasonNoMatch, fmt.Sprintf("no %s subtitles on OpenSubtitles", joinTracks(wanted)))
+	}
+
+	saved := 0
+	for _, t := range wanted {
+		subtitle, ok := picks[t]
+		if !ok {
+			continue
+		}
+		if err := s.downloadSubtitle(mediaFileID, t, subtitle, movieHash); err != nil {
+			var quotaErr *opensubtitles.QuotaError
+			if errors.As(err, &quotaErr) {
+				return err
+			}
+			s.logger.Warn("failed to download subtitle", "error", err, "media_file_id", mediaFileID, "language", t.String())
+			continue
+		}
+		saved++
+	}
+	if saved == 0 {
+		return fmt.Errorf("failed to download any of %d matched subtitles", len(picks))
+	}
+
+	s.logger.Info("downloaded subtitles from OpenSubtitles", "media_file_id", mediaFileID, "count", saved, "wanted", len(wanted))
+	return nil
+}
+
+// wantedTracks lists the configured tracks the file has no downloaded
+// subtitle for, or all of them when overwrite_existing is set
+func (s *DownloadService) wantedTracks(mediaFileID string) ([]track, error) {
+	var wanted []track
+	for _, language := range s.config.Subtitles.GetLanguages() {
+		wanted = append(wanted, track{Language: language})
+		if s.config.Subtitles.Forced {
+			wanted = append(wanted, track{Language: language, Forced: true})
+		}
+	}
+	if s.config.Features.OverwriteExisting {
+		return wanted, nil
+	}
+
+	var downloads []models.SubtitleDownload
+	if err := s.db.Where("media_file_id = ?", mediaFileID).Find(&downloads).Error; err != nil {
+		return nil, fmt.Errorf("failed to load downloaded subtitles: %w", err)
+	}
+	done := make(map[track]bool, len(downloads))
+	for _, download := range downloads {
+		done[track{Language: strings.ToLower(download.Language), Forced: download.Forced}] = true
+	}
+
+	var missing []track
+	for _, t := range wanted {
+		if !done[track{Language: strings.ToLower(t.Language), Forced: t.Forced}] {
+			missing = append(missing, t)
+		}
+	}
+	return missing, nil
+}
+
+// findSubtitles picks the best subtitle for each wanted track, searching by
+// the file's hash first and then by name for the tracks still missing
+func (s *DownloadService) findSubtitles(wanted []track, movieHash, filePath, mediaType string) (map[track]opensubtitles.Subtitle, error) {
+	picks := make(map[track]opensubtitles.Subtitle)
+
+	if movieHash != "" {
+		results, err := s.search(opensubtitles.SearchParams{MovieHash: movieHash}, wanted)
+		if err != nil {
+			return nil, err
+		}
+		// Hash searches also return other subtitles of the matched title,
+		// which aren't timed against this release
+		var matched []opensubtitles.Subtitle
+		for _, subtitle := range results {
+			if subtitle.Attributes.MovieHashMatch {
+				matched = append(matched, subtitle)
+			}
+		}
+		pickBest(picks, wanted, matched)
+	}
+
+	if s.config.Subtitles.HashOnly || len(picks) == len(wanted) {
+		return picks, nil
+	}
+
+	params, ok := nameSearch(filePath, mediaType)
+	if !ok {
+		if len(picks) == 0 {
+			s.logger.Debug("no title in file name to search by", "path", filePath)
+		}
+		return picks, nil
+	}
+	var missing []track
+	for _, t := range wanted {
+		if _, ok := picks[t]; !ok {
+			missing = append(missing, t)
+		}
+	}
+	results, err := s.search(params, missing)
+	if err != nil {
+		return nil, err
+	}
+	pickBest(picks, missing, results)
+	return picks, nil
+}
+
+// search runs a subtitle search for the tracks' languages, including forced
+// subtitles when a forced track is wanted
+func (s *DownloadService) search(params opensubtitles.SearchParams, tracks []track) ([]opensubtitles.Subtitle, error) {
+	forced := false
+	params.Languages = nil
+	for _, t := range tracks {
+		params.Languages = append(params.Languages, t.Language)
+		forced = forced || t.Forced
+	}
+	results, err := s.client.Search(params, forced)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search OpenSubtitles: %w", err)
+	}
+	return results, nil
+}
+
+// nameSearch returns the search for a file by the title, year and episode
+// numbers in its name
+func nameSearch(filePath, mediaType string) (opensubtitles.SearchParams, bool) {
+	parsed := namingparser.Parse(filePath)
+	if parsed.Title == "" {
+		return opensubtitles.SearchParams{}, false
+	}
+
+	params := opensubtitles.SearchParams{Query: parsed.Title, Year: parsed.Year, Type: "movie"}
+	if parsed.Kind == namingparser.KindEpisode || mediaType == "episode" {
+		// Absolute-numbered episodes can't be searched by season and episode
+		if parsed.Episode() == 0 {
+			return opensubtitles.SearchParams{}, false
+		}
+		params.Type = "episode"
+		params.Season = parsed.Season
+		params.Episode = parsed.Episode()
+	}
+	return params, true
+}
+
+// pickBest adds the best result for each track without a pick: hash matches
+// first, then the most downloaded
+func pickBest(picks map[track]opensubtitles.Subtitle, tracks []track, results []opensubtitles.Subtitle) {
+	for _, t := range tracks {
+		if _, ok := picks[t]; ok {
+			continue
+		}
+		var best *opensubtitles.Subtitle
+		for i := range results {
+			subtitle := &results[i]
+			attributes := subtitle.Attributes
+			if !strings.EqualFold(attributes.Language, t.Language) || attributes.ForeignPartsOnly != t.Forced || len(attributes.Files) == 0 {
+				continue
+			}
+			if best == nil || betterSubtitle(attributes, best.Attributes) {
+				best = subtitle
+			}
+		}
+		if best != nil {
+			picks[t] = *best
+		}
+	}
+}
+
+func betterSubtitle(a, b opensubtitles.SubtitleAttributes) bool {
+	if a.MovieHashMatch != b.MovieHashMatch {
+		return a.MovieHashMatch
+	}
+	return a.DownloadCount > b.DownloadCount
+}
+
+// downloadSubtitle downloads a subtitle, saves it as an asset of the media
+// file and records the download
+func (s *DownloadService) downloadSubtitle(mediaFileID string, t track, subtitle opensubtitles.Subtitle, movieHash string) error {
+	attributes := subtitle.Attributes
+	file := attributes.Files[0]
+	download, err := s.client.Download(file.FileID)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	response, err := s.unifiedClient.AssetService().SaveAsset(ctx, &plugins.SaveAssetRequest{
+		MediaFileID: mediaFileID,
+		AssetType:   "subtitle",
+		Category:    "subtitle",
+		Subtype:     t.Language,
+		Data:        download.Data,
+		MimeType:    "text/vtt",
+		SourceURL:   attributes.URL,
+		PluginID:    "opensubtitles",
+		Metadata: map[string]string{
+			"language": t.Language,
+			"forced":   strconv.FormatBool(t.Forced),
+			"sdh":      strconv.FormatBool(attributes.HearingImpaired),
+			"format":   download.Format,
+			"release":  attributes.Release,
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to save subtitle: %w", err)
+	}
+	if !response.Success {
+		return fmt.Errorf("failed to save subtitle: %s", response.Error)
+	}
+
+	// Upsert on the track so a forced re-download replaces its row
+	return s.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "media_file_id"}, {Name: "language"}, {Name: "forced"}},
+		UpdateAll: true,
+	}).Create(&models.SubtitleDownload{
+		MediaFileID: mediaFileID,
+		Language:    t.Language,
+		Forced:      t.Forced,
+		SDH:         attributes.HearingImpaired,
+		SubtitleID:  subtitle.ID,
+		FileID:      file.FileID,
+		Release:     attributes.Release,
+		MovieHash:   movieHash,
+		HashMatch:   attributes.MovieHashMatch,
+		AssetID:     response.AssetID,
+	}).Error
+}
+
+// RemoveMediaFile forgets the subtitles downloaded for a media file, so
+// they are downloaded again the next time it is scanned
+func (s *DownloadService) RemoveMediaFile(mediaFileID string) error {
+	if err := s.db.Where("media_file_id = ?", mediaFileID).Delete(&models.SubtitleDownload{}).Error; err != nil {
+		return fmt.Errorf("failed to remove downloads: %w", err)
+	}
+	return nil
+}
+
+func joinTracks(tracks []track) string {
+	names := make([]string, len(tracks))
+	for i, t := range tracks {
+		names[i] = t.String()
+	}
+	return strings.Join(names, ", ")
+}
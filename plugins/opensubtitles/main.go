@@ -0,0 +1,267 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	plugins "github.com/mantonx/viewra/sdk"
+	"google.golang.org/grpc/connectivity"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+
+	"github.com/mantonx/viewra/plugins/opensubtitles/internal/config"
+	"github.com/mantonx/viewra/plugins/opensubtitles/internal/models"
+	"github.com/mantonx/viewra/plugins/opensubtitles/internal/services"
+)
+
+// Version is the plugin version, overridable at build time
+var Version = "1.0.0"
+
+// OpenSubtitles downloads subtitles for scanned movie and episode files from
+// OpenSubtitles.com and saves them with the host as WebVTT assets, listed
+// next to the files' embedded and sidecar subtitles.
+type OpenSubtitles struct {
+	*plugins.BasePlugin
+
+	db         *gorm.DB
+	logger     plugins.Logger
+	config     *config.Config
+	downloader *services.DownloadService
+
+	// OpenSubtitles request latency, reported to the admin dashboard
+	performanceMonitor *plugins.BasePerformanceMonitor
+
+	// Host service connections
+	unifiedClient *plugins.UnifiedServiceClient
+}
+
+// Plugin lifecycle methods
+func (o *OpenSubtitles) Initialize(ctx *plugins.PluginContext) error {
+	if ctx == nil {
+		return fmt.Errorf("plugin context is nil")
+	}
+	if ctx.Logger == nil {
+		return fmt.Errorf("logger in plugin context is nil")
+	}
+	o.logger = ctx.Logger
+
+	if ctx.PluginBasePath == "" {
+		return fmt.Errorf("PluginBasePath is empty")
+	}
+
+	cfg := config.DefaultConfig()
+	if err := plugins.LoadPluginConfig(ctx, cfg); err != nil {
+		return fmt.Errorf("failed to load OpenSubtitles configuration: %w", err)
+	}
+	if err := cfg.Validate(); err != nil {
+		return fmt.Errorf("invalid OpenSubtitles configuration: %w", err)
+	}
+	if cfg.API.Key == "" {
+		o.logger.Warn("no OpenSubtitles API key configured; requests will fail outside mock provider mode")
+	}
+	o.config = cfg
+
+	dbPath := filepath.Join(ctx.PluginBasePath, "opensubtitles.db")
+	db, err := gorm.Open(sqlite.Open(dbPath), &gorm.Config{})
+	if err != nil {
+		return fmt.Errorf("failed to open database: %w", err)
+	}
+	if err := models.Migrate(db); err != nil {
+		return fmt.Errorf("failed to migrate database: %w", err)
+	}
+	o.db = db
+
+	if ctx.HostServiceAddr != "" {
+		clientConfig := plugins.DefaultClientConfig()
+		clientConfig.OnStateChange = func(state connectivity.State) {
+			o.logger.Info("host service connection state changed", "state", state.String())
+		}
+		client, err := plugins.NewUnifiedServiceClientWithConfig(ctx.HostServiceAddr, clientConfig)
+		if err != nil {
+			o.logger.Warn("failed to connect to host services", "error", err)
+		} else {
+			o.unifiedClient = client
+		}
+	}
+
+	o.performanceMonitor = plugins.NewBasePerformanceMonitor("OpenSubtitles")
+	o.downloader = services.NewDownloadService(o.db, o.config, o.unifiedClient, o.logger, o.performanceMonitor)
+
+	o.logger.Info("OpenSubtitles initialized", "languages", cfg.Subtitles.GetLanguages(), "forced", cfg.Subtitles.Forced,
+		"hearing_impaired", cfg.Subtitles.HearingImpaired, "logged_in", cfg.API.Username != "")
+	return nil
+}
+
+func (o *OpenSubtitles) Start() error {
+	o.logger.Info("OpenSubtitles started")
+	return nil
+}
+
+func (o *OpenSubtitles) Stop() error {
+	if o.db != nil {
+		if sqlDB, err := o.db.DB(); err == nil {
+			sqlDB.Close()
+		}
+	}
+	if o.unifiedClient != nil {
+		o.unifiedClient.Close()
+	}
+	o.logger.Info("OpenSubtitles stopped")
+	return nil
+}
+
+func (o *OpenSubtitles) Info() (*plugins.PluginInfo, error) {
+	return &plugins.PluginInfo{
+		ID:          "opensubtitles",
+		Name:        "OpenSubtitles Downloader",
+		Version:     Version,
+		Type:        "scanner_hook",
+		Description: "Downloads subtitles for movies and episodes from OpenSubtitles.com, matched by file hash",
+		Author:      "Viewra Team",
+	}, nil
+}
+
+// Health returns nil if the plugin is healthy
+func (o *OpenSubtitles) Health() error {
+	if o.db == nil {
+		return fmt.Errorf("database not initialized")
+	}
+	if sqlDB, err := o.db.DB(); err != nil {
+		return fmt.Errorf("database error: %w", err)
+	} else if err := sqlDB.Ping(); err != nil {
+		return fmt.Errorf("database ping failed: %w", err)
+	}
+	return nil
+}
+
+// Scanner hook service implementation
+func (o *OpenSubtitles) OnMediaFileScanned(mediaFileID string, filePath string, metadata map[string]string) error {
+	if !o.config.Features.AutoDownload {
+		return plugins.SkipHook(plugins.SkipReasonDisabled, "automatic downloads are disabled")
+	}
+
+	// The host only keeps subtitles for movies and episodes
+	switch metadata[plugins.HookMetadataMediaType] {
+	case "movie", "episode":
+	default:
+		return plugins.SkipHook(plugins.SkipReasonUnsupportedType, "not a movie or episode")
+	}
+
+	if err := o.downloader.ProcessMediaFile(mediaFileID, filePath, metadata); err != nil {
+		if _, skipped := plugins.AsSkipError(err); !skipped {
+			o.logger.Warn("subtitle download failed", "error", err, "media_file_id", mediaFileID)
+		}
+		return err
+	}
+	return nil
+}
+
+// OnMediaFileRemoved forgets the downloads of a deleted file. The host
+// removes the subtitle assets with the file.
+func (o *OpenSubtitles) OnMediaFileRemoved(mediaFileID string, filePath string, metadata map[string]string) error {
+	if err := o.downloader.RemoveMediaFile(mediaFileID); err != nil {
+		o.logger.Warn("failed to remove downloads", "error", err, "media_file_id", mediaFileID)
+		return err
+	}
+	return nil
+}
+
+// OnMediaFileUpdated downloads subtitles again for a replaced file, whose
+// hash and release may differ. The new downloads replace the old assets.
+func (o *OpenSubtitles) OnMediaFileUpdated(mediaFileID string, filePath string, metadata map[string]string) error {
+	if !o.config.Features.AutoDownload {
+		return plugins.SkipHook(plugins.SkipReasonDisabled, "automatic downloads are disabled")
+	}
+	if err := o.downloader.RemoveMediaFile(mediaFileID); err != nil {
+		return err
+	}
+	return o.OnMediaFileScanned(mediaFileID, filePath, metadata)
+}
+
+func (o *OpenSubtitles) OnScanStarted(scanJobID, libraryID uint32, libraryPath string) error {
+	o.logger.Info("scan started", "scan_job_id", scanJobID, "library_id", libraryID)
+	return nil
+}
+
+func (o *OpenSubtitles) OnScanCompleted(scanJobID, libraryID uint32, stats map[string]string) error {
+	return nil
+}
+
+// Metadata scraper service implementation
+func (o *OpenSubtitles) CanHandle(filePath, mimeType string) bool {
+	if strings.HasPrefix(mimeType, "video/") {
+		return true
+	}
+
+	ext := strings.ToLower(filepath.Ext(filePath))
+	videoExtensions := []string{".mp4", ".mkv", ".avi", ".mov", ".wmv", ".flv", ".webm", ".m4v", ".ts", ".mpg", ".mpeg"}
+	for _, videoExt := range videoExtensions {
+		if ext == videoExt {
+			return true
+		}
+	}
+	return false
+}
+
+func (o *OpenSubtitles) ExtractMetadata(filePath string) (map[string]string, error) {
+	// This plugin doesn't read metadata; it downloads subtitles
+	return map[string]string{
+		"source": "opensubtitles",
+	}, nil
+}
+
+func (o *OpenSubtitles) GetSupportedTypes() []string {
+	return []string{"movie", "episode"}
+}
+
+// Database service implementation
+func (o *OpenSubtitles) GetModels() []string {
+	return []string{
+		"SubtitleDownload",
+	}
+}
+
+func (o *OpenSubtitles) Migrate(connectionString string) error {
+	db, err := gorm.Open(sqlite.Open(connectionString), &gorm.Config{})
+	if err != nil {
+		return fmt.Errorf("failed to connect to database: %w", err)
+	}
+	if err := models.Migrate(db); err != nil {
+		return fmt.Errorf("failed to migrate database: %w", err)
+	}
+	return nil
+}
+
+func (o *OpenSubtitles) Rollback(connectionString string) error {
+	db, err := gorm.Open(sqlite.Open(connectionString), &gorm.Config{})
+	if err != nil {
+		return fmt.Errorf("failed to connect to database: %w", err)
+	}
+	return db.Migrator().DropTable(&models.SubtitleDownload{})
+}
+
+// Service interfaces implementation
+func (o *OpenSubtitles) MetadataScraperService() plugins.MetadataScraperService {
+	return o
+}
+
+func (o *OpenSubtitles) ScannerHookService() plugins.ScannerHookService {
+	return o
+}
+
+func (o *OpenSubtitles) DatabaseService() plugins.DatabaseService {
+	return o
+}
+
+// PerformanceMonitorService reports OpenSubtitles request latency and status codes
+func (o *OpenSubtitles) PerformanceMonitorService() plugins.PerformanceMonitorService {
+	return o.performanceMonitor
+}
+
+func main() {
+	plugin := &OpenSubtitles{
+		BasePlugin: plugins.NewBasePlugin("OpenSubtitles Downloader", Version, "scanner_hook", "Downloads subtitles from OpenSubtitles.com"),
+	}
+	plugins.StartPlugin(plugin)
+}
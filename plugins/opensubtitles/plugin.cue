@@ -0,0 +1,86 @@
+#Plugin: {
+	schema_version: "1.0"
+
+	// Plugin identification
+	id:            "opensubtitles"
+	name:          "OpenSubtitles Downloader"
+	version:       "1.0.0"
+	description:   "Downloads subtitles for movies and episodes from OpenSubtitles.com, matched by file hash"
+	author:        "Viewra Team"
+	website:       "https://github.com/mantonx/viewra"
+	repository:    "https://github.com/mantonx/viewra"
+	license:       "MIT"
+	type:          "scanner_hook"
+	tags: [
+		"subtitles",
+		"movie",
+		"tv",
+		"opensubtitles",
+		"external-api"
+	]
+	media_types: ["movie", "tv"]
+
+	// Plugin behavior. Off until an API key is configured, as every
+	// download counts against the account's daily quota.
+	enabled_by_default: false
+
+	// Plugin capabilities
+	capabilities: {
+		metadata_extraction: false
+		scanner_hooks:       true
+		search_service:      false
+		api_endpoints:       false
+		database_access:     true
+		background_tasks:    false
+		external_services:   true
+		asset_management:    true
+	}
+
+	// Entry points
+	entry_points: {
+		main: "opensubtitles"
+	}
+
+	// Permissions
+	permissions: [
+		"database:read",
+		"database:write",
+		"network:external",
+		"filesystem:read"
+	]
+
+	settings: {
+		// OpenSubtitles REST API settings. A consumer API key is required;
+		// logging in with an account raises the daily download quota.
+		// rate_limit is in requests per second.
+		api: {
+			key:         string | *""
+			username:    string | *""
+			password:    string | *""
+			base_url:    string | *"https://api.opensubtitles.com/api/v1"
+			user_agent:  string | *"Viewra v1.0.0"
+			timeout_sec: int | *30
+			rate_limit:  float64 | *4.0
+		}
+
+		// Which subtitles to download. languages is a comma-separated list
+		// of codes, e.g. "en,pt-BR". hearing_impaired is "include", "exclude"
+		// or "only". forced also downloads a forced (foreign parts only)
+		// track per language. hash_only skips the search by file name, so
+		// only subtitles timed against the exact release are downloaded.
+		subtitles: {
+			languages:                  string | *"en"
+			hearing_impaired:           string | *"include"
+			forced:                     bool | *false
+			hash_only:                  bool | *false
+			exclude_machine_translated: bool | *true
+		}
+
+		// Download during scanning, and download again for files that
+		// already have subtitles when overwrite_existing is set
+		features: {
+			auto_download:      bool | *true
+			overwrite_existing: bool | *false
+		}
+	}
+}
@@ -23,14 +23,17 @@ type APIClient struct {
 // NewAPIClient creates a new TMDb API client
 func NewAPIClient(cfg *config.Config, logger plugins.Logger) *APIClient {
 	return &APIClient{
-		config: cfg,
-		logger: logger,
-		httpClient: &http.Client{
-			Timeout: cfg.API.GetRequestTimeout(),
-		},
+		config:     cfg,
+		logger:     logger,
+		httpClient: plugins.NewProviderHTTPClient(cfg.API.GetRequestTimeout()),
 	}
 }
 
+// SetPerformanceMonitor records the client's requests in monitor
+func (c *APIClient) SetPerformanceMonitor(monitor *plugins.BasePerformanceMonitor) {
+	plugins.InstrumentProviderClient(c.httpClient, monitor, "tmdb")
+}
+
 // MakeRequest makes an HTTP request to the TMDb API with rate limiting
 func (c *APIClient) MakeRequest(url string, result interface{}) error {
 	// Ensure rate limiting
@@ -13,6 +13,8 @@ type Config struct {
 	Artwork     ArtworkConfig     `json:"artwork"`
 	Matching    MatchingConfig    `json:"matching"`
 	Cache       CacheConfig       `json:"cache"`
+	Changes     ChangesConfig     `json:"changes"`
+	People      PeopleConfig      `json:"people"`
 	Reliability ReliabilityConfig `json:"reliability"`
 	Debug       DebugConfig       `json:"debug"`
 }
@@ -62,9 +64,11 @@ type ArtworkConfig struct {
 
 // MatchingConfig contains content matching settings
 type MatchingConfig struct {
-	MatchThreshold float64 `json:"match_threshold"` // Minimum similarity score for matches
-	MatchYear      bool    `json:"match_year"`      // Use release year for matching
-	YearTolerance  int     `json:"year_tolerance"`  // Allow +/- years difference
+	AutoThreshold   float64 `json:"auto_threshold"`   // Minimum score to apply a match without review
+	ReviewThreshold float64 `json:"review_threshold"` // Minimum score to queue a match for review, below it files are unmatched
+	MatchYear       bool    `json:"match_year"`       // Use release year for matching
+	YearTolerance   int     `json:"year_tolerance"`   // Allow +/- years difference
+	LearnAliases    bool    `json:"learn_aliases"`    // Remember matched release names and reuse the matches on later scans
 }
 
 // CacheConfig contains caching settings
@@ -73,6 +77,23 @@ type CacheConfig struct {
 	CleanupInterval int `json:"cleanup_interval"` // Cleanup interval in hours
 }
 
+// ChangesConfig contains settings for polling TMDb's change lists, which
+// keep enriched items current without re-fetching the whole library
+type ChangesConfig struct {
+	Enabled       bool `json:"enabled"`        // Re-fetch library items TMDb reports changed
+	IntervalHours int  `json:"interval_hours"` // Hours between polls
+}
+
+// PeopleConfig contains settings for recording the cast and crew of matched
+// movies and episodes through the host
+type PeopleConfig struct {
+	Enabled          bool   `json:"enabled"`           // Record cast and crew
+	MaxCast          int    `json:"max_cast"`          // Top-billed cast members recorded per item
+	DetailsLimit     int    `json:"details_limit"`     // Credited people per item whose biography and dates are fetched
+	DownloadProfiles bool   `json:"download_profiles"` // Have the host download profile images
+	ProfileSize      string `json:"profile_size"`      // w45, w185, h632, original
+}
+
 // ReliabilityConfig contains retry and reliability settings
 type ReliabilityConfig struct {
 	MaxRetries           int     `json:"max_retries"`            // Maximum retry attempts
@@ -130,14 +151,27 @@ func DefaultConfig() *Config {
 			SkipExistingAssets: true, // Skip existing assets
 		},
 		Matching: MatchingConfig{
-			MatchThreshold: 0.85, // 85% similarity threshold
-			MatchYear:      true, // Use year for matching
-			YearTolerance:  2,    // Allow +/- 2 years difference
+			AutoThreshold:   0.85, // 85% similarity applies the match
+			ReviewThreshold: 0.6,  // 60% similarity queues it for review
+			MatchYear:       true, // Use year for matching
+			YearTolerance:   2,    // Allow +/- 2 years difference
+			LearnAliases:    true, // Reuse earlier matches of a release name
 		},
 		Cache: CacheConfig{
 			DurationHours:   168, // 1 week cache duration
 			CleanupInterval: 24,  // Daily cleanup
 		},
+		Changes: ChangesConfig{
+			Enabled:       true, // Keep enriched items current
+			IntervalHours: 24,   // Daily, matching TMDb's day-granular change lists
+		},
+		People: PeopleConfig{
+			Enabled:          true,   // Record cast and crew
+			MaxCast:          20,     // Top 20 billed cast members
+			DetailsLimit:     10,     // Biographies of the first 10 credited people
+			DownloadProfiles: true,   // Download profile images
+			ProfileSize:      "w185", // 185px width profiles
+		},
 		Reliability: ReliabilityConfig{
 			MaxRetries:           5,    // 5 retry attempts
 			InitialDelaySeconds:  2,    // 2 second initial delay
@@ -173,6 +207,11 @@ func (c *CacheConfig) GetCleanupInterval() time.Duration {
 	return time.Duration(c.CleanupInterval) * time.Hour
 }
 
+// GetPollInterval returns the interval between change list polls
+func (c *ChangesConfig) GetPollInterval() time.Duration {
+	return time.Duration(c.IntervalHours) * time.Hour
+}
+
 // GetInitialRetryDelay returns the initial retry delay duration
 func (c *ReliabilityConfig) GetInitialRetryDelay() time.Duration {
 	return time.Duration(c.InitialDelaySeconds) * time.Second
@@ -199,8 +238,11 @@ func (c *Config) Validate() error {
 	}
 
 	// Validate matching configuration
-	if c.Matching.MatchThreshold < 0 || c.Matching.MatchThreshold > 1 {
-		return fmt.Errorf("match threshold must be between 0 and 1")
+	if c.Matching.AutoThreshold < 0 || c.Matching.AutoThreshold > 1 {
+		return fmt.Errorf("auto threshold must be between 0 and 1")
+	}
+	if c.Matching.ReviewThreshold < 0 || c.Matching.ReviewThreshold > c.Matching.AutoThreshold {
+		return fmt.Errorf("review threshold must be between 0 and the auto threshold")
 	}
 
 	// Validate cache configuration
@@ -208,6 +250,16 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("cache duration must be positive")
 	}
 
+	// Validate change polling configuration
+	if c.Changes.Enabled && c.Changes.IntervalHours <= 0 {
+		return fmt.Errorf("change poll interval must be positive")
+	}
+
+	// Validate people configuration
+	if c.People.MaxCast < 0 || c.People.DetailsLimit < 0 {
+		return fmt.Errorf("people limits must be non-negative")
+	}
+
 	// Validate reliability configuration
 	if c.Reliability.MaxRetries < 0 {
 		return fmt.Errorf("max retries must be non-negative")
@@ -362,6 +362,60 @@ func (c *TMDbConfigurationService) createTMDbSchema() *plugins.ConfigurationSche
 					},
 				},
 			},
+			"changes": map[string]interface{}{
+				"type":        "object",
+				"description": "TMDb change list polling",
+				"properties": map[string]interface{}{
+					"enabled": map[string]interface{}{
+						"type":        "boolean",
+						"description": "Re-fetch library items TMDb reports changed",
+						"default":     true,
+					},
+					"interval_hours": map[string]interface{}{
+						"type":        "integer",
+						"description": "Hours between polls",
+						"minimum":     1,
+						"maximum":     336, // TMDb change lists span at most 14 days per request
+						"default":     24,
+					},
+				},
+			},
+			"people": map[string]interface{}{
+				"type":        "object",
+				"description": "Cast and crew of matched movies and episodes",
+				"properties": map[string]interface{}{
+					"enabled": map[string]interface{}{
+						"type":        "boolean",
+						"description": "Record cast and crew through the host",
+						"default":     true,
+					},
+					"max_cast": map[string]interface{}{
+						"type":        "integer",
+						"description": "Top-billed cast members recorded per item",
+						"minimum":     0,
+						"maximum":     200,
+						"default":     20,
+					},
+					"details_limit": map[string]interface{}{
+						"type":        "integer",
+						"description": "Credited people per item whose biography and dates are fetched",
+						"minimum":     0,
+						"maximum":     100,
+						"default":     10,
+					},
+					"download_profiles": map[string]interface{}{
+						"type":        "boolean",
+						"description": "Have the host download profile images",
+						"default":     true,
+					},
+					"profile_size": map[string]interface{}{
+						"type":        "string",
+						"description": "TMDb profile image size",
+						"enum":        []string{"w45", "w185", "h632", "original"},
+						"default":     "w185",
+					},
+				},
+			},
 		},
 		Examples: map[string]interface{}{
 			"minimal": map[string]interface{}{
@@ -3,6 +3,8 @@ package models
 import (
 	"fmt"
 	"time"
+
+	"gorm.io/gorm"
 )
 
 // TMDbCache represents cached API responses from TMDb
@@ -43,6 +45,17 @@ type TMDbEnrichment struct {
 	EpisodeNumber *int `json:"episode_number,omitempty"`
 	ShowTMDbID    *int `json:"show_tmdb_id,omitempty"` // For episodes, reference to show
 
+	// Episode details, set when a TV file was matched to its episode
+	EpisodeTitle    string     `json:"episode_title,omitempty"`
+	EpisodeOverview string     `gorm:"type:text" json:"episode_overview,omitempty"`
+	EpisodeAirDate  *time.Time `json:"episode_air_date,omitempty"`
+	EpisodeStillURL string     `json:"episode_still_url,omitempty"`
+
+	// Collection the movie belongs to, such as a franchise
+	CollectionID         *int   `json:"collection_id,omitempty"`
+	CollectionName       string `json:"collection_name,omitempty"`
+	CollectionPosterPath string `json:"collection_poster_path,omitempty"`
+
 	// Additional metadata (stored as JSON for flexibility)
 	Genres      string `gorm:"type:text" json:"genres,omitempty"`       // JSON array of genres
 	Cast        string `gorm:"type:text" json:"cast,omitempty"`         // JSON array of cast members
@@ -77,6 +90,39 @@ func (e *TMDbEnrichment) IsMovie() bool {
 	return e.TMDbType == "movie"
 }
 
+// TMDbChangeSync records how far TMDb's change list for a media type has
+// been polled
+type TMDbChangeSync struct {
+	MediaType     string    `gorm:"primaryKey" json:"media_type"`     // movie or tv
+	PolledThrough time.Time `gorm:"not null" json:"polled_through"`   // Changes up to this time were applied
+	UpdatedAt     time.Time `gorm:"autoUpdateTime" json:"updated_at"` // Last poll
+}
+
+// TableName returns the table name for TMDbChangeSync
+func (TMDbChangeSync) TableName() string {
+	return "tmdb_change_sync"
+}
+
+// TMDbReleaseAlias records the movie or show a release name was matched to,
+// so later scans of the name reuse the match instead of searching again.
+// Keys are normalized file names, or titles for every file of a title.
+type TMDbReleaseAlias struct {
+	ID         uint32     `gorm:"primaryKey" json:"id"`
+	AliasKey   string     `gorm:"uniqueIndex;not null" json:"alias_key"` // release:<name> or title:<type>:<title>:<year>
+	TMDbID     int        `gorm:"not null;index" json:"tmdb_id"`
+	TMDbType   string     `gorm:"not null" json:"tmdb_type"` // movie or tv
+	Source     string     `gorm:"not null" json:"source"`    // auto, or identified by a user
+	Hits       int        `json:"hits"`                      // Scans that reused the alias
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	CreatedAt  time.Time  `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt  time.Time  `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// TableName returns the table name for TMDbReleaseAlias
+func (TMDbReleaseAlias) TableName() string {
+	return "tmdb_release_aliases"
+}
+
 // TMDbArtwork represents artwork metadata downloaded from TMDb
 type TMDbArtwork struct {
 	ID          uint32 `gorm:"primaryKey" json:"id"`
@@ -174,3 +220,22 @@ func (a *TMDbArtwork) GetFileSizeString() string {
 		return fmt.Sprintf("%d bytes", a.FileSize)
 	}
 }
+
+// Migrate creates or updates the plugin's tables. A file has one enrichment;
+// duplicates left by concurrent scans are removed, keeping the newest, before
+// the unique key on media_file_id is added.
+func Migrate(db *gorm.DB) error {
+	if err := db.AutoMigrate(&TMDbCache{}, &TMDbEnrichment{}, &TMDbArtwork{}, &TMDbChangeSync{}, &TMDbReleaseAlias{}); err != nil {
+		return err
+	}
+
+	if err := db.Exec(`DELETE FROM tmdb_enrichments WHERE id NOT IN (
+		SELECT MAX(id) FROM tmdb_enrichments GROUP BY media_file_id
+	)`).Error; err != nil {
+		return fmt.Errorf("failed to remove duplicate enrichments: %w", err)
+	}
+	if err := db.Exec("CREATE UNIQUE INDEX IF NOT EXISTS idx_tmdb_enrichments_media_file_id_unique ON tmdb_enrichments(media_file_id)").Error; err != nil {
+		return fmt.Errorf("failed to add unique key on media_file_id: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,119 @@
+package services
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/mantonx/viewra/plugins/tmdb_enricher_v2/internal/models"
+	"github.com/mantonx/viewra/plugins/tmdb_enricher_v2/internal/types"
+	"gorm.io/gorm"
+)
+
+// Where a release alias came from. Aliases of matches a user identified by
+// hand aren't replaced by automatic matches.
+const (
+	AliasSourceAuto       = "auto"
+	AliasSourceIdentified = "identified"
+)
+
+// aliasKeys returns the alias keys of a file, most specific first: its file
+// name, then the title and year a scan searches for. The title key carries
+// the type the file's library gives it, so a movie and a show sharing a
+// title in different libraries don't share a match.
+func (s *EnrichmentService) aliasKeys(filePath, title string, year int, metadata map[string]string) []string {
+	var keys []string
+	name := strings.TrimSuffix(filepath.Base(filePath), filepath.Ext(filePath))
+	if release := normalizeAlias(name); release != "" {
+		keys = append(keys, "release:"+release)
+	}
+	if normalized := normalizeAlias(title); normalized != "" {
+		keys = append(keys, fmt.Sprintf("title:%s:%s:%d", s.classifiedType(metadata), normalized, year))
+	}
+	return keys
+}
+
+// normalizeAlias lowercases a name and reduces its punctuation and
+// separators to single spaces, so "Show.Name_2019" and "show name 2019"
+// share a key
+func normalizeAlias(name string) string {
+	return strings.Join(strings.FieldsFunc(strings.ToLower(name), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	}), " ")
+}
+
+// aliasedResult returns the movie or show the first known alias key was
+// matched to, or nil when none is known. Lookup failures are logged and
+// leave the file to a search.
+func (s *EnrichmentService) aliasedResult(keys []string) *types.Result {
+	if len(keys) == 0 {
+		return nil
+	}
+
+	var aliases []models.TMDbReleaseAlias
+	if err := s.db.Where("alias_key IN ?", keys).Find(&aliases).Error; err != nil {
+		s.logger.Warn("failed to look up release aliases", "error", err)
+		return nil
+	}
+	byKey := make(map[string]models.TMDbReleaseAlias, len(aliases))
+	for _, alias := range aliases {
+		byKey[alias.AliasKey] = alias
+	}
+
+	for _, key := range keys {
+		alias, ok := byKey[key]
+		if !ok {
+			continue
+		}
+		result, err := s.lookupResult(alias.TMDbID, alias.TMDbType)
+		if err != nil {
+			s.logger.Warn("failed to fetch aliased TMDb result", "error", err, "alias", key, "tmdb_id", alias.TMDbID)
+			return nil
+		}
+
+		now := time.Now()
+		if err := s.db.Model(&models.TMDbReleaseAlias{}).Where("id = ?", alias.ID).Updates(map[string]interface{}{
+			"hits":         gorm.Expr("hits + 1"),
+			"last_used_at": now,
+		}).Error; err != nil {
+			s.logger.Warn("failed to record release alias use", "error", err, "alias", key)
+		}
+		s.logger.Debug("matched by release alias", "alias", key, "tmdb_id", alias.TMDbID, "source", alias.Source)
+		return result
+	}
+	return nil
+}
+
+// recordAliases remembers that the alias keys matched result. Automatic
+// matches leave aliases a user identified alone.
+func (s *EnrichmentService) recordAliases(keys []string, result *types.Result, source string) {
+	mediaType := s.resultMediaType(*result)
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		for _, key := range keys {
+			var alias models.TMDbReleaseAlias
+			if err := tx.Where("alias_key = ?", key).Limit(1).Find(&alias).Error; err != nil {
+				return err
+			}
+			if alias.Source == AliasSourceIdentified && source != AliasSourceIdentified {
+				continue
+			}
+			if alias.TMDbID == result.ID && alias.TMDbType == mediaType && alias.Source == source {
+				continue
+			}
+
+			alias.AliasKey = key
+			alias.TMDbID = result.ID
+			alias.TMDbType = mediaType
+			alias.Source = source
+			if err := tx.Save(&alias).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		s.logger.Warn("failed to record release aliases", "error", err, "tmdb_id", result.ID)
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"io"
 	"net/http"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"time"
 
@@ -14,6 +15,8 @@ import (
 	"github.com/mantonx/viewra/plugins/tmdb_enricher_v2/internal/models"
 	"github.com/mantonx/viewra/plugins/tmdb_enricher_v2/internal/types"
 	plugins "github.com/mantonx/viewra/sdk"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	"gorm.io/gorm"
 )
 
@@ -34,13 +37,19 @@ func NewArtworkService(db *gorm.DB, cfg *config.Config, client *plugins.UnifiedS
 		config:        cfg,
 		unifiedClient: client,
 		logger:        logger,
-		httpClient: &http.Client{
-			Timeout: time.Duration(cfg.Artwork.AssetTimeoutSec) * time.Second,
-		},
-		apiClient: api.NewAPIClient(cfg, logger),
+		httpClient:    plugins.NewProviderHTTPClient(time.Duration(cfg.Artwork.AssetTimeoutSec) * time.Second),
+		apiClient:     api.NewAPIClient(cfg, logger),
 	}
 }
 
+// SetPerformanceMonitor records the service's image downloads and TMDb
+// requests in monitor. Images come from TMDb's image CDN, so they are
+// recorded as a provider of their own.
+func (a *ArtworkService) SetPerformanceMonitor(monitor *plugins.BasePerformanceMonitor) {
+	plugins.InstrumentProviderClient(a.httpClient, monitor, "tmdb_images")
+	a.apiClient.SetPerformanceMonitor(monitor)
+}
+
 // DownloadArtworkForEnrichment downloads artwork for a TMDb enrichment
 func (a *ArtworkService) DownloadArtworkForEnrichment(mediaFileID string, enrichment *models.TMDbEnrichment) error {
 	if !a.config.Features.EnableArtwork {
@@ -60,6 +69,13 @@ func (a *ArtworkService) DownloadArtworkForEnrichment(mediaFileID string, enrich
 			a.logger.Warn("failed to download movie artwork", "error", err)
 			errors = append(errors, fmt.Sprintf("movie artwork: %v", err))
 		}
+		if enrichment.CollectionID != nil && a.config.Artwork.DownloadPosters {
+			if err := a.downloadCollectionPoster(mediaFileID, enrichment); err != nil {
+				errors = append(errors, fmt.Sprintf("collection poster: %v", err))
+			} else {
+				downloadCount++
+			}
+		}
 
 	case "tv":
 		if err := a.downloadTVShowArtwork(mediaFileID, enrichment.TMDbID, &downloadCount, &errors); err != nil {
@@ -68,8 +84,14 @@ func (a *ArtworkService) DownloadArtworkForEnrichment(mediaFileID string, enrich
 		}
 
 	case "episode":
+		if enrichment.ShowTMDbID != nil {
+			if err := a.downloadTVShowArtwork(mediaFileID, *enrichment.ShowTMDbID, &downloadCount, &errors); err != nil {
+				a.logger.Warn("failed to download TV show artwork", "error", err)
+				errors = append(errors, fmt.Sprintf("TV show artwork: %v", err))
+			}
+		}
 		if enrichment.ShowTMDbID != nil && enrichment.SeasonNumber != nil && enrichment.EpisodeNumber != nil {
-			if err := a.downloadEpisodeArtwork(mediaFileID, *enrichment.ShowTMDbID, *enrichment.SeasonNumber, *enrichment.EpisodeNumber, &downloadCount, &errors); err != nil {
+			if err := a.downloadEpisodeArtwork(mediaFileID, *enrichment.ShowTMDbID, *enrichment.SeasonNumber, *enrichment.EpisodeNumber, enrichment.EpisodeStillURL, &downloadCount, &errors); err != nil {
 				a.logger.Warn("failed to download episode artwork", "error", err)
 				errors = append(errors, fmt.Sprintf("episode artwork: %v", err))
 			}
@@ -124,20 +146,15 @@ func (a *ArtworkService) downloadTVShowArtwork(mediaFileID string, tmdbID int, s
 	return nil
 }
 
-// downloadEpisodeArtwork downloads artwork for episodes
-func (a *ArtworkService) downloadEpisodeArtwork(mediaFileID string, tmdbID, seasonNumber, episodeNumber int, successCount *int, errors *[]string) error {
-	// Download season poster if enabled
-	if a.config.Artwork.DownloadSeasonPosters {
-		if err := a.downloadSeasonPoster(mediaFileID, tmdbID, seasonNumber); err != nil {
-			*errors = append(*errors, fmt.Sprintf("season poster: %v", err))
-		} else {
-			*successCount++
-		}
-	}
-
+// downloadEpisodeArtwork downloads artwork for episodes. stillURL is the
+// still found during enrichment; when empty the episode is fetched again.
+//
+// Season posters aren't downloaded here: the host files season artwork saved
+// for an episode file under its show, where it would replace the show poster.
+func (a *ArtworkService) downloadEpisodeArtwork(mediaFileID string, tmdbID, seasonNumber, episodeNumber int, stillURL string, successCount *int, errors *[]string) error {
 	// Download episode still if enabled
 	if a.config.Artwork.DownloadEpisodeStills {
-		if err := a.downloadEpisodeStill(mediaFileID, tmdbID, seasonNumber, episodeNumber); err != nil {
+		if err := a.downloadEpisodeStill(mediaFileID, tmdbID, seasonNumber, episodeNumber, stillURL); err != nil {
 			*errors = append(*errors, fmt.Sprintf("episode still: %v", err))
 		} else {
 			*successCount++
@@ -157,7 +174,7 @@ func (a *ArtworkService) downloadBestImage(mediaFileID, category, artworkType, s
 	image := &images[0]
 	imageURL := a.buildImageURL(image.FilePath, artworkType)
 
-	return a.downloadAndSaveImage(mediaFileID, category, artworkType, subtype, imageURL, image)
+	return a.downloadAndSaveImage(mediaFileID, category, artworkType, subtype, imageURL, image, nil)
 }
 
 // downloadSeasonPoster downloads a poster for a TV season
@@ -172,26 +189,53 @@ func (a *ArtworkService) downloadSeasonPoster(mediaFileID string, tmdbID, season
 	}
 
 	imageURL := a.buildImageURL(season.PosterPath, "poster")
-	return a.downloadAndSaveImage(mediaFileID, "season", "poster", fmt.Sprintf("season_%d", seasonNumber), imageURL, nil)
+	return a.downloadAndSaveImage(mediaFileID, "season", "poster", fmt.Sprintf("season_%d", seasonNumber), imageURL, nil, nil)
 }
 
 // downloadEpisodeStill downloads a still image for an episode
-func (a *ArtworkService) downloadEpisodeStill(mediaFileID string, tmdbID, seasonNumber, episodeNumber int) error {
-	episode, err := a.fetchEpisodeDetails(tmdbID, seasonNumber, episodeNumber)
-	if err != nil {
-		return fmt.Errorf("failed to fetch episode details: %w", err)
+func (a *ArtworkService) downloadEpisodeStill(mediaFileID string, tmdbID, seasonNumber, episodeNumber int, imageURL string) error {
+	if imageURL == "" {
+		episode, err := a.fetchEpisodeDetails(tmdbID, seasonNumber, episodeNumber)
+		if err != nil {
+			return fmt.Errorf("failed to fetch episode details: %w", err)
+		}
+
+		if episode.StillPath == "" {
+			return fmt.Errorf("no episode still available")
+		}
+		imageURL = a.buildImageURL(episode.StillPath, "still")
+	}
+
+	return a.downloadAndSaveImage(mediaFileID, "episode", "still", fmt.Sprintf("s%de%d", seasonNumber, episodeNumber), imageURL, nil, nil)
+}
+
+// downloadCollectionPoster saves the poster of the collection a movie belongs
+// to. Every movie of a collection shares it, so it is downloaded only once.
+func (a *ArtworkService) downloadCollectionPoster(mediaFileID string, enrichment *models.TMDbEnrichment) error {
+	if enrichment.CollectionPosterPath == "" {
+		return nil
 	}
+	imageURL := a.buildImageURL(enrichment.CollectionPosterPath, "poster")
 
-	if episode.StillPath == "" {
-		return fmt.Errorf("no episode still available")
+	var count int64
+	if err := a.db.Model(&models.TMDbArtwork{}).
+		Where("category = ? AND original_url = ?", "collection", imageURL).
+		Count(&count).Error; err != nil {
+		a.logger.Warn("failed to check if collection poster exists", "error", err)
+	} else if count > 0 {
+		return nil
 	}
 
-	imageURL := a.buildImageURL(episode.StillPath, "still")
-	return a.downloadAndSaveImage(mediaFileID, "episode", "still", fmt.Sprintf("s%de%d", seasonNumber, episodeNumber), imageURL, nil)
+	return a.downloadAndSaveImage(mediaFileID, "collection", "poster", "", imageURL, nil, map[string]string{
+		"tmdb_collection_id": strconv.Itoa(*enrichment.CollectionID),
+		"collection_name":    enrichment.CollectionName,
+	})
 }
 
-// downloadAndSaveImage downloads an image and saves it via the unified service
-func (a *ArtworkService) downloadAndSaveImage(mediaFileID, category, artworkType, subtype, imageURL string, imageInfo *types.ImageInfo) error {
+// downloadAndSaveImage downloads an image and saves it via the unified
+// service. extraMetadata is sent along with the image details, such as the
+// collection a collection poster belongs to.
+func (a *ArtworkService) downloadAndSaveImage(mediaFileID, category, artworkType, subtype, imageURL string, imageInfo *types.ImageInfo, extraMetadata map[string]string) error {
 	// Check if artwork already exists
 	if a.config.Artwork.SkipExistingAssets {
 		if exists, err := a.artworkExists(mediaFileID, category, artworkType, subtype, imageURL); err != nil {
@@ -202,26 +246,131 @@ func (a *ArtworkService) downloadAndSaveImage(mediaFileID, category, artworkType
 		}
 	}
 
-	// Download the image
+	if a.unifiedClient == nil {
+		return fmt.Errorf("unified client not available")
+	}
+
+	// Create metadata
+	metadata := map[string]string{
+		"source":       "tmdb",
+		"tmdb_url":     imageURL,
+		"artwork_type": artworkType,
+	}
+	if imageInfo != nil {
+		metadata["width"] = fmt.Sprintf("%d", imageInfo.Width)
+		metadata["height"] = fmt.Sprintf("%d", imageInfo.Height)
+		metadata["aspect_ratio"] = fmt.Sprintf("%.2f", imageInfo.AspectRatio)
+		metadata["vote_average"] = fmt.Sprintf("%.1f", imageInfo.VoteAverage)
+		metadata["vote_count"] = fmt.Sprintf("%d", imageInfo.VoteCount)
+		if imageInfo.ISO639_1 != "" {
+			metadata["language"] = imageInfo.ISO639_1
+		}
+	}
+	for key, value := range extraMetadata {
+		metadata[key] = value
+	}
+
+	// The host downloads through its shared pool, which caps downloads across
+	// plugins and retries failures. Hosts without one get the image from us.
+	response, err := a.downloadViaHost(mediaFileID, category, artworkType, imageURL, metadata)
+	if status.Code(err) == codes.Unimplemented {
+		response, err = a.downloadDirect(mediaFileID, category, artworkType, imageURL, metadata)
+	}
+	if err != nil {
+		return err
+	}
+
+	// Record in our database
+	artwork := &models.TMDbArtwork{
+		MediaFileID:  mediaFileID,
+		TMDbID:       0, // We'd need to pass this from the caller
+		ArtworkType:  artworkType,
+		Category:     category,
+		Subtype:      subtype,
+		OriginalURL:  imageURL,
+		LocalPath:    response.RelativePath,
+		FileName:     filepath.Base(imageURL),
+		MimeType:     response.MimeType,
+		FileSize:     response.Size,
+		FileHash:     response.Hash,
+		SourcePlugin: "tmdb_enricher_v2",
+	}
+	// Season posters and episode stills come without image details
+	if imageInfo != nil {
+		artwork.Width = imageInfo.Width
+		artwork.Height = imageInfo.Height
+		artwork.AspectRatio = imageInfo.AspectRatio
+		artwork.Language = imageInfo.ISO639_1
+		artwork.VoteAverage = imageInfo.VoteAverage
+		artwork.VoteCount = imageInfo.VoteCount
+	}
+
+	if err := a.db.Create(artwork).Error; err != nil {
+		a.logger.Warn("failed to record artwork in database", "error", err, "asset_id", response.AssetID)
+	}
+
+	a.logger.Info("artwork saved successfully",
+		"asset_id", response.AssetID,
+		"path", response.RelativePath,
+		"hash", response.Hash)
+
+	return nil
+}
+
+// downloadViaHost has the host download and save an image
+func (a *ArtworkService) downloadViaHost(mediaFileID, category, artworkType, imageURL string, metadata map[string]string) (*plugins.DownloadAssetResponse, error) {
+	// The host may queue the download behind other plugins' and retry it, so
+	// allow it several attempts' worth of time
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(a.config.Artwork.AssetTimeoutSec)*time.Second*4)
+	defer cancel()
+
+	response, err := a.unifiedClient.AssetService().DownloadAsset(ctx, &plugins.DownloadAssetRequest{
+		URL:         imageURL,
+		MediaFileID: mediaFileID,
+		AssetType:   category,
+		Category:    category,
+		Subtype:     artworkType,
+		PluginID:    "tmdb_enricher_v2",
+		Metadata:    metadata,
+		MaxSize:     int64(a.config.Artwork.MaxAssetSizeMB) * 1024 * 1024,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !response.Success {
+		if response.StatusCode != 0 && response.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("HTTP error %d downloading image", response.StatusCode)
+		}
+		return nil, fmt.Errorf("asset download failed: %s", response.Error)
+	}
+	return response, nil
+}
+
+// downloadDirect downloads an image itself and saves it via the unified
+// service, for hosts without a download pool
+func (a *ArtworkService) downloadDirect(mediaFileID, category, artworkType, imageURL string, metadata map[string]string) (*plugins.DownloadAssetResponse, error) {
 	resp, err := a.httpClient.Get(imageURL)
 	if err != nil {
-		return fmt.Errorf("failed to download image: %w", err)
+		return nil, fmt.Errorf("failed to download image: %w", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("HTTP error %d downloading image", resp.StatusCode)
+		return nil, fmt.Errorf("HTTP error %d downloading image", resp.StatusCode)
 	}
 
 	// Read the image data
 	data, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return fmt.Errorf("failed to read image data: %w", err)
+		return nil, fmt.Errorf("failed to read image data: %w", err)
 	}
 
 	// Check size limits
 	if len(data) > a.config.Artwork.MaxAssetSizeMB*1024*1024 {
-		return fmt.Errorf("image too large: %d bytes > %d MB limit", len(data), a.config.Artwork.MaxAssetSizeMB)
+		return nil, fmt.Errorf("image too large: %d bytes > %d MB limit", len(data), a.config.Artwork.MaxAssetSizeMB)
+	}
+	if len(data) > a.unifiedClient.MaxAssetSize() {
+		return nil, fmt.Errorf("image too large: %d bytes > %d bytes host limit", len(data), a.unifiedClient.MaxAssetSize())
 	}
 
 	// Determine MIME type
@@ -241,90 +390,42 @@ func (a *ArtworkService) downloadAndSaveImage(mediaFileID, category, artworkType
 		}
 	}
 
-	// Create metadata
-	metadata := map[string]string{
-		"source":       "tmdb",
-		"tmdb_url":     imageURL,
-		"artwork_type": artworkType,
-	}
-	if imageInfo != nil {
-		metadata["width"] = fmt.Sprintf("%d", imageInfo.Width)
-		metadata["height"] = fmt.Sprintf("%d", imageInfo.Height)
-		metadata["aspect_ratio"] = fmt.Sprintf("%.2f", imageInfo.AspectRatio)
-		metadata["vote_average"] = fmt.Sprintf("%.1f", imageInfo.VoteAverage)
-		metadata["vote_count"] = fmt.Sprintf("%d", imageInfo.VoteCount)
-		if imageInfo.ISO639_1 != "" {
-			metadata["language"] = imageInfo.ISO639_1
-		}
-	}
-
 	a.logger.Debug("saving artwork via unified service",
 		"media_file_id", mediaFileID,
 		"category", category,
 		"artwork_type", artworkType,
-		"subtype", subtype,
 		"size", len(data),
 		"mime_type", mimeType)
 
-	// Save via unified service
-	if a.unifiedClient != nil {
-		request := &plugins.SaveAssetRequest{
-			MediaFileID: mediaFileID,
-			AssetType:   category,
-			Category:    category,
-			Subtype:     artworkType,
-			Data:        data,
-			MimeType:    "image/jpeg",
-			SourceURL:   imageURL,
-			PluginID:    "tmdb_enricher_v2",
-			Metadata:    metadata,
-		}
-
-		ctx := context.Background()
-		response, err := a.unifiedClient.AssetService().SaveAsset(ctx, request)
-		if err != nil {
-			return fmt.Errorf("failed to save asset via unified service: %w", err)
-		}
-
-		if !response.Success {
-			return fmt.Errorf("asset save failed: %s", response.Error)
-		}
-
-		// Record in our database
-		artwork := &models.TMDbArtwork{
-			MediaFileID:  mediaFileID,
-			TMDbID:       0, // We'd need to pass this from the caller
-			ArtworkType:  artworkType,
-			Category:     category,
-			Subtype:      subtype,
-			OriginalURL:  imageURL,
-			LocalPath:    response.RelativePath,
-			FileName:     filepath.Base(imageURL),
-			MimeType:     mimeType,
-			FileSize:     int64(len(data)),
-			FileHash:     response.Hash,
-			Width:        imageInfo.Width,
-			Height:       imageInfo.Height,
-			AspectRatio:  imageInfo.AspectRatio,
-			Language:     imageInfo.ISO639_1,
-			VoteAverage:  imageInfo.VoteAverage,
-			VoteCount:    imageInfo.VoteCount,
-			SourcePlugin: "tmdb_enricher_v2",
-		}
-
-		if err := a.db.Create(artwork).Error; err != nil {
-			a.logger.Warn("failed to record artwork in database", "error", err, "asset_id", response.AssetID)
-		}
-
-		a.logger.Info("artwork saved successfully",
-			"asset_id", response.AssetID,
-			"path", response.RelativePath,
-			"hash", response.Hash)
+	request := &plugins.SaveAssetRequest{
+		MediaFileID: mediaFileID,
+		AssetType:   category,
+		Category:    category,
+		Subtype:     artworkType,
+		Data:        data,
+		MimeType:    "image/jpeg",
+		SourceURL:   imageURL,
+		PluginID:    "tmdb_enricher_v2",
+		Metadata:    metadata,
+	}
 
-		return nil
+	response, err := a.unifiedClient.AssetService().SaveAsset(context.Background(), request)
+	if err != nil {
+		return nil, fmt.Errorf("failed to save asset via unified service: %w", err)
+	}
+	if !response.Success {
+		return nil, fmt.Errorf("asset save failed: %s", response.Error)
 	}
 
-	return fmt.Errorf("unified client not available")
+	return &plugins.DownloadAssetResponse{
+		Success:      true,
+		AssetID:      response.AssetID,
+		Hash:         response.Hash,
+		RelativePath: response.RelativePath,
+		Size:         int64(len(data)),
+		MimeType:     mimeType,
+		StatusCode:   resp.StatusCode,
+	}, nil
 }
 
 // buildImageURL builds a full TMDb image URL
@@ -0,0 +1,61 @@
+package services
+
+import (
+	"fmt"
+
+	"github.com/mantonx/viewra/plugins/tmdb_enricher_v2/internal/types"
+)
+
+// fallbackRatingCountry is used when a title has no rating in the
+// configured region
+const fallbackRatingCountry = "US"
+
+// fetchContentRatings returns a movie's certifications or a show's content
+// ratings by country, from the cache when possible
+func (s *EnrichmentService) fetchContentRatings(mediaType string, tmdbID int) (map[string]string, error) {
+	queryHash := s.generateQueryHash(fmt.Sprintf("ratings:%s:%d", mediaType, tmdbID))
+	ratings := make(map[string]string)
+	if err := s.getCachedJSON("ratings", queryHash, &ratings); err == nil {
+		return ratings, nil
+	}
+
+	if mediaType == "tv" {
+		var response types.ContentRatingsResponse
+		url := fmt.Sprintf("https://api.themoviedb.org/3/tv/%d/content_ratings", tmdbID)
+		if err := s.makeAPIRequestWithRetries(url, &response, fmt.Sprintf("content ratings of show %d", tmdbID)); err != nil {
+			return nil, err
+		}
+		for _, result := range response.Results {
+			if result.Rating != "" {
+				ratings[result.Country] = result.Rating
+			}
+		}
+	} else {
+		var response types.ReleaseDatesResponse
+		url := fmt.Sprintf("https://api.themoviedb.org/3/movie/%d/release_dates", tmdbID)
+		if err := s.makeAPIRequestWithRetries(url, &response, fmt.Sprintf("release dates of movie %d", tmdbID)); err != nil {
+			return nil, err
+		}
+		for _, result := range response.Results {
+			// The first certified release of a country, usually the theatrical one
+			for _, release := range result.ReleaseDates {
+				if release.Certification != "" {
+					ratings[result.Country] = release.Certification
+					break
+				}
+			}
+		}
+	}
+
+	s.cacheJSON("ratings", queryHash, ratings)
+	return ratings, nil
+}
+
+// contentRating picks the rating for the configured region, falling back to
+// the United States
+func (s *EnrichmentService) contentRating(ratings map[string]string) string {
+	if rating := ratings[s.config.API.Region]; rating != "" {
+		return rating
+	}
+	return ratings[fallbackRatingCountry]
+}
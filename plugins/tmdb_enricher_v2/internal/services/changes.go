@@ -0,0 +1,257 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/mantonx/viewra/plugins/tmdb_enricher_v2/internal/models"
+	"github.com/mantonx/viewra/plugins/tmdb_enricher_v2/internal/types"
+	"gorm.io/gorm"
+)
+
+// changesWindow is the longest date range TMDb's change lists accept
+const changesWindow = 14 * 24 * time.Hour
+
+// StartChangePolling keeps enriched movies and shows current by polling
+// TMDb's change lists and re-fetching only the library items that changed,
+// instead of refreshing the whole library. Polls run at startup and then on
+// the configured interval until ctx is done.
+func (s *EnrichmentService) StartChangePolling(ctx context.Context) {
+	interval := s.config.Changes.GetPollInterval()
+	if !s.config.Changes.Enabled || interval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	s.logger.Info("change polling started", "interval", interval.String())
+
+	for {
+		if refreshed, err := s.PollChanges(); err != nil {
+			s.logger.Warn("failed to poll TMDb changes", "error", err)
+		} else if refreshed > 0 {
+			s.logger.Info("refreshed changed items", "count", refreshed)
+		}
+
+		select {
+		case <-ctx.Done():
+			s.logger.Info("change polling stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// PollChanges re-fetches the movies and shows in the library that TMDb lists
+// as changed since the last poll and returns how many were refreshed. Their
+// cast and crew are re-fetched with them; people's own changes aren't polled.
+func (s *EnrichmentService) PollChanges() (int, error) {
+	if !s.config.Changes.Enabled {
+		return 0, nil
+	}
+
+	now := time.Now().UTC()
+	refreshed := 0
+	for _, mediaType := range []string{"movie", "tv"} {
+		if mediaType == "movie" && !s.config.Features.EnableMovies {
+			continue
+		}
+		if mediaType == "tv" && !s.config.Features.EnableTVShows {
+			continue
+		}
+
+		n, err := s.pollChanges(mediaType, now)
+		refreshed += n
+		if err != nil {
+			return refreshed, fmt.Errorf("failed to poll %s changes: %w", mediaType, err)
+		}
+	}
+	return refreshed, nil
+}
+
+// pollChanges applies one media type's changes up to now. The first poll
+// only records where to start: everything in the library was fetched fresh
+// when it was enriched. The polled position only advances once the change
+// lists were read, so a failed poll is retried from the same point.
+func (s *EnrichmentService) pollChanges(mediaType string, now time.Time) (int, error) {
+	var sync models.TMDbChangeSync
+	err := s.db.Where("media_type = ?", mediaType).First(&sync).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return 0, s.db.Create(&models.TMDbChangeSync{MediaType: mediaType, PolledThrough: now}).Error
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	library, err := s.libraryIDs(mediaType)
+	if err != nil {
+		return 0, err
+	}
+
+	changed := make(map[int]bool)
+	if len(library) > 0 {
+		for start := sync.PolledThrough; start.Before(now); start = start.Add(changesWindow) {
+			end := start.Add(changesWindow)
+			if end.After(now) {
+				end = now
+			}
+			ids, err := s.fetchChangedIDs(mediaType, start, end)
+			if err != nil {
+				return 0, err
+			}
+			for _, id := range ids {
+				if library[id] {
+					changed[id] = true
+				}
+			}
+		}
+	}
+
+	refreshed := 0
+	for id := range changed {
+		// An item that fails, e.g. one removed from TMDb, is picked up again
+		// by its next change or the next scan
+		if err := s.refreshItem(mediaType, id); err != nil {
+			s.logger.Warn("failed to refresh changed item", "error", err, "tmdb_id", id, "type", mediaType)
+			continue
+		}
+		refreshed++
+	}
+
+	sync.PolledThrough = now
+	if err := s.db.Save(&sync).Error; err != nil {
+		return refreshed, err
+	}
+	return refreshed, nil
+}
+
+// fetchChangedIDs lists the IDs TMDb reports changed between start and end,
+// reading every page. Change lists are never cached.
+func (s *EnrichmentService) fetchChangedIDs(mediaType string, start, end time.Time) ([]int, error) {
+	var ids []int
+	for page := 1; ; page++ {
+		changesURL := fmt.Sprintf("https://api.themoviedb.org/3/%s/changes?start_date=%s&end_date=%s&page=%d",
+			mediaType, start.Format("2006-01-02"), end.Format("2006-01-02"), page)
+		var response types.ChangesResponse
+		if err := s.makeAPIRequestWithRetries(changesURL, &response, fmt.Sprintf("%s changes page %d", mediaType, page)); err != nil {
+			return nil, err
+		}
+		for _, item := range response.Results {
+			ids = append(ids, item.ID)
+		}
+		if page >= response.TotalPages {
+			return ids, nil
+		}
+	}
+}
+
+// libraryIDs returns the TMDb IDs of the movies or shows in the library.
+// Episodes count towards their show.
+func (s *EnrichmentService) libraryIDs(mediaType string) (map[int]bool, error) {
+	var ids []int
+	if err := s.db.Model(&models.TMDbEnrichment{}).
+		Where("tmdb_type = ?", mediaType).
+		Distinct().Pluck("tmdb_id", &ids).Error; err != nil {
+		return nil, err
+	}
+	if mediaType == "tv" {
+		var showIDs []int
+		if err := s.db.Model(&models.TMDbEnrichment{}).
+			Where("tmdb_type = ? AND show_tmdb_id IS NOT NULL", "episode").
+			Distinct().Pluck("show_tmdb_id", &showIDs).Error; err != nil {
+			return nil, err
+		}
+		ids = append(ids, showIDs...)
+	}
+
+	library := make(map[int]bool, len(ids))
+	for _, id := range ids {
+		library[id] = true
+	}
+	return library, nil
+}
+
+// refreshItem re-fetches a changed movie or show, dropping its cached
+// responses first, and updates the enrichment of every file matched to it
+func (s *EnrichmentService) refreshItem(mediaType string, tmdbID int) error {
+	var enrichments []models.TMDbEnrichment
+	query := s.db.Where("tmdb_type = ? AND tmdb_id = ?", mediaType, tmdbID)
+	if mediaType == "tv" {
+		query = s.db.Where("(tmdb_type = ? AND tmdb_id = ?) OR (tmdb_type = ? AND show_tmdb_id = ?)", "tv", tmdbID, "episode", tmdbID)
+	}
+	if err := query.Find(&enrichments).Error; err != nil {
+		return fmt.Errorf("failed to find enrichments: %w", err)
+	}
+	if len(enrichments) == 0 {
+		return nil
+	}
+
+	if err := s.forgetCached(mediaType, tmdbID, enrichments); err != nil {
+		return err
+	}
+
+	result, err := s.lookupResult(tmdbID, mediaType)
+	if err != nil {
+		return err
+	}
+
+	for _, enrichment := range enrichments {
+		var episode *episodeMatch
+		if enrichment.IsEpisode() {
+			if enrichment.SeasonNumber == nil || enrichment.EpisodeNumber == nil {
+				continue
+			}
+			details, err := s.fetchEpisode(tmdbID, *enrichment.SeasonNumber, *enrichment.EpisodeNumber)
+			if err != nil {
+				// Keep the file's episode enrichment rather than saving it as the show
+				s.logger.Warn("failed to refresh episode", "error", err, "media_file_id", enrichment.MediaFileID,
+					"season", *enrichment.SeasonNumber, "episode", *enrichment.EpisodeNumber)
+				continue
+			}
+			episode = &episodeMatch{
+				SeasonNumber:  *enrichment.SeasonNumber,
+				EpisodeNumber: *enrichment.EpisodeNumber,
+				Details:       details,
+			}
+		}
+		if err := s.saveEnrichment(enrichment.MediaFileID, result, episode); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// forgetCached deletes the cached responses of a changed movie or show, and
+// of the seasons and episodes its files were matched to, with their credits
+func (s *EnrichmentService) forgetCached(mediaType string, tmdbID int, enrichments []models.TMDbEnrichment) error {
+	hashes := []string{s.generateQueryHash(fmt.Sprintf("details:%s:%d", mediaType, tmdbID))}
+	if mediaType == "movie" {
+		hashes = append(hashes, s.generateQueryHash(fmt.Sprintf("credits:movie:%d", tmdbID)))
+	}
+	if mediaType == "tv" {
+		hashes = append(hashes, s.generateQueryHash(fmt.Sprintf("show:%d", tmdbID)))
+		seasons := make(map[int]bool)
+		for _, enrichment := range enrichments {
+			if !enrichment.IsEpisode() || enrichment.SeasonNumber == nil || enrichment.EpisodeNumber == nil {
+				continue
+			}
+			season := *enrichment.SeasonNumber
+			if !seasons[season] {
+				seasons[season] = true
+				hashes = append(hashes, s.generateQueryHash(fmt.Sprintf("season:%d:%d", tmdbID, season)))
+			}
+			hashes = append(hashes,
+				s.generateQueryHash(fmt.Sprintf("episode:%d:%d:%d", tmdbID, season, *enrichment.EpisodeNumber)),
+				s.generateQueryHash(fmt.Sprintf("credits:episode:%d:%d:%d", tmdbID, season, *enrichment.EpisodeNumber)),
+			)
+		}
+	}
+
+	if err := s.db.Where("query_hash IN ?", hashes).Delete(&models.TMDbCache{}).Error; err != nil {
+		return fmt.Errorf("failed to clear cached responses: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,76 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/mantonx/viewra/plugins/tmdb_enricher_v2/internal/types"
+)
+
+// collectionEnrichment is the collection field registered for a movie
+type collectionEnrichment struct {
+	ID          int    `json:"id"`
+	Name        string `json:"name"`
+	Overview    string `json:"overview,omitempty"`
+	PosterURL   string `json:"poster_url,omitempty"`
+	BackdropURL string `json:"backdrop_url,omitempty"`
+	PartCount   int    `json:"part_count,omitempty"`
+}
+
+// fetchMovieCollection returns the collection a movie belongs to, or nil
+// when it belongs to none. Movie and collection details are cached.
+func (s *EnrichmentService) fetchMovieCollection(tmdbID int) (*types.CollectionDetails, error) {
+	movieHash := s.generateQueryHash(fmt.Sprintf("movie:%d", tmdbID))
+	var movie types.MovieDetails
+	if err := s.getCachedJSON("movie_details", movieHash, &movie); err != nil {
+		url := fmt.Sprintf("https://api.themoviedb.org/3/movie/%d", tmdbID)
+		if err := s.makeAPIRequestWithRetries(url, &movie, fmt.Sprintf("details of movie %d", tmdbID)); err != nil {
+			return nil, err
+		}
+		s.cacheJSON("movie_details", movieHash, movie)
+	}
+	if movie.BelongsToCollection == nil || movie.BelongsToCollection.ID == 0 {
+		return nil, nil
+	}
+
+	summary := movie.BelongsToCollection
+	collectionHash := s.generateQueryHash(fmt.Sprintf("collection:%d", summary.ID))
+	var collection types.CollectionDetails
+	if err := s.getCachedJSON("collection", collectionHash, &collection); err == nil {
+		return &collection, nil
+	}
+	url := fmt.Sprintf("https://api.themoviedb.org/3/collection/%d", summary.ID)
+	if err := s.makeAPIRequestWithRetries(url, &collection, fmt.Sprintf("collection %d", summary.ID)); err != nil {
+		// The movie's details name the collection well enough to group by
+		s.logger.Warn("failed to fetch collection details", "error", err, "collection_id", summary.ID)
+		return &types.CollectionDetails{
+			ID:           summary.ID,
+			Name:         summary.Name,
+			PosterPath:   summary.PosterPath,
+			BackdropPath: summary.BackdropPath,
+		}, nil
+	}
+	s.cacheJSON("collection", collectionHash, collection)
+	return &collection, nil
+}
+
+// collectionJSON encodes a collection as the collection enrichment field
+func (s *EnrichmentService) collectionJSON(collection *types.CollectionDetails) (string, error) {
+	enrichment := collectionEnrichment{
+		ID:        collection.ID,
+		Name:      collection.Name,
+		Overview:  collection.Overview,
+		PartCount: len(collection.Parts),
+	}
+	if collection.PosterPath != "" {
+		enrichment.PosterURL = fmt.Sprintf("https://image.tmdb.org/t/p/%s%s", s.config.Artwork.PosterSize, collection.PosterPath)
+	}
+	if collection.BackdropPath != "" {
+		enrichment.BackdropURL = fmt.Sprintf("https://image.tmdb.org/t/p/%s%s", s.config.Artwork.BackdropSize, collection.BackdropPath)
+	}
+	data, err := json.Marshal(enrichment)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
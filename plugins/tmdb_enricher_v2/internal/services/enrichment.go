@@ -8,17 +8,19 @@ import (
 	"io"
 	"net/http"
 	"net/url"
-	"path/filepath"
 	"regexp"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/mantonx/viewra/plugins/tmdb_enricher_v2/internal/config"
 	"github.com/mantonx/viewra/plugins/tmdb_enricher_v2/internal/models"
 	"github.com/mantonx/viewra/plugins/tmdb_enricher_v2/internal/types"
 	plugins "github.com/mantonx/viewra/sdk"
+	"github.com/mantonx/viewra/sdk/namingparser"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // EnrichmentService handles the core enrichment logic
@@ -28,6 +30,12 @@ type EnrichmentService struct {
 	unifiedClient *plugins.UnifiedServiceClient
 	logger        plugins.Logger
 	lastAPICall   *time.Time
+	seasonMu      sync.Mutex
+
+	prefetchMu      sync.Mutex
+	prefetchedShows map[int]time.Time // When each show's prefetch started
+
+	performanceMonitor *plugins.BasePerformanceMonitor
 }
 
 // NewEnrichmentService creates a new enrichment service
@@ -37,19 +45,42 @@ func NewEnrichmentService(db *gorm.DB, cfg *config.Config, client *plugins.Unifi
 		config:        cfg,
 		unifiedClient: client,
 		logger:        logger,
+
+		prefetchedShows: make(map[int]time.Time),
 	}, nil
 }
 
+// SetPerformanceMonitor records the service's TMDb requests in monitor
+func (s *EnrichmentService) SetPerformanceMonitor(monitor *plugins.BasePerformanceMonitor) {
+	s.performanceMonitor = monitor
+}
+
 // ProcessMediaFile processes a media file for enrichment
 func (s *EnrichmentService) ProcessMediaFile(mediaFileID string, filePath string, metadata map[string]string) error {
 	s.logger.Info("processing media file for enrichment", "media_file_id", mediaFileID, "path", filePath)
 
+	// Files identified by hand are matched to the picked result
+	identified, err := s.identifiedResult(metadata)
+	if err != nil {
+		return err
+	}
+	if identified != nil {
+		if err := s.enrichWithResult(mediaFileID, filePath, metadata, identified); err != nil {
+			return err
+		}
+		if s.config.Matching.LearnAliases {
+			title, year := s.extractTitle(filePath, metadata), s.extractYear(filePath, metadata)
+			s.recordAliases(s.aliasKeys(filePath, title, year, metadata), identified, AliasSourceIdentified)
+		}
+		return nil
+	}
+
 	// Check if already enriched
 	if !s.config.Features.OverwriteExisting {
 		var existing models.TMDbEnrichment
 		if err := s.db.Where("media_file_id = ?", mediaFileID).First(&existing).Error; err == nil {
 			s.logger.Debug("media file already enriched, skipping", "media_file_id", mediaFileID)
-			return nil
+			return plugins.SkipHook(plugins.SkipReasonAlreadyProcessed, "already enriched")
 		}
 	}
 
@@ -59,7 +90,17 @@ func (s *EnrichmentService) ProcessMediaFile(mediaFileID string, filePath string
 
 	if title == "" {
 		s.logger.Debug("no title extracted, skipping enrichment", "media_file_id", mediaFileID)
-		return nil
+		return plugins.SkipHook(plugins.SkipReasonMissingMetadata, "no title in file name or metadata")
+	}
+
+	// Release names matched before keep their match without a search
+	var aliasKeys []string
+	if s.config.Matching.LearnAliases {
+		aliasKeys = s.aliasKeys(filePath, title, year, metadata)
+		if aliased := s.aliasedResult(aliasKeys); aliased != nil {
+			s.logger.Info("Found TMDb match by release alias", "media_file_id", mediaFileID, "title", title, "tmdb_id", aliased.ID)
+			return s.enrichWithResult(mediaFileID, filePath, metadata, aliased)
+		}
 	}
 
 	s.logger.Debug("searching TMDb", "title", title, "year", year, "file_path", filePath)
@@ -68,28 +109,80 @@ func (s *EnrichmentService) ProcessMediaFile(mediaFileID string, filePath string
 	results, err := s.searchContent(title, year)
 	if err != nil {
 		s.logger.Warn("failed to search for content", "error", err, "title", title)
-		return nil
+		return fmt.Errorf("failed to search TMDb: %w", err)
 	}
 
-	// Find best match
-	bestMatch := s.findBestMatch(results, title, year, filePath)
+	// Find best match, keeping to the type a mixed library gave the file by naming
+	bestMatch, score := s.findBestMatch(results, title, year, filePath, s.classifiedType(metadata))
 	if bestMatch == nil {
-		s.logger.Debug("no suitable match found", "title", title, "threshold", s.config.Matching.MatchThreshold)
-		return nil
+		s.logger.Debug("no suitable match found", "title", title, "threshold", s.config.Matching.ReviewThreshold)
+		return plugins.SkipHook(plugins.SkipReasonNoMatch, fmt.Sprintf("no TMDb match for %q", title))
+	}
+
+	// Within a provider chain, weak matches are left to the next provider
+	if minConfidence, err := strconv.ParseFloat(metadata[plugins.HookMetadataMinConfidence], 64); err == nil {
+		if score := s.calculateMatchScore(*bestMatch, title, year); score < minConfidence {
+			s.logger.Debug("match below chain confidence", "title", title, "score", score, "min_confidence", minConfidence)
+			return plugins.SkipHook(plugins.SkipReasonLowConfidence,
+				fmt.Sprintf("TMDb match %q scored %.2f, below %.2f", s.getResultTitle(*bestMatch), score, minConfidence))
+		}
+	}
+
+	// Matches short of the auto threshold wait for a user to confirm them
+	if score < s.config.Matching.AutoThreshold {
+		candidate := plugins.ReviewCandidate{
+			ID:        strconv.Itoa(bestMatch.ID),
+			MediaType: s.resultMediaType(*bestMatch),
+			Title:     s.getResultTitle(*bestMatch),
+			Year:      s.getResultYear(*bestMatch),
+			Score:     score,
+		}
+		s.logger.Info("queued TMDb match for review", "media_file_id", mediaFileID, "title", title, "tmdb_id", bestMatch.ID, "score", score)
+		return plugins.NeedsReview(candidate)
 	}
 
 	s.logger.Info("Found TMDb match", "media_file_id", mediaFileID, "title", title, "tmdb_id", bestMatch.ID, "match_title", s.getResultTitle(*bestMatch))
+	if err := s.enrichWithResult(mediaFileID, filePath, metadata, bestMatch); err != nil {
+		return err
+	}
+	if aliasKeys != nil {
+		s.recordAliases(aliasKeys, bestMatch, AliasSourceAuto)
+	}
+	return nil
+}
+
+// enrichWithResult saves the enrichment of a media file matched to result
+func (s *EnrichmentService) enrichWithResult(mediaFileID, filePath string, metadata map[string]string, result *types.Result) error {
+	// Files of a matched show are enriched with their episode's details too
+	var episode *episodeMatch
+	if s.resultMediaType(*result) == "tv" {
+		s.prefetchShow(result.ID)
+		episode = s.matchEpisode(mediaFileID, result.ID, filePath, metadata)
+	}
 
 	// Save enrichment
-	if err := s.saveEnrichment(mediaFileID, bestMatch); err != nil {
+	if err := s.saveEnrichment(mediaFileID, result, episode); err != nil {
 		s.logger.Warn("Failed to save enrichment", "error", err, "media_file_id", mediaFileID)
-		return nil
+		return fmt.Errorf("failed to save enrichment: %w", err)
 	}
 
-	s.logger.Info("Successfully enriched media file", "media_file_id", mediaFileID, "tmdb_id", bestMatch.ID)
+	s.logger.Info("Successfully enriched media file", "media_file_id", mediaFileID, "tmdb_id", result.ID)
 	return nil
 }
 
+// RemoveMediaFile deletes the enrichment and artwork rows of a media file
+func (s *EnrichmentService) RemoveMediaFile(mediaFileID string) error {
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("media_file_id = ?", mediaFileID).Delete(&models.TMDbEnrichment{}).Error; err != nil {
+			return fmt.Errorf("failed to remove enrichment: %w", err)
+		}
+		if err := tx.Where("media_file_id = ?", mediaFileID).Delete(&models.TMDbArtwork{}).Error; err != nil {
+			return fmt.Errorf("failed to remove artwork: %w", err)
+		}
+		return nil
+	})
+}
+
 // searchContent searches TMDb for content
 func (s *EnrichmentService) searchContent(title string, year int) ([]types.Result, error) {
 	// Check cache first
@@ -155,28 +248,11 @@ func (s *EnrichmentService) extractTitle(filePath string, metadata map[string]st
 		}
 	}
 
-	// Extract from filename
-	filename := filepath.Base(filePath)
-	filename = strings.TrimSuffix(filename, filepath.Ext(filename))
-
-	s.logger.Info("extracting title from filename", "original", filename)
-
-	// Try TV show patterns
-	if title := s.extractTVShowTitle(filename, filePath); title != "" {
-		s.logger.Debug("extracted TV show title", "title", title, "filename", filename)
-		return title
-	}
-
-	// Try movie patterns
-	if title := s.extractMovieTitle(filename); title != "" {
-		s.logger.Debug("extracted movie title", "title", title, "filename", filename)
-		return title
-	}
-
-	// Fallback: clean up the filename
-	title := s.cleanupTitle(filename)
-	s.logger.Debug("fallback title extraction", "title", title, "filename", filename)
-	return title
+	// Extract from the file name, or the show directory for files named by
+	// their numbers alone
+	parsed := namingparser.Parse(plugins.MatchPath(filePath, metadata))
+	s.logger.Debug("extracted title from file name", "title", parsed.Title, "kind", parsed.Kind, "path", filePath)
+	return parsed.Title
 }
 
 // looksLikeEpisodeTitle checks if a title looks like an episode title
@@ -198,114 +274,6 @@ func (s *EnrichmentService) looksLikeEpisodeTitle(title string) bool {
 	return qualityCount >= 2
 }
 
-// extractTVShowTitle handles various TV show filename patterns
-func (s *EnrichmentService) extractTVShowTitle(filename, filePath string) string {
-	// Pattern 1: "Show Name (Year) - S##E## - Episode Title [Quality]"
-	seasonPatterns := []string{" - S", "- S", " -S", "-S"}
-	for _, pattern := range seasonPatterns {
-		if strings.Contains(filename, pattern) && strings.Contains(filename, "E") {
-			seasonPos := strings.Index(filename, pattern)
-			if seasonPos > 0 {
-				showPart := filename[:seasonPos]
-				cleaned := s.cleanupTitle(showPart)
-				if cleaned != "" {
-					s.logger.Debug("extracted show title via season pattern", "pattern", pattern, "show", cleaned, "filename", filename)
-					return cleaned
-				}
-			}
-		}
-	}
-
-	// Pattern 2: "Show Name S##E## Episode Title"
-	seasonRegex := regexp.MustCompile(`^(.+?)\s+S\d+E\d+`)
-	if matches := seasonRegex.FindStringSubmatch(filename); len(matches) > 1 {
-		cleaned := s.cleanupTitle(matches[1])
-		if cleaned != "" {
-			s.logger.Debug("extracted show title via regex pattern", "show", cleaned, "filename", filename)
-			return cleaned
-		}
-	}
-
-	// Pattern 3: "Show Name.S##E##.Episode.Title"
-	dotSeasonRegex := regexp.MustCompile(`^(.+?)\.S\d+E\d+`)
-	if matches := dotSeasonRegex.FindStringSubmatch(filename); len(matches) > 1 {
-		showName := strings.ReplaceAll(matches[1], ".", " ")
-		cleaned := s.cleanupTitle(showName)
-		if cleaned != "" {
-			s.logger.Debug("extracted show title via dot pattern", "show", cleaned, "filename", filename)
-			return cleaned
-		}
-	}
-
-	// Pattern 4: Directory-based detection
-	parentDir := filepath.Base(filepath.Dir(filePath))
-	if parentDir != "" && parentDir != "." && !strings.Contains(parentDir, "Season") {
-		if cleanDir := s.cleanupTitle(parentDir); cleanDir != "" {
-			if len(cleanDir) > 2 && !strings.Contains(strings.ToLower(cleanDir), "season") {
-				s.logger.Debug("extracted show title from directory", "show", cleanDir, "directory", parentDir)
-				return cleanDir
-			}
-		}
-	}
-
-	s.logger.Debug("no TV show pattern matched", "filename", filename)
-	return ""
-}
-
-// extractMovieTitle handles various movie filename patterns
-func (s *EnrichmentService) extractMovieTitle(filename string) string {
-	title := filename
-
-	// Remove release group patterns
-	releaseGroupRegex := regexp.MustCompile(`-[A-Z][A-Za-z0-9]*$`)
-	title = releaseGroupRegex.ReplaceAllString(title, "")
-
-	// Remove quality tags in brackets
-	qualityRegex := regexp.MustCompile(`\[[^\]]*\]`)
-	title = qualityRegex.ReplaceAllString(title, "")
-
-	// Remove quality indicators
-	qualityPatterns := []string{
-		`\bBluRay\b`, `\bBDRip\b`, `\bBRRip\b`, `\bDVDRip\b`, `\bWEBRip\b`, `\bWEB-DL\b`,
-		`\bHDTV\b`, `\bSDTV\b`, `\b720p\b`, `\b1080p\b`, `\b4K\b`, `\bUHD\b`,
-		`\bx264\b`, `\bx265\b`, `\bH\.?264\b`, `\bH\.?265\b`, `\bHEVC\b`,
-		`\bAAC\b`, `\bAC3\b`, `\bDTS\b`, `\bFLAC\b`, `\bMP3\b`,
-		`\b2\.0\b`, `\b5\.1\b`, `\b7\.1\b`,
-	}
-
-	for _, pattern := range qualityPatterns {
-		re := regexp.MustCompile(`(?i)` + pattern)
-		title = re.ReplaceAllString(title, "")
-	}
-
-	// Clean up spaces
-	title = regexp.MustCompile(`\s+`).ReplaceAllString(title, " ")
-	title = strings.TrimSpace(title)
-
-	// Handle year extraction - "Movie Title (2023)"
-	yearRegex := regexp.MustCompile(`^(.+?)\s*\((\d{4})\)$`)
-	if matches := yearRegex.FindStringSubmatch(title); len(matches) > 2 {
-		movieTitle := strings.TrimSpace(matches[1])
-		if movieTitle != "" {
-			return movieTitle
-		}
-	}
-
-	// Look for year without parentheses - "Movie Title 2023"
-	yearEndRegex := regexp.MustCompile(`^(.+?)\s+(\d{4})$`)
-	if matches := yearEndRegex.FindStringSubmatch(title); len(matches) > 2 {
-		year, _ := strconv.Atoi(matches[2])
-		if year >= 1900 && year <= time.Now().Year()+5 {
-			movieTitle := strings.TrimSpace(matches[1])
-			if movieTitle != "" {
-				return movieTitle
-			}
-		}
-	}
-
-	return s.cleanupTitle(title)
-}
-
 // cleanupTitle performs common cleanup operations on titles
 func (s *EnrichmentService) cleanupTitle(title string) string {
 	if title == "" {
@@ -364,30 +332,69 @@ func (s *EnrichmentService) extractYear(filePath string, metadata map[string]str
 		}
 	}
 
-	// Extract from filename
-	filename := filepath.Base(filePath)
+	return namingparser.Parse(plugins.MatchPath(filePath, metadata)).Year
+}
 
-	// Look for 4-digit year patterns
-	for i := len(filename) - 4; i >= 0; i-- {
-		if i+4 <= len(filename) {
-			substr := filename[i : i+4]
-			if year, err := strconv.Atoi(substr); err == nil {
-				if year >= 1900 && year <= time.Now().Year()+5 {
-					return year
-				}
-			}
-		}
+// classifiedType returns the TMDb type ("movie" or "tv") the file's library
+// type implies, or that a mixed library classified the file as from its
+// naming, or "" when the type is open
+func (s *EnrichmentService) classifiedType(metadata map[string]string) string {
+	switch metadata[plugins.HookMetadataLibraryType] {
+	case "movie":
+		return "movie"
+	case "tv":
+		return "tv"
+	}
+	if metadata["classified_by"] != "naming" && metadata["classified_by"] != "tmdb" {
+		return ""
 	}
+	switch metadata["media_type"] {
+	case "movie":
+		return "movie"
+	case "episode":
+		return "tv"
+	}
+	return ""
+}
 
-	return 0
+// resultMediaType returns "movie" or "tv" for a search result
+func (s *EnrichmentService) resultMediaType(result types.Result) string {
+	if result.MediaType == "tv" || result.Name != "" || result.FirstAirDate != "" {
+		return "tv"
+	}
+	return "movie"
 }
 
-// findBestMatch finds the best matching result
-func (s *EnrichmentService) findBestMatch(results []types.Result, title string, year int, filePath string) *types.Result {
+// findBestMatch finds the best matching result scoring at least the review
+// threshold, and its score. When the file's type is known (classifiedType),
+// results of the other type are skipped.
+func (s *EnrichmentService) findBestMatch(results []types.Result, title string, year int, filePath string, classifiedType string) (*types.Result, float64) {
 	var bestMatch *types.Result
 	bestScore := 0.0
 
-	// Determine content type hints from file path
+	isLikelyTVShow, isLikelyMovie := s.typeHints(title, filePath, classifiedType)
+	for _, result := range results {
+		if classifiedType != "" && s.resultMediaType(result) != classifiedType {
+			continue
+		}
+
+		score := s.contextScore(result, title, year, isLikelyTVShow, isLikelyMovie)
+		if score > bestScore && score >= s.config.Matching.ReviewThreshold {
+			bestScore = score
+			bestMatch = &result
+		}
+	}
+
+	if bestMatch != nil {
+		s.logger.Debug("found best match", "title", s.getResultTitle(*bestMatch), "type", bestMatch.MediaType, "score", bestScore)
+	}
+
+	return bestMatch, bestScore
+}
+
+// typeHints reports whether a file's title and path suggest a show or a
+// movie. When the file's type is known (classifiedType), it decides.
+func (s *EnrichmentService) typeHints(title, filePath, classifiedType string) (bool, bool) {
 	isLikelyTVShow := s.looksLikeEpisodeTitle(title) ||
 		strings.Contains(strings.ToLower(filePath), "/tv/") ||
 		strings.Contains(strings.ToLower(filePath), "/shows/") ||
@@ -402,27 +409,23 @@ func (s *EnrichmentService) findBestMatch(results []types.Result, title string,
 		strings.Contains(strings.ToLower(filePath), "/films/") ||
 		strings.Contains(strings.ToLower(filePath), "/cinema/")
 
-	for _, result := range results {
-		score := s.calculateMatchScore(result, title, year)
-
-		// Add context bonus for media type matching
-		if isLikelyTVShow && (result.MediaType == "tv" || result.FirstAirDate != "" || (result.Name != "" && result.Title == "")) {
-			score += 0.15
-		} else if isLikelyMovie && (result.MediaType == "movie" || result.ReleaseDate != "" || (result.Title != "" && result.Name == "")) {
-			score += 0.15
-		}
-
-		if score > bestScore && score >= s.config.Matching.MatchThreshold {
-			bestScore = score
-			bestMatch = &result
-		}
+	if classifiedType != "" {
+		isLikelyTVShow = classifiedType == "tv"
+		isLikelyMovie = classifiedType == "movie"
 	}
+	return isLikelyTVShow, isLikelyMovie
+}
 
-	if bestMatch != nil {
-		s.logger.Debug("found best match", "title", s.getResultTitle(*bestMatch), "type", bestMatch.MediaType, "score", bestScore)
+// contextScore scores a result against the search terms, with a bonus when
+// its type is the one the file suggests
+func (s *EnrichmentService) contextScore(result types.Result, title string, year int, isLikelyTVShow, isLikelyMovie bool) float64 {
+	score := s.calculateMatchScore(result, title, year)
+	if isLikelyTVShow && (result.MediaType == "tv" || result.FirstAirDate != "" || (result.Name != "" && result.Title == "")) {
+		score += 0.15
+	} else if isLikelyMovie && (result.MediaType == "movie" || result.ReleaseDate != "" || (result.Title != "" && result.Name == "")) {
+		score += 0.15
 	}
-
-	return bestMatch
+	return score
 }
 
 // calculateMatchScore calculates match score between result and search terms
@@ -481,13 +484,22 @@ func (s *EnrichmentService) getResultYear(result types.Result) int {
 	return 0
 }
 
-// saveEnrichment saves enrichment data to database
-func (s *EnrichmentService) saveEnrichment(mediaFileID string, result *types.Result) error {
+// saveEnrichment saves enrichment data to database. A matched episode makes
+// the enrichment an episode one, keeping the show's ID in ShowTMDbID.
+// enrichmentUpdateColumns are the tmdb_enrichments columns a new match
+// overwrites on an existing row
+var enrichmentUpdateColumns = []string{
+	"tm_db_id", "tm_db_type", "title", "original_title", "overview", "release_date",
+	"season_number", "episode_number", "show_tm_db_id",
+	"episode_title", "episode_overview", "episode_air_date", "episode_still_url",
+	"collection_id", "collection_name", "collection_poster_path",
+	"genres", "cast", "crew", "keywords", "external_ids",
+	"confidence_score", "source_plugin", "updated_at",
+}
+
+func (s *EnrichmentService) saveEnrichment(mediaFileID string, result *types.Result, episode *episodeMatch) error {
 	// Determine media type
-	mediaType := "movie"
-	if result.MediaType == "tv" || result.Name != "" || result.FirstAirDate != "" {
-		mediaType = "tv"
-	}
+	mediaType := s.resultMediaType(*result)
 
 	var releaseDate *time.Time
 	dateStr := result.ReleaseDate
@@ -512,6 +524,34 @@ func (s *EnrichmentService) saveEnrichment(mediaFileID string, result *types.Res
 		SourcePlugin:    "tmdb_enricher_v2",
 	}
 
+	if episode != nil {
+		showID := result.ID
+		enrichment.TMDbType = "episode"
+		enrichment.ShowTMDbID = &showID
+		if episode.Details.ID > 0 {
+			enrichment.TMDbID = episode.Details.ID
+		}
+		enrichment.SeasonNumber = &episode.SeasonNumber
+		enrichment.EpisodeNumber = &episode.EpisodeNumber
+		enrichment.EpisodeTitle = episode.Details.Name
+		enrichment.EpisodeOverview = episode.Details.Overview
+		enrichment.EpisodeAirDate = episodeAirDate(episode.Details)
+		enrichment.EpisodeStillURL = s.stillURL(episode.Details)
+	}
+
+	// Movies of a franchise carry their collection
+	var collection *types.CollectionDetails
+	if mediaType == "movie" {
+		var err error
+		if collection, err = s.fetchMovieCollection(result.ID); err != nil {
+			s.logger.Warn("failed to fetch movie collection", "error", err, "tmdb_id", result.ID)
+		} else if collection != nil {
+			enrichment.CollectionID = &collection.ID
+			enrichment.CollectionName = collection.Name
+			enrichment.CollectionPosterPath = collection.PosterPath
+		}
+	}
+
 	// Store additional metadata as JSON
 	if len(result.GenreIDs) > 0 {
 		if genresJSON, err := json.Marshal(result.GenreIDs); err == nil {
@@ -519,23 +559,31 @@ func (s *EnrichmentService) saveEnrichment(mediaFileID string, result *types.Res
 		}
 	}
 
-	if err := s.db.Save(enrichment).Error; err != nil {
+	// Upsert on media_file_id so concurrent hooks for one file keep a single row.
+	// processed_at records the first match and is left alone.
+	if err := s.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "media_file_id"}},
+		DoUpdates: clause.AssignmentColumns(enrichmentUpdateColumns),
+	}).Create(enrichment).Error; err != nil {
 		return fmt.Errorf("failed to save enrichment: %w", err)
 	}
 
 	// Register with centralized enrichment system
 	if s.unifiedClient != nil {
-		if err := s.registerWithCentralizedSystem(mediaFileID, result, mediaType); err != nil {
+		if err := s.registerWithCentralizedSystem(mediaFileID, result, mediaType, episode, collection); err != nil {
 			s.logger.Warn("Failed to register with centralized system", "error", err)
 		}
+		if err := s.saveCredits(mediaFileID, result, mediaType, episode); err != nil {
+			s.logger.Warn("failed to save credits", "error", err, "media_file_id", mediaFileID)
+		}
 	}
 
-	s.logger.Info("saved enrichment", "media_file_id", mediaFileID, "tmdb_id", result.ID, "title", enrichment.Title, "type", mediaType)
+	s.logger.Info("saved enrichment", "media_file_id", mediaFileID, "tmdb_id", result.ID, "title", enrichment.Title, "type", enrichment.TMDbType)
 	return nil
 }
 
 // registerWithCentralizedSystem registers enrichment with the centralized system
-func (s *EnrichmentService) registerWithCentralizedSystem(mediaFileID string, result *types.Result, mediaType string) error {
+func (s *EnrichmentService) registerWithCentralizedSystem(mediaFileID string, result *types.Result, mediaType string, episode *episodeMatch, collection *types.CollectionDetails) error {
 	enrichments := make(map[string]string)
 
 	enrichments["tmdb_id"] = fmt.Sprintf("%d", result.ID)
@@ -558,6 +606,36 @@ func (s *EnrichmentService) registerWithCentralizedSystem(mediaFileID string, re
 		}
 	}
 
+	// Certifications by country, and the one for the configured region
+	if ratings, err := s.fetchContentRatings(mediaType, result.ID); err != nil {
+		s.logger.Warn("failed to fetch content ratings", "error", err, "tmdb_id", result.ID)
+	} else if len(ratings) > 0 {
+		if ratingsJSON, err := json.Marshal(ratings); err == nil {
+			enrichments["content_ratings"] = string(ratingsJSON)
+		}
+		if rating := s.contentRating(ratings); rating != "" {
+			enrichments["content_rating"] = rating
+		}
+	}
+
+	if collection != nil {
+		if collectionJSON, err := s.collectionJSON(collection); err == nil {
+			enrichments["collection"] = collectionJSON
+		}
+	}
+
+	if episode != nil {
+		enrichments["season_number"] = strconv.Itoa(episode.SeasonNumber)
+		enrichments["episode_number"] = strconv.Itoa(episode.EpisodeNumber)
+		enrichments["episode_tmdb_id"] = strconv.Itoa(episode.Details.ID)
+		enrichments["episode_title"] = episode.Details.Name
+		enrichments["episode_overview"] = episode.Details.Overview
+		enrichments["episode_air_date"] = episode.Details.AirDate
+		if stillURL := s.stillURL(episode.Details); stillURL != "" {
+			enrichments["episode_still_url"] = stillURL
+		}
+	}
+
 	if result.PosterPath != "" {
 		enrichments["poster_path"] = result.PosterPath
 		enrichments["poster_url"] = fmt.Sprintf("https://image.tmdb.org/t/p/%s%s", s.config.Artwork.PosterSize, result.PosterPath)
@@ -633,9 +711,7 @@ func (s *EnrichmentService) makeAPIRequestWithRetries(url string, result interfa
 
 // makeAPIRequest performs a single API request
 func (s *EnrichmentService) makeAPIRequest(url string, result interface{}) error {
-	client := &http.Client{
-		Timeout: s.config.API.GetRequestTimeout(),
-	}
+	client := plugins.InstrumentProviderClient(plugins.NewProviderHTTPClient(s.config.API.GetRequestTimeout()), s.performanceMonitor, "tmdb")
 
 	req, err := http.NewRequest("GET", url, nil)
 	if err != nil {
@@ -743,23 +819,31 @@ func (s *EnrichmentService) generateQueryHash(query string) string {
 
 // getCachedResponse retrieves cached API response
 func (s *EnrichmentService) getCachedResponse(queryType, queryHash string) ([]types.Result, error) {
-	var cache models.TMDbCache
-	if err := s.db.Where("query_type = ? AND query_hash = ? AND expires_at > ?",
-		queryType, queryHash, time.Now()).First(&cache).Error; err != nil {
-		return nil, err
-	}
-
 	var results []types.Result
-	if err := json.Unmarshal([]byte(cache.Response), &results); err != nil {
+	if err := s.getCachedJSON(queryType, queryHash, &results); err != nil {
 		return nil, err
 	}
-
 	return results, nil
 }
 
+// getCachedJSON decodes an unexpired cached API response into out
+func (s *EnrichmentService) getCachedJSON(queryType, queryHash string, out interface{}) error {
+	var cache models.TMDbCache
+	if err := s.db.Where("query_type = ? AND query_hash = ? AND expires_at > ?",
+		queryType, queryHash, time.Now()).First(&cache).Error; err != nil {
+		return err
+	}
+	return json.Unmarshal([]byte(cache.Response), out)
+}
+
 // cacheResults caches API response
 func (s *EnrichmentService) cacheResults(queryType, queryHash string, results []types.Result) {
-	data, err := json.Marshal(results)
+	s.cacheJSON(queryType, queryHash, results)
+}
+
+// cacheJSON caches an API response, replacing an expired entry for the query
+func (s *EnrichmentService) cacheJSON(queryType, queryHash string, value interface{}) {
+	data, err := json.Marshal(value)
 	if err != nil {
 		s.logger.Error("failed to marshal cache data", "error", err)
 		return
@@ -772,7 +856,12 @@ func (s *EnrichmentService) cacheResults(queryType, queryHash string, results []
 		ExpiresAt: time.Now().Add(s.config.Cache.GetCacheDuration()),
 	}
 
-	s.db.Save(cache)
+	if err := s.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "query_hash"}},
+		DoUpdates: clause.AssignmentColumns([]string{"response", "expires_at"}),
+	}).Create(cache).Error; err != nil {
+		s.logger.Warn("failed to cache TMDb response", "query_type", queryType, "error", err)
+	}
 }
 
 // Helper function for absolute value
@@ -0,0 +1,124 @@
+package services
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/mantonx/viewra/plugins/tmdb_enricher_v2/internal/types"
+	plugins "github.com/mantonx/viewra/sdk"
+	"github.com/mantonx/viewra/sdk/namingparser"
+)
+
+// episodeNumbers returns the season and episode of a TV file, from the
+// scanner's tags when present and otherwise from the file name
+func episodeNumbers(filePath string, metadata map[string]string) (int, int, bool) {
+	season, seasonErr := strconv.Atoi(metadata["season_number"])
+	episode, episodeErr := strconv.Atoi(metadata["episode_number"])
+	if seasonErr == nil && episodeErr == nil && episode > 0 {
+		return season, episode, true
+	}
+
+	if parsed := namingparser.Parse(plugins.MatchPath(filePath, metadata)); parsed.Episode() > 0 {
+		return parsed.Season, parsed.Episode(), true
+	}
+	return 0, 0, false
+}
+
+// fetchEpisode returns TMDb's details for an episode. Episodes are looked up
+// in their season's details, fetched once per season and cached, so a
+// season's files cost one request between them; an episode missing from its
+// season is fetched on its own.
+func (s *EnrichmentService) fetchEpisode(showID, seasonNumber, episodeNumber int) (*types.TVEpisodeDetails, error) {
+	season, err := s.fetchSeason(showID, seasonNumber)
+	if err != nil {
+		return nil, err
+	}
+	for i := range season.Episodes {
+		if season.Episodes[i].EpisodeNumber == episodeNumber {
+			return &season.Episodes[i], nil
+		}
+	}
+
+	queryHash := s.generateQueryHash(fmt.Sprintf("episode:%d:%d:%d", showID, seasonNumber, episodeNumber))
+	var episode types.TVEpisodeDetails
+	if err := s.getCachedJSON("episode", queryHash, &episode); err == nil {
+		return &episode, nil
+	}
+
+	episodeURL := fmt.Sprintf("https://api.themoviedb.org/3/tv/%d/season/%d/episode/%d?language=%s",
+		showID, seasonNumber, episodeNumber, s.config.API.Language)
+	if err := s.makeAPIRequestWithRetries(episodeURL, &episode, fmt.Sprintf("episode S%02dE%02d of %d", seasonNumber, episodeNumber, showID)); err != nil {
+		return nil, err
+	}
+	s.cacheJSON("episode", queryHash, &episode)
+	return &episode, nil
+}
+
+// fetchSeason returns a season with its episodes, from the cache when
+// possible. Fetches are serialized so files of one season scanned together
+// don't all request it.
+func (s *EnrichmentService) fetchSeason(showID, seasonNumber int) (*types.TVSeasonDetails, error) {
+	s.seasonMu.Lock()
+	defer s.seasonMu.Unlock()
+
+	queryHash := s.generateQueryHash(fmt.Sprintf("season:%d:%d", showID, seasonNumber))
+	var season types.TVSeasonDetails
+	if err := s.getCachedJSON("season", queryHash, &season); err == nil {
+		return &season, nil
+	}
+
+	seasonURL := fmt.Sprintf("https://api.themoviedb.org/3/tv/%d/season/%d?language=%s",
+		showID, seasonNumber, s.config.API.Language)
+	if err := s.makeAPIRequestWithRetries(seasonURL, &season, fmt.Sprintf("season %d of %d", seasonNumber, showID)); err != nil {
+		return nil, err
+	}
+	s.cacheJSON("season", queryHash, &season)
+	return &season, nil
+}
+
+// episodeAirDate parses an episode's air date, or returns nil when it has none
+func episodeAirDate(episode *types.TVEpisodeDetails) *time.Time {
+	if episode.AirDate == "" {
+		return nil
+	}
+	date, err := time.Parse("2006-01-02", episode.AirDate)
+	if err != nil {
+		return nil
+	}
+	return &date
+}
+
+// episodeMatch is the episode a TV file was matched to within its show
+type episodeMatch struct {
+	SeasonNumber  int
+	EpisodeNumber int
+	Details       *types.TVEpisodeDetails
+}
+
+// matchEpisode looks up the episode a file of a matched show is, or returns
+// nil when the file has no season and episode or TMDb doesn't know it. The
+// show-level match is kept either way.
+func (s *EnrichmentService) matchEpisode(mediaFileID string, showID int, filePath string, metadata map[string]string) *episodeMatch {
+	seasonNumber, episodeNumber, ok := episodeNumbers(filePath, metadata)
+	if !ok {
+		s.logger.Debug("no season and episode numbers found", "media_file_id", mediaFileID, "path", filePath)
+		return nil
+	}
+
+	details, err := s.fetchEpisode(showID, seasonNumber, episodeNumber)
+	if err != nil {
+		s.logger.Warn("failed to fetch episode details", "error", err, "media_file_id", mediaFileID,
+			"tmdb_id", showID, "season", seasonNumber, "episode", episodeNumber)
+		return nil
+	}
+	return &episodeMatch{SeasonNumber: seasonNumber, EpisodeNumber: episodeNumber, Details: details}
+}
+
+// stillURL returns the full URL of an episode's still, or "" when it has none
+func (s *EnrichmentService) stillURL(episode *types.TVEpisodeDetails) string {
+	if episode.StillPath == "" {
+		return ""
+	}
+	return fmt.Sprintf("https://image.tmdb.org/t/p/%s%s", s.config.Artwork.StillSize, episode.StillPath)
+}
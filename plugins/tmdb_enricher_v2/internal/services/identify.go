@@ -0,0 +1,121 @@
+package services
+
+import (
+	"fmt"
+	"strconv"
+
+	plugins "github.com/mantonx/viewra/sdk"
+
+	"github.com/mantonx/viewra/plugins/tmdb_enricher_v2/internal/types"
+)
+
+// SearchCandidates searches TMDb for the results a user picks from when
+// identifying a file by hand. mediaType, movie or tv, limits the results to
+// one type when set. Results are scored as a scan matching the file at
+// filePath would score them; filePath may be empty.
+func (s *EnrichmentService) SearchCandidates(title string, year int, mediaType, filePath string) ([]*plugins.SearchResult, error) {
+	results, err := s.searchContent(title, year)
+	if err != nil {
+		return nil, err
+	}
+
+	isLikelyTVShow, isLikelyMovie := s.typeHints(title, filePath, mediaType)
+
+	candidates := make([]*plugins.SearchResult, 0, len(results))
+	for _, result := range results {
+		// Multi search also returns people
+		if result.MediaType != "" && result.MediaType != "movie" && result.MediaType != "tv" {
+			continue
+		}
+		resultType := s.resultMediaType(result)
+		if mediaType != "" && resultType != mediaType {
+			continue
+		}
+		candidate := s.searchResult(result, resultType)
+		candidate.Score = s.contextScore(result, title, year, isLikelyTVShow, isLikelyMovie)
+		candidates = append(candidates, candidate)
+	}
+	return candidates, nil
+}
+
+// FileQuery returns the title and year a scan searches TMDb for to match the
+// file at filePath, from its name alone
+func (s *EnrichmentService) FileQuery(filePath string) (string, int) {
+	metadata := map[string]string{}
+	return s.extractTitle(filePath, metadata), s.extractYear(filePath, metadata)
+}
+
+// LookupCandidate fetches a movie or show by TMDb ID, for a search by tmdb_id
+func (s *EnrichmentService) LookupCandidate(tmdbID int, mediaType string) (*plugins.SearchResult, error) {
+	result, err := s.lookupResult(tmdbID, mediaType)
+	if err != nil {
+		return nil, err
+	}
+	return s.searchResult(*result, s.resultMediaType(*result)), nil
+}
+
+// searchResult describes a TMDb result to the host. Only the ID, title and
+// metadata reach the host, so the type and year go in the metadata.
+func (s *EnrichmentService) searchResult(result types.Result, mediaType string) *plugins.SearchResult {
+	metadata := map[string]string{
+		"media_type": mediaType,
+		"overview":   result.Overview,
+		"url":        fmt.Sprintf("https://www.themoviedb.org/%s/%d", mediaType, result.ID),
+	}
+	if year := s.getResultYear(result); year > 0 {
+		metadata["year"] = strconv.Itoa(year)
+	}
+	if result.PosterPath != "" {
+		metadata["poster_url"] = fmt.Sprintf("https://image.tmdb.org/t/p/%s%s", s.config.Artwork.PosterSize, result.PosterPath)
+	}
+
+	return &plugins.SearchResult{
+		ID:       strconv.Itoa(result.ID),
+		Type:     mediaType,
+		Title:    s.getResultTitle(result),
+		URL:      metadata["url"],
+		Metadata: metadata,
+	}
+}
+
+// lookupResult fetches the movie or show a user identified a file as, in
+// the shape search results have
+func (s *EnrichmentService) lookupResult(tmdbID int, mediaType string) (*types.Result, error) {
+	if mediaType != "tv" {
+		mediaType = "movie"
+	}
+
+	queryHash := s.generateQueryHash(fmt.Sprintf("details:%s:%d", mediaType, tmdbID))
+	var result types.Result
+	if err := s.getCachedJSON("details", queryHash, &result); err == nil {
+		return &result, nil
+	}
+
+	detailsURL := fmt.Sprintf("https://api.themoviedb.org/3/%s/%d?language=%s", mediaType, tmdbID, s.config.API.Language)
+	if err := s.makeAPIRequestWithRetries(detailsURL, &result, fmt.Sprintf("%s %d", mediaType, tmdbID)); err != nil {
+		return nil, err
+	}
+	// Details responses don't say what they are, and name their genres
+	result.MediaType = mediaType
+	if len(result.GenreIDs) == 0 {
+		for _, genre := range result.Genres {
+			result.GenreIDs = append(result.GenreIDs, genre.ID)
+		}
+	}
+	s.cacheJSON("details", queryHash, &result)
+	return &result, nil
+}
+
+// identifiedResult returns the result a user picked for a file, or nil when
+// the file wasn't identified by hand
+func (s *EnrichmentService) identifiedResult(metadata map[string]string) (*types.Result, error) {
+	identifyID := metadata[plugins.HookMetadataIdentifyID]
+	if identifyID == "" {
+		return nil, nil
+	}
+	tmdbID, err := strconv.Atoi(identifyID)
+	if err != nil || tmdbID <= 0 {
+		return nil, &plugins.PluginError{Code: plugins.ErrorCodeInvalidArgument, Message: fmt.Sprintf("invalid TMDb ID %q", identifyID)}
+	}
+	return s.lookupResult(tmdbID, metadata[plugins.HookMetadataIdentifyType])
+}
@@ -26,4 +26,6 @@ var (
 	_ ConfigurableService = (*EnrichmentService)(nil)
 	_ ConfigurableService = (*MatchingService)(nil)
 	_ ConfigurableService = (*ArtworkService)(nil)
+	_ PerformanceAware    = (*EnrichmentService)(nil)
+	_ PerformanceAware    = (*ArtworkService)(nil)
 )
@@ -33,7 +33,8 @@ type MatchResult struct {
 	Reason string
 }
 
-// FindBestMatch finds the best match from search results
+// FindBestMatch finds the best match from search results scoring at least
+// the review threshold. Matches below the auto threshold need review.
 func (m *MatchingService) FindBestMatch(results []types.Result, title string, year int, filePath string) *MatchResult {
 	if len(results) == 0 {
 		return nil
@@ -53,7 +54,7 @@ func (m *MatchingService) FindBestMatch(results []types.Result, title string, ye
 			"score", fmt.Sprintf("%.3f", score),
 			"reason", reason)
 
-		if score > bestScore && score >= m.config.Matching.MatchThreshold {
+		if score > bestScore && score >= m.config.Matching.ReviewThreshold {
 			bestMatch = &MatchResult{
 				Result: &result,
 				Score:  score,
@@ -71,7 +72,7 @@ func (m *MatchingService) FindBestMatch(results []types.Result, title string, ye
 			"reason", bestMatch.Reason)
 	} else {
 		m.logger.Debug("no match above threshold",
-			"threshold", m.config.Matching.MatchThreshold,
+			"threshold", m.config.Matching.ReviewThreshold,
 			"best_score", fmt.Sprintf("%.3f", bestScore))
 	}
 
@@ -501,7 +502,8 @@ func max(a, b int) int {
 func (s *MatchingService) UpdateConfiguration(newConfig *config.Config) {
 	s.config = newConfig
 	s.logger.Debug("matching service configuration updated",
-		"match_threshold", newConfig.Matching.MatchThreshold,
+		"auto_threshold", newConfig.Matching.AutoThreshold,
+		"review_threshold", newConfig.Matching.ReviewThreshold,
 		"match_year", newConfig.Matching.MatchYear,
 		"year_tolerance", newConfig.Matching.YearTolerance)
 }
@@ -0,0 +1,207 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mantonx/viewra/plugins/tmdb_enricher_v2/internal/types"
+	plugins "github.com/mantonx/viewra/sdk"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// roleGuest is the role of an episode's guest stars
+const roleGuest = "guest"
+
+// creditedJobs are the crew jobs recorded, by their TMDb name. The rest of
+// the crew is left out.
+var creditedJobs = map[string]bool{
+	"Director":                true,
+	"Writer":                  true,
+	"Screenplay":              true,
+	"Story":                   true,
+	"Novel":                   true,
+	"Original Music Composer": true,
+	"Director of Photography": true,
+}
+
+// saveCredits records the cast and crew of a matched movie or episode with
+// the host. Shows matched without an episode have no credits of their own.
+func (s *EnrichmentService) saveCredits(mediaFileID string, result *types.Result, mediaType string, episode *episodeMatch) error {
+	if !s.config.People.Enabled {
+		return nil
+	}
+	if mediaType != "movie" && episode == nil {
+		return nil
+	}
+
+	var response *types.CreditsResponse
+	var err error
+	if episode != nil {
+		response, err = s.fetchEpisodeCredits(result.ID, episode.SeasonNumber, episode.EpisodeNumber)
+	} else {
+		response, err = s.fetchMovieCredits(result.ID)
+	}
+	if err != nil {
+		return err
+	}
+
+	credits := s.buildCredits(response)
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	saved, err := s.unifiedClient.MediaEntityService().SetCredits(ctx, mediaFileID, credits)
+	if code := status.Code(err); code == codes.Unimplemented || code == codes.FailedPrecondition {
+		// Older hosts keep no people, and files not yet linked to a movie or
+		// episode have nothing to credit
+		s.logger.Debug("credits not recorded", "media_file_id", mediaFileID, "reason", err)
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to set credits: %w", err)
+	}
+
+	s.logger.Debug("saved credits", "media_file_id", mediaFileID, "media_id", saved.MediaID, "credits", len(credits))
+	return nil
+}
+
+// buildCredits converts TMDb's cast and crew, keeping the top-billed cast,
+// the guest stars and the key crew. The first credited people carry their
+// biography and dates.
+func (s *EnrichmentService) buildCredits(response *types.CreditsResponse) []plugins.Credit {
+	var credits []plugins.Credit
+	for i, member := range response.Cast {
+		if s.config.People.MaxCast > 0 && i >= s.config.People.MaxCast {
+			break
+		}
+		credits = append(credits, plugins.Credit{
+			Person:     s.creditedPerson(member.ID, member.Name, member.KnownForDepartment, member.ProfilePath),
+			Role:       "actor",
+			Character:  member.Character,
+			Department: "Acting",
+			Order:      member.Order,
+		})
+	}
+	for _, member := range response.GuestStars {
+		credits = append(credits, plugins.Credit{
+			Person:     s.creditedPerson(member.ID, member.Name, member.KnownForDepartment, member.ProfilePath),
+			Role:       roleGuest,
+			Character:  member.Character,
+			Department: "Acting",
+			Order:      len(credits),
+		})
+	}
+	for _, member := range response.Crew {
+		if !creditedJobs[member.Job] {
+			continue
+		}
+		credits = append(credits, plugins.Credit{
+			Person:     s.creditedPerson(member.ID, member.Name, member.KnownForDepartment, member.ProfilePath),
+			Role:       strings.ToLower(member.Job),
+			Department: member.Department,
+			Order:      len(credits),
+		})
+	}
+
+	// Details cost a request per person, so only the first are filled in;
+	// the rest are matched by their TMDb ID and named
+	fetched := make(map[string]bool)
+	for i := range credits {
+		if len(fetched) >= s.config.People.DetailsLimit {
+			break
+		}
+		tmdbID := credits[i].Person.ExternalIDs["tmdb"]
+		if fetched[tmdbID] {
+			continue
+		}
+		fetched[tmdbID] = true
+		id, _ := strconv.Atoi(tmdbID)
+		details, err := s.fetchPerson(id)
+		if err != nil {
+			s.logger.Warn("failed to fetch person details", "error", err, "tmdb_id", id)
+			continue
+		}
+		for j := i; j < len(credits); j++ {
+			if credits[j].Person.ExternalIDs["tmdb"] == tmdbID {
+				s.addPersonDetails(&credits[j].Person, details)
+			}
+		}
+	}
+	return credits
+}
+
+// creditedPerson describes a credited person by their TMDb ID, name and
+// profile image
+func (s *EnrichmentService) creditedPerson(tmdbID int, name, department, profilePath string) plugins.Person {
+	person := plugins.Person{
+		Name:               name,
+		ExternalIDs:        map[string]string{"tmdb": strconv.Itoa(tmdbID)},
+		KnownForDepartment: department,
+	}
+	if s.config.People.DownloadProfiles && profilePath != "" {
+		person.ProfileURL = fmt.Sprintf("https://image.tmdb.org/t/p/%s%s", s.config.People.ProfileSize, profilePath)
+	}
+	return person
+}
+
+// addPersonDetails fills in a person's IMDb ID, biography and dates
+func (s *EnrichmentService) addPersonDetails(person *plugins.Person, details *types.PersonDetails) {
+	if details.ImdbID != "" {
+		person.ExternalIDs["imdb"] = details.ImdbID
+	}
+	person.Biography = details.Biography
+	person.Birthdate = details.Birthday
+	person.Deathdate = details.Deathday
+	person.PlaceOfBirth = details.PlaceOfBirth
+	if details.KnownForDepartment != "" {
+		person.KnownForDepartment = details.KnownForDepartment
+	}
+}
+
+// fetchMovieCredits returns a movie's cast and crew, from the cache when
+// possible
+func (s *EnrichmentService) fetchMovieCredits(tmdbID int) (*types.CreditsResponse, error) {
+	url := fmt.Sprintf("https://api.themoviedb.org/3/movie/%d/credits", tmdbID)
+	return s.fetchCredits(fmt.Sprintf("credits:movie:%d", tmdbID), url, fmt.Sprintf("credits of movie %d", tmdbID))
+}
+
+// fetchEpisodeCredits returns an episode's cast, guest stars and crew, from
+// the cache when possible
+func (s *EnrichmentService) fetchEpisodeCredits(showID, seasonNumber, episodeNumber int) (*types.CreditsResponse, error) {
+	url := fmt.Sprintf("https://api.themoviedb.org/3/tv/%d/season/%d/episode/%d/credits", showID, seasonNumber, episodeNumber)
+	return s.fetchCredits(
+		fmt.Sprintf("credits:episode:%d:%d:%d", showID, seasonNumber, episodeNumber),
+		url,
+		fmt.Sprintf("credits of show %d S%02dE%02d", showID, seasonNumber, episodeNumber),
+	)
+}
+
+func (s *EnrichmentService) fetchCredits(cacheKey, url, operation string) (*types.CreditsResponse, error) {
+	queryHash := s.generateQueryHash(cacheKey)
+	var response types.CreditsResponse
+	if err := s.getCachedJSON("credits", queryHash, &response); err == nil {
+		return &response, nil
+	}
+	if err := s.makeAPIRequestWithRetries(url, &response, operation); err != nil {
+		return nil, err
+	}
+	s.cacheJSON("credits", queryHash, response)
+	return &response, nil
+}
+
+// fetchPerson returns a person's details, from the cache when possible
+func (s *EnrichmentService) fetchPerson(tmdbID int) (*types.PersonDetails, error) {
+	queryHash := s.generateQueryHash(fmt.Sprintf("person:%d", tmdbID))
+	var person types.PersonDetails
+	if err := s.getCachedJSON("person", queryHash, &person); err == nil {
+		return &person, nil
+	}
+	url := fmt.Sprintf("https://api.themoviedb.org/3/person/%d", tmdbID)
+	if err := s.makeAPIRequestWithRetries(url, &person, fmt.Sprintf("details of person %d", tmdbID)); err != nil {
+		return nil, err
+	}
+	s.cacheJSON("person", queryHash, person)
+	return &person, nil
+}
@@ -0,0 +1,73 @@
+package services
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/mantonx/viewra/plugins/tmdb_enricher_v2/internal/types"
+)
+
+// prefetchShow fetches a show's details and every season in the background
+// the first time one of its episodes is matched, so the rest of a new show's
+// episodes, scanned right after, enrich from the cache instead of fetching
+// their season mid-scan. A show is prefetched again once its cached seasons
+// may have expired.
+func (s *EnrichmentService) prefetchShow(showID int) {
+	if !s.config.Features.EnableEpisodes || showID <= 0 {
+		return
+	}
+
+	s.prefetchMu.Lock()
+	started, seen := s.prefetchedShows[showID]
+	if seen && time.Since(started) < s.config.Cache.GetCacheDuration() {
+		s.prefetchMu.Unlock()
+		return
+	}
+	s.prefetchedShows[showID] = time.Now()
+	s.prefetchMu.Unlock()
+
+	go func() {
+		start := time.Now()
+		details, err := s.fetchShow(showID)
+		if err != nil {
+			s.logger.Warn("failed to prefetch show details", "error", err, "tmdb_id", showID)
+			s.forgetPrefetch(showID)
+			return
+		}
+
+		fetched := 0
+		for _, season := range details.Seasons {
+			if _, err := s.fetchSeason(showID, season.SeasonNumber); err != nil {
+				s.logger.Warn("failed to prefetch season", "error", err, "tmdb_id", showID, "season", season.SeasonNumber)
+				continue
+			}
+			fetched++
+		}
+		s.logger.Debug("prefetched show", "tmdb_id", showID, "name", details.Name,
+			"seasons", fetched, "duration_ms", time.Since(start).Milliseconds())
+	}()
+}
+
+// forgetPrefetch lets the next episode of a show retry a failed prefetch
+func (s *EnrichmentService) forgetPrefetch(showID int) {
+	s.prefetchMu.Lock()
+	delete(s.prefetchedShows, showID)
+	s.prefetchMu.Unlock()
+}
+
+// fetchShow returns a show's full details with its season list, from the
+// cache when possible
+func (s *EnrichmentService) fetchShow(showID int) (*types.TVSeriesDetails, error) {
+	queryHash := s.generateQueryHash(fmt.Sprintf("show:%d", showID))
+	var details types.TVSeriesDetails
+	if err := s.getCachedJSON("show", queryHash, &details); err == nil {
+		return &details, nil
+	}
+
+	showURL := fmt.Sprintf("https://api.themoviedb.org/3/tv/%d?language=%s", showID, s.config.API.Language)
+	if err := s.makeAPIRequestWithRetries(showURL, &details, fmt.Sprintf("show %d", showID)); err != nil {
+		return nil, err
+	}
+	s.cacheJSON("show", queryHash, &details)
+	return &details, nil
+}
@@ -18,6 +18,7 @@ type Result struct {
 	ReleaseDate      string   `json:"release_date,omitempty"`   // Movies
 	FirstAirDate     string   `json:"first_air_date,omitempty"` // TV Shows
 	GenreIDs         []int    `json:"genre_ids"`
+	Genres           []Genre  `json:"genres,omitempty"` // Details responses, in place of GenreIDs
 	VoteAverage      float64  `json:"vote_average"`
 	VoteCount        int      `json:"vote_count"`
 	Popularity       float64  `json:"popularity"`
@@ -30,6 +31,20 @@ type Result struct {
 	OriginalLanguage string   `json:"original_language"`
 }
 
+// ChangesResponse is a page of TMDb's change list for movies or shows
+type ChangesResponse struct {
+	Page         int           `json:"page"`
+	Results      []ChangedItem `json:"results"`
+	TotalPages   int           `json:"total_pages"`
+	TotalResults int           `json:"total_results"`
+}
+
+// ChangedItem is a movie or show edited on TMDb
+type ChangedItem struct {
+	ID    int  `json:"id"`
+	Adult bool `json:"adult"`
+}
+
 // TMDb Images API response types
 type ImagesResponse struct {
 	ID        int         `json:"id"`
@@ -96,33 +111,125 @@ type MovieDetails struct {
 	Genres           []Genre `json:"genres"`
 	PosterPath       string  `json:"poster_path"`
 	BackdropPath     string  `json:"backdrop_path"`
+
+	BelongsToCollection *CollectionSummary `json:"belongs_to_collection"`
+}
+
+// CollectionSummary is the collection a movie belongs to, as its details
+// name it
+type CollectionSummary struct {
+	ID           int    `json:"id"`
+	Name         string `json:"name"`
+	PosterPath   string `json:"poster_path"`
+	BackdropPath string `json:"backdrop_path"`
+}
+
+// CollectionDetails is a collection and the movies in it
+type CollectionDetails struct {
+	ID           int      `json:"id"`
+	Name         string   `json:"name"`
+	Overview     string   `json:"overview"`
+	PosterPath   string   `json:"poster_path"`
+	BackdropPath string   `json:"backdrop_path"`
+	Parts        []Result `json:"parts"`
 }
 
 // TV Series details response
 type TVSeriesDetails struct {
-	ID               int      `json:"id"`
-	Name             string   `json:"name"`
-	OriginalName     string   `json:"original_name"`
-	Overview         string   `json:"overview"`
-	FirstAirDate     string   `json:"first_air_date"`
-	LastAirDate      string   `json:"last_air_date"`
-	Status           string   `json:"status"`
-	Type             string   `json:"type"`
-	InProduction     bool     `json:"in_production"`
-	NumberOfSeasons  int      `json:"number_of_seasons"`
-	NumberOfEpisodes int      `json:"number_of_episodes"`
-	EpisodeRunTime   []int    `json:"episode_run_time"`
-	Genres           []Genre  `json:"genres"`
-	VoteAverage      float64  `json:"vote_average"`
-	VoteCount        int      `json:"vote_count"`
-	Popularity       float64  `json:"popularity"`
-	PosterPath       string   `json:"poster_path"`
-	BackdropPath     string   `json:"backdrop_path"`
-	OriginCountry    []string `json:"origin_country"`
-	OriginalLanguage string   `json:"original_language"`
+	ID               int               `json:"id"`
+	Name             string            `json:"name"`
+	OriginalName     string            `json:"original_name"`
+	Overview         string            `json:"overview"`
+	FirstAirDate     string            `json:"first_air_date"`
+	LastAirDate      string            `json:"last_air_date"`
+	Status           string            `json:"status"`
+	Type             string            `json:"type"`
+	InProduction     bool              `json:"in_production"`
+	NumberOfSeasons  int               `json:"number_of_seasons"`
+	NumberOfEpisodes int               `json:"number_of_episodes"`
+	EpisodeRunTime   []int             `json:"episode_run_time"`
+	Genres           []Genre           `json:"genres"`
+	VoteAverage      float64           `json:"vote_average"`
+	VoteCount        int               `json:"vote_count"`
+	Popularity       float64           `json:"popularity"`
+	PosterPath       string            `json:"poster_path"`
+	BackdropPath     string            `json:"backdrop_path"`
+	OriginCountry    []string          `json:"origin_country"`
+	OriginalLanguage string            `json:"original_language"`
+	Seasons          []TVSeasonSummary `json:"seasons"`
+}
+
+// TVSeasonSummary is a season as listed in a show's details
+type TVSeasonSummary struct {
+	ID           int    `json:"id"`
+	Name         string `json:"name"`
+	SeasonNumber int    `json:"season_number"`
+	EpisodeCount int    `json:"episode_count"`
+	AirDate      string `json:"air_date"`
 }
 
 type Genre struct {
 	ID   int    `json:"id"`
 	Name string `json:"name"`
 }
+
+// ReleaseDatesResponse lists a movie's releases by country, with the
+// certification each was given
+type ReleaseDatesResponse struct {
+	Results []struct {
+		Country      string `json:"iso_3166_1"`
+		ReleaseDates []struct {
+			Certification string `json:"certification"`
+			Type          int    `json:"type"`
+		} `json:"release_dates"`
+	} `json:"results"`
+}
+
+// ContentRatingsResponse lists a show's rating in each country
+type ContentRatingsResponse struct {
+	Results []struct {
+		Country string `json:"iso_3166_1"`
+		Rating  string `json:"rating"`
+	} `json:"results"`
+}
+
+// CreditsResponse lists the cast and crew of a movie or episode. Guest stars
+// are only listed for episodes.
+type CreditsResponse struct {
+	Cast       []CastMember `json:"cast"`
+	GuestStars []CastMember `json:"guest_stars,omitempty"`
+	Crew       []CrewMember `json:"crew"`
+}
+
+// CastMember is an actor credited with a character
+type CastMember struct {
+	ID                 int    `json:"id"`
+	Name               string `json:"name"`
+	Character          string `json:"character"`
+	Order              int    `json:"order"`
+	ProfilePath        string `json:"profile_path"`
+	KnownForDepartment string `json:"known_for_department"`
+}
+
+// CrewMember is a person credited with a job
+type CrewMember struct {
+	ID                 int    `json:"id"`
+	Name               string `json:"name"`
+	Job                string `json:"job"`
+	Department         string `json:"department"`
+	ProfilePath        string `json:"profile_path"`
+	KnownForDepartment string `json:"known_for_department"`
+}
+
+// PersonDetails is a person's biography and dates
+type PersonDetails struct {
+	ID                 int    `json:"id"`
+	Name               string `json:"name"`
+	Biography          string `json:"biography"`
+	Birthday           string `json:"birthday"`
+	Deathday           string `json:"deathday"`
+	PlaceOfBirth       string `json:"place_of_birth"`
+	KnownForDepartment string `json:"known_for_department"`
+	ProfilePath        string `json:"profile_path"`
+	ImdbID             string `json:"imdb_id"`
+}
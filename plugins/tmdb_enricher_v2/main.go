@@ -4,10 +4,11 @@ import (
 	"context"
 	"fmt"
 	"path/filepath"
+	"strconv"
 	"strings"
-	"time"
 
 	plugins "github.com/mantonx/viewra/sdk"
+	"google.golang.org/grpc/connectivity"
 	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
 
@@ -75,7 +76,7 @@ func (t *TMDbEnricherV2) Initialize(ctx *plugins.PluginContext) error {
 
 	// Auto-migrate the database
 	t.logger.Info("Starting database migration")
-	if err := t.db.AutoMigrate(&models.TMDbCache{}, &models.TMDbEnrichment{}, &models.TMDbArtwork{}); err != nil {
+	if err := models.Migrate(t.db); err != nil {
 		t.logger.Error("Database migration failed", "error", err)
 		return fmt.Errorf("failed to migrate database: %w", err)
 	}
@@ -84,7 +85,11 @@ func (t *TMDbEnricherV2) Initialize(ctx *plugins.PluginContext) error {
 	// Initialize unified client for host services
 	if ctx.HostServiceAddr != "" {
 		t.logger.Info("Connecting to host services", "addr", ctx.HostServiceAddr)
-		client, err := plugins.NewUnifiedServiceClient(ctx.HostServiceAddr)
+		clientConfig := plugins.DefaultClientConfig()
+		clientConfig.OnStateChange = func(state connectivity.State) {
+			t.logger.Info("host service connection state changed", "state", state.String())
+		}
+		client, err := plugins.NewUnifiedServiceClientWithConfig(ctx.HostServiceAddr, clientConfig)
 		if err != nil {
 			t.logger.Warn("failed to connect to host services", "error", err)
 		} else {
@@ -114,6 +119,9 @@ func (t *TMDbEnricherV2) Start() error {
 	if t.cacheManager != nil {
 		go t.cacheManager.StartCleanupRoutine(context.Background())
 	}
+	if t.enricher != nil {
+		go t.enricher.StartChangePolling(context.Background())
+	}
 
 	return nil
 }
@@ -171,6 +179,7 @@ func (t *TMDbEnricherV2) GetModels() []string {
 		"TMDbCache",
 		"TMDbEnrichment",
 		"TMDbArtwork",
+		"TMDbChangeSync",
 	}
 }
 
@@ -181,12 +190,7 @@ func (t *TMDbEnricherV2) Migrate(connectionString string) error {
 	}
 
 	// Auto-migrate all models
-	err = db.AutoMigrate(
-		&models.TMDbCache{},
-		&models.TMDbEnrichment{},
-		&models.TMDbArtwork{},
-	)
-	if err != nil {
+	if err := models.Migrate(db); err != nil {
 		return fmt.Errorf("failed to migrate database: %w", err)
 	}
 
@@ -200,19 +204,31 @@ func (t *TMDbEnricherV2) Rollback(connectionString string) error {
 		return fmt.Errorf("failed to connect to database: %w", err)
 	}
 
-	return db.Migrator().DropTable(&models.TMDbCache{}, &models.TMDbEnrichment{}, &models.TMDbArtwork{})
+	return db.Migrator().DropTable(&models.TMDbCache{}, &models.TMDbEnrichment{}, &models.TMDbArtwork{}, &models.TMDbChangeSync{})
 }
 
 // Scanner hook service implementation
 func (t *TMDbEnricherV2) OnMediaFileScanned(mediaFileID string, filePath string, metadata map[string]string) error {
 	if !t.configService.GetTMDbConfig().Features.AutoEnrich {
 		t.logger.Debug("auto-enrichment disabled, skipping", "file", filePath)
-		return nil
+		return plugins.SkipHook(plugins.SkipReasonDisabled, "auto-enrichment is disabled")
+	}
+	return t.enrichMediaFile(mediaFileID, filePath, metadata)
+}
+
+// enrichMediaFile matches a file on TMDb and downloads its artwork
+func (t *TMDbEnricherV2) enrichMediaFile(mediaFileID string, filePath string, metadata map[string]string) error {
+	// Music and home video libraries have nothing to match on TMDb
+	switch metadata[plugins.HookMetadataLibraryType] {
+	case "music", "home":
+		return plugins.SkipHook(plugins.SkipReasonUnsupportedType, "not a movie or TV library")
 	}
 
 	// Use enrichment service to process the file
 	if err := t.enricher.ProcessMediaFile(mediaFileID, filePath, metadata); err != nil {
-		t.logger.Warn("enrichment failed", "error", err, "media_file_id", mediaFileID)
+		if _, skipped := plugins.AsSkipError(err); !skipped {
+			t.logger.Warn("enrichment failed", "error", err, "media_file_id", mediaFileID)
+		}
 		return err
 	}
 
@@ -236,6 +252,29 @@ func (t *TMDbEnricherV2) OnMediaFileScanned(mediaFileID string, filePath string,
 	return nil
 }
 
+// OnMediaFileRemoved drops what the plugin stored for a deleted file
+func (t *TMDbEnricherV2) OnMediaFileRemoved(mediaFileID string, filePath string, metadata map[string]string) error {
+	if err := t.enricher.RemoveMediaFile(mediaFileID); err != nil {
+		t.logger.Warn("failed to remove enrichment", "error", err, "media_file_id", mediaFileID)
+		return err
+	}
+	return nil
+}
+
+// OnMediaFileUpdated matches a replaced or retagged file again, as its title
+// or year may have changed. Files a user identified by hand are matched to
+// the TMDb ID they picked, even with auto-enrichment off.
+func (t *TMDbEnricherV2) OnMediaFileUpdated(mediaFileID string, filePath string, metadata map[string]string) error {
+	identified := metadata[plugins.HookMetadataIdentifyID] != ""
+	if !identified && !t.configService.GetTMDbConfig().Features.AutoEnrich {
+		return plugins.SkipHook(plugins.SkipReasonDisabled, "auto-enrichment is disabled")
+	}
+	if err := t.enricher.RemoveMediaFile(mediaFileID); err != nil {
+		return err
+	}
+	return t.enrichMediaFile(mediaFileID, filePath, metadata)
+}
+
 func (t *TMDbEnricherV2) OnScanStarted(scanJobID, libraryID uint32, libraryPath string) error {
 	t.logger.Info("scan started", "scan_job_id", scanJobID, "library_id", libraryID, "path", libraryPath)
 
@@ -296,14 +335,52 @@ func (t *TMDbEnricherV2) GetSupportedTypes() []string {
 	return types
 }
 
-// Search service implementation (placeholder - to be implemented)
+// Search finds the movies and shows a user picks from when identifying a
+// file by hand, by title and optional year, or by tmdb_id. media_type, movie
+// or tv, limits the results to one type. With file_path and no title, the
+// file is searched for as a scan would match it, which enrichment
+// simulations use; results are scored either way.
 func (t *TMDbEnricherV2) Search(ctx context.Context, query map[string]string, limit, offset uint32) ([]*plugins.SearchResult, uint32, bool, error) {
-	// Future implementation: Use enrichment service to search TMDb
-	return nil, 0, false, fmt.Errorf("search service not yet implemented in v2 - use enrichment service directly")
+	if t.enricher == nil {
+		return nil, 0, false, fmt.Errorf("enrichment service not initialized")
+	}
+
+	if tmdbID, err := strconv.Atoi(query["tmdb_id"]); err == nil && tmdbID > 0 {
+		result, err := t.enricher.LookupCandidate(tmdbID, query["media_type"])
+		if err != nil {
+			return nil, 0, false, err
+		}
+		return []*plugins.SearchResult{result}, 1, false, nil
+	}
+
+	filePath := query[plugins.SearchQueryFilePath]
+	title := strings.TrimSpace(query["title"])
+	year, _ := strconv.Atoi(query["year"])
+	if title == "" && filePath != "" {
+		title, year = t.enricher.FileQuery(filePath)
+	}
+	if title == "" {
+		return nil, 0, false, &plugins.PluginError{Code: plugins.ErrorCodeInvalidArgument, Message: "title or tmdb_id is required"}
+	}
+
+	results, err := t.enricher.SearchCandidates(title, year, query["media_type"], filePath)
+	if err != nil {
+		return nil, 0, false, err
+	}
+
+	total := uint32(len(results))
+	if offset >= total {
+		return []*plugins.SearchResult{}, total, false, nil
+	}
+	end := total
+	if limit > 0 && offset+limit < total {
+		end = offset + limit
+	}
+	return results[offset:end], total, end < total, nil
 }
 
 func (t *TMDbEnricherV2) GetSearchCapabilities(ctx context.Context) ([]string, bool, uint32, error) {
-	return []string{"title", "year", "tmdb_id"}, true, 100, nil
+	return []string{"title", "year", "tmdb_id", "media_type", plugins.SearchQueryFilePath}, true, 100, nil
 }
 
 // Asset service implementation (placeholder - integrates with artwork service)
@@ -385,9 +462,10 @@ func (t *TMDbEnricherV2) ConfigurationService() plugins.ConfigurationService {
 	return t.configService
 }
 
-// PerformanceMonitorService returns the performance monitoring service
+// PerformanceMonitorService returns the performance monitoring service, whose
+// snapshot includes TMDb request latency
 func (t *TMDbEnricherV2) PerformanceMonitorService() plugins.PerformanceMonitorService {
-	return &performanceServiceAdapter{monitor: t.performanceMonitor}
+	return t.performanceMonitor
 }
 
 // TranscodingService returns nil since this is not a transcoding plugin
@@ -400,31 +478,6 @@ func (t *TMDbEnricherV2) EnhancedAdminPageService() plugins.EnhancedAdminPageSer
 	return nil // Return nil if enhanced admin page service is not implemented
 }
 
-// performanceServiceAdapter adapts BasePerformanceMonitor to PerformanceMonitorService interface
-type performanceServiceAdapter struct {
-	monitor *plugins.BasePerformanceMonitor
-}
-
-func (p *performanceServiceAdapter) GetPerformanceSnapshot(ctx context.Context) (*plugins.PerformanceSnapshot, error) {
-	return p.monitor.GetSnapshot(), nil
-}
-
-func (p *performanceServiceAdapter) RecordOperation(operationName string, duration time.Duration, success bool, context string) {
-	p.monitor.RecordOperation(operationName, duration, success, context)
-}
-
-func (p *performanceServiceAdapter) RecordError(errorType, message, context, operation string) {
-	p.monitor.RecordError(errorType, message, context, operation)
-}
-
-func (p *performanceServiceAdapter) GetUptimeString() string {
-	return p.monitor.GetUptimeString()
-}
-
-func (p *performanceServiceAdapter) Reset() {
-	p.monitor.Reset()
-}
-
 // GetTMDbConfig returns the current TMDb configuration (plugin-specific method)
 func (t *TMDbEnricherV2) GetTMDbConfig() *config.Config {
 	return t.configService.GetTMDbConfig()
@@ -531,6 +584,10 @@ func (t *TMDbEnricherV2) initializeServices() error {
 	t.artwork = services.NewArtworkService(t.db, tmdbConfig, t.unifiedClient, t.logger)
 	t.logger.Info("Artwork service initialized")
 
+	// Record TMDb request latency and status codes for the admin dashboard
+	t.enricher.SetPerformanceMonitor(t.performanceMonitor)
+	t.artwork.SetPerformanceMonitor(t.performanceMonitor)
+
 	// Add configuration change callback to update services when config changes
 	t.logger.Info("Adding configuration callback")
 	t.configService.AddConfigurationCallback(t.onConfigurationChanged)
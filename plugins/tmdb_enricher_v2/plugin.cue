@@ -19,6 +19,7 @@
 		"tmdb",
 		"external-api"
 	]
+	media_types: ["movie", "tv"]
 
 	// Plugin behavior
 	enabled_by_default: true
@@ -103,9 +104,11 @@
 
 		// Matching and quality settings
 		matching: {
-			match_threshold: float64 | *0.85 // Minimum similarity score for matches
-			match_year:      bool | *true    // Use release year for matching
-			year_tolerance:  int | *2        // Allow +/- years difference
+			auto_threshold:   float64 | *0.85 // Minimum score to apply a match without review
+			review_threshold: float64 | *0.6  // Minimum score to queue a match for review, below it files are unmatched
+			match_year:       bool | *true    // Use release year for matching
+			year_tolerance:   int | *2        // Allow +/- years difference
+			learn_aliases:    bool | *true    // Remember matched release names and reuse the matches on later scans
 		}
 
 		// Cache settings
@@ -117,6 +120,21 @@
 			cleanup_interval_hours: int | *24 // Cleanup interval in hours
 		}
 
+		// TMDb change list polling
+		changes: {
+			enabled:        bool | *true // Re-fetch library items TMDb reports changed
+			interval_hours: int | *24    // Hours between polls
+		}
+
+		// Cast and crew
+		people: {
+			enabled:           bool | *true     // Record cast and crew through the host
+			max_cast:          int | *20        // Top-billed cast members recorded per item
+			details_limit:     int | *10        // Credited people per item whose biography and dates are fetched
+			download_profiles: bool | *true     // Have the host download profile images
+			profile_size:      string | *"w185" // w45, w185, h632, original
+		}
+
 		// Retry and reliability settings
 		reliability: {
 			max_retries:            int | *5        // Maximum retry attempts
@@ -0,0 +1,106 @@
+package config
+
+import (
+	"fmt"
+	"time"
+)
+
+// Config represents the complete plugin configuration structure
+// This mirrors the CUE schema defined in plugin.cue
+type Config struct {
+	API      APIConfig      `json:"api"`
+	Auth     AuthConfig     `json:"auth"`
+	Account  AccountConfig  `json:"account"`
+	Scrobble ScrobbleConfig `json:"scrobble"`
+	Sync     SyncConfig     `json:"sync"`
+}
+
+// APIConfig contains Trakt API settings
+type APIConfig struct {
+	ClientID     string  `json:"client_id"`     // Client ID of the Trakt API application (sensitive)
+	ClientSecret string  `json:"client_secret"` // Client secret, needed to refresh the access token (sensitive)
+	BaseURL      string  `json:"base_url"`      // API root, e.g. https://api.trakt.tv
+	TimeoutSec   int     `json:"timeout_sec"`   // Request timeout in seconds
+	RateLimit    float64 `json:"rate_limit"`    // Requests per second
+}
+
+// AuthConfig contains the OAuth tokens of the Trakt account
+type AuthConfig struct {
+	AccessToken  string `json:"access_token"`  // OAuth access token (sensitive)
+	RefreshToken string `json:"refresh_token"` // OAuth refresh token, used once the access token expires (sensitive)
+}
+
+// AccountConfig ties the Trakt account to a Viewra user
+type AccountConfig struct {
+	UserID uint32 `json:"user_id"` // Viewra user whose playback is scrobbled and whose history is synced
+}
+
+// ScrobbleConfig controls forwarding playback to Trakt
+type ScrobbleConfig struct {
+	Enabled bool `json:"enabled"` // Scrobble playback starts, pauses and stops
+}
+
+// SyncConfig controls syncing Trakt history back into Viewra
+type SyncConfig struct {
+	Enabled         bool `json:"enabled"`          // Sync periodically
+	IntervalMinutes int  `json:"interval_minutes"` // Minutes between syncs
+	Watched         bool `json:"watched"`          // Import watched movies and episodes
+	Ratings         bool `json:"ratings"`          // Import ratings
+}
+
+// DefaultConfig returns the default configuration
+func DefaultConfig() *Config {
+	return &Config{
+		API: APIConfig{
+			ClientID:   "", // Must be provided by user
+			BaseURL:    "https://api.trakt.tv",
+			TimeoutSec: 30,
+			RateLimit:  1, // Trakt allows one write per second
+		},
+		Scrobble: ScrobbleConfig{
+			Enabled: true,
+		},
+		Sync: SyncConfig{
+			Enabled:         true,
+			IntervalMinutes: 60,
+			Watched:         true,
+			Ratings:         true,
+		},
+	}
+}
+
+// GetRequestTimeout returns the request timeout duration
+func (c *APIConfig) GetRequestTimeout() time.Duration {
+	return time.Duration(c.TimeoutSec) * time.Second
+}
+
+// GetRequestDelay returns the minimum delay between API requests
+func (c *APIConfig) GetRequestDelay() time.Duration {
+	return time.Duration(float64(time.Second) / c.RateLimit)
+}
+
+// GetInterval returns the time between syncs
+func (c *SyncConfig) GetInterval() time.Duration {
+	return time.Duration(c.IntervalMinutes) * time.Minute
+}
+
+// Validate validates the configuration
+func (c *Config) Validate() error {
+	if c.API.BaseURL == "" {
+		return fmt.Errorf("API base URL is required")
+	}
+
+	if c.API.RateLimit <= 0 {
+		return fmt.Errorf("API rate limit must be positive")
+	}
+
+	if c.API.TimeoutSec <= 0 {
+		return fmt.Errorf("API timeout must be positive")
+	}
+
+	if c.Sync.Enabled && c.Sync.IntervalMinutes < 5 {
+		return fmt.Errorf("sync interval must be at least 5 minutes")
+	}
+
+	return nil
+}
@@ -0,0 +1,64 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Sync kinds, one TraktSyncState each
+const (
+	SyncKindHistory = "history"
+	SyncKindRatings = "ratings"
+)
+
+// TraktToken is the account's current OAuth token. Trakt hands out a new
+// refresh token with every refresh, so tokens refreshed by the plugin are
+// kept here rather than in the configuration.
+type TraktToken struct {
+	ID           uint32    `gorm:"primaryKey" json:"id"`
+	AccessToken  string    `gorm:"not null" json:"-"`
+	RefreshToken string    `json:"-"`
+	ExpiresAt    time.Time `json:"expires_at"`
+	// SeedToken is the configured refresh token these tokens descend from;
+	// once the configuration holds another, the stored tokens are dropped
+	SeedToken string    `json:"-"`
+	UpdatedAt time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// TableName returns the table name for TraktToken
+func (TraktToken) TableName() string {
+	return "trakt_tokens"
+}
+
+// TraktSyncState records how far Trakt history has been synced into Viewra
+type TraktSyncState struct {
+	Kind         string    `gorm:"primaryKey" json:"kind"` // history or ratings
+	SyncedUntil  time.Time `json:"synced_until"`           // Newest watch or rating imported
+	LastSyncedAt time.Time `json:"last_synced_at"`
+	LastError    string    `json:"last_error,omitempty"`
+}
+
+// TableName returns the table name for TraktSyncState
+func (TraktSyncState) TableName() string {
+	return "trakt_sync_state"
+}
+
+// TraktScrobble records a watch the plugin scrobbled, so syncing history
+// back doesn't import Viewra's own plays a second time
+type TraktScrobble struct {
+	HistoryID   int64     `gorm:"primaryKey;autoIncrement:false" json:"history_id"` // Trakt history ID of the watch
+	SessionID   string    `gorm:"index" json:"session_id"`                          // Viewra playback session
+	MediaFileID string    `json:"media_file_id"`
+	CreatedAt   time.Time `gorm:"autoCreateTime" json:"created_at"`
+}
+
+// TableName returns the table name for TraktScrobble
+func (TraktScrobble) TableName() string {
+	return "trakt_scrobbles"
+}
+
+// Migrate creates or updates the plugin's tables
+func Migrate(db *gorm.DB) error {
+	return db.AutoMigrate(&TraktToken{}, &TraktSyncState{}, &TraktScrobble{})
+}
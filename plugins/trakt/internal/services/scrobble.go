@@ -0,0 +1,108 @@
+package services
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/mantonx/viewra/plugins/trakt/internal/config"
+	"github.com/mantonx/viewra/plugins/trakt/internal/models"
+	"github.com/mantonx/viewra/plugins/trakt/internal/trakt"
+	plugins "github.com/mantonx/viewra/sdk"
+	"gorm.io/gorm"
+)
+
+// ScrobbleService forwards the configured user's playback to Trakt
+type ScrobbleService struct {
+	db     *gorm.DB
+	config *config.Config
+	client *trakt.Client
+	logger plugins.Logger
+}
+
+// NewScrobbleService creates a new scrobble service
+func NewScrobbleService(db *gorm.DB, cfg *config.Config, client *trakt.Client, logger plugins.Logger) *ScrobbleService {
+	return &ScrobbleService{
+		db:     db,
+		config: cfg,
+		client: client,
+		logger: logger,
+	}
+}
+
+// Scrobble reports a playback event to Trakt as a scrobble start, pause or
+// stop. Events of other users, of tracks and of media Trakt can't be told
+// about are ignored. Watches Trakt records on stop are remembered so syncing
+// doesn't import them back.
+func (s *ScrobbleService) Scrobble(action string, event *plugins.PlaybackEvent) error {
+	if !s.config.Scrobble.Enabled || event.UserID != s.config.Account.UserID {
+		return nil
+	}
+	item, ok := scrobbleItem(event)
+	if !ok {
+		s.logger.Debug("not scrobbling playback without a title or IDs", "session_id", event.SessionID, "media_type", event.MediaType)
+		return nil
+	}
+
+	result, err := s.client.Scrobble(action, item, event.Progress)
+	if errors.Is(err, trakt.ErrAlreadyScrobbled) {
+		s.logger.Debug("playback already scrobbled", "session_id", event.SessionID)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	s.logger.Debug("scrobbled playback", "action", result.Action, "session_id", event.SessionID, "progress", result.Progress)
+
+	if action == trakt.ScrobbleStop && result.Action == "scrobble" && result.ID > 0 {
+		scrobble := models.TraktScrobble{
+			HistoryID:   result.ID,
+			SessionID:   event.SessionID,
+			MediaFileID: event.MediaFileID,
+		}
+		if err := s.db.Save(&scrobble).Error; err != nil {
+			s.logger.Warn("failed to record scrobble", "error", err, "history_id", result.ID)
+		}
+	}
+	return nil
+}
+
+// scrobbleItem describes what an event is playing the way Trakt finds it:
+// by IDs where known, else by title and year
+func scrobbleItem(event *plugins.PlaybackEvent) (*trakt.ScrobbleItem, bool) {
+	switch event.MediaType {
+	case "movie":
+		movie := &trakt.Movie{
+			Title: event.Title,
+			Year:  event.Year,
+			IDs:   trakt.IDs{TMDb: parseID(event.TmdbID), IMDb: event.ImdbID},
+		}
+		if movie.Title == "" && movie.IDs.TMDb == 0 && movie.IDs.IMDb == "" {
+			return nil, false
+		}
+		return &trakt.ScrobbleItem{Movie: movie}, true
+
+	case "episode":
+		show := &trakt.Show{
+			Title: event.ShowTitle,
+			Year:  event.Year,
+			IDs:   trakt.IDs{TMDb: parseID(event.ShowTmdbID)},
+		}
+		if (show.Title == "" && show.IDs.TMDb == 0) || event.Episode <= 0 {
+			return nil, false
+		}
+		return &trakt.ScrobbleItem{
+			Show:    show,
+			Episode: &trakt.Episode{Season: event.Season, Number: event.Episode},
+		}, true
+	}
+	return nil, false
+}
+
+// parseID reads a numeric external ID, 0 when there is none
+func parseID(id string) int {
+	n, err := strconv.Atoi(id)
+	if err != nil {
+		return 0
+	}
+	return n
+}
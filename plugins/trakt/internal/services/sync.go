@@ -0,0 +1,299 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/mantonx/viewra/plugins/trakt/internal/config"
+	"github.com/mantonx/viewra/plugins/trakt/internal/models"
+	"github.com/mantonx/viewra/plugins/trakt/internal/trakt"
+	plugins "github.com/mantonx/viewra/sdk"
+	"gorm.io/gorm"
+)
+
+// firstSyncDelay gives the host time to finish starting before the first sync
+const firstSyncDelay = time.Minute
+
+// importBatchSize bounds the entries sent to the host in one import
+const importBatchSize = 500
+
+// Trakt list types synced, movies and episodes
+var syncMediaTypes = []string{"movies", "episodes"}
+
+// SyncService imports the account's Trakt watches and ratings into the
+// configured user's Viewra history. Each sync picks up where the previous
+// one stopped; the host skips watches it already has, so overlaps are
+// harmless.
+type SyncService struct {
+	db      *gorm.DB
+	config  *config.Config
+	client  *trakt.Client
+	history plugins.WatchHistoryServiceClient
+	logger  plugins.Logger
+
+	mu sync.Mutex // Held for the length of a sync
+}
+
+// NewSyncService creates a new sync service. history is the host's watch
+// history service, nil when the host services are unreachable.
+func NewSyncService(db *gorm.DB, cfg *config.Config, client *trakt.Client, history plugins.WatchHistoryServiceClient, logger plugins.Logger) *SyncService {
+	return &SyncService{
+		db:      db,
+		config:  cfg,
+		client:  client,
+		history: history,
+		logger:  logger,
+	}
+}
+
+// Run syncs every configured interval until ctx is done
+func (s *SyncService) Run(ctx context.Context) {
+	timer := time.NewTimer(firstSyncDelay)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-timer.C:
+		}
+
+		if err := s.Sync(ctx); err != nil && ctx.Err() == nil {
+			s.logger.Warn("Trakt sync failed", "error", err)
+		}
+		timer.Reset(s.config.Sync.GetInterval())
+	}
+}
+
+// Sync imports the watches and ratings added on Trakt since the last sync
+func (s *SyncService) Sync(ctx context.Context) error {
+	if s.history == nil {
+		return fmt.Errorf("host watch history service is not available")
+	}
+	if !s.client.Authorized() {
+		return trakt.ErrNotAuthorized
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.config.Sync.Watched {
+		if err := s.syncHistory(ctx); err != nil {
+			return err
+		}
+	}
+	if s.config.Sync.Ratings {
+		if err := s.syncRatings(ctx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// syncHistory imports the watches made since the newest one imported
+func (s *SyncService) syncHistory(ctx context.Context) error {
+	state := s.syncState(models.SyncKindHistory)
+	syncedUntil := state.SyncedUntil
+	imported := 0
+
+	for _, mediaType := range syncMediaTypes {
+		for page := 1; ; page++ {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			items, pages, err := s.client.History(mediaType, state.SyncedUntil, page)
+			if err != nil {
+				return s.saveSyncState(models.SyncKindHistory, state.SyncedUntil, err)
+			}
+
+			scrobbled, err := s.scrobbledHistoryIDs(items)
+			if err != nil {
+				return s.saveSyncState(models.SyncKindHistory, state.SyncedUntil, err)
+			}
+			entries := make([]*plugins.WatchHistoryEntry, 0, len(items))
+			for _, item := range items {
+				if item.WatchedAt.After(syncedUntil) {
+					syncedUntil = item.WatchedAt
+				}
+				// Viewra's own plays are already in its history
+				if scrobbled[item.ID] {
+					continue
+				}
+				if entry, ok := historyEntry(item); ok {
+					entries = append(entries, entry)
+				}
+			}
+			if err := s.importEntries(ctx, entries); err != nil {
+				return s.saveSyncState(models.SyncKindHistory, state.SyncedUntil, err)
+			}
+			imported += len(entries)
+
+			if page >= pages {
+				break
+			}
+		}
+	}
+
+	s.logger.Info("synced Trakt history", "watches", imported, "synced_until", syncedUntil.Format(time.RFC3339))
+	return s.saveSyncState(models.SyncKindHistory, syncedUntil, nil)
+}
+
+// syncRatings imports the ratings made since the newest one imported.
+// Trakt lists all ratings at once, so older ones are dropped here.
+func (s *SyncService) syncRatings(ctx context.Context) error {
+	state := s.syncState(models.SyncKindRatings)
+	syncedUntil := state.SyncedUntil
+
+	var entries []*plugins.WatchHistoryEntry
+	for _, mediaType := range syncMediaTypes {
+		items, err := s.client.Ratings(mediaType)
+		if err != nil {
+			return s.saveSyncState(models.SyncKindRatings, state.SyncedUntil, err)
+		}
+		for _, item := range items {
+			if !item.RatedAt.After(state.SyncedUntil) {
+				continue
+			}
+			if item.RatedAt.After(syncedUntil) {
+				syncedUntil = item.RatedAt
+			}
+			if entry, ok := ratingEntry(item); ok {
+				entries = append(entries, entry)
+			}
+		}
+	}
+	if err := s.importEntries(ctx, entries); err != nil {
+		return s.saveSyncState(models.SyncKindRatings, state.SyncedUntil, err)
+	}
+
+	s.logger.Info("synced Trakt ratings", "ratings", len(entries), "synced_until", syncedUntil.Format(time.RFC3339))
+	return s.saveSyncState(models.SyncKindRatings, syncedUntil, nil)
+}
+
+// importEntries sends entries to the host in batches
+func (s *SyncService) importEntries(ctx context.Context, entries []*plugins.WatchHistoryEntry) error {
+	for start := 0; start < len(entries); start += importBatchSize {
+		end := min(start+importBatchSize, len(entries))
+		resp, err := s.history.ImportHistory(ctx, &plugins.WatchHistoryImportRequest{
+			UserID:  s.config.Account.UserID,
+			Entries: entries[start:end],
+		})
+		if err != nil {
+			return fmt.Errorf("failed to import history: %w", err)
+		}
+		if len(resp.Unmatched) > 0 {
+			s.logger.Debug("Trakt history not in the library", "unmatched", len(resp.Unmatched), "examples", resp.Unmatched[:min(5, len(resp.Unmatched))])
+		}
+	}
+	return nil
+}
+
+// scrobbledHistoryIDs returns which of the items the plugin scrobbled itself
+func (s *SyncService) scrobbledHistoryIDs(items []trakt.HistoryItem) (map[int64]bool, error) {
+	ids := make([]int64, 0, len(items))
+	for _, item := range items {
+		ids = append(ids, item.ID)
+	}
+	scrobbled := make(map[int64]bool)
+	if len(ids) == 0 {
+		return scrobbled, nil
+	}
+
+	var known []int64
+	if err := s.db.Model(&models.TraktScrobble{}).Where("history_id IN ?", ids).Pluck("history_id", &known).Error; err != nil {
+		return nil, fmt.Errorf("failed to look up scrobbles: %w", err)
+	}
+	for _, id := range known {
+		scrobbled[id] = true
+	}
+	return scrobbled, nil
+}
+
+// syncState returns the sync state of kind, zero before its first sync
+func (s *SyncService) syncState(kind string) models.TraktSyncState {
+	state := models.TraktSyncState{Kind: kind}
+	if err := s.db.Where("kind = ?", kind).Limit(1).Find(&state).Error; err != nil {
+		s.logger.Warn("failed to load sync state", "error", err, "kind", kind)
+	}
+	return state
+}
+
+// saveSyncState records a sync of kind, with its error if it failed, and
+// returns the error
+func (s *SyncService) saveSyncState(kind string, syncedUntil time.Time, syncErr error) error {
+	state := models.TraktSyncState{
+		Kind:         kind,
+		SyncedUntil:  syncedUntil,
+		LastSyncedAt: time.Now(),
+	}
+	if syncErr != nil {
+		state.LastError = syncErr.Error()
+	}
+	if err := s.db.Save(&state).Error; err != nil {
+		s.logger.Warn("failed to save sync state", "error", err, "kind", kind)
+	}
+	return syncErr
+}
+
+// historyEntry converts a Trakt watch into a history entry
+func historyEntry(item trakt.HistoryItem) (*plugins.WatchHistoryEntry, bool) {
+	entry, ok := mediaEntry(item.Type, item.Movie, item.Show, item.Episode)
+	if !ok {
+		return nil, false
+	}
+	entry.Kind = plugins.WatchHistoryKindWatch
+	entry.Date = item.WatchedAt
+	entry.Completed = true
+	return entry, true
+}
+
+// ratingEntry converts a Trakt rating into a history entry
+func ratingEntry(item trakt.RatingItem) (*plugins.WatchHistoryEntry, bool) {
+	entry, ok := mediaEntry(item.Type, item.Movie, item.Show, item.Episode)
+	if !ok {
+		return nil, false
+	}
+	entry.Kind = plugins.WatchHistoryKindRating
+	entry.Date = item.RatedAt
+	entry.Rating = item.Rating
+	return entry, true
+}
+
+// mediaEntry describes a Trakt movie or episode by the titles and IDs the
+// host matches history with
+func mediaEntry(itemType string, movie *trakt.Movie, show *trakt.Show, episode *trakt.Episode) (*plugins.WatchHistoryEntry, bool) {
+	switch {
+	case itemType == "movie" && movie != nil:
+		return &plugins.WatchHistoryEntry{
+			MediaType: "movie",
+			Title:     movie.Title,
+			Year:      movie.Year,
+			TmdbID:    formatID(movie.IDs.TMDb),
+			ImdbID:    movie.IDs.IMDb,
+		}, true
+	case itemType == "episode" && show != nil && episode != nil:
+		return &plugins.WatchHistoryEntry{
+			MediaType:  "episode",
+			Title:      episode.Title,
+			Year:       show.Year,
+			ShowTitle:  show.Title,
+			ShowTmdbID: formatID(show.IDs.TMDb),
+			Season:     episode.Season,
+			Episode:    episode.Number,
+			TmdbID:     formatID(episode.IDs.TMDb),
+			ImdbID:     episode.IDs.IMDb,
+		}, true
+	}
+	return nil, false
+}
+
+// formatID writes a numeric external ID, empty when there is none
+func formatID(id int) string {
+	if id == 0 {
+		return ""
+	}
+	return strconv.Itoa(id)
+}
@@ -0,0 +1,342 @@
+package trakt
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mantonx/viewra/plugins/trakt/internal/config"
+	plugins "github.com/mantonx/viewra/sdk"
+)
+
+// Scrobble actions, named after their API endpoints
+const (
+	ScrobbleStart = "start"
+	ScrobblePause = "pause"
+	ScrobbleStop  = "stop"
+)
+
+// HistoryPageSize is the number of history items fetched per page
+const HistoryPageSize = 100
+
+// tokenRefreshMargin refreshes access tokens this long before they expire
+const tokenRefreshMargin = time.Hour
+
+// ErrAlreadyScrobbled is returned by a stop when Trakt recorded the same
+// watch moments ago, e.g. because the player reported the end twice
+var ErrAlreadyScrobbled = errors.New("already scrobbled")
+
+// ErrNotAuthorized is returned when no access token is configured
+var ErrNotAuthorized = errors.New("no Trakt access token configured")
+
+// IDs are the IDs Trakt knows a movie, show or episode by
+type IDs struct {
+	Trakt int64  `json:"trakt,omitempty"`
+	Slug  string `json:"slug,omitempty"`
+	IMDb  string `json:"imdb,omitempty"`
+	TMDb  int    `json:"tmdb,omitempty"`
+	TVDB  int    `json:"tvdb,omitempty"`
+}
+
+// Movie is a Trakt movie
+type Movie struct {
+	Title string `json:"title,omitempty"`
+	Year  int    `json:"year,omitempty"`
+	IDs   IDs    `json:"ids"`
+}
+
+// Show is a Trakt show
+type Show struct {
+	Title string `json:"title,omitempty"`
+	Year  int    `json:"year,omitempty"`
+	IDs   IDs    `json:"ids"`
+}
+
+// Episode is an episode of a Trakt show
+type Episode struct {
+	Season int    `json:"season"`
+	Number int    `json:"number"`
+	Title  string `json:"title,omitempty"`
+	IDs    IDs    `json:"ids"`
+}
+
+// ScrobbleItem is what is being watched: a movie, or an episode of a show
+type ScrobbleItem struct {
+	Movie   *Movie   `json:"movie,omitempty"`
+	Show    *Show    `json:"show,omitempty"`
+	Episode *Episode `json:"episode,omitempty"`
+}
+
+// ScrobbleResult is Trakt's answer to a scrobble. A stop past 80% progress
+// is recorded as a watch with action "scrobble" and its history ID.
+type ScrobbleResult struct {
+	ID       int64   `json:"id"`
+	Action   string  `json:"action"` // start, pause or scrobble
+	Progress float64 `json:"progress"`
+}
+
+// HistoryItem is a watch in the account's history
+type HistoryItem struct {
+	ID        int64     `json:"id"`
+	WatchedAt time.Time `json:"watched_at"`
+	Action    string    `json:"action"` // scrobble, checkin or watch
+	Type      string    `json:"type"`   // movie or episode
+	Movie     *Movie    `json:"movie,omitempty"`
+	Show      *Show     `json:"show,omitempty"`
+	Episode   *Episode  `json:"episode,omitempty"`
+}
+
+// RatingItem is a rating of the account
+type RatingItem struct {
+	RatedAt time.Time `json:"rated_at"`
+	Rating  int       `json:"rating"` // 1-10
+	Type    string    `json:"type"`   // movie or episode
+	Movie   *Movie    `json:"movie,omitempty"`
+	Show    *Show     `json:"show,omitempty"`
+	Episode *Episode  `json:"episode,omitempty"`
+}
+
+// Token is an OAuth token of the account
+type Token struct {
+	AccessToken  string
+	RefreshToken string
+	ExpiresAt    time.Time // Zero when unknown
+}
+
+// Client handles Trakt API interactions. Access tokens are refreshed before
+// they expire, or when the API rejects them, and handed to onRefresh to be
+// kept for the next start.
+type Client struct {
+	config     *config.Config
+	logger     plugins.Logger
+	httpClient *http.Client
+	monitor    *plugins.BasePerformanceMonitor
+	onRefresh  func(Token)
+
+	mu          sync.Mutex
+	token       Token
+	lastAPICall time.Time
+}
+
+// NewClient creates a new Trakt API client using token. Its requests are
+// recorded in monitor when it is set.
+func NewClient(cfg *config.Config, token Token, logger plugins.Logger, monitor *plugins.BasePerformanceMonitor, onRefresh func(Token)) *Client {
+	return &Client{
+		config:     cfg,
+		logger:     logger,
+		httpClient: plugins.InstrumentProviderClient(plugins.NewProviderHTTPClient(cfg.API.GetRequestTimeout()), monitor, "trakt"),
+		monitor:    monitor,
+		onRefresh:  onRefresh,
+		token:      token,
+	}
+}
+
+// Authorized reports whether the client has an access token
+func (c *Client) Authorized() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.token.AccessToken != ""
+}
+
+// Scrobble reports that playback of item started, paused or stopped at
+// progress percent
+func (c *Client) Scrobble(action string, item *ScrobbleItem, progress float64) (*ScrobbleResult, error) {
+	body := struct {
+		*ScrobbleItem
+		Progress float64 `json:"progress"`
+	}{item, progress}
+
+	var result ScrobbleResult
+	status, _, err := c.call("POST", "/scrobble/"+action, body, &result)
+	if status == http.StatusConflict {
+		return nil, ErrAlreadyScrobbled
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to scrobble %s: %w", action, err)
+	}
+	return &result, nil
+}
+
+// History returns a page of the account's watched movies or episodes
+// since startAt, oldest first, and the number of pages
+func (c *Client) History(mediaType string, startAt time.Time, page int) ([]HistoryItem, int, error) {
+	query := url.Values{}
+	query.Set("page", strconv.Itoa(page))
+	query.Set("limit", strconv.Itoa(HistoryPageSize))
+	if !startAt.IsZero() {
+		query.Set("start_at", startAt.UTC().Format(time.RFC3339))
+	}
+
+	var items []HistoryItem
+	_, header, err := c.call("GET", "/sync/history/"+mediaType+"?"+query.Encode(), nil, &items)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get %s history: %w", mediaType, err)
+	}
+	pages, _ := strconv.Atoi(header.Get("X-Pagination-Page-Count"))
+	return items, pages, nil
+}
+
+// Ratings returns all of the account's ratings of movies or episodes
+func (c *Client) Ratings(mediaType string) ([]RatingItem, error) {
+	var items []RatingItem
+	if _, _, err := c.call("GET", "/sync/ratings/"+mediaType, nil, &items); err != nil {
+		return nil, fmt.Errorf("failed to get %s ratings: %w", mediaType, err)
+	}
+	return items, nil
+}
+
+// call sends an API request, decoding the JSON response into result and
+// returning the response headers, which carry the pagination of lists. A
+// rejected access token is refreshed and the request retried once.
+func (c *Client) call(method, apiPath string, body interface{}, result interface{}) (int, http.Header, error) {
+	if err := c.refreshIfExpiring(); err != nil {
+		c.logger.Warn("failed to refresh Trakt access token", "error", err)
+	}
+
+	c.mu.Lock()
+	accessToken := c.token.AccessToken
+	c.mu.Unlock()
+	status, header, err := c.do(method, apiPath, accessToken, body, result)
+	if status == http.StatusUnauthorized {
+		if refreshErr := c.refresh(accessToken); refreshErr != nil {
+			return status, header, fmt.Errorf("%w; refreshing the access token failed: %v", err, refreshErr)
+		}
+		c.mu.Lock()
+		accessToken = c.token.AccessToken
+		c.mu.Unlock()
+		status, header, err = c.do(method, apiPath, accessToken, body, result)
+	}
+	return status, header, err
+}
+
+func (c *Client) do(method, apiPath, accessToken string, body interface{}, result interface{}) (int, http.Header, error) {
+	if accessToken == "" {
+		return 0, nil, ErrNotAuthorized
+	}
+	c.waitForRateLimit()
+
+	req, err := c.newRequest(method, apiPath, body)
+	if err != nil {
+		return 0, nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+	return c.send(req, result)
+}
+
+// refreshIfExpiring refreshes the access token when it expires soon
+func (c *Client) refreshIfExpiring() error {
+	c.mu.Lock()
+	token := c.token
+	c.mu.Unlock()
+	if token.ExpiresAt.IsZero() || time.Until(token.ExpiresAt) > tokenRefreshMargin {
+		return nil
+	}
+	return c.refresh(token.AccessToken)
+}
+
+// refresh exchanges the refresh token for a new access token, unless the
+// stale access token was already replaced by a concurrent refresh
+func (c *Client) refresh(stale string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.token.AccessToken != stale {
+		return nil
+	}
+	if c.token.RefreshToken == "" || c.config.API.ClientSecret == "" {
+		return fmt.Errorf("a refresh token and client secret are required")
+	}
+
+	req, err := c.newRequest("POST", "/oauth/token", map[string]string{
+		"refresh_token": c.token.RefreshToken,
+		"client_id":     c.config.API.ClientID,
+		"client_secret": c.config.API.ClientSecret,
+		"redirect_uri":  "urn:ietf:wg:oauth:2.0:oob",
+		"grant_type":    "refresh_token",
+	})
+	if err != nil {
+		return err
+	}
+
+	var token struct {
+		AccessToken  string `json:"access_token"`
+		RefreshToken string `json:"refresh_token"`
+		ExpiresIn    int64  `json:"expires_in"`
+		CreatedAt    int64  `json:"created_at"`
+	}
+	if _, _, err := c.send(req, &token); err != nil {
+		return fmt.Errorf("Trakt token refresh failed: %w", err)
+	}
+	if token.AccessToken == "" {
+		return fmt.Errorf("Trakt token refresh returned no token")
+	}
+
+	c.token = Token{
+		AccessToken:  token.AccessToken,
+		RefreshToken: token.RefreshToken,
+		ExpiresAt:    time.Unix(token.CreatedAt+token.ExpiresIn, 0),
+	}
+	if c.onRefresh != nil {
+		c.onRefresh(c.token)
+	}
+	c.logger.Info("refreshed Trakt access token", "expires_at", c.token.ExpiresAt.Format(time.RFC3339))
+	return nil
+}
+
+// newRequest builds an API request with the headers Trakt requires
+func (c *Client) newRequest(method, apiPath string, body interface{}) (*http.Request, error) {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return nil, err
+		}
+		reader = bytes.NewReader(data)
+	}
+	req, err := http.NewRequest(method, strings.TrimSuffix(c.config.API.BaseURL, "/")+apiPath, reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("trakt-api-version", "2")
+	req.Header.Set("trakt-api-key", c.config.API.ClientID)
+	return req, nil
+}
+
+// send performs a request and decodes its JSON body into result
+func (c *Client) send(req *http.Request, result interface{}) (int, http.Header, error) {
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return 0, nil, fmt.Errorf("HTTP request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return resp.StatusCode, resp.Header, fmt.Errorf("failed to read response body: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		return resp.StatusCode, resp.Header, fmt.Errorf("Trakt API returned status %d", resp.StatusCode)
+	}
+	if err := json.Unmarshal(body, result); err != nil {
+		return resp.StatusCode, resp.Header, fmt.Errorf("failed to unmarshal JSON response: %w", err)
+	}
+	return resp.StatusCode, resp.Header, nil
+}
+
+// waitForRateLimit spaces requests by the configured rate limit
+func (c *Client) waitForRateLimit() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if wait := c.config.API.GetRequestDelay() - time.Since(c.lastAPICall); wait > 0 {
+		time.Sleep(wait)
+	}
+	c.lastAPICall = time.Now()
+}
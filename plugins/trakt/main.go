@@ -0,0 +1,248 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+
+	plugins "github.com/mantonx/viewra/sdk"
+	"google.golang.org/grpc/connectivity"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+
+	"github.com/mantonx/viewra/plugins/trakt/internal/config"
+	"github.com/mantonx/viewra/plugins/trakt/internal/models"
+	"github.com/mantonx/viewra/plugins/trakt/internal/services"
+	"github.com/mantonx/viewra/plugins/trakt/internal/trakt"
+)
+
+// Version is the plugin version, overridable at build time
+var Version = "1.0.0"
+
+// Trakt scrobbles the configured user's playback to Trakt.tv and
+// periodically syncs the account's watches and ratings back into the
+// user's Viewra history.
+type Trakt struct {
+	*plugins.BasePlugin
+
+	db       *gorm.DB
+	logger   plugins.Logger
+	config   *config.Config
+	client   *trakt.Client
+	scrobble *services.ScrobbleService
+	syncer   *services.SyncService
+
+	// Trakt request latency, reported to the admin dashboard
+	performanceMonitor *plugins.BasePerformanceMonitor
+
+	// Host service connections
+	unifiedClient *plugins.UnifiedServiceClient
+
+	stopSync context.CancelFunc
+}
+
+// Plugin lifecycle methods
+func (t *Trakt) Initialize(ctx *plugins.PluginContext) error {
+	if ctx == nil {
+		return fmt.Errorf("plugin context is nil")
+	}
+	if ctx.Logger == nil {
+		return fmt.Errorf("logger in plugin context is nil")
+	}
+	t.logger = ctx.Logger
+
+	if ctx.PluginBasePath == "" {
+		return fmt.Errorf("PluginBasePath is empty")
+	}
+
+	cfg := config.DefaultConfig()
+	if err := plugins.LoadPluginConfig(ctx, cfg); err != nil {
+		return fmt.Errorf("failed to load Trakt configuration: %w", err)
+	}
+	if err := cfg.Validate(); err != nil {
+		return fmt.Errorf("invalid Trakt configuration: %w", err)
+	}
+	if cfg.API.ClientID == "" || cfg.Auth.AccessToken == "" {
+		t.logger.Warn("no Trakt client ID or access token configured; scrobbling and sync are off until they are set")
+	}
+	t.config = cfg
+
+	dbPath := filepath.Join(ctx.PluginBasePath, "trakt.db")
+	db, err := gorm.Open(sqlite.Open(dbPath), &gorm.Config{})
+	if err != nil {
+		return fmt.Errorf("failed to open database: %w", err)
+	}
+	if err := models.Migrate(db); err != nil {
+		return fmt.Errorf("failed to migrate database: %w", err)
+	}
+	t.db = db
+
+	var history plugins.WatchHistoryServiceClient
+	if ctx.HostServiceAddr != "" {
+		clientConfig := plugins.DefaultClientConfig()
+		clientConfig.OnStateChange = func(state connectivity.State) {
+			t.logger.Info("host service connection state changed", "state", state.String())
+		}
+		client, err := plugins.NewUnifiedServiceClientWithConfig(ctx.HostServiceAddr, clientConfig)
+		if err != nil {
+			t.logger.Warn("failed to connect to host services", "error", err)
+		} else {
+			t.unifiedClient = client
+			history = client.WatchHistoryService()
+		}
+	}
+
+	t.performanceMonitor = plugins.NewBasePerformanceMonitor("Trakt")
+	t.client = trakt.NewClient(cfg, t.loadToken(), t.logger, t.performanceMonitor, t.saveToken)
+	t.scrobble = services.NewScrobbleService(t.db, t.config, t.client, t.logger)
+	t.syncer = services.NewSyncService(t.db, t.config, t.client, history, t.logger)
+
+	t.logger.Info("Trakt initialized", "user_id", cfg.Account.UserID, "scrobble", cfg.Scrobble.Enabled,
+		"sync", cfg.Sync.Enabled, "sync_interval_minutes", cfg.Sync.IntervalMinutes)
+	return nil
+}
+
+func (t *Trakt) Start() error {
+	if t.config.Sync.Enabled {
+		ctx, cancel := context.WithCancel(context.Background())
+		t.stopSync = cancel
+		go t.syncer.Run(ctx)
+	}
+	t.logger.Info("Trakt started")
+	return nil
+}
+
+func (t *Trakt) Stop() error {
+	if t.stopSync != nil {
+		t.stopSync()
+	}
+	if t.db != nil {
+		if sqlDB, err := t.db.DB(); err == nil {
+			sqlDB.Close()
+		}
+	}
+	if t.unifiedClient != nil {
+		t.unifiedClient.Close()
+	}
+	t.logger.Info("Trakt stopped")
+	return nil
+}
+
+func (t *Trakt) Info() (*plugins.PluginInfo, error) {
+	return &plugins.PluginInfo{
+		ID:          "trakt",
+		Name:        "Trakt Scrobbler",
+		Version:     Version,
+		Type:        plugins.PluginTypeGeneric,
+		Description: "Scrobbles playback to Trakt.tv and syncs watched status and ratings back into Viewra",
+		Author:      "Viewra Team",
+	}, nil
+}
+
+// Health returns nil if the plugin is healthy
+func (t *Trakt) Health() error {
+	if t.db == nil {
+		return fmt.Errorf("database not initialized")
+	}
+	if sqlDB, err := t.db.DB(); err != nil {
+		return fmt.Errorf("database error: %w", err)
+	} else if err := sqlDB.Ping(); err != nil {
+		return fmt.Errorf("database ping failed: %w", err)
+	}
+	return nil
+}
+
+// Playback hook service implementation
+func (t *Trakt) OnPlaybackStarted(event *plugins.PlaybackEvent) error {
+	return t.scrobble.Scrobble(trakt.ScrobbleStart, event)
+}
+
+func (t *Trakt) OnPlaybackPaused(event *plugins.PlaybackEvent) error {
+	return t.scrobble.Scrobble(trakt.ScrobblePause, event)
+}
+
+func (t *Trakt) OnPlaybackStopped(event *plugins.PlaybackEvent) error {
+	return t.scrobble.Scrobble(trakt.ScrobbleStop, event)
+}
+
+// loadToken returns the account's token: the one last refreshed by the
+// plugin, unless the configuration holds new tokens since
+func (t *Trakt) loadToken() trakt.Token {
+	configured := trakt.Token{
+		AccessToken:  t.config.Auth.AccessToken,
+		RefreshToken: t.config.Auth.RefreshToken,
+	}
+
+	var stored models.TraktToken
+	if err := t.db.Limit(1).Find(&stored).Error; err != nil {
+		t.logger.Warn("failed to load refreshed Trakt token", "error", err)
+		return configured
+	}
+	if stored.AccessToken == "" || stored.SeedToken != t.config.Auth.RefreshToken {
+		return configured
+	}
+	return trakt.Token{
+		AccessToken:  stored.AccessToken,
+		RefreshToken: stored.RefreshToken,
+		ExpiresAt:    stored.ExpiresAt,
+	}
+}
+
+// saveToken keeps a refreshed token for the next start
+func (t *Trakt) saveToken(token trakt.Token) {
+	stored := models.TraktToken{
+		ID:           1,
+		AccessToken:  token.AccessToken,
+		RefreshToken: token.RefreshToken,
+		ExpiresAt:    token.ExpiresAt,
+		SeedToken:    t.config.Auth.RefreshToken,
+	}
+	if err := t.db.Save(&stored).Error; err != nil {
+		t.logger.Warn("failed to save refreshed Trakt token", "error", err)
+	}
+}
+
+// Database service implementation
+func (t *Trakt) GetModels() []string {
+	return []string{
+		"TraktToken",
+		"TraktSyncState",
+		"TraktScrobble",
+	}
+}
+
+func (t *Trakt) Migrate(connectionString string) error {
+	db, err := gorm.Open(sqlite.Open(connectionString), &gorm.Config{})
+	if err != nil {
+		return fmt.Errorf("failed to connect to database: %w", err)
+	}
+	if err := models.Migrate(db); err != nil {
+		return fmt.Errorf("failed to migrate database: %w", err)
+	}
+	return nil
+}
+
+func (t *Trakt) Rollback(connectionString string) error {
+	db, err := gorm.Open(sqlite.Open(connectionString), &gorm.Config{})
+	if err != nil {
+		return fmt.Errorf("failed to connect to database: %w", err)
+	}
+	return db.Migrator().DropTable(&models.TraktToken{}, &models.TraktSyncState{}, &models.TraktScrobble{})
+}
+
+// Service interfaces implementation
+func (t *Trakt) DatabaseService() plugins.DatabaseService {
+	return t
+}
+
+// PerformanceMonitorService reports Trakt request latency and status codes
+func (t *Trakt) PerformanceMonitorService() plugins.PerformanceMonitorService {
+	return t.performanceMonitor
+}
+
+func main() {
+	plugin := &Trakt{
+		BasePlugin: plugins.NewBasePlugin("Trakt Scrobbler", Version, plugins.PluginTypeGeneric, "Scrobbles playback to Trakt.tv"),
+	}
+	plugins.StartPlugin(plugin)
+}
@@ -0,0 +1,90 @@
+#Plugin: {
+	schema_version: "1.0"
+
+	// Plugin identification
+	id:            "trakt"
+	name:          "Trakt Scrobbler"
+	version:       "1.0.0"
+	description:   "Scrobbles playback to Trakt.tv and syncs watched status and ratings back into Viewra"
+	author:        "Viewra Team"
+	website:       "https://github.com/mantonx/viewra"
+	repository:    "https://github.com/mantonx/viewra"
+	license:       "MIT"
+	type:          "generic"
+	tags: [
+		"scrobbling",
+		"watch-history",
+		"movie",
+		"tv",
+		"trakt",
+		"external-api"
+	]
+	media_types: ["movie", "tv"]
+
+	// Plugin behavior. Off until a Trakt application and account tokens
+	// are configured.
+	enabled_by_default: false
+
+	// Plugin capabilities
+	capabilities: {
+		metadata_extraction: false
+		scanner_hooks:       false
+		playback_hooks:      true
+		search_service:      false
+		api_endpoints:       false
+		database_access:     true
+		background_tasks:    true
+		external_services:   true
+		asset_management:    false
+	}
+
+	// Entry points
+	entry_points: {
+		main: "trakt"
+	}
+
+	// Permissions
+	permissions: [
+		"database:read",
+		"database:write",
+		"network:external"
+	]
+
+	settings: {
+		// Trakt API application. client_secret is needed to refresh the
+		// access token once it expires. rate_limit is in requests per second.
+		api: {
+			client_id:     string | *""
+			client_secret: string | *""
+			base_url:      string | *"https://api.trakt.tv"
+			timeout_sec:   int | *30
+			rate_limit:    float64 | *1.0
+		}
+
+		// OAuth tokens of the Trakt account, from the device or PIN flow.
+		// Refreshed tokens are kept by the plugin.
+		auth: {
+			access_token:  string | *""
+			refresh_token: string | *""
+		}
+
+		// Viewra user whose playback is scrobbled and whose history the
+		// account's watches and ratings are synced into
+		account: {
+			user_id: int | *0
+		}
+
+		// Forward playback starts, pauses and stops to Trakt
+		scrobble: {
+			enabled: bool | *true
+		}
+
+		// Import Trakt watches and ratings every interval_minutes (at least 5)
+		sync: {
+			enabled:          bool | *true
+			interval_minutes: int | *60
+			watched:          bool | *true
+			ratings:          bool | *true
+		}
+	}
+}
@@ -4,10 +4,12 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"path/filepath"
 	"time"
 
 	plugins "github.com/mantonx/viewra/sdk"
 	"github.com/mantonx/viewra/sdk/transcoding"
+	"github.com/mantonx/viewra/sdk/transcoding/config"
 	"github.com/mantonx/viewra/sdk/transcoding/types"
 )
 
@@ -19,6 +21,7 @@ type SoftwareTranscoder struct {
 	author      string
 	priority    int
 	transcoder  *transcoding.Transcoder
+	config      *config.FFmpegConfigurationService
 }
 
 // Plugin implementation
@@ -32,6 +35,37 @@ func (p *SoftwareTranscoder) Initialize(ctx *plugins.PluginContext) error {
 		p.priority,
 	)
 	p.transcoder.SetLogger(ctx.Logger)
+
+	// Argument templates come from the plugin's config file; a missing or
+	// invalid file leaves the built-in command construction untouched
+	basePath := ctx.BasePath
+	if basePath == "" {
+		basePath = ctx.PluginBasePath
+	}
+	p.config = config.NewFFmpegConfigurationService(filepath.Join(basePath, "ffmpeg_config.json"))
+	p.config.AddConfigurationCallback(func(_, _ *plugins.PluginConfiguration) error {
+		p.transcoder.SetHardware(p.config.GetFFmpegConfig().Hardware)
+		if err := p.transcoder.SetABRLadder(p.config.GetFFmpegConfig().FFmpeg.ABRLadder); err != nil {
+			return err
+		}
+		return p.transcoder.SetArgTemplates(p.config.GetFFmpegConfig().FFmpeg.Templates)
+	})
+	if err := p.config.Initialize(); err != nil {
+		ctx.Logger.Warn("failed to load ffmpeg configuration, argument templates disabled", "error", err)
+	} else if err := p.transcoder.SetArgTemplates(p.config.GetFFmpegConfig().FFmpeg.Templates); err != nil {
+		ctx.Logger.Warn("failed to apply ffmpeg argument templates", "error", err)
+	} else if templates := p.config.GetFFmpegConfig().FFmpeg.Templates; len(templates) > 0 {
+		ctx.Logger.Info("loaded ffmpeg argument templates", "count", len(templates))
+	}
+	if err := p.transcoder.SetABRLadder(p.config.GetFFmpegConfig().FFmpeg.ABRLadder); err != nil {
+		ctx.Logger.Warn("failed to apply ABR ladder, using the default ladder", "error", err)
+	} else if rungs := p.config.GetFFmpegConfig().FFmpeg.ABRLadder; len(rungs) > 0 {
+		ctx.Logger.Info("loaded ABR ladder", "rungs", len(rungs))
+	}
+
+	// Hardware devices are detected in the background; without a config
+	// file the defaults apply
+	p.transcoder.SetHardware(p.config.GetFFmpegConfig().Hardware)
 	
 	ctx.Logger.Info("ffmpeg software transcoder plugin initialized (simplified)")
 	return nil
@@ -79,8 +113,11 @@ func (p *SoftwareTranscoder) GetInfo() plugins.ProviderInfo {
 			"h265_encoding",
 			"vp9_encoding",
 			"av1_encoding",
+			"audio_transcoding",
 			"multi_pass",
 			"high_quality",
+			"subtitle_burn_in",
+			"subtitle_passthrough",
 		},
 	}
 }
@@ -118,15 +155,50 @@ func (p *SoftwareTranscoder) GetSupportedFormats() []plugins.ContainerFormat {
 		{
 			Format:      "hls",
 			MimeType:    "application/vnd.apple.mpegurl",
-			Extensions:  []string{".m3u8", ".ts"},
+			Extensions:  []string{".m3u8", ".m4s"},
 			Description: "HLS Adaptive Streaming",
 			Adaptive:    true,
 		},
+		{
+			Format:      "ogg",
+			MimeType:    "audio/ogg",
+			Extensions:  []string{".ogg"},
+			Description: "Ogg Opus Audio",
+			Adaptive:    false,
+		},
+		{
+			Format:      "m4a",
+			MimeType:    "audio/mp4",
+			Extensions:  []string{".m4a"},
+			Description: "MPEG-4 AAC Audio",
+			Adaptive:    false,
+		},
+		{
+			Format:      "mp3",
+			MimeType:    "audio/mpeg",
+			Extensions:  []string{".mp3"},
+			Description: "MP3 Audio",
+			Adaptive:    false,
+		},
+		{
+			Format:      "opus",
+			MimeType:    "audio/ogg",
+			Extensions:  []string{".opus"},
+			Description: "Opus Audio",
+			Adaptive:    false,
+		},
+		{
+			Format:      "flac",
+			MimeType:    "audio/flac",
+			Extensions:  []string{".flac"},
+			Description: "FLAC Lossless Audio",
+			Adaptive:    false,
+		},
 	}
 }
 
 func (p *SoftwareTranscoder) GetHardwareAccelerators() []plugins.HardwareAccelerator {
-	return []plugins.HardwareAccelerator{
+	accelerators := []plugins.HardwareAccelerator{
 		{
 			Type:        "software",
 			ID:          "cpu",
@@ -135,6 +207,27 @@ func (p *SoftwareTranscoder) GetHardwareAccelerators() []plugins.HardwareAcceler
 			DeviceCount: 1,
 		},
 	}
+	if p.transcoder == nil {
+		return accelerators
+	}
+
+	// One entry per hardware type, counting its detected devices
+	index := make(map[types.HardwareType]int)
+	for _, device := range p.transcoder.HardwareDevices() {
+		if i, ok := index[device.Type]; ok {
+			accelerators[i].DeviceCount++
+			continue
+		}
+		index[device.Type] = len(accelerators)
+		accelerators = append(accelerators, plugins.HardwareAccelerator{
+			Type:        string(device.Type),
+			ID:          device.ID,
+			Name:        device.Name,
+			Available:   true,
+			DeviceCount: 1,
+		})
+	}
+	return accelerators
 }
 
 func (p *SoftwareTranscoder) GetQualityPresets() []plugins.QualityPreset {
@@ -188,12 +281,24 @@ func (p *SoftwareTranscoder) StartTranscode(ctx context.Context, req plugins.Tra
 		Container:      req.Container,
 		VideoCodec:     req.VideoCodec,
 		AudioCodec:     req.AudioCodec,
+		AudioBitrate:   req.AudioBitrate,
+		AudioOnly:      req.AudioOnly,
+		AudioStreamIndex: req.AudioStreamIndex,
+		Gapless:        req.Gapless,
+		Loudness:       req.Loudness,
+		Deinterlace:    req.Deinterlace,
+		TenBit:         req.TenBit,
+		SubtitleMode:   req.SubtitleMode,
+		SubtitleTrackIndex: req.SubtitleTrackIndex,
+		SubtitleCodec:  req.SubtitleCodec,
+		SubtitlePath:   req.SubtitlePath,
 		Quality:        req.Quality,
 		SpeedPriority:  types.SpeedPriority(req.SpeedPriority),
 		Seek:           req.Seek, // Pass through the seek position
-		HardwareType:   types.HardwareTypeNone,
-		PreferHardware: false,
+		HardwareType:   req.HardwareType,
+		PreferHardware: req.PreferHardware,
 		EnableABR:      req.EnableABR, // Pass through ABR flag
+		FastStart:      req.FastStart,
 	}
 	
 	handle, err := p.transcoder.StartTranscode(ctx, transcodingReq)
@@ -241,6 +346,11 @@ func (p *SoftwareTranscoder) GetProgress(handle *plugins.TranscodeHandle) (*plug
 		BytesWritten:    progress.BytesWritten,
 		CurrentSpeed:    progress.CurrentSpeed,
 		AverageSpeed:    progress.AverageSpeed,
+		CurrentFrame:    progress.CurrentFrame,
+		TotalFrames:     progress.TotalFrames,
+		CurrentFPS:      progress.CurrentFPS,
+		CurrentBitrate:  progress.CurrentBitrate,
+		DroppedFrames:   progress.DroppedFrames,
 	}, nil
 }
 
@@ -279,12 +389,24 @@ func (p *SoftwareTranscoder) StartStream(ctx context.Context, req plugins.Transc
 		Container:      req.Container,
 		VideoCodec:     req.VideoCodec,
 		AudioCodec:     req.AudioCodec,
+		AudioBitrate:   req.AudioBitrate,
+		AudioOnly:      req.AudioOnly,
+		AudioStreamIndex: req.AudioStreamIndex,
+		Gapless:        req.Gapless,
+		Loudness:       req.Loudness,
+		Deinterlace:    req.Deinterlace,
+		TenBit:         req.TenBit,
+		SubtitleMode:   req.SubtitleMode,
+		SubtitleTrackIndex: req.SubtitleTrackIndex,
+		SubtitleCodec:  req.SubtitleCodec,
+		SubtitlePath:   req.SubtitlePath,
 		Quality:        req.Quality,
 		SpeedPriority:  types.SpeedPriority(req.SpeedPriority),
 		Seek:           req.Seek, // Pass through the seek position
-		HardwareType:   types.HardwareTypeNone,
-		PreferHardware: false,
+		HardwareType:   req.HardwareType,
+		PreferHardware: req.PreferHardware,
 		EnableABR:      req.EnableABR, // Pass through ABR flag
+		FastStart:      req.FastStart,
 	}
 	
 	handle, err := p.transcoder.StartStream(ctx, transcodingReq)
@@ -360,7 +482,12 @@ func (p *SoftwareTranscoder) AdminPageService() plugins.AdminPageService
 func (p *SoftwareTranscoder) APIRegistrationService() plugins.APIRegistrationService         { return nil }
 func (p *SoftwareTranscoder) SearchService() plugins.SearchService                           { return nil }
 func (p *SoftwareTranscoder) HealthMonitorService() plugins.HealthMonitorService             { return nil }
-func (p *SoftwareTranscoder) ConfigurationService() plugins.ConfigurationService             { return nil }
+func (p *SoftwareTranscoder) ConfigurationService() plugins.ConfigurationService {
+	if p.config == nil {
+		return nil
+	}
+	return p.config
+}
 func (p *SoftwareTranscoder) PerformanceMonitorService() plugins.PerformanceMonitorService   { return nil }
 func (p *SoftwareTranscoder) EnhancedAdminPageService() plugins.EnhancedAdminPageService     { return nil }
 
@@ -25,7 +25,7 @@
 	// Plugin capabilities
 	capabilities: {
 		transcoding:         true
-		hardware_accel:      false  // Software only
+		hardware_accel:      true   // VAAPI, NVENC and QSV when detected
 		streaming:           true
 		adaptive_streaming:  true
 		database_access:     false
@@ -75,6 +75,30 @@
 				vp9: int & >=0 & <=63 | *31
 					@ui(title="VP9 Default CRF", importance=5)
 			}
+
+			// Adaptive bitrate ladder, encoded within one session; rungs above
+			// the source's resolution are skipped, empty uses 480p/720p/1080p
+			abr_ladder: [...{
+				label:         string & =~"^[A-Za-z0-9_-]+$"
+				height:        int & >0
+				video_bitrate: int & >0     // kbps at quality 80
+				audio_bitrate: int & >=0 | *128
+			}] | *[]
+				@ui(title="ABR Ladder", importance=4)
+		}
+
+		// Hardware encoding settings
+		hardware: {
+			enabled: bool | *true
+				@ui(title="Enable Hardware Encoding", importance=10, is_basic=true)
+			preferred_type: "auto" | "cuda" | "vaapi" | "qsv" | "none" | *"none"
+				@ui(title="Preferred Hardware", importance=9, is_basic=true)
+			device_selection: "auto" | "first" | "load-balanced" | *"auto"
+				@ui(title="Device Selection", importance=7)
+			fallback: bool | *true
+				@ui(title="Fall Back to Software", importance=8)
+			max_sessions: int & >=0 & <=32 | *0
+				@ui(title="Max Sessions per Device (0=driver default)", importance=6)
 		}
 
 		// Session management
@@ -0,0 +1,157 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Episode orders TheTVDB can list a series' episodes in
+const (
+	OrderDefault  = "default"  // Aired order
+	OrderDVD      = "dvd"      // DVD release order
+	OrderAbsolute = "absolute" // One running number across all seasons
+)
+
+// Config represents the complete plugin configuration structure
+// This mirrors the CUE schema defined in plugin.cue
+type Config struct {
+	API      APIConfig      `json:"api"`
+	Features FeaturesConfig `json:"features"`
+	Matching MatchingConfig `json:"matching"`
+	Episodes EpisodesConfig `json:"episodes"`
+	Cache    CacheConfig    `json:"cache"`
+}
+
+// APIConfig contains TheTVDB API-related settings
+type APIConfig struct {
+	Key        string  `json:"key"`         // TheTVDB project API key (sensitive)
+	PIN        string  `json:"pin"`         // Subscriber PIN for user-supported keys
+	BaseURL    string  `json:"base_url"`    // API root, e.g. https://api4.thetvdb.com/v4
+	Language   string  `json:"language"`    // ISO 639-2 language of episode names
+	TimeoutSec int     `json:"timeout_sec"` // Request timeout in seconds
+	RateLimit  float64 `json:"rate_limit"`  // Requests per second
+}
+
+// FeaturesConfig contains feature toggle settings
+type FeaturesConfig struct {
+	AutoEnrich        bool `json:"auto_enrich"`        // Automatically enrich during scanning
+	OverwriteExisting bool `json:"overwrite_existing"` // Re-enrich files that already have TVDb data
+}
+
+// MatchingConfig contains series matching settings
+type MatchingConfig struct {
+	MatchThreshold float64 `json:"match_threshold"` // Minimum similarity score for matches
+	YearTolerance  int     `json:"year_tolerance"`  // Allow +/- years difference
+}
+
+// EpisodesConfig selects the episode order files are numbered in
+type EpisodesConfig struct {
+	Order         string `json:"order"`          // default, dvd or absolute
+	LibraryOrders string `json:"library_orders"` // Per-library overrides, e.g. "3=dvd,7=absolute"
+}
+
+// CacheConfig contains caching settings
+type CacheConfig struct {
+	DurationHours int `json:"duration_hours"` // Cache duration in hours
+}
+
+// DefaultConfig returns the default configuration
+func DefaultConfig() *Config {
+	return &Config{
+		API: APIConfig{
+			Key:        "", // Must be provided by user
+			BaseURL:    "https://api4.thetvdb.com/v4",
+			Language:   "eng",
+			TimeoutSec: 30,
+			RateLimit:  5,
+		},
+		Features: FeaturesConfig{
+			AutoEnrich:        true,
+			OverwriteExisting: false,
+		},
+		Matching: MatchingConfig{
+			MatchThreshold: 0.8,
+			YearTolerance:  1,
+		},
+		Episodes: EpisodesConfig{
+			Order: OrderDefault,
+		},
+		Cache: CacheConfig{
+			DurationHours: 168, // 1 week cache duration
+		},
+	}
+}
+
+// GetRequestTimeout returns the request timeout duration
+func (c *APIConfig) GetRequestTimeout() time.Duration {
+	return time.Duration(c.TimeoutSec) * time.Second
+}
+
+// GetRequestDelay returns the minimum delay between API requests
+func (c *APIConfig) GetRequestDelay() time.Duration {
+	return time.Duration(float64(time.Second) / c.RateLimit)
+}
+
+// GetCacheDuration returns the cache duration
+func (c *CacheConfig) GetCacheDuration() time.Duration {
+	return time.Duration(c.DurationHours) * time.Hour
+}
+
+// EpisodeOrderFor returns the episode order of a library: its entry in
+// library_orders if it has one, otherwise the plugin-wide order
+func (c *EpisodesConfig) EpisodeOrderFor(libraryID string) string {
+	for _, pair := range strings.Split(c.LibraryOrders, ",") {
+		id, order, ok := strings.Cut(pair, "=")
+		if ok && libraryID != "" && strings.TrimSpace(id) == libraryID {
+			return strings.ToLower(strings.TrimSpace(order))
+		}
+	}
+	return strings.ToLower(c.Order)
+}
+
+// Validate validates the configuration
+func (c *Config) Validate() error {
+	if c.API.BaseURL == "" {
+		return fmt.Errorf("API base URL is required")
+	}
+
+	if c.API.RateLimit <= 0 {
+		return fmt.Errorf("API rate limit must be positive")
+	}
+
+	if c.API.TimeoutSec <= 0 {
+		return fmt.Errorf("API timeout must be positive")
+	}
+
+	if c.Matching.MatchThreshold < 0 || c.Matching.MatchThreshold > 1 {
+		return fmt.Errorf("match threshold must be between 0 and 1")
+	}
+
+	if !validOrder(c.Episodes.Order) {
+		return fmt.Errorf("invalid episode order %q: use default, dvd or absolute", c.Episodes.Order)
+	}
+	for _, pair := range strings.Split(c.Episodes.LibraryOrders, ",") {
+		if strings.TrimSpace(pair) == "" {
+			continue
+		}
+		id, order, ok := strings.Cut(pair, "=")
+		if !ok || strings.TrimSpace(id) == "" || !validOrder(strings.TrimSpace(order)) {
+			return fmt.Errorf("invalid library episode order %q: use <library id>=<default|dvd|absolute>", strings.TrimSpace(pair))
+		}
+	}
+
+	if c.Cache.DurationHours <= 0 {
+		return fmt.Errorf("cache duration must be positive")
+	}
+
+	return nil
+}
+
+func validOrder(order string) bool {
+	switch strings.ToLower(order) {
+	case OrderDefault, OrderDVD, OrderAbsolute:
+		return true
+	}
+	return false
+}
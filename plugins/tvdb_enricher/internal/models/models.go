@@ -0,0 +1,68 @@
+package models
+
+import (
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// TVDbCache represents cached API responses from TheTVDB
+type TVDbCache struct {
+	ID        uint32    `gorm:"primaryKey" json:"id"`
+	QueryHash string    `gorm:"uniqueIndex;not null" json:"query_hash"` // Hash of the query parameters
+	QueryType string    `gorm:"not null;index" json:"query_type"`       // Type of query (search, episodes)
+	Response  string    `gorm:"type:text;not null" json:"response"`     // JSON response from TheTVDB
+	ExpiresAt time.Time `gorm:"not null;index" json:"expires_at"`       // When this cache entry expires
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
+}
+
+// TableName returns the table name for TVDbCache
+func (TVDbCache) TableName() string {
+	return "tvdb_cache"
+}
+
+// IsExpired checks if the cache entry has expired
+func (c *TVDbCache) IsExpired() bool {
+	return time.Now().After(c.ExpiresAt)
+}
+
+// TVDbEnrichment represents the TheTVDB episode a media file was matched to
+type TVDbEnrichment struct {
+	ID          uint32 `gorm:"primaryKey" json:"id"`
+	MediaFileID string `gorm:"not null;index" json:"media_file_id"` // Reference to media file
+	SeriesID    int    `gorm:"not null;index" json:"series_id"`     // TheTVDB series ID
+	EpisodeID   int    `gorm:"not null;index" json:"episode_id"`    // TheTVDB episode ID
+
+	SeriesName     string     `gorm:"not null" json:"series_name"`
+	EpisodeName    string     `json:"episode_name,omitempty"`
+	Overview       string     `gorm:"type:text" json:"overview,omitempty"`
+	SeasonNumber   int        `json:"season_number"`
+	EpisodeNumber  int        `json:"episode_number"`
+	AbsoluteNumber int        `json:"absolute_number,omitempty"`
+	EpisodeOrder   string     `gorm:"not null" json:"episode_order"` // Order the numbers are in: default, dvd or absolute
+	AirDate        *time.Time `json:"air_date,omitempty"`
+	Runtime        int        `json:"runtime,omitempty"` // Minutes
+
+	ConfidenceScore float64   `gorm:"not null;default:0" json:"confidence_score"` // Series match confidence (0-1)
+	ProcessedAt     time.Time `gorm:"autoCreateTime" json:"processed_at"`
+	UpdatedAt       time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// TableName returns the table name for TVDbEnrichment
+func (TVDbEnrichment) TableName() string {
+	return "tvdb_enrichments"
+}
+
+// Migrate creates or updates the plugin's tables and adds the unique key on
+// media_file_id that enrichment upserts rely on
+func Migrate(db *gorm.DB) error {
+	if err := db.AutoMigrate(&TVDbCache{}, &TVDbEnrichment{}); err != nil {
+		return err
+	}
+
+	if err := db.Exec("CREATE UNIQUE INDEX IF NOT EXISTS idx_tvdb_enrichments_media_file_id_unique ON tvdb_enrichments(media_file_id)").Error; err != nil {
+		return fmt.Errorf("failed to add unique key on media_file_id: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,274 @@
+package services
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/mantonx/viewra/plugins/tvdb_enricher/internal/config"
+	"github.com/mantonx/viewra/plugins/tvdb_enricher/internal/models"
+	"github.com/mantonx/viewra/plugins/tvdb_enricher/internal/tvdb"
+	plugins "github.com/mantonx/viewra/sdk"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// EnrichmentService matches episode files to TheTVDB and records the result
+type EnrichmentService struct {
+	db            *gorm.DB
+	config        *config.Config
+	client        *tvdb.Client
+	unifiedClient *plugins.UnifiedServiceClient
+	logger        plugins.Logger
+}
+
+// NewEnrichmentService creates a new enrichment service, recording its
+// TheTVDB requests in monitor
+func NewEnrichmentService(db *gorm.DB, cfg *config.Config, client *plugins.UnifiedServiceClient, logger plugins.Logger, monitor *plugins.BasePerformanceMonitor) *EnrichmentService {
+	return &EnrichmentService{
+		db:            db,
+		config:        cfg,
+		client:        tvdb.NewClient(cfg, logger, monitor),
+		unifiedClient: client,
+		logger:        logger,
+	}
+}
+
+// UpdateConfiguration updates the service configuration
+func (s *EnrichmentService) UpdateConfiguration(newConfig *config.Config) {
+	s.config = newConfig
+	s.client.UpdateConfiguration(newConfig)
+}
+
+// ProcessMediaFile matches an episode file to its series and episode in
+// the episode order of the file's library
+func (s *EnrichmentService) ProcessMediaFile(mediaFileID string, filePath string, metadata map[string]string) error {
+	if !s.config.Features.OverwriteExisting {
+		var existing models.TVDbEnrichment
+		if err := s.db.Where("media_file_id = ?", mediaFileID).First(&existing).Error; err == nil {
+			return plugins.SkipHook(plugins.SkipReasonAlreadyProcessed, "already enriched")
+		}
+	}
+
+	info, ok := ParseEpisodePath(plugins.MatchPath(filePath, metadata))
+	if !ok || info.SeriesName == "" {
+		return plugins.SkipHook(plugins.SkipReasonMissingMetadata, "no series name and episode number in file name")
+	}
+
+	results, err := s.searchSeries(info.SeriesName, info.Year)
+	if err != nil {
+		return fmt.Errorf("failed to search TheTVDB: %w", err)
+	}
+	series, score := bestSeries(results, info.SeriesName, info.Year, s.config)
+	if series == nil {
+		s.logger.Debug("no suitable series match", "series", info.SeriesName, "best_score", score)
+		return plugins.SkipHook(plugins.SkipReasonNoMatch, fmt.Sprintf("no TheTVDB series match for %q", info.SeriesName))
+	}
+
+	// Files numbered by season can't be looked up in absolute order, whose
+	// episodes all sit in one season
+	order := s.config.Episodes.EpisodeOrderFor(metadata[plugins.HookMetadataLibraryID])
+	if order == config.OrderAbsolute && info.AbsoluteNumber == 0 {
+		order = config.OrderDefault
+	}
+
+	episodes, err := s.seriesEpisodes(series.ID(), order)
+	if err != nil {
+		return fmt.Errorf("failed to fetch TheTVDB episodes: %w", err)
+	}
+	episode := findEpisode(episodes, info, order)
+	if episode == nil {
+		return plugins.SkipHook(plugins.SkipReasonNoMatch, fmt.Sprintf("%s has no %s episode %s on TheTVDB", series.Name, order, episodeLabel(info)))
+	}
+
+	if err := s.saveEnrichment(mediaFileID, series, episode, order, score); err != nil {
+		return fmt.Errorf("failed to save enrichment: %w", err)
+	}
+
+	s.logger.Info("enriched episode from TheTVDB", "media_file_id", mediaFileID, "series_id", series.ID(),
+		"episode_id", episode.ID, "order", order)
+	return nil
+}
+
+// saveEnrichment stores the match and registers it with the host
+func (s *EnrichmentService) saveEnrichment(mediaFileID string, series *tvdb.SearchResult, episode *tvdb.Episode, order string, score float64) error {
+	enrichment := &models.TVDbEnrichment{
+		MediaFileID:     mediaFileID,
+		SeriesID:        series.ID(),
+		EpisodeID:       episode.ID,
+		SeriesName:      series.Name,
+		EpisodeName:     episode.Name,
+		Overview:        episode.Overview,
+		SeasonNumber:    episode.SeasonNumber,
+		EpisodeNumber:   episode.Number,
+		AbsoluteNumber:  episode.AbsoluteNumber,
+		EpisodeOrder:    order,
+		Runtime:         episode.Runtime,
+		ConfidenceScore: score,
+	}
+	if aired, err := time.Parse("2006-01-02", episode.Aired); err == nil {
+		enrichment.AirDate = &aired
+	}
+
+	// Upsert on media_file_id so concurrent hooks for one file keep a single row
+	if err := s.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "media_file_id"}},
+		UpdateAll: true,
+	}).Create(enrichment).Error; err != nil {
+		return err
+	}
+
+	if s.unifiedClient != nil {
+		if err := s.registerWithCentralizedSystem(enrichment, series); err != nil {
+			s.logger.Warn("failed to register with centralized system", "error", err)
+		}
+	}
+	return nil
+}
+
+// registerWithCentralizedSystem registers the episode's data with the host
+func (s *EnrichmentService) registerWithCentralizedSystem(enrichment *models.TVDbEnrichment, series *tvdb.SearchResult) error {
+	enrichments := map[string]string{
+		"tvdb_id":         strconv.Itoa(enrichment.SeriesID),
+		"tvdb_episode_id": strconv.Itoa(enrichment.EpisodeID),
+		"media_type":      "episode",
+		"series_name":     enrichment.SeriesName,
+		"title":           enrichment.EpisodeName,
+		"overview":        enrichment.Overview,
+		"season_number":   strconv.Itoa(enrichment.SeasonNumber),
+		"episode_number":  strconv.Itoa(enrichment.EpisodeNumber),
+		"episode_order":   enrichment.EpisodeOrder,
+	}
+	if enrichment.AbsoluteNumber > 0 {
+		enrichments["absolute_number"] = strconv.Itoa(enrichment.AbsoluteNumber)
+	}
+	if enrichment.AirDate != nil {
+		enrichments["air_date"] = enrichment.AirDate.Format("2006-01-02")
+		enrichments["release_year"] = strconv.Itoa(enrichment.AirDate.Year())
+	}
+	if enrichment.Runtime > 0 {
+		enrichments["duration"] = strconv.Itoa(enrichment.Runtime * 60)
+	}
+	if series.ImageURL != "" {
+		enrichments["poster_url"] = series.ImageURL
+	}
+
+	request := &plugins.RegisterEnrichmentRequest{
+		MediaFileID:     enrichment.MediaFileID,
+		SourceName:      "tvdb",
+		Enrichments:     enrichments,
+		ConfidenceScore: enrichment.ConfidenceScore,
+		MatchMetadata: map[string]string{
+			"source":      "tvdb",
+			"series_year": strconv.Itoa(series.FirstAirYear()),
+		},
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	response, err := s.unifiedClient.EnrichmentService().RegisterEnrichment(ctx, request)
+	if err != nil {
+		return fmt.Errorf("failed to register enrichment: %w", err)
+	}
+	if !response.Success {
+		return fmt.Errorf("enrichment registration failed: %s", response.Message)
+	}
+	return nil
+}
+
+// searchSeries searches TheTVDB for a series, using the cache when it can
+func (s *EnrichmentService) searchSeries(name string, year int) ([]tvdb.SearchResult, error) {
+	var results []tvdb.SearchResult
+	key := fmt.Sprintf("search:%s:%d", name, year)
+	if s.getCached("search", key, &results) {
+		return results, nil
+	}
+
+	results, err := s.client.SearchSeries(name, year)
+	if err != nil {
+		return nil, err
+	}
+	// A year in the file name can be off; retry without it before giving up
+	if len(results) == 0 && year > 0 {
+		if results, err = s.client.SearchSeries(name, 0); err != nil {
+			return nil, err
+		}
+	}
+	s.setCached("search", key, results)
+	return results, nil
+}
+
+// seriesEpisodes returns a series' episodes in an order, using the cache
+// when it can
+func (s *EnrichmentService) seriesEpisodes(seriesID int, order string) ([]tvdb.Episode, error) {
+	var episodes []tvdb.Episode
+	key := fmt.Sprintf("episodes:%d:%s", seriesID, order)
+	if s.getCached("episodes", key, &episodes) {
+		return episodes, nil
+	}
+
+	episodes, err := s.client.SeriesEpisodes(seriesID, order)
+	if err != nil {
+		return nil, err
+	}
+	s.setCached("episodes", key, episodes)
+	return episodes, nil
+}
+
+func (s *EnrichmentService) getCached(queryType, key string, result interface{}) bool {
+	var cached models.TVDbCache
+	if err := s.db.Where("query_hash = ? AND query_type = ?", queryHash(key), queryType).First(&cached).Error; err != nil {
+		return false
+	}
+	if cached.IsExpired() {
+		return false
+	}
+	return json.Unmarshal([]byte(cached.Response), result) == nil
+}
+
+func (s *EnrichmentService) setCached(queryType, key string, value interface{}) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return
+	}
+	entry := &models.TVDbCache{
+		QueryHash: queryHash(key),
+		QueryType: queryType,
+		Response:  string(data),
+		ExpiresAt: time.Now().Add(s.config.Cache.GetCacheDuration()),
+	}
+	if err := s.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "query_hash"}},
+		DoUpdates: clause.AssignmentColumns([]string{"response", "expires_at"}),
+	}).Create(entry).Error; err != nil {
+		s.logger.Warn("failed to cache TheTVDB response", "query_type", queryType, "error", err)
+	}
+}
+
+// RemoveMediaFile deletes the enrichment of a media file
+func (s *EnrichmentService) RemoveMediaFile(mediaFileID string) error {
+	if err := s.db.Where("media_file_id = ?", mediaFileID).Delete(&models.TVDbEnrichment{}).Error; err != nil {
+		return fmt.Errorf("failed to remove enrichment: %w", err)
+	}
+	return nil
+}
+
+// CleanupExpiredCache removes expired cache entries
+func (s *EnrichmentService) CleanupExpiredCache() error {
+	return s.db.Where("expires_at < ?", time.Now()).Delete(&models.TVDbCache{}).Error
+}
+
+func queryHash(key string) string {
+	return fmt.Sprintf("%x", md5.Sum([]byte(key)))
+}
+
+func episodeLabel(info EpisodeInfo) string {
+	if info.AbsoluteNumber > 0 {
+		return strconv.Itoa(info.AbsoluteNumber)
+	}
+	return fmt.Sprintf("S%02dE%02d", info.SeasonNumber, info.EpisodeNumber)
+}
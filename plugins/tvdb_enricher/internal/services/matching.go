@@ -0,0 +1,102 @@
+package services
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/mantonx/viewra/plugins/tvdb_enricher/internal/config"
+	"github.com/mantonx/viewra/plugins/tvdb_enricher/internal/tvdb"
+)
+
+// bestSeries returns the search result that best matches a series name and
+// year, with its score, or nil when none reaches the match threshold
+func bestSeries(results []tvdb.SearchResult, name string, year int, cfg *config.Config) (*tvdb.SearchResult, float64) {
+	var best *tvdb.SearchResult
+	bestScore := 0.0
+	for i := range results {
+		result := &results[i]
+		score := titleSimilarity(name, result.Name)
+		for _, alias := range result.Aliases {
+			score = max(score, titleSimilarity(name, alias))
+		}
+
+		if year > 0 {
+			if resultYear := result.FirstAirYear(); resultYear > 0 {
+				diff := year - resultYear
+				if diff < 0 {
+					diff = -diff
+				}
+				if diff > cfg.Matching.YearTolerance {
+					continue
+				}
+				if diff == 0 {
+					score = min(1, score+0.05)
+				}
+			}
+		}
+
+		if score > bestScore {
+			best, bestScore = result, score
+		}
+	}
+
+	if best == nil || bestScore < cfg.Matching.MatchThreshold {
+		return nil, bestScore
+	}
+	return best, bestScore
+}
+
+// titleSimilarity scores two titles from 0 to 1: 1 when they normalize to
+// the same words, otherwise the share of words they have in common
+func titleSimilarity(a, b string) float64 {
+	wordsA, wordsB := titleWords(a), titleWords(b)
+	if len(wordsA) == 0 || len(wordsB) == 0 {
+		return 0
+	}
+	if strings.Join(wordsA, " ") == strings.Join(wordsB, " ") {
+		return 1
+	}
+
+	inB := make(map[string]bool, len(wordsB))
+	for _, word := range wordsB {
+		inB[word] = true
+	}
+	common := 0
+	for _, word := range wordsA {
+		if inB[word] {
+			common++
+		}
+	}
+	return float64(common) / float64(max(len(wordsA), len(wordsB)))
+}
+
+// titleWords lowercases a title and splits it into words, dropping
+// punctuation and a leading article
+func titleWords(title string) []string {
+	words := strings.FieldsFunc(strings.ToLower(title), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	if len(words) > 1 && (words[0] == "the" || words[0] == "a" || words[0] == "an") {
+		words = words[1:]
+	}
+	return words
+}
+
+// findEpisode returns the episode a file's numbers point to in a list of
+// episodes fetched in the given order
+func findEpisode(episodes []tvdb.Episode, info EpisodeInfo, order string) *tvdb.Episode {
+	for i := range episodes {
+		episode := &episodes[i]
+		if info.AbsoluteNumber > 0 {
+			if episode.AbsoluteNumber == info.AbsoluteNumber ||
+				(order == config.OrderAbsolute && episode.Number == info.AbsoluteNumber) {
+				return episode
+			}
+			continue
+		}
+		if episode.SeasonNumber == info.SeasonNumber && episode.Number == info.EpisodeNumber {
+			return episode
+		}
+	}
+	return nil
+}
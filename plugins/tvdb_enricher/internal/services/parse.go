@@ -0,0 +1,31 @@
+package services
+
+import "github.com/mantonx/viewra/sdk/namingparser"
+
+// EpisodeInfo is what a file's path says about the episode it holds
+type EpisodeInfo struct {
+	SeriesName     string
+	Year           int // Series year in the name, 0 when absent
+	SeasonNumber   int // 0 when the file only carries an absolute number
+	EpisodeNumber  int
+	AbsoluteNumber int // Set for absolute-numbered files such as "Show - 012"
+}
+
+// ParseEpisodePath extracts the series name and episode numbers from a file
+// path with the SDK's naming parser. The series name falls back to the show
+// directory when the file name starts with the numbers. ok is false when no
+// numbering was found; date-based episodes aren't matched by number.
+func ParseEpisodePath(filePath string) (EpisodeInfo, bool) {
+	parsed := namingparser.Parse(filePath)
+	if parsed.Kind != namingparser.KindEpisode || (parsed.Episode() == 0 && parsed.AbsoluteEpisode() == 0) {
+		return EpisodeInfo{}, false
+	}
+
+	return EpisodeInfo{
+		SeriesName:     parsed.Title,
+		Year:           parsed.Year,
+		SeasonNumber:   parsed.Season,
+		EpisodeNumber:  parsed.Episode(),
+		AbsoluteNumber: parsed.AbsoluteEpisode(),
+	}, true
+}
@@ -0,0 +1,255 @@
+package tvdb
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mantonx/viewra/plugins/tvdb_enricher/internal/config"
+	plugins "github.com/mantonx/viewra/sdk"
+)
+
+// maxEpisodePages bounds the pages read for one series; TheTVDB returns
+// 500 episodes per page
+const maxEpisodePages = 20
+
+// SearchResult is a series returned by /search
+type SearchResult struct {
+	TVDbID       string   `json:"tvdb_id"`
+	Name         string   `json:"name"`
+	Year         string   `json:"year"`
+	FirstAirTime string   `json:"first_air_time"`
+	Overview     string   `json:"overview"`
+	ImageURL     string   `json:"image_url"`
+	Aliases      []string `json:"aliases"`
+}
+
+// ID returns the result's numeric series ID
+func (r SearchResult) ID() int {
+	id, _ := strconv.Atoi(r.TVDbID)
+	return id
+}
+
+// FirstAirYear returns the year the series started, or 0 when unknown
+func (r SearchResult) FirstAirYear() int {
+	if year, err := strconv.Atoi(r.Year); err == nil {
+		return year
+	}
+	if len(r.FirstAirTime) >= 4 {
+		year, _ := strconv.Atoi(r.FirstAirTime[:4])
+		return year
+	}
+	return 0
+}
+
+// Episode is an episode of a series in one of its orders. In absolute order
+// Number is the absolute number.
+type Episode struct {
+	ID             int    `json:"id"`
+	Name           string `json:"name"`
+	Overview       string `json:"overview"`
+	Aired          string `json:"aired"`
+	Runtime        int    `json:"runtime"`
+	Image          string `json:"image"`
+	SeasonNumber   int    `json:"seasonNumber"`
+	Number         int    `json:"number"`
+	AbsoluteNumber int    `json:"absoluteNumber"`
+}
+
+// Client handles TheTVDB v4 API interactions. It logs in with the project
+// key on first use and reuses the bearer token until the API rejects it.
+type Client struct {
+	config     *config.Config
+	logger     plugins.Logger
+	httpClient *http.Client
+	monitor    *plugins.BasePerformanceMonitor
+
+	mu          sync.Mutex
+	token       string
+	lastAPICall time.Time
+}
+
+// NewClient creates a new TheTVDB API client. Its requests are recorded in
+// monitor when it is set.
+func NewClient(cfg *config.Config, logger plugins.Logger, monitor *plugins.BasePerformanceMonitor) *Client {
+	return &Client{
+		config:     cfg,
+		logger:     logger,
+		httpClient: newHTTPClient(cfg, monitor),
+		monitor:    monitor,
+	}
+}
+
+// newHTTPClient returns the provider client requests are made with
+func newHTTPClient(cfg *config.Config, monitor *plugins.BasePerformanceMonitor) *http.Client {
+	return plugins.InstrumentProviderClient(plugins.NewProviderHTTPClient(cfg.API.GetRequestTimeout()), monitor, "tvdb")
+}
+
+// UpdateConfiguration switches the client to new settings, logging in
+// again on the next request
+func (c *Client) UpdateConfiguration(cfg *config.Config) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.config = cfg
+	c.token = ""
+	c.httpClient = newHTTPClient(cfg, c.monitor)
+}
+
+// SearchSeries searches for series by name, narrowed to a first air year
+// when year is set
+func (c *Client) SearchSeries(query string, year int) ([]SearchResult, error) {
+	params := url.Values{}
+	params.Set("query", query)
+	params.Set("type", "series")
+	if year > 0 {
+		params.Set("year", strconv.Itoa(year))
+	}
+
+	var results []SearchResult
+	if _, err := c.get("/search?"+params.Encode(), &results); err != nil {
+		return nil, fmt.Errorf("failed to search series %q: %w", query, err)
+	}
+	return results, nil
+}
+
+// SeriesEpisodes returns every episode of a series in the given order
+func (c *Client) SeriesEpisodes(seriesID int, order string) ([]Episode, error) {
+	var episodes []Episode
+	for page := 0; page < maxEpisodePages; page++ {
+		var data struct {
+			Episodes []Episode `json:"episodes"`
+		}
+		path := fmt.Sprintf("/series/%d/episodes/%s?page=%d", seriesID, order, page)
+		next, err := c.get(path, &data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch %s episodes of series %d: %w", order, seriesID, err)
+		}
+		episodes = append(episodes, data.Episodes...)
+		if next == "" {
+			break
+		}
+	}
+	return episodes, nil
+}
+
+// get fetches an API path into data, returning the next page link if any.
+// An expired token is replaced and the request retried once.
+func (c *Client) get(path string, data interface{}) (string, error) {
+	next, status, err := c.do(path, data)
+	if status == http.StatusUnauthorized {
+		c.mu.Lock()
+		c.token = ""
+		c.mu.Unlock()
+		next, _, err = c.do(path, data)
+	}
+	return next, err
+}
+
+func (c *Client) do(path string, data interface{}) (string, int, error) {
+	token, err := c.ensureToken()
+	if err != nil {
+		return "", 0, err
+	}
+	c.waitForRateLimit()
+
+	req, err := http.NewRequest("GET", strings.TrimSuffix(c.config.API.BaseURL, "/")+path, nil)
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Accept", "application/json")
+	if c.config.API.Language != "" {
+		req.Header.Set("Accept-Language", c.config.API.Language)
+	}
+
+	var envelope struct {
+		Data  json.RawMessage `json:"data"`
+		Links struct {
+			Next *string `json:"next"`
+		} `json:"links"`
+	}
+	status, err := c.send(req, &envelope)
+	if err != nil {
+		return "", status, err
+	}
+	if len(envelope.Data) > 0 && string(envelope.Data) != "null" {
+		if err := json.Unmarshal(envelope.Data, data); err != nil {
+			return "", status, fmt.Errorf("failed to unmarshal TheTVDB data: %w", err)
+		}
+	}
+	if envelope.Links.Next != nil {
+		return *envelope.Links.Next, status, nil
+	}
+	return "", status, nil
+}
+
+// ensureToken returns the bearer token, logging in when there is none
+func (c *Client) ensureToken() (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.token != "" {
+		return c.token, nil
+	}
+
+	body, err := json.Marshal(map[string]string{"apikey": c.config.API.Key, "pin": c.config.API.PIN})
+	if err != nil {
+		return "", err
+	}
+	req, err := http.NewRequest("POST", strings.TrimSuffix(c.config.API.BaseURL, "/")+"/login", bytes.NewReader(body))
+	if err != nil {
+		return "", fmt.Errorf("failed to create login request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	var login struct {
+		Data struct {
+			Token string `json:"token"`
+		} `json:"data"`
+	}
+	if _, err := c.send(req, &login); err != nil {
+		return "", fmt.Errorf("TheTVDB login failed: %w", err)
+	}
+	if login.Data.Token == "" {
+		return "", fmt.Errorf("TheTVDB login returned no token")
+	}
+	c.token = login.Data.Token
+	return c.token, nil
+}
+
+func (c *Client) send(req *http.Request, result interface{}) (int, error) {
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("HTTP request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return resp.StatusCode, fmt.Errorf("TheTVDB API returned status %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return resp.StatusCode, fmt.Errorf("failed to read response body: %w", err)
+	}
+	if err := json.Unmarshal(body, result); err != nil {
+		return resp.StatusCode, fmt.Errorf("failed to unmarshal JSON response: %w", err)
+	}
+	return resp.StatusCode, nil
+}
+
+// waitForRateLimit spaces requests by the configured rate limit
+func (c *Client) waitForRateLimit() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if wait := c.config.API.GetRequestDelay() - time.Since(c.lastAPICall); wait > 0 {
+		time.Sleep(wait)
+	}
+	c.lastAPICall = time.Now()
+}
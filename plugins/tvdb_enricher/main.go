@@ -0,0 +1,272 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	plugins "github.com/mantonx/viewra/sdk"
+	"google.golang.org/grpc/connectivity"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+
+	"github.com/mantonx/viewra/plugins/tvdb_enricher/internal/config"
+	"github.com/mantonx/viewra/plugins/tvdb_enricher/internal/models"
+	"github.com/mantonx/viewra/plugins/tvdb_enricher/internal/services"
+)
+
+// Version is the plugin version, overridable at build time
+var Version = "1.0.0"
+
+// TVDbEnricher enriches TV episodes from TheTVDB. It only claims episode
+// files, so a library can pick it instead of, or alongside, TMDb through its
+// enrichment providers.
+type TVDbEnricher struct {
+	*plugins.BasePlugin
+
+	db       *gorm.DB
+	logger   plugins.Logger
+	config   *config.Config
+	enricher *services.EnrichmentService
+
+	// TheTVDB request latency, reported to the admin dashboard
+	performanceMonitor *plugins.BasePerformanceMonitor
+
+	// Host service connections
+	unifiedClient *plugins.UnifiedServiceClient
+}
+
+// Plugin lifecycle methods
+func (t *TVDbEnricher) Initialize(ctx *plugins.PluginContext) error {
+	if ctx == nil {
+		return fmt.Errorf("plugin context is nil")
+	}
+	if ctx.Logger == nil {
+		return fmt.Errorf("logger in plugin context is nil")
+	}
+	t.logger = ctx.Logger
+
+	if ctx.PluginBasePath == "" {
+		return fmt.Errorf("PluginBasePath is empty")
+	}
+
+	cfg := config.DefaultConfig()
+	if err := plugins.LoadPluginConfig(ctx, cfg); err != nil {
+		return fmt.Errorf("failed to load TVDb configuration: %w", err)
+	}
+	if err := cfg.Validate(); err != nil {
+		return fmt.Errorf("invalid TVDb configuration: %w", err)
+	}
+	if cfg.API.Key == "" {
+		t.logger.Warn("no TheTVDB API key configured; requests will fail outside mock provider mode")
+	}
+	t.config = cfg
+
+	dbPath := filepath.Join(ctx.PluginBasePath, "tvdb_enricher.db")
+	db, err := gorm.Open(sqlite.Open(dbPath), &gorm.Config{})
+	if err != nil {
+		return fmt.Errorf("failed to open database: %w", err)
+	}
+	if err := models.Migrate(db); err != nil {
+		return fmt.Errorf("failed to migrate database: %w", err)
+	}
+	t.db = db
+
+	if ctx.HostServiceAddr != "" {
+		clientConfig := plugins.DefaultClientConfig()
+		clientConfig.OnStateChange = func(state connectivity.State) {
+			t.logger.Info("host service connection state changed", "state", state.String())
+		}
+		client, err := plugins.NewUnifiedServiceClientWithConfig(ctx.HostServiceAddr, clientConfig)
+		if err != nil {
+			t.logger.Warn("failed to connect to host services", "error", err)
+		} else {
+			t.unifiedClient = client
+		}
+	}
+
+	t.performanceMonitor = plugins.NewBasePerformanceMonitor("TVDb Enricher")
+	t.enricher = services.NewEnrichmentService(t.db, t.config, t.unifiedClient, t.logger, t.performanceMonitor)
+
+	t.logger.Info("TVDb Enricher initialized", "episode_order", cfg.Episodes.Order, "library_orders", cfg.Episodes.LibraryOrders)
+	return nil
+}
+
+func (t *TVDbEnricher) Start() error {
+	t.logger.Info("TVDb Enricher started")
+	return nil
+}
+
+func (t *TVDbEnricher) Stop() error {
+	if t.db != nil {
+		if sqlDB, err := t.db.DB(); err == nil {
+			sqlDB.Close()
+		}
+	}
+	if t.unifiedClient != nil {
+		t.unifiedClient.Close()
+	}
+	t.logger.Info("TVDb Enricher stopped")
+	return nil
+}
+
+func (t *TVDbEnricher) Info() (*plugins.PluginInfo, error) {
+	return &plugins.PluginInfo{
+		ID:          "tvdb_enricher",
+		Name:        "TVDb Metadata Enricher",
+		Version:     Version,
+		Type:        "metadata_scraper",
+		Description: "Enriches TV episodes using TheTVDB v4 API, with aired, DVD and absolute episode ordering",
+		Author:      "Viewra Team",
+	}, nil
+}
+
+// Health returns nil if the plugin is healthy
+func (t *TVDbEnricher) Health() error {
+	if t.db == nil {
+		return fmt.Errorf("database not initialized")
+	}
+	if sqlDB, err := t.db.DB(); err != nil {
+		return fmt.Errorf("database error: %w", err)
+	} else if err := sqlDB.Ping(); err != nil {
+		return fmt.Errorf("database ping failed: %w", err)
+	}
+	return nil
+}
+
+// Scanner hook service implementation
+func (t *TVDbEnricher) OnMediaFileScanned(mediaFileID string, filePath string, metadata map[string]string) error {
+	if !t.config.Features.AutoEnrich {
+		return plugins.SkipHook(plugins.SkipReasonDisabled, "auto-enrichment is disabled")
+	}
+
+	// TheTVDB only has series; anything the scanner didn't classify as an
+	// episode is left to other enrichers
+	if mediaType := metadata[plugins.HookMetadataMediaType]; mediaType != "" && mediaType != "episode" {
+		return plugins.SkipHook(plugins.SkipReasonUnsupportedType, "not a TV episode")
+	}
+	switch metadata[plugins.HookMetadataLibraryType] {
+	case "movie", "music", "home":
+		return plugins.SkipHook(plugins.SkipReasonUnsupportedType, "not a TV library")
+	}
+
+	if err := t.enricher.ProcessMediaFile(mediaFileID, filePath, metadata); err != nil {
+		if _, skipped := plugins.AsSkipError(err); !skipped {
+			t.logger.Warn("enrichment failed", "error", err, "media_file_id", mediaFileID)
+		}
+		return err
+	}
+	return nil
+}
+
+// OnMediaFileRemoved drops the enrichment of a deleted file
+func (t *TVDbEnricher) OnMediaFileRemoved(mediaFileID string, filePath string, metadata map[string]string) error {
+	if err := t.enricher.RemoveMediaFile(mediaFileID); err != nil {
+		t.logger.Warn("failed to remove enrichment", "error", err, "media_file_id", mediaFileID)
+		return err
+	}
+	return nil
+}
+
+// OnMediaFileUpdated matches a replaced or retagged file again
+func (t *TVDbEnricher) OnMediaFileUpdated(mediaFileID string, filePath string, metadata map[string]string) error {
+	if !t.config.Features.AutoEnrich {
+		return plugins.SkipHook(plugins.SkipReasonDisabled, "auto-enrichment is disabled")
+	}
+	if err := t.enricher.RemoveMediaFile(mediaFileID); err != nil {
+		return err
+	}
+	return t.OnMediaFileScanned(mediaFileID, filePath, metadata)
+}
+
+func (t *TVDbEnricher) OnScanStarted(scanJobID, libraryID uint32, libraryPath string) error {
+	t.logger.Info("scan started", "scan_job_id", scanJobID, "library_id", libraryID,
+		"episode_order", t.config.Episodes.EpisodeOrderFor(fmt.Sprint(libraryID)))
+	return nil
+}
+
+func (t *TVDbEnricher) OnScanCompleted(scanJobID, libraryID uint32, stats map[string]string) error {
+	if err := t.enricher.CleanupExpiredCache(); err != nil {
+		t.logger.Warn("failed to clean up expired cache", "error", err)
+	}
+	return nil
+}
+
+// Metadata scraper service implementation
+func (t *TVDbEnricher) CanHandle(filePath, mimeType string) bool {
+	if strings.HasPrefix(mimeType, "video/") {
+		return true
+	}
+
+	ext := strings.ToLower(filepath.Ext(filePath))
+	videoExtensions := []string{".mp4", ".mkv", ".avi", ".mov", ".wmv", ".flv", ".webm", ".m4v", ".ts", ".mpg", ".mpeg"}
+	for _, videoExt := range videoExtensions {
+		if ext == videoExt {
+			return true
+		}
+	}
+	return false
+}
+
+func (t *TVDbEnricher) ExtractMetadata(filePath string) (map[string]string, error) {
+	// This plugin doesn't read files; it enriches episodes from TheTVDB
+	return map[string]string{
+		"source": "tvdb_enricher",
+	}, nil
+}
+
+func (t *TVDbEnricher) GetSupportedTypes() []string {
+	return []string{"tv", "episode"}
+}
+
+// Database service implementation
+func (t *TVDbEnricher) GetModels() []string {
+	return []string{
+		"TVDbCache",
+		"TVDbEnrichment",
+	}
+}
+
+func (t *TVDbEnricher) Migrate(connectionString string) error {
+	db, err := gorm.Open(sqlite.Open(connectionString), &gorm.Config{})
+	if err != nil {
+		return fmt.Errorf("failed to connect to database: %w", err)
+	}
+	if err := models.Migrate(db); err != nil {
+		return fmt.Errorf("failed to migrate database: %w", err)
+	}
+	return nil
+}
+
+func (t *TVDbEnricher) Rollback(connectionString string) error {
+	db, err := gorm.Open(sqlite.Open(connectionString), &gorm.Config{})
+	if err != nil {
+		return fmt.Errorf("failed to connect to database: %w", err)
+	}
+	return db.Migrator().DropTable(&models.TVDbCache{}, &models.TVDbEnrichment{})
+}
+
+// Service interfaces implementation
+func (t *TVDbEnricher) MetadataScraperService() plugins.MetadataScraperService {
+	return t
+}
+
+func (t *TVDbEnricher) ScannerHookService() plugins.ScannerHookService {
+	return t
+}
+
+func (t *TVDbEnricher) DatabaseService() plugins.DatabaseService {
+	return t
+}
+
+// PerformanceMonitorService reports TheTVDB request latency and status codes
+func (t *TVDbEnricher) PerformanceMonitorService() plugins.PerformanceMonitorService {
+	return t.performanceMonitor
+}
+
+func main() {
+	plugin := &TVDbEnricher{
+		BasePlugin: plugins.NewBasePlugin("TVDb Metadata Enricher", Version, "metadata_scraper", "Enriches TV episodes using TheTVDB"),
+	}
+	plugins.StartPlugin(plugin)
+}
@@ -0,0 +1,90 @@
+#Plugin: {
+	schema_version: "1.0"
+
+	// Plugin identification
+	id:            "tvdb_enricher"
+	name:          "TVDb Metadata Enricher"
+	version:       "1.0.0"
+	description:   "Enriches TV episodes using TheTVDB v4 API, with aired, DVD and absolute episode ordering"
+	author:        "Viewra Team"
+	website:       "https://github.com/mantonx/viewra"
+	repository:    "https://github.com/mantonx/viewra"
+	license:       "MIT"
+	type:          "metadata_scraper"
+	tags: [
+		"tv",
+		"metadata",
+		"enrichment",
+		"tvdb",
+		"external-api"
+	]
+	media_types: ["tv"]
+
+	// Plugin behavior
+	enabled_by_default: true
+
+	// Plugin capabilities
+	capabilities: {
+		metadata_extraction: true
+		scanner_hooks:       true
+		search_service:      false
+		api_endpoints:       false
+		database_access:     true
+		background_tasks:    false
+		external_services:   true
+		asset_management:    false
+	}
+
+	// Entry points
+	entry_points: {
+		main: "tvdb_enricher"
+	}
+
+	// Permissions
+	permissions: [
+		"database:read",
+		"database:write",
+		"network:external"
+	]
+
+	settings: {
+		// TheTVDB v4 API settings. A project API key is required; the PIN is
+		// only needed for user-supported keys. language is the ISO 639-2 code
+		// of episode names and rate_limit is in requests per second.
+		api: {
+			key:         string | *""
+			pin:         string | *""
+			base_url:    string | *"https://api4.thetvdb.com/v4"
+			language:    string | *"eng"
+			timeout_sec: int | *30
+			rate_limit:  float64 | *5.0
+		}
+
+		// Enrich automatically during scanning, and re-enrich files that
+		// already have TVDb data when overwrite_existing is set
+		features: {
+			auto_enrich:        bool | *true
+			overwrite_existing: bool | *false
+		}
+
+		// Minimum similarity score for series matches, and the +/- years a
+		// file's year may differ from the series' first air year
+		matching: {
+			match_threshold: float64 | *0.8
+			year_tolerance:  int | *1
+		}
+
+		// Episode ordering: "default" (aired), "dvd" or "absolute".
+		// library_orders overrides the order per library as
+		// "<library id>=<order>" pairs, e.g. "3=dvd,7=absolute".
+		episodes: {
+			order:          string | *"default"
+			library_orders: string | *""
+		}
+
+		// Hours API responses are cached for
+		cache: {
+			duration_hours: int | *168
+		}
+	}
+}
@@ -0,0 +1,145 @@
+package plugins
+
+import (
+	"context"
+	"sort"
+
+	"github.com/mantonx/viewra/sdk/proto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// SDKVersion is the version of this SDK, reported to the host during the handshake
+const SDKVersion = "1.2.0"
+
+// ABIVersion is the plugin protocol revision this SDK speaks. It is bumped
+// whenever a service or message changes in a way older peers can't handle.
+//
+//	1: plugin.proto services only, no handshake
+//	2: ABI handshake, HTTP bridge, JSON configuration schema, typed errors
+const ABIVersion = 2
+
+// LegacyABIVersion is assumed for plugins built before the handshake existed
+const LegacyABIVersion = 1
+
+// MinHostABIVersion is the oldest host protocol plugins built with this SDK run against
+const MinHostABIVersion = LegacyABIVersion
+
+// ABIServiceName is the gRPC service used for the version handshake
+const ABIServiceName = "viewra.ABIService"
+
+// ABINegotiateMethod is the full gRPC method name for the handshake
+const ABINegotiateMethod = "/" + ABIServiceName + "/Negotiate"
+
+// ABIHandshakeRequest describes the host to the plugin
+type ABIHandshakeRequest struct {
+	HostVersion   string `json:"host_version"`
+	ABIVersion    int    `json:"abi_version"`
+	MinABIVersion int    `json:"min_abi_version"`
+	// MaxMessageSize is the largest message in bytes the host's service
+	// server accepts, 0 from hosts that predate it
+	MaxMessageSize int `json:"max_message_size,omitempty"`
+}
+
+// ABIHandshakeResponse describes the plugin's SDK and the gRPC services it serves
+type ABIHandshakeResponse struct {
+	SDKVersion        string   `json:"sdk_version"`
+	ABIVersion        int      `json:"abi_version"`
+	MinHostABIVersion int      `json:"min_host_abi_version"`
+	Services          []string `json:"services"`
+}
+
+type abiServer interface {
+	Negotiate(context.Context, *ABIHandshakeRequest) (*ABIHandshakeResponse, error)
+}
+
+// ABIServer answers the host's handshake. Services are read from the gRPC
+// server at call time so they reflect everything registered by GRPCServer.
+type ABIServer struct {
+	server *grpc.Server
+}
+
+// Negotiate reports this SDK's versions and services, refusing hosts too old to run the plugin
+func (s *ABIServer) Negotiate(ctx context.Context, req *ABIHandshakeRequest) (*ABIHandshakeResponse, error) {
+	if req.ABIVersion < MinHostABIVersion {
+		return nil, status.Errorf(codes.FailedPrecondition,
+			"plugin built with SDK %s requires host ABI %d or newer, host %s speaks ABI %d",
+			SDKVersion, MinHostABIVersion, req.HostVersion, req.ABIVersion)
+	}
+
+	setHostMaxMessageSize(req.MaxMessageSize)
+
+	services := make([]string, 0)
+	for name := range s.server.GetServiceInfo() {
+		services = append(services, name)
+	}
+	sort.Strings(services)
+
+	return &ABIHandshakeResponse{
+		SDKVersion:        SDKVersion,
+		ABIVersion:        ABIVersion,
+		MinHostABIVersion: MinHostABIVersion,
+		Services:          services,
+	}, nil
+}
+
+func negotiateHandler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ABIHandshakeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(abiServer).Negotiate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ABINegotiateMethod,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(abiServer).Negotiate(ctx, req.(*ABIHandshakeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// abiServiceDesc describes the handshake service, hand-written like the HTTP bridge
+var abiServiceDesc = grpc.ServiceDesc{
+	ServiceName: ABIServiceName,
+	HandlerType: (*abiServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Negotiate",
+			Handler:    negotiateHandler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "abi.go",
+}
+
+// RegisterABIServer registers the handshake service on a plugin's gRPC server
+func RegisterABIServer(s *grpc.Server) {
+	s.RegisterService(&abiServiceDesc, &ABIServer{server: s})
+}
+
+// NegotiateABI performs the version handshake with a plugin. Plugins built
+// before the handshake existed return a codes.Unimplemented status.
+func NegotiateABI(ctx context.Context, conn grpc.ClientConnInterface, req *ABIHandshakeRequest, opts ...grpc.CallOption) (*ABIHandshakeResponse, error) {
+	resp := new(ABIHandshakeResponse)
+	opts = append([]grpc.CallOption{grpc.CallContentSubtype(JSONCodec)}, opts...)
+	if err := conn.Invoke(ctx, ABINegotiateMethod, req, resp, opts...); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// LegacyServices lists the services defined in plugin.proto, the most a
+// plugin predating the handshake can serve
+func LegacyServices() []string {
+	descriptors := proto.File_plugin_proto.Services()
+	services := make([]string, 0, descriptors.Len())
+	for i := 0; i < descriptors.Len(); i++ {
+		services = append(services, string(descriptors.Get(i).FullName()))
+	}
+	sort.Strings(services)
+	return services
+}
@@ -7,7 +7,6 @@ import (
 
 	"github.com/mantonx/viewra/sdk/proto"
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
 )
 
 // GRPCAssetServiceClient implements AssetServiceClient using gRPC
@@ -26,16 +25,9 @@ func NewAssetServiceClient(hostServiceAddr string) (AssetServiceClient, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
-	// Connect to host gRPC service with appropriate message size limits
-	opts := []grpc.DialOption{
-		grpc.WithTransportCredentials(insecure.NewCredentials()),
-		grpc.WithDefaultCallOptions(
-			grpc.MaxCallRecvMsgSize(16*1024*1024), // 16MB to match server
-			grpc.MaxCallSendMsgSize(16*1024*1024), // 16MB to match server
-		),
-	}
-	
-	conn, err := grpc.DialContext(ctx, hostServiceAddr, opts...)
+	// Connect to host gRPC service with the host's message size limits
+	cfg := DefaultClientConfig()
+	conn, err := grpc.DialContext(ctx, hostServiceAddr, hostDialOptions(cfg, negotiatedMessageSize(cfg.MaxMessageSize))...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to host service: %w", err)
 	}
@@ -115,6 +107,37 @@ func (c *GRPCAssetServiceClient) RemoveAsset(ctx context.Context, req *RemoveAss
 	}, nil
 }
 
+// DownloadAsset implements AssetServiceClient.DownloadAsset
+func (c *GRPCAssetServiceClient) DownloadAsset(ctx context.Context, req *DownloadAssetRequest) (*DownloadAssetResponse, error) {
+	protoReq := &proto.DownloadAssetRequest{
+		Url:         req.URL,
+		MediaFileId: req.MediaFileID,
+		AssetType:   req.AssetType,
+		Category:    req.Category,
+		Subtype:     req.Subtype,
+		Metadata:    req.Metadata,
+		PluginId:    req.PluginID,
+		Headers:     req.Headers,
+		MaxSize:     req.MaxSize,
+	}
+
+	protoResp, err := c.client.DownloadAsset(ctx, protoReq)
+	if err != nil {
+		return nil, err
+	}
+
+	return &DownloadAssetResponse{
+		Success:      protoResp.Success,
+		Error:        protoResp.Error,
+		AssetID:      protoResp.AssetId,
+		Hash:         protoResp.Hash,
+		RelativePath: protoResp.RelativePath,
+		Size:         protoResp.Size,
+		MimeType:     protoResp.MimeType,
+		StatusCode:   int(protoResp.StatusCode),
+	}, nil
+}
+
 // Close closes the gRPC connection
 func (c *GRPCAssetServiceClient) Close() error {
 	if c.conn != nil {
@@ -0,0 +1,198 @@
+// Package cassette replays recorded metadata provider responses so enrichment
+// plugins can run without API keys or network access.
+//
+// A cassette is a JSON file of request/response pairs for one provider.
+// Cassettes for TMDb, TheTVDB, MusicBrainz, AudioDB and OpenSubtitles are
+// bundled with the SDK; a directory of additional cassettes can be layered on
+// top and is searched first. Transport serves matching requests from cassettes and, in record
+// mode, captures live responses into new ones.
+package cassette
+
+import (
+	"embed"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+//go:embed cassettes/*.json
+var bundled embed.FS
+
+// Cassette holds the recorded interactions for one provider
+type Cassette struct {
+	Provider     string        `json:"provider"`
+	Interactions []Interaction `json:"interactions"`
+}
+
+// Interaction pairs a request pattern with the response to replay for it
+type Interaction struct {
+	Request  Request  `json:"request"`
+	Response Response `json:"response"`
+}
+
+// Request describes which requests an interaction answers.
+//
+// Path segments of "*" match any single segment and a final "**" matches
+// any remainder. Query lists the parameters that must be present; a value
+// of "*" accepts anything and other values compare case-insensitively.
+// Parameters not listed, such as language or API keys, are ignored.
+type Request struct {
+	Method string            `json:"method,omitempty"`
+	Host   string            `json:"host"`
+	Path   string            `json:"path"`
+	Query  map[string]string `json:"query,omitempty"`
+}
+
+// Response is a recorded reply. JSON bodies are stored inline in Body;
+// anything else is base64 encoded in BodyBase64.
+type Response struct {
+	Status     int               `json:"status,omitempty"`
+	Headers    map[string]string `json:"headers,omitempty"`
+	Body       json.RawMessage   `json:"body,omitempty"`
+	BodyBase64 string            `json:"body_base64,omitempty"`
+}
+
+// body returns the raw response bytes
+func (r Response) body() ([]byte, error) {
+	if r.BodyBase64 != "" {
+		return base64.StdEncoding.DecodeString(r.BodyBase64)
+	}
+	return r.Body, nil
+}
+
+// matches reports whether the interaction answers req
+func (r Request) matches(method string, u *url.URL) bool {
+	want := r.Method
+	if want == "" {
+		want = "GET"
+	}
+	if !strings.EqualFold(want, method) || !strings.EqualFold(r.Host, u.Host) {
+		return false
+	}
+	if !pathMatches(r.Path, u.Path) {
+		return false
+	}
+	query := u.Query()
+	for key, value := range r.Query {
+		got, ok := query[key]
+		if !ok {
+			return false
+		}
+		if value != "*" && !strings.EqualFold(strings.TrimSpace(got[0]), strings.TrimSpace(value)) {
+			return false
+		}
+	}
+	return true
+}
+
+func pathMatches(pattern, p string) bool {
+	want := strings.Split(strings.Trim(pattern, "/"), "/")
+	got := strings.Split(strings.Trim(path.Clean("/"+p), "/"), "/")
+	for i, segment := range want {
+		if segment == "**" && i == len(want)-1 {
+			return true
+		}
+		if i >= len(got) || (segment != "*" && segment != got[i]) {
+			return false
+		}
+	}
+	return len(want) == len(got)
+}
+
+// Library is an ordered set of cassettes searched for matching interactions
+type Library struct {
+	cassettes []*Cassette
+}
+
+// Load returns the cassettes in dir followed by the bundled ones. dir may be
+// empty to use only the bundled cassettes.
+func Load(dir string) (*Library, error) {
+	lib := &Library{}
+	if dir != "" {
+		if err := lib.addDir(os.DirFS(dir), "."); err != nil && !os.IsNotExist(err) {
+			return nil, fmt.Errorf("failed to load cassettes from %s: %w", dir, err)
+		}
+	}
+	if err := lib.addDir(bundled, "cassettes"); err != nil {
+		return nil, fmt.Errorf("failed to load bundled cassettes: %w", err)
+	}
+	return lib, nil
+}
+
+func (l *Library) addDir(fsys fs.FS, dir string) error {
+	names, err := fs.Glob(fsys, path.Join(dir, "*.json"))
+	if err != nil {
+		return err
+	}
+	if len(names) == 0 {
+		if _, err := fs.Stat(fsys, dir); err != nil {
+			return err
+		}
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		data, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return err
+		}
+		var c Cassette
+		if err := json.Unmarshal(data, &c); err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+		l.cassettes = append(l.cassettes, &c)
+	}
+	return nil
+}
+
+// Find returns the first interaction that answers the request
+func (l *Library) Find(method string, u *url.URL) (*Interaction, bool) {
+	for _, c := range l.cassettes {
+		for i := range c.Interactions {
+			if c.Interactions[i].Request.matches(method, u) {
+				return &c.Interactions[i], true
+			}
+		}
+	}
+	return nil, false
+}
+
+// Providers lists the providers covered by the library
+func (l *Library) Providers() []string {
+	var providers []string
+	seen := map[string]bool{}
+	for _, c := range l.cassettes {
+		if !seen[c.Provider] {
+			seen[c.Provider] = true
+			providers = append(providers, c.Provider)
+		}
+	}
+	return providers
+}
+
+// appendInteraction adds an interaction to <dir>/<provider>.json
+func appendInteraction(dir, provider string, interaction Interaction) error {
+	file := filepath.Join(dir, provider+".json")
+	c := Cassette{Provider: provider}
+	if data, err := os.ReadFile(file); err == nil {
+		if err := json.Unmarshal(data, &c); err != nil {
+			return fmt.Errorf("failed to read cassette %s: %w", file, err)
+		}
+	}
+	c.Interactions = append(c.Interactions, interaction)
+
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(file, data, 0644)
+}
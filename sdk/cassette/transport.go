@@ -0,0 +1,274 @@
+package cassette
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+)
+
+const (
+	// ModeEnv selects mock provider mode: off, replay or record. The values
+	// 1 and true are accepted as replay.
+	ModeEnv = "VIEWRA_MOCK_PROVIDERS"
+	// DirEnv names a directory of cassettes searched before the bundled ones,
+	// and where record mode writes new interactions
+	DirEnv = "VIEWRA_PROVIDER_CASSETTES"
+)
+
+// Mode controls how provider requests are handled
+type Mode string
+
+const (
+	// ModeOff sends requests to the real providers
+	ModeOff Mode = "off"
+	// ModeReplay answers requests from cassettes and never touches the network
+	ModeReplay Mode = "replay"
+	// ModeRecord sends requests to the real providers and saves the responses
+	ModeRecord Mode = "record"
+)
+
+// ParseMode parses a mode name, treating empty, 0 and false as off and 1 and
+// true as replay
+func ParseMode(s string) (Mode, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "", "0", "false", "off":
+		return ModeOff, nil
+	case "1", "true", "replay":
+		return ModeReplay, nil
+	case "record":
+		return ModeRecord, nil
+	}
+	return ModeOff, fmt.Errorf("invalid %s value %q: use off, replay or record", ModeEnv, s)
+}
+
+// ModeFromEnv returns the mode set in VIEWRA_MOCK_PROVIDERS
+func ModeFromEnv() (Mode, error) {
+	return ParseMode(os.Getenv(ModeEnv))
+}
+
+// providerHosts maps API hosts to the cassette they're recorded in
+var providerHosts = map[string]string{
+	"api.themoviedb.org":    "tmdb",
+	"image.tmdb.org":        "tmdb",
+	"musicbrainz.org":       "musicbrainz",
+	"coverartarchive.org":   "musicbrainz",
+	"www.theaudiodb.com":    "audiodb",
+	"theaudiodb.com":        "audiodb",
+	"api4.thetvdb.com":      "tvdb",
+	"artworks.thetvdb.com":  "tvdb",
+	"api.opensubtitles.com": "opensubtitles",
+	"www.opensubtitles.com": "opensubtitles",
+}
+
+// secretParams are query parameters never written to a cassette
+var secretParams = map[string]bool{
+	"api_key": true, "apikey": true, "key": true, "token": true, "access_token": true,
+}
+
+// Transport is an http.RoundTripper that replays or records provider traffic
+type Transport struct {
+	mode    Mode
+	dir     string
+	library *Library
+	base    http.RoundTripper
+	mu      sync.Mutex
+}
+
+// NewTransport returns a transport for mode. dir holds extra cassettes and is
+// required for record mode. base performs real requests in record mode and
+// defaults to http.DefaultTransport.
+func NewTransport(mode Mode, dir string, base http.RoundTripper) (*Transport, error) {
+	if mode == ModeRecord && dir == "" {
+		return nil, fmt.Errorf("record mode needs a cassette directory; set %s", DirEnv)
+	}
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	library, err := Load(dir)
+	if err != nil {
+		return nil, err
+	}
+	return &Transport{mode: mode, dir: dir, library: library, base: base}, nil
+}
+
+// Wrap returns base wrapped according to VIEWRA_MOCK_PROVIDERS and
+// VIEWRA_PROVIDER_CASSETTES, or base itself when mock mode is off
+func Wrap(base http.RoundTripper) (http.RoundTripper, error) {
+	mode, err := ModeFromEnv()
+	if err != nil {
+		return nil, err
+	}
+	if mode == ModeOff {
+		if base == nil {
+			base = http.DefaultTransport
+		}
+		return base, nil
+	}
+	return NewTransport(mode, os.Getenv(DirEnv), base)
+}
+
+// Mode returns the transport's mode
+func (t *Transport) Mode() Mode {
+	return t.mode
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if t.mode == ModeRecord {
+		return t.record(req)
+	}
+
+	interaction, ok := t.library.Find(req.Method, req.URL)
+	if !ok {
+		if req.Body != nil {
+			req.Body.Close()
+		}
+		return nil, fmt.Errorf("mock provider mode: no recorded response for %s %s", req.Method, redactURL(req.URL))
+	}
+	return interaction.Response.toHTTP(req)
+}
+
+func (r Response) toHTTP(req *http.Request) (*http.Response, error) {
+	body, err := r.body()
+	if err != nil {
+		return nil, fmt.Errorf("mock provider mode: invalid cassette body for %s: %w", redactURL(req.URL), err)
+	}
+	status := r.Status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	header := http.Header{}
+	for k, v := range r.Headers {
+		header.Set(k, v)
+	}
+	if header.Get("Content-Type") == "" && len(r.Body) > 0 {
+		header.Set("Content-Type", "application/json")
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", status, http.StatusText(status)),
+		StatusCode:    status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}, nil
+}
+
+// record performs the live request and appends the exchange to the
+// provider's cassette in the cassette directory
+func (t *Transport) record(req *http.Request) (*http.Response, error) {
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	recorded := Response{Status: resp.StatusCode}
+	if ct := resp.Header.Get("Content-Type"); ct != "" {
+		recorded.Headers = map[string]string{"Content-Type": ct}
+	}
+	if json.Valid(body) {
+		recorded.Body = json.RawMessage(redactBody(req.URL.Host, req.URL.Path, body))
+	} else {
+		recorded.BodyBase64 = base64.StdEncoding.EncodeToString(body)
+	}
+
+	query := map[string]string{}
+	for key, values := range req.URL.Query() {
+		if !secretParams[strings.ToLower(key)] && len(values) > 0 {
+			query[key] = values[0]
+		}
+	}
+	interaction := Interaction{
+		Request: Request{
+			Method: req.Method,
+			Host:   req.URL.Host,
+			Path:   redactPath(req.URL.Host, req.URL.Path),
+			Query:  query,
+		},
+		Response: recorded,
+	}
+
+	provider := providerHosts[strings.ToLower(req.URL.Hostname())]
+	if provider == "" {
+		provider = req.URL.Hostname()
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if err := appendInteraction(t.dir, provider, interaction); err != nil {
+		return nil, fmt.Errorf("mock provider mode: failed to record %s: %w", redactURL(req.URL), err)
+	}
+	return resp, nil
+}
+
+// redactPath strips API keys that providers put in the path. AudioDB uses
+// /api/v1/json/<key>/...
+func redactPath(host, p string) string {
+	if providerHosts[strings.ToLower(host)] != "audiodb" {
+		return p
+	}
+	segments := strings.Split(p, "/")
+	for i := 0; i+1 < len(segments); i++ {
+		if segments[i] == "json" {
+			segments[i+1] = "*"
+			break
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+// redactBody strips session tokens from login responses. TheTVDB answers
+// its login with a bearer token that stays valid for a month, OpenSubtitles
+// with one tied to the user's download quota.
+func redactBody(host, p string, body []byte) []byte {
+	provider := providerHosts[strings.ToLower(host)]
+	if (provider != "tvdb" && provider != "opensubtitles") || !strings.HasSuffix(p, "/login") {
+		return body
+	}
+	var login map[string]interface{}
+	if err := json.Unmarshal(body, &login); err != nil {
+		return body
+	}
+	if data, ok := login["data"].(map[string]interface{}); ok && data["token"] != nil {
+		data["token"] = "recorded"
+	}
+	if login["token"] != nil {
+		login["token"] = "recorded"
+	}
+	redacted, err := json.Marshal(login)
+	if err != nil {
+		return body
+	}
+	return redacted
+}
+
+// redactURL formats u for error messages without secrets
+func redactURL(u *url.URL) string {
+	clean := *u
+	query := clean.Query()
+	for key := range query {
+		if secretParams[strings.ToLower(key)] {
+			query.Del(key)
+		}
+	}
+	clean.RawQuery = query.Encode()
+	clean.Path = redactPath(clean.Host, clean.Path)
+	clean.RawPath = ""
+	return clean.String()
+}
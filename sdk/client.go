@@ -3,53 +3,231 @@ package plugins
 import (
 	"context"
 	"fmt"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	pluginspb "github.com/mantonx/viewra/sdk/proto"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/backoff"
+	"google.golang.org/grpc/connectivity"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/encoding/gzip"
+	"google.golang.org/grpc/keepalive"
 )
 
+// DefaultMaxMessageSize is the message size limit assumed for hosts that
+// don't report theirs during the handshake
+const DefaultMaxMessageSize = 16 * 1024 * 1024
+
+// assetRequestOverhead is left for the fields of a SaveAsset request other
+// than the asset data
+const assetRequestOverhead = 64 * 1024
+
+// hostMaxMessageSize is the limit the host reported during the handshake
+var hostMaxMessageSize atomic.Int64
+
+func setHostMaxMessageSize(size int) {
+	if size > 0 {
+		hostMaxMessageSize.Store(int64(size))
+	}
+}
+
+// HostMaxMessageSize returns the largest message the host accepts, as
+// reported during the handshake, or DefaultMaxMessageSize
+func HostMaxMessageSize() int {
+	if size := hostMaxMessageSize.Load(); size > 0 {
+		return int(size)
+	}
+	return DefaultMaxMessageSize
+}
+
+// ClientConfig controls how the unified client keeps its connection to the
+// host alive during long scans
+type ClientConfig struct {
+	// KeepaliveTime is how often an idle connection is pinged. The host
+	// accepts pings every 20 seconds at most.
+	KeepaliveTime time.Duration
+	// KeepaliveTimeout is how long a ping may go unanswered before the
+	// connection is considered dead and reconnected
+	KeepaliveTimeout time.Duration
+	// CallTimeout bounds calls whose context has no deadline, including
+	// the time spent waiting for a reconnect
+	CallTimeout time.Duration
+	// ReconnectBaseDelay and ReconnectMaxDelay bound the exponential backoff
+	// between reconnect attempts
+	ReconnectBaseDelay time.Duration
+	ReconnectMaxDelay  time.Duration
+	// MaxMessageSize caps messages in bytes in both directions. 0 uses the
+	// host's limit; larger values are lowered to it since the host would
+	// reject the messages anyway.
+	MaxMessageSize int
+	// Compression gzips requests. Responses are compressed the same way.
+	Compression bool
+	// OnStateChange, when set, is called from a background goroutine each
+	// time the connection changes state, e.g. to log reconnects
+	OnStateChange func(state connectivity.State)
+}
+
+// DefaultClientConfig returns the settings NewUnifiedServiceClient uses
+func DefaultClientConfig() *ClientConfig {
+	return &ClientConfig{
+		KeepaliveTime:      30 * time.Second,
+		KeepaliveTimeout:   10 * time.Second,
+		CallTimeout:        30 * time.Second,
+		ReconnectBaseDelay: 1 * time.Second,
+		ReconnectMaxDelay:  30 * time.Second,
+		Compression:        true,
+	}
+}
+
 // UnifiedServiceClient provides both asset and enrichment services from a single connection
 type UnifiedServiceClient struct {
-	conn        *grpc.ClientConn
-	assetClient pluginspb.AssetServiceClient
+	conn           *grpc.ClientConn
+	assetClient    pluginspb.AssetServiceClient
+	maxMessageSize int
 	// Remove enrichment client for now
 	// enrichmentClient  enrichmentpb.EnrichmentServiceClient
+
+	stopWatch context.CancelFunc
+	closeOnce sync.Once
 }
 
 // NewUnifiedServiceClient creates a single connection that provides both asset and enrichment services
 func NewUnifiedServiceClient(hostServiceAddr string) (*UnifiedServiceClient, error) {
+	return NewUnifiedServiceClientWithConfig(hostServiceAddr, DefaultClientConfig())
+}
+
+// NewUnifiedServiceClientWithConfig creates the unified client with custom
+// keepalive, timeout and reconnect settings
+func NewUnifiedServiceClientWithConfig(hostServiceAddr string, cfg *ClientConfig) (*UnifiedServiceClient, error) {
 	if hostServiceAddr == "" {
 		return nil, fmt.Errorf("host service address is required")
 	}
+	if cfg == nil {
+		cfg = DefaultClientConfig()
+	}
 
 	// Create connection context with timeout
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
 	// Connect to host gRPC service (both services run on same server)
-	opts := []grpc.DialOption{
-		grpc.WithTransportCredentials(insecure.NewCredentials()),
-		grpc.WithDefaultCallOptions(
-			grpc.MaxCallRecvMsgSize(16*1024*1024), // 16MB to match server
-			grpc.MaxCallSendMsgSize(16*1024*1024), // 16MB to match server
-		),
-	}
-
-	conn, err := grpc.DialContext(ctx, hostServiceAddr, opts...)
+	maxMessageSize := negotiatedMessageSize(cfg.MaxMessageSize)
+	conn, err := grpc.DialContext(ctx, hostServiceAddr, hostDialOptions(cfg, maxMessageSize)...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to host service: %w", err)
 	}
 
+	watchCtx, stopWatch := context.WithCancel(context.Background())
+	go watchConnection(watchCtx, conn, cfg.OnStateChange)
+
 	return &UnifiedServiceClient{
-		conn:        conn,
-		assetClient: pluginspb.NewAssetServiceClient(conn),
+		conn:           conn,
+		assetClient:    pluginspb.NewAssetServiceClient(conn),
+		maxMessageSize: maxMessageSize,
 		// Remove enrichment client initialization
 		// enrichmentClient:  enrichmentpb.NewEnrichmentServiceClient(conn),
+		stopWatch: stopWatch,
 	}, nil
 }
 
+// negotiatedMessageSize returns the message size limit to use given the
+// configured one, never above what the host accepts
+func negotiatedMessageSize(configured int) int {
+	hostLimit := HostMaxMessageSize()
+	if configured <= 0 || configured > hostLimit {
+		return hostLimit
+	}
+	return configured
+}
+
+// hostDialOptions builds the dial options for a connection to the host.
+// Keepalive pings notice connections that died silently and calls wait for
+// the connection to come back, up to the call timeout, instead of failing
+// while it reconnects.
+func hostDialOptions(cfg *ClientConfig, maxMessageSize int) []grpc.DialOption {
+	backoffConfig := backoff.DefaultConfig
+	backoffConfig.BaseDelay = cfg.ReconnectBaseDelay
+	backoffConfig.MaxDelay = cfg.ReconnectMaxDelay
+
+	callOptions := []grpc.CallOption{
+		grpc.MaxCallRecvMsgSize(maxMessageSize),
+		grpc.MaxCallSendMsgSize(maxMessageSize),
+		grpc.WaitForReady(true),
+	}
+	if cfg.Compression {
+		callOptions = append(callOptions, grpc.UseCompressor(gzip.Name))
+	}
+
+	return []grpc.DialOption{
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithDefaultCallOptions(callOptions...),
+		grpc.WithKeepaliveParams(keepalive.ClientParameters{
+			Time:                cfg.KeepaliveTime,
+			Timeout:             cfg.KeepaliveTimeout,
+			PermitWithoutStream: true,
+		}),
+		grpc.WithConnectParams(grpc.ConnectParams{
+			Backoff:           backoffConfig,
+			MinConnectTimeout: 10 * time.Second,
+		}),
+		grpc.WithUnaryInterceptor(callTimeoutInterceptor(cfg.CallTimeout)),
+	}
+}
+
+// callTimeoutInterceptor applies timeout to calls made without a deadline so
+// a call can't hang on a connection that never comes back
+func callTimeoutInterceptor(timeout time.Duration) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		if _, ok := ctx.Deadline(); !ok && timeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, timeout)
+			defer cancel()
+		}
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+}
+
+// watchConnection reports state changes to onStateChange and reconnects
+// right away when the connection goes idle, e.g. after the host restarted,
+// rather than waiting for the next call to do so
+func watchConnection(ctx context.Context, conn *grpc.ClientConn, onStateChange func(connectivity.State)) {
+	state := conn.GetState()
+	for {
+		if state == connectivity.Idle {
+			conn.Connect()
+		}
+		if !conn.WaitForStateChange(ctx, state) {
+			return
+		}
+		state = conn.GetState()
+		if state == connectivity.Shutdown {
+			return
+		}
+		if onStateChange != nil {
+			onStateChange(state)
+		}
+	}
+}
+
+// State returns the current state of the connection to the host
+func (c *UnifiedServiceClient) State() connectivity.State {
+	return c.conn.GetState()
+}
+
+// MaxMessageSize returns the largest message in bytes the client sends or
+// receives, negotiated with the host
+func (c *UnifiedServiceClient) MaxMessageSize() int {
+	return c.maxMessageSize
+}
+
+// MaxAssetSize returns the largest asset in bytes SaveAsset can send, for
+// checking downloads before they are uploaded
+func (c *UnifiedServiceClient) MaxAssetSize() int {
+	return c.maxMessageSize - assetRequestOverhead
+}
+
 // AssetService returns the asset service client
 func (c *UnifiedServiceClient) AssetService() AssetServiceClient {
 	return &GRPCAssetServiceClient{
@@ -58,6 +236,21 @@ func (c *UnifiedServiceClient) AssetService() AssetServiceClient {
 	}
 }
 
+// MediaDataService returns the media data service client
+func (c *UnifiedServiceClient) MediaDataService() MediaDataServiceClient {
+	return &GRPCMediaDataServiceClient{client: pluginspb.NewMediaDataServiceClient(c.conn)}
+}
+
+// MediaEntityService returns the media entity service client
+func (c *UnifiedServiceClient) MediaEntityService() MediaEntityServiceClient {
+	return &GRPCMediaEntityServiceClient{client: pluginspb.NewMediaEntityServiceClient(c.conn)}
+}
+
+// WatchHistoryService returns the watch history service client
+func (c *UnifiedServiceClient) WatchHistoryService() WatchHistoryServiceClient {
+	return NewWatchHistoryServiceClient(c.conn)
+}
+
 // EnrichmentService returns the enrichment service client (stub implementation)
 func (c *UnifiedServiceClient) EnrichmentService() EnrichmentServiceClient {
 	// Return a stub implementation for now
@@ -66,8 +259,14 @@ func (c *UnifiedServiceClient) EnrichmentService() EnrichmentServiceClient {
 
 // Close closes the unified connection
 func (c *UnifiedServiceClient) Close() error {
-	if c.conn != nil {
-		return c.conn.Close()
-	}
-	return nil
+	var err error
+	c.closeOnce.Do(func() {
+		if c.stopWatch != nil {
+			c.stopWatch()
+		}
+		if c.conn != nil {
+			err = c.conn.Close()
+		}
+	})
+	return err
 }
@@ -0,0 +1,167 @@
+package plugins
+
+import (
+	"context"
+	"reflect"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// JSONSchemaDraft is the JSON Schema dialect produced for configuration forms
+const JSONSchemaDraft = "https://json-schema.org/draft/2020-12/schema"
+
+// ConfigurationSchemaServiceName is the gRPC service exposing a plugin's configuration schema
+const ConfigurationSchemaServiceName = "viewra.ConfigurationSchemaService"
+
+// ConfigurationSchemaMethod is the full gRPC method name for fetching the schema
+const ConfigurationSchemaMethod = "/" + ConfigurationSchemaServiceName + "/GetJSONSchema"
+
+// JSONSchemaRequest asks a plugin for its configuration schema
+type JSONSchemaRequest struct{}
+
+// JSONSchemaResponse carries a plugin's configuration schema as JSON Schema
+type JSONSchemaResponse struct {
+	Schema   map[string]interface{} `json:"schema"`
+	Examples map[string]interface{} `json:"examples,omitempty"`
+}
+
+// JSONSchema returns the schema as a standalone JSON Schema object. Schemas
+// that only list properties, like the default schema, are wrapped in an
+// object schema and their Defaults are applied to matching properties.
+func (s *ConfigurationSchema) JSONSchema() map[string]interface{} {
+	if s == nil {
+		return nil
+	}
+
+	schema := make(map[string]interface{})
+	if _, ok := s.Schema["properties"]; ok {
+		for key, value := range s.Schema {
+			schema[key] = value
+		}
+	} else {
+		schema["type"] = "object"
+		schema["properties"] = s.Schema
+	}
+	if _, ok := schema["$schema"]; !ok {
+		schema["$schema"] = JSONSchemaDraft
+	}
+
+	// Copy the properties before filling in defaults so the original schema is untouched
+	source, _ := schema["properties"].(map[string]interface{})
+	properties := make(map[string]interface{}, len(source))
+	for key, value := range source {
+		properties[key] = value
+	}
+	schema["properties"] = properties
+
+	for key, value := range s.Defaults {
+		property, ok := properties[key].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if _, hasDefault := property["default"]; hasDefault {
+			continue
+		}
+		withDefault := make(map[string]interface{}, len(property)+1)
+		for k, v := range property {
+			withDefault[k] = v
+		}
+		withDefault["default"] = value
+		properties[key] = withDefault
+	}
+
+	return schema
+}
+
+// configurationSchemaServer is the server side of the configuration schema service
+type configurationSchemaServer interface {
+	GetJSONSchema(ctx context.Context, req *JSONSchemaRequest) (*JSONSchemaResponse, error)
+}
+
+// ConfigurationSchemaServer exposes a plugin's configuration schema over gRPC.
+// The ConfigurationService is looked up per call since plugins usually create
+// it during Initialize, after the gRPC server is registered.
+type ConfigurationSchemaServer struct {
+	Impl Implementation
+}
+
+// GetJSONSchema returns the plugin's configuration schema as JSON Schema
+func (s *ConfigurationSchemaServer) GetJSONSchema(ctx context.Context, req *JSONSchemaRequest) (*JSONSchemaResponse, error) {
+	configService := s.Impl.ConfigurationService()
+	if isNilService(configService) {
+		return nil, status.Error(codes.Unimplemented, "plugin has no configuration service")
+	}
+
+	schema, err := configService.GetConfigurationSchema()
+	if err != nil {
+		return nil, err
+	}
+	if schema == nil {
+		return &JSONSchemaResponse{}, nil
+	}
+
+	return &JSONSchemaResponse{
+		Schema:   schema.JSONSchema(),
+		Examples: schema.Examples,
+	}, nil
+}
+
+func getJSONSchemaHandler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(JSONSchemaRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(configurationSchemaServer).GetJSONSchema(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ConfigurationSchemaMethod,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(configurationSchemaServer).GetJSONSchema(ctx, req.(*JSONSchemaRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// configurationSchemaServiceDesc describes the schema service, hand-written like the HTTP bridge
+var configurationSchemaServiceDesc = grpc.ServiceDesc{
+	ServiceName: ConfigurationSchemaServiceName,
+	HandlerType: (*configurationSchemaServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetJSONSchema",
+			Handler:    getJSONSchemaHandler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "config_schema.go",
+}
+
+// RegisterConfigurationSchemaServer registers the schema service for a plugin
+func RegisterConfigurationSchemaServer(s *grpc.Server, impl Implementation) {
+	s.RegisterService(&configurationSchemaServiceDesc, &ConfigurationSchemaServer{Impl: impl})
+}
+
+// isNilService reports whether a service is nil, including typed nil pointers
+// returned by plugins whose service field isn't set yet
+func isNilService(service interface{}) bool {
+	if service == nil {
+		return true
+	}
+	v := reflect.ValueOf(service)
+	return v.Kind() == reflect.Ptr && v.IsNil()
+}
+
+// GetPluginJSONSchema fetches a plugin's configuration schema over its gRPC connection.
+// Plugins without a ConfigurationService return a codes.Unimplemented status.
+func GetPluginJSONSchema(ctx context.Context, conn grpc.ClientConnInterface, opts ...grpc.CallOption) (*JSONSchemaResponse, error) {
+	resp := new(JSONSchemaResponse)
+	opts = append([]grpc.CallOption{grpc.CallContentSubtype(JSONCodec)}, opts...)
+	if err := conn.Invoke(ctx, ConfigurationSchemaMethod, &JSONSchemaRequest{}, resp, opts...); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
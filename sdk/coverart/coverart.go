@@ -0,0 +1,189 @@
+// Package coverart looks up album artwork in the Cover Art Archive, the image
+// store MusicBrainz releases link to, for music enrichment plugins.
+//
+// Many releases have no art of their own while another release in the same
+// release group does, so Lookup falls back from the release to its release
+// group. Where a release has several images, Best picks the one to use.
+package coverart
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	plugins "github.com/mantonx/viewra/sdk"
+)
+
+// DefaultBaseURL is the Cover Art Archive API
+const DefaultBaseURL = "https://coverartarchive.org"
+
+// Source says which MusicBrainz entity an image was found under
+type Source string
+
+const (
+	// SourceRelease is art uploaded for the release itself
+	SourceRelease Source = "release"
+	// SourceReleaseGroup is art the release group takes from one of its releases
+	SourceReleaseGroup Source = "release-group"
+)
+
+// Image is one image listed for a release
+type Image struct {
+	ID         json.Number       `json:"id"`
+	Image      string            `json:"image"` // Full size image URL
+	Thumbnails map[string]string `json:"thumbnails"`
+	Types      []string          `json:"types"` // e.g. Front, Back, Booklet
+	Approved   bool              `json:"approved"`
+	Front      bool              `json:"front"`
+	Back       bool              `json:"back"`
+}
+
+// Resolution is the largest thumbnail width the archive has for the image,
+// 0 when it lists none. The archive doesn't give full size dimensions, so
+// images with larger thumbnails are taken to be the larger originals.
+func (i Image) Resolution() int {
+	largest := 0
+	for size := range i.Thumbnails {
+		width, err := strconv.Atoi(size)
+		if err != nil {
+			// Legacy names for the 250 and 500 pixel thumbnails
+			switch size {
+			case "small":
+				width = 250
+			case "large":
+				width = 500
+			}
+		}
+		largest = max(largest, width)
+	}
+	return largest
+}
+
+// Best picks the image to use as a release's cover: approved images before
+// unapproved ones, then front covers, then the highest resolution. It
+// returns nil when there are no images.
+func Best(images []Image) *Image {
+	var best *Image
+	for i := range images {
+		if best == nil || better(images[i], *best) {
+			best = &images[i]
+		}
+	}
+	return best
+}
+
+// better reports whether image a ranks above b in Best's order
+func better(a, b Image) bool {
+	if a.Approved != b.Approved {
+		return a.Approved
+	}
+	if a.Front != b.Front {
+		return a.Front
+	}
+	return a.Resolution() > b.Resolution()
+}
+
+// Result is the cover Lookup found
+type Result struct {
+	Image  Image
+	Source Source
+	ID     string // MBID of the release or release group the image is listed under
+}
+
+// Client calls the Cover Art Archive
+type Client struct {
+	BaseURL   string
+	UserAgent string
+	http      *http.Client
+}
+
+// NewClient returns a client using the provider HTTP client, so requests are
+// answered from cassettes when the host runs in mock provider mode
+func NewClient(userAgent string, timeout time.Duration) *Client {
+	return &Client{
+		BaseURL:   DefaultBaseURL,
+		UserAgent: userAgent,
+		http:      plugins.NewProviderHTTPClient(timeout),
+	}
+}
+
+// Lookup finds the cover for a release, falling back to the release group's
+// when the release has no art. Either ID may be empty. It returns a
+// NotFound PluginError when neither has any.
+func (c *Client) Lookup(ctx context.Context, releaseID, releaseGroupID string) (*Result, error) {
+	for _, entity := range []struct {
+		source Source
+		id     string
+	}{{SourceRelease, releaseID}, {SourceReleaseGroup, releaseGroupID}} {
+		if entity.id == "" {
+			continue
+		}
+		images, err := c.Images(ctx, entity.source, entity.id)
+		if err != nil {
+			return nil, err
+		}
+		if best := Best(images); best != nil {
+			return &Result{Image: *best, Source: entity.source, ID: entity.id}, nil
+		}
+	}
+	return nil, plugins.NewNotFoundError("no cover art for release or release group").
+		WithMetadata("release_id", releaseID).
+		WithMetadata("release_group_id", releaseGroupID)
+}
+
+// Images lists the images for a release or release group. An entity without
+// art has no images; that isn't an error.
+func (c *Client) Images(ctx context.Context, source Source, id string) ([]Image, error) {
+	url := fmt.Sprintf("%s/%s/%s", strings.TrimSuffix(c.BaseURL, "/"), source, id)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	if c.UserAgent != "" {
+		req.Header.Set("User-Agent", c.UserAgent)
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, plugins.NewTemporaryError("Cover Art Archive request failed", err)
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return nil, nil
+	case resp.StatusCode == http.StatusTooManyRequests:
+		return nil, plugins.NewRateLimitedError("Cover Art Archive rate limit reached", retryAfter(resp))
+	case resp.StatusCode >= 500:
+		return nil, plugins.NewTemporaryError(fmt.Sprintf("Cover Art Archive returned status %d", resp.StatusCode), nil)
+	case resp.StatusCode != http.StatusOK:
+		return nil, fmt.Errorf("Cover Art Archive returned status %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+	var listing struct {
+		Images []Image `json:"images"`
+	}
+	if err := json.Unmarshal(body, &listing); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal JSON response: %w", err)
+	}
+	return listing.Images, nil
+}
+
+// retryAfter reads a Retry-After header given in seconds
+func retryAfter(resp *http.Response) time.Duration {
+	seconds, err := strconv.Atoi(resp.Header.Get("Retry-After"))
+	if err != nil || seconds < 0 {
+		return 0
+	}
+	return time.Duration(seconds) * time.Second
+}
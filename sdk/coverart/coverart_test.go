@@ -0,0 +1,111 @@
+package coverart
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	plugins "github.com/mantonx/viewra/sdk"
+	"github.com/mantonx/viewra/sdk/cassette"
+)
+
+func TestBest(t *testing.T) {
+	sized := func(id string, approved, front bool, sizes ...string) Image {
+		thumbnails := make(map[string]string, len(sizes))
+		for _, size := range sizes {
+			thumbnails[size] = id + "-" + size
+		}
+		return Image{ID: "1", Image: id, Approved: approved, Front: front, Thumbnails: thumbnails}
+	}
+
+	tests := []struct {
+		name   string
+		images []Image
+		want   string
+	}{
+		{"no images", nil, ""},
+		{"approved before front", []Image{sized("front", false, true, "1200"), sized("back", true, false, "250")}, "back"},
+		{"front before resolution", []Image{sized("booklet", true, false, "1200"), sized("front", true, true, "500")}, "front"},
+		{"highest resolution", []Image{sized("small", true, true, "250", "500"), sized("large", true, true, "250", "500", "1200")}, "large"},
+		{"legacy thumbnail names", []Image{sized("small", true, true, "small"), sized("large", true, true, "large")}, "large"},
+		{"first of equals", []Image{sized("first", true, true, "500"), sized("second", true, true, "500")}, "first"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			best := Best(tt.images)
+			got := ""
+			if best != nil {
+				got = best.Image
+			}
+			if got != tt.want {
+				t.Errorf("Best() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLookup(t *testing.T) {
+	t.Setenv(cassette.ModeEnv, "")
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/release/with-art":
+			w.Write([]byte(`{"images":[{"id":1,"image":"release-back","approved":true,"back":true},
+				{"id":2,"image":"release-front","approved":true,"front":true,"thumbnails":{"500":"t"}}]}`))
+		case "/release/empty":
+			w.Write([]byte(`{"images":[]}`))
+		case "/release-group/group":
+			w.Write([]byte(`{"images":[{"id":3,"image":"group-front","approved":true,"front":true}]}`))
+		case "/release/throttled":
+			w.Header().Set("Retry-After", "2")
+			w.WriteHeader(http.StatusTooManyRequests)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient("viewra-test", 5*time.Second)
+	client.BaseURL = server.URL
+
+	tests := []struct {
+		name           string
+		releaseID      string
+		releaseGroupID string
+		wantImage      string
+		wantSource     Source
+		wantCode       plugins.ErrorCode
+	}{
+		{"release art", "with-art", "group", "release-front", SourceRelease, ""},
+		{"release without art", "missing", "group", "group-front", SourceReleaseGroup, ""},
+		{"release with no images", "empty", "group", "group-front", SourceReleaseGroup, ""},
+		{"release group only", "", "group", "group-front", SourceReleaseGroup, ""},
+		{"no art anywhere", "missing", "missing", "", "", plugins.ErrorCodeNotFound},
+		{"rate limited", "throttled", "group", "", "", plugins.ErrorCodeRateLimited},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := client.Lookup(context.Background(), tt.releaseID, tt.releaseGroupID)
+			if tt.wantCode != "" {
+				pluginErr, ok := plugins.AsPluginError(err)
+				if !ok || pluginErr.Code != tt.wantCode {
+					t.Fatalf("Lookup() error = %v, want code %s", err, tt.wantCode)
+				}
+				if tt.wantCode == plugins.ErrorCodeRateLimited && pluginErr.RetryAfter != 2*time.Second {
+					t.Errorf("RetryAfter = %v, want 2s", pluginErr.RetryAfter)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Lookup() error = %v", err)
+			}
+			if result.Image.Image != tt.wantImage || result.Source != tt.wantSource {
+				t.Errorf("Lookup() = %s from %s, want %s from %s", result.Image.Image, result.Source, tt.wantImage, tt.wantSource)
+			}
+		})
+	}
+}
@@ -0,0 +1,168 @@
+package plugins
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/grpc"
+)
+
+// DashboardWidgetServiceName is the gRPC service exposing a plugin's dashboard widgets
+const DashboardWidgetServiceName = "viewra.DashboardWidgetService"
+
+// DashboardWidgetsMethod is the full gRPC method name for fetching widgets with their data
+const DashboardWidgetsMethod = "/" + DashboardWidgetServiceName + "/GetWidgets"
+
+// Dashboard widget types, telling the admin home screen how to draw a widget
+const (
+	WidgetTypeStat   = "stat"   // A single value, e.g. TMDb requests remaining
+	WidgetTypeGauge  = "gauge"  // A value out of Max, e.g. transcode queue depth
+	WidgetTypeList   = "list"   // A short list of labelled values
+	WidgetTypeStatus = "status" // A status with a message
+)
+
+// Widget statuses, used to color a widget
+const (
+	WidgetStatusOK      = "ok"
+	WidgetStatusWarning = "warning"
+	WidgetStatusError   = "error"
+)
+
+// DashboardWidgetService is implemented by plugins that contribute small
+// widgets to the admin home screen. Where an admin page gives a plugin a
+// whole screen, a widget is one figure the host shows next to those of
+// other plugins, such as an API quota or a queue depth.
+//
+// Implementing this interface is optional; plugins that don't are left unchanged.
+type DashboardWidgetService interface {
+	// DashboardWidgets describes the widgets the plugin offers
+	DashboardWidgets() []*DashboardWidget
+	// WidgetData returns the current data of one widget
+	WidgetData(ctx context.Context, widgetID string) (*DashboardWidgetData, error)
+}
+
+// DashboardWidget describes a widget on the admin home screen
+type DashboardWidget struct {
+	ID              string `json:"id"`
+	Title           string `json:"title"`
+	Type            string `json:"type"` // stat, gauge, list or status
+	Description     string `json:"description,omitempty"`
+	Icon            string `json:"icon,omitempty"`
+	Size            string `json:"size,omitempty"`             // small, medium or large
+	RefreshInterval int    `json:"refresh_interval,omitempty"` // Seconds between refreshes
+	Link            string `json:"link,omitempty"`             // Admin page with more detail
+}
+
+// DashboardWidgetData is a widget's current data
+type DashboardWidgetData struct {
+	Value     interface{}          `json:"value,omitempty"`
+	Unit      string               `json:"unit,omitempty"`
+	Max       *float64             `json:"max,omitempty"` // Gauge maximum
+	Status    string               `json:"status,omitempty"`
+	Message   string               `json:"message,omitempty"`
+	Items     []*DashboardListItem `json:"items,omitempty"`
+	UpdatedAt time.Time            `json:"updated_at"`
+}
+
+// DashboardListItem is one row of a list widget
+type DashboardListItem struct {
+	Label  string      `json:"label"`
+	Value  interface{} `json:"value,omitempty"`
+	Status string      `json:"status,omitempty"`
+}
+
+// DashboardWidgetState is a widget with its data, or the error fetching it
+type DashboardWidgetState struct {
+	Widget *DashboardWidget     `json:"widget"`
+	Data   *DashboardWidgetData `json:"data,omitempty"`
+	Error  string               `json:"error,omitempty"`
+}
+
+// DashboardWidgetsRequest asks a plugin for its widgets
+type DashboardWidgetsRequest struct{}
+
+// DashboardWidgetsResponse lists a plugin's widgets with their data
+type DashboardWidgetsResponse struct {
+	Widgets []*DashboardWidgetState `json:"widgets"`
+}
+
+// dashboardWidgetServer is the server side of the dashboard widget service
+type dashboardWidgetServer interface {
+	GetWidgets(ctx context.Context, req *DashboardWidgetsRequest) (*DashboardWidgetsResponse, error)
+}
+
+// DashboardWidgetServer serves the widgets of a plugin's DashboardWidgetService
+type DashboardWidgetServer struct {
+	Impl DashboardWidgetService
+}
+
+// GetWidgets returns every widget with its current data. A widget whose data
+// fails to load is returned with the error, so one widget can't hide the rest.
+func (s *DashboardWidgetServer) GetWidgets(ctx context.Context, req *DashboardWidgetsRequest) (*DashboardWidgetsResponse, error) {
+	widgets := s.Impl.DashboardWidgets()
+	resp := &DashboardWidgetsResponse{Widgets: make([]*DashboardWidgetState, 0, len(widgets))}
+	for _, widget := range widgets {
+		if widget == nil {
+			continue
+		}
+		state := &DashboardWidgetState{Widget: widget}
+		data, err := s.Impl.WidgetData(ctx, widget.ID)
+		if err != nil {
+			state.Error = err.Error()
+		} else {
+			if data != nil && data.UpdatedAt.IsZero() {
+				data.UpdatedAt = time.Now()
+			}
+			state.Data = data
+		}
+		resp.Widgets = append(resp.Widgets, state)
+	}
+	return resp, nil
+}
+
+func getWidgetsHandler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DashboardWidgetsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(dashboardWidgetServer).GetWidgets(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DashboardWidgetsMethod,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(dashboardWidgetServer).GetWidgets(ctx, req.(*DashboardWidgetsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// dashboardWidgetServiceDesc describes the widget service, hand-written like the HTTP bridge
+var dashboardWidgetServiceDesc = grpc.ServiceDesc{
+	ServiceName: DashboardWidgetServiceName,
+	HandlerType: (*dashboardWidgetServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetWidgets",
+			Handler:    getWidgetsHandler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "dashboard_widgets.go",
+}
+
+// RegisterDashboardWidgetServer registers the widget service for a plugin
+func RegisterDashboardWidgetServer(s *grpc.Server, impl DashboardWidgetService) {
+	s.RegisterService(&dashboardWidgetServiceDesc, &DashboardWidgetServer{Impl: impl})
+}
+
+// GetPluginDashboardWidgets fetches a plugin's widgets and their data over its gRPC connection
+func GetPluginDashboardWidgets(ctx context.Context, conn grpc.ClientConnInterface, opts ...grpc.CallOption) (*DashboardWidgetsResponse, error) {
+	resp := new(DashboardWidgetsResponse)
+	opts = append([]grpc.CallOption{grpc.CallContentSubtype(JSONCodec)}, opts...)
+	if err := conn.Invoke(ctx, DashboardWidgetsMethod, &DashboardWidgetsRequest{}, resp, opts...); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
@@ -0,0 +1,182 @@
+package plugins
+
+import (
+	"errors"
+	"time"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/protoadapt"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// ErrorCode classifies plugin failures so the host can decide whether to
+// retry and what to tell the user
+type ErrorCode string
+
+const (
+	// ErrorCodeRateLimited means an upstream API throttled the plugin; retry after RetryAfter
+	ErrorCodeRateLimited ErrorCode = "RATE_LIMITED"
+	// ErrorCodeNotFound means the requested item doesn't exist; retrying won't help
+	ErrorCodeNotFound ErrorCode = "NOT_FOUND"
+	// ErrorCodeAuthFailed means credentials such as an API key are missing or rejected
+	ErrorCodeAuthFailed ErrorCode = "AUTH_FAILED"
+	// ErrorCodeTemporary is a transient failure (network, timeout) that may succeed on retry
+	ErrorCodeTemporary ErrorCode = "TEMPORARY"
+	// ErrorCodeInvalidArgument means the request itself was malformed
+	ErrorCodeInvalidArgument ErrorCode = "INVALID_ARGUMENT"
+	// ErrorCodeInternal is any other plugin failure
+	ErrorCodeInternal ErrorCode = "INTERNAL"
+)
+
+// ErrorDomain identifies plugin error details in gRPC statuses
+const ErrorDomain = "plugins.viewra"
+
+// grpcCodes maps plugin error codes to the closest gRPC status code
+var grpcCodes = map[ErrorCode]codes.Code{
+	ErrorCodeRateLimited:     codes.ResourceExhausted,
+	ErrorCodeNotFound:        codes.NotFound,
+	ErrorCodeAuthFailed:      codes.Unauthenticated,
+	ErrorCodeTemporary:       codes.Unavailable,
+	ErrorCodeInvalidArgument: codes.InvalidArgument,
+	ErrorCodeInternal:        codes.Internal,
+}
+
+// PluginError is a typed plugin failure. Returning one (or an error wrapping
+// one) from any service method sends the code, retry delay and metadata to
+// the host as gRPC status details instead of an opaque string.
+type PluginError struct {
+	Code       ErrorCode
+	Message    string
+	RetryAfter time.Duration
+	Metadata   map[string]string
+	Err        error
+}
+
+// NewRateLimitedError reports that an upstream API throttled the plugin
+func NewRateLimitedError(message string, retryAfter time.Duration) *PluginError {
+	return &PluginError{Code: ErrorCodeRateLimited, Message: message, RetryAfter: retryAfter}
+}
+
+// NewNotFoundError reports that the requested item doesn't exist
+func NewNotFoundError(message string) *PluginError {
+	return &PluginError{Code: ErrorCodeNotFound, Message: message}
+}
+
+// NewAuthFailedError reports missing or rejected credentials
+func NewAuthFailedError(message string) *PluginError {
+	return &PluginError{Code: ErrorCodeAuthFailed, Message: message}
+}
+
+// NewTemporaryError reports a transient failure caused by err
+func NewTemporaryError(message string, err error) *PluginError {
+	return &PluginError{Code: ErrorCodeTemporary, Message: message, Err: err}
+}
+
+// WithMetadata attaches a key/value pair, such as the provider or item ID
+func (e *PluginError) WithMetadata(key, value string) *PluginError {
+	if e.Metadata == nil {
+		e.Metadata = make(map[string]string)
+	}
+	e.Metadata[key] = value
+	return e
+}
+
+func (e *PluginError) Error() string {
+	if e.Err != nil {
+		return e.Message + ": " + e.Err.Error()
+	}
+	return e.Message
+}
+
+func (e *PluginError) Unwrap() error {
+	return e.Err
+}
+
+// Retryable reports whether the same request may succeed later
+func (e *PluginError) Retryable() bool {
+	return e.Code == ErrorCodeRateLimited || e.Code == ErrorCodeTemporary
+}
+
+// GRPCStatus converts the error to a gRPC status carrying ErrorInfo and,
+// when set, RetryInfo details. gRPC calls this when the error crosses the boundary.
+func (e *PluginError) GRPCStatus() *status.Status {
+	code, ok := grpcCodes[e.Code]
+	if !ok {
+		code = codes.Unknown
+	}
+
+	st := status.New(code, e.Error())
+	details := []protoadapt.MessageV1{&errdetails.ErrorInfo{
+		Reason:   string(e.Code),
+		Domain:   ErrorDomain,
+		Metadata: e.Metadata,
+	}}
+	if e.RetryAfter > 0 {
+		details = append(details, &errdetails.RetryInfo{RetryDelay: durationpb.New(e.RetryAfter)})
+	}
+
+	withDetails, err := st.WithDetails(details...)
+	if err != nil {
+		return st
+	}
+	return withDetails
+}
+
+// AsPluginError extracts a typed plugin error from err, whether it came from
+// the same process or across gRPC. Statuses without plugin details are
+// classified by their gRPC code; errors with no useful code return false.
+func AsPluginError(err error) (*PluginError, bool) {
+	if err == nil {
+		return nil, false
+	}
+
+	var pluginErr *PluginError
+	if errors.As(err, &pluginErr) {
+		return pluginErr, true
+	}
+
+	st, ok := status.FromError(err)
+	if !ok {
+		return nil, false
+	}
+
+	result := &PluginError{Message: st.Message()}
+	for _, detail := range st.Details() {
+		switch d := detail.(type) {
+		case *errdetails.ErrorInfo:
+			if d.GetDomain() == ErrorDomain {
+				result.Code = ErrorCode(d.GetReason())
+				result.Metadata = d.GetMetadata()
+			}
+		case *errdetails.RetryInfo:
+			result.RetryAfter = d.GetRetryDelay().AsDuration()
+		}
+	}
+	if result.Code != "" {
+		return result, true
+	}
+
+	switch st.Code() {
+	case codes.ResourceExhausted:
+		result.Code = ErrorCodeRateLimited
+	case codes.NotFound:
+		result.Code = ErrorCodeNotFound
+	case codes.Unauthenticated, codes.PermissionDenied:
+		result.Code = ErrorCodeAuthFailed
+	case codes.Unavailable, codes.DeadlineExceeded, codes.Aborted:
+		result.Code = ErrorCodeTemporary
+	case codes.InvalidArgument:
+		result.Code = ErrorCodeInvalidArgument
+	default:
+		return nil, false
+	}
+	return result, true
+}
+
+// IsRetryableError reports whether err is a plugin error worth retrying
+func IsRetryableError(err error) bool {
+	pluginErr, ok := AsPluginError(err)
+	return ok && pluginErr.Retryable()
+}
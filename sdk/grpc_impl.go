@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"strconv"
 	"time"
 
 	"github.com/hashicorp/go-hclog"
@@ -12,6 +13,8 @@ import (
 	"github.com/mantonx/viewra/sdk/proto"
 	"github.com/mantonx/viewra/sdk/transcoding/types"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 // HCLogAdapter adapts hclog.Logger to our Logger interface
@@ -135,7 +138,7 @@ func convertSearchResultToProto(results []*SearchResult) []*proto.SearchResult {
 			// Extract artist and album from metadata if available
 			artist := ""
 			album := ""
-			score := 0.0
+			score := searchResult.Score
 			if searchResult.Metadata != nil {
 				if a, ok := searchResult.Metadata["artist"]; ok {
 					artist = a
@@ -217,6 +220,31 @@ func (p *GRPCPlugin) GRPCServer(broker *goplugin.GRPCBroker, s *grpc.Server) err
 		proto.RegisterTranscodingProviderServiceServer(s, server)
 	}
 
+	// Expose the configuration schema so the host can render settings forms.
+	// Plugins without a ConfigurationService answer Unimplemented.
+	RegisterConfigurationSchemaServer(s, p.Impl)
+
+	// Expose the performance snapshot, with external provider latency, for
+	// the admin dashboard. Plugins without a PerformanceMonitorService
+	// answer Unimplemented.
+	RegisterPerformanceMonitorServer(s, p.Impl)
+
+	// Register the HTTP bridge used for plugin admin pages and API routes
+	RegisterHTTPBridgeServer(s, p.Impl)
+
+	// Register the dashboard widgets shown on the admin home screen if implemented
+	if widgetService, ok := p.Impl.(DashboardWidgetService); ok {
+		RegisterDashboardWidgetServer(s, widgetService)
+	}
+
+	// Register the playback hooks, through which the host reports what users watch, if implemented
+	if playbackHooks, ok := p.Impl.(PlaybackHookService); ok {
+		RegisterPlaybackHookServer(s, playbackHooks)
+	}
+
+	// Answer the host's version handshake with the SDK version and the services above
+	RegisterABIServer(s)
+
 	return nil
 }
 
@@ -333,9 +361,7 @@ func (s *MetadataScraperServer) CanHandle(ctx context.Context, req *proto.CanHan
 func (s *MetadataScraperServer) ExtractMetadata(ctx context.Context, req *proto.ExtractMetadataRequest) (*proto.ExtractMetadataResponse, error) {
 	metadata, err := s.Impl.ExtractMetadata(req.FilePath)
 	if err != nil {
-		return &proto.ExtractMetadataResponse{
-			Error: err.Error(),
-		}, nil
+		return &proto.ExtractMetadataResponse{}, err
 	}
 	return &proto.ExtractMetadataResponse{Metadata: metadata}, nil
 }
@@ -354,10 +380,53 @@ type ScannerHookServer struct {
 func (s *ScannerHookServer) OnMediaFileScanned(ctx context.Context, req *proto.OnMediaFileScannedRequest) (*proto.OnMediaFileScannedResponse, error) {
 	// Pass the UUID string directly to the plugin implementation
 	err := s.Impl.OnMediaFileScanned(req.MediaFileId, req.FilePath, req.Metadata)
+	if skipErr, ok := AsSkipError(err); ok {
+		return &proto.OnMediaFileScannedResponse{
+			Status: string(HookStatusSkipped),
+			Reason: skipErr.Reason,
+			Detail: skipErr.Detail,
+		}, nil
+	}
 	if err != nil {
 		return &proto.OnMediaFileScannedResponse{}, err
 	}
-	return &proto.OnMediaFileScannedResponse{}, nil
+	return &proto.OnMediaFileScannedResponse{Status: string(HookStatusProcessed)}, nil
+}
+
+func (s *ScannerHookServer) OnMediaFilesScanned(ctx context.Context, req *proto.OnMediaFilesScannedRequest) (*proto.OnMediaFilesScannedResponse, error) {
+	files := make([]ScannedMediaFile, len(req.Files))
+	for i, file := range req.Files {
+		files[i] = ScannedMediaFile{MediaFileID: file.MediaFileId, FilePath: file.FilePath, Metadata: file.Metadata}
+	}
+
+	var errs []error
+	if batchHook, ok := s.Impl.(BatchScannerHookService); ok {
+		errs = batchHook.OnMediaFilesScanned(files)
+	} else {
+		errs = make([]error, len(files))
+		for i, file := range files {
+			errs[i] = s.Impl.OnMediaFileScanned(file.MediaFileID, file.FilePath, file.Metadata)
+		}
+	}
+
+	resp := &proto.OnMediaFilesScannedResponse{Results: make([]*proto.MediaFileHookResult, len(files))}
+	for i, file := range files {
+		result := &proto.MediaFileHookResult{MediaFileId: file.MediaFileID, Status: string(HookStatusProcessed)}
+		var err error
+		if i < len(errs) {
+			err = errs[i]
+		}
+		if skipErr, ok := AsSkipError(err); ok {
+			result.Status = string(HookStatusSkipped)
+			result.Reason = skipErr.Reason
+			result.Detail = skipErr.Detail
+		} else if err != nil {
+			result.Status = string(HookStatusFailed)
+			result.Detail = err.Error()
+		}
+		resp.Results[i] = result
+	}
+	return resp, nil
 }
 
 func (s *ScannerHookServer) OnScanStarted(ctx context.Context, req *proto.OnScanStartedRequest) (*proto.OnScanStartedResponse, error) {
@@ -376,6 +445,36 @@ func (s *ScannerHookServer) OnScanCompleted(ctx context.Context, req *proto.OnSc
 	return &proto.OnScanCompletedResponse{}, nil
 }
 
+func (s *ScannerHookServer) OnMediaFileRemoved(ctx context.Context, req *proto.OnMediaFileRemovedRequest) (*proto.OnMediaFileRemovedResponse, error) {
+	changeHook, ok := s.Impl.(MediaFileChangeHookService)
+	if !ok {
+		return nil, status.Error(codes.Unimplemented, "plugin does not handle removed media files")
+	}
+	if err := changeHook.OnMediaFileRemoved(req.MediaFileId, req.FilePath, req.Metadata); err != nil {
+		return &proto.OnMediaFileRemovedResponse{}, err
+	}
+	return &proto.OnMediaFileRemovedResponse{}, nil
+}
+
+func (s *ScannerHookServer) OnMediaFileUpdated(ctx context.Context, req *proto.OnMediaFileUpdatedRequest) (*proto.OnMediaFileUpdatedResponse, error) {
+	changeHook, ok := s.Impl.(MediaFileChangeHookService)
+	if !ok {
+		return nil, status.Error(codes.Unimplemented, "plugin does not handle updated media files")
+	}
+	err := changeHook.OnMediaFileUpdated(req.MediaFileId, req.FilePath, req.Metadata)
+	if skipErr, ok := AsSkipError(err); ok {
+		return &proto.OnMediaFileUpdatedResponse{
+			Status: string(HookStatusSkipped),
+			Reason: skipErr.Reason,
+			Detail: skipErr.Detail,
+		}, nil
+	}
+	if err != nil {
+		return &proto.OnMediaFileUpdatedResponse{}, err
+	}
+	return &proto.OnMediaFileUpdatedResponse{Status: string(HookStatusProcessed)}, nil
+}
+
 // DatabaseServer implements the database service
 type DatabaseServer struct {
 	proto.UnimplementedDatabaseServiceServer
@@ -468,10 +567,7 @@ type SearchServer struct {
 func (s *SearchServer) Search(ctx context.Context, req *proto.SearchRequest) (*proto.SearchResponse, error) {
 	results, totalCount, hasMore, err := s.Impl.Search(ctx, req.Query, req.Limit, req.Offset)
 	if err != nil {
-		return &proto.SearchResponse{
-			Success: false,
-			Error:   err.Error(),
-		}, nil
+		return &proto.SearchResponse{}, err
 	}
 
 	return &proto.SearchResponse{
@@ -510,12 +606,19 @@ type TranscodingProviderServer struct {
 // GetProviderInfo returns provider information
 func (s *TranscodingProviderServer) GetProviderInfo(ctx context.Context, req *proto.GetProviderInfoRequest) (*proto.GetProviderInfoResponse, error) {
 	info := s.Impl.GetInfo()
+
+	// Capabilities are a flat list in the SDK; the proto carries them as a set
+	capabilities := make(map[string]string, len(info.Capabilities))
+	for _, capability := range info.Capabilities {
+		capabilities[capability] = "true"
+	}
+
 	return &proto.GetProviderInfoResponse{
 		Info: &proto.ProviderInfo{
 			Name:         info.Name,
 			Description:  info.Description,
 			Priority:     int32(info.Priority),
-			Capabilities: map[string]string{}, // Empty map since SDK doesn't have this field
+			Capabilities: capabilities,
 		},
 	}, nil
 }
@@ -577,7 +680,10 @@ func (s *TranscodingProviderServer) StartTranscode(ctx context.Context, req *pro
 		Container:      req.Request.Container,
 		VideoCodec:     req.Request.VideoCodec,
 		AudioCodec:     req.Request.AudioCodec,
+		AudioBitrate:   int(req.Request.AudioBitrateKbps),
 		Seek:           time.Duration(req.Request.SeekNs), // Convert nanoseconds to time.Duration
+		PreferHardware: req.Request.PreferHardware,
+		HardwareType:   types.ParseHardwareType(req.Request.HardwareType),
 	}
 	
 	// Check ExtraOptions for enable_abr flag (temporary workaround)
@@ -585,6 +691,33 @@ func (s *TranscodingProviderServer) StartTranscode(ctx context.Context, req *pro
 		if abrStr, ok := req.Request.ExtraOptions["enable_abr"]; ok {
 			transcodeReq.EnableABR = abrStr == "true"
 		}
+		if audioOnlyStr, ok := req.Request.ExtraOptions["audio_only"]; ok {
+			transcodeReq.AudioOnly = audioOnlyStr == "true"
+		}
+		if audioStream, ok := req.Request.ExtraOptions["audio_stream"]; ok {
+			if index, err := strconv.Atoi(audioStream); err == nil && index >= 0 {
+				transcodeReq.AudioStreamIndex = index
+			}
+		}
+		if tenBitStr, ok := req.Request.ExtraOptions["ten_bit"]; ok {
+			transcodeReq.TenBit = tenBitStr == "true"
+		}
+		if gaplessStr, ok := req.Request.ExtraOptions["gapless"]; ok {
+			transcodeReq.Gapless = gaplessStr == "true"
+		}
+		if fastStartStr, ok := req.Request.ExtraOptions["fast_start"]; ok {
+			transcodeReq.FastStart = fastStartStr == "true"
+		}
+		if deinterlace, ok := req.Request.ExtraOptions["deinterlace"]; ok {
+			transcodeReq.Deinterlace = types.DeinterlaceMode(deinterlace)
+		}
+		if loudnessJSON, ok := req.Request.ExtraOptions["loudness"]; ok && loudnessJSON != "" {
+			var loudness types.LoudnessSettings
+			if err := json.Unmarshal([]byte(loudnessJSON), &loudness); err == nil {
+				transcodeReq.Loudness = &loudness
+			}
+		}
+		parseSubtitleOptions(&transcodeReq, req.Request.ExtraOptions)
 	}
 
 	// Handle resolution if provided
@@ -653,9 +786,9 @@ func (s *TranscodingProviderServer) GetProgress(ctx context.Context, req *proto.
 			CurrentSpeed:    progress.CurrentSpeed,
 			BytesRead:       progress.BytesRead,
 			BytesWritten:    progress.BytesWritten,
-			CurrentBitrate:  0,  // SDK doesn't have this field
-			CurrentFrame:    0,  // SDK doesn't have this field
-			CurrentFps:      0,  // SDK doesn't have this field
+			CurrentBitrate:  progress.CurrentBitrate,
+			CurrentFrame:    int32(progress.CurrentFrame),
+			CurrentFps:      progress.CurrentFPS,
 			StatusMessage:   "", // SDK doesn't have this field
 		},
 	}, nil
@@ -692,8 +825,13 @@ func (s *TranscodingProviderServer) StartStream(ctx context.Context, req *proto.
 		Container:      req.Request.Container,
 		VideoCodec:     req.Request.VideoCodec,
 		AudioCodec:     req.Request.AudioCodec,
+		AudioBitrate:   int(req.Request.AudioBitrateKbps),
 		Seek:           time.Duration(req.Request.SeekNs), // Convert nanoseconds to time.Duration
+		PreferHardware: req.Request.PreferHardware,
+		HardwareType:   types.ParseHardwareType(req.Request.HardwareType),
+		AudioOnly:      req.Request.ExtraOptions["audio_only"] == "true",
 	}
+	parseSubtitleOptions(&transcodeReq, req.Request.ExtraOptions)
 
 	// Handle resolution if provided
 	if req.Request.Resolution != "" {
@@ -885,6 +1023,17 @@ func getIntFromString(value string) int32 {
 	return 0
 }
 
+// parseSubtitleOptions reads the subtitle burn-in or passthrough settings,
+// which have no proto fields, from a request's extra options
+func parseSubtitleOptions(transcodeReq *TranscodeRequest, options map[string]string) {
+	transcodeReq.SubtitleMode = types.SubtitleMode(options["subtitle_mode"])
+	if track, err := strconv.Atoi(options["subtitle_stream"]); err == nil && track >= 0 {
+		transcodeReq.SubtitleTrackIndex = track
+	}
+	transcodeReq.SubtitleCodec = options["subtitle_codec"]
+	transcodeReq.SubtitlePath = options["subtitle_path"]
+}
+
 // parseSpeedPriority parses a string speed priority value
 func parseSpeedPriority(priority string) SpeedPriority {
 	switch priority {
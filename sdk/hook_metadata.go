@@ -0,0 +1,49 @@
+package plugins
+
+// Keys the host adds to the metadata passed to OnMediaFileScanned, so
+// plugins can route files without querying core tables. Keys are omitted
+// when the host doesn't know the value.
+const (
+	// HookMetadataMediaType is the file's media type: movie, episode, track, image or home_video
+	HookMetadataMediaType = "media_type"
+	// HookMetadataLibraryID is the ID of the library the file belongs to
+	HookMetadataLibraryID = "library_id"
+	// HookMetadataLibraryType is the library's type: movie, tv, music, mixed or home
+	HookMetadataLibraryType = "library_type"
+	// HookMetadataLibraryPath is the root path of the library
+	HookMetadataLibraryPath = "library_path"
+	// HookMetadataContainer is the file's container format, e.g. mkv, mp4, flac
+	HookMetadataContainer = "container"
+	// HookMetadataDuration is the file's duration in whole seconds
+	HookMetadataDuration = "duration"
+	// HookMetadataSizeBytes is the file's size in bytes
+	HookMetadataSizeBytes = "size_bytes"
+	// HookMetadataMinConfidence is set when the plugin is one of a provider
+	// chain: the lowest match confidence, 0 to 1, that keeps the file from
+	// the next provider. Plugins whose best match scores lower skip the
+	// file with SkipReasonLowConfidence instead of saving it.
+	HookMetadataMinConfidence = "min_confidence"
+	// HookMetadataMatchPath is the file's path with its library's naming
+	// rules applied, set when they changed it. Plugins read titles and
+	// episode numbers from it instead of the real path; use MatchPath.
+	HookMetadataMatchPath = "match_path"
+
+	// HookMetadataIdentifyID is set on OnMediaFileUpdated when a user
+	// identified the file by hand: the ID of the search result they picked.
+	// Plugins match the file to it instead of searching, replacing what
+	// they stored for the file before.
+	HookMetadataIdentifyID = "identify_id"
+	// HookMetadataIdentifyType is the picked result's media_type search
+	// metadata, e.g. movie or tv, when it had one
+	HookMetadataIdentifyType = "identify_type"
+)
+
+// MatchPath returns the path a scanned file's title and numbering should be
+// read from: the path rewritten by its library's naming rules when the host
+// sent one, otherwise the file's own path
+func MatchPath(filePath string, metadata map[string]string) string {
+	if matchPath := metadata[HookMetadataMatchPath]; matchPath != "" {
+		return matchPath
+	}
+	return filePath
+}
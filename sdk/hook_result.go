@@ -0,0 +1,117 @@
+package plugins
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// HookStatus is the outcome of a scanner hook for one media file
+type HookStatus string
+
+const (
+	// HookStatusProcessed means the plugin handled the file
+	HookStatusProcessed HookStatus = "processed"
+	// HookStatusSkipped means the plugin deliberately left the file alone
+	HookStatusSkipped HookStatus = "skipped"
+	// HookStatusFailed means the plugin tried and returned an error
+	HookStatusFailed HookStatus = "failed"
+)
+
+// Common skip reasons. Plugins may report others; these are the ones the
+// host groups in its unmatched-items report.
+const (
+	// SkipReasonDisabled means the plugin or the feature handling the file is turned off
+	SkipReasonDisabled = "disabled"
+	// SkipReasonUnsupportedType means the plugin doesn't handle the file's media or library type
+	SkipReasonUnsupportedType = "unsupported_type"
+	// SkipReasonMissingMetadata means the file lacks what a lookup needs, such as a title
+	SkipReasonMissingMetadata = "missing_metadata"
+	// SkipReasonNoMatch means the lookup ran but nothing upstream matched the file
+	SkipReasonNoMatch = "no_match"
+	// SkipReasonAlreadyProcessed means the file was handled before and didn't need redoing
+	SkipReasonAlreadyProcessed = "already_processed"
+	// SkipReasonLowConfidence means the best match scored below HookMetadataMinConfidence
+	SkipReasonLowConfidence = "low_confidence"
+	// SkipReasonNeedsReview means the best match wasn't confident enough to
+	// apply and is queued for a user to confirm, see NeedsReview
+	SkipReasonNeedsReview = "needs_review"
+)
+
+// ScannedMediaFile is one file of a batched scanner hook
+type ScannedMediaFile struct {
+	MediaFileID string
+	FilePath    string
+	Metadata    map[string]string
+}
+
+// SkipError reports that OnMediaFileScanned deliberately left a file alone.
+// Returning one instead of nil lets the host record why the file wasn't
+// enriched; it is sent as a skipped result, not as a failure.
+type SkipError struct {
+	Reason string
+	Detail string
+}
+
+// SkipHook reports that a scanner hook skipped the file for reason, with an
+// optional human-readable detail
+func SkipHook(reason, detail string) *SkipError {
+	return &SkipError{Reason: reason, Detail: detail}
+}
+
+func (e *SkipError) Error() string {
+	if e.Detail != "" {
+		return "skipped (" + e.Reason + "): " + e.Detail
+	}
+	return "skipped (" + e.Reason + ")"
+}
+
+// AsSkipError extracts a skip result from err
+func AsSkipError(err error) (*SkipError, bool) {
+	var skipErr *SkipError
+	if errors.As(err, &skipErr) {
+		return skipErr, true
+	}
+	return nil, false
+}
+
+// ReviewCandidate is a match a plugin found for a file but scored too low to
+// apply on its own. The host queues it for a user to confirm through
+// identify, which takes ID and MediaType like a picked search result.
+type ReviewCandidate struct {
+	ID        string  `json:"id"`
+	MediaType string  `json:"media_type,omitempty"` // e.g. movie or tv
+	Title     string  `json:"title"`
+	Year      int     `json:"year,omitempty"`
+	Score     float64 `json:"score"` // The plugin's match score, 0 to 1
+}
+
+// NeedsReview reports that a scanner hook left a file for a user to confirm
+// candidate as its match. The candidate travels as the skip's detail.
+func NeedsReview(candidate ReviewCandidate) *SkipError {
+	detail, err := json.Marshal(candidate)
+	if err != nil {
+		return SkipHook(SkipReasonNeedsReview, candidate.Title)
+	}
+	return SkipHook(SkipReasonNeedsReview, string(detail))
+}
+
+// AsReviewCandidate extracts the candidate of a NeedsReview skip
+func AsReviewCandidate(skipErr *SkipError) (*ReviewCandidate, bool) {
+	if skipErr == nil || skipErr.Reason != SkipReasonNeedsReview {
+		return nil, false
+	}
+	var candidate ReviewCandidate
+	if err := json.Unmarshal([]byte(skipErr.Detail), &candidate); err != nil || candidate.ID == "" {
+		return nil, false
+	}
+	return &candidate, true
+}
+
+// String describes the candidate for logs and the unmatched-items report
+func (c ReviewCandidate) String() string {
+	if c.Year > 0 {
+		return fmt.Sprintf("%q (%d) scored %.2f", c.Title, c.Year, c.Score)
+	}
+	return fmt.Sprintf("%q scored %.2f", c.Title, c.Score)
+}
@@ -0,0 +1,242 @@
+package plugins
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/encoding"
+	"google.golang.org/grpc/status"
+)
+
+// JSONCodec is the gRPC content subtype used by the hand-written services
+// (the HTTP bridge and configuration schema). Their messages are plain Go
+// structs, so they travel as JSON instead of protobuf.
+const JSONCodec = "viewra-json"
+
+// HTTPBridgeServiceName is the gRPC service that carries proxied HTTP requests
+const HTTPBridgeServiceName = "viewra.HTTPBridgeService"
+
+// HTTPBridgeForwardMethod is the full gRPC method name used to forward a request
+const HTTPBridgeForwardMethod = "/" + HTTPBridgeServiceName + "/Forward"
+
+// HTTPBridgeRoutesMethod is the full gRPC method name for listing a plugin's API
+// routes. Unlike APIRegistrationService it carries each route's Public flag.
+const HTTPBridgeRoutesMethod = "/" + HTTPBridgeServiceName + "/Routes"
+
+// HTTPHandlerService is implemented by plugins that serve HTTP content. The
+// host forwards requests for /admin/plugins/<id>/* and for the API routes a
+// plugin registers under /api/plugins/<id>/* to the handler with the prefix
+// stripped, so a plugin serving "/config" answers the page registered at
+// /admin/plugins/<id>/config. The original prefix is passed in the
+// X-Forwarded-Prefix header for building links.
+//
+// Implementing this interface is optional; plugins that don't are left unchanged.
+type HTTPHandlerService interface {
+	HTTPHandler() http.Handler
+}
+
+// BridgeRequest is an HTTP request forwarded from the host to a plugin
+type BridgeRequest struct {
+	Method     string      `json:"method"`
+	Path       string      `json:"path"`
+	RawQuery   string      `json:"raw_query,omitempty"`
+	Header     http.Header `json:"header,omitempty"`
+	Body       []byte      `json:"body,omitempty"`
+	RemoteAddr string      `json:"remote_addr,omitempty"`
+}
+
+// BridgeResponse is the plugin's reply to a forwarded request
+type BridgeResponse struct {
+	Status int         `json:"status"`
+	Header http.Header `json:"header,omitempty"`
+	Body   []byte      `json:"body,omitempty"`
+}
+
+func init() {
+	encoding.RegisterCodec(jsonCodec{})
+}
+
+// jsonCodec marshals hand-written service messages as JSON
+type jsonCodec struct{}
+
+func (jsonCodec) Marshal(v interface{}) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+func (jsonCodec) Unmarshal(data []byte, v interface{}) error {
+	return json.Unmarshal(data, v)
+}
+
+func (jsonCodec) Name() string {
+	return JSONCodec
+}
+
+// BridgeRoutesRequest asks a plugin for its API routes
+type BridgeRoutesRequest struct{}
+
+// BridgeRoutesResponse lists the API routes a plugin serves through the bridge
+type BridgeRoutesResponse struct {
+	Routes []*APIRoute `json:"routes"`
+}
+
+// httpBridgeServer is the server side of the HTTP bridge service
+type httpBridgeServer interface {
+	Forward(ctx context.Context, req *BridgeRequest) (*BridgeResponse, error)
+	Routes(ctx context.Context, req *BridgeRoutesRequest) (*BridgeRoutesResponse, error)
+}
+
+// HTTPBridgeServer runs forwarded requests against a plugin's HTTP handler.
+// The handler is looked up per request so plugins can build it in Initialize.
+type HTTPBridgeServer struct {
+	Impl Implementation
+}
+
+// Forward replays the request against the handler and captures the response
+func (s *HTTPBridgeServer) Forward(ctx context.Context, req *BridgeRequest) (*BridgeResponse, error) {
+	target := req.Path
+	if target == "" {
+		target = "/"
+	}
+	if req.RawQuery != "" {
+		target += "?" + req.RawQuery
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, req.Method, target, bytes.NewReader(req.Body))
+	if err != nil {
+		return nil, fmt.Errorf("invalid bridged request: %w", err)
+	}
+	if req.Header != nil {
+		httpReq.Header = req.Header.Clone()
+	}
+	httpReq.RemoteAddr = req.RemoteAddr
+	httpReq.ContentLength = int64(len(req.Body))
+
+	var handler http.Handler
+	if httpService, ok := s.Impl.(HTTPHandlerService); ok {
+		handler = httpService.HTTPHandler()
+	}
+	if handler == nil {
+		return nil, status.Error(codes.Unimplemented, "plugin has no HTTP handler")
+	}
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httpReq)
+
+	result := recorder.Result()
+	defer result.Body.Close()
+
+	body, err := io.ReadAll(result.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read handler response: %w", err)
+	}
+
+	return &BridgeResponse{
+		Status: result.StatusCode,
+		Header: result.Header,
+		Body:   body,
+	}, nil
+}
+
+// Routes returns the routes declared by the plugin's APIRegistrationService
+func (s *HTTPBridgeServer) Routes(ctx context.Context, req *BridgeRoutesRequest) (*BridgeRoutesResponse, error) {
+	apiService := s.Impl.APIRegistrationService()
+	if isNilService(apiService) {
+		return &BridgeRoutesResponse{Routes: []*APIRoute{}}, nil
+	}
+
+	routes, err := apiService.GetRegisteredRoutes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &BridgeRoutesResponse{Routes: routes}, nil
+}
+
+func forwardHandler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BridgeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(httpBridgeServer).Forward(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: HTTPBridgeForwardMethod,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(httpBridgeServer).Forward(ctx, req.(*BridgeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func routesHandler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BridgeRoutesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(httpBridgeServer).Routes(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: HTTPBridgeRoutesMethod,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(httpBridgeServer).Routes(ctx, req.(*BridgeRoutesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// httpBridgeServiceDesc describes the bridge service. It is written by hand
+// rather than generated so the messages can stay plain structs.
+var httpBridgeServiceDesc = grpc.ServiceDesc{
+	ServiceName: HTTPBridgeServiceName,
+	HandlerType: (*httpBridgeServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Forward",
+			Handler:    forwardHandler,
+		},
+		{
+			MethodName: "Routes",
+			Handler:    routesHandler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "http_bridge.go",
+}
+
+// RegisterHTTPBridgeServer registers the bridge service for a plugin. Plugins
+// that don't implement HTTPHandlerService answer forwarded requests with
+// codes.Unimplemented.
+func RegisterHTTPBridgeServer(s *grpc.Server, impl Implementation) {
+	s.RegisterService(&httpBridgeServiceDesc, &HTTPBridgeServer{Impl: impl})
+}
+
+// ForwardHTTP sends a request to a plugin's HTTP handler over its gRPC connection.
+// Plugins without a handler return a codes.Unimplemented status.
+func ForwardHTTP(ctx context.Context, conn grpc.ClientConnInterface, req *BridgeRequest, opts ...grpc.CallOption) (*BridgeResponse, error) {
+	resp := new(BridgeResponse)
+	opts = append([]grpc.CallOption{grpc.CallContentSubtype(JSONCodec)}, opts...)
+	if err := conn.Invoke(ctx, HTTPBridgeForwardMethod, req, resp, opts...); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// GetBridgeRoutes lists a plugin's API routes, including their Public flag
+func GetBridgeRoutes(ctx context.Context, conn grpc.ClientConnInterface, opts ...grpc.CallOption) ([]*APIRoute, error) {
+	resp := new(BridgeRoutesResponse)
+	opts = append([]grpc.CallOption{grpc.CallContentSubtype(JSONCodec)}, opts...)
+	if err := conn.Invoke(ctx, HTTPBridgeRoutesMethod, &BridgeRoutesRequest{}, resp, opts...); err != nil {
+		return nil, err
+	}
+	return resp.Routes, nil
+}
@@ -57,6 +57,32 @@ type ScannerHookService interface {
 	OnScanCompleted(scanJobID, libraryID uint32, stats map[string]string) error
 }
 
+// MediaFileChangeHookService is implemented by scanner hook plugins that keep
+// per-file data, so they can drop it when a file is deleted and refresh it
+// when a file is replaced or retagged. OnMediaFileRemoved is called before
+// the host deletes the file. OnMediaFileUpdated may return a SkipError like
+// OnMediaFileScanned.
+//
+// Implementing this interface is optional; the host checks for it on the
+// value returned by ScannerHookService.
+type MediaFileChangeHookService interface {
+	OnMediaFileRemoved(mediaFileID string, filePath string, metadata map[string]string) error
+	OnMediaFileUpdated(mediaFileID string, filePath string, metadata map[string]string) error
+}
+
+// BatchScannerHookService is implemented by scanner hook plugins that handle
+// scanned files in batches, such as to look several up in one API request.
+// It returns one error per file, in order, meaning what OnMediaFileScanned's
+// return value does.
+//
+// The host only sends batches to plugins whose manifest sets the
+// batch_scanner_hooks capability. Implementing this interface is optional;
+// without it the SDK passes each file of a batch to OnMediaFileScanned, which
+// still saves a round-trip per file.
+type BatchScannerHookService interface {
+	OnMediaFilesScanned(files []ScannedMediaFile) []error
+}
+
 type AssetService interface {
 	SaveAsset(mediaFileID string, assetType, category, subtype string, data []byte, mimeType, sourceURL, pluginID string, metadata map[string]string) (uint32, string, string, error)
 	AssetExists(mediaFileID string, assetType, category, subtype, hash string) (bool, uint32, string, error)
@@ -97,12 +123,43 @@ type AssetServiceClient interface {
 	SaveAsset(ctx context.Context, req *SaveAssetRequest) (*SaveAssetResponse, error)
 	AssetExists(ctx context.Context, req *AssetExistsRequest) (*AssetExistsResponse, error)
 	RemoveAsset(ctx context.Context, req *RemoveAssetRequest) (*RemoveAssetResponse, error)
+	// DownloadAsset has the host download and save an asset. The host caps
+	// concurrent downloads across all plugins, spaces out requests to each
+	// provider and retries failed ones, so plugins needn't.
+	DownloadAsset(ctx context.Context, req *DownloadAssetRequest) (*DownloadAssetResponse, error)
 }
 
 type EnrichmentServiceClient interface {
 	RegisterEnrichment(ctx context.Context, req *RegisterEnrichmentRequest) (*RegisterEnrichmentResponse, error)
 }
 
+// MediaDataServiceClient reads core library data from the host, so plugins
+// don't depend on the core database schema
+type MediaDataServiceClient interface {
+	// GetMediaFile returns a media file with its library and item titles, or nil when the host has none
+	GetMediaFile(ctx context.Context, mediaFileID string) (*MediaFileInfo, error)
+	// FindMediaByExternalID returns the items carrying an external ID, optionally only of mediaType
+	FindMediaByExternalID(ctx context.Context, source, externalID, mediaType string) ([]*MediaItemInfo, error)
+}
+
+// MediaEntityServiceClient creates library items through the host, which
+// matches existing items by external ID and title so plugins never write
+// movies, shows, seasons or episodes themselves
+type MediaEntityServiceClient interface {
+	// UpsertMovie finds or creates a movie, optionally linking a media file to it
+	UpsertMovie(ctx context.Context, req *UpsertMovieRequest) (*UpsertResult, error)
+	// UpsertShow finds or creates a TV show
+	UpsertShow(ctx context.Context, req *UpsertShowRequest) (*UpsertResult, error)
+	// UpsertSeason finds or creates a season of an existing show
+	UpsertSeason(ctx context.Context, showID string, seasonNumber int) (*UpsertResult, error)
+	// UpsertEpisode finds or creates an episode of an existing season, optionally linking a media file to it
+	UpsertEpisode(ctx context.Context, req *UpsertEpisodeRequest) (*UpsertResult, error)
+	// UpsertPerson finds a person by external ID, then name, creating or updating them
+	UpsertPerson(ctx context.Context, person *Person) (*UpsertResult, error)
+	// SetCredits replaces the cast and crew of a media file's movie or episode
+	SetCredits(ctx context.Context, mediaFileID string, credits []Credit) (*SetCreditsResult, error)
+}
+
 // Data structures
 type PluginContext struct {
 	PluginID        string `json:"plugin_id"` // Plugin identifier passed from manager
@@ -192,12 +249,18 @@ type APIRoute struct {
 	Public      bool   `json:"public"`
 }
 
+// SearchQueryFilePath is an optional search query field with the path of the
+// file a search is matching. Plugins that score results can score them the
+// way they match scanned files, e.g. with hints from the file's folders.
+const SearchQueryFilePath = "file_path"
+
 type SearchResult struct {
 	ID       string            `json:"id"`
 	Type     string            `json:"type"`
 	Title    string            `json:"title"`
 	Subtitle string            `json:"subtitle"`
 	URL      string            `json:"url"`
+	Score    float64           `json:"score,omitempty"` // How well the result matches the query, 0 when the plugin doesn't score
 	Metadata map[string]string `json:"metadata"`
 }
 
@@ -245,6 +308,34 @@ type RemoveAssetResponse struct {
 	Error   string `json:"error"`
 }
 
+// DownloadAssetRequest asks the host to download an asset from URL and save
+// it like a SaveAssetRequest with the same fields
+type DownloadAssetRequest struct {
+	URL         string            `json:"url"`
+	MediaFileID string            `json:"media_file_id"`
+	AssetType   string            `json:"asset_type"`
+	Category    string            `json:"category"`
+	Subtype     string            `json:"subtype"`
+	PluginID    string            `json:"plugin_id,omitempty"`
+	Metadata    map[string]string `json:"metadata"`
+	Headers     map[string]string `json:"headers,omitempty"`  // Extra request headers, e.g. a User-Agent the provider requires
+	MaxSize     int64             `json:"max_size,omitempty"` // Optional limit in bytes, below the host's own
+}
+
+// DownloadAssetResponse is the outcome of a DownloadAssetRequest. StatusCode
+// is the HTTP status of the last attempt, so plugins can tell a missing image
+// (404) from a failed download.
+type DownloadAssetResponse struct {
+	Success      bool   `json:"success"`
+	Error        string `json:"error"`
+	AssetID      uint32 `json:"asset_id"`
+	Hash         string `json:"hash"`
+	RelativePath string `json:"relative_path"`
+	Size         int64  `json:"size"`
+	MimeType     string `json:"mime_type"`
+	StatusCode   int    `json:"status_code"`
+}
+
 // Enrichment service request/response types
 type RegisterEnrichmentRequest struct {
 	MediaFileID     string            `json:"media_file_id"`
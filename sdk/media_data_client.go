@@ -0,0 +1,103 @@
+package plugins
+
+import (
+	"context"
+
+	"github.com/mantonx/viewra/sdk/proto"
+)
+
+// MediaFileInfo is a media file as the host's media data service describes it
+type MediaFileInfo struct {
+	ID            string `json:"id"`
+	MediaID       string `json:"media_id"`
+	MediaType     string `json:"media_type"`
+	LibraryID     uint32 `json:"library_id"`
+	LibraryType   string `json:"library_type"`
+	LibraryPath   string `json:"library_path"`
+	Path          string `json:"path"`
+	Container     string `json:"container,omitempty"`
+	VideoCodec    string `json:"video_codec,omitempty"`
+	AudioCodec    string `json:"audio_codec,omitempty"`
+	Resolution    string `json:"resolution,omitempty"`
+	Duration      int    `json:"duration,omitempty"`
+	SizeBytes     int64  `json:"size_bytes"`
+	Title         string `json:"title,omitempty"`
+	Year          int    `json:"year,omitempty"`
+	ShowID        string `json:"show_id,omitempty"`
+	ShowTitle     string `json:"show_title,omitempty"`
+	SeasonNumber  int    `json:"season_number,omitempty"`
+	EpisodeNumber int    `json:"episode_number,omitempty"`
+}
+
+// MediaItemInfo is a library item (movie, TV show, episode or track) found
+// by the host's media data service
+type MediaItemInfo struct {
+	ID          string            `json:"id"`
+	MediaType   string            `json:"media_type"`
+	Title       string            `json:"title"`
+	Year        int               `json:"year,omitempty"`
+	ExternalIDs map[string]string `json:"external_ids,omitempty"`
+}
+
+// GRPCMediaDataServiceClient implements MediaDataServiceClient using gRPC
+type GRPCMediaDataServiceClient struct {
+	client proto.MediaDataServiceClient
+}
+
+// GetMediaFile implements MediaDataServiceClient.GetMediaFile
+func (c *GRPCMediaDataServiceClient) GetMediaFile(ctx context.Context, mediaFileID string) (*MediaFileInfo, error) {
+	resp, err := c.client.GetMediaFile(ctx, &proto.GetMediaFileRequest{MediaFileId: mediaFileID})
+	if err != nil {
+		return nil, err
+	}
+	if !resp.Found || resp.MediaFile == nil {
+		return nil, nil
+	}
+
+	file := resp.MediaFile
+	return &MediaFileInfo{
+		ID:            file.Id,
+		MediaID:       file.MediaId,
+		MediaType:     file.MediaType,
+		LibraryID:     file.LibraryId,
+		LibraryType:   file.LibraryType,
+		LibraryPath:   file.LibraryPath,
+		Path:          file.Path,
+		Container:     file.Container,
+		VideoCodec:    file.VideoCodec,
+		AudioCodec:    file.AudioCodec,
+		Resolution:    file.Resolution,
+		Duration:      int(file.Duration),
+		SizeBytes:     file.SizeBytes,
+		Title:         file.Title,
+		Year:          int(file.Year),
+		ShowID:        file.ShowId,
+		ShowTitle:     file.ShowTitle,
+		SeasonNumber:  int(file.SeasonNumber),
+		EpisodeNumber: int(file.EpisodeNumber),
+	}, nil
+}
+
+// FindMediaByExternalID implements MediaDataServiceClient.FindMediaByExternalID
+func (c *GRPCMediaDataServiceClient) FindMediaByExternalID(ctx context.Context, source, externalID, mediaType string) ([]*MediaItemInfo, error) {
+	resp, err := c.client.FindMediaByExternalID(ctx, &proto.FindMediaByExternalIDRequest{
+		Source:     source,
+		ExternalId: externalID,
+		MediaType:  mediaType,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]*MediaItemInfo, 0, len(resp.Items))
+	for _, item := range resp.Items {
+		items = append(items, &MediaItemInfo{
+			ID:          item.Id,
+			MediaType:   item.MediaType,
+			Title:       item.Title,
+			Year:        int(item.Year),
+			ExternalIDs: item.ExternalIds,
+		})
+	}
+	return items, nil
+}
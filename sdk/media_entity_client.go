@@ -0,0 +1,163 @@
+package plugins
+
+import (
+	"context"
+
+	"github.com/mantonx/viewra/sdk/proto"
+)
+
+// UpsertMovieRequest describes a movie to find or create through the host
+type UpsertMovieRequest struct {
+	Title       string            `json:"title"`
+	Year        int               `json:"year,omitempty"`          // Release year, 0 when unknown
+	ExternalIDs map[string]string `json:"external_ids,omitempty"`  // By source, e.g. tmdb, imdb
+	MediaFileID string            `json:"media_file_id,omitempty"` // Media file to link to the movie
+}
+
+// UpsertShowRequest describes a TV show to find or create through the host
+type UpsertShowRequest struct {
+	Title       string            `json:"title"`
+	Year        int               `json:"year,omitempty"`         // First air year, 0 when unknown
+	ExternalIDs map[string]string `json:"external_ids,omitempty"` // By source, e.g. tmdb, tvdb, imdb
+}
+
+// UpsertEpisodeRequest describes an episode of a season to find or create
+// through the host
+type UpsertEpisodeRequest struct {
+	SeasonID      string            `json:"season_id"`
+	EpisodeNumber int               `json:"episode_number"`
+	Title         string            `json:"title,omitempty"` // Replaces the stored title when set
+	ExternalIDs   map[string]string `json:"external_ids,omitempty"`
+	MediaFileID   string            `json:"media_file_id,omitempty"` // Media file to link to the episode
+}
+
+// UpsertResult identifies the item an upsert resolved to
+type UpsertResult struct {
+	ID      string `json:"id"`
+	Created bool   `json:"created"` // False when an existing item matched
+}
+
+// Person describes a cast or crew member to find or create through the host.
+// Details left empty keep what the host already has.
+type Person struct {
+	Name               string            `json:"name"`
+	ExternalIDs        map[string]string `json:"external_ids,omitempty"` // By source, e.g. tmdb, imdb
+	Biography          string            `json:"biography,omitempty"`
+	Birthdate          string            `json:"birthdate,omitempty"` // YYYY-MM-DD
+	Deathdate          string            `json:"deathdate,omitempty"` // YYYY-MM-DD
+	PlaceOfBirth       string            `json:"place_of_birth,omitempty"`
+	KnownForDepartment string            `json:"known_for_department,omitempty"` // e.g. Acting, Directing
+	ProfileURL         string            `json:"profile_url,omitempty"`          // Downloaded by the host as the person's headshot
+}
+
+// Credit is a person's part in a movie or episode
+type Credit struct {
+	Person     Person `json:"person"`
+	Role       string `json:"role"`                // actor, or the crew job, e.g. director, writer
+	Character  string `json:"character,omitempty"` // Actors only
+	Department string `json:"department,omitempty"`
+	Order      int    `json:"order"` // Billing order, lowest first
+}
+
+// SetCreditsResult identifies the item credits were set on and its people
+type SetCreditsResult struct {
+	MediaID   string   `json:"media_id"`
+	PersonIDs []string `json:"person_ids"` // Person of each credit, in order
+}
+
+// GRPCMediaEntityServiceClient implements MediaEntityServiceClient using gRPC
+type GRPCMediaEntityServiceClient struct {
+	client proto.MediaEntityServiceClient
+}
+
+// UpsertMovie implements MediaEntityServiceClient.UpsertMovie
+func (c *GRPCMediaEntityServiceClient) UpsertMovie(ctx context.Context, req *UpsertMovieRequest) (*UpsertResult, error) {
+	resp, err := c.client.UpsertMovie(ctx, &proto.UpsertMovieRequest{
+		Title:       req.Title,
+		Year:        int32(req.Year),
+		ExternalIds: req.ExternalIDs,
+		MediaFileId: req.MediaFileID,
+	})
+	return upsertResult(resp, err)
+}
+
+// UpsertShow implements MediaEntityServiceClient.UpsertShow
+func (c *GRPCMediaEntityServiceClient) UpsertShow(ctx context.Context, req *UpsertShowRequest) (*UpsertResult, error) {
+	resp, err := c.client.UpsertShow(ctx, &proto.UpsertShowRequest{
+		Title:       req.Title,
+		Year:        int32(req.Year),
+		ExternalIds: req.ExternalIDs,
+	})
+	return upsertResult(resp, err)
+}
+
+// UpsertSeason implements MediaEntityServiceClient.UpsertSeason
+func (c *GRPCMediaEntityServiceClient) UpsertSeason(ctx context.Context, showID string, seasonNumber int) (*UpsertResult, error) {
+	resp, err := c.client.UpsertSeason(ctx, &proto.UpsertSeasonRequest{
+		ShowId:       showID,
+		SeasonNumber: int32(seasonNumber),
+	})
+	return upsertResult(resp, err)
+}
+
+// UpsertEpisode implements MediaEntityServiceClient.UpsertEpisode
+func (c *GRPCMediaEntityServiceClient) UpsertEpisode(ctx context.Context, req *UpsertEpisodeRequest) (*UpsertResult, error) {
+	resp, err := c.client.UpsertEpisode(ctx, &proto.UpsertEpisodeRequest{
+		SeasonId:      req.SeasonID,
+		EpisodeNumber: int32(req.EpisodeNumber),
+		Title:         req.Title,
+		ExternalIds:   req.ExternalIDs,
+		MediaFileId:   req.MediaFileID,
+	})
+	return upsertResult(resp, err)
+}
+
+// UpsertPerson implements MediaEntityServiceClient.UpsertPerson
+func (c *GRPCMediaEntityServiceClient) UpsertPerson(ctx context.Context, person *Person) (*UpsertResult, error) {
+	resp, err := c.client.UpsertPerson(ctx, &proto.UpsertPersonRequest{Person: personInfo(person)})
+	return upsertResult(resp, err)
+}
+
+// SetCredits implements MediaEntityServiceClient.SetCredits
+func (c *GRPCMediaEntityServiceClient) SetCredits(ctx context.Context, mediaFileID string, credits []Credit) (*SetCreditsResult, error) {
+	req := &proto.SetCreditsRequest{
+		MediaFileId: mediaFileID,
+		Credits:     make([]*proto.Credit, 0, len(credits)),
+	}
+	for i := range credits {
+		credit := &credits[i]
+		req.Credits = append(req.Credits, &proto.Credit{
+			Person:     personInfo(&credit.Person),
+			Role:       credit.Role,
+			Character:  credit.Character,
+			Department: credit.Department,
+			Order:      int32(credit.Order),
+		})
+	}
+
+	resp, err := c.client.SetCredits(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return &SetCreditsResult{MediaID: resp.MediaId, PersonIDs: resp.PersonIds}, nil
+}
+
+func personInfo(person *Person) *proto.PersonInfo {
+	return &proto.PersonInfo{
+		Name:               person.Name,
+		ExternalIds:        person.ExternalIDs,
+		Biography:          person.Biography,
+		Birthdate:          person.Birthdate,
+		Deathdate:          person.Deathdate,
+		PlaceOfBirth:       person.PlaceOfBirth,
+		KnownForDepartment: person.KnownForDepartment,
+		ProfileUrl:         person.ProfileURL,
+	}
+}
+
+func upsertResult(resp *proto.UpsertEntityResponse, err error) (*UpsertResult, error) {
+	if err != nil {
+		return nil, err
+	}
+	return &UpsertResult{ID: resp.Id, Created: resp.Created}, nil
+}
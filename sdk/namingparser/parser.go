@@ -0,0 +1,404 @@
+// Package namingparser reads titles, years and episode numbers from media
+// file names, so the scanner and enrichment plugins agree on what a file is.
+//
+// Parsing is rules-based: episode namings are regular expressions tried in
+// order (see Rule), and release tokens such as resolutions and codecs are
+// recognized by token sets (see TokenSet) and kept out of titles. Parse uses
+// the default rules; plugins with unusual naming build their own Parser from
+// DefaultRules and DefaultTokenSets. Users handle their own libraries'
+// naming with rewrites of the file names instead (see Rewrite).
+package namingparser
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Kind is what a file name says a file holds
+type Kind string
+
+const (
+	// KindUnknown is a file whose name has neither episode numbering nor a year
+	KindUnknown Kind = ""
+	// KindMovie is a file named with a title and year
+	KindMovie Kind = "movie"
+	// KindEpisode is a file named with episode numbering or kept in a season folder
+	KindEpisode Kind = "episode"
+)
+
+// Result is what a file's path says about the media it holds
+type Result struct {
+	Kind  Kind
+	Title string // Movie title or series name
+	Year  int    // Release or series year, 0 when absent
+
+	Season           int    // 0 for specials, or when the file only carries absolute numbers
+	Episodes         []int  // Episode numbers within the season, several for multi-episode files
+	AbsoluteEpisodes []int  // Absolute episode numbers, as anime releases use
+	AirDate          string // Air date of date-based episodes, as 2006-01-02
+	EpisodeTitle     string
+
+	ReleaseGroup string
+	Tokens       map[string]string // First token found per token set name, e.g. "resolution": "1080p"
+	Rule         string            // Name of the episode rule that matched, if any
+}
+
+// Episode returns the file's first episode number, or 0 when it has none
+func (r Result) Episode() int {
+	if len(r.Episodes) == 0 {
+		return 0
+	}
+	return r.Episodes[0]
+}
+
+// AbsoluteEpisode returns the file's first absolute episode number, or 0
+func (r Result) AbsoluteEpisode() int {
+	if len(r.AbsoluteEpisodes) == 0 {
+		return 0
+	}
+	return r.AbsoluteEpisodes[0]
+}
+
+// Parser parses file names with a set of episode rules and token sets
+type Parser struct {
+	rules  []Rule
+	tokens []TokenSet
+}
+
+// New returns a parser trying rules in order and recognizing tokens
+func New(rules []Rule, tokens []TokenSet) *Parser {
+	return &Parser{rules: rules, tokens: tokens}
+}
+
+var defaultParser = New(defaultRules, defaultTokenSets)
+
+// Parse parses a file path with the default rules and token sets
+func Parse(path string) Result {
+	return defaultParser.Parse(path)
+}
+
+var (
+	leadingGroupPattern   = regexp.MustCompile(`^\s*\[([^\]]+)\]\s*`)
+	trailingGroupPattern  = regexp.MustCompile(`-([A-Za-z0-9]+)$`)
+	bracketPattern        = regexp.MustCompile(`\[[^\]]*\]|\{[^}]*\}`)
+	trailingBrackets      = regexp.MustCompile(`(?:\s*(?:\[[^\]]*\]|\{[^}]*\}))+\s*$`)
+	yearPattern           = regexp.MustCompile(`(?:^|[\s(\[])((?:19|20)\d{2})\b`)
+	trailingYearPattern   = regexp.MustCompile(`[\s.(\[]((?:19|20)\d{2})[\s.)\]]*$`)
+	seasonDirPattern      = regexp.MustCompile(`(?i)^(?:season|series|staffel|saison|s)[\s._-]*(\d{1,3})$|^(specials?)$`)
+	trailingNumberPattern = regexp.MustCompile(`\s(\d{1,4})(?:v\d)?$`)
+	leadingNumberPattern  = regexp.MustCompile(`(?i)^\s*(?:episode|ep|e)?[\s.]*(\d{1,3})(?:$|[\s.-])`)
+	numberPattern         = regexp.MustCompile(`\d+`)
+	acronymPattern        = regexp.MustCompile(`(?:\b[A-Za-z]\.){2,}`)
+	mediaExtPattern       = regexp.MustCompile(`^\.[A-Za-z0-9]{2,5}$`)
+)
+
+// maxEpisodeRange bounds how many episodes a range such as "E01-E03" expands to
+const maxEpisodeRange = 100
+
+// tokenSpan is where a release token was found in a name
+type tokenSpan struct {
+	start, end int
+}
+
+// Parse parses a file path. The name carries the numbering; directories
+// are only used for the series name of files named by numbers alone, and
+// for the season of files kept in season folders.
+func (p *Parser) Parse(path string) Result {
+	result := Result{Tokens: map[string]string{}}
+	name := fileName(path)
+
+	// A leading [Group] is the release group of anime and fansub releases
+	anime := false
+	if m := leadingGroupPattern.FindStringSubmatch(name); m != nil {
+		result.ReleaseGroup = strings.TrimSpace(m[1])
+		name = name[len(m[0]):]
+		anime = true
+	}
+
+	cut, spans := p.findTokens(name, &result)
+
+	// A trailing -GROUP after the release tokens is the scene release group
+	if len(spans) > 0 {
+		trimmed := strings.TrimRight(trailingBrackets.ReplaceAllString(name, ""), " ")
+		if loc := trailingGroupPattern.FindStringSubmatchIndex(trimmed); loc != nil && loc[0] >= cut && !insideToken(spans, loc[2]) {
+			if result.ReleaseGroup == "" {
+				result.ReleaseGroup = trimmed[loc[2]:loc[3]]
+			}
+		}
+	}
+
+	work := normalize(name)
+
+	for _, rule := range p.rules {
+		loc := rule.Pattern.FindStringSubmatchIndex(work)
+		if loc == nil {
+			continue
+		}
+		if !p.applyRule(rule, work, loc, anime, &result) {
+			continue
+		}
+
+		result.Kind = KindEpisode
+		result.Rule = rule.Name
+		result.Title, result.Year = splitYear(cleanTitle(work[:loc[0]]))
+		if loc[1] < cut {
+			result.EpisodeTitle = cleanTitle(work[loc[1]:cut])
+		}
+		if result.Title == "" {
+			result.Title, result.Year = showDirectory(path)
+		} else if result.Year == 0 {
+			// "Show (2019)/Season 1/Show - S01E02.mkv" is of the 2019 show
+			if title, year := showDirectory(path); strings.EqualFold(title, result.Title) {
+				result.Year = year
+			}
+		}
+		return result
+	}
+
+	// Fansub releases may number episodes without a separator, as in
+	// "[Group] Show 01 (1080p)"
+	if anime {
+		titlePart := strings.TrimRight(work[:cut], " ([")
+		if m := trailingNumberPattern.FindStringSubmatchIndex(titlePart); m != nil {
+			number, _ := strconv.Atoi(titlePart[m[2]:m[3]])
+			result.Kind = KindEpisode
+			result.Rule = "anime_trailing"
+			result.AbsoluteEpisodes = []int{number}
+			result.Title, result.Year = splitYear(cleanTitle(titlePart[:m[0]]))
+			if result.Title == "" {
+				result.Title, result.Year = showDirectory(path)
+			}
+			return result
+		}
+	}
+
+	// Files without numbering in a season folder are episodes of its show
+	if season, ok := seasonFolder(filepath.Base(filepath.Dir(path))); ok {
+		result.Kind = KindEpisode
+		result.Rule = "season_folder"
+		result.Season = season
+		if m := leadingNumberPattern.FindStringSubmatchIndex(work); m != nil {
+			episode, _ := strconv.Atoi(work[m[2]:m[3]])
+			result.Episodes = []int{episode}
+			if m[1] < cut {
+				result.EpisodeTitle = cleanTitle(work[m[1]:cut])
+			}
+		} else {
+			result.EpisodeTitle = cleanTitle(work[:cut])
+		}
+		result.Title, result.Year = showDirectory(path)
+		return result
+	}
+
+	// Otherwise the last year before the release tokens ends a movie title,
+	// so "1917 (2019)" and "Blade Runner 2049 (2017)" keep their numbers
+	titlePart := work[:cut]
+	years := yearPattern.FindAllStringSubmatchIndex(titlePart, -1)
+	for i := len(years) - 1; i >= 0; i-- {
+		start := years[i][2]
+		title := cleanTitle(titlePart[:start])
+		if title == "" {
+			continue
+		}
+		result.Kind = KindMovie
+		result.Title = title
+		result.Year, _ = strconv.Atoi(titlePart[start:years[i][3]])
+		return result
+	}
+
+	result.Title = cleanTitle(titlePart)
+	return result
+}
+
+// findTokens records the release tokens in a name and returns where the first
+// one starts, or the name's length when it has none, along with every token
+// found. A token at the very start of a name is taken as part of the title.
+func (p *Parser) findTokens(name string, result *Result) (int, []tokenSpan) {
+	cut := len(name)
+	var spans []tokenSpan
+	for _, set := range p.tokens {
+		for _, loc := range set.Pattern.FindAllStringSubmatchIndex(name, -1) {
+			start, end := loc[0], loc[1]
+			if len(loc) >= 4 && loc[2] >= 0 {
+				start, end = loc[2], loc[3]
+			}
+			if strings.TrimSpace(name[:start]) == "" {
+				continue
+			}
+			spans = append(spans, tokenSpan{start, end})
+			if _, seen := result.Tokens[set.Name]; !seen {
+				result.Tokens[set.Name] = name[start:end]
+			}
+			if start < cut {
+				cut = start
+			}
+		}
+	}
+	return cut, spans
+}
+
+// applyRule fills in the numbers a rule matched. It reports false for matches
+// that are better read otherwise, such as "Movie - 1999" where the absolute
+// number is a year.
+func (p *Parser) applyRule(rule Rule, work string, loc []int, anime bool, result *Result) bool {
+	group := func(name string) string {
+		i := rule.Pattern.SubexpIndex(name)
+		if i < 0 || loc[2*i] < 0 {
+			return ""
+		}
+		return work[loc[2*i]:loc[2*i+1]]
+	}
+
+	if year := group("year"); year != "" {
+		month, day := group("month"), group("day")
+		y, _ := strconv.Atoi(year)
+		m, _ := strconv.Atoi(month)
+		d, _ := strconv.Atoi(day)
+		result.AirDate = fmt.Sprintf("%04d-%02d-%02d", y, m, d)
+		return true
+	}
+
+	if absolute := group("absolute"); absolute != "" {
+		numbers := episodeNumbers(absolute)
+		if !anime && len(numbers) == 1 && len(absolute) == 4 && numbers[0] >= 1900 && numbers[0] < 2100 {
+			return false
+		}
+		result.AbsoluteEpisodes = numbers
+		return true
+	}
+
+	episodes := episodeNumbers(group("episodes"))
+	if len(episodes) == 0 {
+		return false
+	}
+	result.Season, _ = strconv.Atoi(group("season"))
+	result.Episodes = episodes
+	return true
+}
+
+// episodeNumbers reads the numbers in an episodes group, expanding ranges:
+// "E01E02" is 1 and 2, "E01-E03" and "01-03" are 1 through 3
+func episodeNumbers(value string) []int {
+	locs := numberPattern.FindAllStringIndex(value, -1)
+	var numbers []int
+	for i, loc := range locs {
+		number, _ := strconv.Atoi(value[loc[0]:loc[1]])
+		if i > 0 && len(numbers) > 0 && strings.Contains(value[locs[i-1][1]:loc[0]], "-") {
+			last := numbers[len(numbers)-1]
+			if number > last && number-last <= maxEpisodeRange {
+				for n := last + 1; n <= number; n++ {
+					numbers = append(numbers, n)
+				}
+				continue
+			}
+		}
+		numbers = append(numbers, number)
+	}
+	return numbers
+}
+
+// insideToken reports whether a position falls within a token, so the "-DL"
+// of "WEB-DL" isn't taken for a release group
+func insideToken(spans []tokenSpan, pos int) bool {
+	for _, span := range spans {
+		if pos > span.start && pos < span.end {
+			return true
+		}
+	}
+	return false
+}
+
+// fileName returns the base name of a path without its extension
+func fileName(path string) string {
+	name := filepath.Base(path)
+	if ext := filepath.Ext(name); mediaExtPattern.MatchString(ext) {
+		name = strings.TrimSuffix(name, ext)
+	}
+	return name
+}
+
+// normalize blanks out brackets and separators without moving any text, so
+// positions found in the name still apply. Dots only separate words in
+// names without spaces, keeping "Mr. Robot" intact, and never within
+// acronyms such as "S.H.I.E.L.D.".
+func normalize(name string) string {
+	blank := func(match string) string { return strings.Repeat(" ", len(match)) }
+	work := bracketPattern.ReplaceAllStringFunc(name, blank)
+	work = strings.ReplaceAll(work, "_", " ")
+	if strings.Contains(strings.TrimSpace(work), " ") {
+		return work
+	}
+
+	dots := []byte(work)
+	acronyms := acronymPattern.FindAllStringIndex(work, -1)
+	for i := range dots {
+		if dots[i] == '.' && !insideSpan(acronyms, i) {
+			dots[i] = ' '
+		}
+	}
+	return string(dots)
+}
+
+// insideSpan reports whether a position falls within one of the spans
+func insideSpan(spans [][]int, pos int) bool {
+	for _, span := range spans {
+		if pos >= span[0] && pos < span[1] {
+			return true
+		}
+	}
+	return false
+}
+
+// cleanTitle collapses whitespace and trims the separators and brackets
+// left around a title once numbers and tokens are taken out
+func cleanTitle(title string) string {
+	title = strings.Join(strings.Fields(title), " ")
+	title = strings.Trim(title, " -–.,:;([{")
+	title = strings.TrimSuffix(title, " ()")
+	if strings.Count(title, "(") < strings.Count(title, ")") {
+		title = strings.TrimRight(title, ")")
+	}
+	return strings.TrimSpace(title)
+}
+
+// splitYear separates a trailing "(1959)" or "1959" from a title
+func splitYear(title string) (string, int) {
+	m := trailingYearPattern.FindStringSubmatchIndex(title)
+	if m == nil || m[0] == 0 {
+		return title, 0
+	}
+	year, _ := strconv.Atoi(title[m[2]:m[3]])
+	return cleanTitle(title[:m[0]]), year
+}
+
+// seasonFolder reads the season number of a folder such as "Season 1", "S01"
+// or "Specials"
+func seasonFolder(dir string) (int, bool) {
+	m := seasonDirPattern.FindStringSubmatch(strings.TrimSpace(dir))
+	if m == nil {
+		return 0, false
+	}
+	if m[2] != "" {
+		return 0, true
+	}
+	season, _ := strconv.Atoi(m[1])
+	return season, true
+}
+
+// showDirectory returns the series name and year of the show directory above
+// a file, skipping a season folder
+func showDirectory(path string) (string, int) {
+	dir := filepath.Dir(path)
+	if _, ok := seasonFolder(filepath.Base(dir)); ok {
+		dir = filepath.Dir(dir)
+	}
+	base := filepath.Base(dir)
+	if base == "." || base == string(filepath.Separator) {
+		return "", 0
+	}
+	base = leadingGroupPattern.ReplaceAllString(base, "")
+	return splitYear(cleanTitle(normalize(base)))
+}
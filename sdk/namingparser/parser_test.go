@@ -0,0 +1,227 @@
+package namingparser
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// fixture is one line of testdata/filenames.tsv
+type fixture struct {
+	path         string
+	kind         Kind
+	title        string
+	year         int
+	season       int
+	episodes     []int
+	absolute     []int
+	airDate      string
+	episodeTitle string
+	releaseGroup string
+}
+
+func loadFixtures(t *testing.T) []fixture {
+	t.Helper()
+	f, err := os.Open(filepath.Join("testdata", "filenames.tsv"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	var fixtures []fixture
+	scanner := bufio.NewScanner(f)
+	for line := 1; scanner.Scan(); line++ {
+		text := scanner.Text()
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		fields := strings.Split(text, "\t")
+		if len(fields) != 10 {
+			t.Fatalf("filenames.tsv:%d: want 10 fields, got %d", line, len(fields))
+		}
+		fixtures = append(fixtures, fixture{
+			path:         fields[0],
+			kind:         Kind(fields[1]),
+			title:        fields[2],
+			year:         atoi(t, fields[3]),
+			season:       atoi(t, fields[4]),
+			episodes:     numberList(t, fields[5]),
+			absolute:     numberList(t, fields[6]),
+			airDate:      fields[7],
+			episodeTitle: fields[8],
+			releaseGroup: fields[9],
+		})
+	}
+	if err := scanner.Err(); err != nil {
+		t.Fatal(err)
+	}
+	return fixtures
+}
+
+func atoi(t *testing.T, value string) int {
+	t.Helper()
+	if value == "" {
+		return 0
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return n
+}
+
+func numberList(t *testing.T, value string) []int {
+	t.Helper()
+	if value == "" {
+		return nil
+	}
+	var numbers []int
+	for _, part := range strings.Split(value, ",") {
+		numbers = append(numbers, atoi(t, part))
+	}
+	return numbers
+}
+
+func TestParseFixtures(t *testing.T) {
+	fixtures := loadFixtures(t)
+	if len(fixtures) < 300 {
+		t.Fatalf("expected at least 300 fixtures, got %d", len(fixtures))
+	}
+
+	for _, want := range fixtures {
+		t.Run(filepath.Base(want.path), func(t *testing.T) {
+			got := Parse(want.path)
+			if got.Kind != want.kind {
+				t.Errorf("kind = %q, want %q", got.Kind, want.kind)
+			}
+			if got.Title != want.title {
+				t.Errorf("title = %q, want %q", got.Title, want.title)
+			}
+			if got.Year != want.year {
+				t.Errorf("year = %d, want %d", got.Year, want.year)
+			}
+			if got.Season != want.season {
+				t.Errorf("season = %d, want %d", got.Season, want.season)
+			}
+			if !slices.Equal(got.Episodes, want.episodes) {
+				t.Errorf("episodes = %v, want %v", got.Episodes, want.episodes)
+			}
+			if !slices.Equal(got.AbsoluteEpisodes, want.absolute) {
+				t.Errorf("absolute episodes = %v, want %v", got.AbsoluteEpisodes, want.absolute)
+			}
+			if got.AirDate != want.airDate {
+				t.Errorf("air date = %q, want %q", got.AirDate, want.airDate)
+			}
+			if got.EpisodeTitle != want.episodeTitle {
+				t.Errorf("episode title = %q, want %q", got.EpisodeTitle, want.episodeTitle)
+			}
+			if got.ReleaseGroup != want.releaseGroup {
+				t.Errorf("release group = %q, want %q", got.ReleaseGroup, want.releaseGroup)
+			}
+		})
+	}
+}
+
+func TestParseTokens(t *testing.T) {
+	got := Parse("/downloads/Severance.S01E01.Good.News.About.Hell.2160p.ATVP.WEB-DL.DDP5.1.Atmos.DV.HEVC-CasStudio.mkv")
+	want := map[string]string{
+		TokenResolution:   "2160p",
+		TokenSource:       "ATVP",
+		TokenVideoCodec:   "HEVC",
+		TokenAudioCodec:   "DDP5.1",
+		TokenDynamicRange: "DV",
+	}
+	for name, value := range want {
+		if got.Tokens[name] != value {
+			t.Errorf("token %s = %q, want %q", name, got.Tokens[name], value)
+		}
+	}
+}
+
+func TestEpisodeNumbers(t *testing.T) {
+	testCases := []struct {
+		value string
+		want  []int
+	}{
+		{"E01", []int{1}},
+		{"E01E02E03", []int{1, 2, 3}},
+		{"E01-E03", []int{1, 2, 3}},
+		{"E01-03", []int{1, 2, 3}},
+		{"17-18", []int{17, 18}},
+		{"05-02", []int{5, 2}},
+		{"01-999", []int{1, 999}},
+	}
+
+	for _, tc := range testCases {
+		if got := episodeNumbers(tc.value); !slices.Equal(got, tc.want) {
+			t.Errorf("episodeNumbers(%q) = %v, want %v", tc.value, got, tc.want)
+		}
+	}
+}
+
+func TestParserCustomRule(t *testing.T) {
+	// A plugin for a library naming episodes "Show - Folge 12" adds its rule
+	// ahead of the defaults
+	rules := append([]Rule{{
+		Name:    "german_episode",
+		Pattern: regexp.MustCompile(`(?i)\s-\s+Folge\s+(?P<absolute>\d{1,4})\b`),
+	}}, DefaultRules()...)
+	parser := New(rules, DefaultTokenSets())
+
+	got := parser.Parse("/tv/Tatort/Tatort - Folge 1000 - Taxi nach Leipzig.mkv")
+	if got.Kind != KindEpisode || got.Rule != "german_episode" {
+		t.Fatalf("kind = %q, rule = %q, want an episode matched by german_episode", got.Kind, got.Rule)
+	}
+	if got.Title != "Tatort" || got.AbsoluteEpisode() != 1000 || got.EpisodeTitle != "Taxi nach Leipzig" {
+		t.Errorf("got %q episode %d %q", got.Title, got.AbsoluteEpisode(), got.EpisodeTitle)
+	}
+
+	if Parse("/tv/Tatort/Tatort - Folge 1000 - Taxi nach Leipzig.mkv").Rule == "german_episode" {
+		t.Error("custom rules must not change the default parser")
+	}
+}
+
+func TestApplyRewrites(t *testing.T) {
+	// A library naming episodes "Show.Folge.12" is rewritten into SxxEyy
+	// naming, and a group tag inside the title is dropped
+	rewrites := make([]Rewrite, 0, 2)
+	for _, rule := range [][2]string{
+		{`(?i)\.Folge\.(\d+)`, `.S01E$1`},
+		{`\s*\{[A-Z]+\}`, ``},
+	} {
+		rewrite, err := CompileRewrite(rule[0], rule[1])
+		if err != nil {
+			t.Fatal(err)
+		}
+		rewrites = append(rewrites, rewrite)
+	}
+
+	testCases := []struct {
+		path string
+		want string
+	}{
+		{"/tv/Tatort/Tatort.Folge.12.mkv", "/tv/Tatort/Tatort.S01E12.mkv"},
+		{"/movies/Alien {XYZ} (1979).mp4", "/movies/Alien (1979).mp4"},
+		{"/tv/Folge.3/Show - S02E01.mkv", "/tv/Folge.3/Show - S02E01.mkv"},
+		{"Tatort Folge 12", "Tatort Folge 12"},
+	}
+	for _, tc := range testCases {
+		if got := ApplyRewrites(tc.path, rewrites); got != tc.want {
+			t.Errorf("ApplyRewrites(%q) = %q, want %q", tc.path, got, tc.want)
+		}
+	}
+
+	got := Parse(ApplyRewrites("/tv/Tatort/Tatort.Folge.12.mkv", rewrites))
+	if got.Kind != KindEpisode || got.Title != "Tatort" || got.Season != 1 || got.Episode() != 12 {
+		t.Errorf("rewritten name parsed as %q %q S%dE%d", got.Kind, got.Title, got.Season, got.Episode())
+	}
+
+	if _, err := CompileRewrite(`(unclosed`, ""); err == nil {
+		t.Error("invalid patterns must not compile")
+	}
+}
@@ -0,0 +1,47 @@
+package namingparser
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// Rewrite is a regular expression replacement applied to a file name before
+// it is parsed. Libraries with release naming the rules don't read, such as
+// "Show.Folge.12" or a group tag in the middle of the title, are handled by
+// rewriting the names into a form they do read instead of adding rules.
+type Rewrite struct {
+	Pattern     *regexp.Regexp
+	Replacement string // May refer to submatches as $1 or ${name}
+}
+
+// CompileRewrite compiles a rewrite of the matches of pattern
+func CompileRewrite(pattern, replacement string) (Rewrite, error) {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return Rewrite{}, fmt.Errorf("invalid pattern %q: %w", pattern, err)
+	}
+	return Rewrite{Pattern: re, Replacement: replacement}, nil
+}
+
+// ApplyRewrites applies rewrites in order to the file name of path, without
+// its media extension. Directories are left as they are, so show and season
+// folders are still read.
+func ApplyRewrites(path string, rewrites []Rewrite) string {
+	if len(rewrites) == 0 {
+		return path
+	}
+
+	dir, name := filepath.Split(path)
+	ext := filepath.Ext(name)
+	if mediaExtPattern.MatchString(ext) {
+		name = strings.TrimSuffix(name, ext)
+	} else {
+		ext = ""
+	}
+	for _, rewrite := range rewrites {
+		name = rewrite.Pattern.ReplaceAllString(name, rewrite.Replacement)
+	}
+	return dir + name + ext
+}
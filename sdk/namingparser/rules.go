@@ -0,0 +1,100 @@
+package namingparser
+
+import "regexp"
+
+// Rule recognizes one way of numbering episodes in a file name. The
+// pattern's named groups say what it found:
+//
+//   - season: the season number
+//   - episodes: one or more episode numbers, e.g. "E01E02" or "01-03"; a
+//     "-" between two numbers marks a range
+//   - absolute: an absolute episode number or range, as anime releases use
+//   - year, month and day: the air date of a date-based episode
+//
+// Text before the match is the series name and text after it, up to the
+// first release token, the episode title.
+type Rule struct {
+	Name    string
+	Pattern *regexp.Regexp
+}
+
+// TokenSet recognizes one kind of release token, such as resolutions or
+// video codecs. The pattern's first group, or the whole match when it has
+// none, is the token. Tokens are recorded in Result.Tokens under the set's
+// name and everything from the first token on is left out of titles.
+type TokenSet struct {
+	Name    string
+	Pattern *regexp.Regexp
+}
+
+// Token set names used by the default token sets
+const (
+	TokenResolution   = "resolution"
+	TokenSource       = "source"
+	TokenVideoCodec   = "video_codec"
+	TokenAudioCodec   = "audio_codec"
+	TokenDynamicRange = "dynamic_range"
+	TokenEdition      = "edition"
+	TokenRelease      = "release"
+)
+
+// defaultRules are tried in order, so more specific namings come first.
+// Names have "_" replaced, and "." too when they contain no spaces, by the
+// time rules see them.
+var defaultRules = []Rule{
+	// "Show S01E02", "Show s1e2", "Show S2024E01", "Show S01E02E03", "Show S01E02-E03", "Show S01 E02"
+	{Name: "season_episode", Pattern: regexp.MustCompile(
+		`(?i)\bS(?P<season>\d{1,4})[\s.-]*(?P<episodes>E\d{1,4}(?:-E?\d{1,4}|[\s.-]*E\d{1,4})*)\b`)},
+	// "Show 1x02", "Show 1x02-03", "Show 1x02x03"
+	{Name: "cross", Pattern: regexp.MustCompile(
+		`(?i)\b(?P<season>\d{1,2})x(?P<episodes>\d{2,3}(?:[-x]\d{2,3})*)\b`)},
+	// "Show Season 1 Episode 2"
+	{Name: "season_word", Pattern: regexp.MustCompile(
+		`(?i)\bseason[\s.-]*(?P<season>\d{1,3})[\s.,-]*(?:episode|ep)[\s.-]*(?P<episodes>\d{1,4}(?:\s*-\s*\d{1,4})?)\b`)},
+	// "The Daily Show - 2013-02-08", "Show.2013.02.08"
+	{Name: "daily", Pattern: regexp.MustCompile(
+		`\b(?P<year>(?:19|20)\d{2})[\s.-](?P<month>0[1-9]|1[0-2])[\s.-](?P<day>0[1-9]|[12]\d|3[01])\b`)},
+	// "[Group] Show S2 - 05"
+	{Name: "anime_season", Pattern: regexp.MustCompile(
+		`(?i)\bS(?P<season>\d{1,2})\s+-\s+(?P<episodes>\d{1,4})(?:v\d)?\b`)},
+	// "[Group] Show - 012", "Show - 1042v2", "Show #12"
+	{Name: "absolute", Pattern: regexp.MustCompile(
+		`(?:\s-\s+|\s#)(?P<absolute>\d{1,4}(?:-\d{1,4})?)(?:v\d)?(?:\s|$)`)},
+	// "Show Episode 12", "Show Ep.12"
+	{Name: "episode_word", Pattern: regexp.MustCompile(
+		`(?i)(?:^|\s)(?:episode|ep)[\s.]*(?P<absolute>\d{1,4})\b`)},
+}
+
+// tokenPattern wraps alternatives so they only match as whole words between
+// the separators release names use
+func tokenPattern(alternatives string) *regexp.Regexp {
+	return regexp.MustCompile(`(?:^|[\s._\-\[\](){}+,])(` + alternatives + `)(?:$|[\s._\-\[\](){}+,])`)
+}
+
+// defaultTokenSets recognize common scene and fansub release tokens. Words
+// that also occur in titles, such as WEB or CAM, only match in upper case.
+var defaultTokenSets = []TokenSet{
+	{Name: TokenResolution, Pattern: tokenPattern(`(?i:2160p|1440p|1080[pi]|720p|576[pi]|540p|480[pi]|360p|4K|UHD)|\d{3,4}x\d{3,4}`)},
+	{Name: TokenSource, Pattern: tokenPattern(
+		`(?i:blu-?ray|bdrip|brrip|bdremux|bd25|bd50|web-?dl|web-?rip|hdtv|pdtv|sdtv|dvd-?rip|dvd-?r|dvdscr|dvd5|dvd9|hdrip|remux|telesync|telecine)` +
+			`|WEB|DVD|HD-?TS|CAM|TS|TC|AMZN|NF|DSNP|HMAX|ATVP|HULU|PCOK|iT`)},
+	{Name: TokenVideoCodec, Pattern: tokenPattern(`(?i:[xh][.\s]?26[45]|hevc|avc|xvid|divx|av1|vc-?1|mpeg-?2)`)},
+	{Name: TokenAudioCodec, Pattern: tokenPattern(
+		`(?i:dts-?hd(?:[.\s-]?ma)?|dts-?x|dts|truehd|atmos|e-?ac-?3|ac-?3|ddp?(?:[257]\.[01])?|aac(?:[257]\.[01])?|flac|opus|mp3|lpcm|[257]\.[01])`)},
+	{Name: TokenDynamicRange, Pattern: tokenPattern(`(?i:hdr10\+?|hdr|dolby[.\s]vision|10-?bit|8-?bit|hi10p?)|DV|SDR`)},
+	{Name: TokenEdition, Pattern: tokenPattern(
+		`(?i:director'?s[.\s]cut|extended[.\s](?:cut|edition)|theatrical[.\s](?:cut|edition)|special[.\s]edition|unrated|uncut|remastered|imax|criterion)` +
+			`|EXTENDED|THEATRICAL`)},
+	{Name: TokenRelease, Pattern: tokenPattern(`PROPER|REPACK|RERIP|REAL|INTERNAL|LIMITED|READNFO|MULTI|(?i:dubbed|subbed|dual[.\s-]audio)`)},
+}
+
+// DefaultRules returns a copy of the episode rules Parse uses, for building a
+// Parser that adds its own
+func DefaultRules() []Rule {
+	return append([]Rule(nil), defaultRules...)
+}
+
+// DefaultTokenSets returns a copy of the token sets Parse uses
+func DefaultTokenSets() []TokenSet {
+	return append([]TokenSet(nil), defaultTokenSets...)
+}
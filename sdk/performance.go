@@ -1,12 +1,14 @@
 package plugins
 
 import (
+	"context"
 	"fmt"
 	"sync"
 	"time"
 )
 
-// BasePerformanceMonitor provides reusable performance monitoring for plugins
+// BasePerformanceMonitor provides reusable performance monitoring for plugins.
+// Plugins can return it as their PerformanceMonitorService.
 type BasePerformanceMonitor struct {
 	mutex      sync.RWMutex
 	startTime  time.Time
@@ -30,6 +32,9 @@ type BasePerformanceMonitor struct {
 	customCounters map[string]int64
 	customGauges   map[string]float64
 	customTimers   map[string]time.Duration
+
+	// Requests to external providers, by provider
+	providers map[string]*providerMetrics
 }
 
 // OperationMetrics tracks metrics for a specific operation type
@@ -80,6 +85,9 @@ type PerformanceSnapshot struct {
 	CustomCounters map[string]int64         `json:"custom_counters"`
 	CustomGauges   map[string]float64       `json:"custom_gauges"`
 	CustomTimers   map[string]time.Duration `json:"custom_timers"`
+
+	// External provider latency, by provider
+	Providers map[string]*ProviderSnapshot `json:"providers,omitempty"`
 }
 
 // OperationSnapshot represents metrics for a specific operation
@@ -94,6 +102,8 @@ type OperationSnapshot struct {
 	LastCall       time.Time     `json:"last_call"`
 }
 
+var _ PerformanceMonitorService = (*BasePerformanceMonitor)(nil)
+
 // NewBasePerformanceMonitor creates a new base performance monitor
 func NewBasePerformanceMonitor(pluginName string) *BasePerformanceMonitor {
 	return &BasePerformanceMonitor{
@@ -106,6 +116,7 @@ func NewBasePerformanceMonitor(pluginName string) *BasePerformanceMonitor {
 		customCounters:  make(map[string]int64),
 		customGauges:    make(map[string]float64),
 		customTimers:    make(map[string]time.Duration),
+		providers:       make(map[string]*providerMetrics),
 	}
 }
 
@@ -297,6 +308,11 @@ func (pm *BasePerformanceMonitor) GetSnapshot() *PerformanceSnapshot {
 	recentErrors := make([]ErrorEvent, len(pm.recentErrors))
 	copy(recentErrors, pm.recentErrors)
 
+	providers := make(map[string]*ProviderSnapshot, len(pm.providers))
+	for name, metrics := range pm.providers {
+		providers[name] = metrics.snapshot(name)
+	}
+
 	return &PerformanceSnapshot{
 		Timestamp:            now,
 		PluginName:           pm.pluginName,
@@ -315,9 +331,15 @@ func (pm *BasePerformanceMonitor) GetSnapshot() *PerformanceSnapshot {
 		CustomCounters:       customCounters,
 		CustomGauges:         customGauges,
 		CustomTimers:         customTimers,
+		Providers:            providers,
 	}
 }
 
+// GetPerformanceSnapshot implements PerformanceMonitorService
+func (pm *BasePerformanceMonitor) GetPerformanceSnapshot(ctx context.Context) (*PerformanceSnapshot, error) {
+	return pm.GetSnapshot(), nil
+}
+
 // Reset resets all performance metrics
 func (pm *BasePerformanceMonitor) Reset() {
 	pm.mutex.Lock()
@@ -334,6 +356,7 @@ func (pm *BasePerformanceMonitor) Reset() {
 	pm.customCounters = make(map[string]int64)
 	pm.customGauges = make(map[string]float64)
 	pm.customTimers = make(map[string]time.Duration)
+	pm.providers = make(map[string]*providerMetrics)
 }
 
 // GetUptimeString returns a human-readable uptime string
@@ -0,0 +1,96 @@
+package plugins
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// PerformanceMonitorServiceName is the gRPC service exposing a plugin's performance snapshot
+const PerformanceMonitorServiceName = "viewra.PerformanceMonitorService"
+
+// PerformanceSnapshotMethod is the full gRPC method name for fetching the snapshot
+const PerformanceSnapshotMethod = "/" + PerformanceMonitorServiceName + "/GetPerformanceSnapshot"
+
+// PerformanceSnapshotRequest asks a plugin for its performance snapshot
+type PerformanceSnapshotRequest struct{}
+
+// performanceMonitorServer is the server side of the performance service
+type performanceMonitorServer interface {
+	GetPerformanceSnapshot(ctx context.Context, req *PerformanceSnapshotRequest) (*PerformanceSnapshot, error)
+}
+
+// PerformanceMonitorServer exposes a plugin's PerformanceMonitorService over
+// gRPC. The service is looked up per call since plugins usually create it
+// during Initialize, after the gRPC server is registered.
+type PerformanceMonitorServer struct {
+	Impl Implementation
+}
+
+// GetPerformanceSnapshot returns the plugin's current performance snapshot
+func (s *PerformanceMonitorServer) GetPerformanceSnapshot(ctx context.Context, req *PerformanceSnapshotRequest) (*PerformanceSnapshot, error) {
+	monitor := s.Impl.PerformanceMonitorService()
+	if isNilService(monitor) {
+		return nil, status.Error(codes.Unimplemented, "plugin has no performance monitor service")
+	}
+
+	snapshot, err := monitor.GetPerformanceSnapshot(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if snapshot == nil {
+		return &PerformanceSnapshot{}, nil
+	}
+	return snapshot, nil
+}
+
+func getPerformanceSnapshotHandler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PerformanceSnapshotRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(performanceMonitorServer).GetPerformanceSnapshot(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PerformanceSnapshotMethod,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(performanceMonitorServer).GetPerformanceSnapshot(ctx, req.(*PerformanceSnapshotRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// performanceMonitorServiceDesc describes the performance service, hand-written like the HTTP bridge
+var performanceMonitorServiceDesc = grpc.ServiceDesc{
+	ServiceName: PerformanceMonitorServiceName,
+	HandlerType: (*performanceMonitorServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetPerformanceSnapshot",
+			Handler:    getPerformanceSnapshotHandler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "performance_service.go",
+}
+
+// RegisterPerformanceMonitorServer registers the performance service for a plugin
+func RegisterPerformanceMonitorServer(s *grpc.Server, impl Implementation) {
+	s.RegisterService(&performanceMonitorServiceDesc, &PerformanceMonitorServer{Impl: impl})
+}
+
+// GetPluginPerformanceSnapshot fetches a plugin's performance snapshot over its
+// gRPC connection. Plugins without a PerformanceMonitorService return a
+// codes.Unimplemented status.
+func GetPluginPerformanceSnapshot(ctx context.Context, conn grpc.ClientConnInterface, opts ...grpc.CallOption) (*PerformanceSnapshot, error) {
+	resp := new(PerformanceSnapshot)
+	opts = append([]grpc.CallOption{grpc.CallContentSubtype(JSONCodec)}, opts...)
+	if err := conn.Invoke(ctx, PerformanceSnapshotMethod, &PerformanceSnapshotRequest{}, resp, opts...); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
@@ -0,0 +1,135 @@
+package plugins
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"google.golang.org/grpc"
+)
+
+// PlaybackHookServiceName is the gRPC service through which the host reports playback
+const PlaybackHookServiceName = "viewra.PlaybackHookService"
+
+// PlaybackEventMethod is the full gRPC method name for reporting a playback event
+const PlaybackEventMethod = "/" + PlaybackHookServiceName + "/OnPlaybackEvent"
+
+// Playback event types
+const (
+	PlaybackEventStarted = "started" // Playback began, or resumed after a pause
+	PlaybackEventPaused  = "paused"
+	PlaybackEventStopped = "stopped" // The session ended
+)
+
+// PlaybackHookService is implemented by plugins that follow what users
+// watch, such as scrobblers. The host reports each playback session's
+// start, pauses and resumes, and end; a resume is reported as started.
+// Events are sent in the background after the session is recorded, so a
+// slow or failing plugin never holds up the player.
+//
+// Implementing this interface is optional; plugins that don't are left unchanged.
+type PlaybackHookService interface {
+	OnPlaybackStarted(event *PlaybackEvent) error
+	OnPlaybackPaused(event *PlaybackEvent) error
+	OnPlaybackStopped(event *PlaybackEvent) error
+}
+
+// PlaybackEvent describes a playback session when it changes state, with
+// the external IDs and titles of what is playing for matching it elsewhere
+type PlaybackEvent struct {
+	Type            string    `json:"type"` // started, paused or stopped
+	SessionID       string    `json:"session_id"`
+	UserID          uint32    `json:"user_id,omitempty"`
+	MediaFileID     string    `json:"media_file_id"`
+	MediaID         string    `json:"media_id,omitempty"`
+	MediaType       string    `json:"media_type,omitempty"` // movie, episode or track
+	PositionSeconds float64   `json:"position_seconds"`
+	DurationSeconds float64   `json:"duration_seconds"`
+	WatchedSeconds  float64   `json:"watched_seconds"`
+	Progress        float64   `json:"progress"` // Position as a percentage of the duration
+	Completed       bool      `json:"completed,omitempty"`
+	Title           string    `json:"title,omitempty"`
+	Year            int       `json:"year,omitempty"`
+	TmdbID          string    `json:"tmdb_id,omitempty"`
+	ImdbID          string    `json:"imdb_id,omitempty"`
+	ShowTitle       string    `json:"show_title,omitempty"`
+	ShowTmdbID      string    `json:"show_tmdb_id,omitempty"`
+	Season          int       `json:"season,omitempty"`
+	Episode         int       `json:"episode,omitempty"`
+	OccurredAt      time.Time `json:"occurred_at"`
+}
+
+// PlaybackEventResponse acknowledges a playback event
+type PlaybackEventResponse struct{}
+
+// playbackHookServer is the server side of the playback hook service
+type playbackHookServer interface {
+	OnPlaybackEvent(ctx context.Context, event *PlaybackEvent) (*PlaybackEventResponse, error)
+}
+
+// PlaybackHookServer passes playback events to a plugin's PlaybackHookService
+type PlaybackHookServer struct {
+	Impl PlaybackHookService
+}
+
+// OnPlaybackEvent calls the hook matching the event's type
+func (s *PlaybackHookServer) OnPlaybackEvent(ctx context.Context, event *PlaybackEvent) (*PlaybackEventResponse, error) {
+	var err error
+	switch event.Type {
+	case PlaybackEventStarted:
+		err = s.Impl.OnPlaybackStarted(event)
+	case PlaybackEventPaused:
+		err = s.Impl.OnPlaybackPaused(event)
+	case PlaybackEventStopped:
+		err = s.Impl.OnPlaybackStopped(event)
+	default:
+		err = fmt.Errorf("unknown playback event type %q", event.Type)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &PlaybackEventResponse{}, nil
+}
+
+func onPlaybackEventHandler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PlaybackEvent)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(playbackHookServer).OnPlaybackEvent(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PlaybackEventMethod,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(playbackHookServer).OnPlaybackEvent(ctx, req.(*PlaybackEvent))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// playbackHookServiceDesc describes the playback hook service, hand-written like the HTTP bridge
+var playbackHookServiceDesc = grpc.ServiceDesc{
+	ServiceName: PlaybackHookServiceName,
+	HandlerType: (*playbackHookServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "OnPlaybackEvent",
+			Handler:    onPlaybackEventHandler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "playback_hooks.go",
+}
+
+// RegisterPlaybackHookServer registers the playback hook service for a plugin
+func RegisterPlaybackHookServer(s *grpc.Server, impl PlaybackHookService) {
+	s.RegisterService(&playbackHookServiceDesc, &PlaybackHookServer{Impl: impl})
+}
+
+// SendPlaybackEvent reports a playback event to a plugin over its gRPC connection
+func SendPlaybackEvent(ctx context.Context, conn grpc.ClientConnInterface, event *PlaybackEvent, opts ...grpc.CallOption) error {
+	opts = append([]grpc.CallOption{grpc.CallContentSubtype(JSONCodec)}, opts...)
+	return conn.Invoke(ctx, PlaybackEventMethod, event, new(PlaybackEventResponse), opts...)
+}
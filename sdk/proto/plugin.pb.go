@@ -579,6 +579,214 @@ func (x *RemoveAssetResponse) GetError() string {
 	return ""
 }
 
+type DownloadAssetRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Url           string                 `protobuf:"bytes,1,opt,name=url,proto3" json:"url,omitempty"` // Where to download the asset from
+	MediaFileId   string                 `protobuf:"bytes,2,opt,name=media_file_id,json=mediaFileId,proto3" json:"media_file_id,omitempty"`
+	AssetType     string                 `protobuf:"bytes,3,opt,name=asset_type,json=assetType,proto3" json:"asset_type,omitempty"`
+	Category      string                 `protobuf:"bytes,4,opt,name=category,proto3" json:"category,omitempty"`
+	Subtype       string                 `protobuf:"bytes,5,opt,name=subtype,proto3" json:"subtype,omitempty"`
+	Metadata      map[string]string      `protobuf:"bytes,6,rep,name=metadata,proto3" json:"metadata,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"` // Saved with the asset, as in SaveAssetRequest
+	PluginId      string                 `protobuf:"bytes,7,opt,name=plugin_id,json=pluginId,proto3" json:"plugin_id,omitempty"`
+	Headers       map[string]string      `protobuf:"bytes,8,rep,name=headers,proto3" json:"headers,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"` // Extra request headers, e.g. a User-Agent the provider requires
+	MaxSize       int64                  `protobuf:"varint,9,opt,name=max_size,json=maxSize,proto3" json:"max_size,omitempty"`                                                           // Optional limit in bytes, below the host's own
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DownloadAssetRequest) Reset() {
+	*x = DownloadAssetRequest{}
+	mi := &file_plugin_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DownloadAssetRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DownloadAssetRequest) ProtoMessage() {}
+
+func (x *DownloadAssetRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_plugin_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DownloadAssetRequest.ProtoReflect.Descriptor instead.
+func (*DownloadAssetRequest) Descriptor() ([]byte, []int) {
+	return file_plugin_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *DownloadAssetRequest) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *DownloadAssetRequest) GetMediaFileId() string {
+	if x != nil {
+		return x.MediaFileId
+	}
+	return ""
+}
+
+func (x *DownloadAssetRequest) GetAssetType() string {
+	if x != nil {
+		return x.AssetType
+	}
+	return ""
+}
+
+func (x *DownloadAssetRequest) GetCategory() string {
+	if x != nil {
+		return x.Category
+	}
+	return ""
+}
+
+func (x *DownloadAssetRequest) GetSubtype() string {
+	if x != nil {
+		return x.Subtype
+	}
+	return ""
+}
+
+func (x *DownloadAssetRequest) GetMetadata() map[string]string {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+func (x *DownloadAssetRequest) GetPluginId() string {
+	if x != nil {
+		return x.PluginId
+	}
+	return ""
+}
+
+func (x *DownloadAssetRequest) GetHeaders() map[string]string {
+	if x != nil {
+		return x.Headers
+	}
+	return nil
+}
+
+func (x *DownloadAssetRequest) GetMaxSize() int64 {
+	if x != nil {
+		return x.MaxSize
+	}
+	return 0
+}
+
+type DownloadAssetResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Error         string                 `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
+	AssetId       uint32                 `protobuf:"varint,3,opt,name=asset_id,json=assetId,proto3" json:"asset_id,omitempty"`
+	Hash          string                 `protobuf:"bytes,4,opt,name=hash,proto3" json:"hash,omitempty"`
+	RelativePath  string                 `protobuf:"bytes,5,opt,name=relative_path,json=relativePath,proto3" json:"relative_path,omitempty"`
+	Size          int64                  `protobuf:"varint,6,opt,name=size,proto3" json:"size,omitempty"` // Bytes downloaded
+	MimeType      string                 `protobuf:"bytes,7,opt,name=mime_type,json=mimeType,proto3" json:"mime_type,omitempty"`
+	StatusCode    int32                  `protobuf:"varint,8,opt,name=status_code,json=statusCode,proto3" json:"status_code,omitempty"` // HTTP status of the last attempt, e.g. 404 when the provider has no such image
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DownloadAssetResponse) Reset() {
+	*x = DownloadAssetResponse{}
+	mi := &file_plugin_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DownloadAssetResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DownloadAssetResponse) ProtoMessage() {}
+
+func (x *DownloadAssetResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_plugin_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DownloadAssetResponse.ProtoReflect.Descriptor instead.
+func (*DownloadAssetResponse) Descriptor() ([]byte, []int) {
+	return file_plugin_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *DownloadAssetResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *DownloadAssetResponse) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *DownloadAssetResponse) GetAssetId() uint32 {
+	if x != nil {
+		return x.AssetId
+	}
+	return 0
+}
+
+func (x *DownloadAssetResponse) GetHash() string {
+	if x != nil {
+		return x.Hash
+	}
+	return ""
+}
+
+func (x *DownloadAssetResponse) GetRelativePath() string {
+	if x != nil {
+		return x.RelativePath
+	}
+	return ""
+}
+
+func (x *DownloadAssetResponse) GetSize() int64 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+func (x *DownloadAssetResponse) GetMimeType() string {
+	if x != nil {
+		return x.MimeType
+	}
+	return ""
+}
+
+func (x *DownloadAssetResponse) GetStatusCode() int32 {
+	if x != nil {
+		return x.StatusCode
+	}
+	return 0
+}
+
 // Search service messages
 type SearchRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
@@ -591,7 +799,7 @@ type SearchRequest struct {
 
 func (x *SearchRequest) Reset() {
 	*x = SearchRequest{}
-	mi := &file_plugin_proto_msgTypes[9]
+	mi := &file_plugin_proto_msgTypes[11]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -603,7 +811,7 @@ func (x *SearchRequest) String() string {
 func (*SearchRequest) ProtoMessage() {}
 
 func (x *SearchRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_plugin_proto_msgTypes[9]
+	mi := &file_plugin_proto_msgTypes[11]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -616,7 +824,7 @@ func (x *SearchRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use SearchRequest.ProtoReflect.Descriptor instead.
 func (*SearchRequest) Descriptor() ([]byte, []int) {
-	return file_plugin_proto_rawDescGZIP(), []int{9}
+	return file_plugin_proto_rawDescGZIP(), []int{11}
 }
 
 func (x *SearchRequest) GetQuery() map[string]string {
@@ -653,7 +861,7 @@ type SearchResponse struct {
 
 func (x *SearchResponse) Reset() {
 	*x = SearchResponse{}
-	mi := &file_plugin_proto_msgTypes[10]
+	mi := &file_plugin_proto_msgTypes[12]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -665,7 +873,7 @@ func (x *SearchResponse) String() string {
 func (*SearchResponse) ProtoMessage() {}
 
 func (x *SearchResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_plugin_proto_msgTypes[10]
+	mi := &file_plugin_proto_msgTypes[12]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -678,7 +886,7 @@ func (x *SearchResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use SearchResponse.ProtoReflect.Descriptor instead.
 func (*SearchResponse) Descriptor() ([]byte, []int) {
-	return file_plugin_proto_rawDescGZIP(), []int{10}
+	return file_plugin_proto_rawDescGZIP(), []int{12}
 }
 
 func (x *SearchResponse) GetSuccess() bool {
@@ -730,7 +938,7 @@ type SearchResult struct {
 
 func (x *SearchResult) Reset() {
 	*x = SearchResult{}
-	mi := &file_plugin_proto_msgTypes[11]
+	mi := &file_plugin_proto_msgTypes[13]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -742,7 +950,7 @@ func (x *SearchResult) String() string {
 func (*SearchResult) ProtoMessage() {}
 
 func (x *SearchResult) ProtoReflect() protoreflect.Message {
-	mi := &file_plugin_proto_msgTypes[11]
+	mi := &file_plugin_proto_msgTypes[13]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -755,7 +963,7 @@ func (x *SearchResult) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use SearchResult.ProtoReflect.Descriptor instead.
 func (*SearchResult) Descriptor() ([]byte, []int) {
-	return file_plugin_proto_rawDescGZIP(), []int{11}
+	return file_plugin_proto_rawDescGZIP(), []int{13}
 }
 
 func (x *SearchResult) GetId() string {
@@ -808,7 +1016,7 @@ type GetSearchCapabilitiesRequest struct {
 
 func (x *GetSearchCapabilitiesRequest) Reset() {
 	*x = GetSearchCapabilitiesRequest{}
-	mi := &file_plugin_proto_msgTypes[12]
+	mi := &file_plugin_proto_msgTypes[14]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -820,7 +1028,7 @@ func (x *GetSearchCapabilitiesRequest) String() string {
 func (*GetSearchCapabilitiesRequest) ProtoMessage() {}
 
 func (x *GetSearchCapabilitiesRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_plugin_proto_msgTypes[12]
+	mi := &file_plugin_proto_msgTypes[14]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -833,7 +1041,7 @@ func (x *GetSearchCapabilitiesRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetSearchCapabilitiesRequest.ProtoReflect.Descriptor instead.
 func (*GetSearchCapabilitiesRequest) Descriptor() ([]byte, []int) {
-	return file_plugin_proto_rawDescGZIP(), []int{12}
+	return file_plugin_proto_rawDescGZIP(), []int{14}
 }
 
 type GetSearchCapabilitiesResponse struct {
@@ -847,7 +1055,7 @@ type GetSearchCapabilitiesResponse struct {
 
 func (x *GetSearchCapabilitiesResponse) Reset() {
 	*x = GetSearchCapabilitiesResponse{}
-	mi := &file_plugin_proto_msgTypes[13]
+	mi := &file_plugin_proto_msgTypes[15]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -859,7 +1067,7 @@ func (x *GetSearchCapabilitiesResponse) String() string {
 func (*GetSearchCapabilitiesResponse) ProtoMessage() {}
 
 func (x *GetSearchCapabilitiesResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_plugin_proto_msgTypes[13]
+	mi := &file_plugin_proto_msgTypes[15]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -872,7 +1080,7 @@ func (x *GetSearchCapabilitiesResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetSearchCapabilitiesResponse.ProtoReflect.Descriptor instead.
 func (*GetSearchCapabilitiesResponse) Descriptor() ([]byte, []int) {
-	return file_plugin_proto_rawDescGZIP(), []int{13}
+	return file_plugin_proto_rawDescGZIP(), []int{15}
 }
 
 func (x *GetSearchCapabilitiesResponse) GetSupportedFields() []string {
@@ -906,7 +1114,7 @@ type InitializeRequest struct {
 
 func (x *InitializeRequest) Reset() {
 	*x = InitializeRequest{}
-	mi := &file_plugin_proto_msgTypes[14]
+	mi := &file_plugin_proto_msgTypes[16]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -918,7 +1126,7 @@ func (x *InitializeRequest) String() string {
 func (*InitializeRequest) ProtoMessage() {}
 
 func (x *InitializeRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_plugin_proto_msgTypes[14]
+	mi := &file_plugin_proto_msgTypes[16]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -931,7 +1139,7 @@ func (x *InitializeRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use InitializeRequest.ProtoReflect.Descriptor instead.
 func (*InitializeRequest) Descriptor() ([]byte, []int) {
-	return file_plugin_proto_rawDescGZIP(), []int{14}
+	return file_plugin_proto_rawDescGZIP(), []int{16}
 }
 
 func (x *InitializeRequest) GetContext() *PluginContext {
@@ -951,7 +1159,7 @@ type InitializeResponse struct {
 
 func (x *InitializeResponse) Reset() {
 	*x = InitializeResponse{}
-	mi := &file_plugin_proto_msgTypes[15]
+	mi := &file_plugin_proto_msgTypes[17]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -963,7 +1171,7 @@ func (x *InitializeResponse) String() string {
 func (*InitializeResponse) ProtoMessage() {}
 
 func (x *InitializeResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_plugin_proto_msgTypes[15]
+	mi := &file_plugin_proto_msgTypes[17]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -976,7 +1184,7 @@ func (x *InitializeResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use InitializeResponse.ProtoReflect.Descriptor instead.
 func (*InitializeResponse) Descriptor() ([]byte, []int) {
-	return file_plugin_proto_rawDescGZIP(), []int{15}
+	return file_plugin_proto_rawDescGZIP(), []int{17}
 }
 
 func (x *InitializeResponse) GetSuccess() bool {
@@ -1001,7 +1209,7 @@ type StartRequest struct {
 
 func (x *StartRequest) Reset() {
 	*x = StartRequest{}
-	mi := &file_plugin_proto_msgTypes[16]
+	mi := &file_plugin_proto_msgTypes[18]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1013,7 +1221,7 @@ func (x *StartRequest) String() string {
 func (*StartRequest) ProtoMessage() {}
 
 func (x *StartRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_plugin_proto_msgTypes[16]
+	mi := &file_plugin_proto_msgTypes[18]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1026,7 +1234,7 @@ func (x *StartRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use StartRequest.ProtoReflect.Descriptor instead.
 func (*StartRequest) Descriptor() ([]byte, []int) {
-	return file_plugin_proto_rawDescGZIP(), []int{16}
+	return file_plugin_proto_rawDescGZIP(), []int{18}
 }
 
 type StartResponse struct {
@@ -1039,7 +1247,7 @@ type StartResponse struct {
 
 func (x *StartResponse) Reset() {
 	*x = StartResponse{}
-	mi := &file_plugin_proto_msgTypes[17]
+	mi := &file_plugin_proto_msgTypes[19]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1051,7 +1259,7 @@ func (x *StartResponse) String() string {
 func (*StartResponse) ProtoMessage() {}
 
 func (x *StartResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_plugin_proto_msgTypes[17]
+	mi := &file_plugin_proto_msgTypes[19]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1064,7 +1272,7 @@ func (x *StartResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use StartResponse.ProtoReflect.Descriptor instead.
 func (*StartResponse) Descriptor() ([]byte, []int) {
-	return file_plugin_proto_rawDescGZIP(), []int{17}
+	return file_plugin_proto_rawDescGZIP(), []int{19}
 }
 
 func (x *StartResponse) GetSuccess() bool {
@@ -1089,7 +1297,7 @@ type StopRequest struct {
 
 func (x *StopRequest) Reset() {
 	*x = StopRequest{}
-	mi := &file_plugin_proto_msgTypes[18]
+	mi := &file_plugin_proto_msgTypes[20]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1101,7 +1309,7 @@ func (x *StopRequest) String() string {
 func (*StopRequest) ProtoMessage() {}
 
 func (x *StopRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_plugin_proto_msgTypes[18]
+	mi := &file_plugin_proto_msgTypes[20]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1114,7 +1322,7 @@ func (x *StopRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use StopRequest.ProtoReflect.Descriptor instead.
 func (*StopRequest) Descriptor() ([]byte, []int) {
-	return file_plugin_proto_rawDescGZIP(), []int{18}
+	return file_plugin_proto_rawDescGZIP(), []int{20}
 }
 
 type StopResponse struct {
@@ -1127,7 +1335,7 @@ type StopResponse struct {
 
 func (x *StopResponse) Reset() {
 	*x = StopResponse{}
-	mi := &file_plugin_proto_msgTypes[19]
+	mi := &file_plugin_proto_msgTypes[21]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1139,7 +1347,7 @@ func (x *StopResponse) String() string {
 func (*StopResponse) ProtoMessage() {}
 
 func (x *StopResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_plugin_proto_msgTypes[19]
+	mi := &file_plugin_proto_msgTypes[21]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1152,7 +1360,7 @@ func (x *StopResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use StopResponse.ProtoReflect.Descriptor instead.
 func (*StopResponse) Descriptor() ([]byte, []int) {
-	return file_plugin_proto_rawDescGZIP(), []int{19}
+	return file_plugin_proto_rawDescGZIP(), []int{21}
 }
 
 func (x *StopResponse) GetSuccess() bool {
@@ -1177,7 +1385,7 @@ type InfoRequest struct {
 
 func (x *InfoRequest) Reset() {
 	*x = InfoRequest{}
-	mi := &file_plugin_proto_msgTypes[20]
+	mi := &file_plugin_proto_msgTypes[22]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1189,7 +1397,7 @@ func (x *InfoRequest) String() string {
 func (*InfoRequest) ProtoMessage() {}
 
 func (x *InfoRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_plugin_proto_msgTypes[20]
+	mi := &file_plugin_proto_msgTypes[22]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1202,7 +1410,7 @@ func (x *InfoRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use InfoRequest.ProtoReflect.Descriptor instead.
 func (*InfoRequest) Descriptor() ([]byte, []int) {
-	return file_plugin_proto_rawDescGZIP(), []int{20}
+	return file_plugin_proto_rawDescGZIP(), []int{22}
 }
 
 type InfoResponse struct {
@@ -1214,7 +1422,7 @@ type InfoResponse struct {
 
 func (x *InfoResponse) Reset() {
 	*x = InfoResponse{}
-	mi := &file_plugin_proto_msgTypes[21]
+	mi := &file_plugin_proto_msgTypes[23]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1226,7 +1434,7 @@ func (x *InfoResponse) String() string {
 func (*InfoResponse) ProtoMessage() {}
 
 func (x *InfoResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_plugin_proto_msgTypes[21]
+	mi := &file_plugin_proto_msgTypes[23]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1239,7 +1447,7 @@ func (x *InfoResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use InfoResponse.ProtoReflect.Descriptor instead.
 func (*InfoResponse) Descriptor() ([]byte, []int) {
-	return file_plugin_proto_rawDescGZIP(), []int{21}
+	return file_plugin_proto_rawDescGZIP(), []int{23}
 }
 
 func (x *InfoResponse) GetInfo() *PluginInfo {
@@ -1257,7 +1465,7 @@ type HealthRequest struct {
 
 func (x *HealthRequest) Reset() {
 	*x = HealthRequest{}
-	mi := &file_plugin_proto_msgTypes[22]
+	mi := &file_plugin_proto_msgTypes[24]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1269,7 +1477,7 @@ func (x *HealthRequest) String() string {
 func (*HealthRequest) ProtoMessage() {}
 
 func (x *HealthRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_plugin_proto_msgTypes[22]
+	mi := &file_plugin_proto_msgTypes[24]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1282,7 +1490,7 @@ func (x *HealthRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use HealthRequest.ProtoReflect.Descriptor instead.
 func (*HealthRequest) Descriptor() ([]byte, []int) {
-	return file_plugin_proto_rawDescGZIP(), []int{22}
+	return file_plugin_proto_rawDescGZIP(), []int{24}
 }
 
 type HealthResponse struct {
@@ -1295,7 +1503,7 @@ type HealthResponse struct {
 
 func (x *HealthResponse) Reset() {
 	*x = HealthResponse{}
-	mi := &file_plugin_proto_msgTypes[23]
+	mi := &file_plugin_proto_msgTypes[25]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1307,7 +1515,7 @@ func (x *HealthResponse) String() string {
 func (*HealthResponse) ProtoMessage() {}
 
 func (x *HealthResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_plugin_proto_msgTypes[23]
+	mi := &file_plugin_proto_msgTypes[25]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1320,7 +1528,7 @@ func (x *HealthResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use HealthResponse.ProtoReflect.Descriptor instead.
 func (*HealthResponse) Descriptor() ([]byte, []int) {
-	return file_plugin_proto_rawDescGZIP(), []int{23}
+	return file_plugin_proto_rawDescGZIP(), []int{25}
 }
 
 func (x *HealthResponse) GetHealthy() bool {
@@ -1348,7 +1556,7 @@ type CanHandleRequest struct {
 
 func (x *CanHandleRequest) Reset() {
 	*x = CanHandleRequest{}
-	mi := &file_plugin_proto_msgTypes[24]
+	mi := &file_plugin_proto_msgTypes[26]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1360,7 +1568,7 @@ func (x *CanHandleRequest) String() string {
 func (*CanHandleRequest) ProtoMessage() {}
 
 func (x *CanHandleRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_plugin_proto_msgTypes[24]
+	mi := &file_plugin_proto_msgTypes[26]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1373,7 +1581,7 @@ func (x *CanHandleRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CanHandleRequest.ProtoReflect.Descriptor instead.
 func (*CanHandleRequest) Descriptor() ([]byte, []int) {
-	return file_plugin_proto_rawDescGZIP(), []int{24}
+	return file_plugin_proto_rawDescGZIP(), []int{26}
 }
 
 func (x *CanHandleRequest) GetFilePath() string {
@@ -1399,7 +1607,7 @@ type CanHandleResponse struct {
 
 func (x *CanHandleResponse) Reset() {
 	*x = CanHandleResponse{}
-	mi := &file_plugin_proto_msgTypes[25]
+	mi := &file_plugin_proto_msgTypes[27]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1411,7 +1619,7 @@ func (x *CanHandleResponse) String() string {
 func (*CanHandleResponse) ProtoMessage() {}
 
 func (x *CanHandleResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_plugin_proto_msgTypes[25]
+	mi := &file_plugin_proto_msgTypes[27]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1424,7 +1632,7 @@ func (x *CanHandleResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CanHandleResponse.ProtoReflect.Descriptor instead.
 func (*CanHandleResponse) Descriptor() ([]byte, []int) {
-	return file_plugin_proto_rawDescGZIP(), []int{25}
+	return file_plugin_proto_rawDescGZIP(), []int{27}
 }
 
 func (x *CanHandleResponse) GetCanHandle() bool {
@@ -1443,7 +1651,7 @@ type ExtractMetadataRequest struct {
 
 func (x *ExtractMetadataRequest) Reset() {
 	*x = ExtractMetadataRequest{}
-	mi := &file_plugin_proto_msgTypes[26]
+	mi := &file_plugin_proto_msgTypes[28]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1455,7 +1663,7 @@ func (x *ExtractMetadataRequest) String() string {
 func (*ExtractMetadataRequest) ProtoMessage() {}
 
 func (x *ExtractMetadataRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_plugin_proto_msgTypes[26]
+	mi := &file_plugin_proto_msgTypes[28]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1468,7 +1676,7 @@ func (x *ExtractMetadataRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ExtractMetadataRequest.ProtoReflect.Descriptor instead.
 func (*ExtractMetadataRequest) Descriptor() ([]byte, []int) {
-	return file_plugin_proto_rawDescGZIP(), []int{26}
+	return file_plugin_proto_rawDescGZIP(), []int{28}
 }
 
 func (x *ExtractMetadataRequest) GetFilePath() string {
@@ -1488,7 +1696,7 @@ type ExtractMetadataResponse struct {
 
 func (x *ExtractMetadataResponse) Reset() {
 	*x = ExtractMetadataResponse{}
-	mi := &file_plugin_proto_msgTypes[27]
+	mi := &file_plugin_proto_msgTypes[29]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1500,7 +1708,7 @@ func (x *ExtractMetadataResponse) String() string {
 func (*ExtractMetadataResponse) ProtoMessage() {}
 
 func (x *ExtractMetadataResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_plugin_proto_msgTypes[27]
+	mi := &file_plugin_proto_msgTypes[29]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1513,7 +1721,7 @@ func (x *ExtractMetadataResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ExtractMetadataResponse.ProtoReflect.Descriptor instead.
 func (*ExtractMetadataResponse) Descriptor() ([]byte, []int) {
-	return file_plugin_proto_rawDescGZIP(), []int{27}
+	return file_plugin_proto_rawDescGZIP(), []int{29}
 }
 
 func (x *ExtractMetadataResponse) GetMetadata() map[string]string {
@@ -1538,7 +1746,7 @@ type GetSupportedTypesRequest struct {
 
 func (x *GetSupportedTypesRequest) Reset() {
 	*x = GetSupportedTypesRequest{}
-	mi := &file_plugin_proto_msgTypes[28]
+	mi := &file_plugin_proto_msgTypes[30]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1550,7 +1758,7 @@ func (x *GetSupportedTypesRequest) String() string {
 func (*GetSupportedTypesRequest) ProtoMessage() {}
 
 func (x *GetSupportedTypesRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_plugin_proto_msgTypes[28]
+	mi := &file_plugin_proto_msgTypes[30]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1563,7 +1771,7 @@ func (x *GetSupportedTypesRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetSupportedTypesRequest.ProtoReflect.Descriptor instead.
 func (*GetSupportedTypesRequest) Descriptor() ([]byte, []int) {
-	return file_plugin_proto_rawDescGZIP(), []int{28}
+	return file_plugin_proto_rawDescGZIP(), []int{30}
 }
 
 type GetSupportedTypesResponse struct {
@@ -1575,7 +1783,7 @@ type GetSupportedTypesResponse struct {
 
 func (x *GetSupportedTypesResponse) Reset() {
 	*x = GetSupportedTypesResponse{}
-	mi := &file_plugin_proto_msgTypes[29]
+	mi := &file_plugin_proto_msgTypes[31]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1587,7 +1795,7 @@ func (x *GetSupportedTypesResponse) String() string {
 func (*GetSupportedTypesResponse) ProtoMessage() {}
 
 func (x *GetSupportedTypesResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_plugin_proto_msgTypes[29]
+	mi := &file_plugin_proto_msgTypes[31]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1600,7 +1808,7 @@ func (x *GetSupportedTypesResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetSupportedTypesResponse.ProtoReflect.Descriptor instead.
 func (*GetSupportedTypesResponse) Descriptor() ([]byte, []int) {
-	return file_plugin_proto_rawDescGZIP(), []int{29}
+	return file_plugin_proto_rawDescGZIP(), []int{31}
 }
 
 func (x *GetSupportedTypesResponse) GetTypes() []string {
@@ -1622,7 +1830,7 @@ type OnMediaFileScannedRequest struct {
 
 func (x *OnMediaFileScannedRequest) Reset() {
 	*x = OnMediaFileScannedRequest{}
-	mi := &file_plugin_proto_msgTypes[30]
+	mi := &file_plugin_proto_msgTypes[32]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1634,7 +1842,7 @@ func (x *OnMediaFileScannedRequest) String() string {
 func (*OnMediaFileScannedRequest) ProtoMessage() {}
 
 func (x *OnMediaFileScannedRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_plugin_proto_msgTypes[30]
+	mi := &file_plugin_proto_msgTypes[32]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1647,7 +1855,7 @@ func (x *OnMediaFileScannedRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use OnMediaFileScannedRequest.ProtoReflect.Descriptor instead.
 func (*OnMediaFileScannedRequest) Descriptor() ([]byte, []int) {
-	return file_plugin_proto_rawDescGZIP(), []int{30}
+	return file_plugin_proto_rawDescGZIP(), []int{32}
 }
 
 func (x *OnMediaFileScannedRequest) GetMediaFileId() string {
@@ -1671,15 +1879,20 @@ func (x *OnMediaFileScannedRequest) GetMetadata() map[string]string {
 	return nil
 }
 
+// Outcome of a scanner hook for one file. Plugins predating it leave status
+// empty, which the host treats as processed.
 type OnMediaFileScannedResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
+	Status        string                 `protobuf:"bytes,1,opt,name=status,proto3" json:"status,omitempty"` // processed, skipped
+	Reason        string                 `protobuf:"bytes,2,opt,name=reason,proto3" json:"reason,omitempty"` // Why the file was skipped, e.g. disabled, unsupported_type, missing_metadata
+	Detail        string                 `protobuf:"bytes,3,opt,name=detail,proto3" json:"detail,omitempty"` // Human-readable detail for the skip
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *OnMediaFileScannedResponse) Reset() {
 	*x = OnMediaFileScannedResponse{}
-	mi := &file_plugin_proto_msgTypes[31]
+	mi := &file_plugin_proto_msgTypes[33]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1691,7 +1904,7 @@ func (x *OnMediaFileScannedResponse) String() string {
 func (*OnMediaFileScannedResponse) ProtoMessage() {}
 
 func (x *OnMediaFileScannedResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_plugin_proto_msgTypes[31]
+	mi := &file_plugin_proto_msgTypes[33]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1704,7 +1917,28 @@ func (x *OnMediaFileScannedResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use OnMediaFileScannedResponse.ProtoReflect.Descriptor instead.
 func (*OnMediaFileScannedResponse) Descriptor() ([]byte, []int) {
-	return file_plugin_proto_rawDescGZIP(), []int{31}
+	return file_plugin_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *OnMediaFileScannedResponse) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *OnMediaFileScannedResponse) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *OnMediaFileScannedResponse) GetDetail() string {
+	if x != nil {
+		return x.Detail
+	}
+	return ""
 }
 
 type OnScanStartedRequest struct {
@@ -1718,7 +1952,7 @@ type OnScanStartedRequest struct {
 
 func (x *OnScanStartedRequest) Reset() {
 	*x = OnScanStartedRequest{}
-	mi := &file_plugin_proto_msgTypes[32]
+	mi := &file_plugin_proto_msgTypes[34]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1730,7 +1964,7 @@ func (x *OnScanStartedRequest) String() string {
 func (*OnScanStartedRequest) ProtoMessage() {}
 
 func (x *OnScanStartedRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_plugin_proto_msgTypes[32]
+	mi := &file_plugin_proto_msgTypes[34]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1743,7 +1977,7 @@ func (x *OnScanStartedRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use OnScanStartedRequest.ProtoReflect.Descriptor instead.
 func (*OnScanStartedRequest) Descriptor() ([]byte, []int) {
-	return file_plugin_proto_rawDescGZIP(), []int{32}
+	return file_plugin_proto_rawDescGZIP(), []int{34}
 }
 
 func (x *OnScanStartedRequest) GetScanJobId() uint32 {
@@ -1775,7 +2009,7 @@ type OnScanStartedResponse struct {
 
 func (x *OnScanStartedResponse) Reset() {
 	*x = OnScanStartedResponse{}
-	mi := &file_plugin_proto_msgTypes[33]
+	mi := &file_plugin_proto_msgTypes[35]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1787,7 +2021,7 @@ func (x *OnScanStartedResponse) String() string {
 func (*OnScanStartedResponse) ProtoMessage() {}
 
 func (x *OnScanStartedResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_plugin_proto_msgTypes[33]
+	mi := &file_plugin_proto_msgTypes[35]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1800,7 +2034,7 @@ func (x *OnScanStartedResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use OnScanStartedResponse.ProtoReflect.Descriptor instead.
 func (*OnScanStartedResponse) Descriptor() ([]byte, []int) {
-	return file_plugin_proto_rawDescGZIP(), []int{33}
+	return file_plugin_proto_rawDescGZIP(), []int{35}
 }
 
 type OnScanCompletedRequest struct {
@@ -1814,7 +2048,7 @@ type OnScanCompletedRequest struct {
 
 func (x *OnScanCompletedRequest) Reset() {
 	*x = OnScanCompletedRequest{}
-	mi := &file_plugin_proto_msgTypes[34]
+	mi := &file_plugin_proto_msgTypes[36]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1826,7 +2060,7 @@ func (x *OnScanCompletedRequest) String() string {
 func (*OnScanCompletedRequest) ProtoMessage() {}
 
 func (x *OnScanCompletedRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_plugin_proto_msgTypes[34]
+	mi := &file_plugin_proto_msgTypes[36]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1839,7 +2073,7 @@ func (x *OnScanCompletedRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use OnScanCompletedRequest.ProtoReflect.Descriptor instead.
 func (*OnScanCompletedRequest) Descriptor() ([]byte, []int) {
-	return file_plugin_proto_rawDescGZIP(), []int{34}
+	return file_plugin_proto_rawDescGZIP(), []int{36}
 }
 
 func (x *OnScanCompletedRequest) GetScanJobId() uint32 {
@@ -1871,7 +2105,7 @@ type OnScanCompletedResponse struct {
 
 func (x *OnScanCompletedResponse) Reset() {
 	*x = OnScanCompletedResponse{}
-	mi := &file_plugin_proto_msgTypes[35]
+	mi := &file_plugin_proto_msgTypes[37]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1883,7 +2117,7 @@ func (x *OnScanCompletedResponse) String() string {
 func (*OnScanCompletedResponse) ProtoMessage() {}
 
 func (x *OnScanCompletedResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_plugin_proto_msgTypes[35]
+	mi := &file_plugin_proto_msgTypes[37]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1896,31 +2130,36 @@ func (x *OnScanCompletedResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use OnScanCompletedResponse.ProtoReflect.Descriptor instead.
 func (*OnScanCompletedResponse) Descriptor() ([]byte, []int) {
-	return file_plugin_proto_rawDescGZIP(), []int{35}
+	return file_plugin_proto_rawDescGZIP(), []int{37}
 }
 
-// Database messages
-type GetModelsRequest struct {
+// Sent before a media file's row is deleted, so plugins can drop their
+// enrichment rows and assets for it. metadata is the file's last known
+// hook metadata.
+type OnMediaFileRemovedRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
+	MediaFileId   string                 `protobuf:"bytes,1,opt,name=media_file_id,json=mediaFileId,proto3" json:"media_file_id,omitempty"`
+	FilePath      string                 `protobuf:"bytes,2,opt,name=file_path,json=filePath,proto3" json:"file_path,omitempty"`
+	Metadata      map[string]string      `protobuf:"bytes,3,rep,name=metadata,proto3" json:"metadata,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetModelsRequest) Reset() {
-	*x = GetModelsRequest{}
-	mi := &file_plugin_proto_msgTypes[36]
+func (x *OnMediaFileRemovedRequest) Reset() {
+	*x = OnMediaFileRemovedRequest{}
+	mi := &file_plugin_proto_msgTypes[38]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetModelsRequest) String() string {
+func (x *OnMediaFileRemovedRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetModelsRequest) ProtoMessage() {}
+func (*OnMediaFileRemovedRequest) ProtoMessage() {}
 
-func (x *GetModelsRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_plugin_proto_msgTypes[36]
+func (x *OnMediaFileRemovedRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_plugin_proto_msgTypes[38]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1931,33 +2170,53 @@ func (x *GetModelsRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetModelsRequest.ProtoReflect.Descriptor instead.
-func (*GetModelsRequest) Descriptor() ([]byte, []int) {
-	return file_plugin_proto_rawDescGZIP(), []int{36}
+// Deprecated: Use OnMediaFileRemovedRequest.ProtoReflect.Descriptor instead.
+func (*OnMediaFileRemovedRequest) Descriptor() ([]byte, []int) {
+	return file_plugin_proto_rawDescGZIP(), []int{38}
 }
 
-type GetModelsResponse struct {
+func (x *OnMediaFileRemovedRequest) GetMediaFileId() string {
+	if x != nil {
+		return x.MediaFileId
+	}
+	return ""
+}
+
+func (x *OnMediaFileRemovedRequest) GetFilePath() string {
+	if x != nil {
+		return x.FilePath
+	}
+	return ""
+}
+
+func (x *OnMediaFileRemovedRequest) GetMetadata() map[string]string {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+type OnMediaFileRemovedResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	ModelNames    []string               `protobuf:"bytes,1,rep,name=model_names,json=modelNames,proto3" json:"model_names,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetModelsResponse) Reset() {
-	*x = GetModelsResponse{}
-	mi := &file_plugin_proto_msgTypes[37]
+func (x *OnMediaFileRemovedResponse) Reset() {
+	*x = OnMediaFileRemovedResponse{}
+	mi := &file_plugin_proto_msgTypes[39]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetModelsResponse) String() string {
+func (x *OnMediaFileRemovedResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetModelsResponse) ProtoMessage() {}
+func (*OnMediaFileRemovedResponse) ProtoMessage() {}
 
-func (x *GetModelsResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_plugin_proto_msgTypes[37]
+func (x *OnMediaFileRemovedResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_plugin_proto_msgTypes[39]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1968,40 +2227,37 @@ func (x *GetModelsResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetModelsResponse.ProtoReflect.Descriptor instead.
-func (*GetModelsResponse) Descriptor() ([]byte, []int) {
-	return file_plugin_proto_rawDescGZIP(), []int{37}
-}
-
-func (x *GetModelsResponse) GetModelNames() []string {
-	if x != nil {
-		return x.ModelNames
-	}
-	return nil
+// Deprecated: Use OnMediaFileRemovedResponse.ProtoReflect.Descriptor instead.
+func (*OnMediaFileRemovedResponse) Descriptor() ([]byte, []int) {
+	return file_plugin_proto_rawDescGZIP(), []int{39}
 }
 
-type MigrateRequest struct {
-	state            protoimpl.MessageState `protogen:"open.v1"`
-	ConnectionString string                 `protobuf:"bytes,1,opt,name=connection_string,json=connectionString,proto3" json:"connection_string,omitempty"`
-	unknownFields    protoimpl.UnknownFields
-	sizeCache        protoimpl.SizeCache
+// Sent when an existing media file changes on disk (replaced or retagged).
+// The media file ID is unchanged.
+type OnMediaFileUpdatedRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	MediaFileId   string                 `protobuf:"bytes,1,opt,name=media_file_id,json=mediaFileId,proto3" json:"media_file_id,omitempty"`
+	FilePath      string                 `protobuf:"bytes,2,opt,name=file_path,json=filePath,proto3" json:"file_path,omitempty"`
+	Metadata      map[string]string      `protobuf:"bytes,3,rep,name=metadata,proto3" json:"metadata,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
-func (x *MigrateRequest) Reset() {
-	*x = MigrateRequest{}
-	mi := &file_plugin_proto_msgTypes[38]
+func (x *OnMediaFileUpdatedRequest) Reset() {
+	*x = OnMediaFileUpdatedRequest{}
+	mi := &file_plugin_proto_msgTypes[40]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *MigrateRequest) String() string {
+func (x *OnMediaFileUpdatedRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*MigrateRequest) ProtoMessage() {}
+func (*OnMediaFileUpdatedRequest) ProtoMessage() {}
 
-func (x *MigrateRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_plugin_proto_msgTypes[38]
+func (x *OnMediaFileUpdatedRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_plugin_proto_msgTypes[40]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2012,41 +2268,56 @@ func (x *MigrateRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use MigrateRequest.ProtoReflect.Descriptor instead.
-func (*MigrateRequest) Descriptor() ([]byte, []int) {
-	return file_plugin_proto_rawDescGZIP(), []int{38}
+// Deprecated: Use OnMediaFileUpdatedRequest.ProtoReflect.Descriptor instead.
+func (*OnMediaFileUpdatedRequest) Descriptor() ([]byte, []int) {
+	return file_plugin_proto_rawDescGZIP(), []int{40}
 }
 
-func (x *MigrateRequest) GetConnectionString() string {
+func (x *OnMediaFileUpdatedRequest) GetMediaFileId() string {
 	if x != nil {
-		return x.ConnectionString
+		return x.MediaFileId
 	}
 	return ""
 }
 
-type MigrateResponse struct {
+func (x *OnMediaFileUpdatedRequest) GetFilePath() string {
+	if x != nil {
+		return x.FilePath
+	}
+	return ""
+}
+
+func (x *OnMediaFileUpdatedRequest) GetMetadata() map[string]string {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+type OnMediaFileUpdatedResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
-	Error         string                 `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
+	Status        string                 `protobuf:"bytes,1,opt,name=status,proto3" json:"status,omitempty"` // processed, skipped
+	Reason        string                 `protobuf:"bytes,2,opt,name=reason,proto3" json:"reason,omitempty"`
+	Detail        string                 `protobuf:"bytes,3,opt,name=detail,proto3" json:"detail,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *MigrateResponse) Reset() {
-	*x = MigrateResponse{}
-	mi := &file_plugin_proto_msgTypes[39]
+func (x *OnMediaFileUpdatedResponse) Reset() {
+	*x = OnMediaFileUpdatedResponse{}
+	mi := &file_plugin_proto_msgTypes[41]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *MigrateResponse) String() string {
+func (x *OnMediaFileUpdatedResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*MigrateResponse) ProtoMessage() {}
+func (*OnMediaFileUpdatedResponse) ProtoMessage() {}
 
-func (x *MigrateResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_plugin_proto_msgTypes[39]
+func (x *OnMediaFileUpdatedResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_plugin_proto_msgTypes[41]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2057,47 +2328,56 @@ func (x *MigrateResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use MigrateResponse.ProtoReflect.Descriptor instead.
-func (*MigrateResponse) Descriptor() ([]byte, []int) {
-	return file_plugin_proto_rawDescGZIP(), []int{39}
+// Deprecated: Use OnMediaFileUpdatedResponse.ProtoReflect.Descriptor instead.
+func (*OnMediaFileUpdatedResponse) Descriptor() ([]byte, []int) {
+	return file_plugin_proto_rawDescGZIP(), []int{41}
 }
 
-func (x *MigrateResponse) GetSuccess() bool {
+func (x *OnMediaFileUpdatedResponse) GetStatus() string {
 	if x != nil {
-		return x.Success
+		return x.Status
 	}
-	return false
+	return ""
 }
 
-func (x *MigrateResponse) GetError() string {
+func (x *OnMediaFileUpdatedResponse) GetReason() string {
 	if x != nil {
-		return x.Error
+		return x.Reason
 	}
 	return ""
 }
 
-type RollbackRequest struct {
-	state            protoimpl.MessageState `protogen:"open.v1"`
-	ConnectionString string                 `protobuf:"bytes,1,opt,name=connection_string,json=connectionString,proto3" json:"connection_string,omitempty"`
-	unknownFields    protoimpl.UnknownFields
-	sizeCache        protoimpl.SizeCache
+func (x *OnMediaFileUpdatedResponse) GetDetail() string {
+	if x != nil {
+		return x.Detail
+	}
+	return ""
 }
 
-func (x *RollbackRequest) Reset() {
-	*x = RollbackRequest{}
-	mi := &file_plugin_proto_msgTypes[40]
+// A batch of scanned files, sent instead of one OnMediaFileScanned call per
+// file to plugins declaring the batch_scanner_hooks capability
+type OnMediaFilesScannedRequest struct {
+	state         protoimpl.MessageState       `protogen:"open.v1"`
+	Files         []*OnMediaFileScannedRequest `protobuf:"bytes,1,rep,name=files,proto3" json:"files,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OnMediaFilesScannedRequest) Reset() {
+	*x = OnMediaFilesScannedRequest{}
+	mi := &file_plugin_proto_msgTypes[42]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *RollbackRequest) String() string {
+func (x *OnMediaFilesScannedRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*RollbackRequest) ProtoMessage() {}
+func (*OnMediaFilesScannedRequest) ProtoMessage() {}
 
-func (x *RollbackRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_plugin_proto_msgTypes[40]
+func (x *OnMediaFilesScannedRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_plugin_proto_msgTypes[42]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2108,41 +2388,40 @@ func (x *RollbackRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use RollbackRequest.ProtoReflect.Descriptor instead.
-func (*RollbackRequest) Descriptor() ([]byte, []int) {
-	return file_plugin_proto_rawDescGZIP(), []int{40}
+// Deprecated: Use OnMediaFilesScannedRequest.ProtoReflect.Descriptor instead.
+func (*OnMediaFilesScannedRequest) Descriptor() ([]byte, []int) {
+	return file_plugin_proto_rawDescGZIP(), []int{42}
 }
 
-func (x *RollbackRequest) GetConnectionString() string {
+func (x *OnMediaFilesScannedRequest) GetFiles() []*OnMediaFileScannedRequest {
 	if x != nil {
-		return x.ConnectionString
+		return x.Files
 	}
-	return ""
+	return nil
 }
 
-type RollbackResponse struct {
+type OnMediaFilesScannedResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
-	Error         string                 `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
+	Results       []*MediaFileHookResult `protobuf:"bytes,1,rep,name=results,proto3" json:"results,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *RollbackResponse) Reset() {
-	*x = RollbackResponse{}
-	mi := &file_plugin_proto_msgTypes[41]
+func (x *OnMediaFilesScannedResponse) Reset() {
+	*x = OnMediaFilesScannedResponse{}
+	mi := &file_plugin_proto_msgTypes[43]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *RollbackResponse) String() string {
+func (x *OnMediaFilesScannedResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*RollbackResponse) ProtoMessage() {}
+func (*OnMediaFilesScannedResponse) ProtoMessage() {}
 
-func (x *RollbackResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_plugin_proto_msgTypes[41]
+func (x *OnMediaFilesScannedResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_plugin_proto_msgTypes[43]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2153,47 +2432,44 @@ func (x *RollbackResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use RollbackResponse.ProtoReflect.Descriptor instead.
-func (*RollbackResponse) Descriptor() ([]byte, []int) {
-	return file_plugin_proto_rawDescGZIP(), []int{41}
-}
-
-func (x *RollbackResponse) GetSuccess() bool {
-	if x != nil {
-		return x.Success
-	}
-	return false
+// Deprecated: Use OnMediaFilesScannedResponse.ProtoReflect.Descriptor instead.
+func (*OnMediaFilesScannedResponse) Descriptor() ([]byte, []int) {
+	return file_plugin_proto_rawDescGZIP(), []int{43}
 }
 
-func (x *RollbackResponse) GetError() string {
+func (x *OnMediaFilesScannedResponse) GetResults() []*MediaFileHookResult {
 	if x != nil {
-		return x.Error
+		return x.Results
 	}
-	return ""
+	return nil
 }
 
-// Admin page messages
-type GetAdminPagesRequest struct {
+// Outcome of a scanner hook for one file of a batch
+type MediaFileHookResult struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
+	MediaFileId   string                 `protobuf:"bytes,1,opt,name=media_file_id,json=mediaFileId,proto3" json:"media_file_id,omitempty"`
+	Status        string                 `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"` // processed, skipped, failed
+	Reason        string                 `protobuf:"bytes,3,opt,name=reason,proto3" json:"reason,omitempty"`
+	Detail        string                 `protobuf:"bytes,4,opt,name=detail,proto3" json:"detail,omitempty"` // Skip detail or error message
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetAdminPagesRequest) Reset() {
-	*x = GetAdminPagesRequest{}
-	mi := &file_plugin_proto_msgTypes[42]
+func (x *MediaFileHookResult) Reset() {
+	*x = MediaFileHookResult{}
+	mi := &file_plugin_proto_msgTypes[44]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetAdminPagesRequest) String() string {
+func (x *MediaFileHookResult) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetAdminPagesRequest) ProtoMessage() {}
+func (*MediaFileHookResult) ProtoMessage() {}
 
-func (x *GetAdminPagesRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_plugin_proto_msgTypes[42]
+func (x *MediaFileHookResult) ProtoReflect() protoreflect.Message {
+	mi := &file_plugin_proto_msgTypes[44]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2204,33 +2480,61 @@ func (x *GetAdminPagesRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetAdminPagesRequest.ProtoReflect.Descriptor instead.
-func (*GetAdminPagesRequest) Descriptor() ([]byte, []int) {
-	return file_plugin_proto_rawDescGZIP(), []int{42}
+// Deprecated: Use MediaFileHookResult.ProtoReflect.Descriptor instead.
+func (*MediaFileHookResult) Descriptor() ([]byte, []int) {
+	return file_plugin_proto_rawDescGZIP(), []int{44}
 }
 
-type GetAdminPagesResponse struct {
+func (x *MediaFileHookResult) GetMediaFileId() string {
+	if x != nil {
+		return x.MediaFileId
+	}
+	return ""
+}
+
+func (x *MediaFileHookResult) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *MediaFileHookResult) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *MediaFileHookResult) GetDetail() string {
+	if x != nil {
+		return x.Detail
+	}
+	return ""
+}
+
+// Database messages
+type GetModelsRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Pages         []*AdminPageConfig     `protobuf:"bytes,1,rep,name=pages,proto3" json:"pages,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetAdminPagesResponse) Reset() {
-	*x = GetAdminPagesResponse{}
-	mi := &file_plugin_proto_msgTypes[43]
+func (x *GetModelsRequest) Reset() {
+	*x = GetModelsRequest{}
+	mi := &file_plugin_proto_msgTypes[45]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetAdminPagesResponse) String() string {
+func (x *GetModelsRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetAdminPagesResponse) ProtoMessage() {}
+func (*GetModelsRequest) ProtoMessage() {}
 
-func (x *GetAdminPagesResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_plugin_proto_msgTypes[43]
+func (x *GetModelsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_plugin_proto_msgTypes[45]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2241,40 +2545,77 @@ func (x *GetAdminPagesResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetAdminPagesResponse.ProtoReflect.Descriptor instead.
-func (*GetAdminPagesResponse) Descriptor() ([]byte, []int) {
-	return file_plugin_proto_rawDescGZIP(), []int{43}
+// Deprecated: Use GetModelsRequest.ProtoReflect.Descriptor instead.
+func (*GetModelsRequest) Descriptor() ([]byte, []int) {
+	return file_plugin_proto_rawDescGZIP(), []int{45}
 }
 
-func (x *GetAdminPagesResponse) GetPages() []*AdminPageConfig {
+type GetModelsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ModelNames    []string               `protobuf:"bytes,1,rep,name=model_names,json=modelNames,proto3" json:"model_names,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetModelsResponse) Reset() {
+	*x = GetModelsResponse{}
+	mi := &file_plugin_proto_msgTypes[46]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetModelsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetModelsResponse) ProtoMessage() {}
+
+func (x *GetModelsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_plugin_proto_msgTypes[46]
 	if x != nil {
-		return x.Pages
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetModelsResponse.ProtoReflect.Descriptor instead.
+func (*GetModelsResponse) Descriptor() ([]byte, []int) {
+	return file_plugin_proto_rawDescGZIP(), []int{46}
+}
+
+func (x *GetModelsResponse) GetModelNames() []string {
+	if x != nil {
+		return x.ModelNames
 	}
 	return nil
 }
 
-type RegisterRoutesRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	BasePath      string                 `protobuf:"bytes,1,opt,name=base_path,json=basePath,proto3" json:"base_path,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+type MigrateRequest struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	ConnectionString string                 `protobuf:"bytes,1,opt,name=connection_string,json=connectionString,proto3" json:"connection_string,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
 }
 
-func (x *RegisterRoutesRequest) Reset() {
-	*x = RegisterRoutesRequest{}
-	mi := &file_plugin_proto_msgTypes[44]
+func (x *MigrateRequest) Reset() {
+	*x = MigrateRequest{}
+	mi := &file_plugin_proto_msgTypes[47]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *RegisterRoutesRequest) String() string {
+func (x *MigrateRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*RegisterRoutesRequest) ProtoMessage() {}
+func (*MigrateRequest) ProtoMessage() {}
 
-func (x *RegisterRoutesRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_plugin_proto_msgTypes[44]
+func (x *MigrateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_plugin_proto_msgTypes[47]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2285,19 +2626,19 @@ func (x *RegisterRoutesRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use RegisterRoutesRequest.ProtoReflect.Descriptor instead.
-func (*RegisterRoutesRequest) Descriptor() ([]byte, []int) {
-	return file_plugin_proto_rawDescGZIP(), []int{44}
+// Deprecated: Use MigrateRequest.ProtoReflect.Descriptor instead.
+func (*MigrateRequest) Descriptor() ([]byte, []int) {
+	return file_plugin_proto_rawDescGZIP(), []int{47}
 }
 
-func (x *RegisterRoutesRequest) GetBasePath() string {
+func (x *MigrateRequest) GetConnectionString() string {
 	if x != nil {
-		return x.BasePath
+		return x.ConnectionString
 	}
 	return ""
 }
 
-type RegisterRoutesResponse struct {
+type MigrateResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
 	Error         string                 `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
@@ -2305,21 +2646,21 @@ type RegisterRoutesResponse struct {
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *RegisterRoutesResponse) Reset() {
-	*x = RegisterRoutesResponse{}
-	mi := &file_plugin_proto_msgTypes[45]
+func (x *MigrateResponse) Reset() {
+	*x = MigrateResponse{}
+	mi := &file_plugin_proto_msgTypes[48]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *RegisterRoutesResponse) String() string {
+func (x *MigrateResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*RegisterRoutesResponse) ProtoMessage() {}
+func (*MigrateResponse) ProtoMessage() {}
 
-func (x *RegisterRoutesResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_plugin_proto_msgTypes[45]
+func (x *MigrateResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_plugin_proto_msgTypes[48]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2330,54 +2671,47 @@ func (x *RegisterRoutesResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use RegisterRoutesResponse.ProtoReflect.Descriptor instead.
-func (*RegisterRoutesResponse) Descriptor() ([]byte, []int) {
-	return file_plugin_proto_rawDescGZIP(), []int{45}
+// Deprecated: Use MigrateResponse.ProtoReflect.Descriptor instead.
+func (*MigrateResponse) Descriptor() ([]byte, []int) {
+	return file_plugin_proto_rawDescGZIP(), []int{48}
 }
 
-func (x *RegisterRoutesResponse) GetSuccess() bool {
+func (x *MigrateResponse) GetSuccess() bool {
 	if x != nil {
 		return x.Success
 	}
 	return false
 }
 
-func (x *RegisterRoutesResponse) GetError() string {
+func (x *MigrateResponse) GetError() string {
 	if x != nil {
 		return x.Error
 	}
 	return ""
 }
 
-// Common data structures
-type PluginContext struct {
-	state           protoimpl.MessageState `protogen:"open.v1"`
-	PluginId        string                 `protobuf:"bytes,1,opt,name=plugin_id,json=pluginId,proto3" json:"plugin_id,omitempty"`
-	Config          map[string]string      `protobuf:"bytes,2,rep,name=config,proto3" json:"config,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
-	LogLevel        string                 `protobuf:"bytes,3,opt,name=log_level,json=logLevel,proto3" json:"log_level,omitempty"`
-	DatabaseUrl     string                 `protobuf:"bytes,4,opt,name=database_url,json=databaseUrl,proto3" json:"database_url,omitempty"`
-	BasePath        string                 `protobuf:"bytes,5,opt,name=base_path,json=basePath,proto3" json:"base_path,omitempty"`
-	HostServiceAddr string                 `protobuf:"bytes,6,opt,name=host_service_addr,json=hostServiceAddr,proto3" json:"host_service_addr,omitempty"` // Address of host's gRPC server for bidirectional communication
-	PluginBasePath  string                 `protobuf:"bytes,7,opt,name=plugin_base_path,json=pluginBasePath,proto3" json:"plugin_base_path,omitempty"`    // Plugin-specific base path for plugin files and data
-	unknownFields   protoimpl.UnknownFields
-	sizeCache       protoimpl.SizeCache
+type RollbackRequest struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	ConnectionString string                 `protobuf:"bytes,1,opt,name=connection_string,json=connectionString,proto3" json:"connection_string,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
 }
 
-func (x *PluginContext) Reset() {
-	*x = PluginContext{}
-	mi := &file_plugin_proto_msgTypes[46]
+func (x *RollbackRequest) Reset() {
+	*x = RollbackRequest{}
+	mi := &file_plugin_proto_msgTypes[49]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *PluginContext) String() string {
+func (x *RollbackRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*PluginContext) ProtoMessage() {}
+func (*RollbackRequest) ProtoMessage() {}
 
-func (x *PluginContext) ProtoReflect() protoreflect.Message {
-	mi := &file_plugin_proto_msgTypes[46]
+func (x *RollbackRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_plugin_proto_msgTypes[49]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2388,95 +2722,1274 @@ func (x *PluginContext) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use PluginContext.ProtoReflect.Descriptor instead.
-func (*PluginContext) Descriptor() ([]byte, []int) {
-	return file_plugin_proto_rawDescGZIP(), []int{46}
+// Deprecated: Use RollbackRequest.ProtoReflect.Descriptor instead.
+func (*RollbackRequest) Descriptor() ([]byte, []int) {
+	return file_plugin_proto_rawDescGZIP(), []int{49}
 }
 
-func (x *PluginContext) GetPluginId() string {
+func (x *RollbackRequest) GetConnectionString() string {
 	if x != nil {
-		return x.PluginId
+		return x.ConnectionString
 	}
 	return ""
 }
 
-func (x *PluginContext) GetConfig() map[string]string {
-	if x != nil {
-		return x.Config
-	}
-	return nil
+type RollbackResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Error         string                 `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
-func (x *PluginContext) GetLogLevel() string {
-	if x != nil {
-		return x.LogLevel
-	}
-	return ""
+func (x *RollbackResponse) Reset() {
+	*x = RollbackResponse{}
+	mi := &file_plugin_proto_msgTypes[50]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
 }
 
-func (x *PluginContext) GetDatabaseUrl() string {
-	if x != nil {
-		return x.DatabaseUrl
-	}
-	return ""
+func (x *RollbackResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
 }
 
-func (x *PluginContext) GetBasePath() string {
+func (*RollbackResponse) ProtoMessage() {}
+
+func (x *RollbackResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_plugin_proto_msgTypes[50]
 	if x != nil {
-		return x.BasePath
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return ""
+	return mi.MessageOf(x)
 }
 
-func (x *PluginContext) GetHostServiceAddr() string {
+// Deprecated: Use RollbackResponse.ProtoReflect.Descriptor instead.
+func (*RollbackResponse) Descriptor() ([]byte, []int) {
+	return file_plugin_proto_rawDescGZIP(), []int{50}
+}
+
+func (x *RollbackResponse) GetSuccess() bool {
 	if x != nil {
-		return x.HostServiceAddr
+		return x.Success
 	}
-	return ""
+	return false
 }
 
-func (x *PluginContext) GetPluginBasePath() string {
+func (x *RollbackResponse) GetError() string {
 	if x != nil {
-		return x.PluginBasePath
+		return x.Error
+	}
+	return ""
+}
+
+// Admin page messages
+type GetAdminPagesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetAdminPagesRequest) Reset() {
+	*x = GetAdminPagesRequest{}
+	mi := &file_plugin_proto_msgTypes[51]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetAdminPagesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetAdminPagesRequest) ProtoMessage() {}
+
+func (x *GetAdminPagesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_plugin_proto_msgTypes[51]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetAdminPagesRequest.ProtoReflect.Descriptor instead.
+func (*GetAdminPagesRequest) Descriptor() ([]byte, []int) {
+	return file_plugin_proto_rawDescGZIP(), []int{51}
+}
+
+type GetAdminPagesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Pages         []*AdminPageConfig     `protobuf:"bytes,1,rep,name=pages,proto3" json:"pages,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetAdminPagesResponse) Reset() {
+	*x = GetAdminPagesResponse{}
+	mi := &file_plugin_proto_msgTypes[52]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetAdminPagesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetAdminPagesResponse) ProtoMessage() {}
+
+func (x *GetAdminPagesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_plugin_proto_msgTypes[52]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetAdminPagesResponse.ProtoReflect.Descriptor instead.
+func (*GetAdminPagesResponse) Descriptor() ([]byte, []int) {
+	return file_plugin_proto_rawDescGZIP(), []int{52}
+}
+
+func (x *GetAdminPagesResponse) GetPages() []*AdminPageConfig {
+	if x != nil {
+		return x.Pages
+	}
+	return nil
+}
+
+type RegisterRoutesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	BasePath      string                 `protobuf:"bytes,1,opt,name=base_path,json=basePath,proto3" json:"base_path,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RegisterRoutesRequest) Reset() {
+	*x = RegisterRoutesRequest{}
+	mi := &file_plugin_proto_msgTypes[53]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RegisterRoutesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RegisterRoutesRequest) ProtoMessage() {}
+
+func (x *RegisterRoutesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_plugin_proto_msgTypes[53]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RegisterRoutesRequest.ProtoReflect.Descriptor instead.
+func (*RegisterRoutesRequest) Descriptor() ([]byte, []int) {
+	return file_plugin_proto_rawDescGZIP(), []int{53}
+}
+
+func (x *RegisterRoutesRequest) GetBasePath() string {
+	if x != nil {
+		return x.BasePath
+	}
+	return ""
+}
+
+type RegisterRoutesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Error         string                 `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RegisterRoutesResponse) Reset() {
+	*x = RegisterRoutesResponse{}
+	mi := &file_plugin_proto_msgTypes[54]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RegisterRoutesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RegisterRoutesResponse) ProtoMessage() {}
+
+func (x *RegisterRoutesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_plugin_proto_msgTypes[54]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RegisterRoutesResponse.ProtoReflect.Descriptor instead.
+func (*RegisterRoutesResponse) Descriptor() ([]byte, []int) {
+	return file_plugin_proto_rawDescGZIP(), []int{54}
+}
+
+func (x *RegisterRoutesResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *RegisterRoutesResponse) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+// Common data structures
+type PluginContext struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	PluginId        string                 `protobuf:"bytes,1,opt,name=plugin_id,json=pluginId,proto3" json:"plugin_id,omitempty"`
+	Config          map[string]string      `protobuf:"bytes,2,rep,name=config,proto3" json:"config,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	LogLevel        string                 `protobuf:"bytes,3,opt,name=log_level,json=logLevel,proto3" json:"log_level,omitempty"`
+	DatabaseUrl     string                 `protobuf:"bytes,4,opt,name=database_url,json=databaseUrl,proto3" json:"database_url,omitempty"`
+	BasePath        string                 `protobuf:"bytes,5,opt,name=base_path,json=basePath,proto3" json:"base_path,omitempty"`
+	HostServiceAddr string                 `protobuf:"bytes,6,opt,name=host_service_addr,json=hostServiceAddr,proto3" json:"host_service_addr,omitempty"` // Address of host's gRPC server for bidirectional communication
+	PluginBasePath  string                 `protobuf:"bytes,7,opt,name=plugin_base_path,json=pluginBasePath,proto3" json:"plugin_base_path,omitempty"`    // Plugin-specific base path for plugin files and data
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *PluginContext) Reset() {
+	*x = PluginContext{}
+	mi := &file_plugin_proto_msgTypes[55]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PluginContext) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PluginContext) ProtoMessage() {}
+
+func (x *PluginContext) ProtoReflect() protoreflect.Message {
+	mi := &file_plugin_proto_msgTypes[55]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PluginContext.ProtoReflect.Descriptor instead.
+func (*PluginContext) Descriptor() ([]byte, []int) {
+	return file_plugin_proto_rawDescGZIP(), []int{55}
+}
+
+func (x *PluginContext) GetPluginId() string {
+	if x != nil {
+		return x.PluginId
+	}
+	return ""
+}
+
+func (x *PluginContext) GetConfig() map[string]string {
+	if x != nil {
+		return x.Config
+	}
+	return nil
+}
+
+func (x *PluginContext) GetLogLevel() string {
+	if x != nil {
+		return x.LogLevel
+	}
+	return ""
+}
+
+func (x *PluginContext) GetDatabaseUrl() string {
+	if x != nil {
+		return x.DatabaseUrl
+	}
+	return ""
+}
+
+func (x *PluginContext) GetBasePath() string {
+	if x != nil {
+		return x.BasePath
+	}
+	return ""
+}
+
+func (x *PluginContext) GetHostServiceAddr() string {
+	if x != nil {
+		return x.HostServiceAddr
+	}
+	return ""
+}
+
+func (x *PluginContext) GetPluginBasePath() string {
+	if x != nil {
+		return x.PluginBasePath
 	}
 	return ""
 }
 
 type PluginInfo struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
-	Version       string                 `protobuf:"bytes,3,opt,name=version,proto3" json:"version,omitempty"`
-	Description   string                 `protobuf:"bytes,4,opt,name=description,proto3" json:"description,omitempty"`
-	Author        string                 `protobuf:"bytes,5,opt,name=author,proto3" json:"author,omitempty"`
-	Website       string                 `protobuf:"bytes,6,opt,name=website,proto3" json:"website,omitempty"`
-	Repository    string                 `protobuf:"bytes,7,opt,name=repository,proto3" json:"repository,omitempty"`
-	License       string                 `protobuf:"bytes,8,opt,name=license,proto3" json:"license,omitempty"`
-	Type          string                 `protobuf:"bytes,9,opt,name=type,proto3" json:"type,omitempty"`
-	Tags          []string               `protobuf:"bytes,10,rep,name=tags,proto3" json:"tags,omitempty"`
-	Status        string                 `protobuf:"bytes,11,opt,name=status,proto3" json:"status,omitempty"`
-	InstallPath   string                 `protobuf:"bytes,12,opt,name=install_path,json=installPath,proto3" json:"install_path,omitempty"`
-	CreatedAt     int64                  `protobuf:"varint,13,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
-	UpdatedAt     int64                  `protobuf:"varint,14,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Version       string                 `protobuf:"bytes,3,opt,name=version,proto3" json:"version,omitempty"`
+	Description   string                 `protobuf:"bytes,4,opt,name=description,proto3" json:"description,omitempty"`
+	Author        string                 `protobuf:"bytes,5,opt,name=author,proto3" json:"author,omitempty"`
+	Website       string                 `protobuf:"bytes,6,opt,name=website,proto3" json:"website,omitempty"`
+	Repository    string                 `protobuf:"bytes,7,opt,name=repository,proto3" json:"repository,omitempty"`
+	License       string                 `protobuf:"bytes,8,opt,name=license,proto3" json:"license,omitempty"`
+	Type          string                 `protobuf:"bytes,9,opt,name=type,proto3" json:"type,omitempty"`
+	Tags          []string               `protobuf:"bytes,10,rep,name=tags,proto3" json:"tags,omitempty"`
+	Status        string                 `protobuf:"bytes,11,opt,name=status,proto3" json:"status,omitempty"`
+	InstallPath   string                 `protobuf:"bytes,12,opt,name=install_path,json=installPath,proto3" json:"install_path,omitempty"`
+	CreatedAt     int64                  `protobuf:"varint,13,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt     int64                  `protobuf:"varint,14,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PluginInfo) Reset() {
+	*x = PluginInfo{}
+	mi := &file_plugin_proto_msgTypes[56]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PluginInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PluginInfo) ProtoMessage() {}
+
+func (x *PluginInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_plugin_proto_msgTypes[56]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PluginInfo.ProtoReflect.Descriptor instead.
+func (*PluginInfo) Descriptor() ([]byte, []int) {
+	return file_plugin_proto_rawDescGZIP(), []int{56}
+}
+
+func (x *PluginInfo) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *PluginInfo) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *PluginInfo) GetVersion() string {
+	if x != nil {
+		return x.Version
+	}
+	return ""
+}
+
+func (x *PluginInfo) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *PluginInfo) GetAuthor() string {
+	if x != nil {
+		return x.Author
+	}
+	return ""
+}
+
+func (x *PluginInfo) GetWebsite() string {
+	if x != nil {
+		return x.Website
+	}
+	return ""
+}
+
+func (x *PluginInfo) GetRepository() string {
+	if x != nil {
+		return x.Repository
+	}
+	return ""
+}
+
+func (x *PluginInfo) GetLicense() string {
+	if x != nil {
+		return x.License
+	}
+	return ""
+}
+
+func (x *PluginInfo) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *PluginInfo) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+func (x *PluginInfo) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *PluginInfo) GetInstallPath() string {
+	if x != nil {
+		return x.InstallPath
+	}
+	return ""
+}
+
+func (x *PluginInfo) GetCreatedAt() int64 {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return 0
+}
+
+func (x *PluginInfo) GetUpdatedAt() int64 {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return 0
+}
+
+type AdminPageConfig struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Title         string                 `protobuf:"bytes,2,opt,name=title,proto3" json:"title,omitempty"`
+	Path          string                 `protobuf:"bytes,3,opt,name=path,proto3" json:"path,omitempty"`
+	Icon          string                 `protobuf:"bytes,4,opt,name=icon,proto3" json:"icon,omitempty"`
+	Category      string                 `protobuf:"bytes,5,opt,name=category,proto3" json:"category,omitempty"`
+	Url           string                 `protobuf:"bytes,6,opt,name=url,proto3" json:"url,omitempty"`
+	Type          string                 `protobuf:"bytes,7,opt,name=type,proto3" json:"type,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AdminPageConfig) Reset() {
+	*x = AdminPageConfig{}
+	mi := &file_plugin_proto_msgTypes[57]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AdminPageConfig) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AdminPageConfig) ProtoMessage() {}
+
+func (x *AdminPageConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_plugin_proto_msgTypes[57]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AdminPageConfig.ProtoReflect.Descriptor instead.
+func (*AdminPageConfig) Descriptor() ([]byte, []int) {
+	return file_plugin_proto_rawDescGZIP(), []int{57}
+}
+
+func (x *AdminPageConfig) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *AdminPageConfig) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *AdminPageConfig) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *AdminPageConfig) GetIcon() string {
+	if x != nil {
+		return x.Icon
+	}
+	return ""
+}
+
+func (x *AdminPageConfig) GetCategory() string {
+	if x != nil {
+		return x.Category
+	}
+	return ""
+}
+
+func (x *AdminPageConfig) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *AdminPageConfig) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+// Provider info messages
+type GetProviderInfoRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetProviderInfoRequest) Reset() {
+	*x = GetProviderInfoRequest{}
+	mi := &file_plugin_proto_msgTypes[58]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetProviderInfoRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetProviderInfoRequest) ProtoMessage() {}
+
+func (x *GetProviderInfoRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_plugin_proto_msgTypes[58]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetProviderInfoRequest.ProtoReflect.Descriptor instead.
+func (*GetProviderInfoRequest) Descriptor() ([]byte, []int) {
+	return file_plugin_proto_rawDescGZIP(), []int{58}
+}
+
+type GetProviderInfoResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Info          *ProviderInfo          `protobuf:"bytes,1,opt,name=info,proto3" json:"info,omitempty"`
+	Error         string                 `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetProviderInfoResponse) Reset() {
+	*x = GetProviderInfoResponse{}
+	mi := &file_plugin_proto_msgTypes[59]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetProviderInfoResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetProviderInfoResponse) ProtoMessage() {}
+
+func (x *GetProviderInfoResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_plugin_proto_msgTypes[59]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetProviderInfoResponse.ProtoReflect.Descriptor instead.
+func (*GetProviderInfoResponse) Descriptor() ([]byte, []int) {
+	return file_plugin_proto_rawDescGZIP(), []int{59}
+}
+
+func (x *GetProviderInfoResponse) GetInfo() *ProviderInfo {
+	if x != nil {
+		return x.Info
+	}
+	return nil
+}
+
+func (x *GetProviderInfoResponse) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+type ProviderInfo struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Description   string                 `protobuf:"bytes,2,opt,name=description,proto3" json:"description,omitempty"`
+	Priority      int32                  `protobuf:"varint,3,opt,name=priority,proto3" json:"priority,omitempty"`
+	Capabilities  map[string]string      `protobuf:"bytes,4,rep,name=capabilities,proto3" json:"capabilities,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ProviderInfo) Reset() {
+	*x = ProviderInfo{}
+	mi := &file_plugin_proto_msgTypes[60]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ProviderInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProviderInfo) ProtoMessage() {}
+
+func (x *ProviderInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_plugin_proto_msgTypes[60]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProviderInfo.ProtoReflect.Descriptor instead.
+func (*ProviderInfo) Descriptor() ([]byte, []int) {
+	return file_plugin_proto_rawDescGZIP(), []int{60}
+}
+
+func (x *ProviderInfo) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ProviderInfo) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *ProviderInfo) GetPriority() int32 {
+	if x != nil {
+		return x.Priority
+	}
+	return 0
+}
+
+func (x *ProviderInfo) GetCapabilities() map[string]string {
+	if x != nil {
+		return x.Capabilities
+	}
+	return nil
+}
+
+// Capabilities messages
+type GetSupportedFormatsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetSupportedFormatsRequest) Reset() {
+	*x = GetSupportedFormatsRequest{}
+	mi := &file_plugin_proto_msgTypes[61]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetSupportedFormatsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetSupportedFormatsRequest) ProtoMessage() {}
+
+func (x *GetSupportedFormatsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_plugin_proto_msgTypes[61]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetSupportedFormatsRequest.ProtoReflect.Descriptor instead.
+func (*GetSupportedFormatsRequest) Descriptor() ([]byte, []int) {
+	return file_plugin_proto_rawDescGZIP(), []int{61}
+}
+
+type GetSupportedFormatsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Formats       []*ContainerFormat     `protobuf:"bytes,1,rep,name=formats,proto3" json:"formats,omitempty"`
+	Error         string                 `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetSupportedFormatsResponse) Reset() {
+	*x = GetSupportedFormatsResponse{}
+	mi := &file_plugin_proto_msgTypes[62]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetSupportedFormatsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetSupportedFormatsResponse) ProtoMessage() {}
+
+func (x *GetSupportedFormatsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_plugin_proto_msgTypes[62]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetSupportedFormatsResponse.ProtoReflect.Descriptor instead.
+func (*GetSupportedFormatsResponse) Descriptor() ([]byte, []int) {
+	return file_plugin_proto_rawDescGZIP(), []int{62}
+}
+
+func (x *GetSupportedFormatsResponse) GetFormats() []*ContainerFormat {
+	if x != nil {
+		return x.Formats
+	}
+	return nil
+}
+
+func (x *GetSupportedFormatsResponse) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+type ContainerFormat struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Name            string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Description     string                 `protobuf:"bytes,2,opt,name=description,proto3" json:"description,omitempty"`
+	Extensions      []string               `protobuf:"bytes,3,rep,name=extensions,proto3" json:"extensions,omitempty"`
+	SupportedCodecs []string               `protobuf:"bytes,4,rep,name=supported_codecs,json=supportedCodecs,proto3" json:"supported_codecs,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *ContainerFormat) Reset() {
+	*x = ContainerFormat{}
+	mi := &file_plugin_proto_msgTypes[63]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ContainerFormat) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ContainerFormat) ProtoMessage() {}
+
+func (x *ContainerFormat) ProtoReflect() protoreflect.Message {
+	mi := &file_plugin_proto_msgTypes[63]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ContainerFormat.ProtoReflect.Descriptor instead.
+func (*ContainerFormat) Descriptor() ([]byte, []int) {
+	return file_plugin_proto_rawDescGZIP(), []int{63}
+}
+
+func (x *ContainerFormat) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ContainerFormat) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *ContainerFormat) GetExtensions() []string {
+	if x != nil {
+		return x.Extensions
+	}
+	return nil
+}
+
+func (x *ContainerFormat) GetSupportedCodecs() []string {
+	if x != nil {
+		return x.SupportedCodecs
+	}
+	return nil
+}
+
+type GetHardwareAcceleratorsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetHardwareAcceleratorsRequest) Reset() {
+	*x = GetHardwareAcceleratorsRequest{}
+	mi := &file_plugin_proto_msgTypes[64]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetHardwareAcceleratorsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetHardwareAcceleratorsRequest) ProtoMessage() {}
+
+func (x *GetHardwareAcceleratorsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_plugin_proto_msgTypes[64]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetHardwareAcceleratorsRequest.ProtoReflect.Descriptor instead.
+func (*GetHardwareAcceleratorsRequest) Descriptor() ([]byte, []int) {
+	return file_plugin_proto_rawDescGZIP(), []int{64}
+}
+
+type GetHardwareAcceleratorsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Accelerators  []*HardwareAccelerator `protobuf:"bytes,1,rep,name=accelerators,proto3" json:"accelerators,omitempty"`
+	Error         string                 `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetHardwareAcceleratorsResponse) Reset() {
+	*x = GetHardwareAcceleratorsResponse{}
+	mi := &file_plugin_proto_msgTypes[65]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetHardwareAcceleratorsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetHardwareAcceleratorsResponse) ProtoMessage() {}
+
+func (x *GetHardwareAcceleratorsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_plugin_proto_msgTypes[65]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetHardwareAcceleratorsResponse.ProtoReflect.Descriptor instead.
+func (*GetHardwareAcceleratorsResponse) Descriptor() ([]byte, []int) {
+	return file_plugin_proto_rawDescGZIP(), []int{65}
+}
+
+func (x *GetHardwareAcceleratorsResponse) GetAccelerators() []*HardwareAccelerator {
+	if x != nil {
+		return x.Accelerators
+	}
+	return nil
+}
+
+func (x *GetHardwareAcceleratorsResponse) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+type HardwareAccelerator struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	DevicePath    string                 `protobuf:"bytes,3,opt,name=device_path,json=devicePath,proto3" json:"device_path,omitempty"`
+	Available     bool                   `protobuf:"varint,4,opt,name=available,proto3" json:"available,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HardwareAccelerator) Reset() {
+	*x = HardwareAccelerator{}
+	mi := &file_plugin_proto_msgTypes[66]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HardwareAccelerator) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HardwareAccelerator) ProtoMessage() {}
+
+func (x *HardwareAccelerator) ProtoReflect() protoreflect.Message {
+	mi := &file_plugin_proto_msgTypes[66]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HardwareAccelerator.ProtoReflect.Descriptor instead.
+func (*HardwareAccelerator) Descriptor() ([]byte, []int) {
+	return file_plugin_proto_rawDescGZIP(), []int{66}
+}
+
+func (x *HardwareAccelerator) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *HardwareAccelerator) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *HardwareAccelerator) GetDevicePath() string {
+	if x != nil {
+		return x.DevicePath
+	}
+	return ""
+}
+
+func (x *HardwareAccelerator) GetAvailable() bool {
+	if x != nil {
+		return x.Available
+	}
+	return false
+}
+
+type GetQualityPresetsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetQualityPresetsRequest) Reset() {
+	*x = GetQualityPresetsRequest{}
+	mi := &file_plugin_proto_msgTypes[67]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetQualityPresetsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetQualityPresetsRequest) ProtoMessage() {}
+
+func (x *GetQualityPresetsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_plugin_proto_msgTypes[67]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetQualityPresetsRequest.ProtoReflect.Descriptor instead.
+func (*GetQualityPresetsRequest) Descriptor() ([]byte, []int) {
+	return file_plugin_proto_rawDescGZIP(), []int{67}
+}
+
+type GetQualityPresetsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Presets       []*QualityPreset       `protobuf:"bytes,1,rep,name=presets,proto3" json:"presets,omitempty"`
+	Error         string                 `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetQualityPresetsResponse) Reset() {
+	*x = GetQualityPresetsResponse{}
+	mi := &file_plugin_proto_msgTypes[68]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetQualityPresetsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetQualityPresetsResponse) ProtoMessage() {}
+
+func (x *GetQualityPresetsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_plugin_proto_msgTypes[68]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetQualityPresetsResponse.ProtoReflect.Descriptor instead.
+func (*GetQualityPresetsResponse) Descriptor() ([]byte, []int) {
+	return file_plugin_proto_rawDescGZIP(), []int{68}
+}
+
+func (x *GetQualityPresetsResponse) GetPresets() []*QualityPreset {
+	if x != nil {
+		return x.Presets
+	}
+	return nil
+}
+
+func (x *GetQualityPresetsResponse) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+type QualityPreset struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Description   string                 `protobuf:"bytes,2,opt,name=description,proto3" json:"description,omitempty"`
+	Quality       int32                  `protobuf:"varint,3,opt,name=quality,proto3" json:"quality,omitempty"` // 0-100
+	Details       string                 `protobuf:"bytes,4,opt,name=details,proto3" json:"details,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *QualityPreset) Reset() {
+	*x = QualityPreset{}
+	mi := &file_plugin_proto_msgTypes[69]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *QualityPreset) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*QualityPreset) ProtoMessage() {}
+
+func (x *QualityPreset) ProtoReflect() protoreflect.Message {
+	mi := &file_plugin_proto_msgTypes[69]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use QualityPreset.ProtoReflect.Descriptor instead.
+func (*QualityPreset) Descriptor() ([]byte, []int) {
+	return file_plugin_proto_rawDescGZIP(), []int{69}
+}
+
+func (x *QualityPreset) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *QualityPreset) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *QualityPreset) GetQuality() int32 {
+	if x != nil {
+		return x.Quality
+	}
+	return 0
+}
+
+func (x *QualityPreset) GetDetails() string {
+	if x != nil {
+		return x.Details
+	}
+	return ""
+}
+
+// Transcoding messages
+type StartTranscodeProviderRequest struct {
+	state         protoimpl.MessageState    `protogen:"open.v1"`
+	Request       *TranscodeProviderRequest `protobuf:"bytes,1,opt,name=request,proto3" json:"request,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StartTranscodeProviderRequest) Reset() {
+	*x = StartTranscodeProviderRequest{}
+	mi := &file_plugin_proto_msgTypes[70]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StartTranscodeProviderRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StartTranscodeProviderRequest) ProtoMessage() {}
+
+func (x *StartTranscodeProviderRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_plugin_proto_msgTypes[70]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StartTranscodeProviderRequest.ProtoReflect.Descriptor instead.
+func (*StartTranscodeProviderRequest) Descriptor() ([]byte, []int) {
+	return file_plugin_proto_rawDescGZIP(), []int{70}
+}
+
+func (x *StartTranscodeProviderRequest) GetRequest() *TranscodeProviderRequest {
+	if x != nil {
+		return x.Request
+	}
+	return nil
+}
+
+type StartTranscodeProviderResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Handle        *TranscodeHandle       `protobuf:"bytes,1,opt,name=handle,proto3" json:"handle,omitempty"`
+	Error         string                 `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *PluginInfo) Reset() {
-	*x = PluginInfo{}
-	mi := &file_plugin_proto_msgTypes[47]
+func (x *StartTranscodeProviderResponse) Reset() {
+	*x = StartTranscodeProviderResponse{}
+	mi := &file_plugin_proto_msgTypes[71]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *PluginInfo) String() string {
+func (x *StartTranscodeProviderResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*PluginInfo) ProtoMessage() {}
+func (*StartTranscodeProviderResponse) ProtoMessage() {}
 
-func (x *PluginInfo) ProtoReflect() protoreflect.Message {
-	mi := &file_plugin_proto_msgTypes[47]
+func (x *StartTranscodeProviderResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_plugin_proto_msgTypes[71]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2487,137 +4000,206 @@ func (x *PluginInfo) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use PluginInfo.ProtoReflect.Descriptor instead.
-func (*PluginInfo) Descriptor() ([]byte, []int) {
-	return file_plugin_proto_rawDescGZIP(), []int{47}
+// Deprecated: Use StartTranscodeProviderResponse.ProtoReflect.Descriptor instead.
+func (*StartTranscodeProviderResponse) Descriptor() ([]byte, []int) {
+	return file_plugin_proto_rawDescGZIP(), []int{71}
 }
 
-func (x *PluginInfo) GetId() string {
+func (x *StartTranscodeProviderResponse) GetHandle() *TranscodeHandle {
 	if x != nil {
-		return x.Id
+		return x.Handle
 	}
-	return ""
+	return nil
 }
 
-func (x *PluginInfo) GetName() string {
+func (x *StartTranscodeProviderResponse) GetError() string {
 	if x != nil {
-		return x.Name
+		return x.Error
 	}
 	return ""
 }
 
-func (x *PluginInfo) GetVersion() string {
+type TranscodeProviderRequest struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	SessionId string                 `protobuf:"bytes,1,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
+	InputPath string                 `protobuf:"bytes,2,opt,name=input_path,json=inputPath,proto3" json:"input_path,omitempty"`
+	OutputDir string                 `protobuf:"bytes,3,opt,name=output_dir,json=outputDir,proto3" json:"output_dir,omitempty"`
+	// Generic settings
+	Quality       int32  `protobuf:"varint,4,opt,name=quality,proto3" json:"quality,omitempty"`                                 // 0-100 quality scale
+	SpeedPriority string `protobuf:"bytes,5,opt,name=speed_priority,json=speedPriority,proto3" json:"speed_priority,omitempty"` // "fastest", "balanced", "quality"
+	Container     string `protobuf:"bytes,6,opt,name=container,proto3" json:"container,omitempty"`                              // "mp4", "mkv", "dash", "hls"
+	// Video settings
+	VideoCodec string `protobuf:"bytes,7,opt,name=video_codec,json=videoCodec,proto3" json:"video_codec,omitempty"` // "h264", "h265", "vp8", "vp9", "av1"
+	Resolution string `protobuf:"bytes,8,opt,name=resolution,proto3" json:"resolution,omitempty"`                   // "1080p", "720p", "480p", "keep"
+	// Audio settings
+	AudioCodec       string `protobuf:"bytes,9,opt,name=audio_codec,json=audioCodec,proto3" json:"audio_codec,omitempty"` // "aac", "opus", "mp3", "copy"
+	AudioBitrateKbps int32  `protobuf:"varint,10,opt,name=audio_bitrate_kbps,json=audioBitrateKbps,proto3" json:"audio_bitrate_kbps,omitempty"`
+	// Hardware preferences
+	PreferHardware bool   `protobuf:"varint,11,opt,name=prefer_hardware,json=preferHardware,proto3" json:"prefer_hardware,omitempty"`
+	HardwareType   string `protobuf:"bytes,12,opt,name=hardware_type,json=hardwareType,proto3" json:"hardware_type,omitempty"` // "auto", "nvenc", "vaapi", "qsv", "videotoolbox"
+	// Playback position
+	SeekNs int64 `protobuf:"varint,13,opt,name=seek_ns,json=seekNs,proto3" json:"seek_ns,omitempty"` // Seek position in nanoseconds
+	// Additional options
+	ExtraOptions  map[string]string `protobuf:"bytes,14,rep,name=extra_options,json=extraOptions,proto3" json:"extra_options,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TranscodeProviderRequest) Reset() {
+	*x = TranscodeProviderRequest{}
+	mi := &file_plugin_proto_msgTypes[72]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TranscodeProviderRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TranscodeProviderRequest) ProtoMessage() {}
+
+func (x *TranscodeProviderRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_plugin_proto_msgTypes[72]
 	if x != nil {
-		return x.Version
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return ""
+	return mi.MessageOf(x)
 }
 
-func (x *PluginInfo) GetDescription() string {
+// Deprecated: Use TranscodeProviderRequest.ProtoReflect.Descriptor instead.
+func (*TranscodeProviderRequest) Descriptor() ([]byte, []int) {
+	return file_plugin_proto_rawDescGZIP(), []int{72}
+}
+
+func (x *TranscodeProviderRequest) GetSessionId() string {
 	if x != nil {
-		return x.Description
+		return x.SessionId
 	}
 	return ""
 }
 
-func (x *PluginInfo) GetAuthor() string {
+func (x *TranscodeProviderRequest) GetInputPath() string {
 	if x != nil {
-		return x.Author
+		return x.InputPath
 	}
 	return ""
 }
 
-func (x *PluginInfo) GetWebsite() string {
+func (x *TranscodeProviderRequest) GetOutputDir() string {
 	if x != nil {
-		return x.Website
+		return x.OutputDir
 	}
 	return ""
 }
 
-func (x *PluginInfo) GetRepository() string {
+func (x *TranscodeProviderRequest) GetQuality() int32 {
 	if x != nil {
-		return x.Repository
+		return x.Quality
 	}
-	return ""
+	return 0
 }
 
-func (x *PluginInfo) GetLicense() string {
+func (x *TranscodeProviderRequest) GetSpeedPriority() string {
 	if x != nil {
-		return x.License
+		return x.SpeedPriority
 	}
 	return ""
 }
 
-func (x *PluginInfo) GetType() string {
+func (x *TranscodeProviderRequest) GetContainer() string {
 	if x != nil {
-		return x.Type
+		return x.Container
 	}
 	return ""
 }
 
-func (x *PluginInfo) GetTags() []string {
+func (x *TranscodeProviderRequest) GetVideoCodec() string {
 	if x != nil {
-		return x.Tags
+		return x.VideoCodec
 	}
-	return nil
+	return ""
 }
 
-func (x *PluginInfo) GetStatus() string {
+func (x *TranscodeProviderRequest) GetResolution() string {
 	if x != nil {
-		return x.Status
+		return x.Resolution
 	}
 	return ""
 }
 
-func (x *PluginInfo) GetInstallPath() string {
+func (x *TranscodeProviderRequest) GetAudioCodec() string {
 	if x != nil {
-		return x.InstallPath
+		return x.AudioCodec
 	}
 	return ""
 }
 
-func (x *PluginInfo) GetCreatedAt() int64 {
+func (x *TranscodeProviderRequest) GetAudioBitrateKbps() int32 {
 	if x != nil {
-		return x.CreatedAt
+		return x.AudioBitrateKbps
 	}
 	return 0
 }
 
-func (x *PluginInfo) GetUpdatedAt() int64 {
+func (x *TranscodeProviderRequest) GetPreferHardware() bool {
 	if x != nil {
-		return x.UpdatedAt
+		return x.PreferHardware
+	}
+	return false
+}
+
+func (x *TranscodeProviderRequest) GetHardwareType() string {
+	if x != nil {
+		return x.HardwareType
+	}
+	return ""
+}
+
+func (x *TranscodeProviderRequest) GetSeekNs() int64 {
+	if x != nil {
+		return x.SeekNs
 	}
 	return 0
 }
 
-type AdminPageConfig struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	Title         string                 `protobuf:"bytes,2,opt,name=title,proto3" json:"title,omitempty"`
-	Path          string                 `protobuf:"bytes,3,opt,name=path,proto3" json:"path,omitempty"`
-	Icon          string                 `protobuf:"bytes,4,opt,name=icon,proto3" json:"icon,omitempty"`
-	Category      string                 `protobuf:"bytes,5,opt,name=category,proto3" json:"category,omitempty"`
-	Url           string                 `protobuf:"bytes,6,opt,name=url,proto3" json:"url,omitempty"`
-	Type          string                 `protobuf:"bytes,7,opt,name=type,proto3" json:"type,omitempty"`
+func (x *TranscodeProviderRequest) GetExtraOptions() map[string]string {
+	if x != nil {
+		return x.ExtraOptions
+	}
+	return nil
+}
+
+type TranscodeHandle struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	SessionId string                 `protobuf:"bytes,1,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
+	Provider  string                 `protobuf:"bytes,2,opt,name=provider,proto3" json:"provider,omitempty"`
+	StartTime int64                  `protobuf:"varint,3,opt,name=start_time,json=startTime,proto3" json:"start_time,omitempty"`
+	Directory string                 `protobuf:"bytes,4,opt,name=directory,proto3" json:"directory,omitempty"`
+	// Private data stored as JSON
+	PrivateData   string `protobuf:"bytes,5,opt,name=private_data,json=privateData,proto3" json:"private_data,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *AdminPageConfig) Reset() {
-	*x = AdminPageConfig{}
-	mi := &file_plugin_proto_msgTypes[48]
+func (x *TranscodeHandle) Reset() {
+	*x = TranscodeHandle{}
+	mi := &file_plugin_proto_msgTypes[73]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *AdminPageConfig) String() string {
+func (x *TranscodeHandle) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*AdminPageConfig) ProtoMessage() {}
+func (*TranscodeHandle) ProtoMessage() {}
 
-func (x *AdminPageConfig) ProtoReflect() protoreflect.Message {
-	mi := &file_plugin_proto_msgTypes[48]
+func (x *TranscodeHandle) ProtoReflect() protoreflect.Message {
+	mi := &file_plugin_proto_msgTypes[73]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2628,82 +4210,68 @@ func (x *AdminPageConfig) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use AdminPageConfig.ProtoReflect.Descriptor instead.
-func (*AdminPageConfig) Descriptor() ([]byte, []int) {
-	return file_plugin_proto_rawDescGZIP(), []int{48}
-}
-
-func (x *AdminPageConfig) GetId() string {
-	if x != nil {
-		return x.Id
-	}
-	return ""
-}
-
-func (x *AdminPageConfig) GetTitle() string {
-	if x != nil {
-		return x.Title
-	}
-	return ""
+// Deprecated: Use TranscodeHandle.ProtoReflect.Descriptor instead.
+func (*TranscodeHandle) Descriptor() ([]byte, []int) {
+	return file_plugin_proto_rawDescGZIP(), []int{73}
 }
 
-func (x *AdminPageConfig) GetPath() string {
+func (x *TranscodeHandle) GetSessionId() string {
 	if x != nil {
-		return x.Path
+		return x.SessionId
 	}
 	return ""
 }
 
-func (x *AdminPageConfig) GetIcon() string {
+func (x *TranscodeHandle) GetProvider() string {
 	if x != nil {
-		return x.Icon
+		return x.Provider
 	}
 	return ""
 }
 
-func (x *AdminPageConfig) GetCategory() string {
+func (x *TranscodeHandle) GetStartTime() int64 {
 	if x != nil {
-		return x.Category
+		return x.StartTime
 	}
-	return ""
+	return 0
 }
 
-func (x *AdminPageConfig) GetUrl() string {
+func (x *TranscodeHandle) GetDirectory() string {
 	if x != nil {
-		return x.Url
+		return x.Directory
 	}
 	return ""
 }
 
-func (x *AdminPageConfig) GetType() string {
+func (x *TranscodeHandle) GetPrivateData() string {
 	if x != nil {
-		return x.Type
+		return x.PrivateData
 	}
 	return ""
 }
 
-// Provider info messages
-type GetProviderInfoRequest struct {
+type GetProgressRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
+	Handle        *TranscodeHandle       `protobuf:"bytes,1,opt,name=handle,proto3" json:"handle,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetProviderInfoRequest) Reset() {
-	*x = GetProviderInfoRequest{}
-	mi := &file_plugin_proto_msgTypes[49]
+func (x *GetProgressRequest) Reset() {
+	*x = GetProgressRequest{}
+	mi := &file_plugin_proto_msgTypes[74]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetProviderInfoRequest) String() string {
+func (x *GetProgressRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetProviderInfoRequest) ProtoMessage() {}
+func (*GetProgressRequest) ProtoMessage() {}
 
-func (x *GetProviderInfoRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_plugin_proto_msgTypes[49]
+func (x *GetProgressRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_plugin_proto_msgTypes[74]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2714,34 +4282,41 @@ func (x *GetProviderInfoRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetProviderInfoRequest.ProtoReflect.Descriptor instead.
-func (*GetProviderInfoRequest) Descriptor() ([]byte, []int) {
-	return file_plugin_proto_rawDescGZIP(), []int{49}
+// Deprecated: Use GetProgressRequest.ProtoReflect.Descriptor instead.
+func (*GetProgressRequest) Descriptor() ([]byte, []int) {
+	return file_plugin_proto_rawDescGZIP(), []int{74}
 }
 
-type GetProviderInfoResponse struct {
+func (x *GetProgressRequest) GetHandle() *TranscodeHandle {
+	if x != nil {
+		return x.Handle
+	}
+	return nil
+}
+
+type GetProgressResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Info          *ProviderInfo          `protobuf:"bytes,1,opt,name=info,proto3" json:"info,omitempty"`
+	Progress      *TranscodingProgress   `protobuf:"bytes,1,opt,name=progress,proto3" json:"progress,omitempty"`
 	Error         string                 `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetProviderInfoResponse) Reset() {
-	*x = GetProviderInfoResponse{}
-	mi := &file_plugin_proto_msgTypes[50]
+func (x *GetProgressResponse) Reset() {
+	*x = GetProgressResponse{}
+	mi := &file_plugin_proto_msgTypes[75]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetProviderInfoResponse) String() string {
+func (x *GetProgressResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetProviderInfoResponse) ProtoMessage() {}
+func (*GetProgressResponse) ProtoMessage() {}
 
-func (x *GetProviderInfoResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_plugin_proto_msgTypes[50]
+func (x *GetProgressResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_plugin_proto_msgTypes[75]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2752,50 +4327,56 @@ func (x *GetProviderInfoResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetProviderInfoResponse.ProtoReflect.Descriptor instead.
-func (*GetProviderInfoResponse) Descriptor() ([]byte, []int) {
-	return file_plugin_proto_rawDescGZIP(), []int{50}
+// Deprecated: Use GetProgressResponse.ProtoReflect.Descriptor instead.
+func (*GetProgressResponse) Descriptor() ([]byte, []int) {
+	return file_plugin_proto_rawDescGZIP(), []int{75}
 }
 
-func (x *GetProviderInfoResponse) GetInfo() *ProviderInfo {
+func (x *GetProgressResponse) GetProgress() *TranscodingProgress {
 	if x != nil {
-		return x.Info
+		return x.Progress
 	}
 	return nil
 }
 
-func (x *GetProviderInfoResponse) GetError() string {
+func (x *GetProgressResponse) GetError() string {
 	if x != nil {
 		return x.Error
 	}
 	return ""
 }
 
-type ProviderInfo struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
-	Description   string                 `protobuf:"bytes,2,opt,name=description,proto3" json:"description,omitempty"`
-	Priority      int32                  `protobuf:"varint,3,opt,name=priority,proto3" json:"priority,omitempty"`
-	Capabilities  map[string]string      `protobuf:"bytes,4,rep,name=capabilities,proto3" json:"capabilities,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+type TranscodingProgress struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	PercentComplete int32                  `protobuf:"varint,1,opt,name=percent_complete,json=percentComplete,proto3" json:"percent_complete,omitempty"`
+	TimeElapsed     int64                  `protobuf:"varint,2,opt,name=time_elapsed,json=timeElapsed,proto3" json:"time_elapsed,omitempty"`       // nanoseconds
+	TimeRemaining   int64                  `protobuf:"varint,3,opt,name=time_remaining,json=timeRemaining,proto3" json:"time_remaining,omitempty"` // nanoseconds
+	CurrentSpeed    float64                `protobuf:"fixed64,4,opt,name=current_speed,json=currentSpeed,proto3" json:"current_speed,omitempty"`   // encoding speed multiplier
+	BytesRead       int64                  `protobuf:"varint,5,opt,name=bytes_read,json=bytesRead,proto3" json:"bytes_read,omitempty"`
+	BytesWritten    int64                  `protobuf:"varint,6,opt,name=bytes_written,json=bytesWritten,proto3" json:"bytes_written,omitempty"`
+	CurrentBitrate  float64                `protobuf:"fixed64,7,opt,name=current_bitrate,json=currentBitrate,proto3" json:"current_bitrate,omitempty"`
+	CurrentFrame    int32                  `protobuf:"varint,8,opt,name=current_frame,json=currentFrame,proto3" json:"current_frame,omitempty"`
+	CurrentFps      float64                `protobuf:"fixed64,9,opt,name=current_fps,json=currentFps,proto3" json:"current_fps,omitempty"`
+	StatusMessage   string                 `protobuf:"bytes,10,opt,name=status_message,json=statusMessage,proto3" json:"status_message,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
 }
 
-func (x *ProviderInfo) Reset() {
-	*x = ProviderInfo{}
-	mi := &file_plugin_proto_msgTypes[51]
+func (x *TranscodingProgress) Reset() {
+	*x = TranscodingProgress{}
+	mi := &file_plugin_proto_msgTypes[76]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ProviderInfo) String() string {
+func (x *TranscodingProgress) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ProviderInfo) ProtoMessage() {}
+func (*TranscodingProgress) ProtoMessage() {}
 
-func (x *ProviderInfo) ProtoReflect() protoreflect.Message {
-	mi := &file_plugin_proto_msgTypes[51]
+func (x *TranscodingProgress) ProtoReflect() protoreflect.Message {
+	mi := &file_plugin_proto_msgTypes[76]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2806,99 +4387,103 @@ func (x *ProviderInfo) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ProviderInfo.ProtoReflect.Descriptor instead.
-func (*ProviderInfo) Descriptor() ([]byte, []int) {
-	return file_plugin_proto_rawDescGZIP(), []int{51}
+// Deprecated: Use TranscodingProgress.ProtoReflect.Descriptor instead.
+func (*TranscodingProgress) Descriptor() ([]byte, []int) {
+	return file_plugin_proto_rawDescGZIP(), []int{76}
 }
 
-func (x *ProviderInfo) GetName() string {
+func (x *TranscodingProgress) GetPercentComplete() int32 {
 	if x != nil {
-		return x.Name
+		return x.PercentComplete
 	}
-	return ""
+	return 0
 }
 
-func (x *ProviderInfo) GetDescription() string {
+func (x *TranscodingProgress) GetTimeElapsed() int64 {
 	if x != nil {
-		return x.Description
+		return x.TimeElapsed
 	}
-	return ""
+	return 0
 }
 
-func (x *ProviderInfo) GetPriority() int32 {
+func (x *TranscodingProgress) GetTimeRemaining() int64 {
 	if x != nil {
-		return x.Priority
+		return x.TimeRemaining
 	}
 	return 0
 }
 
-func (x *ProviderInfo) GetCapabilities() map[string]string {
+func (x *TranscodingProgress) GetCurrentSpeed() float64 {
 	if x != nil {
-		return x.Capabilities
+		return x.CurrentSpeed
 	}
-	return nil
+	return 0
 }
 
-// Capabilities messages
-type GetSupportedFormatsRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+func (x *TranscodingProgress) GetBytesRead() int64 {
+	if x != nil {
+		return x.BytesRead
+	}
+	return 0
 }
 
-func (x *GetSupportedFormatsRequest) Reset() {
-	*x = GetSupportedFormatsRequest{}
-	mi := &file_plugin_proto_msgTypes[52]
-	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-	ms.StoreMessageInfo(mi)
+func (x *TranscodingProgress) GetBytesWritten() int64 {
+	if x != nil {
+		return x.BytesWritten
+	}
+	return 0
 }
 
-func (x *GetSupportedFormatsRequest) String() string {
-	return protoimpl.X.MessageStringOf(x)
+func (x *TranscodingProgress) GetCurrentBitrate() float64 {
+	if x != nil {
+		return x.CurrentBitrate
+	}
+	return 0
 }
 
-func (*GetSupportedFormatsRequest) ProtoMessage() {}
+func (x *TranscodingProgress) GetCurrentFrame() int32 {
+	if x != nil {
+		return x.CurrentFrame
+	}
+	return 0
+}
 
-func (x *GetSupportedFormatsRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_plugin_proto_msgTypes[52]
+func (x *TranscodingProgress) GetCurrentFps() float64 {
 	if x != nil {
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		if ms.LoadMessageInfo() == nil {
-			ms.StoreMessageInfo(mi)
-		}
-		return ms
+		return x.CurrentFps
 	}
-	return mi.MessageOf(x)
+	return 0
 }
 
-// Deprecated: Use GetSupportedFormatsRequest.ProtoReflect.Descriptor instead.
-func (*GetSupportedFormatsRequest) Descriptor() ([]byte, []int) {
-	return file_plugin_proto_rawDescGZIP(), []int{52}
+func (x *TranscodingProgress) GetStatusMessage() string {
+	if x != nil {
+		return x.StatusMessage
+	}
+	return ""
 }
 
-type GetSupportedFormatsResponse struct {
+type StopTranscodeProviderRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Formats       []*ContainerFormat     `protobuf:"bytes,1,rep,name=formats,proto3" json:"formats,omitempty"`
-	Error         string                 `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
+	Handle        *TranscodeHandle       `protobuf:"bytes,1,opt,name=handle,proto3" json:"handle,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetSupportedFormatsResponse) Reset() {
-	*x = GetSupportedFormatsResponse{}
-	mi := &file_plugin_proto_msgTypes[53]
+func (x *StopTranscodeProviderRequest) Reset() {
+	*x = StopTranscodeProviderRequest{}
+	mi := &file_plugin_proto_msgTypes[77]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetSupportedFormatsResponse) String() string {
+func (x *StopTranscodeProviderRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetSupportedFormatsResponse) ProtoMessage() {}
+func (*StopTranscodeProviderRequest) ProtoMessage() {}
 
-func (x *GetSupportedFormatsResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_plugin_proto_msgTypes[53]
+func (x *StopTranscodeProviderRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_plugin_proto_msgTypes[77]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2909,50 +4494,41 @@ func (x *GetSupportedFormatsResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetSupportedFormatsResponse.ProtoReflect.Descriptor instead.
-func (*GetSupportedFormatsResponse) Descriptor() ([]byte, []int) {
-	return file_plugin_proto_rawDescGZIP(), []int{53}
+// Deprecated: Use StopTranscodeProviderRequest.ProtoReflect.Descriptor instead.
+func (*StopTranscodeProviderRequest) Descriptor() ([]byte, []int) {
+	return file_plugin_proto_rawDescGZIP(), []int{77}
 }
 
-func (x *GetSupportedFormatsResponse) GetFormats() []*ContainerFormat {
+func (x *StopTranscodeProviderRequest) GetHandle() *TranscodeHandle {
 	if x != nil {
-		return x.Formats
+		return x.Handle
 	}
 	return nil
 }
 
-func (x *GetSupportedFormatsResponse) GetError() string {
-	if x != nil {
-		return x.Error
-	}
-	return ""
-}
-
-type ContainerFormat struct {
-	state           protoimpl.MessageState `protogen:"open.v1"`
-	Name            string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
-	Description     string                 `protobuf:"bytes,2,opt,name=description,proto3" json:"description,omitempty"`
-	Extensions      []string               `protobuf:"bytes,3,rep,name=extensions,proto3" json:"extensions,omitempty"`
-	SupportedCodecs []string               `protobuf:"bytes,4,rep,name=supported_codecs,json=supportedCodecs,proto3" json:"supported_codecs,omitempty"`
-	unknownFields   protoimpl.UnknownFields
-	sizeCache       protoimpl.SizeCache
+type StopTranscodeProviderResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Error         string                 `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ContainerFormat) Reset() {
-	*x = ContainerFormat{}
-	mi := &file_plugin_proto_msgTypes[54]
+func (x *StopTranscodeProviderResponse) Reset() {
+	*x = StopTranscodeProviderResponse{}
+	mi := &file_plugin_proto_msgTypes[78]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ContainerFormat) String() string {
+func (x *StopTranscodeProviderResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ContainerFormat) ProtoMessage() {}
+func (*StopTranscodeProviderResponse) ProtoMessage() {}
 
-func (x *ContainerFormat) ProtoReflect() protoreflect.Message {
-	mi := &file_plugin_proto_msgTypes[54]
+func (x *StopTranscodeProviderResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_plugin_proto_msgTypes[78]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2963,60 +4539,48 @@ func (x *ContainerFormat) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ContainerFormat.ProtoReflect.Descriptor instead.
-func (*ContainerFormat) Descriptor() ([]byte, []int) {
-	return file_plugin_proto_rawDescGZIP(), []int{54}
+// Deprecated: Use StopTranscodeProviderResponse.ProtoReflect.Descriptor instead.
+func (*StopTranscodeProviderResponse) Descriptor() ([]byte, []int) {
+	return file_plugin_proto_rawDescGZIP(), []int{78}
 }
 
-func (x *ContainerFormat) GetName() string {
+func (x *StopTranscodeProviderResponse) GetSuccess() bool {
 	if x != nil {
-		return x.Name
+		return x.Success
 	}
-	return ""
+	return false
 }
 
-func (x *ContainerFormat) GetDescription() string {
+func (x *StopTranscodeProviderResponse) GetError() string {
 	if x != nil {
-		return x.Description
+		return x.Error
 	}
 	return ""
 }
 
-func (x *ContainerFormat) GetExtensions() []string {
-	if x != nil {
-		return x.Extensions
-	}
-	return nil
-}
-
-func (x *ContainerFormat) GetSupportedCodecs() []string {
-	if x != nil {
-		return x.SupportedCodecs
-	}
-	return nil
-}
-
-type GetHardwareAcceleratorsRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
+// Streaming messages
+type StartStreamRequest struct {
+	state         protoimpl.MessageState    `protogen:"open.v1"`
+	Request       *TranscodeProviderRequest `protobuf:"bytes,1,opt,name=request,proto3" json:"request,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetHardwareAcceleratorsRequest) Reset() {
-	*x = GetHardwareAcceleratorsRequest{}
-	mi := &file_plugin_proto_msgTypes[55]
+func (x *StartStreamRequest) Reset() {
+	*x = StartStreamRequest{}
+	mi := &file_plugin_proto_msgTypes[79]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetHardwareAcceleratorsRequest) String() string {
+func (x *StartStreamRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetHardwareAcceleratorsRequest) ProtoMessage() {}
+func (*StartStreamRequest) ProtoMessage() {}
 
-func (x *GetHardwareAcceleratorsRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_plugin_proto_msgTypes[55]
+func (x *StartStreamRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_plugin_proto_msgTypes[79]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -3027,34 +4591,41 @@ func (x *GetHardwareAcceleratorsRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetHardwareAcceleratorsRequest.ProtoReflect.Descriptor instead.
-func (*GetHardwareAcceleratorsRequest) Descriptor() ([]byte, []int) {
-	return file_plugin_proto_rawDescGZIP(), []int{55}
+// Deprecated: Use StartStreamRequest.ProtoReflect.Descriptor instead.
+func (*StartStreamRequest) Descriptor() ([]byte, []int) {
+	return file_plugin_proto_rawDescGZIP(), []int{79}
 }
 
-type GetHardwareAcceleratorsResponse struct {
+func (x *StartStreamRequest) GetRequest() *TranscodeProviderRequest {
+	if x != nil {
+		return x.Request
+	}
+	return nil
+}
+
+type StartStreamResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Accelerators  []*HardwareAccelerator `protobuf:"bytes,1,rep,name=accelerators,proto3" json:"accelerators,omitempty"`
+	Handle        *StreamHandle          `protobuf:"bytes,1,opt,name=handle,proto3" json:"handle,omitempty"`
 	Error         string                 `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetHardwareAcceleratorsResponse) Reset() {
-	*x = GetHardwareAcceleratorsResponse{}
-	mi := &file_plugin_proto_msgTypes[56]
+func (x *StartStreamResponse) Reset() {
+	*x = StartStreamResponse{}
+	mi := &file_plugin_proto_msgTypes[80]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetHardwareAcceleratorsResponse) String() string {
+func (x *StartStreamResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetHardwareAcceleratorsResponse) ProtoMessage() {}
+func (*StartStreamResponse) ProtoMessage() {}
 
-func (x *GetHardwareAcceleratorsResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_plugin_proto_msgTypes[56]
+func (x *StartStreamResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_plugin_proto_msgTypes[80]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -3065,50 +4636,53 @@ func (x *GetHardwareAcceleratorsResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetHardwareAcceleratorsResponse.ProtoReflect.Descriptor instead.
-func (*GetHardwareAcceleratorsResponse) Descriptor() ([]byte, []int) {
-	return file_plugin_proto_rawDescGZIP(), []int{56}
+// Deprecated: Use StartStreamResponse.ProtoReflect.Descriptor instead.
+func (*StartStreamResponse) Descriptor() ([]byte, []int) {
+	return file_plugin_proto_rawDescGZIP(), []int{80}
 }
 
-func (x *GetHardwareAcceleratorsResponse) GetAccelerators() []*HardwareAccelerator {
+func (x *StartStreamResponse) GetHandle() *StreamHandle {
 	if x != nil {
-		return x.Accelerators
+		return x.Handle
 	}
 	return nil
 }
 
-func (x *GetHardwareAcceleratorsResponse) GetError() string {
+func (x *StartStreamResponse) GetError() string {
 	if x != nil {
 		return x.Error
 	}
 	return ""
 }
 
-type HardwareAccelerator struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
-	DevicePath    string                 `protobuf:"bytes,3,opt,name=device_path,json=devicePath,proto3" json:"device_path,omitempty"`
-	Available     bool                   `protobuf:"varint,4,opt,name=available,proto3" json:"available,omitempty"`
+type StreamHandle struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	SessionId   string                 `protobuf:"bytes,1,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
+	Provider    string                 `protobuf:"bytes,2,opt,name=provider,proto3" json:"provider,omitempty"`
+	StartTime   int64                  `protobuf:"varint,3,opt,name=start_time,json=startTime,proto3" json:"start_time,omitempty"`
+	ContentType string                 `protobuf:"bytes,4,opt,name=content_type,json=contentType,proto3" json:"content_type,omitempty"`
+	Codec       string                 `protobuf:"bytes,5,opt,name=codec,proto3" json:"codec,omitempty"`
+	// Private data stored as JSON
+	PrivateData   string `protobuf:"bytes,6,opt,name=private_data,json=privateData,proto3" json:"private_data,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *HardwareAccelerator) Reset() {
-	*x = HardwareAccelerator{}
-	mi := &file_plugin_proto_msgTypes[57]
+func (x *StreamHandle) Reset() {
+	*x = StreamHandle{}
+	mi := &file_plugin_proto_msgTypes[81]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *HardwareAccelerator) String() string {
+func (x *StreamHandle) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*HardwareAccelerator) ProtoMessage() {}
+func (*StreamHandle) ProtoMessage() {}
 
-func (x *HardwareAccelerator) ProtoReflect() protoreflect.Message {
-	mi := &file_plugin_proto_msgTypes[57]
+func (x *StreamHandle) ProtoReflect() protoreflect.Message {
+	mi := &file_plugin_proto_msgTypes[81]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -3119,98 +4693,75 @@ func (x *HardwareAccelerator) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use HardwareAccelerator.ProtoReflect.Descriptor instead.
-func (*HardwareAccelerator) Descriptor() ([]byte, []int) {
-	return file_plugin_proto_rawDescGZIP(), []int{57}
+// Deprecated: Use StreamHandle.ProtoReflect.Descriptor instead.
+func (*StreamHandle) Descriptor() ([]byte, []int) {
+	return file_plugin_proto_rawDescGZIP(), []int{81}
 }
 
-func (x *HardwareAccelerator) GetId() string {
+func (x *StreamHandle) GetSessionId() string {
 	if x != nil {
-		return x.Id
+		return x.SessionId
 	}
 	return ""
 }
 
-func (x *HardwareAccelerator) GetName() string {
+func (x *StreamHandle) GetProvider() string {
 	if x != nil {
-		return x.Name
+		return x.Provider
 	}
 	return ""
 }
 
-func (x *HardwareAccelerator) GetDevicePath() string {
+func (x *StreamHandle) GetStartTime() int64 {
 	if x != nil {
-		return x.DevicePath
+		return x.StartTime
 	}
-	return ""
+	return 0
 }
 
-func (x *HardwareAccelerator) GetAvailable() bool {
+func (x *StreamHandle) GetContentType() string {
 	if x != nil {
-		return x.Available
+		return x.ContentType
 	}
-	return false
-}
-
-type GetQualityPresetsRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
-}
-
-func (x *GetQualityPresetsRequest) Reset() {
-	*x = GetQualityPresetsRequest{}
-	mi := &file_plugin_proto_msgTypes[58]
-	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-	ms.StoreMessageInfo(mi)
-}
-
-func (x *GetQualityPresetsRequest) String() string {
-	return protoimpl.X.MessageStringOf(x)
+	return ""
 }
 
-func (*GetQualityPresetsRequest) ProtoMessage() {}
-
-func (x *GetQualityPresetsRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_plugin_proto_msgTypes[58]
+func (x *StreamHandle) GetCodec() string {
 	if x != nil {
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		if ms.LoadMessageInfo() == nil {
-			ms.StoreMessageInfo(mi)
-		}
-		return ms
+		return x.Codec
 	}
-	return mi.MessageOf(x)
+	return ""
 }
 
-// Deprecated: Use GetQualityPresetsRequest.ProtoReflect.Descriptor instead.
-func (*GetQualityPresetsRequest) Descriptor() ([]byte, []int) {
-	return file_plugin_proto_rawDescGZIP(), []int{58}
+func (x *StreamHandle) GetPrivateData() string {
+	if x != nil {
+		return x.PrivateData
+	}
+	return ""
 }
 
-type GetQualityPresetsResponse struct {
+type GetStreamDataRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Presets       []*QualityPreset       `protobuf:"bytes,1,rep,name=presets,proto3" json:"presets,omitempty"`
-	Error         string                 `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
+	Handle        *StreamHandle          `protobuf:"bytes,1,opt,name=handle,proto3" json:"handle,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetQualityPresetsResponse) Reset() {
-	*x = GetQualityPresetsResponse{}
-	mi := &file_plugin_proto_msgTypes[59]
+func (x *GetStreamDataRequest) Reset() {
+	*x = GetStreamDataRequest{}
+	mi := &file_plugin_proto_msgTypes[82]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetQualityPresetsResponse) String() string {
+func (x *GetStreamDataRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetQualityPresetsResponse) ProtoMessage() {}
+func (*GetStreamDataRequest) ProtoMessage() {}
 
-func (x *GetQualityPresetsResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_plugin_proto_msgTypes[59]
+func (x *GetStreamDataRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_plugin_proto_msgTypes[82]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -3221,50 +4772,42 @@ func (x *GetQualityPresetsResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetQualityPresetsResponse.ProtoReflect.Descriptor instead.
-func (*GetQualityPresetsResponse) Descriptor() ([]byte, []int) {
-	return file_plugin_proto_rawDescGZIP(), []int{59}
+// Deprecated: Use GetStreamDataRequest.ProtoReflect.Descriptor instead.
+func (*GetStreamDataRequest) Descriptor() ([]byte, []int) {
+	return file_plugin_proto_rawDescGZIP(), []int{82}
 }
 
-func (x *GetQualityPresetsResponse) GetPresets() []*QualityPreset {
+func (x *GetStreamDataRequest) GetHandle() *StreamHandle {
 	if x != nil {
-		return x.Presets
+		return x.Handle
 	}
 	return nil
 }
 
-func (x *GetQualityPresetsResponse) GetError() string {
-	if x != nil {
-		return x.Error
-	}
-	return ""
-}
-
-type QualityPreset struct {
+type StreamDataChunk struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
-	Description   string                 `protobuf:"bytes,2,opt,name=description,proto3" json:"description,omitempty"`
-	Quality       int32                  `protobuf:"varint,3,opt,name=quality,proto3" json:"quality,omitempty"` // 0-100
-	Details       string                 `protobuf:"bytes,4,opt,name=details,proto3" json:"details,omitempty"`
+	Data          []byte                 `protobuf:"bytes,1,opt,name=data,proto3" json:"data,omitempty"`
+	Eof           bool                   `protobuf:"varint,2,opt,name=eof,proto3" json:"eof,omitempty"`
+	Error         string                 `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *QualityPreset) Reset() {
-	*x = QualityPreset{}
-	mi := &file_plugin_proto_msgTypes[60]
+func (x *StreamDataChunk) Reset() {
+	*x = StreamDataChunk{}
+	mi := &file_plugin_proto_msgTypes[83]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *QualityPreset) String() string {
+func (x *StreamDataChunk) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*QualityPreset) ProtoMessage() {}
+func (*StreamDataChunk) ProtoMessage() {}
 
-func (x *QualityPreset) ProtoReflect() protoreflect.Message {
-	mi := &file_plugin_proto_msgTypes[60]
+func (x *StreamDataChunk) ProtoReflect() protoreflect.Message {
+	mi := &file_plugin_proto_msgTypes[83]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -3275,62 +4818,54 @@ func (x *QualityPreset) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use QualityPreset.ProtoReflect.Descriptor instead.
-func (*QualityPreset) Descriptor() ([]byte, []int) {
-	return file_plugin_proto_rawDescGZIP(), []int{60}
-}
-
-func (x *QualityPreset) GetName() string {
-	if x != nil {
-		return x.Name
-	}
-	return ""
+// Deprecated: Use StreamDataChunk.ProtoReflect.Descriptor instead.
+func (*StreamDataChunk) Descriptor() ([]byte, []int) {
+	return file_plugin_proto_rawDescGZIP(), []int{83}
 }
 
-func (x *QualityPreset) GetDescription() string {
+func (x *StreamDataChunk) GetData() []byte {
 	if x != nil {
-		return x.Description
+		return x.Data
 	}
-	return ""
+	return nil
 }
 
-func (x *QualityPreset) GetQuality() int32 {
+func (x *StreamDataChunk) GetEof() bool {
 	if x != nil {
-		return x.Quality
+		return x.Eof
 	}
-	return 0
+	return false
 }
 
-func (x *QualityPreset) GetDetails() string {
+func (x *StreamDataChunk) GetError() string {
 	if x != nil {
-		return x.Details
+		return x.Error
 	}
 	return ""
 }
 
-// Transcoding messages
-type StartTranscodeProviderRequest struct {
-	state         protoimpl.MessageState    `protogen:"open.v1"`
-	Request       *TranscodeProviderRequest `protobuf:"bytes,1,opt,name=request,proto3" json:"request,omitempty"`
+type StopStreamRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Handle        *StreamHandle          `protobuf:"bytes,1,opt,name=handle,proto3" json:"handle,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *StartTranscodeProviderRequest) Reset() {
-	*x = StartTranscodeProviderRequest{}
-	mi := &file_plugin_proto_msgTypes[61]
+func (x *StopStreamRequest) Reset() {
+	*x = StopStreamRequest{}
+	mi := &file_plugin_proto_msgTypes[84]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *StartTranscodeProviderRequest) String() string {
+func (x *StopStreamRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*StartTranscodeProviderRequest) ProtoMessage() {}
+func (*StopStreamRequest) ProtoMessage() {}
 
-func (x *StartTranscodeProviderRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_plugin_proto_msgTypes[61]
+func (x *StopStreamRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_plugin_proto_msgTypes[84]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -3341,41 +4876,41 @@ func (x *StartTranscodeProviderRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use StartTranscodeProviderRequest.ProtoReflect.Descriptor instead.
-func (*StartTranscodeProviderRequest) Descriptor() ([]byte, []int) {
-	return file_plugin_proto_rawDescGZIP(), []int{61}
+// Deprecated: Use StopStreamRequest.ProtoReflect.Descriptor instead.
+func (*StopStreamRequest) Descriptor() ([]byte, []int) {
+	return file_plugin_proto_rawDescGZIP(), []int{84}
 }
 
-func (x *StartTranscodeProviderRequest) GetRequest() *TranscodeProviderRequest {
+func (x *StopStreamRequest) GetHandle() *StreamHandle {
 	if x != nil {
-		return x.Request
+		return x.Handle
 	}
 	return nil
 }
 
-type StartTranscodeProviderResponse struct {
+type StopStreamResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Handle        *TranscodeHandle       `protobuf:"bytes,1,opt,name=handle,proto3" json:"handle,omitempty"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
 	Error         string                 `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *StartTranscodeProviderResponse) Reset() {
-	*x = StartTranscodeProviderResponse{}
-	mi := &file_plugin_proto_msgTypes[62]
+func (x *StopStreamResponse) Reset() {
+	*x = StopStreamResponse{}
+	mi := &file_plugin_proto_msgTypes[85]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *StartTranscodeProviderResponse) String() string {
+func (x *StopStreamResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*StartTranscodeProviderResponse) ProtoMessage() {}
+func (*StopStreamResponse) ProtoMessage() {}
 
-func (x *StartTranscodeProviderResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_plugin_proto_msgTypes[62]
+func (x *StopStreamResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_plugin_proto_msgTypes[85]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -3386,66 +4921,47 @@ func (x *StartTranscodeProviderResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use StartTranscodeProviderResponse.ProtoReflect.Descriptor instead.
-func (*StartTranscodeProviderResponse) Descriptor() ([]byte, []int) {
-	return file_plugin_proto_rawDescGZIP(), []int{62}
+// Deprecated: Use StopStreamResponse.ProtoReflect.Descriptor instead.
+func (*StopStreamResponse) Descriptor() ([]byte, []int) {
+	return file_plugin_proto_rawDescGZIP(), []int{85}
 }
 
-func (x *StartTranscodeProviderResponse) GetHandle() *TranscodeHandle {
+func (x *StopStreamResponse) GetSuccess() bool {
 	if x != nil {
-		return x.Handle
+		return x.Success
 	}
-	return nil
+	return false
 }
 
-func (x *StartTranscodeProviderResponse) GetError() string {
+func (x *StopStreamResponse) GetError() string {
 	if x != nil {
 		return x.Error
 	}
 	return ""
 }
 
-type TranscodeProviderRequest struct {
-	state     protoimpl.MessageState `protogen:"open.v1"`
-	SessionId string                 `protobuf:"bytes,1,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
-	InputPath string                 `protobuf:"bytes,2,opt,name=input_path,json=inputPath,proto3" json:"input_path,omitempty"`
-	OutputDir string                 `protobuf:"bytes,3,opt,name=output_dir,json=outputDir,proto3" json:"output_dir,omitempty"`
-	// Generic settings
-	Quality       int32  `protobuf:"varint,4,opt,name=quality,proto3" json:"quality,omitempty"`                                 // 0-100 quality scale
-	SpeedPriority string `protobuf:"bytes,5,opt,name=speed_priority,json=speedPriority,proto3" json:"speed_priority,omitempty"` // "fastest", "balanced", "quality"
-	Container     string `protobuf:"bytes,6,opt,name=container,proto3" json:"container,omitempty"`                              // "mp4", "mkv", "dash", "hls"
-	// Video settings
-	VideoCodec string `protobuf:"bytes,7,opt,name=video_codec,json=videoCodec,proto3" json:"video_codec,omitempty"` // "h264", "h265", "vp8", "vp9", "av1"
-	Resolution string `protobuf:"bytes,8,opt,name=resolution,proto3" json:"resolution,omitempty"`                   // "1080p", "720p", "480p", "keep"
-	// Audio settings
-	AudioCodec       string `protobuf:"bytes,9,opt,name=audio_codec,json=audioCodec,proto3" json:"audio_codec,omitempty"` // "aac", "opus", "mp3", "copy"
-	AudioBitrateKbps int32  `protobuf:"varint,10,opt,name=audio_bitrate_kbps,json=audioBitrateKbps,proto3" json:"audio_bitrate_kbps,omitempty"`
-	// Hardware preferences
-	PreferHardware bool   `protobuf:"varint,11,opt,name=prefer_hardware,json=preferHardware,proto3" json:"prefer_hardware,omitempty"`
-	HardwareType   string `protobuf:"bytes,12,opt,name=hardware_type,json=hardwareType,proto3" json:"hardware_type,omitempty"` // "auto", "nvenc", "vaapi", "qsv", "videotoolbox"
-	// Playback position
-	SeekNs int64 `protobuf:"varint,13,opt,name=seek_ns,json=seekNs,proto3" json:"seek_ns,omitempty"` // Seek position in nanoseconds
-	// Additional options
-	ExtraOptions  map[string]string `protobuf:"bytes,14,rep,name=extra_options,json=extraOptions,proto3" json:"extra_options,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+// Dashboard service messages
+type GetDashboardSectionsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *TranscodeProviderRequest) Reset() {
-	*x = TranscodeProviderRequest{}
-	mi := &file_plugin_proto_msgTypes[63]
+func (x *GetDashboardSectionsRequest) Reset() {
+	*x = GetDashboardSectionsRequest{}
+	mi := &file_plugin_proto_msgTypes[86]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *TranscodeProviderRequest) String() string {
+func (x *GetDashboardSectionsRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*TranscodeProviderRequest) ProtoMessage() {}
+func (*GetDashboardSectionsRequest) ProtoMessage() {}
 
-func (x *TranscodeProviderRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_plugin_proto_msgTypes[63]
+func (x *GetDashboardSectionsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_plugin_proto_msgTypes[86]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -3456,136 +4972,85 @@ func (x *TranscodeProviderRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use TranscodeProviderRequest.ProtoReflect.Descriptor instead.
-func (*TranscodeProviderRequest) Descriptor() ([]byte, []int) {
-	return file_plugin_proto_rawDescGZIP(), []int{63}
-}
-
-func (x *TranscodeProviderRequest) GetSessionId() string {
-	if x != nil {
-		return x.SessionId
-	}
-	return ""
-}
-
-func (x *TranscodeProviderRequest) GetInputPath() string {
-	if x != nil {
-		return x.InputPath
-	}
-	return ""
-}
-
-func (x *TranscodeProviderRequest) GetOutputDir() string {
-	if x != nil {
-		return x.OutputDir
-	}
-	return ""
-}
-
-func (x *TranscodeProviderRequest) GetQuality() int32 {
-	if x != nil {
-		return x.Quality
-	}
-	return 0
-}
-
-func (x *TranscodeProviderRequest) GetSpeedPriority() string {
-	if x != nil {
-		return x.SpeedPriority
-	}
-	return ""
-}
-
-func (x *TranscodeProviderRequest) GetContainer() string {
-	if x != nil {
-		return x.Container
-	}
-	return ""
+// Deprecated: Use GetDashboardSectionsRequest.ProtoReflect.Descriptor instead.
+func (*GetDashboardSectionsRequest) Descriptor() ([]byte, []int) {
+	return file_plugin_proto_rawDescGZIP(), []int{86}
 }
 
-func (x *TranscodeProviderRequest) GetVideoCodec() string {
-	if x != nil {
-		return x.VideoCodec
-	}
-	return ""
+type GetDashboardSectionsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Sections      []*DashboardSection    `protobuf:"bytes,1,rep,name=sections,proto3" json:"sections,omitempty"`
+	Error         string                 `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
-func (x *TranscodeProviderRequest) GetResolution() string {
-	if x != nil {
-		return x.Resolution
-	}
-	return ""
+func (x *GetDashboardSectionsResponse) Reset() {
+	*x = GetDashboardSectionsResponse{}
+	mi := &file_plugin_proto_msgTypes[87]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
 }
 
-func (x *TranscodeProviderRequest) GetAudioCodec() string {
-	if x != nil {
-		return x.AudioCodec
-	}
-	return ""
+func (x *GetDashboardSectionsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
 }
 
-func (x *TranscodeProviderRequest) GetAudioBitrateKbps() int32 {
-	if x != nil {
-		return x.AudioBitrateKbps
-	}
-	return 0
-}
+func (*GetDashboardSectionsResponse) ProtoMessage() {}
 
-func (x *TranscodeProviderRequest) GetPreferHardware() bool {
+func (x *GetDashboardSectionsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_plugin_proto_msgTypes[87]
 	if x != nil {
-		return x.PreferHardware
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return false
+	return mi.MessageOf(x)
 }
 
-func (x *TranscodeProviderRequest) GetHardwareType() string {
-	if x != nil {
-		return x.HardwareType
-	}
-	return ""
+// Deprecated: Use GetDashboardSectionsResponse.ProtoReflect.Descriptor instead.
+func (*GetDashboardSectionsResponse) Descriptor() ([]byte, []int) {
+	return file_plugin_proto_rawDescGZIP(), []int{87}
 }
 
-func (x *TranscodeProviderRequest) GetSeekNs() int64 {
+func (x *GetDashboardSectionsResponse) GetSections() []*DashboardSection {
 	if x != nil {
-		return x.SeekNs
+		return x.Sections
 	}
-	return 0
+	return nil
 }
 
-func (x *TranscodeProviderRequest) GetExtraOptions() map[string]string {
+func (x *GetDashboardSectionsResponse) GetError() string {
 	if x != nil {
-		return x.ExtraOptions
+		return x.Error
 	}
-	return nil
+	return ""
 }
 
-type TranscodeHandle struct {
-	state     protoimpl.MessageState `protogen:"open.v1"`
-	SessionId string                 `protobuf:"bytes,1,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
-	Provider  string                 `protobuf:"bytes,2,opt,name=provider,proto3" json:"provider,omitempty"`
-	StartTime int64                  `protobuf:"varint,3,opt,name=start_time,json=startTime,proto3" json:"start_time,omitempty"`
-	Directory string                 `protobuf:"bytes,4,opt,name=directory,proto3" json:"directory,omitempty"`
-	// Private data stored as JSON
-	PrivateData   string `protobuf:"bytes,5,opt,name=private_data,json=privateData,proto3" json:"private_data,omitempty"`
+type GetMainDataRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SectionId     string                 `protobuf:"bytes,1,opt,name=section_id,json=sectionId,proto3" json:"section_id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *TranscodeHandle) Reset() {
-	*x = TranscodeHandle{}
-	mi := &file_plugin_proto_msgTypes[64]
+func (x *GetMainDataRequest) Reset() {
+	*x = GetMainDataRequest{}
+	mi := &file_plugin_proto_msgTypes[88]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *TranscodeHandle) String() string {
+func (x *GetMainDataRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*TranscodeHandle) ProtoMessage() {}
+func (*GetMainDataRequest) ProtoMessage() {}
 
-func (x *TranscodeHandle) ProtoReflect() protoreflect.Message {
-	mi := &file_plugin_proto_msgTypes[64]
+func (x *GetMainDataRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_plugin_proto_msgTypes[88]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -3596,68 +5061,92 @@ func (x *TranscodeHandle) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use TranscodeHandle.ProtoReflect.Descriptor instead.
-func (*TranscodeHandle) Descriptor() ([]byte, []int) {
-	return file_plugin_proto_rawDescGZIP(), []int{64}
+// Deprecated: Use GetMainDataRequest.ProtoReflect.Descriptor instead.
+func (*GetMainDataRequest) Descriptor() ([]byte, []int) {
+	return file_plugin_proto_rawDescGZIP(), []int{88}
 }
 
-func (x *TranscodeHandle) GetSessionId() string {
+func (x *GetMainDataRequest) GetSectionId() string {
 	if x != nil {
-		return x.SessionId
+		return x.SectionId
 	}
 	return ""
 }
 
-func (x *TranscodeHandle) GetProvider() string {
-	if x != nil {
-		return x.Provider
-	}
-	return ""
+type GetMainDataResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	DataJson      string                 `protobuf:"bytes,1,opt,name=data_json,json=dataJson,proto3" json:"data_json,omitempty"` // JSON-encoded data
+	Error         string                 `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
-func (x *TranscodeHandle) GetStartTime() int64 {
+func (x *GetMainDataResponse) Reset() {
+	*x = GetMainDataResponse{}
+	mi := &file_plugin_proto_msgTypes[89]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetMainDataResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetMainDataResponse) ProtoMessage() {}
+
+func (x *GetMainDataResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_plugin_proto_msgTypes[89]
 	if x != nil {
-		return x.StartTime
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return 0
+	return mi.MessageOf(x)
 }
 
-func (x *TranscodeHandle) GetDirectory() string {
+// Deprecated: Use GetMainDataResponse.ProtoReflect.Descriptor instead.
+func (*GetMainDataResponse) Descriptor() ([]byte, []int) {
+	return file_plugin_proto_rawDescGZIP(), []int{89}
+}
+
+func (x *GetMainDataResponse) GetDataJson() string {
 	if x != nil {
-		return x.Directory
+		return x.DataJson
 	}
 	return ""
 }
 
-func (x *TranscodeHandle) GetPrivateData() string {
+func (x *GetMainDataResponse) GetError() string {
 	if x != nil {
-		return x.PrivateData
+		return x.Error
 	}
 	return ""
 }
 
-type GetProgressRequest struct {
+type GetNerdDataRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Handle        *TranscodeHandle       `protobuf:"bytes,1,opt,name=handle,proto3" json:"handle,omitempty"`
+	SectionId     string                 `protobuf:"bytes,1,opt,name=section_id,json=sectionId,proto3" json:"section_id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetProgressRequest) Reset() {
-	*x = GetProgressRequest{}
-	mi := &file_plugin_proto_msgTypes[65]
+func (x *GetNerdDataRequest) Reset() {
+	*x = GetNerdDataRequest{}
+	mi := &file_plugin_proto_msgTypes[90]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetProgressRequest) String() string {
+func (x *GetNerdDataRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetProgressRequest) ProtoMessage() {}
+func (*GetNerdDataRequest) ProtoMessage() {}
 
-func (x *GetProgressRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_plugin_proto_msgTypes[65]
+func (x *GetNerdDataRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_plugin_proto_msgTypes[90]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -3668,41 +5157,41 @@ func (x *GetProgressRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetProgressRequest.ProtoReflect.Descriptor instead.
-func (*GetProgressRequest) Descriptor() ([]byte, []int) {
-	return file_plugin_proto_rawDescGZIP(), []int{65}
+// Deprecated: Use GetNerdDataRequest.ProtoReflect.Descriptor instead.
+func (*GetNerdDataRequest) Descriptor() ([]byte, []int) {
+	return file_plugin_proto_rawDescGZIP(), []int{90}
 }
 
-func (x *GetProgressRequest) GetHandle() *TranscodeHandle {
+func (x *GetNerdDataRequest) GetSectionId() string {
 	if x != nil {
-		return x.Handle
+		return x.SectionId
 	}
-	return nil
+	return ""
 }
 
-type GetProgressResponse struct {
+type GetNerdDataResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Progress      *TranscodingProgress   `protobuf:"bytes,1,opt,name=progress,proto3" json:"progress,omitempty"`
+	DataJson      string                 `protobuf:"bytes,1,opt,name=data_json,json=dataJson,proto3" json:"data_json,omitempty"` // JSON-encoded data
 	Error         string                 `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetProgressResponse) Reset() {
-	*x = GetProgressResponse{}
-	mi := &file_plugin_proto_msgTypes[66]
+func (x *GetNerdDataResponse) Reset() {
+	*x = GetNerdDataResponse{}
+	mi := &file_plugin_proto_msgTypes[91]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetProgressResponse) String() string {
+func (x *GetNerdDataResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetProgressResponse) ProtoMessage() {}
+func (*GetNerdDataResponse) ProtoMessage() {}
 
-func (x *GetProgressResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_plugin_proto_msgTypes[66]
+func (x *GetNerdDataResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_plugin_proto_msgTypes[91]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -3713,56 +5202,50 @@ func (x *GetProgressResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetProgressResponse.ProtoReflect.Descriptor instead.
-func (*GetProgressResponse) Descriptor() ([]byte, []int) {
-	return file_plugin_proto_rawDescGZIP(), []int{66}
+// Deprecated: Use GetNerdDataResponse.ProtoReflect.Descriptor instead.
+func (*GetNerdDataResponse) Descriptor() ([]byte, []int) {
+	return file_plugin_proto_rawDescGZIP(), []int{91}
 }
 
-func (x *GetProgressResponse) GetProgress() *TranscodingProgress {
+func (x *GetNerdDataResponse) GetDataJson() string {
 	if x != nil {
-		return x.Progress
+		return x.DataJson
 	}
-	return nil
+	return ""
 }
 
-func (x *GetProgressResponse) GetError() string {
+func (x *GetNerdDataResponse) GetError() string {
 	if x != nil {
 		return x.Error
 	}
 	return ""
 }
 
-type TranscodingProgress struct {
-	state           protoimpl.MessageState `protogen:"open.v1"`
-	PercentComplete int32                  `protobuf:"varint,1,opt,name=percent_complete,json=percentComplete,proto3" json:"percent_complete,omitempty"`
-	TimeElapsed     int64                  `protobuf:"varint,2,opt,name=time_elapsed,json=timeElapsed,proto3" json:"time_elapsed,omitempty"`       // nanoseconds
-	TimeRemaining   int64                  `protobuf:"varint,3,opt,name=time_remaining,json=timeRemaining,proto3" json:"time_remaining,omitempty"` // nanoseconds
-	CurrentSpeed    float64                `protobuf:"fixed64,4,opt,name=current_speed,json=currentSpeed,proto3" json:"current_speed,omitempty"`   // encoding speed multiplier
-	BytesRead       int64                  `protobuf:"varint,5,opt,name=bytes_read,json=bytesRead,proto3" json:"bytes_read,omitempty"`
-	BytesWritten    int64                  `protobuf:"varint,6,opt,name=bytes_written,json=bytesWritten,proto3" json:"bytes_written,omitempty"`
-	CurrentBitrate  float64                `protobuf:"fixed64,7,opt,name=current_bitrate,json=currentBitrate,proto3" json:"current_bitrate,omitempty"`
-	CurrentFrame    int32                  `protobuf:"varint,8,opt,name=current_frame,json=currentFrame,proto3" json:"current_frame,omitempty"`
-	CurrentFps      float64                `protobuf:"fixed64,9,opt,name=current_fps,json=currentFps,proto3" json:"current_fps,omitempty"`
-	StatusMessage   string                 `protobuf:"bytes,10,opt,name=status_message,json=statusMessage,proto3" json:"status_message,omitempty"`
-	unknownFields   protoimpl.UnknownFields
-	sizeCache       protoimpl.SizeCache
+type GetMetricsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SectionId     string                 `protobuf:"bytes,1,opt,name=section_id,json=sectionId,proto3" json:"section_id,omitempty"`
+	StartTime     int64                  `protobuf:"varint,2,opt,name=start_time,json=startTime,proto3" json:"start_time,omitempty"` // Unix timestamp
+	EndTime       int64                  `protobuf:"varint,3,opt,name=end_time,json=endTime,proto3" json:"end_time,omitempty"`       // Unix timestamp
+	Step          string                 `protobuf:"bytes,4,opt,name=step,proto3" json:"step,omitempty"`                             // Time step (e.g., "1m", "5m", "1h")
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
-func (x *TranscodingProgress) Reset() {
-	*x = TranscodingProgress{}
-	mi := &file_plugin_proto_msgTypes[67]
+func (x *GetMetricsRequest) Reset() {
+	*x = GetMetricsRequest{}
+	mi := &file_plugin_proto_msgTypes[92]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *TranscodingProgress) String() string {
+func (x *GetMetricsRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*TranscodingProgress) ProtoMessage() {}
+func (*GetMetricsRequest) ProtoMessage() {}
 
-func (x *TranscodingProgress) ProtoReflect() protoreflect.Message {
-	mi := &file_plugin_proto_msgTypes[67]
+func (x *GetMetricsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_plugin_proto_msgTypes[92]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -3773,103 +5256,62 @@ func (x *TranscodingProgress) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use TranscodingProgress.ProtoReflect.Descriptor instead.
-func (*TranscodingProgress) Descriptor() ([]byte, []int) {
-	return file_plugin_proto_rawDescGZIP(), []int{67}
-}
-
-func (x *TranscodingProgress) GetPercentComplete() int32 {
-	if x != nil {
-		return x.PercentComplete
-	}
-	return 0
-}
-
-func (x *TranscodingProgress) GetTimeElapsed() int64 {
-	if x != nil {
-		return x.TimeElapsed
-	}
-	return 0
-}
-
-func (x *TranscodingProgress) GetTimeRemaining() int64 {
-	if x != nil {
-		return x.TimeRemaining
-	}
-	return 0
-}
-
-func (x *TranscodingProgress) GetCurrentSpeed() float64 {
-	if x != nil {
-		return x.CurrentSpeed
-	}
-	return 0
-}
-
-func (x *TranscodingProgress) GetBytesRead() int64 {
-	if x != nil {
-		return x.BytesRead
-	}
-	return 0
-}
-
-func (x *TranscodingProgress) GetBytesWritten() int64 {
-	if x != nil {
-		return x.BytesWritten
-	}
-	return 0
+// Deprecated: Use GetMetricsRequest.ProtoReflect.Descriptor instead.
+func (*GetMetricsRequest) Descriptor() ([]byte, []int) {
+	return file_plugin_proto_rawDescGZIP(), []int{92}
 }
 
-func (x *TranscodingProgress) GetCurrentBitrate() float64 {
+func (x *GetMetricsRequest) GetSectionId() string {
 	if x != nil {
-		return x.CurrentBitrate
+		return x.SectionId
 	}
-	return 0
+	return ""
 }
 
-func (x *TranscodingProgress) GetCurrentFrame() int32 {
+func (x *GetMetricsRequest) GetStartTime() int64 {
 	if x != nil {
-		return x.CurrentFrame
+		return x.StartTime
 	}
 	return 0
 }
 
-func (x *TranscodingProgress) GetCurrentFps() float64 {
+func (x *GetMetricsRequest) GetEndTime() int64 {
 	if x != nil {
-		return x.CurrentFps
+		return x.EndTime
 	}
 	return 0
 }
 
-func (x *TranscodingProgress) GetStatusMessage() string {
+func (x *GetMetricsRequest) GetStep() string {
 	if x != nil {
-		return x.StatusMessage
+		return x.Step
 	}
 	return ""
 }
 
-type StopTranscodeProviderRequest struct {
+type GetMetricsResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Handle        *TranscodeHandle       `protobuf:"bytes,1,opt,name=handle,proto3" json:"handle,omitempty"`
+	Points        []*MetricPoint         `protobuf:"bytes,1,rep,name=points,proto3" json:"points,omitempty"`
+	Error         string                 `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *StopTranscodeProviderRequest) Reset() {
-	*x = StopTranscodeProviderRequest{}
-	mi := &file_plugin_proto_msgTypes[68]
+func (x *GetMetricsResponse) Reset() {
+	*x = GetMetricsResponse{}
+	mi := &file_plugin_proto_msgTypes[93]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *StopTranscodeProviderRequest) String() string {
+func (x *GetMetricsResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*StopTranscodeProviderRequest) ProtoMessage() {}
+func (*GetMetricsResponse) ProtoMessage() {}
 
-func (x *StopTranscodeProviderRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_plugin_proto_msgTypes[68]
+func (x *GetMetricsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_plugin_proto_msgTypes[93]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -3880,41 +5322,55 @@ func (x *StopTranscodeProviderRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use StopTranscodeProviderRequest.ProtoReflect.Descriptor instead.
-func (*StopTranscodeProviderRequest) Descriptor() ([]byte, []int) {
-	return file_plugin_proto_rawDescGZIP(), []int{68}
+// Deprecated: Use GetMetricsResponse.ProtoReflect.Descriptor instead.
+func (*GetMetricsResponse) Descriptor() ([]byte, []int) {
+	return file_plugin_proto_rawDescGZIP(), []int{93}
 }
 
-func (x *StopTranscodeProviderRequest) GetHandle() *TranscodeHandle {
+func (x *GetMetricsResponse) GetPoints() []*MetricPoint {
 	if x != nil {
-		return x.Handle
+		return x.Points
 	}
 	return nil
 }
 
-type StopTranscodeProviderResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
-	Error         string                 `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
+func (x *GetMetricsResponse) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+type DashboardSection struct {
+	state         protoimpl.MessageState  `protogen:"open.v1"`
+	Id            string                  `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	PluginId      string                  `protobuf:"bytes,2,opt,name=plugin_id,json=pluginId,proto3" json:"plugin_id,omitempty"`
+	Type          string                  `protobuf:"bytes,3,opt,name=type,proto3" json:"type,omitempty"`
+	Title         string                  `protobuf:"bytes,4,opt,name=title,proto3" json:"title,omitempty"`
+	Description   string                  `protobuf:"bytes,5,opt,name=description,proto3" json:"description,omitempty"`
+	Icon          string                  `protobuf:"bytes,6,opt,name=icon,proto3" json:"icon,omitempty"`
+	Priority      int32                   `protobuf:"varint,7,opt,name=priority,proto3" json:"priority,omitempty"`
+	Config        *DashboardSectionConfig `protobuf:"bytes,8,opt,name=config,proto3" json:"config,omitempty"`
+	Manifest      *DashboardManifest      `protobuf:"bytes,9,opt,name=manifest,proto3" json:"manifest,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *StopTranscodeProviderResponse) Reset() {
-	*x = StopTranscodeProviderResponse{}
-	mi := &file_plugin_proto_msgTypes[69]
+func (x *DashboardSection) Reset() {
+	*x = DashboardSection{}
+	mi := &file_plugin_proto_msgTypes[94]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *StopTranscodeProviderResponse) String() string {
+func (x *DashboardSection) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*StopTranscodeProviderResponse) ProtoMessage() {}
+func (*DashboardSection) ProtoMessage() {}
 
-func (x *StopTranscodeProviderResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_plugin_proto_msgTypes[69]
+func (x *DashboardSection) ProtoReflect() protoreflect.Message {
+	mi := &file_plugin_proto_msgTypes[94]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -3925,48 +5381,101 @@ func (x *StopTranscodeProviderResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use StopTranscodeProviderResponse.ProtoReflect.Descriptor instead.
-func (*StopTranscodeProviderResponse) Descriptor() ([]byte, []int) {
-	return file_plugin_proto_rawDescGZIP(), []int{69}
+// Deprecated: Use DashboardSection.ProtoReflect.Descriptor instead.
+func (*DashboardSection) Descriptor() ([]byte, []int) {
+	return file_plugin_proto_rawDescGZIP(), []int{94}
 }
 
-func (x *StopTranscodeProviderResponse) GetSuccess() bool {
+func (x *DashboardSection) GetId() string {
 	if x != nil {
-		return x.Success
+		return x.Id
 	}
-	return false
+	return ""
 }
 
-func (x *StopTranscodeProviderResponse) GetError() string {
+func (x *DashboardSection) GetPluginId() string {
 	if x != nil {
-		return x.Error
+		return x.PluginId
 	}
 	return ""
 }
 
-// Streaming messages
-type StartStreamRequest struct {
-	state         protoimpl.MessageState    `protogen:"open.v1"`
-	Request       *TranscodeProviderRequest `protobuf:"bytes,1,opt,name=request,proto3" json:"request,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+func (x *DashboardSection) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
 }
 
-func (x *StartStreamRequest) Reset() {
-	*x = StartStreamRequest{}
-	mi := &file_plugin_proto_msgTypes[70]
+func (x *DashboardSection) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *DashboardSection) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *DashboardSection) GetIcon() string {
+	if x != nil {
+		return x.Icon
+	}
+	return ""
+}
+
+func (x *DashboardSection) GetPriority() int32 {
+	if x != nil {
+		return x.Priority
+	}
+	return 0
+}
+
+func (x *DashboardSection) GetConfig() *DashboardSectionConfig {
+	if x != nil {
+		return x.Config
+	}
+	return nil
+}
+
+func (x *DashboardSection) GetManifest() *DashboardManifest {
+	if x != nil {
+		return x.Manifest
+	}
+	return nil
+}
+
+type DashboardSectionConfig struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	RefreshInterval  int32                  `protobuf:"varint,1,opt,name=refresh_interval,json=refreshInterval,proto3" json:"refresh_interval,omitempty"`
+	SupportsRealtime bool                   `protobuf:"varint,2,opt,name=supports_realtime,json=supportsRealtime,proto3" json:"supports_realtime,omitempty"`
+	HasNerdPanel     bool                   `protobuf:"varint,3,opt,name=has_nerd_panel,json=hasNerdPanel,proto3" json:"has_nerd_panel,omitempty"`
+	RequiresAuth     bool                   `protobuf:"varint,4,opt,name=requires_auth,json=requiresAuth,proto3" json:"requires_auth,omitempty"`
+	MinRefreshRate   int32                  `protobuf:"varint,5,opt,name=min_refresh_rate,json=minRefreshRate,proto3" json:"min_refresh_rate,omitempty"`
+	MaxDataPoints    int32                  `protobuf:"varint,6,opt,name=max_data_points,json=maxDataPoints,proto3" json:"max_data_points,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *DashboardSectionConfig) Reset() {
+	*x = DashboardSectionConfig{}
+	mi := &file_plugin_proto_msgTypes[95]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *StartStreamRequest) String() string {
+func (x *DashboardSectionConfig) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*StartStreamRequest) ProtoMessage() {}
+func (*DashboardSectionConfig) ProtoMessage() {}
 
-func (x *StartStreamRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_plugin_proto_msgTypes[70]
+func (x *DashboardSectionConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_plugin_proto_msgTypes[95]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -3977,41 +5486,77 @@ func (x *StartStreamRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use StartStreamRequest.ProtoReflect.Descriptor instead.
-func (*StartStreamRequest) Descriptor() ([]byte, []int) {
-	return file_plugin_proto_rawDescGZIP(), []int{70}
+// Deprecated: Use DashboardSectionConfig.ProtoReflect.Descriptor instead.
+func (*DashboardSectionConfig) Descriptor() ([]byte, []int) {
+	return file_plugin_proto_rawDescGZIP(), []int{95}
 }
 
-func (x *StartStreamRequest) GetRequest() *TranscodeProviderRequest {
+func (x *DashboardSectionConfig) GetRefreshInterval() int32 {
 	if x != nil {
-		return x.Request
+		return x.RefreshInterval
 	}
-	return nil
+	return 0
 }
 
-type StartStreamResponse struct {
+func (x *DashboardSectionConfig) GetSupportsRealtime() bool {
+	if x != nil {
+		return x.SupportsRealtime
+	}
+	return false
+}
+
+func (x *DashboardSectionConfig) GetHasNerdPanel() bool {
+	if x != nil {
+		return x.HasNerdPanel
+	}
+	return false
+}
+
+func (x *DashboardSectionConfig) GetRequiresAuth() bool {
+	if x != nil {
+		return x.RequiresAuth
+	}
+	return false
+}
+
+func (x *DashboardSectionConfig) GetMinRefreshRate() int32 {
+	if x != nil {
+		return x.MinRefreshRate
+	}
+	return 0
+}
+
+func (x *DashboardSectionConfig) GetMaxDataPoints() int32 {
+	if x != nil {
+		return x.MaxDataPoints
+	}
+	return 0
+}
+
+type DashboardManifest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Handle        *StreamHandle          `protobuf:"bytes,1,opt,name=handle,proto3" json:"handle,omitempty"`
-	Error         string                 `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
+	ComponentType string                 `protobuf:"bytes,1,opt,name=component_type,json=componentType,proto3" json:"component_type,omitempty"`
+	Actions       []*DashboardAction     `protobuf:"bytes,2,rep,name=actions,proto3" json:"actions,omitempty"`
+	UiSchema      map[string]string      `protobuf:"bytes,3,rep,name=ui_schema,json=uiSchema,proto3" json:"ui_schema,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"` // Simplified as string map
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *StartStreamResponse) Reset() {
-	*x = StartStreamResponse{}
-	mi := &file_plugin_proto_msgTypes[71]
+func (x *DashboardManifest) Reset() {
+	*x = DashboardManifest{}
+	mi := &file_plugin_proto_msgTypes[96]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *StartStreamResponse) String() string {
+func (x *DashboardManifest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*StartStreamResponse) ProtoMessage() {}
+func (*DashboardManifest) ProtoMessage() {}
 
-func (x *StartStreamResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_plugin_proto_msgTypes[71]
+func (x *DashboardManifest) ProtoReflect() protoreflect.Message {
+	mi := &file_plugin_proto_msgTypes[96]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -4022,53 +5567,62 @@ func (x *StartStreamResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use StartStreamResponse.ProtoReflect.Descriptor instead.
-func (*StartStreamResponse) Descriptor() ([]byte, []int) {
-	return file_plugin_proto_rawDescGZIP(), []int{71}
+// Deprecated: Use DashboardManifest.ProtoReflect.Descriptor instead.
+func (*DashboardManifest) Descriptor() ([]byte, []int) {
+	return file_plugin_proto_rawDescGZIP(), []int{96}
 }
 
-func (x *StartStreamResponse) GetHandle() *StreamHandle {
+func (x *DashboardManifest) GetComponentType() string {
 	if x != nil {
-		return x.Handle
+		return x.ComponentType
 	}
-	return nil
+	return ""
 }
 
-func (x *StartStreamResponse) GetError() string {
+func (x *DashboardManifest) GetActions() []*DashboardAction {
 	if x != nil {
-		return x.Error
+		return x.Actions
 	}
-	return ""
+	return nil
 }
 
-type StreamHandle struct {
-	state       protoimpl.MessageState `protogen:"open.v1"`
-	SessionId   string                 `protobuf:"bytes,1,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
-	Provider    string                 `protobuf:"bytes,2,opt,name=provider,proto3" json:"provider,omitempty"`
-	StartTime   int64                  `protobuf:"varint,3,opt,name=start_time,json=startTime,proto3" json:"start_time,omitempty"`
-	ContentType string                 `protobuf:"bytes,4,opt,name=content_type,json=contentType,proto3" json:"content_type,omitempty"`
-	Codec       string                 `protobuf:"bytes,5,opt,name=codec,proto3" json:"codec,omitempty"`
-	// Private data stored as JSON
-	PrivateData   string `protobuf:"bytes,6,opt,name=private_data,json=privateData,proto3" json:"private_data,omitempty"`
+func (x *DashboardManifest) GetUiSchema() map[string]string {
+	if x != nil {
+		return x.UiSchema
+	}
+	return nil
+}
+
+type DashboardAction struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Label         string                 `protobuf:"bytes,2,opt,name=label,proto3" json:"label,omitempty"`
+	Icon          string                 `protobuf:"bytes,3,opt,name=icon,proto3" json:"icon,omitempty"`
+	Style         string                 `protobuf:"bytes,4,opt,name=style,proto3" json:"style,omitempty"`
+	Endpoint      string                 `protobuf:"bytes,5,opt,name=endpoint,proto3" json:"endpoint,omitempty"`
+	Method        string                 `protobuf:"bytes,6,opt,name=method,proto3" json:"method,omitempty"`
+	Confirm       bool                   `protobuf:"varint,7,opt,name=confirm,proto3" json:"confirm,omitempty"`
+	PayloadJson   string                 `protobuf:"bytes,8,opt,name=payload_json,json=payloadJson,proto3" json:"payload_json,omitempty"` // JSON-encoded payload
+	Shortcut      string                 `protobuf:"bytes,9,opt,name=shortcut,proto3" json:"shortcut,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *StreamHandle) Reset() {
-	*x = StreamHandle{}
-	mi := &file_plugin_proto_msgTypes[72]
+func (x *DashboardAction) Reset() {
+	*x = DashboardAction{}
+	mi := &file_plugin_proto_msgTypes[97]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *StreamHandle) String() string {
+func (x *DashboardAction) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*StreamHandle) ProtoMessage() {}
+func (*DashboardAction) ProtoMessage() {}
 
-func (x *StreamHandle) ProtoReflect() protoreflect.Message {
-	mi := &file_plugin_proto_msgTypes[72]
+func (x *DashboardAction) ProtoReflect() protoreflect.Message {
+	mi := &file_plugin_proto_msgTypes[97]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -4079,75 +5633,99 @@ func (x *StreamHandle) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use StreamHandle.ProtoReflect.Descriptor instead.
-func (*StreamHandle) Descriptor() ([]byte, []int) {
-	return file_plugin_proto_rawDescGZIP(), []int{72}
+// Deprecated: Use DashboardAction.ProtoReflect.Descriptor instead.
+func (*DashboardAction) Descriptor() ([]byte, []int) {
+	return file_plugin_proto_rawDescGZIP(), []int{97}
 }
 
-func (x *StreamHandle) GetSessionId() string {
+func (x *DashboardAction) GetId() string {
 	if x != nil {
-		return x.SessionId
+		return x.Id
 	}
 	return ""
 }
 
-func (x *StreamHandle) GetProvider() string {
+func (x *DashboardAction) GetLabel() string {
 	if x != nil {
-		return x.Provider
+		return x.Label
 	}
 	return ""
 }
 
-func (x *StreamHandle) GetStartTime() int64 {
+func (x *DashboardAction) GetIcon() string {
 	if x != nil {
-		return x.StartTime
+		return x.Icon
 	}
-	return 0
+	return ""
 }
 
-func (x *StreamHandle) GetContentType() string {
+func (x *DashboardAction) GetStyle() string {
 	if x != nil {
-		return x.ContentType
+		return x.Style
 	}
 	return ""
 }
 
-func (x *StreamHandle) GetCodec() string {
+func (x *DashboardAction) GetEndpoint() string {
 	if x != nil {
-		return x.Codec
+		return x.Endpoint
 	}
 	return ""
 }
 
-func (x *StreamHandle) GetPrivateData() string {
+func (x *DashboardAction) GetMethod() string {
 	if x != nil {
-		return x.PrivateData
+		return x.Method
 	}
 	return ""
 }
 
-type GetStreamDataRequest struct {
+func (x *DashboardAction) GetConfirm() bool {
+	if x != nil {
+		return x.Confirm
+	}
+	return false
+}
+
+func (x *DashboardAction) GetPayloadJson() string {
+	if x != nil {
+		return x.PayloadJson
+	}
+	return ""
+}
+
+func (x *DashboardAction) GetShortcut() string {
+	if x != nil {
+		return x.Shortcut
+	}
+	return ""
+}
+
+type MetricPoint struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Handle        *StreamHandle          `protobuf:"bytes,1,opt,name=handle,proto3" json:"handle,omitempty"`
+	Timestamp     int64                  `protobuf:"varint,1,opt,name=timestamp,proto3" json:"timestamp,omitempty"` // Unix timestamp
+	Value         float64                `protobuf:"fixed64,2,opt,name=value,proto3" json:"value,omitempty"`
+	Labels        map[string]string      `protobuf:"bytes,3,rep,name=labels,proto3" json:"labels,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	MetadataJson  string                 `protobuf:"bytes,4,opt,name=metadata_json,json=metadataJson,proto3" json:"metadata_json,omitempty"` // JSON-encoded metadata
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetStreamDataRequest) Reset() {
-	*x = GetStreamDataRequest{}
-	mi := &file_plugin_proto_msgTypes[73]
+func (x *MetricPoint) Reset() {
+	*x = MetricPoint{}
+	mi := &file_plugin_proto_msgTypes[98]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetStreamDataRequest) String() string {
+func (x *MetricPoint) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetStreamDataRequest) ProtoMessage() {}
+func (*MetricPoint) ProtoMessage() {}
 
-func (x *GetStreamDataRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_plugin_proto_msgTypes[73]
+func (x *MetricPoint) ProtoReflect() protoreflect.Message {
+	mi := &file_plugin_proto_msgTypes[98]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -4158,42 +5736,79 @@ func (x *GetStreamDataRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetStreamDataRequest.ProtoReflect.Descriptor instead.
-func (*GetStreamDataRequest) Descriptor() ([]byte, []int) {
-	return file_plugin_proto_rawDescGZIP(), []int{73}
+// Deprecated: Use MetricPoint.ProtoReflect.Descriptor instead.
+func (*MetricPoint) Descriptor() ([]byte, []int) {
+	return file_plugin_proto_rawDescGZIP(), []int{98}
 }
 
-func (x *GetStreamDataRequest) GetHandle() *StreamHandle {
+func (x *MetricPoint) GetTimestamp() int64 {
 	if x != nil {
-		return x.Handle
+		return x.Timestamp
+	}
+	return 0
+}
+
+func (x *MetricPoint) GetValue() float64 {
+	if x != nil {
+		return x.Value
+	}
+	return 0
+}
+
+func (x *MetricPoint) GetLabels() map[string]string {
+	if x != nil {
+		return x.Labels
 	}
 	return nil
 }
 
-type StreamDataChunk struct {
+func (x *MetricPoint) GetMetadataJson() string {
+	if x != nil {
+		return x.MetadataJson
+	}
+	return ""
+}
+
+type MediaFileInfo struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Data          []byte                 `protobuf:"bytes,1,opt,name=data,proto3" json:"data,omitempty"`
-	Eof           bool                   `protobuf:"varint,2,opt,name=eof,proto3" json:"eof,omitempty"`
-	Error         string                 `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	MediaId       string                 `protobuf:"bytes,2,opt,name=media_id,json=mediaId,proto3" json:"media_id,omitempty"`       // ID of the movie, episode or track the file belongs to
+	MediaType     string                 `protobuf:"bytes,3,opt,name=media_type,json=mediaType,proto3" json:"media_type,omitempty"` // movie, episode, track, image, home_video
+	LibraryId     uint32                 `protobuf:"varint,4,opt,name=library_id,json=libraryId,proto3" json:"library_id,omitempty"`
+	LibraryType   string                 `protobuf:"bytes,5,opt,name=library_type,json=libraryType,proto3" json:"library_type,omitempty"` // movie, tv, music, mixed, home
+	LibraryPath   string                 `protobuf:"bytes,6,opt,name=library_path,json=libraryPath,proto3" json:"library_path,omitempty"`
+	Path          string                 `protobuf:"bytes,7,opt,name=path,proto3" json:"path,omitempty"`
+	Container     string                 `protobuf:"bytes,8,opt,name=container,proto3" json:"container,omitempty"`
+	VideoCodec    string                 `protobuf:"bytes,9,opt,name=video_codec,json=videoCodec,proto3" json:"video_codec,omitempty"`
+	AudioCodec    string                 `protobuf:"bytes,10,opt,name=audio_codec,json=audioCodec,proto3" json:"audio_codec,omitempty"`
+	Resolution    string                 `protobuf:"bytes,11,opt,name=resolution,proto3" json:"resolution,omitempty"`
+	Duration      int32                  `protobuf:"varint,12,opt,name=duration,proto3" json:"duration,omitempty"` // Seconds
+	SizeBytes     int64                  `protobuf:"varint,13,opt,name=size_bytes,json=sizeBytes,proto3" json:"size_bytes,omitempty"`
+	Title         string                 `protobuf:"bytes,14,opt,name=title,proto3" json:"title,omitempty"`                                       // Title of the movie, episode or track
+	Year          int32                  `protobuf:"varint,15,opt,name=year,proto3" json:"year,omitempty"`                                        // Release or air year, 0 when unknown
+	ShowId        string                 `protobuf:"bytes,16,opt,name=show_id,json=showId,proto3" json:"show_id,omitempty"`                       // Episodes only
+	ShowTitle     string                 `protobuf:"bytes,17,opt,name=show_title,json=showTitle,proto3" json:"show_title,omitempty"`              // Episodes only
+	SeasonNumber  int32                  `protobuf:"varint,18,opt,name=season_number,json=seasonNumber,proto3" json:"season_number,omitempty"`    // Episodes only
+	EpisodeNumber int32                  `protobuf:"varint,19,opt,name=episode_number,json=episodeNumber,proto3" json:"episode_number,omitempty"` // Episodes only
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *StreamDataChunk) Reset() {
-	*x = StreamDataChunk{}
-	mi := &file_plugin_proto_msgTypes[74]
+func (x *MediaFileInfo) Reset() {
+	*x = MediaFileInfo{}
+	mi := &file_plugin_proto_msgTypes[99]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *StreamDataChunk) String() string {
+func (x *MediaFileInfo) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*StreamDataChunk) ProtoMessage() {}
+func (*MediaFileInfo) ProtoMessage() {}
 
-func (x *StreamDataChunk) ProtoReflect() protoreflect.Message {
-	mi := &file_plugin_proto_msgTypes[74]
+func (x *MediaFileInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_plugin_proto_msgTypes[99]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -4204,150 +5819,166 @@ func (x *StreamDataChunk) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use StreamDataChunk.ProtoReflect.Descriptor instead.
-func (*StreamDataChunk) Descriptor() ([]byte, []int) {
-	return file_plugin_proto_rawDescGZIP(), []int{74}
+// Deprecated: Use MediaFileInfo.ProtoReflect.Descriptor instead.
+func (*MediaFileInfo) Descriptor() ([]byte, []int) {
+	return file_plugin_proto_rawDescGZIP(), []int{99}
 }
 
-func (x *StreamDataChunk) GetData() []byte {
+func (x *MediaFileInfo) GetId() string {
 	if x != nil {
-		return x.Data
+		return x.Id
 	}
-	return nil
+	return ""
 }
 
-func (x *StreamDataChunk) GetEof() bool {
+func (x *MediaFileInfo) GetMediaId() string {
 	if x != nil {
-		return x.Eof
+		return x.MediaId
 	}
-	return false
+	return ""
 }
 
-func (x *StreamDataChunk) GetError() string {
+func (x *MediaFileInfo) GetMediaType() string {
 	if x != nil {
-		return x.Error
+		return x.MediaType
 	}
 	return ""
 }
 
-type StopStreamRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Handle        *StreamHandle          `protobuf:"bytes,1,opt,name=handle,proto3" json:"handle,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+func (x *MediaFileInfo) GetLibraryId() uint32 {
+	if x != nil {
+		return x.LibraryId
+	}
+	return 0
 }
 
-func (x *StopStreamRequest) Reset() {
-	*x = StopStreamRequest{}
-	mi := &file_plugin_proto_msgTypes[75]
-	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-	ms.StoreMessageInfo(mi)
+func (x *MediaFileInfo) GetLibraryType() string {
+	if x != nil {
+		return x.LibraryType
+	}
+	return ""
 }
 
-func (x *StopStreamRequest) String() string {
-	return protoimpl.X.MessageStringOf(x)
+func (x *MediaFileInfo) GetLibraryPath() string {
+	if x != nil {
+		return x.LibraryPath
+	}
+	return ""
 }
 
-func (*StopStreamRequest) ProtoMessage() {}
+func (x *MediaFileInfo) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
 
-func (x *StopStreamRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_plugin_proto_msgTypes[75]
+func (x *MediaFileInfo) GetContainer() string {
 	if x != nil {
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		if ms.LoadMessageInfo() == nil {
-			ms.StoreMessageInfo(mi)
-		}
-		return ms
+		return x.Container
 	}
-	return mi.MessageOf(x)
+	return ""
 }
 
-// Deprecated: Use StopStreamRequest.ProtoReflect.Descriptor instead.
-func (*StopStreamRequest) Descriptor() ([]byte, []int) {
-	return file_plugin_proto_rawDescGZIP(), []int{75}
+func (x *MediaFileInfo) GetVideoCodec() string {
+	if x != nil {
+		return x.VideoCodec
+	}
+	return ""
 }
 
-func (x *StopStreamRequest) GetHandle() *StreamHandle {
+func (x *MediaFileInfo) GetAudioCodec() string {
 	if x != nil {
-		return x.Handle
+		return x.AudioCodec
 	}
-	return nil
+	return ""
 }
 
-type StopStreamResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
-	Error         string                 `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+func (x *MediaFileInfo) GetResolution() string {
+	if x != nil {
+		return x.Resolution
+	}
+	return ""
 }
 
-func (x *StopStreamResponse) Reset() {
-	*x = StopStreamResponse{}
-	mi := &file_plugin_proto_msgTypes[76]
-	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-	ms.StoreMessageInfo(mi)
+func (x *MediaFileInfo) GetDuration() int32 {
+	if x != nil {
+		return x.Duration
+	}
+	return 0
 }
 
-func (x *StopStreamResponse) String() string {
-	return protoimpl.X.MessageStringOf(x)
+func (x *MediaFileInfo) GetSizeBytes() int64 {
+	if x != nil {
+		return x.SizeBytes
+	}
+	return 0
 }
 
-func (*StopStreamResponse) ProtoMessage() {}
+func (x *MediaFileInfo) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
 
-func (x *StopStreamResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_plugin_proto_msgTypes[76]
+func (x *MediaFileInfo) GetYear() int32 {
 	if x != nil {
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		if ms.LoadMessageInfo() == nil {
-			ms.StoreMessageInfo(mi)
-		}
-		return ms
+		return x.Year
 	}
-	return mi.MessageOf(x)
+	return 0
 }
 
-// Deprecated: Use StopStreamResponse.ProtoReflect.Descriptor instead.
-func (*StopStreamResponse) Descriptor() ([]byte, []int) {
-	return file_plugin_proto_rawDescGZIP(), []int{76}
+func (x *MediaFileInfo) GetShowId() string {
+	if x != nil {
+		return x.ShowId
+	}
+	return ""
+}
+
+func (x *MediaFileInfo) GetShowTitle() string {
+	if x != nil {
+		return x.ShowTitle
+	}
+	return ""
 }
 
-func (x *StopStreamResponse) GetSuccess() bool {
+func (x *MediaFileInfo) GetSeasonNumber() int32 {
 	if x != nil {
-		return x.Success
+		return x.SeasonNumber
 	}
-	return false
+	return 0
 }
 
-func (x *StopStreamResponse) GetError() string {
+func (x *MediaFileInfo) GetEpisodeNumber() int32 {
 	if x != nil {
-		return x.Error
+		return x.EpisodeNumber
 	}
-	return ""
+	return 0
 }
 
-// Dashboard service messages
-type GetDashboardSectionsRequest struct {
+type GetMediaFileRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
+	MediaFileId   string                 `protobuf:"bytes,1,opt,name=media_file_id,json=mediaFileId,proto3" json:"media_file_id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetDashboardSectionsRequest) Reset() {
-	*x = GetDashboardSectionsRequest{}
-	mi := &file_plugin_proto_msgTypes[77]
+func (x *GetMediaFileRequest) Reset() {
+	*x = GetMediaFileRequest{}
+	mi := &file_plugin_proto_msgTypes[100]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetDashboardSectionsRequest) String() string {
+func (x *GetMediaFileRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetDashboardSectionsRequest) ProtoMessage() {}
+func (*GetMediaFileRequest) ProtoMessage() {}
 
-func (x *GetDashboardSectionsRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_plugin_proto_msgTypes[77]
+func (x *GetMediaFileRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_plugin_proto_msgTypes[100]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -4358,34 +5989,41 @@ func (x *GetDashboardSectionsRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetDashboardSectionsRequest.ProtoReflect.Descriptor instead.
-func (*GetDashboardSectionsRequest) Descriptor() ([]byte, []int) {
-	return file_plugin_proto_rawDescGZIP(), []int{77}
+// Deprecated: Use GetMediaFileRequest.ProtoReflect.Descriptor instead.
+func (*GetMediaFileRequest) Descriptor() ([]byte, []int) {
+	return file_plugin_proto_rawDescGZIP(), []int{100}
 }
 
-type GetDashboardSectionsResponse struct {
+func (x *GetMediaFileRequest) GetMediaFileId() string {
+	if x != nil {
+		return x.MediaFileId
+	}
+	return ""
+}
+
+type GetMediaFileResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Sections      []*DashboardSection    `protobuf:"bytes,1,rep,name=sections,proto3" json:"sections,omitempty"`
-	Error         string                 `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
+	Found         bool                   `protobuf:"varint,1,opt,name=found,proto3" json:"found,omitempty"`
+	MediaFile     *MediaFileInfo         `protobuf:"bytes,2,opt,name=media_file,json=mediaFile,proto3" json:"media_file,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetDashboardSectionsResponse) Reset() {
-	*x = GetDashboardSectionsResponse{}
-	mi := &file_plugin_proto_msgTypes[78]
+func (x *GetMediaFileResponse) Reset() {
+	*x = GetMediaFileResponse{}
+	mi := &file_plugin_proto_msgTypes[101]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetDashboardSectionsResponse) String() string {
+func (x *GetMediaFileResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetDashboardSectionsResponse) ProtoMessage() {}
+func (*GetMediaFileResponse) ProtoMessage() {}
 
-func (x *GetDashboardSectionsResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_plugin_proto_msgTypes[78]
+func (x *GetMediaFileResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_plugin_proto_msgTypes[101]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -4396,47 +6034,51 @@ func (x *GetDashboardSectionsResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetDashboardSectionsResponse.ProtoReflect.Descriptor instead.
-func (*GetDashboardSectionsResponse) Descriptor() ([]byte, []int) {
-	return file_plugin_proto_rawDescGZIP(), []int{78}
+// Deprecated: Use GetMediaFileResponse.ProtoReflect.Descriptor instead.
+func (*GetMediaFileResponse) Descriptor() ([]byte, []int) {
+	return file_plugin_proto_rawDescGZIP(), []int{101}
 }
 
-func (x *GetDashboardSectionsResponse) GetSections() []*DashboardSection {
+func (x *GetMediaFileResponse) GetFound() bool {
 	if x != nil {
-		return x.Sections
+		return x.Found
 	}
-	return nil
+	return false
 }
 
-func (x *GetDashboardSectionsResponse) GetError() string {
+func (x *GetMediaFileResponse) GetMediaFile() *MediaFileInfo {
 	if x != nil {
-		return x.Error
+		return x.MediaFile
 	}
-	return ""
+	return nil
 }
 
-type GetMainDataRequest struct {
+type MediaItemInfo struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	SectionId     string                 `protobuf:"bytes,1,opt,name=section_id,json=sectionId,proto3" json:"section_id,omitempty"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	MediaType     string                 `protobuf:"bytes,2,opt,name=media_type,json=mediaType,proto3" json:"media_type,omitempty"` // movie, tv_show, episode, track
+	Title         string                 `protobuf:"bytes,3,opt,name=title,proto3" json:"title,omitempty"`
+	Year          int32                  `protobuf:"varint,4,opt,name=year,proto3" json:"year,omitempty"`                                                                                                           // Release or first air year, 0 when unknown
+	ExternalIds   map[string]string      `protobuf:"bytes,5,rep,name=external_ids,json=externalIds,proto3" json:"external_ids,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"` // Every external ID known for the item, by source
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetMainDataRequest) Reset() {
-	*x = GetMainDataRequest{}
-	mi := &file_plugin_proto_msgTypes[79]
+func (x *MediaItemInfo) Reset() {
+	*x = MediaItemInfo{}
+	mi := &file_plugin_proto_msgTypes[102]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetMainDataRequest) String() string {
+func (x *MediaItemInfo) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetMainDataRequest) ProtoMessage() {}
+func (*MediaItemInfo) ProtoMessage() {}
 
-func (x *GetMainDataRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_plugin_proto_msgTypes[79]
+func (x *MediaItemInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_plugin_proto_msgTypes[102]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -4447,41 +6089,70 @@ func (x *GetMainDataRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetMainDataRequest.ProtoReflect.Descriptor instead.
-func (*GetMainDataRequest) Descriptor() ([]byte, []int) {
-	return file_plugin_proto_rawDescGZIP(), []int{79}
+// Deprecated: Use MediaItemInfo.ProtoReflect.Descriptor instead.
+func (*MediaItemInfo) Descriptor() ([]byte, []int) {
+	return file_plugin_proto_rawDescGZIP(), []int{102}
 }
 
-func (x *GetMainDataRequest) GetSectionId() string {
+func (x *MediaItemInfo) GetId() string {
 	if x != nil {
-		return x.SectionId
+		return x.Id
 	}
 	return ""
 }
 
-type GetMainDataResponse struct {
+func (x *MediaItemInfo) GetMediaType() string {
+	if x != nil {
+		return x.MediaType
+	}
+	return ""
+}
+
+func (x *MediaItemInfo) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *MediaItemInfo) GetYear() int32 {
+	if x != nil {
+		return x.Year
+	}
+	return 0
+}
+
+func (x *MediaItemInfo) GetExternalIds() map[string]string {
+	if x != nil {
+		return x.ExternalIds
+	}
+	return nil
+}
+
+type FindMediaByExternalIDRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	DataJson      string                 `protobuf:"bytes,1,opt,name=data_json,json=dataJson,proto3" json:"data_json,omitempty"` // JSON-encoded data
-	Error         string                 `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
+	Source        string                 `protobuf:"bytes,1,opt,name=source,proto3" json:"source,omitempty"` // e.g. tmdb, imdb, tvdb, musicbrainz
+	ExternalId    string                 `protobuf:"bytes,2,opt,name=external_id,json=externalId,proto3" json:"external_id,omitempty"`
+	MediaType     string                 `protobuf:"bytes,3,opt,name=media_type,json=mediaType,proto3" json:"media_type,omitempty"` // Optional: only items of this type
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetMainDataResponse) Reset() {
-	*x = GetMainDataResponse{}
-	mi := &file_plugin_proto_msgTypes[80]
+func (x *FindMediaByExternalIDRequest) Reset() {
+	*x = FindMediaByExternalIDRequest{}
+	mi := &file_plugin_proto_msgTypes[103]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetMainDataResponse) String() string {
+func (x *FindMediaByExternalIDRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetMainDataResponse) ProtoMessage() {}
+func (*FindMediaByExternalIDRequest) ProtoMessage() {}
 
-func (x *GetMainDataResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_plugin_proto_msgTypes[80]
+func (x *FindMediaByExternalIDRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_plugin_proto_msgTypes[103]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -4492,47 +6163,54 @@ func (x *GetMainDataResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetMainDataResponse.ProtoReflect.Descriptor instead.
-func (*GetMainDataResponse) Descriptor() ([]byte, []int) {
-	return file_plugin_proto_rawDescGZIP(), []int{80}
+// Deprecated: Use FindMediaByExternalIDRequest.ProtoReflect.Descriptor instead.
+func (*FindMediaByExternalIDRequest) Descriptor() ([]byte, []int) {
+	return file_plugin_proto_rawDescGZIP(), []int{103}
 }
 
-func (x *GetMainDataResponse) GetDataJson() string {
+func (x *FindMediaByExternalIDRequest) GetSource() string {
 	if x != nil {
-		return x.DataJson
+		return x.Source
 	}
 	return ""
 }
 
-func (x *GetMainDataResponse) GetError() string {
+func (x *FindMediaByExternalIDRequest) GetExternalId() string {
 	if x != nil {
-		return x.Error
+		return x.ExternalId
 	}
 	return ""
 }
 
-type GetNerdDataRequest struct {
+func (x *FindMediaByExternalIDRequest) GetMediaType() string {
+	if x != nil {
+		return x.MediaType
+	}
+	return ""
+}
+
+type FindMediaByExternalIDResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	SectionId     string                 `protobuf:"bytes,1,opt,name=section_id,json=sectionId,proto3" json:"section_id,omitempty"`
+	Items         []*MediaItemInfo       `protobuf:"bytes,1,rep,name=items,proto3" json:"items,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetNerdDataRequest) Reset() {
-	*x = GetNerdDataRequest{}
-	mi := &file_plugin_proto_msgTypes[81]
+func (x *FindMediaByExternalIDResponse) Reset() {
+	*x = FindMediaByExternalIDResponse{}
+	mi := &file_plugin_proto_msgTypes[104]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetNerdDataRequest) String() string {
+func (x *FindMediaByExternalIDResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetNerdDataRequest) ProtoMessage() {}
+func (*FindMediaByExternalIDResponse) ProtoMessage() {}
 
-func (x *GetNerdDataRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_plugin_proto_msgTypes[81]
+func (x *FindMediaByExternalIDResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_plugin_proto_msgTypes[104]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -4543,41 +6221,43 @@ func (x *GetNerdDataRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetNerdDataRequest.ProtoReflect.Descriptor instead.
-func (*GetNerdDataRequest) Descriptor() ([]byte, []int) {
-	return file_plugin_proto_rawDescGZIP(), []int{81}
+// Deprecated: Use FindMediaByExternalIDResponse.ProtoReflect.Descriptor instead.
+func (*FindMediaByExternalIDResponse) Descriptor() ([]byte, []int) {
+	return file_plugin_proto_rawDescGZIP(), []int{104}
 }
 
-func (x *GetNerdDataRequest) GetSectionId() string {
+func (x *FindMediaByExternalIDResponse) GetItems() []*MediaItemInfo {
 	if x != nil {
-		return x.SectionId
+		return x.Items
 	}
-	return ""
+	return nil
 }
 
-type GetNerdDataResponse struct {
+type UpsertMovieRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	DataJson      string                 `protobuf:"bytes,1,opt,name=data_json,json=dataJson,proto3" json:"data_json,omitempty"` // JSON-encoded data
-	Error         string                 `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
+	Title         string                 `protobuf:"bytes,1,opt,name=title,proto3" json:"title,omitempty"`
+	Year          int32                  `protobuf:"varint,2,opt,name=year,proto3" json:"year,omitempty"`                                                                                                           // Release year, 0 when unknown
+	ExternalIds   map[string]string      `protobuf:"bytes,3,rep,name=external_ids,json=externalIds,proto3" json:"external_ids,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"` // By source, e.g. tmdb, imdb
+	MediaFileId   string                 `protobuf:"bytes,4,opt,name=media_file_id,json=mediaFileId,proto3" json:"media_file_id,omitempty"`                                                                         // Optional: media file to link to the movie
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetNerdDataResponse) Reset() {
-	*x = GetNerdDataResponse{}
-	mi := &file_plugin_proto_msgTypes[82]
+func (x *UpsertMovieRequest) Reset() {
+	*x = UpsertMovieRequest{}
+	mi := &file_plugin_proto_msgTypes[105]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetNerdDataResponse) String() string {
+func (x *UpsertMovieRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetNerdDataResponse) ProtoMessage() {}
+func (*UpsertMovieRequest) ProtoMessage() {}
 
-func (x *GetNerdDataResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_plugin_proto_msgTypes[82]
+func (x *UpsertMovieRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_plugin_proto_msgTypes[105]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -4588,50 +6268,63 @@ func (x *GetNerdDataResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetNerdDataResponse.ProtoReflect.Descriptor instead.
-func (*GetNerdDataResponse) Descriptor() ([]byte, []int) {
-	return file_plugin_proto_rawDescGZIP(), []int{82}
+// Deprecated: Use UpsertMovieRequest.ProtoReflect.Descriptor instead.
+func (*UpsertMovieRequest) Descriptor() ([]byte, []int) {
+	return file_plugin_proto_rawDescGZIP(), []int{105}
 }
 
-func (x *GetNerdDataResponse) GetDataJson() string {
+func (x *UpsertMovieRequest) GetTitle() string {
 	if x != nil {
-		return x.DataJson
+		return x.Title
 	}
 	return ""
 }
 
-func (x *GetNerdDataResponse) GetError() string {
+func (x *UpsertMovieRequest) GetYear() int32 {
 	if x != nil {
-		return x.Error
+		return x.Year
+	}
+	return 0
+}
+
+func (x *UpsertMovieRequest) GetExternalIds() map[string]string {
+	if x != nil {
+		return x.ExternalIds
+	}
+	return nil
+}
+
+func (x *UpsertMovieRequest) GetMediaFileId() string {
+	if x != nil {
+		return x.MediaFileId
 	}
 	return ""
 }
 
-type GetMetricsRequest struct {
+type UpsertShowRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	SectionId     string                 `protobuf:"bytes,1,opt,name=section_id,json=sectionId,proto3" json:"section_id,omitempty"`
-	StartTime     int64                  `protobuf:"varint,2,opt,name=start_time,json=startTime,proto3" json:"start_time,omitempty"` // Unix timestamp
-	EndTime       int64                  `protobuf:"varint,3,opt,name=end_time,json=endTime,proto3" json:"end_time,omitempty"`       // Unix timestamp
-	Step          string                 `protobuf:"bytes,4,opt,name=step,proto3" json:"step,omitempty"`                             // Time step (e.g., "1m", "5m", "1h")
+	Title         string                 `protobuf:"bytes,1,opt,name=title,proto3" json:"title,omitempty"`
+	Year          int32                  `protobuf:"varint,2,opt,name=year,proto3" json:"year,omitempty"`                                                                                                           // First air year, 0 when unknown
+	ExternalIds   map[string]string      `protobuf:"bytes,3,rep,name=external_ids,json=externalIds,proto3" json:"external_ids,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"` // By source, e.g. tmdb, tvdb, imdb
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetMetricsRequest) Reset() {
-	*x = GetMetricsRequest{}
-	mi := &file_plugin_proto_msgTypes[83]
+func (x *UpsertShowRequest) Reset() {
+	*x = UpsertShowRequest{}
+	mi := &file_plugin_proto_msgTypes[106]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetMetricsRequest) String() string {
+func (x *UpsertShowRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetMetricsRequest) ProtoMessage() {}
+func (*UpsertShowRequest) ProtoMessage() {}
 
-func (x *GetMetricsRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_plugin_proto_msgTypes[83]
+func (x *UpsertShowRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_plugin_proto_msgTypes[106]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -4642,62 +6335,55 @@ func (x *GetMetricsRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetMetricsRequest.ProtoReflect.Descriptor instead.
-func (*GetMetricsRequest) Descriptor() ([]byte, []int) {
-	return file_plugin_proto_rawDescGZIP(), []int{83}
+// Deprecated: Use UpsertShowRequest.ProtoReflect.Descriptor instead.
+func (*UpsertShowRequest) Descriptor() ([]byte, []int) {
+	return file_plugin_proto_rawDescGZIP(), []int{106}
 }
 
-func (x *GetMetricsRequest) GetSectionId() string {
+func (x *UpsertShowRequest) GetTitle() string {
 	if x != nil {
-		return x.SectionId
+		return x.Title
 	}
 	return ""
 }
 
-func (x *GetMetricsRequest) GetStartTime() int64 {
-	if x != nil {
-		return x.StartTime
-	}
-	return 0
-}
-
-func (x *GetMetricsRequest) GetEndTime() int64 {
+func (x *UpsertShowRequest) GetYear() int32 {
 	if x != nil {
-		return x.EndTime
+		return x.Year
 	}
 	return 0
 }
 
-func (x *GetMetricsRequest) GetStep() string {
+func (x *UpsertShowRequest) GetExternalIds() map[string]string {
 	if x != nil {
-		return x.Step
+		return x.ExternalIds
 	}
-	return ""
+	return nil
 }
 
-type GetMetricsResponse struct {
+type UpsertSeasonRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Points        []*MetricPoint         `protobuf:"bytes,1,rep,name=points,proto3" json:"points,omitempty"`
-	Error         string                 `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
+	ShowId        string                 `protobuf:"bytes,1,opt,name=show_id,json=showId,proto3" json:"show_id,omitempty"`
+	SeasonNumber  int32                  `protobuf:"varint,2,opt,name=season_number,json=seasonNumber,proto3" json:"season_number,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetMetricsResponse) Reset() {
-	*x = GetMetricsResponse{}
-	mi := &file_plugin_proto_msgTypes[84]
+func (x *UpsertSeasonRequest) Reset() {
+	*x = UpsertSeasonRequest{}
+	mi := &file_plugin_proto_msgTypes[107]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetMetricsResponse) String() string {
+func (x *UpsertSeasonRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetMetricsResponse) ProtoMessage() {}
+func (*UpsertSeasonRequest) ProtoMessage() {}
 
-func (x *GetMetricsResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_plugin_proto_msgTypes[84]
+func (x *UpsertSeasonRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_plugin_proto_msgTypes[107]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -4708,55 +6394,51 @@ func (x *GetMetricsResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetMetricsResponse.ProtoReflect.Descriptor instead.
-func (*GetMetricsResponse) Descriptor() ([]byte, []int) {
-	return file_plugin_proto_rawDescGZIP(), []int{84}
+// Deprecated: Use UpsertSeasonRequest.ProtoReflect.Descriptor instead.
+func (*UpsertSeasonRequest) Descriptor() ([]byte, []int) {
+	return file_plugin_proto_rawDescGZIP(), []int{107}
 }
 
-func (x *GetMetricsResponse) GetPoints() []*MetricPoint {
+func (x *UpsertSeasonRequest) GetShowId() string {
 	if x != nil {
-		return x.Points
+		return x.ShowId
 	}
-	return nil
+	return ""
 }
 
-func (x *GetMetricsResponse) GetError() string {
+func (x *UpsertSeasonRequest) GetSeasonNumber() int32 {
 	if x != nil {
-		return x.Error
+		return x.SeasonNumber
 	}
-	return ""
+	return 0
 }
 
-type DashboardSection struct {
-	state         protoimpl.MessageState  `protogen:"open.v1"`
-	Id            string                  `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	PluginId      string                  `protobuf:"bytes,2,opt,name=plugin_id,json=pluginId,proto3" json:"plugin_id,omitempty"`
-	Type          string                  `protobuf:"bytes,3,opt,name=type,proto3" json:"type,omitempty"`
-	Title         string                  `protobuf:"bytes,4,opt,name=title,proto3" json:"title,omitempty"`
-	Description   string                  `protobuf:"bytes,5,opt,name=description,proto3" json:"description,omitempty"`
-	Icon          string                  `protobuf:"bytes,6,opt,name=icon,proto3" json:"icon,omitempty"`
-	Priority      int32                   `protobuf:"varint,7,opt,name=priority,proto3" json:"priority,omitempty"`
-	Config        *DashboardSectionConfig `protobuf:"bytes,8,opt,name=config,proto3" json:"config,omitempty"`
-	Manifest      *DashboardManifest      `protobuf:"bytes,9,opt,name=manifest,proto3" json:"manifest,omitempty"`
+type UpsertEpisodeRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SeasonId      string                 `protobuf:"bytes,1,opt,name=season_id,json=seasonId,proto3" json:"season_id,omitempty"`
+	EpisodeNumber int32                  `protobuf:"varint,2,opt,name=episode_number,json=episodeNumber,proto3" json:"episode_number,omitempty"`
+	Title         string                 `protobuf:"bytes,3,opt,name=title,proto3" json:"title,omitempty"` // Optional: replaces the stored title when set
+	ExternalIds   map[string]string      `protobuf:"bytes,4,rep,name=external_ids,json=externalIds,proto3" json:"external_ids,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	MediaFileId   string                 `protobuf:"bytes,5,opt,name=media_file_id,json=mediaFileId,proto3" json:"media_file_id,omitempty"` // Optional: media file to link to the episode
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *DashboardSection) Reset() {
-	*x = DashboardSection{}
-	mi := &file_plugin_proto_msgTypes[85]
+func (x *UpsertEpisodeRequest) Reset() {
+	*x = UpsertEpisodeRequest{}
+	mi := &file_plugin_proto_msgTypes[108]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *DashboardSection) String() string {
+func (x *UpsertEpisodeRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*DashboardSection) ProtoMessage() {}
+func (*UpsertEpisodeRequest) ProtoMessage() {}
 
-func (x *DashboardSection) ProtoReflect() protoreflect.Message {
-	mi := &file_plugin_proto_msgTypes[85]
+func (x *UpsertEpisodeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_plugin_proto_msgTypes[108]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -4767,101 +6449,127 @@ func (x *DashboardSection) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use DashboardSection.ProtoReflect.Descriptor instead.
-func (*DashboardSection) Descriptor() ([]byte, []int) {
-	return file_plugin_proto_rawDescGZIP(), []int{85}
+// Deprecated: Use UpsertEpisodeRequest.ProtoReflect.Descriptor instead.
+func (*UpsertEpisodeRequest) Descriptor() ([]byte, []int) {
+	return file_plugin_proto_rawDescGZIP(), []int{108}
 }
 
-func (x *DashboardSection) GetId() string {
+func (x *UpsertEpisodeRequest) GetSeasonId() string {
 	if x != nil {
-		return x.Id
+		return x.SeasonId
 	}
 	return ""
 }
 
-func (x *DashboardSection) GetPluginId() string {
+func (x *UpsertEpisodeRequest) GetEpisodeNumber() int32 {
 	if x != nil {
-		return x.PluginId
+		return x.EpisodeNumber
 	}
-	return ""
+	return 0
 }
 
-func (x *DashboardSection) GetType() string {
+func (x *UpsertEpisodeRequest) GetTitle() string {
 	if x != nil {
-		return x.Type
+		return x.Title
 	}
 	return ""
 }
 
-func (x *DashboardSection) GetTitle() string {
+func (x *UpsertEpisodeRequest) GetExternalIds() map[string]string {
 	if x != nil {
-		return x.Title
+		return x.ExternalIds
+	}
+	return nil
+}
+
+func (x *UpsertEpisodeRequest) GetMediaFileId() string {
+	if x != nil {
+		return x.MediaFileId
 	}
 	return ""
 }
 
-func (x *DashboardSection) GetDescription() string {
-	if x != nil {
-		return x.Description
-	}
-	return ""
-}
+type UpsertEntityResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Created       bool                   `protobuf:"varint,2,opt,name=created,proto3" json:"created,omitempty"` // False when an existing item matched
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpsertEntityResponse) Reset() {
+	*x = UpsertEntityResponse{}
+	mi := &file_plugin_proto_msgTypes[109]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpsertEntityResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpsertEntityResponse) ProtoMessage() {}
 
-func (x *DashboardSection) GetIcon() string {
+func (x *UpsertEntityResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_plugin_proto_msgTypes[109]
 	if x != nil {
-		return x.Icon
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return ""
+	return mi.MessageOf(x)
 }
 
-func (x *DashboardSection) GetPriority() int32 {
-	if x != nil {
-		return x.Priority
-	}
-	return 0
+// Deprecated: Use UpsertEntityResponse.ProtoReflect.Descriptor instead.
+func (*UpsertEntityResponse) Descriptor() ([]byte, []int) {
+	return file_plugin_proto_rawDescGZIP(), []int{109}
 }
 
-func (x *DashboardSection) GetConfig() *DashboardSectionConfig {
+func (x *UpsertEntityResponse) GetId() string {
 	if x != nil {
-		return x.Config
+		return x.Id
 	}
-	return nil
+	return ""
 }
 
-func (x *DashboardSection) GetManifest() *DashboardManifest {
+func (x *UpsertEntityResponse) GetCreated() bool {
 	if x != nil {
-		return x.Manifest
+		return x.Created
 	}
-	return nil
+	return false
 }
 
-type DashboardSectionConfig struct {
-	state            protoimpl.MessageState `protogen:"open.v1"`
-	RefreshInterval  int32                  `protobuf:"varint,1,opt,name=refresh_interval,json=refreshInterval,proto3" json:"refresh_interval,omitempty"`
-	SupportsRealtime bool                   `protobuf:"varint,2,opt,name=supports_realtime,json=supportsRealtime,proto3" json:"supports_realtime,omitempty"`
-	HasNerdPanel     bool                   `protobuf:"varint,3,opt,name=has_nerd_panel,json=hasNerdPanel,proto3" json:"has_nerd_panel,omitempty"`
-	RequiresAuth     bool                   `protobuf:"varint,4,opt,name=requires_auth,json=requiresAuth,proto3" json:"requires_auth,omitempty"`
-	MinRefreshRate   int32                  `protobuf:"varint,5,opt,name=min_refresh_rate,json=minRefreshRate,proto3" json:"min_refresh_rate,omitempty"`
-	MaxDataPoints    int32                  `protobuf:"varint,6,opt,name=max_data_points,json=maxDataPoints,proto3" json:"max_data_points,omitempty"`
-	unknownFields    protoimpl.UnknownFields
-	sizeCache        protoimpl.SizeCache
+type PersonInfo struct {
+	state              protoimpl.MessageState `protogen:"open.v1"`
+	Name               string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	ExternalIds        map[string]string      `protobuf:"bytes,2,rep,name=external_ids,json=externalIds,proto3" json:"external_ids,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"` // By source, e.g. tmdb, imdb
+	Biography          string                 `protobuf:"bytes,3,opt,name=biography,proto3" json:"biography,omitempty"`
+	Birthdate          string                 `protobuf:"bytes,4,opt,name=birthdate,proto3" json:"birthdate,omitempty"` // YYYY-MM-DD, empty when unknown
+	Deathdate          string                 `protobuf:"bytes,5,opt,name=deathdate,proto3" json:"deathdate,omitempty"` // YYYY-MM-DD, empty when unknown
+	PlaceOfBirth       string                 `protobuf:"bytes,6,opt,name=place_of_birth,json=placeOfBirth,proto3" json:"place_of_birth,omitempty"`
+	KnownForDepartment string                 `protobuf:"bytes,7,opt,name=known_for_department,json=knownForDepartment,proto3" json:"known_for_department,omitempty"` // e.g. Acting, Directing, Writing
+	ProfileUrl         string                 `protobuf:"bytes,8,opt,name=profile_url,json=profileUrl,proto3" json:"profile_url,omitempty"`                           // Optional: image the host downloads as the person's headshot
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
 }
 
-func (x *DashboardSectionConfig) Reset() {
-	*x = DashboardSectionConfig{}
-	mi := &file_plugin_proto_msgTypes[86]
+func (x *PersonInfo) Reset() {
+	*x = PersonInfo{}
+	mi := &file_plugin_proto_msgTypes[110]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *DashboardSectionConfig) String() string {
+func (x *PersonInfo) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*DashboardSectionConfig) ProtoMessage() {}
+func (*PersonInfo) ProtoMessage() {}
 
-func (x *DashboardSectionConfig) ProtoReflect() protoreflect.Message {
-	mi := &file_plugin_proto_msgTypes[86]
+func (x *PersonInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_plugin_proto_msgTypes[110]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -4872,77 +6580,89 @@ func (x *DashboardSectionConfig) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use DashboardSectionConfig.ProtoReflect.Descriptor instead.
-func (*DashboardSectionConfig) Descriptor() ([]byte, []int) {
-	return file_plugin_proto_rawDescGZIP(), []int{86}
+// Deprecated: Use PersonInfo.ProtoReflect.Descriptor instead.
+func (*PersonInfo) Descriptor() ([]byte, []int) {
+	return file_plugin_proto_rawDescGZIP(), []int{110}
 }
 
-func (x *DashboardSectionConfig) GetRefreshInterval() int32 {
+func (x *PersonInfo) GetName() string {
 	if x != nil {
-		return x.RefreshInterval
+		return x.Name
 	}
-	return 0
+	return ""
 }
 
-func (x *DashboardSectionConfig) GetSupportsRealtime() bool {
+func (x *PersonInfo) GetExternalIds() map[string]string {
 	if x != nil {
-		return x.SupportsRealtime
+		return x.ExternalIds
 	}
-	return false
+	return nil
 }
 
-func (x *DashboardSectionConfig) GetHasNerdPanel() bool {
+func (x *PersonInfo) GetBiography() string {
 	if x != nil {
-		return x.HasNerdPanel
+		return x.Biography
 	}
-	return false
+	return ""
 }
 
-func (x *DashboardSectionConfig) GetRequiresAuth() bool {
+func (x *PersonInfo) GetBirthdate() string {
 	if x != nil {
-		return x.RequiresAuth
+		return x.Birthdate
 	}
-	return false
+	return ""
 }
 
-func (x *DashboardSectionConfig) GetMinRefreshRate() int32 {
+func (x *PersonInfo) GetDeathdate() string {
 	if x != nil {
-		return x.MinRefreshRate
+		return x.Deathdate
 	}
-	return 0
+	return ""
 }
 
-func (x *DashboardSectionConfig) GetMaxDataPoints() int32 {
+func (x *PersonInfo) GetPlaceOfBirth() string {
 	if x != nil {
-		return x.MaxDataPoints
+		return x.PlaceOfBirth
 	}
-	return 0
+	return ""
 }
 
-type DashboardManifest struct {
+func (x *PersonInfo) GetKnownForDepartment() string {
+	if x != nil {
+		return x.KnownForDepartment
+	}
+	return ""
+}
+
+func (x *PersonInfo) GetProfileUrl() string {
+	if x != nil {
+		return x.ProfileUrl
+	}
+	return ""
+}
+
+type UpsertPersonRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	ComponentType string                 `protobuf:"bytes,1,opt,name=component_type,json=componentType,proto3" json:"component_type,omitempty"`
-	Actions       []*DashboardAction     `protobuf:"bytes,2,rep,name=actions,proto3" json:"actions,omitempty"`
-	UiSchema      map[string]string      `protobuf:"bytes,3,rep,name=ui_schema,json=uiSchema,proto3" json:"ui_schema,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"` // Simplified as string map
+	Person        *PersonInfo            `protobuf:"bytes,1,opt,name=person,proto3" json:"person,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *DashboardManifest) Reset() {
-	*x = DashboardManifest{}
-	mi := &file_plugin_proto_msgTypes[87]
+func (x *UpsertPersonRequest) Reset() {
+	*x = UpsertPersonRequest{}
+	mi := &file_plugin_proto_msgTypes[111]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *DashboardManifest) String() string {
+func (x *UpsertPersonRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*DashboardManifest) ProtoMessage() {}
+func (*UpsertPersonRequest) ProtoMessage() {}
 
-func (x *DashboardManifest) ProtoReflect() protoreflect.Message {
-	mi := &file_plugin_proto_msgTypes[87]
+func (x *UpsertPersonRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_plugin_proto_msgTypes[111]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -4953,62 +6673,44 @@ func (x *DashboardManifest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use DashboardManifest.ProtoReflect.Descriptor instead.
-func (*DashboardManifest) Descriptor() ([]byte, []int) {
-	return file_plugin_proto_rawDescGZIP(), []int{87}
-}
-
-func (x *DashboardManifest) GetComponentType() string {
-	if x != nil {
-		return x.ComponentType
-	}
-	return ""
-}
-
-func (x *DashboardManifest) GetActions() []*DashboardAction {
-	if x != nil {
-		return x.Actions
-	}
-	return nil
+// Deprecated: Use UpsertPersonRequest.ProtoReflect.Descriptor instead.
+func (*UpsertPersonRequest) Descriptor() ([]byte, []int) {
+	return file_plugin_proto_rawDescGZIP(), []int{111}
 }
 
-func (x *DashboardManifest) GetUiSchema() map[string]string {
+func (x *UpsertPersonRequest) GetPerson() *PersonInfo {
 	if x != nil {
-		return x.UiSchema
+		return x.Person
 	}
 	return nil
 }
 
-type DashboardAction struct {
+type Credit struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	Label         string                 `protobuf:"bytes,2,opt,name=label,proto3" json:"label,omitempty"`
-	Icon          string                 `protobuf:"bytes,3,opt,name=icon,proto3" json:"icon,omitempty"`
-	Style         string                 `protobuf:"bytes,4,opt,name=style,proto3" json:"style,omitempty"`
-	Endpoint      string                 `protobuf:"bytes,5,opt,name=endpoint,proto3" json:"endpoint,omitempty"`
-	Method        string                 `protobuf:"bytes,6,opt,name=method,proto3" json:"method,omitempty"`
-	Confirm       bool                   `protobuf:"varint,7,opt,name=confirm,proto3" json:"confirm,omitempty"`
-	PayloadJson   string                 `protobuf:"bytes,8,opt,name=payload_json,json=payloadJson,proto3" json:"payload_json,omitempty"` // JSON-encoded payload
-	Shortcut      string                 `protobuf:"bytes,9,opt,name=shortcut,proto3" json:"shortcut,omitempty"`
+	Person        *PersonInfo            `protobuf:"bytes,1,opt,name=person,proto3" json:"person,omitempty"`
+	Role          string                 `protobuf:"bytes,2,opt,name=role,proto3" json:"role,omitempty"`             // actor, or the crew job, e.g. director, writer
+	Character     string                 `protobuf:"bytes,3,opt,name=character,proto3" json:"character,omitempty"`   // Actors only
+	Department    string                 `protobuf:"bytes,4,opt,name=department,proto3" json:"department,omitempty"` // e.g. Acting, Directing, Writing
+	Order         int32                  `protobuf:"varint,5,opt,name=order,proto3" json:"order,omitempty"`          // Billing order, lowest first
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *DashboardAction) Reset() {
-	*x = DashboardAction{}
-	mi := &file_plugin_proto_msgTypes[88]
+func (x *Credit) Reset() {
+	*x = Credit{}
+	mi := &file_plugin_proto_msgTypes[112]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *DashboardAction) String() string {
+func (x *Credit) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*DashboardAction) ProtoMessage() {}
+func (*Credit) ProtoMessage() {}
 
-func (x *DashboardAction) ProtoReflect() protoreflect.Message {
-	mi := &file_plugin_proto_msgTypes[88]
+func (x *Credit) ProtoReflect() protoreflect.Message {
+	mi := &file_plugin_proto_msgTypes[112]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -5019,99 +6721,121 @@ func (x *DashboardAction) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use DashboardAction.ProtoReflect.Descriptor instead.
-func (*DashboardAction) Descriptor() ([]byte, []int) {
-	return file_plugin_proto_rawDescGZIP(), []int{88}
+// Deprecated: Use Credit.ProtoReflect.Descriptor instead.
+func (*Credit) Descriptor() ([]byte, []int) {
+	return file_plugin_proto_rawDescGZIP(), []int{112}
 }
 
-func (x *DashboardAction) GetId() string {
+func (x *Credit) GetPerson() *PersonInfo {
 	if x != nil {
-		return x.Id
+		return x.Person
 	}
-	return ""
+	return nil
 }
 
-func (x *DashboardAction) GetLabel() string {
+func (x *Credit) GetRole() string {
 	if x != nil {
-		return x.Label
+		return x.Role
 	}
 	return ""
 }
 
-func (x *DashboardAction) GetIcon() string {
+func (x *Credit) GetCharacter() string {
 	if x != nil {
-		return x.Icon
+		return x.Character
 	}
 	return ""
 }
 
-func (x *DashboardAction) GetStyle() string {
+func (x *Credit) GetDepartment() string {
 	if x != nil {
-		return x.Style
+		return x.Department
 	}
 	return ""
 }
 
-func (x *DashboardAction) GetEndpoint() string {
+func (x *Credit) GetOrder() int32 {
 	if x != nil {
-		return x.Endpoint
+		return x.Order
 	}
-	return ""
+	return 0
 }
 
-func (x *DashboardAction) GetMethod() string {
-	if x != nil {
-		return x.Method
-	}
-	return ""
+type SetCreditsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	MediaFileId   string                 `protobuf:"bytes,1,opt,name=media_file_id,json=mediaFileId,proto3" json:"media_file_id,omitempty"` // Credits go to the movie or episode of the file
+	Credits       []*Credit              `protobuf:"bytes,2,rep,name=credits,proto3" json:"credits,omitempty"`                              // Replace any credits the item had
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
-func (x *DashboardAction) GetConfirm() bool {
+func (x *SetCreditsRequest) Reset() {
+	*x = SetCreditsRequest{}
+	mi := &file_plugin_proto_msgTypes[113]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetCreditsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetCreditsRequest) ProtoMessage() {}
+
+func (x *SetCreditsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_plugin_proto_msgTypes[113]
 	if x != nil {
-		return x.Confirm
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return false
+	return mi.MessageOf(x)
 }
 
-func (x *DashboardAction) GetPayloadJson() string {
+// Deprecated: Use SetCreditsRequest.ProtoReflect.Descriptor instead.
+func (*SetCreditsRequest) Descriptor() ([]byte, []int) {
+	return file_plugin_proto_rawDescGZIP(), []int{113}
+}
+
+func (x *SetCreditsRequest) GetMediaFileId() string {
 	if x != nil {
-		return x.PayloadJson
+		return x.MediaFileId
 	}
 	return ""
 }
 
-func (x *DashboardAction) GetShortcut() string {
+func (x *SetCreditsRequest) GetCredits() []*Credit {
 	if x != nil {
-		return x.Shortcut
+		return x.Credits
 	}
-	return ""
+	return nil
 }
 
-type MetricPoint struct {
+type SetCreditsResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Timestamp     int64                  `protobuf:"varint,1,opt,name=timestamp,proto3" json:"timestamp,omitempty"` // Unix timestamp
-	Value         float64                `protobuf:"fixed64,2,opt,name=value,proto3" json:"value,omitempty"`
-	Labels        map[string]string      `protobuf:"bytes,3,rep,name=labels,proto3" json:"labels,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
-	MetadataJson  string                 `protobuf:"bytes,4,opt,name=metadata_json,json=metadataJson,proto3" json:"metadata_json,omitempty"` // JSON-encoded metadata
+	MediaId       string                 `protobuf:"bytes,1,opt,name=media_id,json=mediaId,proto3" json:"media_id,omitempty"`
+	PersonIds     []string               `protobuf:"bytes,2,rep,name=person_ids,json=personIds,proto3" json:"person_ids,omitempty"` // Person of each credit, in request order
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *MetricPoint) Reset() {
-	*x = MetricPoint{}
-	mi := &file_plugin_proto_msgTypes[89]
+func (x *SetCreditsResponse) Reset() {
+	*x = SetCreditsResponse{}
+	mi := &file_plugin_proto_msgTypes[114]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *MetricPoint) String() string {
+func (x *SetCreditsResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*MetricPoint) ProtoMessage() {}
+func (*SetCreditsResponse) ProtoMessage() {}
 
-func (x *MetricPoint) ProtoReflect() protoreflect.Message {
-	mi := &file_plugin_proto_msgTypes[89]
+func (x *SetCreditsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_plugin_proto_msgTypes[114]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -5122,39 +6846,25 @@ func (x *MetricPoint) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use MetricPoint.ProtoReflect.Descriptor instead.
-func (*MetricPoint) Descriptor() ([]byte, []int) {
-	return file_plugin_proto_rawDescGZIP(), []int{89}
-}
-
-func (x *MetricPoint) GetTimestamp() int64 {
-	if x != nil {
-		return x.Timestamp
-	}
-	return 0
+// Deprecated: Use SetCreditsResponse.ProtoReflect.Descriptor instead.
+func (*SetCreditsResponse) Descriptor() ([]byte, []int) {
+	return file_plugin_proto_rawDescGZIP(), []int{114}
 }
 
-func (x *MetricPoint) GetValue() float64 {
+func (x *SetCreditsResponse) GetMediaId() string {
 	if x != nil {
-		return x.Value
+		return x.MediaId
 	}
-	return 0
+	return ""
 }
 
-func (x *MetricPoint) GetLabels() map[string]string {
+func (x *SetCreditsResponse) GetPersonIds() []string {
 	if x != nil {
-		return x.Labels
+		return x.PersonIds
 	}
 	return nil
 }
 
-func (x *MetricPoint) GetMetadataJson() string {
-	if x != nil {
-		return x.MetadataJson
-	}
-	return ""
-}
-
 var File_plugin_proto protoreflect.FileDescriptor
 
 const file_plugin_proto_rawDesc = "" +
@@ -5203,7 +6913,34 @@ const file_plugin_proto_rawDesc = "" +
 	"\basset_id\x18\x01 \x01(\rR\aassetId\"E\n" +
 	"\x13RemoveAssetResponse\x12\x18\n" +
 	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x14\n" +
-	"\x05error\x18\x02 \x01(\tR\x05error\"\xaf\x01\n" +
+	"\x05error\x18\x02 \x01(\tR\x05error\"\xdf\x03\n" +
+	"\x14DownloadAssetRequest\x12\x10\n" +
+	"\x03url\x18\x01 \x01(\tR\x03url\x12\"\n" +
+	"\rmedia_file_id\x18\x02 \x01(\tR\vmediaFileId\x12\x1d\n" +
+	"\n" +
+	"asset_type\x18\x03 \x01(\tR\tassetType\x12\x1a\n" +
+	"\bcategory\x18\x04 \x01(\tR\bcategory\x12\x18\n" +
+	"\asubtype\x18\x05 \x01(\tR\asubtype\x12F\n" +
+	"\bmetadata\x18\x06 \x03(\v2*.plugin.DownloadAssetRequest.MetadataEntryR\bmetadata\x12\x1b\n" +
+	"\tplugin_id\x18\a \x01(\tR\bpluginId\x12C\n" +
+	"\aheaders\x18\b \x03(\v2).plugin.DownloadAssetRequest.HeadersEntryR\aheaders\x12\x19\n" +
+	"\bmax_size\x18\t \x01(\x03R\amaxSize\x1a;\n" +
+	"\rMetadataEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\x1a:\n" +
+	"\fHeadersEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\xed\x01\n" +
+	"\x15DownloadAssetResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x14\n" +
+	"\x05error\x18\x02 \x01(\tR\x05error\x12\x19\n" +
+	"\basset_id\x18\x03 \x01(\rR\aassetId\x12\x12\n" +
+	"\x04hash\x18\x04 \x01(\tR\x04hash\x12#\n" +
+	"\rrelative_path\x18\x05 \x01(\tR\frelativePath\x12\x12\n" +
+	"\x04size\x18\x06 \x01(\x03R\x04size\x12\x1b\n" +
+	"\tmime_type\x18\a \x01(\tR\bmimeType\x12\x1f\n" +
+	"\vstatus_code\x18\b \x01(\x05R\n" +
+	"statusCode\"\xaf\x01\n" +
 	"\rSearchRequest\x126\n" +
 	"\x05query\x18\x01 \x03(\v2 .plugin.SearchRequest.QueryEntryR\x05query\x12\x14\n" +
 	"\x05limit\x18\x02 \x01(\rR\x05limit\x12\x16\n" +
@@ -5278,8 +7015,11 @@ const file_plugin_proto_rawDesc = "" +
 	"\bmetadata\x18\x03 \x03(\v2/.plugin.OnMediaFileScannedRequest.MetadataEntryR\bmetadata\x1a;\n" +
 	"\rMetadataEntry\x12\x10\n" +
 	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
-	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\x1c\n" +
-	"\x1aOnMediaFileScannedResponse\"x\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"d\n" +
+	"\x1aOnMediaFileScannedResponse\x12\x16\n" +
+	"\x06status\x18\x01 \x01(\tR\x06status\x12\x16\n" +
+	"\x06reason\x18\x02 \x01(\tR\x06reason\x12\x16\n" +
+	"\x06detail\x18\x03 \x01(\tR\x06detail\"x\n" +
 	"\x14OnScanStartedRequest\x12\x1e\n" +
 	"\vscan_job_id\x18\x01 \x01(\rR\tscanJobId\x12\x1d\n" +
 	"\n" +
@@ -5295,7 +7035,35 @@ const file_plugin_proto_rawDesc = "" +
 	"StatsEntry\x12\x10\n" +
 	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
 	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\x19\n" +
-	"\x17OnScanCompletedResponse\"\x12\n" +
+	"\x17OnScanCompletedResponse\"\xe6\x01\n" +
+	"\x19OnMediaFileRemovedRequest\x12\"\n" +
+	"\rmedia_file_id\x18\x01 \x01(\tR\vmediaFileId\x12\x1b\n" +
+	"\tfile_path\x18\x02 \x01(\tR\bfilePath\x12K\n" +
+	"\bmetadata\x18\x03 \x03(\v2/.plugin.OnMediaFileRemovedRequest.MetadataEntryR\bmetadata\x1a;\n" +
+	"\rMetadataEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\x1c\n" +
+	"\x1aOnMediaFileRemovedResponse\"\xe6\x01\n" +
+	"\x19OnMediaFileUpdatedRequest\x12\"\n" +
+	"\rmedia_file_id\x18\x01 \x01(\tR\vmediaFileId\x12\x1b\n" +
+	"\tfile_path\x18\x02 \x01(\tR\bfilePath\x12K\n" +
+	"\bmetadata\x18\x03 \x03(\v2/.plugin.OnMediaFileUpdatedRequest.MetadataEntryR\bmetadata\x1a;\n" +
+	"\rMetadataEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"d\n" +
+	"\x1aOnMediaFileUpdatedResponse\x12\x16\n" +
+	"\x06status\x18\x01 \x01(\tR\x06status\x12\x16\n" +
+	"\x06reason\x18\x02 \x01(\tR\x06reason\x12\x16\n" +
+	"\x06detail\x18\x03 \x01(\tR\x06detail\"U\n" +
+	"\x1aOnMediaFilesScannedRequest\x127\n" +
+	"\x05files\x18\x01 \x03(\v2!.plugin.OnMediaFileScannedRequestR\x05files\"T\n" +
+	"\x1bOnMediaFilesScannedResponse\x125\n" +
+	"\aresults\x18\x01 \x03(\v2\x1b.plugin.MediaFileHookResultR\aresults\"\x81\x01\n" +
+	"\x13MediaFileHookResult\x12\"\n" +
+	"\rmedia_file_id\x18\x01 \x01(\tR\vmediaFileId\x12\x16\n" +
+	"\x06status\x18\x02 \x01(\tR\x06status\x12\x16\n" +
+	"\x06reason\x18\x03 \x01(\tR\x06reason\x12\x16\n" +
+	"\x06detail\x18\x04 \x01(\tR\x06detail\"\x12\n" +
 	"\x10GetModelsRequest\"4\n" +
 	"\x11GetModelsResponse\x12\x1f\n" +
 	"\vmodel_names\x18\x01 \x03(\tR\n" +
@@ -5555,7 +7323,121 @@ const file_plugin_proto_rawDesc = "" +
 	"\rmetadata_json\x18\x04 \x01(\tR\fmetadataJson\x1a9\n" +
 	"\vLabelsEntry\x12\x10\n" +
 	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
-	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x012\xa9\x02\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\xbb\x04\n" +
+	"\rMediaFileInfo\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x19\n" +
+	"\bmedia_id\x18\x02 \x01(\tR\amediaId\x12\x1d\n" +
+	"\n" +
+	"media_type\x18\x03 \x01(\tR\tmediaType\x12\x1d\n" +
+	"\n" +
+	"library_id\x18\x04 \x01(\rR\tlibraryId\x12!\n" +
+	"\flibrary_type\x18\x05 \x01(\tR\vlibraryType\x12!\n" +
+	"\flibrary_path\x18\x06 \x01(\tR\vlibraryPath\x12\x12\n" +
+	"\x04path\x18\a \x01(\tR\x04path\x12\x1c\n" +
+	"\tcontainer\x18\b \x01(\tR\tcontainer\x12\x1f\n" +
+	"\vvideo_codec\x18\t \x01(\tR\n" +
+	"videoCodec\x12\x1f\n" +
+	"\vaudio_codec\x18\n" +
+	" \x01(\tR\n" +
+	"audioCodec\x12\x1e\n" +
+	"\n" +
+	"resolution\x18\v \x01(\tR\n" +
+	"resolution\x12\x1a\n" +
+	"\bduration\x18\f \x01(\x05R\bduration\x12\x1d\n" +
+	"\n" +
+	"size_bytes\x18\r \x01(\x03R\tsizeBytes\x12\x14\n" +
+	"\x05title\x18\x0e \x01(\tR\x05title\x12\x12\n" +
+	"\x04year\x18\x0f \x01(\x05R\x04year\x12\x17\n" +
+	"\ashow_id\x18\x10 \x01(\tR\x06showId\x12\x1d\n" +
+	"\n" +
+	"show_title\x18\x11 \x01(\tR\tshowTitle\x12#\n" +
+	"\rseason_number\x18\x12 \x01(\x05R\fseasonNumber\x12%\n" +
+	"\x0eepisode_number\x18\x13 \x01(\x05R\repisodeNumber\"9\n" +
+	"\x13GetMediaFileRequest\x12\"\n" +
+	"\rmedia_file_id\x18\x01 \x01(\tR\vmediaFileId\"b\n" +
+	"\x14GetMediaFileResponse\x12\x14\n" +
+	"\x05found\x18\x01 \x01(\bR\x05found\x124\n" +
+	"\n" +
+	"media_file\x18\x02 \x01(\v2\x15.plugin.MediaFileInfoR\tmediaFile\"\xf3\x01\n" +
+	"\rMediaItemInfo\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1d\n" +
+	"\n" +
+	"media_type\x18\x02 \x01(\tR\tmediaType\x12\x14\n" +
+	"\x05title\x18\x03 \x01(\tR\x05title\x12\x12\n" +
+	"\x04year\x18\x04 \x01(\x05R\x04year\x12I\n" +
+	"\fexternal_ids\x18\x05 \x03(\v2&.plugin.MediaItemInfo.ExternalIdsEntryR\vexternalIds\x1a>\n" +
+	"\x10ExternalIdsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"v\n" +
+	"\x1cFindMediaByExternalIDRequest\x12\x16\n" +
+	"\x06source\x18\x01 \x01(\tR\x06source\x12\x1f\n" +
+	"\vexternal_id\x18\x02 \x01(\tR\n" +
+	"externalId\x12\x1d\n" +
+	"\n" +
+	"media_type\x18\x03 \x01(\tR\tmediaType\"L\n" +
+	"\x1dFindMediaByExternalIDResponse\x12+\n" +
+	"\x05items\x18\x01 \x03(\v2\x15.plugin.MediaItemInfoR\x05items\"\xf2\x01\n" +
+	"\x12UpsertMovieRequest\x12\x14\n" +
+	"\x05title\x18\x01 \x01(\tR\x05title\x12\x12\n" +
+	"\x04year\x18\x02 \x01(\x05R\x04year\x12N\n" +
+	"\fexternal_ids\x18\x03 \x03(\v2+.plugin.UpsertMovieRequest.ExternalIdsEntryR\vexternalIds\x12\"\n" +
+	"\rmedia_file_id\x18\x04 \x01(\tR\vmediaFileId\x1a>\n" +
+	"\x10ExternalIdsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\xcc\x01\n" +
+	"\x11UpsertShowRequest\x12\x14\n" +
+	"\x05title\x18\x01 \x01(\tR\x05title\x12\x12\n" +
+	"\x04year\x18\x02 \x01(\x05R\x04year\x12M\n" +
+	"\fexternal_ids\x18\x03 \x03(\v2*.plugin.UpsertShowRequest.ExternalIdsEntryR\vexternalIds\x1a>\n" +
+	"\x10ExternalIdsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"S\n" +
+	"\x13UpsertSeasonRequest\x12\x17\n" +
+	"\ashow_id\x18\x01 \x01(\tR\x06showId\x12#\n" +
+	"\rseason_number\x18\x02 \x01(\x05R\fseasonNumber\"\xa6\x02\n" +
+	"\x14UpsertEpisodeRequest\x12\x1b\n" +
+	"\tseason_id\x18\x01 \x01(\tR\bseasonId\x12%\n" +
+	"\x0eepisode_number\x18\x02 \x01(\x05R\repisodeNumber\x12\x14\n" +
+	"\x05title\x18\x03 \x01(\tR\x05title\x12P\n" +
+	"\fexternal_ids\x18\x04 \x03(\v2-.plugin.UpsertEpisodeRequest.ExternalIdsEntryR\vexternalIds\x12\"\n" +
+	"\rmedia_file_id\x18\x05 \x01(\tR\vmediaFileId\x1a>\n" +
+	"\x10ExternalIdsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"@\n" +
+	"\x14UpsertEntityResponse\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x18\n" +
+	"\acreated\x18\x02 \x01(\bR\acreated\"\xfb\x02\n" +
+	"\n" +
+	"PersonInfo\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12F\n" +
+	"\fexternal_ids\x18\x02 \x03(\v2#.plugin.PersonInfo.ExternalIdsEntryR\vexternalIds\x12\x1c\n" +
+	"\tbiography\x18\x03 \x01(\tR\tbiography\x12\x1c\n" +
+	"\tbirthdate\x18\x04 \x01(\tR\tbirthdate\x12\x1c\n" +
+	"\tdeathdate\x18\x05 \x01(\tR\tdeathdate\x12$\n" +
+	"\x0eplace_of_birth\x18\x06 \x01(\tR\fplaceOfBirth\x120\n" +
+	"\x14known_for_department\x18\a \x01(\tR\x12knownForDepartment\x12\x1f\n" +
+	"\vprofile_url\x18\b \x01(\tR\n" +
+	"profileUrl\x1a>\n" +
+	"\x10ExternalIdsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"A\n" +
+	"\x13UpsertPersonRequest\x12*\n" +
+	"\x06person\x18\x01 \x01(\v2\x12.plugin.PersonInfoR\x06person\"\x9c\x01\n" +
+	"\x06Credit\x12*\n" +
+	"\x06person\x18\x01 \x01(\v2\x12.plugin.PersonInfoR\x06person\x12\x12\n" +
+	"\x04role\x18\x02 \x01(\tR\x04role\x12\x1c\n" +
+	"\tcharacter\x18\x03 \x01(\tR\tcharacter\x12\x1e\n" +
+	"\n" +
+	"department\x18\x04 \x01(\tR\n" +
+	"department\x12\x14\n" +
+	"\x05order\x18\x05 \x01(\x05R\x05order\"a\n" +
+	"\x11SetCreditsRequest\x12\"\n" +
+	"\rmedia_file_id\x18\x01 \x01(\tR\vmediaFileId\x12(\n" +
+	"\acredits\x18\x02 \x03(\v2\x0e.plugin.CreditR\acredits\"N\n" +
+	"\x12SetCreditsResponse\x12\x19\n" +
+	"\bmedia_id\x18\x01 \x01(\tR\amediaId\x12\x1d\n" +
+	"\n" +
+	"person_ids\x18\x02 \x03(\tR\tpersonIds2\xa9\x02\n" +
 	"\rPluginService\x12C\n" +
 	"\n" +
 	"Initialize\x12\x19.plugin.InitializeRequest\x1a\x1a.plugin.InitializeResponse\x124\n" +
@@ -5566,15 +7448,19 @@ const file_plugin_proto_rawDesc = "" +
 	"\x16MetadataScraperService\x12@\n" +
 	"\tCanHandle\x12\x18.plugin.CanHandleRequest\x1a\x19.plugin.CanHandleResponse\x12R\n" +
 	"\x0fExtractMetadata\x12\x1e.plugin.ExtractMetadataRequest\x1a\x1f.plugin.ExtractMetadataResponse\x12X\n" +
-	"\x11GetSupportedTypes\x12 .plugin.GetSupportedTypesRequest\x1a!.plugin.GetSupportedTypesResponse2\x93\x02\n" +
+	"\x11GetSupportedTypes\x12 .plugin.GetSupportedTypesRequest\x1a!.plugin.GetSupportedTypesResponse2\xad\x04\n" +
 	"\x12ScannerHookService\x12[\n" +
 	"\x12OnMediaFileScanned\x12!.plugin.OnMediaFileScannedRequest\x1a\".plugin.OnMediaFileScannedResponse\x12L\n" +
 	"\rOnScanStarted\x12\x1c.plugin.OnScanStartedRequest\x1a\x1d.plugin.OnScanStartedResponse\x12R\n" +
-	"\x0fOnScanCompleted\x12\x1e.plugin.OnScanCompletedRequest\x1a\x1f.plugin.OnScanCompletedResponse2\xe0\x01\n" +
+	"\x0fOnScanCompleted\x12\x1e.plugin.OnScanCompletedRequest\x1a\x1f.plugin.OnScanCompletedResponse\x12[\n" +
+	"\x12OnMediaFileRemoved\x12!.plugin.OnMediaFileRemovedRequest\x1a\".plugin.OnMediaFileRemovedResponse\x12[\n" +
+	"\x12OnMediaFileUpdated\x12!.plugin.OnMediaFileUpdatedRequest\x1a\".plugin.OnMediaFileUpdatedResponse\x12^\n" +
+	"\x13OnMediaFilesScanned\x12\".plugin.OnMediaFilesScannedRequest\x1a#.plugin.OnMediaFilesScannedResponse2\xae\x02\n" +
 	"\fAssetService\x12@\n" +
 	"\tSaveAsset\x12\x18.plugin.SaveAssetRequest\x1a\x19.plugin.SaveAssetResponse\x12F\n" +
 	"\vAssetExists\x12\x1a.plugin.AssetExistsRequest\x1a\x1b.plugin.AssetExistsResponse\x12F\n" +
-	"\vRemoveAsset\x12\x1a.plugin.RemoveAssetRequest\x1a\x1b.plugin.RemoveAssetResponse2\xce\x01\n" +
+	"\vRemoveAsset\x12\x1a.plugin.RemoveAssetRequest\x1a\x1b.plugin.RemoveAssetResponse\x12L\n" +
+	"\rDownloadAsset\x12\x1c.plugin.DownloadAssetRequest\x1a\x1d.plugin.DownloadAssetResponse2\xce\x01\n" +
 	"\x0fDatabaseService\x12@\n" +
 	"\tGetModels\x12\x18.plugin.GetModelsRequest\x1a\x19.plugin.GetModelsResponse\x12:\n" +
 	"\aMigrate\x12\x16.plugin.MigrateRequest\x1a\x17.plugin.MigrateResponse\x12=\n" +
@@ -5604,7 +7490,19 @@ const file_plugin_proto_rawDesc = "" +
 	"\vGetMainData\x12\x1a.plugin.GetMainDataRequest\x1a\x1b.plugin.GetMainDataResponse\x12F\n" +
 	"\vGetNerdData\x12\x1a.plugin.GetNerdDataRequest\x1a\x1b.plugin.GetNerdDataResponse\x12C\n" +
 	"\n" +
-	"GetMetrics\x12\x19.plugin.GetMetricsRequest\x1a\x1a.plugin.GetMetricsResponseB-Z+github.com/mantonx/viewra/pkg/plugins/protob\x06proto3"
+	"GetMetrics\x12\x19.plugin.GetMetricsRequest\x1a\x1a.plugin.GetMetricsResponse2\xc3\x01\n" +
+	"\x10MediaDataService\x12I\n" +
+	"\fGetMediaFile\x12\x1b.plugin.GetMediaFileRequest\x1a\x1c.plugin.GetMediaFileResponse\x12d\n" +
+	"\x15FindMediaByExternalID\x12$.plugin.FindMediaByExternalIDRequest\x1a%.plugin.FindMediaByExternalIDResponse2\xcc\x03\n" +
+	"\x12MediaEntityService\x12G\n" +
+	"\vUpsertMovie\x12\x1a.plugin.UpsertMovieRequest\x1a\x1c.plugin.UpsertEntityResponse\x12E\n" +
+	"\n" +
+	"UpsertShow\x12\x19.plugin.UpsertShowRequest\x1a\x1c.plugin.UpsertEntityResponse\x12I\n" +
+	"\fUpsertSeason\x12\x1b.plugin.UpsertSeasonRequest\x1a\x1c.plugin.UpsertEntityResponse\x12K\n" +
+	"\rUpsertEpisode\x12\x1c.plugin.UpsertEpisodeRequest\x1a\x1c.plugin.UpsertEntityResponse\x12I\n" +
+	"\fUpsertPerson\x12\x1b.plugin.UpsertPersonRequest\x1a\x1c.plugin.UpsertEntityResponse\x12C\n" +
+	"\n" +
+	"SetCredits\x12\x19.plugin.SetCreditsRequest\x1a\x1a.plugin.SetCreditsResponseB-Z+github.com/mantonx/viewra/pkg/plugins/protob\x06proto3"
 
 var (
 	file_plugin_proto_rawDescOnce sync.Once
@@ -5618,7 +7516,7 @@ func file_plugin_proto_rawDescGZIP() []byte {
 	return file_plugin_proto_rawDescData
 }
 
-var file_plugin_proto_msgTypes = make([]protoimpl.MessageInfo, 101)
+var file_plugin_proto_msgTypes = make([]protoimpl.MessageInfo, 135)
 var file_plugin_proto_goTypes = []any{
 	(*APIRoute)(nil),                        // 0: plugin.APIRoute
 	(*GetRegisteredRoutesRequest)(nil),      // 1: plugin.GetRegisteredRoutesRequest
@@ -5629,211 +7527,285 @@ var file_plugin_proto_goTypes = []any{
 	(*AssetExistsResponse)(nil),             // 6: plugin.AssetExistsResponse
 	(*RemoveAssetRequest)(nil),              // 7: plugin.RemoveAssetRequest
 	(*RemoveAssetResponse)(nil),             // 8: plugin.RemoveAssetResponse
-	(*SearchRequest)(nil),                   // 9: plugin.SearchRequest
-	(*SearchResponse)(nil),                  // 10: plugin.SearchResponse
-	(*SearchResult)(nil),                    // 11: plugin.SearchResult
-	(*GetSearchCapabilitiesRequest)(nil),    // 12: plugin.GetSearchCapabilitiesRequest
-	(*GetSearchCapabilitiesResponse)(nil),   // 13: plugin.GetSearchCapabilitiesResponse
-	(*InitializeRequest)(nil),               // 14: plugin.InitializeRequest
-	(*InitializeResponse)(nil),              // 15: plugin.InitializeResponse
-	(*StartRequest)(nil),                    // 16: plugin.StartRequest
-	(*StartResponse)(nil),                   // 17: plugin.StartResponse
-	(*StopRequest)(nil),                     // 18: plugin.StopRequest
-	(*StopResponse)(nil),                    // 19: plugin.StopResponse
-	(*InfoRequest)(nil),                     // 20: plugin.InfoRequest
-	(*InfoResponse)(nil),                    // 21: plugin.InfoResponse
-	(*HealthRequest)(nil),                   // 22: plugin.HealthRequest
-	(*HealthResponse)(nil),                  // 23: plugin.HealthResponse
-	(*CanHandleRequest)(nil),                // 24: plugin.CanHandleRequest
-	(*CanHandleResponse)(nil),               // 25: plugin.CanHandleResponse
-	(*ExtractMetadataRequest)(nil),          // 26: plugin.ExtractMetadataRequest
-	(*ExtractMetadataResponse)(nil),         // 27: plugin.ExtractMetadataResponse
-	(*GetSupportedTypesRequest)(nil),        // 28: plugin.GetSupportedTypesRequest
-	(*GetSupportedTypesResponse)(nil),       // 29: plugin.GetSupportedTypesResponse
-	(*OnMediaFileScannedRequest)(nil),       // 30: plugin.OnMediaFileScannedRequest
-	(*OnMediaFileScannedResponse)(nil),      // 31: plugin.OnMediaFileScannedResponse
-	(*OnScanStartedRequest)(nil),            // 32: plugin.OnScanStartedRequest
-	(*OnScanStartedResponse)(nil),           // 33: plugin.OnScanStartedResponse
-	(*OnScanCompletedRequest)(nil),          // 34: plugin.OnScanCompletedRequest
-	(*OnScanCompletedResponse)(nil),         // 35: plugin.OnScanCompletedResponse
-	(*GetModelsRequest)(nil),                // 36: plugin.GetModelsRequest
-	(*GetModelsResponse)(nil),               // 37: plugin.GetModelsResponse
-	(*MigrateRequest)(nil),                  // 38: plugin.MigrateRequest
-	(*MigrateResponse)(nil),                 // 39: plugin.MigrateResponse
-	(*RollbackRequest)(nil),                 // 40: plugin.RollbackRequest
-	(*RollbackResponse)(nil),                // 41: plugin.RollbackResponse
-	(*GetAdminPagesRequest)(nil),            // 42: plugin.GetAdminPagesRequest
-	(*GetAdminPagesResponse)(nil),           // 43: plugin.GetAdminPagesResponse
-	(*RegisterRoutesRequest)(nil),           // 44: plugin.RegisterRoutesRequest
-	(*RegisterRoutesResponse)(nil),          // 45: plugin.RegisterRoutesResponse
-	(*PluginContext)(nil),                   // 46: plugin.PluginContext
-	(*PluginInfo)(nil),                      // 47: plugin.PluginInfo
-	(*AdminPageConfig)(nil),                 // 48: plugin.AdminPageConfig
-	(*GetProviderInfoRequest)(nil),          // 49: plugin.GetProviderInfoRequest
-	(*GetProviderInfoResponse)(nil),         // 50: plugin.GetProviderInfoResponse
-	(*ProviderInfo)(nil),                    // 51: plugin.ProviderInfo
-	(*GetSupportedFormatsRequest)(nil),      // 52: plugin.GetSupportedFormatsRequest
-	(*GetSupportedFormatsResponse)(nil),     // 53: plugin.GetSupportedFormatsResponse
-	(*ContainerFormat)(nil),                 // 54: plugin.ContainerFormat
-	(*GetHardwareAcceleratorsRequest)(nil),  // 55: plugin.GetHardwareAcceleratorsRequest
-	(*GetHardwareAcceleratorsResponse)(nil), // 56: plugin.GetHardwareAcceleratorsResponse
-	(*HardwareAccelerator)(nil),             // 57: plugin.HardwareAccelerator
-	(*GetQualityPresetsRequest)(nil),        // 58: plugin.GetQualityPresetsRequest
-	(*GetQualityPresetsResponse)(nil),       // 59: plugin.GetQualityPresetsResponse
-	(*QualityPreset)(nil),                   // 60: plugin.QualityPreset
-	(*StartTranscodeProviderRequest)(nil),   // 61: plugin.StartTranscodeProviderRequest
-	(*StartTranscodeProviderResponse)(nil),  // 62: plugin.StartTranscodeProviderResponse
-	(*TranscodeProviderRequest)(nil),        // 63: plugin.TranscodeProviderRequest
-	(*TranscodeHandle)(nil),                 // 64: plugin.TranscodeHandle
-	(*GetProgressRequest)(nil),              // 65: plugin.GetProgressRequest
-	(*GetProgressResponse)(nil),             // 66: plugin.GetProgressResponse
-	(*TranscodingProgress)(nil),             // 67: plugin.TranscodingProgress
-	(*StopTranscodeProviderRequest)(nil),    // 68: plugin.StopTranscodeProviderRequest
-	(*StopTranscodeProviderResponse)(nil),   // 69: plugin.StopTranscodeProviderResponse
-	(*StartStreamRequest)(nil),              // 70: plugin.StartStreamRequest
-	(*StartStreamResponse)(nil),             // 71: plugin.StartStreamResponse
-	(*StreamHandle)(nil),                    // 72: plugin.StreamHandle
-	(*GetStreamDataRequest)(nil),            // 73: plugin.GetStreamDataRequest
-	(*StreamDataChunk)(nil),                 // 74: plugin.StreamDataChunk
-	(*StopStreamRequest)(nil),               // 75: plugin.StopStreamRequest
-	(*StopStreamResponse)(nil),              // 76: plugin.StopStreamResponse
-	(*GetDashboardSectionsRequest)(nil),     // 77: plugin.GetDashboardSectionsRequest
-	(*GetDashboardSectionsResponse)(nil),    // 78: plugin.GetDashboardSectionsResponse
-	(*GetMainDataRequest)(nil),              // 79: plugin.GetMainDataRequest
-	(*GetMainDataResponse)(nil),             // 80: plugin.GetMainDataResponse
-	(*GetNerdDataRequest)(nil),              // 81: plugin.GetNerdDataRequest
-	(*GetNerdDataResponse)(nil),             // 82: plugin.GetNerdDataResponse
-	(*GetMetricsRequest)(nil),               // 83: plugin.GetMetricsRequest
-	(*GetMetricsResponse)(nil),              // 84: plugin.GetMetricsResponse
-	(*DashboardSection)(nil),                // 85: plugin.DashboardSection
-	(*DashboardSectionConfig)(nil),          // 86: plugin.DashboardSectionConfig
-	(*DashboardManifest)(nil),               // 87: plugin.DashboardManifest
-	(*DashboardAction)(nil),                 // 88: plugin.DashboardAction
-	(*MetricPoint)(nil),                     // 89: plugin.MetricPoint
-	nil,                                     // 90: plugin.SaveAssetRequest.MetadataEntry
-	nil,                                     // 91: plugin.SearchRequest.QueryEntry
-	nil,                                     // 92: plugin.SearchResult.MetadataEntry
-	nil,                                     // 93: plugin.ExtractMetadataResponse.MetadataEntry
-	nil,                                     // 94: plugin.OnMediaFileScannedRequest.MetadataEntry
-	nil,                                     // 95: plugin.OnScanCompletedRequest.StatsEntry
-	nil,                                     // 96: plugin.PluginContext.ConfigEntry
-	nil,                                     // 97: plugin.ProviderInfo.CapabilitiesEntry
-	nil,                                     // 98: plugin.TranscodeProviderRequest.ExtraOptionsEntry
-	nil,                                     // 99: plugin.DashboardManifest.UiSchemaEntry
-	nil,                                     // 100: plugin.MetricPoint.LabelsEntry
+	(*DownloadAssetRequest)(nil),            // 9: plugin.DownloadAssetRequest
+	(*DownloadAssetResponse)(nil),           // 10: plugin.DownloadAssetResponse
+	(*SearchRequest)(nil),                   // 11: plugin.SearchRequest
+	(*SearchResponse)(nil),                  // 12: plugin.SearchResponse
+	(*SearchResult)(nil),                    // 13: plugin.SearchResult
+	(*GetSearchCapabilitiesRequest)(nil),    // 14: plugin.GetSearchCapabilitiesRequest
+	(*GetSearchCapabilitiesResponse)(nil),   // 15: plugin.GetSearchCapabilitiesResponse
+	(*InitializeRequest)(nil),               // 16: plugin.InitializeRequest
+	(*InitializeResponse)(nil),              // 17: plugin.InitializeResponse
+	(*StartRequest)(nil),                    // 18: plugin.StartRequest
+	(*StartResponse)(nil),                   // 19: plugin.StartResponse
+	(*StopRequest)(nil),                     // 20: plugin.StopRequest
+	(*StopResponse)(nil),                    // 21: plugin.StopResponse
+	(*InfoRequest)(nil),                     // 22: plugin.InfoRequest
+	(*InfoResponse)(nil),                    // 23: plugin.InfoResponse
+	(*HealthRequest)(nil),                   // 24: plugin.HealthRequest
+	(*HealthResponse)(nil),                  // 25: plugin.HealthResponse
+	(*CanHandleRequest)(nil),                // 26: plugin.CanHandleRequest
+	(*CanHandleResponse)(nil),               // 27: plugin.CanHandleResponse
+	(*ExtractMetadataRequest)(nil),          // 28: plugin.ExtractMetadataRequest
+	(*ExtractMetadataResponse)(nil),         // 29: plugin.ExtractMetadataResponse
+	(*GetSupportedTypesRequest)(nil),        // 30: plugin.GetSupportedTypesRequest
+	(*GetSupportedTypesResponse)(nil),       // 31: plugin.GetSupportedTypesResponse
+	(*OnMediaFileScannedRequest)(nil),       // 32: plugin.OnMediaFileScannedRequest
+	(*OnMediaFileScannedResponse)(nil),      // 33: plugin.OnMediaFileScannedResponse
+	(*OnScanStartedRequest)(nil),            // 34: plugin.OnScanStartedRequest
+	(*OnScanStartedResponse)(nil),           // 35: plugin.OnScanStartedResponse
+	(*OnScanCompletedRequest)(nil),          // 36: plugin.OnScanCompletedRequest
+	(*OnScanCompletedResponse)(nil),         // 37: plugin.OnScanCompletedResponse
+	(*OnMediaFileRemovedRequest)(nil),       // 38: plugin.OnMediaFileRemovedRequest
+	(*OnMediaFileRemovedResponse)(nil),      // 39: plugin.OnMediaFileRemovedResponse
+	(*OnMediaFileUpdatedRequest)(nil),       // 40: plugin.OnMediaFileUpdatedRequest
+	(*OnMediaFileUpdatedResponse)(nil),      // 41: plugin.OnMediaFileUpdatedResponse
+	(*OnMediaFilesScannedRequest)(nil),      // 42: plugin.OnMediaFilesScannedRequest
+	(*OnMediaFilesScannedResponse)(nil),     // 43: plugin.OnMediaFilesScannedResponse
+	(*MediaFileHookResult)(nil),             // 44: plugin.MediaFileHookResult
+	(*GetModelsRequest)(nil),                // 45: plugin.GetModelsRequest
+	(*GetModelsResponse)(nil),               // 46: plugin.GetModelsResponse
+	(*MigrateRequest)(nil),                  // 47: plugin.MigrateRequest
+	(*MigrateResponse)(nil),                 // 48: plugin.MigrateResponse
+	(*RollbackRequest)(nil),                 // 49: plugin.RollbackRequest
+	(*RollbackResponse)(nil),                // 50: plugin.RollbackResponse
+	(*GetAdminPagesRequest)(nil),            // 51: plugin.GetAdminPagesRequest
+	(*GetAdminPagesResponse)(nil),           // 52: plugin.GetAdminPagesResponse
+	(*RegisterRoutesRequest)(nil),           // 53: plugin.RegisterRoutesRequest
+	(*RegisterRoutesResponse)(nil),          // 54: plugin.RegisterRoutesResponse
+	(*PluginContext)(nil),                   // 55: plugin.PluginContext
+	(*PluginInfo)(nil),                      // 56: plugin.PluginInfo
+	(*AdminPageConfig)(nil),                 // 57: plugin.AdminPageConfig
+	(*GetProviderInfoRequest)(nil),          // 58: plugin.GetProviderInfoRequest
+	(*GetProviderInfoResponse)(nil),         // 59: plugin.GetProviderInfoResponse
+	(*ProviderInfo)(nil),                    // 60: plugin.ProviderInfo
+	(*GetSupportedFormatsRequest)(nil),      // 61: plugin.GetSupportedFormatsRequest
+	(*GetSupportedFormatsResponse)(nil),     // 62: plugin.GetSupportedFormatsResponse
+	(*ContainerFormat)(nil),                 // 63: plugin.ContainerFormat
+	(*GetHardwareAcceleratorsRequest)(nil),  // 64: plugin.GetHardwareAcceleratorsRequest
+	(*GetHardwareAcceleratorsResponse)(nil), // 65: plugin.GetHardwareAcceleratorsResponse
+	(*HardwareAccelerator)(nil),             // 66: plugin.HardwareAccelerator
+	(*GetQualityPresetsRequest)(nil),        // 67: plugin.GetQualityPresetsRequest
+	(*GetQualityPresetsResponse)(nil),       // 68: plugin.GetQualityPresetsResponse
+	(*QualityPreset)(nil),                   // 69: plugin.QualityPreset
+	(*StartTranscodeProviderRequest)(nil),   // 70: plugin.StartTranscodeProviderRequest
+	(*StartTranscodeProviderResponse)(nil),  // 71: plugin.StartTranscodeProviderResponse
+	(*TranscodeProviderRequest)(nil),        // 72: plugin.TranscodeProviderRequest
+	(*TranscodeHandle)(nil),                 // 73: plugin.TranscodeHandle
+	(*GetProgressRequest)(nil),              // 74: plugin.GetProgressRequest
+	(*GetProgressResponse)(nil),             // 75: plugin.GetProgressResponse
+	(*TranscodingProgress)(nil),             // 76: plugin.TranscodingProgress
+	(*StopTranscodeProviderRequest)(nil),    // 77: plugin.StopTranscodeProviderRequest
+	(*StopTranscodeProviderResponse)(nil),   // 78: plugin.StopTranscodeProviderResponse
+	(*StartStreamRequest)(nil),              // 79: plugin.StartStreamRequest
+	(*StartStreamResponse)(nil),             // 80: plugin.StartStreamResponse
+	(*StreamHandle)(nil),                    // 81: plugin.StreamHandle
+	(*GetStreamDataRequest)(nil),            // 82: plugin.GetStreamDataRequest
+	(*StreamDataChunk)(nil),                 // 83: plugin.StreamDataChunk
+	(*StopStreamRequest)(nil),               // 84: plugin.StopStreamRequest
+	(*StopStreamResponse)(nil),              // 85: plugin.StopStreamResponse
+	(*GetDashboardSectionsRequest)(nil),     // 86: plugin.GetDashboardSectionsRequest
+	(*GetDashboardSectionsResponse)(nil),    // 87: plugin.GetDashboardSectionsResponse
+	(*GetMainDataRequest)(nil),              // 88: plugin.GetMainDataRequest
+	(*GetMainDataResponse)(nil),             // 89: plugin.GetMainDataResponse
+	(*GetNerdDataRequest)(nil),              // 90: plugin.GetNerdDataRequest
+	(*GetNerdDataResponse)(nil),             // 91: plugin.GetNerdDataResponse
+	(*GetMetricsRequest)(nil),               // 92: plugin.GetMetricsRequest
+	(*GetMetricsResponse)(nil),              // 93: plugin.GetMetricsResponse
+	(*DashboardSection)(nil),                // 94: plugin.DashboardSection
+	(*DashboardSectionConfig)(nil),          // 95: plugin.DashboardSectionConfig
+	(*DashboardManifest)(nil),               // 96: plugin.DashboardManifest
+	(*DashboardAction)(nil),                 // 97: plugin.DashboardAction
+	(*MetricPoint)(nil),                     // 98: plugin.MetricPoint
+	(*MediaFileInfo)(nil),                   // 99: plugin.MediaFileInfo
+	(*GetMediaFileRequest)(nil),             // 100: plugin.GetMediaFileRequest
+	(*GetMediaFileResponse)(nil),            // 101: plugin.GetMediaFileResponse
+	(*MediaItemInfo)(nil),                   // 102: plugin.MediaItemInfo
+	(*FindMediaByExternalIDRequest)(nil),    // 103: plugin.FindMediaByExternalIDRequest
+	(*FindMediaByExternalIDResponse)(nil),   // 104: plugin.FindMediaByExternalIDResponse
+	(*UpsertMovieRequest)(nil),              // 105: plugin.UpsertMovieRequest
+	(*UpsertShowRequest)(nil),               // 106: plugin.UpsertShowRequest
+	(*UpsertSeasonRequest)(nil),             // 107: plugin.UpsertSeasonRequest
+	(*UpsertEpisodeRequest)(nil),            // 108: plugin.UpsertEpisodeRequest
+	(*UpsertEntityResponse)(nil),            // 109: plugin.UpsertEntityResponse
+	(*PersonInfo)(nil),                      // 110: plugin.PersonInfo
+	(*UpsertPersonRequest)(nil),             // 111: plugin.UpsertPersonRequest
+	(*Credit)(nil),                          // 112: plugin.Credit
+	(*SetCreditsRequest)(nil),               // 113: plugin.SetCreditsRequest
+	(*SetCreditsResponse)(nil),              // 114: plugin.SetCreditsResponse
+	nil,                                     // 115: plugin.SaveAssetRequest.MetadataEntry
+	nil,                                     // 116: plugin.DownloadAssetRequest.MetadataEntry
+	nil,                                     // 117: plugin.DownloadAssetRequest.HeadersEntry
+	nil,                                     // 118: plugin.SearchRequest.QueryEntry
+	nil,                                     // 119: plugin.SearchResult.MetadataEntry
+	nil,                                     // 120: plugin.ExtractMetadataResponse.MetadataEntry
+	nil,                                     // 121: plugin.OnMediaFileScannedRequest.MetadataEntry
+	nil,                                     // 122: plugin.OnScanCompletedRequest.StatsEntry
+	nil,                                     // 123: plugin.OnMediaFileRemovedRequest.MetadataEntry
+	nil,                                     // 124: plugin.OnMediaFileUpdatedRequest.MetadataEntry
+	nil,                                     // 125: plugin.PluginContext.ConfigEntry
+	nil,                                     // 126: plugin.ProviderInfo.CapabilitiesEntry
+	nil,                                     // 127: plugin.TranscodeProviderRequest.ExtraOptionsEntry
+	nil,                                     // 128: plugin.DashboardManifest.UiSchemaEntry
+	nil,                                     // 129: plugin.MetricPoint.LabelsEntry
+	nil,                                     // 130: plugin.MediaItemInfo.ExternalIdsEntry
+	nil,                                     // 131: plugin.UpsertMovieRequest.ExternalIdsEntry
+	nil,                                     // 132: plugin.UpsertShowRequest.ExternalIdsEntry
+	nil,                                     // 133: plugin.UpsertEpisodeRequest.ExternalIdsEntry
+	nil,                                     // 134: plugin.PersonInfo.ExternalIdsEntry
 }
 var file_plugin_proto_depIdxs = []int32{
 	0,   // 0: plugin.GetRegisteredRoutesResponse.routes:type_name -> plugin.APIRoute
-	90,  // 1: plugin.SaveAssetRequest.metadata:type_name -> plugin.SaveAssetRequest.MetadataEntry
-	91,  // 2: plugin.SearchRequest.query:type_name -> plugin.SearchRequest.QueryEntry
-	11,  // 3: plugin.SearchResponse.results:type_name -> plugin.SearchResult
-	92,  // 4: plugin.SearchResult.metadata:type_name -> plugin.SearchResult.MetadataEntry
-	46,  // 5: plugin.InitializeRequest.context:type_name -> plugin.PluginContext
-	47,  // 6: plugin.InfoResponse.info:type_name -> plugin.PluginInfo
-	93,  // 7: plugin.ExtractMetadataResponse.metadata:type_name -> plugin.ExtractMetadataResponse.MetadataEntry
-	94,  // 8: plugin.OnMediaFileScannedRequest.metadata:type_name -> plugin.OnMediaFileScannedRequest.MetadataEntry
-	95,  // 9: plugin.OnScanCompletedRequest.stats:type_name -> plugin.OnScanCompletedRequest.StatsEntry
-	48,  // 10: plugin.GetAdminPagesResponse.pages:type_name -> plugin.AdminPageConfig
-	96,  // 11: plugin.PluginContext.config:type_name -> plugin.PluginContext.ConfigEntry
-	51,  // 12: plugin.GetProviderInfoResponse.info:type_name -> plugin.ProviderInfo
-	97,  // 13: plugin.ProviderInfo.capabilities:type_name -> plugin.ProviderInfo.CapabilitiesEntry
-	54,  // 14: plugin.GetSupportedFormatsResponse.formats:type_name -> plugin.ContainerFormat
-	57,  // 15: plugin.GetHardwareAcceleratorsResponse.accelerators:type_name -> plugin.HardwareAccelerator
-	60,  // 16: plugin.GetQualityPresetsResponse.presets:type_name -> plugin.QualityPreset
-	63,  // 17: plugin.StartTranscodeProviderRequest.request:type_name -> plugin.TranscodeProviderRequest
-	64,  // 18: plugin.StartTranscodeProviderResponse.handle:type_name -> plugin.TranscodeHandle
-	98,  // 19: plugin.TranscodeProviderRequest.extra_options:type_name -> plugin.TranscodeProviderRequest.ExtraOptionsEntry
-	64,  // 20: plugin.GetProgressRequest.handle:type_name -> plugin.TranscodeHandle
-	67,  // 21: plugin.GetProgressResponse.progress:type_name -> plugin.TranscodingProgress
-	64,  // 22: plugin.StopTranscodeProviderRequest.handle:type_name -> plugin.TranscodeHandle
-	63,  // 23: plugin.StartStreamRequest.request:type_name -> plugin.TranscodeProviderRequest
-	72,  // 24: plugin.StartStreamResponse.handle:type_name -> plugin.StreamHandle
-	72,  // 25: plugin.GetStreamDataRequest.handle:type_name -> plugin.StreamHandle
-	72,  // 26: plugin.StopStreamRequest.handle:type_name -> plugin.StreamHandle
-	85,  // 27: plugin.GetDashboardSectionsResponse.sections:type_name -> plugin.DashboardSection
-	89,  // 28: plugin.GetMetricsResponse.points:type_name -> plugin.MetricPoint
-	86,  // 29: plugin.DashboardSection.config:type_name -> plugin.DashboardSectionConfig
-	87,  // 30: plugin.DashboardSection.manifest:type_name -> plugin.DashboardManifest
-	88,  // 31: plugin.DashboardManifest.actions:type_name -> plugin.DashboardAction
-	99,  // 32: plugin.DashboardManifest.ui_schema:type_name -> plugin.DashboardManifest.UiSchemaEntry
-	100, // 33: plugin.MetricPoint.labels:type_name -> plugin.MetricPoint.LabelsEntry
-	14,  // 34: plugin.PluginService.Initialize:input_type -> plugin.InitializeRequest
-	16,  // 35: plugin.PluginService.Start:input_type -> plugin.StartRequest
-	18,  // 36: plugin.PluginService.Stop:input_type -> plugin.StopRequest
-	20,  // 37: plugin.PluginService.Info:input_type -> plugin.InfoRequest
-	22,  // 38: plugin.PluginService.Health:input_type -> plugin.HealthRequest
-	24,  // 39: plugin.MetadataScraperService.CanHandle:input_type -> plugin.CanHandleRequest
-	26,  // 40: plugin.MetadataScraperService.ExtractMetadata:input_type -> plugin.ExtractMetadataRequest
-	28,  // 41: plugin.MetadataScraperService.GetSupportedTypes:input_type -> plugin.GetSupportedTypesRequest
-	30,  // 42: plugin.ScannerHookService.OnMediaFileScanned:input_type -> plugin.OnMediaFileScannedRequest
-	32,  // 43: plugin.ScannerHookService.OnScanStarted:input_type -> plugin.OnScanStartedRequest
-	34,  // 44: plugin.ScannerHookService.OnScanCompleted:input_type -> plugin.OnScanCompletedRequest
-	3,   // 45: plugin.AssetService.SaveAsset:input_type -> plugin.SaveAssetRequest
-	5,   // 46: plugin.AssetService.AssetExists:input_type -> plugin.AssetExistsRequest
-	7,   // 47: plugin.AssetService.RemoveAsset:input_type -> plugin.RemoveAssetRequest
-	36,  // 48: plugin.DatabaseService.GetModels:input_type -> plugin.GetModelsRequest
-	38,  // 49: plugin.DatabaseService.Migrate:input_type -> plugin.MigrateRequest
-	40,  // 50: plugin.DatabaseService.Rollback:input_type -> plugin.RollbackRequest
-	42,  // 51: plugin.AdminPageService.GetAdminPages:input_type -> plugin.GetAdminPagesRequest
-	44,  // 52: plugin.AdminPageService.RegisterRoutes:input_type -> plugin.RegisterRoutesRequest
-	1,   // 53: plugin.APIRegistrationService.GetRegisteredRoutes:input_type -> plugin.GetRegisteredRoutesRequest
-	9,   // 54: plugin.SearchService.Search:input_type -> plugin.SearchRequest
-	12,  // 55: plugin.SearchService.GetSearchCapabilities:input_type -> plugin.GetSearchCapabilitiesRequest
-	49,  // 56: plugin.TranscodingProviderService.GetProviderInfo:input_type -> plugin.GetProviderInfoRequest
-	52,  // 57: plugin.TranscodingProviderService.GetSupportedFormats:input_type -> plugin.GetSupportedFormatsRequest
-	55,  // 58: plugin.TranscodingProviderService.GetHardwareAccelerators:input_type -> plugin.GetHardwareAcceleratorsRequest
-	58,  // 59: plugin.TranscodingProviderService.GetQualityPresets:input_type -> plugin.GetQualityPresetsRequest
-	61,  // 60: plugin.TranscodingProviderService.StartTranscode:input_type -> plugin.StartTranscodeProviderRequest
-	65,  // 61: plugin.TranscodingProviderService.GetProgress:input_type -> plugin.GetProgressRequest
-	68,  // 62: plugin.TranscodingProviderService.StopTranscode:input_type -> plugin.StopTranscodeProviderRequest
-	70,  // 63: plugin.TranscodingProviderService.StartStream:input_type -> plugin.StartStreamRequest
-	73,  // 64: plugin.TranscodingProviderService.GetStreamData:input_type -> plugin.GetStreamDataRequest
-	75,  // 65: plugin.TranscodingProviderService.StopStream:input_type -> plugin.StopStreamRequest
-	77,  // 66: plugin.DashboardService.GetDashboardSections:input_type -> plugin.GetDashboardSectionsRequest
-	79,  // 67: plugin.DashboardService.GetMainData:input_type -> plugin.GetMainDataRequest
-	81,  // 68: plugin.DashboardService.GetNerdData:input_type -> plugin.GetNerdDataRequest
-	83,  // 69: plugin.DashboardService.GetMetrics:input_type -> plugin.GetMetricsRequest
-	15,  // 70: plugin.PluginService.Initialize:output_type -> plugin.InitializeResponse
-	17,  // 71: plugin.PluginService.Start:output_type -> plugin.StartResponse
-	19,  // 72: plugin.PluginService.Stop:output_type -> plugin.StopResponse
-	21,  // 73: plugin.PluginService.Info:output_type -> plugin.InfoResponse
-	23,  // 74: plugin.PluginService.Health:output_type -> plugin.HealthResponse
-	25,  // 75: plugin.MetadataScraperService.CanHandle:output_type -> plugin.CanHandleResponse
-	27,  // 76: plugin.MetadataScraperService.ExtractMetadata:output_type -> plugin.ExtractMetadataResponse
-	29,  // 77: plugin.MetadataScraperService.GetSupportedTypes:output_type -> plugin.GetSupportedTypesResponse
-	31,  // 78: plugin.ScannerHookService.OnMediaFileScanned:output_type -> plugin.OnMediaFileScannedResponse
-	33,  // 79: plugin.ScannerHookService.OnScanStarted:output_type -> plugin.OnScanStartedResponse
-	35,  // 80: plugin.ScannerHookService.OnScanCompleted:output_type -> plugin.OnScanCompletedResponse
-	4,   // 81: plugin.AssetService.SaveAsset:output_type -> plugin.SaveAssetResponse
-	6,   // 82: plugin.AssetService.AssetExists:output_type -> plugin.AssetExistsResponse
-	8,   // 83: plugin.AssetService.RemoveAsset:output_type -> plugin.RemoveAssetResponse
-	37,  // 84: plugin.DatabaseService.GetModels:output_type -> plugin.GetModelsResponse
-	39,  // 85: plugin.DatabaseService.Migrate:output_type -> plugin.MigrateResponse
-	41,  // 86: plugin.DatabaseService.Rollback:output_type -> plugin.RollbackResponse
-	43,  // 87: plugin.AdminPageService.GetAdminPages:output_type -> plugin.GetAdminPagesResponse
-	45,  // 88: plugin.AdminPageService.RegisterRoutes:output_type -> plugin.RegisterRoutesResponse
-	2,   // 89: plugin.APIRegistrationService.GetRegisteredRoutes:output_type -> plugin.GetRegisteredRoutesResponse
-	10,  // 90: plugin.SearchService.Search:output_type -> plugin.SearchResponse
-	13,  // 91: plugin.SearchService.GetSearchCapabilities:output_type -> plugin.GetSearchCapabilitiesResponse
-	50,  // 92: plugin.TranscodingProviderService.GetProviderInfo:output_type -> plugin.GetProviderInfoResponse
-	53,  // 93: plugin.TranscodingProviderService.GetSupportedFormats:output_type -> plugin.GetSupportedFormatsResponse
-	56,  // 94: plugin.TranscodingProviderService.GetHardwareAccelerators:output_type -> plugin.GetHardwareAcceleratorsResponse
-	59,  // 95: plugin.TranscodingProviderService.GetQualityPresets:output_type -> plugin.GetQualityPresetsResponse
-	62,  // 96: plugin.TranscodingProviderService.StartTranscode:output_type -> plugin.StartTranscodeProviderResponse
-	66,  // 97: plugin.TranscodingProviderService.GetProgress:output_type -> plugin.GetProgressResponse
-	69,  // 98: plugin.TranscodingProviderService.StopTranscode:output_type -> plugin.StopTranscodeProviderResponse
-	71,  // 99: plugin.TranscodingProviderService.StartStream:output_type -> plugin.StartStreamResponse
-	74,  // 100: plugin.TranscodingProviderService.GetStreamData:output_type -> plugin.StreamDataChunk
-	76,  // 101: plugin.TranscodingProviderService.StopStream:output_type -> plugin.StopStreamResponse
-	78,  // 102: plugin.DashboardService.GetDashboardSections:output_type -> plugin.GetDashboardSectionsResponse
-	80,  // 103: plugin.DashboardService.GetMainData:output_type -> plugin.GetMainDataResponse
-	82,  // 104: plugin.DashboardService.GetNerdData:output_type -> plugin.GetNerdDataResponse
-	84,  // 105: plugin.DashboardService.GetMetrics:output_type -> plugin.GetMetricsResponse
-	70,  // [70:106] is the sub-list for method output_type
-	34,  // [34:70] is the sub-list for method input_type
-	34,  // [34:34] is the sub-list for extension type_name
-	34,  // [34:34] is the sub-list for extension extendee
-	0,   // [0:34] is the sub-list for field type_name
+	115, // 1: plugin.SaveAssetRequest.metadata:type_name -> plugin.SaveAssetRequest.MetadataEntry
+	116, // 2: plugin.DownloadAssetRequest.metadata:type_name -> plugin.DownloadAssetRequest.MetadataEntry
+	117, // 3: plugin.DownloadAssetRequest.headers:type_name -> plugin.DownloadAssetRequest.HeadersEntry
+	118, // 4: plugin.SearchRequest.query:type_name -> plugin.SearchRequest.QueryEntry
+	13,  // 5: plugin.SearchResponse.results:type_name -> plugin.SearchResult
+	119, // 6: plugin.SearchResult.metadata:type_name -> plugin.SearchResult.MetadataEntry
+	55,  // 7: plugin.InitializeRequest.context:type_name -> plugin.PluginContext
+	56,  // 8: plugin.InfoResponse.info:type_name -> plugin.PluginInfo
+	120, // 9: plugin.ExtractMetadataResponse.metadata:type_name -> plugin.ExtractMetadataResponse.MetadataEntry
+	121, // 10: plugin.OnMediaFileScannedRequest.metadata:type_name -> plugin.OnMediaFileScannedRequest.MetadataEntry
+	122, // 11: plugin.OnScanCompletedRequest.stats:type_name -> plugin.OnScanCompletedRequest.StatsEntry
+	123, // 12: plugin.OnMediaFileRemovedRequest.metadata:type_name -> plugin.OnMediaFileRemovedRequest.MetadataEntry
+	124, // 13: plugin.OnMediaFileUpdatedRequest.metadata:type_name -> plugin.OnMediaFileUpdatedRequest.MetadataEntry
+	32,  // 14: plugin.OnMediaFilesScannedRequest.files:type_name -> plugin.OnMediaFileScannedRequest
+	44,  // 15: plugin.OnMediaFilesScannedResponse.results:type_name -> plugin.MediaFileHookResult
+	57,  // 16: plugin.GetAdminPagesResponse.pages:type_name -> plugin.AdminPageConfig
+	125, // 17: plugin.PluginContext.config:type_name -> plugin.PluginContext.ConfigEntry
+	60,  // 18: plugin.GetProviderInfoResponse.info:type_name -> plugin.ProviderInfo
+	126, // 19: plugin.ProviderInfo.capabilities:type_name -> plugin.ProviderInfo.CapabilitiesEntry
+	63,  // 20: plugin.GetSupportedFormatsResponse.formats:type_name -> plugin.ContainerFormat
+	66,  // 21: plugin.GetHardwareAcceleratorsResponse.accelerators:type_name -> plugin.HardwareAccelerator
+	69,  // 22: plugin.GetQualityPresetsResponse.presets:type_name -> plugin.QualityPreset
+	72,  // 23: plugin.StartTranscodeProviderRequest.request:type_name -> plugin.TranscodeProviderRequest
+	73,  // 24: plugin.StartTranscodeProviderResponse.handle:type_name -> plugin.TranscodeHandle
+	127, // 25: plugin.TranscodeProviderRequest.extra_options:type_name -> plugin.TranscodeProviderRequest.ExtraOptionsEntry
+	73,  // 26: plugin.GetProgressRequest.handle:type_name -> plugin.TranscodeHandle
+	76,  // 27: plugin.GetProgressResponse.progress:type_name -> plugin.TranscodingProgress
+	73,  // 28: plugin.StopTranscodeProviderRequest.handle:type_name -> plugin.TranscodeHandle
+	72,  // 29: plugin.StartStreamRequest.request:type_name -> plugin.TranscodeProviderRequest
+	81,  // 30: plugin.StartStreamResponse.handle:type_name -> plugin.StreamHandle
+	81,  // 31: plugin.GetStreamDataRequest.handle:type_name -> plugin.StreamHandle
+	81,  // 32: plugin.StopStreamRequest.handle:type_name -> plugin.StreamHandle
+	94,  // 33: plugin.GetDashboardSectionsResponse.sections:type_name -> plugin.DashboardSection
+	98,  // 34: plugin.GetMetricsResponse.points:type_name -> plugin.MetricPoint
+	95,  // 35: plugin.DashboardSection.config:type_name -> plugin.DashboardSectionConfig
+	96,  // 36: plugin.DashboardSection.manifest:type_name -> plugin.DashboardManifest
+	97,  // 37: plugin.DashboardManifest.actions:type_name -> plugin.DashboardAction
+	128, // 38: plugin.DashboardManifest.ui_schema:type_name -> plugin.DashboardManifest.UiSchemaEntry
+	129, // 39: plugin.MetricPoint.labels:type_name -> plugin.MetricPoint.LabelsEntry
+	99,  // 40: plugin.GetMediaFileResponse.media_file:type_name -> plugin.MediaFileInfo
+	130, // 41: plugin.MediaItemInfo.external_ids:type_name -> plugin.MediaItemInfo.ExternalIdsEntry
+	102, // 42: plugin.FindMediaByExternalIDResponse.items:type_name -> plugin.MediaItemInfo
+	131, // 43: plugin.UpsertMovieRequest.external_ids:type_name -> plugin.UpsertMovieRequest.ExternalIdsEntry
+	132, // 44: plugin.UpsertShowRequest.external_ids:type_name -> plugin.UpsertShowRequest.ExternalIdsEntry
+	133, // 45: plugin.UpsertEpisodeRequest.external_ids:type_name -> plugin.UpsertEpisodeRequest.ExternalIdsEntry
+	134, // 46: plugin.PersonInfo.external_ids:type_name -> plugin.PersonInfo.ExternalIdsEntry
+	110, // 47: plugin.UpsertPersonRequest.person:type_name -> plugin.PersonInfo
+	110, // 48: plugin.Credit.person:type_name -> plugin.PersonInfo
+	112, // 49: plugin.SetCreditsRequest.credits:type_name -> plugin.Credit
+	16,  // 50: plugin.PluginService.Initialize:input_type -> plugin.InitializeRequest
+	18,  // 51: plugin.PluginService.Start:input_type -> plugin.StartRequest
+	20,  // 52: plugin.PluginService.Stop:input_type -> plugin.StopRequest
+	22,  // 53: plugin.PluginService.Info:input_type -> plugin.InfoRequest
+	24,  // 54: plugin.PluginService.Health:input_type -> plugin.HealthRequest
+	26,  // 55: plugin.MetadataScraperService.CanHandle:input_type -> plugin.CanHandleRequest
+	28,  // 56: plugin.MetadataScraperService.ExtractMetadata:input_type -> plugin.ExtractMetadataRequest
+	30,  // 57: plugin.MetadataScraperService.GetSupportedTypes:input_type -> plugin.GetSupportedTypesRequest
+	32,  // 58: plugin.ScannerHookService.OnMediaFileScanned:input_type -> plugin.OnMediaFileScannedRequest
+	34,  // 59: plugin.ScannerHookService.OnScanStarted:input_type -> plugin.OnScanStartedRequest
+	36,  // 60: plugin.ScannerHookService.OnScanCompleted:input_type -> plugin.OnScanCompletedRequest
+	38,  // 61: plugin.ScannerHookService.OnMediaFileRemoved:input_type -> plugin.OnMediaFileRemovedRequest
+	40,  // 62: plugin.ScannerHookService.OnMediaFileUpdated:input_type -> plugin.OnMediaFileUpdatedRequest
+	42,  // 63: plugin.ScannerHookService.OnMediaFilesScanned:input_type -> plugin.OnMediaFilesScannedRequest
+	3,   // 64: plugin.AssetService.SaveAsset:input_type -> plugin.SaveAssetRequest
+	5,   // 65: plugin.AssetService.AssetExists:input_type -> plugin.AssetExistsRequest
+	7,   // 66: plugin.AssetService.RemoveAsset:input_type -> plugin.RemoveAssetRequest
+	9,   // 67: plugin.AssetService.DownloadAsset:input_type -> plugin.DownloadAssetRequest
+	45,  // 68: plugin.DatabaseService.GetModels:input_type -> plugin.GetModelsRequest
+	47,  // 69: plugin.DatabaseService.Migrate:input_type -> plugin.MigrateRequest
+	49,  // 70: plugin.DatabaseService.Rollback:input_type -> plugin.RollbackRequest
+	51,  // 71: plugin.AdminPageService.GetAdminPages:input_type -> plugin.GetAdminPagesRequest
+	53,  // 72: plugin.AdminPageService.RegisterRoutes:input_type -> plugin.RegisterRoutesRequest
+	1,   // 73: plugin.APIRegistrationService.GetRegisteredRoutes:input_type -> plugin.GetRegisteredRoutesRequest
+	11,  // 74: plugin.SearchService.Search:input_type -> plugin.SearchRequest
+	14,  // 75: plugin.SearchService.GetSearchCapabilities:input_type -> plugin.GetSearchCapabilitiesRequest
+	58,  // 76: plugin.TranscodingProviderService.GetProviderInfo:input_type -> plugin.GetProviderInfoRequest
+	61,  // 77: plugin.TranscodingProviderService.GetSupportedFormats:input_type -> plugin.GetSupportedFormatsRequest
+	64,  // 78: plugin.TranscodingProviderService.GetHardwareAccelerators:input_type -> plugin.GetHardwareAcceleratorsRequest
+	67,  // 79: plugin.TranscodingProviderService.GetQualityPresets:input_type -> plugin.GetQualityPresetsRequest
+	70,  // 80: plugin.TranscodingProviderService.StartTranscode:input_type -> plugin.StartTranscodeProviderRequest
+	74,  // 81: plugin.TranscodingProviderService.GetProgress:input_type -> plugin.GetProgressRequest
+	77,  // 82: plugin.TranscodingProviderService.StopTranscode:input_type -> plugin.StopTranscodeProviderRequest
+	79,  // 83: plugin.TranscodingProviderService.StartStream:input_type -> plugin.StartStreamRequest
+	82,  // 84: plugin.TranscodingProviderService.GetStreamData:input_type -> plugin.GetStreamDataRequest
+	84,  // 85: plugin.TranscodingProviderService.StopStream:input_type -> plugin.StopStreamRequest
+	86,  // 86: plugin.DashboardService.GetDashboardSections:input_type -> plugin.GetDashboardSectionsRequest
+	88,  // 87: plugin.DashboardService.GetMainData:input_type -> plugin.GetMainDataRequest
+	90,  // 88: plugin.DashboardService.GetNerdData:input_type -> plugin.GetNerdDataRequest
+	92,  // 89: plugin.DashboardService.GetMetrics:input_type -> plugin.GetMetricsRequest
+	100, // 90: plugin.MediaDataService.GetMediaFile:input_type -> plugin.GetMediaFileRequest
+	103, // 91: plugin.MediaDataService.FindMediaByExternalID:input_type -> plugin.FindMediaByExternalIDRequest
+	105, // 92: plugin.MediaEntityService.UpsertMovie:input_type -> plugin.UpsertMovieRequest
+	106, // 93: plugin.MediaEntityService.UpsertShow:input_type -> plugin.UpsertShowRequest
+	107, // 94: plugin.MediaEntityService.UpsertSeason:input_type -> plugin.UpsertSeasonRequest
+	108, // 95: plugin.MediaEntityService.UpsertEpisode:input_type -> plugin.UpsertEpisodeRequest
+	111, // 96: plugin.MediaEntityService.UpsertPerson:input_type -> plugin.UpsertPersonRequest
+	113, // 97: plugin.MediaEntityService.SetCredits:input_type -> plugin.SetCreditsRequest
+	17,  // 98: plugin.PluginService.Initialize:output_type -> plugin.InitializeResponse
+	19,  // 99: plugin.PluginService.Start:output_type -> plugin.StartResponse
+	21,  // 100: plugin.PluginService.Stop:output_type -> plugin.StopResponse
+	23,  // 101: plugin.PluginService.Info:output_type -> plugin.InfoResponse
+	25,  // 102: plugin.PluginService.Health:output_type -> plugin.HealthResponse
+	27,  // 103: plugin.MetadataScraperService.CanHandle:output_type -> plugin.CanHandleResponse
+	29,  // 104: plugin.MetadataScraperService.ExtractMetadata:output_type -> plugin.ExtractMetadataResponse
+	31,  // 105: plugin.MetadataScraperService.GetSupportedTypes:output_type -> plugin.GetSupportedTypesResponse
+	33,  // 106: plugin.ScannerHookService.OnMediaFileScanned:output_type -> plugin.OnMediaFileScannedResponse
+	35,  // 107: plugin.ScannerHookService.OnScanStarted:output_type -> plugin.OnScanStartedResponse
+	37,  // 108: plugin.ScannerHookService.OnScanCompleted:output_type -> plugin.OnScanCompletedResponse
+	39,  // 109: plugin.ScannerHookService.OnMediaFileRemoved:output_type -> plugin.OnMediaFileRemovedResponse
+	41,  // 110: plugin.ScannerHookService.OnMediaFileUpdated:output_type -> plugin.OnMediaFileUpdatedResponse
+	43,  // 111: plugin.ScannerHookService.OnMediaFilesScanned:output_type -> plugin.OnMediaFilesScannedResponse
+	4,   // 112: plugin.AssetService.SaveAsset:output_type -> plugin.SaveAssetResponse
+	6,   // 113: plugin.AssetService.AssetExists:output_type -> plugin.AssetExistsResponse
+	8,   // 114: plugin.AssetService.RemoveAsset:output_type -> plugin.RemoveAssetResponse
+	10,  // 115: plugin.AssetService.DownloadAsset:output_type -> plugin.DownloadAssetResponse
+	46,  // 116: plugin.DatabaseService.GetModels:output_type -> plugin.GetModelsResponse
+	48,  // 117: plugin.DatabaseService.Migrate:output_type -> plugin.MigrateResponse
+	50,  // 118: plugin.DatabaseService.Rollback:output_type -> plugin.RollbackResponse
+	52,  // 119: plugin.AdminPageService.GetAdminPages:output_type -> plugin.GetAdminPagesResponse
+	54,  // 120: plugin.AdminPageService.RegisterRoutes:output_type -> plugin.RegisterRoutesResponse
+	2,   // 121: plugin.APIRegistrationService.GetRegisteredRoutes:output_type -> plugin.GetRegisteredRoutesResponse
+	12,  // 122: plugin.SearchService.Search:output_type -> plugin.SearchResponse
+	15,  // 123: plugin.SearchService.GetSearchCapabilities:output_type -> plugin.GetSearchCapabilitiesResponse
+	59,  // 124: plugin.TranscodingProviderService.GetProviderInfo:output_type -> plugin.GetProviderInfoResponse
+	62,  // 125: plugin.TranscodingProviderService.GetSupportedFormats:output_type -> plugin.GetSupportedFormatsResponse
+	65,  // 126: plugin.TranscodingProviderService.GetHardwareAccelerators:output_type -> plugin.GetHardwareAcceleratorsResponse
+	68,  // 127: plugin.TranscodingProviderService.GetQualityPresets:output_type -> plugin.GetQualityPresetsResponse
+	71,  // 128: plugin.TranscodingProviderService.StartTranscode:output_type -> plugin.StartTranscodeProviderResponse
+	75,  // 129: plugin.TranscodingProviderService.GetProgress:output_type -> plugin.GetProgressResponse
+	78,  // 130: plugin.TranscodingProviderService.StopTranscode:output_type -> plugin.StopTranscodeProviderResponse
+	80,  // 131: plugin.TranscodingProviderService.StartStream:output_type -> plugin.StartStreamResponse
+	83,  // 132: plugin.TranscodingProviderService.GetStreamData:output_type -> plugin.StreamDataChunk
+	85,  // 133: plugin.TranscodingProviderService.StopStream:output_type -> plugin.StopStreamResponse
+	87,  // 134: plugin.DashboardService.GetDashboardSections:output_type -> plugin.GetDashboardSectionsResponse
+	89,  // 135: plugin.DashboardService.GetMainData:output_type -> plugin.GetMainDataResponse
+	91,  // 136: plugin.DashboardService.GetNerdData:output_type -> plugin.GetNerdDataResponse
+	93,  // 137: plugin.DashboardService.GetMetrics:output_type -> plugin.GetMetricsResponse
+	101, // 138: plugin.MediaDataService.GetMediaFile:output_type -> plugin.GetMediaFileResponse
+	104, // 139: plugin.MediaDataService.FindMediaByExternalID:output_type -> plugin.FindMediaByExternalIDResponse
+	109, // 140: plugin.MediaEntityService.UpsertMovie:output_type -> plugin.UpsertEntityResponse
+	109, // 141: plugin.MediaEntityService.UpsertShow:output_type -> plugin.UpsertEntityResponse
+	109, // 142: plugin.MediaEntityService.UpsertSeason:output_type -> plugin.UpsertEntityResponse
+	109, // 143: plugin.MediaEntityService.UpsertEpisode:output_type -> plugin.UpsertEntityResponse
+	109, // 144: plugin.MediaEntityService.UpsertPerson:output_type -> plugin.UpsertEntityResponse
+	114, // 145: plugin.MediaEntityService.SetCredits:output_type -> plugin.SetCreditsResponse
+	98,  // [98:146] is the sub-list for method output_type
+	50,  // [50:98] is the sub-list for method input_type
+	50,  // [50:50] is the sub-list for extension type_name
+	50,  // [50:50] is the sub-list for extension extendee
+	0,   // [0:50] is the sub-list for field type_name
 }
 
 func init() { file_plugin_proto_init() }
@@ -5847,9 +7819,9 @@ func file_plugin_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_plugin_proto_rawDesc), len(file_plugin_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   101,
+			NumMessages:   135,
 			NumExtensions: 0,
-			NumServices:   10,
+			NumServices:   12,
 		},
 		GoTypes:           file_plugin_proto_goTypes,
 		DependencyIndexes: file_plugin_proto_depIdxs,
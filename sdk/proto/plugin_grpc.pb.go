@@ -460,9 +460,12 @@ var MetadataScraperService_ServiceDesc = grpc.ServiceDesc{
 }
 
 const (
-	ScannerHookService_OnMediaFileScanned_FullMethodName = "/plugin.ScannerHookService/OnMediaFileScanned"
-	ScannerHookService_OnScanStarted_FullMethodName      = "/plugin.ScannerHookService/OnScanStarted"
-	ScannerHookService_OnScanCompleted_FullMethodName    = "/plugin.ScannerHookService/OnScanCompleted"
+	ScannerHookService_OnMediaFileScanned_FullMethodName  = "/plugin.ScannerHookService/OnMediaFileScanned"
+	ScannerHookService_OnScanStarted_FullMethodName       = "/plugin.ScannerHookService/OnScanStarted"
+	ScannerHookService_OnScanCompleted_FullMethodName     = "/plugin.ScannerHookService/OnScanCompleted"
+	ScannerHookService_OnMediaFileRemoved_FullMethodName  = "/plugin.ScannerHookService/OnMediaFileRemoved"
+	ScannerHookService_OnMediaFileUpdated_FullMethodName  = "/plugin.ScannerHookService/OnMediaFileUpdated"
+	ScannerHookService_OnMediaFilesScanned_FullMethodName = "/plugin.ScannerHookService/OnMediaFilesScanned"
 )
 
 // ScannerHookServiceClient is the client API for ScannerHookService service.
@@ -474,6 +477,9 @@ type ScannerHookServiceClient interface {
 	OnMediaFileScanned(ctx context.Context, in *OnMediaFileScannedRequest, opts ...grpc.CallOption) (*OnMediaFileScannedResponse, error)
 	OnScanStarted(ctx context.Context, in *OnScanStartedRequest, opts ...grpc.CallOption) (*OnScanStartedResponse, error)
 	OnScanCompleted(ctx context.Context, in *OnScanCompletedRequest, opts ...grpc.CallOption) (*OnScanCompletedResponse, error)
+	OnMediaFileRemoved(ctx context.Context, in *OnMediaFileRemovedRequest, opts ...grpc.CallOption) (*OnMediaFileRemovedResponse, error)
+	OnMediaFileUpdated(ctx context.Context, in *OnMediaFileUpdatedRequest, opts ...grpc.CallOption) (*OnMediaFileUpdatedResponse, error)
+	OnMediaFilesScanned(ctx context.Context, in *OnMediaFilesScannedRequest, opts ...grpc.CallOption) (*OnMediaFilesScannedResponse, error)
 }
 
 type scannerHookServiceClient struct {
@@ -514,6 +520,36 @@ func (c *scannerHookServiceClient) OnScanCompleted(ctx context.Context, in *OnSc
 	return out, nil
 }
 
+func (c *scannerHookServiceClient) OnMediaFileRemoved(ctx context.Context, in *OnMediaFileRemovedRequest, opts ...grpc.CallOption) (*OnMediaFileRemovedResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(OnMediaFileRemovedResponse)
+	err := c.cc.Invoke(ctx, ScannerHookService_OnMediaFileRemoved_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *scannerHookServiceClient) OnMediaFileUpdated(ctx context.Context, in *OnMediaFileUpdatedRequest, opts ...grpc.CallOption) (*OnMediaFileUpdatedResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(OnMediaFileUpdatedResponse)
+	err := c.cc.Invoke(ctx, ScannerHookService_OnMediaFileUpdated_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *scannerHookServiceClient) OnMediaFilesScanned(ctx context.Context, in *OnMediaFilesScannedRequest, opts ...grpc.CallOption) (*OnMediaFilesScannedResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(OnMediaFilesScannedResponse)
+	err := c.cc.Invoke(ctx, ScannerHookService_OnMediaFilesScanned_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // ScannerHookServiceServer is the server API for ScannerHookService service.
 // All implementations must embed UnimplementedScannerHookServiceServer
 // for forward compatibility.
@@ -523,6 +559,9 @@ type ScannerHookServiceServer interface {
 	OnMediaFileScanned(context.Context, *OnMediaFileScannedRequest) (*OnMediaFileScannedResponse, error)
 	OnScanStarted(context.Context, *OnScanStartedRequest) (*OnScanStartedResponse, error)
 	OnScanCompleted(context.Context, *OnScanCompletedRequest) (*OnScanCompletedResponse, error)
+	OnMediaFileRemoved(context.Context, *OnMediaFileRemovedRequest) (*OnMediaFileRemovedResponse, error)
+	OnMediaFileUpdated(context.Context, *OnMediaFileUpdatedRequest) (*OnMediaFileUpdatedResponse, error)
+	OnMediaFilesScanned(context.Context, *OnMediaFilesScannedRequest) (*OnMediaFilesScannedResponse, error)
 	mustEmbedUnimplementedScannerHookServiceServer()
 }
 
@@ -542,6 +581,15 @@ func (UnimplementedScannerHookServiceServer) OnScanStarted(context.Context, *OnS
 func (UnimplementedScannerHookServiceServer) OnScanCompleted(context.Context, *OnScanCompletedRequest) (*OnScanCompletedResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method OnScanCompleted not implemented")
 }
+func (UnimplementedScannerHookServiceServer) OnMediaFileRemoved(context.Context, *OnMediaFileRemovedRequest) (*OnMediaFileRemovedResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method OnMediaFileRemoved not implemented")
+}
+func (UnimplementedScannerHookServiceServer) OnMediaFileUpdated(context.Context, *OnMediaFileUpdatedRequest) (*OnMediaFileUpdatedResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method OnMediaFileUpdated not implemented")
+}
+func (UnimplementedScannerHookServiceServer) OnMediaFilesScanned(context.Context, *OnMediaFilesScannedRequest) (*OnMediaFilesScannedResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method OnMediaFilesScanned not implemented")
+}
 func (UnimplementedScannerHookServiceServer) mustEmbedUnimplementedScannerHookServiceServer() {}
 func (UnimplementedScannerHookServiceServer) testEmbeddedByValue()                            {}
 
@@ -617,6 +665,60 @@ func _ScannerHookService_OnScanCompleted_Handler(srv interface{}, ctx context.Co
 	return interceptor(ctx, in, info, handler)
 }
 
+func _ScannerHookService_OnMediaFileRemoved_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(OnMediaFileRemovedRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ScannerHookServiceServer).OnMediaFileRemoved(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ScannerHookService_OnMediaFileRemoved_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ScannerHookServiceServer).OnMediaFileRemoved(ctx, req.(*OnMediaFileRemovedRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ScannerHookService_OnMediaFileUpdated_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(OnMediaFileUpdatedRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ScannerHookServiceServer).OnMediaFileUpdated(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ScannerHookService_OnMediaFileUpdated_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ScannerHookServiceServer).OnMediaFileUpdated(ctx, req.(*OnMediaFileUpdatedRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ScannerHookService_OnMediaFilesScanned_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(OnMediaFilesScannedRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ScannerHookServiceServer).OnMediaFilesScanned(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ScannerHookService_OnMediaFilesScanned_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ScannerHookServiceServer).OnMediaFilesScanned(ctx, req.(*OnMediaFilesScannedRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // ScannerHookService_ServiceDesc is the grpc.ServiceDesc for ScannerHookService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -636,15 +738,28 @@ var ScannerHookService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "OnScanCompleted",
 			Handler:    _ScannerHookService_OnScanCompleted_Handler,
 		},
+		{
+			MethodName: "OnMediaFileRemoved",
+			Handler:    _ScannerHookService_OnMediaFileRemoved_Handler,
+		},
+		{
+			MethodName: "OnMediaFileUpdated",
+			Handler:    _ScannerHookService_OnMediaFileUpdated_Handler,
+		},
+		{
+			MethodName: "OnMediaFilesScanned",
+			Handler:    _ScannerHookService_OnMediaFilesScanned_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "plugin.proto",
 }
 
 const (
-	AssetService_SaveAsset_FullMethodName   = "/plugin.AssetService/SaveAsset"
-	AssetService_AssetExists_FullMethodName = "/plugin.AssetService/AssetExists"
-	AssetService_RemoveAsset_FullMethodName = "/plugin.AssetService/RemoveAsset"
+	AssetService_SaveAsset_FullMethodName     = "/plugin.AssetService/SaveAsset"
+	AssetService_AssetExists_FullMethodName   = "/plugin.AssetService/AssetExists"
+	AssetService_RemoveAsset_FullMethodName   = "/plugin.AssetService/RemoveAsset"
+	AssetService_DownloadAsset_FullMethodName = "/plugin.AssetService/DownloadAsset"
 )
 
 // AssetServiceClient is the client API for AssetService service.
@@ -656,6 +771,10 @@ type AssetServiceClient interface {
 	SaveAsset(ctx context.Context, in *SaveAssetRequest, opts ...grpc.CallOption) (*SaveAssetResponse, error)
 	AssetExists(ctx context.Context, in *AssetExistsRequest, opts ...grpc.CallOption) (*AssetExistsResponse, error)
 	RemoveAsset(ctx context.Context, in *RemoveAssetRequest, opts ...grpc.CallOption) (*RemoveAssetResponse, error)
+	// DownloadAsset has the host download an asset through its shared download
+	// pool, which caps concurrent downloads, spaces out requests to each host
+	// and retries failures, then saves it as SaveAsset would
+	DownloadAsset(ctx context.Context, in *DownloadAssetRequest, opts ...grpc.CallOption) (*DownloadAssetResponse, error)
 }
 
 type assetServiceClient struct {
@@ -696,6 +815,16 @@ func (c *assetServiceClient) RemoveAsset(ctx context.Context, in *RemoveAssetReq
 	return out, nil
 }
 
+func (c *assetServiceClient) DownloadAsset(ctx context.Context, in *DownloadAssetRequest, opts ...grpc.CallOption) (*DownloadAssetResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DownloadAssetResponse)
+	err := c.cc.Invoke(ctx, AssetService_DownloadAsset_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // AssetServiceServer is the server API for AssetService service.
 // All implementations must embed UnimplementedAssetServiceServer
 // for forward compatibility.
@@ -705,6 +834,10 @@ type AssetServiceServer interface {
 	SaveAsset(context.Context, *SaveAssetRequest) (*SaveAssetResponse, error)
 	AssetExists(context.Context, *AssetExistsRequest) (*AssetExistsResponse, error)
 	RemoveAsset(context.Context, *RemoveAssetRequest) (*RemoveAssetResponse, error)
+	// DownloadAsset has the host download an asset through its shared download
+	// pool, which caps concurrent downloads, spaces out requests to each host
+	// and retries failures, then saves it as SaveAsset would
+	DownloadAsset(context.Context, *DownloadAssetRequest) (*DownloadAssetResponse, error)
 	mustEmbedUnimplementedAssetServiceServer()
 }
 
@@ -724,6 +857,9 @@ func (UnimplementedAssetServiceServer) AssetExists(context.Context, *AssetExists
 func (UnimplementedAssetServiceServer) RemoveAsset(context.Context, *RemoveAssetRequest) (*RemoveAssetResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method RemoveAsset not implemented")
 }
+func (UnimplementedAssetServiceServer) DownloadAsset(context.Context, *DownloadAssetRequest) (*DownloadAssetResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DownloadAsset not implemented")
+}
 func (UnimplementedAssetServiceServer) mustEmbedUnimplementedAssetServiceServer() {}
 func (UnimplementedAssetServiceServer) testEmbeddedByValue()                      {}
 
@@ -799,6 +935,24 @@ func _AssetService_RemoveAsset_Handler(srv interface{}, ctx context.Context, dec
 	return interceptor(ctx, in, info, handler)
 }
 
+func _AssetService_DownloadAsset_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DownloadAssetRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AssetServiceServer).DownloadAsset(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AssetService_DownloadAsset_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AssetServiceServer).DownloadAsset(ctx, req.(*DownloadAssetRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // AssetService_ServiceDesc is the grpc.ServiceDesc for AssetService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -818,6 +972,10 @@ var AssetService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "RemoveAsset",
 			Handler:    _AssetService_RemoveAsset_Handler,
 		},
+		{
+			MethodName: "DownloadAsset",
+			Handler:    _AssetService_DownloadAsset_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "plugin.proto",
@@ -2084,3 +2242,449 @@ var DashboardService_ServiceDesc = grpc.ServiceDesc{
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "plugin.proto",
 }
+
+const (
+	MediaDataService_GetMediaFile_FullMethodName          = "/plugin.MediaDataService/GetMediaFile"
+	MediaDataService_FindMediaByExternalID_FullMethodName = "/plugin.MediaDataService/FindMediaByExternalID"
+)
+
+// MediaDataServiceClient is the client API for MediaDataService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// MediaDataService gives plugins read-only access to core library data,
+// served by the host so plugins don't query core tables directly
+type MediaDataServiceClient interface {
+	GetMediaFile(ctx context.Context, in *GetMediaFileRequest, opts ...grpc.CallOption) (*GetMediaFileResponse, error)
+	FindMediaByExternalID(ctx context.Context, in *FindMediaByExternalIDRequest, opts ...grpc.CallOption) (*FindMediaByExternalIDResponse, error)
+}
+
+type mediaDataServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewMediaDataServiceClient(cc grpc.ClientConnInterface) MediaDataServiceClient {
+	return &mediaDataServiceClient{cc}
+}
+
+func (c *mediaDataServiceClient) GetMediaFile(ctx context.Context, in *GetMediaFileRequest, opts ...grpc.CallOption) (*GetMediaFileResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetMediaFileResponse)
+	err := c.cc.Invoke(ctx, MediaDataService_GetMediaFile_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mediaDataServiceClient) FindMediaByExternalID(ctx context.Context, in *FindMediaByExternalIDRequest, opts ...grpc.CallOption) (*FindMediaByExternalIDResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(FindMediaByExternalIDResponse)
+	err := c.cc.Invoke(ctx, MediaDataService_FindMediaByExternalID_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// MediaDataServiceServer is the server API for MediaDataService service.
+// All implementations must embed UnimplementedMediaDataServiceServer
+// for forward compatibility.
+//
+// MediaDataService gives plugins read-only access to core library data,
+// served by the host so plugins don't query core tables directly
+type MediaDataServiceServer interface {
+	GetMediaFile(context.Context, *GetMediaFileRequest) (*GetMediaFileResponse, error)
+	FindMediaByExternalID(context.Context, *FindMediaByExternalIDRequest) (*FindMediaByExternalIDResponse, error)
+	mustEmbedUnimplementedMediaDataServiceServer()
+}
+
+// UnimplementedMediaDataServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedMediaDataServiceServer struct{}
+
+func (UnimplementedMediaDataServiceServer) GetMediaFile(context.Context, *GetMediaFileRequest) (*GetMediaFileResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetMediaFile not implemented")
+}
+func (UnimplementedMediaDataServiceServer) FindMediaByExternalID(context.Context, *FindMediaByExternalIDRequest) (*FindMediaByExternalIDResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method FindMediaByExternalID not implemented")
+}
+func (UnimplementedMediaDataServiceServer) mustEmbedUnimplementedMediaDataServiceServer() {}
+func (UnimplementedMediaDataServiceServer) testEmbeddedByValue()                          {}
+
+// UnsafeMediaDataServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to MediaDataServiceServer will
+// result in compilation errors.
+type UnsafeMediaDataServiceServer interface {
+	mustEmbedUnimplementedMediaDataServiceServer()
+}
+
+func RegisterMediaDataServiceServer(s grpc.ServiceRegistrar, srv MediaDataServiceServer) {
+	// If the following call pancis, it indicates UnimplementedMediaDataServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&MediaDataService_ServiceDesc, srv)
+}
+
+func _MediaDataService_GetMediaFile_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetMediaFileRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MediaDataServiceServer).GetMediaFile(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MediaDataService_GetMediaFile_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MediaDataServiceServer).GetMediaFile(ctx, req.(*GetMediaFileRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MediaDataService_FindMediaByExternalID_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FindMediaByExternalIDRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MediaDataServiceServer).FindMediaByExternalID(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MediaDataService_FindMediaByExternalID_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MediaDataServiceServer).FindMediaByExternalID(ctx, req.(*FindMediaByExternalIDRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// MediaDataService_ServiceDesc is the grpc.ServiceDesc for MediaDataService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var MediaDataService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "plugin.MediaDataService",
+	HandlerType: (*MediaDataServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetMediaFile",
+			Handler:    _MediaDataService_GetMediaFile_Handler,
+		},
+		{
+			MethodName: "FindMediaByExternalID",
+			Handler:    _MediaDataService_FindMediaByExternalID_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "plugin.proto",
+}
+
+const (
+	MediaEntityService_UpsertMovie_FullMethodName   = "/plugin.MediaEntityService/UpsertMovie"
+	MediaEntityService_UpsertShow_FullMethodName    = "/plugin.MediaEntityService/UpsertShow"
+	MediaEntityService_UpsertSeason_FullMethodName  = "/plugin.MediaEntityService/UpsertSeason"
+	MediaEntityService_UpsertEpisode_FullMethodName = "/plugin.MediaEntityService/UpsertEpisode"
+	MediaEntityService_UpsertPerson_FullMethodName  = "/plugin.MediaEntityService/UpsertPerson"
+	MediaEntityService_SetCredits_FullMethodName    = "/plugin.MediaEntityService/SetCredits"
+)
+
+// MediaEntityServiceClient is the client API for MediaEntityService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// MediaEntityService lets plugins create or find library items through the
+// host, which matches existing items by external ID before title and
+// serializes writers so concurrent scans don't create duplicates
+type MediaEntityServiceClient interface {
+	UpsertMovie(ctx context.Context, in *UpsertMovieRequest, opts ...grpc.CallOption) (*UpsertEntityResponse, error)
+	UpsertShow(ctx context.Context, in *UpsertShowRequest, opts ...grpc.CallOption) (*UpsertEntityResponse, error)
+	UpsertSeason(ctx context.Context, in *UpsertSeasonRequest, opts ...grpc.CallOption) (*UpsertEntityResponse, error)
+	UpsertEpisode(ctx context.Context, in *UpsertEpisodeRequest, opts ...grpc.CallOption) (*UpsertEntityResponse, error)
+	UpsertPerson(ctx context.Context, in *UpsertPersonRequest, opts ...grpc.CallOption) (*UpsertEntityResponse, error)
+	SetCredits(ctx context.Context, in *SetCreditsRequest, opts ...grpc.CallOption) (*SetCreditsResponse, error)
+}
+
+type mediaEntityServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewMediaEntityServiceClient(cc grpc.ClientConnInterface) MediaEntityServiceClient {
+	return &mediaEntityServiceClient{cc}
+}
+
+func (c *mediaEntityServiceClient) UpsertMovie(ctx context.Context, in *UpsertMovieRequest, opts ...grpc.CallOption) (*UpsertEntityResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UpsertEntityResponse)
+	err := c.cc.Invoke(ctx, MediaEntityService_UpsertMovie_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mediaEntityServiceClient) UpsertShow(ctx context.Context, in *UpsertShowRequest, opts ...grpc.CallOption) (*UpsertEntityResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UpsertEntityResponse)
+	err := c.cc.Invoke(ctx, MediaEntityService_UpsertShow_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mediaEntityServiceClient) UpsertSeason(ctx context.Context, in *UpsertSeasonRequest, opts ...grpc.CallOption) (*UpsertEntityResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UpsertEntityResponse)
+	err := c.cc.Invoke(ctx, MediaEntityService_UpsertSeason_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mediaEntityServiceClient) UpsertEpisode(ctx context.Context, in *UpsertEpisodeRequest, opts ...grpc.CallOption) (*UpsertEntityResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UpsertEntityResponse)
+	err := c.cc.Invoke(ctx, MediaEntityService_UpsertEpisode_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mediaEntityServiceClient) UpsertPerson(ctx context.Context, in *UpsertPersonRequest, opts ...grpc.CallOption) (*UpsertEntityResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UpsertEntityResponse)
+	err := c.cc.Invoke(ctx, MediaEntityService_UpsertPerson_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mediaEntityServiceClient) SetCredits(ctx context.Context, in *SetCreditsRequest, opts ...grpc.CallOption) (*SetCreditsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SetCreditsResponse)
+	err := c.cc.Invoke(ctx, MediaEntityService_SetCredits_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// MediaEntityServiceServer is the server API for MediaEntityService service.
+// All implementations must embed UnimplementedMediaEntityServiceServer
+// for forward compatibility.
+//
+// MediaEntityService lets plugins create or find library items through the
+// host, which matches existing items by external ID before title and
+// serializes writers so concurrent scans don't create duplicates
+type MediaEntityServiceServer interface {
+	UpsertMovie(context.Context, *UpsertMovieRequest) (*UpsertEntityResponse, error)
+	UpsertShow(context.Context, *UpsertShowRequest) (*UpsertEntityResponse, error)
+	UpsertSeason(context.Context, *UpsertSeasonRequest) (*UpsertEntityResponse, error)
+	UpsertEpisode(context.Context, *UpsertEpisodeRequest) (*UpsertEntityResponse, error)
+	UpsertPerson(context.Context, *UpsertPersonRequest) (*UpsertEntityResponse, error)
+	SetCredits(context.Context, *SetCreditsRequest) (*SetCreditsResponse, error)
+	mustEmbedUnimplementedMediaEntityServiceServer()
+}
+
+// UnimplementedMediaEntityServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedMediaEntityServiceServer struct{}
+
+func (UnimplementedMediaEntityServiceServer) UpsertMovie(context.Context, *UpsertMovieRequest) (*UpsertEntityResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpsertMovie not implemented")
+}
+func (UnimplementedMediaEntityServiceServer) UpsertShow(context.Context, *UpsertShowRequest) (*UpsertEntityResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpsertShow not implemented")
+}
+func (UnimplementedMediaEntityServiceServer) UpsertSeason(context.Context, *UpsertSeasonRequest) (*UpsertEntityResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpsertSeason not implemented")
+}
+func (UnimplementedMediaEntityServiceServer) UpsertEpisode(context.Context, *UpsertEpisodeRequest) (*UpsertEntityResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpsertEpisode not implemented")
+}
+func (UnimplementedMediaEntityServiceServer) UpsertPerson(context.Context, *UpsertPersonRequest) (*UpsertEntityResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpsertPerson not implemented")
+}
+func (UnimplementedMediaEntityServiceServer) SetCredits(context.Context, *SetCreditsRequest) (*SetCreditsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetCredits not implemented")
+}
+func (UnimplementedMediaEntityServiceServer) mustEmbedUnimplementedMediaEntityServiceServer() {}
+func (UnimplementedMediaEntityServiceServer) testEmbeddedByValue()                            {}
+
+// UnsafeMediaEntityServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to MediaEntityServiceServer will
+// result in compilation errors.
+type UnsafeMediaEntityServiceServer interface {
+	mustEmbedUnimplementedMediaEntityServiceServer()
+}
+
+func RegisterMediaEntityServiceServer(s grpc.ServiceRegistrar, srv MediaEntityServiceServer) {
+	// If the following call pancis, it indicates UnimplementedMediaEntityServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&MediaEntityService_ServiceDesc, srv)
+}
+
+func _MediaEntityService_UpsertMovie_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpsertMovieRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MediaEntityServiceServer).UpsertMovie(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MediaEntityService_UpsertMovie_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MediaEntityServiceServer).UpsertMovie(ctx, req.(*UpsertMovieRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MediaEntityService_UpsertShow_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpsertShowRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MediaEntityServiceServer).UpsertShow(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MediaEntityService_UpsertShow_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MediaEntityServiceServer).UpsertShow(ctx, req.(*UpsertShowRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MediaEntityService_UpsertSeason_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpsertSeasonRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MediaEntityServiceServer).UpsertSeason(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MediaEntityService_UpsertSeason_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MediaEntityServiceServer).UpsertSeason(ctx, req.(*UpsertSeasonRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MediaEntityService_UpsertEpisode_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpsertEpisodeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MediaEntityServiceServer).UpsertEpisode(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MediaEntityService_UpsertEpisode_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MediaEntityServiceServer).UpsertEpisode(ctx, req.(*UpsertEpisodeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MediaEntityService_UpsertPerson_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpsertPersonRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MediaEntityServiceServer).UpsertPerson(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MediaEntityService_UpsertPerson_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MediaEntityServiceServer).UpsertPerson(ctx, req.(*UpsertPersonRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MediaEntityService_SetCredits_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetCreditsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MediaEntityServiceServer).SetCredits(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MediaEntityService_SetCredits_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MediaEntityServiceServer).SetCredits(ctx, req.(*SetCreditsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// MediaEntityService_ServiceDesc is the grpc.ServiceDesc for MediaEntityService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var MediaEntityService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "plugin.MediaEntityService",
+	HandlerType: (*MediaEntityServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "UpsertMovie",
+			Handler:    _MediaEntityService_UpsertMovie_Handler,
+		},
+		{
+			MethodName: "UpsertShow",
+			Handler:    _MediaEntityService_UpsertShow_Handler,
+		},
+		{
+			MethodName: "UpsertSeason",
+			Handler:    _MediaEntityService_UpsertSeason_Handler,
+		},
+		{
+			MethodName: "UpsertEpisode",
+			Handler:    _MediaEntityService_UpsertEpisode_Handler,
+		},
+		{
+			MethodName: "UpsertPerson",
+			Handler:    _MediaEntityService_UpsertPerson_Handler,
+		},
+		{
+			MethodName: "SetCredits",
+			Handler:    _MediaEntityService_SetCredits_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "plugin.proto",
+}
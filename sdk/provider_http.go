@@ -0,0 +1,33 @@
+package plugins
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/mantonx/viewra/sdk/cassette"
+)
+
+// NewProviderHTTPClient returns the http.Client enrichment plugins should use
+// for calls to external metadata providers such as TMDb, MusicBrainz and
+// AudioDB. When the host runs in mock provider mode (VIEWRA_MOCK_PROVIDERS)
+// requests are answered from recorded cassettes instead of the network.
+func NewProviderHTTPClient(timeout time.Duration) *http.Client {
+	transport, err := cassette.Wrap(http.DefaultTransport)
+	if err != nil {
+		// Never fall back to live requests when mock mode was asked for
+		transport = failingTransport{err: err}
+	}
+	return &http.Client{Timeout: timeout, Transport: transport}
+}
+
+// failingTransport fails every request with a configuration error
+type failingTransport struct {
+	err error
+}
+
+func (t failingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Body != nil {
+		req.Body.Close()
+	}
+	return nil, t.err
+}
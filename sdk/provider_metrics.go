@@ -0,0 +1,190 @@
+package plugins
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// ProviderLatencyBuckets are the upper bounds of the request duration
+// histogram kept per external provider. Requests slower than the last bound
+// are counted in a final unbounded bucket.
+var ProviderLatencyBuckets = []time.Duration{
+	50 * time.Millisecond,
+	100 * time.Millisecond,
+	250 * time.Millisecond,
+	500 * time.Millisecond,
+	time.Second,
+	2500 * time.Millisecond,
+	5 * time.Second,
+	10 * time.Second,
+}
+
+// ProviderStatusError is the status code key of requests that got no response,
+// such as timeouts and connection failures
+const ProviderStatusError = "error"
+
+// providerMetrics accumulates the requests made to one external provider
+type providerMetrics struct {
+	requests    int64
+	failures    int64
+	totalTime   time.Duration
+	maxTime     time.Duration
+	buckets     []int64 // One per ProviderLatencyBuckets bound, plus the unbounded one
+	statusCodes map[string]int64
+	lastRequest time.Time
+}
+
+// ProviderSnapshot is the latency histogram and status code counts of the
+// requests a plugin made to one external provider
+type ProviderSnapshot struct {
+	Provider    string           `json:"provider"`
+	Requests    int64            `json:"requests"`
+	Failures    int64            `json:"failures"` // No response or a 5xx status
+	AverageTime time.Duration    `json:"average_time"`
+	MaxTime     time.Duration    `json:"max_time"`
+	P50         time.Duration    `json:"p50"` // Upper bound of the bucket holding the median
+	P95         time.Duration    `json:"p95"`
+	Buckets     []LatencyBucket  `json:"buckets"`
+	StatusCodes map[string]int64 `json:"status_codes"` // By status code, or ProviderStatusError
+	LastRequest time.Time        `json:"last_request"`
+}
+
+// LatencyBucket counts the requests that took at most UpperBound and longer
+// than the previous bucket's bound. The last bucket's UpperBound is 0 and
+// counts every slower request.
+type LatencyBucket struct {
+	UpperBound time.Duration `json:"upper_bound"`
+	Count      int64         `json:"count"`
+}
+
+// record adds one request to the provider's metrics. statusCode is 0 when
+// the request got no response.
+func (p *providerMetrics) record(duration time.Duration, statusCode int) {
+	p.requests++
+	p.totalTime += duration
+	if duration > p.maxTime {
+		p.maxTime = duration
+	}
+	p.lastRequest = time.Now()
+
+	bucket := len(ProviderLatencyBuckets)
+	for i, bound := range ProviderLatencyBuckets {
+		if duration <= bound {
+			bucket = i
+			break
+		}
+	}
+	p.buckets[bucket]++
+
+	code := ProviderStatusError
+	if statusCode > 0 {
+		code = strconv.Itoa(statusCode)
+	}
+	p.statusCodes[code]++
+	if statusCode == 0 || statusCode >= http.StatusInternalServerError {
+		p.failures++
+	}
+}
+
+// snapshot copies the provider's metrics
+func (p *providerMetrics) snapshot(provider string) *ProviderSnapshot {
+	snapshot := &ProviderSnapshot{
+		Provider:    provider,
+		Requests:    p.requests,
+		Failures:    p.failures,
+		MaxTime:     p.maxTime,
+		Buckets:     make([]LatencyBucket, len(p.buckets)),
+		StatusCodes: make(map[string]int64, len(p.statusCodes)),
+		LastRequest: p.lastRequest,
+	}
+	if p.requests > 0 {
+		snapshot.AverageTime = p.totalTime / time.Duration(p.requests)
+	}
+	for i, count := range p.buckets {
+		if i < len(ProviderLatencyBuckets) {
+			snapshot.Buckets[i].UpperBound = ProviderLatencyBuckets[i]
+		}
+		snapshot.Buckets[i].Count = count
+	}
+	for code, count := range p.statusCodes {
+		snapshot.StatusCodes[code] = count
+	}
+	snapshot.P50 = p.quantile(0.5)
+	snapshot.P95 = p.quantile(0.95)
+	return snapshot
+}
+
+// quantile returns the upper bound of the bucket holding the q quantile, or
+// the slowest request when it falls in the unbounded bucket
+func (p *providerMetrics) quantile(q float64) time.Duration {
+	if p.requests == 0 {
+		return 0
+	}
+	rank := int64(math.Ceil(q * float64(p.requests)))
+	var seen int64
+	for i, count := range p.buckets {
+		seen += count
+		if seen >= rank {
+			if i < len(ProviderLatencyBuckets) {
+				return ProviderLatencyBuckets[i]
+			}
+			break
+		}
+	}
+	return p.maxTime
+}
+
+// RecordProviderRequest records the duration and status code of a request to
+// an external provider such as "tmdb". statusCode is 0 when the request got
+// no response. Most plugins record through InstrumentProviderClient instead.
+func (pm *BasePerformanceMonitor) RecordProviderRequest(provider string, duration time.Duration, statusCode int) {
+	pm.mutex.Lock()
+	defer pm.mutex.Unlock()
+
+	metrics, exists := pm.providers[provider]
+	if !exists {
+		metrics = &providerMetrics{
+			buckets:     make([]int64, len(ProviderLatencyBuckets)+1),
+			statusCodes: make(map[string]int64),
+		}
+		pm.providers[provider] = metrics
+	}
+	metrics.record(duration, statusCode)
+}
+
+// InstrumentProviderClient records every request client makes in monitor
+// under provider, so slow or failing external APIs show up in the plugin's
+// performance snapshot. It wraps the client's transport in place and returns
+// the client; a nil monitor leaves the client unchanged.
+func InstrumentProviderClient(client *http.Client, monitor *BasePerformanceMonitor, provider string) *http.Client {
+	if client == nil || monitor == nil {
+		return client
+	}
+	base := client.Transport
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	client.Transport = &providerMetricsTransport{base: base, monitor: monitor, provider: provider}
+	return client
+}
+
+// providerMetricsTransport times the requests passing through it
+type providerMetricsTransport struct {
+	base     http.RoundTripper
+	monitor  *BasePerformanceMonitor
+	provider string
+}
+
+func (t *providerMetricsTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	start := time.Now()
+	resp, err := t.base.RoundTrip(req)
+
+	statusCode := 0
+	if err == nil && resp != nil {
+		statusCode = resp.StatusCode
+	}
+	t.monitor.RecordProviderRequest(t.provider, time.Since(start), statusCode)
+	return resp, err
+}
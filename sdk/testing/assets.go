@@ -0,0 +1,347 @@
+// Package plugintest provides in-memory fakes of the Viewra host services and
+// golden-file helpers so plugins can be unit tested without a running host.
+//
+// Import it as:
+//
+//	plugintest "github.com/mantonx/viewra/sdk/testing"
+package plugintest
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"sync"
+
+	plugins "github.com/mantonx/viewra/sdk"
+	"github.com/mantonx/viewra/sdk/proto"
+)
+
+// Asset is an asset saved through a FakeAssetService
+type Asset struct {
+	ID           uint32            `json:"id"`
+	MediaFileID  string            `json:"media_file_id"`
+	AssetType    string            `json:"asset_type"`
+	Category     string            `json:"category"`
+	Subtype      string            `json:"subtype"`
+	MimeType     string            `json:"mime_type"`
+	SourceURL    string            `json:"source_url"`
+	PluginID     string            `json:"plugin_id,omitempty"`
+	Hash         string            `json:"hash"`
+	Size         int               `json:"size"`
+	RelativePath string            `json:"relative_path"`
+	Metadata     map[string]string `json:"metadata,omitempty"`
+	Data         []byte            `json:"-"`
+}
+
+// FakeAssetService is an in-memory plugins.AssetServiceClient. Like the host,
+// saving the same content twice for a media file returns the existing asset.
+type FakeAssetService struct {
+	mu     sync.Mutex
+	nextID uint32
+	assets map[uint32]*Asset
+	errs   map[string]error
+}
+
+// NewFakeAssetService creates an empty asset store
+func NewFakeAssetService() *FakeAssetService {
+	return &FakeAssetService{
+		assets: make(map[uint32]*Asset),
+		errs:   make(map[string]error),
+	}
+}
+
+// FailWith makes every call to method ("SaveAsset", "AssetExists",
+// "RemoveAsset" or "DownloadAsset") return err. Pass a nil err to clear it.
+func (f *FakeAssetService) FailWith(method string, err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err == nil {
+		delete(f.errs, method)
+		return
+	}
+	f.errs[method] = err
+}
+
+// SaveAsset implements plugins.AssetServiceClient
+func (f *FakeAssetService) SaveAsset(ctx context.Context, req *plugins.SaveAssetRequest) (*plugins.SaveAssetResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.errs["SaveAsset"]; err != nil {
+		return nil, err
+	}
+	if req.MediaFileID == "" || len(req.Data) == 0 {
+		return &plugins.SaveAssetResponse{Success: false, Error: "media file ID and data are required"}, nil
+	}
+
+	sum := sha256.Sum256(req.Data)
+	hash := hex.EncodeToString(sum[:])
+	for _, existing := range f.assets {
+		if existing.MediaFileID == req.MediaFileID && existing.AssetType == req.AssetType &&
+			existing.Category == req.Category && existing.Subtype == req.Subtype && existing.Hash == hash {
+			return &plugins.SaveAssetResponse{
+				Success:      true,
+				AssetID:      existing.ID,
+				Hash:         hash,
+				RelativePath: existing.RelativePath,
+			}, nil
+		}
+	}
+
+	f.nextID++
+	asset := &Asset{
+		ID:           f.nextID,
+		MediaFileID:  req.MediaFileID,
+		AssetType:    req.AssetType,
+		Category:     req.Category,
+		Subtype:      req.Subtype,
+		MimeType:     req.MimeType,
+		SourceURL:    req.SourceURL,
+		PluginID:     req.PluginID,
+		Hash:         hash,
+		Size:         len(req.Data),
+		RelativePath: fmt.Sprintf("%s/%s/%s/%s", req.AssetType, req.Category, hash[:2], hash),
+		Metadata:     copyMap(req.Metadata),
+		Data:         append([]byte(nil), req.Data...),
+	}
+	f.assets[asset.ID] = asset
+
+	return &plugins.SaveAssetResponse{
+		Success:      true,
+		AssetID:      asset.ID,
+		Hash:         hash,
+		RelativePath: asset.RelativePath,
+	}, nil
+}
+
+// AssetExists implements plugins.AssetServiceClient
+func (f *FakeAssetService) AssetExists(ctx context.Context, req *plugins.AssetExistsRequest) (*plugins.AssetExistsResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.errs["AssetExists"]; err != nil {
+		return nil, err
+	}
+
+	for _, asset := range f.assets {
+		if asset.MediaFileID == req.MediaFileID && asset.AssetType == req.AssetType &&
+			asset.Category == req.Category && asset.Subtype == req.Subtype &&
+			(req.Hash == "" || asset.Hash == req.Hash) {
+			return &plugins.AssetExistsResponse{Exists: true, AssetID: asset.ID, RelativePath: asset.RelativePath}, nil
+		}
+	}
+	return &plugins.AssetExistsResponse{}, nil
+}
+
+// RemoveAsset implements plugins.AssetServiceClient
+func (f *FakeAssetService) RemoveAsset(ctx context.Context, req *plugins.RemoveAssetRequest) (*plugins.RemoveAssetResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.errs["RemoveAsset"]; err != nil {
+		return nil, err
+	}
+
+	if _, ok := f.assets[req.AssetID]; !ok {
+		return &plugins.RemoveAssetResponse{Success: false, Error: "asset not found"}, nil
+	}
+	delete(f.assets, req.AssetID)
+	return &plugins.RemoveAssetResponse{Success: true}, nil
+}
+
+// DownloadAsset implements plugins.AssetServiceClient. It fetches the URL
+// directly, once, without the host's concurrency cap or retries, so tests can
+// point it at an httptest server.
+func (f *FakeAssetService) DownloadAsset(ctx context.Context, req *plugins.DownloadAssetRequest) (*plugins.DownloadAssetResponse, error) {
+	f.mu.Lock()
+	err := f.errs["DownloadAsset"]
+	f.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodGet, req.URL, nil)
+	if err != nil {
+		return &plugins.DownloadAssetResponse{Error: err.Error()}, nil
+	}
+	for key, value := range req.Headers {
+		httpReq.Header.Set(key, value)
+	}
+	resp, err := http.DefaultClient.Do(httpReq)
+	if err != nil {
+		return &plugins.DownloadAssetResponse{Error: err.Error()}, nil
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return &plugins.DownloadAssetResponse{Error: fmt.Sprintf("HTTP %d", resp.StatusCode), StatusCode: resp.StatusCode}, nil
+	}
+
+	var body io.Reader = resp.Body
+	if req.MaxSize > 0 {
+		body = io.LimitReader(resp.Body, req.MaxSize+1)
+	}
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return &plugins.DownloadAssetResponse{Error: err.Error(), StatusCode: resp.StatusCode}, nil
+	}
+	if req.MaxSize > 0 && int64(len(data)) > req.MaxSize {
+		return &plugins.DownloadAssetResponse{Error: fmt.Sprintf("asset exceeds %d bytes", req.MaxSize), StatusCode: resp.StatusCode}, nil
+	}
+
+	mimeType := resp.Header.Get("Content-Type")
+	if mimeType == "" {
+		mimeType = http.DetectContentType(data)
+	}
+	saved, err := f.SaveAsset(ctx, &plugins.SaveAssetRequest{
+		MediaFileID: req.MediaFileID,
+		AssetType:   req.AssetType,
+		Category:    req.Category,
+		Subtype:     req.Subtype,
+		Data:        data,
+		MimeType:    mimeType,
+		SourceURL:   req.URL,
+		PluginID:    req.PluginID,
+		Metadata:    req.Metadata,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &plugins.DownloadAssetResponse{
+		Success:      saved.Success,
+		Error:        saved.Error,
+		AssetID:      saved.AssetID,
+		Hash:         saved.Hash,
+		RelativePath: saved.RelativePath,
+		Size:         int64(len(data)),
+		MimeType:     mimeType,
+		StatusCode:   resp.StatusCode,
+	}, nil
+}
+
+// Assets returns every stored asset ordered by ID
+func (f *FakeAssetService) Assets() []Asset {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	assets := make([]Asset, 0, len(f.assets))
+	for _, asset := range f.assets {
+		assets = append(assets, *asset)
+	}
+	sort.Slice(assets, func(i, j int) bool { return assets[i].ID < assets[j].ID })
+	return assets
+}
+
+// AssetsFor returns the stored assets for one media file ordered by ID
+func (f *FakeAssetService) AssetsFor(mediaFileID string) []Asset {
+	var assets []Asset
+	for _, asset := range f.Assets() {
+		if asset.MediaFileID == mediaFileID {
+			assets = append(assets, asset)
+		}
+	}
+	return assets
+}
+
+// Reset removes all assets and injected errors
+func (f *FakeAssetService) Reset() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.nextID = 0
+	f.assets = make(map[uint32]*Asset)
+	f.errs = make(map[string]error)
+}
+
+// assetServer exposes a FakeAssetService as the host's gRPC AssetService
+type assetServer struct {
+	proto.UnimplementedAssetServiceServer
+	fake *FakeAssetService
+}
+
+func (s *assetServer) SaveAsset(ctx context.Context, req *proto.SaveAssetRequest) (*proto.SaveAssetResponse, error) {
+	resp, err := s.fake.SaveAsset(ctx, &plugins.SaveAssetRequest{
+		MediaFileID: req.MediaFileId,
+		AssetType:   req.AssetType,
+		Category:    req.Category,
+		Subtype:     req.Subtype,
+		Data:        req.Data,
+		MimeType:    req.MimeType,
+		SourceURL:   req.SourceUrl,
+		PluginID:    req.PluginId,
+		Metadata:    req.Metadata,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &proto.SaveAssetResponse{
+		Success:      resp.Success,
+		Error:        resp.Error,
+		AssetId:      resp.AssetID,
+		Hash:         resp.Hash,
+		RelativePath: resp.RelativePath,
+	}, nil
+}
+
+func (s *assetServer) AssetExists(ctx context.Context, req *proto.AssetExistsRequest) (*proto.AssetExistsResponse, error) {
+	resp, err := s.fake.AssetExists(ctx, &plugins.AssetExistsRequest{
+		MediaFileID: req.MediaFileId,
+		AssetType:   req.AssetType,
+		Category:    req.Category,
+		Subtype:     req.Subtype,
+		Hash:        req.Hash,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &proto.AssetExistsResponse{
+		Exists:       resp.Exists,
+		AssetId:      resp.AssetID,
+		RelativePath: resp.RelativePath,
+	}, nil
+}
+
+func (s *assetServer) RemoveAsset(ctx context.Context, req *proto.RemoveAssetRequest) (*proto.RemoveAssetResponse, error) {
+	resp, err := s.fake.RemoveAsset(ctx, &plugins.RemoveAssetRequest{AssetID: req.AssetId})
+	if err != nil {
+		return nil, err
+	}
+	return &proto.RemoveAssetResponse{Success: resp.Success, Error: resp.Error}, nil
+}
+
+func (s *assetServer) DownloadAsset(ctx context.Context, req *proto.DownloadAssetRequest) (*proto.DownloadAssetResponse, error) {
+	resp, err := s.fake.DownloadAsset(ctx, &plugins.DownloadAssetRequest{
+		URL:         req.Url,
+		MediaFileID: req.MediaFileId,
+		AssetType:   req.AssetType,
+		Category:    req.Category,
+		Subtype:     req.Subtype,
+		Metadata:    req.Metadata,
+		PluginID:    req.PluginId,
+		Headers:     req.Headers,
+		MaxSize:     req.MaxSize,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &proto.DownloadAssetResponse{
+		Success:      resp.Success,
+		Error:        resp.Error,
+		AssetId:      resp.AssetID,
+		Hash:         resp.Hash,
+		RelativePath: resp.RelativePath,
+		Size:         resp.Size,
+		MimeType:     resp.MimeType,
+		StatusCode:   int32(resp.StatusCode),
+	}, nil
+}
+
+func copyMap(m map[string]string) map[string]string {
+	if m == nil {
+		return nil
+	}
+	out := make(map[string]string, len(m))
+	for k, v := range m {
+		out[k] = v
+	}
+	return out
+}
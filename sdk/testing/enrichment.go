@@ -0,0 +1,89 @@
+package plugintest
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	plugins "github.com/mantonx/viewra/sdk"
+)
+
+// Enrichment is an enrichment registered through a FakeEnrichmentService
+type Enrichment struct {
+	JobID           string            `json:"job_id"`
+	MediaFileID     string            `json:"media_file_id"`
+	SourceName      string            `json:"source_name"`
+	Enrichments     map[string]string `json:"enrichments"`
+	ConfidenceScore float64           `json:"confidence_score"`
+	MatchMetadata   map[string]string `json:"match_metadata,omitempty"`
+}
+
+// FakeEnrichmentService is an in-memory plugins.EnrichmentServiceClient that
+// records every registered enrichment in order
+type FakeEnrichmentService struct {
+	mu          sync.Mutex
+	enrichments []Enrichment
+	err         error
+}
+
+// NewFakeEnrichmentService creates an empty enrichment recorder
+func NewFakeEnrichmentService() *FakeEnrichmentService {
+	return &FakeEnrichmentService{}
+}
+
+// FailWith makes RegisterEnrichment return err. Pass nil to clear it.
+func (f *FakeEnrichmentService) FailWith(err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.err = err
+}
+
+// RegisterEnrichment implements plugins.EnrichmentServiceClient
+func (f *FakeEnrichmentService) RegisterEnrichment(ctx context.Context, req *plugins.RegisterEnrichmentRequest) (*plugins.RegisterEnrichmentResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.err != nil {
+		return nil, f.err
+	}
+	if req.MediaFileID == "" || req.SourceName == "" {
+		return &plugins.RegisterEnrichmentResponse{Success: false, Message: "media file ID and source name are required"}, nil
+	}
+
+	jobID := fmt.Sprintf("job-%d", len(f.enrichments)+1)
+	f.enrichments = append(f.enrichments, Enrichment{
+		JobID:           jobID,
+		MediaFileID:     req.MediaFileID,
+		SourceName:      req.SourceName,
+		Enrichments:     copyMap(req.Enrichments),
+		ConfidenceScore: req.ConfidenceScore,
+		MatchMetadata:   copyMap(req.MatchMetadata),
+	})
+
+	return &plugins.RegisterEnrichmentResponse{Success: true, Message: "enrichment registered", JobID: jobID}, nil
+}
+
+// Enrichments returns every registered enrichment in registration order
+func (f *FakeEnrichmentService) Enrichments() []Enrichment {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]Enrichment{}, f.enrichments...)
+}
+
+// EnrichmentsFor returns the enrichments registered for one media file
+func (f *FakeEnrichmentService) EnrichmentsFor(mediaFileID string) []Enrichment {
+	var enrichments []Enrichment
+	for _, enrichment := range f.Enrichments() {
+		if enrichment.MediaFileID == mediaFileID {
+			enrichments = append(enrichments, enrichment)
+		}
+	}
+	return enrichments
+}
+
+// Reset clears recorded enrichments and any injected error
+func (f *FakeEnrichmentService) Reset() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.enrichments = nil
+	f.err = nil
+}
@@ -0,0 +1,105 @@
+package plugintest
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	plugins "github.com/mantonx/viewra/sdk"
+)
+
+// UpdateGoldenEnv rewrites golden files instead of comparing against them when set to 1
+const UpdateGoldenEnv = "VIEWRA_UPDATE_GOLDEN"
+
+// ScannerHookCase is one OnMediaFileScanned call in a golden test
+type ScannerHookCase struct {
+	// Name identifies the case and its golden file, testdata/<Golden or Name>.golden
+	Name        string
+	MediaFileID string
+	FilePath    string
+	Metadata    map[string]string
+	// Golden overrides the golden file name, letting cases share expected output
+	Golden string
+}
+
+// ScannerHookResult is what a scanner hook did for one case, as stored in its golden file
+type ScannerHookResult struct {
+	SkipReason  string       `json:"skip_reason,omitempty"`
+	Error       string       `json:"error,omitempty"`
+	ErrorCode   string       `json:"error_code,omitempty"`
+	Enrichments []Enrichment `json:"enrichments"`
+	Assets      []Asset      `json:"assets"`
+}
+
+// RunScannerHookGolden runs each case as a subtest against hook and compares
+// the enrichments and assets it sends to host with testdata/<name>.golden.
+// The host's fakes are reset before every case. Run with
+// VIEWRA_UPDATE_GOLDEN=1 to write the golden files.
+func RunScannerHookGolden(t *testing.T, host *Host, hook plugins.ScannerHookService, cases []ScannerHookCase) {
+	t.Helper()
+
+	for _, tc := range cases {
+		t.Run(tc.Name, func(t *testing.T) {
+			host.Assets.Reset()
+			host.Enrichments.Reset()
+
+			var result ScannerHookResult
+			err := hook.OnMediaFileScanned(tc.MediaFileID, tc.FilePath, tc.Metadata)
+			if skipErr, ok := plugins.AsSkipError(err); ok {
+				result.SkipReason = skipErr.Reason
+			} else if err != nil {
+				result.Error = err.Error()
+				if pluginErr, ok := plugins.AsPluginError(err); ok {
+					result.ErrorCode = string(pluginErr.Code)
+				}
+			}
+			result.Enrichments = host.Enrichments.Enrichments()
+			result.Assets = host.Assets.Assets()
+
+			name := tc.Golden
+			if name == "" {
+				name = tc.Name
+			}
+			AssertGolden(t, GoldenPath(name), result)
+		})
+	}
+}
+
+var unsafeGoldenChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// GoldenPath returns testdata/<name>.golden with unsafe characters replaced
+func GoldenPath(name string) string {
+	return filepath.Join("testdata", unsafeGoldenChars.ReplaceAllString(name, "_")+".golden")
+}
+
+// AssertGolden compares v, encoded as indented JSON, with the golden file at path
+func AssertGolden(tb testing.TB, path string, v interface{}) {
+	tb.Helper()
+
+	got, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		tb.Fatalf("plugintest: failed to encode golden value: %v", err)
+	}
+	got = append(got, '\n')
+
+	if os.Getenv(UpdateGoldenEnv) == "1" {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			tb.Fatalf("plugintest: failed to create golden directory: %v", err)
+		}
+		if err := os.WriteFile(path, got, 0644); err != nil {
+			tb.Fatalf("plugintest: failed to write golden file: %v", err)
+		}
+		return
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		tb.Fatalf("plugintest: failed to read golden file (run with %s=1 to create it): %v", UpdateGoldenEnv, err)
+	}
+	if !bytes.Equal(want, got) {
+		tb.Errorf("plugintest: %s does not match\n--- want\n%s\n--- got\n%s", path, want, got)
+	}
+}
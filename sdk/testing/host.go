@@ -0,0 +1,114 @@
+package plugintest
+
+import (
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+	plugins "github.com/mantonx/viewra/sdk"
+	"github.com/mantonx/viewra/sdk/proto"
+	"google.golang.org/grpc"
+)
+
+// Host is a fake Viewra host for one test. Its asset, media data and media
+// entity services are also served over gRPC on a loopback port, so plugins that dial PluginContext.HostServiceAddr
+// with plugins.NewUnifiedServiceClient work unchanged.
+type Host struct {
+	Assets      *FakeAssetService
+	Enrichments *FakeEnrichmentService
+	MediaData   *FakeMediaDataService
+	Entities    *FakeMediaEntityService
+
+	addr string
+	tb   testing.TB
+}
+
+// NewHost starts a fake host that is shut down when the test ends
+func NewHost(tb testing.TB) *Host {
+	tb.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		tb.Fatalf("plugintest: failed to listen for host services: %v", err)
+	}
+
+	host := &Host{
+		Assets:      NewFakeAssetService(),
+		Enrichments: NewFakeEnrichmentService(),
+		MediaData:   NewFakeMediaDataService(),
+		Entities:    NewFakeMediaEntityService(),
+		addr:        listener.Addr().String(),
+		tb:          tb,
+	}
+
+	server := grpc.NewServer()
+	proto.RegisterAssetServiceServer(server, &assetServer{fake: host.Assets})
+	proto.RegisterMediaDataServiceServer(server, &mediaDataServer{fake: host.MediaData})
+	proto.RegisterMediaEntityServiceServer(server, &mediaEntityServer{fake: host.Entities})
+	go server.Serve(listener)
+	tb.Cleanup(server.Stop)
+
+	return host
+}
+
+// Addr returns the address host services are served on
+func (h *Host) Addr() string {
+	return h.addr
+}
+
+// AssetService returns the fake asset service, matching plugins.UnifiedServiceClient
+func (h *Host) AssetService() plugins.AssetServiceClient {
+	return h.Assets
+}
+
+// EnrichmentService returns the fake enrichment service, matching plugins.UnifiedServiceClient
+func (h *Host) EnrichmentService() plugins.EnrichmentServiceClient {
+	return h.Enrichments
+}
+
+// MediaDataService returns the fake media data service, matching plugins.UnifiedServiceClient
+func (h *Host) MediaDataService() plugins.MediaDataServiceClient {
+	return h.MediaData
+}
+
+// MediaEntityService returns the fake media entity service, matching plugins.UnifiedServiceClient
+func (h *Host) MediaEntityService() plugins.MediaEntityServiceClient {
+	return h.Entities
+}
+
+// Context returns a PluginContext for pluginID pointing at this host, with
+// temporary plugin directories and a logger that writes to the test log
+func (h *Host) Context(pluginID string) *plugins.PluginContext {
+	dir := h.tb.TempDir()
+	return &plugins.PluginContext{
+		PluginID:        pluginID,
+		DatabaseURL:     "sqlite://:memory:",
+		HostServiceAddr: h.addr,
+		PluginBasePath:  dir,
+		LogLevel:        "debug",
+		BasePath:        dir,
+		Logger:          NewLogger(h.tb, pluginID),
+	}
+}
+
+// NewLogger returns a logger that writes to the test log
+func NewLogger(tb testing.TB, name string) hclog.Logger {
+	return hclog.New(&hclog.LoggerOptions{
+		Name:        name,
+		Level:       hclog.Debug,
+		Output:      testWriter{tb},
+		DisableTime: true,
+	})
+}
+
+// testWriter forwards log lines to testing.TB.Log
+type testWriter struct {
+	tb testing.TB
+}
+
+func (w testWriter) Write(p []byte) (int, error) {
+	w.tb.Helper()
+	w.tb.Log(strings.TrimRight(string(p), "\n"))
+	return len(p), nil
+}
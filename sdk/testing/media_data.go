@@ -0,0 +1,131 @@
+package plugintest
+
+import (
+	"context"
+	"sync"
+
+	plugins "github.com/mantonx/viewra/sdk"
+	"github.com/mantonx/viewra/sdk/proto"
+)
+
+// FakeMediaDataService is an in-memory plugins.MediaDataServiceClient serving
+// the media files and items a test adds
+type FakeMediaDataService struct {
+	mu    sync.Mutex
+	files map[string]plugins.MediaFileInfo
+	items []plugins.MediaItemInfo
+}
+
+// NewFakeMediaDataService creates an empty media data service
+func NewFakeMediaDataService() *FakeMediaDataService {
+	return &FakeMediaDataService{files: make(map[string]plugins.MediaFileInfo)}
+}
+
+// AddMediaFile makes GetMediaFile return file for its ID
+func (f *FakeMediaDataService) AddMediaFile(file plugins.MediaFileInfo) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.files[file.ID] = file
+}
+
+// AddMediaItem makes FindMediaByExternalID return item for each of its external IDs
+func (f *FakeMediaDataService) AddMediaItem(item plugins.MediaItemInfo) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	item.ExternalIDs = copyMap(item.ExternalIDs)
+	f.items = append(f.items, item)
+}
+
+// GetMediaFile implements plugins.MediaDataServiceClient
+func (f *FakeMediaDataService) GetMediaFile(ctx context.Context, mediaFileID string) (*plugins.MediaFileInfo, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	file, ok := f.files[mediaFileID]
+	if !ok {
+		return nil, nil
+	}
+	return &file, nil
+}
+
+// FindMediaByExternalID implements plugins.MediaDataServiceClient
+func (f *FakeMediaDataService) FindMediaByExternalID(ctx context.Context, source, externalID, mediaType string) ([]*plugins.MediaItemInfo, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var items []*plugins.MediaItemInfo
+	for _, item := range f.items {
+		if item.ExternalIDs[source] != externalID || (mediaType != "" && item.MediaType != mediaType) {
+			continue
+		}
+		found := item
+		found.ExternalIDs = copyMap(item.ExternalIDs)
+		items = append(items, &found)
+	}
+	return items, nil
+}
+
+// Reset removes every added file and item
+func (f *FakeMediaDataService) Reset() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.files = make(map[string]plugins.MediaFileInfo)
+	f.items = nil
+}
+
+// mediaDataServer serves a FakeMediaDataService over gRPC
+type mediaDataServer struct {
+	proto.UnimplementedMediaDataServiceServer
+	fake *FakeMediaDataService
+}
+
+func (s *mediaDataServer) GetMediaFile(ctx context.Context, req *proto.GetMediaFileRequest) (*proto.GetMediaFileResponse, error) {
+	file, err := s.fake.GetMediaFile(ctx, req.MediaFileId)
+	if err != nil {
+		return nil, err
+	}
+	if file == nil {
+		return &proto.GetMediaFileResponse{Found: false}, nil
+	}
+	return &proto.GetMediaFileResponse{
+		Found: true,
+		MediaFile: &proto.MediaFileInfo{
+			Id:            file.ID,
+			MediaId:       file.MediaID,
+			MediaType:     file.MediaType,
+			LibraryId:     file.LibraryID,
+			LibraryType:   file.LibraryType,
+			LibraryPath:   file.LibraryPath,
+			Path:          file.Path,
+			Container:     file.Container,
+			VideoCodec:    file.VideoCodec,
+			AudioCodec:    file.AudioCodec,
+			Resolution:    file.Resolution,
+			Duration:      int32(file.Duration),
+			SizeBytes:     file.SizeBytes,
+			Title:         file.Title,
+			Year:          int32(file.Year),
+			ShowId:        file.ShowID,
+			ShowTitle:     file.ShowTitle,
+			SeasonNumber:  int32(file.SeasonNumber),
+			EpisodeNumber: int32(file.EpisodeNumber),
+		},
+	}, nil
+}
+
+func (s *mediaDataServer) FindMediaByExternalID(ctx context.Context, req *proto.FindMediaByExternalIDRequest) (*proto.FindMediaByExternalIDResponse, error) {
+	items, err := s.fake.FindMediaByExternalID(ctx, req.Source, req.ExternalId, req.MediaType)
+	if err != nil {
+		return nil, err
+	}
+	resp := &proto.FindMediaByExternalIDResponse{}
+	for _, item := range items {
+		resp.Items = append(resp.Items, &proto.MediaItemInfo{
+			Id:          item.ID,
+			MediaType:   item.MediaType,
+			Title:       item.Title,
+			Year:        int32(item.Year),
+			ExternalIds: item.ExternalIDs,
+		})
+	}
+	return resp, nil
+}
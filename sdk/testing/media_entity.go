@@ -0,0 +1,400 @@
+package plugintest
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	plugins "github.com/mantonx/viewra/sdk"
+	"github.com/mantonx/viewra/sdk/proto"
+)
+
+// MediaEntity is a movie, TV show, season or episode created through a
+// FakeMediaEntityService
+type MediaEntity struct {
+	ID          string            `json:"id"`
+	MediaType   string            `json:"media_type"` // movie, tv_show, season or episode
+	ParentID    string            `json:"parent_id,omitempty"`
+	Number      int               `json:"number,omitempty"` // Season or episode number
+	Title       string            `json:"title,omitempty"`
+	Year        int               `json:"year,omitempty"`
+	ExternalIDs map[string]string `json:"external_ids,omitempty"`
+}
+
+// StoredPerson is a person created through a FakeMediaEntityService
+type StoredPerson struct {
+	ID string `json:"id"`
+	plugins.Person
+}
+
+// FakeMediaEntityService is an in-memory plugins.MediaEntityServiceClient
+// that matches items the way the host does: by external ID, then by title
+// and year for movies and shows, by number within the parent for seasons
+// and episodes, and by external ID then name for people
+type FakeMediaEntityService struct {
+	mu       sync.Mutex
+	entities []*MediaEntity
+	links    map[string]string
+	people   []*StoredPerson
+	credits  map[string][]plugins.Credit
+}
+
+// NewFakeMediaEntityService creates an empty media entity service
+func NewFakeMediaEntityService() *FakeMediaEntityService {
+	return &FakeMediaEntityService{
+		links:   make(map[string]string),
+		credits: make(map[string][]plugins.Credit),
+	}
+}
+
+// UpsertMovie implements plugins.MediaEntityServiceClient
+func (f *FakeMediaEntityService) UpsertMovie(ctx context.Context, req *plugins.UpsertMovieRequest) (*plugins.UpsertResult, error) {
+	if strings.TrimSpace(req.Title) == "" {
+		return nil, fmt.Errorf("title is required")
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	result := f.upsertTitled("movie", req.Title, req.Year, req.ExternalIDs)
+	f.link(req.MediaFileID, result.ID)
+	return result, nil
+}
+
+// UpsertShow implements plugins.MediaEntityServiceClient
+func (f *FakeMediaEntityService) UpsertShow(ctx context.Context, req *plugins.UpsertShowRequest) (*plugins.UpsertResult, error) {
+	if strings.TrimSpace(req.Title) == "" {
+		return nil, fmt.Errorf("title is required")
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.upsertTitled("tv_show", req.Title, req.Year, req.ExternalIDs), nil
+}
+
+// UpsertSeason implements plugins.MediaEntityServiceClient
+func (f *FakeMediaEntityService) UpsertSeason(ctx context.Context, showID string, seasonNumber int) (*plugins.UpsertResult, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.find(showID, "tv_show") == nil {
+		return nil, fmt.Errorf("TV show %s not found", showID)
+	}
+	return f.upsertNumbered("season", showID, seasonNumber, ""), nil
+}
+
+// UpsertEpisode implements plugins.MediaEntityServiceClient
+func (f *FakeMediaEntityService) UpsertEpisode(ctx context.Context, req *plugins.UpsertEpisodeRequest) (*plugins.UpsertResult, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.find(req.SeasonID, "season") == nil {
+		return nil, fmt.Errorf("season %s not found", req.SeasonID)
+	}
+
+	title := req.Title
+	if title == "" {
+		title = fmt.Sprintf("Episode %d", req.EpisodeNumber)
+	}
+	result := f.upsertNumbered("episode", req.SeasonID, req.EpisodeNumber, title)
+	episode := f.find(result.ID, "episode")
+	if req.Title != "" {
+		episode.Title = req.Title
+	}
+	for source, id := range req.ExternalIDs {
+		episode.ExternalIDs[source] = id
+	}
+	f.link(req.MediaFileID, result.ID)
+	return result, nil
+}
+
+// UpsertPerson implements plugins.MediaEntityServiceClient
+func (f *FakeMediaEntityService) UpsertPerson(ctx context.Context, person *plugins.Person) (*plugins.UpsertResult, error) {
+	if strings.TrimSpace(person.Name) == "" {
+		return nil, fmt.Errorf("name is required")
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.upsertPerson(person), nil
+}
+
+// SetCredits implements plugins.MediaEntityServiceClient. Credits are kept
+// by media file, as the fake doesn't know which item a file belongs to.
+func (f *FakeMediaEntityService) SetCredits(ctx context.Context, mediaFileID string, credits []plugins.Credit) (*plugins.SetCreditsResult, error) {
+	if mediaFileID == "" {
+		return nil, fmt.Errorf("media_file_id is required")
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	result := &plugins.SetCreditsResult{MediaID: f.links[mediaFileID], PersonIDs: make([]string, 0, len(credits))}
+	for i := range credits {
+		if strings.TrimSpace(credits[i].Person.Name) == "" {
+			return nil, fmt.Errorf("credit %d has no name", i)
+		}
+		result.PersonIDs = append(result.PersonIDs, f.upsertPerson(&credits[i].Person).ID)
+	}
+	f.credits[mediaFileID] = append([]plugins.Credit(nil), credits...)
+	return result, nil
+}
+
+// upsertPerson finds a person by external ID, then by name among people
+// without external IDs, or creates them, filling in the details given
+func (f *FakeMediaEntityService) upsertPerson(person *plugins.Person) *plugins.UpsertResult {
+	var match *StoredPerson
+	for _, stored := range f.people {
+		for source, id := range person.ExternalIDs {
+			if id != "" && stored.ExternalIDs[source] == id {
+				match = stored
+			}
+		}
+	}
+	if match == nil {
+		for _, stored := range f.people {
+			if strings.EqualFold(stored.Name, person.Name) && len(stored.ExternalIDs) == 0 {
+				match = stored
+				break
+			}
+		}
+	}
+
+	created := match == nil
+	if created {
+		match = &StoredPerson{
+			ID:     fmt.Sprintf("person-%d", len(f.people)+1),
+			Person: plugins.Person{Name: person.Name, ExternalIDs: make(map[string]string)},
+		}
+		f.people = append(f.people, match)
+	}
+	for source, id := range person.ExternalIDs {
+		if id != "" {
+			match.ExternalIDs[source] = id
+		}
+	}
+	details := []struct {
+		stored *string
+		value  string
+	}{
+		{&match.Biography, person.Biography},
+		{&match.Birthdate, person.Birthdate},
+		{&match.Deathdate, person.Deathdate},
+		{&match.PlaceOfBirth, person.PlaceOfBirth},
+		{&match.KnownForDepartment, person.KnownForDepartment},
+		{&match.ProfileURL, person.ProfileURL},
+	}
+	for _, detail := range details {
+		if detail.value != "" {
+			*detail.stored = detail.value
+		}
+	}
+	return &plugins.UpsertResult{ID: match.ID, Created: created}
+}
+
+// upsertTitled finds a movie or show by external ID, then title and year,
+// or creates it
+func (f *FakeMediaEntityService) upsertTitled(mediaType, title string, year int, externalIDs map[string]string) *plugins.UpsertResult {
+	var match *MediaEntity
+	for _, entity := range f.entities {
+		if entity.MediaType != mediaType {
+			continue
+		}
+		for source, id := range externalIDs {
+			if id != "" && entity.ExternalIDs[source] == id {
+				match = entity
+			}
+		}
+	}
+	if match == nil {
+		for _, entity := range f.entities {
+			if entity.MediaType == mediaType && strings.EqualFold(entity.Title, title) &&
+				(year == 0 || entity.Year == 0 || entity.Year == year) {
+				match = entity
+				break
+			}
+		}
+	}
+
+	created := match == nil
+	if created {
+		match = &MediaEntity{
+			ID:          fmt.Sprintf("%s-%d", mediaType, len(f.entities)+1),
+			MediaType:   mediaType,
+			Title:       title,
+			ExternalIDs: make(map[string]string),
+		}
+		f.entities = append(f.entities, match)
+	}
+	if match.Year == 0 {
+		match.Year = year
+	}
+	for source, id := range externalIDs {
+		if id != "" {
+			match.ExternalIDs[source] = id
+		}
+	}
+	return &plugins.UpsertResult{ID: match.ID, Created: created}
+}
+
+// upsertNumbered finds a season or episode by number within its parent, or
+// creates it
+func (f *FakeMediaEntityService) upsertNumbered(mediaType, parentID string, number int, title string) *plugins.UpsertResult {
+	for _, entity := range f.entities {
+		if entity.MediaType == mediaType && entity.ParentID == parentID && entity.Number == number {
+			return &plugins.UpsertResult{ID: entity.ID}
+		}
+	}
+
+	entity := &MediaEntity{
+		ID:          fmt.Sprintf("%s-%d", mediaType, len(f.entities)+1),
+		MediaType:   mediaType,
+		ParentID:    parentID,
+		Number:      number,
+		Title:       title,
+		ExternalIDs: make(map[string]string),
+	}
+	f.entities = append(f.entities, entity)
+	return &plugins.UpsertResult{ID: entity.ID, Created: true}
+}
+
+func (f *FakeMediaEntityService) find(id, mediaType string) *MediaEntity {
+	for _, entity := range f.entities {
+		if entity.ID == id && entity.MediaType == mediaType {
+			return entity
+		}
+	}
+	return nil
+}
+
+func (f *FakeMediaEntityService) link(mediaFileID, entityID string) {
+	if mediaFileID != "" {
+		f.links[mediaFileID] = entityID
+	}
+}
+
+// Entities returns every created item in creation order
+func (f *FakeMediaEntityService) Entities() []MediaEntity {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	entities := make([]MediaEntity, 0, len(f.entities))
+	for _, entity := range f.entities {
+		copied := *entity
+		copied.ExternalIDs = copyMap(entity.ExternalIDs)
+		entities = append(entities, copied)
+	}
+	return entities
+}
+
+// LinkedEntity returns the ID of the item a media file was linked to, or ""
+func (f *FakeMediaEntityService) LinkedEntity(mediaFileID string) string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.links[mediaFileID]
+}
+
+// People returns every created person in creation order
+func (f *FakeMediaEntityService) People() []StoredPerson {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	people := make([]StoredPerson, 0, len(f.people))
+	for _, person := range f.people {
+		copied := *person
+		copied.ExternalIDs = copyMap(person.ExternalIDs)
+		people = append(people, copied)
+	}
+	return people
+}
+
+// Credits returns the credits last set for a media file
+func (f *FakeMediaEntityService) Credits(mediaFileID string) []plugins.Credit {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]plugins.Credit(nil), f.credits[mediaFileID]...)
+}
+
+// Reset removes every created item, person, credit and link
+func (f *FakeMediaEntityService) Reset() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.entities = nil
+	f.links = make(map[string]string)
+	f.people = nil
+	f.credits = make(map[string][]plugins.Credit)
+}
+
+// mediaEntityServer serves a FakeMediaEntityService over gRPC
+type mediaEntityServer struct {
+	proto.UnimplementedMediaEntityServiceServer
+	fake *FakeMediaEntityService
+}
+
+func (s *mediaEntityServer) UpsertMovie(ctx context.Context, req *proto.UpsertMovieRequest) (*proto.UpsertEntityResponse, error) {
+	return upsertResponse(s.fake.UpsertMovie(ctx, &plugins.UpsertMovieRequest{
+		Title:       req.Title,
+		Year:        int(req.Year),
+		ExternalIDs: req.ExternalIds,
+		MediaFileID: req.MediaFileId,
+	}))
+}
+
+func (s *mediaEntityServer) UpsertShow(ctx context.Context, req *proto.UpsertShowRequest) (*proto.UpsertEntityResponse, error) {
+	return upsertResponse(s.fake.UpsertShow(ctx, &plugins.UpsertShowRequest{
+		Title:       req.Title,
+		Year:        int(req.Year),
+		ExternalIDs: req.ExternalIds,
+	}))
+}
+
+func (s *mediaEntityServer) UpsertSeason(ctx context.Context, req *proto.UpsertSeasonRequest) (*proto.UpsertEntityResponse, error) {
+	return upsertResponse(s.fake.UpsertSeason(ctx, req.ShowId, int(req.SeasonNumber)))
+}
+
+func (s *mediaEntityServer) UpsertEpisode(ctx context.Context, req *proto.UpsertEpisodeRequest) (*proto.UpsertEntityResponse, error) {
+	return upsertResponse(s.fake.UpsertEpisode(ctx, &plugins.UpsertEpisodeRequest{
+		SeasonID:      req.SeasonId,
+		EpisodeNumber: int(req.EpisodeNumber),
+		Title:         req.Title,
+		ExternalIDs:   req.ExternalIds,
+		MediaFileID:   req.MediaFileId,
+	}))
+}
+
+func (s *mediaEntityServer) UpsertPerson(ctx context.Context, req *proto.UpsertPersonRequest) (*proto.UpsertEntityResponse, error) {
+	return upsertResponse(s.fake.UpsertPerson(ctx, fromPersonInfo(req.Person)))
+}
+
+func (s *mediaEntityServer) SetCredits(ctx context.Context, req *proto.SetCreditsRequest) (*proto.SetCreditsResponse, error) {
+	credits := make([]plugins.Credit, 0, len(req.Credits))
+	for _, credit := range req.Credits {
+		credits = append(credits, plugins.Credit{
+			Person:     *fromPersonInfo(credit.Person),
+			Role:       credit.Role,
+			Character:  credit.Character,
+			Department: credit.Department,
+			Order:      int(credit.Order),
+		})
+	}
+	result, err := s.fake.SetCredits(ctx, req.MediaFileId, credits)
+	if err != nil {
+		return nil, err
+	}
+	return &proto.SetCreditsResponse{MediaId: result.MediaID, PersonIds: result.PersonIDs}, nil
+}
+
+func fromPersonInfo(info *proto.PersonInfo) *plugins.Person {
+	if info == nil {
+		return &plugins.Person{}
+	}
+	return &plugins.Person{
+		Name:               info.Name,
+		ExternalIDs:        info.ExternalIds,
+		Biography:          info.Biography,
+		Birthdate:          info.Birthdate,
+		Deathdate:          info.Deathdate,
+		PlaceOfBirth:       info.PlaceOfBirth,
+		KnownForDepartment: info.KnownForDepartment,
+		ProfileURL:         info.ProfileUrl,
+	}
+}
+
+func upsertResponse(result *plugins.UpsertResult, err error) (*proto.UpsertEntityResponse, error) {
+	if err != nil {
+		return nil, err
+	}
+	return &proto.UpsertEntityResponse{Id: result.ID, Created: result.Created}, nil
+}
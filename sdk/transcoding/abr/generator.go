@@ -19,6 +19,10 @@
 package abr
 
 import (
+	"fmt"
+	"regexp"
+	"sort"
+
 	"github.com/mantonx/viewra/sdk/transcoding/types"
 )
 
@@ -35,60 +39,117 @@ type BitrateLadderRung struct {
 	UseCase      string // Device/network type this targets
 }
 
+// RungConfig configures one quality level of the ladder, as set in the
+// transcoder's config. Profile, Level and CRF are derived from the height
+// when unset.
+type RungConfig struct {
+	Label        string `json:"label"`              // Names the variant's playlist and segments, e.g. 720p
+	Height       int    `json:"height"`             // Width follows the source's aspect ratio
+	VideoBitrate int    `json:"video_bitrate"`      // kbps at quality 80, scaled with the requested quality
+	AudioBitrate int    `json:"audio_bitrate"`      // kbps
+	Profile      string `json:"profile,omitempty"`  // H.264 profile
+	Level        string `json:"level,omitempty"`    // H.264 level
+	CRF          int    `json:"crf,omitempty"`      // Constant Rate Factor
+	UseCase      string `json:"use_case,omitempty"` // Device/network type this targets
+}
+
+// DefaultRungs is the ladder used when none is configured
+var DefaultRungs = []RungConfig{
+	// CPU-optimized ladder with fewer rungs
+	// Comment: Reduced from 6 to 3 rungs to lower CPU usage by 50%
+	// Mobile/Low bandwidth - covers 240p-480p use cases
+	{Label: "480p", Height: 480, VideoBitrate: 700, AudioBitrate: 96, Profile: "main", Level: "3.1", CRF: 28, UseCase: "mobile/WiFi"},
+	// HD for standard viewing - most common use case
+	{Label: "720p", Height: 720, VideoBitrate: 1500, AudioBitrate: 96, Profile: "main", Level: "4.0", CRF: 26, UseCase: "broadband"},
+	// Full HD for high quality - only when needed
+	{Label: "1080p", Height: 1080, VideoBitrate: 2800, AudioBitrate: 128, Profile: "high", Level: "4.1", CRF: 24, UseCase: "fiber/excellent"},
+}
+
+// rungLabelPattern keeps labels usable in file names and FFmpeg's stream maps
+var rungLabelPattern = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// Validate checks a configured rung
+func (r RungConfig) Validate() error {
+	if !rungLabelPattern.MatchString(r.Label) {
+		return fmt.Errorf("label %q must be letters, digits, '-' or '_'", r.Label)
+	}
+	if r.Height <= 0 || r.Height%2 != 0 {
+		return fmt.Errorf("rung %s: height must be positive and even", r.Label)
+	}
+	if r.VideoBitrate <= 0 {
+		return fmt.Errorf("rung %s: video bitrate must be positive", r.Label)
+	}
+	if r.AudioBitrate < 0 {
+		return fmt.Errorf("rung %s: audio bitrate must be non-negative", r.Label)
+	}
+	if r.CRF < 0 || r.CRF > 51 {
+		return fmt.Errorf("rung %s: CRF must be between 0 and 51", r.Label)
+	}
+	return nil
+}
+
+// ValidateRungs checks a configured ladder. Labels name each variant's
+// output, so they must be unique.
+func ValidateRungs(rungs []RungConfig) error {
+	labels := make(map[string]bool, len(rungs))
+	for _, rung := range rungs {
+		if err := rung.Validate(); err != nil {
+			return err
+		}
+		if labels[rung.Label] {
+			return fmt.Errorf("duplicate rung label %q", rung.Label)
+		}
+		labels[rung.Label] = true
+	}
+	return nil
+}
+
 // Generator handles adaptive bitrate ladder generation
 type Generator struct {
 	logger types.Logger
+	rungs  []RungConfig
 }
 
-// NewGenerator creates a new ABR ladder generator
+// NewGenerator creates a new ABR ladder generator using DefaultRungs
 func NewGenerator(logger types.Logger) *Generator {
 	return &Generator{
 		logger: logger,
+		rungs:  DefaultRungs,
+	}
+}
+
+// SetRungs replaces the ladder's rungs; an empty ladder restores
+// DefaultRungs. Rungs are ordered by bitrate, lowest first for the fastest
+// startup.
+func (g *Generator) SetRungs(rungs []RungConfig) {
+	if len(rungs) == 0 {
+		g.rungs = DefaultRungs
+		return
 	}
+	g.rungs = append([]RungConfig(nil), rungs...)
+	sort.SliceStable(g.rungs, func(i, j int) bool { return g.rungs[i].VideoBitrate < g.rungs[j].VideoBitrate })
 }
 
 // GenerateLadder creates an optimized set of encoding profiles for different device types
 func (g *Generator) GenerateLadder(sourceWidth, sourceHeight, quality int) []BitrateLadderRung {
 	var ladder []BitrateLadderRung
-	
+
 	// Calculate aspect ratio
 	aspectRatio := float64(sourceWidth) / float64(sourceHeight)
-	
-	// Define device-optimized ladder rungs
-	// Order matters: lowest bandwidth first for fastest startup
-	standardRungs := []struct {
-		height       int
-		videoBitrate int // kbps
-		audioBitrate int // kbps  
-		profile      string
-		level        string
-		crf          int
-		label        string
-		useCase      string // Device/network type this targets
-	}{
-		// CPU-optimized ladder with fewer rungs
-		// Comment: Reduced from 6 to 3 rungs to lower CPU usage by 50%
-		// Mobile/Low bandwidth - covers 240p-480p use cases
-		{480, 700, 96, "main", "3.1", 28, "480p", "mobile/WiFi"},
-		// HD for standard viewing - most common use case
-		{720, 1500, 96, "main", "4.0", 26, "720p", "broadband"},
-		// Full HD for high quality - only when needed
-		{1080, 2800, 128, "high", "4.1", 24, "1080p", "fiber/excellent"},
-	}
-	
+
 	// Filter rungs based on source resolution and create adaptive ladder
-	for _, rung := range standardRungs {
+	for _, rung := range g.rungs {
 		// Don't exceed source resolution
-		if rung.height > sourceHeight {
+		if rung.Height > sourceHeight {
 			continue
 		}
-		
+
 		// Calculate proper width maintaining aspect ratio
-		width := int(float64(rung.height) * aspectRatio)
+		width := int(float64(rung.Height) * aspectRatio)
 		if width%2 != 0 {
 			width++ // Ensure even width for video encoding
 		}
-		
+
 		// Adjust bitrate based on quality setting (0-100 range)
 		// Optimized for real-time streaming
 		qualityMultiplier := float64(quality) / 80.0 // Normalize around 80% quality
@@ -98,27 +159,41 @@ func (g *Generator) GenerateLadder(sourceWidth, sourceHeight, quality int) []Bit
 		if qualityMultiplier > 1.2 {
 			qualityMultiplier = 1.2 // Maximum quality threshold for real-time
 		}
-		
-		adjustedBitrate := int(float64(rung.videoBitrate) * qualityMultiplier)
-		
+
+		adjustedBitrate := int(float64(rung.VideoBitrate) * qualityMultiplier)
+		profile, level, crf := rung.Profile, rung.Level, rung.CRF
+		if profile == "" {
+			profile = profileForHeight(rung.Height)
+		}
+		if level == "" {
+			level = levelForHeight(rung.Height)
+		}
+		if crf == 0 {
+			crf = crfForHeight(rung.Height)
+		}
+		audioBitrate := rung.AudioBitrate
+		if audioBitrate == 0 {
+			audioBitrate = 128
+		}
+
 		ladder = append(ladder, BitrateLadderRung{
 			Width:        width,
-			Height:       rung.height,
+			Height:       rung.Height,
 			VideoBitrate: adjustedBitrate,
-			AudioBitrate: rung.audioBitrate,
-			Profile:      rung.profile,
-			Level:        rung.level,
-			CRF:          rung.crf,
-			Label:        rung.label,
-			UseCase:      rung.useCase,
+			AudioBitrate: audioBitrate,
+			Profile:      profile,
+			Level:        level,
+			CRF:          crf,
+			Label:        rung.Label,
+			UseCase:      rung.UseCase,
 		})
-		
+
 		if g.logger != nil {
 			g.logger.Debug("added ladder rung",
-				"label", rung.label,
-				"resolution", rung.height,
+				"label", rung.Label,
+				"resolution", rung.Height,
 				"bitrate", adjustedBitrate,
-				"use_case", rung.useCase,
+				"use_case", rung.UseCase,
 			)
 		}
 	}
@@ -159,6 +234,44 @@ func (g *Generator) GenerateLadder(sourceWidth, sourceHeight, quality int) []Bit
 	return ladder
 }
 
+// profileForHeight returns the H.264 profile for a rung without one
+func profileForHeight(height int) string {
+	if height >= 1080 {
+		return "high"
+	}
+	return "main"
+}
+
+// levelForHeight returns the lowest H.264 level covering a rung's resolution
+// at 30fps
+func levelForHeight(height int) string {
+	switch {
+	case height <= 480:
+		return "3.1"
+	case height <= 720:
+		return "4.0"
+	case height <= 1080:
+		return "4.1"
+	case height <= 1440:
+		return "5.0"
+	default:
+		return "5.1"
+	}
+}
+
+// crfForHeight returns the CRF for a rung without one; larger frames hide
+// compression less
+func crfForHeight(height int) int {
+	switch {
+	case height <= 480:
+		return 28
+	case height <= 720:
+		return 26
+	default:
+		return 24
+	}
+}
+
 // GetOptimalRung returns the best quality rung for a given bandwidth
 func (g *Generator) GetOptimalRung(ladder []BitrateLadderRung, availableBandwidth int) *BitrateLadderRung {
 	// Safety margin - use 80% of available bandwidth
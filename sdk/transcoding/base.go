@@ -20,7 +20,6 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
-	"syscall"
 	"time"
 
 	"github.com/mantonx/viewra/sdk/transcoding/dashboard"
@@ -132,7 +131,7 @@ func (bt *BaseTranscoder) GetSupportedFormats() []types.ContainerFormat {
 		{
 			Format:      "hls",
 			MimeType:    "application/vnd.apple.mpegurl",
-			Extensions:  []string{".m3u8", ".ts"},
+			Extensions:  []string{".m3u8", ".m4s"},
 			Description: "HLS Adaptive Streaming",
 			Adaptive:    true,
 		},
@@ -168,9 +167,7 @@ func (bt *BaseTranscoder) StartTranscode(ctx context.Context, req types.Transcod
 
 	// Create command
 	cmd := exec.Command(bt.ffmpegPath, args...)
-	cmd.SysProcAttr = &syscall.SysProcAttr{
-		Setpgid: true, // Create new process group
-	}
+	process.ConfigureCommand(cmd) // Own process group so children are cleaned up too
 	cmd.Dir = outputDir
 
 	// Set up logging
@@ -180,6 +177,9 @@ func (bt *BaseTranscoder) StartTranscode(ctx context.Context, req types.Transcod
 		}
 	}
 
+	// Parse FFmpeg's -progress output into real-time stats
+	tracker := attachProgressTracker(cmd, req.Seek)
+
 	// Log the command
 	if bt.logger != nil {
 		bt.logger.Info("starting FFmpeg transcoding",
@@ -191,6 +191,7 @@ func (bt *BaseTranscoder) StartTranscode(ctx context.Context, req types.Transcod
 	// Update session with process
 	bt.sessionManager.UpdateSession(sess.ID, func(s *session.Session) {
 		s.Process = cmd
+		s.Tracker = tracker
 		s.Status = session.SessionStatusStarting
 	})
 
@@ -231,13 +232,19 @@ func (bt *BaseTranscoder) GetProgress(handle *types.TranscodeHandle) (*types.Tra
 		return nil, err
 	}
 
-	// Calculate progress
-	elapsed := time.Since(sess.StartTime)
+	// Prefer real stats parsed from FFmpeg's -progress output
+	if sess.Tracker != nil && sess.Tracker.HasStats() {
+		progress := sess.Tracker.Progress()
+		if progress.PercentComplete == 0 {
+			progress.PercentComplete = sess.Progress
+		}
+		return progress, nil
+	}
+
+	// No stats reported yet
 	progress := &types.TranscodingProgress{
 		PercentComplete: sess.Progress,
-		TimeElapsed:     elapsed,
-		CurrentSpeed:    1.0,
-		AverageSpeed:    1.0,
+		TimeElapsed:     time.Since(sess.StartTime),
 	}
 
 	return progress, nil
@@ -316,10 +323,7 @@ func (bt *BaseTranscoder) prepareOutputPaths(req types.TranscodeRequest, session
 	if req.OutputPath != "" {
 		outputDir = req.OutputPath
 	} else {
-		baseDir := "/app/viewra-data/transcoding"
-		if envDir := os.Getenv("VIEWRA_TRANSCODING_DIR"); envDir != "" {
-			baseDir = envDir
-		}
+		baseDir := utils.TranscodingDir()
 		outputDir = filepath.Join(baseDir, fmt.Sprintf("%s_%s_%s", req.Container, bt.name, sessionID))
 	}
 	
@@ -334,7 +338,7 @@ func (bt *BaseTranscoder) prepareOutputPaths(req types.TranscodeRequest, session
 	case "dash":
 		outputPath = filepath.Join(outputDir, "manifest.mpd")
 	case "hls":
-		outputPath = filepath.Join(outputDir, "playlist.m3u8")
+		outputPath = filepath.Join(outputDir, ffmpeg.HLSMasterPlaylist)
 	default:
 		outputPath = filepath.Join(outputDir, fmt.Sprintf("output.%s", req.Container))
 	}
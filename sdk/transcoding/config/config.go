@@ -24,7 +24,10 @@ import (
 	"time"
 
 	plugins "github.com/mantonx/viewra/sdk"
+	"github.com/mantonx/viewra/sdk/transcoding/abr"
+	"github.com/mantonx/viewra/sdk/transcoding/ffmpeg"
 	"github.com/mantonx/viewra/sdk/transcoding/types"
+	"github.com/mantonx/viewra/sdk/transcoding/utils"
 )
 
 // Config represents the complete transcoder configuration
@@ -54,6 +57,7 @@ type HardwareConfig struct {
 	PreferredType   types.HardwareType `json:"preferred_type"`   // "auto", "cuda", "vaapi", etc.
 	DeviceSelection string               `json:"device_selection"` // "auto", "first", "load-balanced"
 	Fallback        bool                 `json:"fallback"`         // Fall back to software if HW fails
+	MaxSessions     int                  `json:"max_sessions"`     // Concurrent encodes per device, 0 uses each device type's default
 }
 
 // SessionConfig contains generic session management settings
@@ -93,6 +97,12 @@ type FFmpegConfig struct {
 	TwoPass         bool     `json:"two_pass"`          // Enable two-pass encoding
 	AudioBitrate    int      `json:"audio_bitrate"`     // Default audio bitrate in kbps
 	LogFFmpegOutput bool     `json:"log_ffmpeg_output"` // Log FFmpeg command output
+
+	// Per-profile argument templates; the first matching template is applied
+	Templates []ffmpeg.ArgTemplate `json:"templates,omitempty"`
+
+	// Rungs of the ABR ladder, encoded together in one session; empty uses abr.DefaultRungs
+	ABRLadder []abr.RungConfig `json:"abr_ladder,omitempty"`
 }
 
 // DefaultConfig returns the default configuration
@@ -101,7 +111,7 @@ func DefaultConfig() *Config {
 		Core: CoreConfig{
 			Enabled:         true,
 			Priority:        50,
-			OutputDirectory: "/viewra-data/transcoding",
+			OutputDirectory: utils.DataDir(utils.TranscodingDirEnv, "/viewra-data/transcoding", "transcoding"),
 		},
 		Hardware: HardwareConfig{
 			Enabled:         true,
@@ -204,6 +214,25 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("thread count must be non-negative")
 	}
 
+	if c.Hardware.MaxSessions < 0 {
+		return fmt.Errorf("hardware sessions per device must be non-negative")
+	}
+
+	templateNames := make(map[string]bool)
+	for _, template := range c.FFmpeg.Templates {
+		if err := template.Validate(); err != nil {
+			return fmt.Errorf("invalid FFmpeg template %q: %w", template.Name, err)
+		}
+		if templateNames[template.Name] {
+			return fmt.Errorf("duplicate FFmpeg template %q", template.Name)
+		}
+		templateNames[template.Name] = true
+	}
+
+	if err := abr.ValidateRungs(c.FFmpeg.ABRLadder); err != nil {
+		return fmt.Errorf("invalid ABR ladder: %w", err)
+	}
+
 	// Validate session settings
 	if c.Sessions.MaxConcurrent <= 0 {
 		return fmt.Errorf("max concurrent sessions must be positive")
@@ -363,6 +392,46 @@ func (c *FFmpegConfigurationService) createFFmpegSchema() *plugins.Configuration
 				"description": "Enable or disable the FFmpeg transcoder plugin",
 				"default":     true,
 			},
+			"hardware": map[string]interface{}{
+				"type":  "object",
+				"title": "Hardware Acceleration",
+				"properties": map[string]interface{}{
+					"enabled": map[string]interface{}{
+						"type":        "boolean",
+						"title":       "Enable Hardware Encoding",
+						"description": "Encode on detected VAAPI, NVENC or Quick Sync devices when a transcode asks for hardware",
+						"default":     true,
+					},
+					"preferred_type": map[string]interface{}{
+						"type":        "string",
+						"title":       "Preferred Hardware",
+						"description": "Hardware used for every transcode; none only uses hardware when a transcode asks for it",
+						"enum":        []string{"none", "auto", "nvidia", "vaapi", "qsv"},
+						"default":     "none",
+					},
+					"device_selection": map[string]interface{}{
+						"type":        "string",
+						"title":       "Device Selection",
+						"description": "How encodes are spread over several devices",
+						"enum":        []string{"auto", "first", "load-balanced"},
+						"default":     "auto",
+					},
+					"fallback": map[string]interface{}{
+						"type":        "boolean",
+						"title":       "Software Fallback",
+						"description": "Encode in software when no device is free or the hardware encoder fails",
+						"default":     true,
+					},
+					"max_sessions": map[string]interface{}{
+						"type":        "integer",
+						"title":       "Sessions per Device",
+						"description": "Concurrent encodes per device (0 = default for the device type)",
+						"minimum":     0,
+						"maximum":     32,
+						"default":     0,
+					},
+				},
+			},
 			"ffmpeg": map[string]interface{}{
 				"type":  "object",
 				"title": "FFmpeg Settings",
@@ -395,6 +464,45 @@ func (c *FFmpegConfigurationService) createFFmpegSchema() *plugins.Configuration
 						"maximum":     20,
 						"default":     0,
 					},
+					"templates": map[string]interface{}{
+						"type":        "array",
+						"title":       "Argument Templates",
+						"description": "Extra FFmpeg arguments and filters applied to matching transcodes, first match wins",
+						"items": map[string]interface{}{
+							"type":     "object",
+							"required": []string{"name"},
+							"properties": map[string]interface{}{
+								"name":          map[string]interface{}{"type": "string"},
+								"description":   map[string]interface{}{"type": "string"},
+								"codecs":        map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "string"}},
+								"containers":    map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "string"}},
+								"input_args":    map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "string"}},
+								"video_args":    map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "string"}},
+								"video_filters": map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "string"}},
+								"audio_filters": map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "string"}},
+								"output_args":   map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "string"}},
+							},
+						},
+					},
+					"abr_ladder": map[string]interface{}{
+						"type":        "array",
+						"title":       "ABR Ladder",
+						"description": "Renditions encoded together for adaptive bitrate streaming, above the source's resolution skipped (empty = 480p, 720p, 1080p)",
+						"items": map[string]interface{}{
+							"type":     "object",
+							"required": []string{"label", "height", "video_bitrate"},
+							"properties": map[string]interface{}{
+								"label":         map[string]interface{}{"type": "string", "pattern": "^[A-Za-z0-9_-]+$"},
+								"height":        map[string]interface{}{"type": "integer", "minimum": 2},
+								"video_bitrate": map[string]interface{}{"type": "integer", "minimum": 1, "description": "kbps at quality 80"},
+								"audio_bitrate": map[string]interface{}{"type": "integer", "minimum": 0, "description": "kbps (0 = 128)"},
+								"profile":       map[string]interface{}{"type": "string"},
+								"level":         map[string]interface{}{"type": "string"},
+								"crf":           map[string]interface{}{"type": "integer", "minimum": 0, "maximum": 51},
+								"use_case":      map[string]interface{}{"type": "string"},
+							},
+						},
+					},
 				},
 			},
 			"transcoding": map[string]interface{}{
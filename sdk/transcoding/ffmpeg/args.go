@@ -25,10 +25,10 @@ package ffmpeg
 
 import (
 	"fmt"
-	"path/filepath"
 	"runtime"
 	"strconv"
 	"strings"
+	"sync"
 
 	"github.com/mantonx/viewra/sdk/transcoding/abr"
 	"github.com/mantonx/viewra/sdk/transcoding/types"
@@ -38,6 +38,12 @@ import (
 type FFmpegArgsBuilder struct {
 	logger          types.Logger
 	resourceManager *ResourceManager
+
+	templatesMu sync.RWMutex
+	templates   []ArgTemplate
+
+	ladderMu sync.RWMutex
+	ladder   []abr.RungConfig
 }
 
 // NewFFmpegArgsBuilder creates a new FFmpeg args builder
@@ -54,6 +60,11 @@ func (b *FFmpegArgsBuilder) BuildArgs(req types.TranscodeRequest, outputPath str
 
 	// Hardware acceleration - auto-detect best available method
 	args = append(args, "-hwaccel", "auto")
+
+	// Open the device a hardware encoder was assigned
+	if encoder := requestHardwareEncoder(req); encoder != "" {
+		args = append(args, HardwareInputArgs(req.HardwareType, req.HardwareDevice)...)
+	}
 	
 	// Get resource configuration
 	resources := b.resourceManager.GetOptimalResources(
@@ -72,27 +83,44 @@ func (b *FFmpegArgsBuilder) BuildArgs(req types.TranscodeRequest, outputPath str
 	args = append(args, "-y") // Always overwrite output files
 	args = append(args, "-hide_banner") // Hide FFmpeg banner for cleaner logs
 
+	// Report progress as key=value blocks on stdout for the progress tracker
+	args = append(args, GlobalArgs.Progress...)
+	args = append(args, GlobalArgs.NoStats...)
+
 	// Seek to position if specified (input seeking for efficiency)
 	if req.Seek > 0 {
 		args = append(args, InputArgs.SeekStart...)
 		args = append(args, fmt.Sprintf("%.3f", req.Seek.Seconds()))
 	}
 
+	// Template input options (e.g. decoder settings) must precede the input
+	template := b.templateFor(req)
+	if template != nil {
+		args = append(args, template.InputArgs...)
+	}
+
 	// Input file
 	args = append(args, InputArgs.Input...)
 	args = append(args, req.InputPath)
 
-	// For ABR (Adaptive Bitrate) streaming, let the specific function handle everything
-	if (req.Container == "dash" || req.Container == "hls") && req.EnableABR {
+	// Audio-only output (music streaming) has its own codec and muxer handling
+	if req.AudioOnly || IsAudioContainer(req.Container) {
+		args = append(args, b.buildAudioOnlyArgs(req, outputPath)...)
+	} else if (req.Container == "dash" || req.Container == "hls") && req.EnableABR {
+		// For ABR (Adaptive Bitrate) streaming, let the specific function handle everything
 		// Container-specific settings will handle all mapping and encoding
 		containerArgs := b.getContainerSpecificArgs(req, outputPath)
 		args = append(args, containerArgs...)
 	} else {
+		// An external subtitle passed through is a second input
+		args = append(args, subtitleInputArgs(req)...)
+
 		// Advanced video mapping and filtering
+		videoFilters, videoMaps := videoFilterArgs(req, []string{b.getVideoFilters(req)}, func(int) string { return "-vf" })
 		args = append(args, StreamMappingArgs.Map...)
-		args = append(args, "0:v:0") // Map first video stream
+		args = append(args, videoMaps[0]) // Map first video stream, or its subtitled output
 		args = append(args, StreamMappingArgs.Map...)
-		args = append(args, "0:a:0") // Map first audio stream
+		args = append(args, audioStreamSpecifier(req)) // Map the selected audio stream
 
 		// Video codec with intelligent defaults
 		videoCodec := b.getOptimalVideoCodec(req)
@@ -113,16 +141,21 @@ func (b *FFmpegArgsBuilder) BuildArgs(req types.TranscodeRequest, outputPath str
 		keyframeArgs := b.getKeyframeAlignmentArgs(req)
 		args = append(args, keyframeArgs...)
 
-		// Video filtering for quality enhancement
-		videoFilters := b.getVideoFilters(req)
-		if len(videoFilters) > 0 {
-			args = append(args, "-vf", videoFilters)
+		// Template encoder options come last so they override the defaults
+		if template != nil {
+			args = append(args, template.VideoArgs...)
 		}
 
+		// Video filtering for quality enhancement
+		args = append(args, videoFilters...)
+
 		// Audio settings optimized for source content
 		audioArgs := b.getOptimalAudioSettings(req)
 		args = append(args, audioArgs...)
 
+		// Subtitle stream for containers that carry one
+		args = append(args, b.subtitlePassthroughArgs(req)...)
+
 		// Apply resource optimizations
 		args = append(args, b.applyResourceOptimizations(resources, false)...)
 
@@ -131,7 +164,15 @@ func (b *FFmpegArgsBuilder) BuildArgs(req types.TranscodeRequest, outputPath str
 		args = append(args, containerArgs...)
 	}
 
-	// Output file
+	// Template output options
+	if template != nil {
+		args = append(args, template.OutputArgs...)
+	}
+
+	// Output file; HLS writes its media playlists beside the master playlist
+	if strings.ToLower(req.Container) == "hls" {
+		outputPath = hlsOutputPath(req, outputPath)
+	}
 	args = append(args, outputPath)
 
 	return args
@@ -143,9 +184,9 @@ func (b *FFmpegArgsBuilder) getStreamCount(req types.TranscodeRequest) int {
 		return 1
 	}
 	
-	// For ABR, use the default ladder size (3 quality levels)
-	// This could be made configurable in the future
-	return 3
+	// For ABR, one stream per rung of the configured ladder; it is logged
+	// when the ABR arguments generate it
+	return len(b.generateLadder(req, nil))
 }
 
 // applyResourceOptimizations applies all resource-related FFmpeg arguments
@@ -173,22 +214,56 @@ func (b *FFmpegArgsBuilder) applyResourceOptimizations(resources ResourceConfig,
 
 // getOptimalVideoCodec selects the best video codec based on request and available hardware
 func (b *FFmpegArgsBuilder) getOptimalVideoCodec(req types.TranscodeRequest) string {
-	if req.VideoCodec != "" {
+	if req.VideoCodec == "" {
+		// Default to H.264 for compatibility, on the assigned hardware when there is one
+		if encoder := requestHardwareEncoder(req); encoder != "" {
+			return encoder
+		}
+		return "libx264"
+	}
+
+	profile, ok := GetEncoderProfile(req.VideoCodec)
+	if !ok {
+		// Unknown codec, assume the caller passed an explicit encoder name
 		return req.VideoCodec
 	}
-	
-	// Default to H.264 for compatibility, hardware acceleration will auto-detect
-	return "libx264"
+
+	// Enforce container constraints, falling back to H.264 which every container carries
+	if req.Container != "" && !profile.SupportsContainer(req.Container) {
+		if b.logger != nil {
+			b.logger.Warn("codec not supported by container, falling back to h264",
+				"codec", profile.Codec,
+				"container", req.Container,
+			)
+		}
+		return "libx264"
+	}
+
+	if encoder := requestHardwareEncoder(req); encoder != "" {
+		return encoder
+	}
+	return profile.Encoder
 }
 
 // getOptimalPreset selects the best encoding preset for quality/speed balance
 func (b *FFmpegArgsBuilder) getOptimalPreset(speedPriority types.SpeedPriority, codec string) string {
+	// Named presets only exist for the x264/x265 family; VP9 and AV1 speed
+	// settings come from their encoder profiles
+	if codec != "libx264" && codec != "libx265" {
+		return ""
+	}
+
 	// Use the resource manager to get system-aware preset
 	return b.resourceManager.GetEncodingPreset(speedPriority, runtime.NumCPU())
 }
 
 // getOptimalQualitySettings returns quality parameters optimized for content
 func (b *FFmpegArgsBuilder) getOptimalQualitySettings(req types.TranscodeRequest, codec string) []string {
+	// Hardware encoders have their own rate control and presets
+	if HardwareTypeOf(codec) != types.HardwareTypeNone {
+		return HardwareEncoderArgs(codec, req.HardwareDevice, req.Quality, req.SpeedPriority)
+	}
+
 	var args []string
 	
 	// CRF calculation optimized for streaming
@@ -219,37 +294,67 @@ func (b *FFmpegArgsBuilder) getOptimalQualitySettings(req types.TranscodeRequest
 			resources.RCLookahead)
 		args = append(args, "-x264-params", x264Params)
 	} else if codec == "libx265" {
-		args = append(args, "-preset", "medium")
 		args = append(args, "-x265-params", "keyint=48:min-keyint=24:no-open-gop=1")
-	} else if codec == "libvpx-vp9" {
-		args = append(args, "-b:v", "0") // Use constant quality mode
-		args = append(args, "-deadline", "good")
-		args = append(args, "-cpu-used", "2")
-		args = append(args, "-row-mt", "1")
-		args = append(args, "-tile-columns", "2")
-		args = append(args, "-tile-rows", "1")
-		args = append(args, "-g", "48") // Keyframe interval
+	} else if profile, ok := GetEncoderProfile(codec); ok {
+		// VP9 and AV1 use their own CRF scale, speed controls and threading
+		args = args[:0]
+		args = append(args, profile.RateControlArgs(req.Quality)...)
+		args = append(args, profile.SpeedArgs(req.SpeedPriority, runtime.NumCPU())...)
+		args = append(args, profile.ThreadingArgs(b.getOutputHeight(req))...)
 	}
 	
 	return args
 }
 
-// getVideoFilters returns video filters for quality enhancement
+// getOutputHeight returns the requested output height, or 1080 when unknown
+func (b *FFmpegArgsBuilder) getOutputHeight(req types.TranscodeRequest) int {
+	if req.Resolution != nil && req.Resolution.Height > 0 {
+		return req.Resolution.Height
+	}
+	return 1080
+}
+
+// audioStreamSpecifier returns the input stream specifier for the selected audio track
+func audioStreamSpecifier(req types.TranscodeRequest) string {
+	return fmt.Sprintf("0:a:%d", req.AudioStreamIndex)
+}
+
+// pixelFormat returns the output pixel format. 10-bit is only honored for
+// codecs whose 10-bit profiles are widely decodable.
+func pixelFormat(req types.TranscodeRequest) string {
+	if req.TenBit {
+		switch NormalizeCodec(req.VideoCodec) {
+		case "hevc", "av1", "vp9":
+			return "yuv420p10le"
+		}
+	}
+	return "yuv420p"
+}
+
+// getVideoFilters returns video filters for quality enhancement. Deinterlacing
+// and subtitle burn-in run ahead of them, added by videoFilterArgs.
 func (b *FFmpegArgsBuilder) getVideoFilters(req types.TranscodeRequest) string {
 	var filters []string
-	
+
 	// Resolution scaling if specified
 	if req.Resolution != nil && req.Resolution.Width > 0 && req.Resolution.Height > 0 {
 		// Use lanczos for high quality downscaling
 		scaleFilter := fmt.Sprintf("scale=%d:%d:flags=lanczos", req.Resolution.Width, req.Resolution.Height)
 		filters = append(filters, scaleFilter)
 	}
+
+	// Custom filters from the matching template
+	if template := b.templateFor(req); template != nil {
+		filters = append(filters, template.VideoFilters...)
+	}
 	
-	// Deinterlacing if needed (detect interlaced content)
-	filters = append(filters, "yadif=mode=send_field:deint=interlaced")
-	
-	// Pixel format conversion for compatibility
-	filters = append(filters, "format=yuv420p")
+	// Pixel format conversion for compatibility, uploaded to the device
+	// for hardware encoders
+	if requestHardwareEncoder(req) != "" {
+		filters = append(filters, HardwareUploadFilter(req.HardwareType, pixelFormat(req) == "yuv420p10le"))
+	} else {
+		filters = append(filters, "format="+pixelFormat(req))
+	}
 	
 	if len(filters) > 0 {
 		return strings.Join(filters, ",")
@@ -266,7 +371,17 @@ func (b *FFmpegArgsBuilder) getOptimalAudioSettings(req types.TranscodeRequest)
 	if audioCodec == "" {
 		audioCodec = "aac"
 	}
+
+	// WebM can only carry Opus or Vorbis audio
+	if req.Container == "webm" && audioCodec != "libopus" && audioCodec != "libvorbis" {
+		audioCodec = "libopus"
+	}
 	args = append(args, "-c:a", audioCodec)
+
+	if audioCodec == "libopus" {
+		args = append(args, "-b:a", "128k")
+		args = append(args, "-ac", "2")
+	}
 	
 	// Conservative audio settings to prevent pops and artifacts
 	if audioCodec == "aac" {
@@ -282,6 +397,9 @@ func (b *FFmpegArgsBuilder) getOptimalAudioSettings(req types.TranscodeRequest)
 		// No audio filters - let FFmpeg handle conversion naturally
 		// Audio filters can introduce artifacts and pops
 	}
+
+	// Only template and loudness filters are applied, and only when configured
+	args = append(args, b.getAudioFilterArgs(req)...)
 	
 	return args
 }
@@ -307,16 +425,20 @@ func (b *FFmpegArgsBuilder) getKeyframeAlignmentArgs(req types.TranscodeRequest)
 	gopSize := int(segmentDuration * frameRate)
 	
 	// Force keyframes at exact segment boundaries for perfect alignment
-	keyframeExpr := fmt.Sprintf("expr:gte(t,n_forced*%.1f)", segmentDuration)
-	args = append(args, "-force_key_frames", keyframeExpr)
+	args = append(args, "-force_key_frames", keyframeExpr(req, segmentDuration))
 	
 	// Set GOP size to match segment duration exactly
 	args = append(args, VideoEncodingArgs.KeyInt...)
 	args = append(args, strconv.Itoa(gopSize))
 	
-	// Set minimum keyframe interval to match GOP size
+	// Set minimum keyframe interval to match GOP size, or the shorter
+	// fast-start segments while those are encoded
+	minGOPSize := gopSize
+	if fastStartEnabled(req) {
+		minGOPSize = int(fastStartSegmentDuration * frameRate)
+	}
 	args = append(args, VideoEncodingArgs.KeyIntMin...)
-	args = append(args, strconv.Itoa(gopSize))
+	args = append(args, strconv.Itoa(minGOPSize))
 	
 	// Ensure closed GOPs for better seeking and segment independence
 	args = append(args, "-flags", "+cgop")
@@ -349,11 +471,15 @@ func (b *FFmpegArgsBuilder) getContainerSpecificArgs(req types.TranscodeRequest,
 		
 		// Single bitrate DASH for VOD content
 		// CRITICAL: Force VOD mode for static manifests
+		segDuration := muxerSegmentDuration(req, 2) // 2s segments for faster startup
 		args = append(args,
 			"-f", "dash",
 			"-dash_segment_type", "mp4",
-			"-seg_duration", "2",                  // 2s segments for faster startup
-			"-use_timeline", "0",                 // Don't use timeline (causes issues)
+			"-seg_duration", fmt.Sprintf("%g", segDuration),
+		)
+		// Don't use timeline (causes issues) unless fast-start segments need it
+		args = append(args, dashTimelineArgs(req)...)
+		args = append(args,
 			"-use_template", "1",                 // Use template-based addressing
 			// Segment naming
 			"-init_seg_name", "init-$RepresentationID$.m4s",
@@ -368,7 +494,7 @@ func (b *FFmpegArgsBuilder) getContainerSpecificArgs(req types.TranscodeRequest,
 			"-ldash", "0",                       // Disable low latency DASH
 			// Fragmentation settings
 			"-frag_duration", "0.5",             // 500ms fragments in seconds
-			"-min_seg_duration", "2000000",      // Minimum segment duration in microseconds
+			"-min_seg_duration", strconv.Itoa(int(segDuration*1e6)), // Minimum segment duration in microseconds
 			"-movflags", "+dash+cmaf+faststart+delay_moov", // DASH optimizations
 			// Timestamp handling
 			"-avoid_negative_ts", "make_zero",   // Fix timestamp issues
@@ -380,36 +506,26 @@ func (b *FFmpegArgsBuilder) getContainerSpecificArgs(req types.TranscodeRequest,
 		}
 		
 		// Single bitrate HLS
-		outputDir := filepath.Dir(outputPath)
 		segDuration := b.getAdaptiveSegmentDuration(req)
-		
-		// Use fMP4 segments for better seeking with byte-range support
+		if fastStartEnabled(req) {
+			segDuration = fmt.Sprintf("%g", fastStartSegmentDuration)
+		}
+		args = append(args, hlsMuxerArgs(outputPath, segDuration, false, "independent_segments")...)
+
+	case "webm":
 		args = append(args,
-			"-f", "hls",
-			"-hls_time", segDuration,               // Adaptive segment duration
-			"-hls_playlist_type", "vod",
-			"-hls_segment_type", "fmp4",            // Use fMP4 for byte-range support
-			"-hls_fmp4_init_filename", "init.mp4",  // Single init segment
-			"-hls_segment_filename", filepath.Join(outputDir, "segment_%03d.m4s"),
-			"-hls_flags", "independent_segments+program_date_time+single_file",
-			// Enable byte-range support for efficient seeking
-			"-hls_segment_options", "movflags=+cmaf+dash+delay_moov+global_sidx+write_colr+write_gama",
-			// Low-latency HLS optimizations
-			"-hls_list_size", "0",                  // Keep all segments in playlist
-			"-hls_start_number_source", "datetime", // Better segment numbering
-			// Partial segment support for LL-HLS
-			"-hls_partial_duration", "0.5",        // 500ms partial segments
+			"-f", "webm",
+			"-cluster_time_limit", "2000",         // Cluster per segment duration for seeking
+			"-cluster_size_limit", "5242880",      // Cap cluster size at 5MB
+			"-dash", "1",                          // Write cues suitable for byte-range streaming
 		)
-		
-		// Add LL-HLS specific settings if seek position indicates need for responsiveness
-		if req.Seek > 0 {
-			args = append(args,
-				"-hls_flags", "independent_segments+program_date_time+single_file+temp_file",
-				"-master_pl_name", "master.m3u8",
-				"-master_pl_publish_rate", "2",     // Update master playlist every 2 segments
-			)
-		}
-		
+
+	case "mkv":
+		args = append(args,
+			"-f", "matroska",
+			"-cluster_time_limit", "2000",
+		)
+
 	default: // MP4 with streaming optimizations
 		args = append(args,
 			"-f", "mp4",
@@ -445,49 +561,60 @@ func (b *FFmpegArgsBuilder) getAdaptiveSegmentDuration(req types.TranscodeReques
 func (b *FFmpegArgsBuilder) getDashABRArgs(req types.TranscodeRequest, outputPath string) []string {
 	var args []string
 	
-	// Get source dimensions (simplified - in real implementation would probe the file)
-	sourceWidth := 1920
-	sourceHeight := 1080
-	if req.Resolution != nil {
-		sourceWidth = req.Resolution.Width
-		sourceHeight = req.Resolution.Height
-	}
-	
 	// Generate bitrate ladder
-	abrGen := abr.NewGenerator(b.logger)
-	ladder := abrGen.GenerateLadder(sourceWidth, sourceHeight, req.Quality)
+	ladder := b.abrLadder(req)
 	
 	// Map streams for each quality level
 	var maps []string
 	var videoStreamIndices []string
 	var audioStreamIndices []string
 	
+	// Each rung scales the video, after any deinterlacing and subtitle burn-in
+	chains := make([]string, len(ladder))
+	for i, rung := range ladder {
+		chains[i] = withHardwareUpload(req, fmt.Sprintf("scale=%d:%d:flags=lanczos", rung.Width, rung.Height))
+	}
+	videoFilters, videoMaps := videoFilterArgs(req, chains, func(i int) string { return fmt.Sprintf("-vf:%d", i*2) })
+
 	// First add all the maps
-	for range ladder {
+	for i := range ladder {
 		// Create a named output for each quality
 		maps = append(maps,
-			"-map", "0:v:0",
-			"-map", "0:a:0",
+			"-map", videoMaps[i],
+			"-map", audioStreamSpecifier(req),
 		)
 	}
 	
 	// Add all maps to args first
 	args = append(args, maps...)
+	args = append(args, videoFilters...)
 	
+	videoCodec := b.getOptimalVideoCodec(req)
+	template := b.templateFor(req)
+
 	// Then add encoding settings for each stream
 	for i, rung := range ladder {
 		// Video encoding settings for this rung
 		streamIndex := i * 2
 		args = append(args,
-			fmt.Sprintf("-c:v:%d", streamIndex), "libx264",
+			fmt.Sprintf("-c:v:%d", streamIndex), videoCodec,
 			fmt.Sprintf("-b:v:%d", streamIndex), fmt.Sprintf("%dk", rung.VideoBitrate),
 			fmt.Sprintf("-maxrate:%d", streamIndex), fmt.Sprintf("%dk", int(float64(rung.VideoBitrate)*1.2)),
 			fmt.Sprintf("-bufsize:%d", streamIndex), fmt.Sprintf("%dk", rung.VideoBitrate),
-			fmt.Sprintf("-vf:%d", streamIndex), fmt.Sprintf("scale=%d:%d:flags=lanczos", rung.Width, rung.Height),
-			fmt.Sprintf("-profile:v:%d", streamIndex), rung.Profile,
-			fmt.Sprintf("-level:%d", streamIndex), rung.Level,
-			fmt.Sprintf("-crf:%d", streamIndex), strconv.Itoa(rung.CRF),
 		)
+		if videoCodec == "libx264" {
+			args = append(args,
+				fmt.Sprintf("-profile:v:%d", streamIndex), rung.Profile,
+				fmt.Sprintf("-level:%d", streamIndex), rung.Level,
+				fmt.Sprintf("-crf:%d", streamIndex), strconv.Itoa(rung.CRF),
+			)
+		} else {
+			args = append(args, b.getRungEncoderArgs(req, videoCodec, streamIndex, rung.Height)...)
+		}
+		// Template encoder options override the rung's
+		if template != nil {
+			args = append(args, templateRungArgs(template.VideoArgs, streamIndex)...)
+		}
 		
 		// Audio encoding settings for this rung
 		audioIndex := streamIndex + 1
@@ -498,12 +625,17 @@ func (b *FFmpegArgsBuilder) getDashABRArgs(req types.TranscodeRequest, outputPat
 			fmt.Sprintf("-ac:%d", audioIndex), "2",  // Force stereo for compatibility
 			fmt.Sprintf("-profile:a:%d", audioIndex), "aac_low",
 		)
+		args = append(args, b.getStreamAudioFilterArgs(req, audioIndex)...)
 		
 		// Collect stream indices for adaptation sets
 		videoStreamIndices = append(videoStreamIndices, strconv.Itoa(streamIndex))
 		audioStreamIndices = append(audioStreamIndices, strconv.Itoa(audioIndex))
 	}
 	
+	if template != nil {
+		args = append(args, templateOutputArgs(template.VideoArgs)...)
+	}
+
 	// Build adaptation sets - one for all video streams, one for all audio streams
 	adaptationSets := fmt.Sprintf("id=0,streams=%s id=1,streams=%s", 
 		strings.Join(videoStreamIndices, ","),
@@ -513,14 +645,22 @@ func (b *FFmpegArgsBuilder) getDashABRArgs(req types.TranscodeRequest, outputPat
 	resources := b.resourceManager.GetOptimalResources(true, len(ladder), req.SpeedPriority)
 	args = append(args, b.applyResourceOptimizations(resources, true)...)
 	
+	// Short opening segments need keyframes to cut them at
+	if fastStartEnabled(req) {
+		args = append(args, "-force_key_frames", keyframeExpr(req, 2))
+	}
+
 	// DASH muxer settings with VOD optimization
-	segDuration := "2" // 2 second segments for faster adaptation
+	segDuration := muxerSegmentDuration(req, 2) // 2 second segments for faster adaptation
 	args = append(args,
 		"-f", "dash",
 		"-dash_segment_type", "mp4",           // Use MP4 segments
-		"-seg_duration", segDuration,
+		"-seg_duration", fmt.Sprintf("%g", segDuration),
 		"-use_template", "1",                   // Use template naming
-		"-use_timeline", "0",                   // Don't use timeline for better compatibility
+	)
+	// Don't use timeline for better compatibility, unless fast start needs it
+	args = append(args, dashTimelineArgs(req)...)
+	args = append(args,
 		"-single_file", "0",                    // Separate segment files
 		"-adaptation_sets", adaptationSets,
 		"-media_seg_name", "chunk-$RepresentationID$-$Number%05d$.m4s",
@@ -533,8 +673,11 @@ func (b *FFmpegArgsBuilder) getDashABRArgs(req types.TranscodeRequest, outputPat
 		"-ldash", "0",                        // Disable low latency DASH
 		// Fragmentation settings
 		"-frag_duration", "0.5",              // 500ms fragments in seconds
-		"-min_seg_duration", "2000000",       // Minimum segment duration in microseconds
+		"-min_seg_duration", strconv.Itoa(int(segDuration*1e6)), // Minimum segment duration in microseconds
 		"-movflags", "+dash+cmaf+faststart+delay_moov", // DASH optimizations
+		// HLS playlists over the same fMP4 segments, so HLS-only players
+		// such as Safari can open the ladder as HLSMasterPlaylist
+		"-hls_playlist", "1",
 	)
 	
 	return args
@@ -544,24 +687,24 @@ func (b *FFmpegArgsBuilder) getDashABRArgs(req types.TranscodeRequest, outputPat
 func (b *FFmpegArgsBuilder) getHLSABRArgs(req types.TranscodeRequest, outputPath string) []string {
 	var args []string
 	
-	// Get source dimensions
-	sourceWidth := 1920
-	sourceHeight := 1080
-	if req.Resolution != nil {
-		sourceWidth = req.Resolution.Width
-		sourceHeight = req.Resolution.Height
-	}
-	
 	// Generate bitrate ladder
-	abrGen := abr.NewGenerator(b.logger)
-	ladder := abrGen.GenerateLadder(sourceWidth, sourceHeight, req.Quality)
-	outputDir := filepath.Dir(outputPath)
+	ladder := b.abrLadder(req)
+	videoCodec := b.getOptimalVideoCodec(req)
+	template := b.templateFor(req)
 	
+	// Each rung scales the video, after any deinterlacing and subtitle burn-in
+	chains := make([]string, len(ladder))
+	for i, rung := range ladder {
+		chains[i] = withHardwareUpload(req, fmt.Sprintf("scale=%d:%d:flags=lanczos", rung.Width, rung.Height))
+	}
+	videoFilters, videoMaps := videoFilterArgs(req, chains, func(i int) string { return fmt.Sprintf("-vf:%d", i) })
+	args = append(args, videoFilters...)
+
 	// Add optimized encoding settings for ABR before mapping
 	for i := range ladder {
 		// Force keyframe interval for all variants
 		args = append(args,
-			fmt.Sprintf("-force_key_frames:v:%d", i), "expr:gte(t,n_forced*2)",
+			fmt.Sprintf("-force_key_frames:v:%d", i), keyframeExpr(req, 2),
 		)
 	}
 		
@@ -571,20 +714,29 @@ func (b *FFmpegArgsBuilder) getHLSABRArgs(req types.TranscodeRequest, outputPath
 		for i, rung := range ladder {
 		// Map video and audio
 		args = append(args,
-			"-map", "0:v:0",
-			"-map", "0:a:0",
+			"-map", videoMaps[i],
+			"-map", audioStreamSpecifier(req),
 		)
 		
 		// Video encoding settings
 		args = append(args,
-			fmt.Sprintf("-c:v:%d", i), "libx264",
+			fmt.Sprintf("-c:v:%d", i), videoCodec,
 			fmt.Sprintf("-b:v:%d", i), fmt.Sprintf("%dk", rung.VideoBitrate),
 			fmt.Sprintf("-maxrate:%d", i), fmt.Sprintf("%dk", int(float64(rung.VideoBitrate)*1.5)),
 			fmt.Sprintf("-bufsize:%d", i), fmt.Sprintf("%dk", rung.VideoBitrate*2),
-			fmt.Sprintf("-vf:%d", i), fmt.Sprintf("scale=%d:%d:flags=lanczos", rung.Width, rung.Height),
-			fmt.Sprintf("-profile:v:%d", i), rung.Profile,
-			fmt.Sprintf("-level:%d", i), rung.Level,
 		)
+		if videoCodec == "libx264" {
+			args = append(args,
+				fmt.Sprintf("-profile:v:%d", i), rung.Profile,
+				fmt.Sprintf("-level:%d", i), rung.Level,
+			)
+		} else {
+			args = append(args, b.getRungEncoderArgs(req, videoCodec, i, rung.Height)...)
+		}
+		// Template encoder options override the rung's
+		if template != nil {
+			args = append(args, templateRungArgs(template.VideoArgs, i)...)
+		}
 		
 		// Audio encoding settings
 		args = append(args,
@@ -594,6 +746,7 @@ func (b *FFmpegArgsBuilder) getHLSABRArgs(req types.TranscodeRequest, outputPath
 			fmt.Sprintf("-ac:%d", i), "2",  // Force stereo for compatibility
 			fmt.Sprintf("-profile:a:%d", i), "aac_low",
 		)
+		args = append(args, b.getStreamAudioFilterArgs(req, i)...)
 		
 		// Optimized GOP size and B-frames for this variant
 		args = append(args,
@@ -608,23 +761,56 @@ func (b *FFmpegArgsBuilder) getHLSABRArgs(req types.TranscodeRequest, outputPath
 			)
 	}
 	
+	if template != nil {
+		args = append(args, templateOutputArgs(template.VideoArgs)...)
+	}
+
 	// Apply resource optimizations for HLS ABR
 	resources := b.resourceManager.GetOptimalResources(true, len(ladder), req.SpeedPriority)
 	args = append(args, b.applyResourceOptimizations(resources, true)...)
 	
-	// HLS muxer settings with optimizations
-	segDuration := "2" // Fixed 2 second segments for ABR
-	args = append(args,
-	"-f", "hls",
-	"-hls_time", segDuration,
-	"-hls_playlist_type", "vod",
-	"-hls_segment_type", "mpegts",
-	"-hls_flags", "independent_segments+split_by_time",
-	"-hls_list_size", "0", // Keep all segments
-	"-master_pl_name", "playlist.m3u8",
-	"-hls_segment_filename", filepath.Join(outputDir, "stream_%v/segment_%03d.ts"),
-	 "-var_stream_map", strings.Join(variantStreams, " "),
-		)
-	
+	// Splitting by time alone would keep every segment short, so fast start
+	// leaves the cuts to the keyframes
+	hlsFlags := "independent_segments+split_by_time"
+	if fastStartEnabled(req) {
+		hlsFlags = "independent_segments"
+	}
+
+	// HLS muxer settings, one media playlist per variant under the master
+	segDuration := fmt.Sprintf("%g", muxerSegmentDuration(req, 2)) // Fixed 2 second segments for ABR
+	args = append(args, hlsMuxerArgs(outputPath, segDuration, true, hlsFlags)...)
+	args = append(args, "-var_stream_map", strings.Join(variantStreams, " "))
+
+	return args
+}
+
+// getRungEncoderArgs returns per-stream encoder settings for non-H.264 ABR rungs
+func (b *FFmpegArgsBuilder) getRungEncoderArgs(req types.TranscodeRequest, encoder string, index int, height int) []string {
+	// Hardware rungs take their preset per stream; the bitrate ladder sets the rate
+	if HardwareTypeOf(encoder) != types.HardwareTypeNone {
+		var args []string
+		options := append(HardwareSpeedArgs(encoder, req.SpeedPriority), hardwareDeviceArgs(req.HardwareType, req.HardwareDevice)...)
+		for i := 0; i+1 < len(options); i += 2 {
+			args = append(args, fmt.Sprintf("%s:v:%d", options[i], index), options[i+1])
+		}
+		return args
+	}
+
+	profile, ok := GetEncoderProfile(encoder)
+	if !ok {
+		return nil
+	}
+
+	var args []string
+	if preset := b.getOptimalPreset(req.SpeedPriority, encoder); preset != "" {
+		args = append(args, fmt.Sprintf("-preset:v:%d", index), preset)
+	}
+
+	// Apply profile options to this output stream only
+	options := append(profile.SpeedArgs(req.SpeedPriority, runtime.NumCPU()), profile.ThreadingArgs(height)...)
+	for i := 0; i+1 < len(options); i += 2 {
+		args = append(args, fmt.Sprintf("%s:v:%d", options[i], index), options[i+1])
+	}
+
 	return args
 }
\ No newline at end of file
@@ -18,11 +18,17 @@ var GlobalArgs = struct {
 	HwAccel []string
 	// Threading options
 	Threads []string
+	// Machine-readable progress reporting on stdout
+	Progress []string
+	// Suppress the interactive stats line on stderr
+	NoStats []string
 }{
 	Overwrite:  []string{"-y"},
 	HideBanner: []string{"-hide_banner"},
 	HwAccel:    []string{"-hwaccel", "auto"},
 	Threads:    []string{"-threads", "0"},
+	Progress:   []string{"-progress", "pipe:1"},
+	NoStats:    []string{"-nostats"},
 }
 
 // InputArgs contains arguments related to input handling
@@ -0,0 +1,182 @@
+// Package ffmpeg provides audio-only transcoding arguments for music streaming.
+// Audio-only output drops video (including embedded cover art) and picks
+// containers and segmenting that keep track boundaries gapless.
+package ffmpeg
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/mantonx/viewra/sdk/transcoding/types"
+)
+
+// Default audio bitrates in kbps when the request doesn't specify one
+const (
+	DefaultOpusBitrate = 128
+	DefaultAACBitrate  = 192
+	DefaultMP3Bitrate  = 192
+)
+
+// audioSegmentDuration is the HLS/DASH segment length for audio-only output.
+// Audio segments are cheap, so longer segments mean fewer requests per track.
+const audioSegmentDuration = "10"
+
+// IsAudioContainer reports whether the container only carries audio
+func IsAudioContainer(container string) bool {
+	switch strings.ToLower(container) {
+	case "ogg", "opus", "m4a", "mp3", "flac":
+		return true
+	}
+	return false
+}
+
+// GetAudioEncoder maps an audio codec name to its FFmpeg encoder
+func GetAudioEncoder(codec string) string {
+	switch strings.ToLower(codec) {
+	case "opus", "libopus":
+		return "libopus"
+	case "mp3", "libmp3lame":
+		return "libmp3lame"
+	case "flac":
+		return "flac"
+	case "vorbis", "libvorbis":
+		return "libvorbis"
+	default:
+		return "aac"
+	}
+}
+
+// buildAudioOnlyArgs returns the output arguments for an audio-only transcode
+func (b *FFmpegArgsBuilder) buildAudioOnlyArgs(req types.TranscodeRequest, outputPath string) []string {
+	var args []string
+
+	// Selected audio stream only; -vn also drops embedded cover art
+	args = append(args, StreamMappingArgs.Map...)
+	args = append(args, audioStreamSpecifier(req))
+	args = append(args, "-vn", "-sn", "-dn")
+	args = append(args, "-map_metadata", "0")
+
+	encoder := b.getAudioOnlyEncoder(req)
+	args = append(args, AudioEncodingArgs.Codec...)
+	args = append(args, encoder)
+
+	if encoder != "flac" {
+		args = append(args, AudioEncodingArgs.Bitrate...)
+		args = append(args, fmt.Sprintf("%dk", b.getAudioOnlyBitrate(req, encoder)))
+	}
+	args = append(args, AudioEncodingArgs.Channels...)
+	args = append(args, "2")
+
+	switch encoder {
+	case "libopus":
+		// Opus always runs at 48kHz; audio application mode favours music
+		args = append(args, AudioEncodingArgs.SampleRate...)
+		args = append(args, "48000")
+		args = append(args, "-application", "audio")
+	case "aac":
+		args = append(args, AudioEncodingArgs.Profile...)
+		args = append(args, "aac_low")
+	}
+
+	args = append(args, b.getAudioFilterArgs(req)...)
+	args = append(args, b.getAudioContainerArgs(req, outputPath)...)
+	args = append(args, b.getGaplessArgs(req)...)
+
+	return args
+}
+
+// getGaplessArgs keeps gapless signalling accurate for album playback. The
+// encoder records its own priming and padding (MP4 edit lists, Ogg pre-skip,
+// the LAME header), so iTunSMPB tags copied from the source would describe the
+// wrong encode and are dropped, and MP4 outputs always keep their edit list.
+func (b *FFmpegArgsBuilder) getGaplessArgs(req types.TranscodeRequest) []string {
+	if !req.Gapless {
+		return nil
+	}
+
+	args := []string{"-metadata", "iTunSMPB="}
+	switch strings.ToLower(req.Container) {
+	case "ogg", "opus", "webm", "mp3", "flac":
+		return args
+	default: // MP4 family: m4a and fMP4 HLS/DASH segments
+		return append(args, "-use_editlist", "1")
+	}
+}
+
+// getAudioOnlyEncoder picks an encoder that the output container can carry
+func (b *FFmpegArgsBuilder) getAudioOnlyEncoder(req types.TranscodeRequest) string {
+	encoder := GetAudioEncoder(req.AudioCodec)
+
+	switch strings.ToLower(req.Container) {
+	case "ogg", "opus", "webm":
+		if encoder != "libopus" && encoder != "libvorbis" && encoder != "flac" {
+			encoder = "libopus"
+		}
+	case "mp3":
+		encoder = "libmp3lame"
+	case "flac":
+		encoder = "flac"
+	case "m4a":
+		encoder = "aac"
+	}
+
+	return encoder
+}
+
+// getAudioOnlyBitrate returns the requested bitrate or the codec default in kbps
+func (b *FFmpegArgsBuilder) getAudioOnlyBitrate(req types.TranscodeRequest, encoder string) int {
+	if req.AudioBitrate > 0 {
+		return req.AudioBitrate
+	}
+
+	switch encoder {
+	case "libopus", "libvorbis":
+		return DefaultOpusBitrate
+	case "libmp3lame":
+		return DefaultMP3Bitrate
+	default:
+		return DefaultAACBitrate
+	}
+}
+
+// getAudioContainerArgs returns muxer settings for audio-only output.
+// Segmented output uses fMP4 rather than MPEG-TS: TS pads every segment with
+// AAC priming samples, which is audible as a click between segments and tracks.
+func (b *FFmpegArgsBuilder) getAudioContainerArgs(req types.TranscodeRequest, outputPath string) []string {
+	switch strings.ToLower(req.Container) {
+	case "hls":
+		return hlsMuxerArgs(outputPath, audioSegmentDuration, false, "independent_segments")
+
+	case "dash":
+		return []string{
+			"-f", "dash",
+			"-dash_segment_type", "mp4",
+			"-seg_duration", audioSegmentDuration,
+			"-use_timeline", "1",
+			"-use_template", "1",
+			"-init_seg_name", "init-$RepresentationID$.m4s",
+			"-media_seg_name", "chunk-$RepresentationID$-$Number%05d$.m4s",
+			"-adaptation_sets", "id=0,streams=a",
+			"-window_size", "0",
+			"-remove_at_exit", "0",
+		}
+
+	case "ogg", "opus":
+		// Ogg Opus signals encoder delay via pre-skip, so tracks join cleanly
+		return []string{"-f", "ogg", "-page_duration", "1000000"}
+
+	case "webm":
+		return []string{"-f", "webm", "-dash", "1"}
+
+	case "mp3":
+		// The Xing/LAME header carries encoder delay and padding for gapless playback
+		return []string{"-f", "mp3", "-write_xing", "1", "-id3v2_version", "3"}
+
+	case "flac":
+		return []string{"-f", "flac"}
+
+	default: // m4a
+		// Edit lists in the moov carry AAC priming for gapless playback
+		return []string{"-f", "ipod", "-movflags", "+faststart"}
+	}
+}
@@ -0,0 +1,61 @@
+// Package ffmpeg provides deinterlacing and inverse telecine filters.
+// Interlaced broadcast and DVD sources show combing on progressive displays,
+// and film content with 3:2 pulldown needs field matching rather than
+// deinterlacing to recover the original 24fps frames.
+package ffmpeg
+
+import (
+	"strings"
+
+	"github.com/mantonx/viewra/sdk/transcoding/types"
+)
+
+// ParseDeinterlaceMode converts a mode name to a DeinterlaceMode.
+// Unknown names fall back to automatic handling.
+func ParseDeinterlaceMode(mode string) types.DeinterlaceMode {
+	switch strings.ToLower(strings.TrimSpace(mode)) {
+	case "off", "none", "progressive":
+		return types.DeinterlaceOff
+	case "yadif":
+		return types.DeinterlaceYadif
+	case "bwdif":
+		return types.DeinterlaceBwdif
+	case "ivtc", "telecine", "pulldown":
+		return types.DeinterlaceTelecine
+	default:
+		return types.DeinterlaceAuto
+	}
+}
+
+// DeinterlaceFilter returns the filter chain for a deinterlace mode, or an
+// empty string when no filtering is needed. The chain must run before scaling,
+// since resizing interlaced frames blends the two fields together.
+func DeinterlaceFilter(mode types.DeinterlaceMode) string {
+	switch mode {
+	case types.DeinterlaceOff:
+		return ""
+	case types.DeinterlaceYadif:
+		return "yadif=mode=send_frame:parity=auto:deint=all"
+	case types.DeinterlaceBwdif:
+		return "bwdif=mode=send_frame:parity=auto:deint=all"
+	case types.DeinterlaceTelecine:
+		// Match fields back into progressive frames, deinterlace any orphans,
+		// then drop the duplicate frame from each 5-frame pulldown cycle
+		return "fieldmatch=order=auto:combmatch=full,yadif=deint=interlaced,decimate"
+	default:
+		// Only touches frames flagged as interlaced, so progressive sources pass through
+		return "yadif=mode=send_field:deint=interlaced"
+	}
+}
+
+// withDeinterlace prepends the request's deinterlace filter to a filter chain
+func withDeinterlace(req types.TranscodeRequest, chain string) string {
+	deinterlace := DeinterlaceFilter(req.Deinterlace)
+	if deinterlace == "" {
+		return chain
+	}
+	if chain == "" {
+		return deinterlace
+	}
+	return deinterlace + "," + chain
+}
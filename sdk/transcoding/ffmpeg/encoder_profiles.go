@@ -0,0 +1,189 @@
+// Package ffmpeg provides codec-specific encoder profiles.
+// Each profile captures how a software encoder should be driven for streaming
+// output: quality range, speed presets, threading and which containers can
+// carry the resulting bitstream. Keeping this in one table avoids scattering
+// codec special cases across the argument builder.
+package ffmpeg
+
+import (
+	"runtime"
+	"strconv"
+	"strings"
+
+	"github.com/mantonx/viewra/sdk/transcoding/types"
+)
+
+// EncoderProfile describes how to drive a specific FFmpeg encoder
+type EncoderProfile struct {
+	// Codec is the normalized codec name (h264, hevc, vp9, av1)
+	Codec string
+	// Encoder is the FFmpeg encoder name
+	Encoder string
+	// CRFBest and CRFWorst bound the CRF range used when mapping 0-100 quality
+	CRFBest  int
+	CRFWorst int
+	// Containers lists the container formats that can carry this codec
+	Containers []string
+	// RequiresFMP4 is set when HLS output must use fMP4 instead of MPEG-TS segments
+	RequiresFMP4 bool
+}
+
+// encoderProfiles contains the software encoder profiles keyed by normalized codec
+var encoderProfiles = map[string]EncoderProfile{
+	"h264": {
+		Codec:      "h264",
+		Encoder:    "libx264",
+		CRFBest:    18,
+		CRFWorst:   35,
+		Containers: []string{"mp4", "mkv", "dash", "hls"},
+	},
+	"hevc": {
+		Codec:        "hevc",
+		Encoder:      "libx265",
+		CRFBest:      20,
+		CRFWorst:     36,
+		Containers:   []string{"mp4", "mkv", "dash", "hls"},
+		RequiresFMP4: true,
+	},
+	"vp9": {
+		Codec:        "vp9",
+		Encoder:      "libvpx-vp9",
+		CRFBest:      24,
+		CRFWorst:     45,
+		Containers:   []string{"webm", "mkv", "mp4", "dash", "hls"},
+		RequiresFMP4: true,
+	},
+	"av1": {
+		Codec:        "av1",
+		Encoder:      "libsvtav1",
+		CRFBest:      22,
+		CRFWorst:     45,
+		Containers:   []string{"webm", "mkv", "mp4", "dash", "hls"},
+		RequiresFMP4: true,
+	},
+}
+
+// NormalizeCodec maps codec and encoder names to a canonical codec name
+func NormalizeCodec(codec string) string {
+	switch strings.ToLower(codec) {
+	case "h264", "avc", "avc1", "libx264":
+		return "h264"
+	case "h265", "hevc", "hvc1", "hev1", "libx265":
+		return "hevc"
+	case "vp9", "vp09", "libvpx-vp9":
+		return "vp9"
+	case "av1", "av01", "libsvtav1", "libaom-av1", "librav1e":
+		return "av1"
+	default:
+		return strings.ToLower(codec)
+	}
+}
+
+// GetEncoderProfile returns the software encoder profile for a codec or encoder name
+func GetEncoderProfile(codec string) (EncoderProfile, bool) {
+	profile, ok := encoderProfiles[NormalizeCodec(codec)]
+	return profile, ok
+}
+
+// SupportsContainer reports whether the codec can be muxed into the container
+func (p EncoderProfile) SupportsContainer(container string) bool {
+	for _, c := range p.Containers {
+		if c == container {
+			return true
+		}
+	}
+	return false
+}
+
+// CRF maps a 0-100 quality value onto the encoder's CRF range
+func (p EncoderProfile) CRF(quality int) int {
+	if quality < 0 {
+		quality = 0
+	}
+	if quality > 100 {
+		quality = 100
+	}
+	return p.CRFWorst - (quality * (p.CRFWorst - p.CRFBest) / 100)
+}
+
+// SpeedArgs returns encoder speed preset arguments for the speed priority
+func (p EncoderProfile) SpeedArgs(priority types.SpeedPriority, cpuCores int) []string {
+	switch p.Codec {
+	case "av1":
+		// SVT-AV1 presets run 0 (slowest) to 13 (fastest). Anything below 6
+		// is far too slow for on-demand streaming.
+		preset := 8
+		switch priority {
+		case types.SpeedPriorityFastest:
+			preset = 10
+		case types.SpeedPriorityQuality:
+			preset = 6
+		}
+		if cpuCores < 4 && preset < 10 {
+			preset = 10
+		}
+		return []string{"-preset", strconv.Itoa(preset)}
+
+	case "vp9":
+		// libvpx uses deadline + cpu-used instead of named presets
+		cpuUsed := 4
+		deadline := "good"
+		switch priority {
+		case types.SpeedPriorityFastest:
+			cpuUsed = 8
+			deadline = "realtime"
+		case types.SpeedPriorityQuality:
+			cpuUsed = 2
+		}
+		if cpuCores < 4 && deadline == "good" {
+			cpuUsed = 5
+		}
+		return []string{"-deadline", deadline, "-cpu-used", strconv.Itoa(cpuUsed)}
+	}
+
+	return nil
+}
+
+// ThreadingArgs returns encoder-specific threading arguments for the output height
+func (p EncoderProfile) ThreadingArgs(height int) []string {
+	switch p.Codec {
+	case "vp9":
+		// Row-based multithreading plus tile columns scaled to the frame width.
+		// libvpx requires tile columns to be given as log2.
+		tileColumns := 2
+		switch {
+		case height >= 2160:
+			tileColumns = 4
+		case height >= 1440:
+			tileColumns = 3
+		case height > 0 && height <= 480:
+			tileColumns = 1
+		}
+		return []string{
+			"-row-mt", "1",
+			"-tile-columns", strconv.Itoa(tileColumns),
+			"-frame-parallel", "0",
+		}
+
+	case "av1":
+		// Level of parallelism for SVT-AV1, scene change detection disabled
+		// to keep GOP boundaries aligned with segments.
+		lp := runtime.NumCPU() / 2
+		if lp < 1 {
+			lp = 1
+		}
+		return []string{"-svtav1-params", "tune=0:scd=0:lp=" + strconv.Itoa(lp)}
+	}
+
+	return nil
+}
+
+// RateControlArgs returns the constant-quality rate control arguments
+func (p EncoderProfile) RateControlArgs(quality int) []string {
+	args := []string{"-crf", strconv.Itoa(p.CRF(quality))}
+	if p.Codec == "vp9" {
+		// libvpx only honours CRF in constrained quality mode when -b:v is 0
+		args = append(args, "-b:v", "0")
+	}
+	return args
+}
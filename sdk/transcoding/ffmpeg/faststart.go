@@ -0,0 +1,54 @@
+package ffmpeg
+
+import (
+	"fmt"
+
+	"github.com/mantonx/viewra/sdk/transcoding/types"
+)
+
+// Fast start encodes the opening of DASH and HLS output as short segments,
+// so players have enough buffered to start after a fraction of the usual
+// wait. Past the window segments return to their normal length.
+const (
+	fastStartWindow          = 30.0 // Seconds of output encoded as short segments
+	fastStartSegmentDuration = 1.0  // Segment length within the window, in seconds
+)
+
+// fastStartEnabled reports whether the output opens with short segments
+func fastStartEnabled(req types.TranscodeRequest) bool {
+	return req.FastStart && (req.Container == "dash" || req.Container == "hls")
+}
+
+// keyframeExpr forces a keyframe every segmentDuration seconds, or with fast
+// start every fastStartSegmentDuration seconds within the window first.
+// The muxers only cut segments at keyframes, so the keyframe spacing is what
+// makes the opening segments short.
+func keyframeExpr(req types.TranscodeRequest, segmentDuration float64) string {
+	if !fastStartEnabled(req) || segmentDuration <= fastStartSegmentDuration {
+		return fmt.Sprintf("expr:gte(t,n_forced*%.1f)", segmentDuration)
+	}
+
+	windowKeyframes := fastStartWindow / fastStartSegmentDuration
+	return fmt.Sprintf("expr:gte(t,if(lt(n_forced,%g),n_forced*%g,%g+(n_forced-%g)*%g))",
+		windowKeyframes, fastStartSegmentDuration, fastStartWindow, windowKeyframes, segmentDuration)
+}
+
+// muxerSegmentDuration is the segment duration in seconds handed to the DASH
+// or HLS muxer. With fast start it is the short length: the muxer cuts at
+// the first keyframe past it, and keyframes space out after the window.
+func muxerSegmentDuration(req types.TranscodeRequest, normal float64) float64 {
+	if fastStartEnabled(req) {
+		return fastStartSegmentDuration
+	}
+	return normal
+}
+
+// dashTimelineArgs returns the DASH manifest addressing. Fast-start segments
+// vary in length, which only a segment timeline can describe; otherwise
+// fixed-duration template addressing is kept.
+func dashTimelineArgs(req types.TranscodeRequest) []string {
+	if fastStartEnabled(req) {
+		return []string{"-use_timeline", "1"}
+	}
+	return []string{"-use_timeline", "0"}
+}
@@ -57,7 +57,7 @@ func GetVideoEncoder(codec string, hwAccelType string) string {
 		case "vp9":
 			return "libvpx-vp9"
 		case "av1":
-			return "libaom-av1"
+			return "libsvtav1"
 		default:
 			return "libx264"
 		}
@@ -0,0 +1,210 @@
+// Package ffmpeg provides hardware encoder arguments.
+// Hardware encodes keep decoding and filtering (deinterlacing, lanczos
+// scaling, templates) in software so the filter chain is the same for every
+// backend; frames are only uploaded to the device at the end of the chain.
+// That costs some bandwidth over a full GPU pipeline but lets any source
+// fall back to software without rebuilding the filter graph.
+package ffmpeg
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/mantonx/viewra/sdk/transcoding/types"
+)
+
+// hardwareEncoders maps each hardware type's normalized codecs to its FFmpeg encoder
+var hardwareEncoders = map[types.HardwareType]map[string]string{
+	types.HardwareTypeNVIDIA: {
+		"h264": "h264_nvenc",
+		"hevc": "hevc_nvenc",
+		"av1":  "av1_nvenc",
+	},
+	types.HardwareTypeVAAPI: {
+		"h264": "h264_vaapi",
+		"hevc": "hevc_vaapi",
+		"vp9":  "vp9_vaapi",
+		"av1":  "av1_vaapi",
+	},
+	types.HardwareTypeQSV: {
+		"h264": "h264_qsv",
+		"hevc": "hevc_qsv",
+		"vp9":  "vp9_qsv",
+		"av1":  "av1_qsv",
+	},
+	types.HardwareTypeVideoToolbox: {
+		"h264": "h264_videotoolbox",
+		"hevc": "hevc_videotoolbox",
+	},
+}
+
+// HardwareEncoder returns the FFmpeg encoder for a codec on a hardware type
+func HardwareEncoder(hw types.HardwareType, codec string) (string, bool) {
+	encoder, ok := hardwareEncoders[hw][NormalizeCodec(codec)]
+	return encoder, ok
+}
+
+// HardwareEncoders returns every encoder of a hardware type, sorted
+func HardwareEncoders(hw types.HardwareType) []string {
+	encoders := make([]string, 0, len(hardwareEncoders[hw]))
+	for _, encoder := range hardwareEncoders[hw] {
+		encoders = append(encoders, encoder)
+	}
+	sort.Strings(encoders)
+	return encoders
+}
+
+// HardwareTypeOf returns the hardware type an encoder runs on, or
+// HardwareTypeNone for software encoders
+func HardwareTypeOf(encoder string) types.HardwareType {
+	for hw, encoders := range hardwareEncoders {
+		for _, name := range encoders {
+			if name == encoder {
+				return hw
+			}
+		}
+	}
+	return types.HardwareTypeNone
+}
+
+// HardwareInputArgs returns the global options that open the device, which
+// must precede the input
+func HardwareInputArgs(hw types.HardwareType, device string) []string {
+	switch hw {
+	case types.HardwareTypeVAAPI:
+		return []string{"-init_hw_device", "vaapi=hw:" + device, "-filter_hw_device", "hw"}
+	case types.HardwareTypeQSV:
+		// QSV runs on top of a VAAPI device on Linux, which picks the GPU
+		if device == "" {
+			return []string{"-init_hw_device", "qsv=hw:hw_any", "-filter_hw_device", "hw"}
+		}
+		return []string{
+			"-init_hw_device", "vaapi=va:" + device,
+			"-init_hw_device", "qsv=hw@va",
+			"-filter_hw_device", "hw",
+		}
+	}
+	// NVENC picks its GPU with an encoder option and takes frames from system memory
+	return nil
+}
+
+// HardwareUploadFilter returns the filter that ends the chain for a hardware
+// encoder, converting to the device's pixel format and uploading when the
+// encoder only takes device frames
+func HardwareUploadFilter(hw types.HardwareType, tenBit bool) string {
+	format := "nv12"
+	if tenBit {
+		format = "p010le"
+	}
+	switch hw {
+	case types.HardwareTypeVAAPI:
+		return "format=" + format + ",hwupload"
+	case types.HardwareTypeQSV:
+		return "format=" + format + ",hwupload=extra_hw_frames=64"
+	}
+	return "format=" + format
+}
+
+// HardwareEncoderArgs returns the rate control, speed and device arguments
+// for a hardware encoder. Quality maps onto each encoder's constant quality
+// scale using the CRF range of the codec's software profile, which the
+// hardware scales roughly follow.
+func HardwareEncoderArgs(encoder, device string, quality int, speed types.SpeedPriority) []string {
+	hw := HardwareTypeOf(encoder)
+	codec := NormalizeCodec(strings.SplitN(encoder, "_", 2)[0])
+	q := 23
+	if profile, ok := GetEncoderProfile(codec); ok {
+		q = profile.CRF(quality)
+	}
+
+	var args []string
+	switch hw {
+	case types.HardwareTypeNVIDIA:
+		args = append(args, HardwareSpeedArgs(encoder, speed)...)
+		args = append(args, "-rc", "vbr", "-cq", strconv.Itoa(q), "-b:v", "0")
+		args = append(args, hardwareDeviceArgs(hw, device)...)
+	case types.HardwareTypeVAAPI:
+		if codec == "h264" || codec == "hevc" {
+			args = append(args, "-rc_mode", "CQP", "-qp", strconv.Itoa(q))
+		} else {
+			args = append(args, "-global_quality", strconv.Itoa(q))
+		}
+	case types.HardwareTypeQSV:
+		args = append(args, HardwareSpeedArgs(encoder, speed)...)
+		args = append(args, "-global_quality", strconv.Itoa(q), "-look_ahead", "0")
+	case types.HardwareTypeVideoToolbox:
+		// VideoToolbox quality runs 1-100 the same way as the request
+		args = append(args, "-q:v", strconv.Itoa(quality))
+	}
+	return args
+}
+
+// HardwareSpeedArgs returns the preset of a hardware encoder for the speed
+// priority. VAAPI encoders have no presets.
+func HardwareSpeedArgs(encoder string, speed types.SpeedPriority) []string {
+	switch HardwareTypeOf(encoder) {
+	case types.HardwareTypeNVIDIA:
+		preset := "p4"
+		switch speed {
+		case types.SpeedPriorityFastest:
+			preset = "p1"
+		case types.SpeedPriorityQuality:
+			preset = "p6"
+		}
+		return []string{"-preset", preset}
+	case types.HardwareTypeQSV:
+		preset := "medium"
+		switch speed {
+		case types.SpeedPriorityFastest:
+			preset = "veryfast"
+		case types.SpeedPriorityQuality:
+			preset = "slower"
+		}
+		return []string{"-preset", preset}
+	}
+	return nil
+}
+
+// hardwareDeviceArgs selects the device for encoders that pick it with an
+// encoder option rather than a hardware device context
+func hardwareDeviceArgs(hw types.HardwareType, device string) []string {
+	if hw != types.HardwareTypeNVIDIA || device == "" {
+		return nil
+	}
+	if _, err := strconv.Atoi(device); err != nil {
+		return nil
+	}
+	return []string{"-gpu", device}
+}
+
+// requestHardwareEncoder returns the hardware encoder a request runs on, or
+// "" when it encodes in software. Only requests the transcoder assigned a
+// device to run on hardware, so a request without one never fails to open it.
+func requestHardwareEncoder(req types.TranscodeRequest) string {
+	if req.HardwareDevice == "" || req.HardwareType == "" || req.HardwareType == types.HardwareTypeNone {
+		return ""
+	}
+	codec := req.VideoCodec
+	if codec == "" {
+		codec = "h264"
+	}
+	encoder, ok := HardwareEncoder(req.HardwareType, codec)
+	if !ok {
+		return ""
+	}
+	return encoder
+}
+
+// withHardwareUpload appends the hardware upload filter to a filter chain
+// when the request encodes on hardware
+func withHardwareUpload(req types.TranscodeRequest, chain string) string {
+	if requestHardwareEncoder(req) == "" {
+		return chain
+	}
+	upload := HardwareUploadFilter(req.HardwareType, pixelFormat(req) == "yuv420p10le")
+	if chain == "" {
+		return upload
+	}
+	return chain + "," + upload
+}
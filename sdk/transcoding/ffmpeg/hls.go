@@ -0,0 +1,61 @@
+package ffmpeg
+
+import (
+	"path/filepath"
+
+	"github.com/mantonx/viewra/sdk/transcoding/types"
+)
+
+// HLS output is a master playlist listing one media playlist per variant,
+// each with fMP4 segments. fMP4 carries every codec the transcoder produces
+// and plays natively in Safari and on Apple devices, and the segments are
+// the same CMAF fragments DASH serves.
+const (
+	// HLSMasterPlaylist is the playlist players open. It is the output path
+	// handed to BuildArgs for HLS; FFmpeg writes the media playlists beside it.
+	HLSMasterPlaylist = "master.m3u8"
+
+	hlsMediaPlaylist         = "playlist.m3u8"    // Single variant
+	hlsVariantPlaylist       = "playlist_%v.m3u8" // One per ABR variant, %v is its name, e.g. 720p
+	hlsInitSegment           = "init.mp4"
+	hlsVariantInitSegment    = "init_%v.mp4"
+	hlsSegmentPattern        = "segment_%03d.m4s"
+	hlsVariantSegmentPattern = "segment_%v_%03d.m4s"
+)
+
+// hlsMuxerArgs returns the HLS muxer settings shared by every HLS output.
+// outputPath is the master playlist; abr names the playlists and segments
+// of each variant, which -var_stream_map then defines.
+// Playlists are EVENT rather than VOD: they are served while the transcode
+// is still appending segments, and a VOD playlist tells players its segment
+// list never changes, so they need not reload it.
+func hlsMuxerArgs(outputPath string, segDuration string, abr bool, flags string) []string {
+	outputDir := filepath.Dir(outputPath)
+	initSegment, segments := hlsInitSegment, hlsSegmentPattern
+	if abr {
+		initSegment, segments = hlsVariantInitSegment, hlsVariantSegmentPattern
+	}
+
+	return []string{
+		"-f", "hls",
+		"-hls_time", segDuration,
+		"-hls_playlist_type", "event",
+		"-hls_segment_type", "fmp4",
+		"-hls_fmp4_init_filename", initSegment,
+		"-hls_segment_filename", filepath.Join(outputDir, segments),
+		"-hls_flags", flags + "+temp_file", // Segments appear only once complete
+		"-hls_list_size", "0", // Keep all segments
+		"-master_pl_name", filepath.Base(outputPath),
+	}
+}
+
+// hlsOutputPath returns the path FFmpeg writes for HLS output: the media
+// playlist, or one playlist per variant for ABR. FFmpeg writes the master
+// playlist named by -master_pl_name in the same directory.
+func hlsOutputPath(req types.TranscodeRequest, outputPath string) string {
+	outputDir := filepath.Dir(outputPath)
+	if req.EnableABR && !req.AudioOnly && !IsAudioContainer(req.Container) {
+		return filepath.Join(outputDir, hlsVariantPlaylist)
+	}
+	return filepath.Join(outputDir, hlsMediaPlaylist)
+}
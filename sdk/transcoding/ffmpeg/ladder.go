@@ -0,0 +1,44 @@
+package ffmpeg
+
+import (
+	"github.com/mantonx/viewra/sdk/transcoding/abr"
+	"github.com/mantonx/viewra/sdk/transcoding/types"
+)
+
+// SetLadder replaces the ABR ladder rungs; an empty ladder restores
+// abr.DefaultRungs. An invalid ladder is rejected as a whole.
+func (b *FFmpegArgsBuilder) SetLadder(rungs []abr.RungConfig) error {
+	if err := abr.ValidateRungs(rungs); err != nil {
+		return err
+	}
+
+	b.ladderMu.Lock()
+	defer b.ladderMu.Unlock()
+	b.ladder = append([]abr.RungConfig(nil), rungs...)
+	return nil
+}
+
+// abrLadder returns the variants an ABR request encodes: the configured
+// rungs the source's resolution reaches
+func (b *FFmpegArgsBuilder) abrLadder(req types.TranscodeRequest) []abr.BitrateLadderRung {
+	return b.generateLadder(req, b.logger)
+}
+
+// generateLadder generates the request's ladder, logging to logger if set
+func (b *FFmpegArgsBuilder) generateLadder(req types.TranscodeRequest, logger types.Logger) []abr.BitrateLadderRung {
+	// Get source dimensions (simplified - in real implementation would probe the file)
+	sourceWidth := 1920
+	sourceHeight := 1080
+	if req.Resolution != nil {
+		sourceWidth = req.Resolution.Width
+		sourceHeight = req.Resolution.Height
+	}
+
+	b.ladderMu.RLock()
+	rungs := b.ladder
+	b.ladderMu.RUnlock()
+
+	gen := abr.NewGenerator(logger)
+	gen.SetRungs(rungs)
+	return gen.GenerateLadder(sourceWidth, sourceHeight, req.Quality)
+}
@@ -0,0 +1,61 @@
+// Package ffmpeg provides EBU R128 loudness normalization filters.
+// Normalization uses FFmpeg's loudnorm filter, either in linear mode fed by
+// previously measured values (accurate, no pumping) or in single-pass
+// dynamic mode when no measurement is available.
+package ffmpeg
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/mantonx/viewra/sdk/transcoding/types"
+)
+
+// Default EBU R128 targets for streaming playback
+const (
+	DefaultLoudnessTarget   = -16.0 // LUFS, common for streaming services
+	DefaultLoudnessTruePeak = -1.5  // dBTP
+	DefaultLoudnessRange    = 11.0  // LU
+)
+
+// LoudnormFilter builds the loudnorm audio filter for the settings.
+// Returns an empty string when normalization is disabled.
+func LoudnormFilter(settings *types.LoudnessSettings) string {
+	if settings == nil {
+		return ""
+	}
+
+	target := settings.TargetIntegrated
+	if target == 0 {
+		target = DefaultLoudnessTarget
+	}
+	truePeak := settings.TargetTruePeak
+	if truePeak == 0 {
+		truePeak = DefaultLoudnessTruePeak
+	}
+	lra := settings.TargetRange
+	if lra == 0 {
+		lra = DefaultLoudnessRange
+	}
+
+	params := []string{
+		fmt.Sprintf("I=%.1f", target),
+		fmt.Sprintf("TP=%.1f", truePeak),
+		fmt.Sprintf("LRA=%.1f", lra),
+	}
+
+	// Second pass: apply the measured values as a single linear gain
+	if m := settings.Measured; m != nil {
+		params = append(params,
+			fmt.Sprintf("measured_I=%.2f", m.Integrated),
+			fmt.Sprintf("measured_TP=%.2f", m.TruePeak),
+			fmt.Sprintf("measured_LRA=%.2f", m.Range),
+			fmt.Sprintf("measured_thresh=%.2f", m.Threshold),
+			fmt.Sprintf("offset=%.2f", m.TargetOffset),
+			"linear=true",
+		)
+	}
+
+	// loudnorm upsamples to 192kHz internally, so resample back for the encoder
+	return "loudnorm=" + strings.Join(params, ":") + ",aresample=48000"
+}
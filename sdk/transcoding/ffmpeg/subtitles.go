@@ -0,0 +1,185 @@
+package ffmpeg
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/mantonx/viewra/sdk/transcoding/types"
+)
+
+// Subtitles are burned into the video when a client can't render the track,
+// or passed through as a stream for containers that carry them. Text tracks
+// and external files are rendered by libass through the subtitles filter;
+// bitmap tracks (PGS, VobSub, DVB) have no text to render and are overlaid.
+
+// bitmapSubtitleCodecs are the subtitle codecs stored as images
+var bitmapSubtitleCodecs = map[string]bool{
+	"hdmv_pgs_subtitle": true,
+	"pgssub":            true,
+	"dvd_subtitle":      true,
+	"dvdsub":            true,
+	"dvb_subtitle":      true,
+	"dvbsub":            true,
+	"xsub":              true,
+}
+
+// IsBitmapSubtitle reports whether a subtitle codec is stored as images
+func IsBitmapSubtitle(codec string) bool {
+	return bitmapSubtitleCodecs[strings.ToLower(codec)]
+}
+
+// overlaysSubtitle reports whether the request burns in an embedded bitmap
+// track, which needs a complex filtergraph to overlay it on the video
+func overlaysSubtitle(req types.TranscodeRequest) bool {
+	return req.SubtitleMode == types.SubtitleBurnIn && req.SubtitlePath == "" && IsBitmapSubtitle(req.SubtitleCodec)
+}
+
+// subtitlesFilter returns the filter rendering the request's text subtitle
+// into the video, or an empty string when nothing is rendered that way
+func subtitlesFilter(req types.TranscodeRequest) string {
+	if req.SubtitleMode != types.SubtitleBurnIn || overlaysSubtitle(req) {
+		return ""
+	}
+
+	source := "filename=" + escapeFilterValue(req.InputPath) + fmt.Sprintf(":si=%d", req.SubtitleTrackIndex)
+	if req.SubtitlePath != "" {
+		source = "filename=" + escapeFilterValue(req.SubtitlePath)
+	}
+	filter := "subtitles=" + source
+
+	// Input seeking restarts the video at zero while the filter reads the
+	// subtitles from the start of the file, so render at source time
+	if req.Seek > 0 {
+		offset := fmt.Sprintf("%.3f", req.Seek.Seconds())
+		filter = "setpts=PTS+" + offset + "/TB," + filter + ",setpts=PTS-STARTPTS"
+	}
+	return filter
+}
+
+// withSubtitles prepends the request's subtitle rendering to a filter chain.
+// It runs before scaling so every ABR rung shows the same subtitles.
+func withSubtitles(req types.TranscodeRequest, chain string) string {
+	return joinFilters(subtitlesFilter(req), chain)
+}
+
+// videoFilterArgs returns the arguments that filter the video of each
+// output and the stream to map for it. chains are each output's filters
+// after deinterlacing and subtitle rendering. Outputs are filtered with
+// -vf, or all in one -filter_complex when a bitmap subtitle is overlaid,
+// since FFmpeg refuses -vf on a stream fed by a complex filtergraph.
+// vfOption names the -vf option of output i.
+func videoFilterArgs(req types.TranscodeRequest, chains []string, vfOption func(i int) string) (filterArgs []string, maps []string) {
+	if !overlaysSubtitle(req) {
+		for i, chain := range chains {
+			maps = append(maps, "0:v:0")
+			if chain = withDeinterlace(req, withSubtitles(req, chain)); chain != "" {
+				filterArgs = append(filterArgs, vfOption(i), chain)
+			}
+		}
+		return filterArgs, maps
+	}
+
+	// Deinterlace before overlaying so the subtitle images stay sharp
+	graph := "[0:v:0]"
+	if deinterlace := DeinterlaceFilter(req.Deinterlace); deinterlace != "" {
+		graph += deinterlace + "[deinterlaced];[deinterlaced]"
+	}
+	graph += fmt.Sprintf("[0:s:%d]overlay", req.SubtitleTrackIndex)
+
+	if len(chains) == 1 {
+		if chains[0] != "" {
+			graph += "," + chains[0]
+		}
+		return []string{"-filter_complex", graph + "[v0]"}, []string{"[v0]"}
+	}
+
+	graph += fmt.Sprintf(",split=%d", len(chains))
+	for i := range chains {
+		graph += fmt.Sprintf("[subtitled%d]", i)
+	}
+	for i, chain := range chains {
+		if chain == "" {
+			chain = "null"
+		}
+		graph += fmt.Sprintf(";[subtitled%d]%s[v%d]", i, chain, i)
+		maps = append(maps, fmt.Sprintf("[v%d]", i))
+	}
+	return []string{"-filter_complex", graph}, maps
+}
+
+// subtitlePassthroughArgs returns the arguments that map the request's
+// subtitle into a single file output and pick a codec the container
+// carries. DASH and HLS get none: players load their subtitles as WebVTT
+// alongside the manifest. Bitmap tracks only pass through to Matroska.
+func (b *FFmpegArgsBuilder) subtitlePassthroughArgs(req types.TranscodeRequest) []string {
+	if req.SubtitleMode != types.SubtitlePassthrough {
+		return nil
+	}
+
+	stream := fmt.Sprintf("0:s:%d", req.SubtitleTrackIndex)
+	bitmap := IsBitmapSubtitle(req.SubtitleCodec)
+	if req.SubtitlePath != "" {
+		stream, bitmap = "1:s:0", false // Added as the second input
+	}
+
+	var codec string
+	switch strings.ToLower(req.Container) {
+	case "mkv":
+		codec = "copy"
+		if req.SubtitlePath != "" {
+			codec = "ass" // Keeps ASS/SSA styling, and converts SRT and WebVTT to a muxable format
+		}
+	case "webm":
+		codec = "webvtt"
+	case "dash", "hls":
+		codec = ""
+	default:
+		codec = "mov_text"
+	}
+	if codec == "" || (bitmap && codec != "copy") {
+		if b.logger != nil {
+			b.logger.Warn("subtitle can't be passed through to container, leaving it out",
+				"container", req.Container,
+				"codec", req.SubtitleCodec,
+			)
+		}
+		return nil
+	}
+
+	args := append([]string{}, StreamMappingArgs.Map...)
+	return append(args, stream, "-c:s", codec)
+}
+
+// subtitleInputArgs returns the arguments opening an external subtitle file
+// passed through as a second input, seeked like the main input
+func subtitleInputArgs(req types.TranscodeRequest) []string {
+	if req.SubtitleMode != types.SubtitlePassthrough || req.SubtitlePath == "" {
+		return nil
+	}
+
+	var args []string
+	if req.Seek > 0 {
+		args = append(args, InputArgs.SeekStart...)
+		args = append(args, fmt.Sprintf("%.3f", req.Seek.Seconds()))
+	}
+	args = append(args, InputArgs.Input...)
+	return append(args, req.SubtitlePath)
+}
+
+// escapeFilterValue escapes a filter option value for use in a filtergraph:
+// once for the option parser and again for the graph parser
+func escapeFilterValue(value string) string {
+	option := strings.NewReplacer(`\`, `\\`, `'`, `\'`, `:`, `\:`).Replace(value)
+	return strings.NewReplacer(`\`, `\\`, `'`, `\'`, `[`, `\[`, `]`, `\]`, `,`, `\,`, `;`, `\;`).Replace(option)
+}
+
+// joinFilters joins the non-empty filters into one chain
+func joinFilters(filters ...string) string {
+	var chain []string
+	for _, filter := range filters {
+		if filter != "" {
+			chain = append(chain, filter)
+		}
+	}
+	return strings.Join(chain, ",")
+}
@@ -0,0 +1,417 @@
+// Package ffmpeg provides user-configurable argument templates.
+// Templates let power users append encoder options and filters to the
+// generated command (e.g. x265 params or a custom deinterlacer) without
+// replacing the builder. Only options from an allowlist of decoder, encoder
+// and muxer settings are accepted, none of which name files.
+package ffmpeg
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/mantonx/viewra/sdk/transcoding/types"
+)
+
+// ArgTemplate is a named set of extra FFmpeg arguments applied to matching requests
+type ArgTemplate struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+
+	// Match limits the template to requests with these codecs/containers.
+	// Empty lists match everything.
+	Codecs     []string `json:"codecs,omitempty"`
+	Containers []string `json:"containers,omitempty"`
+
+	// InputArgs are placed before the input file (e.g. decoder options)
+	InputArgs []string `json:"input_args,omitempty"`
+	// VideoArgs are appended after the generated video encoder options and
+	// take precedence over them. In ABR ladders, options without a stream
+	// specifier or with just :v are applied to every rung's video stream.
+	VideoArgs []string `json:"video_args,omitempty"`
+	// VideoFilters are inserted into the video filter chain before pixel format conversion
+	VideoFilters []string `json:"video_filters,omitempty"`
+	// AudioFilters are inserted into the audio filter chain before loudness normalization
+	AudioFilters []string `json:"audio_filters,omitempty"`
+	// OutputArgs are placed immediately before the output path
+	OutputArgs []string `json:"output_args,omitempty"`
+}
+
+// templateOptions are the options templates may use, by name without stream
+// specifier, and whether each takes a value. Options that name files, add
+// inputs or outputs, or change the muxer (-i, -f, -map, -vstats_file,
+// -passlogfile, -filter_complex_script and the like) are left out, and so is
+// every bare argument that isn't an option's value: ffmpeg would take it as
+// another output file.
+var templateOptions = map[string]bool{
+	// Decoding
+	"-hwaccel":               true,
+	"-hwaccel_device":        true,
+	"-hwaccel_output_format": true,
+	"-extra_hw_frames":       true,
+	"-thread_queue_size":     true,
+	"-probesize":             true,
+	"-analyzeduration":       true,
+	"-fflags":                true,
+	"-err_detect":            true,
+
+	// Codecs and encoder settings
+	"-c":               true,
+	"-codec":           true,
+	"-preset":          true,
+	"-tune":            true,
+	"-profile":         true,
+	"-level":           true,
+	"-crf":             true,
+	"-cq":              true,
+	"-qp":              true,
+	"-q":               true,
+	"-qscale":          true,
+	"-qmin":            true,
+	"-qmax":            true,
+	"-b":               true,
+	"-maxrate":         true,
+	"-minrate":         true,
+	"-bufsize":         true,
+	"-rc":              true,
+	"-rc-lookahead":    true,
+	"-g":               true,
+	"-keyint_min":      true,
+	"-bf":              true,
+	"-refs":            true,
+	"-sc_threshold":    true,
+	"-aq-mode":         true,
+	"-aq-strength":     true,
+	"-x264-params":     true,
+	"-x264opts":        true,
+	"-x265-params":     true,
+	"-svtav1-params":   true,
+	"-cpu-used":        true,
+	"-deadline":        true,
+	"-row-mt":          true,
+	"-tile-columns":    true,
+	"-tile-rows":       true,
+	"-threads":         true,
+	"-pix_fmt":         true,
+	"-r":               true,
+	"-aspect":          true,
+	"-color_primaries": true,
+	"-color_trc":       true,
+	"-colorspace":      true,
+	"-color_range":     true,
+	"-tag":             true,
+	"-ar":              true,
+	"-ac":              true,
+	"-strict":          true,
+	"-flags":           true,
+
+	// Output and muxing
+	"-fps_mode":              true,
+	"-vsync":                 true,
+	"-max_muxing_queue_size": true,
+	"-movflags":              true,
+	"-metadata":              true,
+	"-disposition":           true,
+	"-an":                    false,
+	"-vn":                    false,
+	"-sn":                    false,
+	"-dn":                    false,
+	"-shortest":              false,
+}
+
+// videoStreamOptions are the allowed options set per output stream, which
+// ABR ladders apply to each rung's video stream
+var videoStreamOptions = map[string]bool{
+	"-c": true, "-codec": true, "-preset": true, "-tune": true, "-profile": true,
+	"-level": true, "-crf": true, "-cq": true, "-qp": true, "-q": true,
+	"-qscale": true, "-qmin": true, "-qmax": true, "-b": true, "-maxrate": true,
+	"-minrate": true, "-bufsize": true, "-rc": true, "-rc-lookahead": true,
+	"-g": true, "-keyint_min": true, "-bf": true, "-refs": true,
+	"-sc_threshold": true, "-aq-mode": true, "-aq-strength": true,
+	"-x264-params": true, "-x264opts": true, "-x265-params": true,
+	"-svtav1-params": true, "-cpu-used": true, "-deadline": true, "-row-mt": true,
+	"-tile-columns": true, "-tile-rows": true, "-pix_fmt": true, "-r": true,
+	"-aspect": true, "-color_primaries": true, "-color_trc": true,
+	"-colorspace": true, "-color_range": true, "-tag": true,
+}
+
+// encoderParamOptions take a key=value list handed to the encoder library
+var encoderParamOptions = map[string]bool{
+	"-x264-params":   true,
+	"-x264opts":      true,
+	"-x265-params":   true,
+	"-svtav1-params": true,
+}
+
+// forbiddenEncoderParams are encoder library settings that read or write
+// files, such as x265's csv log or x264's two-pass stats
+var forbiddenEncoderParams = map[string]bool{
+	"csv":                 true,
+	"csv-log-level":       true,
+	"stats":               true,
+	"qpfile":              true,
+	"zonefile":            true,
+	"scaling-list":        true,
+	"lambda-file":         true,
+	"analysis-save":       true,
+	"analysis-load":       true,
+	"analysis-reuse-file": true,
+	"dolby-vision-rpu":    true,
+	"dump-yuv":            true,
+	"cqmfile":             true,
+	"tcfile-in":           true,
+	"tcfile-out":          true,
+	"recon":               true,
+	"fgs-table":           true,
+	"input":               true,
+	"output":              true,
+}
+
+// forbiddenTemplateFilters read or write files or accept commands at runtime
+var forbiddenTemplateFilters = []string{"movie", "amovie", "sendcmd", "asendcmd", "zmq", "azmq"}
+
+// Validate checks that the template only adds encoding options
+func (t ArgTemplate) Validate() error {
+	if strings.TrimSpace(t.Name) == "" {
+		return fmt.Errorf("template name is required")
+	}
+
+	for _, group := range []struct {
+		name string
+		args []string
+	}{
+		{"input_args", t.InputArgs},
+		{"video_args", t.VideoArgs},
+		{"output_args", t.OutputArgs},
+	} {
+		if err := validateTemplateArgs(group.args); err != nil {
+			return fmt.Errorf("%s: %w", group.name, err)
+		}
+	}
+
+	for _, filter := range append(append([]string{}, t.VideoFilters...), t.AudioFilters...) {
+		if err := validateTemplateFilter(filter); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Matches reports whether the template applies to the request
+func (t ArgTemplate) Matches(req types.TranscodeRequest) bool {
+	if len(t.Codecs) > 0 {
+		codec := NormalizeCodec(req.VideoCodec)
+		if req.AudioOnly || IsAudioContainer(req.Container) {
+			codec = "audio"
+		}
+		matched := false
+		for _, c := range t.Codecs {
+			if NormalizeCodec(c) == codec {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	if len(t.Containers) > 0 && !containsFold(t.Containers, req.Container) {
+		return false
+	}
+	return true
+}
+
+// validateTemplateArgs checks a flag/value argument list: every argument is
+// an allowed option or the value of the option before it
+func validateTemplateArgs(args []string) error {
+	for i, arg := range args {
+		if arg == "" {
+			return fmt.Errorf("argument %d is empty", i)
+		}
+		if strings.ContainsAny(arg, "\n\r") {
+			return fmt.Errorf("argument %q contains a line break", arg)
+		}
+		if strings.Contains(arg, "://") || strings.HasPrefix(arg, "file:") || strings.HasPrefix(arg, "pipe:") {
+			return fmt.Errorf("argument %q references an external resource", arg)
+		}
+	}
+
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if !strings.HasPrefix(arg, "-") || len(arg) == 1 {
+			return fmt.Errorf("argument %q is not an option or an option's value", arg)
+		}
+
+		// Stream specifiers (-c:v, -b:a:0) are checked by their base option
+		option, _ := templateOptionName(arg)
+		takesValue, ok := templateOptions[option]
+		if !ok {
+			return fmt.Errorf("option %s is not allowed in templates", arg)
+		}
+		if !takesValue {
+			continue
+		}
+
+		i++
+		if i == len(args) {
+			return fmt.Errorf("option %s needs a value", arg)
+		}
+		if encoderParamOptions[option] {
+			if err := validateEncoderParams(arg, args[i]); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// templateOptionName splits an option into its name and stream specifier
+func templateOptionName(arg string) (string, string) {
+	if idx := strings.Index(arg[1:], ":"); idx >= 0 {
+		return arg[:idx+1], arg[idx+2:]
+	}
+	return arg, ""
+}
+
+// validateEncoderParams checks a key=value list given to an encoder library
+func validateEncoderParams(option, params string) error {
+	for _, param := range strings.Split(params, ":") {
+		key, _, _ := strings.Cut(strings.TrimSpace(param), "=")
+		key = strings.TrimPrefix(strings.ToLower(key), "no-")
+		if forbiddenEncoderParams[key] {
+			return fmt.Errorf("%s setting %s is not allowed in templates", option, key)
+		}
+	}
+	return nil
+}
+
+// rungScoped reports whether a template video option applies to every
+// video stream, so ABR ladders scope it to each rung
+func rungScoped(arg string) bool {
+	option, specifier := templateOptionName(arg)
+	return videoStreamOptions[option] && (specifier == "" || specifier == "v")
+}
+
+// templateRungArgs scopes a template's per-stream video options to one
+// rung's video stream of an ABR ladder
+func templateRungArgs(args []string, index int) []string {
+	var scoped []string
+	for i := 0; i < len(args); i++ {
+		option, _ := templateOptionName(args[i])
+		if !templateOptions[option] {
+			continue // Flag
+		}
+		if i+1 < len(args) && rungScoped(args[i]) {
+			scoped = append(scoped, fmt.Sprintf("%s:v:%d", option, index), args[i+1])
+		}
+		i++
+	}
+	return scoped
+}
+
+// templateOutputArgs returns the template video options templateRungArgs
+// doesn't scope to a rung, such as muxer options and flags, for ABR ladders
+func templateOutputArgs(args []string) []string {
+	var rest []string
+	for i := 0; i < len(args); i++ {
+		option, _ := templateOptionName(args[i])
+		if !templateOptions[option] {
+			rest = append(rest, args[i]) // Flag
+			continue
+		}
+		if i+1 < len(args) && !rungScoped(args[i]) {
+			rest = append(rest, args[i], args[i+1])
+		}
+		i++
+	}
+	return rest
+}
+
+// validateTemplateFilter checks a single filter chain entry
+func validateTemplateFilter(filter string) error {
+	if strings.TrimSpace(filter) == "" {
+		return fmt.Errorf("filter is empty")
+	}
+	if strings.ContainsAny(filter, ";[]\n\r") {
+		return fmt.Errorf("filter %q must be a simple filter, not a filter graph", filter)
+	}
+
+	for _, part := range strings.Split(filter, ",") {
+		name, _, _ := strings.Cut(strings.TrimSpace(part), "=")
+		for _, forbidden := range forbiddenTemplateFilters {
+			if name == forbidden {
+				return fmt.Errorf("filter %s is not allowed in templates", name)
+			}
+		}
+	}
+	return nil
+}
+
+// containsFold reports whether list contains value, ignoring case
+func containsFold(list []string, value string) bool {
+	for _, item := range list {
+		if strings.EqualFold(item, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// audioFilterChain combines template audio filters with loudness normalization
+func (b *FFmpegArgsBuilder) audioFilterChain(req types.TranscodeRequest) string {
+	var filters []string
+	if template := b.templateFor(req); template != nil {
+		filters = append(filters, template.AudioFilters...)
+	}
+	if loudnorm := LoudnormFilter(req.Loudness); loudnorm != "" {
+		filters = append(filters, loudnorm)
+	}
+	return strings.Join(filters, ",")
+}
+
+// getAudioFilterArgs returns the audio filter arguments, if any filters apply
+func (b *FFmpegArgsBuilder) getAudioFilterArgs(req types.TranscodeRequest) []string {
+	chain := b.audioFilterChain(req)
+	if chain == "" {
+		return nil
+	}
+	return append(append([]string{}, AudioEncodingArgs.AudioFilter...), chain)
+}
+
+// getStreamAudioFilterArgs returns audio filter arguments for a single ABR audio output
+func (b *FFmpegArgsBuilder) getStreamAudioFilterArgs(req types.TranscodeRequest, index int) []string {
+	chain := b.audioFilterChain(req)
+	if chain == "" {
+		return nil
+	}
+	return []string{fmt.Sprintf("-filter:a:%d", index), chain}
+}
+
+// SetTemplates replaces the argument templates. Invalid templates are rejected
+// as a whole so a bad config can't partially apply.
+func (b *FFmpegArgsBuilder) SetTemplates(templates []ArgTemplate) error {
+	for _, t := range templates {
+		if err := t.Validate(); err != nil {
+			return fmt.Errorf("template %q: %w", t.Name, err)
+		}
+	}
+
+	b.templatesMu.Lock()
+	defer b.templatesMu.Unlock()
+	b.templates = append([]ArgTemplate(nil), templates...)
+	return nil
+}
+
+// templateFor returns the first template matching the request, or nil
+func (b *FFmpegArgsBuilder) templateFor(req types.TranscodeRequest) *ArgTemplate {
+	b.templatesMu.RLock()
+	defer b.templatesMu.RUnlock()
+
+	for i := range b.templates {
+		if b.templates[i].Matches(req) {
+			t := b.templates[i]
+			return &t
+		}
+	}
+	return nil
+}
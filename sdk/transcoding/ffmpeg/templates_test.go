@@ -0,0 +1,114 @@
+package ffmpeg
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestValidateTemplateArgs(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		wantErr string
+	}{
+		{"empty", nil, ""},
+		{"encoder options", []string{"-preset", "slow", "-tune", "film", "-crf", "20"}, ""},
+		{"stream specifiers", []string{"-c:v", "libx265", "-b:a:0", "192k", "-profile:v", "main10"}, ""},
+		{"flags", []string{"-sn", "-dn"}, ""},
+		{"flag between options", []string{"-an", "-crf", "18"}, ""},
+		{"negative value", []string{"-crf", "-1"}, ""},
+		{"encoder params", []string{"-x265-params", "aq-mode=3:no-sao=1"}, ""},
+		{"decoder options", []string{"-hwaccel", "cuda", "-hwaccel_output_format", "cuda"}, ""},
+		{"movflags", []string{"-movflags", "+faststart"}, ""},
+
+		{"leading positional", []string{"/tmp/x.mkv"}, "not an option"},
+		{"positional after flag", []string{"-an", "/tmp/x.mkv"}, "not an option"},
+		{"positional after value", []string{"-crf", "20", "/tmp/x.mkv"}, "not an option"},
+		{"bare dash", []string{"-"}, "not an option"},
+		{"missing value", []string{"-preset"}, "needs a value"},
+		{"empty argument", []string{"-crf", ""}, "empty"},
+		{"line break", []string{"-metadata", "title=a\nb"}, "line break"},
+		{"url value", []string{"-metadata", "comment=http://example.com"}, "external resource"},
+		{"file protocol", []string{"-metadata", "file:/etc/passwd"}, "external resource"},
+		{"pipe protocol", []string{"-metadata", "pipe:1"}, "external resource"},
+
+		{"input", []string{"-i", "/etc/passwd"}, "not allowed"},
+		{"overwrite", []string{"-y"}, "not allowed"},
+		{"format", []string{"-f", "null"}, "not allowed"},
+		{"map", []string{"-map", "0"}, "not allowed"},
+		{"progress", []string{"-progress", "/tmp/p"}, "not allowed"},
+		{"report", []string{"-report"}, "not allowed"},
+		{"vstats file", []string{"-vstats_file", "/tmp/v"}, "not allowed"},
+		{"vstats", []string{"-vstats"}, "not allowed"},
+		{"passlogfile", []string{"-passlogfile", "/tmp/p"}, "not allowed"},
+		{"sdp file", []string{"-sdp_file", "/tmp/s"}, "not allowed"},
+		{"filter complex script", []string{"-filter_complex_script", "/tmp/f"}, "not allowed"},
+		{"filter complex", []string{"-filter_complex", "null"}, "not allowed"},
+		{"stats period", []string{"-stats_period", "1"}, "not allowed"},
+		{"stats enc", []string{"-stats_enc_post", "/tmp/s"}, "not allowed"},
+		{"video filter", []string{"-vf", "yadif"}, "not allowed"},
+		{"specifier on forbidden", []string{"-filter:v", "yadif"}, "not allowed"},
+		{"attach", []string{"-attach", "/tmp/a"}, "not allowed"},
+		{"hls segment name", []string{"-hls_segment_filename", "/tmp/%d.ts"}, "not allowed"},
+
+		{"x265 csv", []string{"-x265-params", "csv=/tmp/log.csv"}, "csv"},
+		{"x264 stats", []string{"-x264-params", "ref=4:stats=/tmp/s"}, "stats"},
+		{"svt stats", []string{"-svtav1-params", "stats=/tmp/s"}, "stats"},
+		{"x265 analysis", []string{"-x265-params:v", "analysis-save=/tmp/a"}, "analysis-save"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateTemplateArgs(tt.args)
+			switch {
+			case tt.wantErr == "" && err != nil:
+				t.Fatalf("validateTemplateArgs(%q) = %v, want nil", tt.args, err)
+			case tt.wantErr != "" && err == nil:
+				t.Fatalf("validateTemplateArgs(%q) = nil, want error containing %q", tt.args, tt.wantErr)
+			case tt.wantErr != "" && !strings.Contains(err.Error(), tt.wantErr):
+				t.Fatalf("validateTemplateArgs(%q) = %v, want error containing %q", tt.args, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateTemplate(t *testing.T) {
+	tests := []struct {
+		name     string
+		template ArgTemplate
+		wantErr  bool
+	}{
+		{"valid", ArgTemplate{Name: "x265", VideoArgs: []string{"-x265-params", "aq-mode=3"}, VideoFilters: []string{"bwdif=mode=1"}}, false},
+		{"no name", ArgTemplate{VideoArgs: []string{"-crf", "20"}}, true},
+		{"output positional", ArgTemplate{Name: "out", OutputArgs: []string{"-an", "/tmp/x.mkv"}}, true},
+		{"input positional", ArgTemplate{Name: "in", InputArgs: []string{"/tmp/x.mkv"}}, true},
+		{"filter graph", ArgTemplate{Name: "graph", VideoFilters: []string{"[0:v]null[out]"}}, true},
+		{"movie filter", ArgTemplate{Name: "movie", VideoFilters: []string{"movie=/etc/passwd"}}, true},
+		{"sendcmd filter", ArgTemplate{Name: "cmd", AudioFilters: []string{"asendcmd=f=/tmp/c"}}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.template.Validate(); (err != nil) != tt.wantErr {
+				t.Fatalf("Validate() = %v, want error %t", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestTemplateABRArgs(t *testing.T) {
+	args := []string{"-preset", "slow", "-an", "-crf:v", "18", "-b:a", "128k", "-movflags", "+faststart", "-tag:v:0", "hvc1"}
+
+	rung := templateRungArgs(args, 2)
+	wantRung := []string{"-preset:v:2", "slow", "-crf:v:2", "18"}
+	if !slices.Equal(rung, wantRung) {
+		t.Errorf("templateRungArgs() = %q, want %q", rung, wantRung)
+	}
+
+	output := templateOutputArgs(args)
+	wantOutput := []string{"-an", "-b:a", "128k", "-movflags", "+faststart", "-tag:v:0", "hvc1"}
+	if !slices.Equal(output, wantOutput) {
+		t.Errorf("templateOutputArgs() = %q, want %q", output, wantOutput)
+	}
+}
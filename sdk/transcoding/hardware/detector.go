@@ -23,17 +23,24 @@ import (
 	"context"
 	"os/exec"
 	"strings"
+	"sync"
 	"time"
 
-	plugins "github.com/mantonx/viewra/sdk"
 	"github.com/mantonx/viewra/sdk/transcoding/types"
 )
 
+// detectInterval is how long detection results are reused
+const detectInterval = 5 * time.Minute
+
 // hardwareDetector detects available hardware acceleration
 type hardwareDetector struct {
-	logger     plugins.Logger
+	logger     types.Logger
+	mu         sync.Mutex
 	hwInfo     *types.HardwareInfo
 	lastDetect time.Time
+
+	encodersOnce sync.Once
+	encoders     string // Output of ffmpeg -encoders
 }
 
 // HardwareDetector interface for hardware detection
@@ -44,66 +51,44 @@ type HardwareDetector interface {
 }
 
 // NewHardwareDetector creates a new hardware detector
-func NewHardwareDetector(logger plugins.Logger) HardwareDetector {
+func NewHardwareDetector(logger types.Logger) HardwareDetector {
 	return &hardwareDetector{
 		logger: logger,
 	}
 }
 
-// DetectHardware detects available hardware acceleration
+// DetectHardware detects available hardware acceleration. Each device's
+// encoders are test-encoded, so only encoders that actually run are listed.
 func (d *hardwareDetector) DetectHardware() (*types.HardwareInfo, error) {
-	// Cache hardware info for 5 minutes
-	if d.hwInfo != nil && time.Since(d.lastDetect) < 5*time.Minute {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	// Cache hardware info, test encodes take a while
+	if d.hwInfo != nil && time.Since(d.lastDetect) < detectInterval {
 		return d.hwInfo, nil
 	}
 
-	d.logger.Info("detecting hardware acceleration capabilities")
+	if d.logger != nil {
+		d.logger.Info("detecting hardware acceleration capabilities")
+	}
 
 	hwInfo := &types.HardwareInfo{
 		Available: false,
-		Type:      "none",
+		Type:      string(types.HardwareTypeNone),
 		Encoders:  make(map[string][]string),
+		Devices:   d.detectDevices(),
 	}
-
-	// Check for NVIDIA GPU
-	if d.hasNVIDIA() {
-		hwInfo.Available = true
-		hwInfo.Type = "nvidia"
-		hwInfo.Encoders["h264"] = []string{"h264_nvenc"}
-		hwInfo.Encoders["hevc"] = []string{"hevc_nvenc"}
-		hwInfo.Encoders["av1"] = []string{"av1_nvenc"}
-		d.logger.Info("NVIDIA hardware acceleration detected")
-	}
-
-	// Check for VAAPI (Intel/AMD on Linux)
-	if d.hasVAAPI() {
-		hwInfo.Available = true
-		if hwInfo.Type == "none" {
-			hwInfo.Type = "vaapi"
+	for _, device := range hwInfo.Devices {
+		if !hwInfo.Available {
+			hwInfo.Available = true
+			hwInfo.Type = string(device.Type)
 		}
-		hwInfo.Encoders["h264"] = append(hwInfo.Encoders["h264"], "h264_vaapi")
-		hwInfo.Encoders["hevc"] = append(hwInfo.Encoders["hevc"], "hevc_vaapi")
-		d.logger.Info("VAAPI hardware acceleration detected")
-	}
-
-	// Check for QSV (Intel)
-	if d.hasQSV() {
-		hwInfo.Available = true
-		if hwInfo.Type == "none" {
-			hwInfo.Type = "qsv"
+		for _, encoder := range device.Encoders {
+			codec := strings.SplitN(encoder, "_", 2)[0]
+			if !containsString(hwInfo.Encoders[codec], encoder) {
+				hwInfo.Encoders[codec] = append(hwInfo.Encoders[codec], encoder)
+			}
 		}
-		hwInfo.Encoders["h264"] = append(hwInfo.Encoders["h264"], "h264_qsv")
-		hwInfo.Encoders["hevc"] = append(hwInfo.Encoders["hevc"], "hevc_qsv")
-		d.logger.Info("Intel QSV hardware acceleration detected")
-	}
-
-	// Check for VideoToolbox (macOS)
-	if d.hasVideoToolbox() {
-		hwInfo.Available = true
-		hwInfo.Type = "videotoolbox"
-		hwInfo.Encoders["h264"] = []string{"h264_videotoolbox"}
-		hwInfo.Encoders["hevc"] = []string{"hevc_videotoolbox"}
-		d.logger.Info("VideoToolbox hardware acceleration detected")
 	}
 
 	d.hwInfo = hwInfo
@@ -119,70 +104,37 @@ func (d *hardwareDetector) GetBestEncoder(codec string) string {
 		return d.getSoftwareEncoder(codec)
 	}
 
-	// Check hardware encoders
+	// Detected encoders already passed a test encode
 	if encoders, ok := hwInfo.Encoders[codec]; ok && len(encoders) > 0 {
-		// Test which encoder is actually available
-		for _, encoder := range encoders {
-			if d.IsEncoderAvailable(encoder) {
-				return encoder
-			}
-		}
+		return encoders[0]
 	}
 
 	// Fallback to software encoder
 	return d.getSoftwareEncoder(codec)
 }
 
-// IsEncoderAvailable checks if an encoder is available
+// IsEncoderAvailable checks if FFmpeg was built with an encoder
 func (d *hardwareDetector) IsEncoderAvailable(encoder string) bool {
-	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
-	defer cancel()
-
-	cmd := exec.CommandContext(ctx, "ffmpeg", "-hide_banner", "-encoders")
-	output, err := cmd.Output()
-	if err != nil {
-		return false
-	}
-
-	return strings.Contains(string(output), encoder)
-}
-
-// Hardware detection methods
-func (d *hardwareDetector) hasNVIDIA() bool {
-	// Check if nvidia-smi is available
-	cmd := exec.Command("nvidia-smi", "--query-gpu=name", "--format=csv,noheader")
-	if err := cmd.Run(); err == nil {
-		return true
-	}
-	return false
-}
-
-func (d *hardwareDetector) hasVAAPI() bool {
-	// Check if VAAPI device exists
-	cmd := exec.Command("ls", "/dev/dri/renderD128")
-	if err := cmd.Run(); err == nil {
-		return true
-	}
-	return false
-}
-
-func (d *hardwareDetector) hasQSV() bool {
-	// Check if Intel GPU is present
-	cmd := exec.Command("lspci")
-	output, err := cmd.Output()
-	if err == nil && strings.Contains(strings.ToLower(string(output)), "intel") {
-		// Further check if QSV is available in FFmpeg
-		return d.IsEncoderAvailable("h264_qsv")
-	}
-	return false
-}
-
-func (d *hardwareDetector) hasVideoToolbox() bool {
-	// Check if running on macOS
-	cmd := exec.Command("uname", "-s")
-	output, err := cmd.Output()
-	if err == nil && strings.TrimSpace(string(output)) == "Darwin" {
-		return d.IsEncoderAvailable("h264_videotoolbox")
+	d.encodersOnce.Do(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		output, err := exec.CommandContext(ctx, "ffmpeg", "-hide_banner", "-encoders").Output()
+		if err != nil {
+			if d.logger != nil {
+				d.logger.Warn("failed to list ffmpeg encoders", "error", err)
+			}
+			return
+		}
+		d.encoders = string(output)
+	})
+
+	for _, line := range strings.Split(d.encoders, "\n") {
+		// Lines read " V....D h264_nvenc  NVIDIA NVENC H.264 encoder"
+		fields := strings.Fields(line)
+		if len(fields) >= 2 && fields[1] == encoder {
+			return true
+		}
 	}
 	return false
 }
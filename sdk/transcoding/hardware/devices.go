@@ -0,0 +1,159 @@
+package hardware
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/mantonx/viewra/sdk/transcoding/ffmpeg"
+	"github.com/mantonx/viewra/sdk/transcoding/types"
+)
+
+// DefaultMaxSessions is how many encodes a device of each type runs at once.
+// Consumer NVIDIA drivers cap NVENC sessions; VAAPI and QSV have no hard
+// limit but slow down past a few parallel encodes.
+var DefaultMaxSessions = map[types.HardwareType]int{
+	types.HardwareTypeNVIDIA:       3,
+	types.HardwareTypeVAAPI:        4,
+	types.HardwareTypeQSV:          4,
+	types.HardwareTypeVideoToolbox: 2,
+}
+
+// probeTimeout bounds each test encode; opening a wedged device can hang
+const probeTimeout = 10 * time.Second
+
+// intelVendorID is the PCI vendor of Intel GPUs, the only ones QSV runs on
+const intelVendorID = "0x8086"
+
+// detectDevices lists the hardware encoder devices on the system, keeping
+// only those with at least one encoder that passed a test encode
+func (d *hardwareDetector) detectDevices() []types.HardwareDevice {
+	var candidates []types.HardwareDevice
+	candidates = append(candidates, nvidiaDevices()...)
+	for _, node := range renderNodes() {
+		name := filepath.Base(node)
+		candidates = append(candidates, types.HardwareDevice{
+			Type: types.HardwareTypeVAAPI,
+			ID:   "vaapi:" + name,
+			Name: "VAAPI " + name,
+			Path: node,
+		})
+		if renderNodeVendor(node) == intelVendorID {
+			candidates = append(candidates, types.HardwareDevice{
+				Type: types.HardwareTypeQSV,
+				ID:   "qsv:" + name,
+				Name: "Intel Quick Sync " + name,
+				Path: node,
+			})
+		}
+	}
+	if runtime.GOOS == "darwin" {
+		candidates = append(candidates, types.HardwareDevice{
+			Type: types.HardwareTypeVideoToolbox,
+			ID:   "videotoolbox",
+			Name: "Apple VideoToolbox",
+			Path: "videotoolbox",
+		})
+	}
+
+	var devices []types.HardwareDevice
+	for _, device := range candidates {
+		for _, encoder := range ffmpeg.HardwareEncoders(device.Type) {
+			if !d.IsEncoderAvailable(encoder) {
+				continue
+			}
+			if err := probeEncoder(device, encoder); err != nil {
+				if d.logger != nil {
+					d.logger.Debug("hardware encoder failed test encode", "device", device.ID, "encoder", encoder, "error", err)
+				}
+				continue
+			}
+			device.Encoders = append(device.Encoders, encoder)
+		}
+		if len(device.Encoders) == 0 {
+			continue
+		}
+		device.MaxSessions = DefaultMaxSessions[device.Type]
+		devices = append(devices, device)
+		if d.logger != nil {
+			d.logger.Info("hardware encoder device detected", "device", device.ID, "name", device.Name, "encoders", device.Encoders)
+		}
+	}
+	return devices
+}
+
+// nvidiaDevices lists the GPUs nvidia-smi reports, by index
+func nvidiaDevices() []types.HardwareDevice {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	output, err := exec.CommandContext(ctx, "nvidia-smi", "--query-gpu=index,name", "--format=csv,noheader").Output()
+	if err != nil {
+		return nil
+	}
+
+	var devices []types.HardwareDevice
+	for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		index, name, ok := strings.Cut(line, ",")
+		if !ok {
+			continue
+		}
+		index = strings.TrimSpace(index)
+		devices = append(devices, types.HardwareDevice{
+			Type: types.HardwareTypeNVIDIA,
+			ID:   "nvidia:" + index,
+			Name: strings.TrimSpace(name),
+			Path: index,
+		})
+	}
+	return devices
+}
+
+// renderNodes lists the DRM render nodes VAAPI and QSV open
+func renderNodes() []string {
+	nodes, err := filepath.Glob("/dev/dri/renderD*")
+	if err != nil {
+		return nil
+	}
+	return nodes
+}
+
+// renderNodeVendor returns the PCI vendor ID of a render node's GPU
+func renderNodeVendor(node string) string {
+	vendor, err := os.ReadFile(filepath.Join("/sys/class/drm", filepath.Base(node), "device", "vendor"))
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(vendor))
+}
+
+// probeEncoder encodes a few frames of a test pattern on the device, which
+// catches encoders FFmpeg was built with but the driver or GPU can't run
+func probeEncoder(device types.HardwareDevice, encoder string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), probeTimeout)
+	defer cancel()
+
+	args := []string{"-hide_banner", "-v", "error"}
+	args = append(args, ffmpeg.HardwareInputArgs(device.Type, device.Path)...)
+	args = append(args,
+		"-f", "lavfi", "-i", "testsrc2=size=320x240:rate=25:duration=0.2",
+		"-vf", ffmpeg.HardwareUploadFilter(device.Type, false),
+		"-c:v", encoder,
+	)
+	args = append(args, ffmpeg.HardwareEncoderArgs(encoder, device.Path, 50, types.SpeedPriorityFastest)...)
+	args = append(args, "-f", "null", "-")
+
+	output, err := exec.CommandContext(ctx, "ffmpeg", args...).CombinedOutput()
+	if err != nil {
+		if message := strings.TrimSpace(string(output)); message != "" {
+			return fmt.Errorf("%w: %s", err, message)
+		}
+		return err
+	}
+	return nil
+}
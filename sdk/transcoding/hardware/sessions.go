@@ -0,0 +1,135 @@
+package hardware
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/mantonx/viewra/sdk/transcoding/ffmpeg"
+	"github.com/mantonx/viewra/sdk/transcoding/types"
+)
+
+// SessionLimiter hands hardware devices out to encodes, keeping each device
+// under its session limit. Sessions are counted per physical device, so VAAPI
+// and QSV encodes on one Intel GPU share its limit.
+type SessionLimiter struct {
+	mu       sync.Mutex
+	devices  []types.HardwareDevice
+	active   map[string]int // Encodes running, by device path
+	firstFit bool           // Fill devices in order instead of balancing their load
+}
+
+// DeviceLease is a session slot on a hardware device, held for the length
+// of an encode
+type DeviceLease struct {
+	Device  types.HardwareDevice
+	Encoder string
+
+	limiter *SessionLimiter
+	once    sync.Once
+}
+
+// DeviceSessions is a device with the number of encodes running on it
+type DeviceSessions struct {
+	types.HardwareDevice
+	Active int `json:"active"`
+}
+
+// NewSessionLimiter creates a limiter over devices. A positive maxSessions
+// replaces every device's own limit. selection "first" fills the devices in
+// detection order; anything else hands each encode to the least busy device.
+func NewSessionLimiter(devices []types.HardwareDevice, maxSessions int, selection string) *SessionLimiter {
+	limited := make([]types.HardwareDevice, len(devices))
+	copy(limited, devices)
+	for i := range limited {
+		if maxSessions > 0 {
+			limited[i].MaxSessions = maxSessions
+		}
+		if limited[i].MaxSessions <= 0 {
+			limited[i].MaxSessions = 1
+		}
+	}
+	return &SessionLimiter{
+		devices:  limited,
+		active:   make(map[string]int),
+		firstFit: selection == "first",
+	}
+}
+
+// Acquire reserves a slot on a device that can encode codec.
+// hw limits the search to one hardware type; HardwareTypeNone takes any
+// device, preferring them in detection order. It returns false when every
+// capable device is at its limit.
+func (l *SessionLimiter) Acquire(hw types.HardwareType, codec string) (*DeviceLease, bool) {
+	if codec == "" {
+		codec = "h264"
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	var best *DeviceLease
+	bestLoad := 0.0
+	for _, device := range l.devices {
+		if hw != types.HardwareTypeNone && hw != "" && device.Type != hw {
+			continue
+		}
+		encoder, ok := ffmpeg.HardwareEncoder(device.Type, codec)
+		if !ok || !containsString(device.Encoders, encoder) {
+			continue
+		}
+		active := l.active[device.Path]
+		if active >= device.MaxSessions {
+			continue
+		}
+		load := float64(active) / float64(device.MaxSessions)
+		if best == nil || load < bestLoad {
+			best = &DeviceLease{Device: device, Encoder: encoder, limiter: l}
+			bestLoad = load
+		}
+		if l.firstFit {
+			break
+		}
+	}
+	if best == nil {
+		return nil, false
+	}
+	l.active[best.Device.Path]++
+	return best, true
+}
+
+// Release returns the slot to the device. Releasing twice is harmless.
+func (lease *DeviceLease) Release() {
+	if lease == nil {
+		return
+	}
+	lease.once.Do(func() {
+		l := lease.limiter
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		if l.active[lease.Device.Path] > 0 {
+			l.active[lease.Device.Path]--
+		}
+	})
+}
+
+// Devices returns every device with the encodes running on it
+func (l *SessionLimiter) Devices() []DeviceSessions {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	devices := make([]DeviceSessions, 0, len(l.devices))
+	for _, device := range l.devices {
+		devices = append(devices, DeviceSessions{HardwareDevice: device, Active: l.active[device.Path]})
+	}
+	sort.SliceStable(devices, func(i, j int) bool { return devices[i].ID < devices[j].ID })
+	return devices
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
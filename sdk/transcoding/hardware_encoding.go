@@ -0,0 +1,174 @@
+package transcoding
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/mantonx/viewra/sdk/transcoding/config"
+	"github.com/mantonx/viewra/sdk/transcoding/hardware"
+	"github.com/mantonx/viewra/sdk/transcoding/session"
+	"github.com/mantonx/viewra/sdk/transcoding/types"
+)
+
+// SetHardware configures hardware encoding. Must be called after SetLogger.
+// Devices are detected once in the background, as each is test-encoded;
+// transcodes started before detection finishes run in software.
+func (t *Transcoder) SetHardware(cfg config.HardwareConfig) {
+	t.hardwareMu.Lock()
+	t.hardwareConfig = cfg
+	detect := cfg.Enabled && !t.hardwareDetecting
+	if detect {
+		t.hardwareDetecting = true
+	}
+	t.hardwareMu.Unlock()
+
+	if detect {
+		go t.detectHardware()
+	}
+}
+
+// detectHardware finds the hardware encoder devices and sets up their session limits
+func (t *Transcoder) detectHardware() {
+	info, err := hardware.NewHardwareDetector(t.logger).DetectHardware()
+	if err != nil {
+		if t.logger != nil {
+			t.logger.Warn("hardware detection failed, encoding in software", "error", err)
+		}
+		return
+	}
+
+	t.hardwareMu.Lock()
+	defer t.hardwareMu.Unlock()
+	t.hardwareSessions = hardware.NewSessionLimiter(info.Devices, t.hardwareConfig.MaxSessions, t.hardwareConfig.DeviceSelection)
+	if t.logger != nil {
+		t.logger.Info("hardware encoding ready", "devices", len(info.Devices), "type", info.Type)
+	}
+}
+
+// HardwareDevices returns the detected hardware encoder devices with the
+// encodes running on each, or nil before detection finishes
+func (t *Transcoder) HardwareDevices() []hardware.DeviceSessions {
+	t.hardwareMu.RLock()
+	limiter := t.hardwareSessions
+	t.hardwareMu.RUnlock()
+
+	if limiter == nil {
+		return nil
+	}
+	return limiter.Devices()
+}
+
+// assignHardware reserves a hardware device for a request that asks for one,
+// or that the configuration's preferred type sends to hardware. Requests
+// that get no device encode in software when fallback is enabled.
+func (t *Transcoder) assignHardware(req types.TranscodeRequest) (types.TranscodeRequest, *hardware.DeviceLease, error) {
+	t.hardwareMu.RLock()
+	cfg := t.hardwareConfig
+	limiter := t.hardwareSessions
+	t.hardwareMu.RUnlock()
+
+	// Configured type names such as "cuda" or "auto" are normalized here
+	preferred := types.ParseHardwareType(string(cfg.PreferredType))
+	requested := req.HardwareType
+	if requested != "" && requested != types.HardwareTypeNone {
+		requested = types.ParseHardwareType(string(requested))
+	}
+	wantsHardware := req.WantsHardware() || preferred != types.HardwareTypeNone || cfg.PreferredType == "auto"
+
+	software := req
+	software.HardwareType = types.HardwareTypeNone
+	software.HardwareDevice = ""
+	if !cfg.Enabled || !wantsHardware || req.AudioOnly {
+		return software, nil, nil
+	}
+
+	hwType := requested
+	if hwType == "" || hwType == types.HardwareTypeNone {
+		hwType = preferred
+	}
+
+	var lease *hardware.DeviceLease
+	if limiter != nil {
+		lease, _ = limiter.Acquire(hwType, req.VideoCodec)
+	}
+	if lease == nil {
+		if !cfg.Fallback {
+			return req, nil, fmt.Errorf("no hardware encoder available for %s", hardwareLabel(hwType))
+		}
+		if t.logger != nil {
+			t.logger.Info("no hardware encoder available, encoding in software",
+				"session_id", req.SessionID,
+				"hardware_type", hardwareLabel(hwType),
+			)
+		}
+		return software, nil, nil
+	}
+
+	req.HardwareType = lease.Device.Type
+	req.HardwareDevice = lease.Device.Path
+	return req, lease, nil
+}
+
+// fallBackToSoftware restarts a session whose hardware encoder failed in
+// software. The encode restarts from the request's seek position with the
+// same segment layout, so segments already written are replaced by
+// equivalent ones. It returns false when the session can't fall back.
+func (t *Transcoder) fallBackToSoftware(sess *session.Session, exitCode int) bool {
+	t.hardwareMu.RLock()
+	fallback := t.hardwareConfig.Fallback
+	t.hardwareMu.RUnlock()
+
+	if !fallback || sess.Hardware == nil ||
+		sess.Status == session.SessionStatusStopping || sess.Status == session.SessionStatusStopped {
+		return false
+	}
+
+	device := sess.Hardware.Device
+	sess.Hardware.Release()
+	if t.logger != nil {
+		t.logger.Warn("hardware encoder failed, restarting in software",
+			"session_id", sess.ID,
+			"device", device.ID,
+			"exit_code", exitCode,
+		)
+	}
+
+	req := sess.Request
+	req.HardwareType = types.HardwareTypeNone
+	req.HardwareDevice = ""
+	outputDir, outputPath, err := t.prepareOutputPaths(req, sess.ID)
+	if err != nil {
+		if t.logger != nil {
+			t.logger.Error("failed to restart in software", "session_id", sess.ID, "error", err)
+		}
+		return false
+	}
+
+	// Keep the hardware encoder's log, the software run writes a new one
+	stderrPath := filepath.Join(outputDir, "ffmpeg-stderr.log")
+	if err := os.Rename(stderrPath, filepath.Join(outputDir, "ffmpeg-stderr-hardware.log")); err != nil && !os.IsNotExist(err) && t.logger != nil {
+		t.logger.Debug("failed to keep hardware encoder log", "session_id", sess.ID, "error", err)
+	}
+
+	t.sessionManager.UpdateSession(sess.ID, func(s *session.Session) {
+		s.Hardware = nil
+		s.FellBack = true
+		s.Progress = 0
+	})
+	if err := t.startProcess(sess.ID, req, outputDir, outputPath); err != nil {
+		if t.logger != nil {
+			t.logger.Error("failed to restart in software", "session_id", sess.ID, "error", err)
+		}
+		return false
+	}
+	return true
+}
+
+// hardwareLabel names a hardware type in logs and errors
+func hardwareLabel(hw types.HardwareType) string {
+	if hw == "" || hw == types.HardwareTypeNone {
+		return "any hardware"
+	}
+	return string(hw)
+}
@@ -27,7 +27,6 @@ package process
 import (
 	"fmt"
 	"os/exec"
-	"syscall"
 	"time"
 
 	"github.com/mantonx/viewra/sdk/transcoding/types"
@@ -138,54 +137,37 @@ func (m *Monitor) StopProcess(pid int, timeout time.Duration) error {
 	return nil
 }
 
-// terminateProcessGroup gracefully terminates a process group
+// terminateProcessGroup gracefully terminates a process and its children
 func (m *Monitor) terminateProcessGroup(pid int) error {
-	// Get process group ID
-	pgid, err := syscall.Getpgid(pid)
-	if err != nil {
-		return fmt.Errorf("failed to get process group: %w", err)
+	if err := Terminate(pid); err != nil {
+		return fmt.Errorf("failed to terminate process: %w", err)
 	}
-	
-	// Send SIGTERM to the entire process group
-	if err := syscall.Kill(-pgid, syscall.SIGTERM); err != nil {
-		return fmt.Errorf("failed to send SIGTERM to process group: %w", err)
-	}
-	
+
 	// Give it 5 seconds to terminate gracefully
-	time.Sleep(5 * time.Second)
-	
-	// Check if the main process is still running
-	if err := syscall.Kill(pid, 0); err == nil {
-		// Process is still running, need to force kill
-		return fmt.Errorf("process %d still running after SIGTERM", pid)
+	if !waitForExit(pid, 5*time.Second) {
+		return fmt.Errorf("process %d still running after terminate", pid)
 	}
-	
+
 	return nil
 }
 
-// forceKillProcess forcefully kills a process and its group
+// forceKillProcess forcefully kills a process and its children
 func (m *Monitor) forceKillProcess(pid int) error {
 	if m.logger != nil {
 		m.logger.Warn("force killing process", "pid", pid)
 	}
-	
-	// Try to kill the process group first
-	if pgid, err := syscall.Getpgid(pid); err == nil {
-		syscall.Kill(-pgid, syscall.SIGKILL)
-	}
-	
-	// Kill the main process
-	if err := syscall.Kill(pid, syscall.SIGKILL); err != nil {
+
+	if err := ForceKill(pid); err != nil {
 		return fmt.Errorf("failed to kill process: %w", err)
 	}
-	
+
 	return nil
 }
 
 // GetProcessInfo returns information about a process
 func (m *Monitor) GetProcessInfo(pid int) (*ProcessInfo, error) {
 	// Check if process exists
-	if err := syscall.Kill(pid, 0); err != nil {
+	if !IsRunning(pid) {
 		return nil, fmt.Errorf("process not found: %d", pid)
 	}
 
@@ -212,9 +194,7 @@ func (m *Monitor) GetProcessInfo(pid int) (*ProcessInfo, error) {
 
 // CheckHealth checks if a process is still running
 func (m *Monitor) CheckHealth(pid int) bool {
-	// Simple check if process exists
-	err := syscall.Kill(pid, 0)
-	return err == nil
+	return IsRunning(pid)
 }
 
 // GetResourceUsage returns resource usage for a process
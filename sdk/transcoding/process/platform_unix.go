@@ -0,0 +1,46 @@
+//go:build !windows
+
+package process
+
+import (
+	"errors"
+	"os/exec"
+	"syscall"
+)
+
+// ConfigureCommand starts cmd in its own process group so FFmpeg and any
+// children it spawns can be signalled together
+func ConfigureCommand(cmd *exec.Cmd) {
+	if cmd.SysProcAttr == nil {
+		cmd.SysProcAttr = &syscall.SysProcAttr{}
+	}
+	cmd.SysProcAttr.Setpgid = true
+}
+
+// IsRunning reports whether a process with pid exists
+func IsRunning(pid int) bool {
+	err := syscall.Kill(pid, 0)
+	// EPERM means the process exists but belongs to another user
+	return err == nil || errors.Is(err, syscall.EPERM)
+}
+
+// Terminate asks a process and its group to exit with SIGTERM
+func Terminate(pid int) error {
+	return signalGroup(pid, syscall.SIGTERM)
+}
+
+// ForceKill kills a process and its group with SIGKILL
+func ForceKill(pid int) error {
+	return signalGroup(pid, syscall.SIGKILL)
+}
+
+// signalGroup sends sig to the process group led by pid, if any, and to pid itself
+func signalGroup(pid int, sig syscall.Signal) error {
+	if pgid, err := syscall.Getpgid(pid); err == nil && pgid == pid {
+		syscall.Kill(-pgid, sig)
+	}
+	if err := syscall.Kill(pid, sig); err != nil && !errors.Is(err, syscall.ESRCH) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+//go:build windows
+
+package process
+
+import (
+	"os/exec"
+	"strconv"
+	"syscall"
+)
+
+// stillActive is the exit code Windows reports for a running process
+const stillActive = 259
+
+// ConfigureCommand starts cmd in its own process group and without a console
+// window, so FFmpeg and its children can be stopped as a tree
+func ConfigureCommand(cmd *exec.Cmd) {
+	if cmd.SysProcAttr == nil {
+		cmd.SysProcAttr = &syscall.SysProcAttr{}
+	}
+	cmd.SysProcAttr.CreationFlags |= syscall.CREATE_NEW_PROCESS_GROUP
+	cmd.SysProcAttr.HideWindow = true
+}
+
+// IsRunning reports whether a process with pid exists and hasn't exited
+func IsRunning(pid int) bool {
+	handle, err := syscall.OpenProcess(syscall.PROCESS_QUERY_INFORMATION, false, uint32(pid))
+	if err != nil {
+		return false
+	}
+	defer syscall.CloseHandle(handle)
+
+	var code uint32
+	if err := syscall.GetExitCodeProcess(handle, &code); err != nil {
+		return false
+	}
+	return code == stillActive
+}
+
+// Terminate asks a process tree to close. Console programs such as FFmpeg
+// may ignore the request, in which case callers escalate to ForceKill.
+func Terminate(pid int) error {
+	return taskkill(pid, false)
+}
+
+// ForceKill ends a process and all of its children
+func ForceKill(pid int) error {
+	return taskkill(pid, true)
+}
+
+func taskkill(pid int, force bool) error {
+	if !IsRunning(pid) {
+		return nil
+	}
+	args := []string{"/T", "/PID", strconv.Itoa(pid)}
+	if force {
+		args = append([]string{"/F"}, args...)
+	}
+	cmd := exec.Command("taskkill", args...)
+	cmd.SysProcAttr = &syscall.SysProcAttr{HideWindow: true}
+	return cmd.Run()
+}
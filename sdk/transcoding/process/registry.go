@@ -13,7 +13,7 @@
 // Key features:
 // - Global process tracking across all transcoding providers
 // - Thread-safe operations for concurrent access
-// - Graceful termination with signal escalation (SIGTERM -> SIGKILL)
+// - Graceful termination with escalation to a forced kill on every platform
 // - Process group management to handle child processes
 // - Session-to-process mapping for easy lookup
 //
@@ -25,7 +25,6 @@ package process
 import (
 	"fmt"
 	"sync"
-	"syscall"
 	"time"
 
 	"github.com/mantonx/viewra/sdk/transcoding/types"
@@ -106,58 +105,49 @@ func (pr *ProcessRegistry) Unregister(pid int) {
 	}
 }
 
-// KillProcess kills a process with proper signal escalation
+// KillProcess stops a process and its children, escalating from a graceful
+// terminate to a forced kill if it is still running after five seconds
 func (pr *ProcessRegistry) KillProcess(pid int) error {
-	// First check if process exists
-	if err := syscall.Kill(pid, 0); err != nil {
-		// Process doesn't exist
+	if !IsRunning(pid) {
 		return nil
 	}
 
-	// Try to get process group ID
-	pgid, err := syscall.Getpgid(pid)
-	if err != nil {
-		// Can't get pgid, just use pid
-		pgid = pid
+	// Step 1: Ask the process tree to exit
+	if err := Terminate(pid); err != nil && pr.logger != nil {
+		pr.logger.Debug("graceful terminate failed", "pid", pid, "error", err)
 	}
 
-	// Step 1: Send SIGTERM to process group
-	if pgid != pid {
-		syscall.Kill(-pgid, syscall.SIGTERM)
-	}
-	syscall.Kill(pid, syscall.SIGTERM)
-
 	// Step 2: Wait for graceful termination (5 seconds)
-	deadline := time.Now().Add(5 * time.Second)
-	for time.Now().Before(deadline) {
-		if err := syscall.Kill(pid, 0); err != nil {
-			// Process is gone
-			return nil
-		}
-		time.Sleep(100 * time.Millisecond)
+	if waitForExit(pid, 5*time.Second) {
+		return nil
 	}
 
-	// Step 3: Force kill with SIGKILL
+	// Step 3: Force kill
 	if pr.logger != nil {
-		pr.logger.Warn("process did not terminate gracefully, sending SIGKILL", "pid", pid)
+		pr.logger.Warn("process did not terminate gracefully, force killing", "pid", pid)
 	}
-
-	if pgid != pid {
-		syscall.Kill(-pgid, syscall.SIGKILL)
+	if err := ForceKill(pid); err != nil && pr.logger != nil {
+		pr.logger.Warn("force kill failed", "pid", pid, "error", err)
 	}
-	syscall.Kill(pid, syscall.SIGKILL)
 
 	// Step 4: Verify process is dead
-	deadline = time.Now().Add(2 * time.Second)
+	if waitForExit(pid, 2*time.Second) {
+		return nil
+	}
+
+	return fmt.Errorf("process %d could not be killed", pid)
+}
+
+// waitForExit polls until pid exits or timeout passes, reporting whether it exited
+func waitForExit(pid int, timeout time.Duration) bool {
+	deadline := time.Now().Add(timeout)
 	for time.Now().Before(deadline) {
-		if err := syscall.Kill(pid, 0); err != nil {
-			// Process is gone
-			return nil
+		if !IsRunning(pid) {
+			return true
 		}
 		time.Sleep(100 * time.Millisecond)
 	}
-
-	return fmt.Errorf("process %d could not be killed", pid)
+	return !IsRunning(pid)
 }
 
 // GetEntry returns information about a specific process
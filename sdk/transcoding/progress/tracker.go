@@ -0,0 +1,226 @@
+package progress
+
+import (
+	"bytes"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mantonx/viewra/sdk/transcoding/types"
+)
+
+// Stats holds the values reported in a single FFmpeg -progress block.
+// FFmpeg emits one key=value pair per line and terminates each block with
+// a "progress=continue" or "progress=end" line.
+type Stats struct {
+	Frame      int64
+	FPS        float64
+	Bitrate    float64 // kbits/s
+	TotalSize  int64   // bytes written so far
+	OutTime    time.Duration
+	Speed      float64
+	DupFrames  int64
+	DropFrames int64
+	Ended      bool
+}
+
+// Tracker consumes FFmpeg's machine-readable progress output (enabled with
+// "-progress pipe:1") and keeps the most recent stats for a session.
+//
+// Tracker implements io.Writer so it can be assigned directly to the
+// command's Stdout. The writer returned by DurationWriter should receive
+// stderr so the input duration can be picked up from FFmpeg's banner,
+// which is needed to turn output time into a completion percentage.
+type Tracker struct {
+	mu        sync.RWMutex
+	startTime time.Time
+	seek      time.Duration
+	duration  time.Duration
+	pending   Stats
+	latest    Stats
+	updates   int
+	speedSum  float64
+	lineBuf   []byte
+	stderrBuf []byte
+}
+
+// NewTracker creates a tracker for a transcode starting at the given seek offset
+func NewTracker(seek time.Duration) *Tracker {
+	return &Tracker{
+		startTime: time.Now(),
+		seek:      seek,
+	}
+}
+
+// SetDuration sets the total input duration when it is known up front
+func (t *Tracker) SetDuration(d time.Duration) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.duration = d
+}
+
+// Write implements io.Writer for FFmpeg's -progress output
+func (t *Tracker) Write(p []byte) (int, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.lineBuf = append(t.lineBuf, p...)
+	for {
+		idx := bytes.IndexByte(t.lineBuf, '\n')
+		if idx < 0 {
+			break
+		}
+		line := strings.TrimSpace(string(t.lineBuf[:idx]))
+		t.lineBuf = t.lineBuf[idx+1:]
+		t.parseLine(line)
+	}
+
+	return len(p), nil
+}
+
+// DurationWriter returns a writer that scans stderr for the input duration
+func (t *Tracker) DurationWriter() io.Writer {
+	return &durationWriter{tracker: t}
+}
+
+// HasStats reports whether at least one progress block has been received
+func (t *Tracker) HasStats() bool {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.updates > 0
+}
+
+// Stats returns the most recent complete progress block
+func (t *Tracker) Stats() Stats {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.latest
+}
+
+// Progress converts the latest stats into a TranscodingProgress
+func (t *Tracker) Progress() *types.TranscodingProgress {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	stats := t.latest
+	progress := &types.TranscodingProgress{
+		TimeElapsed:    time.Since(t.startTime),
+		CurrentSpeed:   stats.Speed,
+		CurrentFrame:   stats.Frame,
+		CurrentFPS:     stats.FPS,
+		CurrentBitrate: stats.Bitrate,
+		BytesWritten:   stats.TotalSize,
+		DroppedFrames:  stats.DropFrames,
+	}
+
+	if t.updates > 0 {
+		progress.AverageSpeed = t.speedSum / float64(t.updates)
+	}
+
+	total := t.duration - t.seek
+	if total > 0 {
+		progress.PercentComplete = float64(stats.OutTime) / float64(total) * 100
+		if progress.PercentComplete > 100 {
+			progress.PercentComplete = 100
+		}
+
+		if stats.Speed > 0 {
+			remaining := total - stats.OutTime
+			if remaining > 0 {
+				progress.TimeRemaining = time.Duration(float64(remaining) / stats.Speed)
+				progress.EstimatedTime = progress.TimeElapsed + progress.TimeRemaining
+			}
+		}
+
+		if stats.FPS > 0 && stats.OutTime > 0 {
+			// Derive total frames from the observed output frame rate
+			frameRate := float64(stats.Frame) / stats.OutTime.Seconds()
+			progress.TotalFrames = int64(frameRate * total.Seconds())
+		}
+	}
+
+	if stats.Ended {
+		progress.PercentComplete = 100
+		progress.TimeRemaining = 0
+	}
+
+	return progress
+}
+
+// parseLine applies a single key=value line to the pending block.
+// Must be called with the lock held.
+func (t *Tracker) parseLine(line string) {
+	key, value, ok := strings.Cut(line, "=")
+	if !ok {
+		return
+	}
+	value = strings.TrimSpace(value)
+
+	switch key {
+	case "frame":
+		t.pending.Frame, _ = strconv.ParseInt(value, 10, 64)
+	case "fps":
+		t.pending.FPS, _ = strconv.ParseFloat(value, 64)
+	case "bitrate":
+		t.pending.Bitrate, _ = strconv.ParseFloat(strings.TrimSuffix(value, "kbits/s"), 64)
+	case "total_size":
+		t.pending.TotalSize, _ = strconv.ParseInt(value, 10, 64)
+	case "out_time_us":
+		// out_time_ms is also reported in microseconds (a long-standing FFmpeg quirk),
+		// so out_time_us is the only key used for timing.
+		if us, err := strconv.ParseInt(value, 10, 64); err == nil && us >= 0 {
+			t.pending.OutTime = time.Duration(us) * time.Microsecond
+		}
+	case "speed":
+		t.pending.Speed, _ = strconv.ParseFloat(strings.TrimSuffix(value, "x"), 64)
+	case "dup_frames":
+		t.pending.DupFrames, _ = strconv.ParseInt(value, 10, 64)
+	case "drop_frames":
+		t.pending.DropFrames, _ = strconv.ParseInt(value, 10, 64)
+	case "progress":
+		t.pending.Ended = value == "end"
+		t.latest = t.pending
+		t.updates++
+		t.speedSum += t.pending.Speed
+	}
+}
+
+// durationWriter scans FFmpeg's stderr for the "Duration:" line of the input
+type durationWriter struct {
+	tracker *Tracker
+	found   bool
+}
+
+// Write implements io.Writer
+func (w *durationWriter) Write(p []byte) (int, error) {
+	if w.found {
+		return len(p), nil
+	}
+
+	t := w.tracker
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.stderrBuf = append(t.stderrBuf, p...)
+	for {
+		idx := bytes.IndexByte(t.stderrBuf, '\n')
+		if idx < 0 {
+			break
+		}
+		line := string(t.stderrBuf[:idx])
+		t.stderrBuf = t.stderrBuf[idx+1:]
+
+		if strings.Contains(line, "Duration:") {
+			if d := ParseFFmpegDuration(line); d > 0 && t.duration == 0 {
+				t.duration = d
+			}
+			w.found = true
+			t.stderrBuf = nil
+			break
+		}
+	}
+
+	return len(p), nil
+}
@@ -0,0 +1,30 @@
+package transcoding
+
+import (
+	"io"
+	"os/exec"
+	"time"
+
+	"github.com/mantonx/viewra/sdk/transcoding/progress"
+)
+
+// attachProgressTracker routes FFmpeg's -progress output into a tracker while
+// keeping any log writers already configured on the command. Stderr is also
+// teed into the tracker so it can pick up the input duration.
+func attachProgressTracker(cmd *exec.Cmd, seek time.Duration) *progress.Tracker {
+	tracker := progress.NewTracker(seek)
+
+	if cmd.Stdout != nil {
+		cmd.Stdout = io.MultiWriter(cmd.Stdout, tracker)
+	} else {
+		cmd.Stdout = tracker
+	}
+
+	if cmd.Stderr != nil {
+		cmd.Stderr = io.MultiWriter(cmd.Stderr, tracker.DurationWriter())
+	} else {
+		cmd.Stderr = tracker.DurationWriter()
+	}
+
+	return tracker
+}
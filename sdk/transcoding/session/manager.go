@@ -37,6 +37,8 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/mantonx/viewra/sdk/transcoding/hardware"
+	"github.com/mantonx/viewra/sdk/transcoding/progress"
 	"github.com/mantonx/viewra/sdk/transcoding/types"
 )
 
@@ -50,6 +52,9 @@ type Session struct {
 	Cancel    context.CancelFunc
 	Progress  float64
 	Status    SessionStatus
+	Tracker   *progress.Tracker // Real-time stats parsed from FFmpeg -progress output
+	Hardware  *hardware.DeviceLease // Device slot the session encodes on, nil for software encodes
+	FellBack  bool                  // Restarted in software after the hardware encoder failed
 }
 
 // SessionStatus represents the current state of a session
@@ -37,13 +37,13 @@ import (
 	"os/exec"
 	"path/filepath"
 	"sync"
-	"syscall"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/mantonx/viewra/sdk/transcoding/ffmpeg"
 	"github.com/mantonx/viewra/sdk/transcoding/process"
 	"github.com/mantonx/viewra/sdk/transcoding/types"
+	"github.com/mantonx/viewra/sdk/transcoding/utils"
 )
 
 // Streamer handles video streaming operations
@@ -116,9 +116,7 @@ func (s *Streamer) StartStream(ctx context.Context, req types.TranscodeRequest)
 
 	// Create command
 	cmd := exec.CommandContext(streamCtx, s.ffmpegPath, args...)
-	cmd.SysProcAttr = &syscall.SysProcAttr{
-		Setpgid: true, // Create new process group
-	}
+	process.ConfigureCommand(cmd) // Own process group so children are cleaned up too
 
 	// Set up logging
 	if err := s.setupProcessLogging(cmd, outputDir, sessionID); err != nil {
@@ -294,10 +292,7 @@ func (s *Streamer) prepareOutputPaths(req types.TranscodeRequest, sessionID stri
 	if req.OutputPath != "" {
 		outputDir = req.OutputPath
 	} else {
-		baseDir := "/app/viewra-data/streaming"
-		if envDir := os.Getenv("VIEWRA_STREAMING_DIR"); envDir != "" {
-			baseDir = envDir
-		}
+		baseDir := utils.StreamingDir()
 		outputDir = filepath.Join(baseDir, fmt.Sprintf("%s_%s", req.Container, sessionID))
 	}
 	
@@ -312,7 +307,7 @@ func (s *Streamer) prepareOutputPaths(req types.TranscodeRequest, sessionID stri
 	case "dash":
 		manifestFile = "manifest.mpd"
 	case "hls":
-		manifestFile = "playlist.m3u8"
+		manifestFile = ffmpeg.HLSMasterPlaylist
 	}
 
 	outputPath := filepath.Join(outputDir, manifestFile)
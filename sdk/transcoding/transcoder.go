@@ -45,14 +45,17 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
-	"syscall"
+	"sync"
 	"time"
 
 	"github.com/mantonx/viewra/sdk/transcoding/abr"
+	"github.com/mantonx/viewra/sdk/transcoding/config"
 	"github.com/mantonx/viewra/sdk/transcoding/ffmpeg"
+	"github.com/mantonx/viewra/sdk/transcoding/hardware"
 	"github.com/mantonx/viewra/sdk/transcoding/process"
 	"github.com/mantonx/viewra/sdk/transcoding/session"
 	"github.com/mantonx/viewra/sdk/transcoding/types"
+	"github.com/mantonx/viewra/sdk/transcoding/utils"
 	"github.com/mantonx/viewra/sdk/transcoding/validation"
 )
 
@@ -74,6 +77,12 @@ type Transcoder struct {
 	processRegistry *process.Registry
 	argsBuilder    *ffmpeg.FFmpegArgsBuilder
 	abrGenerator   *abr.Generator
+
+	// Hardware encoding
+	hardwareMu        sync.RWMutex
+	hardwareConfig    config.HardwareConfig
+	hardwareDetecting bool
+	hardwareSessions  *hardware.SessionLimiter // Set once devices are detected
 }
 
 // NewTranscoder creates a new transcoder  
@@ -99,6 +108,22 @@ func (t *Transcoder) SetLogger(logger types.Logger) {
 	t.abrGenerator = abr.NewGenerator(logger)
 }
 
+// SetArgTemplates configures the FFmpeg argument templates. Must be called after SetLogger.
+func (t *Transcoder) SetArgTemplates(templates []ffmpeg.ArgTemplate) error {
+	if t.argsBuilder == nil {
+		return fmt.Errorf("transcoder not initialized")
+	}
+	return t.argsBuilder.SetTemplates(templates)
+}
+
+// SetABRLadder configures the rungs of the ABR ladder. Must be called after SetLogger.
+func (t *Transcoder) SetABRLadder(rungs []abr.RungConfig) error {
+	if t.argsBuilder == nil {
+		return fmt.Errorf("transcoder not initialized")
+	}
+	return t.argsBuilder.SetLadder(rungs)
+}
+
 // GetInfo returns provider information
 func (t *Transcoder) GetInfo() types.ProviderInfo {
 	return types.ProviderInfo{
@@ -117,7 +142,7 @@ func (t *Transcoder) GetSupportedFormats() []types.ContainerFormat {
 		{
 			Format:      "hls",
 			MimeType:    "application/vnd.apple.mpegurl",
-			Extensions:  []string{".m3u8", ".ts"},
+			Extensions:  []string{".m3u8", ".m4s"},
 			Description: "HLS Adaptive Streaming",
 			Adaptive:    true,
 		},
@@ -156,14 +181,41 @@ func (t *Transcoder) StartTranscode(ctx context.Context, req types.TranscodeRequ
 	// Update session handle with directory
 	sess.Handle.Directory = outputDir
 
+	// Reserve a hardware device when the request can use one
+	req, lease, err := t.assignHardware(req)
+	if err != nil {
+		t.sessionManager.RemoveSession(sess.ID)
+		return nil, err
+	}
+	t.sessionManager.UpdateSession(sess.ID, func(s *session.Session) {
+		s.Hardware = lease
+	})
+
+	if err := t.startProcess(sess.ID, req, outputDir, outputPath); err != nil {
+		lease.Release()
+		t.handleStartError(sess.ID, err)
+		return nil, fmt.Errorf("failed to start FFmpeg: %w", err)
+	}
+
+	// Start validation for DASH output
+	if req.Container == "dash" {
+		go t.validateOutput(sess.ID, outputDir)
+	}
+
+	// Start progress monitoring
+	go t.monitorProgress(sess.ID)
+
+	return sess.Handle, nil
+}
+
+// startProcess launches FFmpeg for a session's request and starts monitoring it
+func (t *Transcoder) startProcess(sessionID string, req types.TranscodeRequest, outputDir, outputPath string) error {
 	// Build FFmpeg arguments using the args builder
 	args := t.argsBuilder.BuildArgs(req, outputPath)
 
 	// Create and configure FFmpeg command
 	cmd := exec.Command("ffmpeg", args...)
-	cmd.SysProcAttr = &syscall.SysProcAttr{
-		Setpgid: true, // Create new process group
-	}
+	process.ConfigureCommand(cmd) // Own process group so children are cleaned up too
 	cmd.Dir = outputDir
 
 	// Set up logging
@@ -173,47 +225,44 @@ func (t *Transcoder) StartTranscode(ctx context.Context, req types.TranscodeRequ
 		}
 	}
 
+	// Parse FFmpeg's -progress output into real-time stats
+	tracker := attachProgressTracker(cmd, req.Seek)
+
 	// Log the command
 	if t.logger != nil {
 		t.logger.Info("starting FFmpeg transcoding",
-			"session_id", sess.ID,
+			"session_id", sessionID,
+			"hardware_device", req.HardwareDevice,
 			"command", fmt.Sprintf("ffmpeg %v", args),
 		)
 	}
 
 	// Update session with process
-	t.sessionManager.UpdateSession(sess.ID, func(s *session.Session) {
+	t.sessionManager.UpdateSession(sessionID, func(s *session.Session) {
 		s.Process = cmd
+		s.Tracker = tracker
+		s.Request = req
 		s.Status = session.SessionStatusStarting
 	})
 
 	// Start the process
 	if err := cmd.Start(); err != nil {
-		t.handleStartError(sess.ID, err)
-		return nil, fmt.Errorf("failed to start FFmpeg: %w", err)
+		return err
 	}
 
 	// Start monitoring the process
-	if err := t.processMonitor.MonitorProcess(cmd, sess.ID, t.name); err != nil {
+	if err := t.processMonitor.MonitorProcess(cmd, sessionID, t.name); err != nil {
 		if t.logger != nil {
 			t.logger.Warn("failed to start process monitoring", "error", err)
 		}
 	}
 
 	// Update session status
-	t.sessionManager.UpdateSession(sess.ID, func(s *session.Session) {
+	t.sessionManager.UpdateSession(sessionID, func(s *session.Session) {
 		s.Status = session.SessionStatusRunning
 	})
 
-	// Start validation for DASH output
-	if req.Container == "dash" {
-		go t.validateOutput(sess.ID, outputDir)
-	}
-
-	// Start progress monitoring
-	go t.monitorProgress(sess.ID)
-
-	return sess.Handle, nil
+	return nil
 }
 
 // GetProgress returns transcoding progress
@@ -227,13 +276,19 @@ func (t *Transcoder) GetProgress(handle *types.TranscodeHandle) (*types.Transcod
 		return nil, err
 	}
 
-	// Calculate progress based on session data
-	elapsed := time.Since(sess.StartTime)
+	// Prefer real stats parsed from FFmpeg's -progress output
+	if sess.Tracker != nil && sess.Tracker.HasStats() {
+		progress := sess.Tracker.Progress()
+		if progress.PercentComplete == 0 {
+			progress.PercentComplete = sess.Progress
+		}
+		return progress, nil
+	}
+
+	// No stats reported yet
 	progress := &types.TranscodingProgress{
 		PercentComplete: sess.Progress,
-		TimeElapsed:     elapsed,
-		CurrentSpeed:    1.0,
-		AverageSpeed:    1.0,
+		TimeElapsed:     time.Since(sess.StartTime),
 	}
 
 	return progress, nil
@@ -270,6 +325,7 @@ func (t *Transcoder) StopTranscode(handle *types.TranscodeHandle) error {
 	t.sessionManager.UpdateSession(handle.SessionID, func(s *session.Session) {
 		s.Status = session.SessionStatusStopped
 	})
+	sess.Hardware.Release()
 
 	// Schedule session removal
 	go func() {
@@ -286,10 +342,7 @@ func (t *Transcoder) prepareOutputPaths(req types.TranscodeRequest, sessionID st
 	if req.OutputPath != "" {
 		outputDir = req.OutputPath
 	} else {
-		baseDir := "/app/viewra-data/transcoding"
-		if envDir := os.Getenv("VIEWRA_TRANSCODING_DIR"); envDir != "" {
-			baseDir = envDir
-		}
+		baseDir := utils.TranscodingDir()
 		outputDir = fmt.Sprintf("%s/%s_%s_%s", baseDir, req.Container, t.name, sessionID)
 	}
 	
@@ -302,9 +355,11 @@ func (t *Transcoder) prepareOutputPaths(req types.TranscodeRequest, sessionID st
 	var outputPath string
 	switch req.Container {
 	case "hls":
-		outputPath = filepath.Join(outputDir, "playlist.m3u8")
+		outputPath = filepath.Join(outputDir, ffmpeg.HLSMasterPlaylist)
 	case "dash":
 		outputPath = filepath.Join(outputDir, "manifest.mpd")
+	case "webm", "mkv", "ogg", "opus", "m4a", "mp3", "flac":
+		outputPath = filepath.Join(outputDir, "output."+req.Container)
 	default:
 		outputPath = filepath.Join(outputDir, "output.mp4")
 	}
@@ -403,18 +458,28 @@ func (t *Transcoder) monitorProgress(sessionID string) {
 						s.Progress = 100.0
 						s.Status = session.SessionStatusComplete
 					})
+				} else if t.fallBackToSoftware(sess, exitCode) {
+					// Keep monitoring the software run
+					continue
 				} else {
 					t.sessionManager.UpdateSession(sessionID, func(s *session.Session) {
 						s.Status = session.SessionStatusFailed
 					})
 				}
+				sess.Hardware.Release()
 				
 				return
 			}
 
-			// TODO: Parse FFmpeg progress from stderr log
-			// For now, just increment progress
+			// Use the parsed completion percentage once the input duration is known,
+			// otherwise fall back to a coarse estimate
 			t.sessionManager.UpdateSession(sessionID, func(s *session.Session) {
+				if s.Tracker != nil && s.Tracker.HasStats() {
+					if percent := s.Tracker.Progress().PercentComplete; percent > 0 {
+						s.Progress = percent
+						return
+					}
+				}
 				if s.Progress < 95 {
 					s.Progress += 5
 				}
@@ -460,6 +525,9 @@ func (t *Transcoder) GetDashboardData(sectionID string) (interface{}, error) {
 		stats := t.sessionManager.GetSessionStats()
 		stats["provider"] = t.name
 		stats["version"] = t.version
+		if devices := t.HardwareDevices(); devices != nil {
+			stats["hardware_devices"] = devices
+		}
 		return stats, nil
 		
 	case "sessions":
@@ -19,6 +19,7 @@ package types
 
 import (
 	"context"
+	"strings"
 	"time"
 )
 
@@ -32,20 +33,33 @@ type Logger interface {
 
 // TranscodeRequest contains the parameters for a transcoding request
 type TranscodeRequest struct {
-	SessionID        string
-	InputPath        string
-	OutputPath       string
-	Container        string
-	VideoCodec       string
-	AudioCodec       string
-	Resolution       *Resolution
-	Quality          int
-	SpeedPriority    SpeedPriority
-	Seek             time.Duration
-	EnableABR        bool
-	PreferHardware   bool          // Whether to prefer hardware acceleration
-	HardwareType     HardwareType  // Specific hardware type to use
-	ProviderSettings []byte        // Provider-specific settings as JSON
+	SessionID          string
+	InputPath          string
+	OutputPath         string
+	Container          string
+	VideoCodec         string
+	AudioCodec         string
+	AudioBitrate       int               // Audio bitrate in kbps, 0 uses the codec default
+	AudioOnly          bool              // Drop video and produce an audio-only stream
+	AudioStreamIndex   int               // Audio track to encode, counted among audio streams only
+	Gapless            bool              // Keep encoder delay/padding signalling so consecutive tracks join without gaps
+	Loudness           *LoudnessSettings // Loudness normalization, nil leaves levels untouched
+	Deinterlace        DeinterlaceMode   // Interlace/telecine handling, empty deinterlaces flagged frames only
+	TenBit             bool              // Encode 10-bit video; ignored for H.264, which browsers can't decode at 10-bit
+	SubtitleMode       SubtitleMode      // Whether the selected subtitle is burned in, passed through or left out
+	SubtitleTrackIndex int               // Embedded subtitle track, counted among subtitle streams only
+	SubtitleCodec      string            // Codec of the embedded track; bitmap tracks are burned in with an overlay
+	SubtitlePath       string            // External subtitle file used instead of an embedded track
+	Resolution         *Resolution
+	Quality            int
+	SpeedPriority      SpeedPriority
+	Seek               time.Duration
+	EnableABR          bool
+	FastStart          bool         // Short DASH/HLS segments for the opening seconds, so playback starts sooner
+	PreferHardware     bool         // Whether to prefer hardware acceleration
+	HardwareType       HardwareType // Specific hardware type to use
+	HardwareDevice     string       // Device the hardware encoder runs on, assigned by the transcoder
+	ProviderSettings   []byte       // Provider-specific settings as JSON
 }
 
 // Resolution represents video dimensions
@@ -54,6 +68,25 @@ type Resolution struct {
 	Height int
 }
 
+// LoudnessSettings configures EBU R128 loudness normalization.
+// When Measured is set the values from a previous analysis pass are used for
+// accurate linear normalization; otherwise FFmpeg normalizes dynamically.
+type LoudnessSettings struct {
+	TargetIntegrated float64              `json:"target_i"`   // Target integrated loudness in LUFS
+	TargetTruePeak   float64              `json:"target_tp"`  // Maximum true peak in dBTP
+	TargetRange      float64              `json:"target_lra"` // Target loudness range in LU
+	Measured         *LoudnessMeasurement `json:"measured,omitempty"`
+}
+
+// LoudnessMeasurement holds EBU R128 values measured for the source audio
+type LoudnessMeasurement struct {
+	Integrated   float64 `json:"integrated"`    // Integrated loudness in LUFS
+	TruePeak     float64 `json:"true_peak"`     // True peak in dBTP
+	Range        float64 `json:"range"`         // Loudness range in LU
+	Threshold    float64 `json:"threshold"`     // Gating threshold in LUFS
+	TargetOffset float64 `json:"target_offset"` // Offset gain from the first pass in LU
+}
+
 // SpeedPriority represents encoding speed vs quality tradeoff
 type SpeedPriority int
 
@@ -97,6 +130,9 @@ type TranscodingProgress struct {
 	EstimatedTime   time.Duration
 	CurrentFrame    int64
 	TotalFrames     int64
+	CurrentFPS      float64
+	CurrentBitrate  float64 // kbits/s
+	DroppedFrames   int64
 	BytesRead       int64
 	BytesWritten    int64
 }
@@ -159,11 +195,66 @@ const (
 	HardwareTypeVideoToolbox HardwareType = "videotoolbox"
 )
 
+// ParseHardwareType maps the hardware names used in requests and
+// configuration ("nvenc", "cuda", "quicksync", ...) to a HardwareType.
+// "auto" and unknown names map to HardwareTypeNone.
+func ParseHardwareType(name string) HardwareType {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "nvidia", "nvenc", "cuda":
+		return HardwareTypeNVIDIA
+	case "vaapi":
+		return HardwareTypeVAAPI
+	case "qsv", "quicksync", "intel":
+		return HardwareTypeQSV
+	case "videotoolbox":
+		return HardwareTypeVideoToolbox
+	default:
+		return HardwareTypeNone
+	}
+}
+
+// WantsHardware reports whether the request asks for hardware encoding,
+// either a specific type or any available one
+func (r TranscodeRequest) WantsHardware() bool {
+	return r.PreferHardware || (r.HardwareType != "" && r.HardwareType != HardwareTypeNone)
+}
+
+// HardwareDevice is one hardware encoder device found on the system
+type HardwareDevice struct {
+	Type        HardwareType `json:"type"`
+	ID          string       `json:"id"`           // Unique per device, e.g. "vaapi:renderD128" or "nvidia:0"
+	Name        string       `json:"name"`         // Human readable name, e.g. the GPU model
+	Path        string       `json:"path"`         // DRM render node for VAAPI/QSV, GPU index for NVIDIA
+	Encoders    []string     `json:"encoders"`     // FFmpeg encoders that passed a test encode on the device
+	MaxSessions int          `json:"max_sessions"` // Concurrent encodes the device accepts
+}
+
+// DeinterlaceMode selects how interlaced or telecined video is converted to progressive
+type DeinterlaceMode string
+
+const (
+	DeinterlaceAuto     DeinterlaceMode = ""      // Deinterlace only frames flagged as interlaced
+	DeinterlaceOff      DeinterlaceMode = "off"   // Leave the source untouched
+	DeinterlaceYadif    DeinterlaceMode = "yadif" // Fast deinterlacing for interlaced video
+	DeinterlaceBwdif    DeinterlaceMode = "bwdif" // Higher quality deinterlacing for interlaced video
+	DeinterlaceTelecine DeinterlaceMode = "ivtc"  // Inverse telecine for film content with 3:2 pulldown
+)
+
+// SubtitleMode selects what happens to the request's subtitle track
+type SubtitleMode string
+
+const (
+	SubtitleNone        SubtitleMode = ""            // Leave subtitles out of the output
+	SubtitleBurnIn      SubtitleMode = "burn"        // Render the subtitle into the video frames
+	SubtitlePassthrough SubtitleMode = "passthrough" // Carry the subtitle as a stream in the output
+)
+
 // HardwareInfo contains information about available hardware acceleration
 type HardwareInfo struct {
 	Available bool                       `json:"available"`
 	Type      string                     `json:"type"`
 	Encoders  map[string][]string        `json:"encoders"`  // codec -> encoder list
+	Devices   []HardwareDevice           `json:"devices"`
 }
 
 // TranscodeResult represents the result of a completed transcoding operation
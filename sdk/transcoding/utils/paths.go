@@ -0,0 +1,44 @@
+package utils
+
+import (
+	"os"
+	"path/filepath"
+)
+
+const (
+	// TranscodingDirEnv overrides the base directory for transcoded output
+	TranscodingDirEnv = "VIEWRA_TRANSCODING_DIR"
+	// StreamingDirEnv overrides the base directory for streaming output
+	StreamingDirEnv = "VIEWRA_STREAMING_DIR"
+	// DataDirEnv sets a parent directory for all transcoder data, for installs
+	// outside the container image such as Windows and macOS home servers
+	DataDirEnv = "VIEWRA_DATA_DIR"
+)
+
+// TranscodingDir returns the base directory for transcoded output
+func TranscodingDir() string {
+	return DataDir(TranscodingDirEnv, "/app/viewra-data/transcoding", "transcoding")
+}
+
+// StreamingDir returns the base directory for streaming output
+func StreamingDir() string {
+	return DataDir(StreamingDirEnv, "/app/viewra-data/streaming", "streaming")
+}
+
+// DataDir resolves a data directory in order of precedence: the envVar
+// override, <VIEWRA_DATA_DIR>/name, containerDir when its parent exists (as
+// it does in the Docker image), and finally <temp dir>/viewra/name.
+func DataDir(envVar, containerDir, name string) string {
+	if dir := os.Getenv(envVar); dir != "" {
+		return dir
+	}
+	if dir := os.Getenv(DataDirEnv); dir != "" {
+		return filepath.Join(dir, name)
+	}
+	if containerDir != "" {
+		if info, err := os.Stat(filepath.Dir(containerDir)); err == nil && info.IsDir() {
+			return containerDir
+		}
+	}
+	return filepath.Join(os.TempDir(), "viewra", name)
+}
@@ -19,6 +19,10 @@ type (
 	VideoInfo              = types.VideoInfo
 	AudioInfo              = types.AudioInfo
 	Resolution             = types.Resolution
+	LoudnessSettings       = types.LoudnessSettings
+	LoudnessMeasurement    = types.LoudnessMeasurement
+	DeinterlaceMode        = types.DeinterlaceMode
+	SubtitleMode           = types.SubtitleMode
 )
 
 // Constants
@@ -38,5 +42,15 @@ const (
 	HardwareTypeVAAPI        = types.HardwareTypeVAAPI
 	HardwareTypeQSV          = types.HardwareTypeQSV
 	HardwareTypeVideoToolbox = types.HardwareTypeVideoToolbox
+
+	DeinterlaceAuto     = types.DeinterlaceAuto
+	DeinterlaceOff      = types.DeinterlaceOff
+	DeinterlaceYadif    = types.DeinterlaceYadif
+	DeinterlaceBwdif    = types.DeinterlaceBwdif
+	DeinterlaceTelecine = types.DeinterlaceTelecine
+
+	SubtitleNone        = types.SubtitleNone
+	SubtitleBurnIn      = types.SubtitleBurnIn
+	SubtitlePassthrough = types.SubtitlePassthrough
 )
 
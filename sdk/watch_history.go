@@ -0,0 +1,125 @@
+package plugins
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/grpc"
+)
+
+// WatchHistoryServiceName is the host gRPC service through which plugins add
+// to users' watch history
+const WatchHistoryServiceName = "viewra.WatchHistoryService"
+
+// WatchHistoryImportMethod is the full gRPC method name for importing watch history
+const WatchHistoryImportMethod = "/" + WatchHistoryServiceName + "/ImportHistory"
+
+// Watch history entry kinds
+const (
+	WatchHistoryKindWatch  = "watch"
+	WatchHistoryKindRating = "rating"
+)
+
+// WatchHistoryEntry is a watch or rating recorded elsewhere, such as on a
+// scrobbling service. Entries are matched to the library by media ID, then
+// TMDb or IMDb IDs, then title, like a history file import.
+type WatchHistoryEntry struct {
+	Kind           string    `json:"kind"` // watch or rating
+	MediaID        string    `json:"media_id,omitempty"`
+	MediaType      string    `json:"media_type"` // movie or episode
+	Title          string    `json:"title"`
+	Year           int       `json:"year,omitempty"`
+	ShowTitle      string    `json:"show_title,omitempty"`
+	ShowTmdbID     string    `json:"show_tmdb_id,omitempty"`
+	Season         int       `json:"season,omitempty"`
+	Episode        int       `json:"episode,omitempty"`
+	TmdbID         string    `json:"tmdb_id,omitempty"`
+	ImdbID         string    `json:"imdb_id,omitempty"`
+	Date           time.Time `json:"date"` // Watched or rated time
+	WatchedSeconds float64   `json:"watched_seconds,omitempty"`
+	Completed      bool      `json:"completed,omitempty"`
+	Rating         int       `json:"rating,omitempty"` // 1-10
+}
+
+// WatchHistoryImportRequest adds entries to a user's watch history
+type WatchHistoryImportRequest struct {
+	UserID  uint32               `json:"user_id"`
+	Entries []*WatchHistoryEntry `json:"entries"`
+}
+
+// WatchHistoryImportResponse summarizes an import. Watches already recorded
+// at the same time are counted as duplicate rather than added again.
+type WatchHistoryImportResponse struct {
+	Watches   int      `json:"watches"`
+	Ratings   int      `json:"ratings"`
+	Duplicate int      `json:"duplicate"`
+	Unmatched []string `json:"unmatched"`
+}
+
+// WatchHistoryService is implemented by the host to let plugins add watches
+// and ratings to users' history
+type WatchHistoryService interface {
+	ImportHistory(ctx context.Context, req *WatchHistoryImportRequest) (*WatchHistoryImportResponse, error)
+}
+
+// WatchHistoryServiceClient is the plugin side of the host's WatchHistoryService
+type WatchHistoryServiceClient interface {
+	// ImportHistory adds watches and ratings to a user's history
+	ImportHistory(ctx context.Context, req *WatchHistoryImportRequest) (*WatchHistoryImportResponse, error)
+}
+
+func importHistoryHandler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(WatchHistoryImportRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WatchHistoryService).ImportHistory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: WatchHistoryImportMethod,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WatchHistoryService).ImportHistory(ctx, req.(*WatchHistoryImportRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// watchHistoryServiceDesc describes the watch history service, hand-written like the HTTP bridge
+var watchHistoryServiceDesc = grpc.ServiceDesc{
+	ServiceName: WatchHistoryServiceName,
+	HandlerType: (*WatchHistoryService)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ImportHistory",
+			Handler:    importHistoryHandler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "watch_history.go",
+}
+
+// RegisterWatchHistoryServer registers the host's watch history service
+func RegisterWatchHistoryServer(s *grpc.Server, impl WatchHistoryService) {
+	s.RegisterService(&watchHistoryServiceDesc, impl)
+}
+
+// GRPCWatchHistoryServiceClient calls the host's watch history service
+type GRPCWatchHistoryServiceClient struct {
+	conn grpc.ClientConnInterface
+}
+
+// NewWatchHistoryServiceClient returns a watch history client over a connection to the host
+func NewWatchHistoryServiceClient(conn grpc.ClientConnInterface) *GRPCWatchHistoryServiceClient {
+	return &GRPCWatchHistoryServiceClient{conn: conn}
+}
+
+// ImportHistory implements WatchHistoryServiceClient.ImportHistory
+func (c *GRPCWatchHistoryServiceClient) ImportHistory(ctx context.Context, req *WatchHistoryImportRequest) (*WatchHistoryImportResponse, error) {
+	resp := new(WatchHistoryImportResponse)
+	if err := c.conn.Invoke(ctx, WatchHistoryImportMethod, req, resp, grpc.CallContentSubtype(JSONCodec)); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}